@@ -43,12 +43,14 @@ import (
 var (
 	actionTimeout = time.Hour
 	server        string
+	logLevel      = logutilpb.Level_INFO.String()
 )
 
 func init() {
 	servenv.OnParse(func(fs *pflag.FlagSet) {
 		fs.DurationVar(&actionTimeout, "action_timeout", actionTimeout, "timeout for the total command")
 		fs.StringVar(&server, "server", server, "server to use for connection")
+		fs.StringVar(&logLevel, "log-level", logLevel, "minimum level of the log events of the command that the server streams back: INFO, WARNING or ERROR. The output of the command is always streamed back.")
 
 		acl.RegisterFlags(fs)
 	})
@@ -87,12 +89,19 @@ func main() {
 		os.Exit(1)
 	}
 
+	level, err := logutil.ParseLevel(logLevel)
+	if err != nil {
+		log.Error(fmt.Errorf("invalid --log-level: %w", err))
+		os.Exit(1)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
 	defer cancel()
+	ctx = vtctlclient.NewContextWithLogLevel(ctx, level)
 
 	checkDeprecations(args)
 
-	err := vtctlclient.RunCommandAndWait(ctx, server, args, func(e *logutilpb.Event) {
+	err = vtctlclient.RunCommandAndWait(ctx, server, args, func(e *logutilpb.Event) {
 		logutil.LogEvent(logger, e)
 	})
 	if err != nil {
@@ -78,6 +78,9 @@ func run(cmd *cobra.Command, args []string) error {
 	// Register http debug/health
 	vtctld.RegisterDebugHealthHandler(ts)
 
+	// Register http debug/console-log-min-level
+	vtctld.RegisterDebugConsoleLogHandler()
+
 	// Start schema manager service.
 	initSchema(cmd.Context())
 
@@ -47,6 +47,7 @@ var (
 		Short: "Finds the most up-to-date REPLICA, RDONLY, or SPARE tablet in the given shard and uses the BackupStorage service on that tablet to create and store a new backup.",
 		Long: `Finds the most up-to-date REPLICA, RDONLY, or SPARE tablet in the given shard and uses the BackupStorage service on that tablet to create and store a new backup.
 
+The tablets are ranked by type, RDONLY first, then REPLICA, then SPARE, and then by replication lag, except that the tablets more than 5 minutes behind come last.
 If no replica-type tablet can be found, the backup can be taken on the primary if --allow-primary is specified.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
@@ -44,22 +45,32 @@ be stored starting at the provided root.`,
 	}
 	// AddCellsAlias makes an AddCellsAlias gRPC call to a vtctld.
 	AddCellsAlias = &cobra.Command{
-		Use:   "AddCellsAlias --cells <cell1,cell2,...> [--cells <cell3> ...] <alias>",
+		Use:   "AddCellsAlias --cells <cell1,cell2,...> [--cells <cell3> ...] [--force] [--dry-run] <alias>",
 		Short: "Defines a group of cells that can be referenced by a single name (the alias).",
 		Long: `Defines a group of cells that can be referenced by a single name (the alias).
 
 When routing query traffic, replica/rdonly traffic can be routed across cells
 within the group (alias). Only primary traffic can be routed across cells not in
-the same group (alias).`,
+the same group (alias).
+
+Every cell must have a CellInfo, and cannot be in another alias unless --force is
+passed. The keyspaces and tablet types whose routing changes, according to the
+SrvKeyspaces of the cells, are printed. With --dry-run, the alias is not created.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandAddCellsAlias,
 	}
 	// DeleteCellInfo makes a DeleteCellInfo gRPC call to a vtctld.
 	DeleteCellInfo = &cobra.Command{
-		Use:                   "DeleteCellInfo [--force] <cell>",
-		Short:                 "Deletes the CellInfo for the provided cell.",
-		Long:                  "Deletes the CellInfo for the provided cell. The cell cannot be referenced by any Shard record.",
+		Use:   "DeleteCellInfo [--force] [--purge-references] <cell>",
+		Short: "Deletes the CellInfo for the provided cell.",
+		Long: `Deletes the CellInfo for the provided cell.
+
+The cell cannot be referenced by any tablet, shard replication graph, shard primary or SrvKeyspace, unless
+--force is passed, nor by any shard tablet control, cells alias or SrvVSchema, unless --purge-references or
+--force is passed. If it is, the command fails with the number of references of each kind. With
+--purge-references, the cell is removed from the tablet controls of the shards and from the cells aliases, and
+its SrvVSchema is deleted, before the cell itself is deleted; each change is printed.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandDeleteCellInfo,
@@ -110,9 +121,14 @@ If a value is empty, it is ignored.`,
 	}
 	// UpdateCellsAlias makes an UpdateCellsAlias gRPC call to a vtctld.
 	UpdateCellsAlias = &cobra.Command{
-		Use:                   "UpdateCellsAlias [--cells <cell1,cell2,...> [--cells <cell4> ...]] <alias>",
-		Short:                 "Updates the content of a CellsAlias with the provided parameters, creating the CellsAlias if it does not exist.",
-		Long:                  "Updates the content of a CellsAlias with the provided parameters, creating the CellsAlias if it does not exist.",
+		Use:   "UpdateCellsAlias [--cells <cell1,cell2,...> [--cells <cell4> ...]] [--force] [--dry-run] <alias>",
+		Short: "Updates the content of a CellsAlias with the provided parameters, creating the CellsAlias if it does not exist.",
+		Long: `Updates the content of a CellsAlias with the provided parameters, creating the CellsAlias if it does not exist.
+
+Every cell must have a CellInfo, and cannot be in another alias unless --force is
+passed. The added and removed cells, and the keyspaces and tablet types whose
+routing changes according to the SrvKeyspaces of the cells, are printed. With
+--dry-run, the alias is not updated.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandUpdateCellsAlias,
@@ -137,40 +153,68 @@ func commandAddCellInfo(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-var addCellsAliasOptions topodatapb.CellsAlias
+var addCellsAliasOptions = struct {
+	Cells  []string
+	Force  bool
+	DryRun bool
+}{}
 
 func commandAddCellsAlias(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	alias := cmd.Flags().Arg(0)
-	_, err := client.AddCellsAlias(commandCtx, &vtctldatapb.AddCellsAliasRequest{
-		Name:  alias,
-		Cells: addCellsAliasOptions.Cells,
+	resp, err := client.AddCellsAlias(commandCtx, &vtctldatapb.AddCellsAliasRequest{
+		Name:   alias,
+		Cells:  addCellsAliasOptions.Cells,
+		Force:  addCellsAliasOptions.Force,
+		DryRun: addCellsAliasOptions.DryRun,
 	})
 	if err != nil {
 		return err
 	}
 
+	printCellsAliasRoutingChanges(resp.RoutingChanges)
+	if addCellsAliasOptions.DryRun {
+		fmt.Printf("Would create cells alias: %s (cells = %v)\n", alias, addCellsAliasOptions.Cells)
+		return nil
+	}
 	fmt.Printf("Created cells alias: %s (cells = %v)\n", alias, addCellsAliasOptions.Cells)
 	return nil
 }
 
+// printCellsAliasRoutingChanges prints the keyspaces and tablet types whose
+// routing changes with a cells alias.
+func printCellsAliasRoutingChanges(changes []*vtctldatapb.CellsAliasRoutingChange) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Println("Routing changes between the cells of the alias:")
+	for _, change := range changes {
+		fmt.Printf("  %s %s (served in %s)\n", change.Keyspace, topoproto.TabletTypeLString(change.TabletType), strings.Join(change.Cells, ", "))
+	}
+}
+
 var deleteCellInfoOptions = struct {
-	Force bool
+	Force           bool
+	PurgeReferences bool
 }{}
 
 func commandDeleteCellInfo(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	cell := cmd.Flags().Arg(0)
-	_, err := client.DeleteCellInfo(commandCtx, &vtctldatapb.DeleteCellInfoRequest{
-		Name:  cell,
-		Force: deleteCellInfoOptions.Force,
+	resp, err := client.DeleteCellInfo(commandCtx, &vtctldatapb.DeleteCellInfoRequest{
+		Name:            cell,
+		Force:           deleteCellInfoOptions.Force,
+		PurgeReferences: deleteCellInfoOptions.PurgeReferences,
 	})
 	if err != nil {
 		return err
 	}
 
+	for _, purged := range resp.PurgedReferences {
+		fmt.Println(purged)
+	}
 	fmt.Printf("Deleted cell %s\n", cell)
 	return nil
 }
@@ -264,7 +308,11 @@ func commandUpdateCellInfo(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-var updateCellsAliasOptions topodatapb.CellsAlias
+var updateCellsAliasOptions = struct {
+	topodatapb.CellsAlias
+	Force  bool
+	DryRun bool
+}{}
 
 func commandUpdateCellsAlias(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
@@ -272,7 +320,9 @@ func commandUpdateCellsAlias(cmd *cobra.Command, args []string) error {
 	alias := cmd.Flags().Arg(0)
 	resp, err := client.UpdateCellsAlias(commandCtx, &vtctldatapb.UpdateCellsAliasRequest{
 		Name:       alias,
-		CellsAlias: &updateCellsAliasOptions,
+		CellsAlias: &updateCellsAliasOptions.CellsAlias,
+		Force:      updateCellsAliasOptions.Force,
+		DryRun:     updateCellsAliasOptions.DryRun,
 	})
 	if err != nil {
 		return err
@@ -283,6 +333,17 @@ func commandUpdateCellsAlias(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if len(resp.AddedCells) > 0 {
+		fmt.Printf("Added cells: %s\n", strings.Join(resp.AddedCells, ", "))
+	}
+	if len(resp.RemovedCells) > 0 {
+		fmt.Printf("Removed cells: %s\n", strings.Join(resp.RemovedCells, ", "))
+	}
+	printCellsAliasRoutingChanges(resp.RoutingChanges)
+	if updateCellsAliasOptions.DryRun {
+		fmt.Printf("Would update cells alias %s. New CellsAlias:\n%s\n", resp.Name, data)
+		return nil
+	}
 	fmt.Printf("Updated cells alias %s. New CellsAlias:\n%s\n", resp.Name, data)
 	return nil
 }
@@ -294,9 +355,12 @@ func init() {
 	Root.AddCommand(AddCellInfo)
 
 	AddCellsAlias.Flags().StringSliceVarP(&addCellsAliasOptions.Cells, "cells", "c", nil, "The list of cell names that are members of this alias.")
+	AddCellsAlias.Flags().BoolVar(&addCellsAliasOptions.Force, "force", false, "Allows the alias to have cells that are in other aliases.")
+	AddCellsAlias.Flags().BoolVar(&addCellsAliasOptions.DryRun, "dry-run", false, "Prints the routing changes without creating the alias.")
 	Root.AddCommand(AddCellsAlias)
 
-	DeleteCellInfo.Flags().BoolVarP(&deleteCellInfoOptions.Force, "force", "f", false, "Proceeds even if the cell's topology server cannot be reached. The assumption is that you shut down the entire cell, and just need to update the global topo data.")
+	DeleteCellInfo.Flags().BoolVarP(&deleteCellInfoOptions.Force, "force", "f", false, "Proceeds even if the cell's topology server cannot be reached. The assumption is that you shut down the entire cell, and just need to update the global topo data, or if the cell is still referenced.")
+	DeleteCellInfo.Flags().BoolVar(&deleteCellInfoOptions.PurgeReferences, "purge-references", false, "Removes the cell from the tablet controls of the shards and from the cells aliases, and deletes its SrvVSchema, before deleting it.")
 	Root.AddCommand(DeleteCellInfo)
 	Root.AddCommand(DeleteCellsAlias)
 
@@ -309,5 +373,7 @@ func init() {
 	Root.AddCommand(UpdateCellInfo)
 
 	UpdateCellsAlias.Flags().StringSliceVarP(&updateCellsAliasOptions.Cells, "cells", "c", nil, "The list of cell names that are members of this alias.")
+	UpdateCellsAlias.Flags().BoolVar(&updateCellsAliasOptions.Force, "force", false, "Allows the alias to have cells that are in other aliases.")
+	UpdateCellsAlias.Flags().BoolVar(&updateCellsAliasOptions.DryRun, "dry-run", false, "Prints the changes without updating the alias.")
 	Root.AddCommand(UpdateCellsAlias)
 }
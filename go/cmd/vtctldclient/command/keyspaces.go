@@ -342,13 +342,35 @@ func commandSetKeyspaceDurabilityPolicy(cmd *cobra.Command, args []string) error
 	return nil
 }
 
+var validateVersionKeyspaceOptions = struct {
+	Shards                  []string
+	ReferenceShard          string
+	IncludeNonServing       bool
+	IncludeNonServingShards bool
+	Stream                  bool
+}{}
+
 func commandValidateVersionKeyspace(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	ks := cmd.Flags().Arg(0)
-	resp, err := client.ValidateVersionKeyspace(commandCtx, &vtctldatapb.ValidateVersionKeyspaceRequest{
-		Keyspace: ks,
-	})
+	req := &vtctldatapb.ValidateVersionKeyspaceRequest{
+		Keyspace:                ks,
+		Shards:                  validateVersionKeyspaceOptions.Shards,
+		ReferenceShard:          validateVersionKeyspaceOptions.ReferenceShard,
+		IncludeNonServing:       validateVersionKeyspaceOptions.IncludeNonServing,
+		IncludeNonServingShards: validateVersionKeyspaceOptions.IncludeNonServingShards,
+	}
+	if validateVersionKeyspaceOptions.Stream {
+		stream, err := client.ValidateVersionKeyspaceStream(commandCtx, req)
+		if err != nil {
+			return err
+		}
+		_, err = printValidationStream(stream)
+		return err
+	}
+
+	resp, err := client.ValidateVersionKeyspace(commandCtx, req)
 
 	if err != nil {
 		return err
@@ -388,5 +410,10 @@ func init() {
 	SetKeyspaceDurabilityPolicy.Flags().StringVar(&setKeyspaceDurabilityPolicyOptions.DurabilityPolicy, "durability-policy", policy.DurabilityNone, "Type of durability to enforce for this keyspace. Default is none. Other values include 'semi_sync' and others as dictated by registered plugins.")
 	Root.AddCommand(SetKeyspaceDurabilityPolicy)
 
+	ValidateVersionKeyspace.Flags().StringSliceVar(&validateVersionKeyspaceOptions.Shards, "shards", nil, "Optional comma-separated list of shards to validate in the keyspace. Defaults to all shards.")
+	ValidateVersionKeyspace.Flags().StringVar(&validateVersionKeyspaceOptions.ReferenceShard, "reference-shard", "", "Optional shard whose primary's version the others are compared with. Defaults to the first serving shard.")
+	ValidateVersionKeyspace.Flags().BoolVar(&validateVersionKeyspaceOptions.IncludeNonServing, "include-non-serving", false, "Also validates the DRAINED, BACKUP and RESTORE tablets, which are skipped by default.")
+	ValidateVersionKeyspace.Flags().BoolVar(&validateVersionKeyspaceOptions.IncludeNonServingShards, "include-non-serving-shards", false, "Also validates the shards that serve no traffic, such as the overlapping shards of a reshard, which are skipped by default.")
+	ValidateVersionKeyspace.Flags().BoolVar(&validateVersionKeyspaceOptions.Stream, "stream", false, "Prints each finding as soon as it is found, and the progress of the validation periodically.")
 	Root.AddCommand(ValidateVersionKeyspace)
 }
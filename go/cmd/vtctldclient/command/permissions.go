@@ -18,11 +18,14 @@ package command
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/vtctldclient"
 
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
@@ -36,11 +39,11 @@ var (
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandGetPermissions,
 	}
-	// ValidatePermissionsShard makes a ValidatePermissionsKeyspace gRPC call to a
-	// vtctld with the specified shard to examine in the keyspace.
+	// ValidatePermissionsShard makes a ValidatePermissionsShard gRPC call to a
+	// vtctld.
 	ValidatePermissionsShard = &cobra.Command{
-		Use:                   "ValidatePermissionsShard <keyspace/shard>",
-		Short:                 "Validates that the permissions on the primary match all of the replicas.",
+		Use:                   "ValidatePermissionsShard [--reference-tablet <alias>] [--ignore-users <user1,user2,...>] [--include-non-serving] <keyspace/shard>",
+		Short:                 "Validates that the permissions on the primary, or on the reference tablet, match those of all of the other tablets in the shard.",
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandValidatePermissionsShard,
@@ -48,8 +51,13 @@ var (
 	// ValidatePermissionsKeyspace makes a ValidatePermissionsKeyspace gRPC call to a
 	// vtctld.
 	ValidatePermissionsKeyspace = &cobra.Command{
-		Use:                   "ValidatePermissionsKeyspace <keyspace name>",
-		Short:                 "Validates that the permissions on the primary of the first shard match those of all of the other tablets in the keyspace.",
+		Use:   "ValidatePermissionsKeyspace [--require-all-shards] [--include-non-serving-shards] <keyspace name>",
+		Short: "Validates that the permissions on the primary of the first shard match those of all of the other tablets in the keyspace.",
+		Long: `Validates that the permissions on the primary of the first shard match those of all of the other tablets in the keyspace.
+
+The shards without a primary, e.g. because they are failing over, are skipped and reported apart from the shards whose permissions differ. They only fail the validation with --require-all-shards.
+
+The shards that serve no traffic, such as the overlapping shards of a reshard, are skipped unless --include-non-serving-shards is set. They never fail the validation.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandValidatePermissionsKeyspace,
@@ -79,36 +87,181 @@ func commandGetPermissions(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var validatePermissionsKeyspaceOptions = struct {
+	Shards                  []string
+	ReferenceShard          string
+	IncludeNonServing       bool
+	IncludeNonServingShards bool
+	Stream                  bool
+	RequireAllShards        bool
+}{}
+
 func commandValidatePermissionsKeyspace(cmd *cobra.Command, args []string) error {
 	keyspace := cmd.Flags().Arg(0)
 
 	cli.FinishedParsing(cmd)
 
-	_, err := client.ValidatePermissionsKeyspace(commandCtx, &vtctldatapb.ValidatePermissionsKeyspaceRequest{
-		Keyspace: keyspace,
-	})
+	req := &vtctldatapb.ValidatePermissionsKeyspaceRequest{
+		Keyspace:                keyspace,
+		Shards:                  validatePermissionsKeyspaceOptions.Shards,
+		ReferenceShard:          validatePermissionsKeyspaceOptions.ReferenceShard,
+		IncludeNonServing:       validatePermissionsKeyspaceOptions.IncludeNonServing,
+		IncludeNonServingShards: validatePermissionsKeyspaceOptions.IncludeNonServingShards,
+		ReportFindings:          true,
+	}
+	if validatePermissionsKeyspaceOptions.Stream {
+		stream, err := client.ValidatePermissionsKeyspaceStream(commandCtx, req)
+		if err != nil {
+			return err
+		}
+		findings, err := printValidationStream(stream)
+		if err != nil {
+			return err
+		}
+		return permissionsValidationError(findings, validatePermissionsKeyspaceOptions.RequireAllShards)
+	}
+
+	resp, err := client.ValidatePermissionsKeyspace(commandCtx, req)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return printValidatePermissionsResponse(resp, validatePermissionsKeyspaceOptions.RequireAllShards)
 }
 
+var validatePermissionsShardOptions = struct {
+	ReferenceTablet   string
+	IgnoreUsers       []string
+	IncludeNonServing bool
+}{}
+
 func commandValidatePermissionsShard(cmd *cobra.Command, args []string) error {
 	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
 
+	req := &vtctldatapb.ValidatePermissionsShardRequest{
+		Keyspace:          keyspace,
+		Shard:             shard,
+		IgnoreUsers:       validatePermissionsShardOptions.IgnoreUsers,
+		IncludeNonServing: validatePermissionsShardOptions.IncludeNonServing,
+	}
+	if validatePermissionsShardOptions.ReferenceTablet != "" {
+		req.ReferenceTablet, err = topoproto.ParseTabletAlias(validatePermissionsShardOptions.ReferenceTablet)
+		if err != nil {
+			return err
+		}
+	}
+
 	cli.FinishedParsing(cmd)
 
-	_, err = client.ValidatePermissionsKeyspace(commandCtx, &vtctldatapb.ValidatePermissionsKeyspaceRequest{
-		Keyspace: keyspace,
-		Shards:   []string{shard},
+	resp, err := client.ValidatePermissionsShard(commandCtx, req)
+	if err != nil {
+		return err
+	}
+
+	return printValidatePermissionsResponse(resp, false)
+}
+
+// validatePermissionsResponse is the response of a keyspace or shard
+// permissions validation.
+type validatePermissionsResponse interface {
+	GetFindings() []*vtctldatapb.ValidationFinding
+	GetSkippedTablets() []*vtctldatapb.SkippedTablet
+}
+
+// printValidatePermissionsResponse prints the findings and skipped tablets
+// of a permissions validation, and fails as permissionsValidationError
+// says. The keyspace and shard responses have the same JSON format.
+func printValidatePermissionsResponse(resp validatePermissionsResponse, requireAllShards bool) error {
+	if len(resp.GetFindings()) == 0 && len(resp.GetSkippedTablets()) == 0 {
+		return nil
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", data)
+
+	return permissionsValidationError(resp.GetFindings(), requireAllShards)
+}
+
+// permissionsValidationError fails a permissions validation if there are
+// differences, or tablets that couldn't be compared, or, if requireAllShards
+// is set, skipped shards. The skipped shards are counted apart from the
+// differences.
+func permissionsValidationError(findings []*vtctldatapb.ValidationFinding, requireAllShards bool) error {
+	differences := 0
+	var skippedShards []string
+	for _, finding := range findings {
+		if finding.Severity == vtctldatapb.ValidationFinding_SKIPPED {
+			if !finding.NotServing {
+				skippedShards = append(skippedShards, finding.Shard)
+			}
+			continue
+		}
+		differences++
+	}
+	if !requireAllShards {
+		skippedShards = nil
+	}
+
+	switch {
+	case differences > 0 && len(skippedShards) > 0:
+		return fmt.Errorf("found %d permissions differences, and skipped shards %v, which have no primary", differences, strings.Join(skippedShards, ","))
+	case differences > 0:
+		return fmt.Errorf("found %d permissions differences", differences)
+	case len(skippedShards) > 0:
+		return fmt.Errorf("skipped shards %v, which have no primary", strings.Join(skippedShards, ","))
+	}
+	return nil
+}
+
+// printValidationStream prints each message of a streaming validation as
+// soon as it is received, and returns the findings. The skipped tablets are
+// not findings.
+func printValidationStream(stream vtctldclient.ValidationStream) ([]*vtctldatapb.ValidationFinding, error) {
+	var findings []*vtctldatapb.ValidationFinding
+	printResponse := func(resp *vtctldatapb.ValidationStreamResponse) error {
+		data, err := cli.MarshalJSON(resp)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+	err := vtctldclient.ConsumeValidationStream(stream, func(finding *vtctldatapb.ValidationFinding) error {
+		findings = append(findings, finding)
+		return printResponse(&vtctldatapb.ValidationStreamResponse{Finding: finding})
+	}, func(skipped *vtctldatapb.SkippedTablet) error {
+		return printResponse(&vtctldatapb.ValidationStreamResponse{SkippedTablet: skipped})
+	}, func(progress *vtctldatapb.ValidationProgress) error {
+		return printResponse(&vtctldatapb.ValidationStreamResponse{Progress: progress})
 	})
+	return findings, err
+}
 
-	return err
+// printSkippedTablets prints the tablets a validation skipped to stderr, so
+// that the output of the command is unchanged.
+func printSkippedTablets(skipped []*vtctldatapb.SkippedTablet) {
+	for _, st := range skipped {
+		fmt.Fprintf(os.Stderr, "Skipped %v tablet %v of shard %v\n", st.TabletType, topoproto.TabletAliasString(st.TabletAlias), st.Shard)
+	}
 }
 
 func init() {
 	Root.AddCommand(GetPermissions)
+	ValidatePermissionsKeyspace.Flags().StringSliceVar(&validatePermissionsKeyspaceOptions.Shards, "shards", nil, "Optional comma-separated list of shards to validate in the keyspace. Defaults to all shards.")
+	ValidatePermissionsKeyspace.Flags().StringVar(&validatePermissionsKeyspaceOptions.ReferenceShard, "reference-shard", "", "Optional shard whose primary's permissions the others are compared with. Defaults to the first serving shard.")
+	ValidatePermissionsKeyspace.Flags().BoolVar(&validatePermissionsKeyspaceOptions.IncludeNonServing, "include-non-serving", false, "Also validates the DRAINED, BACKUP and RESTORE tablets, which are skipped by default.")
+	ValidatePermissionsKeyspace.Flags().BoolVar(&validatePermissionsKeyspaceOptions.IncludeNonServingShards, "include-non-serving-shards", false, "Also validates the shards that serve no traffic, such as the overlapping shards of a reshard, which are skipped by default.")
+	ValidatePermissionsKeyspace.Flags().BoolVar(&validatePermissionsKeyspaceOptions.Stream, "stream", false, "Prints each finding as soon as it is found, and the progress of the validation periodically.")
+	ValidatePermissionsKeyspace.Flags().BoolVar(&validatePermissionsKeyspaceOptions.RequireAllShards, "require-all-shards", false, "Fails the validation if a shard is skipped because it has no primary.")
 	Root.AddCommand(ValidatePermissionsKeyspace)
+	ValidatePermissionsShard.Flags().StringVar(&validatePermissionsShardOptions.ReferenceTablet, "reference-tablet", "", "Optional tablet whose permissions the others are compared with. Defaults to the primary of the shard.")
+	ValidatePermissionsShard.Flags().StringSliceVar(&validatePermissionsShardOptions.IgnoreUsers, "ignore-users", nil, "Optional comma-separated list of MySQL users whose permissions are not compared.")
+	ValidatePermissionsShard.Flags().BoolVar(&validatePermissionsShardOptions.IncludeNonServing, "include-non-serving", false, "Also validates the DRAINED, BACKUP and RESTORE tablets, which are skipped by default.")
 	Root.AddCommand(ValidatePermissionsShard)
 }
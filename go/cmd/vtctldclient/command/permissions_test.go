@@ -547,6 +547,10 @@ func TestPermissions(t *testing.T) {
 				},
 			},
 		},
+		"SELECT * FROM mysql.tables_priv ORDER BY host, db, user, table_name": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Host|Db|User|Table_name|Table_priv|Column_priv", "char|char|char|char|varchar|varchar"),
+			"test_host|test_db|test_user|test_table|Select|"),
+		"SELECT * FROM mysql.columns_priv ORDER BY host, db, user, table_name, column_name": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Host|Db|User|Table_name|Column_name|Column_priv", "char|char|char|char|char|varchar"),
+			"test_host|test_db|test_user|test_table|test_column|Select"),
 	}
 	primary.StartActionLoop(t, ts)
 	defer primary.StopActionLoop(t)
@@ -557,8 +561,10 @@ func TestPermissions(t *testing.T) {
 
 	// The replica will be asked for permissions.
 	replica.FakeMysqlDaemon.FetchSuperQueryMap = map[string]*sqltypes.Result{
-		"SELECT * FROM mysql.user ORDER BY host, user":   &user,
-		"SELECT * FROM mysql.db ORDER BY host, db, user": primary.FakeMysqlDaemon.FetchSuperQueryMap["SELECT * FROM mysql.db ORDER BY host, db, user"],
+		"SELECT * FROM mysql.user ORDER BY host, user":                                      &user,
+		"SELECT * FROM mysql.db ORDER BY host, db, user":                                    primary.FakeMysqlDaemon.FetchSuperQueryMap["SELECT * FROM mysql.db ORDER BY host, db, user"],
+		"SELECT * FROM mysql.tables_priv ORDER BY host, db, user, table_name":               primary.FakeMysqlDaemon.FetchSuperQueryMap["SELECT * FROM mysql.tables_priv ORDER BY host, db, user, table_name"],
+		"SELECT * FROM mysql.columns_priv ORDER BY host, db, user, table_name, column_name": primary.FakeMysqlDaemon.FetchSuperQueryMap["SELECT * FROM mysql.columns_priv ORDER BY host, db, user, table_name, column_name"],
 	}
 	replica.FakeMysqlDaemon.SetReplicationSourceInputs = append(replica.FakeMysqlDaemon.SetReplicationSourceInputs, topoproto.MysqlAddr(primary.Tablet))
 	replica.FakeMysqlDaemon.ExpectedExecuteSuperQueryList = []string{
@@ -581,17 +587,22 @@ func TestPermissions(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	_, err = vtctld.ValidatePermissionsKeyspace(ctx, &vtctldatapb.ValidatePermissionsKeyspaceRequest{
-		Keyspace: primary.Tablet.Keyspace,
+	resp, err := vtctld.ValidatePermissionsKeyspace(ctx, &vtctldatapb.ValidatePermissionsKeyspaceRequest{
+		Keyspace:       primary.Tablet.Keyspace,
+		ReportFindings: true,
 	})
 	require.NoError(t, err)
-	_, err = vtctld.ValidatePermissionsKeyspace(ctx, &vtctldatapb.ValidatePermissionsKeyspaceRequest{
-		Keyspace: primary.Tablet.Keyspace,
-		Shards:   []string{primary.Tablet.Shard},
+	require.Empty(t, resp.Findings)
+	resp, err = vtctld.ValidatePermissionsKeyspace(ctx, &vtctldatapb.ValidatePermissionsKeyspaceRequest{
+		Keyspace:       primary.Tablet.Keyspace,
+		Shards:         []string{primary.Tablet.Shard},
+		ReportFindings: true,
 	})
 	require.NoError(t, err)
+	require.Empty(t, resp.Findings)
 
-	// Modify one field, which should result in a validation failure/error.
+	// Modify one field, which should result in a validation error, or
+	// finding with ReportFindings.
 	replica.FakeMysqlDaemon.FetchSuperQueryMap["SELECT * FROM mysql.user ORDER BY host, user"].Fields[0] = &querypb.Field{
 		Name: "Wrong",
 		Type: sqltypes.Char,
@@ -605,4 +616,68 @@ func TestPermissions(t *testing.T) {
 		Shards:   []string{primary.Tablet.Shard},
 	})
 	require.ErrorContains(t, err, "has an extra user")
+	resp, err = vtctld.ValidatePermissionsKeyspace(ctx, &vtctldatapb.ValidatePermissionsKeyspaceRequest{
+		Keyspace:       primary.Tablet.Keyspace,
+		ReportFindings: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Findings, 1)
+	require.Equal(t, vtctldatapb.ValidationFinding_ERROR, resp.Findings[0].Severity)
+	require.Contains(t, resp.Findings[0].Message, "has an extra user")
+
+	// The shard validation compares the tablets with the primary by default.
+	shardResp, err := vtctld.ValidatePermissionsShard(ctx, &vtctldatapb.ValidatePermissionsShardRequest{
+		Keyspace: primary.Tablet.Keyspace,
+		Shard:    primary.Tablet.Shard,
+	})
+	require.NoError(t, err)
+	require.Len(t, shardResp.Findings, 1)
+	require.Equal(t, vtctldatapb.ValidationFinding_ERROR, shardResp.Findings[0].Severity)
+	require.True(t, topoproto.TabletAliasEqual(replica.Tablet.Alias, shardResp.Findings[0].TabletAlias))
+	require.Contains(t, shardResp.Findings[0].Message, "has an extra user")
+
+	// With the replica as the reference, the finding is about the primary.
+	shardResp, err = vtctld.ValidatePermissionsShard(ctx, &vtctldatapb.ValidatePermissionsShardRequest{
+		Keyspace:        primary.Tablet.Keyspace,
+		Shard:           primary.Tablet.Shard,
+		ReferenceTablet: replica.Tablet.Alias,
+	})
+	require.NoError(t, err)
+	require.Len(t, shardResp.Findings, 1)
+	require.True(t, topoproto.TabletAliasEqual(primary.Tablet.Alias, shardResp.Findings[0].TabletAlias))
+	require.Contains(t, shardResp.Findings[0].Message, "has an extra user")
+
+	// The users that differ can be ignored.
+	shardResp, err = vtctld.ValidatePermissionsShard(ctx, &vtctldatapb.ValidatePermissionsShardRequest{
+		Keyspace:    primary.Tablet.Keyspace,
+		Shard:       primary.Tablet.Shard,
+		IgnoreUsers: []string{"test_user1", "test_user2", "test_user3", "test_user4"},
+	})
+	require.NoError(t, err)
+	require.Empty(t, shardResp.Findings)
+
+	// The shard is required.
+	_, err = vtctld.ValidatePermissionsShard(ctx, &vtctldatapb.ValidatePermissionsShardRequest{
+		Keyspace: primary.Tablet.Keyspace,
+	})
+	require.Error(t, err)
+}
+
+func TestPermissionsValidationError(t *testing.T) {
+	skipped := &vtctldatapb.ValidationFinding{
+		Severity: vtctldatapb.ValidationFinding_SKIPPED,
+		Shard:    "80-",
+		Message:  "no primary in shard ks/80-, skipped",
+	}
+	diff := &vtctldatapb.ValidationFinding{
+		Severity: vtctldatapb.ValidationFinding_ERROR,
+		Shard:    "-80",
+		Message:  "has an extra user",
+	}
+
+	require.NoError(t, permissionsValidationError(nil, true))
+	require.NoError(t, permissionsValidationError([]*vtctldatapb.ValidationFinding{skipped}, false))
+	require.EqualError(t, permissionsValidationError([]*vtctldatapb.ValidationFinding{skipped}, true), "skipped shards 80-, which have no primary")
+	require.EqualError(t, permissionsValidationError([]*vtctldatapb.ValidationFinding{skipped, diff}, false), "found 1 permissions differences")
+	require.EqualError(t, permissionsValidationError([]*vtctldatapb.ValidationFinding{skipped, diff}, true), "found 1 permissions differences, and skipped shards 80-, which have no primary")
 }
@@ -26,13 +26,19 @@ import (
 	"vitess.io/vitess/go/vt/topo/topoproto"
 
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 var (
 	// ExecuteFetchAsApp makes an ExecuteFetchAsApp gRPC call to a vtctld.
 	ExecuteFetchAsApp = &cobra.Command{
-		Use:                   "ExecuteFetchAsApp [--max-rows <max-rows>] [--json|-j] [--use-pool] <tablet-alias> <query>",
-		Short:                 "Executes the given query as the App user on the remote tablet.",
+		Use:   "ExecuteFetchAsApp [--max-rows <max-rows>] [--json|-j] [--use-pool] [--caller-id <principal> [--caller-groups <group1,group2,...>]] <tablet-alias> <query>",
+		Short: "Executes the given query as the App user on the remote tablet.",
+		Long: `Executes the given query as the App user on the remote tablet.
+
+With --caller-id, the tablet first checks its table ACLs for the principal, in the given groups, as its query service
+would: the query is rejected if they deny it and the tablet runs with --queryserver-config-strict-table-acl, unless
+--queryserver-config-enable-table-acl-dry-run is set or the principal is in --queryserver-config-acl-exempt-acl.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(2),
 		RunE:                  commandExecuteFetchAsApp,
@@ -58,9 +64,11 @@ var (
 )
 
 var executeFetchAsAppOptions = struct {
-	MaxRows int64
-	UsePool bool
-	JSON    bool
+	MaxRows      int64
+	UsePool      bool
+	JSON         bool
+	CallerID     string
+	CallerGroups []string
 }{
 	MaxRows: 10_000,
 }
@@ -70,6 +78,16 @@ func commandExecuteFetchAsApp(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if executeFetchAsAppOptions.CallerID == "" && len(executeFetchAsAppOptions.CallerGroups) > 0 {
+		return fmt.Errorf("--caller-groups requires --caller-id")
+	}
+	var callerID *vtrpcpb.CallerID
+	if executeFetchAsAppOptions.CallerID != "" {
+		callerID = &vtrpcpb.CallerID{
+			Principal: executeFetchAsAppOptions.CallerID,
+			Groups:    executeFetchAsAppOptions.CallerGroups,
+		}
+	}
 
 	cli.FinishedParsing(cmd)
 
@@ -80,6 +98,7 @@ func commandExecuteFetchAsApp(cmd *cobra.Command, args []string) error {
 		Query:       query,
 		MaxRows:     executeFetchAsAppOptions.MaxRows,
 		UsePool:     executeFetchAsAppOptions.UsePool,
+		CallerId:    callerID,
 	})
 	if err != nil {
 		return err
@@ -202,6 +221,8 @@ func init() {
 	ExecuteFetchAsApp.Flags().Int64Var(&executeFetchAsAppOptions.MaxRows, "max-rows", 10_000, "The maximum number of rows to fetch from the remote tablet.")
 	ExecuteFetchAsApp.Flags().BoolVar(&executeFetchAsAppOptions.UsePool, "use-pool", false, "Use the tablet connection pool instead of creating a fresh connection.")
 	ExecuteFetchAsApp.Flags().BoolVarP(&executeFetchAsAppOptions.JSON, "json", "j", false, "Output the results in JSON instead of a human-readable table.")
+	ExecuteFetchAsApp.Flags().StringVar(&executeFetchAsAppOptions.CallerID, "caller-id", "", "Principal the tablet checks its table ACLs for before running the query.")
+	ExecuteFetchAsApp.Flags().StringSliceVar(&executeFetchAsAppOptions.CallerGroups, "caller-groups", nil, "Groups of the --caller-id principal.")
 	Root.AddCommand(ExecuteFetchAsApp)
 
 	ExecuteFetchAsDBA.Flags().Int64Var(&executeFetchAsDBAOptions.MaxRows, "max-rows", 10_000, "The maximum number of rows to fetch from the remote tablet.")
@@ -201,6 +201,12 @@ func getClientForCommand(cmd *cobra.Command) (vtctldclient.VtctldClient, error)
 		return nil, nil
 	}
 
+	return newClient(cmd)
+}
+
+// newClient returns a vtctldclient.VtctldClient, for commands that skip client
+// creation but need a client for some of their flags.
+func newClient(cmd *cobra.Command) (vtctldclient.VtctldClient, error) {
 	if VtctldClientProtocol != "local" && server == "" {
 		return nil, errNoServer
 	}
@@ -31,6 +31,7 @@ import (
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/vtctl/localvtctldclient"
 
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
 )
 
@@ -116,3 +117,31 @@ func TestRootWithInternalVtctld(t *testing.T) {
 		})
 	}
 }
+
+// TestGenerateShardRangesCreate tests that GenerateShardRanges, which skips
+// client creation, creates the shards with --create.
+func TestGenerateShardRangesCreate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	ts, factory := memorytopo.NewServerAndFactory(ctx, "zone1")
+	topo.RegisterFactory("test-generate-shard-ranges", factory)
+	origProtocol := command.VtctldClientProtocol
+	command.VtctldClientProtocol = "local"
+
+	args := append([]string{}, os.Args...)
+	t.Cleanup(func() {
+		ts.Close()
+		os.Args = append([]string{}, args...)
+		command.VtctldClientProtocol = origProtocol
+	})
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+
+	os.Args = []string{"vtctldclient", "--server", "internal", "--topo-implementation", "test-generate-shard-ranges",
+		"GenerateShardRanges", "--create", "ks", "4"}
+	require.NoError(t, command.Root.Execute())
+
+	shards, err := ts.GetShardNames(ctx, "ks")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"-40", "40-80", "80-c0", "c0-"}, shards)
+}
@@ -31,6 +31,7 @@ import (
 	"vitess.io/vitess/go/vt/schema"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+	"vitess.io/vitess/go/vt/vtctl/schematools"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
@@ -40,7 +41,7 @@ import (
 var (
 	// ApplySchema makes an ApplySchema gRPC call to a vtctld.
 	ApplySchema = &cobra.Command{
-		Use:   "ApplySchema [--ddl-strategy <strategy>] [--uuid <uuid> ...] [--migration-context <context>] [--wait-replicas-timeout <duration>] [--caller-id <caller_id>] {--sql-file <file> | --sql <sql>} <keyspace>",
+		Use:   "ApplySchema [--ddl-strategy <strategy>] [--uuid <uuid> ...] [--migration-context <context>] [--wait-replicas-timeout <duration>] [--caller-id <caller_id>] [--respect-throttler [--throttler-app-name <app>] [--max-throttle-wait <duration>]] {--sql-file <file> | --sql <sql>} <keyspace>",
 		Short: "Applies the schema change to the specified keyspace on every primary, running in parallel on all shards. The changes are then propagated to replicas via replication.",
 		Long: `Applies the schema change to the specified keyspace on every primary, running in parallel on all shards. The changes are then propagated to replicas via replication.
 
@@ -48,6 +49,7 @@ If --allow-long-unavailability is set, schema changes affecting a large number o
 --ddl-strategy is used to instruct migrations via vreplication, mysql or direct with optional parameters.
 --migration-context allows the user to specify a custom migration context for online DDL migrations.
 If --skip-preflight, SQL goes directly to shards without going through sanity checks.
+If --respect-throttler is set, direct schema changes wait before each statement (or batch) while the throttler of the primary throttles --throttler-app-name, and fail once they have waited --max-throttle-wait in total.
 
 The --uuid and --sql flags are repeatable, so they can be passed multiple times to build a list of values.
 For --uuid, this is used like "--uuid $first_uuid --uuid $second_uuid".
@@ -60,7 +62,7 @@ For --sql, semi-colons and repeated values may be mixed, for example:
 		RunE:                  commandApplySchema,
 	}
 	CopySchemaShard = &cobra.Command{
-		Use:                   "CopySchemaShard [--tables=<table1>,<table2>,...] [--exclude-tables=<table1>,<table2>,...] [--include-views] [--skip-verify] [--wait-replicas-timeout=10s] {<source keyspace/shard> || <source tablet alias>} <destination keyspace/shard>",
+		Use:                   "CopySchemaShard [--tables=<table1>,<table2>,...] [--exclude-tables=<table1>,<table2>,...] [--include-views] [--skip-verify] [--wait-replicas-timeout=10s] [--respect-throttler [--throttler-app-name <app>] [--max-throttle-wait <duration>]] {<source keyspace/shard> || <source tablet alias>} <destination keyspace/shard>",
 		Short:                 "Copies the schema from a source shard's primary (or a specific tablet) to a destination shard. The schema is applied directly on the primary of the destination shard, and it is propagated to the replicas through binlogs.",
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(2),
@@ -74,6 +76,14 @@ For --sql, semi-colons and repeated values may be mixed, for example:
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandGetSchema,
 	}
+	// GetSchemaChangeHistory makes a GetSchemaChangeHistory gRPC call to a vtctld.
+	GetSchemaChangeHistory = &cobra.Command{
+		Use:                   "GetSchemaChangeHistory [--limit <limit>] <keyspace>",
+		Short:                 "Displays the schema changes applied to a keyspace with ApplySchema, from the most recent one, with the caller who requested them. Requires vtctld to run with --schema_change_journal.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandGetSchemaChangeHistory,
+	}
 	// ReloadSchema makes a ReloadSchema gRPC call to a vtctld.
 	ReloadSchema = &cobra.Command{
 		Use:                   "ReloadSchema <tablet_alias>",
@@ -100,8 +110,8 @@ For --sql, semi-colons and repeated values may be mixed, for example:
 	}
 	// ValidateSchemaKeyspace makes a ValidateSchemaKeyspace gRPC call to a vtctld.
 	ValidateSchemaKeyspace = &cobra.Command{
-		Use:                   "ValidateSchemaKeyspace [--exclude-tables=<exclude_tables>] [--include-views] [--skip-no-primary] [--include-vschema] <keyspace>",
-		Short:                 "Validates that the schema on the primary tablet for the first shard matches the schema on all other tablets in the keyspace.",
+		Use:                   "ValidateSchemaKeyspace [--exclude-tables=<exclude_tables>] [--include-views] [--skip-no-primary] [--include-vschema] [--include-non-serving] [--include-non-serving-shards] [--reference-shard=<shard> | --reference-tablet=<tablet_alias>] <keyspace>",
+		Short:                 "Validates that the schema on the reference tablet matches the schema on all other tablets in the keyspace.",
 		DisableFlagsInUseLine: true,
 		Aliases:               []string{"validateschemakeyspace"},
 		Args:                  cobra.ExactArgs(1),
@@ -110,7 +120,7 @@ For --sql, semi-colons and repeated values may be mixed, for example:
 	// ValidateSchemaShard makes a ValidateSchemaKeyspace gRPC call to a vtctld with
 	// the specified shard to examine in the keyspace.
 	ValidateSchemaShard = &cobra.Command{
-		Use:                   "ValidateSchemaShard [--exclude-tables=<exclude_tables>] [--include-views] [--skip-no-primary] [--include-vschema] <keyspace/shard>",
+		Use:                   "ValidateSchemaShard [--exclude-tables=<exclude_tables>] [--include-views] [--skip-no-primary] [--include-vschema] [--include-non-serving] <keyspace/shard>",
 		Short:                 "Validates that the schema on the primary tablet for the specified shard matches the schema on all other tablets in that shard.",
 		DisableFlagsInUseLine: true,
 		Aliases:               []string{"validateschemashard"},
@@ -130,6 +140,9 @@ var applySchemaOptions = struct {
 	SkipPreflight           bool
 	CallerID                string
 	BatchSize               int64
+	RespectThrottler        bool
+	ThrottlerAppName        string
+	MaxThrottleWait         time.Duration
 }{}
 
 func commandApplySchema(cmd *cobra.Command, args []string) error {
@@ -172,6 +185,9 @@ func commandApplySchema(cmd *cobra.Command, args []string) error {
 		WaitReplicasTimeout: protoutil.DurationToProto(applySchemaOptions.WaitReplicasTimeout),
 		CallerId:            cid,
 		BatchSize:           applySchemaOptions.BatchSize,
+		RespectThrottler:    applySchemaOptions.RespectThrottler,
+		ThrottlerAppName:    applySchemaOptions.ThrottlerAppName,
+		MaxThrottleWait:     protoutil.DurationToProto(applySchemaOptions.MaxThrottleWait),
 	})
 	if err != nil {
 		return err
@@ -187,6 +203,9 @@ var copySchemaShardOptions = struct {
 	includeViews        bool
 	skipVerify          bool
 	waitReplicasTimeout time.Duration
+	respectThrottler    bool
+	throttlerAppName    string
+	maxThrottleWait     time.Duration
 }{}
 
 func commandCopySchemaShard(cmd *cobra.Command, args []string) error {
@@ -229,6 +248,9 @@ func commandCopySchemaShard(cmd *cobra.Command, args []string) error {
 		WaitReplicasTimeout: protoutil.DurationToProto(copySchemaShardOptions.waitReplicasTimeout),
 		DestinationKeyspace: destKeyspace,
 		DestinationShard:    destShard,
+		RespectThrottler:    copySchemaShardOptions.respectThrottler,
+		ThrottlerAppName:    copySchemaShardOptions.throttlerAppName,
+		MaxThrottleWait:     protoutil.DurationToProto(copySchemaShardOptions.maxThrottleWait),
 	}
 
 	_, err = client.CopySchemaShard(commandCtx, req)
@@ -292,6 +314,31 @@ func commandGetSchema(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var getSchemaChangeHistoryOptions = struct {
+	Limit uint32
+}{}
+
+func commandGetSchemaChangeHistory(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetSchemaChangeHistory(commandCtx, &vtctldatapb.GetSchemaChangeHistoryRequest{
+		Keyspace: cmd.Flags().Arg(0),
+		Limit:    getSchemaChangeHistoryOptions.Limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.Changes)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
 func commandReloadSchema(cmd *cobra.Command, args []string) error {
 	tabletAlias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
@@ -367,29 +414,63 @@ func commandReloadSchemaShard(cmd *cobra.Command, args []string) error {
 }
 
 var validateSchemaKeyspaceOptions = struct {
-	ExcludeTables  []string
-	IncludeViews   bool
-	SkipNoPrimary  bool
-	IncludeVSchema bool
-	Shard          string
+	ExcludeTables           []string
+	IncludeViews            bool
+	SkipNoPrimary           bool
+	IncludeVSchema          bool
+	IncludeNonServing       bool
+	IncludeNonServingShards bool
+	Shard                   string
+	ReferenceShard          string
+	ReferenceTablet         string
+	Stream                  bool
 }{}
 
 func commandValidateSchemaKeyspace(cmd *cobra.Command, args []string) error {
+	req := &vtctldatapb.ValidateSchemaKeyspaceRequest{
+		Keyspace:                cmd.Flags().Arg(0),
+		ExcludeTables:           validateSchemaKeyspaceOptions.ExcludeTables,
+		IncludeVschema:          validateSchemaKeyspaceOptions.IncludeVSchema,
+		SkipNoPrimary:           validateSchemaKeyspaceOptions.SkipNoPrimary,
+		IncludeViews:            validateSchemaKeyspaceOptions.IncludeViews,
+		IncludeNonServing:       validateSchemaKeyspaceOptions.IncludeNonServing,
+		ReferenceShard:          validateSchemaKeyspaceOptions.ReferenceShard,
+		IncludeNonServingShards: validateSchemaKeyspaceOptions.IncludeNonServingShards,
+	}
+	if validateSchemaKeyspaceOptions.ReferenceTablet != "" {
+		var err error
+		req.ReferenceTablet, err = topoproto.ParseTabletAlias(validateSchemaKeyspaceOptions.ReferenceTablet)
+		if err != nil {
+			return err
+		}
+	}
+
 	cli.FinishedParsing(cmd)
 
-	keyspace := cmd.Flags().Arg(0)
-	resp, err := client.ValidateSchemaKeyspace(commandCtx, &vtctldatapb.ValidateSchemaKeyspaceRequest{
-		Keyspace:       keyspace,
-		ExcludeTables:  validateSchemaKeyspaceOptions.ExcludeTables,
-		IncludeVschema: validateSchemaKeyspaceOptions.IncludeVSchema,
-		SkipNoPrimary:  validateSchemaKeyspaceOptions.SkipNoPrimary,
-		IncludeViews:   validateSchemaKeyspaceOptions.IncludeViews,
-	})
+	if validateSchemaKeyspaceOptions.Stream {
+		stream, err := client.ValidateSchemaKeyspaceStream(commandCtx, req)
+		if err != nil {
+			return err
+		}
+		_, err = printValidationStream(stream)
+		return err
+	}
 
+	resp, err := client.ValidateSchemaKeyspace(commandCtx, req)
 	if err != nil {
 		return err
 	}
 
+	printSkippedTablets(resp.SkippedTablets)
+	if len(resp.NonServingShards) > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped non-serving shards: %s\n", strings.Join(resp.NonServingShards, ","))
+	}
+	if resp.ReferenceReason != "" {
+		fmt.Fprintf(os.Stderr, "Reference: %s\n", resp.ReferenceReason)
+	}
+	if len(resp.OutlierShards) > 0 {
+		fmt.Fprintf(os.Stderr, "Outlier shards: %s\n", strings.Join(resp.OutlierShards, ","))
+	}
 	data, err := cli.MarshalJSON(resp.ResultsByShard)
 	if err != nil {
 		return err
@@ -408,18 +489,22 @@ func commandValidateSchemaShard(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	resp, err := client.ValidateSchemaKeyspace(commandCtx, &vtctldatapb.ValidateSchemaKeyspaceRequest{
-		Keyspace:       keyspace,
-		Shards:         []string{shard},
-		ExcludeTables:  validateSchemaKeyspaceOptions.ExcludeTables,
-		IncludeVschema: validateSchemaKeyspaceOptions.IncludeVSchema,
-		SkipNoPrimary:  validateSchemaKeyspaceOptions.SkipNoPrimary,
-		IncludeViews:   validateSchemaKeyspaceOptions.IncludeViews,
+		Keyspace:          keyspace,
+		Shards:            []string{shard},
+		ExcludeTables:     validateSchemaKeyspaceOptions.ExcludeTables,
+		IncludeVschema:    validateSchemaKeyspaceOptions.IncludeVSchema,
+		SkipNoPrimary:     validateSchemaKeyspaceOptions.SkipNoPrimary,
+		IncludeViews:      validateSchemaKeyspaceOptions.IncludeViews,
+		IncludeNonServing: validateSchemaKeyspaceOptions.IncludeNonServing,
+		// The requested shard is validated even if it serves no traffic.
+		IncludeNonServingShards: true,
 	})
 
 	if err != nil {
 		return err
 	}
 
+	printSkippedTablets(resp.SkippedTablets)
 	data, err := cli.MarshalJSON(resp.Results)
 	if err != nil {
 		return err
@@ -438,6 +523,9 @@ func init() {
 	ApplySchema.Flags().StringArrayVar(&applySchemaOptions.SQL, "sql", nil, "Semicolon-delimited, repeatable SQL commands to apply. Exactly one of --sql|--sql-file is required.")
 	ApplySchema.Flags().StringVar(&applySchemaOptions.SQLFile, "sql-file", "", "Path to a file containing semicolon-delimited SQL commands to apply. Exactly one of --sql|--sql-file is required.")
 	ApplySchema.Flags().Int64Var(&applySchemaOptions.BatchSize, "batch-size", 0, "How many queries to batch together. Only applicable when all queries are CREATE TABLE|VIEW")
+	ApplySchema.Flags().BoolVar(&applySchemaOptions.RespectThrottler, "respect-throttler", false, "Wait while the throttler of each primary throttles the schema change before applying each statement (or batch). Only applicable to the direct strategy.")
+	ApplySchema.Flags().StringVar(&applySchemaOptions.ThrottlerAppName, "throttler-app-name", "", "The app name to check the throttler as, with --respect-throttler. Defaults to \"direct-ddl\".")
+	ApplySchema.Flags().DurationVar(&applySchemaOptions.MaxThrottleWait, "max-throttle-wait", schematools.DefaultMaxThrottleWait, "The maximum total time to wait for the throttler of a primary, with --respect-throttler, before failing the schema change.")
 	Root.AddCommand(ApplySchema)
 
 	CopySchemaShard.Flags().StringSliceVar(&copySchemaShardOptions.tables, "tables", nil, "Specifies a comma-separated list of tables to copy. Each is either an exact match, or a regular expression of the form /regexp/")
@@ -445,6 +533,9 @@ func init() {
 	CopySchemaShard.Flags().BoolVar(&copySchemaShardOptions.includeViews, "include-views", true, "Includes views in the output")
 	CopySchemaShard.Flags().BoolVar(&copySchemaShardOptions.skipVerify, "skip-verify", false, "Skip verification of source and target schema after copy")
 	CopySchemaShard.Flags().DurationVar(&copySchemaShardOptions.waitReplicasTimeout, "wait-replicas-timeout", grpcvtctldserver.DefaultWaitReplicasTimeout, "The amount of time to wait for replicas to receive the schema change via replication.")
+	CopySchemaShard.Flags().BoolVar(&copySchemaShardOptions.respectThrottler, "respect-throttler", false, "Wait while the throttler of the destination primary throttles the copy before applying each statement.")
+	CopySchemaShard.Flags().StringVar(&copySchemaShardOptions.throttlerAppName, "throttler-app-name", "", "The app name to check the throttler as, with --respect-throttler. Defaults to \"direct-ddl\".")
+	CopySchemaShard.Flags().DurationVar(&copySchemaShardOptions.maxThrottleWait, "max-throttle-wait", schematools.DefaultMaxThrottleWait, "The maximum total time to wait for the throttler of the destination primary, with --respect-throttler, before failing the copy.")
 	Root.AddCommand(CopySchemaShard)
 
 	GetSchema.Flags().StringSliceVar(&getSchemaOptions.Tables, "tables", nil, "List of tables to display the schema for. Each is either an exact match, or a regular expression of the form `/regexp/`.")
@@ -455,6 +546,9 @@ func init() {
 	GetSchema.Flags().BoolVarP(&getSchemaOptions.TableSchemaOnly, "table-schema-only", "", false, "Skip introspecting columns and fields metadata.")
 	Root.AddCommand(GetSchema)
 
+	GetSchemaChangeHistory.Flags().Uint32Var(&getSchemaChangeHistoryOptions.Limit, "limit", 0, "The maximum number of schema changes to display, from the most recent one. Zero displays them all.")
+	Root.AddCommand(GetSchemaChangeHistory)
+
 	Root.AddCommand(ReloadSchema)
 
 	ReloadSchemaKeyspace.Flags().Int32Var(&reloadSchemaKeyspaceOptions.Concurrency, "concurrency", 10, "Number of tablets to reload in parallel. Set to zero for unbounded concurrency.")
@@ -469,11 +563,17 @@ func init() {
 	ValidateSchemaKeyspace.Flags().BoolVar(&validateSchemaKeyspaceOptions.IncludeVSchema, "include-vschema", false, "Includes VSchema validation in validation results.")
 	ValidateSchemaKeyspace.Flags().BoolVar(&validateSchemaKeyspaceOptions.SkipNoPrimary, "skip-no-primary", false, "Skips validation on whether or not a primary exists in shards.")
 	ValidateSchemaKeyspace.Flags().StringSliceVar(&validateSchemaKeyspaceOptions.ExcludeTables, "exclude-tables", []string{}, "Tables to exclude during schema comparison.")
+	ValidateSchemaKeyspace.Flags().BoolVar(&validateSchemaKeyspaceOptions.IncludeNonServing, "include-non-serving", false, "Also validates the DRAINED, BACKUP and RESTORE tablets, which are skipped by default.")
+	ValidateSchemaKeyspace.Flags().BoolVar(&validateSchemaKeyspaceOptions.IncludeNonServingShards, "include-non-serving-shards", false, "Also validates the shards that serve no traffic, such as the overlapping shards of a reshard, which are skipped by default.")
+	ValidateSchemaKeyspace.Flags().StringVar(&validateSchemaKeyspaceOptions.ReferenceShard, "reference-shard", "", "Optional shard whose primary's schema the others are compared with. Defaults to a shard with the schema most shard primaries agree on.")
+	ValidateSchemaKeyspace.Flags().StringVar(&validateSchemaKeyspaceOptions.ReferenceTablet, "reference-tablet", "", "Optional tablet whose schema the others are compared with, instead of a shard primary.")
+	ValidateSchemaKeyspace.Flags().BoolVar(&validateSchemaKeyspaceOptions.Stream, "stream", false, "Prints each finding as soon as it is found, and the progress of the validation periodically.")
 	Root.AddCommand(ValidateSchemaKeyspace)
 
 	ValidateSchemaShard.Flags().BoolVar(&validateSchemaKeyspaceOptions.IncludeViews, "include-views", false, "Includes views in compared schemas.")
 	ValidateSchemaShard.Flags().BoolVar(&validateSchemaKeyspaceOptions.IncludeVSchema, "include-vschema", false, "Includes VSchema validation in validation results.")
 	ValidateSchemaShard.Flags().BoolVar(&validateSchemaKeyspaceOptions.SkipNoPrimary, "skip-no-primary", false, "Skips validation on whether or not a primary exists in shards.")
 	ValidateSchemaShard.Flags().StringSliceVar(&validateSchemaKeyspaceOptions.ExcludeTables, "exclude-tables", []string{}, "Tables to exclude during schema comparison.")
+	ValidateSchemaShard.Flags().BoolVar(&validateSchemaKeyspaceOptions.IncludeNonServing, "include-non-serving", false, "Also validates the DRAINED, BACKUP and RESTORE tablets, which are skipped by default.")
 	Root.AddCommand(ValidateSchemaShard)
 }
@@ -18,19 +18,28 @@ package command
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/vt/topotools"
 
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 var (
 	// DeleteSrvVSchema makes a DeleteSrvVSchema gRPC call to a vtctld.
 	DeleteSrvVSchema = &cobra.Command{
-		Use:                   "DeleteSrvVSchema <cell>",
-		Short:                 "Deletes the SrvVSchema object in the given cell.",
+		Use:   "DeleteSrvVSchema [--force] <cell>",
+		Short: "Deletes the SrvVSchema object in the given cell.",
+		Long: `Deletes the SrvVSchema object in the given cell.
+
+vtgates in the cell can no longer route queries without the SrvVSchema, so the command first reports the keyspaces it covers and the keyspaces served in the cell, and requires --force to delete it.
+vtgates don't register themselves in the topo, so keyspaces served in the cell are the best indication of vtgates relying on the SrvVSchema.
+RestoreSrvVSchema rebuilds a deleted SrvVSchema.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandDeleteSrvVSchema,
@@ -45,16 +54,23 @@ var (
 	}
 	// GetSrvKeyspaces makes a GetSrvKeyspaces gRPC call to a vtctld.
 	GetSrvKeyspaces = &cobra.Command{
-		Use:                   "GetSrvKeyspaces <keyspace> [<cell> ...]",
-		Short:                 "Returns the SrvKeyspaces for the given keyspace in one or more cells.",
+		Use:   "GetSrvKeyspaces [--visualize] <keyspace> [<cell> ...]",
+		Short: "Returns the SrvKeyspaces for the given keyspace in one or more cells.",
+		Long: `Returns the SrvKeyspaces for the given keyspace in one or more cells.
+
+If --visualize is set, shows instead, for each cell and served tablet type, the key range segments in order and the shards serving each, flagging gaps and overlaps.
+This is meant to make sense of the partitions in the middle of a reshard.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.MinimumNArgs(1),
 		RunE:                  commandGetSrvKeyspaces,
 	}
 	// GetSrvVSchema makes a GetSrvVSchema gRPC call to a vtctld.
 	GetSrvVSchema = &cobra.Command{
-		Use:                   "GetSrvVSchema cell",
-		Short:                 "Returns the SrvVSchema for the given cell.",
+		Use:   "GetSrvVSchema [--diff-against-cell <cell>] cell",
+		Short: "Returns the SrvVSchema for the given cell.",
+		Long: `Returns the SrvVSchema for the given cell.
+
+If --diff-against-cell is set, shows instead the differences between the SrvVSchemas of the two cells, if any.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandGetSrvVSchema,
@@ -69,7 +85,7 @@ var (
 	}
 	// RebuildKeyspaceGraph makes one or more RebuildKeyspaceGraph gRPC calls to a vtctld.
 	RebuildKeyspaceGraph = &cobra.Command{
-		Use:                   "RebuildKeyspaceGraph [--cells=c1,c2,...] [--allow-partial] ks1 [ks2 ...]",
+		Use:                   "RebuildKeyspaceGraph [--cells=c1,c2,...] [--allow-partial] [--force-write] ks1 [ks2 ...]",
 		Short:                 "Rebuilds the serving data for the keyspace(s). This command may trigger an update to all connected clients.",
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.MinimumNArgs(1),
@@ -83,17 +99,59 @@ var (
 		Args:                  cobra.NoArgs,
 		RunE:                  commandRebuildVSchemaGraph,
 	}
+	// RestoreSrvVSchema makes a RebuildVSchemaGraph gRPC call to a vtctld for a single cell.
+	RestoreSrvVSchema = &cobra.Command{
+		Use:                   "RestoreSrvVSchema <cell>",
+		Short:                 "Rebuilds the SrvVSchema in the given cell from the global VSchema objects, for instance after it was deleted.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandRestoreSrvVSchema,
+	}
 )
 
+var deleteSrvVSchemaOptions = struct {
+	Force bool
+}{}
+
 func commandDeleteSrvVSchema(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	cell := cmd.Flags().Arg(0)
-	_, err := client.DeleteSrvVSchema(commandCtx, &vtctldatapb.DeleteSrvVSchemaRequest{
-		Cell: cell,
+	resp, err := client.DeleteSrvVSchema(commandCtx, &vtctldatapb.DeleteSrvVSchemaRequest{
+		Cell:   cell,
+		DryRun: !deleteSrvVSchemaOptions.Force,
 	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(formatDeleteSrvVSchemaPreflight(cell, resp))
+	if !resp.Deleted {
+		return fmt.Errorf("not deleting the SrvVSchema in cell %s without --force", cell)
+	}
+
+	fmt.Printf("Deleted the SrvVSchema in cell %s. Run RestoreSrvVSchema %s to rebuild it.\n", cell, cell)
+	return nil
+}
+
+// formatDeleteSrvVSchemaPreflight describes what deleting the SrvVSchema of
+// the cell affects.
+func formatDeleteSrvVSchemaPreflight(cell string, resp *vtctldatapb.DeleteSrvVSchemaResponse) string {
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "The SrvVSchema in cell %s covers %d keyspace(s)", cell, len(resp.Keyspaces))
+	if len(resp.Keyspaces) > 0 {
+		fmt.Fprintf(&sb, ": %s", strings.Join(resp.Keyspaces, ", "))
+	}
+	sb.WriteString("\n")
+
+	if len(resp.ServingKeyspaces) == 0 {
+		fmt.Fprintf(&sb, "No keyspace is served in cell %s, so no vtgate should rely on the SrvVSchema.\n", cell)
+	} else {
+		fmt.Fprintf(&sb, "%d keyspace(s) are served in cell %s: %s. vtgates in the cell rely on the SrvVSchema to route queries to them.\n",
+			len(resp.ServingKeyspaces), cell, strings.Join(resp.ServingKeyspaces, ", "))
+	}
 
-	return err
+	return sb.String()
 }
 
 func commandGetSrvKeyspaceNames(cmd *cobra.Command, args []string) error {
@@ -116,6 +174,10 @@ func commandGetSrvKeyspaceNames(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var getSrvKeyspacesOptions = struct {
+	Visualize bool
+}{}
+
 func commandGetSrvKeyspaces(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
@@ -130,6 +192,11 @@ func commandGetSrvKeyspaces(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if getSrvKeyspacesOptions.Visualize {
+		fmt.Print(formatSrvKeyspacesPartitions(resp.SrvKeyspaces))
+		return nil
+	}
+
 	data, err := cli.MarshalJSON(resp.SrvKeyspaces)
 	if err != nil {
 		return err
@@ -140,6 +207,33 @@ func commandGetSrvKeyspaces(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// formatSrvKeyspacesPartitions renders the partitions of the SrvKeyspace of
+// each cell, in cell order.
+func formatSrvKeyspacesPartitions(srvKeyspaces map[string]*topodatapb.SrvKeyspace) string {
+	cells := make([]string, 0, len(srvKeyspaces))
+	for cell := range srvKeyspaces {
+		cells = append(cells, cell)
+	}
+	sort.Strings(cells)
+
+	sb := strings.Builder{}
+	for _, cell := range cells {
+		fmt.Fprintf(&sb, "%s:\n", cell)
+		partitions := topotools.FormatSrvKeyspacePartitions(srvKeyspaces[cell])
+		for _, line := range strings.SplitAfter(partitions, "\n") {
+			if line != "" {
+				sb.WriteString("  " + line)
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+var getSrvVSchemaOptions = struct {
+	DiffAgainstCell string
+}{}
+
 func commandGetSrvVSchema(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
@@ -152,6 +246,25 @@ func commandGetSrvVSchema(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if otherCell := getSrvVSchemaOptions.DiffAgainstCell; otherCell != "" {
+		otherResp, err := client.GetSrvVSchema(commandCtx, &vtctldatapb.GetSrvVSchemaRequest{
+			Cell: otherCell,
+		})
+		if err != nil {
+			return err
+		}
+
+		diffs := topotools.DiffSrvVSchemas(cell, resp.SrvVSchema, otherCell, otherResp.SrvVSchema)
+		if len(diffs) == 0 {
+			fmt.Printf("The SrvVSchemas in cells %s and %s are identical.\n", cell, otherCell)
+			return nil
+		}
+		for _, diff := range diffs {
+			fmt.Println(diff)
+		}
+		return nil
+	}
+
 	data, err := cli.MarshalJSON(resp.SrvVSchema)
 	if err != nil {
 		return err
@@ -192,6 +305,7 @@ func commandGetSrvVSchemas(cmd *cobra.Command, args []string) error {
 var rebuildKeyspaceGraphOptions = struct {
 	Cells        []string
 	AllowPartial bool
+	ForceWrite   bool
 }{}
 
 func commandRebuildKeyspaceGraph(cmd *cobra.Command, args []string) error {
@@ -203,6 +317,7 @@ func commandRebuildKeyspaceGraph(cmd *cobra.Command, args []string) error {
 			Keyspace:     ks,
 			Cells:        rebuildKeyspaceGraphOptions.Cells,
 			AllowPartial: rebuildKeyspaceGraphOptions.AllowPartial,
+			ForceWrite:   rebuildKeyspaceGraphOptions.ForceWrite,
 		})
 		if err != nil {
 			return fmt.Errorf("RebuildKeyspaceGraph(%v) failed: %v", ks, err)
@@ -231,18 +346,47 @@ func commandRebuildVSchemaGraph(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func commandRestoreSrvVSchema(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	cell := cmd.Flags().Arg(0)
+	_, err := client.RebuildVSchemaGraph(commandCtx, &vtctldatapb.RebuildVSchemaGraphRequest{
+		Cells: []string{cell},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.GetSrvVSchema(commandCtx, &vtctldatapb.GetSrvVSchemaRequest{
+		Cell: cell,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restored the SrvVSchema in cell %s, covering %d keyspace(s).\n", cell, len(resp.SrvVSchema.GetKeyspaces()))
+
+	return nil
+}
+
 func init() {
+	DeleteSrvVSchema.Flags().BoolVarP(&deleteSrvVSchemaOptions.Force, "force", "f", false, "Delete the SrvVSchema. Without it, only reports what the SrvVSchema covers.")
 	Root.AddCommand(DeleteSrvVSchema)
 
 	Root.AddCommand(GetSrvKeyspaceNames)
+	GetSrvKeyspaces.Flags().BoolVar(&getSrvKeyspacesOptions.Visualize, "visualize", false, "Show the key range segments of each served tablet type and the shards serving them, flagging gaps and overlaps, instead of the SrvKeyspaces.")
 	Root.AddCommand(GetSrvKeyspaces)
+	GetSrvVSchema.Flags().StringVar(&getSrvVSchemaOptions.DiffAgainstCell, "diff-against-cell", "", "Show the differences between the SrvVSchema of the cell and the one of this cell, instead of the SrvVSchema.")
 	Root.AddCommand(GetSrvVSchema)
 	Root.AddCommand(GetSrvVSchemas)
 
 	RebuildKeyspaceGraph.Flags().StringSliceVarP(&rebuildKeyspaceGraphOptions.Cells, "cells", "c", nil, "Specifies a comma-separated list of cells to update.")
 	RebuildKeyspaceGraph.Flags().BoolVar(&rebuildKeyspaceGraphOptions.AllowPartial, "allow-partial", false, "Specifies whether a SNAPSHOT keyspace is allowed to serve with an incomplete set of shards. Ignored for all other types of keyspaces.")
+	RebuildKeyspaceGraph.Flags().BoolVar(&rebuildKeyspaceGraphOptions.ForceWrite, "force-write", false, "Writes the serving data even if it is unchanged, which triggers an update to all connected clients.")
 	Root.AddCommand(RebuildKeyspaceGraph)
 
 	RebuildVSchemaGraph.Flags().StringSliceVarP(&rebuildVSchemaGraphOptions.Cells, "cells", "c", nil, "Specifies a comma-separated list of cells to look for tablets.")
 	Root.AddCommand(RebuildVSchemaGraph)
+
+	Root.AddCommand(RestoreSrvVSchema)
 }
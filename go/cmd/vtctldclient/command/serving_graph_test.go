@@ -0,0 +1,41 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestFormatDeleteSrvVSchemaPreflight(t *testing.T) {
+	resp := &vtctldatapb.DeleteSrvVSchemaResponse{
+		Keyspaces:        []string{"ks1", "ks2"},
+		ServingKeyspaces: []string{"ks1"},
+	}
+	want := `The SrvVSchema in cell zone1 covers 2 keyspace(s): ks1, ks2
+1 keyspace(s) are served in cell zone1: ks1. vtgates in the cell rely on the SrvVSchema to route queries to them.
+`
+	assert.Equal(t, want, formatDeleteSrvVSchemaPreflight("zone1", resp))
+
+	want = `The SrvVSchema in cell zone2 covers 0 keyspace(s)
+No keyspace is served in cell zone2, so no vtgate should rely on the SrvVSchema.
+`
+	assert.Equal(t, want, formatDeleteSrvVSchemaPreflight("zone2", &vtctldatapb.DeleteSrvVSchemaResponse{}))
+}
@@ -18,7 +18,9 @@ package command
 
 import (
 	"fmt"
+	"os"
 	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -56,31 +58,16 @@ that shard.`,
 	// GenerateShardRanges outputs a set of shard ranges assuming a (mostly)
 	// equal distribution of N shards.
 	GenerateShardRanges = &cobra.Command{
-		Use:                   "GenerateShardRanges <num_shards>",
-		Short:                 "Print a set of shard ranges assuming a keyspace with N shards.",
+		Use:   "GenerateShardRanges [--key-width=<bytes>] [--allow-uneven] [--format=<format>] [--create=<keyspace>] <num_shards>",
+		Short: "Print a set of shard ranges assuming a keyspace with N shards.",
+		Long: `Print a set of shard ranges assuming a keyspace with N shards, as a JSON list of shard names, or with --format=text as a comma-separated list, e.g. "-40,40-80,80-c0,c0-".
+
+The shard count must be a power of two, so that all the shards cover ranges of the same size, unless --allow-uneven is passed.
+The boundaries are one byte wide for up to 256 shards and two bytes wide for more, unless --key-width is passed.
+With --create, also creates the shards in the given keyspace, which requires --server.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			n, err := strconv.Atoi(cmd.Flags().Arg(0))
-			if err != nil {
-				return err
-			}
-
-			cli.FinishedParsing(cmd)
-
-			shards, err := key.GenerateShardRanges(n)
-			if err != nil {
-				return err
-			}
-
-			data, err := cli.MarshalJSON(shards)
-			if err != nil {
-				return err
-			}
-
-			fmt.Printf("%s\n", data)
-			return nil
-		},
+		RunE:                  commandGenerateShardRanges,
 		Annotations: map[string]string{
 			skipClientCreationKey: "true",
 		},
@@ -153,7 +140,7 @@ useful after a MoveTables has finished to remove serving restrictions.`,
 	// ShardReplicationFix makes a ShardReplicationFix gRPC request to a vtctld.
 	ShardReplicationFix = &cobra.Command{
 		Use:                   "ShardReplicationFix <cell> <keyspace/shard>",
-		Short:                 "Walks through a ShardReplication object and fixes the first error encountered.",
+		Short:                 "Fixes the ShardReplication object of a shard in a cell: removes the invalid entries and adds the missing tablets.",
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(2),
 		RunE:                  commandShardReplicationFix,
@@ -197,7 +184,7 @@ Use ctrl-C to interrupt the command and see partial results if needed.`,
 
 	// ValidateVersionShard makes a ValidateVersionShard gRPC request to a vtctld.
 	ValidateVersionShard = &cobra.Command{
-		Use:                   "ValidateVersionShard <keyspace/shard>",
+		Use:                   "ValidateVersionShard [--include-non-serving] <keyspace/shard>",
 		Short:                 "Validates that the version on the primary matches all of the replicas.",
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
@@ -238,6 +225,68 @@ func commandCreateShard(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var generateShardRangesOptions = struct {
+	KeyWidth    int
+	AllowUneven bool
+	Format      string
+	Create      string
+}{}
+
+func commandGenerateShardRanges(cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(generateShardRangesOptions.Format)
+	switch format {
+	case "json", "text":
+	default:
+		return fmt.Errorf("invalid output format, got %s", generateShardRangesOptions.Format)
+	}
+
+	n, err := strconv.Atoi(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	shards, err := key.GenerateShardRangesWithWidth(n, generateShardRangesOptions.KeyWidth, generateShardRangesOptions.AllowUneven)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "json":
+		data, err := cli.MarshalJSON(shards)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", data)
+	case "text":
+		fmt.Printf("%s\n", strings.Join(shards, ","))
+	}
+
+	if generateShardRangesOptions.Create == "" {
+		return nil
+	}
+
+	// The command skips client creation, as it only needs a client to
+	// create the shards.
+	client, err = newClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	for _, shard := range shards {
+		if _, err := client.CreateShard(commandCtx, &vtctldatapb.CreateShardRequest{
+			Keyspace:  generateShardRangesOptions.Create,
+			ShardName: shard,
+		}); err != nil {
+			return fmt.Errorf("failed to create shard %s/%s: %w", generateShardRangesOptions.Create, shard, err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Created %d shards in keyspace %s.\n", len(shards), generateShardRangesOptions.Create)
+	return nil
+}
+
 var deleteShardsOptions = struct {
 	Recursive     bool
 	EvenIfServing bool
@@ -468,11 +517,16 @@ func commandShardReplicationFix(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	switch resp.Error {
-	case nil:
+	if len(resp.Added) == 0 && len(resp.Removed) == 0 {
 		fmt.Println("All nodes in the replication graph are valid.")
-	default:
-		fmt.Printf("%s has been fixed for %s.\n", topoproto.ShardReplicationErrorTypeString(resp.Error.Type), topoproto.TabletAliasString(resp.Error.TabletAlias))
+		return nil
+	}
+
+	for _, alias := range resp.Added {
+		fmt.Printf("Added %s.\n", topoproto.TabletAliasString(alias))
+	}
+	for _, problem := range resp.Removed {
+		fmt.Printf("%s has been fixed for %s.\n", topoproto.ShardReplicationErrorTypeString(problem.Type), topoproto.TabletAliasString(problem.TabletAlias))
 	}
 
 	return nil
@@ -626,6 +680,10 @@ func commandSourceShardDelete(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var validateVersionShardOptions = struct {
+	IncludeNonServing bool
+}{}
+
 func commandValidateVersionShard(cmd *cobra.Command, args []string) error {
 	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
@@ -635,8 +693,9 @@ func commandValidateVersionShard(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	resp, err := client.ValidateVersionShard(commandCtx, &vtctldatapb.ValidateVersionShardRequest{
-		Keyspace: keyspace,
-		Shard:    shard,
+		Keyspace:          keyspace,
+		Shard:             shard,
+		IncludeNonServing: validateVersionShardOptions.IncludeNonServing,
 	})
 	if err != nil {
 		return err
@@ -663,6 +722,10 @@ func init() {
 
 	Root.AddCommand(GetShard)
 	Root.AddCommand(GetShardReplication)
+	GenerateShardRanges.Flags().IntVar(&generateShardRangesOptions.KeyWidth, "key-width", 0, "Width of the shard range boundaries, in bytes. Defaults to 1 for up to 256 shards and 2 for more.")
+	GenerateShardRanges.Flags().BoolVar(&generateShardRangesOptions.AllowUneven, "allow-uneven", false, "Allow a shard count that is not a power of two, spreading the remainder of the key space over the shards.")
+	GenerateShardRanges.Flags().StringVar(&generateShardRangesOptions.Format, "format", "json", "Output format to use; valid choices are (json, text).")
+	GenerateShardRanges.Flags().StringVar(&generateShardRangesOptions.Create, "create", "", "Also create the shards in this keyspace, which must already exist.")
 	Root.AddCommand(GenerateShardRanges)
 
 	RemoveShardCell.Flags().BoolVarP(&removeShardCellOptions.Force, "force", "f", false, "Proceed even if the cell's topology server cannot be reached. The assumption is that you turned down the entire cell, and just need to update the global topo data.")
@@ -681,6 +744,7 @@ func init() {
 	Root.AddCommand(ShardReplicationFix)
 	Root.AddCommand(ShardReplicationPositions)
 	Root.AddCommand(ShardReplicationRemove)
+	ValidateVersionShard.Flags().BoolVar(&validateVersionShardOptions.IncludeNonServing, "include-non-serving", false, "Also validates the DRAINED, BACKUP and RESTORE tablets, which are skipped by default.")
 	Root.AddCommand(ValidateVersionShard)
 
 	SourceShardAdd.Flags().StringVar(&sourceShardAddOptions.KeyRangeStr, "key-range", "", "Key range to use for the SourceShard.")
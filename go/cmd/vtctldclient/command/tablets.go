@@ -159,6 +159,14 @@ Valid output formats are "awk" and "json".`, strings.Join(topoproto.MakeUniqueSt
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandRefreshStateByShard,
 	}
+	// ReloadTabletConfig makes a ReloadTabletConfig gRPC call to a vtctld.
+	ReloadTabletConfig = &cobra.Command{
+		Use:                   "ReloadTabletConfig [--category=table_acl] [--concurrency=10] {<keyspace> | <keyspace/shard>}",
+		Short:                 "Reloads a config on all tablets in a keyspace or shard, and flags the tablets whose config differs from the majority.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandReloadTabletConfig,
+	}
 	// RunHealthCheck makes a RunHealthCheck gRPC call to a vtctld.
 	RunHealthCheck = &cobra.Command{
 		Use:                   "RunHealthCheck <tablet_alias>",
@@ -560,6 +568,61 @@ func commandRefreshStateByShard(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var reloadTabletConfigOptions = struct {
+	Category    string
+	Concurrency int32
+}{}
+
+func commandReloadTabletConfig(cmd *cobra.Command, args []string) error {
+	keyspace, shard := cmd.Flags().Arg(0), ""
+	if strings.Contains(keyspace, "/") {
+		var err error
+		keyspace, shard, err = topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+		if err != nil {
+			return err
+		}
+	}
+
+	category, ok := tabletmanagerdatapb.TabletConfigCategory_value[strings.ToUpper(reloadTabletConfigOptions.Category)]
+	if !ok {
+		return fmt.Errorf("invalid config category %s", reloadTabletConfigOptions.Category)
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.ReloadTabletConfig(commandCtx, &vtctldatapb.ReloadTabletConfigRequest{
+		Keyspace:    keyspace,
+		Shard:       shard,
+		Category:    tabletmanagerdatapb.TabletConfigCategory(category),
+		Concurrency: reloadTabletConfigOptions.Concurrency,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	var failed, mismatched int
+	for _, result := range resp.Results {
+		switch {
+		case result.Error != "":
+			failed++
+		case result.Mismatch:
+			mismatched++
+		}
+	}
+	if failed > 0 || mismatched > 0 {
+		return fmt.Errorf("%d tablet(s) failed to reload their config, %d tablet(s) report a different config than the majority", failed, mismatched)
+	}
+
+	return nil
+}
+
 func commandRunHealthCheck(cmd *cobra.Command, args []string) error {
 	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
@@ -672,6 +735,10 @@ func init() {
 	RefreshStateByShard.Flags().StringSliceVarP(&refreshStateByShardOptions.Cells, "cells", "c", nil, "If specified, only call RefreshState on tablets in the specified cells. If empty, all cells are considered.")
 	Root.AddCommand(RefreshStateByShard)
 
+	ReloadTabletConfig.Flags().StringVar(&reloadTabletConfigOptions.Category, "category", "table_acl", "Config to reload; valid choices are (table_acl).")
+	ReloadTabletConfig.Flags().Int32Var(&reloadTabletConfigOptions.Concurrency, "concurrency", 10, "Number of tablets to reload in parallel. Set to zero for unbounded concurrency.")
+	Root.AddCommand(ReloadTabletConfig)
+
 	Root.AddCommand(RunHealthCheck)
 	Root.AddCommand(SetWritable)
 	Root.AddCommand(SleepTablet)
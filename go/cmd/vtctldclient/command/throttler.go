@@ -18,6 +18,7 @@ package command
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -37,8 +38,11 @@ import (
 var (
 	// UpdateThrottlerConfig makes a UpdateThrottlerConfig gRPC call to a vtctld.
 	UpdateThrottlerConfig = &cobra.Command{
-		Use:                   "UpdateThrottlerConfig [--enable|--disable] [--metric-name=<name>] [--threshold=<float64>] [--custom-query=<query>] [--throttle-app|unthrottle-app=<name>] [--throttle-app-ratio=<float, range [0..1]>] [--throttle-app-duration=<duration>] [--throttle-app-exempt=<bool>] [--app-name=<name> --app-metrics=<metrics>] <keyspace>",
-		Short:                 "Update the tablet throttler configuration for all tablets in the given keyspace (across all cells)",
+		Use:   "UpdateThrottlerConfig [--enable|--disable] [--metric-name=<name>] [--threshold=<float64>] [--custom-query=<query>] [--throttle-app|unthrottle-app=<name>] [--throttle-app-ratio=<float, range [0..1]>] [--throttle-app-duration=<duration>] [--throttle-app-exempt=<bool>] [--app-name=<name> --app-metrics=<metrics>] [--wait-for-acknowledgement=<duration>] <keyspace>",
+		Short: "Update the tablet throttler configuration for all tablets in the given keyspace (across all cells)",
+		Long: `Update the tablet throttler configuration for all tablets in the given keyspace (across all cells).
+
+If --wait-for-acknowledgement is set, waits up to that long for the throttlers of the tablets in the keyspace to apply the updated configuration, and reports which tablets did.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
 		PreRunE:               validateUpdateThrottlerConfig,
@@ -53,6 +57,29 @@ var (
 		RunE:                  commandCheckThrottler,
 	}
 
+	GetThrottlerConfig = &cobra.Command{
+		Use:                   "GetThrottlerConfig <keyspace>",
+		Short:                 "Get the throttler configuration of the given keyspace, and whether the throttlers of its tablets have applied it.",
+		Example:               "GetThrottlerConfig commerce",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandGetThrottlerConfig,
+	}
+
+	// ThrottlerStatus makes a GetKeyspaceThrottlerStatus gRPC call to a vtctld.
+	ThrottlerStatus = &cobra.Command{
+		Use:   "ThrottlerStatus [--app-name <name>] [--format <text|json>] <keyspace>",
+		Short: "Check the throttler of the primary of every shard in the given keyspace, showing which shards throttle the app and why.",
+		Long: `Check the throttler of the primary of every shard in the given keyspace, showing which shards throttle the app and why.
+
+For each shard, shows whether the app is throttled, the metrics whose value exceeds their threshold, and the apps that are throttled by rule.
+Primaries that can't be reached are reported, without failing the command.`,
+		Example:               "ThrottlerStatus --app-name vreplication commerce",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandThrottlerStatus,
+	}
+
 	GetThrottlerStatus = &cobra.Command{
 		Use:                   "GetThrottlerStatus <tablet alias>",
 		Short:                 "Get the throttler status for the given tablet.",
@@ -68,9 +95,15 @@ var (
 	throttledAppRule             topodatapb.ThrottledAppRule
 	unthrottledAppRule           topodatapb.ThrottledAppRule
 	throttledAppDuration         time.Duration
+	waitForAcknowledgement       time.Duration
 
 	checkThrottlerOptions vtctldatapb.CheckThrottlerRequest
 	requestHeartbeats     bool
+
+	throttlerStatusOptions = struct {
+		AppName string
+		Format  string
+	}{}
 )
 
 func validateUpdateThrottlerConfig(cmd *cobra.Command, args []string) error {
@@ -99,10 +132,23 @@ func commandUpdateThrottlerConfig(cmd *cobra.Command, args []string) error {
 		updateThrottlerConfigOptions.ThrottledApp = &unthrottledAppRule
 	}
 
-	_, err := client.UpdateThrottlerConfig(commandCtx, &updateThrottlerConfigOptions)
+	updateThrottlerConfigOptions.WaitForAcknowledgement = protoutil.DurationToProto(waitForAcknowledgement)
+
+	resp, err := client.UpdateThrottlerConfig(commandCtx, &updateThrottlerConfigOptions)
 	if err != nil {
 		return err
 	}
+	if waitForAcknowledgement == 0 {
+		return nil
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
 	return nil
 }
 
@@ -137,6 +183,95 @@ func commandCheckThrottler(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func commandGetThrottlerConfig(cmd *cobra.Command, args []string) error {
+	keyspace := cmd.Flags().Arg(0)
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetThrottlerConfig(commandCtx, &vtctldatapb.GetThrottlerConfigRequest{
+		Keyspace: keyspace,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+func commandThrottlerStatus(cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(throttlerStatusOptions.Format)
+	switch format {
+	case "text", "json":
+	default:
+		return fmt.Errorf("invalid output format, got %s", throttlerStatusOptions.Format)
+	}
+
+	keyspace := cmd.Flags().Arg(0)
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetKeyspaceThrottlerStatus(commandCtx, &vtctldatapb.GetKeyspaceThrottlerStatusRequest{
+		Keyspace: keyspace,
+		AppName:  throttlerStatusOptions.AppName,
+	})
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		data, err := cli.MarshalJSON(resp)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+
+	fmt.Print(formatKeyspaceThrottlerStatus(resp))
+	return nil
+}
+
+// formatKeyspaceThrottlerStatus returns a line for each shard, saying whether
+// its primary throttles the app and why, or why it couldn't be checked.
+func formatKeyspaceThrottlerStatus(resp *vtctldatapb.GetKeyspaceThrottlerStatusResponse) string {
+	sb := strings.Builder{}
+	for _, shard := range resp.Shards {
+		sb.WriteString(shard.Shard)
+		if shard.PrimaryAlias != nil {
+			fmt.Fprintf(&sb, " (%s)", topoproto.TabletAliasString(shard.PrimaryAlias))
+		}
+
+		switch {
+		case shard.Error != "":
+			fmt.Fprintf(&sb, ": unreachable: %s\n", shard.Error)
+			continue
+		case shard.Throttled:
+			sb.WriteString(": throttled")
+		default:
+			sb.WriteString(": not throttled")
+		}
+		for _, metric := range shard.ExceededMetrics {
+			fmt.Fprintf(&sb, "; %s is %v, over the threshold of %v", metric.Name, metric.Value, metric.Threshold)
+		}
+		if len(shard.ThrottledApps) > 0 {
+			apps := make([]string, 0, len(shard.ThrottledApps))
+			for _, app := range shard.ThrottledApps {
+				apps = append(apps, fmt.Sprintf("%s (ratio %v)", app.Name, app.Ratio))
+			}
+			fmt.Fprintf(&sb, "; throttled apps: %s", strings.Join(apps, ", "))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
 func commandGetThrottlerStatus(cmd *cobra.Command, args []string) error {
 	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
@@ -177,6 +312,7 @@ func init() {
 	UpdateThrottlerConfig.Flags().BoolVar(&throttledAppRule.Exempt, "throttle-app-exempt", throttledAppRule.Exempt, "exempt this app from being at all throttled. WARNING: use with extreme care, as this is likely to push metrics beyond the throttler's threshold, and starve other apps")
 	UpdateThrottlerConfig.Flags().StringVar(&updateThrottlerConfigOptions.AppName, "app-name", "", "app name for which to assign metrics (requires --app-metrics)")
 	UpdateThrottlerConfig.Flags().StringSliceVar(&updateThrottlerConfigOptions.AppCheckedMetrics, "app-metrics", nil, "metrics to be used when checking the throttler for the app (requires --app-name). Empty to restore to default metrics. Example: --app-metrics=lag,custom,shard/loadavg")
+	UpdateThrottlerConfig.Flags().DurationVar(&waitForAcknowledgement, "wait-for-acknowledgement", 0, "if set, how long to wait for the tablets in the keyspace to apply the updated configuration, reporting which tablets did")
 	UpdateThrottlerConfig.MarkFlagsMutuallyExclusive("unthrottle-app", "throttle-app")
 	UpdateThrottlerConfig.MarkFlagsRequiredTogether("app-name", "app-metrics")
 
@@ -188,6 +324,14 @@ func init() {
 	CheckThrottler.Flags().BoolVar(&checkThrottlerOptions.OkIfNotExists, "ok-if-not-exists", false, "return OK even if metric does not exist")
 	Root.AddCommand(CheckThrottler)
 
+	// GetThrottlerConfig
+	Root.AddCommand(GetThrottlerConfig)
+
+	// ThrottlerStatus
+	ThrottlerStatus.Flags().StringVar(&throttlerStatusOptions.AppName, "app-name", throttlerapp.VitessName.String(), "app name to check")
+	ThrottlerStatus.Flags().StringVar(&throttlerStatusOptions.Format, "format", "text", "Output format to use; valid choices are (text, json).")
+	Root.AddCommand(ThrottlerStatus)
+
 	// GetThrottlerStatus
 	Root.AddCommand(GetThrottlerStatus)
 }
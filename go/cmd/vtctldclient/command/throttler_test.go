@@ -0,0 +1,66 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestFormatKeyspaceThrottlerStatus(t *testing.T) {
+	resp := &vtctldatapb.GetKeyspaceThrottlerStatusResponse{
+		Shards: []*vtctldatapb.ShardThrottlerStatus{
+			{
+				Shard:        "-40",
+				PrimaryAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+			},
+			{
+				Shard:        "40-80",
+				PrimaryAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+				Throttled:    true,
+				ExceededMetrics: []*tabletmanagerdatapb.CheckThrottlerResponse_Metric{
+					{Name: "lag", Value: 12.5, Threshold: 5},
+				},
+				ThrottledApps: []*topodatapb.ThrottledAppRule{
+					{Name: "online-ddl", Ratio: 1},
+					{Name: "vreplication", Ratio: 0.5},
+				},
+			},
+			{
+				Shard:        "80-c0",
+				PrimaryAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 300},
+				Error:        "failed to check the throttler: connection refused",
+			},
+			{
+				Shard: "c0-",
+				Error: "shard ks/c0- has no primary",
+			},
+		},
+	}
+
+	want := `-40 (zone1-0000000100): not throttled
+40-80 (zone1-0000000200): throttled; lag is 12.5, over the threshold of 5; throttled apps: online-ddl (ratio 1), vreplication (ratio 0.5)
+80-c0 (zone1-0000000300): unreachable: failed to check the throttler: connection refused
+c0-: unreachable: shard ks/c0- has no primary
+`
+	assert.Equal(t, want, formatKeyspaceThrottlerStatus(resp))
+}
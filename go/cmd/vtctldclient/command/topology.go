@@ -39,6 +39,28 @@ var (
 		RunE:                  commandGetTopologyPath,
 	}
 
+	// TopoGet makes a TopoGet gRPC call to a vtctld.
+	TopoGet = &cobra.Command{
+		Use:                   "TopoGet [--decode [--json]] <path>",
+		Short:                 "Gets the contents of a file in the topology server, decoding it if it is of a known type.",
+		Long:                  "Gets the contents of a file in the topology server. The path starts with the cell of the file, e.g. /global/keyspaces/commerce/Keyspace.",
+		Example:               "TopoGet --decode --json /global/keyspaces/commerce/Keyspace",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandTopoGet,
+	}
+
+	// TopoList makes a TopoList gRPC call to a vtctld.
+	TopoList = &cobra.Command{
+		Use:                   "TopoList [--page-token <token>] [--page-size <size>] <path>",
+		Short:                 "Lists a page of the children of a directory in the topology server.",
+		Long:                  "Lists a page of the children of a directory in the topology server. The path starts with the cell of the directory, e.g. /global/keyspaces. The root, /, lists the cells.",
+		Example:               "TopoList --page-size 10 /global/keyspaces",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandTopoList,
+	}
+
 	// WriteTopologyPath writes the contents of a local file to a path
 	// in the topology server.
 	WriteTopologyPath = &cobra.Command{
@@ -96,6 +118,65 @@ func commandGetTopologyPath(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var topoGetOptions = struct {
+	decode bool
+	asJSON bool
+}{}
+
+func commandTopoGet(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.TopoGet(commandCtx, &vtctldatapb.TopoGetRequest{
+		Path:   cmd.Flags().Arg(0),
+		Decode: topoGetOptions.decode,
+		AsJson: topoGetOptions.asJSON,
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.Decoded != "" {
+		fmt.Println(resp.Decoded)
+		return nil
+	}
+
+	data, err := cli.MarshalJSONPretty(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+var topoListOptions = struct {
+	pageToken string
+	pageSize  int32
+}{}
+
+func commandTopoList(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.TopoList(commandCtx, &vtctldatapb.TopoListRequest{
+		Path:      cmd.Flags().Arg(0),
+		PageToken: topoListOptions.pageToken,
+		PageSize:  topoListOptions.pageSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSONPretty(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
 var writeTopologyPathOptions = struct {
 	// The cell to use for the copy. Defaults to the global cell.
 	cell string
@@ -131,6 +212,14 @@ func init() {
 	GetTopologyPath.Flags().BoolVar(&getTopologyPathOptions.dataAsJSON, "data-as-json", getTopologyPathOptions.dataAsJSON, "If true, only the data is output and it is in JSON format rather than prototext.")
 	Root.AddCommand(GetTopologyPath)
 
+	TopoGet.Flags().BoolVar(&topoGetOptions.decode, "decode", false, "Decode the contents of a file of a known type, like a Keyspace, Shard, Tablet or SrvKeyspace, as prototext.")
+	TopoGet.Flags().BoolVar(&topoGetOptions.asJSON, "json", false, "With --decode, decode the contents as JSON instead of prototext.")
+	Root.AddCommand(TopoGet)
+
+	TopoList.Flags().StringVar(&topoListOptions.pageToken, "page-token", "", "The next_page_token of the previous page, to list the page that follows it.")
+	TopoList.Flags().Int32Var(&topoListOptions.pageSize, "page-size", 0, "The maximum number of entries to list. Defaults to 100 on the server, and is capped at 1000.")
+	Root.AddCommand(TopoList)
+
 	WriteTopologyPath.Flags().StringVar(&writeTopologyPathOptions.cell, "cell", topo.GlobalCell, "Topology server cell to copy the file to.")
 	Root.AddCommand(WriteTopologyPath)
 }
@@ -20,10 +20,12 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
@@ -40,7 +42,7 @@ var (
 	}
 	// ValidateKeyspace makes a ValidateKeyspace gRPC call to a vtctld.
 	ValidateKeyspace = &cobra.Command{
-		Use:                   "ValidateKeyspace [--ping-tablets] <keyspace>",
+		Use:                   "ValidateKeyspace [--ping-tablets] [--check-srv-keyspace] <keyspace>",
 		Short:                 "Validates that all nodes reachable from the specified keyspace are consistent.",
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
@@ -48,7 +50,7 @@ var (
 	}
 	// ValidateShard makes a ValidateShard gRPC call to a vtctld.
 	ValidateShard = &cobra.Command{
-		Use:                   "ValidateShard [--ping-tablets] <keyspace/shard>",
+		Use:                   "ValidateShard [--ping-tablets] [--ping-timeout <duration>] [--include-serving-state] [--serving-state-wait <duration>] <keyspace/shard>",
 		Short:                 "Validates that all nodes reachable from the specified shard are consistent.",
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
@@ -81,7 +83,8 @@ func commandValidate(cmd *cobra.Command, args []string) error {
 }
 
 var validateKeyspaceOptions = struct {
-	PingTablets bool
+	PingTablets      bool
+	CheckSrvKeyspace bool
 }{}
 
 func commandValidateKeyspace(cmd *cobra.Command, args []string) error {
@@ -89,8 +92,9 @@ func commandValidateKeyspace(cmd *cobra.Command, args []string) error {
 
 	keyspace := cmd.Flags().Arg(0)
 	resp, err := client.ValidateKeyspace(commandCtx, &vtctldatapb.ValidateKeyspaceRequest{
-		Keyspace:    keyspace,
-		PingTablets: validateKeyspaceOptions.PingTablets,
+		Keyspace:         keyspace,
+		PingTablets:      validateKeyspaceOptions.PingTablets,
+		CheckSrvKeyspace: validateKeyspaceOptions.CheckSrvKeyspace,
 	})
 	if err != nil {
 		return err
@@ -107,7 +111,10 @@ func commandValidateKeyspace(cmd *cobra.Command, args []string) error {
 }
 
 var validateShardOptions = struct {
-	PingTablets bool
+	PingTablets         bool
+	PingTimeout         time.Duration
+	IncludeServingState bool
+	ServingStateWait    time.Duration
 }{}
 
 func commandValidateShard(cmd *cobra.Command, args []string) error {
@@ -118,11 +125,19 @@ func commandValidateShard(cmd *cobra.Command, args []string) error {
 
 	cli.FinishedParsing(cmd)
 
-	resp, err := client.ValidateShard(commandCtx, &vtctldatapb.ValidateShardRequest{
-		Keyspace:    keyspace,
-		Shard:       shard,
-		PingTablets: validateShardOptions.PingTablets,
-	})
+	req := &vtctldatapb.ValidateShardRequest{
+		Keyspace:            keyspace,
+		Shard:               shard,
+		PingTablets:         validateShardOptions.PingTablets,
+		IncludeServingState: validateShardOptions.IncludeServingState,
+	}
+	if validateShardOptions.PingTimeout > 0 {
+		req.PingTimeout = protoutil.DurationToProto(validateShardOptions.PingTimeout)
+	}
+	if validateShardOptions.ServingStateWait > 0 {
+		req.ServingStateWait = protoutil.DurationToProto(validateShardOptions.ServingStateWait)
+	}
+	resp, err := client.ValidateShard(commandCtx, req)
 	if err != nil {
 		return err
 	}
@@ -197,7 +212,11 @@ func init() {
 
 	Validate.Flags().BoolVarP(&validateOptions.PingTablets, pingTabletsName, pingTabletsShort, pingTabletsDefault, pingTabletsUsage)
 	ValidateKeyspace.Flags().BoolVarP(&validateKeyspaceOptions.PingTablets, pingTabletsName, pingTabletsShort, pingTabletsDefault, pingTabletsUsage)
+	ValidateKeyspace.Flags().BoolVar(&validateKeyspaceOptions.CheckSrvKeyspace, "check-srv-keyspace", false, "Compares the SrvKeyspace of each cell with the one RebuildKeyspaceGraph would compute from the shard records, and reports the cells where it is stale or missing.")
 	ValidateShard.Flags().BoolVarP(&validateShardOptions.PingTablets, pingTabletsName, pingTabletsShort, pingTabletsDefault, pingTabletsUsage)
+	ValidateShard.Flags().DurationVar(&validateShardOptions.PingTimeout, "ping-timeout", 0, "The timeout of each tablet ping, when pinging tablets. Defaults to the topo remote operation timeout of the vtctld.")
+	ValidateShard.Flags().BoolVar(&validateShardOptions.IncludeServingState, "include-serving-state", false, "Compares the tablet type and serving state each tablet broadcasts in its health stream with its topo record.")
+	ValidateShard.Flags().DurationVar(&validateShardOptions.ServingStateWait, "serving-state-wait", 0, "How long to wait for the health data of each tablet with --include-serving-state. Tablets that send none are reported. Defaults to 5s.")
 
 	Root.AddCommand(Validate)
 	Root.AddCommand(ValidateKeyspace)
@@ -279,6 +279,17 @@ func OutputStatusResponse(resp *vtctldatapb.WorkflowStatusResponse, format strin
 		}
 		tout.WriteString("\nTraffic State: ")
 		tout.WriteString(resp.TrafficState)
+		if len(resp.Sequences) > 0 {
+			tout.WriteString("\n\nThe following sequence tables were initialized:\n\n")
+			for _, seq := range resp.Sequences {
+				action := "existing"
+				if seq.Created {
+					action = "created"
+				}
+				tout.WriteString(fmt.Sprintf("%s.%s (%s) used by %s: next value %d\n",
+					seq.Keyspace, seq.Table, action, seq.UsingTable, seq.StartValue))
+			}
+		}
 		output = tout.Bytes()
 	}
 	fmt.Println(string(output))
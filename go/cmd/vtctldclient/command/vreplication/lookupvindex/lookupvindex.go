@@ -18,6 +18,7 @@ package lookupvindex
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -71,8 +72,10 @@ var (
 	}{}
 
 	externalizeOptions = struct {
-		Keyspace string
-		Delete   bool
+		Keyspace               string
+		Delete                 bool
+		Force                  bool
+		VerificationSampleSize int32
 	}{}
 
 	internalizeOptions = struct {
@@ -178,8 +181,12 @@ var (
 
 	// externalize makes a LookupVindexExternalize call to a vtctld.
 	externalize = &cobra.Command{
-		Use:                   "externalize",
-		Short:                 "Externalize the Lookup Vindex. If the Vindex has an owner the VReplication workflow will also be stopped/deleted.",
+		Use:   "externalize",
+		Short: "Externalize the Lookup Vindex. If the Vindex has an owner the VReplication workflow will also be stopped/deleted.",
+		Long: `Externalize the Lookup Vindex. If the Vindex has an owner the VReplication workflow will also be stopped/deleted.
+
+Unless --force is used, the backfill must have finished copying, and the lookup table must have rows for a sample
+of the most recent rows of the owner table on each source shard.`,
 		Example:               `vtctldclient --server localhost:15999 LookupVindex --name corder_lookup_vdx --table-keyspace customer externalize`,
 		SilenceUsage:          true,
 		DisableFlagsInUseLine: true,
@@ -200,6 +207,18 @@ var (
 		RunE:                  commandInternalize,
 	}
 
+	// progress makes a GetWorkflowProgress call to a vtctld.
+	progress = &cobra.Command{
+		Use:                   "progress",
+		Short:                 "Show how many rows the VReplication workflow has written to the lookup table, per target shard, against the estimated number of source rows.",
+		Example:               `vtctldclient --server localhost:15999 LookupVindex --name corder_lookup_vdx --table-keyspace customer progress`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Progress"},
+		Args:                  cobra.NoArgs,
+		RunE:                  commandProgress,
+	}
+
 	// show makes a GetWorkflows call to a vtctld.
 	show = &cobra.Command{
 		Use:                   "show",
@@ -294,7 +313,9 @@ func commandExternalize(cmd *cobra.Command, args []string) error {
 		// Where the lookup table and VReplication workflow were created.
 		TableKeyspace: baseOptions.TableKeyspace,
 		// Delete the workflow after externalizing, instead of stopping.
-		DeleteWorkflow: externalizeOptions.Delete,
+		DeleteWorkflow:         externalizeOptions.Delete,
+		Force:                  externalizeOptions.Force,
+		VerificationSampleSize: externalizeOptions.VerificationSampleSize,
 	})
 
 	if err != nil {
@@ -336,6 +357,45 @@ func commandInternalize(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func commandProgress(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().GetWorkflowProgress(common.GetCommandCtx(), &vtctldatapb.GetWorkflowProgressRequest{
+		Keyspace: baseOptions.TableKeyspace,
+		Workflow: baseOptions.Name,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("LookupVindex %s backfill: %s\n", baseOptions.Name, formatBackfillProgress(resp.Progress))
+	shards := make([]string, 0, len(resp.Shards))
+	for shard := range resp.Shards {
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+	for _, shard := range shards {
+		shardProgress := resp.Shards[shard]
+		states := make([]string, 0, len(shardProgress.Streams))
+		for _, stream := range shardProgress.Streams {
+			states = append(states, stream.State)
+		}
+		fmt.Printf("  %s/%s: %s (%s)\n", baseOptions.TableKeyspace, shard, formatBackfillProgress(shardProgress.Progress), strings.Join(states, ", "))
+	}
+
+	return nil
+}
+
+// formatBackfillProgress describes the rows written to the lookup table
+// against the estimated number of source rows.
+func formatBackfillProgress(progress *vtctldatapb.GetWorkflowProgressResponse_Progress) string {
+	if progress.GetRowsEstimated() == 0 {
+		return fmt.Sprintf("%d rows written", progress.GetRowsCopied())
+	}
+	return fmt.Sprintf("%d of ~%d estimated source rows written (%.0f%%)", progress.GetRowsCopied(), progress.GetRowsEstimated(),
+		100*float64(progress.GetRowsCopied())/float64(progress.GetRowsEstimated()))
+}
+
 func commandShow(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
@@ -389,11 +449,17 @@ func registerCommands(root *cobra.Command) {
 	// for the VReplication workflow used.
 	base.AddCommand(show)
 
+	// This will show the rows the VReplication workflow has
+	// written to the lookup table so far.
+	base.AddCommand(progress)
+
 	// This will also stop the VReplication workflow if the
 	// vindex has an owner as the lookup vindex will then be
 	// managed by VTGate.
 	externalize.Flags().StringVar(&externalizeOptions.Keyspace, "keyspace", "", "The keyspace containing the Lookup Vindex. If no value is specified then the table-keyspace will be used.")
 	externalize.Flags().BoolVar(&externalizeOptions.Delete, "delete", false, "Delete the VReplication workflow after externalizing the Vindex, instead of stopping (default false).")
+	externalize.Flags().BoolVar(&externalizeOptions.Force, "force", false, "Externalize the Vindex even if the backfill has not finished copying, and skip the verification of the lookup table.")
+	externalize.Flags().Int32Var(&externalizeOptions.VerificationSampleSize, "verification-sample-size", 10, "The number of the most recent rows of the owner table, per source shard, that must have a row in the lookup table before the Vindex is externalized. Use 0 to skip the verification.")
 	base.AddCommand(externalize)
 
 	internalize.Flags().StringVar(&internalizeOptions.Keyspace, "keyspace", "", "The keyspace containing the Lookup Vindex. If no value is specified then the table-keyspace will be used.")
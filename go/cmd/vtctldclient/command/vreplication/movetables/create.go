@@ -39,6 +39,7 @@ var (
 		SourceTimeZone      string
 		NoRoutingRules      bool
 		AtomicCopy          bool
+		CreateSequences     bool
 		WorkflowOptions     vtctldatapb.WorkflowOptions
 		// This maps to a WorkflowOptions.ShardedAutoIncrementHandling ENUM value.
 		ShardedAutoIncrementHandlingStr string
@@ -102,6 +103,9 @@ var (
 			if val == int32(vtctldatapb.ShardedAutoIncrementHandling_REPLACE) && createOptions.WorkflowOptions.GlobalKeyspace == "" {
 				fmt.Println("WARNING: no global-keyspace value provided so all sequence table references not fully qualified must be created manually before switching traffic")
 			}
+			if createOptions.CreateSequences && createOptions.WorkflowOptions.GlobalKeyspace == "" {
+				return fmt.Errorf("--create-sequences requires a --global-keyspace to create the sequence tables in")
+			}
 
 			return nil
 		},
@@ -141,6 +145,7 @@ func commandCreate(cmd *cobra.Command, args []string) error {
 		StopAfterCopy:             common.CreateOptions.StopAfterCopy,
 		NoRoutingRules:            createOptions.NoRoutingRules,
 		AtomicCopy:                createOptions.AtomicCopy,
+		CreateSequences:           createOptions.CreateSequences,
 		WorkflowOptions:           &createOptions.WorkflowOptions,
 	}
 
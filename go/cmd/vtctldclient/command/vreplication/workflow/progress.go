@@ -0,0 +1,63 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// progress makes a GetWorkflowProgress gRPC call to a vtctld.
+	progress = &cobra.Command{
+		Use:                   "progress",
+		Short:                 "Show the copy and replication progress of a VReplication workflow, per stream, per target shard, and overall.",
+		Example:               `vtctldclient --server localhost:15999 workflow --keyspace customer progress --workflow commerce2customer`,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Progress"},
+		Args:                  cobra.NoArgs,
+		RunE:                  commandProgress,
+	}
+)
+
+func commandProgress(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	req := &vtctldatapb.GetWorkflowProgressRequest{
+		Keyspace: baseOptions.Keyspace,
+		Workflow: baseOptions.Workflow,
+		Shards:   baseOptions.Shards,
+	}
+	resp, err := common.GetClient().GetWorkflowProgress(common.GetCommandCtx(), req)
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSONPretty(resp)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+
+	return nil
+}
@@ -69,6 +69,11 @@ func registerCommands(root *cobra.Command) {
 	common.AddShardSubsetFlag(workflowList, &baseOptions.Shards)
 	base.AddCommand(workflowList)
 
+	progress.Flags().StringVarP(&baseOptions.Workflow, "workflow", "w", "", "The workflow you want the progress of.")
+	progress.MarkFlagRequired("workflow")
+	common.AddShardSubsetFlag(progress, &baseOptions.Shards)
+	base.AddCommand(progress)
+
 	show.Flags().StringVarP(&baseOptions.Workflow, "workflow", "w", "", "The workflow you want the details for.")
 	show.MarkFlagRequired("workflow")
 	show.Flags().BoolVar(&workflowShowOptions.IncludeLogs, "include-logs", true, "Include recent logs for the workflow.")
@@ -19,6 +19,7 @@ package command
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -40,8 +41,15 @@ var (
 	}
 	// ApplyVSchema makes an ApplyVSchema gRPC call to a vtctld.
 	ApplyVSchema = &cobra.Command{
-		Use:                   "ApplyVSchema {--vschema=<vschema> || --vschema-file=<vschema file> || --sql=<sql> || --sql-file=<sql file>} [--cells=c1,c2,...] [--skip-rebuild] [--dry-run] [--strict] <keyspace>",
-		Short:                 "Applies the VTGate routing schema to the provided keyspace. Shows the result after application.",
+		Use:   "ApplyVSchema {--vschema=<vschema> || --vschema-file=<vschema file> || --sql=<sql> || --sql-file=<sql file>} [--cells=c1,c2,...] [--skip-rebuild] [--dry-run] [--show-diff] [--strict] [--retry-on-conflict] <keyspace>",
+		Short: "Applies the VTGate routing schema to the provided keyspace. Shows the result after application.",
+		Long: `Applies the VTGate routing schema to the provided keyspace. Shows the result after application.
+
+With --dry-run or --show-diff, shows before the result what changes from the current VSchema of the keyspace: tables added or removed, vindex definitions, primary vindexes and auto_increment settings changed, and so on.
+Changes that reroute queries are flagged as routing changes. With --dry-run, the VSchema is not saved.
+
+The VSchema is saved under the keyspace lock, and the command fails if another writer changes the VSchema while it is being applied.
+With --retry-on-conflict, a --sql or --sql-file change is instead applied again to the VSchema saved by the other writer.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandApplyVSchema,
@@ -54,9 +62,12 @@ var applyVSchemaOptions = struct {
 	SQL         string
 	SQLFile     string
 	DryRun      bool
+	ShowDiff    bool
 	SkipRebuild bool
 	Cells       []string
 	Strict      bool
+
+	RetryOnConflict bool
 }{}
 
 func commandApplyVSchema(cmd *cobra.Command, args []string) error {
@@ -71,12 +82,18 @@ func commandApplyVSchema(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("one of the sql, sql-file, vschema, or vschema-file flags must be specified when calling the ApplyVSchema command")
 	}
 
+	if applyVSchemaOptions.RetryOnConflict && !sqlMode {
+		return fmt.Errorf("the retry-on-conflict flag may only be specified with the sql or sql-file flags when calling the ApplyVSchema command")
+	}
+
 	req := &vtctldatapb.ApplyVSchemaRequest{
 		Keyspace:    cmd.Flags().Arg(0),
 		SkipRebuild: applyVSchemaOptions.SkipRebuild,
 		Cells:       applyVSchemaOptions.Cells,
 		DryRun:      applyVSchemaOptions.DryRun,
 		Strict:      applyVSchemaOptions.Strict,
+
+		RetryOnConflict: applyVSchemaOptions.RetryOnConflict,
 	}
 
 	var err error
@@ -115,6 +132,9 @@ func commandApplyVSchema(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if applyVSchemaOptions.DryRun || applyVSchemaOptions.ShowDiff {
+		fmt.Print(formatVSchemaDiff(req.Keyspace, res.Diff))
+	}
 	vsData, err := cli.MarshalJSON(res.VSchema)
 	if err != nil {
 		return err
@@ -125,9 +145,26 @@ func commandApplyVSchema(cmd *cobra.Command, args []string) error {
 			fmt.Printf("Unknown parameter in vindex %s: %s\n", vdxName, param)
 		}
 	}
+	if req.DryRun {
+		fmt.Println("Dry run: the VSchema was not saved.")
+	}
 	return nil
 }
 
+// formatVSchemaDiff renders the changes to the VSchema of the keyspace.
+func formatVSchemaDiff(keyspace string, diff []string) string {
+	if len(diff) == 0 {
+		return fmt.Sprintf("No changes to the VSchema of keyspace %s.\n", keyspace)
+	}
+
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "Changes to the VSchema of keyspace %s:\n", keyspace)
+	for _, change := range diff {
+		fmt.Fprintf(&sb, "  %s\n", change)
+	}
+	return sb.String()
+}
+
 func commandGetVSchema(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
@@ -155,10 +192,12 @@ func init() {
 	ApplyVSchema.Flags().StringVar(&applyVSchemaOptions.VSchemaFile, "vschema-file", "", "Path to a file containing the vschema to apply, in JSON form.")
 	ApplyVSchema.Flags().StringVar(&applyVSchemaOptions.SQL, "sql", "", "A VSchema DDL SQL statement, e.g. `alter table t add vindex hash(id)`.")
 	ApplyVSchema.Flags().StringVar(&applyVSchemaOptions.SQLFile, "sql-file", "", "Path to a file containing a VSchema DDL SQL.")
-	ApplyVSchema.Flags().BoolVar(&applyVSchemaOptions.DryRun, "dry-run", false, "If set, do not save the altered vschema, simply echo to console.")
+	ApplyVSchema.Flags().BoolVar(&applyVSchemaOptions.DryRun, "dry-run", false, "If set, do not save the altered vschema, simply echo it and its changes to console.")
+	ApplyVSchema.Flags().BoolVar(&applyVSchemaOptions.ShowDiff, "show-diff", false, "If set, also echo the changes to the vschema to console. Always set with --dry-run.")
 	ApplyVSchema.Flags().BoolVar(&applyVSchemaOptions.SkipRebuild, "skip-rebuild", false, "Skip rebuilding the SrvSchema objects.")
 	ApplyVSchema.Flags().StringSliceVar(&applyVSchemaOptions.Cells, "cells", nil, "Limits the rebuild to the specified cells, after application. Ignored if --skip-rebuild is set.")
 	ApplyVSchema.Flags().BoolVar(&applyVSchemaOptions.Strict, "strict", false, "If set, treat unknown vindex params as errors.")
+	ApplyVSchema.Flags().BoolVar(&applyVSchemaOptions.RetryOnConflict, "retry-on-conflict", false, "If another writer changes the VSchema while it is being applied, apply the --sql or --sql-file change again to the updated VSchema instead of failing.")
 	Root.AddCommand(ApplyVSchema)
 
 	Root.AddCommand(GetVSchema)
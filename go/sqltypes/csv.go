@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqltypes
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// CSVBinaryEncoding is how a CSVEncoder writes the values of binary
+// columns.
+type CSVBinaryEncoding int
+
+const (
+	// CSVBinaryBase64 writes binary values in standard base64.
+	CSVBinaryBase64 = CSVBinaryEncoding(iota)
+	// CSVBinaryHex writes binary values in lowercase hexadecimal.
+	CSVBinaryHex
+)
+
+// ParseCSVBinaryEncoding returns the CSVBinaryEncoding named by s, either
+// "base64" or "hex".
+func ParseCSVBinaryEncoding(s string) (CSVBinaryEncoding, error) {
+	switch s {
+	case "base64":
+		return CSVBinaryBase64, nil
+	case "hex":
+		return CSVBinaryHex, nil
+	}
+	return CSVBinaryBase64, fmt.Errorf("invalid binary encoding %q, expected base64 or hex", s)
+}
+
+// CSVOptions configures a CSVEncoder.
+type CSVOptions struct {
+	// Header writes the column names as the first record.
+	Header bool
+	// Null is written in place of NULL values. Note the zero value
+	// makes NULL indistinguishable from the empty string.
+	Null string
+	// Binary is the encoding of the values of binary columns: BINARY,
+	// VARBINARY, BLOB, BIT and GEOMETRY.
+	Binary CSVBinaryEncoding
+}
+
+// CSVEncoder writes results as CSV, as described in RFC 4180. Each record
+// is flushed as soon as it is written, so that the encoder can follow a
+// stream of results without holding more than one row.
+type CSVEncoder struct {
+	w      *csv.Writer
+	opts   CSVOptions
+	binary []bool
+	record []string
+}
+
+// NewCSVEncoder returns an encoder that writes to w.
+func NewCSVEncoder(w io.Writer, opts CSVOptions) *CSVEncoder {
+	return &CSVEncoder{
+		w:    csv.NewWriter(w),
+		opts: opts,
+	}
+}
+
+// WriteFields sets the columns of the rows to come, and writes the header
+// record if the options ask for one.
+func (e *CSVEncoder) WriteFields(fields []*querypb.Field) error {
+	e.binary = make([]bool, len(fields))
+	e.record = make([]string, len(fields))
+	for i, field := range fields {
+		e.binary[i] = IsBinary(field.Type) || field.Type == Bit || field.Type == Geometry
+		e.record[i] = field.Name
+	}
+	if !e.opts.Header {
+		return nil
+	}
+	return e.write()
+}
+
+// WriteRow writes one row, with the columns of the last WriteFields call.
+func (e *CSVEncoder) WriteRow(row Row) error {
+	if len(row) != len(e.record) {
+		return fmt.Errorf("row has %d values, expected %d", len(row), len(e.record))
+	}
+	for i, v := range row {
+		switch {
+		case v.IsNull():
+			e.record[i] = e.opts.Null
+		case e.binary[i] && e.opts.Binary == CSVBinaryHex:
+			e.record[i] = hex.EncodeToString(v.Raw())
+		case e.binary[i]:
+			e.record[i] = base64.StdEncoding.EncodeToString(v.Raw())
+		default:
+			e.record[i] = v.ToString()
+		}
+	}
+	return e.write()
+}
+
+// WriteResult writes the rows of qr. Its fields, if any, are written with
+// WriteFields first, so that the results of a stream can be passed one
+// after the other.
+func (e *CSVEncoder) WriteResult(qr *Result) error {
+	if len(qr.Fields) > 0 {
+		if err := e.WriteFields(qr.Fields); err != nil {
+			return err
+		}
+	}
+	for _, row := range qr.Rows {
+		if err := e.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *CSVEncoder) write() error {
+	if err := e.w.Write(e.record); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
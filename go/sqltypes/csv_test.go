@@ -0,0 +1,100 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqltypes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordWriter records each call to Write.
+type recordWriter struct {
+	writes []string
+}
+
+func (w *recordWriter) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func TestCSVEncoder(t *testing.T) {
+	fields := MakeTestFields("id|name|data", "int64|varchar|varbinary")
+	qr := MakeTestResult(fields,
+		"1|plain|ab",
+		`2|with, comma|null`,
+		`3|"quoted"|`,
+		"null||\x00\xff",
+	)
+	qr.Rows = append(qr.Rows, Row{NewInt64(4), NewVarChar("two\nlines"), NULL})
+
+	testcases := []struct {
+		name string
+		opts CSVOptions
+		want string
+	}{{
+		name: "defaults",
+		want: "1,plain,YWI=\n" +
+			"2,\"with, comma\",\n" +
+			"3,\"\"\"quoted\"\"\",\n" +
+			",,AP8=\n" +
+			"4,\"two\nlines\",\n",
+	}, {
+		name: "header, NULL and hex",
+		opts: CSVOptions{Header: true, Null: `\N`, Binary: CSVBinaryHex},
+		want: "id,name,data\n" +
+			"1,plain,6162\n" +
+			"2,\"with, comma\",\\N\n" +
+			"3,\"\"\"quoted\"\"\",\n" +
+			"\\N,,00ff\n" +
+			"4,\"two\nlines\",\\N\n",
+	}}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var sb strings.Builder
+			require.NoError(t, NewCSVEncoder(&sb, tc.opts).WriteResult(qr))
+			require.Equal(t, tc.want, sb.String())
+		})
+	}
+}
+
+func TestCSVEncoderStreaming(t *testing.T) {
+	fields := MakeTestFields("id|name", "int64|varchar")
+	results := MakeTestStreamingResults(fields, "1|a", "2|b", "---", "3|c")
+
+	w := &recordWriter{}
+	enc := NewCSVEncoder(w, CSVOptions{Header: true})
+	for _, qr := range results {
+		require.NoError(t, enc.WriteResult(qr))
+	}
+	// Every record reaches the writer on its own.
+	require.Equal(t, []string{"id,name\n", "1,a\n", "2,b\n", "3,c\n"}, w.writes)
+
+	require.ErrorContains(t, enc.WriteRow(Row{NewInt64(4)}), "row has 1 values, expected 2")
+}
+
+func TestParseCSVBinaryEncoding(t *testing.T) {
+	enc, err := ParseCSVBinaryEncoding("hex")
+	require.NoError(t, err)
+	require.Equal(t, CSVBinaryHex, enc)
+	enc, err = ParseCSVBinaryEncoding("base64")
+	require.NoError(t, err)
+	require.Equal(t, CSVBinaryBase64, enc)
+	_, err = ParseCSVBinaryEncoding("raw")
+	require.ErrorContains(t, err, "invalid binary encoding")
+}
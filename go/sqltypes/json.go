@@ -0,0 +1,170 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqltypes
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// JSONValue is a Value with a canonical JSON representation, which keeps
+// its type and survives a round trip unchanged, unlike the marshaling of
+// Value itself:
+//
+//	{"type": "INT64", "value": "-1"}
+//	{"type": "DECIMAL", "value": "12345678901234567890.5"}
+//	{"type": "VARBINARY", "value": "AP8=", "encoding": "base64"}
+//	{"type": "NULL_TYPE", "value": null}
+//
+// The value is always a string, so that numbers are never truncated to a
+// float64 by the reader. It is base64 encoded for the binary types (BINARY,
+// VARBINARY, BLOB, BIT, GEOMETRY and VECTOR), and for any other value that
+// isn't valid UTF-8.
+type JSONValue Value
+
+type jsonValue struct {
+	Type     string  `json:"type"`
+	Value    *string `json:"value"`
+	Encoding string  `json:"encoding,omitempty"`
+}
+
+const jsonEncodingBase64 = "base64"
+
+func isJSONBinary(typ querypb.Type) bool {
+	return IsBinary(typ) || typ == Bit || typ == Geometry || typ == Vector
+}
+
+// MarshalJSON implements json.Marshaler.
+func (jv JSONValue) MarshalJSON() ([]byte, error) {
+	v := Value(jv)
+	out := jsonValue{Type: v.Type().String()}
+	if !v.IsNull() {
+		raw := v.Raw()
+		var s string
+		if isJSONBinary(v.Type()) || !utf8.Valid(raw) {
+			s = base64.StdEncoding.EncodeToString(raw)
+			out.Encoding = jsonEncodingBase64
+		} else {
+			s = string(raw)
+		}
+		out.Value = &s
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (jv *JSONValue) UnmarshalJSON(b []byte) error {
+	var in jsonValue
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	typ, ok := querypb.Type_value[in.Type]
+	if !ok {
+		return fmt.Errorf("unknown value type %q", in.Type)
+	}
+	if querypb.Type(typ) == Null || in.Value == nil {
+		if querypb.Type(typ) != Null || in.Value != nil {
+			return fmt.Errorf("only NULL_TYPE values can be null, got %s", b)
+		}
+		*jv = JSONValue(NULL)
+		return nil
+	}
+
+	raw := []byte(*in.Value)
+	switch in.Encoding {
+	case "":
+	case jsonEncodingBase64:
+		var err error
+		if raw, err = base64.StdEncoding.DecodeString(*in.Value); err != nil {
+			return fmt.Errorf("invalid base64 %s value: %v", in.Type, err)
+		}
+	default:
+		return fmt.Errorf("unknown value encoding %q", in.Encoding)
+	}
+	*jv = JSONValue(MakeTrusted(querypb.Type(typ), raw))
+	return nil
+}
+
+// JSONResult is a Result whose rows marshal to JSON with JSONValue. Its
+// other fields marshal like those of Result.
+type JSONResult Result
+
+// jsonResult has the fields of Result, in the same order.
+type jsonResult struct {
+	Fields              []*querypb.Field `json:"fields"`
+	RowsAffected        uint64           `json:"rows_affected"`
+	InsertID            uint64           `json:"insert_id"`
+	InsertIDChanged     bool             `json:"insert_id_changed"`
+	Rows                [][]JSONValue    `json:"rows"`
+	SessionStateChanges string           `json:"session_state_changes"`
+	StatusFlags         uint16           `json:"status_flags"`
+	Info                string           `json:"info"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (jr *JSONResult) MarshalJSON() ([]byte, error) {
+	out := jsonResult{
+		Fields:              jr.Fields,
+		RowsAffected:        jr.RowsAffected,
+		InsertID:            jr.InsertID,
+		InsertIDChanged:     jr.InsertIDChanged,
+		SessionStateChanges: jr.SessionStateChanges,
+		StatusFlags:         jr.StatusFlags,
+		Info:                jr.Info,
+	}
+	if jr.Rows != nil {
+		out.Rows = make([][]JSONValue, len(jr.Rows))
+		for i, row := range jr.Rows {
+			out.Rows[i] = make([]JSONValue, len(row))
+			for j, v := range row {
+				out.Rows[i][j] = JSONValue(v)
+			}
+		}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (jr *JSONResult) UnmarshalJSON(b []byte) error {
+	var in jsonResult
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	*jr = JSONResult{
+		Fields:              in.Fields,
+		RowsAffected:        in.RowsAffected,
+		InsertID:            in.InsertID,
+		InsertIDChanged:     in.InsertIDChanged,
+		SessionStateChanges: in.SessionStateChanges,
+		StatusFlags:         in.StatusFlags,
+		Info:                in.Info,
+	}
+	if in.Rows != nil {
+		jr.Rows = make([]Row, len(in.Rows))
+		for i, row := range in.Rows {
+			jr.Rows[i] = make(Row, len(row))
+			for j, v := range row {
+				jr.Rows[i][j] = Value(v)
+			}
+		}
+	}
+	return nil
+}
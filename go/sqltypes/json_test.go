@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqltypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestJSONValueRoundTrip(t *testing.T) {
+	samples := []string{
+		"",
+		"0",
+		"-9223372036854775808",
+		"18446744073709551615",
+		"12345678901234567890.123456789",
+		"2025-01-02 03:04:05.678901",
+		`{"a": [1, "b"]}`,
+		"ünïcode \"quoted\"",
+		"\x00\xff\xfe binary",
+	}
+	for name, typ := range querypb.Type_value {
+		if querypb.Type(typ) == Null {
+			continue
+		}
+		for _, sample := range samples {
+			v := MakeTrusted(querypb.Type(typ), []byte(sample))
+			b, err := json.Marshal(JSONValue(v))
+			require.NoError(t, err)
+
+			var got JSONValue
+			require.NoError(t, json.Unmarshal(b, &got), "%s", b)
+			require.Equal(t, v.Type(), Value(got).Type(), "%s", b)
+			require.Equal(t, v.Raw(), Value(got).Raw(), "%s: %s", name, b)
+		}
+	}
+
+	b, err := json.Marshal(JSONValue(NULL))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type": "NULL_TYPE", "value": null}`, string(b))
+	var got JSONValue
+	require.NoError(t, json.Unmarshal(b, &got))
+	require.True(t, Value(got).IsNull())
+}
+
+func TestJSONValueEncoding(t *testing.T) {
+	testcases := []struct {
+		v    Value
+		want string
+	}{
+		{NewInt64(-1), `{"type": "INT64", "value": "-1"}`},
+		{NewUint64(18446744073709551615), `{"type": "UINT64", "value": "18446744073709551615"}`},
+		{TestValue(Decimal, "12345678901234567890.5"), `{"type": "DECIMAL", "value": "12345678901234567890.5"}`},
+		{NewVarChar("a\"b"), `{"type": "VARCHAR", "value": "a\"b"}`},
+		// Binary types are always base64 encoded, other types only when
+		// they aren't valid UTF-8.
+		{NewVarBinary("ab"), `{"type": "VARBINARY", "value": "YWI=", "encoding": "base64"}`},
+		{TestValue(Bit, "\x01"), `{"type": "BIT", "value": "AQ==", "encoding": "base64"}`},
+		{NewVarChar("\xff"), `{"type": "VARCHAR", "value": "/w==", "encoding": "base64"}`},
+	}
+	for _, tc := range testcases {
+		b, err := json.Marshal(JSONValue(tc.v))
+		require.NoError(t, err)
+		require.JSONEq(t, tc.want, string(b))
+	}
+}
+
+func TestJSONValueUnmarshalErrors(t *testing.T) {
+	testcases := []struct {
+		in      string
+		wantErr string
+	}{
+		{`{"type": "INT65", "value": "1"}`, `unknown value type "INT65"`},
+		{`{"type": "INT64", "value": null}`, "only NULL_TYPE values can be null"},
+		{`{"type": "NULL_TYPE", "value": "1"}`, "only NULL_TYPE values can be null"},
+		{`{"type": "BLOB", "value": "!", "encoding": "base64"}`, "invalid base64 BLOB value"},
+		{`{"type": "BLOB", "value": "00", "encoding": "hex"}`, `unknown value encoding "hex"`},
+		{`1`, "cannot unmarshal"},
+	}
+	for _, tc := range testcases {
+		var got JSONValue
+		require.ErrorContains(t, json.Unmarshal([]byte(tc.in), &got), tc.wantErr, tc.in)
+	}
+}
+
+func TestJSONResultRoundTrip(t *testing.T) {
+	qr := MakeTestResult(MakeTestFields("id|name|data", "int64|varchar|blob"), "1|a|\x00", "2|null|b")
+	qr.RowsAffected = 2
+	qr.Info = "info"
+
+	b, err := json.Marshal((*JSONResult)(qr))
+	require.NoError(t, err)
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(b, &raw))
+	require.Equal(t, []any{
+		map[string]any{"type": "INT64", "value": "1"},
+		map[string]any{"type": "VARCHAR", "value": "a"},
+		map[string]any{"type": "BLOB", "value": "AA==", "encoding": "base64"},
+	}, raw["rows"].([]any)[0])
+	require.Equal(t, "info", raw["info"])
+
+	got := &JSONResult{}
+	require.NoError(t, json.Unmarshal(b, got))
+	require.True(t, qr.Equal((*Result)(got)), "%v != %v", qr, got)
+	require.Equal(t, qr.RowsAffected, got.RowsAffected)
+	require.Equal(t, qr.Info, got.Info)
+}
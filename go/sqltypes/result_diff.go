@@ -0,0 +1,183 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqltypes
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ColumnDiff is a column whose value differs between two rows with the
+// same key.
+type ColumnDiff struct {
+	Name  string
+	Left  Value
+	Right Value
+}
+
+// RowDiff is a row present in both results, with at least one differing
+// column. Key holds the values of the key columns.
+type RowDiff struct {
+	Key     Row
+	Columns []ColumnDiff
+}
+
+// ResultDiff describes the differences between two results, as returned by
+// DiffResults.
+type ResultDiff struct {
+	// OnlyInLeft and OnlyInRight are the rows whose key is only found on
+	// one side, in the order of their result.
+	OnlyInLeft  []Row
+	OnlyInRight []Row
+	// Different are the rows found on both sides whose values differ, in
+	// the order of the left result.
+	Different []RowDiff
+	// LeftOnlyColumns and RightOnlyColumns are the columns that are only
+	// found on one side. They are not compared.
+	LeftOnlyColumns  []string
+	RightOnlyColumns []string
+}
+
+// IsEmpty returns true if the two results had the same columns and rows.
+func (rd *ResultDiff) IsEmpty() bool {
+	return len(rd.OnlyInLeft) == 0 && len(rd.OnlyInRight) == 0 && len(rd.Different) == 0 &&
+		len(rd.LeftOnlyColumns) == 0 && len(rd.RightOnlyColumns) == 0
+}
+
+// DiffResults compares the rows of left and right, matching them on the
+// values of keyColumns, and the columns by name. The key columns must be
+// present in both results and identify a single row on each side.
+//
+// Values are compared on their raw bytes, regardless of their type. NULL
+// is different from every other value, including the empty string.
+func DiffResults(left, right *Result, keyColumns []string) (*ResultDiff, error) {
+	if len(keyColumns) == 0 {
+		return nil, fmt.Errorf("no key columns to match the rows on")
+	}
+	leftColumns := columnIndexes(left)
+	rightColumns := columnIndexes(right)
+	var leftKey, rightKey []int
+	for _, name := range keyColumns {
+		li, lok := leftColumns[name]
+		ri, rok := rightColumns[name]
+		if !lok || !rok {
+			return nil, fmt.Errorf("key column %v is missing from the results", name)
+		}
+		leftKey = append(leftKey, li)
+		rightKey = append(rightKey, ri)
+	}
+
+	diff := &ResultDiff{}
+	// common holds the left and right indexes of the columns found on
+	// both sides, in the order of the left result.
+	var common [][2]int
+	for _, field := range left.Fields {
+		if ri, ok := rightColumns[field.Name]; ok {
+			common = append(common, [2]int{leftColumns[field.Name], ri})
+		} else {
+			diff.LeftOnlyColumns = append(diff.LeftOnlyColumns, field.Name)
+		}
+	}
+	for _, field := range right.Fields {
+		if _, ok := leftColumns[field.Name]; !ok {
+			diff.RightOnlyColumns = append(diff.RightOnlyColumns, field.Name)
+		}
+	}
+
+	rightRows := make(map[string]Row, len(right.Rows))
+	for _, row := range right.Rows {
+		key := rowKey(row, rightKey)
+		if _, ok := rightRows[key]; ok {
+			return nil, fmt.Errorf("duplicate key %v in the right result", keyString(row, rightKey))
+		}
+		rightRows[key] = row
+	}
+
+	leftRows := make(map[string]bool, len(left.Rows))
+	for _, lrow := range left.Rows {
+		key := rowKey(lrow, leftKey)
+		if leftRows[key] {
+			return nil, fmt.Errorf("duplicate key %v in the left result", keyString(lrow, leftKey))
+		}
+		leftRows[key] = true
+
+		rrow, ok := rightRows[key]
+		if !ok {
+			diff.OnlyInLeft = append(diff.OnlyInLeft, lrow)
+			continue
+		}
+		var columns []ColumnDiff
+		for _, c := range common {
+			lv, rv := lrow[c[0]], rrow[c[1]]
+			if !valuesEqual(lv, rv) {
+				columns = append(columns, ColumnDiff{Name: left.Fields[c[0]].Name, Left: lv, Right: rv})
+			}
+		}
+		if len(columns) > 0 {
+			key := make(Row, len(leftKey))
+			for i, ki := range leftKey {
+				key[i] = lrow[ki]
+			}
+			diff.Different = append(diff.Different, RowDiff{Key: key, Columns: columns})
+		}
+	}
+	for _, rrow := range right.Rows {
+		if !leftRows[rowKey(rrow, rightKey)] {
+			diff.OnlyInRight = append(diff.OnlyInRight, rrow)
+		}
+	}
+	return diff, nil
+}
+
+func columnIndexes(result *Result) map[string]int {
+	indexes := make(map[string]int, len(result.Fields))
+	for i, field := range result.Fields {
+		indexes[field.Name] = i
+	}
+	return indexes
+}
+
+func valuesEqual(a, b Value) bool {
+	return a.IsNull() == b.IsNull() && bytes.Equal(a.Raw(), b.Raw())
+}
+
+// rowKey encodes the key columns of row so that NULL and the empty string
+// can't collide, and neither can values that contain the separator.
+func rowKey(row Row, key []int) string {
+	var sb strings.Builder
+	for _, i := range key {
+		if row[i].IsNull() {
+			sb.WriteString("N;")
+			continue
+		}
+		raw := row[i].Raw()
+		sb.WriteString(strconv.Itoa(len(raw)))
+		sb.WriteByte(':')
+		sb.Write(raw)
+	}
+	return sb.String()
+}
+
+func keyString(row Row, key []int) string {
+	values := make([]string, len(key))
+	for i, ki := range key {
+		values[i] = row[ki].ToString()
+	}
+	return strings.Join(values, ", ")
+}
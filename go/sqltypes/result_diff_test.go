@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqltypes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffResults(t *testing.T) {
+	fields := MakeTestFields("id|name|value", "int64|varchar|varchar")
+
+	t.Run("identical", func(t *testing.T) {
+		left := MakeTestResult(fields, "1|a|x", "2|b|null")
+		right := MakeTestResult(fields, "2|b|null", "1|a|x")
+		diff, err := DiffResults(left, right, []string{"id"})
+		require.NoError(t, err)
+		require.True(t, diff.IsEmpty())
+	})
+
+	t.Run("rows on one side", func(t *testing.T) {
+		left := MakeTestResult(fields, "1|a|x", "2|b|y", "3|c|z")
+		right := MakeTestResult(fields, "4|d|w", "2|b|y")
+		diff, err := DiffResults(left, right, []string{"id"})
+		require.NoError(t, err)
+		require.False(t, diff.IsEmpty())
+		require.Equal(t, []Row{left.Rows[0], left.Rows[2]}, diff.OnlyInLeft)
+		require.Equal(t, []Row{right.Rows[0]}, diff.OnlyInRight)
+		require.Empty(t, diff.Different)
+	})
+
+	t.Run("differing columns", func(t *testing.T) {
+		left := MakeTestResult(fields, "1|a|x", "2|b|y")
+		right := MakeTestResult(fields, "1|a|x2", "2|B|y2")
+		diff, err := DiffResults(left, right, []string{"id"})
+		require.NoError(t, err)
+		require.Empty(t, diff.OnlyInLeft)
+		require.Empty(t, diff.OnlyInRight)
+		require.Equal(t, []RowDiff{{
+			Key: Row{NewInt64(1)},
+			Columns: []ColumnDiff{
+				{Name: "value", Left: NewVarChar("x"), Right: NewVarChar("x2")},
+			},
+		}, {
+			Key: Row{NewInt64(2)},
+			Columns: []ColumnDiff{
+				{Name: "name", Left: NewVarChar("b"), Right: NewVarChar("B")},
+				{Name: "value", Left: NewVarChar("y"), Right: NewVarChar("y2")},
+			},
+		}}, diff.Different)
+	})
+
+	t.Run("NULL is not the empty string", func(t *testing.T) {
+		left := MakeTestResult(fields, "1|a|null")
+		right := MakeTestResult(fields, "1|a|")
+		diff, err := DiffResults(left, right, []string{"id"})
+		require.NoError(t, err)
+		require.Equal(t, []RowDiff{{
+			Key:     Row{NewInt64(1)},
+			Columns: []ColumnDiff{{Name: "value", Left: NULL, Right: NewVarChar("")}},
+		}}, diff.Different)
+
+		// The same goes for key columns.
+		left = MakeTestResult(fields, "1|null|x")
+		right = MakeTestResult(fields, "1||x")
+		diff, err = DiffResults(left, right, []string{"id", "name"})
+		require.NoError(t, err)
+		require.Equal(t, left.Rows, diff.OnlyInLeft)
+		require.Equal(t, right.Rows, diff.OnlyInRight)
+		require.Empty(t, diff.Different)
+	})
+
+	t.Run("composite keys", func(t *testing.T) {
+		// Without an unambiguous encoding, ("a:", "b") and ("a", ":b")
+		// would be the same key.
+		left := MakeTestResult(fields, "1|a:|b")
+		right := MakeTestResult(MakeTestFields("value|name|id", "varchar|varchar|int64"), "b|a:|1", ":b|a|1")
+		diff, err := DiffResults(left, right, []string{"name", "value"})
+		require.NoError(t, err)
+		require.Empty(t, diff.OnlyInLeft)
+		require.Equal(t, []Row{right.Rows[1]}, diff.OnlyInRight)
+		require.Empty(t, diff.Different)
+	})
+
+	t.Run("differing field counts", func(t *testing.T) {
+		left := MakeTestResult(fields, "1|a|x", "2|b|y")
+		right := MakeTestResult(MakeTestFields("id|extra|name", "int64|varchar|varchar"), "1|e|a", "2|e|c")
+		diff, err := DiffResults(left, right, []string{"id"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"value"}, diff.LeftOnlyColumns)
+		require.Equal(t, []string{"extra"}, diff.RightOnlyColumns)
+		require.Equal(t, []RowDiff{{
+			Key:     Row{NewInt64(2)},
+			Columns: []ColumnDiff{{Name: "name", Left: NewVarChar("b"), Right: NewVarChar("c")}},
+		}}, diff.Different)
+		require.False(t, diff.IsEmpty())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		left := MakeTestResult(fields, "1|a|x")
+		_, err := DiffResults(left, left, nil)
+		require.ErrorContains(t, err, "no key columns")
+
+		right := MakeTestResult(MakeTestFields("name|value", "varchar|varchar"), "a|x")
+		_, err = DiffResults(left, right, []string{"id"})
+		require.ErrorContains(t, err, "key column id is missing")
+
+		_, err = DiffResults(MakeTestResult(fields, "1|a|x", "1|b|y"), left, []string{"id"})
+		require.ErrorContains(t, err, "duplicate key 1 in the left result")
+		_, err = DiffResults(left, MakeTestResult(fields, "1|a|x", "1|b|y"), []string{"id"})
+		require.ErrorContains(t, err, "duplicate key 1 in the right result")
+	})
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqltypes
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+)
+
+// ErrMismatchLimit is returned by StreamCompare when it stops after
+// reaching the configured number of mismatches.
+var ErrMismatchLimit = errors.New("mismatch limit reached")
+
+// RowStream is a source of rows, as consumed by StreamCompare. Next returns
+// a nil row once the stream is exhausted.
+type RowStream interface {
+	Next() (Row, error)
+}
+
+type rowSliceStream struct {
+	rows []Row
+}
+
+// NewRowSliceStream returns a RowStream over rows.
+func NewRowSliceStream(rows []Row) RowStream {
+	return &rowSliceStream{rows: rows}
+}
+
+// Next is part of the RowStream interface.
+func (s *rowSliceStream) Next() (Row, error) {
+	if len(s.rows) == 0 {
+		return nil, nil
+	}
+	row := s.rows[0]
+	s.rows = s.rows[1:]
+	return row, nil
+}
+
+type rowChanStream <-chan Row
+
+// NewRowChanStream returns a RowStream that reads rows from ch until it is
+// closed.
+func NewRowChanStream(ch <-chan Row) RowStream {
+	return rowChanStream(ch)
+}
+
+// Next is part of the RowStream interface.
+func (s rowChanStream) Next() (Row, error) {
+	return <-s, nil
+}
+
+// Mismatch is a difference found by StreamCompare. Left or Right is nil
+// when the row is only found on the other side. Otherwise, Columns holds
+// the indexes of the columns that differ.
+type Mismatch struct {
+	Left    Row
+	Right   Row
+	Columns []int
+}
+
+// StreamCompareOptions configures StreamCompare.
+type StreamCompareOptions struct {
+	// KeyColumns are the indexes of the columns both streams are ordered
+	// by. They must be unique on each side.
+	KeyColumns []int
+	// CompareKeys orders two rows by their key. If nil, the key columns
+	// are compared one after the other with CompareKeyValues.
+	CompareKeys func(left, right Row) int
+	// OnMismatch is called for each mismatch, in key order. Returning an
+	// error stops the comparison.
+	OnMismatch func(*Mismatch) error
+	// MaxMismatches stops the comparison with ErrMismatchLimit once that
+	// many mismatches are found. Zero means no limit.
+	MaxMismatches int64
+}
+
+// StreamCompareStats are the counts of rows processed by StreamCompare.
+// Compared counts the keys seen on either side, so that Compared is always
+// Matched + Mismatched.
+type StreamCompareStats struct {
+	Compared   int64
+	Matched    int64
+	Mismatched int64
+}
+
+// StreamCompare compares two streams of rows ordered by the same key,
+// reporting the mismatches as it goes instead of loading either side in
+// memory. The rows of both streams must have the same columns. Values are
+// compared on their raw bytes, so NULL differs from the empty string.
+//
+// The stats are returned along with any error, and cover the rows
+// processed until then.
+func StreamCompare(left, right RowStream, opts StreamCompareOptions) (StreamCompareStats, error) {
+	var stats StreamCompareStats
+	if len(opts.KeyColumns) == 0 && opts.CompareKeys == nil {
+		return stats, fmt.Errorf("no key columns to match the rows on")
+	}
+	compareKeys := opts.CompareKeys
+	if compareKeys == nil {
+		compareKeys = func(l, r Row) int {
+			for _, i := range opts.KeyColumns {
+				if c := CompareKeyValues(l[i], r[i]); c != 0 {
+					return c
+				}
+			}
+			return 0
+		}
+	}
+
+	mismatch := func(m *Mismatch) error {
+		stats.Mismatched++
+		if opts.OnMismatch != nil {
+			if err := opts.OnMismatch(m); err != nil {
+				return err
+			}
+		}
+		if opts.MaxMismatches > 0 && stats.Mismatched >= opts.MaxMismatches {
+			return ErrMismatchLimit
+		}
+		return nil
+	}
+
+	lrow, err := left.Next()
+	if err != nil {
+		return stats, fmt.Errorf("left stream: %w", err)
+	}
+	rrow, err := right.Next()
+	if err != nil {
+		return stats, fmt.Errorf("right stream: %w", err)
+	}
+	for lrow != nil || rrow != nil {
+		stats.Compared++
+		var c int
+		switch {
+		case lrow == nil:
+			c = 1
+		case rrow == nil:
+			c = -1
+		default:
+			c = compareKeys(lrow, rrow)
+		}
+
+		switch {
+		case c < 0:
+			err = mismatch(&Mismatch{Left: lrow})
+		case c > 0:
+			err = mismatch(&Mismatch{Right: rrow})
+		default:
+			if columns := diffRowColumns(lrow, rrow); len(columns) > 0 {
+				err = mismatch(&Mismatch{Left: lrow, Right: rrow, Columns: columns})
+			} else {
+				stats.Matched++
+			}
+		}
+		if err != nil {
+			return stats, err
+		}
+
+		if c <= 0 {
+			if lrow, err = left.Next(); err != nil {
+				return stats, fmt.Errorf("left stream: %w", err)
+			}
+		}
+		if c >= 0 {
+			if rrow, err = right.Next(); err != nil {
+				return stats, fmt.Errorf("right stream: %w", err)
+			}
+		}
+	}
+	return stats, nil
+}
+
+func diffRowColumns(left, right Row) []int {
+	var columns []int
+	n := max(len(left), len(right))
+	for i := 0; i < n; i++ {
+		if i >= len(left) || i >= len(right) || !valuesEqual(left[i], right[i]) {
+			columns = append(columns, i)
+		}
+	}
+	return columns
+}
+
+// CompareKeyValues orders two key values: NULL first, then integers and
+// floating point numbers numerically, and everything else by its raw
+// bytes, as with a binary collation.
+func CompareKeyValues(a, b Value) int {
+	switch {
+	case a.IsNull() && b.IsNull():
+		return 0
+	case a.IsNull():
+		return -1
+	case b.IsNull():
+		return 1
+	}
+
+	at, bt := a.Type(), b.Type()
+	switch {
+	case IsSigned(at) && IsSigned(bt):
+		ai, aerr := a.ToInt64()
+		bi, berr := b.ToInt64()
+		if aerr == nil && berr == nil {
+			return cmp.Compare(ai, bi)
+		}
+	case IsIntegral(at) && IsIntegral(bt):
+		// At least one side is unsigned: a negative signed value is
+		// smaller, everything else fits in an uint64.
+		if IsSigned(at) && bytes.HasPrefix(a.Raw(), []byte("-")) {
+			return -1
+		}
+		if IsSigned(bt) && bytes.HasPrefix(b.Raw(), []byte("-")) {
+			return 1
+		}
+		au, aerr := a.ToUint64()
+		bu, berr := b.ToUint64()
+		if aerr == nil && berr == nil {
+			return cmp.Compare(au, bu)
+		}
+	case (IsIntegral(at) || IsFloat(at)) && (IsIntegral(bt) || IsFloat(bt)):
+		af, aerr := a.ToFloat64()
+		bf, berr := b.ToFloat64()
+		if aerr == nil && berr == nil {
+			return cmp.Compare(af, bf)
+		}
+	}
+	return bytes.Compare(a.Raw(), b.Raw())
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqltypes
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type errorRowStream struct {
+	RowStream
+	err error
+}
+
+func (s *errorRowStream) Next() (Row, error) {
+	row, err := s.RowStream.Next()
+	if row == nil && err == nil {
+		return nil, s.err
+	}
+	return row, err
+}
+
+func TestStreamCompare(t *testing.T) {
+	fields := MakeTestFields("id|name", "int64|varchar")
+	rows := func(rows ...string) RowStream {
+		return NewRowSliceStream(MakeTestResult(fields, rows...).Rows)
+	}
+	collect := func(mismatches *[]*Mismatch) func(*Mismatch) error {
+		return func(m *Mismatch) error {
+			*mismatches = append(*mismatches, m)
+			return nil
+		}
+	}
+
+	t.Run("mismatches", func(t *testing.T) {
+		var mismatches []*Mismatch
+		stats, err := StreamCompare(
+			rows("1|a", "2|b", "3|c", "10|j", "11|k"),
+			rows("1|a", "3|C", "4|d", "10|j"),
+			StreamCompareOptions{KeyColumns: []int{0}, OnMismatch: collect(&mismatches)},
+		)
+		require.NoError(t, err)
+		require.Equal(t, StreamCompareStats{Compared: 6, Matched: 2, Mismatched: 4}, stats)
+		require.Equal(t, []*Mismatch{
+			{Left: Row{NewInt64(2), NewVarChar("b")}},
+			{Left: Row{NewInt64(3), NewVarChar("c")}, Right: Row{NewInt64(3), NewVarChar("C")}, Columns: []int{1}},
+			{Right: Row{NewInt64(4), NewVarChar("d")}},
+			// The right side ended early.
+			{Left: Row{NewInt64(11), NewVarChar("k")}},
+		}, mismatches)
+	})
+
+	t.Run("NULL is not the empty string", func(t *testing.T) {
+		var mismatches []*Mismatch
+		stats, err := StreamCompare(rows("null|x", "1|null"), rows("null|x", "1|"),
+			StreamCompareOptions{KeyColumns: []int{0}, OnMismatch: collect(&mismatches)})
+		require.NoError(t, err)
+		require.Equal(t, StreamCompareStats{Compared: 2, Matched: 1, Mismatched: 1}, stats)
+		require.Equal(t, []int{1}, mismatches[0].Columns)
+	})
+
+	t.Run("channels", func(t *testing.T) {
+		left := make(chan Row)
+		right := make(chan Row)
+		go func() {
+			defer close(left)
+			for _, row := range MakeTestResult(fields, "1|a", "2|b").Rows {
+				left <- row
+			}
+		}()
+		close(right)
+		stats, err := StreamCompare(NewRowChanStream(left), NewRowChanStream(right), StreamCompareOptions{KeyColumns: []int{0}})
+		require.NoError(t, err)
+		require.Equal(t, StreamCompareStats{Compared: 2, Mismatched: 2}, stats)
+	})
+
+	t.Run("mismatch limit", func(t *testing.T) {
+		var mismatches []*Mismatch
+		stats, err := StreamCompare(rows("1|a", "2|b", "3|c", "4|d"), rows("4|d"),
+			StreamCompareOptions{KeyColumns: []int{0}, OnMismatch: collect(&mismatches), MaxMismatches: 2})
+		require.ErrorIs(t, err, ErrMismatchLimit)
+		require.Equal(t, StreamCompareStats{Compared: 2, Mismatched: 2}, stats)
+		require.Len(t, mismatches, 2)
+	})
+
+	t.Run("callback error", func(t *testing.T) {
+		stop := errors.New("stop")
+		stats, err := StreamCompare(rows("1|a", "2|b"), rows(), StreamCompareOptions{
+			KeyColumns: []int{0},
+			OnMismatch: func(*Mismatch) error { return stop },
+		})
+		require.ErrorIs(t, err, stop)
+		require.Equal(t, StreamCompareStats{Compared: 1, Mismatched: 1}, stats)
+	})
+
+	t.Run("stream error", func(t *testing.T) {
+		broken := errors.New("broken")
+		stats, err := StreamCompare(rows("1|a", "2|b"), &errorRowStream{RowStream: rows("1|a"), err: broken},
+			StreamCompareOptions{KeyColumns: []int{0}})
+		require.ErrorIs(t, err, broken)
+		require.ErrorContains(t, err, "right stream")
+		require.Equal(t, StreamCompareStats{Compared: 1, Matched: 1}, stats)
+	})
+
+	t.Run("custom key order", func(t *testing.T) {
+		// Both streams are ordered by descending id.
+		stats, err := StreamCompare(rows("3|c", "2|b", "1|a"), rows("3|c", "1|a"), StreamCompareOptions{
+			CompareKeys: func(left, right Row) int { return CompareKeyValues(right[0], left[0]) },
+		})
+		require.NoError(t, err)
+		require.Equal(t, StreamCompareStats{Compared: 3, Matched: 2, Mismatched: 1}, stats)
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		_, err := StreamCompare(rows(), rows(), StreamCompareOptions{})
+		require.ErrorContains(t, err, "no key columns")
+	})
+}
+
+func TestCompareKeyValues(t *testing.T) {
+	testcases := []struct {
+		a, b Value
+		want int
+	}{
+		{NULL, NULL, 0},
+		{NULL, NewVarChar(""), -1},
+		{NewVarChar(""), NULL, 1},
+		{NewInt64(9), NewInt64(10), -1},
+		{NewInt64(-1), NewUint64(0), -1},
+		{NewUint64(18446744073709551615), NewInt64(1), 1},
+		{NewUint64(10), NewInt32(10), 0},
+		{NewFloat64(1.5), NewInt64(2), -1},
+		{NewVarChar("b"), NewVarChar("a"), 1},
+		{NewVarChar("B"), NewVarChar("a"), -1},
+		{NewVarChar("a"), NewVarChar("a"), 0},
+	}
+	for _, tc := range testcases {
+		require.Equal(t, tc.want, CompareKeyValues(tc.a, tc.b), "%v vs %v", tc.a, tc.b)
+	}
+}
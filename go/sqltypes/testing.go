@@ -86,6 +86,58 @@ func MakeTestResult(fields []*querypb.Field, rows ...string) *Result {
 	return result
 }
 
+// MakeTestNamedResult builds a *sqltypes.Result from rows given as
+// column name to value maps, for fixtures with many columns.
+//
+//	result := sqltypes.MakeTestNamedResult(
+//	  sqltypes.MakeTestFields("id|name|active", "int64|varchar|char"),
+//	  map[string]string{"active": "Y"},
+//	  map[string]string{"id": "1", "name": "a"},
+//	  map[string]string{"id": "2", "name": "b", "active": "N"},
+//	)
+//
+// The columns a row doesn't list take their value from defaults, or
+// are NULL if defaults doesn't list them either. As with MakeTestResult,
+// "null" is treated as NULL and the values get the field types. It
+// panics if a row or defaults lists a column that isn't in fields.
+func MakeTestNamedResult(fields []*querypb.Field, defaults map[string]string, rows ...map[string]string) *Result {
+	indexes := make(map[string]int, len(fields))
+	for i, field := range fields {
+		indexes[field.Name] = i
+	}
+	checkColumns := func(values map[string]string) {
+		for name := range values {
+			if _, ok := indexes[name]; !ok {
+				panic(fmt.Sprintf("unknown column %v", name))
+			}
+		}
+	}
+	checkColumns(defaults)
+
+	result := &Result{
+		Fields: fields,
+	}
+	if len(rows) > 0 {
+		result.Rows = make([][]Value, len(rows))
+	}
+	for i, row := range rows {
+		checkColumns(row)
+		result.Rows[i] = make([]Value, len(fields))
+		for j, field := range fields {
+			col, ok := row[field.Name]
+			if !ok {
+				col, ok = defaults[field.Name]
+			}
+			if !ok || strings.ToLower(col) == "null" {
+				result.Rows[i][j] = NULL
+				continue
+			}
+			result.Rows[i][j] = MakeTrusted(field.Type, []byte(col))
+		}
+	}
+	return result
+}
+
 // MakeTestStreamingResults builds a list of results for streaming.
 //
 //	  results := sqltypes.MakeStreamingResults(
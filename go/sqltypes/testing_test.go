@@ -0,0 +1,41 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqltypes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeTestNamedResult(t *testing.T) {
+	fields := MakeTestFields("id|name|active|note", "int64|varchar|char|varchar")
+	got := MakeTestNamedResult(fields,
+		map[string]string{"active": "Y"},
+		map[string]string{"id": "1", "name": "a"},
+		map[string]string{"id": "2", "name": "null", "active": "N", "note": ""},
+	)
+	want := MakeTestResult(fields, "1|a|Y|null", "2|null|N|")
+	require.Equal(t, want, got)
+
+	require.PanicsWithValue(t, "unknown column nme", func() {
+		MakeTestNamedResult(fields, nil, map[string]string{"nme": "a"})
+	})
+	require.PanicsWithValue(t, "unknown column enabled", func() {
+		MakeTestNamedResult(fields, map[string]string{"enabled": "Y"})
+	})
+}
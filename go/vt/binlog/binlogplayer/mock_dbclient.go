@@ -63,6 +63,7 @@ func NewMockDBClient(t *testing.T) *MockDBClient {
 			"CREATE TABLE IF NOT EXISTS _vt.vreplication_log":           {},
 			"select id, type, state, message from _vt.vreplication_log": {},
 			"insert into _vt.vreplication_log":                          {},
+			"select id from _vt.vreplication_log where vrepl_id":        {},
 			// The following statements don't have a deterministic order as they are
 			// executed in the normal program flow, but ALSO done in a defer as a protective
 			// measure as they are resetting the values back to the original one. This also
@@ -104,6 +104,10 @@ type TabletPickerOptions struct {
 	TabletOrder                         string
 	IncludeNonServingTablets            bool
 	ExcludeTabletsWithMaxReplicationLag time.Duration
+	// RetryDelay is how long PickForStreaming waits before looking for a
+	// tablet again when none was found. If it is zero, the delay set with
+	// SetTabletPickerRetryDelay is used.
+	RetryDelay time.Duration
 }
 
 func parseTabletPickerCellPreferenceString(str string) (TabletPickerCellPreference, error) {
@@ -340,6 +344,14 @@ func (tp *TabletPicker) sortCandidates(ctx context.Context, candidates []*topo.T
 	return candidates
 }
 
+// retryDelay returns how long to wait before looking for a tablet again.
+func (tp *TabletPicker) retryDelay() time.Duration {
+	if tp.options.RetryDelay > 0 {
+		return tp.options.RetryDelay
+	}
+	return GetTabletPickerRetryDelay()
+}
+
 // PickForStreaming picks a tablet that is healthy and serving.
 // Selection is based on CellPreference.
 // See prioritizeTablets for prioritization logic.
@@ -358,8 +370,8 @@ func (tp *TabletPicker) PickForStreaming(ctx context.Context) (*topodatapb.Table
 			// If no viable candidates were found, sleep and try again.
 			tp.incNoTabletFoundStat()
 			log.Infof("No healthy serving tablet found for streaming, shard %s.%s, cells %v, tabletTypes %v, maxReplicationLag: %v, sleeping for %.3f seconds.",
-				tp.keyspace, tp.shard, tp.cells, tp.tabletTypes, tp.options.ExcludeTabletsWithMaxReplicationLag, float64(GetTabletPickerRetryDelay().Milliseconds())/1000.0)
-			timer := time.NewTimer(GetTabletPickerRetryDelay())
+				tp.keyspace, tp.shard, tp.cells, tp.tabletTypes, tp.options.ExcludeTabletsWithMaxReplicationLag, float64(tp.retryDelay().Milliseconds())/1000.0)
+			timer := time.NewTimer(tp.retryDelay())
 			select {
 			case <-ctx.Done():
 				timer.Stop()
@@ -380,23 +380,49 @@ func EvenShardsKeyRange(i, n int) (*topodatapb.KeyRange, error) {
 
 // GenerateShardRanges returns shard ranges assuming a keyspace with N shards.
 func GenerateShardRanges(shards int) ([]string, error) {
-	var format string
-	var maxShards int
+	return GenerateShardRangesWithWidth(shards, 0, true)
+}
+
+// maxShardRangeKeyWidth is the widest shard range boundary, in bytes, that
+// GenerateShardRangesWithWidth supports.
+const maxShardRangeKeyWidth = 4
 
+// GenerateShardRangesWithWidth returns shard ranges assuming a keyspace with N
+// shards, with boundaries that are keyWidth bytes wide. A keyWidth of 0 uses
+// one byte for up to 256 shards and two bytes for more.
+//
+// Only a power of two number of shards splits the key space into ranges of
+// the same size, so other numbers are rejected unless allowUneven is set.
+// The boundaries of uneven shards are then computed with integer math, as
+// i * (key space size) / N, so the remainder of the key space is spread over
+// the shards rather than tacked on to the last one, and the ranges differ in
+// size by one boundary unit at most.
+func GenerateShardRangesWithWidth(shards, keyWidth int, allowUneven bool) ([]string, error) {
 	switch {
 	case shards <= 0:
 		return nil, errors.New("shards must be greater than zero")
-	case shards <= 256:
-		format = "%02x"
-		maxShards = 256
-	case shards <= 65536:
-		format = "%04x"
-		maxShards = 65536
-	default:
-		return nil, errors.New("this function does not support more than 65336 shards in a single keyspace")
+	case shards > 65536:
+		return nil, errors.New("this function does not support more than 65536 shards in a single keyspace")
+	case keyWidth < 0 || keyWidth > maxShardRangeKeyWidth:
+		return nil, fmt.Errorf("the key width must be between 1 and %d bytes: %d", maxShardRangeKeyWidth, keyWidth)
+	}
+
+	if keyWidth == 0 {
+		keyWidth = 1
+		if shards > 256 {
+			keyWidth = 2
+		}
+	}
+	maxShards := uint64(1) << (8 * keyWidth)
+	if uint64(shards) > maxShards {
+		return nil, fmt.Errorf("%d shards do not fit in a key width of %d bytes", shards, keyWidth)
+	}
+	if !allowUneven && shards&(shards-1) != 0 {
+		return nil, fmt.Errorf("%d shards do not split the key space evenly, the shard count must be a power of two", shards)
 	}
 
-	rangeFormatter := func(start, end int) string {
+	format := fmt.Sprintf("%%0%dx", 2*keyWidth)
+	rangeFormatter := func(start, end uint64) string {
 		var (
 			startKid string
 			endKid   string
@@ -413,35 +439,10 @@ func GenerateShardRanges(shards int) ([]string, error) {
 		return fmt.Sprintf("%s-%s", startKid, endKid)
 	}
 
-	start := 0
-	end := 0
-
-	// If shards does not divide evenly into maxShards, then there is some lossiness,
-	// where each shard is smaller than it should technically be (if, for example, size == 25.6).
-	// If we choose to keep everything in ints, then we have two choices:
-	// 	- Have every shard in #numshards be a uniform size, tack on an additional shard
-	//	  at the end of the range to account for the loss. This is bad because if you ask for
-	//	  7 shards, you'll actually get 7 uniform shards with 1 small shard, for 8 total shards.
-	//	  It's also bad because one shard will have much different data distribution than the rest.
-	//	- Expand the final shard to include whatever is left in the keyrange. This will give the
-	//	  correct number of shards, which is good, but depending on how lossy each individual shard is,
-	//	  you could end with that final shard being significantly larger than the rest of the shards,
-	//	  so this doesn't solve the data distribution problem.
-	//
-	// By tracking the "real" end (both in the real number sense, and in the truthfulness of the value sense),
-	// we can re-truncate the integer end on each iteration, which spreads the lossiness more
-	// evenly across the shards.
-	//
-	// This implementation has no impact on shard numbers that are powers of 2, even at large numbers,
-	// which you can see in the tests.
-	size := float64(maxShards) / float64(shards)
-	realEnd := float64(0)
 	shardRanges := make([]string, 0, shards)
-
+	start := uint64(0)
 	for i := 1; i <= shards; i++ {
-		realEnd = float64(i) * size
-
-		end = int(realEnd)
+		end := uint64(i) * maxShards / uint64(shards)
 		shardRanges = append(shardRanges, rangeFormatter(start, end))
 		start = end
 	}
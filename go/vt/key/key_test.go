@@ -1545,6 +1545,72 @@ func TestGenerateShardRanges(t *testing.T) {
 	}
 }
 
+func TestGenerateShardRangesWithWidth(t *testing.T) {
+	tests := []struct {
+		name        string
+		shards      int
+		keyWidth    int
+		allowUneven bool
+		want        []string
+		wantErr     string
+	}{
+		{
+			name:   "even shards",
+			shards: 4,
+			want:   []string{"-40", "40-80", "80-c0", "c0-"},
+		},
+		{
+			name:     "wider keys",
+			shards:   4,
+			keyWidth: 2,
+			want:     []string{"-4000", "4000-8000", "8000-c000", "c000-"},
+		},
+		{
+			name:    "uneven shards",
+			shards:  3,
+			wantErr: "3 shards do not split the key space evenly",
+		},
+		{
+			name:        "allowed uneven shards",
+			shards:      3,
+			allowUneven: true,
+			want:        []string{"-55", "55-aa", "aa-"},
+		},
+		{
+			name:        "allowed uneven shards with wider keys",
+			shards:      3,
+			keyWidth:    2,
+			allowUneven: true,
+			want:        []string{"-5555", "5555-aaaa", "aaaa-"},
+		},
+		{
+			name:     "too many shards for the key width",
+			shards:   512,
+			keyWidth: 1,
+			wantErr:  "512 shards do not fit in a key width of 1 bytes",
+		},
+		{
+			name:     "key width too wide",
+			shards:   2,
+			keyWidth: 5,
+			wantErr:  "the key width must be between 1 and 4 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateShardRangesWithWidth(tt.shards, tt.keyWidth, tt.allowUneven)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestShardCalculatorForShardsGreaterThan512(t *testing.T) {
 	got, err := GenerateShardRanges(512)
 	assert.NoError(t, err)
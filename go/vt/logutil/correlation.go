@@ -0,0 +1,138 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logutil
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+)
+
+// The datatype for the correlation ID Context Key
+type correlationIDKey struct{}
+
+// NewCorrelationID returns a new random ID, used to attribute the log
+// events of one command.
+func NewCorrelationID() string {
+	return fmt.Sprintf("%016x", rand.Uint64())
+}
+
+// NewContextWithCorrelationID adds the correlation ID into the Context.
+func NewContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in the
+// Context, if any.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// EnsureCorrelationID returns ctx and its correlation ID if it has one,
+// or a new context with a new correlation ID otherwise.
+func EnsureCorrelationID(ctx context.Context) (context.Context, string) {
+	if id, ok := CorrelationIDFromContext(ctx); ok {
+		return ctx, id
+	}
+	id := NewCorrelationID()
+	return NewContextWithCorrelationID(ctx, id), id
+}
+
+// NewCorrelationIDLogger returns a logger that prefixes the events it sends
+// to logger with the correlation ID of ctx, or logger itself if ctx has no
+// correlation ID. It is meant for the server log of a command, the output
+// of the command to its client is not prefixed.
+func NewCorrelationIDLogger(ctx context.Context, logger Logger) Logger {
+	id, ok := CorrelationIDFromContext(ctx)
+	if !ok {
+		return logger
+	}
+	return NewPrefixLogger(logger, "["+id+"] ")
+}
+
+// PrefixLogger is a Logger that adds a prefix to the INFO, WARNING and
+// ERROR events it sends to the underlying logger. Printf output is
+// forwarded as is, as it is usually the result of a command.
+type PrefixLogger struct {
+	logger Logger
+	prefix string
+}
+
+// NewPrefixLogger returns a logger that prefixes the events it sends to
+// logger with prefix.
+func NewPrefixLogger(logger Logger, prefix string) *PrefixLogger {
+	return &PrefixLogger{
+		logger: logger,
+		prefix: prefix,
+	}
+}
+
+// InfoDepth is part of the Logger interface
+func (pl *PrefixLogger) InfoDepth(depth int, s string) {
+	pl.logger.InfoDepth(1+depth, pl.prefix+s)
+}
+
+// WarningDepth is part of the Logger interface
+func (pl *PrefixLogger) WarningDepth(depth int, s string) {
+	pl.logger.WarningDepth(1+depth, pl.prefix+s)
+}
+
+// ErrorDepth is part of the Logger interface
+func (pl *PrefixLogger) ErrorDepth(depth int, s string) {
+	pl.logger.ErrorDepth(1+depth, pl.prefix+s)
+}
+
+// InfoKVDepth is part of the KVLogger interface
+func (pl *PrefixLogger) InfoKVDepth(depth int, msg string, kv ...any) {
+	infoKVDepth(pl.logger, 1+depth, pl.prefix+msg, kv)
+}
+
+// WarningKVDepth is part of the KVLogger interface
+func (pl *PrefixLogger) WarningKVDepth(depth int, msg string, kv ...any) {
+	warningKVDepth(pl.logger, 1+depth, pl.prefix+msg, kv)
+}
+
+// Infof is part of the Logger interface
+func (pl *PrefixLogger) Infof(format string, v ...any) {
+	pl.InfoDepth(1, fmt.Sprintf(format, v...))
+}
+
+// Warningf is part of the Logger interface
+func (pl *PrefixLogger) Warningf(format string, v ...any) {
+	pl.WarningDepth(1, fmt.Sprintf(format, v...))
+}
+
+// Errorf is part of the Logger interface
+func (pl *PrefixLogger) Errorf(format string, v ...any) {
+	pl.ErrorDepth(1, fmt.Sprintf(format, v...))
+}
+
+// Errorf2 is part of the Logger interface
+func (pl *PrefixLogger) Errorf2(err error, format string, v ...any) {
+	pl.ErrorDepth(1, fmt.Sprintf(format+": %+v", append(v, err)))
+}
+
+// Error is part of the Logger interface
+func (pl *PrefixLogger) Error(err error) {
+	pl.ErrorDepth(1, fmt.Sprintf("%+v", err))
+}
+
+// Printf is part of the Logger interface
+func (pl *PrefixLogger) Printf(format string, v ...any) {
+	pl.logger.Printf(format, v...)
+}
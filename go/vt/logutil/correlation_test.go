@@ -0,0 +1,96 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrelationID(t *testing.T) {
+	ctx := context.Background()
+	_, ok := CorrelationIDFromContext(ctx)
+	require.False(t, ok)
+
+	id := NewCorrelationID()
+	require.Len(t, id, 16)
+	require.NotEqual(t, id, NewCorrelationID())
+
+	got, ok := CorrelationIDFromContext(NewContextWithCorrelationID(ctx, id))
+	require.True(t, ok)
+	require.Equal(t, id, got)
+
+	// An existing correlation ID is kept, and a new one is added otherwise.
+	idCtx, got := EnsureCorrelationID(NewContextWithCorrelationID(ctx, id))
+	require.Equal(t, id, got)
+	got, ok = CorrelationIDFromContext(idCtx)
+	require.True(t, ok)
+	require.Equal(t, id, got)
+	newCtx, newID := EnsureCorrelationID(ctx)
+	require.NotEqual(t, id, newID)
+	got, ok = CorrelationIDFromContext(newCtx)
+	require.True(t, ok)
+	require.Equal(t, newID, got)
+}
+
+func TestCorrelationIDLogger(t *testing.T) {
+	ml := NewMemoryLogger()
+	require.Same(t, ml, NewCorrelationIDLogger(context.Background(), ml))
+
+	// The ID is read from the context of the command, also from the
+	// goroutines it starts.
+	ctx := NewContextWithCorrelationID(context.Background(), "abc")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		NewCorrelationIDLogger(ctx, ml).Infof("from goroutine")
+	}()
+	<-done
+	NewCorrelationIDLogger(ctx, ml).Infof("info")
+
+	require.Len(t, ml.Events, 2)
+	require.Equal(t, "[abc] from goroutine", ml.Events[0].Value)
+	require.Equal(t, "[abc] info", ml.Events[1].Value)
+}
+
+func TestPrefixLogger(t *testing.T) {
+	ml := NewMemoryLogger()
+	pl := NewPrefixLogger(ml, "[abc] ")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pl.Warningf("from goroutine %v", 1)
+	}()
+	<-done
+	pl.Infof("info %v", 2)
+	pl.Errorf("error %v", 3)
+	InfoKV(pl, "kv", "tablet", "zone1-0000000101")
+	pl.Printf("console %v", 4)
+
+	require.Len(t, ml.Events, 5)
+	require.Equal(t, "[abc] from goroutine 1", ml.Events[0].Value)
+	require.Equal(t, "[abc] info 2", ml.Events[1].Value)
+	require.Equal(t, "[abc] error 3", ml.Events[2].Value)
+	require.Equal(t, "[abc] kv tablet=zone1-0000000101", ml.Events[3].Value)
+	require.Equal(t, "console 4", ml.Events[4].Value)
+	for _, event := range ml.Events[:4] {
+		require.Equal(t, "correlation_test.go", event.File)
+	}
+}
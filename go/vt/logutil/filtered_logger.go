@@ -0,0 +1,157 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logutil
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+)
+
+// FilteredLogger is a Logger that only forwards the events at or above a
+// minimum level to the underlying logger. Printf output is treated as
+// INFO, unless the logger was created by NewFilteredClientLogger. The
+// minimum level is read on every event, so it can be changed at any time,
+// for instance to filter one side of a TeeLogger.
+type FilteredLogger struct {
+	logger      Logger
+	minLevel    *atomic.Int32
+	keepConsole bool
+}
+
+// NewFilteredLogger returns a logger that forwards the events at or above
+// minLevel to logger.
+func NewFilteredLogger(logger Logger, minLevel logutilpb.Level) *FilteredLogger {
+	fl := &FilteredLogger{logger: logger, minLevel: new(atomic.Int32)}
+	fl.SetMinLevel(minLevel)
+	return fl
+}
+
+// NewFilteredClientLogger returns a logger that forwards the events at or
+// above minLevel to logger, and all the Printf output. It filters the log
+// events streamed back to a client, for which Printf output is the result
+// of its command rather than a log.
+func NewFilteredClientLogger(logger Logger, minLevel logutilpb.Level) *FilteredLogger {
+	fl := NewFilteredLogger(logger, minLevel)
+	fl.keepConsole = true
+	return fl
+}
+
+// ParseLevel returns the INFO, WARNING or ERROR level named by s, in any
+// case.
+func ParseLevel(s string) (logutilpb.Level, error) {
+	level, ok := logutilpb.Level_value[strings.ToUpper(s)]
+	if !ok || logutilpb.Level(level) == logutilpb.Level_CONSOLE {
+		return logutilpb.Level_INFO, fmt.Errorf("invalid log level %q, expected one of INFO, WARNING or ERROR", s)
+	}
+	return logutilpb.Level(level), nil
+}
+
+// SetMinLevel changes the minimum level of the events to forward. For the
+// loggers returned by NewConsoleLoggerFromFlags, it changes the level of
+// all of them.
+func (fl *FilteredLogger) SetMinLevel(minLevel logutilpb.Level) {
+	fl.minLevel.Store(int32(minLevel))
+}
+
+// MinLevel returns the minimum level of the events to forward.
+func (fl *FilteredLogger) MinLevel() logutilpb.Level {
+	return logutilpb.Level(fl.minLevel.Load())
+}
+
+func (fl *FilteredLogger) enabled(level logutilpb.Level) bool {
+	return int32(level) >= fl.minLevel.Load()
+}
+
+// InfoDepth is part of the Logger interface
+func (fl *FilteredLogger) InfoDepth(depth int, s string) {
+	if fl.enabled(logutilpb.Level_INFO) {
+		fl.logger.InfoDepth(1+depth, s)
+	}
+}
+
+// WarningDepth is part of the Logger interface
+func (fl *FilteredLogger) WarningDepth(depth int, s string) {
+	if fl.enabled(logutilpb.Level_WARNING) {
+		fl.logger.WarningDepth(1+depth, s)
+	}
+}
+
+// ErrorDepth is part of the Logger interface
+func (fl *FilteredLogger) ErrorDepth(depth int, s string) {
+	if fl.enabled(logutilpb.Level_ERROR) {
+		fl.logger.ErrorDepth(1+depth, s)
+	}
+}
+
+// InfoKVDepth is part of the KVLogger interface
+func (fl *FilteredLogger) InfoKVDepth(depth int, msg string, kv ...any) {
+	if fl.enabled(logutilpb.Level_INFO) {
+		infoKVDepth(fl.logger, 1+depth, msg, kv)
+	}
+}
+
+// WarningKVDepth is part of the KVLogger interface
+func (fl *FilteredLogger) WarningKVDepth(depth int, msg string, kv ...any) {
+	if fl.enabled(logutilpb.Level_WARNING) {
+		warningKVDepth(fl.logger, 1+depth, msg, kv)
+	}
+}
+
+// Infof is part of the Logger interface
+func (fl *FilteredLogger) Infof(format string, v ...any) {
+	if fl.enabled(logutilpb.Level_INFO) {
+		fl.logger.InfoDepth(1, fmt.Sprintf(format, v...))
+	}
+}
+
+// Warningf is part of the Logger interface
+func (fl *FilteredLogger) Warningf(format string, v ...any) {
+	if fl.enabled(logutilpb.Level_WARNING) {
+		fl.logger.WarningDepth(1, fmt.Sprintf(format, v...))
+	}
+}
+
+// Errorf is part of the Logger interface
+func (fl *FilteredLogger) Errorf(format string, v ...any) {
+	if fl.enabled(logutilpb.Level_ERROR) {
+		fl.logger.ErrorDepth(1, fmt.Sprintf(format, v...))
+	}
+}
+
+// Errorf2 is part of the Logger interface
+func (fl *FilteredLogger) Errorf2(err error, format string, v ...any) {
+	if fl.enabled(logutilpb.Level_ERROR) {
+		fl.logger.ErrorDepth(1, fmt.Sprintf(format+": %+v", append(v, err)))
+	}
+}
+
+// Error is part of the Logger interface
+func (fl *FilteredLogger) Error(err error) {
+	if fl.enabled(logutilpb.Level_ERROR) {
+		fl.logger.ErrorDepth(1, fmt.Sprintf("%+v", err))
+	}
+}
+
+// Printf is part of the Logger interface
+func (fl *FilteredLogger) Printf(format string, v ...any) {
+	if fl.keepConsole || fl.enabled(logutilpb.Level_INFO) {
+		fl.logger.Printf(format, v...)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logutil
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+)
+
+func eventValues(ml *MemoryLogger) []string {
+	var values []string
+	for _, event := range ml.LogEvents() {
+		values = append(values, event.Value)
+	}
+	return values
+}
+
+func TestFilteredTeeLogger(t *testing.T) {
+	client := NewMemoryLogger()
+	file := NewMemoryLogger()
+	filtered := NewFilteredLogger(file, logutilpb.Level_WARNING)
+	tl := NewTeeLogger(client, filtered)
+
+	tl.Infof("info %v", 1)
+	tl.Warningf("warning %v", 2)
+	tl.Errorf("error %v", 3)
+	tl.Printf("console %v", 4)
+	InfoKV(tl, "kv info")
+	WarningKV(tl, "kv warning")
+
+	require.Equal(t, []string{"info 1", "warning 2", "error 3", "console 4", "kv info", "kv warning"}, eventValues(client))
+	require.Equal(t, []string{"warning 2", "error 3", "kv warning"}, eventValues(file))
+	for _, event := range file.LogEvents() {
+		require.Equal(t, "filtered_logger_test.go", event.File)
+	}
+
+	// The level can be changed while the logger is in use.
+	filtered.SetMinLevel(logutilpb.Level_ERROR)
+	require.Equal(t, logutilpb.Level_ERROR, filtered.MinLevel())
+	file.Clear()
+	tl.Warningf("warning %v", 5)
+	tl.Errorf("error %v", 6)
+	require.Equal(t, []string{"error 6"}, eventValues(file))
+
+	filtered.SetMinLevel(logutilpb.Level_INFO)
+	file.Clear()
+	tl.Infof("info %v", 7)
+	require.Equal(t, []string{"info 7"}, eventValues(file))
+}
+
+func TestFilteredClientLogger(t *testing.T) {
+	client := NewMemoryLogger()
+	fl := NewFilteredClientLogger(client, logutilpb.Level_ERROR)
+
+	fl.Infof("info %v", 1)
+	fl.Warningf("warning %v", 2)
+	fl.Errorf("error %v", 3)
+	fl.Printf("console %v", 4)
+	require.Equal(t, []string{"error 3", "console 4"}, eventValues(client))
+}
+
+func TestConsoleLogMinLevel(t *testing.T) {
+	defer SetConsoleLogMinLevel(logutilpb.Level_INFO)
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	RegisterConsoleLoggerFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--console-log-min-level=warning"}))
+	require.Equal(t, logutilpb.Level_WARNING, ConsoleLogMinLevel())
+	require.Error(t, fs.Set("console-log-min-level", "debug"))
+
+	// The level is shared by all the console loggers, including the ones
+	// already in use.
+	logger, ok := NewConsoleLoggerFromFlags().(*FilteredLogger)
+	require.True(t, ok)
+	require.Equal(t, logutilpb.Level_WARNING, logger.MinLevel())
+	SetConsoleLogMinLevel(logutilpb.Level_ERROR)
+	require.Equal(t, logutilpb.Level_ERROR, logger.MinLevel())
+	require.Equal(t, "ERROR", fs.Lookup("console-log-min-level").Value.String())
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("warning")
+	require.NoError(t, err)
+	require.Equal(t, logutilpb.Level_WARNING, level)
+
+	_, err = ParseLevel("CONSOLE")
+	require.ErrorContains(t, err, "invalid log level")
+	_, err = ParseLevel("debug")
+	require.ErrorContains(t, err, "invalid log level")
+}
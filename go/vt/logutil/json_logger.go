@@ -0,0 +1,292 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+)
+
+// KVLogger is implemented by the loggers that can record key/value fields
+// along with a message. Use InfoKV and WarningKV to log fields to any
+// Logger, the fields are appended to the message for the loggers that
+// don't implement KVLogger.
+type KVLogger interface {
+	// InfoKVDepth logs msg and the key/value pairs in kv at INFO level.
+	InfoKVDepth(depth int, msg string, kv ...any)
+	// WarningKVDepth logs msg and the key/value pairs in kv at WARNING level.
+	WarningKVDepth(depth int, msg string, kv ...any)
+}
+
+// InfoKV logs msg and the key/value pairs in kv at INFO level.
+func InfoKV(logger Logger, msg string, kv ...any) {
+	infoKVDepth(logger, 1, msg, kv)
+}
+
+// WarningKV logs msg and the key/value pairs in kv at WARNING level.
+func WarningKV(logger Logger, msg string, kv ...any) {
+	warningKVDepth(logger, 1, msg, kv)
+}
+
+func infoKVDepth(logger Logger, depth int, msg string, kv []any) {
+	if kvl, ok := logger.(KVLogger); ok {
+		kvl.InfoKVDepth(1+depth, msg, kv...)
+		return
+	}
+	logger.InfoDepth(1+depth, kvString(msg, kv))
+}
+
+func warningKVDepth(logger Logger, depth int, msg string, kv []any) {
+	if kvl, ok := logger.(KVLogger); ok {
+		kvl.WarningKVDepth(1+depth, msg, kv...)
+		return
+	}
+	logger.WarningDepth(1+depth, kvString(msg, kv))
+}
+
+// kvString formats msg and the key/value pairs in kv as
+// "msg key1=value1 key2=value2", for the loggers that only take strings.
+func kvString(msg string, kv []any) string {
+	var buf strings.Builder
+	buf.WriteString(msg)
+	for i := 0; i < len(kv); i += 2 {
+		fmt.Fprintf(&buf, " %v=%v", kv[i], kvValue(kv, i))
+	}
+	return buf.String()
+}
+
+// kvValue returns the value for the key at index i of kv.
+func kvValue(kv []any, i int) any {
+	if i+1 < len(kv) {
+		return kv[i+1]
+	}
+	return "!MISSING"
+}
+
+// JSONLogger is a Logger that writes every event as a JSON object on its
+// own line, with the time, level, file, line and message of the event
+// followed by the key/value fields passed to InfoKV and WarningKV.
+type JSONLogger struct {
+	// mu protects w, so the lines of concurrent events don't interleave.
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLogger returns a JSONLogger writing to w.
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	return &JSONLogger{w: w}
+}
+
+// jsonLoggerKeys are the keys that every JSONLogger event has. Fields
+// using one of them are renamed with a "field." prefix.
+var jsonLoggerKeys = map[string]bool{
+	"time":    true,
+	"level":   true,
+	"file":    true,
+	"line":    true,
+	"message": true,
+}
+
+func (jl *JSONLogger) log(depth int, level logutilpb.Level, msg string, kv []any) {
+	file, line := fileAndLine(2 + depth)
+
+	var buf strings.Builder
+	buf.WriteString(`{"time":`)
+	writeJSONValue(&buf, time.Now().UTC().Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeJSONValue(&buf, level.String())
+	buf.WriteString(`,"file":`)
+	writeJSONValue(&buf, file)
+	buf.WriteString(`,"line":`)
+	someDigits(&buf, line)
+	buf.WriteString(`,"message":`)
+	writeJSONValue(&buf, strings.TrimSuffix(msg, "\n"))
+	for i := 0; i < len(kv); i += 2 {
+		key := fmt.Sprint(kv[i])
+		if jsonLoggerKeys[key] {
+			key = "field." + key
+		}
+		buf.WriteByte(',')
+		writeJSONValue(&buf, key)
+		buf.WriteByte(':')
+		writeJSONValue(&buf, kvValue(kv, i))
+	}
+	buf.WriteString("}\n")
+
+	jl.mu.Lock()
+	defer jl.mu.Unlock()
+	io.WriteString(jl.w, buf.String())
+}
+
+// writeJSONValue writes v encoded as JSON to buf. Errors are encoded as
+// their message, and values that cannot be encoded as their %v string.
+func writeJSONValue(buf *strings.Builder, v any) {
+	if err, ok := v.(error); ok {
+		v = err.Error()
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprintf("%v", v))
+	}
+	buf.Write(data)
+}
+
+// InfoKV logs msg and the key/value pairs in kv at INFO level.
+func (jl *JSONLogger) InfoKV(msg string, kv ...any) {
+	jl.log(1, logutilpb.Level_INFO, msg, kv)
+}
+
+// WarningKV logs msg and the key/value pairs in kv at WARNING level.
+func (jl *JSONLogger) WarningKV(msg string, kv ...any) {
+	jl.log(1, logutilpb.Level_WARNING, msg, kv)
+}
+
+// InfoKVDepth is part of the KVLogger interface.
+func (jl *JSONLogger) InfoKVDepth(depth int, msg string, kv ...any) {
+	jl.log(1+depth, logutilpb.Level_INFO, msg, kv)
+}
+
+// WarningKVDepth is part of the KVLogger interface.
+func (jl *JSONLogger) WarningKVDepth(depth int, msg string, kv ...any) {
+	jl.log(1+depth, logutilpb.Level_WARNING, msg, kv)
+}
+
+// Infof is part of the Logger interface
+func (jl *JSONLogger) Infof(format string, v ...any) {
+	jl.log(1, logutilpb.Level_INFO, fmt.Sprintf(format, v...), nil)
+}
+
+// Warningf is part of the Logger interface
+func (jl *JSONLogger) Warningf(format string, v ...any) {
+	jl.log(1, logutilpb.Level_WARNING, fmt.Sprintf(format, v...), nil)
+}
+
+// Errorf is part of the Logger interface
+func (jl *JSONLogger) Errorf(format string, v ...any) {
+	jl.log(1, logutilpb.Level_ERROR, fmt.Sprintf(format, v...), nil)
+}
+
+// Errorf2 is part of the Logger interface
+func (jl *JSONLogger) Errorf2(err error, format string, v ...any) {
+	jl.log(1, logutilpb.Level_ERROR, fmt.Sprintf(format+": %+v", append(v, err)), nil)
+}
+
+// Error is part of the Logger interface
+func (jl *JSONLogger) Error(err error) {
+	jl.log(1, logutilpb.Level_ERROR, fmt.Sprintf("%+v", err), nil)
+}
+
+// Printf is part of the Logger interface
+func (jl *JSONLogger) Printf(format string, v ...any) {
+	jl.log(1, logutilpb.Level_CONSOLE, fmt.Sprintf(format, v...), nil)
+}
+
+// InfoDepth is part of the Logger interface.
+func (jl *JSONLogger) InfoDepth(depth int, s string) {
+	jl.log(1+depth, logutilpb.Level_INFO, s, nil)
+}
+
+// WarningDepth is part of the Logger interface.
+func (jl *JSONLogger) WarningDepth(depth int, s string) {
+	jl.log(1+depth, logutilpb.Level_WARNING, s, nil)
+}
+
+// ErrorDepth is part of the Logger interface.
+func (jl *JSONLogger) ErrorDepth(depth int, s string) {
+	jl.log(1+depth, logutilpb.Level_ERROR, s, nil)
+}
+
+const (
+	// ConsoleLogFormatText logs events as text lines, through glog.
+	ConsoleLogFormatText = "text"
+	// ConsoleLogFormatJSON logs events as JSON objects, on stderr.
+	ConsoleLogFormatJSON = "json"
+)
+
+var (
+	consoleLogFormat = ConsoleLogFormatText
+
+	// consoleLogMinLevel is the minimum level of the events the loggers
+	// returned by NewConsoleLoggerFromFlags write. They all share it, so a
+	// change applies to the commands already running.
+	consoleLogMinLevel atomic.Int32
+)
+
+// RegisterConsoleLoggerFlags installs the flags used by
+// NewConsoleLoggerFromFlags.
+func RegisterConsoleLoggerFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&consoleLogFormat, "console-log-format", consoleLogFormat, "format of the command logs written by the server: text or json")
+	fs.Var(consoleLogMinLevelFlag{}, "console-log-min-level", "minimum level of the command logs written by the server: INFO, WARNING or ERROR. Clients still get all the logs of their commands.")
+}
+
+// consoleLogMinLevelFlag is the pflag.Value of --console-log-min-level,
+// which sets the shared consoleLogMinLevel.
+type consoleLogMinLevelFlag struct{}
+
+// String is part of the pflag.Value interface.
+func (consoleLogMinLevelFlag) String() string {
+	return ConsoleLogMinLevel().String()
+}
+
+// Set is part of the pflag.Value interface.
+func (consoleLogMinLevelFlag) Set(s string) error {
+	level, err := ParseLevel(s)
+	if err != nil {
+		return err
+	}
+	SetConsoleLogMinLevel(level)
+	return nil
+}
+
+// Type is part of the pflag.Value interface.
+func (consoleLogMinLevelFlag) Type() string {
+	return "string"
+}
+
+// ConsoleLogMinLevel returns the minimum level of the events the loggers
+// returned by NewConsoleLoggerFromFlags write.
+func ConsoleLogMinLevel() logutilpb.Level {
+	return logutilpb.Level(consoleLogMinLevel.Load())
+}
+
+// SetConsoleLogMinLevel changes the minimum level of the events the loggers
+// returned by NewConsoleLoggerFromFlags write, including the ones in use.
+func SetConsoleLogMinLevel(level logutilpb.Level) {
+	consoleLogMinLevel.Store(int32(level))
+}
+
+// NewConsoleLoggerFromFlags returns a ConsoleLogger, or a JSONLogger writing
+// to stderr if --console-log-format=json, wrapped in a FilteredLogger that
+// reads the minimum level set by --console-log-min-level or
+// SetConsoleLogMinLevel on every event.
+func NewConsoleLoggerFromFlags() Logger {
+	var logger Logger = NewConsoleLogger()
+	if consoleLogFormat == ConsoleLogFormatJSON {
+		logger = NewJSONLogger(os.Stderr)
+	}
+	return &FilteredLogger{logger: logger, minLevel: &consoleLogMinLevel}
+}
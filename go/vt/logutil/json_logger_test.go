@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logutil
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLogger(t *testing.T) {
+	var buf strings.Builder
+	logger := NewJSONLogger(&buf)
+	logger.Infof("info %v", 1)
+	logger.WarningKV("tablet unreachable", "tablet", "zone1-0000000101", "attempt", 3, "err", errors.New("timeout"))
+	logger.Errorf("error\n")
+	logger.Printf("console")
+	logger.InfoKV("odd", "level", "dup", "missing")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 5)
+	events := make([]map[string]any, len(lines))
+	for i, line := range lines {
+		require.NoError(t, json.Unmarshal([]byte(line), &events[i]), line)
+		require.Equal(t, "json_logger_test.go", events[i]["file"])
+		require.NotEmpty(t, events[i]["time"])
+	}
+
+	require.Equal(t, "INFO", events[0]["level"])
+	require.Equal(t, "info 1", events[0]["message"])
+
+	require.Equal(t, "WARNING", events[1]["level"])
+	require.Equal(t, "tablet unreachable", events[1]["message"])
+	require.Equal(t, "zone1-0000000101", events[1]["tablet"])
+	require.EqualValues(t, 3, events[1]["attempt"])
+	require.Equal(t, "timeout", events[1]["err"])
+
+	require.Equal(t, "ERROR", events[2]["level"])
+	require.Equal(t, "error", events[2]["message"])
+
+	require.Equal(t, "CONSOLE", events[3]["level"])
+	require.Equal(t, "console", events[3]["message"])
+
+	require.Equal(t, "INFO", events[4]["level"])
+	require.Equal(t, "dup", events[4]["field.level"])
+	require.Equal(t, "!MISSING", events[4]["missing"])
+}
+
+func TestKV(t *testing.T) {
+	ml := NewMemoryLogger()
+	InfoKV(ml, "refreshed", "tablet", "zone1-0000000101", "took", "1s")
+	WarningKV(ml, "failed", "tablet")
+	require.Equal(t, "refreshed tablet=zone1-0000000101 took=1s", ml.Events[0].Value)
+	require.Equal(t, "json_logger_test.go", ml.Events[0].File)
+	require.Equal(t, "failed tablet=!MISSING", ml.Events[1].Value)
+
+	var buf strings.Builder
+	InfoKV(NewTeeLogger(ml, NewJSONLogger(&buf)), "refreshed", "tablet", "zone1-0000000101")
+	require.Equal(t, "refreshed tablet=zone1-0000000101", ml.Events[2].Value)
+	require.Equal(t, "json_logger_test.go", ml.Events[2].File)
+	var event map[string]any
+	require.NoError(t, json.Unmarshal([]byte(buf.String()), &event))
+	require.Equal(t, "zone1-0000000101", event["tablet"])
+	require.Equal(t, "json_logger_test.go", event["file"])
+}
@@ -25,6 +25,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dustin/go-humanize"
+
 	"vitess.io/vitess/go/protoutil"
 	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
 )
@@ -212,23 +214,68 @@ func (cl *CallbackLogger) Printf(format string, v ...any) {
 type MemoryLogger struct {
 	CallbackLogger
 
+	// set at construction, zero means no limit
+	maxEvents int
+	maxBytes  int
+
 	// mu protects the Events
 	mu     sync.Mutex
 	Events []*logutilpb.Event
+	// size is the total length of the values of Events.
+	size int
+	// dropped is the number of events removed to stay within the limits.
+	dropped int
 }
 
-// NewMemoryLogger returns a new MemoryLogger
+// NewMemoryLogger returns a new MemoryLogger that keeps all the events.
 func NewMemoryLogger() *MemoryLogger {
-	ml := &MemoryLogger{}
+	return NewMemoryLoggerWithLimits(0, 0)
+}
+
+// NewMemoryLoggerWithLimits returns a new MemoryLogger that keeps at most
+// maxEvents events, with values adding up to at most maxBytes. When a limit
+// is exceeded, the oldest events are dropped and counted, see Dropped. The
+// most recent event is always kept. A zero limit means no limit.
+func NewMemoryLoggerWithLimits(maxEvents, maxBytes int) *MemoryLogger {
+	ml := &MemoryLogger{
+		maxEvents: maxEvents,
+		maxBytes:  maxBytes,
+	}
 	ml.CallbackLogger.f = func(e *logutilpb.Event) {
 		ml.mu.Lock()
 		defer ml.mu.Unlock()
 		ml.Events = append(ml.Events, e)
+		ml.size += len(e.Value)
+		n := 0
+		for n < len(ml.Events)-1 && ml.overLimits(len(ml.Events)-n) {
+			ml.size -= len(ml.Events[n].Value)
+			ml.Events[n] = nil
+			n++
+		}
+		if n > 0 {
+			ml.Events = ml.Events[n:]
+			ml.dropped += n
+		}
 	}
 	return ml
 }
 
-// String returns all the lines in one String, separated by '\n'
+// overLimits returns true if keeping count events, with a total size of
+// ml.size, exceeds the limits of the logger.
+func (ml *MemoryLogger) overLimits(count int) bool {
+	return (ml.maxEvents > 0 && count > ml.maxEvents) || (ml.maxBytes > 0 && ml.size > ml.maxBytes)
+}
+
+// Dropped returns the number of events that were dropped to stay within
+// the limits of the logger.
+func (ml *MemoryLogger) Dropped() int {
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+	return ml.dropped
+}
+
+// String returns all the lines in one String, separated by '\n'.
+// If events were dropped, a last line says how many.
 func (ml *MemoryLogger) String() string {
 	var buf strings.Builder
 	ml.mu.Lock()
@@ -237,6 +284,9 @@ func (ml *MemoryLogger) String() string {
 		EventToBuffer(event, &buf)
 		buf.WriteByte('\n')
 	}
+	if ml.dropped > 0 {
+		fmt.Fprintf(&buf, "...and %v earlier events were truncated\n", humanize.Comma(int64(ml.dropped)))
+	}
 	return buf.String()
 }
 
@@ -244,6 +294,8 @@ func (ml *MemoryLogger) String() string {
 func (ml *MemoryLogger) Clear() {
 	ml.mu.Lock()
 	ml.Events = nil
+	ml.size = 0
+	ml.dropped = 0
 	ml.mu.Unlock()
 }
 
@@ -305,6 +357,18 @@ func (tl *TeeLogger) ErrorDepth(depth int, s string) {
 	tl.Two.ErrorDepth(1+depth, s)
 }
 
+// InfoKVDepth is part of the KVLogger interface
+func (tl *TeeLogger) InfoKVDepth(depth int, msg string, kv ...any) {
+	infoKVDepth(tl.One, 1+depth, msg, kv)
+	infoKVDepth(tl.Two, 1+depth, msg, kv)
+}
+
+// WarningKVDepth is part of the KVLogger interface
+func (tl *TeeLogger) WarningKVDepth(depth int, msg string, kv ...any) {
+	warningKVDepth(tl.One, 1+depth, msg, kv)
+	warningKVDepth(tl.Two, 1+depth, msg, kv)
+}
+
 // Infof is part of the Logger interface
 func (tl *TeeLogger) Infof(format string, v ...any) {
 	tl.InfoDepth(1, fmt.Sprintf(format, v...))
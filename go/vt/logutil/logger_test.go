@@ -17,9 +17,12 @@ limitations under the License.
 package logutil
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/require"
+
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/race"
 	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
@@ -112,6 +115,42 @@ func TestMemoryLogger(t *testing.T) {
 	}
 }
 
+func TestMemoryLoggerWithLimits(t *testing.T) {
+	ml := NewMemoryLoggerWithLimits(3, 0)
+	for i := range 5 {
+		ml.Infof("event %v", i)
+	}
+	require.Len(t, ml.Events, 3)
+	require.Equal(t, "event 2", ml.Events[0].Value)
+	require.Equal(t, 2, ml.Dropped())
+	require.True(t, strings.HasSuffix(ml.String(), "] event 4\n...and 2 earlier events were truncated\n"), ml.String())
+
+	ml.Clear()
+	require.Zero(t, ml.Dropped())
+	require.Equal(t, "", ml.String())
+
+	ml = NewMemoryLoggerWithLimits(0, 10)
+	ml.Printf("12345")
+	ml.Printf("67890")
+	require.Len(t, ml.Events, 2)
+	ml.Printf("a")
+	require.Len(t, ml.Events, 2)
+	require.Equal(t, "67890", ml.Events[0].Value)
+	require.Equal(t, 1, ml.Dropped())
+	// The last event is kept even if it is too big on its own.
+	ml.Printf("this event is too big")
+	require.Len(t, ml.Events, 1)
+	require.Equal(t, 3, ml.Dropped())
+
+	// Without limits, every event is kept.
+	ml = NewMemoryLogger()
+	for i := range 1000 {
+		ml.Infof("event %v", i)
+	}
+	require.Len(t, ml.Events, 1000)
+	require.Zero(t, ml.Dropped())
+}
+
 func TestTeeLogger(t *testing.T) {
 	ml1 := NewMemoryLogger()
 	ml2 := NewMemoryLogger()
@@ -18,10 +18,13 @@ package logutil
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"sync"
 	"time"
 
 	"vitess.io/vitess/go/vt/log"
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
 )
 
 // ThrottledLogger will allow logging of messages but won't spam the
@@ -100,3 +103,196 @@ func (tl *ThrottledLogger) Warningf(format string, v ...any) {
 func (tl *ThrottledLogger) Errorf(format string, v ...any) {
 	tl.log(errorDepth, format, v...)
 }
+
+// TemplateThrottledLogger is a Logger that forwards at most maxEvents
+// events per interval for each message template, that is the message with
+// its numbers replaced by '#', so that the messages that only differ by a
+// tablet alias or a count share a template. The events in excess are
+// suppressed and counted, and a summary line is logged when the interval is
+// over, or when Flush is called.
+type TemplateThrottledLogger struct {
+	// set at construction
+	logger    Logger
+	maxEvents int
+	interval  time.Duration
+
+	// mu protects templates
+	mu        sync.Mutex
+	templates map[string]*throttledTemplate
+}
+
+// throttledTemplate is the state of one message template.
+type throttledTemplate struct {
+	level       logutilpb.Level
+	windowStart time.Time
+	count       int
+	suppressed  int
+	timer       *time.Timer
+}
+
+// templateNumbers matches the numbers messageTemplate replaces.
+var templateNumbers = regexp.MustCompile(`[0-9]+`)
+
+// messageTemplate returns the template of a message.
+func messageTemplate(msg string) string {
+	return templateNumbers.ReplaceAllString(msg, "#")
+}
+
+// NewTemplateThrottledLogger returns a TemplateThrottledLogger forwarding
+// at most maxEvents events per interval and per template to logger.
+func NewTemplateThrottledLogger(logger Logger, maxEvents int, interval time.Duration) *TemplateThrottledLogger {
+	return &TemplateThrottledLogger{
+		logger:    logger,
+		maxEvents: maxEvents,
+		interval:  interval,
+		templates: make(map[string]*throttledTemplate),
+	}
+}
+
+// allow returns true if the event with the message can be forwarded, or
+// counts it as suppressed.
+func (ttl *TemplateThrottledLogger) allow(level logutilpb.Level, msg string) bool {
+	template := messageTemplate(msg)
+	now := time.Now()
+
+	ttl.mu.Lock()
+	defer ttl.mu.Unlock()
+	t, ok := ttl.templates[template]
+	if !ok {
+		t = &throttledTemplate{level: level, windowStart: now}
+		ttl.templates[template] = t
+	}
+	if now.Sub(t.windowStart) >= ttl.interval {
+		ttl.summarize(template, t)
+		t.windowStart = now
+		t.count = 0
+	}
+	if t.count < ttl.maxEvents {
+		t.count++
+		return true
+	}
+	// If this is the first event to be suppressed in this interval, start
+	// a timer to log the summary when the interval is over.
+	if t.suppressed == 0 {
+		var timer *time.Timer
+		timer = time.AfterFunc(t.windowStart.Add(ttl.interval).Sub(now), func() {
+			ttl.mu.Lock()
+			defer ttl.mu.Unlock()
+			// The summary may have been logged while this was waiting.
+			if t.timer == timer {
+				ttl.summarize(template, t)
+			}
+		})
+		t.timer = timer
+	}
+	t.suppressed++
+	return false
+}
+
+// summarize logs how many events of the template were suppressed, if
+// any. ttl.mu must be held.
+func (ttl *TemplateThrottledLogger) summarize(template string, t *throttledTemplate) {
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+	if t.suppressed == 0 {
+		return
+	}
+	msg := fmt.Sprintf("suppressed %v similar messages: %q", t.suppressed, template)
+	t.suppressed = 0
+	switch t.level {
+	case logutilpb.Level_INFO:
+		ttl.logger.InfoDepth(0, msg)
+	case logutilpb.Level_WARNING:
+		ttl.logger.WarningDepth(0, msg)
+	case logutilpb.Level_ERROR:
+		ttl.logger.ErrorDepth(0, msg)
+	case logutilpb.Level_CONSOLE:
+		ttl.logger.Printf("%s\n", msg)
+	}
+}
+
+// Flush logs the summary of the events suppressed so far. It should be
+// called once the operation using the logger is done, so no summary is
+// logged after that.
+func (ttl *TemplateThrottledLogger) Flush() {
+	ttl.mu.Lock()
+	defer ttl.mu.Unlock()
+	templates := make([]string, 0, len(ttl.templates))
+	for template := range ttl.templates {
+		templates = append(templates, template)
+	}
+	sort.Strings(templates)
+	for _, template := range templates {
+		ttl.summarize(template, ttl.templates[template])
+	}
+}
+
+// InfoDepth is part of the Logger interface
+func (ttl *TemplateThrottledLogger) InfoDepth(depth int, s string) {
+	if ttl.allow(logutilpb.Level_INFO, s) {
+		ttl.logger.InfoDepth(1+depth, s)
+	}
+}
+
+// WarningDepth is part of the Logger interface
+func (ttl *TemplateThrottledLogger) WarningDepth(depth int, s string) {
+	if ttl.allow(logutilpb.Level_WARNING, s) {
+		ttl.logger.WarningDepth(1+depth, s)
+	}
+}
+
+// ErrorDepth is part of the Logger interface
+func (ttl *TemplateThrottledLogger) ErrorDepth(depth int, s string) {
+	if ttl.allow(logutilpb.Level_ERROR, s) {
+		ttl.logger.ErrorDepth(1+depth, s)
+	}
+}
+
+// Infof is part of the Logger interface
+func (ttl *TemplateThrottledLogger) Infof(format string, v ...any) {
+	msg := fmt.Sprintf(format, v...)
+	if ttl.allow(logutilpb.Level_INFO, msg) {
+		ttl.logger.InfoDepth(1, msg)
+	}
+}
+
+// Warningf is part of the Logger interface
+func (ttl *TemplateThrottledLogger) Warningf(format string, v ...any) {
+	msg := fmt.Sprintf(format, v...)
+	if ttl.allow(logutilpb.Level_WARNING, msg) {
+		ttl.logger.WarningDepth(1, msg)
+	}
+}
+
+// Errorf is part of the Logger interface
+func (ttl *TemplateThrottledLogger) Errorf(format string, v ...any) {
+	msg := fmt.Sprintf(format, v...)
+	if ttl.allow(logutilpb.Level_ERROR, msg) {
+		ttl.logger.ErrorDepth(1, msg)
+	}
+}
+
+// Errorf2 is part of the Logger interface
+func (ttl *TemplateThrottledLogger) Errorf2(err error, format string, v ...any) {
+	msg := fmt.Sprintf(format+": %+v", append(v, err)...)
+	if ttl.allow(logutilpb.Level_ERROR, msg) {
+		ttl.logger.ErrorDepth(1, msg)
+	}
+}
+
+// Error is part of the Logger interface
+func (ttl *TemplateThrottledLogger) Error(err error) {
+	msg := fmt.Sprintf("%+v", err)
+	if ttl.allow(logutilpb.Level_ERROR, msg) {
+		ttl.logger.ErrorDepth(1, msg)
+	}
+}
+
+// Printf is part of the Logger interface
+func (ttl *TemplateThrottledLogger) Printf(format string, v ...any) {
+	if ttl.allow(logutilpb.Level_CONSOLE, fmt.Sprintf(format, v...)) {
+		ttl.logger.Printf(format, v...)
+	}
+}
@@ -17,9 +17,15 @@ limitations under the License.
 package logutil
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/require"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
 )
 
 func skippedCount(tl *ThrottledLogger) int {
@@ -63,3 +69,63 @@ func TestThrottledLogger(t *testing.T) {
 		t.Errorf("skippedCount is %v but was expecting %v", got, want)
 	}
 }
+
+func TestTemplateThrottledLogger(t *testing.T) {
+	ml := NewMemoryLogger()
+	interval := 100 * time.Millisecond
+	ttl := NewTemplateThrottledLogger(ml, 2, interval)
+
+	for i := range 5 {
+		ttl.Infof("RefreshState primary %v", i)
+		ttl.Warningf("tablet %v is unreachable", i)
+	}
+	ttl.Printf("done\n")
+	require.Equal(t, []string{
+		"RefreshState primary 0",
+		"tablet 0 is unreachable",
+		"RefreshState primary 1",
+		"tablet 1 is unreachable",
+		"done\n",
+	}, eventValues(ml))
+
+	// The summaries are logged when the interval is over.
+	require.Eventually(t, func() bool {
+		return len(ml.LogEvents()) == 7
+	}, 5*time.Second, 10*time.Millisecond)
+	events := ml.LogEvents()[5:]
+	sort.Slice(events, func(i, j int) bool { return events[i].Level < events[j].Level })
+	require.Equal(t, logutilpb.Level_INFO, events[0].Level)
+	require.Equal(t, `suppressed 3 similar messages: "RefreshState primary #"`, events[0].Value)
+	require.Equal(t, logutilpb.Level_WARNING, events[1].Level)
+	require.Equal(t, `suppressed 3 similar messages: "tablet # is unreachable"`, events[1].Value)
+
+	// A new interval starts with the next event.
+	ml.Clear()
+	ttl.Infof("RefreshState primary %v", 5)
+	require.Equal(t, []string{"RefreshState primary 5"}, eventValues(ml))
+
+	// Flush logs the summaries right away.
+	ml.Clear()
+	ttl = NewTemplateThrottledLogger(ml, 1, time.Hour)
+	ttl.Error(errors.New("error 1"))
+	ttl.Error(errors.New("error 2"))
+	ttl.Flush()
+	ttl.Flush()
+	require.Equal(t, []string{"error 1", `suppressed 1 similar messages: "error #"`}, eventValues(ml))
+
+	// Messages logged through the same format are only throttled if they
+	// are similar.
+	ml.Clear()
+	ttl = NewTemplateThrottledLogger(ml, 1, time.Hour)
+	ttl.Warningf("%v", "primary mismatch for shard ks/-80")
+	ttl.Warningf("%v", "no primary for shard ks/80-")
+	ttl.Printf("%s\n", "tablet zone1-0000000100 not found in map")
+	ttl.Printf("%s\n", "tablet zone1-0000000101 not found in map")
+	ttl.Flush()
+	require.Equal(t, []string{
+		"primary mismatch for shard ks/-80",
+		"no primary for shard ks/80-",
+		"tablet zone1-0000000100 not found in map\n",
+		"suppressed 1 similar messages: \"tablet zone#-# not found in map\\n\"\n",
+	}, eventValues(ml))
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/vt/logutil"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+)
+
+// Phases of a backup reported in BackupProgress.
+const (
+	BackupPhaseCopyingFiles    = "copying files"
+	BackupPhaseWritingManifest = "writing manifest"
+	BackupPhaseDone            = "done"
+)
+
+// backupProgressPrefix starts the log events carrying a BackupProgress, so
+// clients can tell them from the other events of the backup stream.
+const backupProgressPrefix = "backup progress: "
+
+// BackupProgress is a snapshot of the progress of a backup. The builtin
+// backup engine logs one periodically, and a last one in the done phase.
+type BackupProgress struct {
+	BackupName   string        `json:"backup_name"`
+	Phase        string        `json:"phase"`
+	FilesTotal   int           `json:"files_total"`
+	FilesDone    int           `json:"files_done"`
+	BytesTotal   int64         `json:"bytes_total"`
+	BytesWritten int64         `json:"bytes_written"`
+	Elapsed      time.Duration `json:"elapsed"`
+}
+
+// ParseBackupProgress returns the BackupProgress carried by event, if it is
+// a progress event.
+func ParseBackupProgress(event *logutilpb.Event) (*BackupProgress, bool) {
+	value, ok := strings.CutPrefix(event.GetValue(), backupProgressPrefix)
+	if !ok {
+		return nil, false
+	}
+	progress := &BackupProgress{}
+	if err := json.Unmarshal([]byte(value), progress); err != nil {
+		return nil, false
+	}
+	return progress, true
+}
+
+// backupProgressTracker follows the progress of a backup and logs it.
+type backupProgressTracker struct {
+	backupName string
+	filesTotal int
+	bytesTotal int64
+	start      time.Time
+
+	phase        atomic.Value
+	filesDone    atomic.Int64
+	bytesWritten atomic.Int64
+}
+
+func newBackupProgressTracker(backupName string, filesTotal int, bytesTotal int64) *backupProgressTracker {
+	t := &backupProgressTracker{
+		backupName: backupName,
+		filesTotal: filesTotal,
+		bytesTotal: bytesTotal,
+		start:      time.Now(),
+	}
+	t.phase.Store(BackupPhaseCopyingFiles)
+	return t
+}
+
+func (t *backupProgressTracker) setPhase(phase string) {
+	t.phase.Store(phase)
+}
+
+// countReads returns a reader that adds the bytes read from r to the bytes
+// written by the backup.
+func (t *backupProgressTracker) countReads(r io.Reader) *progressReader {
+	return &progressReader{r: r, t: t}
+}
+
+func (t *backupProgressTracker) snapshot() BackupProgress {
+	return BackupProgress{
+		BackupName:   t.backupName,
+		Phase:        t.phase.Load().(string),
+		FilesTotal:   t.filesTotal,
+		FilesDone:    int(t.filesDone.Load()),
+		BytesTotal:   t.bytesTotal,
+		BytesWritten: t.bytesWritten.Load(),
+		Elapsed:      time.Since(t.start),
+	}
+}
+
+// log logs the current progress as a progress event.
+func (t *backupProgressTracker) log(logger logutil.Logger) {
+	data, err := json.Marshal(t.snapshot())
+	if err != nil {
+		return
+	}
+	logger.Infof("%s%s", backupProgressPrefix, data)
+}
+
+// report logs the progress every period until ctx is done.
+func (t *backupProgressTracker) report(ctx context.Context, period time.Duration, logger logutil.Logger) {
+	tick := time.NewTicker(period)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+			t.log(logger)
+		}
+	}
+}
+
+// progressReader counts the bytes of one file of the backup.
+type progressReader struct {
+	r    io.Reader
+	t    *backupProgressTracker
+	read int64
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	nn, err := pr.r.Read(p)
+	pr.read += int64(nn)
+	pr.t.bytesWritten.Add(int64(nn))
+	return nn, err
+}
+
+// done records the end of the file. A file that failed doesn't count, as it
+// is backed up again from the start.
+func (pr *progressReader) done(err error) {
+	if err != nil {
+		pr.t.bytesWritten.Add(-pr.read)
+		return
+	}
+	pr.t.filesDone.Add(1)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+)
+
+func TestBackupProgressTracker(t *testing.T) {
+	progress := newBackupProgressTracker("backup1", 3, 30)
+
+	done := progress.countReads(strings.NewReader(strings.Repeat("a", 10)))
+	_, err := io.ReadAll(done)
+	require.NoError(t, err)
+	done.done(nil)
+
+	// A failed file is backed up again, its bytes don't count.
+	failed := progress.countReads(strings.NewReader(strings.Repeat("b", 10)))
+	_, err = io.ReadAll(failed)
+	require.NoError(t, err)
+	failed.done(errors.New("write failed"))
+
+	progress.setPhase(BackupPhaseWritingManifest)
+	logger := logutil.NewMemoryLogger()
+	progress.log(logger)
+	require.Len(t, logger.Events, 1)
+
+	got, ok := ParseBackupProgress(logger.Events[0])
+	require.True(t, ok)
+	require.Equal(t, "backup1", got.BackupName)
+	require.Equal(t, BackupPhaseWritingManifest, got.Phase)
+	require.Equal(t, 3, got.FilesTotal)
+	require.Equal(t, 1, got.FilesDone)
+	require.EqualValues(t, 30, got.BytesTotal)
+	require.EqualValues(t, 10, got.BytesWritten)
+
+	logger.Infof("Backing up file: ibdata1")
+	_, ok = ParseBackupProgress(logger.Events[1])
+	require.False(t, ok)
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+// BackupFileValidation is the result of the validation of one file of a
+// backup. ExpectedSize and ExpectedHash are empty when the backup engine
+// doesn't record them.
+type BackupFileValidation struct {
+	Name         string
+	Size         int64
+	ExpectedSize int64  `json:",omitempty"`
+	Hash         string `json:",omitempty"`
+	ExpectedHash string `json:",omitempty"`
+	Error        string `json:",omitempty"`
+}
+
+// BackupValidation is the result of the validation of a backup.
+type BackupValidation struct {
+	BackupName  string
+	Engine      string
+	Position    string
+	BackupTime  string
+	Incremental bool
+	Files       []*BackupFileValidation
+	// Errors lists the problems found with the backup, it is empty if the
+	// backup is valid.
+	Errors []string
+}
+
+// Valid returns true if no problem was found with the backup.
+func (v *BackupValidation) Valid() bool {
+	return len(v.Errors) == 0
+}
+
+// expectedBackupFile is a file that the MANIFEST of a backup references.
+type expectedBackupFile struct {
+	name string
+	size int64
+	hash string
+}
+
+// ValidateBackup reads the MANIFEST of the backup and checks that every
+// file it references can be read from backup storage, with the size and
+// the hash recorded in the MANIFEST, if the engine records them. A MANIFEST
+// that cannot be read or decoded is returned as an error, problems with the
+// files are reported in the returned BackupValidation.
+func ValidateBackup(ctx context.Context, bh backupstorage.BackupHandle) (*BackupValidation, error) {
+	manifest, err := GetBackupManifest(ctx, bh)
+	if err != nil {
+		return nil, err
+	}
+	validation := &BackupValidation{
+		BackupName:  bh.Name(),
+		Engine:      manifest.BackupMethod,
+		Position:    replication.EncodePosition(manifest.Position),
+		BackupTime:  manifest.BackupTime,
+		Incremental: manifest.Incremental,
+	}
+	if validation.Engine == "" {
+		// The builtin engine is the only one that ever left BackupMethod unset.
+		validation.Engine = builtinBackupEngineName
+	}
+	if _, ok := BackupRestoreEngineMap[validation.Engine]; !ok {
+		validation.Errors = append(validation.Errors, fmt.Sprintf("unknown backup engine %q", validation.Engine))
+	}
+
+	files, err := backupFiles(ctx, bh, validation.Engine)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		fv := validateBackupFile(ctx, bh, file)
+		if fv.Error != "" {
+			validation.Errors = append(validation.Errors, fmt.Sprintf("file %v: %v", fv.Name, fv.Error))
+		}
+		validation.Files = append(validation.Files, fv)
+	}
+	return validation, nil
+}
+
+// GetBackupSize returns the total size in backup storage of the files of
+// the backup, as recorded in its MANIFEST, or 0 if the engine that took the
+// backup doesn't record the size of every file.
+func GetBackupSize(ctx context.Context, bh backupstorage.BackupHandle, manifest *BackupManifest) (int64, error) {
+	engine := manifest.BackupMethod
+	if engine == "" {
+		engine = builtinBackupEngineName
+	}
+	files, err := backupFiles(ctx, bh, engine)
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	for _, file := range files {
+		if file.size <= 0 {
+			return 0, nil
+		}
+		size += file.size
+	}
+	return size, nil
+}
+
+// backupFiles returns the files that the MANIFEST of a backup taken by the
+// given engine references. MySQL Shell backups have none, as their data is
+// not kept in backup storage.
+func backupFiles(ctx context.Context, bh backupstorage.BackupHandle, engine string) ([]expectedBackupFile, error) {
+	var files []expectedBackupFile
+	switch engine {
+	case builtinBackupEngineName:
+		var bm builtinBackupManifest
+		if err := getBackupManifestInto(ctx, bh, &bm); err != nil {
+			return nil, err
+		}
+		for i, fe := range bm.FileEntries {
+			files = append(files, expectedBackupFile{name: fmt.Sprintf("%v", i), size: fe.Size, hash: fe.Hash})
+		}
+	case xtrabackupEngineName:
+		var bm xtraBackupManifest
+		if err := getBackupManifestInto(ctx, bh, &bm); err != nil {
+			return nil, err
+		}
+		if bm.NumStripes == 0 {
+			files = append(files, expectedBackupFile{name: bm.FileName})
+		}
+		for i := 0; i < int(bm.NumStripes); i++ {
+			files = append(files, expectedBackupFile{name: stripeFileName(bm.FileName, i)})
+		}
+	}
+	return files, nil
+}
+
+// validateBackupFile reads the whole file from backup storage to check its
+// size and its hash.
+func validateBackupFile(ctx context.Context, bh backupstorage.BackupHandle, file expectedBackupFile) *BackupFileValidation {
+	fv := &BackupFileValidation{
+		Name:         file.name,
+		ExpectedSize: file.size,
+		ExpectedHash: file.hash,
+	}
+	reader, err := bh.ReadFile(ctx, file.name)
+	if err != nil {
+		fv.Error = fmt.Sprintf("cannot read file: %v", err)
+		return fv
+	}
+	defer reader.Close()
+
+	hasher := crc32.NewIEEE()
+	fv.Size, err = io.Copy(hasher, reader)
+	if err != nil {
+		fv.Error = fmt.Sprintf("cannot read file: %v", err)
+		return fv
+	}
+	fv.Hash = hex.EncodeToString(hasher.Sum(nil))
+	switch {
+	case file.size > 0 && fv.Size != file.size:
+		fv.Error = fmt.Sprintf("size mismatch, got %v bytes expected %v", fv.Size, file.size)
+	case file.hash != "" && fv.Hash != file.hash:
+		fv.Error = fmt.Sprintf("hash mismatch, got %v expected %v", fv.Hash, file.hash)
+	}
+	return fv
+}
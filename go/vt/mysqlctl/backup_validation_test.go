@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBackupFiles returns a FakeBackupHandle serving the given files.
+func fakeBackupFiles(files map[string]string) *FakeBackupHandle {
+	return &FakeBackupHandle{
+		NameV: "2025-01-01.000000.cell1-0000000101",
+		ReadFileReturnF: func(ctx context.Context, filename string) (io.ReadCloser, error) {
+			data, ok := files[filename]
+			if !ok {
+				return nil, fmt.Errorf("no such file: %v", filename)
+			}
+			return io.NopCloser(strings.NewReader(data)), nil
+		},
+	}
+}
+
+func crc32Hex(data string) string {
+	hasher := crc32.NewIEEE()
+	_, _ = hasher.Write([]byte(data))
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+func TestValidateBackup(t *testing.T) {
+	ctx := context.Background()
+	manifest := func(t *testing.T, fes []FileEntry) string {
+		data, err := json.Marshal(&builtinBackupManifest{
+			BackupManifest: BackupManifest{
+				BackupMethod: builtinBackupEngineName,
+				BackupTime:   "2025-01-01T00:00:00Z",
+			},
+			FileEntries: fes,
+		})
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		bh := fakeBackupFiles(map[string]string{
+			"MANIFEST": manifest(t, []FileEntry{
+				{Name: "ibdata1", Hash: crc32Hex("data0"), Size: 5},
+				// Backups taken before sizes were recorded.
+				{Name: "t1.ibd", Hash: crc32Hex("data01")},
+			}),
+			"0": "data0",
+			"1": "data01",
+		})
+		validation, err := ValidateBackup(ctx, bh)
+		require.NoError(t, err)
+		require.True(t, validation.Valid(), validation.Errors)
+		require.Equal(t, builtinBackupEngineName, validation.Engine)
+		require.Equal(t, "2025-01-01T00:00:00Z", validation.BackupTime)
+		require.Len(t, validation.Files, 2)
+		require.EqualValues(t, 6, validation.Files[1].Size)
+	})
+
+	t.Run("missing and corrupt files", func(t *testing.T) {
+		bh := fakeBackupFiles(map[string]string{
+			"MANIFEST": manifest(t, []FileEntry{
+				{Name: "ibdata1", Hash: crc32Hex("data0"), Size: 5},
+				{Name: "t1.ibd", Hash: crc32Hex("data1"), Size: 5},
+				{Name: "t2.ibd", Hash: crc32Hex("data2"), Size: 5},
+			}),
+			"0": "data",
+			"1": "datax",
+		})
+		validation, err := ValidateBackup(ctx, bh)
+		require.NoError(t, err)
+		require.False(t, validation.Valid())
+		require.Len(t, validation.Errors, 3)
+		require.Equal(t, "file 0: size mismatch, got 4 bytes expected 5", validation.Errors[0])
+		require.Contains(t, validation.Errors[1], "file 1: hash mismatch")
+		require.Contains(t, validation.Errors[2], "file 2: cannot read file")
+	})
+
+	t.Run("no manifest", func(t *testing.T) {
+		_, err := ValidateBackup(ctx, fakeBackupFiles(nil))
+		require.ErrorContains(t, err, "can't read MANIFEST")
+	})
+}
+
+func TestGetBackupSize(t *testing.T) {
+	ctx := context.Background()
+	size := func(t *testing.T, manifest any) int64 {
+		data, err := json.Marshal(manifest)
+		require.NoError(t, err)
+		bh := fakeBackupFiles(map[string]string{"MANIFEST": string(data)})
+		bm, err := GetBackupManifest(ctx, bh)
+		require.NoError(t, err)
+		size, err := GetBackupSize(ctx, bh, bm)
+		require.NoError(t, err)
+		return size
+	}
+
+	require.EqualValues(t, 30, size(t, &builtinBackupManifest{
+		BackupManifest: BackupManifest{BackupMethod: builtinBackupEngineName},
+		FileEntries:    []FileEntry{{Name: "ibdata1", Size: 10}, {Name: "t1.ibd", Size: 20}},
+	}))
+	// One file without a size makes the size of the backup unknown.
+	require.Zero(t, size(t, &builtinBackupManifest{
+		FileEntries: []FileEntry{{Name: "ibdata1", Size: 10}, {Name: "t1.ibd"}},
+	}))
+	require.Zero(t, size(t, &xtraBackupManifest{
+		BackupManifest: BackupManifest{BackupMethod: xtrabackupEngineName},
+		FileName:       "backup.xbstream.gz",
+	}))
+}
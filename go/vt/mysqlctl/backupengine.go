@@ -135,6 +135,8 @@ type RestoreParams struct {
 	// StartTime: if non-zero, look for a backup that was taken at or before this time
 	// Otherwise, find the most recent backup
 	StartTime time.Time
+	// BackupName: if set, restore this full backup rather than looking one up by time.
+	BackupName string
 	// RestoreToPos hints that a point in time recovery is requested, to recover up to the specific given pos.
 	// When empty, the restore is a normal from full backup
 	RestoreToPos replication.Position
@@ -164,6 +166,7 @@ func (p *RestoreParams) Copy() RestoreParams {
 		Keyspace:             p.Keyspace,
 		Shard:                p.Shard,
 		StartTime:            p.StartTime,
+		BackupName:           p.BackupName,
 		RestoreToPos:         p.RestoreToPos,
 		RestoreToTimestamp:   p.RestoreToTimestamp,
 		DryRun:               p.DryRun,
@@ -563,6 +566,13 @@ func FindBackupToRestore(ctx context.Context, params RestoreParams, bhs []backup
 				}
 
 				switch {
+				case params.BackupName != "":
+					// restore the given backup
+					if bh.Name() != params.BackupName {
+						continue
+					}
+					params.Logger.Infof("Restore: found backup %v %v to restore", bh.Directory(), bh.Name())
+					return index
 				case checkBackupTime:
 					backupTime, err := ParseRFC3339(bm.BackupTime)
 					if err != nil {
@@ -583,6 +593,9 @@ func FindBackupToRestore(ctx context.Context, params RestoreParams, bhs []backup
 			return -1
 		}()
 		if fullBackupIndex < 0 {
+			if params.BackupName != "" {
+				return nil, vterrors.Errorf(vtrpc.Code_NOT_FOUND, "no valid full backup named %q found in %v", params.BackupName, backupDir)
+			}
 			if checkBackupTime {
 				params.Logger.Errorf("No valid backup found before time %v", params.StartTime.Format(BackupTimestampFormat))
 			}
@@ -148,6 +148,10 @@ type FileEntry struct {
 	// compressed if specified) stored in the BackupStorage.
 	Hash string
 
+	// Size is the size of the final data stored in the BackupStorage. It is
+	// zero for backups taken before the field was added.
+	Size int64 `json:",omitempty"`
+
 	// ParentPath is an optional prefix to the Base path. If empty, it is ignored. Useful
 	// for writing files in a temporary directory
 	ParentPath string
@@ -600,21 +604,32 @@ func (be *BuiltinBackupEngine) backupFiles(
 	defer cancel()
 
 	// Get the files to backup.
-	// We don't care about totalSize because we add each file separately.
 	var fes []FileEntry
+	var totalSize int64
 	var err error
 	if isIncrementalBackup(params) {
-		fes, _, err = binlogFilesToBackup(params.Cnf, binlogFiles)
+		fes, totalSize, err = binlogFilesToBackup(params.Cnf, binlogFiles)
 	} else {
-		fes, _, err = findFilesToBackup(params.Cnf)
+		fes, totalSize, err = findFilesToBackup(params.Cnf)
 	}
 	if err != nil {
 		return vterrors.Wrap(err, "can't find files to backup")
 	}
 	params.Logger.Infof("found %v files to backup", len(fes))
 
+	// Report the progress of the whole backup until it is done, and once more
+	// at the end so clients get the final totals.
+	progress := newBackupProgressTracker(bh.Name(), len(fes), totalSize)
+	go progress.report(ctx, builtinBackupProgress, params.Logger)
+	defer func() {
+		if finalErr == nil {
+			progress.setPhase(BackupPhaseDone)
+			progress.log(params.Logger)
+		}
+	}()
+
 	// The error here can be ignored safely. Failed FileEntry's are handled in the next 'if' statement.
-	_ = be.backupFileEntries(ctx, fes, bh, params)
+	_ = be.backupFileEntries(ctx, fes, bh, params, progress)
 
 	// BackupHandle supports the BackupErrorRecorder interface for tracking errors
 	// across any goroutines that fan out to take the backup. This means that we
@@ -645,13 +660,14 @@ func (be *BuiltinBackupEngine) backupFiles(
 			}
 			bh.ResetErrorForFile(file)
 		}
-		err = be.backupFileEntries(ctx, newFEs, bh, params)
+		err = be.backupFileEntries(ctx, newFEs, bh, params, progress)
 		if err != nil {
 			return err
 		}
 	}
 
 	// Backup the MANIFEST file and apply retry logic.
+	progress.setPhase(BackupPhaseWritingManifest)
 	var manifestErr error
 	for currentRetry := 0; currentRetry <= maxRetriesPerFile; currentRetry++ {
 		manifestErr = be.backupManifest(ctx, params, bh, backupPosition, purgedPosition, fromPosition, fromBackupName, serverUUID, mysqlVersion, incrDetails, fes, currentRetry)
@@ -670,7 +686,7 @@ func (be *BuiltinBackupEngine) backupFiles(
 // This function will ignore empty FileEntry, allowing the retry mechanism to send a partially empty slice, to not
 // mess up the index of retriable FileEntry.
 // This function does not leave any background operation behind itself, all calls to bh.AddFile will be finished or canceled.
-func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, params BackupParams) error {
+func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, params BackupParams, progress *backupProgressTracker) error {
 	ctxCancel, cancel := context.WithCancel(ctx)
 	defer func() {
 		// If we reached this defer in all cases we can cancel the context.
@@ -704,7 +720,7 @@ func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []File
 
 			// Backup the individual file.
 			var errBackupFile error
-			if errBackupFile = be.backupFile(ctxCancel, params, bh, fe, name); errBackupFile != nil {
+			if errBackupFile = be.backupFile(ctxCancel, params, bh, fe, name, progress); errBackupFile != nil {
 				bh.RecordError(name, vterrors.Wrapf(errBackupFile, "failed to backup file '%s'", name))
 				if fe.RetryCount >= maxRetriesPerFile {
 					// this is the last attempt, and we have an error, we can cancel everything and fail fast.
@@ -833,7 +849,7 @@ func (bp *backupPipe) ReportProgress(ctx context.Context, period time.Duration,
 }
 
 // backupFile backs up an individual file.
-func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string) (finalErr error) {
+func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string, progress *backupProgressTracker) (finalErr error) {
 	// We need another context that does not live outside of this function.
 	// Reporting progress, compressing and writing are operations that will be
 	// over by the time we exit this function, they can use this cancelable context.
@@ -865,8 +881,11 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 		return err
 	}
 
+	counted := progress.countReads(timedSource)
+	defer func() { counted.done(finalErr) }()
+
 	retryStr := retryToString(fe.RetryCount)
-	br := newBackupReader(fe.Name, fi.Size(), timedSource)
+	br := newBackupReader(fe.Name, fi.Size(), counted)
 	go br.ReportProgress(cancelableCtx, builtinBackupProgress, params.Logger, false /*restore*/, retryStr)
 
 	// Open the destination file for writing, and a buffer.
@@ -957,8 +976,9 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 		return errors.Join(finalErr, err)
 	}
 
-	// Save the hash.
+	// Save the hash and the size.
 	fe.Hash = bw.HashString()
+	fe.Size = atomic.LoadInt64(&bw.nn)
 	return nil
 }
 
@@ -1185,6 +1205,7 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 				Name:       oldFes.Name,
 				ParentPath: oldFes.ParentPath,
 				Hash:       oldFes.Hash,
+				Size:       oldFes.Size,
 				RetryCount: 1,
 			}
 			bh.ResetErrorForFile(file)
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -198,6 +199,16 @@ type FakeMysqlDaemon struct {
 
 	// Version is the version that will be returned by GetVersionString.
 	Version string
+
+	// queryLatencies are the latencies set by SetQueryLatency, protected
+	// by mu.
+	queryLatencies []queryLatency
+}
+
+// queryLatency is a latency added to the queries that match pattern.
+type queryLatency struct {
+	pattern string
+	latency time.Duration
 }
 
 // NewFakeMysqlDaemon returns a FakeMysqlDaemon where mysqld appears
@@ -575,6 +586,46 @@ func (fmd *FakeMysqlDaemon) Promote(ctx context.Context, hookExtraEnv map[string
 	return fmd.PromoteResult, nil
 }
 
+// SetQueryLatency makes FetchSuperQuery and ExecuteSuperQueryList wait for
+// latency before running the queries that match pattern, which is either
+// the exact query or a regular expression, as for FetchSuperQueryMap. The
+// wait ends early with the error of the context if it is done first. A
+// latency of zero removes the latency of pattern.
+func (fmd *FakeMysqlDaemon) SetQueryLatency(pattern string, latency time.Duration) {
+	fmd.mu.Lock()
+	defer fmd.mu.Unlock()
+	fmd.queryLatencies = slices.DeleteFunc(fmd.queryLatencies, func(ql queryLatency) bool {
+		return ql.pattern == pattern
+	})
+	if latency > 0 {
+		fmd.queryLatencies = append(fmd.queryLatencies, queryLatency{pattern: pattern, latency: latency})
+	}
+}
+
+// waitQueryLatency waits for the latency of the first pattern set with
+// SetQueryLatency that matches the query, if any.
+func (fmd *FakeMysqlDaemon) waitQueryLatency(ctx context.Context, query string) error {
+	var latency time.Duration
+	fmd.mu.Lock()
+	for _, ql := range fmd.queryLatencies {
+		if matched, _ := regexp.MatchString(ql.pattern, query); matched || ql.pattern == query {
+			latency = ql.latency
+			break
+		}
+	}
+	fmd.mu.Unlock()
+	if latency == 0 {
+		return nil
+	}
+
+	select {
+	case <-time.After(latency):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // ExecuteSuperQuery is part of the MysqlDaemon interface
 func (fmd *FakeMysqlDaemon) ExecuteSuperQuery(ctx context.Context, query string) error {
 	return fmd.ExecuteSuperQueryList(ctx, []string{query})
@@ -583,6 +634,10 @@ func (fmd *FakeMysqlDaemon) ExecuteSuperQuery(ctx context.Context, query string)
 // ExecuteSuperQueryList is part of the MysqlDaemon interface
 func (fmd *FakeMysqlDaemon) ExecuteSuperQueryList(ctx context.Context, queryList []string) error {
 	for _, query := range queryList {
+		if err := fmd.waitQueryLatency(ctx, query); err != nil {
+			return err
+		}
+
 		// test we still have a query to compare
 		if fmd.ExpectedExecuteSuperQueryCurrent >= len(fmd.ExpectedExecuteSuperQueryList) {
 			return fmt.Errorf("unexpected extra query in ExecuteSuperQueryList: %v", query)
@@ -616,6 +671,9 @@ func (fmd *FakeMysqlDaemon) ExecuteSuperQueryList(ctx context.Context, queryList
 
 // FetchSuperQuery returns the results from the map, if any.
 func (fmd *FakeMysqlDaemon) FetchSuperQuery(ctx context.Context, query string) (*sqltypes.Result, error) {
+	if err := fmd.waitQueryLatency(ctx, query); err != nil {
+		return nil, err
+	}
 	if fmd.FetchSuperQueryMap == nil {
 		return nil, fmt.Errorf("unexpected query: %v", query)
 	}
@@ -26,8 +26,7 @@ import (
 // GetPermissions lists the permissions on the mysqld.
 // The rows are sorted in primary key order to help with comparing
 // permissions between tablets.
-func GetPermissions(mysqld MysqlDaemon) (*tabletmanagerdatapb.Permissions, error) {
-	ctx := context.TODO()
+func GetPermissions(ctx context.Context, mysqld MysqlDaemon) (*tabletmanagerdatapb.Permissions, error) {
 	permissions := &tabletmanagerdatapb.Permissions{}
 
 	// get Users
@@ -48,5 +47,23 @@ func GetPermissions(mysqld MysqlDaemon) (*tabletmanagerdatapb.Permissions, error
 		permissions.DbPermissions = append(permissions.DbPermissions, tmutils.NewDbPermission(qr.Fields, row))
 	}
 
+	// get Tables
+	qr, err = mysqld.FetchSuperQuery(ctx, "SELECT * FROM mysql.tables_priv ORDER BY host, db, user, table_name")
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range qr.Rows {
+		permissions.TablePermissions = append(permissions.TablePermissions, tmutils.NewTablePermission(qr.Fields, row))
+	}
+
+	// get Columns
+	qr, err = mysqld.FetchSuperQuery(ctx, "SELECT * FROM mysql.columns_priv ORDER BY host, db, user, table_name, column_name")
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range qr.Rows {
+		permissions.ColumnPermissions = append(permissions.ColumnPermissions, tmutils.NewColumnPermission(qr.Fields, row))
+	}
+
 	return permissions, nil
 }
@@ -17,12 +17,16 @@ limitations under the License.
 package mysqlctl
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/mysql/fakesqldb"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
 )
 
 func TestGetPermissions(t *testing.T) {
@@ -35,10 +39,48 @@ func TestGetPermissions(t *testing.T) {
 	testMysqld.FetchSuperQueryMap = map[string]*sqltypes.Result{
 		"SELECT * FROM mysql.user ORDER BY host, user":   sqltypes.MakeTestResult(sqltypes.MakeTestFields("host|user", "varchar|varchar"), "test_host1|test_user1", "test_host2|test_user2"),
 		"SELECT * FROM mysql.db ORDER BY host, db, user": sqltypes.MakeTestResult(sqltypes.MakeTestFields("host|user|db", "varchar|varchar|varchar"), "test_host1|test_user1|test_db1", "test_host2|test_user2|test_db2"),
+		"SELECT * FROM mysql.tables_priv ORDER BY host, db, user, table_name": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Host|Db|User|Table_name|Grantor|Timestamp|Table_priv|Column_priv", "varchar|varchar|varchar|varchar|varchar|timestamp|varchar|varchar"),
+			"test_host1|test_db1|test_user1|t1|root@localhost|2025-01-01 00:00:00|Select,Insert|Select"),
+		"SELECT * FROM mysql.columns_priv ORDER BY host, db, user, table_name, column_name": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Host|Db|User|Table_name|Column_name|Timestamp|Column_priv", "varchar|varchar|varchar|varchar|varchar|timestamp|varchar"),
+			"test_host1|test_db1|test_user1|t1|c1|2025-01-01 00:00:00|Select"),
 	}
 
-	per, err := GetPermissions(testMysqld)
+	per, err := GetPermissions(context.Background(), testMysqld)
 	assert.NoError(t, err)
 	assert.Len(t, per.DbPermissions, 2)
 	assert.Len(t, per.UserPermissions, 2)
+	require.Len(t, per.TablePermissions, 1)
+	assert.Equal(t, "test_host1:test_db1:test_user1:t1", tmutils.TablePermissionPrimaryKey(per.TablePermissions[0]))
+	// Who granted the privileges and when is not compared.
+	assert.Equal(t, map[string]string{"Table_priv": "Select,Insert", "Column_priv": "Select"}, per.TablePermissions[0].Privileges)
+	require.Len(t, per.ColumnPermissions, 1)
+	assert.Equal(t, "test_host1:test_db1:test_user1:t1:c1", tmutils.ColumnPermissionPrimaryKey(per.ColumnPermissions[0]))
+	assert.Equal(t, map[string]string{"Column_priv": "Select"}, per.ColumnPermissions[0].Privileges)
+}
+
+func TestGetPermissionsQueryLatency(t *testing.T) {
+	db := fakesqldb.New(t)
+	defer db.Close()
+
+	testMysqld := NewFakeMysqlDaemon(db)
+	defer testMysqld.Close()
+
+	testMysqld.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		"SELECT * FROM mysql.user ORDER BY host, user":                                      sqltypes.MakeTestResult(sqltypes.MakeTestFields("host|user", "varchar|varchar"), "test_host1|test_user1"),
+		"SELECT * FROM mysql.db ORDER BY host, db, user":                                    sqltypes.MakeTestResult(sqltypes.MakeTestFields("host|user|db", "varchar|varchar|varchar"), "test_host1|test_user1|test_db1"),
+		"SELECT * FROM mysql.tables_priv ORDER BY host, db, user, table_name":               sqltypes.MakeTestResult(sqltypes.MakeTestFields("Host|Db|User|Table_name", "varchar|varchar|varchar|varchar")),
+		"SELECT * FROM mysql.columns_priv ORDER BY host, db, user, table_name, column_name": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Host|Db|User|Table_name|Column_name", "varchar|varchar|varchar|varchar|varchar")),
+	}
+
+	// The slow query is given up on when the context is done.
+	testMysqld.SetQueryLatency("FROM mysql\\.db", time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := GetPermissions(ctx, testMysqld)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	testMysqld.SetQueryLatency("FROM mysql\\.db", 0)
+	per, err := GetPermissions(context.Background(), testMysqld)
+	require.NoError(t, err)
+	assert.Len(t, per.DbPermissions, 1)
 }
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"hash/crc64"
 	"sort"
+	"strconv"
 	"strings"
 
 	"vitess.io/vitess/go/sqltypes"
@@ -37,10 +38,15 @@ var (
 	hashTable = crc64.MakeTable(crc64.ISO)
 )
 
-// permissionList is an internal type to facilitate common code between the 3 permission types
+// permissionList is an internal type to facilitate common code between the permission types
 type permissionList interface {
 	Get(int) (primayKey string, value string)
 	Len() int
+	// KeyColumns returns the columns that make up the primary key.
+	KeyColumns() []string
+	// Columns returns the key columns and the other columns of a
+	// permission, with their values.
+	Columns(int) map[string]string
 }
 
 func printPrivileges(priv map[string]string) string {
@@ -106,6 +112,22 @@ func (upl userPermissionList) Len() int {
 	return len(upl)
 }
 
+func (upl userPermissionList) KeyColumns() []string {
+	return []string{"Host", "User"}
+}
+
+func (upl userPermissionList) Columns(i int) map[string]string {
+	columns := map[string]string{
+		"Host":             upl[i].Host,
+		"User":             upl[i].User,
+		"PasswordChecksum": strconv.FormatUint(upl[i].PasswordChecksum, 10),
+	}
+	for k, v := range upl[i].Privileges {
+		columns[k] = v
+	}
+	return columns
+}
+
 // NewDbPermission is a helper method to create a tabletmanagerdatapb.DbPermission
 func NewDbPermission(fields []*querypb.Field, values []sqltypes.Value) *tabletmanagerdatapb.DbPermission {
 	up := &tabletmanagerdatapb.DbPermission{
@@ -146,6 +168,149 @@ func (upl dbPermissionList) Len() int {
 	return len(upl)
 }
 
+func (upl dbPermissionList) KeyColumns() []string {
+	return []string{"Host", "Db", "User"}
+}
+
+func (upl dbPermissionList) Columns(i int) map[string]string {
+	columns := map[string]string{
+		"Host": upl[i].Host,
+		"Db":   upl[i].Db,
+		"User": upl[i].User,
+	}
+	for k, v := range upl[i].Privileges {
+		columns[k] = v
+	}
+	return columns
+}
+
+// NewTablePermission is a helper method to create a tabletmanagerdatapb.TablePermission
+func NewTablePermission(fields []*querypb.Field, values []sqltypes.Value) *tabletmanagerdatapb.TablePermission {
+	tp := &tabletmanagerdatapb.TablePermission{
+		Privileges: make(map[string]string),
+	}
+	for i, field := range fields {
+		switch strings.ToLower(field.Name) {
+		case "host":
+			tp.Host = values[i].ToString()
+		case "db":
+			tp.Db = values[i].ToString()
+		case "user":
+			tp.User = values[i].ToString()
+		case "table_name":
+			tp.TableName = values[i].ToString()
+		case "grantor", "timestamp":
+			// we skip these, as who granted the privileges and
+			// when may be different on primary and replicas.
+		default:
+			tp.Privileges[field.Name] = values[i].ToString()
+		}
+	}
+	return tp
+}
+
+// TablePermissionPrimaryKey returns the sorting key for a TablePermission
+func TablePermissionPrimaryKey(tp *tabletmanagerdatapb.TablePermission) string {
+	return tp.Host + ":" + tp.Db + ":" + tp.User + ":" + tp.TableName
+}
+
+// TablePermissionString pretty-prints a TablePermission
+func TablePermissionString(tp *tabletmanagerdatapb.TablePermission) string {
+	return "TablePermission" + printPrivileges(tp.Privileges)
+}
+
+type tablePermissionList []*tabletmanagerdatapb.TablePermission
+
+func (tpl tablePermissionList) Get(i int) (string, string) {
+	return TablePermissionPrimaryKey(tpl[i]), TablePermissionString(tpl[i])
+}
+
+func (tpl tablePermissionList) Len() int {
+	return len(tpl)
+}
+
+func (tpl tablePermissionList) KeyColumns() []string {
+	return []string{"Host", "Db", "User", "Table_name"}
+}
+
+func (tpl tablePermissionList) Columns(i int) map[string]string {
+	columns := map[string]string{
+		"Host":       tpl[i].Host,
+		"Db":         tpl[i].Db,
+		"User":       tpl[i].User,
+		"Table_name": tpl[i].TableName,
+	}
+	for k, v := range tpl[i].Privileges {
+		columns[k] = v
+	}
+	return columns
+}
+
+// NewColumnPermission is a helper method to create a tabletmanagerdatapb.ColumnPermission
+func NewColumnPermission(fields []*querypb.Field, values []sqltypes.Value) *tabletmanagerdatapb.ColumnPermission {
+	cp := &tabletmanagerdatapb.ColumnPermission{
+		Privileges: make(map[string]string),
+	}
+	for i, field := range fields {
+		switch strings.ToLower(field.Name) {
+		case "host":
+			cp.Host = values[i].ToString()
+		case "db":
+			cp.Db = values[i].ToString()
+		case "user":
+			cp.User = values[i].ToString()
+		case "table_name":
+			cp.TableName = values[i].ToString()
+		case "column_name":
+			cp.ColumnName = values[i].ToString()
+		case "timestamp":
+			// we skip this one, as the value may be
+			// different on primary and replicas.
+		default:
+			cp.Privileges[field.Name] = values[i].ToString()
+		}
+	}
+	return cp
+}
+
+// ColumnPermissionPrimaryKey returns the sorting key for a ColumnPermission
+func ColumnPermissionPrimaryKey(cp *tabletmanagerdatapb.ColumnPermission) string {
+	return cp.Host + ":" + cp.Db + ":" + cp.User + ":" + cp.TableName + ":" + cp.ColumnName
+}
+
+// ColumnPermissionString pretty-prints a ColumnPermission
+func ColumnPermissionString(cp *tabletmanagerdatapb.ColumnPermission) string {
+	return "ColumnPermission" + printPrivileges(cp.Privileges)
+}
+
+type columnPermissionList []*tabletmanagerdatapb.ColumnPermission
+
+func (cpl columnPermissionList) Get(i int) (string, string) {
+	return ColumnPermissionPrimaryKey(cpl[i]), ColumnPermissionString(cpl[i])
+}
+
+func (cpl columnPermissionList) Len() int {
+	return len(cpl)
+}
+
+func (cpl columnPermissionList) KeyColumns() []string {
+	return []string{"Host", "Db", "User", "Table_name", "Column_name"}
+}
+
+func (cpl columnPermissionList) Columns(i int) map[string]string {
+	columns := map[string]string{
+		"Host":        cpl[i].Host,
+		"Db":          cpl[i].Db,
+		"User":        cpl[i].User,
+		"Table_name":  cpl[i].TableName,
+		"Column_name": cpl[i].ColumnName,
+	}
+	for k, v := range cpl[i].Privileges {
+		columns[k] = v
+	}
+	return columns
+}
+
 func printPermissions(name string, permissions permissionList) string {
 	result := name + " Permissions:\n"
 	for i := 0; i < permissions.Len(); i++ {
@@ -158,11 +323,120 @@ func printPermissions(name string, permissions permissionList) string {
 // PermissionsString pretty-prints Permissions
 func PermissionsString(permissions *tabletmanagerdatapb.Permissions) string {
 	return printPermissions("User", userPermissionList(permissions.UserPermissions)) +
-		printPermissions("Db", dbPermissionList(permissions.DbPermissions))
+		printPermissions("Db", dbPermissionList(permissions.DbPermissions)) +
+		printPermissions("Table", tablePermissionList(permissions.TablePermissions)) +
+		printPermissions("Column", columnPermissionList(permissions.ColumnPermissions))
+}
+
+// permissionsResults returns left and right as results with the same
+// fields: the key columns, then all the other columns in name order. The
+// columns a permission doesn't have are NULL.
+func permissionsResults(left, right permissionList) (*sqltypes.Result, *sqltypes.Result) {
+	keyColumns := left.KeyColumns()
+	isKey := make(map[string]bool, len(keyColumns))
+	for _, name := range keyColumns {
+		isKey[name] = true
+	}
+	var others []string
+	seen := make(map[string]bool)
+	for _, pl := range []permissionList{left, right} {
+		for i := 0; i < pl.Len(); i++ {
+			for name := range pl.Columns(i) {
+				if !isKey[name] && !seen[name] {
+					seen[name] = true
+					others = append(others, name)
+				}
+			}
+		}
+	}
+	sort.Strings(others)
+	names := append(append([]string(nil), keyColumns...), others...)
+
+	fields := make([]*querypb.Field, len(names))
+	for i, name := range names {
+		fields[i] = &querypb.Field{Name: name, Type: sqltypes.VarChar}
+	}
+	toResult := func(pl permissionList) *sqltypes.Result {
+		result := &sqltypes.Result{Fields: fields}
+		for i := 0; i < pl.Len(); i++ {
+			columns := pl.Columns(i)
+			row := make(sqltypes.Row, len(names))
+			for j, name := range names {
+				if v, ok := columns[name]; ok {
+					row[j] = sqltypes.NewVarChar(v)
+				} else {
+					row[j] = sqltypes.NULL
+				}
+			}
+			result.Rows = append(result.Rows, row)
+		}
+		return result
+	}
+	return toResult(left), toResult(right)
 }
 
+// diffPermissions records the permissions only one side has, and the
+// columns that differ between the permissions both sides have.
 func diffPermissions(name, leftName string, left permissionList, rightName string, right permissionList, er concurrency.ErrorRecorder) {
+	leftResult, rightResult := permissionsResults(left, right)
+	keyColumns := left.KeyColumns()
+	diff, err := sqltypes.DiffResults(leftResult, rightResult, keyColumns)
+	if err != nil {
+		// A side lists the same permission twice, which DiffResults can't
+		// match, so compare the lists in order.
+		diffSortedPermissions(name, leftName, left, rightName, right, er)
+		return
+	}
+
+	primaryKey := func(key sqltypes.Row) string {
+		parts := make([]string, len(keyColumns))
+		for i := range parts {
+			parts[i] = key[i].ToString()
+		}
+		return strings.Join(parts, ":")
+	}
+	describe := func(v sqltypes.Value) string {
+		if v.IsNull() {
+			return "missing"
+		}
+		return strconv.Quote(v.ToString())
+	}
+
+	// Report the differences in primary key order, as the permissions
+	// are listed.
+	type difference struct {
+		pk  string
+		err error
+	}
+	var differences []difference
+	for _, row := range diff.OnlyInLeft {
+		pk := primaryKey(row)
+		differences = append(differences, difference{pk, fmt.Errorf("%v has an extra %v %v", leftName, name, pk)})
+	}
+	for _, row := range diff.OnlyInRight {
+		pk := primaryKey(row)
+		differences = append(differences, difference{pk, fmt.Errorf("%v has an extra %v %v", rightName, name, pk)})
+	}
+	for _, rd := range diff.Different {
+		pk := primaryKey(rd.Key)
+		columns := make([]string, len(rd.Columns))
+		for i, c := range rd.Columns {
+			columns[i] = fmt.Sprintf("%v is %v on %v but %v on %v", c.Name, describe(c.Left), leftName, describe(c.Right), rightName)
+		}
+		differences = append(differences, difference{pk, fmt.Errorf("permissions differ on %v %v: %v", name, pk, strings.Join(columns, ", "))})
+	}
+	sort.SliceStable(differences, func(i, j int) bool {
+		return differences[i].pk < differences[j].pk
+	})
+	for _, d := range differences {
+		er.RecordError(d.err)
+	}
+}
 
+// diffSortedPermissions compares two permission lists sorted by primary
+// key, side by side, so that a permission listed twice is compared with the
+// one at the same position on the other side.
+func diffSortedPermissions(name, leftName string, left permissionList, rightName string, right permissionList, er concurrency.ErrorRecorder) {
 	leftIndex := 0
 	rightIndex := 0
 	for leftIndex < left.Len() && rightIndex < right.Len() {
@@ -206,6 +480,8 @@ func diffPermissions(name, leftName string, left permissionList, rightName strin
 func DiffPermissions(leftName string, left *tabletmanagerdatapb.Permissions, rightName string, right *tabletmanagerdatapb.Permissions, er concurrency.ErrorRecorder) {
 	diffPermissions("user", leftName, userPermissionList(left.UserPermissions), rightName, userPermissionList(right.UserPermissions), er)
 	diffPermissions("db", leftName, dbPermissionList(left.DbPermissions), rightName, dbPermissionList(right.DbPermissions), er)
+	diffPermissions("table grant", leftName, tablePermissionList(left.TablePermissions), rightName, tablePermissionList(right.TablePermissions), er)
+	diffPermissions("column grant", leftName, columnPermissionList(left.ColumnPermissions), rightName, columnPermissionList(right.ColumnPermissions), er)
 }
 
 // DiffPermissionsToArray difs two sets of permissions, and returns the difference
@@ -84,7 +84,9 @@ func TestPermissionsDiff(t *testing.T) {
 		"User Permissions:\n"+
 			"  %:vt: UserPermission PasswordChecksum(4831957779889520640) Insert_priv(N) Select_priv(Y)\n"+
 			"Db Permissions:\n"+
-			"  %:vt_live:vt: DbPermission Insert_priv(Y) Select_priv(N)\n" {
+			"  %:vt_live:vt: DbPermission Insert_priv(Y) Select_priv(N)\n"+
+			"Table Permissions:\n"+
+			"Column Permissions:\n" {
 		t.Logf("Actual: %v", p1.String())
 		t.Fail()
 	}
@@ -119,18 +121,90 @@ func TestPermissionsDiff(t *testing.T) {
 		"Insert_priv": "N",
 	})))
 	testPermissionsDiff(t, p1, p2, "p1", "p2", []string{
-		"permissions differ on user %:vt:\n" +
-			"p1: UserPermission PasswordChecksum(4831957779889520640) Insert_priv(N) Select_priv(Y)\n" +
-			" differs from:\n" +
-			"p2: UserPermission PasswordChecksum(4831957779889520640) Insert_priv(Y) Select_priv(Y)",
-		"permissions differ on db %:vt_live:vt:\n" +
-			"p1: DbPermission Insert_priv(N) Select_priv(Y)\n" +
-			" differs from:\n" +
-			"p2: DbPermission Insert_priv(Y) Select_priv(N)",
+		`permissions differ on user %:vt: Insert_priv is "N" on p1 but "Y" on p2`,
+		`permissions differ on db %:vt_live:vt: Insert_priv is "N" on p1 but "Y" on p2, Select_priv is "Y" on p1 but "N" on p2`,
 	})
 
 	p2.UserPermissions[0].Privileges["Insert_priv"] = "N"
 	p2.DbPermissions[0].Privileges["Insert_priv"] = "N"
 	p2.DbPermissions[0].Privileges["Select_priv"] = "Y"
 	testPermissionsDiff(t, p1, p2, "p1", "p2", []string{})
+
+	// A privilege that only one side has is a difference too, even when
+	// its value is empty.
+	p2.UserPermissions[0].Privileges["Super_priv"] = ""
+	testPermissionsDiff(t, p1, p2, "p1", "p2", []string{
+		`permissions differ on user %:vt: Super_priv is missing on p1 but "" on p2`,
+	})
+
+	// A permission listed twice is compared in list order.
+	p1.UserPermissions = append(p1.UserPermissions, p1.UserPermissions[0])
+	testPermissionsDiff(t, p1, p2, "p1", "p2", []string{
+		"permissions differ on user %:vt:\n" +
+			"p1: UserPermission PasswordChecksum(4831957779889520640) Insert_priv(N) Select_priv(Y)\n" +
+			" differs from:\n" +
+			"p2: UserPermission PasswordChecksum(4831957779889520640) Insert_priv(N) Select_priv(Y) Super_priv()",
+		"p1 has an extra user %:vt",
+	})
+}
+
+func TestTablePermissionsDiff(t *testing.T) {
+	p1 := &tabletmanagerdatapb.Permissions{}
+	p1.TablePermissions = append(p1.TablePermissions, NewTablePermission(mapToSQLResults(map[string]string{
+		"Host":        "%",
+		"Db":          "vt_live",
+		"User":        "vt",
+		"Table_name":  "t1",
+		"Table_priv":  "Select,Insert",
+		"Column_priv": "",
+		// Test the next fields are skipped (to avoid drifts).
+		"Grantor":   "root@localhost",
+		"Timestamp": "2016-11-08 02:56:23",
+	})))
+	p1.ColumnPermissions = append(p1.ColumnPermissions, NewColumnPermission(mapToSQLResults(map[string]string{
+		"Host":        "%",
+		"Db":          "vt_live",
+		"User":        "vt",
+		"Table_name":  "t1",
+		"Column_name": "c1",
+		"Column_priv": "Select",
+		"Timestamp":   "2016-11-08 02:56:23",
+	})))
+
+	if PermissionsString(p1) !=
+		"User Permissions:\n"+
+			"Db Permissions:\n"+
+			"Table Permissions:\n"+
+			"  %:vt_live:vt:t1: TablePermission Column_priv() Table_priv(Select,Insert)\n"+
+			"Column Permissions:\n"+
+			"  %:vt_live:vt:t1:c1: ColumnPermission Column_priv(Select)\n" {
+		t.Logf("Actual: %v", p1.String())
+		t.Fail()
+	}
+
+	testPermissionsDiff(t, p1, p1, "p1-1", "p1-2", []string{})
+
+	p2 := &tabletmanagerdatapb.Permissions{}
+	testPermissionsDiff(t, p1, p2, "p1", "p2", []string{
+		"p1 has an extra table grant %:vt_live:vt:t1",
+		"p1 has an extra column grant %:vt_live:vt:t1:c1",
+	})
+
+	p2.TablePermissions = append(p2.TablePermissions, NewTablePermission(mapToSQLResults(map[string]string{
+		"Host":        "%",
+		"Db":          "vt_live",
+		"User":        "vt",
+		"Table_name":  "t1",
+		"Table_priv":  "Select",
+		"Column_priv": "",
+		"Grantor":     "vt_dba@localhost",
+		"Timestamp":   "2016-11-09 02:56:23",
+	})))
+	p2.ColumnPermissions = p1.ColumnPermissions
+	testPermissionsDiff(t, p1, p2, "p1", "p2", []string{
+		`permissions differ on table grant %:vt_live:vt:t1: Table_priv is "Select,Insert" on p1 but "Select" on p2`,
+	})
+
+	p2.TablePermissions[0].Privileges["Table_priv"] = "Select,Insert"
+	testPermissionsDiff(t, p1, p2, "p1", "p2", []string{})
 }
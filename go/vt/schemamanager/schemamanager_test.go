@@ -20,7 +20,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -32,6 +34,7 @@ import (
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/schematools"
 	"vitess.io/vitess/go/vt/vttablet/faketmclient"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 	"vitess.io/vitess/go/vt/vttablet/tmclienttest"
@@ -132,6 +135,64 @@ func TestSchemaManagerRun(t *testing.T) {
 	}
 }
 
+func TestSchemaManagerRespectThrottler(t *testing.T) {
+	sql := "create table test_table (pk int)"
+	tests := []struct {
+		name            string
+		maxWait         time.Duration
+		throttledChecks int
+		wantChecks      int
+		wantErr         string
+	}{
+		{
+			name:       "not throttled",
+			wantChecks: 1,
+		},
+		{
+			name:            "waits for the throttler",
+			maxWait:         time.Minute,
+			throttledChecks: 1,
+			wantChecks:      2,
+		},
+		{
+			name:            "aborts once the maximum wait is exceeded",
+			maxWait:         time.Millisecond,
+			throttledChecks: 1,
+			wantChecks:      1,
+			wantErr:         "exceeds the maximum throttle wait",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := newFakeController([]string{sql}, false, false, false)
+			fakeTmc := newFakeTabletManagerClient()
+			fakeTmc.AddSchemaDefinition("vt_test_keyspace", &tabletmanagerdatapb.SchemaDefinition{})
+			fakeTmc.ThrottledChecks = tt.throttledChecks
+
+			logger := logutil.NewMemoryLogger()
+			executor := NewTabletExecutor("TestSchemaManagerRespectThrottler", newFakeTopo(t), fakeTmc, logger, testWaitReplicasTimeout, 0, sqlparser.NewTestParser())
+			executor.SetThrottleWaiter(schematools.NewThrottleWaiter(fakeTmc, logger, "my-app", tt.maxWait))
+
+			_, err := Run(context.Background(), controller, executor)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			// Each of the 3 primaries of test_keyspace checks its throttler
+			// before the statement is applied on it.
+			require.Len(t, fakeTmc.throttlerChecks, 3)
+			for alias, appNames := range fakeTmc.throttlerChecks {
+				assert.Lenf(t, appNames, tt.wantChecks, "throttler checks of %s", alias)
+				for _, appName := range appNames {
+					assert.Equal(t, "my-app", appName)
+				}
+			}
+		})
+	}
+}
+
 func TestSchemaManagerExecutorFail(t *testing.T) {
 	sql := "create table test_table (pk int)"
 	controller := newFakeController([]string{sql}, false, false, false)
@@ -254,6 +315,12 @@ type fakeTabletManagerClient struct {
 	EnableExecuteFetchAsDbaError bool
 	preflightSchemas             map[string]*tabletmanagerdatapb.SchemaChangeResult
 	schemaDefinitions            map[string]*tabletmanagerdatapb.SchemaDefinition
+
+	// ThrottledChecks is the number of throttler checks of each tablet that
+	// are throttled before the throttler lets the app through.
+	ThrottledChecks int
+	mu              sync.Mutex
+	throttlerChecks map[string][]string
 }
 
 func (client *fakeTabletManagerClient) AddSchemaChange(sql string, schemaResult *tabletmanagerdatapb.SchemaChangeResult) {
@@ -300,6 +367,26 @@ func (client *fakeTabletManagerClient) ExecuteMultiFetchAsDba(ctx context.Contex
 	return client.TabletManagerClient.ExecuteMultiFetchAsDba(ctx, tablet, usePool, req)
 }
 
+func (client *fakeTabletManagerClient) CheckThrottler(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.throttlerChecks == nil {
+		client.throttlerChecks = make(map[string][]string)
+	}
+	alias := topoproto.TabletAliasString(tablet.Alias)
+	client.throttlerChecks[alias] = append(client.throttlerChecks[alias], req.AppName)
+	if len(client.throttlerChecks[alias]) <= client.ThrottledChecks {
+		return &tabletmanagerdatapb.CheckThrottlerResponse{
+			ResponseCode: tabletmanagerdatapb.CheckThrottlerResponseCode_THRESHOLD_EXCEEDED,
+			Summary:      "lag exceeds threshold",
+		}, nil
+	}
+	return &tabletmanagerdatapb.CheckThrottlerResponse{
+		ResponseCode: tabletmanagerdatapb.CheckThrottlerResponseCode_OK,
+	}, nil
+}
+
 // newFakeTopo returns a topo with:
 // - a keyspace named 'test_keyspace'.
 // - 3 shards named '1', '2', '3'.
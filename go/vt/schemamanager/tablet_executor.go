@@ -59,6 +59,9 @@ type TabletExecutor struct {
 	uuids               []string
 	batchSize           int64
 	parser              *sqlparser.Parser
+	throttleWaiter      *schematools.ThrottleWaiter
+	// shards restricts the change to some shards of the keyspace, if set.
+	shards []string
 }
 
 // NewTabletExecutor creates a new TabletExecutor instance
@@ -101,6 +104,19 @@ func (exec *TabletExecutor) SetUUIDList(uuids []string) error {
 	return nil
 }
 
+// SetThrottleWaiter makes the statements that are applied directly on the
+// primaries, rather than as online DDL, wait for their throttler first.
+func (exec *TabletExecutor) SetThrottleWaiter(throttleWaiter *schematools.ThrottleWaiter) {
+	exec.throttleWaiter = throttleWaiter
+}
+
+// SetShards restricts the schema changes to the given shards of the
+// keyspace, e.g. to apply them to a few canary shards first. All the shards
+// are changed if it is not called.
+func (exec *TabletExecutor) SetShards(shards []string) {
+	exec.shards = shards
+}
+
 // hasProvidedUUIDs returns true when UUIDs were provided
 func (exec *TabletExecutor) hasProvidedUUIDs() bool {
 	return len(exec.uuids) != 0
@@ -116,6 +132,17 @@ func (exec *TabletExecutor) Open(ctx context.Context, keyspace string) error {
 	if err != nil {
 		return fmt.Errorf("unable to get shards for keyspace: %s, error: %v", keyspace, err)
 	}
+	if len(exec.shards) > 0 {
+		selected := make(map[string]*topo.ShardInfo, len(exec.shards))
+		for _, shardName := range exec.shards {
+			shardInfo, ok := shards[shardName]
+			if !ok {
+				return fmt.Errorf("shard: %s does not exist in keyspace: %s", shardName, keyspace)
+			}
+			selected[shardName] = shardInfo
+		}
+		shards = selected
+	}
 	exec.tablets = make([]*topodatapb.Tablet, 0, len(shards))
 	for shardName, shardInfo := range shards {
 		if !shardInfo.HasPrimary() {
@@ -588,6 +615,10 @@ func (exec *TabletExecutor) executeOneTablet(
 				return
 			}
 		}
+		if err := exec.throttleWaiter.Wait(ctx, tablet); err != nil {
+			errChan <- ShardWithError{Shard: tablet.Shard, Err: err.Error()}
+			return
+		}
 		request := &tabletmanagerdatapb.ExecuteMultiFetchAsDbaRequest{
 			Sql:     []byte(sql),
 			MaxRows: 10,
@@ -52,6 +52,27 @@ func TestTabletExecutorOpen(t *testing.T) {
 	require.NoError(t, err, "open an opened executor should also succeed")
 }
 
+func TestTabletExecutorOpenWithShards(t *testing.T) {
+	executor := newFakeExecutor(t)
+	ctx := context.Background()
+
+	executor.SetShards([]string{"0", "2"})
+	err := executor.Open(ctx, "test_keyspace")
+	require.NoError(t, err)
+	defer executor.Close()
+
+	shards := make([]string, 0, len(executor.tablets))
+	for _, tablet := range executor.tablets {
+		shards = append(shards, tablet.Shard)
+	}
+	assert.ElementsMatch(t, []string{"0", "2"}, shards)
+
+	executor = newFakeExecutor(t)
+	executor.SetShards([]string{"0", "3"})
+	err = executor.Open(ctx, "test_keyspace")
+	require.ErrorContains(t, err, "shard: 3 does not exist in keyspace: test_keyspace")
+}
+
 func TestTabletExecutorOpenWithEmptyPrimaryAlias(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
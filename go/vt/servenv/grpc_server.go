@@ -274,9 +274,23 @@ func interceptors() []grpc.ServerOption {
 
 	trace.AddGrpcServerOptions(interceptors.Add)
 
+	interceptors.streamInterceptors = append(interceptors.streamInterceptors, serviceInterceptors.streamInterceptors...)
+	interceptors.unaryInterceptors = append(interceptors.unaryInterceptors, serviceInterceptors.unaryInterceptors...)
+
 	return interceptors.Build()
 }
 
+// serviceInterceptors are the interceptors added by the services with
+// AddGRPCServerInterceptors. They run after the ones above.
+var serviceInterceptors serverInterceptorBuilder
+
+// AddGRPCServerInterceptors adds interceptors to the gRPC server, e.g. for a
+// service to check the calls to its methods. It has to be called before Run
+// creates the server.
+func AddGRPCServerInterceptors(s grpc.StreamServerInterceptor, u grpc.UnaryServerInterceptor) {
+	serviceInterceptors.Add(s, u)
+}
+
 func serveGRPC() {
 	if grpccommon.EnableGRPCPrometheus() {
 		grpc_prometheus.Register(GRPCServer)
@@ -65,7 +65,7 @@ func initResolver(t *testing.T, ctx context.Context) *Resolver {
 
 	// And rebuild both.
 	for _, keyspace := range []string{"sks", "uks"} {
-		if err := topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, keyspace, []string{cell}, false); err != nil {
+		if err := topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, keyspace, []string{cell}, false, false); err != nil {
 			t.Fatalf("RebuildKeyspace(%v) failed: %v", keyspace, err)
 		}
 	}
@@ -77,12 +77,12 @@ func initResolver(t *testing.T, ctx context.Context) *Resolver {
 	require.NoError(t, err, "CreateShard(-80) failed: %v")
 
 	// Rebuild should error because allowPartial is false and shard does not cover full keyrange
-	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, "rks", []string{cell}, false)
+	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, "rks", []string{cell}, false, false)
 	require.Error(t, err, "RebuildKeyspace(rks) failed")
 	require.EqualError(t, err, "keyspace partition for PRIMARY in cell cell1 does not end with max key")
 
 	// Rebuild should succeed with allowPartial true
-	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, "rks", []string{cell}, true)
+	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, "rks", []string{cell}, true, false)
 	require.NoError(t, err, "RebuildKeyspace(rks) failed")
 
 	// Create missing shard
@@ -90,7 +90,7 @@ func initResolver(t *testing.T, ctx context.Context) *Resolver {
 	require.NoError(t, err, "CreateShard(80-) failed: %v")
 
 	// Rebuild should now succeed even with allowPartial false
-	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, "rks", []string{cell}, false)
+	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, "rks", []string{cell}, false, false)
 	require.NoError(t, err, "RebuildKeyspace(rks) failed")
 
 	return NewResolver(rs, &tabletconntest.FakeQueryService{}, cell)
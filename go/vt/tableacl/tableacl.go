@@ -18,6 +18,8 @@ package tableacl
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
@@ -282,6 +284,21 @@ func (tacl *tableACL) Config() *tableaclpb.Config {
 	return tacl.config.CloneVT()
 }
 
+// ConfigHash returns a hex encoded SHA-256 hash of the current tableacl
+// configuration, so that the configs of different tablets can be compared.
+func ConfigHash() (string, error) {
+	return currentTableACL.ConfigHash()
+}
+
+func (tacl *tableACL) ConfigHash() (string, error) {
+	data, err := tacl.Config().MarshalVT()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 // Register registers an AclFactory.
 func Register(name string, factory acl.Factory) {
 	mu.Lock()
@@ -118,6 +118,32 @@ func TestInitFromProto(t *testing.T) {
 	}
 }
 
+func TestConfigHash(t *testing.T) {
+	tacl := tableACL{factory: &simpleacl.Factory{}}
+	config := &tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			Name:                 "group01",
+			TableNamesOrPrefixes: []string{"test_table"},
+			Readers:              []string{"vt"},
+		}},
+	}
+	require.NoError(t, tacl.Set(config))
+	hash, err := tacl.ConfigHash()
+	require.NoError(t, err)
+	require.Len(t, hash, 64)
+
+	require.NoError(t, tacl.Set(config.CloneVT()))
+	got, err := tacl.ConfigHash()
+	require.NoError(t, err)
+	require.Equal(t, hash, got, "the same config has the same hash")
+
+	config.TableGroups[0].Writers = []string{"vt"}
+	require.NoError(t, tacl.Set(config))
+	got, err = tacl.ConfigHash()
+	require.NoError(t, err)
+	require.NotEqual(t, hash, got, "a different config has a different hash")
+}
+
 func TestTableACLValidateConfig(t *testing.T) {
 	tests := []struct {
 		names []string
@@ -17,10 +17,11 @@ limitations under the License.
 package topo
 
 import (
+	"context"
 	"fmt"
+	"maps"
 	"path"
-
-	"context"
+	"slices"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
@@ -112,14 +113,28 @@ func (ts *Server) DeleteCellsAlias(ctx context.Context, alias string) error {
 	return ts.globalCell.Delete(ctx, filePath, nil)
 }
 
+// CellsAliasOptions controls the validation done by
+// Server.CreateCellsAliasWithOptions and Server.UpdateCellsAliasWithOptions.
+type CellsAliasOptions struct {
+	// AllowOverlap allows the alias to have cells that are in other aliases.
+	// Overlapping aliases make the cells vtgate routes to ambiguous.
+	AllowOverlap bool
+}
+
 // CreateCellsAlias creates a new CellInfo with the provided content.
 func (ts *Server) CreateCellsAlias(ctx context.Context, alias string, cellsAlias *topodatapb.CellsAlias) error {
+	return ts.CreateCellsAliasWithOptions(ctx, alias, cellsAlias, nil)
+}
+
+// CreateCellsAliasWithOptions creates a new CellsAlias with the provided
+// content, validated according to opt, which may be nil.
+func (ts *Server) CreateCellsAliasWithOptions(ctx context.Context, alias string, cellsAlias *topodatapb.CellsAlias, opt *CellsAliasOptions) error {
 	currentAliases, err := ts.GetCellsAliases(ctx, true)
 	if err != nil {
 		return err
 	}
 
-	if err := validateAlias(currentAliases, alias, cellsAlias); err != nil {
+	if err := validateAlias(currentAliases, alias, cellsAlias, opt); err != nil {
 		return fmt.Errorf("cells alias %v is not valid: %v", alias, err)
 	}
 
@@ -142,6 +157,12 @@ func (ts *Server) CreateCellsAlias(ctx context.Context, alias string, cellsAlias
 
 // UpdateCellsAlias updates cells for a given alias
 func (ts *Server) UpdateCellsAlias(ctx context.Context, alias string, update func(*topodatapb.CellsAlias) error) error {
+	return ts.UpdateCellsAliasWithOptions(ctx, alias, update, nil)
+}
+
+// UpdateCellsAliasWithOptions updates cells for a given alias, validating the
+// result according to opt, which may be nil.
+func (ts *Server) UpdateCellsAliasWithOptions(ctx context.Context, alias string, update func(*topodatapb.CellsAlias) error, opt *CellsAliasOptions) error {
 	ts.clearCellAliasesCache()
 
 	filePath := pathForCellsAlias(alias)
@@ -178,7 +199,7 @@ func (ts *Server) UpdateCellsAlias(ctx context.Context, alias string, update fun
 			return err
 		}
 
-		if err := validateAlias(currentAliases, alias, cellsAlias); err != nil {
+		if err := validateAlias(currentAliases, alias, cellsAlias, opt); err != nil {
 			return fmt.Errorf("cells alias %v is not valid: %v", alias, err)
 		}
 
@@ -196,8 +217,22 @@ func (ts *Server) UpdateCellsAlias(ctx context.Context, alias string, update fun
 
 // validateAlias checks whether the given alias is allowed.
 // If the alias overlaps with any existing alias other than itself, this returns
-// a non-nil error.
-func validateAlias(currentAliases map[string]*topodatapb.CellsAlias, newAliasName string, newAlias *topodatapb.CellsAlias) error {
+// a non-nil error, unless opt allows it.
+func validateAlias(currentAliases map[string]*topodatapb.CellsAlias, newAliasName string, newAlias *topodatapb.CellsAlias, opt *CellsAliasOptions) error {
+	if opt != nil && opt.AllowOverlap {
+		return nil
+	}
+	if overlaps := CellsAliasOverlaps(currentAliases, newAliasName, newAlias); len(overlaps) > 0 {
+		name := slices.Sorted(maps.Keys(overlaps))[0]
+		return fmt.Errorf("cell set overlaps with existing alias %v on cells %v", name, overlaps[name])
+	}
+	return nil
+}
+
+// CellsAliasOverlaps returns the cells of the given alias that are in other
+// existing aliases, by the name of those aliases.
+func CellsAliasOverlaps(currentAliases map[string]*topodatapb.CellsAlias, newAliasName string, newAlias *topodatapb.CellsAlias) map[string][]string {
+	overlaps := map[string][]string{}
 	for name, alias := range currentAliases {
 		// Skip the alias we're checking against. It's allowed to overlap with itself.
 		if name == newAliasName {
@@ -206,9 +241,9 @@ func validateAlias(currentAliases map[string]*topodatapb.CellsAlias, newAliasNam
 
 		for _, cell := range alias.Cells {
 			if InCellList(cell, newAlias.Cells) {
-				return fmt.Errorf("cell set overlaps with existing alias %v", name)
+				overlaps[name] = append(overlaps[name], cell)
 			}
 		}
 	}
-	return nil
+	return overlaps
 }
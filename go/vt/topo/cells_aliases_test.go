@@ -28,6 +28,7 @@ func TestValidateAlias(t *testing.T) {
 		currentAliases map[string][]string
 		newAliasName   string
 		newAlias       []string
+		allowOverlap   bool
 		wantErrMsg     string
 	}{
 		{
@@ -46,7 +47,17 @@ func TestValidateAlias(t *testing.T) {
 			},
 			newAliasName: "overlaps_alias2",
 			newAlias:     []string{"cell_x", "cell_c"},
-			wantErrMsg:   "alias2",
+			wantErrMsg:   "alias2 on cells [cell_c]",
+		},
+		{
+			currentAliases: map[string][]string{
+				"alias1": {"cell_a", "cell_b"},
+				"alias2": {"cell_c", "cell_d"},
+			},
+			newAliasName: "overlap_allowed",
+			newAlias:     []string{"cell_x", "cell_c"},
+			allowOverlap: true,
+			wantErrMsg:   "",
 		},
 		{
 			currentAliases: map[string][]string{
@@ -75,7 +86,7 @@ func TestValidateAlias(t *testing.T) {
 		}
 		newAlias := &topodatapb.CellsAlias{Cells: test.newAlias}
 
-		gotErr := validateAlias(currentAliases, test.newAliasName, newAlias)
+		gotErr := validateAlias(currentAliases, test.newAliasName, newAlias, &CellsAliasOptions{AllowOverlap: test.allowOverlap})
 		if test.wantErrMsg == "" {
 			// Expect success.
 			if gotErr != nil {
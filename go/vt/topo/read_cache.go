@@ -0,0 +1,247 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"path"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+)
+
+var _ Conn = (*readCacheConn)(nil)
+
+var (
+	topoReadCacheHits = stats.NewCountersWithSingleLabel(
+		"TopologyReadCacheHits",
+		"TopologyReadCache hits per cell",
+		"Cell")
+
+	topoReadCacheMisses = stats.NewCountersWithSingleLabel(
+		"TopologyReadCacheMisses",
+		"TopologyReadCache misses per cell",
+		"Cell")
+)
+
+// WithReadCache returns a Server that shares the connections of ts, but
+// caches the files it reads with Get, such as the keyspace, shard and
+// tablet records. It is meant to be used for the duration of a single
+// read-heavy command, which would otherwise read the same records many
+// times, and must not be kept around: changes made by others are not seen.
+//
+// A cached file is invalidated when it is created, updated or deleted
+// through the returned Server, and all of them are when it acquires a
+// lock, so that reads under the lock see the changes made by the previous
+// holder.
+func (ts *Server) WithReadCache() *Server {
+	if ts.readCache != nil {
+		return ts
+	}
+
+	cache := &readCache{entries: make(map[string]*readCacheEntry)}
+	globalCell := cache.wrap(GlobalCell, ts.globalCell)
+	globalReadOnlyCell := globalCell
+	if ts.globalReadOnlyCell != ts.globalCell {
+		// Both global connections read the same files.
+		globalReadOnlyCell = cache.wrap(GlobalCell, ts.globalReadOnlyCell)
+	}
+	return &Server{
+		globalCell:         globalCell,
+		globalReadOnlyCell: globalReadOnlyCell,
+		factory:            ts.factory,
+		cellConns:          make(map[string]cellConn),
+		readCache:          cache,
+		parent:             ts,
+	}
+}
+
+// readCache holds the files read through the connections of a Server
+// returned by WithReadCache, by cell and path.
+type readCache struct {
+	mu      sync.Mutex
+	entries map[string]*readCacheEntry
+}
+
+// readCacheEntry is a file in the cache. Concurrent reads of the same file
+// wait for the first one, which closes done once it has set the other
+// fields.
+type readCacheEntry struct {
+	done     chan struct{}
+	contents []byte
+	version  Version
+	err      error
+}
+
+// wrap returns a Conn caching the files read through conn.
+func (c *readCache) wrap(cell string, conn Conn) Conn {
+	return &readCacheConn{
+		cache: c,
+		cell:  cell,
+		conn:  conn,
+	}
+}
+
+// invalidate removes a file from the cache.
+func (c *readCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// clear removes all the files from the cache.
+func (c *readCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*readCacheEntry)
+}
+
+// readCacheConn is a Conn caching the files it reads in a readCache. It
+// does not own the underlying Conn, which Close leaves open.
+type readCacheConn struct {
+	cache *readCache
+	cell  string
+	conn  Conn
+}
+
+func (rc *readCacheConn) key(filePath string) string {
+	return path.Join(rc.cell, filePath)
+}
+
+// ListDir is part of the Conn interface.
+func (rc *readCacheConn) ListDir(ctx context.Context, dirPath string, full bool) ([]DirEntry, error) {
+	return rc.conn.ListDir(ctx, dirPath, full)
+}
+
+// Create is part of the Conn interface.
+func (rc *readCacheConn) Create(ctx context.Context, filePath string, contents []byte) (Version, error) {
+	defer rc.cache.invalidate(rc.key(filePath))
+	return rc.conn.Create(ctx, filePath, contents)
+}
+
+// Update is part of the Conn interface. The file is invalidated even if the
+// update fails, as a BadVersion error means the cached version is stale.
+func (rc *readCacheConn) Update(ctx context.Context, filePath string, contents []byte, version Version) (Version, error) {
+	defer rc.cache.invalidate(rc.key(filePath))
+	return rc.conn.Update(ctx, filePath, contents, version)
+}
+
+// Get is part of the Conn interface. It returns the cached file if there is
+// one, and otherwise reads it and caches it. Errors are not cached.
+func (rc *readCacheConn) Get(ctx context.Context, filePath string) ([]byte, Version, error) {
+	key := rc.key(filePath)
+	rc.cache.mu.Lock()
+	entry, ok := rc.cache.entries[key]
+	if !ok {
+		entry = &readCacheEntry{done: make(chan struct{})}
+		rc.cache.entries[key] = entry
+	}
+	rc.cache.mu.Unlock()
+
+	if ok {
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+		if entry.err == nil {
+			topoReadCacheHits.Add(rc.cell, 1)
+			return entry.contents, entry.version, nil
+		}
+
+		// The first read failed, maybe because of its own context, so
+		// try again without caching.
+		topoReadCacheMisses.Add(rc.cell, 1)
+		return rc.conn.Get(ctx, filePath)
+	}
+
+	topoReadCacheMisses.Add(rc.cell, 1)
+	entry.contents, entry.version, entry.err = rc.conn.Get(ctx, filePath)
+	close(entry.done)
+	if entry.err != nil {
+		rc.cache.mu.Lock()
+		if rc.cache.entries[key] == entry {
+			delete(rc.cache.entries, key)
+		}
+		rc.cache.mu.Unlock()
+	}
+	return entry.contents, entry.version, entry.err
+}
+
+// GetVersion is part of the Conn interface.
+func (rc *readCacheConn) GetVersion(ctx context.Context, filePath string, version int64) ([]byte, error) {
+	return rc.conn.GetVersion(ctx, filePath, version)
+}
+
+// List is part of the Conn interface.
+func (rc *readCacheConn) List(ctx context.Context, filePathPrefix string) ([]KVInfo, error) {
+	return rc.conn.List(ctx, filePathPrefix)
+}
+
+// Delete is part of the Conn interface.
+func (rc *readCacheConn) Delete(ctx context.Context, filePath string, version Version) error {
+	defer rc.cache.invalidate(rc.key(filePath))
+	return rc.conn.Delete(ctx, filePath, version)
+}
+
+// Lock is part of the Conn interface.
+func (rc *readCacheConn) Lock(ctx context.Context, dirPath, contents string) (LockDescriptor, error) {
+	return rc.clearOnLock(rc.conn.Lock(ctx, dirPath, contents))
+}
+
+// LockWithTTL is part of the Conn interface.
+func (rc *readCacheConn) LockWithTTL(ctx context.Context, dirPath, contents string, ttl time.Duration) (LockDescriptor, error) {
+	return rc.clearOnLock(rc.conn.LockWithTTL(ctx, dirPath, contents, ttl))
+}
+
+// LockName is part of the Conn interface.
+func (rc *readCacheConn) LockName(ctx context.Context, dirPath, contents string) (LockDescriptor, error) {
+	return rc.clearOnLock(rc.conn.LockName(ctx, dirPath, contents))
+}
+
+// TryLock is part of the Conn interface.
+func (rc *readCacheConn) TryLock(ctx context.Context, dirPath, contents string) (LockDescriptor, error) {
+	return rc.clearOnLock(rc.conn.TryLock(ctx, dirPath, contents))
+}
+
+// clearOnLock clears the cache once a lock is acquired.
+func (rc *readCacheConn) clearOnLock(ld LockDescriptor, err error) (LockDescriptor, error) {
+	if err == nil {
+		rc.cache.clear()
+	}
+	return ld, err
+}
+
+// Watch is part of the Conn interface.
+func (rc *readCacheConn) Watch(ctx context.Context, filePath string) (*WatchData, <-chan *WatchData, error) {
+	return rc.conn.Watch(ctx, filePath)
+}
+
+// WatchRecursive is part of the Conn interface.
+func (rc *readCacheConn) WatchRecursive(ctx context.Context, dirPath string) ([]*WatchDataRecursive, <-chan *WatchDataRecursive, error) {
+	return rc.conn.WatchRecursive(ctx, dirPath)
+}
+
+// NewLeaderParticipation is part of the Conn interface.
+func (rc *readCacheConn) NewLeaderParticipation(name, id string) (LeaderParticipation, error) {
+	return rc.conn.NewLeaderParticipation(name, id)
+}
+
+// Close is part of the Conn interface. The underlying Conn is shared, and
+// is left open.
+func (rc *readCacheConn) Close() {}
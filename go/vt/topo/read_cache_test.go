@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// TestReadCache tests that a Server with a read cache reads each record
+// once, and sees the changes it makes itself.
+func TestReadCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts, factory := memorytopo.NewServerAndFactory(ctx, "zone1")
+	defer ts.Close()
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "0"))
+	tablet := &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		Keyspace: "ks",
+		Shard:    "0",
+		Type:     topodatapb.TabletType_REPLICA,
+	}
+	require.NoError(t, ts.CreateTablet(ctx, tablet))
+
+	cached := ts.WithReadCache()
+	require.Same(t, cached, cached.WithReadCache())
+	gets := func() int64 {
+		return factory.GetCallStats().Counts()["Get"]
+	}
+
+	// The keyspace and shard records are read once.
+	before := gets()
+	for range 3 {
+		_, err := cached.GetKeyspace(ctx, "ks")
+		require.NoError(t, err)
+		_, err = cached.GetShard(ctx, "ks", "0")
+		require.NoError(t, err)
+	}
+	require.Equal(t, before+2, gets())
+
+	// So are the tablet record and the cell info used to connect to its
+	// cell.
+	before = gets()
+	for range 3 {
+		_, err := cached.GetTablet(ctx, tablet.Alias)
+		require.NoError(t, err)
+	}
+	require.Equal(t, before+2, gets())
+
+	// Changes made through the cache are seen.
+	_, err := cached.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.IsPrimaryServing = false
+		return nil
+	})
+	require.NoError(t, err)
+	si, err := cached.GetShard(ctx, "ks", "0")
+	require.NoError(t, err)
+	require.False(t, si.IsPrimaryServing)
+
+	// Changes made by others are not, until a lock is taken.
+	_, err = ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.IsPrimaryServing = true
+		return nil
+	})
+	require.NoError(t, err)
+	si, err = cached.GetShard(ctx, "ks", "0")
+	require.NoError(t, err)
+	require.False(t, si.IsPrimaryServing)
+
+	lockCtx, unlock, err := cached.LockShard(ctx, "ks", "0", "TestReadCache")
+	require.NoError(t, err)
+	si, err = cached.GetShard(lockCtx, "ks", "0")
+	require.NoError(t, err)
+	require.True(t, si.IsPrimaryServing)
+	unlock(&err)
+	require.NoError(t, err)
+
+	// Errors are not cached.
+	_, err = cached.GetShard(ctx, "ks", "1")
+	require.True(t, topo.IsErrType(err, topo.NoNode))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "1"))
+	_, err = cached.GetShard(ctx, "ks", "1")
+	require.NoError(t, err)
+}
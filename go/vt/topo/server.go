@@ -148,6 +148,12 @@ type Server struct {
 	// will read the list of addresses for that cell from the
 	// global cluster and create clients as needed.
 	cellConns map[string]cellConn
+
+	// readCache, if set, caches the records read through this server,
+	// whose cell connections are then shared with parent. See
+	// WithReadCache.
+	readCache *readCache
+	parent    *Server
 }
 
 type cellConn struct {
@@ -284,6 +290,20 @@ func (ts *Server) ConnForCell(ctx context.Context, cell string) (Conn, error) {
 		return nil, err
 	}
 
+	// A caching server shares the connections of its parent.
+	if ts.parent != nil {
+		conn, err := ts.parent.connForCellInfo(cell, ci)
+		if err != nil {
+			return nil, err
+		}
+		return ts.readCache.wrap(cell, conn), nil
+	}
+	return ts.connForCellInfo(cell, ci)
+}
+
+// connForCellInfo returns a Conn object for the given cell, connecting to
+// the topo server described by its cell info.
+func (ts *Server) connForCellInfo(cell string, ci *topodata.CellInfo) (Conn, error) {
 	// Return a cached client if present.
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
@@ -481,12 +481,44 @@ func DeleteTabletReplicationData(ctx context.Context, ts *Server, tablet *topoda
 	return RemoveShardReplicationRecord(ctx, ts, tablet.Alias.Cell, tablet.Keyspace, tablet.Shard, tablet.Alias)
 }
 
+// TabletReadError is the error reading one of the tablets of a tablet map.
+type TabletReadError struct {
+	Alias *topodatapb.TabletAlias
+	Err   error
+}
+
+// Error is part of the error interface.
+func (e *TabletReadError) Error() string {
+	return fmt.Sprintf("%v: %v", topoproto.TabletAliasString(e.Alias), e.Err)
+}
+
+// Unwrap returns the error reading the tablet.
+func (e *TabletReadError) Unwrap() error {
+	return e.Err
+}
+
 // GetTabletMap tries to read all the tablets in the provided list,
 // and returns them in a map.
 // If error is ErrPartialResult, the results in the map are
 // incomplete, meaning some tablets couldn't be read.
 // The map is indexed by topoproto.TabletAliasString(tablet alias).
 func (ts *Server) GetTabletMap(ctx context.Context, tabletAliases []*topodatapb.TabletAlias, opt *GetTabletsByCellOptions) (map[string]*TabletInfo, error) {
+	tabletMap, readErrs, err := ts.GetTabletMapWithErrors(ctx, tabletAliases, opt)
+	if err != nil {
+		return nil, err
+	}
+	if len(readErrs) > 0 {
+		return tabletMap, NewError(PartialResult, readErrs[0].Alias.GetCell())
+	}
+	return tabletMap, nil
+}
+
+// GetTabletMapWithErrors is like GetTabletMap, but instead of a
+// single ErrPartialResult it returns the error of each tablet that
+// couldn't be read, sorted by alias, so that the caller can decide
+// whether to proceed with the tablets that could be read. Tablets that
+// don't exist are ignored, as they can be deleted concurrently.
+func (ts *Server) GetTabletMapWithErrors(ctx context.Context, tabletAliases []*topodatapb.TabletAlias, opt *GetTabletsByCellOptions) (map[string]*TabletInfo, []*TabletReadError, error) {
 	span, ctx := trace.NewSpan(ctx, "topo.GetTabletMap")
 	span.Annotate("num_tablets", len(tabletAliases))
 	defer span.Finish()
@@ -495,12 +527,12 @@ func (ts *Server) GetTabletMap(ctx context.Context, tabletAliases []*topodatapb.
 		mu        sync.Mutex
 		wg        sync.WaitGroup
 		tabletMap = make(map[string]*TabletInfo)
-		returnErr error
+		readErrs  []*TabletReadError
 	)
 
 	for _, tabletAlias := range tabletAliases {
 		if tabletAlias == nil {
-			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "nil tablet alias in list")
+			return nil, nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "nil tablet alias in list")
 		}
 		wg.Add(1)
 		go func(tabletAlias *topodatapb.TabletAlias) {
@@ -511,9 +543,8 @@ func (ts *Server) GetTabletMap(ctx context.Context, tabletAliases []*topodatapb.
 			if err != nil {
 				log.Warningf("%v: %v", tabletAlias, err)
 				// There can be data races removing nodes - ignore them for now.
-				// We only need to set this on first error.
-				if returnErr == nil && !IsErrType(err, NoNode) {
-					returnErr = NewError(PartialResult, tabletAlias.GetCell())
+				if !IsErrType(err, NoNode) {
+					readErrs = append(readErrs, &TabletReadError{Alias: tabletAlias, Err: err})
 				}
 			} else {
 				if opt != nil && opt.KeyspaceShard != nil {
@@ -529,7 +560,10 @@ func (ts *Server) GetTabletMap(ctx context.Context, tabletAliases []*topodatapb.
 		}(tabletAlias)
 	}
 	wg.Wait()
-	return tabletMap, returnErr
+	sort.Slice(readErrs, func(i, j int) bool {
+		return topoproto.TabletAliasString(readErrs[i].Alias) < topoproto.TabletAliasString(readErrs[j].Alias)
+	})
+	return tabletMap, readErrs, nil
 }
 
 // GetTabletList tries to read all the tablets in the provided list,
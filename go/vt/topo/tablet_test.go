@@ -662,6 +662,42 @@ func TestServerGetTabletMapAndList(t *testing.T) {
 	}
 }
 
+// TestGetTabletMapWithErrors tests that GetTabletMapWithErrors returns the
+// tablets it could read along with the error of each one it couldn't.
+func TestGetTabletMapWithErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts, factory := memorytopo.NewServerAndFactory(ctx, "zone1")
+	defer ts.Close()
+	var aliases []*topodatapb.TabletAlias
+	for uid := int32(1); uid <= 3; uid++ {
+		tablet := getTablet("ks", "0", "zone1", uid)
+		require.NoError(t, ts.CreateTablet(ctx, tablet))
+		aliases = append(aliases, tablet.Alias)
+	}
+	// A tablet that doesn't exist is ignored.
+	aliases = append(aliases, &topodatapb.TabletAlias{Cell: "zone1", Uid: 4})
+
+	fakeErr := errors.New("fake error")
+	factory.AddOperationError(memorytopo.Get, "tablets/zone1-0000000003/Tablet", fakeErr)
+	factory.AddOperationError(memorytopo.Get, "tablets/zone1-0000000001/Tablet", fakeErr)
+
+	tabletMap, readErrs, err := ts.GetTabletMapWithErrors(ctx, aliases, nil)
+	require.NoError(t, err)
+	require.Len(t, tabletMap, 1)
+	require.Contains(t, tabletMap, "zone1-0000000002")
+	require.Len(t, readErrs, 2)
+	require.Equal(t, "zone1-0000000001: fake error", readErrs[0].Error())
+	require.Equal(t, "zone1-0000000003: fake error", readErrs[1].Error())
+	require.ErrorIs(t, readErrs[0], fakeErr)
+
+	// GetTabletMap returns the same tablets with ErrPartialResult.
+	tabletMap, err = ts.GetTabletMap(ctx, aliases, nil)
+	require.True(t, topo.IsErrType(err, topo.PartialResult), "Not a partial result: %v", err)
+	require.Len(t, tabletMap, 1)
+}
+
 // TestGetTabletsIndividuallyByCell tests the GetTabletsIndividuallyByCell function.
 func TestGetTabletsIndividuallyByCell(t *testing.T) {
 	tests := []struct {
@@ -50,6 +50,12 @@ func (k *KeyspaceVSchemaInfo) CloneVT() *KeyspaceVSchemaInfo {
 	return kc
 }
 
+// Version returns the version of the vschema in the topo when it was read
+// or last saved. It is nil for a vschema that hasn't been saved yet.
+func (k *KeyspaceVSchemaInfo) Version() Version {
+	return k.version
+}
+
 // SaveVSchema saves a Vschema. A valid Vschema should be passed in.
 // It does not verify its correctness beyond marshaling it.
 func (ts *Server) SaveVSchema(ctx context.Context, ksvs *KeyspaceVSchemaInfo) error {
@@ -154,25 +160,76 @@ func (ts *Server) SaveRoutingRules(ctx context.Context, routingRules *vschemapb.
 	return err
 }
 
+// UpdateRoutingRules saves the routing rules into the topo like
+// SaveRoutingRules, but only if they haven't changed since they were read at
+// the given version by GetRoutingRulesWithVersion. It returns a BadVersion
+// error if they have.
+func (ts *Server) UpdateRoutingRules(ctx context.Context, routingRules *vschemapb.RoutingRules, version Version) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := routingRules.MarshalVT()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case version == nil && len(data) == 0:
+		// There were no routing rules, and there still must be none.
+		_, _, err = ts.globalCell.Get(ctx, RoutingRulesFile)
+		if err == nil {
+			return NewError(BadVersion, RoutingRulesFile)
+		}
+		if IsErrType(err, NoNode) {
+			return nil
+		}
+		return err
+	case version == nil:
+		_, err = ts.globalCell.Create(ctx, RoutingRulesFile, data)
+		if IsErrType(err, NodeExists) {
+			return NewError(BadVersion, RoutingRulesFile)
+		}
+		return err
+	case len(data) == 0:
+		err = ts.globalCell.Delete(ctx, RoutingRulesFile, version)
+	default:
+		_, err = ts.globalCell.Update(ctx, RoutingRulesFile, data, version)
+	}
+	if IsErrType(err, NoNode) {
+		// The routing rules were deleted since they were read.
+		return NewError(BadVersion, RoutingRulesFile)
+	}
+	return err
+}
+
 // GetRoutingRules fetches the routing rules from the topo.
 func (ts *Server) GetRoutingRules(ctx context.Context) (*vschemapb.RoutingRules, error) {
+	rr, _, err := ts.GetRoutingRulesWithVersion(ctx)
+	return rr, err
+}
+
+// GetRoutingRulesWithVersion fetches the routing rules from the topo, with
+// the version to pass to UpdateRoutingRules. The version is nil if there are
+// no routing rules.
+func (ts *Server) GetRoutingRulesWithVersion(ctx context.Context) (*vschemapb.RoutingRules, Version, error) {
 	if err := ctx.Err(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	rr := &vschemapb.RoutingRules{}
-	data, _, err := ts.globalCell.Get(ctx, RoutingRulesFile)
+	data, version, err := ts.globalCell.Get(ctx, RoutingRulesFile)
 	if err != nil {
 		if IsErrType(err, NoNode) {
-			return rr, nil
+			return rr, nil, nil
 		}
-		return nil, err
+		return nil, nil, err
 	}
 	err = rr.UnmarshalVT(data)
 	if err != nil {
-		return nil, vterrors.Wrapf(err, "bad routing rules data: %q", data)
+		return nil, nil, vterrors.Wrapf(err, "bad routing rules data: %q", data)
 	}
-	return rr, nil
+	return rr, version, nil
 }
 
 // SaveShardRoutingRules saves the shard routing rules into the topo.
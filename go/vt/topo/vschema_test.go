@@ -0,0 +1,84 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func TestUpdateRoutingRules(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	rules1 := &vschemapb.RoutingRules{
+		Rules: []*vschemapb.RoutingRule{{FromTable: "t1", ToTables: []string{"ks1.t1"}}},
+	}
+	rules2 := &vschemapb.RoutingRules{
+		Rules: []*vschemapb.RoutingRule{{FromTable: "t1", ToTables: []string{"ks2.t1"}}},
+	}
+
+	// No routing rules yet.
+	rr, version, err := ts.GetRoutingRulesWithVersion(ctx)
+	require.NoError(t, err)
+	require.Empty(t, rr.Rules)
+	require.Nil(t, version)
+
+	// Someone else creates them first.
+	require.NoError(t, ts.SaveRoutingRules(ctx, rules2))
+	err = ts.UpdateRoutingRules(ctx, rules1, version)
+	require.True(t, topo.IsErrType(err, topo.BadVersion), err)
+	err = ts.UpdateRoutingRules(ctx, &vschemapb.RoutingRules{}, version)
+	require.True(t, topo.IsErrType(err, topo.BadVersion), err)
+
+	// Updated at the version they were read at.
+	_, version, err = ts.GetRoutingRulesWithVersion(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, version)
+	require.NoError(t, ts.UpdateRoutingRules(ctx, rules1, version))
+	rr, err = ts.GetRoutingRules(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "ks1.t1", rr.Rules[0].ToTables[0])
+
+	// The version is stale now.
+	err = ts.UpdateRoutingRules(ctx, rules2, version)
+	require.True(t, topo.IsErrType(err, topo.BadVersion), err)
+
+	// Deleted at the version they were read at.
+	_, version, err = ts.GetRoutingRulesWithVersion(ctx)
+	require.NoError(t, err)
+	require.NoError(t, ts.UpdateRoutingRules(ctx, &vschemapb.RoutingRules{}, version))
+	rr, version, err = ts.GetRoutingRulesWithVersion(ctx)
+	require.NoError(t, err)
+	require.Empty(t, rr.Rules)
+	require.Nil(t, version)
+
+	// Created if there are still none.
+	require.NoError(t, ts.UpdateRoutingRules(ctx, rules2, nil))
+	rr, err = ts.GetRoutingRules(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "ks2.t1", rr.Rules[0].ToTables[0])
+}
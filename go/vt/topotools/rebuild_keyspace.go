@@ -19,9 +19,14 @@ package topotools
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
+	"google.golang.org/protobuf/proto"
+
 	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
@@ -30,14 +35,16 @@ import (
 )
 
 // RebuildKeyspace rebuilds the serving graph data while locking out other changes.
-func RebuildKeyspace(ctx context.Context, log logutil.Logger, ts *topo.Server, keyspace string, cells []string, allowPartial bool) (err error) {
+// The SrvKeyspace records that are unchanged are only written if forceWrite
+// is set.
+func RebuildKeyspace(ctx context.Context, log logutil.Logger, ts *topo.Server, keyspace string, cells []string, allowPartial, forceWrite bool) (err error) {
 	ctx, unlock, lockErr := ts.LockKeyspace(ctx, keyspace, "RebuildKeyspace")
 	if lockErr != nil {
 		return lockErr
 	}
 	defer unlock(&err)
 
-	return RebuildKeyspaceLocked(ctx, log, ts, keyspace, cells, allowPartial)
+	return RebuildKeyspaceLocked(ctx, log, ts, keyspace, cells, allowPartial, forceWrite)
 }
 
 // RebuildKeyspaceLocked should only be used with an action lock on the keyspace
@@ -45,8 +52,9 @@ func RebuildKeyspace(ctx context.Context, log logutil.Logger, ts *topo.Server, k
 // guaranteed.
 //
 // Take data from the global keyspace and rebuild the local serving
-// copies in each cell.
-func RebuildKeyspaceLocked(ctx context.Context, log logutil.Logger, ts *topo.Server, keyspace string, cells []string, allowPartial bool) error {
+// copies in each cell. A copy that is unchanged is not written again, as
+// that would needlessly notify its watchers, unless forceWrite is set.
+func RebuildKeyspaceLocked(ctx context.Context, log logutil.Logger, ts *topo.Server, keyspace string, cells []string, allowPartial, forceWrite bool) error {
 	if err := topo.CheckKeyspaceLocked(ctx, keyspace); err != nil {
 		return err
 	}
@@ -82,10 +90,12 @@ func RebuildKeyspaceLocked(ctx context.Context, log logutil.Logger, ts *topo.Ser
 	//   key: cell
 	//   value: topo.SrvKeyspace object being built
 	srvKeyspaceMap := make(map[string]*topodatapb.SrvKeyspace)
+	existingSrvKeyspaces := make(map[string]*topodatapb.SrvKeyspace)
 	for _, cell := range cells {
 		srvKeyspace, err := ts.GetSrvKeyspace(ctx, cell, keyspace)
 		switch {
 		case err == nil:
+			existingSrvKeyspaces[cell] = srvKeyspace
 			for _, partition := range srvKeyspace.GetPartitions() {
 				for _, shardTabletControl := range partition.GetShardTabletControls() {
 					if shardTabletControl.QueryServiceDisabled {
@@ -103,48 +113,22 @@ func RebuildKeyspaceLocked(ctx context.Context, log logutil.Logger, ts *topo.Ser
 		}
 	}
 
-	servedTypes := []topodatapb.TabletType{topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY}
-
-	// for each entry in the srvKeyspaceMap map, we do the following:
-	// - get the Shard structures for each shard / cell
-	// - if not present, build an empty one from global Shard
-	// - sort the shards in the list by range
-	// - check the ranges are compatible (no hole, covers everything)
+	// for each entry in the srvKeyspaceMap map, add the serving shards, sorted
+	// by range, and check the ranges are compatible.
 	for cell, srvKeyspace := range srvKeyspaceMap {
-		for _, si := range shards {
-			// We rebuild keyspace iff shard primary is in a serving state.
-			if !si.GetIsPrimaryServing() {
-				continue
-			}
-			// for each type this shard is supposed to serve,
-			// add it to srvKeyspace.Partitions
-			for _, tabletType := range servedTypes {
-				partition := topoproto.SrvKeyspaceGetPartition(srvKeyspace, tabletType)
-				if partition == nil {
-					partition = &topodatapb.SrvKeyspace_KeyspacePartition{
-						ServedType: tabletType,
-					}
-					srvKeyspace.Partitions = append(srvKeyspace.Partitions, partition)
-				}
-				partition.ShardReferences = append(partition.ShardReferences, &topodatapb.ShardReference{
-					Name:     si.ShardName(),
-					KeyRange: si.KeyRange,
-				})
-			}
-		}
-
-		if !(ki.KeyspaceType == topodatapb.KeyspaceType_SNAPSHOT && allowPartial) {
-			// skip this check for SNAPSHOT keyspaces so that incomplete keyspaces can still serve
-			if err := topo.OrderAndCheckPartitions(cell, srvKeyspace); err != nil {
-				return err
-			}
+		if err := addShardsToSrvKeyspace(cell, srvKeyspace, ki, shards, allowPartial); err != nil {
+			return err
 		}
-
 	}
+
 	// And then finally save the keyspace objects, in parallel.
 	rec := concurrency.AllErrorRecorder{}
 	wg := sync.WaitGroup{}
 	for cell, srvKeyspace := range srvKeyspaceMap {
+		if existing, ok := existingSrvKeyspaces[cell]; ok && !forceWrite && proto.Equal(existing, srvKeyspace) {
+			log.Infof("SrvKeyspace for keyspace %v in cell %v is unchanged", keyspace, cell)
+			continue
+		}
 		wg.Add(1)
 		go func(cell string, srvKeyspace *topodatapb.SrvKeyspace) {
 			defer wg.Done()
@@ -156,3 +140,204 @@ func RebuildKeyspaceLocked(ctx context.Context, log logutil.Logger, ts *topo.Ser
 	wg.Wait()
 	return rec.Error()
 }
+
+// addShardsToSrvKeyspace adds the partitions of the serving shards to the
+// SrvKeyspace being built for cell, and checks that their key ranges are
+// compatible (no hole, covers everything).
+func addShardsToSrvKeyspace(cell string, srvKeyspace *topodatapb.SrvKeyspace, ki *topo.KeyspaceInfo, shards map[string]*topo.ShardInfo, allowPartial bool) error {
+	servedTypes := []topodatapb.TabletType{topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY}
+
+	for _, si := range shards {
+		// We rebuild keyspace iff shard primary is in a serving state.
+		if !si.GetIsPrimaryServing() {
+			continue
+		}
+		// for each type this shard is supposed to serve,
+		// add it to srvKeyspace.Partitions
+		for _, tabletType := range servedTypes {
+			partition := topoproto.SrvKeyspaceGetPartition(srvKeyspace, tabletType)
+			if partition == nil {
+				partition = &topodatapb.SrvKeyspace_KeyspacePartition{
+					ServedType: tabletType,
+				}
+				srvKeyspace.Partitions = append(srvKeyspace.Partitions, partition)
+			}
+			partition.ShardReferences = append(partition.ShardReferences, &topodatapb.ShardReference{
+				Name:     si.ShardName(),
+				KeyRange: si.KeyRange,
+			})
+		}
+	}
+
+	if !(ki.KeyspaceType == topodatapb.KeyspaceType_SNAPSHOT && allowPartial) {
+		// skip this check for SNAPSHOT keyspaces so that incomplete keyspaces can still serve
+		if err := topo.OrderAndCheckPartitions(cell, srvKeyspace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SrvKeyspaceFinding is a problem ValidateSrvKeyspaces found with the
+// SrvKeyspace of a cell.
+type SrvKeyspaceFinding struct {
+	Cell string
+	// Warning is set if the SrvKeyspace can't be compared with the shard
+	// records, rather than differing from them.
+	Warning bool
+	Message string
+}
+
+// ValidateSrvKeyspaces compares the SrvKeyspace of the keyspace in the given
+// cells, or in all cells, with the one RebuildKeyspace would write, computed
+// from the shard records without writing anything. It returns a finding per
+// cell whose SrvKeyspace is missing, differs, or can't be compared, with the
+// scoped rebuild that fixes it.
+func ValidateSrvKeyspaces(ctx context.Context, ts *topo.Server, keyspace string, cells []string) ([]SrvKeyspaceFinding, error) {
+	ki, err := ts.GetKeyspace(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if len(cells) == 0 {
+		cells, err = ts.GetCellInfoNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	cells = append([]string(nil), cells...)
+	sort.Strings(cells)
+
+	shards, err := ts.FindAllShardsInKeyspace(ctx, keyspace, &topo.FindAllShardsInKeyspaceOptions{
+		Concurrency: 8,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []SrvKeyspaceFinding
+	for _, cell := range cells {
+		fix := fmt.Sprintf("run 'RebuildKeyspaceGraph --cells=%v %v' to rebuild it", cell, keyspace)
+		srvKeyspace, err := ts.GetSrvKeyspace(ctx, cell, keyspace)
+		switch {
+		case topo.IsErrType(err, topo.NoNode):
+			findings = append(findings, SrvKeyspaceFinding{
+				Cell:    cell,
+				Message: fmt.Sprintf("cell %v has no SrvKeyspace for keyspace %v, %v", cell, keyspace, fix),
+			})
+			continue
+		case err != nil:
+			return nil, err
+		}
+		if srvKeyspaceHasDisabledQueryService(srvKeyspace) {
+			findings = append(findings, SrvKeyspaceFinding{
+				Cell:    cell,
+				Warning: true,
+				Message: fmt.Sprintf("SrvKeyspace of keyspace %v in cell %v disables the query service of some shards, as during a migration, so it is not compared with the shard records", keyspace, cell),
+			})
+			continue
+		}
+
+		expected := &topodatapb.SrvKeyspace{
+			ThrottlerConfig: ki.ThrottlerConfig,
+		}
+		// A SNAPSHOT keyspace may serve an incomplete set of shards.
+		allowPartial := ki.KeyspaceType == topodatapb.KeyspaceType_SNAPSHOT
+		if err := addShardsToSrvKeyspace(cell, expected, ki, shards, allowPartial); err != nil {
+			findings = append(findings, SrvKeyspaceFinding{
+				Cell:    cell,
+				Message: fmt.Sprintf("SrvKeyspace of keyspace %v in cell %v can't be computed from the shard records: %v", keyspace, cell, err),
+			})
+			continue
+		}
+		if diffs := srvKeyspaceDiffs(expected, srvKeyspace); len(diffs) > 0 {
+			findings = append(findings, SrvKeyspaceFinding{
+				Cell:    cell,
+				Message: fmt.Sprintf("SrvKeyspace of keyspace %v in cell %v doesn't match the shard records: %v; %v", keyspace, cell, strings.Join(diffs, "; "), fix),
+			})
+		}
+	}
+	return findings, nil
+}
+
+// srvKeyspaceHasDisabledQueryService returns true if a partition of the
+// SrvKeyspace disables the query service of a shard, which RebuildKeyspace
+// refuses to overwrite.
+func srvKeyspaceHasDisabledQueryService(srvKeyspace *topodatapb.SrvKeyspace) bool {
+	for _, partition := range srvKeyspace.Partitions {
+		for _, shardTabletControl := range partition.ShardTabletControls {
+			if shardTabletControl.QueryServiceDisabled {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// srvKeyspaceDiffs describes how the partitions and throttler config of
+// actual differ from expected, in served tablet type order. The order of
+// the shards in a partition doesn't matter.
+func srvKeyspaceDiffs(expected, actual *topodatapb.SrvKeyspace) []string {
+	shardKeyRanges := func(srvKeyspace *topodatapb.SrvKeyspace) map[topodatapb.TabletType]map[string]string {
+		byType := make(map[topodatapb.TabletType]map[string]string)
+		for _, partition := range srvKeyspace.Partitions {
+			keyRanges := make(map[string]string, len(partition.ShardReferences))
+			for _, ref := range partition.ShardReferences {
+				keyRanges[ref.Name] = key.KeyRangeString(ref.KeyRange)
+			}
+			byType[partition.ServedType] = keyRanges
+		}
+		return byType
+	}
+	expectedByType := shardKeyRanges(expected)
+	actualByType := shardKeyRanges(actual)
+
+	servedTypes := make([]topodatapb.TabletType, 0, len(expectedByType)+len(actualByType))
+	for servedType := range expectedByType {
+		servedTypes = append(servedTypes, servedType)
+	}
+	for servedType := range actualByType {
+		if _, ok := expectedByType[servedType]; !ok {
+			servedTypes = append(servedTypes, servedType)
+		}
+	}
+	sort.Slice(servedTypes, func(i, j int) bool { return servedTypes[i] < servedTypes[j] })
+
+	var diffs []string
+	for _, servedType := range servedTypes {
+		expectedShards, actualShards := expectedByType[servedType], actualByType[servedType]
+		var missing, unexpected, moved []string
+		for name, keyRange := range expectedShards {
+			actualKeyRange, ok := actualShards[name]
+			switch {
+			case !ok:
+				missing = append(missing, name)
+			case actualKeyRange != keyRange:
+				moved = append(moved, fmt.Sprintf("%v has key range %v instead of %v", name, actualKeyRange, keyRange))
+			}
+		}
+		for name := range actualShards {
+			if _, ok := expectedShards[name]; !ok {
+				unexpected = append(unexpected, name)
+			}
+		}
+		sort.Strings(missing)
+		sort.Strings(unexpected)
+		sort.Strings(moved)
+
+		var problems []string
+		if len(missing) > 0 {
+			problems = append(problems, fmt.Sprintf("missing shards %v", strings.Join(missing, ", ")))
+		}
+		if len(unexpected) > 0 {
+			problems = append(problems, fmt.Sprintf("unexpected shards %v", strings.Join(unexpected, ", ")))
+		}
+		problems = append(problems, moved...)
+		if len(problems) > 0 {
+			diffs = append(diffs, fmt.Sprintf("%v partition has %v", servedType, strings.Join(problems, ", ")))
+		}
+	}
+	if !proto.Equal(expected.ThrottlerConfig, actual.ThrottlerConfig) {
+		diffs = append(diffs, "throttler config differs from the keyspace record")
+	}
+	return diffs
+}
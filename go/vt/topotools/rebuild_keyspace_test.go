@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotools
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// TestRebuildKeyspaceUnchanged tests that RebuildKeyspace only writes the
+// SrvKeyspace records that changed, unless forced to.
+func TestRebuildKeyspaceUnchanged(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "-80"))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "80-"))
+
+	conn, err := ts.ConnForCell(ctx, "cell1")
+	require.NoError(t, err)
+	srvKeyspaceVersion := func() topo.Version {
+		_, version, err := conn.Get(ctx, path.Join(topo.KeyspacesPath, "ks", topo.SrvKeyspaceFile))
+		require.NoError(t, err)
+		return version
+	}
+
+	logger := logutil.NewMemoryLogger()
+	require.NoError(t, RebuildKeyspace(ctx, logger, ts, "ks", nil, false, false))
+	version := srvKeyspaceVersion()
+
+	// A rebuild with nothing to change leaves the record alone.
+	logger.Clear()
+	require.NoError(t, RebuildKeyspace(ctx, logger, ts, "ks", nil, false, false))
+	require.Equal(t, version.String(), srvKeyspaceVersion().String())
+	require.Contains(t, logger.String(), "SrvKeyspace for keyspace ks in cell cell1 is unchanged")
+
+	// Unless it is forced to write it.
+	require.NoError(t, RebuildKeyspace(ctx, logger, ts, "ks", nil, false, true))
+	require.NotEqual(t, version.String(), srvKeyspaceVersion().String())
+	version = srvKeyspaceVersion()
+
+	// A change is written.
+	lockCtx, unlock, err := ts.LockKeyspace(ctx, "ks", "TestRebuildKeyspaceUnchanged")
+	require.NoError(t, err)
+	ki, err := ts.GetKeyspace(lockCtx, "ks")
+	require.NoError(t, err)
+	ki.ThrottlerConfig = &topodatapb.ThrottlerConfig{Enabled: true}
+	require.NoError(t, ts.UpdateKeyspace(lockCtx, ki))
+	unlock(&err)
+	require.NoError(t, err)
+	require.NoError(t, RebuildKeyspace(ctx, logger, ts, "ks", nil, false, false))
+	require.NotEqual(t, version.String(), srvKeyspaceVersion().String())
+}
+
+func TestValidateSrvKeyspaces(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1", "cell2", "cell3", "cell4")
+	defer ts.Close()
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "-80"))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "80-"))
+	require.NoError(t, RebuildKeyspace(ctx, logutil.NewMemoryLogger(), ts, "ks", []string{"cell1", "cell2", "cell4"}, false, false))
+
+	findings, err := ValidateSrvKeyspaces(ctx, ts, "ks", []string{"cell1", "cell2", "cell4"})
+	require.NoError(t, err)
+	require.Empty(t, findings)
+
+	// cell2 is stale: it doesn't serve 80- and still serves a shard that was
+	// since deleted. cell4 is migrating.
+	stale, err := ts.GetSrvKeyspace(ctx, "cell2", "ks")
+	require.NoError(t, err)
+	for _, partition := range stale.Partitions {
+		if partition.ServedType == topodatapb.TabletType_PRIMARY {
+			partition.ShardReferences = []*topodatapb.ShardReference{
+				partition.ShardReferences[0],
+				{Name: "80-c0", KeyRange: &topodatapb.KeyRange{Start: []byte{0x80}, End: []byte{0xc0}}},
+			}
+		}
+	}
+	require.NoError(t, ts.UpdateSrvKeyspace(ctx, "cell2", "ks", stale))
+	migrating, err := ts.GetSrvKeyspace(ctx, "cell4", "ks")
+	require.NoError(t, err)
+	migrating.Partitions[0].ShardTabletControls = []*topodatapb.ShardTabletControl{{Name: "-80", QueryServiceDisabled: true}}
+	require.NoError(t, ts.UpdateSrvKeyspace(ctx, "cell4", "ks", migrating))
+
+	findings, err = ValidateSrvKeyspaces(ctx, ts, "ks", nil)
+	require.NoError(t, err)
+	require.Equal(t, []SrvKeyspaceFinding{{
+		Cell:    "cell2",
+		Message: "SrvKeyspace of keyspace ks in cell cell2 doesn't match the shard records: PRIMARY partition has missing shards 80-, unexpected shards 80-c0; run 'RebuildKeyspaceGraph --cells=cell2 ks' to rebuild it",
+	}, {
+		Cell:    "cell3",
+		Message: "cell cell3 has no SrvKeyspace for keyspace ks, run 'RebuildKeyspaceGraph --cells=cell3 ks' to rebuild it",
+	}, {
+		Cell:    "cell4",
+		Warning: true,
+		Message: "SrvKeyspace of keyspace ks in cell cell4 disables the query service of some shards, as during a migration, so it is not compared with the shard records",
+	}}, findings)
+}
@@ -0,0 +1,303 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// ShardReplicationFixResult lists the changes FixShardReplication made to
+// the replication graph of a shard in a cell.
+type ShardReplicationFixResult struct {
+	// Cell, Keyspace and Shard identify the graph.
+	Cell     string
+	Keyspace string
+	Shard    string
+	// Added are the tablets that were missing from the graph.
+	Added []*topodatapb.TabletAlias
+	// Removed are the entries that didn't match a tablet of the shard in
+	// the cell, with the reason they were removed.
+	Removed []*topodatapb.ShardReplicationError
+}
+
+// FixShardReplication makes the replication graph of a shard in a cell
+// match the tablet records: it removes the entries for tablets that don't
+// exist or belong elsewhere, and adds the tablets of the shard that are
+// missing, for instance after a partial restore of the topo. The graph is
+// created if it doesn't exist.
+//
+// Unlike topo.FixShardReplication, which fixes one problem at a time, it
+// fixes them all.
+func FixShardReplication(ctx context.Context, ts *topo.Server, logger logutil.Logger, cell, keyspace, shard string) (*ShardReplicationFixResult, error) {
+	tablets, err := ts.GetTabletsByCell(ctx, cell, &topo.GetTabletsByCellOptions{
+		KeyspaceShard: &topo.KeyspaceShard{Keyspace: keyspace, Shard: shard},
+	})
+	if err != nil {
+		// Adding tablets from a partial list is fine, but it would be
+		// wrong to report the graph as complete.
+		return nil, err
+	}
+	return fixShardReplication(ctx, ts, logger, cell, keyspace, shard, tablets)
+}
+
+// RebuildReplicationGraph runs FixShardReplication on every shard of the
+// keyspace in the given cells, or in all cells if there are none. The
+// tablet records of each cell are read once, and each shard is locked
+// while its graphs are fixed. It returns the results of the shards it
+// fixed, in shard then cell order, even on error.
+//
+// It is safe to run while tablets are running: entries are only removed
+// if their tablet record was found invalid, and the entries tablets add
+// concurrently are kept.
+func RebuildReplicationGraph(ctx context.Context, ts *topo.Server, logger logutil.Logger, keyspace string, cells []string) ([]*ShardReplicationFixResult, error) {
+	var err error
+	if len(cells) == 0 {
+		cells, err = ts.GetCellInfoNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	shards, err := ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(shards)
+	isShard := make(map[string]bool, len(shards))
+	for _, shard := range shards {
+		isShard[shard] = true
+	}
+
+	// tabletsByCellShard maps cell then shard to the tablets.
+	tabletsByCellShard := make(map[string]map[string][]*topo.TabletInfo, len(cells))
+	for _, cell := range cells {
+		tablets, err := ts.GetTabletsByCell(ctx, cell, &topo.GetTabletsByCellOptions{
+			KeyspaceShard: &topo.KeyspaceShard{Keyspace: keyspace},
+		})
+		if err != nil {
+			return nil, err
+		}
+		byShard := make(map[string][]*topo.TabletInfo)
+		for _, ti := range tablets {
+			if ti.Keyspace != keyspace {
+				continue
+			}
+			if !isShard[ti.Shard] {
+				logger.Warningf("Tablet %v is in shard %v/%v, which does not exist, skipping it", topoproto.TabletAliasString(ti.Alias), keyspace, ti.Shard)
+				continue
+			}
+			byShard[ti.Shard] = append(byShard[ti.Shard], ti)
+		}
+		tabletsByCellShard[cell] = byShard
+	}
+
+	var results []*ShardReplicationFixResult
+	for _, shard := range shards {
+		shardResults, err := rebuildShardReplicationGraph(ctx, ts, logger, keyspace, shard, cells, tabletsByCellShard)
+		results = append(results, shardResults...)
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+func rebuildShardReplicationGraph(ctx context.Context, ts *topo.Server, logger logutil.Logger, keyspace, shard string, cells []string, tabletsByCellShard map[string]map[string][]*topo.TabletInfo) (results []*ShardReplicationFixResult, err error) {
+	ctx, unlock, lockErr := ts.LockShard(ctx, keyspace, shard, "RebuildReplicationGraph")
+	if lockErr != nil {
+		return nil, lockErr
+	}
+	defer unlock(&err)
+
+	for _, cell := range cells {
+		result, err := fixShardReplication(ctx, ts, logger, cell, keyspace, shard, tabletsByCellShard[cell][shard])
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// fixShardReplication implements FixShardReplication, given the tablets
+// of the cell to check the graph against. Only those in the shard are
+// considered.
+func fixShardReplication(ctx context.Context, ts *topo.Server, logger logutil.Logger, cell, keyspace, shard string, tablets []*topo.TabletInfo) (*ShardReplicationFixResult, error) {
+	var nodes []*topodatapb.ShardReplication_Node
+	sri, err := ts.GetShardReplication(ctx, cell, keyspace, shard)
+	switch {
+	case err == nil:
+		nodes = sri.Nodes
+	case topo.IsErrType(err, topo.NoNode):
+		logger.Warningf("The replication graph of %v/%v in cell %v does not exist, rebuilding it", keyspace, shard, cell)
+	default:
+		return nil, err
+	}
+
+	result := &ShardReplicationFixResult{Cell: cell, Keyspace: keyspace, Shard: shard}
+	inGraph := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		alias := topoproto.TabletAliasString(node.TabletAlias)
+		if inGraph[alias] {
+			continue
+		}
+		inGraph[alias] = true
+
+		ti, err := ts.GetTablet(ctx, node.TabletAlias)
+		switch {
+		case topo.IsErrType(err, topo.NoNode):
+			logger.Warningf("Tablet %v is in the replication graph, but does not exist, removing it", alias)
+			result.Removed = append(result.Removed, &topodatapb.ShardReplicationError{
+				Type:        topodatapb.ShardReplicationError_NOT_FOUND,
+				TabletAlias: node.TabletAlias,
+			})
+		case err != nil:
+			return nil, err
+		case ti.Keyspace != keyspace || ti.Shard != shard || ti.Alias.Cell != cell:
+			logger.Warningf("Tablet '%v' is in the replication graph, but has wrong keyspace/shard/cell, removing it", ti.Tablet)
+			result.Removed = append(result.Removed, &topodatapb.ShardReplicationError{
+				Type:        topodatapb.ShardReplicationError_TOPOLOGY_MISMATCH,
+				TabletAlias: node.TabletAlias,
+			})
+		}
+	}
+
+	for _, ti := range tablets {
+		if ti.Keyspace != keyspace || ti.Shard != shard {
+			continue
+		}
+		if alias := topoproto.TabletAliasString(ti.Alias); !inGraph[alias] {
+			logger.Warningf("Tablet %v is missing from the replication graph, adding it", alias)
+			result.Added = append(result.Added, ti.Alias)
+		}
+	}
+
+	if len(result.Added) == 0 && len(result.Removed) == 0 {
+		logger.Infof("All entries in replication graph are valid")
+		return result, nil
+	}
+
+	removed := make(map[string]bool, len(result.Removed))
+	for _, problem := range result.Removed {
+		removed[topoproto.TabletAliasString(problem.TabletAlias)] = true
+	}
+	err = ts.UpdateShardReplicationFields(ctx, cell, keyspace, shard, func(sr *topodatapb.ShardReplication) error {
+		nodes := make([]*topodatapb.ShardReplication_Node, 0, len(sr.Nodes)+len(result.Added))
+		seen := make(map[string]bool, len(sr.Nodes))
+		for _, node := range sr.Nodes {
+			alias := topoproto.TabletAliasString(node.TabletAlias)
+			if removed[alias] || seen[alias] {
+				continue
+			}
+			seen[alias] = true
+			nodes = append(nodes, node)
+		}
+		for _, tabletAlias := range result.Added {
+			if !seen[topoproto.TabletAliasString(tabletAlias)] {
+				nodes = append(nodes, &topodatapb.ShardReplication_Node{TabletAlias: tabletAlias})
+			}
+		}
+		sr.Nodes = nodes
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ValidateShardReplicationCells checks that the replication graphs of a
+// shard in the given cells, or in all cells if there are none, partition
+// its tablets by cell: every tablet must be listed once, in the graph of
+// the cell of its alias. It returns a description of each violation, with
+// the command that fixes it.
+func ValidateShardReplicationCells(ctx context.Context, ts *topo.Server, keyspace, shard string, cells []string) ([]string, error) {
+	if len(cells) == 0 {
+		var err error
+		cells, err = ts.GetCellInfoNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	cells = append([]string(nil), cells...)
+	sort.Strings(cells)
+
+	var results []string
+	// graphCells maps each alias to the cells of the graphs it is in, and
+	// tabletCells to the cell of its tablet.
+	graphCells := make(map[string][]string)
+	tabletCells := make(map[string]string)
+	for _, cell := range cells {
+		sri, err := ts.GetShardReplication(ctx, cell, keyspace, shard)
+		switch {
+		case topo.IsErrType(err, topo.NoNode):
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		counts := make(map[string]int, len(sri.Nodes))
+		for _, node := range sri.Nodes {
+			alias := topoproto.TabletAliasString(node.TabletAlias)
+			counts[alias]++
+			if counts[alias] > 1 {
+				continue
+			}
+			graphCells[alias] = append(graphCells[alias], cell)
+			tabletCells[alias] = node.TabletAlias.Cell
+			if node.TabletAlias.Cell != cell {
+				results = append(results, fmt.Sprintf("tablet %v is in the replication graph of %v/%v in cell %v instead of cell %v, run 'ShardReplicationFix %v %v/%v' to remove it", alias, keyspace, shard, cell, node.TabletAlias.Cell, cell, keyspace, shard))
+			}
+		}
+		for _, node := range sri.Nodes {
+			alias := topoproto.TabletAliasString(node.TabletAlias)
+			if counts[alias] > 1 {
+				results = append(results, fmt.Sprintf("tablet %v is listed %d times in the replication graph of %v/%v in cell %v, run 'ShardReplicationFix %v %v/%v' to remove the duplicates", alias, counts[alias], keyspace, shard, cell, cell, keyspace, shard))
+				counts[alias] = 0
+			}
+		}
+	}
+
+	aliases := make([]string, 0, len(graphCells))
+	for alias := range graphCells {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		inCells := graphCells[alias]
+		if len(inCells) <= 1 {
+			continue
+		}
+
+		var fixes []string
+		for _, cell := range inCells {
+			if cell != tabletCells[alias] {
+				fixes = append(fixes, fmt.Sprintf("'ShardReplicationFix %v %v/%v'", cell, keyspace, shard))
+			}
+		}
+		results = append(results, fmt.Sprintf("tablet %v is in the replication graphs of %v/%v in cells %v, run %v to keep it only in cell %v", alias, keyspace, shard, strings.Join(inCells, ", "), strings.Join(fixes, " and "), tabletCells[alias]))
+	}
+	return results, nil
+}
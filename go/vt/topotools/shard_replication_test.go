@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestValidateShardReplicationCells(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1", "cell2", "cell3")
+
+	setNodes := func(cell string, aliases ...*topodatapb.TabletAlias) {
+		err := ts.UpdateShardReplicationFields(ctx, cell, "ks", "0", func(sr *topodatapb.ShardReplication) error {
+			sr.Nodes = nil
+			for _, alias := range aliases {
+				sr.Nodes = append(sr.Nodes, &topodatapb.ShardReplication_Node{TabletAlias: alias})
+			}
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	alias := func(cell string, uid uint32) *topodatapb.TabletAlias {
+		return &topodatapb.TabletAlias{Cell: cell, Uid: uid}
+	}
+
+	setNodes("cell1", alias("cell1", 1), alias("cell1", 2))
+	setNodes("cell2", alias("cell2", 3))
+	results, err := ValidateShardReplicationCells(ctx, ts, "ks", "0", nil)
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	// cell1-2 is also in cell2, cell2-3 is listed twice and cell3 has
+	// cell2-4.
+	setNodes("cell2", alias("cell2", 3), alias("cell1", 2), alias("cell2", 3))
+	setNodes("cell3", alias("cell2", 4))
+	results, err = ValidateShardReplicationCells(ctx, ts, "ks", "0", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"tablet cell1-0000000002 is in the replication graph of ks/0 in cell cell2 instead of cell cell1, run 'ShardReplicationFix cell2 ks/0' to remove it",
+		"tablet cell2-0000000003 is listed 2 times in the replication graph of ks/0 in cell cell2, run 'ShardReplicationFix cell2 ks/0' to remove the duplicates",
+		"tablet cell2-0000000004 is in the replication graph of ks/0 in cell cell3 instead of cell cell2, run 'ShardReplicationFix cell3 ks/0' to remove it",
+		"tablet cell1-0000000002 is in the replication graphs of ks/0 in cells cell1, cell2, run 'ShardReplicationFix cell2 ks/0' to keep it only in cell cell1",
+	}, results)
+
+	// Only the given cells are checked.
+	results, err = ValidateShardReplicationCells(ctx, ts, "ks", "0", []string{"cell1", "cell3"})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"tablet cell2-0000000004 is in the replication graph of ks/0 in cell cell3 instead of cell cell2, run 'ShardReplicationFix cell3 ks/0' to remove it",
+	}, results)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotools
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/vt/key"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// KeyRangeSegment is a segment of the keyspace id space, with the shards of
+// a SrvKeyspace partition that serve it. A segment that no shard serves is a
+// gap, and one that several shards serve is an overlap.
+type KeyRangeSegment struct {
+	KeyRange *topodatapb.KeyRange
+	// Shards are the names of the shards serving the segment, in order.
+	Shards []string
+}
+
+// IsGap returns true if no shard serves the segment.
+func (segment *KeyRangeSegment) IsGap() bool {
+	return len(segment.Shards) == 0
+}
+
+// IsOverlap returns true if several shards serve the segment.
+func (segment *KeyRangeSegment) IsOverlap() bool {
+	return len(segment.Shards) > 1
+}
+
+// PartitionSegments splits the keyspace id space at the boundaries of the
+// key ranges of the shards of the partition, and returns the segments in
+// key range order. Adjacent segments served by the same shards are merged,
+// so a partition without gaps or overlaps has a segment per shard. Shards
+// without a key range serve the whole keyspace id space.
+func PartitionSegments(partition *topodatapb.SrvKeyspace_KeyspacePartition) []*KeyRangeSegment {
+	// The min and max keys are implied, and not boundaries.
+	var boundaries [][]byte
+	for _, ref := range partition.ShardReferences {
+		if ref.KeyRange == nil {
+			continue
+		}
+		for _, boundary := range [][]byte{ref.KeyRange.Start, ref.KeyRange.End} {
+			if len(boundary) > 0 {
+				boundaries = append(boundaries, boundary)
+			}
+		}
+	}
+	slices.SortFunc(boundaries, key.Compare)
+	boundaries = slices.CompactFunc(boundaries, key.Equal)
+
+	var segments []*KeyRangeSegment
+	var start []byte
+	for i := 0; i <= len(boundaries); i++ {
+		var end []byte
+		if i < len(boundaries) {
+			end = boundaries[i]
+		}
+		keyRange := &topodatapb.KeyRange{Start: start, End: end}
+		start = end
+
+		var shards []string
+		for _, ref := range partition.ShardReferences {
+			if ref.KeyRange == nil || key.KeyRangeContainsKeyRange(ref.KeyRange, keyRange) {
+				shards = append(shards, ref.Name)
+			}
+		}
+		sort.Strings(shards)
+
+		if n := len(segments); n > 0 && slices.Equal(segments[n-1].Shards, shards) {
+			segments[n-1].KeyRange.End = keyRange.End
+			continue
+		}
+		segments = append(segments, &KeyRangeSegment{
+			KeyRange: keyRange,
+			Shards:   shards,
+		})
+	}
+
+	return segments
+}
+
+// FormatSrvKeyspacePartitions renders the partitions of the SrvKeyspace, in
+// served tablet type order, as the key range segments of each, with the
+// shards serving them, flagging gaps and overlaps.
+func FormatSrvKeyspacePartitions(srvKeyspace *topodatapb.SrvKeyspace) string {
+	partitions := slices.Clone(srvKeyspace.Partitions)
+	sort.SliceStable(partitions, func(i, j int) bool {
+		return partitions[i].ServedType < partitions[j].ServedType
+	})
+
+	sb := strings.Builder{}
+	for _, partition := range partitions {
+		fmt.Fprintf(&sb, "%v:\n", partition.ServedType)
+		for _, segment := range PartitionSegments(partition) {
+			fmt.Fprintf(&sb, "  %s: ", key.KeyRangeString(segment.KeyRange))
+			switch {
+			case segment.IsGap():
+				sb.WriteString("(gap)")
+			case segment.IsOverlap():
+				fmt.Fprintf(&sb, "%s (overlap)", strings.Join(segment.Shards, ", "))
+			default:
+				sb.WriteString(segment.Shards[0])
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// SrvKeyspacePartitionGaps returns a description of each gap in the key
+// range coverage of the partitions of the SrvKeyspace, in served tablet type
+// then key range order.
+func SrvKeyspacePartitionGaps(srvKeyspace *topodatapb.SrvKeyspace) []string {
+	partitions := slices.Clone(srvKeyspace.Partitions)
+	sort.SliceStable(partitions, func(i, j int) bool {
+		return partitions[i].ServedType < partitions[j].ServedType
+	})
+
+	var gaps []string
+	for _, partition := range partitions {
+		for _, segment := range PartitionSegments(partition) {
+			if segment.IsGap() {
+				gaps = append(gaps, fmt.Sprintf("no shard serves %s for %v", key.KeyRangeString(segment.KeyRange), partition.ServedType))
+			}
+		}
+	}
+
+	return gaps
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/key"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func shardReferences(t *testing.T, names ...string) []*topodatapb.ShardReference {
+	refs := make([]*topodatapb.ShardReference, 0, len(names))
+	for _, name := range names {
+		ref := &topodatapb.ShardReference{Name: name}
+		if name != "0" {
+			keyRanges, err := key.ParseShardingSpec(name)
+			require.NoError(t, err)
+			ref.KeyRange = keyRanges[0]
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+func TestPartitionSegments(t *testing.T) {
+	tests := []struct {
+		name   string
+		shards []string
+		want   map[string][]string
+	}{
+		{
+			name:   "unsharded",
+			shards: []string{"0"},
+			want:   map[string][]string{"-": {"0"}},
+		},
+		{
+			name:   "complete",
+			shards: []string{"80-", "-80"},
+			want:   map[string][]string{"-80": {"-80"}, "80-": {"80-"}},
+		},
+		{
+			name:   "gap",
+			shards: []string{"-40", "80-"},
+			want:   map[string][]string{"-40": {"-40"}, "40-80": nil, "80-": {"80-"}},
+		},
+		{
+			name:   "overlap",
+			shards: []string{"-80", "80-", "80-c0", "c0-"},
+			want:   map[string][]string{"-80": {"-80"}, "80-c0": {"80-", "80-c0"}, "c0-": {"80-", "c0-"}},
+		},
+		{
+			name:   "no shards",
+			shards: nil,
+			want:   map[string][]string{"-": nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments := PartitionSegments(&topodatapb.SrvKeyspace_KeyspacePartition{
+				ServedType:      topodatapb.TabletType_PRIMARY,
+				ShardReferences: shardReferences(t, tt.shards...),
+			})
+
+			got := make(map[string][]string, len(segments))
+			for i, segment := range segments {
+				if i > 0 {
+					assert.True(t, key.KeyRangeContiguous(segments[i-1].KeyRange, segment.KeyRange), "segments %d and %d are not contiguous", i-1, i)
+				}
+				got[key.KeyRangeString(segment.KeyRange)] = segment.Shards
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatSrvKeyspacePartitions(t *testing.T) {
+	srvKeyspace := &topodatapb.SrvKeyspace{
+		Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{
+			{
+				ServedType:      topodatapb.TabletType_REPLICA,
+				ShardReferences: shardReferences(t, "-80", "80-c0"),
+			},
+			{
+				ServedType:      topodatapb.TabletType_PRIMARY,
+				ShardReferences: shardReferences(t, "-80", "80-", "80-c0"),
+			},
+		},
+	}
+
+	want := `PRIMARY:
+  -80: -80
+  80-c0: 80-, 80-c0 (overlap)
+  c0-: 80-
+REPLICA:
+  -80: -80
+  80-c0: 80-c0
+  c0-: (gap)
+`
+	assert.Equal(t, want, FormatSrvKeyspacePartitions(srvKeyspace))
+	assert.Equal(t, []string{"no shard serves c0- for REPLICA"}, SrvKeyspacePartitionGaps(srvKeyspace))
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotools
+
+import (
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+// DiffSrvVSchemas compares the SrvVSchemas of two cells, and returns a
+// description of each difference: the keyspaces and tables only one of them
+// has, the tables and keyspace settings that differ, then the rules that
+// differ. It returns nothing if the SrvVSchemas are equal.
+func DiffSrvVSchemas(cell1 string, srvVSchema1 *vschemapb.SrvVSchema, cell2 string, srvVSchema2 *vschemapb.SrvVSchema) []string {
+	var diffs []string
+
+	for _, keyspace := range unionKeys(srvVSchema1.GetKeyspaces(), srvVSchema2.GetKeyspaces()) {
+		ks1, ok1 := srvVSchema1.GetKeyspaces()[keyspace]
+		ks2, ok2 := srvVSchema2.GetKeyspaces()[keyspace]
+		switch {
+		case !ok2:
+			diffs = append(diffs, fmt.Sprintf("keyspace %s is only in cell %s", keyspace, cell1))
+			continue
+		case !ok1:
+			diffs = append(diffs, fmt.Sprintf("keyspace %s is only in cell %s", keyspace, cell2))
+			continue
+		}
+
+		for _, table := range unionKeys(ks1.GetTables(), ks2.GetTables()) {
+			table1, ok1 := ks1.GetTables()[table]
+			table2, ok2 := ks2.GetTables()[table]
+			switch {
+			case !ok2:
+				diffs = append(diffs, fmt.Sprintf("table %s.%s is only in cell %s", keyspace, table, cell1))
+			case !ok1:
+				diffs = append(diffs, fmt.Sprintf("table %s.%s is only in cell %s", keyspace, table, cell2))
+			case !proto.Equal(table1, table2):
+				diffs = append(diffs, fmt.Sprintf("table %s.%s differs", keyspace, table))
+			}
+		}
+
+		// Tables were compared above, so compare everything else.
+		settings1 := ks1.CloneVT()
+		settings1.Tables = nil
+		settings2 := ks2.CloneVT()
+		settings2.Tables = nil
+		if !proto.Equal(settings1, settings2) {
+			diffs = append(diffs, fmt.Sprintf("keyspace %s differs outside of its tables", keyspace))
+		}
+	}
+
+	if !proto.Equal(srvVSchema1.GetRoutingRules(), srvVSchema2.GetRoutingRules()) {
+		diffs = append(diffs, "routing rules differ")
+	}
+	if !proto.Equal(srvVSchema1.GetShardRoutingRules(), srvVSchema2.GetShardRoutingRules()) {
+		diffs = append(diffs, "shard routing rules differ")
+	}
+	if !proto.Equal(srvVSchema1.GetKeyspaceRoutingRules(), srvVSchema2.GetKeyspaceRoutingRules()) {
+		diffs = append(diffs, "keyspace routing rules differ")
+	}
+	if !proto.Equal(srvVSchema1.GetMirrorRules(), srvVSchema2.GetMirrorRules()) {
+		diffs = append(diffs, "mirror rules differ")
+	}
+
+	return diffs
+}
+
+// unionKeys returns the keys of both maps, in order.
+func unionKeys[V any](m1 map[string]V, m2 map[string]V) []string {
+	keys := make([]string, 0, len(m1)+len(m2))
+	for k := range m1 {
+		keys = append(keys, k)
+	}
+	for k := range m2 {
+		if _, ok := m1[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
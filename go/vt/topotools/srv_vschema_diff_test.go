@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func TestDiffSrvVSchemas(t *testing.T) {
+	srvVSchema1 := &vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"ks1": {
+				Sharded: true,
+				Tables: map[string]*vschemapb.Table{
+					"t1": {Type: "sequence"},
+					"t2": {},
+				},
+			},
+			"ks2": {},
+			"ks3": {},
+		},
+		RoutingRules: &vschemapb.RoutingRules{
+			Rules: []*vschemapb.RoutingRule{{FromTable: "t1", ToTables: []string{"ks1.t1"}}},
+		},
+	}
+	srvVSchema2 := &vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"ks1": {
+				Tables: map[string]*vschemapb.Table{
+					"t1": {},
+					"t3": {},
+				},
+			},
+			"ks3": {},
+			"ks4": {},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"table ks1.t1 differs",
+		"table ks1.t2 is only in cell zone1",
+		"table ks1.t3 is only in cell zone2",
+		"keyspace ks1 differs outside of its tables",
+		"keyspace ks2 is only in cell zone1",
+		"keyspace ks4 is only in cell zone2",
+		"routing rules differ",
+	}, DiffSrvVSchemas("zone1", srvVSchema1, "zone2", srvVSchema2))
+	assert.Empty(t, DiffSrvVSchemas("zone1", srvVSchema1, "zone2", srvVSchema1.CloneVT()))
+}
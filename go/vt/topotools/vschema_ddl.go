@@ -47,6 +47,17 @@ func ApplyVSchemaDDL(ctx context.Context, ksName string, topoServer *topo.Server
 		}
 	}
 
+	return AlterVSchema(ksvs, alterVschema)
+}
+
+// AlterVSchema applies the given DDL statement to the given vschema
+// keyspace definition, which it modifies, and returns it.
+func AlterVSchema(ksvs *topo.KeyspaceVSchemaInfo, alterVschema *sqlparser.AlterVschema) (*topo.KeyspaceVSchemaInfo, error) {
+	ksName := ksvs.Name
+	if ksvs.Keyspace == nil {
+		ksvs.Keyspace = &vschemapb.Keyspace{}
+	}
+
 	if ksvs.Tables == nil {
 		ksvs.Tables = map[string]*vschemapb.Table{}
 	}
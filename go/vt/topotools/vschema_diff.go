@@ -0,0 +1,219 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotools
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+// DiffVSchemas compares the current VSchema of a keyspace with the one about
+// to replace it, and returns a description of each change that matters to
+// routing: keyspace settings, then vindexes, then tables, in name order.
+// Changes that reroute rows, like a new primary vindex, are flagged as
+// routing changes. A nil VSchema is empty. It returns nothing if the
+// VSchemas are equivalent.
+func DiffVSchemas(current *vschemapb.Keyspace, updated *vschemapb.Keyspace) []string {
+	if current == nil {
+		current = &vschemapb.Keyspace{}
+	}
+	if updated == nil {
+		updated = &vschemapb.Keyspace{}
+	}
+
+	var diffs []string
+
+	if current.Sharded != updated.Sharded {
+		diffs = append(diffs, fmt.Sprintf("keyspace changes from %s to %s (routing change)", shardedString(current.Sharded), shardedString(updated.Sharded)))
+	}
+	if current.RequireExplicitRouting != updated.RequireExplicitRouting {
+		diffs = append(diffs, fmt.Sprintf("require_explicit_routing changes from %t to %t (routing change)", current.RequireExplicitRouting, updated.RequireExplicitRouting))
+	}
+	if current.ForeignKeyMode != updated.ForeignKeyMode {
+		diffs = append(diffs, fmt.Sprintf("foreign_key_mode changes from %v to %v", current.ForeignKeyMode, updated.ForeignKeyMode))
+	}
+	if !proto.Equal(current.MultiTenantSpec, updated.MultiTenantSpec) {
+		diffs = append(diffs, "multi_tenant_spec changes (routing change)")
+	}
+
+	for _, name := range unionKeys(current.Vindexes, updated.Vindexes) {
+		currentVindex, inCurrent := current.Vindexes[name]
+		updatedVindex, inUpdated := updated.Vindexes[name]
+		switch {
+		case !inUpdated:
+			diffs = append(diffs, fmt.Sprintf("vindex %s (%s) is removed", name, currentVindex.Type))
+		case !inCurrent:
+			diffs = append(diffs, fmt.Sprintf("vindex %s (%s) is added", name, updatedVindex.Type))
+		default:
+			if changes := vindexChanges(currentVindex, updatedVindex); len(changes) > 0 {
+				diff := fmt.Sprintf("vindex %s changes: %s", name, strings.Join(changes, "; "))
+				if tables := tablesWithPrimaryVindex(updated, name); len(tables) > 0 {
+					diff += fmt.Sprintf(" (routing change for %s)", strings.Join(tables, ", "))
+				}
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+
+	for _, name := range unionKeys(current.Tables, updated.Tables) {
+		currentTable, inCurrent := current.Tables[name]
+		updatedTable, inUpdated := updated.Tables[name]
+		switch {
+		case !inUpdated:
+			diffs = append(diffs, fmt.Sprintf("table %s is removed", name))
+		case !inCurrent:
+			diffs = append(diffs, fmt.Sprintf("table %s is added", name))
+		default:
+			for _, change := range tableChanges(currentTable, updatedTable) {
+				diffs = append(diffs, fmt.Sprintf("table %s: %s", name, change))
+			}
+		}
+	}
+
+	return diffs
+}
+
+func shardedString(sharded bool) string {
+	if sharded {
+		return "sharded"
+	}
+	return "unsharded"
+}
+
+// vindexChanges describes the changes to the definition of a vindex.
+func vindexChanges(current *vschemapb.Vindex, updated *vschemapb.Vindex) []string {
+	var changes []string
+	if current.Type != updated.Type {
+		changes = append(changes, fmt.Sprintf("type %s -> %s", current.Type, updated.Type))
+	}
+	for _, param := range unionKeys(current.Params, updated.Params) {
+		currentValue, inCurrent := current.Params[param]
+		updatedValue, inUpdated := updated.Params[param]
+		switch {
+		case !inUpdated:
+			changes = append(changes, fmt.Sprintf("param %s=%s is removed", param, currentValue))
+		case !inCurrent:
+			changes = append(changes, fmt.Sprintf("param %s=%s is added", param, updatedValue))
+		case currentValue != updatedValue:
+			changes = append(changes, fmt.Sprintf("param %s %s -> %s", param, currentValue, updatedValue))
+		}
+	}
+	if current.Owner != updated.Owner {
+		changes = append(changes, fmt.Sprintf("owner %q -> %q", current.Owner, updated.Owner))
+	}
+	return changes
+}
+
+// tablesWithPrimaryVindex returns the tables of the VSchema whose primary
+// vindex is the named one, in order.
+func tablesWithPrimaryVindex(vschema *vschemapb.Keyspace, vindex string) []string {
+	var tables []string
+	for _, name := range slices.Sorted(maps.Keys(vschema.Tables)) {
+		if columnVindexes := vschema.Tables[name].ColumnVindexes; len(columnVindexes) > 0 && columnVindexes[0].Name == vindex {
+			tables = append(tables, name)
+		}
+	}
+	return tables
+}
+
+// tableChanges describes the changes to the definition of a table.
+func tableChanges(current *vschemapb.Table, updated *vschemapb.Table) []string {
+	var changes []string
+	if current.Type != updated.Type {
+		changes = append(changes, fmt.Sprintf("type changes from %q to %q (routing change)", current.Type, updated.Type))
+	}
+
+	var currentPrimary, updatedPrimary *vschemapb.ColumnVindex
+	if len(current.ColumnVindexes) > 0 {
+		currentPrimary = current.ColumnVindexes[0]
+	}
+	if len(updated.ColumnVindexes) > 0 {
+		updatedPrimary = updated.ColumnVindexes[0]
+	}
+	if !proto.Equal(currentPrimary, updatedPrimary) {
+		changes = append(changes, fmt.Sprintf("primary vindex changes from %s to %s (routing change)", columnVindexString(currentPrimary), columnVindexString(updatedPrimary)))
+	}
+	currentSecondaries := columnVindexStrings(current.ColumnVindexes)
+	updatedSecondaries := columnVindexStrings(updated.ColumnVindexes)
+	for _, columnVindex := range currentSecondaries {
+		if !slices.Contains(updatedSecondaries, columnVindex) {
+			changes = append(changes, fmt.Sprintf("secondary vindex %s is removed", columnVindex))
+		}
+	}
+	for _, columnVindex := range updatedSecondaries {
+		if !slices.Contains(currentSecondaries, columnVindex) {
+			changes = append(changes, fmt.Sprintf("secondary vindex %s is added", columnVindex))
+		}
+	}
+
+	if !proto.Equal(current.AutoIncrement, updated.AutoIncrement) {
+		changes = append(changes, fmt.Sprintf("auto_increment changes from %s to %s", autoIncrementString(current.AutoIncrement), autoIncrementString(updated.AutoIncrement)))
+	}
+	if current.Pinned != updated.Pinned {
+		changes = append(changes, fmt.Sprintf("pinned keyspace id changes from %q to %q (routing change)", current.Pinned, updated.Pinned))
+	}
+	if current.Source != updated.Source {
+		changes = append(changes, fmt.Sprintf("source changes from %q to %q (routing change)", current.Source, updated.Source))
+	}
+	if current.ColumnListAuthoritative != updated.ColumnListAuthoritative || !columnsEqual(current.Columns, updated.Columns) {
+		changes = append(changes, "columns change")
+	}
+	return changes
+}
+
+func columnVindexString(columnVindex *vschemapb.ColumnVindex) string {
+	if columnVindex == nil {
+		return "none"
+	}
+	columns := columnVindex.Columns
+	if columnVindex.Column != "" {
+		columns = []string{columnVindex.Column}
+	}
+	return fmt.Sprintf("%s(%s)", columnVindex.Name, strings.Join(columns, ", "))
+}
+
+// columnVindexStrings describes the secondary column vindexes, in order.
+func columnVindexStrings(columnVindexes []*vschemapb.ColumnVindex) []string {
+	var strs []string
+	for i, columnVindex := range columnVindexes {
+		if i > 0 {
+			strs = append(strs, columnVindexString(columnVindex))
+		}
+	}
+	sort.Strings(strs)
+	return strs
+}
+
+func autoIncrementString(autoIncrement *vschemapb.AutoIncrement) string {
+	if autoIncrement == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%s using %s", autoIncrement.Column, autoIncrement.Sequence)
+}
+
+func columnsEqual(current []*vschemapb.Column, updated []*vschemapb.Column) bool {
+	return slices.EqualFunc(current, updated, func(c1, c2 *vschemapb.Column) bool {
+		return proto.Equal(c1, c2)
+	})
+}
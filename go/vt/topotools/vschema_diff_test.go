@@ -0,0 +1,96 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func TestDiffVSchemas(t *testing.T) {
+	current := &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"hash":   {Type: "hash"},
+			"lookup": {Type: "consistent_lookup_unique", Params: map[string]string{"table": "lookup_t", "from": "id"}, Owner: "t1"},
+			"old":    {Type: "numeric"},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{
+					{Column: "id", Name: "hash"},
+					{Column: "email", Name: "lookup"},
+				},
+				AutoIncrement: &vschemapb.AutoIncrement{Column: "id", Sequence: "t1_seq"},
+			},
+			"t2": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}},
+			},
+			"t3": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "old"}},
+			},
+		},
+	}
+	updated := &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"hash":   {Type: "xxhash"},
+			"lookup": {Type: "consistent_lookup_unique", Params: map[string]string{"table": "lookup_t2", "to": "keyspace_id"}, Owner: "t1"},
+			"new":    {Type: "unicode_loose_md5"},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{
+					{Column: "id", Name: "hash"},
+				},
+			},
+			"t2": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{Columns: []string{"id", "name"}, Name: "new"}},
+				AutoIncrement:  &vschemapb.AutoIncrement{Column: "id", Sequence: "t2_seq"},
+			},
+			"t4": {},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"vindex hash changes: type hash -> xxhash (routing change for t1)",
+		"vindex lookup changes: param from=id is removed; param table lookup_t -> lookup_t2; param to=keyspace_id is added",
+		"vindex new (unicode_loose_md5) is added",
+		"vindex old (numeric) is removed",
+		"table t1: secondary vindex lookup(email) is removed",
+		"table t1: auto_increment changes from id using t1_seq to none",
+		"table t2: primary vindex changes from hash(id) to new(id, name) (routing change)",
+		"table t2: auto_increment changes from none to id using t2_seq",
+		"table t3 is removed",
+		"table t4 is added",
+	}, DiffVSchemas(current, updated))
+
+	assert.Equal(t, []string{
+		"keyspace changes from unsharded to sharded (routing change)",
+		"vindex hash (hash) is added",
+		"vindex lookup (consistent_lookup_unique) is added",
+		"vindex old (numeric) is added",
+		"table t1 is added",
+		"table t2 is added",
+		"table t3 is added",
+	}, DiffVSchemas(nil, current))
+
+	assert.Empty(t, DiffVSchemas(current, current.CloneVT()))
+}
@@ -424,7 +424,7 @@ func CreateKs(
 
 	// Rebuild the SrvKeyspace object, so we can support
 	// range-based sharding queries, and export the redirects.
-	if err := topotools.RebuildKeyspace(ctx, wr.Logger(), wr.TopoServer(), keyspace, nil, false); err != nil {
+	if err := topotools.RebuildKeyspace(ctx, wr.Logger(), wr.TopoServer(), keyspace, nil, false, false); err != nil {
 		return 0, fmt.Errorf("cannot rebuild %v: %v", keyspace, err)
 	}
 	return uid, nil
@@ -876,6 +876,14 @@ func (itmc *internalTabletManagerClient) ReloadSchema(ctx context.Context, table
 	return t.tm.ReloadSchema(ctx, waitPosition)
 }
 
+func (itmc *internalTabletManagerClient) ReloadTabletConfig(ctx context.Context, tablet *topodatapb.Tablet, category tabletmanagerdatapb.TabletConfigCategory) (string, error) {
+	t, ok := tabletMap[tablet.Alias.Uid]
+	if !ok {
+		return "", fmt.Errorf("tmclient: cannot find tablet %v", tablet.Alias.Uid)
+	}
+	return t.tm.ReloadTabletConfig(ctx, category)
+}
+
 func (itmc *internalTabletManagerClient) PreflightSchema(ctx context.Context, tablet *topodatapb.Tablet, changes []string) ([]*tabletmanagerdatapb.SchemaChangeResult, error) {
 	t, ok := tabletMap[tablet.Alias.Uid]
 	if !ok {
@@ -18,20 +18,23 @@ package vtctl
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/pflag"
 	"google.golang.org/grpc"
 
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl"
-	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/wrangler"
 
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
@@ -40,14 +43,20 @@ func init() {
 	addCommand("Shards", command{
 		name:   "ListBackups",
 		method: commandListBackups,
-		params: "<keyspace/shard>",
-		help:   "Lists all the backups for a shard.",
+		params: "[--detail] [--format=text|json] [--warn-older-than=<duration>] <keyspace/shard> | <keyspace>",
+		help:   "Lists all the backups for a shard, newest first. --detail reads the MANIFEST of every backup to also print its size, age, duration, engine and tablet. Given a keyspace, lists the backups of every shard of the keyspace, and warns about the shards whose most recent backup is older than --warn-older-than.",
 	})
 	addCommand("Shards", command{
 		name:   "BackupShard",
 		method: commandBackupShard,
-		params: "[--allow_primary=false] <keyspace/shard>",
-		help:   "Chooses a tablet and creates a backup for a shard.",
+		params: "[--allow_primary=false] [--preferred-tablet=<tablet alias>] [--progress-interval=30s] <keyspace/shard>",
+		help:   "Chooses a tablet and creates a backup for a shard. Replicas are ranked by type, RDONLY before REPLICA, then by replication lag, except that those more than 5 minutes behind come last, and the primary only with --allow_primary when no replica is eligible. --preferred-tablet pins the choice to an eligible tablet. The progress of the backup is reported every --progress-interval.",
+	})
+	addCommand("Keyspaces", command{
+		name:   "BackupKeyspace",
+		method: commandBackupKeyspace,
+		params: "[--max-concurrent-shards=1] [--stagger=<duration>] [--skip-recent=<duration>] [--allow_primary=false] [--progress-interval=30s] <keyspace>",
+		help:   "Backs up every shard of a keyspace, choosing the tablet of every shard as BackupShard does. At most --max-concurrent-shards shards are backed up at the same time, and backups start at least --stagger apart. --skip-recent skips the shards with a complete backup more recent than that, to resume an interrupted run. Prints a report of every shard once all backups are done.",
 	})
 	addCommand("Shards", command{
 		name:   "RemoveBackup",
@@ -55,17 +64,41 @@ func init() {
 		params: "<keyspace/shard> <backup name>",
 		help:   "Removes a backup for the BackupStorage.",
 	})
+	addCommand("Shards", command{
+		name:   "RemoveBackups",
+		method: commandRemoveBackups,
+		params: "[--keep-last=<N>] [--older-than=<duration>] [--dry-run] [--include-incomplete] <keyspace/shard>",
+		help:   "Removes the backups of a shard beyond a retention policy: those older than --older-than and not among the --keep-last most recent complete backups. The most recent complete backup is never removed. Incomplete or in-progress backups are skipped unless --include-incomplete. --dry-run only prints the backups that would be removed.",
+	})
+	addCommand("Shards", command{
+		name:   "ValidateBackup",
+		method: commandValidateBackup,
+		params: "[--backup-name=<name>] <keyspace/shard> | <keyspace>",
+		help:   "Validates the most recent backup of a shard, or the one named by --backup-name: reads its MANIFEST and checks that every file it references is in backup storage, with the size and the checksum recorded by the backup engine, and prints the result. Given a keyspace, validates the most recent backup of every shard of the keyspace, and fails if any shard has no valid backup.",
+	})
+	addCommand("Shards", command{
+		name:   "RestoreShardToPointInTime",
+		method: commandRestoreShardToPointInTime,
+		params: "[--restore-to-timestamp=<RFC3339 time> | --restore-to-pos=<pos>] [--force] <keyspace/shard> <tablet alias>",
+		help:   "Restores a tablet of a shard to a point in time: picks the most recent full backup taken before that point, and restores it on the tablet followed by the incremental backups up to that point. The tablet is left DRAINED with replication disabled, and the position it reached is printed, so the rest of the shard can be reparented to it or cloned from it. Refuses to run on a serving shard unless --force.",
+	})
+	addCommand("Tablets", command{
+		name:   "RestoreDrill",
+		method: commandRestoreDrill,
+		params: "[--backup-name=<name>] [--max-replication-lag=30s] [--catch-up-timeout=30m] [--validation-query=<query>]... <tablet alias>",
+		help:   "Tests the backups of a shard on a SPARE or DRAINED tablet: restores the latest full backup, or the one named by --backup-name, on the tablet, waits up to --catch-up-timeout for its replication lag to get down to --max-replication-lag, and runs each --validation-query on its database as the dba user, failing if a query errors or returns no rows. The tablet is then returned to its original type, with replication stopped if it was. Prints a report with the timings of every step, and returns an error if the drill failed. Refuses to run on a serving tablet or on the shard primary.",
+	})
 	addCommand("Tablets", command{
 		name:   "Backup",
 		method: commandBackup,
-		params: "[--concurrency=4] [--allow_primary=false] [--incremental_from_pos=<pos>] <tablet alias>",
+		params: "[--concurrency=4] [--allow_primary=false] [--incremental_from_pos=<pos>] [--progress-interval=30s] <tablet alias>",
 		help:   "Run a full or an incremental backup. Uses the BackupStorage service to store a new backup. With full backup, stops mysqld, takes the backup, starts mysqld and resumes replication. With incremental backup (indicated by '--incremental_from_pos', rotate and copy binary logs without disrupting the mysqld service).",
 	})
 	addCommand("Tablets", command{
 		name:   "RestoreFromBackup",
 		method: commandRestoreFromBackup,
-		params: "[--backup_timestamp=yyyy-MM-dd.HHmmss] [--restore_to_pos=<pos>] [--dry_run] <tablet alias>",
-		help:   "Stops mysqld and restores the data from the latest backup or if a timestamp is specified then the most recent backup at or before that time. If '--restore_to_pos' is given, then a point in time restore based on one full backup followed by zero or more incremental backups. dry-run only validates restore steps without actually restoring data",
+		params: "[--backup-timestamp=yyyy-MM-dd.HHmmss | --backup-name=<name>] [--restore_to_pos=<pos>] [--dry_run] <tablet alias>",
+		help:   "Stops mysqld and restores the data from the latest backup, or if a timestamp is specified then the most recent backup at or before that time, or if a name is specified then that backup. A backup given by timestamp or name is checked in backup storage before the restore starts, and its manifest is printed. If '--restore_to_pos' is given, then a point in time restore based on one full backup followed by zero or more incremental backups. dry-run only validates restore steps without actually restoring data",
 	})
 }
 
@@ -75,6 +108,8 @@ func commandBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.F
 	incrementalFromPos := subFlags.String("incremental_from_pos", "", "Position, or name of backup from which to create an incremental backup. Default: empty. If given, then this backup becomes an incremental backup from given position or given backup. If value is 'auto', this backup will be taken from the last successful backup position.")
 	upgradeSafe := subFlags.Bool("upgrade-safe", false, "Whether to use innodb_fast_shutdown=0 for the backup so it is safe to use for MySQL upgrades.")
 	mysqlShutdownTimeout := subFlags.Duration("mysql-shutdown-timeout", mysqlctl.DefaultShutdownTimeout, "Timeout to use when MySQL is being shut down.")
+	progressInterval := subFlags.Duration("progress-interval", 30*time.Second, "How often to report the progress of the backup. 0 reports every progress update from the tablet.")
+	tablets := newTabletResolver(wr, subFlags)
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -83,7 +118,7 @@ func commandBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.F
 		return fmt.Errorf("the Backup command requires the <tablet alias> argument")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -95,21 +130,21 @@ func commandBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.F
 		IncrementalFromPos:   *incrementalFromPos,
 		UpgradeSafe:          *upgradeSafe,
 		MysqlShutdownTimeout: protoutil.DurationToProto(*mysqlShutdownTimeout),
-	}, &backupEventStreamLogger{logger: wr.Logger(), ctx: ctx})
+	}, &backupEventStreamLogger{forwarder: wr.NewBackupEventForwarder(*progressInterval), ctx: ctx})
 }
 
 // backupEventStreamLogger takes backup events from the vtctldserver and emits
-// them via logutil.LogEvent, preserving legacy behavior.
+// them through a wrangler.BackupEventForwarder.
 type backupEventStreamLogger struct {
 	grpc.ServerStream
-	logger logutil.Logger
-	ctx    context.Context
+	forwarder *wrangler.BackupEventForwarder
+	ctx       context.Context
 }
 
 func (b *backupEventStreamLogger) Context() context.Context { return b.ctx }
 
 func (b *backupEventStreamLogger) Send(resp *vtctldatapb.BackupResponse) error {
-	logutil.LogEvent(b.logger, resp.Event)
+	b.forwarder.Forward(resp.Event)
 	return nil
 }
 
@@ -119,6 +154,9 @@ func commandBackupShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 	incrementalFromPos := subFlags.String("incremental_from_pos", "", "Position, or name of backup from which to create an incremental backup. Default: empty. If given, then this backup becomes an incremental backup from given position or given backup. If value is 'auto', this backup will be taken from the last successful backup position.")
 	upgradeSafe := subFlags.Bool("upgrade-safe", false, "Whether to use innodb_fast_shutdown=0 for the backup so it is safe to use for MySQL upgrades.")
 	mysqlShutdownTimeout := subFlags.Duration("mysql-shutdown-timeout", mysqlctl.DefaultShutdownTimeout, "Timeout to use when MySQL is being shut down.")
+	progressInterval := subFlags.Duration("progress-interval", 30*time.Second, "How often to report the progress of the backup. 0 reports every progress update from the tablet.")
+	preferredTablet := subFlags.String("preferred-tablet", "", "Alias of the tablet to take the backup from, as long as it is eligible.")
+	tablets := newTabletResolver(wr, subFlags)
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -131,47 +169,180 @@ func commandBackupShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 	if err != nil {
 		return err
 	}
+	var preferredAlias *topodatapb.TabletAlias
+	if *preferredTablet != "" {
+		preferredAlias, err = tablets.resolve(ctx, *preferredTablet)
+		if err != nil {
+			return err
+		}
+	}
 
-	return wr.VtctldServer().BackupShard(&vtctldatapb.BackupShardRequest{
-		Keyspace:             keyspace,
-		Shard:                shard,
+	tablet, err := wr.SelectBackupTablet(ctx, keyspace, shard, *allowPrimary, preferredAlias)
+	if err != nil {
+		return err
+	}
+	return wr.VtctldServer().Backup(&vtctldatapb.BackupRequest{
+		TabletAlias:          tablet.Alias,
 		Concurrency:          *concurrency,
 		AllowPrimary:         *allowPrimary,
 		IncrementalFromPos:   *incrementalFromPos,
 		UpgradeSafe:          *upgradeSafe,
 		MysqlShutdownTimeout: protoutil.DurationToProto(*mysqlShutdownTimeout),
-	}, &backupEventStreamLogger{logger: wr.Logger(), ctx: ctx})
+	}, &backupEventStreamLogger{forwarder: wr.NewBackupEventForwarder(*progressInterval), ctx: ctx})
 }
 
-func commandListBackups(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+func commandBackupKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	concurrency := subFlags.Int32("concurrency", 4, "Specifies the number of compression/checksum jobs to run simultaneously")
+	allowPrimary := subFlags.Bool("allow_primary", false, "Whether to use the primary tablet of shards without an eligible replica. Warning!! If you are using the builtin backup engine, this will shutdown your primary mysql for as long as it takes to create a backup.")
+	upgradeSafe := subFlags.Bool("upgrade-safe", false, "Whether to use innodb_fast_shutdown=0 for the backups so they are safe to use for MySQL upgrades.")
+	mysqlShutdownTimeout := subFlags.Duration("mysql-shutdown-timeout", mysqlctl.DefaultShutdownTimeout, "Timeout to use when MySQL is being shut down.")
+	progressInterval := subFlags.Duration("progress-interval", 30*time.Second, "How often to report the progress of every backup. 0 reports every progress update from the tablets.")
+	maxConcurrentShards := subFlags.Int("max-concurrent-shards", 1, "Maximum number of shards backed up at the same time.")
+	stagger := subFlags.Duration("stagger", 0, "Minimum delay between the starts of two shard backups.")
+	skipRecent := subFlags.Duration("skip-recent", 0, "Skip the shards that have a complete backup more recent than this.")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
 	if subFlags.NArg() != 1 {
-		return fmt.Errorf("action ListBackups requires <keyspace/shard>")
+		return fmt.Errorf("action BackupKeyspace requires <keyspace>")
+	}
+	if *maxConcurrentShards <= 0 {
+		return fmt.Errorf("--max-concurrent-shards must be positive")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace := subFlags.Arg(0)
+	reports, err := wr.BackupKeyspace(ctx, keyspace, wrangler.BackupKeyspaceOptions{
+		MaxConcurrentShards:  *maxConcurrentShards,
+		Stagger:              *stagger,
+		SkipRecent:           *skipRecent,
+		ProgressInterval:     *progressInterval,
+		AllowPrimary:         *allowPrimary,
+		Concurrency:          *concurrency,
+		UpgradeSafe:          *upgradeSafe,
+		MysqlShutdownTimeout: *mysqlShutdownTimeout,
+	})
 	if err != nil {
 		return err
 	}
-	bucket := fmt.Sprintf("%v/%v", keyspace, shard)
 
-	bs, err := backupstorage.GetBackupStorage()
+	var failed, canceled []string
+	for _, report := range reports {
+		line := fmt.Sprintf("%v/%v: %v", keyspace, report.Shard, report.Status)
+		if report.BackupName != "" {
+			line += fmt.Sprintf(", backup %v", report.BackupName)
+		}
+		if report.Tablet != "" {
+			line += fmt.Sprintf(" on %v", report.Tablet)
+		}
+		if report.Duration > 0 {
+			line += fmt.Sprintf(" in %v", report.Duration.Round(time.Second))
+		}
+		if report.Reason != "" {
+			line += fmt.Sprintf(": %v", report.Reason)
+		}
+		wr.Logger().Printf("%v\n", line)
+		if report.Status == wrangler.ShardBackupFailed {
+			failed = append(failed, report.Shard)
+		}
+		if report.Status == wrangler.ShardBackupCanceled {
+			canceled = append(canceled, report.Shard)
+		}
+	}
+	if len(canceled) > 0 {
+		return fmt.Errorf("BackupKeyspace was canceled before the backup of %v of %v shards of keyspace %v: %v", len(canceled), len(reports), keyspace, strings.Join(canceled, ", "))
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("the backup of %v of %v shards of keyspace %v failed: %v", len(failed), len(reports), keyspace, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func commandListBackups(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	detail := subFlags.Bool("detail", false, "Also print the size, the start and end times, the engine and the tablet of every backup, from its MANIFEST.")
+	format := subFlags.String("format", "text", "Format of the output") // "json" or "text"
+	warnOlderThan := subFlags.Duration("warn-older-than", 0, "With a keyspace, warn about the shards whose most recent backup is older than this.")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("action ListBackups requires <keyspace/shard> or <keyspace>")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid --format %q, must be one of text, json", *format)
+	}
+
+	if !strings.Contains(subFlags.Arg(0), "/") {
+		keyspace := subFlags.Arg(0)
+		results, err := wr.ListKeyspaceBackups(ctx, keyspace, *detail, *warnOlderThan)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			if result.Warning != "" {
+				wr.Logger().Warningf("%v/%v: %v", keyspace, result.Shard, result.Warning)
+			}
+		}
+		if *format == "json" {
+			return printJSON(wr.Logger(), results)
+		}
+		for _, result := range results {
+			wr.Logger().Printf("%v/%v:\n", keyspace, result.Shard)
+			printBackups(wr.Logger(), result.Backups, *detail, "  ")
+		}
+		return nil
+	}
+
+	if *warnOlderThan != 0 {
+		return fmt.Errorf("--warn-older-than requires <keyspace>")
+	}
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
-	defer bs.Close()
-	bhs, err := bs.ListBackups(ctx, bucket)
+	backups, err := wr.ListBackups(ctx, keyspace, shard, *detail)
 	if err != nil {
 		return err
 	}
-	for _, bh := range bhs {
-		wr.Logger().Printf("%v\n", bh.Name())
+	if *format == "json" {
+		return printJSON(wr.Logger(), backups)
 	}
+	printBackups(wr.Logger(), backups, *detail, "")
 	return nil
 }
 
+// printBackups prints one backup per line, with its details if detail is
+// set, each line starting with indent.
+func printBackups(logger logutil.Logger, backups []*wrangler.BackupInfo, detail bool, indent string) {
+	now := time.Now()
+	for _, backup := range backups {
+		if !detail {
+			logger.Printf("%v%v\n", indent, backup.Name)
+			continue
+		}
+		if backup.Incomplete {
+			logger.Printf("%v%v incomplete\n", indent, backup.Name)
+			continue
+		}
+		size := "unknown size"
+		if backup.Size > 0 {
+			size = humanize.IBytes(uint64(backup.Size))
+		}
+		age := "unknown age"
+		if !backup.BackupTime.IsZero() {
+			age = fmt.Sprintf("%v old", now.Sub(backup.BackupTime).Round(time.Second))
+		}
+		duration := "unknown duration"
+		if !backup.BackupTime.IsZero() && !backup.FinishedTime.IsZero() {
+			duration = fmt.Sprintf("took %v", backup.FinishedTime.Sub(backup.BackupTime).Round(time.Second))
+		}
+		kind := "full"
+		if backup.Incremental {
+			kind = "incremental"
+		}
+		logger.Printf("%v%v %v %v backup, %v, %v, %v, from %v\n", indent, backup.Name, kind, backup.Engine, size, age, duration, backup.TabletAlias)
+	}
+}
+
 func commandRemoveBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -194,6 +365,176 @@ func commandRemoveBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 	return err
 }
 
+func commandRemoveBackups(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	keepLast := subFlags.Int("keep-last", 0, "Number of most recent complete backups to keep.")
+	olderThan := subFlags.Duration("older-than", 0, "Only remove backups older than this.")
+	dryRun := subFlags.Bool("dry-run", false, "Print the backups that would be removed, without removing them.")
+	includeIncomplete := subFlags.Bool("include-incomplete", false, "Also remove incomplete backups, which otherwise are skipped as they may be in progress.")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("action RemoveBackups requires <keyspace/shard>")
+	}
+	if *keepLast < 0 || *olderThan < 0 {
+		return fmt.Errorf("--keep-last and --older-than cannot be negative")
+	}
+	if *keepLast == 0 && *olderThan == 0 {
+		return fmt.Errorf("action RemoveBackups requires --keep-last or --older-than")
+	}
+
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	removed, err := wr.RemoveBackups(ctx, keyspace, shard, wrangler.BackupRetentionPolicy{
+		KeepLast:  *keepLast,
+		OlderThan: *olderThan,
+	}, *includeIncomplete, *dryRun)
+	if err != nil {
+		return err
+	}
+	if len(removed) == 0 {
+		wr.Logger().Printf("No backups to remove in %v/%v\n", keyspace, shard)
+	}
+	return nil
+}
+
+func commandValidateBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	backupName := subFlags.String("backup-name", "", "Name of the backup to validate, rather than the most recent one.")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("action ValidateBackup requires <keyspace/shard> or <keyspace>")
+	}
+
+	if !strings.Contains(subFlags.Arg(0), "/") {
+		if *backupName != "" {
+			return fmt.Errorf("--backup-name requires <keyspace/shard>")
+		}
+		keyspace := subFlags.Arg(0)
+		results, err := wr.ValidateKeyspaceBackups(ctx, keyspace)
+		if err != nil {
+			return err
+		}
+		var invalid []string
+		for _, result := range results {
+			switch {
+			case result.Error != "":
+				wr.Logger().Printf("%v/%v: no valid backup: %v\n", keyspace, result.Shard, result.Error)
+			case !result.Valid():
+				wr.Logger().Printf("%v/%v: backup %v is invalid: %v\n", keyspace, result.Shard, result.Validation.BackupName, strings.Join(result.Validation.Errors, "; "))
+			default:
+				wr.Logger().Printf("%v/%v: backup %v is valid, %v engine at position %v\n", keyspace, result.Shard, result.Validation.BackupName, result.Validation.Engine, result.Validation.Position)
+			}
+			if !result.Valid() {
+				invalid = append(invalid, result.Shard)
+			}
+		}
+		if len(invalid) > 0 {
+			return fmt.Errorf("shards of keyspace %v without a valid backup: %v", keyspace, strings.Join(invalid, ", "))
+		}
+		return nil
+	}
+
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	validation, err := wr.ValidateBackup(ctx, keyspace, shard, *backupName)
+	if err != nil {
+		return err
+	}
+	if err := printJSON(wr.Logger(), validation); err != nil {
+		return err
+	}
+	if !validation.Valid() {
+		return fmt.Errorf("backup %v of %v/%v is invalid: %v", validation.BackupName, keyspace, shard, strings.Join(validation.Errors, "; "))
+	}
+	return nil
+}
+
+func commandRestoreShardToPointInTime(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	restoreToTimestampStr := subFlags.String("restore-to-timestamp", "", "Restore up to, and excluding, this timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`).")
+	restoreToPos := subFlags.String("restore-to-pos", "", "Restore up to, and including, this position.")
+	force := subFlags.Bool("force", false, "Restore even if the shard is serving.")
+	tablets := newTabletResolver(wr, subFlags)
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 2 {
+		return fmt.Errorf("action RestoreShardToPointInTime requires <keyspace/shard> <tablet alias>")
+	}
+	if (*restoreToTimestampStr == "") == (*restoreToPos == "") {
+		return fmt.Errorf("action RestoreShardToPointInTime requires exactly one of --restore-to-timestamp or --restore-to-pos")
+	}
+
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(1))
+	if err != nil {
+		return err
+	}
+	var restoreToTimestamp time.Time
+	if *restoreToTimestampStr != "" {
+		restoreToTimestamp, err = mysqlctl.ParseRFC3339(*restoreToTimestampStr)
+		if err != nil {
+			return vterrors.Wrapf(err, "parsing --restore-to-timestamp")
+		}
+	}
+
+	result, err := wr.RestoreShardToPointInTime(ctx, keyspace, shard, tabletAlias, restoreToTimestamp, *restoreToPos, *force)
+	if err != nil {
+		return err
+	}
+	return printJSON(wr.Logger(), result)
+}
+
+func commandRestoreDrill(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	backupName := subFlags.String("backup-name", "", "Name of the full backup to restore, as listed by ListBackups, rather than the latest backup.")
+	maxReplicationLag := subFlags.Duration("max-replication-lag", 30*time.Second, "Replication lag the tablet must get down to after the restore.")
+	catchUpTimeout := subFlags.Duration("catch-up-timeout", 30*time.Minute, "How long the tablet has to get down to --max-replication-lag after the restore.")
+	validationQueries := subFlags.StringArray("validation-query", nil, "Query to run on the restored tablet, which must succeed and return rows. Can be repeated.")
+	tablets := newTabletResolver(wr, subFlags)
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the RestoreDrill command requires the <tablet alias> argument")
+	}
+
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	report, err := wr.RestoreDrill(ctx, tabletAlias, wrangler.RestoreDrillOptions{
+		BackupName:        *backupName,
+		MaxReplicationLag: *maxReplicationLag,
+		CatchUpTimeout:    *catchUpTimeout,
+		ValidationQueries: *validationQueries,
+	})
+	if err != nil {
+		return err
+	}
+	if err := printJSON(wr.Logger(), report); err != nil {
+		return err
+	}
+	var errs []string
+	if !report.Passed {
+		errs = append(errs, fmt.Sprintf("restore drill of backup %v on %v failed: %v", report.BackupName, report.Tablet, report.Failure))
+	}
+	if report.RevertError != "" {
+		errs = append(errs, fmt.Sprintf("%v was not returned to type %v: %v", report.Tablet, report.OriginalType, report.RevertError))
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
 // backupRestoreEventStreamLogger takes backup restore events from the
 // vtctldserver and emits them via logutil.LogEvent, preserving legacy behavior.
 type backupRestoreEventStreamLogger struct {
@@ -210,11 +551,14 @@ func (b *backupRestoreEventStreamLogger) Send(resp *vtctldatapb.RestoreFromBacku
 }
 
 func commandRestoreFromBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
-	backupTimestampStr := subFlags.String("backup_timestamp", "", "Use the backup taken at or before this timestamp rather than using the latest backup.")
+	backupTimestampStr := subFlags.String("backup-timestamp", "", "Use the backup taken at or before this timestamp (yyyy-MM-dd.HHmmss) rather than using the latest backup.")
+	legacyBackupTimestampStr := subFlags.String("backup_timestamp", "", "Use the backup taken at or before this timestamp rather than using the latest backup.")
+	backupName := subFlags.String("backup-name", "", "Name of the full backup to restore, as listed by ListBackups, rather than using the latest backup.")
 	restoreToPos := subFlags.String("restore_to_pos", "", "Run a point in time recovery that ends with the given position. This will attempt to use one full backup followed by zero or more incremental backups")
 	restoreToTimestampStr := subFlags.String("restore_to_timestamp", "", "Run a point in time recovery that restores up to, and excluding, given timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`). This will attempt to use one full backup followed by zero or more incremental backups")
 
 	dryRun := subFlags.Bool("dry_run", false, "Only validate restore steps, do not actually restore data")
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -222,6 +566,16 @@ func commandRestoreFromBackup(ctx context.Context, wr *wrangler.Wrangler, subFla
 		return fmt.Errorf("the RestoreFromBackup command requires the <tablet alias> argument")
 	}
 
+	if *backupTimestampStr == "" {
+		backupTimestampStr = legacyBackupTimestampStr
+	}
+	if *backupName != "" && *backupTimestampStr != "" {
+		return fmt.Errorf("--backup-name and --backup-timestamp are mutually exclusive")
+	}
+	if *backupName != "" && (*restoreToPos != "" || *restoreToTimestampStr != "") {
+		return fmt.Errorf("--backup-name cannot be used with --restore_to_pos or --restore_to_timestamp")
+	}
+
 	// Zero date will cause us to use the latest, which is the default
 	backupTime := time.Time{}
 
@@ -234,7 +588,7 @@ func commandRestoreFromBackup(ctx context.Context, wr *wrangler.Wrangler, subFla
 		}
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -257,5 +611,20 @@ func commandRestoreFromBackup(ctx context.Context, wr *wrangler.Wrangler, subFla
 		req.BackupTime = protoutil.TimeToProto(backupTime)
 	}
 
+	// When restoring a given backup, make sure it exists before stopping the
+	// tablet, and pin the restore to it.
+	if *backupName != "" || (!backupTime.IsZero() && *restoreToPos == "" && restoreToTimestamp.IsZero()) {
+		name, manifest, err := wr.FindBackupToRestore(ctx, tabletAlias, *backupName, backupTime)
+		if err != nil {
+			return err
+		}
+		wr.Logger().Printf("Restoring backup %v:\n", name)
+		if err := printJSON(wr.Logger(), manifest); err != nil {
+			return err
+		}
+		req.BackupName = name
+		req.BackupTime = nil
+	}
+
 	return wr.VtctldServer().RestoreFromBackup(req, &backupRestoreEventStreamLogger{logger: wr.Logger(), ctx: ctx})
 }
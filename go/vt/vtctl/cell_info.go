@@ -20,9 +20,11 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 
+	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/wrangler"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -53,8 +55,15 @@ func init() {
 	addCommand(cellsGroupName, command{
 		name:   "DeleteCellInfo",
 		method: commandDeleteCellInfo,
-		params: "[--force] <cell>",
-		help:   "Deletes the CellInfo for the provided cell. The cell cannot be referenced by any Shard record.",
+		params: "[--force] [--purge_references] <cell>",
+		help:   "Deletes the CellInfo for the provided cell. The cell cannot be referenced by any tablet, shard replication graph, shard primary or SrvKeyspace, unless --force is passed, nor by any shard tablet control, cells alias or SrvVSchema, unless --purge_references or --force is passed. With --purge_references, the cell is removed from the tablet controls of the shards and from the cells aliases, and its SrvVSchema is deleted, first.",
+	})
+
+	addCommand(cellsGroupName, command{
+		name:   "DrainCell",
+		method: commandDrainCell,
+		params: "[--execute] [--yes] [--wait_replicas_timeout=30s] [--health_timeout=5s] <cell>",
+		help:   "Decommissions a cell in phases: reparents the shards whose primary is in the cell to a tablet in another cell, changes the type of the tablets in the cell to DRAINED, verifies that none of them is serving, then deletes the tablet records, replication graphs and SrvKeyspaces of the cell. Without --execute, only prints what is left to do. With --execute, runs the next phase and stops, so that the one after can be reviewed and confirmed by running the command again, unless --yes is passed to run all the remaining phases. Running it again skips what is already done. The CellInfo is left for DeleteCellInfo.",
 	})
 
 	addCommand(cellsGroupName, command{
@@ -115,7 +124,8 @@ func commandUpdateCellInfo(ctx context.Context, wr *wrangler.Wrangler, subFlags
 }
 
 func commandDeleteCellInfo(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
-	force := subFlags.Bool("force", false, "Proceeds even if the cell's topology server cannot be reached. The assumption is that you turned down the entire cell, and just need to update the global topo data.")
+	force := subFlags.Bool("force", false, "Proceeds even if the cell's topology server cannot be reached. The assumption is that you turned down the entire cell, and just need to update the global topo data, or if the cell is still referenced.")
+	purgeReferences := subFlags.Bool("purge_references", false, "Removes the cell from the tablet controls of the shards and from the cells aliases, and deletes its SrvVSchema, before deleting it.")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -124,10 +134,56 @@ func commandDeleteCellInfo(ctx context.Context, wr *wrangler.Wrangler, subFlags
 	}
 	cell := subFlags.Arg(0)
 
-	_, err := wr.VtctldServer().DeleteCellInfo(ctx, &vtctldatapb.DeleteCellInfoRequest{
-		Name:  cell,
-		Force: *force,
+	resp, err := wr.VtctldServer().DeleteCellInfo(ctx, &vtctldatapb.DeleteCellInfoRequest{
+		Name:            cell,
+		Force:           *force,
+		PurgeReferences: *purgeReferences,
+	})
+	if err != nil {
+		return err
+	}
+	for _, purged := range resp.PurgedReferences {
+		wr.Logger().Printf("%v\n", purged)
+	}
+	return nil
+}
+
+func commandDrainCell(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	execute := subFlags.Bool("execute", false, "Run the next phase, instead of only printing what is left to do.")
+	yes := subFlags.Bool("yes", false, "With --execute, run all the remaining phases instead of stopping after the next one.")
+	waitReplicasTimeout := subFlags.Duration("wait_replicas_timeout", topo.RemoteOperationTimeout, "Time to wait for replicas to catch up when reparenting the primaries out of the cell.")
+	healthTimeout := subFlags.Duration("health_timeout", 5*time.Second, "Time to wait for the health record of each tablet when verifying that none is serving.")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <cell> argument is required for the DrainCell command")
+	}
+	if *yes && !*execute {
+		return fmt.Errorf("--yes requires --execute")
+	}
+	cell := subFlags.Arg(0)
+
+	report, err := wr.DrainCell(ctx, cell, wrangler.DrainCellOptions{
+		Execute:             *execute,
+		AllPhases:           *yes,
+		WaitReplicasTimeout: *waitReplicasTimeout,
+		HealthTimeout:       *healthTimeout,
 	})
+	if report != nil {
+		if perr := printJSON(wr.Logger(), report); perr != nil {
+			return perr
+		}
+		switch {
+		case err != nil:
+		case report.NextPhase == "":
+			wr.Logger().Printf("Cell %v is drained and cleaned up, its CellInfo can be deleted with DeleteCellInfo --purge_references, which also deletes its SrvVSchema and removes it from the shard tablet controls and cells aliases.\n", cell)
+		case *execute:
+			wr.Logger().Printf("Paused before phase %v, run DrainCell --execute again to run it.\n", report.NextPhase)
+		default:
+			wr.Logger().Printf("Next phase is %v, run DrainCell --execute to run it.\n", report.NextPhase)
+		}
+	}
 	return err
 }
 
@@ -23,6 +23,8 @@ import (
 
 	"github.com/spf13/pflag"
 
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/wrangler"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -39,15 +41,15 @@ func init() {
 	addCommand(cellsAliasesGroupName, command{
 		name:   "AddCellsAlias",
 		method: commandAddCellsAlias,
-		params: "[--cells <cell,cell2...>] <alias>",
-		help:   "Defines a group of cells within which replica/rdonly traffic can be routed across cells. Between cells that are not in the same group (alias), only primary traffic can be routed.",
+		params: "[--cells <cell,cell2...>] [--force] [--dry_run] <alias>",
+		help:   "Defines a group of cells within which replica/rdonly traffic can be routed across cells. Between cells that are not in the same group (alias), only primary traffic can be routed. Every cell must have a CellInfo, and cannot be in another alias unless --force is passed. Prints the keyspaces and tablet types whose routing changes. With --dry_run, the alias is not created.",
 	})
 
 	addCommand(cellsAliasesGroupName, command{
 		name:   "UpdateCellsAlias",
 		method: commandUpdateCellsAlias,
-		params: "[--cells <cell,cell2,...>] <alias>",
-		help:   "Updates the content of a CellsAlias with the provided parameters. If a value is empty, it is not updated. The CellsAlias will be created if it doesn't exist.",
+		params: "[--cells <cell,cell2,...>] [--force] [--dry_run] <alias>",
+		help:   "Updates the content of a CellsAlias with the provided parameters. If a value is empty, it is not updated. The CellsAlias will be created if it doesn't exist. Every cell must have a CellInfo, and cannot be in another alias unless --force is passed. Prints the added and removed cells, and the keyspaces and tablet types whose routing changes. With --dry_run, the alias is not updated.",
 	})
 
 	addCommand(cellsAliasesGroupName, command{
@@ -67,6 +69,8 @@ func init() {
 
 func commandAddCellsAlias(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	cells := subFlags.StringSlice("cells", nil, "The list of cell names that are members of this alias.")
+	force := subFlags.Bool("force", false, "Allows the alias to have cells that are in other aliases.")
+	dryRun := subFlags.Bool("dry_run", false, "Prints the routing changes without creating the alias.")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -79,15 +83,23 @@ func commandAddCellsAlias(ctx context.Context, wr *wrangler.Wrangler, subFlags *
 	}
 
 	alias := subFlags.Arg(0)
-	_, err := wr.VtctldServer().AddCellsAlias(ctx, &vtctldatapb.AddCellsAliasRequest{
-		Name:  alias,
-		Cells: *cells,
+	resp, err := wr.VtctldServer().AddCellsAlias(ctx, &vtctldatapb.AddCellsAliasRequest{
+		Name:   alias,
+		Cells:  *cells,
+		Force:  *force,
+		DryRun: *dryRun,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	printCellsAliasRoutingChanges(wr.Logger(), resp.RoutingChanges)
+	return nil
 }
 
 func commandUpdateCellsAlias(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	cells := subFlags.StringSlice("cells", nil, "The list of cell names that are members of this alias.")
+	force := subFlags.Bool("force", false, "Allows the alias to have cells that are in other aliases.")
+	dryRun := subFlags.Bool("dry_run", false, "Prints the changes without updating the alias.")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -100,13 +112,37 @@ func commandUpdateCellsAlias(ctx context.Context, wr *wrangler.Wrangler, subFlag
 	}
 
 	alias := subFlags.Arg(0)
-	_, err := wr.VtctldServer().UpdateCellsAlias(ctx, &vtctldatapb.UpdateCellsAliasRequest{
+	resp, err := wr.VtctldServer().UpdateCellsAlias(ctx, &vtctldatapb.UpdateCellsAliasRequest{
 		Name: alias,
 		CellsAlias: &topodatapb.CellsAlias{
 			Cells: *cells,
 		},
+		Force:  *force,
+		DryRun: *dryRun,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	if len(resp.AddedCells) > 0 {
+		wr.Logger().Printf("Added cells: %v\n", strings.Join(resp.AddedCells, ", "))
+	}
+	if len(resp.RemovedCells) > 0 {
+		wr.Logger().Printf("Removed cells: %v\n", strings.Join(resp.RemovedCells, ", "))
+	}
+	printCellsAliasRoutingChanges(wr.Logger(), resp.RoutingChanges)
+	return nil
+}
+
+// printCellsAliasRoutingChanges prints the keyspaces and tablet types whose
+// routing changes with a cells alias.
+func printCellsAliasRoutingChanges(logger logutil.Logger, changes []*vtctldatapb.CellsAliasRoutingChange) {
+	if len(changes) == 0 {
+		return
+	}
+	logger.Printf("Routing changes between the cells of the alias:\n")
+	for _, change := range changes {
+		logger.Printf("  %v %v (served in %v)\n", change.Keyspace, topoproto.TabletTypeLString(change.TabletType), strings.Join(change.Cells, ", "))
+	}
 }
 
 func commandDeleteCellsAlias(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
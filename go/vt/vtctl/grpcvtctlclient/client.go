@@ -27,6 +27,7 @@ import (
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/vtctl/grpcclientcommon"
 	"vitess.io/vitess/go/vt/vtctl/vtctlclient"
+	"vitess.io/vitess/go/vt/vterrors"
 
 	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
@@ -60,10 +61,12 @@ type eventStreamAdapter struct {
 	stream vtctlservicepb.Vtctl_ExecuteVtctlCommandClient
 }
 
+// Recv returns the next event of the command, or its error with the vtrpc
+// code of the gRPC status.
 func (e *eventStreamAdapter) Recv() (*logutilpb.Event, error) {
 	le, err := e.stream.Recv()
 	if err != nil {
-		return nil, err
+		return nil, vterrors.FromGRPC(err)
 	}
 	return le.Event, nil
 }
@@ -73,6 +76,7 @@ func (client *gRPCVtctlClient) ExecuteVtctlCommand(ctx context.Context, args []s
 	query := &vtctldatapb.ExecuteVtctlCommandRequest{
 		Args:          args,
 		ActionTimeout: int64(actionTimeout.Nanoseconds()),
+		LogLevel:      vtctlclient.LogLevelFromContext(ctx),
 	}
 
 	stream, err := client.c.ExecuteVtctlCommand(ctx, query)
@@ -380,6 +380,15 @@ func (client *gRPCVtctldClient) GetKeyspaceRoutingRules(ctx context.Context, in
 	return client.c.GetKeyspaceRoutingRules(ctx, in, opts...)
 }
 
+// GetKeyspaceThrottlerStatus is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetKeyspaceThrottlerStatus(ctx context.Context, in *vtctldatapb.GetKeyspaceThrottlerStatusRequest, opts ...grpc.CallOption) (*vtctldatapb.GetKeyspaceThrottlerStatusResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetKeyspaceThrottlerStatus(ctx, in, opts...)
+}
+
 // GetKeyspaces is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetKeyspaces(ctx context.Context, in *vtctldatapb.GetKeyspacesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetKeyspacesResponse, error) {
 	if client.c == nil {
@@ -425,6 +434,15 @@ func (client *gRPCVtctldClient) GetSchema(ctx context.Context, in *vtctldatapb.G
 	return client.c.GetSchema(ctx, in, opts...)
 }
 
+// GetSchemaChangeHistory is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetSchemaChangeHistory(ctx context.Context, in *vtctldatapb.GetSchemaChangeHistoryRequest, opts ...grpc.CallOption) (*vtctldatapb.GetSchemaChangeHistoryResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetSchemaChangeHistory(ctx, in, opts...)
+}
+
 // GetSchemaMigrations is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetSchemaMigrations(ctx context.Context, in *vtctldatapb.GetSchemaMigrationsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetSchemaMigrationsResponse, error) {
 	if client.c == nil {
@@ -515,6 +533,15 @@ func (client *gRPCVtctldClient) GetTablets(ctx context.Context, in *vtctldatapb.
 	return client.c.GetTablets(ctx, in, opts...)
 }
 
+// GetThrottlerConfig is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetThrottlerConfig(ctx context.Context, in *vtctldatapb.GetThrottlerConfigRequest, opts ...grpc.CallOption) (*vtctldatapb.GetThrottlerConfigResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetThrottlerConfig(ctx, in, opts...)
+}
+
 // GetThrottlerStatus is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetThrottlerStatus(ctx context.Context, in *vtctldatapb.GetThrottlerStatusRequest, opts ...grpc.CallOption) (*vtctldatapb.GetThrottlerStatusResponse, error) {
 	if client.c == nil {
@@ -569,6 +596,15 @@ func (client *gRPCVtctldClient) GetVersion(ctx context.Context, in *vtctldatapb.
 	return client.c.GetVersion(ctx, in, opts...)
 }
 
+// GetWorkflowProgress is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetWorkflowProgress(ctx context.Context, in *vtctldatapb.GetWorkflowProgressRequest, opts ...grpc.CallOption) (*vtctldatapb.GetWorkflowProgressResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetWorkflowProgress(ctx, in, opts...)
+}
+
 // GetWorkflows is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetWorkflows(ctx context.Context, in *vtctldatapb.GetWorkflowsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetWorkflowsResponse, error) {
 	if client.c == nil {
@@ -785,6 +821,15 @@ func (client *gRPCVtctldClient) ReloadSchemaShard(ctx context.Context, in *vtctl
 	return client.c.ReloadSchemaShard(ctx, in, opts...)
 }
 
+// ReloadTabletConfig is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ReloadTabletConfig(ctx context.Context, in *vtctldatapb.ReloadTabletConfigRequest, opts ...grpc.CallOption) (*vtctldatapb.ReloadTabletConfigResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ReloadTabletConfig(ctx, in, opts...)
+}
+
 // RemoveBackup is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) RemoveBackup(ctx context.Context, in *vtctldatapb.RemoveBackupRequest, opts ...grpc.CallOption) (*vtctldatapb.RemoveBackupResponse, error) {
 	if client.c == nil {
@@ -983,6 +1028,24 @@ func (client *gRPCVtctldClient) TabletExternallyReparented(ctx context.Context,
 	return client.c.TabletExternallyReparented(ctx, in, opts...)
 }
 
+// TopoGet is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) TopoGet(ctx context.Context, in *vtctldatapb.TopoGetRequest, opts ...grpc.CallOption) (*vtctldatapb.TopoGetResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.TopoGet(ctx, in, opts...)
+}
+
+// TopoList is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) TopoList(ctx context.Context, in *vtctldatapb.TopoListRequest, opts ...grpc.CallOption) (*vtctldatapb.TopoListResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.TopoList(ctx, in, opts...)
+}
+
 // UpdateCellInfo is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) UpdateCellInfo(ctx context.Context, in *vtctldatapb.UpdateCellInfoRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateCellInfoResponse, error) {
 	if client.c == nil {
@@ -1082,6 +1145,24 @@ func (client *gRPCVtctldClient) ValidatePermissionsKeyspace(ctx context.Context,
 	return client.c.ValidatePermissionsKeyspace(ctx, in, opts...)
 }
 
+// ValidatePermissionsKeyspaceStream is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ValidatePermissionsKeyspaceStream(ctx context.Context, in *vtctldatapb.ValidatePermissionsKeyspaceRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_ValidatePermissionsKeyspaceStreamClient, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ValidatePermissionsKeyspaceStream(ctx, in, opts...)
+}
+
+// ValidatePermissionsShard is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ValidatePermissionsShard(ctx context.Context, in *vtctldatapb.ValidatePermissionsShardRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidatePermissionsShardResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ValidatePermissionsShard(ctx, in, opts...)
+}
+
 // ValidateSchemaKeyspace is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ValidateSchemaKeyspace(ctx context.Context, in *vtctldatapb.ValidateSchemaKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateSchemaKeyspaceResponse, error) {
 	if client.c == nil {
@@ -1091,6 +1172,15 @@ func (client *gRPCVtctldClient) ValidateSchemaKeyspace(ctx context.Context, in *
 	return client.c.ValidateSchemaKeyspace(ctx, in, opts...)
 }
 
+// ValidateSchemaKeyspaceStream is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ValidateSchemaKeyspaceStream(ctx context.Context, in *vtctldatapb.ValidateSchemaKeyspaceRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_ValidateSchemaKeyspaceStreamClient, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ValidateSchemaKeyspaceStream(ctx, in, opts...)
+}
+
 // ValidateShard is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ValidateShard(ctx context.Context, in *vtctldatapb.ValidateShardRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateShardResponse, error) {
 	if client.c == nil {
@@ -1118,6 +1208,15 @@ func (client *gRPCVtctldClient) ValidateVersionKeyspace(ctx context.Context, in
 	return client.c.ValidateVersionKeyspace(ctx, in, opts...)
 }
 
+// ValidateVersionKeyspaceStream is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ValidateVersionKeyspaceStream(ctx context.Context, in *vtctldatapb.ValidateVersionKeyspaceRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_ValidateVersionKeyspaceStreamClient, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ValidateVersionKeyspaceStream(ctx, in, opts...)
+}
+
 // ValidateVersionShard is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ValidateVersionShard(ctx context.Context, in *vtctldatapb.ValidateVersionShardRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateVersionShardResponse, error) {
 	if client.c == nil {
@@ -0,0 +1,591 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtctldserver
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// keyspaceValidationConcurrency bounds the number of shards and tablets a
+// keyspace-wide validation reads from at once.
+const keyspaceValidationConcurrency = 16
+
+// validationProgressInterval is how often a streaming keyspace-wide
+// validation sends its progress, which also keeps the stream from being idle
+// while there are no findings to send.
+var validationProgressInterval = 10 * time.Second
+
+// nonServingTabletTypes are the types of the tablets that validations skip
+// unless asked to include them, because their MySQL is expected to be
+// unavailable or out of date while they are in that state.
+var nonServingTabletTypes = []topodatapb.TabletType{
+	topodatapb.TabletType_DRAINED,
+	topodatapb.TabletType_BACKUP,
+	topodatapb.TabletType_RESTORE,
+}
+
+// keyspaceComparison describes a keyspace-wide validation that compares a
+// value read from every tablet of the keyspace with the one read from a
+// reference tablet.
+type keyspaceComparison[T any] struct {
+	// name describes the value in logs, e.g. "permissions".
+	name string
+	// get reads the value from a tablet.
+	get func(ctx context.Context, alias *topodatapb.TabletAlias) (T, error)
+	// diff describes the differences between the reference value and the
+	// value of a tablet, if any.
+	diff func(referenceAlias *topodatapb.TabletAlias, reference T, alias *topodatapb.TabletAlias, value T) []string
+	// includeNonServing includes the tablets of a nonServingTabletTypes
+	// type, which are skipped by default.
+	includeNonServing bool
+	// skipShardsWithoutPrimary skips the shards that have no primary, e.g.
+	// because they are failing over, with a SKIPPED finding, instead of
+	// comparing their tablets.
+	skipShardsWithoutPrimary bool
+	// skipNonServingShards skips the shards that serve no traffic, e.g. the
+	// overlapping shards of a keyspace being resharded, with a SKIPPED
+	// finding, instead of comparing their tablets.
+	skipNonServingShards bool
+	// progress, if set, is given the findings as soon as they are found,
+	// and the progress of the validation.
+	progress *validationProgress
+}
+
+// getWithTimeout reads the value from a tablet, giving up after the tablet
+// timeout of ctx, so that a tablet whose mysqld hangs is reported without
+// holding up the validation of the other tablets.
+func (c keyspaceComparison[T]) getWithTimeout(ctx context.Context, alias *topodatapb.TabletAlias) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, tabletTimeout(ctx))
+	defer cancel()
+	return c.get(ctx, alias)
+}
+
+type tabletTimeoutKey struct{}
+
+// WithTabletTimeout returns a copy of ctx with which the validations give up
+// on each tablet after timeout, instead of topo.RemoteOperationTimeout, e.g.
+// to apply the tablet timeout of a vtctl command.
+func WithTabletTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, tabletTimeoutKey{}, timeout)
+}
+
+// tabletTimeout returns how long the validations may wait for each tablet,
+// as set on ctx with WithTabletTimeout.
+func tabletTimeout(ctx context.Context) time.Duration {
+	if timeout, ok := ctx.Value(tabletTimeoutKey{}).(time.Duration); ok && timeout > 0 {
+		return timeout
+	}
+	return topo.RemoteOperationTimeout
+}
+
+// keyspaceShard is a shard of a keyspace being validated, with its record
+// and the tablets to validate, or the error reading them.
+type keyspaceShard struct {
+	name    string
+	si      *topo.ShardInfo
+	aliases []*topodatapb.TabletAlias
+	// skipped are the tablets of the shard that are not validated.
+	skipped []*vtctldatapb.SkippedTablet
+	// notServing is set for a shard that serves no traffic, whose tablets
+	// are not read, with skipNonServingShards.
+	notServing bool
+	err        error
+}
+
+// compareKeyspaceTablets runs the comparison on every tablet of the given
+// shards of the keyspace, or of all of them if there are none, against the
+// primary of referenceShard, or of the first serving shard with a primary
+// in lexicographic order if referenceShard is empty. The shards are
+// resolved, and the tablets compared, concurrently, with bounded
+// parallelism.
+//
+// It returns the validated shards in lexicographic order, the findings in
+// shard then tablet alias order: an ERROR for each difference, a WARNING
+// for each shard or tablet that couldn't be read, and a SKIPPED for each
+// shard skipped by comparison.skipShardsWithoutPrimary or
+// comparison.skipNonServingShards, the tablets that were skipped, and the
+// shards that were skipped because they serve no traffic. It returns an
+// error if the reference value can't be read, or if ctx is done before all
+// the tablets are compared.
+func compareKeyspaceTablets[T any](ctx context.Context, ts *topo.Server, keyspace string, shardNames []string, referenceShard string, comparison keyspaceComparison[T]) ([]string, []*vtctldatapb.ValidationFinding, []*vtctldatapb.SkippedTablet, []string, error) {
+	shards, err := resolveKeyspaceShards(ctx, ts, keyspace, shardNames, comparison.includeNonServing, comparison.skipNonServingShards)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	var names, nonServing []string
+	for _, shard := range shards {
+		if shard.notServing {
+			nonServing = append(nonServing, shard.name)
+			continue
+		}
+		names = append(names, shard.name)
+	}
+
+	referenceAlias, err := keyspaceReferenceAlias(keyspace, referenceShard, shards)
+	if err != nil {
+		return names, nil, nil, nonServing, err
+	}
+	findings, skipped, err := compareTablets(ctx, shards, referenceAlias, comparison)
+	if err != nil {
+		return names, nil, nil, nonServing, err
+	}
+	return names, findings, skipped, nonServing, nil
+}
+
+// compareShardTablets runs the comparison on every tablet of the shard
+// against referenceAlias, or against the primary of the shard if it is nil.
+// The reference tablet doesn't need to be in the shard.
+//
+// It returns the findings in tablet alias order and the skipped tablets,
+// like compareKeyspaceTablets, and an error if the shard can't be read.
+func compareShardTablets[T any](ctx context.Context, ts *topo.Server, keyspace, shard string, referenceAlias *topodatapb.TabletAlias, comparison keyspaceComparison[T]) ([]*vtctldatapb.ValidationFinding, []*vtctldatapb.SkippedTablet, error) {
+	shards, err := resolveKeyspaceShards(ctx, ts, keyspace, []string{shard}, comparison.includeNonServing, false /* skipNonServingShards */)
+	if err != nil {
+		return nil, nil, err
+	}
+	if shards[0].err != nil {
+		return nil, nil, shards[0].err
+	}
+	if referenceAlias == nil {
+		if !shards[0].si.HasPrimary() {
+			return nil, nil, fmt.Errorf("no primary in shard %v/%v", keyspace, shard)
+		}
+		referenceAlias = shards[0].si.PrimaryAlias
+	}
+	return compareTablets(ctx, shards, referenceAlias, comparison)
+}
+
+// compareTablets runs the comparison on every tablet of the resolved shards,
+// except referenceAlias, against referenceAlias, concurrently. It also
+// returns the tablets the shards skipped, except referenceAlias.
+func compareTablets[T any](ctx context.Context, shards []*keyspaceShard, referenceAlias *topodatapb.TabletAlias, comparison keyspaceComparison[T]) ([]*vtctldatapb.ValidationFinding, []*vtctldatapb.SkippedTablet, error) {
+	log.Infof("Gathering %v for reference tablet %v", comparison.name, topoproto.TabletAliasString(referenceAlias))
+	reference, err := comparison.getWithTimeout(ctx, referenceAlias)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot get the %v of reference tablet %v: %w", comparison.name, topoproto.TabletAliasString(referenceAlias), err)
+	}
+
+	// Each shard and tablet writes to its own slot, so that the findings
+	// are in a deterministic order.
+	type tablet struct {
+		shard string
+		alias *topodatapb.TabletAlias
+	}
+	var (
+		tablets []tablet
+		skipped []*vtctldatapb.SkippedTablet
+	)
+	shardFindings := make([][]*vtctldatapb.ValidationFinding, len(shards))
+	for i, shard := range shards {
+		if shard.err != nil {
+			shardFindings[i] = []*vtctldatapb.ValidationFinding{{
+				Severity: vtctldatapb.ValidationFinding_WARNING,
+				Shard:    shard.name,
+				Message:  shard.err.Error(),
+			}}
+			continue
+		}
+		if shard.notServing {
+			shardFindings[i] = []*vtctldatapb.ValidationFinding{{
+				Severity:   vtctldatapb.ValidationFinding_SKIPPED,
+				Shard:      shard.name,
+				Message:    fmt.Sprintf("shard %v/%v is not serving, skipped", shard.si.Keyspace(), shard.name),
+				NotServing: true,
+			}}
+			continue
+		}
+		if comparison.skipShardsWithoutPrimary && !shard.si.HasPrimary() {
+			shardFindings[i] = []*vtctldatapb.ValidationFinding{{
+				Severity: vtctldatapb.ValidationFinding_SKIPPED,
+				Shard:    shard.name,
+				Message:  fmt.Sprintf("no primary in shard %v/%v, skipped", shard.si.Keyspace(), shard.name),
+			}}
+			continue
+		}
+		for _, alias := range shard.aliases {
+			if !topoproto.TabletAliasEqual(alias, referenceAlias) {
+				tablets = append(tablets, tablet{shard: shard.name, alias: alias})
+			}
+		}
+		for _, st := range shard.skipped {
+			if !topoproto.TabletAliasEqual(st.TabletAlias, referenceAlias) {
+				skipped = append(skipped, st)
+			}
+		}
+	}
+	tabletFindings := make([][]*vtctldatapb.ValidationFinding, len(tablets))
+	comparison.progress.start(len(shards), len(tablets))
+	comparison.progress.skip(skipped)
+	for _, f := range shardFindings {
+		comparison.progress.add(0, f)
+	}
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(keyspaceValidationConcurrency)
+	for i, tablet := range tablets {
+		eg.Go(func() error {
+			// Don't start comparing more tablets once the validation is
+			// cancelled.
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			log.Infof("Gathering %v for %v", comparison.name, topoproto.TabletAliasString(tablet.alias))
+			value, err := comparison.getWithTimeout(ctx, tablet.alias)
+			if err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return ctxErr
+				}
+				tabletFindings[i] = []*vtctldatapb.ValidationFinding{{
+					Severity:    vtctldatapb.ValidationFinding_WARNING,
+					Shard:       tablet.shard,
+					TabletAlias: tablet.alias,
+					Message:     fmt.Sprintf("cannot get the %v of %v: %v", comparison.name, topoproto.TabletAliasString(tablet.alias), err),
+				}}
+				comparison.progress.add(1, tabletFindings[i])
+				return nil
+			}
+			for _, diff := range comparison.diff(referenceAlias, reference, tablet.alias, value) {
+				tabletFindings[i] = append(tabletFindings[i], &vtctldatapb.ValidationFinding{
+					Severity:    vtctldatapb.ValidationFinding_ERROR,
+					Shard:       tablet.shard,
+					TabletAlias: tablet.alias,
+					Message:     diff,
+				})
+			}
+			comparison.progress.add(1, tabletFindings[i])
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	var findings []*vtctldatapb.ValidationFinding
+	for _, f := range shardFindings {
+		findings = append(findings, f...)
+	}
+	for _, f := range tabletFindings {
+		findings = append(findings, f...)
+	}
+	return findings, skipped, nil
+}
+
+// resolveKeyspaceShards reads the records and tablets of the given shards
+// of the keyspace, or of all of them if there are none, concurrently. They
+// are returned in lexicographic order, and those that couldn't be read have
+// an error. The tablets are found with findShardTablets. If
+// skipNonServingShards is set, the shards that serve no traffic are marked
+// as not serving instead, without reading their tablets.
+func resolveKeyspaceShards(ctx context.Context, ts *topo.Server, keyspace string, names []string, includeNonServing, skipNonServingShards bool) ([]*keyspaceShard, error) {
+	if len(names) == 0 {
+		var err error
+		names, err = ts.GetShardNames(ctx, keyspace)
+		if err != nil {
+			return nil, err
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no shards in keyspace %v", keyspace)
+		}
+	} else {
+		names = slices.Clone(names)
+	}
+	sort.Strings(names)
+	names = slices.Compact(names)
+
+	shards := make([]*keyspaceShard, len(names))
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(keyspaceValidationConcurrency)
+	for i, name := range names {
+		shard := &keyspaceShard{name: name}
+		shards[i] = shard
+		eg.Go(func() error {
+			shard.si, shard.err = ts.GetShard(ctx, keyspace, name)
+			if shard.err != nil {
+				shard.err = fmt.Errorf("cannot read shard %v/%v: %w", keyspace, name, shard.err)
+				return nil
+			}
+			if skipNonServingShards && !IsShardServing(ctx, ts, shard.si) {
+				shard.notServing = true
+				return nil
+			}
+			aliases, skipped, err := findShardTablets(ctx, ts, keyspace, name, includeNonServing)
+			if err != nil {
+				shard.err = fmt.Errorf("cannot find the tablets of shard %v/%v: %w", keyspace, name, err)
+				return nil
+			}
+			shard.aliases, shard.skipped = aliases, skipped
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	return shards, nil
+}
+
+// IsShardServing returns true if the shard serves traffic: its primary is
+// serving, or it is in the serving graph of a cell for some tablet type,
+// e.g. because the reads were switched to it during a reshard. A shard whose
+// serving graph can't be read is considered serving, so that it is
+// validated.
+func IsShardServing(ctx context.Context, ts *topo.Server, si *topo.ShardInfo) bool {
+	if si.IsPrimaryServing {
+		return true
+	}
+	servingTypes, err := ts.GetShardServingTypes(ctx, si)
+	return err != nil || len(servingTypes) > 0
+}
+
+// splitNonServingShards returns the shards of the keyspace that serve
+// traffic, and those that don't, in the order of shards. The shards that
+// can't be read are returned as serving, so that their validation reports
+// it.
+func splitNonServingShards(ctx context.Context, ts *topo.Server, keyspace string, shards []string) (serving []string, nonServing []string) {
+	for _, shard := range shards {
+		si, err := ts.GetShard(ctx, keyspace, shard)
+		if err == nil && !IsShardServing(ctx, ts, si) {
+			nonServing = append(nonServing, shard)
+			continue
+		}
+		serving = append(serving, shard)
+	}
+	return serving, nonServing
+}
+
+// findShardTablets returns the aliases of the tablets of the shard to
+// validate, in tablet alias order. Unless includeNonServing is set, the
+// tablets of a nonServingTabletTypes type are returned as skipped instead.
+// The tablets whose record can't be read are validated, so that the
+// validation reports them.
+func findShardTablets(ctx context.Context, ts *topo.Server, keyspace, shard string, includeNonServing bool) ([]*topodatapb.TabletAlias, []*vtctldatapb.SkippedTablet, error) {
+	aliases, err := ts.FindAllTabletAliasesInShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, nil, err
+	}
+	slices.SortFunc(aliases, func(a, b *topodatapb.TabletAlias) int {
+		return strings.Compare(topoproto.TabletAliasString(a), topoproto.TabletAliasString(b))
+	})
+	if includeNonServing || len(aliases) == 0 {
+		return aliases, nil, nil
+	}
+
+	tablets, err := ts.GetTabletMap(ctx, aliases, nil)
+	if err != nil && !topo.IsErrType(err, topo.PartialResult) {
+		return nil, nil, err
+	}
+	validated := make([]*topodatapb.TabletAlias, 0, len(aliases))
+	var skipped []*vtctldatapb.SkippedTablet
+	for _, alias := range aliases {
+		ti, ok := tablets[topoproto.TabletAliasString(alias)]
+		if ok && slices.Contains(nonServingTabletTypes, ti.Type) {
+			skipped = append(skipped, &vtctldatapb.SkippedTablet{
+				Shard:       shard,
+				TabletAlias: alias,
+				TabletType:  ti.Type,
+			})
+			continue
+		}
+		validated = append(validated, alias)
+	}
+	return validated, skipped, nil
+}
+
+// keyspaceReferenceAlias returns the primary of referenceShard, or of the
+// first serving shard with a primary if referenceShard is empty.
+func keyspaceReferenceAlias(keyspace, referenceShard string, shards []*keyspaceShard) (*topodatapb.TabletAlias, error) {
+	for _, shard := range shards {
+		if referenceShard == "" {
+			if shard.err == nil && shard.si.IsPrimaryServing && shard.si.HasPrimary() {
+				return shard.si.PrimaryAlias, nil
+			}
+			continue
+		}
+		if shard.name != referenceShard {
+			continue
+		}
+		if shard.err != nil {
+			return nil, shard.err
+		}
+		if shard.notServing {
+			return nil, fmt.Errorf("reference shard %v/%v is not serving", keyspace, referenceShard)
+		}
+		if !shard.si.HasPrimary() {
+			return nil, fmt.Errorf("no primary in reference shard %v/%v", keyspace, referenceShard)
+		}
+		return shard.si.PrimaryAlias, nil
+	}
+
+	if referenceShard != "" {
+		return nil, fmt.Errorf("reference shard %v/%v is not one of the validated shards", keyspace, referenceShard)
+	}
+	return nil, fmt.Errorf("no serving shard with a primary in keyspace %v to use as the reference", keyspace)
+}
+
+// validationProgress tracks a keyspace-wide validation while it runs, so that
+// its findings can be streamed as soon as they are found. Its methods are safe
+// for concurrent use, and do nothing on a nil validationProgress.
+type validationProgress struct {
+	mu       sync.Mutex
+	progress *vtctldatapb.ValidationProgress
+	// pending are the findings that weren't taken yet.
+	pending []*vtctldatapb.ValidationFinding
+	// skipped are the skipped tablets that weren't taken yet.
+	skipped []*vtctldatapb.SkippedTablet
+	// found is signalled when there are pending findings or skipped tablets.
+	found chan struct{}
+}
+
+func newValidationProgress() *validationProgress {
+	return &validationProgress{
+		progress: &vtctldatapb.ValidationProgress{},
+		found:    make(chan struct{}, 1),
+	}
+}
+
+// start records the number of shards and tablets being validated.
+func (p *validationProgress) start(shards, tablets int) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.Shards = uint32(shards)
+	p.progress.Tablets = uint32(tablets)
+}
+
+// skip records the tablets that are not validated.
+func (p *validationProgress) skip(tablets []*vtctldatapb.SkippedTablet) {
+	if p == nil || len(tablets) == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	p.skipped = append(p.skipped, tablets...)
+	p.mu.Unlock()
+
+	select {
+	case p.found <- struct{}{}:
+	default:
+	}
+}
+
+// add records that tablets more tablets were compared, with the given
+// findings.
+func (p *validationProgress) add(tablets int, findings []*vtctldatapb.ValidationFinding) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.progress.TabletsCompared += uint32(tablets)
+	p.progress.Findings += uint32(len(findings))
+	p.pending = append(p.pending, findings...)
+	p.mu.Unlock()
+
+	if len(findings) > 0 {
+		select {
+		case p.found <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// take returns the pending findings and skipped tablets, and the current
+// progress.
+func (p *validationProgress) take() ([]*vtctldatapb.ValidationFinding, []*vtctldatapb.SkippedTablet, *vtctldatapb.ValidationProgress) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pending, skipped := p.pending, p.skipped
+	p.pending, p.skipped = nil, nil
+	return pending, skipped, p.progress.CloneVT()
+}
+
+// streamKeyspaceValidation runs validate, and sends each finding and skipped
+// tablet it gives its validationProgress as soon as it is found, its
+// progress every validationProgressInterval, and its final progress once it
+// is done. The validation is cancelled as soon as ctx is done, e.g. because the client
+// went away, or sending fails.
+func streamKeyspaceValidation(ctx context.Context, send func(*vtctldatapb.ValidationStreamResponse) error, validate func(ctx context.Context, progress *validationProgress) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	progress := newValidationProgress()
+	done := make(chan error, 1)
+	go func() {
+		done <- validate(ctx, progress)
+	}()
+
+	ticker := time.NewTicker(validationProgressInterval)
+	defer ticker.Stop()
+
+	flush := func(withProgress bool) error {
+		findings, skipped, current := progress.take()
+		for _, st := range skipped {
+			if err := send(&vtctldatapb.ValidationStreamResponse{SkippedTablet: st}); err != nil {
+				return err
+			}
+		}
+		for _, finding := range findings {
+			if err := send(&vtctldatapb.ValidationStreamResponse{Finding: finding}); err != nil {
+				return err
+			}
+		}
+		if !withProgress {
+			return nil
+		}
+		return send(&vtctldatapb.ValidationStreamResponse{Progress: current})
+	}
+
+	for {
+		select {
+		case <-progress.found:
+			if err := flush(false); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := flush(true); err != nil {
+				return err
+			}
+		case err := <-done:
+			// The findings found before validate failed are still sent, so
+			// that the client doesn't lose them.
+			flushErr := flush(true)
+			if err != nil {
+				return err
+			}
+			return flushErr
+		}
+	}
+}
@@ -0,0 +1,503 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtctldserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// TestCompareKeyspaceTablets tests the findings of a keyspace-wide
+// comparison, and the choice of the reference tablet.
+func TestCompareKeyspaceTablets(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+
+	// Shard -80 is not serving, so 80- is the default reference.
+	values := map[uint32]string{}
+	addTablet := func(shard string, uid uint32, tabletType topodatapb.TabletType, value string) {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    shard,
+			Type:     tabletType,
+		}
+		require.NoError(t, ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/))
+		if tabletType == topodatapb.TabletType_PRIMARY {
+			_, err := ts.UpdateShardFields(ctx, "ks", shard, func(si *topo.ShardInfo) error {
+				si.PrimaryAlias = tablet.Alias
+				si.IsPrimaryServing = shard != "-80"
+				return nil
+			})
+			require.NoError(t, err)
+		}
+		values[uid] = value
+	}
+	addTablet("-80", 100, topodatapb.TabletType_PRIMARY, "v1")
+	addTablet("-80", 101, topodatapb.TabletType_REPLICA, "v2")
+	addTablet("80-", 200, topodatapb.TabletType_PRIMARY, "v2")
+	for uid := uint32(201); uid < 240; uid++ {
+		addTablet("80-", uid, topodatapb.TabletType_REPLICA, "v2")
+	}
+	addTablet("80-", 240, topodatapb.TabletType_RDONLY, "")
+
+	comparison := keyspaceComparison[string]{
+		name: "value",
+		get: func(ctx context.Context, alias *topodatapb.TabletAlias) (string, error) {
+			if values[alias.Uid] == "" {
+				return "", errors.New("unreachable")
+			}
+			return values[alias.Uid], nil
+		},
+		diff: func(referenceAlias *topodatapb.TabletAlias, reference string, alias *topodatapb.TabletAlias, value string) []string {
+			if value == reference {
+				return nil
+			}
+			return []string{fmt.Sprintf("%v has %v, %v has %v", topoproto.TabletAliasString(alias), value, topoproto.TabletAliasString(referenceAlias), reference)}
+		},
+	}
+
+	want := []*vtctldatapb.ValidationFinding{{
+		Severity:    vtctldatapb.ValidationFinding_ERROR,
+		Shard:       "-80",
+		TabletAlias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 100},
+		Message:     "cell1-0000000100 has v1, cell1-0000000200 has v2",
+	}, {
+		Severity:    vtctldatapb.ValidationFinding_WARNING,
+		Shard:       "80-",
+		TabletAlias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 240},
+		Message:     "cannot get the value of cell1-0000000240: unreachable",
+	}}
+	for range 5 {
+		shards, findings, _, _, err := compareKeyspaceTablets(ctx, ts, "ks", nil, "", comparison)
+		require.NoError(t, err)
+		require.Equal(t, []string{"-80", "80-"}, shards)
+		utils.MustMatch(t, want, findings)
+	}
+
+	_, findings, _, _, err := compareKeyspaceTablets(ctx, ts, "ks", nil, "-80", comparison)
+	require.NoError(t, err)
+	require.Len(t, findings, 42)
+	require.Equal(t, "cell1-0000000101 has v2, cell1-0000000100 has v1", findings[0].Message)
+
+	// Only the given shards are validated.
+	shards, findings, _, _, err := compareKeyspaceTablets(ctx, ts, "ks", []string{"80-"}, "", comparison)
+	require.NoError(t, err)
+	require.Equal(t, []string{"80-"}, shards)
+	utils.MustMatch(t, want[1:], findings)
+
+	_, _, _, _, err = compareKeyspaceTablets(ctx, ts, "ks", []string{"80-"}, "-80", comparison)
+	require.ErrorContains(t, err, "reference shard ks/-80 is not one of the validated shards")
+
+	_, _, _, _, err = compareKeyspaceTablets(ctx, ts, "ks", nil, "c0-", comparison)
+	require.ErrorContains(t, err, "reference shard ks/c0- is not one of the validated shards")
+
+	// A single shard is compared with its primary by default, or with the
+	// given reference tablet, which can be in another shard.
+	findings, _, err = compareShardTablets(ctx, ts, "ks", "-80", nil, comparison)
+	require.NoError(t, err)
+	utils.MustMatch(t, []*vtctldatapb.ValidationFinding{{
+		Severity:    vtctldatapb.ValidationFinding_ERROR,
+		Shard:       "-80",
+		TabletAlias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 101},
+		Message:     "cell1-0000000101 has v2, cell1-0000000100 has v1",
+	}}, findings)
+	findings, _, err = compareShardTablets(ctx, ts, "ks", "-80", &topodatapb.TabletAlias{Cell: "cell1", Uid: 200}, comparison)
+	require.NoError(t, err)
+	utils.MustMatch(t, want[:1], findings)
+
+	_, _, err = compareShardTablets(ctx, ts, "ks", "c0-", nil, comparison)
+	require.ErrorContains(t, err, "cannot read shard ks/c0-")
+
+	// The non-serving shard is skipped, and can't be the reference.
+	comparison.skipNonServingShards = true
+	shards, findings, _, nonServing, err := compareKeyspaceTablets(ctx, ts, "ks", nil, "", comparison)
+	require.NoError(t, err)
+	require.Equal(t, []string{"80-"}, shards)
+	require.Equal(t, []string{"-80"}, nonServing)
+	utils.MustMatch(t, append([]*vtctldatapb.ValidationFinding{{
+		Severity:   vtctldatapb.ValidationFinding_SKIPPED,
+		Shard:      "-80",
+		Message:    "shard ks/-80 is not serving, skipped",
+		NotServing: true,
+	}}, want[1:]...), findings)
+	_, _, _, _, err = compareKeyspaceTablets(ctx, ts, "ks", nil, "-80", comparison)
+	require.ErrorContains(t, err, "reference shard ks/-80 is not serving")
+	comparison.skipNonServingShards = false
+
+	_, err = ts.UpdateShardFields(ctx, "ks", "80-", func(si *topo.ShardInfo) error {
+		si.IsPrimaryServing = false
+		return nil
+	})
+	require.NoError(t, err)
+	_, _, _, _, err = compareKeyspaceTablets(ctx, ts, "ks", nil, "", comparison)
+	require.ErrorContains(t, err, "no serving shard with a primary in keyspace ks")
+}
+
+// TestCompareKeyspaceTabletsTabletTimeout tests that a tablet that doesn't
+// answer is given up on after the tablet timeout of the context.
+func TestCompareKeyspaceTabletsTabletTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	for _, tablet := range []*topodatapb.Tablet{
+		{Alias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}, Keyspace: "ks", Shard: "0", Type: topodatapb.TabletType_PRIMARY},
+		{Alias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 101}, Keyspace: "ks", Shard: "0", Type: topodatapb.TabletType_REPLICA},
+	} {
+		require.NoError(t, ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/))
+	}
+	_, err := ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The replica hangs until its context is done.
+	comparison := keyspaceComparison[string]{
+		name: "value",
+		get: func(ctx context.Context, alias *topodatapb.TabletAlias) (string, error) {
+			if alias.Uid == 101 {
+				<-ctx.Done()
+				return "", ctx.Err()
+			}
+			return "v1", nil
+		},
+		diff: func(referenceAlias *topodatapb.TabletAlias, reference string, alias *topodatapb.TabletAlias, value string) []string {
+			return nil
+		},
+	}
+	start := time.Now()
+	findings, _, err := compareShardTablets(WithTabletTimeout(ctx, 10*time.Millisecond), ts, "ks", "0", nil, comparison)
+	require.NoError(t, err)
+	require.Less(t, time.Since(start), topo.RemoteOperationTimeout)
+	utils.MustMatch(t, []*vtctldatapb.ValidationFinding{{
+		Severity:    vtctldatapb.ValidationFinding_WARNING,
+		Shard:       "0",
+		TabletAlias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 101},
+		Message:     "cannot get the value of cell1-0000000101: context deadline exceeded",
+	}}, findings)
+}
+
+// TestCompareKeyspaceTabletsNonServing tests that the non-serving tablets
+// are skipped unless they are included.
+func TestCompareKeyspaceTabletsNonServing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	types := map[uint32]topodatapb.TabletType{
+		100: topodatapb.TabletType_PRIMARY,
+		101: topodatapb.TabletType_REPLICA,
+		102: topodatapb.TabletType_DRAINED,
+		103: topodatapb.TabletType_BACKUP,
+		104: topodatapb.TabletType_RESTORE,
+		105: topodatapb.TabletType_RDONLY,
+	}
+	for uid, tabletType := range types {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    "-",
+			Type:     tabletType,
+		}
+		require.NoError(t, ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/))
+	}
+	_, err := ts.UpdateShardFields(ctx, "ks", "-", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}
+		si.IsPrimaryServing = true
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Every tablet differs from the reference.
+	comparison := keyspaceComparison[uint32]{
+		name: "uid",
+		get: func(ctx context.Context, alias *topodatapb.TabletAlias) (uint32, error) {
+			return alias.Uid, nil
+		},
+		diff: func(referenceAlias *topodatapb.TabletAlias, reference uint32, alias *topodatapb.TabletAlias, value uint32) []string {
+			return []string{fmt.Sprint(value)}
+		},
+	}
+	messages := func(findings []*vtctldatapb.ValidationFinding) []string {
+		var msgs []string
+		for _, finding := range findings {
+			msgs = append(msgs, finding.Message)
+		}
+		return msgs
+	}
+
+	wantSkipped := []*vtctldatapb.SkippedTablet{{
+		Shard:       "-",
+		TabletAlias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 102},
+		TabletType:  topodatapb.TabletType_DRAINED,
+	}, {
+		Shard:       "-",
+		TabletAlias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 103},
+		TabletType:  topodatapb.TabletType_BACKUP,
+	}, {
+		Shard:       "-",
+		TabletAlias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 104},
+		TabletType:  topodatapb.TabletType_RESTORE,
+	}}
+	_, findings, skipped, _, err := compareKeyspaceTablets(ctx, ts, "ks", nil, "", comparison)
+	require.NoError(t, err)
+	require.Equal(t, []string{"101", "105"}, messages(findings))
+	utils.MustMatch(t, wantSkipped, skipped)
+
+	findings, skipped, err = compareShardTablets(ctx, ts, "ks", "-", nil, comparison)
+	require.NoError(t, err)
+	require.Equal(t, []string{"101", "105"}, messages(findings))
+	utils.MustMatch(t, wantSkipped, skipped)
+
+	// The skipped tablets are streamed too.
+	var streamed []*vtctldatapb.SkippedTablet
+	err = streamKeyspaceValidation(ctx, func(resp *vtctldatapb.ValidationStreamResponse) error {
+		if resp.SkippedTablet != nil {
+			streamed = append(streamed, resp.SkippedTablet)
+		}
+		return nil
+	}, func(ctx context.Context, progress *validationProgress) error {
+		comparison := comparison
+		comparison.progress = progress
+		_, _, _, _, err := compareKeyspaceTablets(ctx, ts, "ks", nil, "", comparison)
+		return err
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, wantSkipped, streamed)
+
+	comparison.includeNonServing = true
+	_, findings, skipped, _, err = compareKeyspaceTablets(ctx, ts, "ks", nil, "", comparison)
+	require.NoError(t, err)
+	require.Equal(t, []string{"101", "102", "103", "104", "105"}, messages(findings))
+	require.Empty(t, skipped)
+}
+
+// TestCompareKeyspaceTabletsWithoutPrimary tests that the shards without a
+// primary are skipped if asked to, and that the other shards are still
+// compared.
+func TestCompareKeyspaceTabletsWithoutPrimary(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	for _, tablet := range []*topodatapb.Tablet{
+		{Alias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}, Shard: "-80", Type: topodatapb.TabletType_PRIMARY},
+		{Alias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 101}, Shard: "-80", Type: topodatapb.TabletType_REPLICA},
+		{Alias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 200}, Shard: "80-", Type: topodatapb.TabletType_REPLICA},
+	} {
+		tablet.Keyspace = "ks"
+		require.NoError(t, ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/))
+	}
+	_, err := ts.UpdateShardFields(ctx, "ks", "-80", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}
+		si.IsPrimaryServing = true
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Every tablet differs from the reference.
+	comparison := keyspaceComparison[uint32]{
+		name: "uid",
+		get: func(ctx context.Context, alias *topodatapb.TabletAlias) (uint32, error) {
+			return alias.Uid, nil
+		},
+		diff: func(referenceAlias *topodatapb.TabletAlias, reference uint32, alias *topodatapb.TabletAlias, value uint32) []string {
+			return []string{fmt.Sprint(value)}
+		},
+	}
+
+	_, findings, _, _, err := compareKeyspaceTablets(ctx, ts, "ks", nil, "", comparison)
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+	require.Equal(t, "200", findings[1].Message)
+
+	comparison.skipShardsWithoutPrimary = true
+	_, findings, _, _, err = compareKeyspaceTablets(ctx, ts, "ks", nil, "", comparison)
+	require.NoError(t, err)
+	utils.MustMatch(t, []*vtctldatapb.ValidationFinding{{
+		Severity: vtctldatapb.ValidationFinding_SKIPPED,
+		Shard:    "80-",
+		Message:  "no primary in shard ks/80-, skipped",
+	}, {
+		Severity:    vtctldatapb.ValidationFinding_ERROR,
+		Shard:       "-80",
+		TabletAlias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 101},
+		Message:     "101",
+	}}, findings)
+}
+
+// TestStreamKeyspaceValidation tests that a streaming keyspace-wide
+// validation sends its findings and progress, and stops as soon as it is
+// cancelled.
+func TestStreamKeyspaceValidation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	for uid := uint32(100); uid <= 110; uid++ {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    "-",
+			Type:     topodatapb.TabletType_REPLICA,
+		}
+		if uid == 100 {
+			tablet.Type = topodatapb.TabletType_PRIMARY
+		}
+		require.NoError(t, ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/))
+	}
+	_, err := ts.UpdateShardFields(ctx, "ks", "-", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}
+		si.IsPrimaryServing = true
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The tablets with an odd uid differ from the reference.
+	validate := func(get func(ctx context.Context, alias *topodatapb.TabletAlias) (uint32, error)) func(ctx context.Context, progress *validationProgress) error {
+		return func(ctx context.Context, progress *validationProgress) error {
+			_, _, _, _, err := compareKeyspaceTablets(ctx, ts, "ks", nil, "", keyspaceComparison[uint32]{
+				name: "uid",
+				get:  get,
+				diff: func(referenceAlias *topodatapb.TabletAlias, reference uint32, alias *topodatapb.TabletAlias, value uint32) []string {
+					if value%2 == reference%2 {
+						return nil
+					}
+					return []string{fmt.Sprint(value)}
+				},
+				progress: progress,
+			})
+			return err
+		}
+	}
+
+	defer func(interval time.Duration) { validationProgressInterval = interval }(validationProgressInterval)
+	validationProgressInterval = time.Millisecond
+
+	t.Run("findings and progress", func(t *testing.T) {
+		// The last tablet isn't compared until the progress was sent.
+		heartbeat := make(chan struct{})
+		var once sync.Once
+		var sent []*vtctldatapb.ValidationStreamResponse
+		err := streamKeyspaceValidation(ctx, func(resp *vtctldatapb.ValidationStreamResponse) error {
+			if resp.Progress != nil {
+				once.Do(func() { close(heartbeat) })
+			}
+			sent = append(sent, resp)
+			return nil
+		}, validate(func(ctx context.Context, alias *topodatapb.TabletAlias) (uint32, error) {
+			if alias.Uid == 110 {
+				<-heartbeat
+			}
+			return alias.Uid, nil
+		}))
+		require.NoError(t, err)
+
+		var findings []string
+		var progress []*vtctldatapb.ValidationProgress
+		for _, resp := range sent {
+			if resp.Finding != nil {
+				findings = append(findings, resp.Finding.Message)
+			} else {
+				progress = append(progress, resp.Progress)
+			}
+		}
+		sort.Strings(findings)
+		require.Equal(t, []string{"101", "103", "105", "107", "109"}, findings)
+		require.Less(t, progress[0].TabletsCompared, uint32(10))
+		utils.MustMatch(t, &vtctldatapb.ValidationProgress{
+			Shards:          1,
+			Tablets:         10,
+			TabletsCompared: 10,
+			Findings:        5,
+		}, sent[len(sent)-1].Progress)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		err := streamKeyspaceValidation(ctx, func(resp *vtctldatapb.ValidationStreamResponse) error {
+			return nil
+		}, validate(func(ctx context.Context, alias *topodatapb.TabletAlias) (uint32, error) {
+			cancel()
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}))
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("validation fails", func(t *testing.T) {
+		// The findings found before the validation failed are still sent.
+		var sent []*vtctldatapb.ValidationStreamResponse
+		err := streamKeyspaceValidation(ctx, func(resp *vtctldatapb.ValidationStreamResponse) error {
+			sent = append(sent, resp)
+			return nil
+		}, func(ctx context.Context, progress *validationProgress) error {
+			progress.start(1, 2)
+			progress.add(1, []*vtctldatapb.ValidationFinding{{
+				Severity: vtctldatapb.ValidationFinding_ERROR,
+				Shard:    "-",
+				Message:  "differs",
+			}})
+			return errors.New("cannot read the second tablet")
+		})
+		require.ErrorContains(t, err, "cannot read the second tablet")
+		require.Len(t, sent, 2)
+		require.Equal(t, "differs", sent[0].Finding.GetMessage())
+		utils.MustMatch(t, &vtctldatapb.ValidationProgress{
+			Shards:          1,
+			Tablets:         2,
+			TabletsCompared: 1,
+			Findings:        1,
+		}, sent[1].Progress)
+	})
+
+	t.Run("send fails", func(t *testing.T) {
+		// The validation is cancelled as soon as a finding can't be sent.
+		aborted := make(chan struct{})
+		err := streamKeyspaceValidation(ctx, func(resp *vtctldatapb.ValidationStreamResponse) error {
+			return errors.New("client went away")
+		}, validate(func(ctx context.Context, alias *topodatapb.TabletAlias) (uint32, error) {
+			if alias.Uid == 110 {
+				<-ctx.Done()
+				close(aborted)
+				return 0, ctx.Err()
+			}
+			return alias.Uid, nil
+		}))
+		require.ErrorContains(t, err, "client went away")
+		<-aborted
+	})
+}
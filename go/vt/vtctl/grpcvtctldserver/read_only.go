@@ -0,0 +1,230 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtctldserver
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// rpcAccess tells whether an RPC changes the topo or the tablets.
+type rpcAccess int
+
+const (
+	// rpcUnclassified is the access of the RPCs missing from rpcAccesses,
+	// which a read-only server refuses.
+	rpcUnclassified rpcAccess = iota
+	// rpcReadOnly RPCs only read the topo and the tablets.
+	rpcReadOnly
+	// rpcMutating RPCs write to the topo, or issue tablet RPCs that change
+	// the tablets.
+	rpcMutating
+)
+
+// rpcAccesses classifies every RPC of the Vtctld service, by method name. An
+// RPC that isn't listed is refused by a read-only server, and
+// TestRPCAccesses fails until it is.
+var rpcAccesses = map[string]rpcAccess{
+	"AddCellInfo":                       rpcMutating,
+	"AddCellsAlias":                     rpcMutating,
+	"ApplyKeyspaceRoutingRules":         rpcMutating,
+	"ApplyRoutingRules":                 rpcMutating,
+	"ApplySchema":                       rpcMutating,
+	"ApplyShardRoutingRules":            rpcMutating,
+	"ApplyVSchema":                      rpcMutating,
+	"Backup":                            rpcMutating,
+	"BackupShard":                       rpcMutating,
+	"CancelSchemaMigration":             rpcMutating,
+	"ChangeTabletTags":                  rpcMutating,
+	"ChangeTabletType":                  rpcMutating,
+	"CheckThrottler":                    rpcReadOnly,
+	"CleanupSchemaMigration":            rpcMutating,
+	"CompleteSchemaMigration":           rpcMutating,
+	"ConcludeTransaction":               rpcMutating,
+	"CopySchemaShard":                   rpcMutating,
+	"CreateKeyspace":                    rpcMutating,
+	"CreateShard":                       rpcMutating,
+	"DeleteCellInfo":                    rpcMutating,
+	"DeleteCellsAlias":                  rpcMutating,
+	"DeleteKeyspace":                    rpcMutating,
+	"DeleteShards":                      rpcMutating,
+	"DeleteSrvVSchema":                  rpcMutating,
+	"DeleteTablets":                     rpcMutating,
+	"EmergencyReparentShard":            rpcMutating,
+	"ExecuteFetchAsApp":                 rpcMutating,
+	"ExecuteFetchAsDBA":                 rpcMutating,
+	"ExecuteHook":                       rpcMutating,
+	"ExecuteMultiFetchAsDBA":            rpcMutating,
+	"FindAllShardsInKeyspace":           rpcReadOnly,
+	"ForceCutOverSchemaMigration":       rpcMutating,
+	"GetBackups":                        rpcReadOnly,
+	"GetCellInfo":                       rpcReadOnly,
+	"GetCellInfoNames":                  rpcReadOnly,
+	"GetCellsAliases":                   rpcReadOnly,
+	"GetFullStatus":                     rpcReadOnly,
+	"GetKeyspace":                       rpcReadOnly,
+	"GetKeyspaceRoutingRules":           rpcReadOnly,
+	"GetKeyspaceThrottlerStatus":        rpcReadOnly,
+	"GetKeyspaces":                      rpcReadOnly,
+	"GetMirrorRules":                    rpcReadOnly,
+	"GetPermissions":                    rpcReadOnly,
+	"GetRoutingRules":                   rpcReadOnly,
+	"GetSchema":                         rpcReadOnly,
+	"GetSchemaChangeHistory":            rpcReadOnly,
+	"GetSchemaMigrations":               rpcReadOnly,
+	"GetShard":                          rpcReadOnly,
+	"GetShardReplication":               rpcReadOnly,
+	"GetShardRoutingRules":              rpcReadOnly,
+	"GetSrvKeyspaceNames":               rpcReadOnly,
+	"GetSrvKeyspaces":                   rpcReadOnly,
+	"GetSrvVSchema":                     rpcReadOnly,
+	"GetSrvVSchemas":                    rpcReadOnly,
+	"GetTablet":                         rpcReadOnly,
+	"GetTablets":                        rpcReadOnly,
+	"GetThrottlerConfig":                rpcReadOnly,
+	"GetThrottlerStatus":                rpcReadOnly,
+	"GetTopologyPath":                   rpcReadOnly,
+	"GetTransactionInfo":                rpcReadOnly,
+	"GetUnresolvedTransactions":         rpcReadOnly,
+	"GetVSchema":                        rpcReadOnly,
+	"GetVersion":                        rpcReadOnly,
+	"GetWorkflowProgress":               rpcReadOnly,
+	"GetWorkflows":                      rpcReadOnly,
+	"InitShardPrimary":                  rpcMutating,
+	"LaunchSchemaMigration":             rpcMutating,
+	"LookupVindexComplete":              rpcMutating,
+	"LookupVindexCreate":                rpcMutating,
+	"LookupVindexExternalize":           rpcMutating,
+	"LookupVindexInternalize":           rpcMutating,
+	"MaterializeCreate":                 rpcMutating,
+	"MigrateCreate":                     rpcMutating,
+	"MountList":                         rpcReadOnly,
+	"MountRegister":                     rpcMutating,
+	"MountShow":                         rpcReadOnly,
+	"MountUnregister":                   rpcMutating,
+	"MoveTablesComplete":                rpcMutating,
+	"MoveTablesCreate":                  rpcMutating,
+	"PingTablet":                        rpcReadOnly,
+	"PlannedReparentShard":              rpcMutating,
+	"RebuildKeyspaceGraph":              rpcMutating,
+	"RebuildVSchemaGraph":               rpcMutating,
+	"RefreshState":                      rpcMutating,
+	"RefreshStateByShard":               rpcMutating,
+	"ReloadSchema":                      rpcMutating,
+	"ReloadSchemaKeyspace":              rpcMutating,
+	"ReloadSchemaShard":                 rpcMutating,
+	"ReloadTabletConfig":                rpcMutating,
+	"RemoveBackup":                      rpcMutating,
+	"RemoveKeyspaceCell":                rpcMutating,
+	"RemoveShardCell":                   rpcMutating,
+	"ReparentTablet":                    rpcMutating,
+	"ReshardCreate":                     rpcMutating,
+	"RestoreFromBackup":                 rpcMutating,
+	"RetrySchemaMigration":              rpcMutating,
+	"RunHealthCheck":                    rpcReadOnly,
+	"SetKeyspaceDurabilityPolicy":       rpcMutating,
+	"SetShardIsPrimaryServing":          rpcMutating,
+	"SetShardTabletControl":             rpcMutating,
+	"SetWritable":                       rpcMutating,
+	"ShardReplicationAdd":               rpcMutating,
+	"ShardReplicationFix":               rpcMutating,
+	"ShardReplicationPositions":         rpcReadOnly,
+	"ShardReplicationRemove":            rpcMutating,
+	"SleepTablet":                       rpcMutating,
+	"SourceShardAdd":                    rpcMutating,
+	"SourceShardDelete":                 rpcMutating,
+	"StartReplication":                  rpcMutating,
+	"StopReplication":                   rpcMutating,
+	"TabletExternallyReparented":        rpcMutating,
+	"TopoGet":                           rpcReadOnly,
+	"TopoList":                          rpcReadOnly,
+	"UpdateCellInfo":                    rpcMutating,
+	"UpdateCellsAlias":                  rpcMutating,
+	"UpdateThrottlerConfig":             rpcMutating,
+	"VDiffCreate":                       rpcMutating,
+	"VDiffDelete":                       rpcMutating,
+	"VDiffResume":                       rpcMutating,
+	"VDiffShow":                         rpcReadOnly,
+	"VDiffStop":                         rpcMutating,
+	"Validate":                          rpcReadOnly,
+	"ValidateKeyspace":                  rpcReadOnly,
+	"ValidatePermissionsKeyspace":       rpcReadOnly,
+	"ValidatePermissionsKeyspaceStream": rpcReadOnly,
+	"ValidatePermissionsShard":          rpcReadOnly,
+	"ValidateSchemaKeyspace":            rpcReadOnly,
+	"ValidateSchemaKeyspaceStream":      rpcReadOnly,
+	"ValidateShard":                     rpcReadOnly,
+	"ValidateVSchema":                   rpcReadOnly,
+	"ValidateVersionKeyspace":           rpcReadOnly,
+	"ValidateVersionKeyspaceStream":     rpcReadOnly,
+	"ValidateVersionShard":              rpcReadOnly,
+	"WorkflowDelete":                    rpcMutating,
+	"WorkflowMirrorTraffic":             rpcMutating,
+	"WorkflowStatus":                    rpcReadOnly,
+	"WorkflowSwitchTraffic":             rpcMutating,
+	"WorkflowUpdate":                    rpcMutating,
+}
+
+// readOnly makes the server refuse the RPCs that aren't read-only.
+var readOnly atomic.Bool
+
+// SetReadOnly sets whether the server refuses the RPCs that change the topo
+// or the tablets, with PERMISSION_DENIED. It takes effect once the
+// interceptors returned by ReadOnlyInterceptors are added to the gRPC server.
+func SetReadOnly(enabled bool) {
+	readOnly.Store(enabled)
+}
+
+// ReadOnlyInterceptors returns the interceptors that refuse the RPCs of the
+// Vtctld service which aren't read-only, while the server is read-only. They
+// let the calls to the other services through.
+func ReadOnlyInterceptors() (grpc.StreamServerInterceptor, grpc.UnaryServerInterceptor) {
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkWritable(info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := checkWritable(info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	return stream, unary
+}
+
+// checkWritable returns a PERMISSION_DENIED error if the server is
+// read-only and fullMethod is an RPC of the Vtctld service that isn't.
+func checkWritable(fullMethod string) error {
+	if !readOnly.Load() {
+		return nil
+	}
+	name, ok := strings.CutPrefix(fullMethod, "/"+vtctlservicepb.Vtctld_ServiceDesc.ServiceName+"/")
+	if !ok || rpcAccesses[name] == rpcReadOnly {
+		return nil
+	}
+	return vterrors.Errorf(vtrpcpb.Code_PERMISSION_DENIED, "%v is not allowed: vtctld is read-only", name)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtctldserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestRPCAccesses(t *testing.T) {
+	registered := make(map[string]bool)
+	for _, method := range vtctlservicepb.Vtctld_ServiceDesc.Methods {
+		registered[method.MethodName] = true
+		assert.NotEqual(t, rpcUnclassified, rpcAccesses[method.MethodName], "RPC %v is not classified in rpcAccesses", method.MethodName)
+	}
+	for _, stream := range vtctlservicepb.Vtctld_ServiceDesc.Streams {
+		registered[stream.StreamName] = true
+		assert.NotEqual(t, rpcUnclassified, rpcAccesses[stream.StreamName], "streaming RPC %v is not classified in rpcAccesses", stream.StreamName)
+	}
+	for name, access := range rpcAccesses {
+		assert.True(t, registered[name], "%v is classified in rpcAccesses but is not an RPC", name)
+		assert.NotEqual(t, rpcUnclassified, access, "%v is explicitly unclassified", name)
+	}
+}
+
+func TestReadOnlyInterceptors(t *testing.T) {
+	defer SetReadOnly(false)
+	stream, unary := ReadOnlyInterceptors()
+
+	call := func(fullMethod string) (unaryErr error, streamErr error) {
+		_, unaryErr = unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: fullMethod}, func(ctx context.Context, req any) (any, error) {
+			return nil, nil
+		})
+		streamErr = stream(nil, nil, &grpc.StreamServerInfo{FullMethod: fullMethod}, func(srv any, ss grpc.ServerStream) error {
+			return nil
+		})
+		return unaryErr, streamErr
+	}
+
+	tests := []struct {
+		method   string
+		readOnly bool
+		denied   bool
+	}{
+		{method: "/vtctlservice.Vtctld/GetTablet", readOnly: true},
+		{method: "/vtctlservice.Vtctld/ValidateVersionKeyspaceStream", readOnly: true},
+		{method: "/vtctlservice.Vtctld/DeleteTablets", readOnly: true, denied: true},
+		{method: "/vtctlservice.Vtctld/Backup", readOnly: true, denied: true},
+		{method: "/vtctlservice.Vtctld/NotAnRPC", readOnly: true, denied: true},
+		{method: "/vtctlservice.Vtctl/ExecuteVtctlCommand", readOnly: true},
+		{method: "/vtctlservice.Vtctld/DeleteTablets"},
+	}
+	for _, tt := range tests {
+		SetReadOnly(tt.readOnly)
+		unaryErr, streamErr := call(tt.method)
+		for _, err := range []error{unaryErr, streamErr} {
+			if !tt.denied {
+				assert.NoError(t, err, "%v with readOnly=%v", tt.method, tt.readOnly)
+				continue
+			}
+			require.Error(t, err, "%v with readOnly=%v", tt.method, tt.readOnly)
+			assert.Equal(t, vtrpcpb.Code_PERMISSION_DENIED, vterrors.Code(err))
+		}
+	}
+}
@@ -26,10 +26,12 @@ import (
 	"net/http"
 	"path/filepath"
 	"runtime/debug"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -79,6 +81,7 @@ import (
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/base"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 )
 
@@ -99,8 +102,12 @@ type VtctldServer struct {
 
 // NewVtctldServer returns a new VtctldServer for the given topo server.
 func NewVtctldServer(env *vtenv.Environment, ts *topo.Server) *VtctldServer {
-	tmc := tmclient.NewTabletManagerClient()
+	return NewVtctldServerWithTabletManagerClient(env, ts, tmclient.NewTabletManagerClient())
+}
 
+// NewVtctldServerWithTabletManagerClient returns a new VtctldServer for the
+// given topo server that uses the given tmclient.
+func NewVtctldServerWithTabletManagerClient(env *vtenv.Environment, ts *topo.Server, tmc tmclient.TabletManagerClient) *VtctldServer {
 	return &VtctldServer{
 		ts:  ts,
 		tmc: tmc,
@@ -118,6 +125,16 @@ func NewTestVtctldServer(ts *topo.Server, tmc tmclient.TabletManagerClient) *Vtc
 	}
 }
 
+// WithTopoServer returns a copy of the server that uses the given topo
+// server, and the same tmclient.
+func (s *VtctldServer) WithTopoServer(env *vtenv.Environment, ts *topo.Server) *VtctldServer {
+	return &VtctldServer{
+		ts:  ts,
+		tmc: s.tmc,
+		ws:  workflow.NewServer(env, ts, s.tmc),
+	}
+}
+
 func panicHandler(err *error) {
 	if x := recover(); x != nil {
 		*err = fmt.Errorf("uncaught panic: %v from: %v", x, string(debug.Stack()))
@@ -159,15 +176,31 @@ func (s *VtctldServer) AddCellsAlias(ctx context.Context, req *vtctldatapb.AddCe
 
 	span.Annotate("cells_alias", req.Name)
 	span.Annotate("cells", strings.Join(req.Cells, ","))
+	span.Annotate("force", req.Force)
+	span.Annotate("dry_run", req.DryRun)
 
 	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
 	defer cancel()
 
-	if err = s.ts.CreateCellsAlias(ctx, req.Name, &topodatapb.CellsAlias{Cells: req.Cells}); err != nil {
+	if err = validateCellsAlias(ctx, s.ts, req.Name, req.Cells, req.Force); err != nil {
+		return nil, err
+	}
+	changes, err := cellsAliasRoutingChanges(ctx, s.ts, nil, req.Cells)
+	if err != nil {
+		return nil, err
+	}
+	resp = &vtctldatapb.AddCellsAliasResponse{
+		RoutingChanges: changes,
+	}
+	if req.DryRun {
+		return resp, nil
+	}
+
+	if err = s.ts.CreateCellsAliasWithOptions(ctx, req.Name, &topodatapb.CellsAlias{Cells: req.Cells}, &topo.CellsAliasOptions{AllowOverlap: req.Force}); err != nil {
 		return nil, err
 	}
 
-	return &vtctldatapb.AddCellsAliasResponse{}, nil
+	return resp, nil
 }
 
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldServer interface.
@@ -180,7 +213,29 @@ func (s *VtctldServer) ApplyRoutingRules(ctx context.Context, req *vtctldatapb.A
 	span.Annotate("skip_rebuild", req.SkipRebuild)
 	span.Annotate("rebuild_cells", strings.Join(req.RebuildCells, ","))
 
-	if err = s.ts.SaveRoutingRules(ctx, req.RoutingRules); err != nil {
+	_, version, err := s.ts.GetRoutingRulesWithVersion(ctx)
+	if err != nil {
+		err = vterrors.Wrapf(err, "GetRoutingRules")
+		return nil, err
+	}
+
+	lctx, unlock, lockErr := s.ts.LockRoutingRules(ctx, "ApplyRoutingRules")
+	if lockErr != nil {
+		err = vterrors.Wrapf(lockErr, "LockRoutingRules")
+		return nil, err
+	}
+	ctx = lctx
+	defer unlock(&err)
+
+	if err = s.ts.UpdateRoutingRules(ctx, req.RoutingRules, version); err != nil {
+		if topo.IsErrType(err, topo.BadVersion) {
+			currentVersion := "none"
+			if _, cv, gerr := s.ts.GetRoutingRulesWithVersion(ctx); gerr == nil {
+				currentVersion = versionString(cv)
+			}
+			err = vterrors.Errorf(vtrpcpb.Code_ABORTED, "the routing rules were changed by another writer while they were being applied (read at version %s, now at version %s), so they were not saved; re-read them and apply the change again",
+				versionString(version), currentVersion)
+		}
 		return nil, err
 	}
 
@@ -290,11 +345,23 @@ func (s *VtctldServer) ApplySchema(ctx context.Context, req *vtctldatapb.ApplySc
 		}
 	}
 
+	if req.RespectThrottler {
+		span.Annotate("throttler_app_name", req.ThrottlerAppName)
+
+		maxThrottleWait, _, err := protoutil.DurationFromProto(req.MaxThrottleWait)
+		if err != nil {
+			err = vterrors.Wrapf(err, "unable to parse MaxThrottleWait into a valid duration")
+			return nil, err
+		}
+		executor.SetThrottleWaiter(schematools.NewThrottleWaiter(s.tmc, logger, req.ThrottlerAppName, maxThrottleWait))
+	}
+
 	execResult, err := schemamanager.Run(
 		ctx,
 		schemamanager.NewPlainController(req.Sql, req.Keyspace),
 		executor,
 	)
+	s.recordSchemaChange(ctx, req, executionUUID, migrationContext, execResult, err)
 	if err != nil {
 		return nil, err
 	}
@@ -313,7 +380,44 @@ func (s *VtctldServer) ApplySchema(ctx context.Context, req *vtctldatapb.ApplySc
 	return resp, err
 }
 
+// recordSchemaChange writes an audit log event of the schema change applied
+// by ApplySchema, whether it succeeded or not, and journals it in the topo if
+// the schema change journal is enabled. A failure to journal the change is
+// only logged, as the change has been applied already.
+func (s *VtctldServer) recordSchemaChange(ctx context.Context, req *vtctldatapb.ApplySchemaRequest, executionUUID string, migrationContext string, execResult *schemamanager.ExecuteResult, err error) {
+	change := &vtctldatapb.SchemaChange{
+		Keyspace:         req.Keyspace,
+		ExecutionUuid:    executionUUID,
+		AppliedAt:        protoutil.TimeToProto(time.Now()),
+		CallerId:         callerid.EffectiveCallerIDFromContext(ctx),
+		Sql:              req.Sql,
+		DdlStrategy:      req.DdlStrategy,
+		MigrationContext: migrationContext,
+	}
+	if execResult != nil {
+		change.UuidList = execResult.UUIDs
+	}
+	if err != nil {
+		change.Error = err.Error()
+	}
+
+	log.Infof("ApplySchema audit: keyspace=%s, caller=%s, executionUUID=%s, migrationContext=%s, ddlStrategy=%s, uuids=%v, sql=%v, error=%v",
+		change.Keyspace, callerid.GetPrincipal(change.CallerId), change.ExecutionUuid, change.MigrationContext, change.DdlStrategy, change.UuidList, change.Sql, change.Error)
+
+	if !schemaChangeJournal.Load() {
+		return
+	}
+	if err := schematools.RecordSchemaChange(ctx, s.ts, change); err != nil {
+		log.Warningf("Failed to journal schema change %s of keyspace %s: %v", executionUUID, req.Keyspace, err)
+	}
+}
+
 // ApplyVSchema is part of the vtctlservicepb.VtctldServer interface.
+//
+// The VSchema is saved under the keyspace lock, and only if it hasn't been
+// changed since it was read to compute the changes, so that concurrent edits
+// aren't silently overwritten. With RetryOnConflict, the Sql is applied again
+// to the VSchema that was saved in the meantime.
 func (s *VtctldServer) ApplyVSchema(ctx context.Context, req *vtctldatapb.ApplyVSchemaRequest) (resp *vtctldatapb.ApplyVSchemaResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyVSchema")
 	defer span.Finish()
@@ -324,6 +428,7 @@ func (s *VtctldServer) ApplyVSchema(ctx context.Context, req *vtctldatapb.ApplyV
 	span.Annotate("cells", strings.Join(req.Cells, ","))
 	span.Annotate("skip_rebuild", req.SkipRebuild)
 	span.Annotate("dry_run", req.DryRun)
+	span.Annotate("retry_on_conflict", req.RetryOnConflict)
 
 	if _, err = s.ts.GetKeyspace(ctx, req.Keyspace); err != nil {
 		if topo.IsErrType(err, topo.NoNode) {
@@ -339,11 +444,12 @@ func (s *VtctldServer) ApplyVSchema(ctx context.Context, req *vtctldatapb.ApplyV
 		err = vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "must pass exactly one of req.VSchema and req.Sql")
 		return nil, err
 	}
-
-	ksvs := &topo.KeyspaceVSchemaInfo{
-		Name: req.Keyspace,
+	if req.RetryOnConflict && req.Sql == "" {
+		err = vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "req.RetryOnConflict is only supported with req.Sql, as req.VSchema replaces the whole VSchema")
+		return nil, err
 	}
 
+	var ddl *sqlparser.AlterVschema
 	if req.Sql != "" {
 		span.Annotate("sql_mode", true)
 
@@ -353,30 +459,103 @@ func (s *VtctldServer) ApplyVSchema(ctx context.Context, req *vtctldatapb.ApplyV
 			err = vterrors.Wrapf(err, "Parse(%s)", req.Sql)
 			return nil, err
 		}
-		ddl, ok := stmt.(*sqlparser.AlterVschema)
+		var ok bool
+		ddl, ok = stmt.(*sqlparser.AlterVschema)
 		if !ok {
 			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "error parsing VSchema DDL statement `%s`", req.Sql)
 			return nil, err
 		}
+	} else { // "jsonMode"
+		span.Annotate("sql_mode", false)
+	}
+
+	ksvs, response, err := s.prepareVSchema(ctx, req, ddl)
+	if err != nil || req.DryRun { // return early on errors or if dry run
+		return response, err
+	}
+
+	lctx, unlock, lockErr := s.ts.LockKeyspace(ctx, req.Keyspace, "ApplyVSchema")
+	if lockErr != nil {
+		err = vterrors.Wrapf(lockErr, "LockKeyspace(%s)", req.Keyspace)
+		return nil, err
+	}
+	ctx = lctx
+	defer unlock(&err)
 
-		ksvs, err = topotools.ApplyVSchemaDDL(ctx, req.Keyspace, s.ts, ddl)
+	for attempt := 1; ; attempt++ {
+		err = s.ts.SaveVSchema(ctx, ksvs)
+		if err == nil {
+			break
+		}
+		if !topo.IsErrType(err, topo.BadVersion) && !topo.IsErrType(err, topo.NoNode) {
+			err = vterrors.Wrapf(err, "SaveVSchema(%s, %v)", req.Keyspace, req.VSchema)
+			return nil, err
+		}
+		if !req.RetryOnConflict || attempt >= maxApplyVSchemaAttempts {
+			err = s.vschemaConflictError(ctx, req.Keyspace, ksvs.Version())
+			return nil, err
+		}
+		log.Infof("The VSchema of keyspace %s changed while it was being applied, applying %q to it again", req.Keyspace, req.Sql)
+		ksvs, response, err = s.prepareVSchema(ctx, req, ddl)
 		if err != nil {
-			err = vterrors.Wrapf(err, "ApplyVSchemaDDL(%s,%v,%v)", req.Keyspace, ksvs, ddl)
+			return response, err
+		}
+	}
+
+	if !req.SkipRebuild {
+		if err = s.ts.RebuildSrvVSchema(ctx, req.Cells); err != nil {
+			err = vterrors.Wrapf(err, "RebuildSrvVSchema")
 			return nil, err
 		}
-	} else { // "jsonMode"
-		span.Annotate("sql_mode", false)
+	}
+	updatedVS, err := s.ts.GetVSchema(ctx, req.Keyspace)
+	if err != nil {
+		err = vterrors.Wrapf(err, "GetVSchema(%s)", req.Keyspace)
+		return nil, err
+	}
+	response.VSchema = updatedVS.Keyspace
+	return response, nil
+}
+
+// maxApplyVSchemaAttempts is the number of times ApplyVSchema tries to save
+// the VSchema with RetryOnConflict.
+const maxApplyVSchemaAttempts = 3
+
+// prepareVSchema reads the current VSchema of the keyspace and returns the
+// one to save in its place, at the version that was read, along with the
+// response describing it. With Strict, it returns the response along with
+// an error if there are unknown vindex params.
+func (s *VtctldServer) prepareVSchema(ctx context.Context, req *vtctldatapb.ApplyVSchemaRequest, ddl *sqlparser.AlterVschema) (*topo.KeyspaceVSchemaInfo, *vtctldatapb.ApplyVSchemaResponse, error) {
+	currentVS, err := s.ts.GetVSchema(ctx, req.Keyspace)
+	switch {
+	case err == nil:
+	case topo.IsErrType(err, topo.NoNode):
+		currentVS = &topo.KeyspaceVSchemaInfo{
+			Name:     req.Keyspace,
+			Keyspace: &vschemapb.Keyspace{},
+		}
+	default:
+		return nil, nil, vterrors.Wrapf(err, "GetVSchema(%s)", req.Keyspace)
+	}
+
+	ksvs := currentVS.CloneVT()
+	if ddl != nil {
+		ksvs, err = topotools.AlterVSchema(ksvs, ddl)
+		if err != nil {
+			return nil, nil, vterrors.Wrapf(err, "ApplyVSchemaDDL(%s,%v,%v)", req.Keyspace, ksvs, ddl)
+		}
+	} else {
 		ksvs.Keyspace = req.VSchema
 	}
 
 	ksVs, err := vindexes.BuildKeyspace(ksvs.Keyspace, s.ws.SQLParser())
 	if err != nil {
-		err = vterrors.Wrapf(err, "BuildKeyspace(%s)", req.Keyspace)
-		return nil, err
+		return nil, nil, vterrors.Wrapf(err, "BuildKeyspace(%s)", req.Keyspace)
 	}
 	response := &vtctldatapb.ApplyVSchemaResponse{
 		VSchema:             ksvs.Keyspace,
 		UnknownVindexParams: make(map[string]*vtctldatapb.ApplyVSchemaResponse_ParamList),
+		Diff:                topotools.DiffVSchemas(currentVS.Keyspace, ksvs.Keyspace),
 	}
 
 	// Attach unknown Vindex params to the response.
@@ -398,33 +577,31 @@ func (s *VtctldServer) ApplyVSchema(ctx context.Context, req *vtctldatapb.ApplyV
 		}
 	}
 
-	if req.Strict && len(unknownVindexParams) > 0 { // return early if unknown params found in strict mode
-		err = vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongArguments, "unknown vindex params: %s", strings.Join(unknownVindexParams, "; "))
-		return response, err
-	}
-
-	if req.DryRun { // return early if dry run
-		return response, err
+	if req.Strict && len(unknownVindexParams) > 0 {
+		return nil, response, vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongArguments, "unknown vindex params: %s", strings.Join(unknownVindexParams, "; "))
 	}
+	return ksvs, response, nil
+}
 
-	if err = s.ts.SaveVSchema(ctx, ksvs); err != nil {
-		err = vterrors.Wrapf(err, "SaveVSchema(%s, %v)", req.Keyspace, req.VSchema)
-		return nil, err
+// vschemaConflictError returns the error for a VSchema that was changed by
+// another writer since it was read at the given version. The topo server
+// doesn't record who changed it, so the versions are all it can tell.
+func (s *VtctldServer) vschemaConflictError(ctx context.Context, keyspace string, readVersion topo.Version) error {
+	currentVersion := "none"
+	if currentVS, err := s.ts.GetVSchema(ctx, keyspace); err == nil {
+		currentVersion = versionString(currentVS.Version())
 	}
+	return vterrors.Errorf(vtrpcpb.Code_ABORTED, "the VSchema of keyspace %s was changed by another writer while it was being applied (read at version %s, now at version %s), so it was not saved; re-read it and apply the change again",
+		keyspace, versionString(readVersion), currentVersion)
+}
 
-	if !req.SkipRebuild {
-		if err = s.ts.RebuildSrvVSchema(ctx, req.Cells); err != nil {
-			err = vterrors.Wrapf(err, "RebuildSrvVSchema")
-			return nil, err
-		}
-	}
-	updatedVS, err := s.ts.GetVSchema(ctx, req.Keyspace)
-	if err != nil {
-		err = vterrors.Wrapf(err, "GetVSchema(%s)", req.Keyspace)
-		return nil, err
+// versionString returns the version, or "none" for a topo object that
+// doesn't exist yet.
+func versionString(version topo.Version) string {
+	if version == nil {
+		return "none"
 	}
-	response.VSchema = updatedVS.Keyspace
-	return response, nil
+	return version.String()
 }
 
 // Backup is part of the vtctlservicepb.VtctldServer interface.
@@ -470,9 +647,8 @@ func (s *VtctldServer) BackupShard(req *vtctldatapb.BackupShardRequest, stream v
 	tablets, stats, err := reparentutil.ShardReplicationStatuses(ctx, s.ts, s.tmc, req.Keyspace, req.Shard)
 	// Instead of return on err directly, only return err when no tablets for backup at all
 	if err != nil {
-		tablets = reparentutil.GetBackupCandidates(tablets, stats)
 		// Only return err when no usable tablet
-		if len(tablets) == 0 {
+		if len(reparentutil.GetBackupCandidates(tablets, stats)) == 0 {
 			return err
 		}
 	}
@@ -482,14 +658,14 @@ func (s *VtctldServer) BackupShard(req *vtctldatapb.BackupShardRequest, stream v
 		backupTabletLag uint32
 	)
 
+	// The tablets whose replication status can't be read have no stats.
 	for i, tablet := range tablets {
-		switch tablet.Type {
-		case topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY, topodatapb.TabletType_SPARE:
-		default:
+		if !reparentutil.IsBackupCandidateType(tablet.Type) || stats[i] == nil {
 			continue
 		}
 
-		if lag := stats[i].ReplicationLagSeconds; backupTablet == nil || lag < backupTabletLag {
+		lag := stats[i].ReplicationLagSeconds
+		if backupTablet == nil || reparentutil.CompareBackupCandidates(tablet.Type, lag, backupTablet.Type, backupTabletLag) < 0 {
 			backupTablet = tablet.Tablet
 			backupTabletLag = lag
 		}
@@ -541,7 +717,7 @@ func (s *VtctldServer) backupTablet(ctx context.Context, tablet *topodatapb.Tabl
 		return err
 	}
 
-	logger := logutil.NewConsoleLogger()
+	logger := logutil.NewConsoleLoggerFromFlags()
 	for {
 		event, err := logStream.Recv()
 		switch err {
@@ -759,6 +935,68 @@ func (s *VtctldServer) CheckThrottler(ctx context.Context, req *vtctldatapb.Chec
 	return resp, nil
 }
 
+// GetKeyspaceThrottlerStatus is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetKeyspaceThrottlerStatus(ctx context.Context, req *vtctldatapb.GetKeyspaceThrottlerStatusRequest) (resp *vtctldatapb.GetKeyspaceThrottlerStatusResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetKeyspaceThrottlerStatus")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	appName := req.AppName
+	if appName == "" {
+		appName = throttlerapp.VitessName.String()
+	}
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("app_name", appName)
+
+	shards, err := s.ts.GetShardNames(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(shards)
+
+	resp = &vtctldatapb.GetKeyspaceThrottlerStatusResponse{
+		Shards: make([]*vtctldatapb.ShardThrottlerStatus, len(shards)),
+	}
+	wg := sync.WaitGroup{}
+	for i, shard := range shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp.Shards[i] = s.shardThrottlerStatus(ctx, req.Keyspace, shard, appName)
+		}()
+	}
+	wg.Wait()
+
+	return resp, nil
+}
+
+// GetThrottlerConfig is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetThrottlerConfig(ctx context.Context, req *vtctldatapb.GetThrottlerConfigRequest) (resp *vtctldatapb.GetThrottlerConfigResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetThrottlerConfig")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+
+	throttlerConfig, err := s.ts.GetThrottlerConfig(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	acks, err := s.throttlerConfigAcknowledgements(ctx, req.Keyspace, throttlerConfig, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.GetThrottlerConfigResponse{
+		ThrottlerConfig:  throttlerConfig,
+		Acknowledgements: acks,
+	}, nil
+}
+
 // GetThrottlerStatus is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetThrottlerStatus(ctx context.Context, req *vtctldatapb.GetThrottlerStatusRequest) (resp *vtctldatapb.GetThrottlerStatusResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetThrottlerStatus")
@@ -892,8 +1130,19 @@ func (s *VtctldServer) CopySchemaShard(ctx context.Context, req *vtctldatapb.Cop
 		return nil, err
 	}
 
+	var throttleWaiter *schematools.ThrottleWaiter
+	if req.RespectThrottler {
+		span.Annotate("throttler_app_name", req.ThrottlerAppName)
+
+		maxThrottleWait, _, err := protoutil.DurationFromProto(req.MaxThrottleWait)
+		if err != nil {
+			return nil, err
+		}
+		throttleWaiter = schematools.NewThrottleWaiter(s.tmc, s.ws.Logger(), req.ThrottlerAppName, maxThrottleWait)
+	}
+
 	err = s.ws.CopySchemaShard(ctx, req.SourceTabletAlias, req.Tables, req.ExcludeTables, req.IncludeViews,
-		req.DestinationKeyspace, req.DestinationShard, waitReplicasTimeout, req.SkipVerify)
+		req.DestinationKeyspace, req.DestinationShard, waitReplicasTimeout, req.SkipVerify, throttleWaiter)
 
 	return &vtctldatapb.CopySchemaShardResponse{}, err
 }
@@ -1082,15 +1331,44 @@ func (s *VtctldServer) DeleteCellInfo(ctx context.Context, req *vtctldatapb.Dele
 
 	span.Annotate("cell", req.Name)
 	span.Annotate("force", req.Force)
+	span.Annotate("purge_references", req.PurgeReferences)
 
 	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
 	defer cancel()
 
+	refs, err := getCellReferences(ctx, s.ts, req.Name, req.Force)
+	if err != nil {
+		return nil, err
+	}
+	if !req.Force && (refs.blocking() || (refs.purgeable() && !req.PurgeReferences)) {
+		msg := "use --force to delete it anyway"
+		if !refs.blocking() {
+			msg = "use --purge-references to remove the cell from the shard tablet controls and cells aliases, and delete its SrvVSchema"
+		}
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cell %v is still referenced (%v); %s", req.Name, refs, msg)
+	}
+	if ctx.Err() != nil && req.Force {
+		// Reading the topo of the cell used up the timeout, which we take as
+		// the cell topo being down, like topo.Server.DeleteCellInfo does.
+		ctx, cancel = context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+		defer cancel()
+	}
+
+	resp = &vtctldatapb.DeleteCellInfoResponse{
+		References: refs.counts,
+	}
+	if req.PurgeReferences {
+		resp.PurgedReferences, err = purgeCellReferences(ctx, s.ts, req.Name, refs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if err = s.ts.DeleteCellInfo(ctx, req.Name, req.Force); err != nil {
 		return nil, err
 	}
 
-	return &vtctldatapb.DeleteCellInfoResponse{}, nil
+	return resp, nil
 }
 
 // DeleteCellsAlias is part of the vtctlservicepb.VtctldServer interface.
@@ -1176,6 +1454,12 @@ func (s *VtctldServer) DeleteKeyspace(ctx context.Context, req *vtctldatapb.Dele
 		}
 	}
 
+	// The shards are deleted, so finish the deletion even if ctx is done by
+	// now, rather than leave the keyspace with the serving graphs of some
+	// cells only.
+	ctx, cancel := cleanupContext(ctx)
+	defer cancel()
+
 	cells, err := s.ts.GetKnownCells(ctx)
 	if err != nil {
 		return nil, err
@@ -1234,15 +1518,39 @@ func (s *VtctldServer) DeleteSrvVSchema(ctx context.Context, req *vtctldatapb.De
 	}
 
 	span.Annotate("cell", req.Cell)
+	span.Annotate("dry_run", req.DryRun)
 
 	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
 	defer cancel()
 
+	srvVSchema, err := s.ts.GetSrvVSchema(ctx, req.Cell)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.DeleteSrvVSchemaResponse{
+		Keyspaces: make([]string, 0, len(srvVSchema.Keyspaces)),
+	}
+	for keyspace := range srvVSchema.Keyspaces {
+		resp.Keyspaces = append(resp.Keyspaces, keyspace)
+	}
+	sort.Strings(resp.Keyspaces)
+
+	resp.ServingKeyspaces, err = s.ts.GetSrvKeyspaceNames(ctx, req.Cell)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DryRun {
+		return resp, nil
+	}
+
 	if err = s.ts.DeleteSrvVSchema(ctx, req.Cell); err != nil {
 		return nil, err
 	}
+	resp.Deleted = true
 
-	return &vtctldatapb.DeleteSrvVSchemaResponse{}, nil
+	return resp, nil
 }
 
 // DeleteTablets is part of the vtctlservicepb.VtctldServer interface.
@@ -1345,6 +1653,9 @@ func (s *VtctldServer) ExecuteFetchAsApp(ctx context.Context, req *vtctldatapb.E
 	span.Annotate("tablet_alias", topoproto.TabletAliasString(req.TabletAlias))
 	span.Annotate("max_rows", req.MaxRows)
 	span.Annotate("use_pool", req.UsePool)
+	if req.CallerId != nil {
+		span.Annotate("caller_id", req.CallerId.Principal)
+	}
 
 	ti, err := s.ts.GetTablet(ctx, req.TabletAlias)
 	if err != nil {
@@ -1352,8 +1663,9 @@ func (s *VtctldServer) ExecuteFetchAsApp(ctx context.Context, req *vtctldatapb.E
 	}
 
 	qr, err := s.tmc.ExecuteFetchAsApp(ctx, ti.Tablet, req.UsePool, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
-		Query:   []byte(req.Query),
-		MaxRows: uint64(req.MaxRows),
+		Query:    []byte(req.Query),
+		MaxRows:  uint64(req.MaxRows),
+		CallerId: req.CallerId,
 	})
 	if err != nil {
 		return nil, err
@@ -1914,6 +2226,29 @@ func (s *VtctldServer) GetSchemaMigrations(ctx context.Context, req *vtctldatapb
 	return resp, err
 }
 
+// GetSchemaChangeHistory is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetSchemaChangeHistory(ctx context.Context, req *vtctldatapb.GetSchemaChangeHistoryRequest) (resp *vtctldatapb.GetSchemaChangeHistoryResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetSchemaChangeHistory")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("limit", req.Limit)
+
+	if req.Keyspace == "" {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "keyspace must be specified")
+		return nil, err
+	}
+
+	changes, err := schematools.GetSchemaChangeHistory(ctx, s.ts, req.Keyspace, int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.GetSchemaChangeHistoryResponse{Changes: changes}, nil
+}
+
 // GetShard is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetShard(ctx context.Context, req *vtctldatapb.GetShardRequest) (resp *vtctldatapb.GetShardResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetShard")
@@ -2142,27 +2477,27 @@ func (s *VtctldServer) UpdateThrottlerConfig(ctx context.Context, req *vtctldata
 		return throttlerConfig
 	}
 
-	ctx, unlock, lockErr := s.ts.LockKeyspace(ctx, req.Keyspace, "UpdateThrottlerConfig")
-	if lockErr != nil {
-		return nil, lockErr
-	}
-	defer unlock(&err)
-
-	ki, err := s.ts.GetKeyspace(ctx, req.Keyspace)
+	waitForAcknowledgement, _, err := protoutil.DurationFromProto(req.WaitForAcknowledgement)
 	if err != nil {
 		return nil, err
 	}
 
-	ki.ThrottlerConfig = update(ki.ThrottlerConfig)
-
-	err = s.ts.UpdateKeyspace(ctx, ki)
+	throttlerConfig, err := s.updateKeyspaceThrottlerConfig(ctx, req.Keyspace, update)
 	if err != nil {
-		return nil, err
+		return &vtctldatapb.UpdateThrottlerConfigResponse{}, err
 	}
 
-	_, err = s.ts.UpdateSrvKeyspaceThrottlerConfig(ctx, req.Keyspace, []string{}, update)
+	resp = &vtctldatapb.UpdateThrottlerConfigResponse{}
+	if waitForAcknowledgement > 0 {
+		span.Annotate("wait_for_acknowledgement", waitForAcknowledgement.String())
+
+		resp.Acknowledgements, err = s.throttlerConfigAcknowledgements(ctx, req.Keyspace, throttlerConfig, waitForAcknowledgement)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	return &vtctldatapb.UpdateThrottlerConfigResponse{}, err
+	return resp, nil
 }
 
 // GetSrvVSchema is part of the vtctlservicepb.VtctldServer interface.
@@ -2708,6 +3043,21 @@ func (s *VtctldServer) GetVSchema(ctx context.Context, req *vtctldatapb.GetVSche
 	}, nil
 }
 
+// GetWorkflowProgress is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetWorkflowProgress(ctx context.Context, req *vtctldatapb.GetWorkflowProgressRequest) (resp *vtctldatapb.GetWorkflowProgressResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetWorkflowProgress")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("shards", req.Shards)
+
+	resp, err = s.ws.GetWorkflowProgress(ctx, req)
+	return resp, err
+}
+
 // GetWorkflows is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetWorkflows(ctx context.Context, req *vtctldatapb.GetWorkflowsRequest) (resp *vtctldatapb.GetWorkflowsResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetWorkflows")
@@ -3327,8 +3677,9 @@ func (s *VtctldServer) RebuildKeyspaceGraph(ctx context.Context, req *vtctldatap
 	span.Annotate("keyspace", req.Keyspace)
 	span.Annotate("cells", strings.Join(req.Cells, ","))
 	span.Annotate("allow_partial", req.AllowPartial)
+	span.Annotate("force_write", req.ForceWrite)
 
-	if err = topotools.RebuildKeyspace(ctx, logutil.NewCallbackLogger(func(e *logutilpb.Event) {}), s.ts, req.Keyspace, req.Cells, req.AllowPartial); err != nil {
+	if err = topotools.RebuildKeyspace(ctx, logutil.NewCallbackLogger(func(e *logutilpb.Event) {}), s.ts, req.Keyspace, req.Cells, req.AllowPartial, req.ForceWrite); err != nil {
 		return nil, err
 	}
 
@@ -3532,25 +3883,135 @@ func (s *VtctldServer) ReloadSchemaKeyspace(ctx context.Context, req *vtctldatap
 	}, nil
 }
 
-// RemoveBackup is part of the vtctlservicepb.VtctldServer interface.
-func (s *VtctldServer) RemoveBackup(ctx context.Context, req *vtctldatapb.RemoveBackupRequest) (resp *vtctldatapb.RemoveBackupResponse, err error) {
-	span, ctx := trace.NewSpan(ctx, "VtctldServer.RemoveBackup")
+// ReloadTabletConfig is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ReloadTabletConfig(ctx context.Context, req *vtctldatapb.ReloadTabletConfigRequest) (resp *vtctldatapb.ReloadTabletConfigResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ReloadTabletConfig")
 	defer span.Finish()
 
 	defer panicHandler(&err)
 
-	bucket := fmt.Sprintf("%v/%v", req.Keyspace, req.Shard)
-
 	span.Annotate("keyspace", req.Keyspace)
 	span.Annotate("shard", req.Shard)
-	span.Annotate("bucket", bucket)
-	span.Annotate("backup_name", req.Name)
-
-	bs, err := backupstorage.GetBackupStorage()
-	if err != nil {
-		return nil, err
-	}
-	defer bs.Close()
+	span.Annotate("category", req.Category.String())
+	span.Annotate("concurrency", req.Concurrency)
+
+	shards := []string{req.Shard}
+	if req.Shard == "" {
+		shards, err = s.ts.GetShardNames(ctx, req.Keyspace)
+		if err != nil {
+			err = vterrors.Errorf(vtrpcpb.Code_INTERNAL, "GetShardNames(%v) failed: %v", req.Keyspace, err)
+			return nil, err
+		}
+	}
+
+	var tablets []*topo.TabletInfo
+	for _, shard := range shards {
+		tabletMap, err := s.ts.GetTabletMapForShard(ctx, req.Keyspace, shard)
+		if err != nil {
+			err = fmt.Errorf("GetTabletMapForShard(%s, %s) failed: %w", req.Keyspace, shard, err)
+			return nil, err
+		}
+		for _, ti := range tabletMap {
+			tablets = append(tablets, ti)
+		}
+	}
+	sort.Slice(tablets, func(i, j int) bool {
+		return topoproto.TabletAliasString(tablets[i].Alias) < topoproto.TabletAliasString(tablets[j].Alias)
+	})
+
+	var (
+		wg      sync.WaitGroup
+		sema    *semaphore.Weighted
+		results = make([]*vtctldatapb.ReloadTabletConfigResult, len(tablets))
+	)
+	if req.Concurrency > 0 {
+		sema = semaphore.NewWeighted(int64(req.Concurrency))
+	}
+
+	for i, ti := range tablets {
+		wg.Add(1)
+		go func(i int, ti *topo.TabletInfo) {
+			defer wg.Done()
+
+			result := &vtctldatapb.ReloadTabletConfigResult{TabletAlias: ti.Alias}
+			results[i] = result
+
+			if sema != nil {
+				if err := sema.Acquire(ctx, 1); err != nil {
+					result.Error = err.Error()
+					return
+				}
+				defer sema.Release(1)
+			}
+
+			hash, err := s.tmc.ReloadTabletConfig(ctx, ti.Tablet, req.Category)
+			if err != nil {
+				log.Warningf("Failed to reload %v config on %v: %v", req.Category, topoproto.TabletAliasString(ti.Alias), err)
+				result.Error = err.Error()
+				return
+			}
+			result.ConfigHash = hash
+		}(i, ti)
+	}
+
+	wg.Wait()
+
+	majority := majorityConfigHash(results)
+	for _, result := range results {
+		result.Mismatch = result.Error == "" && result.ConfigHash != majority
+	}
+
+	return &vtctldatapb.ReloadTabletConfigResponse{
+		Results:      results,
+		MajorityHash: majority,
+	}, nil
+}
+
+// majorityConfigHash returns the config hash reported by the most tablets, or
+// an empty string if there is a tie between hashes.
+func majorityConfigHash(results []*vtctldatapb.ReloadTabletConfigResult) string {
+	counts := map[string]int{}
+	for _, result := range results {
+		if result.Error == "" {
+			counts[result.ConfigHash]++
+		}
+	}
+
+	var (
+		majority string
+		most     int
+	)
+	for hash, count := range counts {
+		switch {
+		case count > most:
+			majority, most = hash, count
+		case count == most:
+			majority = ""
+		}
+	}
+
+	return majority
+}
+
+// RemoveBackup is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) RemoveBackup(ctx context.Context, req *vtctldatapb.RemoveBackupRequest) (resp *vtctldatapb.RemoveBackupResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.RemoveBackup")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	bucket := fmt.Sprintf("%v/%v", req.Keyspace, req.Shard)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("bucket", bucket)
+	span.Annotate("backup_name", req.Name)
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
 
 	if err = bs.RemoveBackup(ctx, bucket, req.Name); err != nil {
 		return nil, err
@@ -3716,6 +4177,9 @@ func (s *VtctldServer) RestoreFromBackup(req *vtctldatapb.RestoreFromBackupReque
 	if !backupTime.IsZero() {
 		span.Annotate("backup_timestamp", backupTime.Format(mysqlctl.BackupTimestampFormat))
 	}
+	if req.BackupName != "" {
+		span.Annotate("backup_name", req.BackupName)
+	}
 
 	ti, err := s.ts.GetTablet(ctx, req.TabletAlias)
 	if err != nil {
@@ -3731,13 +4195,14 @@ func (s *VtctldServer) RestoreFromBackup(req *vtctldatapb.RestoreFromBackupReque
 		RestoreToTimestamp:   req.RestoreToTimestamp,
 		DryRun:               req.DryRun,
 		AllowedBackupEngines: req.AllowedBackupEngines,
+		BackupName:           req.BackupName,
 	}
 	logStream, err := s.tmc.RestoreFromBackup(ctx, ti.Tablet, r)
 	if err != nil {
 		return err
 	}
 
-	logger := logutil.NewConsoleLogger()
+	logger := logutil.NewConsoleLoggerFromFlags()
 
 	for {
 		var event *logutilpb.Event
@@ -4026,19 +4491,22 @@ func (s *VtctldServer) ShardReplicationFix(ctx context.Context, req *vtctldatapb
 	span.Annotate("shard", req.Shard)
 	span.Annotate("cell", req.Cell)
 
-	problem, err := topo.FixShardReplication(ctx, s.ts, logutil.NewConsoleLogger(), req.Cell, req.Keyspace, req.Shard)
+	result, err := topotools.FixShardReplication(ctx, s.ts, logutil.NewConsoleLogger(), req.Cell, req.Keyspace, req.Shard)
 	if err != nil {
 		return nil, err
 	}
 
-	if problem != nil {
-		span.Annotate("problem_tablet", topoproto.TabletAliasString(problem.TabletAlias))
-		span.Annotate("problem_type", strings.ToLower(topoproto.ShardReplicationErrorTypeString(problem.Type)))
-	}
+	span.Annotate("added", len(result.Added))
+	span.Annotate("removed", len(result.Removed))
 
-	return &vtctldatapb.ShardReplicationFixResponse{
-		Error: problem,
-	}, nil
+	resp = &vtctldatapb.ShardReplicationFixResponse{
+		Added:   result.Added,
+		Removed: result.Removed,
+	}
+	if len(result.Removed) > 0 {
+		resp.Error = result.Removed[0]
+	}
+	return resp, nil
 }
 
 // ShardReplicationPositions is part of the vtctldservicepb.VtctldServer interface.
@@ -4500,6 +4968,35 @@ func (s *VtctldServer) TabletExternallyReparented(ctx context.Context, req *vtct
 	return resp, nil
 }
 
+// TopoGet is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) TopoGet(ctx context.Context, req *vtctldatapb.TopoGetRequest) (resp *vtctldatapb.TopoGetResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.TopoGet")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("path", req.Path)
+	span.Annotate("decode", req.Decode)
+	span.Annotate("as_json", req.AsJson)
+
+	resp, err = getTopoFile(ctx, s.ts, req.Path, req.Decode, req.AsJson)
+	return resp, err
+}
+
+// TopoList is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) TopoList(ctx context.Context, req *vtctldatapb.TopoListRequest) (resp *vtctldatapb.TopoListResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.TopoList")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("path", req.Path)
+	span.Annotate("page_size", req.PageSize)
+
+	resp, err = listTopoDir(ctx, s.ts, req.Path, req.PageToken, int(req.PageSize))
+	return resp, err
+}
+
 // UpdateCellInfo is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) UpdateCellInfo(ctx context.Context, req *vtctldatapb.UpdateCellInfoRequest) (resp *vtctldatapb.UpdateCellInfoResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.UpdateCellInfo")
@@ -4555,29 +5052,61 @@ func (s *VtctldServer) UpdateCellsAlias(ctx context.Context, req *vtctldatapb.Up
 
 	span.Annotate("cells_alias", req.Name)
 	span.Annotate("cells_alias_cells", strings.Join(req.CellsAlias.Cells, ","))
+	span.Annotate("force", req.Force)
+	span.Annotate("dry_run", req.DryRun)
 
 	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
 	defer cancel()
 
-	var updatedCa *topodatapb.CellsAlias
-	err = s.ts.UpdateCellsAlias(ctx, req.Name, func(ca *topodatapb.CellsAlias) error {
-		defer func() { updatedCa = ca.CloneVT() }()
+	if err = validateCellsAlias(ctx, s.ts, req.Name, req.CellsAlias.Cells, req.Force); err != nil {
+		return nil, err
+	}
+	current, err := s.ts.GetCellsAlias(ctx, req.Name, true)
+	switch {
+	case err == nil:
+	case topo.IsErrType(err, topo.NoNode):
+		// UpdateCellsAlias creates the alias.
+		current = &topodatapb.CellsAlias{}
+	default:
+		return nil, err
+	}
+	changes, err := cellsAliasRoutingChanges(ctx, s.ts, current.Cells, req.CellsAlias.Cells)
+	if err != nil {
+		return nil, err
+	}
+	oldCells, newCells := sets.New(current.Cells...), sets.New(req.CellsAlias.Cells...)
+	resp = &vtctldatapb.UpdateCellsAliasResponse{
+		Name:           req.Name,
+		CellsAlias:     req.CellsAlias,
+		AddedCells:     sets.List(newCells.Difference(oldCells)),
+		RemovedCells:   sets.List(oldCells.Difference(newCells)),
+		RoutingChanges: changes,
+	}
+	if req.DryRun {
+		return resp, nil
+	}
+
+	err = s.ts.UpdateCellsAliasWithOptions(ctx, req.Name, func(ca *topodatapb.CellsAlias) error {
+		defer func() { resp.CellsAlias = ca.CloneVT() }()
 
 		ca.Cells = req.CellsAlias.Cells
 		return nil
-	})
+	}, &topo.CellsAliasOptions{AllowOverlap: req.Force})
 	if err != nil {
 		return nil, err
 	}
 
-	return &vtctldatapb.UpdateCellsAliasResponse{
-		Name:       req.Name,
-		CellsAlias: updatedCa,
-	}, nil
+	return resp, nil
 }
 
 // Validate is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) Validate(ctx context.Context, req *vtctldatapb.ValidateRequest) (resp *vtctldatapb.ValidateResponse, err error) {
+	return s.ValidateWithRecorder(ctx, req, consoleValidationRecorder{})
+}
+
+// ValidateWithRecorder is Validate, which also records what it checks and
+// finds in rec.
+func (s *VtctldServer) ValidateWithRecorder(ctx context.Context, req *vtctldatapb.ValidateRequest, rec ValidationRecorder) (resp *vtctldatapb.ValidateResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.Validate")
 	defer span.Finish()
 
@@ -4586,12 +5115,17 @@ func (s *VtctldServer) Validate(ctx context.Context, req *vtctldatapb.ValidateRe
 	span.Annotate("ping_tablets", req.PingTablets)
 
 	resp = &vtctldatapb.ValidateResponse{}
+	results := newValidationResults(rec, "", "")
+	defer func() {
+		resp.Results = results.get()
+	}()
+
 	getKeyspacesCtx, getKeyspacesCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
 	defer getKeyspacesCancel()
 
 	keyspaces, err := s.ts.GetKeyspaces(getKeyspacesCtx)
 	if err != nil {
-		resp.Results = append(resp.Results, fmt.Sprintf("GetKeyspaces failed: %v", err))
+		results.topologyErrorf("GetKeyspaces failed: %v", err)
 		return resp, nil
 	}
 
@@ -4614,9 +5148,7 @@ func (s *VtctldServer) Validate(ctx context.Context, req *vtctldatapb.ValidateRe
 				getShardNamesCancel() // don't defer in a loop
 
 				if err != nil {
-					m.Lock()
-					resp.Results = append(resp.Results, fmt.Sprintf("TopologyServer.GetShardNames(%v) failed: %v", keyspace, err))
-					m.Unlock()
+					results.topologyErrorf("TopologyServer.GetShardNames(%v) failed: %v", keyspace, err)
 					continue
 				}
 
@@ -4626,9 +5158,7 @@ func (s *VtctldServer) Validate(ctx context.Context, req *vtctldatapb.ValidateRe
 					findAllTabletAliasesCancel() // don't defer in a loop
 
 					if err != nil {
-						m.Lock()
-						resp.Results = append(resp.Results, fmt.Sprintf("TopologyServer.FindAllTabletAliasesInShard(%v/%v) failed: %v", keyspace, shard, err))
-						m.Unlock()
+						results.topologyErrorf("TopologyServer.FindAllTabletAliasesInShard(%v/%v) failed: %v", keyspace, shard, err)
 						continue
 					}
 
@@ -4644,9 +5174,7 @@ func (s *VtctldServer) Validate(ctx context.Context, req *vtctldatapb.ValidateRe
 				getTabletsByCellCancel() // don't defer in a loop
 
 				if err != nil {
-					m.Lock()
-					resp.Results = append(resp.Results, fmt.Sprintf("TopologyServer.GetTabletsByCell(%v) failed: %v", cell, err))
-					m.Unlock()
+					results.topologyErrorf("TopologyServer.GetTabletsByCell(%v) failed: %v", cell, err)
 					continue
 				}
 
@@ -4664,11 +5192,9 @@ func (s *VtctldServer) Validate(ctx context.Context, req *vtctldatapb.ValidateRe
 						ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
 						defer cancel()
 
+						rec.RecordChecked(ValidationCheckTabletRecords, key)
 						if err := topo.Validate(ctx, s.ts, alias); err != nil {
-							m.Lock()
-							defer m.Unlock()
-
-							resp.Results = append(resp.Results, fmt.Sprintf("topo.Validate(%v) failed: %v", key, err))
+							results.errorf(ValidationCheckTabletRecords, key, "topo.Validate(%v) failed: %v", key, err)
 							return
 						}
 
@@ -4687,17 +5213,19 @@ func (s *VtctldServer) Validate(ctx context.Context, req *vtctldatapb.ValidateRe
 		wg.Add(1)
 		go func(keyspace string) {
 			defer wg.Done()
-			keyspaceResp, err := s.ValidateKeyspace(ctx, &vtctldatapb.ValidateKeyspaceRequest{
+			keyspaceResp, err := s.ValidateKeyspaceWithRecorder(ctx, &vtctldatapb.ValidateKeyspaceRequest{
 				Keyspace:    keyspace,
 				PingTablets: req.PingTablets,
-			})
+			}, rec)
 
 			m.Lock()
 			defer m.Unlock()
 
 			if err != nil {
+				keyspaceResults := newValidationResults(rec, keyspace, "")
+				keyspaceResults.topologyErrorf("failed to validate: %v", err)
 				resp.ResultsByKeyspace[keyspace] = &vtctldatapb.ValidateKeyspaceResponse{
-					Results: []string{fmt.Sprintf("failed to validate: %v", err)},
+					Results: keyspaceResults.get(),
 				}
 				return
 			}
@@ -4712,6 +5240,12 @@ func (s *VtctldServer) Validate(ctx context.Context, req *vtctldatapb.ValidateRe
 
 // ValidateKeyspace is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ValidateKeyspace(ctx context.Context, req *vtctldatapb.ValidateKeyspaceRequest) (resp *vtctldatapb.ValidateKeyspaceResponse, err error) {
+	return s.ValidateKeyspaceWithRecorder(ctx, req, consoleValidationRecorder{})
+}
+
+// ValidateKeyspaceWithRecorder is ValidateKeyspace, which also records what
+// it checks and finds in rec.
+func (s *VtctldServer) ValidateKeyspaceWithRecorder(ctx context.Context, req *vtctldatapb.ValidateKeyspaceRequest, rec ValidationRecorder) (resp *vtctldatapb.ValidateKeyspaceResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ValidateKeyspace")
 	defer span.Finish()
 
@@ -4719,14 +5253,21 @@ func (s *VtctldServer) ValidateKeyspace(ctx context.Context, req *vtctldatapb.Va
 
 	span.Annotate("keyspace", req.Keyspace)
 	span.Annotate("ping_tablets", req.PingTablets)
+	span.Annotate("check_srv_keyspace", req.CheckSrvKeyspace)
 
 	resp = &vtctldatapb.ValidateKeyspaceResponse{}
+	results := newValidationResults(rec, req.Keyspace, "")
+	defer func() {
+		resp.Results = results.get()
+	}()
+
+	rec.RecordKeyspace(req.Keyspace)
 	getShardNamesCtx, getShardNamesCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
 	defer getShardNamesCancel()
 
 	shards, err := s.ts.GetShardNames(getShardNamesCtx, req.Keyspace)
 	if err != nil {
-		resp.Results = append(resp.Results, fmt.Sprintf("TopologyServer.GetShardNames(%v) failed: %v", req.Keyspace, err))
+		results.topologyErrorf("TopologyServer.GetShardNames(%v) failed: %v", req.Keyspace, err)
 		err = nil
 		return resp, err
 	}
@@ -4741,17 +5282,17 @@ func (s *VtctldServer) ValidateKeyspace(ctx context.Context, req *vtctldatapb.Va
 		wg.Add(1)
 		go func(shard string) {
 			defer wg.Done()
-			shardResp, err := s.ValidateShard(ctx, &vtctldatapb.ValidateShardRequest{
+			shardResp, err := s.ValidateShardWithRecorder(ctx, &vtctldatapb.ValidateShardRequest{
 				Keyspace:    req.Keyspace,
 				Shard:       shard,
 				PingTablets: req.PingTablets,
-			})
+			}, rec)
 
 			m.Lock()
 			defer m.Unlock()
 
 			if err != nil {
-				resp.Results = append(resp.Results, fmt.Sprintf("error validating shard %v/%v: %v", req.Keyspace, shard, err))
+				results.topologyErrorf("error validating shard %v/%v: %v", req.Keyspace, shard, err)
 				return
 			}
 
@@ -4760,148 +5301,472 @@ func (s *VtctldServer) ValidateKeyspace(ctx context.Context, req *vtctldatapb.Va
 	}
 
 	wg.Wait()
+
+	resultsByShard := s.validateReplicationGraphs(ctx, req.Keyspace, shards, results)
+	for shard, shardResults := range resultsByShard {
+		if len(shardResults.get()) == 0 {
+			continue
+		}
+		shardResp, ok := resp.ResultsByShard[shard]
+		if !ok {
+			shardResp = &vtctldatapb.ValidateShardResponse{}
+			resp.ResultsByShard[shard] = shardResp
+		}
+		shardResp.Results = append(shardResp.Results, shardResults.get()...)
+	}
+	s.validateSrvKeyspacePartitions(ctx, req.Keyspace, results)
+	if req.CheckSrvKeyspace {
+		s.validateSrvKeyspaces(ctx, req.Keyspace, results)
+	}
 	return resp, err
 }
 
-// ValidatePermissionsKeyspace validates that all the permissions are the
-// same in a keyspace.
-func (s *VtctldServer) ValidatePermissionsKeyspace(ctx context.Context, req *vtctldatapb.ValidatePermissionsKeyspaceRequest) (resp *vtctldatapb.ValidatePermissionsKeyspaceResponse, err error) {
-	span, ctx := trace.NewSpan(ctx, "VtctldServer.ValidatePermissionsKeyspace")
-	defer span.Finish()
+// validateSrvKeyspacePartitions checks that, in every cell that has a
+// SrvKeyspace for the keyspace, the shards serving each tablet type cover
+// the whole keyspace id space, so that no row is left without a shard to
+// route it to.
+func (s *VtctldServer) validateSrvKeyspacePartitions(ctx context.Context, keyspace string, results *validationResults) {
+	getCellInfoNamesCtx, getCellInfoNamesCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer getCellInfoNamesCancel()
 
-	defer panicHandler(&err)
+	cells, err := s.ts.GetCellInfoNames(getCellInfoNamesCtx)
+	if err != nil {
+		results.topologyErrorf("TopologyServer.GetCellInfoNames() failed: %v", err)
+		return
+	}
 
-	span.Annotate("keyspace", req.Keyspace)
-	span.Annotate("shards", req.Shards)
+	for _, cell := range cells {
+		getSrvKeyspaceCtx, getSrvKeyspaceCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+		srvKeyspace, err := s.ts.GetSrvKeyspace(getSrvKeyspaceCtx, cell, keyspace)
+		getSrvKeyspaceCancel() // don't defer in a loop
 
-	var shards []string
-	if len(req.Shards) != 0 {
-		// If the user has specified a list of specific shards, we'll use that.
-		shards = req.Shards
-	} else {
-		// Validate all of the shards.
-		shards, err = s.ts.GetShardNames(ctx, req.Keyspace)
-		if err != nil {
-			return nil, err
+		switch {
+		case topo.IsErrType(err, topo.NoNode):
+			continue
+		case err != nil:
+			results.topologyErrorf("TopologyServer.GetSrvKeyspace(%v, %v) failed: %v", cell, keyspace, err)
+			continue
+		}
+
+		object := srvKeyspaceObject(keyspace, cell)
+		results.rec.RecordChecked(ValidationCheckServingGraph, object)
+		for _, gap := range topotools.SrvKeyspacePartitionGaps(srvKeyspace) {
+			results.errorf(ValidationCheckServingGraph, object, "SrvKeyspace of keyspace %v in cell %v has a gap: %v", keyspace, cell, gap)
 		}
 	}
+}
+
+// validateSrvKeyspaces compares the SrvKeyspace of the keyspace in each
+// cell with the one RebuildKeyspaceGraph would compute from the shard
+// records.
+func (s *VtctldServer) validateSrvKeyspaces(ctx context.Context, keyspace string, results *validationResults) {
+	getCellInfoNamesCtx, getCellInfoNamesCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer getCellInfoNamesCancel()
 
-	if len(shards) == 0 {
-		return nil, fmt.Errorf("no shards found in keyspace %s", req.Keyspace)
+	cells, err := s.ts.GetCellInfoNames(getCellInfoNamesCtx)
+	if err != nil {
+		results.topologyErrorf("TopologyServer.GetCellInfoNames() failed: %v", err)
+		return
 	}
-	sort.Strings(shards)
 
-	// Find the reference permissions using the first shard's primary.
-	si, err := s.ts.GetShard(ctx, req.Keyspace, shards[0])
+	findings, err := topotools.ValidateSrvKeyspaces(ctx, s.ts, keyspace, cells)
 	if err != nil {
-		return nil, err
+		results.topologyErrorf("topotools.ValidateSrvKeyspaces(%v) failed: %v", keyspace, err)
+		return
+	}
+
+	for _, cell := range cells {
+		results.rec.RecordChecked(ValidationCheckServingGraph, srvKeyspaceObject(keyspace, cell))
 	}
-	if !si.HasPrimary() {
-		return nil, fmt.Errorf("no primary tablet in shard %s/%s", req.Keyspace, shards[0])
+	for _, finding := range findings {
+		object := srvKeyspaceObject(keyspace, finding.Cell)
+		if finding.Warning {
+			results.warningf(ValidationCheckServingGraph, object, "%s", finding.Message)
+			continue
+		}
+		results.errorf(ValidationCheckServingGraph, object, "%s", finding.Message)
 	}
-	referenceAlias := si.PrimaryAlias
-	log.Infof("Gathering permissions for reference primary %s", topoproto.TabletAliasString(referenceAlias))
-	pres, err := s.GetPermissions(ctx, &vtctldatapb.GetPermissionsRequest{
-		TabletAlias: si.PrimaryAlias,
-	})
+}
+
+// validateReplicationGraphs checks the replication graphs of the shards of a
+// keyspace, in all cells, against its tablet records. It reports the tablets
+// that are in none of the graphs of their shard, which the operations going
+// through the graphs don't see, and the graph entries that don't match a
+// tablet of the shard. ShardReplicationFix fixes both. The
+// findings about a shard are returned by shard, the others are added to
+// results.
+func (s *VtctldServer) validateReplicationGraphs(ctx context.Context, keyspace string, shards []string, results *validationResults) (resultsByShard map[string]*validationResults) {
+	resultsByShard = make(map[string]*validationResults, len(shards))
+	for _, shard := range shards {
+		resultsByShard[shard] = newValidationResults(results.rec, keyspace, shard)
+		results.rec.RecordChecked(ValidationCheckReplicationGraph, topoproto.KeyspaceShardString(keyspace, shard))
+	}
+	fix := func(cell, shard string) string {
+		return fmt.Sprintf("run 'ShardReplicationFix %v %v/%v' to fix it", cell, keyspace, shard)
+	}
+
+	getCellInfoNamesCtx, getCellInfoNamesCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer getCellInfoNamesCancel()
+
+	cells, err := s.ts.GetCellInfoNames(getCellInfoNamesCtx)
 	if err != nil {
-		return nil, err
+		results.topologyErrorf("TopologyServer.GetCellInfoNames() failed: %v", err)
+		return resultsByShard
+	}
+
+	// tablets has the tablet records of the keyspace in the cells that could
+	// be listed. The graphs of the other cells can't be checked.
+	tablets := make(map[string]*topo.TabletInfo)
+	listed := sets.New[string]()
+	for _, cell := range cells {
+		getTabletsByCellCtx, getTabletsByCellCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+		cellTablets, err := s.ts.GetTabletsByCell(getTabletsByCellCtx, cell, &topo.GetTabletsByCellOptions{
+			KeyspaceShard: &topo.KeyspaceShard{Keyspace: keyspace},
+		})
+		getTabletsByCellCancel() // don't defer in a loop
+
+		if err != nil {
+			results.topologyErrorf("TopologyServer.GetTabletsByCell(%v) failed: %v", cell, err)
+			continue
+		}
+
+		listed.Insert(cell)
+		for _, ti := range cellTablets {
+			if ti.Keyspace == keyspace {
+				tablets[topoproto.TabletAliasString(ti.Alias)] = ti
+			}
+		}
 	}
-	referencePermissions := pres.Permissions
 
-	// Then diff the first/reference tablet with all the others.
-	eg, egctx := errgroup.WithContext(ctx)
+	// inGraph has the aliases in the graphs of each shard, in any cell.
+	inGraph := make(map[string]sets.Set[string], len(shards))
 	for _, shard := range shards {
-		eg.Go(func() error {
-			aliases, err := s.ts.FindAllTabletAliasesInShard(egctx, req.Keyspace, shard)
-			if err != nil {
-				return err
+		inGraph[shard] = sets.New[string]()
+		shardResults := resultsByShard[shard]
+		object := topoproto.KeyspaceShardString(keyspace, shard)
+		for _, cell := range cells {
+			getShardReplicationCtx, getShardReplicationCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+			sri, err := s.ts.GetShardReplication(getShardReplicationCtx, cell, keyspace, shard)
+			getShardReplicationCancel() // don't defer in a loop
+
+			switch {
+			case topo.IsErrType(err, topo.NoNode):
+				continue
+			case err != nil:
+				shardResults.topologyErrorf("TopologyServer.GetShardReplication(%v, %v, %v) failed: %v", cell, keyspace, shard, err)
+				continue
 			}
-			for _, alias := range aliases {
-				if topoproto.TabletAliasEqual(alias, si.PrimaryAlias) {
+
+			for _, node := range sri.Nodes {
+				key := topoproto.TabletAliasString(node.TabletAlias)
+				inGraph[shard].Insert(key)
+				if !listed.Has(cell) {
 					continue
 				}
-				log.Infof("Gathering permissions for %s", topoproto.TabletAliasString(alias))
-				presp, err := s.GetPermissions(ctx, &vtctldatapb.GetPermissionsRequest{
-					TabletAlias: alias,
-				})
-				if err != nil {
-					return err
-				}
 
-				log.Infof("Diffing permissions between %s and %s", topoproto.TabletAliasString(referenceAlias),
-					topoproto.TabletAliasString(alias))
-				er := &concurrency.AllErrorRecorder{}
-				tmutils.DiffPermissions(topoproto.TabletAliasString(referenceAlias), referencePermissions,
-					topoproto.TabletAliasString(alias), presp.Permissions, er)
-				if er.HasErrors() {
-					return er.Error()
+				ti, ok := tablets[key]
+				if !ok {
+					// The tablet may belong to another keyspace.
+					getTabletCtx, getTabletCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+					ti, err = s.ts.GetTablet(getTabletCtx, node.TabletAlias)
+					getTabletCancel() // don't defer in a loop
+
+					switch {
+					case topo.IsErrType(err, topo.NoNode):
+						shardResults.errorf(ValidationCheckReplicationGraph, object, "replication graph of shard %v/%v in cell %v has tablet %v, which does not exist, %v", keyspace, shard, cell, key, fix(cell, shard))
+						continue
+					case err != nil:
+						shardResults.topologyErrorf("TopologyServer.GetTablet(%v) failed: %v", key, err)
+						continue
+					}
+				}
+				// A tablet of the shard in the graph of another cell is
+				// reported by ValidateShard.
+				if ti.Keyspace != keyspace || ti.Shard != shard {
+					shardResults.errorf(ValidationCheckReplicationGraph, object, "replication graph of shard %v/%v in cell %v has tablet %v, which belongs to shard %v/%v in cell %v, %v", keyspace, shard, cell, key, ti.Keyspace, ti.Shard, ti.Alias.Cell, fix(cell, shard))
 				}
 			}
-			return nil
-		})
+		}
+	}
+
+	keys := make([]string, 0, len(tablets))
+	for key := range tablets {
+		keys = append(keys, key)
 	}
-	if err := eg.Wait(); err != nil {
-		return nil, fmt.Errorf("permissions diffs: %v", err)
+	sort.Strings(keys)
+	for _, key := range keys {
+		ti := tablets[key]
+		shardAliases, ok := inGraph[ti.Shard]
+		switch {
+		case !ok:
+			results.errorf(ValidationCheckTabletRecords, key, "tablet %v is in shard %v/%v, which does not exist", key, keyspace, ti.Shard)
+		case !shardAliases.Has(key):
+			resultsByShard[ti.Shard].errorf(ValidationCheckReplicationGraph, topoproto.KeyspaceShardString(keyspace, ti.Shard), "tablet %v of shard %v/%v is in no replication graph, %v", key, keyspace, ti.Shard, fix(ti.Alias.Cell, ti.Shard))
+		}
 	}
 
-	return &vtctldatapb.ValidatePermissionsKeyspaceResponse{}, nil
+	return resultsByShard
 }
 
-// ValidateSchemaKeyspace is a part of the vtctlservicepb.VtctldServer interface.
-// It will diff the schema between the tablets in all shards -- or a subset if
-// any specific shards are specified -- within the keyspace.
-func (s *VtctldServer) ValidateSchemaKeyspace(ctx context.Context, req *vtctldatapb.ValidateSchemaKeyspaceRequest) (resp *vtctldatapb.ValidateSchemaKeyspaceResponse, err error) {
-	span, ctx := trace.NewSpan(ctx, "VtctldServer.ValidateSchemaKeyspace")
+// ValidatePermissionsKeyspace validates that all the permissions are the
+// same in a keyspace, or in a subset of its shards, as those of the primary
+// of the reference shard. The shards without a primary are skipped, and so
+// are the shards that serve no traffic unless IncludeNonServingShards is set.
+// The differences, and the tablets that could not be compared, fail the
+// request, unless ReportFindings is set to get them as findings instead.
+func (s *VtctldServer) ValidatePermissionsKeyspace(ctx context.Context, req *vtctldatapb.ValidatePermissionsKeyspaceRequest) (resp *vtctldatapb.ValidatePermissionsKeyspaceResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ValidatePermissionsKeyspace")
 	defer span.Finish()
 
 	defer panicHandler(&err)
 
 	span.Annotate("keyspace", req.Keyspace)
 	span.Annotate("shards", req.Shards)
-	keyspace := req.Keyspace
+	span.Annotate("reference_shard", req.ReferenceShard)
+	span.Annotate("include_non_serving", req.IncludeNonServing)
+	span.Annotate("include_non_serving_shards", req.IncludeNonServingShards)
+	span.Annotate("report_findings", req.ReportFindings)
 
-	resp = &vtctldatapb.ValidateSchemaKeyspaceResponse{
-		Results: []string{},
+	comparison := s.permissionsComparison(nil, req.IncludeNonServing, nil)
+	comparison.skipShardsWithoutPrimary = true
+	comparison.skipNonServingShards = !req.IncludeNonServingShards
+	_, findings, skipped, nonServing, err := compareKeyspaceTablets(ctx, s.ts, req.Keyspace, req.Shards, req.ReferenceShard, comparison)
+	if err != nil {
+		return nil, err
 	}
-
-	var shards []string
-	if len(req.Shards) != 0 {
-		// If the user has specified a list of specific shards, we'll use that.
-		shards = req.Shards
-	} else {
-		// Otherwise we look at all the shards in the keyspace.
-		shards, err = s.ts.GetShardNames(ctx, keyspace)
-		if err != nil {
-			resp.Results = append(resp.Results, fmt.Sprintf("TopologyServer.GetShardNames(%s) failed: %v", req.Keyspace, err))
-			err = nil
-			return resp, err
+	if !req.ReportFindings {
+		var diffs []string
+		for _, finding := range findings {
+			if finding.Severity != vtctldatapb.ValidationFinding_SKIPPED {
+				diffs = append(diffs, finding.Message)
+			}
+		}
+		if len(diffs) > 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "permissions diffs: %v", strings.Join(diffs, "; "))
 		}
 	}
 
-	resp.ResultsByShard = make(map[string]*vtctldatapb.ValidateShardResponse, len(shards))
+	return &vtctldatapb.ValidatePermissionsKeyspaceResponse{Findings: findings, SkippedTablets: skipped, NonServingShards: nonServing}, nil
+}
 
-	// Initiate individual shard results first
-	for _, shard := range shards {
-		resp.ResultsByShard[shard] = &vtctldatapb.ValidateShardResponse{
-			Results: []string{},
-		}
-	}
+// ValidatePermissionsKeyspaceStream is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ValidatePermissionsKeyspaceStream(req *vtctldatapb.ValidatePermissionsKeyspaceRequest, stream vtctlservicepb.Vtctld_ValidatePermissionsKeyspaceStreamServer) (err error) {
+	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.ValidatePermissionsKeyspaceStream")
+	defer span.Finish()
 
-	if req.IncludeVschema {
-		results, err2 := s.ValidateVSchema(ctx, &vtctldatapb.ValidateVSchemaRequest{
-			Keyspace:      keyspace,
-			Shards:        shards,
-			ExcludeTables: req.ExcludeTables,
-			IncludeViews:  req.IncludeViews,
-		})
-		if err2 != nil {
-			err = err2
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shards", req.Shards)
+	span.Annotate("reference_shard", req.ReferenceShard)
+	span.Annotate("include_non_serving", req.IncludeNonServing)
+	span.Annotate("include_non_serving_shards", req.IncludeNonServingShards)
+
+	return streamKeyspaceValidation(ctx, stream.Send, func(ctx context.Context, progress *validationProgress) error {
+		comparison := s.permissionsComparison(nil, req.IncludeNonServing, progress)
+		comparison.skipShardsWithoutPrimary = true
+		comparison.skipNonServingShards = !req.IncludeNonServingShards
+		_, _, _, _, err := compareKeyspaceTablets(ctx, s.ts, req.Keyspace, req.Shards, req.ReferenceShard, comparison)
+		return err
+	})
+}
+
+// ValidatePermissionsShard is part of the vtctlservicepb.VtctldServer interface.
+// It validates that the permissions of all the tablets of a shard are the
+// same as those of its primary, or of the reference tablet if one is given.
+func (s *VtctldServer) ValidatePermissionsShard(ctx context.Context, req *vtctldatapb.ValidatePermissionsShardRequest) (resp *vtctldatapb.ValidatePermissionsShardResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ValidatePermissionsShard")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("reference_tablet", topoproto.TabletAliasString(req.ReferenceTablet))
+	span.Annotate("ignore_users", req.IgnoreUsers)
+	span.Annotate("include_non_serving", req.IncludeNonServing)
+
+	if req.Keyspace == "" || req.Shard == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "keyspace and shard are required")
+	}
+
+	findings, skipped, err := compareShardTablets(ctx, s.ts, req.Keyspace, req.Shard, req.ReferenceTablet, s.permissionsComparison(req.IgnoreUsers, req.IncludeNonServing, nil))
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.ValidatePermissionsShardResponse{Findings: findings, SkippedTablets: skipped}, nil
+}
+
+// permissionsComparison compares the permissions of the tablets of a
+// keyspace, except those of ignoreUsers, reporting to progress if it is set.
+// The non-serving tablets are only compared if includeNonServing is set.
+func (s *VtctldServer) permissionsComparison(ignoreUsers []string, includeNonServing bool, progress *validationProgress) keyspaceComparison[*tabletmanagerdatapb.Permissions] {
+	return keyspaceComparison[*tabletmanagerdatapb.Permissions]{
+		name: "permissions",
+		get: func(ctx context.Context, alias *topodatapb.TabletAlias) (*tabletmanagerdatapb.Permissions, error) {
+			resp, err := s.GetPermissions(ctx, &vtctldatapb.GetPermissionsRequest{TabletAlias: alias})
+			if err != nil {
+				return nil, err
+			}
+			return withoutUsers(resp.Permissions, ignoreUsers), nil
+		},
+		diff: func(referenceAlias *topodatapb.TabletAlias, reference *tabletmanagerdatapb.Permissions, alias *topodatapb.TabletAlias, value *tabletmanagerdatapb.Permissions) []string {
+			er := concurrency.AllErrorRecorder{}
+			tmutils.DiffPermissions(topoproto.TabletAliasString(referenceAlias), reference, topoproto.TabletAliasString(alias), value, &er)
+			return er.ErrorStrings()
+		},
+		includeNonServing: includeNonServing,
+		progress:          progress,
+	}
+}
+
+// withoutUsers returns the permissions without the user, db, table and
+// column permissions of the given users.
+func withoutUsers(permissions *tabletmanagerdatapb.Permissions, users []string) *tabletmanagerdatapb.Permissions {
+	if len(users) == 0 || permissions == nil {
+		return permissions
+	}
+
+	filtered := &tabletmanagerdatapb.Permissions{}
+	for _, up := range permissions.UserPermissions {
+		if !slices.Contains(users, up.User) {
+			filtered.UserPermissions = append(filtered.UserPermissions, up)
+		}
+	}
+	for _, dp := range permissions.DbPermissions {
+		if !slices.Contains(users, dp.User) {
+			filtered.DbPermissions = append(filtered.DbPermissions, dp)
+		}
+	}
+	for _, tp := range permissions.TablePermissions {
+		if !slices.Contains(users, tp.User) {
+			filtered.TablePermissions = append(filtered.TablePermissions, tp)
+		}
+	}
+	for _, cp := range permissions.ColumnPermissions {
+		if !slices.Contains(users, cp.User) {
+			filtered.ColumnPermissions = append(filtered.ColumnPermissions, cp)
+		}
+	}
+	return filtered
+}
+
+// ValidateSchemaKeyspace is a part of the vtctlservicepb.VtctldServer interface.
+// It will diff the schema between the tablets in all shards -- or a subset if
+// any specific shards are specified -- within the keyspace, and the reference
+// tablet. The reference is the requested tablet or shard primary, or else the
+// primary of the first shard with the schema most shard primaries agree on.
+func (s *VtctldServer) ValidateSchemaKeyspace(ctx context.Context, req *vtctldatapb.ValidateSchemaKeyspaceRequest) (resp *vtctldatapb.ValidateSchemaKeyspaceResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ValidateSchemaKeyspace")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shards", req.Shards)
+	span.Annotate("include_non_serving", req.IncludeNonServing)
+	span.Annotate("include_non_serving_shards", req.IncludeNonServingShards)
+	span.Annotate("reference_shard", req.ReferenceShard)
+	span.Annotate("reference_tablet", topoproto.TabletAliasString(req.ReferenceTablet))
+
+	return s.validateSchemaKeyspace(ctx, req, nil)
+}
+
+// ValidateSchemaKeyspaceStream is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ValidateSchemaKeyspaceStream(req *vtctldatapb.ValidateSchemaKeyspaceRequest, stream vtctlservicepb.Vtctld_ValidateSchemaKeyspaceStreamServer) (err error) {
+	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.ValidateSchemaKeyspaceStream")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shards", req.Shards)
+	span.Annotate("include_non_serving", req.IncludeNonServing)
+	span.Annotate("include_non_serving_shards", req.IncludeNonServingShards)
+	span.Annotate("reference_shard", req.ReferenceShard)
+	span.Annotate("reference_tablet", topoproto.TabletAliasString(req.ReferenceTablet))
+
+	return streamKeyspaceValidation(ctx, stream.Send, func(ctx context.Context, progress *validationProgress) error {
+		_, err := s.validateSchemaKeyspace(ctx, req, progress)
+		return err
+	})
+}
+
+// validateSchemaKeyspace validates the schema of the keyspace for
+// ValidateSchemaKeyspace and ValidateSchemaKeyspaceStream, reporting each
+// result to progress as soon as it is found if progress is set.
+func (s *VtctldServer) validateSchemaKeyspace(ctx context.Context, req *vtctldatapb.ValidateSchemaKeyspaceRequest, progress *validationProgress) (resp *vtctldatapb.ValidateSchemaKeyspaceResponse, err error) {
+	keyspace := req.Keyspace
+
+	if req.ReferenceShard != "" && req.ReferenceTablet != nil {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "only one of reference_shard and reference_tablet can be set")
+		return nil, err
+	}
+
+	resp = &vtctldatapb.ValidateSchemaKeyspaceResponse{
+		Results: []string{},
+	}
+	// addKeyspaceResult records a result that isn't about a single shard.
+	addKeyspaceResult := func(severity vtctldatapb.ValidationFinding_Severity, message string) {
+		resp.Results = append(resp.Results, message)
+		progress.add(0, []*vtctldatapb.ValidationFinding{{Severity: severity, Message: message}})
+	}
+
+	var shards []string
+	if len(req.Shards) != 0 {
+		// If the user has specified a list of specific shards, we'll use that.
+		shards = req.Shards
+	} else {
+		// Otherwise we look at all the shards in the keyspace.
+		shards, err = s.ts.GetShardNames(ctx, keyspace)
+		if err != nil {
+			addKeyspaceResult(vtctldatapb.ValidationFinding_WARNING, fmt.Sprintf("TopologyServer.GetShardNames(%s) failed: %v", req.Keyspace, err))
+			err = nil
+			return resp, err
+		}
+	}
+	if !req.IncludeNonServingShards {
+		shards, resp.NonServingShards = splitNonServingShards(ctx, s.ts, keyspace, shards)
+		for _, shard := range resp.NonServingShards {
+			progress.add(0, []*vtctldatapb.ValidationFinding{{
+				Severity:   vtctldatapb.ValidationFinding_SKIPPED,
+				Shard:      shard,
+				Message:    fmt.Sprintf("shard %v/%v is not serving, skipped", keyspace, shard),
+				NotServing: true,
+			}})
+		}
+		if len(shards) == 0 {
+			addKeyspaceResult(vtctldatapb.ValidationFinding_WARNING, fmt.Sprintf("no serving shard in keyspace %v", keyspace))
+			return resp, nil
+		}
+	}
+
+	resp.ResultsByShard = make(map[string]*vtctldatapb.ValidateShardResponse, len(shards))
+
+	// Initiate individual shard results first
+	for _, shard := range shards {
+		resp.ResultsByShard[shard] = &vtctldatapb.ValidateShardResponse{
+			Results: []string{},
+		}
+	}
+
+	if req.IncludeVschema {
+		results, err2 := s.ValidateVSchema(ctx, &vtctldatapb.ValidateVSchemaRequest{
+			Keyspace:      keyspace,
+			Shards:        shards,
+			ExcludeTables: req.ExcludeTables,
+			IncludeViews:  req.IncludeViews,
+		})
+		if err2 != nil {
+			err = err2
 			return nil, err
 		}
 
 		if len(results.Results) > 0 {
-			resp.Results = append(resp.Results, results.Results...)
+			for _, result := range results.Results {
+				addKeyspaceResult(vtctldatapb.ValidationFinding_ERROR, result)
+			}
 			for shard, shardResults := range resp.ResultsByShard {
 				resp.ResultsByShard[shard].Results = append(resp.ResultsByShard[shard].Results, shardResults.Results...)
 			}
@@ -4911,94 +5776,280 @@ func (s *VtctldServer) ValidateSchemaKeyspace(ctx context.Context, req *vtctldat
 
 	sort.Strings(shards)
 
-	var (
-		referenceSchema *tabletmanagerdatapb.SchemaDefinition
-		referenceAlias  *topodatapb.TabletAlias
-		m               sync.Mutex
-		wg              sync.WaitGroup
-	)
-
 	r := &tabletmanagerdatapb.GetSchemaRequest{ExcludeTables: req.ExcludeTables, IncludeViews: req.IncludeViews}
-	for _, shard := range shards {
-		wg.Add(1)
-		go func(shard string) {
-			defer wg.Done()
+	referenceAlias, referenceSchema, reason, outliers, err := s.schemaKeyspaceReference(ctx, req, shards, r)
+	if err != nil {
+		return nil, err
+	}
+	resp.ReferenceTablet = referenceAlias
+	resp.ReferenceReason = reason
+	resp.OutlierShards = outliers
 
-			si, err := s.ts.GetShard(ctx, keyspace, shard)
+	// The shards are validated one at a time, in order, so that at most
+	// validateSchemaKeyspaceConcurrency schemas are held besides the
+	// reference.
+	// The tablets to compare are only known once their shard is validated,
+	// so the progress counts them as their shards are reached.
+	tablets := 0
+	progress.start(len(shards), tablets)
+	for _, shard := range shards {
+		addResult := func(severity vtctldatapb.ValidationFinding_Severity, errMessage string) {
+			resp.ResultsByShard[shard].Results = append(resp.ResultsByShard[shard].Results, errMessage)
+			resp.Results = append(resp.Results, errMessage)
+			progress.add(0, []*vtctldatapb.ValidationFinding{{Severity: severity, Shard: shard, Message: errMessage}})
+		}
 
-			m.Lock()
-			defer m.Unlock()
+		si, err := s.ts.GetShard(ctx, keyspace, shard)
+		if err != nil {
+			addResult(vtctldatapb.ValidationFinding_WARNING, fmt.Sprintf("GetShard(%v, %v) failed: %v", keyspace, shard, err))
+			continue
+		}
 
-			if err != nil {
-				errMessage := fmt.Sprintf("GetShard(%v, %v) failed: %v", keyspace, shard, err)
-				resp.ResultsByShard[shard].Results = append(resp.ResultsByShard[shard].Results, errMessage)
-				resp.Results = append(resp.Results, errMessage)
-				return
+		if !si.HasPrimary() {
+			if req.SkipNoPrimary {
+				progress.add(0, []*vtctldatapb.ValidationFinding{{
+					Severity: vtctldatapb.ValidationFinding_SKIPPED,
+					Shard:    shard,
+					Message:  fmt.Sprintf("no primary in shard %v/%v, skipped", keyspace, shard),
+				}})
+			} else {
+				addResult(vtctldatapb.ValidationFinding_WARNING, fmt.Sprintf("no primary in shard %v/%v", keyspace, shard))
 			}
+			continue
+		}
 
-			if !si.HasPrimary() {
-				if !req.SkipNoPrimary {
-					errMessage := fmt.Sprintf("no primary in shard %v/%v", keyspace, shard)
-					resp.ResultsByShard[shard].Results = append(resp.ResultsByShard[shard].Results, errMessage)
-					resp.Results = append(resp.Results, errMessage)
-				}
-				return
+		if referenceSchema == nil {
+			// No shard primary schema could be read, there is nothing to
+			// compare the tablets to.
+			addResult(vtctldatapb.ValidationFinding_WARNING, fmt.Sprintf("no reference schema to validate shard %v/%v: %v", keyspace, shard, reason))
+			continue
+		}
+
+		aliases, skipped, err := findShardTablets(ctx, s.ts, keyspace, shard, req.IncludeNonServing)
+		if err != nil {
+			addResult(vtctldatapb.ValidationFinding_WARNING, fmt.Sprintf("FindAllTabletAliasesInShard(%v, %v) failed: %v", keyspace, shard, err))
+			continue
+		}
+		resp.SkippedTablets = append(resp.SkippedTablets, skipped...)
+		progress.skip(skipped)
+		for _, alias := range aliases {
+			if !topoproto.TabletAliasEqual(referenceAlias, alias) {
+				tablets++
 			}
+		}
+		progress.start(len(shards), tablets)
 
-			if referenceSchema == nil {
-				referenceAlias = si.PrimaryAlias
-				referenceSchema, err = schematools.GetSchema(ctx, s.ts, s.tmc, referenceAlias, r)
+		// Each tablet records its differences in its own slot, and drops
+		// its schema once it is compared.
+		var (
+			aliasWg   sync.WaitGroup
+			aliasSem  = make(chan struct{}, validateSchemaKeyspaceConcurrency)
+			aliasErrs = make([]concurrency.AllErrorRecorder, len(aliases))
+		)
+		for i, alias := range aliases {
+			if topoproto.TabletAliasEqual(referenceAlias, alias) {
+				continue
+			}
+			aliasWg.Add(1)
+			aliasSem <- struct{}{}
+			go func(alias *topodatapb.TabletAlias, er *concurrency.AllErrorRecorder) {
+				defer aliasWg.Done()
+				defer func() { <-aliasSem }()
+				severity := vtctldatapb.ValidationFinding_ERROR
+				defer func() {
+					findings := make([]*vtctldatapb.ValidationFinding, 0, len(er.Errors))
+					for _, err := range er.Errors {
+						findings = append(findings, &vtctldatapb.ValidationFinding{
+							Severity:    severity,
+							Shard:       shard,
+							TabletAlias: alias,
+							Message:     err.Error(),
+						})
+					}
+					progress.add(1, findings)
+				}()
+				replicaSchema, err := schematools.GetSchema(ctx, s.ts, s.tmc, alias, r)
 				if err != nil {
+					severity = vtctldatapb.ValidationFinding_WARNING
+					er.RecordError(fmt.Errorf("GetSchema(%v, nil, %v, %v) failed: %v", alias, req.ExcludeTables, req.IncludeViews, err))
 					return
 				}
-			}
 
-			aliases, err := s.ts.FindAllTabletAliasesInShard(ctx, keyspace, shard)
-			if err != nil {
-				errMessage := fmt.Sprintf("FindAllTabletAliasesInShard(%v, %v) failed: %v", keyspace, shard, err)
-				resp.ResultsByShard[shard].Results = append(resp.ResultsByShard[shard].Results, errMessage)
-				resp.Results = append(resp.Results, errMessage)
-				return
+				tmutils.DiffSchema(topoproto.TabletAliasString(referenceAlias), referenceSchema, topoproto.TabletAliasString(alias), replicaSchema, er)
+			}(alias, &aliasErrs[i])
+		}
+		aliasWg.Wait()
+
+		// The tablet results were already reported to progress.
+		for i := range aliasErrs {
+			for _, err := range aliasErrs[i].Errors {
+				resp.ResultsByShard[shard].Results = append(resp.ResultsByShard[shard].Results, err.Error())
+				resp.Results = append(resp.Results, err.Error())
 			}
+		}
+	}
 
-			aliasWg := sync.WaitGroup{}
-			aliasErrs := concurrency.AllErrorRecorder{}
+	return resp, err
+}
 
-			for _, alias := range aliases {
-				if referenceAlias == alias {
-					continue
-				}
-				aliasWg.Add(1)
-				go func(alias *topodatapb.TabletAlias) {
-					defer aliasWg.Done()
-					replicaSchema, err := schematools.GetSchema(ctx, s.ts, s.tmc, alias, r)
-					if err != nil {
-						aliasErrs.RecordError(fmt.Errorf("GetSchema(%v, nil, %v, %v) failed: %v", alias, req.ExcludeTables, req.IncludeViews, err))
-						return
-					}
+// schemaKeyspaceReference returns the tablet ValidateSchemaKeyspace compares
+// the others to, its schema, and why it was chosen. If the request doesn't
+// name one, it is the primary of the first shard whose schema is shared by
+// the most shard primaries, and the shards whose primary has another schema
+// are returned as outliers. It returns a nil alias and schema if no shard
+// primary schema could be read.
+func (s *VtctldServer) schemaKeyspaceReference(ctx context.Context, req *vtctldatapb.ValidateSchemaKeyspaceRequest, shards []string, r *tabletmanagerdatapb.GetSchemaRequest) (*topodatapb.TabletAlias, *tabletmanagerdatapb.SchemaDefinition, string, []string, error) {
+	switch {
+	case req.ReferenceTablet != nil:
+		schema, err := schematools.GetSchema(ctx, s.ts, s.tmc, req.ReferenceTablet, r)
+		if err != nil {
+			return nil, nil, "", nil, fmt.Errorf("cannot get the schema of reference tablet %v: %w", topoproto.TabletAliasString(req.ReferenceTablet), err)
+		}
+		return req.ReferenceTablet, schemaForDiff(schema), fmt.Sprintf("%v is the requested reference tablet", topoproto.TabletAliasString(req.ReferenceTablet)), nil, nil
+	case req.ReferenceShard != "":
+		if !slices.Contains(shards, req.ReferenceShard) {
+			return nil, nil, "", nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "reference shard %v/%v is not one of the validated shards", req.Keyspace, req.ReferenceShard)
+		}
+		si, err := s.ts.GetShard(ctx, req.Keyspace, req.ReferenceShard)
+		if err != nil {
+			return nil, nil, "", nil, err
+		}
+		if !si.HasPrimary() {
+			return nil, nil, "", nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no primary in reference shard %v/%v", req.Keyspace, req.ReferenceShard)
+		}
+		schema, err := schematools.GetSchema(ctx, s.ts, s.tmc, si.PrimaryAlias, r)
+		if err != nil {
+			return nil, nil, "", nil, fmt.Errorf("cannot get the schema of reference tablet %v: %w", topoproto.TabletAliasString(si.PrimaryAlias), err)
+		}
+		return si.PrimaryAlias, schemaForDiff(schema), fmt.Sprintf("%v is the primary of the requested reference shard %v", topoproto.TabletAliasString(si.PrimaryAlias), req.ReferenceShard), nil, nil
+	}
 
-					tmutils.DiffSchema(topoproto.TabletAliasString(referenceAlias), referenceSchema, topoproto.TabletAliasString(alias), replicaSchema, &aliasErrs)
-				}(alias)
+	// Each group holds the shards whose primaries have the same schema. The
+	// shards whose primary or schema can't be read don't take part: their
+	// validation reports it.
+	type schemaGroup struct {
+		schema *tabletmanagerdatapb.SchemaDefinition
+		shards []string
+	}
+	var (
+		mu        sync.Mutex
+		groups    []*schemaGroup
+		primaries = make(map[string]*topodatapb.TabletAlias, len(shards))
+		wg        sync.WaitGroup
+		sem       = make(chan struct{}, validateSchemaKeyspaceConcurrency)
+	)
+	for _, shard := range shards {
+		si, err := s.ts.GetShard(ctx, req.Keyspace, shard)
+		if err != nil || !si.HasPrimary() {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(shard string, primary *topodatapb.TabletAlias) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			schema, err := schematools.GetSchema(ctx, s.ts, s.tmc, primary, r)
+			if err != nil {
+				return
 			}
-			aliasWg.Wait()
+			schema = schemaForDiff(schema)
 
-			if aliasErrs.HasErrors() {
-				for _, err := range aliasErrs.Errors {
-					errMessage := err.Error()
-					resp.ResultsByShard[shard].Results = append(resp.ResultsByShard[shard].Results, errMessage)
-					resp.Results = append(resp.Results, errMessage)
+			mu.Lock()
+			defer mu.Unlock()
+			primaries[shard] = primary
+			for _, group := range groups {
+				if len(tmutils.DiffSchemaToArray("group", group.schema, "primary", schema)) == 0 {
+					group.shards = append(group.shards, shard)
+					return
 				}
 			}
-		}(shard)
+			groups = append(groups, &schemaGroup{schema: schema, shards: []string{shard}})
+		}(shard, si.PrimaryAlias)
 	}
-
 	wg.Wait()
 
-	return resp, err
+	if len(groups) == 0 {
+		return nil, nil, "no shard primary schema could be read", nil, nil
+	}
+
+	// The shards are sorted, so a tie goes to the group with the first shard,
+	// whose primary used to be the reference.
+	for _, group := range groups {
+		sort.Strings(group.shards)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if len(groups[i].shards) != len(groups[j].shards) {
+			return len(groups[i].shards) > len(groups[j].shards)
+		}
+		return groups[i].shards[0] < groups[j].shards[0]
+	})
+
+	var outliers []string
+	for _, group := range groups[1:] {
+		outliers = append(outliers, group.shards...)
+	}
+	sort.Strings(outliers)
+
+	shard := groups[0].shards[0]
+	alias := primaries[shard]
+	agreeing, voting := len(groups[0].shards), len(primaries)
+	var reason string
+	switch {
+	case voting == 1:
+		reason = fmt.Sprintf("%v is the primary of shard %v, the only shard whose primary schema could be read", topoproto.TabletAliasString(alias), shard)
+	case agreeing == voting:
+		reason = fmt.Sprintf("%v is the primary of shard %v, and all %d shard primaries have the same schema", topoproto.TabletAliasString(alias), shard, voting)
+	case 2*agreeing > voting:
+		reason = fmt.Sprintf("%v is the primary of shard %v, which has the schema of the majority of the shard primaries (%d of %d)", topoproto.TabletAliasString(alias), shard, agreeing, voting)
+	default:
+		reason = fmt.Sprintf("no schema is shared by the majority of the shard primaries, %v is the primary of shard %v, the first shard with the most common schema (%d of %d)", topoproto.TabletAliasString(alias), shard, agreeing, voting)
+	}
+	return alias, groups[0].schema, reason, outliers, nil
 }
 
+// validateSchemaKeyspaceConcurrency is the number of tablet schemas
+// ValidateSchemaKeyspace fetches and compares at the same time.
+const validateSchemaKeyspaceConcurrency = 8
+
+// schemaForDiff returns a copy of the schema with only what
+// tmutils.DiffSchema compares, so that the reference schema of
+// ValidateSchemaKeyspace doesn't hold on to the columns and fields of every
+// table while all the tablets of the keyspace are compared with it.
+func schemaForDiff(sd *tabletmanagerdatapb.SchemaDefinition) *tabletmanagerdatapb.SchemaDefinition {
+	if sd == nil {
+		return nil
+	}
+
+	trimmed := &tabletmanagerdatapb.SchemaDefinition{
+		DatabaseSchema:   sd.DatabaseSchema,
+		TableDefinitions: make([]*tabletmanagerdatapb.TableDefinition, len(sd.TableDefinitions)),
+	}
+	for i, td := range sd.TableDefinitions {
+		trimmed.TableDefinitions[i] = &tabletmanagerdatapb.TableDefinition{
+			Name:   td.Name,
+			Schema: td.Schema,
+			Type:   td.Type,
+		}
+	}
+	return trimmed
+}
+
+// validateShardPingConcurrency is the number of tablets ValidateShard pings
+// at the same time.
+const validateShardPingConcurrency = 16
+
+// defaultServingStateWait is how long ValidateShard waits for the health
+// data of each tablet, when the request sets none.
+const defaultServingStateWait = 5 * time.Second
+
 // ValidateShard is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ValidateShard(ctx context.Context, req *vtctldatapb.ValidateShardRequest) (resp *vtctldatapb.ValidateShardResponse, err error) {
+	return s.ValidateShardWithRecorder(ctx, req, consoleValidationRecorder{})
+}
+
+// ValidateShardWithRecorder is ValidateShard, which also records what it
+// checks and finds in rec.
+func (s *VtctldServer) ValidateShardWithRecorder(ctx context.Context, req *vtctldatapb.ValidateShardRequest, rec ValidationRecorder) (resp *vtctldatapb.ValidateShardResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ValidateShard")
 	defer span.Finish()
 
@@ -5008,13 +6059,38 @@ func (s *VtctldServer) ValidateShard(ctx context.Context, req *vtctldatapb.Valid
 	span.Annotate("shard", req.Shard)
 	span.Annotate("ping_tablets", req.PingTablets)
 
+	pingTimeout, ok, err := protoutil.DurationFromProto(req.PingTimeout)
+	if err != nil {
+		err = vterrors.Wrapf(err, "unable to parse PingTimeout into a valid duration")
+		return nil, err
+	} else if !ok || pingTimeout <= 0 {
+		pingTimeout = tabletTimeout(ctx)
+	}
+	span.Annotate("ping_timeout", pingTimeout.String())
+	span.Annotate("include_serving_state", req.IncludeServingState)
+
+	servingStateWait, ok, err := protoutil.DurationFromProto(req.ServingStateWait)
+	if err != nil {
+		err = vterrors.Wrapf(err, "unable to parse ServingStateWait into a valid duration")
+		return nil, err
+	} else if !ok || servingStateWait <= 0 {
+		servingStateWait = defaultServingStateWait
+	}
+
 	resp = &vtctldatapb.ValidateShardResponse{}
+	results := newValidationResults(rec, req.Keyspace, req.Shard)
+	defer func() {
+		if resp != nil {
+			resp.Results = results.get()
+		}
+	}()
+
 	getShardCtx, getShardCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
 	defer getShardCancel()
 
 	si, err := s.ts.GetShard(getShardCtx, req.Keyspace, req.Shard)
 	if err != nil {
-		resp.Results = append(resp.Results, fmt.Sprintf("TopologyServer.GetShard(%v, %v) failed: %v", req.Keyspace, req.Shard, err))
+		results.topologyErrorf("TopologyServer.GetShard(%v, %v) failed: %v", req.Keyspace, req.Shard, err)
 		err = nil
 		return resp, err
 	}
@@ -5024,11 +6100,16 @@ func (s *VtctldServer) ValidateShard(ctx context.Context, req *vtctldatapb.Valid
 
 	aliases, err := s.ts.FindAllTabletAliasesInShard(findAllTabletAliasesCtx, req.Keyspace, req.Shard)
 	if err != nil {
-		resp.Results = append(resp.Results, fmt.Sprintf("TopologyServer.FindAllTabletAliasesInShard(%v, %v) failed: %v", req.Keyspace, req.Shard, err))
+		results.topologyErrorf("TopologyServer.FindAllTabletAliasesInShard(%v, %v) failed: %v", req.Keyspace, req.Shard, err)
 		err = nil
 		return resp, err
 	}
 
+	shardObject := topoproto.KeyspaceShardString(req.Keyspace, req.Shard)
+	rec.RecordShard(req.Keyspace, req.Shard, aliases)
+	rec.RecordChecked(ValidationCheckPrimary, shardObject)
+	rec.RecordChecked(ValidationCheckReplicationGraph, shardObject)
+
 	getTabletMapCtx, getTabletMapCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
 	defer getTabletMapCancel()
 	tabletMap, _ := s.ts.GetTabletMap(getTabletMapCtx, aliases, nil)
@@ -5038,7 +6119,7 @@ func (s *VtctldServer) ValidateShard(ctx context.Context, req *vtctldatapb.Valid
 		key := topoproto.TabletAliasString(alias)
 		ti, ok := tabletMap[key]
 		if !ok {
-			resp.Results = append(resp.Results, fmt.Sprintf("tablet %v not found in map", key))
+			results.errorf(ValidationCheckTabletRecords, key, "tablet %v not found in map", key)
 			continue
 		}
 
@@ -5047,20 +6128,32 @@ func (s *VtctldServer) ValidateShard(ctx context.Context, req *vtctldatapb.Valid
 			case nil:
 				primaryAlias = alias
 			default:
-				resp.Results = append(resp.Results, fmt.Sprintf("shard %v/%v already has primary %v but found other primary %v", req.Keyspace, req.Shard, topoproto.TabletAliasString(primaryAlias), key))
+				results.errorf(ValidationCheckPrimary, shardObject, "shard %v/%v already has primary %v but found other primary %v", req.Keyspace, req.Shard, topoproto.TabletAliasString(primaryAlias), key)
 			}
 		}
 	}
 
 	if primaryAlias == nil {
-		resp.Results = append(resp.Results, fmt.Sprintf("no primary for shard %v/%v", req.Keyspace, req.Shard))
+		results.errorf(ValidationCheckPrimary, shardObject, "no primary for shard %v/%v", req.Keyspace, req.Shard)
 	} else if !topoproto.TabletAliasEqual(si.PrimaryAlias, primaryAlias) {
-		resp.Results = append(resp.Results, fmt.Sprintf("primary mismatch for shard %v/%v: found %v, expected %v", si.Keyspace(), si.ShardName(), topoproto.TabletAliasString(primaryAlias), topoproto.TabletAliasString(si.PrimaryAlias)))
+		results.errorf(ValidationCheckPrimary, shardObject, "primary mismatch for shard %v/%v: found %v, expected %v", si.Keyspace(), si.ShardName(), topoproto.TabletAliasString(primaryAlias), topoproto.TabletAliasString(si.PrimaryAlias))
 	}
 
+	validateCellsCtx, validateCellsCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer validateCellsCancel()
+
+	if cellResults, err := topotools.ValidateShardReplicationCells(validateCellsCtx, s.ts, req.Keyspace, req.Shard, nil); err != nil {
+		results.topologyErrorf("topotools.ValidateShardReplicationCells(%v, %v) failed: %v", req.Keyspace, req.Shard, err)
+	} else {
+		for _, result := range cellResults {
+			results.errorf(ValidationCheckReplicationGraph, shardObject, "%s", result)
+		}
+	}
+
+	// The results of the checks run concurrently follow the others.
 	var (
-		wg      sync.WaitGroup
-		results = make(chan string, len(aliases))
+		wg           sync.WaitGroup
+		asyncResults = newValidationResults(rec, req.Keyspace, req.Shard)
 	)
 
 	for _, alias := range aliases {
@@ -5071,26 +6164,29 @@ func (s *VtctldServer) ValidateShard(ctx context.Context, req *vtctldatapb.Valid
 			ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
 			defer cancel()
 
+			key := topoproto.TabletAliasString(alias)
+			rec.RecordChecked(ValidationCheckTabletRecords, key)
 			if err := topo.Validate(ctx, s.ts, alias); err != nil {
-				results <- fmt.Sprintf("topo.Validate(%v) failed: %v", topoproto.TabletAliasString(alias), err)
+				asyncResults.errorf(ValidationCheckTabletRecords, key, "topo.Validate(%v) failed: %v", key, err)
 				return
 			}
 
-			log.Infof("tablet %v is valid", topoproto.TabletAliasString(alias))
+			log.Infof("tablet %v is valid", key)
 		}(alias)
 	}
 
 	if req.PingTablets {
-		validateReplication := func(ctx context.Context, si *topo.ShardInfo, tabletMap map[string]*topo.TabletInfo, results chan<- string) {
+		validateReplication := func(ctx context.Context, si *topo.ShardInfo, tabletMap map[string]*topo.TabletInfo) {
+			rec.RecordChecked(ValidationCheckReplication, shardObject)
 			if si.PrimaryAlias == nil {
-				results <- fmt.Sprintf("no primary in shard record %v/%v", si.Keyspace(), si.ShardName())
+				results.errorf(ValidationCheckPrimary, shardObject, "no primary in shard record %v/%v", si.Keyspace(), si.ShardName())
 				return
 			}
 
 			shardPrimaryAliasStr := topoproto.TabletAliasString(si.PrimaryAlias)
 			primaryTabletInfo, ok := tabletMap[shardPrimaryAliasStr]
 			if !ok {
-				results <- fmt.Sprintf("primary %v not in tablet map", shardPrimaryAliasStr)
+				results.errorf(ValidationCheckPrimary, shardObject, "primary %v not in tablet map", shardPrimaryAliasStr)
 				return
 			}
 
@@ -5099,12 +6195,12 @@ func (s *VtctldServer) ValidateShard(ctx context.Context, req *vtctldatapb.Valid
 
 			replicaList, err := s.tmc.GetReplicas(ctx, primaryTabletInfo.Tablet)
 			if err != nil {
-				results <- fmt.Sprintf("GetReplicas(%v) failed: %v", primaryTabletInfo, err)
+				results.warningf(ValidationCheckReplication, shardObject, "GetReplicas(%v) failed: %v", primaryTabletInfo, err)
 				return
 			}
 
 			if len(replicaList) == 0 {
-				results <- fmt.Sprintf("no replicas of tablet %v found", shardPrimaryAliasStr)
+				results.warningf(ValidationCheckReplication, shardObject, "no replicas of tablet %v found", shardPrimaryAliasStr)
 				return
 			}
 
@@ -5113,7 +6209,7 @@ func (s *VtctldServer) ValidateShard(ctx context.Context, req *vtctldatapb.Valid
 			for _, tablet := range tabletMap {
 				ip, err := topoproto.MySQLIP(tablet.Tablet)
 				if err != nil {
-					results <- fmt.Sprintf("could not resolve IP for tablet %s: %v", tablet.Tablet.MysqlHostname, err)
+					results.warningf(ValidationCheckReplication, shardObject, "could not resolve IP for tablet %s: %v", tablet.Tablet.MysqlHostname, err)
 					continue
 				}
 
@@ -5123,7 +6219,7 @@ func (s *VtctldServer) ValidateShard(ctx context.Context, req *vtctldatapb.Valid
 			// See if every replica is in the replication graph.
 			for _, replicaAddr := range replicaList {
 				if tabletIPMap[netutil.NormalizeIP(replicaAddr)] == nil {
-					results <- fmt.Sprintf("replica %v not in replication graph for shard %v/%v (mysql instance without vttablet?)", replicaAddr, si.Keyspace(), si.ShardName())
+					results.warningf(ValidationCheckReplication, shardObject, "replica %v not in replication graph for shard %v/%v (mysql instance without vttablet?)", replicaAddr, si.Keyspace(), si.ShardName())
 				}
 
 				replicaIPMap[netutil.NormalizeIP(replicaAddr)] = true
@@ -5137,150 +6233,200 @@ func (s *VtctldServer) ValidateShard(ctx context.Context, req *vtctldatapb.Valid
 
 				ip, err := topoproto.MySQLIP(tablet.Tablet)
 				if err != nil {
-					results <- fmt.Sprintf("could not resolve IP for tablet %s: %v", tablet.Tablet.MysqlHostname, err)
+					results.warningf(ValidationCheckReplication, shardObject, "could not resolve IP for tablet %s: %v", tablet.Tablet.MysqlHostname, err)
 					continue
 				}
 
 				if !replicaIPMap[netutil.NormalizeIP(ip)] {
-					results <- fmt.Sprintf("replica %v not replicating: %v replica list: %q", topoproto.TabletAliasString(tablet.Alias), ip, replicaList)
+					results.warningf(ValidationCheckReplication, shardObject, "replica %v not replicating: %v replica list: %q", topoproto.TabletAliasString(tablet.Alias), ip, replicaList)
 				}
 			}
 		}
-		pingTablets := func(ctx context.Context, tabletMap map[string]*topo.TabletInfo, results chan<- string) {
-			for alias, ti := range tabletMap {
+		pingTablets := func(ctx context.Context, tabletMap map[string]*topo.TabletInfo) {
+			tablets := make(chan *topo.TabletInfo, len(tabletMap))
+			for _, ti := range tabletMap {
+				tablets <- ti
+			}
+			close(tablets)
+
+			// Each ping has its own timeout, so that a few dead tablets
+			// only hold up their worker.
+			for range min(len(tabletMap), validateShardPingConcurrency) {
 				wg.Add(1)
-				go func(alias string, ti *topo.TabletInfo) {
+				go func() {
 					defer wg.Done()
 
-					ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
-					defer cancel()
+					for ti := range tablets {
+						alias := topoproto.TabletAliasString(ti.Alias)
+						rec.RecordChecked(ValidationCheckPing, alias)
+						ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+						start := time.Now()
+						err := s.tmc.Ping(ctx, ti.Tablet)
+						latency := time.Since(start)
+						cancel()
+
+						if err != nil {
+							rec.Logger().Infof("Ping(%v) failed after %v: %v", alias, latency, err)
+							asyncResults.warningf(ValidationCheckPing, alias, "Ping(%v) failed: %v tablet hostname: %v", alias, err, ti.Hostname)
+							continue
+						}
 
-					if err := s.tmc.Ping(ctx, ti.Tablet); err != nil {
-						results <- fmt.Sprintf("Ping(%v) failed: %v tablet hostname: %v", alias, err, ti.Hostname)
+						rec.Logger().Infof("Ping(%v) succeeded in %v", alias, latency)
 					}
-				}(alias, ti)
+				}()
 			}
 		}
 
-		validateReplication(ctx, si, tabletMap, results) // done synchronously
-		pingTablets(ctx, tabletMap, results)             // done async, using the waitgroup declared above in the main method body.
+		validateReplication(ctx, si, tabletMap) // done synchronously
+		pingTablets(ctx, tabletMap)             // done async, using the waitgroup declared above in the main method body.
 	}
 
-	done := make(chan bool)
-	go func() {
-		for result := range results {
-			resp.Results = append(resp.Results, result)
-		}
-		done <- true
-	}()
+	if req.IncludeServingState {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.validateServingState(ctx, si, tabletMap, servingStateWait, asyncResults)
+		}()
+	}
 
 	wg.Wait()
-	close(results)
-	<-done
+	results.merge(asyncResults)
 
 	return resp, err
 }
 
+// validateServingState compares the tablet type and serving flag each
+// tablet of the shard broadcasts in its health stream with its topo record,
+// which catches tablets whose type was changed behind the topo's back, for
+// instance by an external failover. Tablets that send no health record
+// within wait are reported as warnings.
+func (s *VtctldServer) validateServingState(ctx context.Context, si *topo.ShardInfo, tabletMap map[string]*topo.TabletInfo, wait time.Duration, results *validationResults) {
+	type servingStateResult struct {
+		alias    string
+		result   string
+		severity vtctldatapb.ValidationFinding_Severity
+	}
+
+	var (
+		m             sync.Mutex
+		wg            sync.WaitGroup
+		servingStates []servingStateResult
+	)
+	for alias, ti := range tabletMap {
+		results.rec.RecordChecked(ValidationCheckServingState, alias)
+
+		wg.Add(1)
+		go func(alias string, tablet *topodatapb.Tablet) {
+			defer wg.Done()
+
+			shr, err := ReadTabletHealth(ctx, tablet, wait)
+			result, severity := ServingStateResult(si, tablet, shr, err, wait)
+			if result == "" {
+				return
+			}
+
+			m.Lock()
+			defer m.Unlock()
+			servingStates = append(servingStates, servingStateResult{alias: alias, result: result, severity: severity})
+		}(alias, ti.Tablet)
+	}
+	wg.Wait()
+
+	sort.Slice(servingStates, func(i, j int) bool { return servingStates[i].alias < servingStates[j].alias })
+	for _, state := range servingStates {
+		results.add(ValidationCheckServingState, state.severity, state.alias, state.result)
+	}
+}
+
 // ValidateVersionKeyspace validates all versions are the same in all
-// tablets in a keyspace
+// tablets in a keyspace, or in a subset of its shards, as the one of the
+// primary of the reference shard.
 func (s *VtctldServer) ValidateVersionKeyspace(ctx context.Context, req *vtctldatapb.ValidateVersionKeyspaceRequest) (resp *vtctldatapb.ValidateVersionKeyspaceResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ValidateVersionKeyspace")
 	defer span.Finish()
 
 	defer panicHandler(&err)
 
-	keyspace := req.Keyspace
-	shards, err := s.ts.GetShardNames(ctx, keyspace)
-	resp = &vtctldatapb.ValidateVersionKeyspaceResponse{
-		Results:        []string{},
-		ResultsByShard: make(map[string]*vtctldatapb.ValidateShardResponse, len(shards)),
-	}
-
-	if err != nil {
-		resp.Results = append(resp.Results, fmt.Sprintf("TopologyServer.GetShardNames(%v) failed: %v", keyspace, err))
-		err = nil
-		return
-	}
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shards", req.Shards)
+	span.Annotate("reference_shard", req.ReferenceShard)
+	span.Annotate("include_non_serving", req.IncludeNonServing)
+	span.Annotate("include_non_serving_shards", req.IncludeNonServingShards)
 
-	if len(shards) == 0 {
-		resp.Results = append(resp.Results, fmt.Sprintf("no shards in keyspace %v", keyspace))
-		return
-	}
+	shards, findings, skipped, nonServing, err := compareKeyspaceTablets(ctx, s.ts, req.Keyspace, req.Shards, req.ReferenceShard, s.versionComparison(req.IncludeNonServing, !req.IncludeNonServingShards, nil))
 
-	si, err := s.ts.GetShard(ctx, keyspace, shards[0])
-	if err != nil {
-		resp.Results = append(resp.Results, fmt.Sprintf("unable to find primary shard %v/%v", keyspace, shards[0]))
-		err = nil
-		return
-	}
-	if !si.HasPrimary() {
-		resp.Results = append(resp.Results, fmt.Sprintf("no primary in shard %v/%v", keyspace, shards[0]))
-		return
+	resp = &vtctldatapb.ValidateVersionKeyspaceResponse{
+		Results:          []string{},
+		ResultsByShard:   make(map[string]*vtctldatapb.ValidateShardResponse, len(shards)),
+		Findings:         findings,
+		SkippedTablets:   skipped,
+		NonServingShards: nonServing,
 	}
-
-	referenceAlias := si.PrimaryAlias
-	referenceVersion, err := s.GetVersion(ctx, &vtctldatapb.GetVersionRequest{TabletAlias: referenceAlias})
 	if err != nil {
-		resp.Results = append(resp.Results, fmt.Sprintf("unable to get reference version of first shard's primary tablet: %v", err))
-		err = nil
-		return
+		// The validation couldn't start, which is reported as a result
+		// rather than as an error.
+		resp.Results = append(resp.Results, err.Error())
+		return resp, nil
 	}
-
-	var validateVersionKeyspaceResponseMutex sync.Mutex
-
 	for _, shard := range shards {
-		shardResp := vtctldatapb.ValidateShardResponse{
-			Results: []string{},
+		resp.ResultsByShard[shard] = &vtctldatapb.ValidateShardResponse{Results: []string{}}
+	}
+	for _, finding := range findings {
+		if finding.Severity == vtctldatapb.ValidationFinding_SKIPPED {
+			// The skipped shards are listed in NonServingShards.
+			continue
 		}
+		resp.Results = append(resp.Results, finding.Message)
+		shardResp := resp.ResultsByShard[finding.Shard]
+		shardResp.Results = append(shardResp.Results, finding.Message)
+	}
 
-		var (
-			validateShardResponseMutex sync.Mutex
-			tabletWaitGroup            sync.WaitGroup
-		)
+	return resp, nil
+}
 
-		aliases, err := s.ts.FindAllTabletAliasesInShard(ctx, keyspace, shard)
-		if err != nil {
-			errMessage := fmt.Sprintf("unable to find tablet aliases in shard %v: %v", shard, err)
-			shardResp.Results = append(shardResp.Results, errMessage)
-			validateVersionKeyspaceResponseMutex.Lock()
-			resp.Results = append(resp.Results, errMessage)
-			resp.ResultsByShard[shard] = &shardResp
-			validateVersionKeyspaceResponseMutex.Unlock()
-			continue
-		}
+// ValidateVersionKeyspaceStream is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ValidateVersionKeyspaceStream(req *vtctldatapb.ValidateVersionKeyspaceRequest, stream vtctlservicepb.Vtctld_ValidateVersionKeyspaceStreamServer) (err error) {
+	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.ValidateVersionKeyspaceStream")
+	defer span.Finish()
 
-		for _, alias := range aliases {
-			if topoproto.TabletAliasEqual(alias, si.PrimaryAlias) {
-				continue
-			}
+	defer panicHandler(&err)
 
-			tabletWaitGroup.Add(1)
-			go func(alias *topodatapb.TabletAlias, m *sync.Mutex, ctx context.Context) {
-				defer tabletWaitGroup.Done()
-				replicaVersion, err := s.GetVersion(ctx, &vtctldatapb.GetVersionRequest{TabletAlias: alias})
-				if err != nil {
-					validateShardResponseMutex.Lock()
-					shardResp.Results = append(shardResp.Results, fmt.Sprintf("unable to get version for tablet %v: %v", alias, err))
-					validateShardResponseMutex.Unlock()
-					return
-				}
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shards", req.Shards)
+	span.Annotate("reference_shard", req.ReferenceShard)
+	span.Annotate("include_non_serving", req.IncludeNonServing)
+	span.Annotate("include_non_serving_shards", req.IncludeNonServingShards)
 
-				if referenceVersion.Version != replicaVersion.Version {
-					validateShardResponseMutex.Lock()
-					shardResp.Results = append(shardResp.Results, fmt.Sprintf("primary %v version %v is different than replica %v version %v", topoproto.TabletAliasString(referenceAlias), referenceVersion, topoproto.TabletAliasString(alias), replicaVersion))
-					validateShardResponseMutex.Unlock()
-				}
-			}(alias, &validateShardResponseMutex, ctx)
-		}
+	return streamKeyspaceValidation(ctx, stream.Send, func(ctx context.Context, progress *validationProgress) error {
+		_, _, _, _, err := compareKeyspaceTablets(ctx, s.ts, req.Keyspace, req.Shards, req.ReferenceShard, s.versionComparison(req.IncludeNonServing, !req.IncludeNonServingShards, progress))
+		return err
+	})
+}
 
-		tabletWaitGroup.Wait()
-		validateVersionKeyspaceResponseMutex.Lock()
-		resp.Results = append(resp.Results, shardResp.Results...)
-		resp.ResultsByShard[shard] = &shardResp
-		validateVersionKeyspaceResponseMutex.Unlock()
+// versionComparison compares the versions of the tablets of a keyspace,
+// reporting to progress if it is set. The non-serving tablets are only
+// compared if includeNonServing is set, and the shards that serve no
+// traffic are skipped if skipNonServingShards is set.
+func (s *VtctldServer) versionComparison(includeNonServing, skipNonServingShards bool, progress *validationProgress) keyspaceComparison[string] {
+	return keyspaceComparison[string]{
+		name: "version",
+		get: func(ctx context.Context, alias *topodatapb.TabletAlias) (string, error) {
+			resp, err := s.GetVersion(ctx, &vtctldatapb.GetVersionRequest{TabletAlias: alias})
+			if err != nil {
+				return "", err
+			}
+			return resp.Version, nil
+		},
+		diff: func(referenceAlias *topodatapb.TabletAlias, reference string, alias *topodatapb.TabletAlias, value string) []string {
+			if value == reference {
+				return nil
+			}
+			return []string{fmt.Sprintf("primary %v version %v is different than replica %v version %v", topoproto.TabletAliasString(referenceAlias), reference, topoproto.TabletAliasString(alias), value)}
+		},
+		includeNonServing:    includeNonServing,
+		skipNonServingShards: skipNonServingShards,
+		progress:             progress,
 	}
-
-	return resp, err
 }
 
 // ValidateVersionShard validates all versions are the same in all
@@ -5291,6 +6437,10 @@ func (s *VtctldServer) ValidateVersionShard(ctx context.Context, req *vtctldatap
 
 	defer panicHandler(&err)
 
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("include_non_serving", req.IncludeNonServing)
+
 	shard, err := s.ts.GetShard(ctx, req.Keyspace, req.Shard)
 	if err != nil {
 		err = fmt.Errorf("GetShard(%s) failed: %v", req.Shard, err)
@@ -5303,7 +6453,9 @@ func (s *VtctldServer) ValidateVersionShard(ctx context.Context, req *vtctldatap
 	}
 
 	log.Infof("Gathering version for primary %v", topoproto.TabletAliasString(shard.PrimaryAlias))
-	primaryVersion, err := s.GetVersion(ctx, &vtctldatapb.GetVersionRequest{
+	primaryCtx, cancel := context.WithTimeout(ctx, tabletTimeout(ctx))
+	defer cancel()
+	primaryVersion, err := s.GetVersion(primaryCtx, &vtctldatapb.GetVersionRequest{
 		TabletAlias: shard.PrimaryAlias,
 	})
 	if err != nil {
@@ -5311,7 +6463,7 @@ func (s *VtctldServer) ValidateVersionShard(ctx context.Context, req *vtctldatap
 		return nil, err
 	}
 
-	aliases, err := s.ts.FindAllTabletAliasesInShard(ctx, req.Keyspace, req.Shard)
+	aliases, skipped, err := findShardTablets(ctx, s.ts, req.Keyspace, req.Shard, req.IncludeNonServing)
 	if err != nil {
 		err = fmt.Errorf("FindAllTabletAliasesInShard(%s, %s) failed: %v", req.Keyspace, req.Shard, err)
 		return nil, err
@@ -5332,7 +6484,7 @@ func (s *VtctldServer) ValidateVersionShard(ctx context.Context, req *vtctldatap
 
 	wg.Wait()
 
-	response := vtctldatapb.ValidateVersionShardResponse{}
+	response := vtctldatapb.ValidateVersionShardResponse{SkippedTablets: skipped}
 	if er.HasErrors() {
 		response.Results = append(response.Results, er.ErrorStrings()...)
 	}
@@ -5698,6 +6850,16 @@ var getVersionFromTabletDebugVars = func(tabletAddr string) (string, error) {
 	return version, nil
 }
 
+// schemaChangeJournal tells whether ApplySchema journals the schema changes
+// in the topo, for GetSchemaChangeHistory.
+var schemaChangeJournal atomic.Bool
+
+// SetSchemaChangeJournal sets whether ApplySchema journals the schema changes
+// it applies in the topo, in addition to logging them.
+func SetSchemaChangeJournal(enabled bool) {
+	schemaChangeJournal.Store(enabled)
+}
+
 var (
 	versionFuncMu        sync.Mutex
 	getVersionFromTablet = getVersionFromTabletDebugVars
@@ -5718,6 +6880,8 @@ func GetVersionFunc() func(string) (string, error) {
 // helper method to asynchronously get and diff a version
 func (s *VtctldServer) diffVersion(ctx context.Context, primaryVersion string, primaryAlias *topodatapb.TabletAlias, alias *topodatapb.TabletAlias, wg *sync.WaitGroup, er concurrency.ErrorRecorder) {
 	defer wg.Done()
+	ctx, cancel := context.WithTimeout(ctx, tabletTimeout(ctx))
+	defer cancel()
 	log.Infof("Gathering version for %v", topoproto.TabletAliasString(alias))
 	replicaVersion, err := s.GetVersion(ctx, &vtctldatapb.GetVersionRequest{
 		TabletAlias: alias,
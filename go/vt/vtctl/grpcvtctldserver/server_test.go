@@ -22,9 +22,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"runtime"
 	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -37,21 +39,27 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/event"
 	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
 	hk "vitess.io/vitess/go/vt/hook"
+	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
 	"vitess.io/vitess/go/vt/proto/vttime"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/events"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver/testutil"
 	"vitess.io/vitess/go/vt/vtctl/localvtctldclient"
 	"vitess.io/vitess/go/vt/vtctl/schematools"
+	"vitess.io/vitess/go/vt/vtctl/vtctldclient"
 	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 	"vitess.io/vitess/go/vt/vttablet/tmclienttest"
 
@@ -64,6 +72,7 @@ import (
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 func init() {
@@ -371,6 +380,10 @@ func TestApplyVSchema(t *testing.T) {
 				VSchema: &vschemapb.Keyspace{
 					Sharded: false,
 				},
+				Diff: []string{
+					"keyspace changes from sharded to unsharded (routing change)",
+					"vindex v1 (hash) is removed",
+				},
 			},
 			shouldErr: false,
 		}, {
@@ -386,6 +399,10 @@ func TestApplyVSchema(t *testing.T) {
 				VSchema: &vschemapb.Keyspace{
 					Sharded: false,
 				},
+				Diff: []string{
+					"keyspace changes from sharded to unsharded (routing change)",
+					"vindex v1 (hash) is removed",
+				},
 			},
 			shouldErr: false,
 		}, {
@@ -440,6 +457,10 @@ func TestApplyVSchema(t *testing.T) {
 						Params: []string{"goodbye", "hello"},
 					},
 				},
+				Diff: []string{
+					"vindex lookup1 (lookup) is added",
+					"vindex v1 (hash) is removed",
+				},
 			},
 			shouldErr: false,
 		}, {
@@ -495,6 +516,10 @@ func TestApplyVSchema(t *testing.T) {
 				VSchema: &vschemapb.Keyspace{
 					Sharded: false,
 				},
+				Diff: []string{
+					"keyspace changes from sharded to unsharded (routing change)",
+					"vindex v1 (hash) is removed",
+				},
 			},
 			shouldErr: false,
 		}, {
@@ -549,6 +574,10 @@ func TestApplyVSchema(t *testing.T) {
 						Params: []string{"goodbye", "hello"},
 					},
 				},
+				Diff: []string{
+					"vindex lookup1 (lookup) is added",
+					"vindex v1 (hash) is removed",
+				},
 			},
 			shouldErr: false,
 		}, {
@@ -591,6 +620,43 @@ func TestApplyVSchema(t *testing.T) {
 			},
 			shouldErr: true,
 			err:       "unknown vindex params: lookup1 (goodbye, hello)",
+		}, {
+			name: "sql with retry on conflict",
+			req: &vtctldatapb.ApplyVSchemaRequest{
+				Keyspace:        "testkeyspace",
+				Sql:             "alter vschema create vindex v2 using xxhash",
+				SkipRebuild:     true,
+				RetryOnConflict: true,
+			},
+			exp: &vtctldatapb.ApplyVSchemaResponse{
+				VSchema: &vschemapb.Keyspace{
+					Sharded: true,
+					Vindexes: map[string]*vschemapb.Vindex{
+						"v1": {
+							Type: "hash",
+						},
+						"v2": {
+							Type: "xxhash",
+						},
+					},
+					Tables: map[string]*vschemapb.Table{},
+				},
+				Diff: []string{
+					"vindex v2 (xxhash) is added",
+				},
+			},
+			shouldErr: false,
+		}, {
+			name: "retry on conflict without sql",
+			req: &vtctldatapb.ApplyVSchemaRequest{
+				Keyspace: "testkeyspace",
+				VSchema: &vschemapb.Keyspace{
+					Sharded: false,
+				},
+				RetryOnConflict: true,
+			},
+			shouldErr: true,
+			err:       "req.RetryOnConflict is only supported with req.Sql",
 		},
 	}
 
@@ -997,6 +1063,83 @@ func TestBackupShard(t *testing.T) {
 				assert.Equal(t, 3, len(responses), "expected 3 messages from backupclient stream")
 			},
 		},
+		{
+			name: "rdonly before a less lagged replica",
+			ts:   memorytopo.NewServer(ctx, "zone1"),
+			tmc: &testutil.TabletManagerClient{
+				Backups: map[string]struct {
+					Events        []*logutilpb.Event
+					EventInterval time.Duration
+					EventJitter   time.Duration
+					ErrorAfter    time.Duration
+				}{
+					"zone1-0000000101": {
+						Events: []*logutilpb.Event{{}, {}, {}},
+					},
+				},
+				PrimaryPositionResults: map[string]struct {
+					Position string
+					Error    error
+				}{
+					"zone1-0000000200": {
+						Position: "some-position",
+					},
+				},
+				ReplicationStatusResults: map[string]struct {
+					Position *replicationdatapb.Status
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Position: &replicationdatapb.Status{
+							ReplicationLagSeconds: 0,
+						},
+					},
+					"zone1-0000000101": {
+						Position: &replicationdatapb.Status{
+							ReplicationLagSeconds: 10,
+						},
+					},
+				},
+			},
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+					Keyspace: "ks",
+					Shard:    "-",
+					Type:     topodatapb.TabletType_REPLICA,
+				},
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  101,
+					},
+					Keyspace: "ks",
+					Shard:    "-",
+					Type:     topodatapb.TabletType_RDONLY,
+				},
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  200,
+					},
+					Keyspace: "ks",
+					Shard:    "-",
+					Type:     topodatapb.TabletType_PRIMARY,
+				},
+			},
+			req: &vtctldatapb.BackupShardRequest{
+				Keyspace: "ks",
+				Shard:    "-",
+			},
+			assertion: func(t *testing.T, responses []*vtctldatapb.BackupResponse, err error) {
+				assert.ErrorIs(t, err, io.EOF, "expected Recv loop to end with io.EOF")
+				require.Equal(t, 3, len(responses), "expected 3 messages from backupclient stream")
+				assert.Equal(t, "zone1-0000000101", topoproto.TabletAliasString(responses[0].TabletAlias))
+			},
+		},
 		{
 			name: "cannot backup primary",
 			ts:   memorytopo.NewServer(ctx, "zone1"),
@@ -3178,6 +3321,72 @@ func TestDeleteCellInfo(t *testing.T) {
 	}
 }
 
+func TestDeleteCellInfoReferences(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "0"))
+	_, err := ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.TabletControls = []*topodatapb.Shard_TabletControl{
+			{TabletType: topodatapb.TabletType_REPLICA, Cells: []string{"zone1", "zone2"}},
+			{TabletType: topodatapb.TabletType_RDONLY, Cells: []string{"zone2"}},
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, ts.CreateCellsAlias(ctx, "zones", &topodatapb.CellsAlias{Cells: []string{"zone1", "zone2"}}))
+	tablet := &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone2", Uid: 200},
+		Keyspace: "ks",
+		Shard:    "0",
+		Type:     topodatapb.TabletType_REPLICA,
+	}
+	require.NoError(t, ts.CreateTablet(ctx, tablet))
+	require.NoError(t, ts.UpdateSrvKeyspace(ctx, "zone2", "ks", &topodatapb.SrvKeyspace{}))
+	require.NoError(t, ts.UpdateSrvVSchema(ctx, "zone2", &vschemapb.SrvVSchema{}))
+
+	_, err = vtctld.DeleteCellInfo(ctx, &vtctldatapb.DeleteCellInfoRequest{Name: "zone2", PurgeReferences: true})
+	require.ErrorContains(t, err, "cell zone2 is still referenced (tablets: 1, shard replication graphs: 1, shard tablet controls: 1, shard primaries: 0, SrvKeyspaces: 1, SrvVSchema: true, cells aliases: 1); use --force")
+
+	require.NoError(t, ts.DeleteTablet(ctx, tablet.Alias))
+	require.NoError(t, ts.DeleteShardReplication(ctx, "zone2", "ks", "0"))
+	require.NoError(t, ts.DeleteSrvKeyspace(ctx, "zone2", "ks"))
+
+	_, err = vtctld.DeleteCellInfo(ctx, &vtctldatapb.DeleteCellInfoRequest{Name: "zone2"})
+	require.ErrorContains(t, err, "cell zone2 is still referenced (tablets: 0, shard replication graphs: 0, shard tablet controls: 1, shard primaries: 0, SrvKeyspaces: 0, SrvVSchema: true, cells aliases: 1); use --purge-references")
+
+	resp, err := vtctld.DeleteCellInfo(ctx, &vtctldatapb.DeleteCellInfoRequest{Name: "zone2", PurgeReferences: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"Removed cell zone2 from the REPLICA tablet control of shard ks/0",
+		"Removed the RDONLY tablet control of shard ks/0, which only listed cell zone2",
+		"Removed cell zone2 from cells alias zones",
+		"Deleted the SrvVSchema of cell zone2",
+	}, resp.PurgedReferences)
+	assert.EqualValues(t, 1, resp.References.ShardTabletControls)
+	assert.EqualValues(t, 1, resp.References.CellsAliases)
+	assert.True(t, resp.References.SrvVschema)
+
+	si, err := ts.GetShard(ctx, "ks", "0")
+	require.NoError(t, err)
+	require.Len(t, si.TabletControls, 1)
+	assert.Equal(t, []string{"zone1"}, si.TabletControls[0].Cells)
+	aliases, err := ts.GetCellsAliases(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zone1"}, aliases["zones"].Cells)
+	_, err = ts.GetSrvVSchema(ctx, "zone2")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), err)
+	_, err = ts.GetCellInfo(ctx, "zone2", true)
+	assert.True(t, topo.IsErrType(err, topo.NoNode), err)
+}
+
 func TestDeleteCellsAlias(t *testing.T) {
 	t.Parallel()
 
@@ -3516,6 +3725,52 @@ func TestDeleteKeyspace(t *testing.T) {
 	}
 }
 
+// TestDeleteKeyspaceCancelled tests that once its shards are deleted, the
+// keyspace and its serving graphs are still deleted if the context is
+// cancelled.
+func TestDeleteKeyspaceCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	keyspace := "delete_keyspace_cancelled"
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	testutil.AddShards(ctx, t, ts,
+		&vtctldatapb.Shard{Keyspace: keyspace, Name: "-80"},
+		&vtctldatapb.Shard{Keyspace: keyspace, Name: "80-"},
+	)
+	testutil.UpdateSrvKeyspaces(ctx, t, ts, map[string]map[string]*topodatapb.SrvKeyspace{
+		"zone1": {keyspace: &topodatapb.SrvKeyspace{}},
+	})
+
+	// Cancel the request right after its last shard is deleted.
+	requestCtx, requestCancel := context.WithCancel(ctx)
+	defer requestCancel()
+	event.AddListener(func(ev *events.ShardChange) {
+		if ev.KeyspaceName == keyspace && ev.ShardName == "80-" && ev.Status == "deleted" {
+			requestCancel()
+		}
+	})
+
+	_, err := vtctld.DeleteKeyspace(requestCtx, &vtctldatapb.DeleteKeyspaceRequest{
+		Keyspace:  keyspace,
+		Recursive: true,
+	})
+	require.NoError(t, err)
+	require.Error(t, requestCtx.Err())
+
+	_, err = ts.GetKeyspace(ctx, keyspace)
+	assert.True(t, topo.IsErrType(err, topo.NoNode), err)
+	_, err = ts.GetSrvKeyspace(ctx, "zone1", keyspace)
+	assert.True(t, topo.IsErrType(err, topo.NoNode), err)
+}
+
 func TestDeleteShards(t *testing.T) {
 	t.Parallel()
 
@@ -4029,10 +4284,12 @@ func TestDeleteSrvKeyspace(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name      string
-		vschemas  map[string]*vschemapb.SrvVSchema
-		req       *vtctldatapb.DeleteSrvVSchemaRequest
-		shouldErr bool
+		name         string
+		vschemas     map[string]*vschemapb.SrvVSchema
+		srvKeyspaces map[string][]string
+		req          *vtctldatapb.DeleteSrvVSchemaRequest
+		expected     *vtctldatapb.DeleteSrvVSchemaResponse
+		shouldErr    bool
 	}{
 		{
 			name: "success",
@@ -4051,9 +4308,36 @@ func TestDeleteSrvKeyspace(t *testing.T) {
 					RoutingRules: &vschemapb.RoutingRules{Rules: []*vschemapb.RoutingRule{}},
 				},
 			},
+			srvKeyspaces: map[string][]string{
+				"zone2": {"ks3"},
+			},
 			req: &vtctldatapb.DeleteSrvVSchemaRequest{
 				Cell: "zone2",
 			},
+			expected: &vtctldatapb.DeleteSrvVSchemaResponse{
+				Keyspaces:        []string{"ks3"},
+				ServingKeyspaces: []string{"ks3"},
+				Deleted:          true,
+			},
+		},
+		{
+			name: "dry run",
+			vschemas: map[string]*vschemapb.SrvVSchema{
+				"zone1": {
+					Keyspaces: map[string]*vschemapb.Keyspace{
+						"ks2": {},
+						"ks1": {},
+					},
+					RoutingRules: &vschemapb.RoutingRules{Rules: []*vschemapb.RoutingRule{}},
+				},
+			},
+			req: &vtctldatapb.DeleteSrvVSchemaRequest{
+				Cell:   "zone1",
+				DryRun: true,
+			},
+			expected: &vtctldatapb.DeleteSrvVSchemaResponse{
+				Keyspaces: []string{"ks1", "ks2"},
+			},
 		},
 		{
 			name: "cell not found",
@@ -4093,7 +4377,7 @@ func TestDeleteSrvKeyspace(t *testing.T) {
 			for cell, vschema := range tt.vschemas {
 				cells = append(cells, cell)
 
-				if cell == tt.req.Cell {
+				if cell == tt.req.Cell && !tt.req.DryRun {
 					vschema = nil
 				}
 
@@ -4107,17 +4391,24 @@ func TestDeleteSrvKeyspace(t *testing.T) {
 				err := ts.UpdateSrvVSchema(ctx, cell, vschema)
 				require.NoError(t, err, "failed to update SrvVSchema in cell = %v, vschema = %+v", cell, vschema)
 			}
+			for cell, keyspaces := range tt.srvKeyspaces {
+				for _, keyspace := range keyspaces {
+					err := ts.UpdateSrvKeyspace(ctx, cell, keyspace, &topodatapb.SrvKeyspace{})
+					require.NoError(t, err, "failed to update SrvKeyspace in cell = %v, keyspace = %v", cell, keyspace)
+				}
+			}
 
 			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
 				return NewVtctldServer(vtenv.NewTestEnv(), ts)
 			})
-			_, err := vtctld.DeleteSrvVSchema(ctx, tt.req)
+			deleteResp, err := vtctld.DeleteSrvVSchema(ctx, tt.req)
 			if tt.shouldErr {
 				assert.Error(t, err)
 				return
 			}
 
-			assert.NoError(t, err)
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, deleteResp)
 
 			resp, err := vtctld.GetSrvVSchemas(ctx, &vtctldatapb.GetSrvVSchemasRequest{})
 			require.NoError(t, err, "GetSrvVSchemas error")
@@ -6696,6 +6987,52 @@ func TestGetSchemaMigrations(t *testing.T) {
 	}
 }
 
+func TestGetSchemaChangeHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	_, err := vtctld.GetSchemaChangeHistory(ctx, &vtctldatapb.GetSchemaChangeHistoryRequest{})
+	assert.Error(t, err, "the keyspace is required")
+
+	resp, err := vtctld.GetSchemaChangeHistory(ctx, &vtctldatapb.GetSchemaChangeHistoryRequest{Keyspace: "testkeyspace"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Changes)
+
+	changes := []*vtctldatapb.SchemaChange{
+		{
+			Keyspace:      "testkeyspace",
+			ExecutionUuid: "exec1",
+			AppliedAt:     protoutil.TimeToProto(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+			CallerId:      &vtrpcpb.CallerID{Principal: "alice"},
+			Sql:           []string{"create table t1 (id int primary key)"},
+			DdlStrategy:   "direct",
+		},
+		{
+			Keyspace:         "testkeyspace",
+			ExecutionUuid:    "exec2",
+			AppliedAt:        protoutil.TimeToProto(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)),
+			CallerId:         &vtrpcpb.CallerID{Principal: "bob"},
+			Sql:              []string{"alter table t1 add column c int"},
+			DdlStrategy:      "vitess",
+			MigrationContext: "vtctl:exec2",
+			UuidList:         []string{"bf3e8b02_ae50_11ef_9ab6_0a43f95f28a3"},
+		},
+	}
+	for _, change := range changes {
+		require.NoError(t, schematools.RecordSchemaChange(ctx, ts, change))
+	}
+
+	resp, err = vtctld.GetSchemaChangeHistory(ctx, &vtctldatapb.GetSchemaChangeHistoryRequest{Keyspace: "testkeyspace", Limit: 1})
+	require.NoError(t, err)
+	utils.MustMatch(t, []*vtctldatapb.SchemaChange{changes[1]}, resp.Changes)
+}
+
 func TestGetShard(t *testing.T) {
 	t.Parallel()
 
@@ -9782,46 +10119,166 @@ func TestReloadSchemaShard(t *testing.T) {
 	}
 }
 
-func TestRemoveBackup(t *testing.T) {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	ts := memorytopo.NewServer(ctx)
-	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
-		return NewVtctldServer(vtenv.NewTestEnv(), ts)
-	})
+func TestReloadTabletConfig(t *testing.T) {
+	t.Parallel()
 
-	setup := func() {
-		testutil.BackupStorage.Backups = map[string][]string{
-			"testkeyspace/-": {"backup1", "backup2", "backup3"},
-		}
+	tablets := []*topodatapb.Tablet{
+		{
+			Keyspace: "ks1",
+			Shard:    "-80",
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+			Type:     topodatapb.TabletType_PRIMARY,
+		},
+		{
+			Keyspace: "ks1",
+			Shard:    "-80",
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+			Type:     topodatapb.TabletType_REPLICA,
+		},
+		{
+			Keyspace: "ks1",
+			Shard:    "80-",
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			Type:     topodatapb.TabletType_PRIMARY,
+		},
+		{
+			Keyspace: "ks1",
+			Shard:    "80-",
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 201},
+			Type:     topodatapb.TabletType_REPLICA,
+		},
+	}
+	type result = struct {
+		Hash  string
+		Error error
 	}
 
-	t.Run("ok", func(t *testing.T) {
-		setup()
-		_, err := vtctld.RemoveBackup(ctx, &vtctldatapb.RemoveBackupRequest{
-			Keyspace: "testkeyspace",
-			Shard:    "-",
-			Name:     "backup2",
-		})
-
-		assert.NoError(t, err)
-
-		resp, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
-			Keyspace: "testkeyspace",
-			Shard:    "-",
-		})
-		require.NoError(t, err)
-
-		var backupNames []string
-		for _, bi := range resp.Backups {
-			backupNames = append(backupNames, bi.Name)
-		}
-		utils.MustMatch(t, []string{"backup1", "backup3"}, backupNames, "expected \"backup2\" to be removed")
-	})
-
-	t.Run("no bucket found", func(t *testing.T) {
-		setup()
-		_, err := vtctld.RemoveBackup(ctx, &vtctldatapb.RemoveBackupRequest{
+	tests := []struct {
+		name      string
+		tmc       testutil.TabletManagerClient
+		req       *vtctldatapb.ReloadTabletConfigRequest
+		expected  *vtctldatapb.ReloadTabletConfigResponse
+		shouldErr bool
+	}{
+		{
+			name: "keyspace",
+			tmc: testutil.TabletManagerClient{
+				ReloadTabletConfigResults: map[string]result{
+					"zone1-0000000100": {Hash: "aaa"},
+					"zone1-0000000101": {Hash: "aaa"},
+					"zone1-0000000200": {Hash: "bbb"},
+					"zone1-0000000201": {Error: assert.AnError},
+				},
+			},
+			req: &vtctldatapb.ReloadTabletConfigRequest{
+				Keyspace:    "ks1",
+				Concurrency: 2,
+			},
+			expected: &vtctldatapb.ReloadTabletConfigResponse{
+				Results: []*vtctldatapb.ReloadTabletConfigResult{
+					{TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}, ConfigHash: "aaa"},
+					{TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101}, ConfigHash: "aaa"},
+					{TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 200}, ConfigHash: "bbb", Mismatch: true},
+					{TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 201}, Error: assert.AnError.Error()},
+				},
+				MajorityHash: "aaa",
+			},
+		},
+		{
+			name: "shard without majority",
+			tmc: testutil.TabletManagerClient{
+				ReloadTabletConfigResults: map[string]result{
+					"zone1-0000000100": {Hash: "aaa"},
+					"zone1-0000000200": {Hash: "bbb"},
+				},
+			},
+			req: &vtctldatapb.ReloadTabletConfigRequest{
+				Keyspace: "ks1",
+				Shard:    "-80",
+			},
+			expected: &vtctldatapb.ReloadTabletConfigResponse{
+				Results: []*vtctldatapb.ReloadTabletConfigResult{
+					{TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}, ConfigHash: "aaa", Mismatch: true},
+					{TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 200}, ConfigHash: "bbb", Mismatch: true},
+				},
+			},
+		},
+		{
+			name: "keyspace not found",
+			req: &vtctldatapb.ReloadTabletConfigRequest{
+				Keyspace: "ks2",
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary: true,
+			}, tablets...)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tt.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.ReloadTabletConfig(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
+func TestRemoveBackup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx)
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	setup := func() {
+		testutil.BackupStorage.Backups = map[string][]string{
+			"testkeyspace/-": {"backup1", "backup2", "backup3"},
+		}
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		setup()
+		_, err := vtctld.RemoveBackup(ctx, &vtctldatapb.RemoveBackupRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Name:     "backup2",
+		})
+
+		assert.NoError(t, err)
+
+		resp, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		})
+		require.NoError(t, err)
+
+		var backupNames []string
+		for _, bi := range resp.Backups {
+			backupNames = append(backupNames, bi.Name)
+		}
+		utils.MustMatch(t, []string{"backup1", "backup3"}, backupNames, "expected \"backup2\" to be removed")
+	})
+
+	t.Run("no bucket found", func(t *testing.T) {
+		setup()
+		_, err := vtctld.RemoveBackup(ctx, &vtctldatapb.RemoveBackupRequest{
 			Keyspace: "notfound",
 			Shard:    "-",
 			Name:     "somebackup",
@@ -12026,6 +12483,76 @@ func TestShardReplicationAdd(t *testing.T) {
 	}, resp.Error)
 }
 
+func TestShardReplicationFix(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	tablets := []*topodatapb.Tablet{
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+			Keyspace: "ks",
+			Shard:    "-",
+		},
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  101,
+			},
+			Keyspace: "ks",
+			Shard:    "-",
+		},
+	}
+	testutil.AddTablets(ctx, t, ts, nil, tablets...)
+
+	// The graph misses a tablet of the shard and lists one that doesn't
+	// exist.
+	err := topo.RemoveShardReplicationRecord(ctx, ts, "zone1", "ks", "-", tablets[1].Alias)
+	require.NoError(t, err)
+	_, err = vtctld.ShardReplicationAdd(ctx, &vtctldatapb.ShardReplicationAddRequest{
+		Keyspace: "ks",
+		Shard:    "-",
+		TabletAlias: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  404,
+		},
+	})
+	require.NoError(t, err)
+
+	req := &vtctldatapb.ShardReplicationFixRequest{
+		Keyspace: "ks",
+		Shard:    "-",
+		Cell:     "zone1",
+	}
+	resp, err := vtctld.ShardReplicationFix(ctx, req)
+	require.NoError(t, err)
+	notFound := &topodatapb.ShardReplicationError{
+		TabletAlias: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  404,
+		},
+		Type: topodatapb.ShardReplicationError_NOT_FOUND,
+	}
+	utils.MustMatch(t, &vtctldatapb.ShardReplicationFixResponse{
+		Error:   notFound,
+		Added:   []*topodatapb.TabletAlias{tablets[1].Alias},
+		Removed: []*topodatapb.ShardReplicationError{notFound},
+	}, resp)
+
+	// Everything was fixed at once.
+	resp, err = vtctld.ShardReplicationFix(ctx, req)
+	require.NoError(t, err)
+	utils.MustMatch(t, &vtctldatapb.ShardReplicationFixResponse{}, resp)
+}
+
 func TestShardReplicationPositions(t *testing.T) {
 	t.Parallel()
 
@@ -13354,6 +13881,221 @@ func TestTabletExternallyReparented(t *testing.T) {
 	}
 }
 
+// TestTopoGet isn't parallel as it lowers maxTopoResponseSize.
+func TestTopoGet(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	testutil.AddTablets(ctx, t, ts, nil, &topodatapb.Tablet{
+		Alias:         &topodatapb.TabletAlias{Cell: "cell1", Uid: 100},
+		Hostname:      "localhost",
+		Keyspace:      "keyspace1",
+		MysqlHostname: "localhost",
+		MysqlPort:     17100,
+	})
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	require.NoError(t, err)
+	_, err = conn.Create(ctx, "/notes/README", []byte("hello"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		req      *vtctldatapb.TopoGetRequest
+		expected *vtctldatapb.TopoGetResponse
+		wantCode vtrpcpb.Code
+	}{
+		{
+			name: "decoded as json",
+			req: &vtctldatapb.TopoGetRequest{
+				Path:   "/cell1/tablets/cell1-0000000100/Tablet",
+				Decode: true,
+				AsJson: true,
+			},
+			expected: &vtctldatapb.TopoGetResponse{
+				Size:    48,
+				Decoded: "{\n  \"alias\": {\n    \"cell\": \"cell1\",\n    \"uid\": 100\n  },\n  \"hostname\": \"localhost\",\n  \"keyspace\": \"keyspace1\",\n  \"mysql_hostname\": \"localhost\",\n  \"mysql_port\": 17100\n}",
+			},
+		},
+		{
+			name: "unknown type is not decoded",
+			req: &vtctldatapb.TopoGetRequest{
+				Path:   "/global/notes/README",
+				Decode: true,
+				AsJson: true,
+			},
+			expected: &vtctldatapb.TopoGetResponse{
+				Size: 5,
+				Data: []byte("hello"),
+			},
+		},
+		{
+			name: "as json without decode",
+			req: &vtctldatapb.TopoGetRequest{
+				Path:   "/global/notes/README",
+				AsJson: true,
+			},
+			expected: &vtctldatapb.TopoGetResponse{
+				Size: 5,
+				Data: []byte("hello"),
+			},
+		},
+		{
+			name: "invalid path",
+			req: &vtctldatapb.TopoGetRequest{
+				Path: "global/notes/README",
+			},
+			wantCode: vtrpcpb.Code_INVALID_ARGUMENT,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := vtctld.TopoGet(ctx, tt.req)
+			if tt.wantCode != vtrpcpb.Code_OK {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantCode, vterrors.Code(err))
+				return
+			}
+			require.NoError(t, err)
+
+			// We cannot compare versions as the value is non-deterministic.
+			assert.NotEmpty(t, resp.Version)
+			resp.Version = ""
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+
+	t.Run("raw", func(t *testing.T) {
+		resp, err := vtctld.TopoGet(ctx, &vtctldatapb.TopoGetRequest{
+			Path: "/cell1/tablets/cell1-0000000100/Tablet",
+		})
+		require.NoError(t, err)
+		require.Empty(t, resp.Decoded)
+		require.EqualValues(t, len(resp.Data), resp.Size)
+
+		tablet := &topodatapb.Tablet{}
+		require.NoError(t, proto.Unmarshal(resp.Data, tablet))
+		assert.Equal(t, "localhost", tablet.Hostname)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := vtctld.TopoGet(ctx, &vtctldatapb.TopoGetRequest{
+			Path: "/global/notes/TODO",
+		})
+		require.Error(t, err)
+		assert.True(t, topo.IsErrType(err, topo.NoNode))
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		defer func(size int) { maxTopoResponseSize = size }(maxTopoResponseSize)
+		maxTopoResponseSize = 4
+
+		_, err := vtctld.TopoGet(ctx, &vtctldatapb.TopoGetRequest{
+			Path: "/global/notes/README",
+		})
+		require.Error(t, err)
+		assert.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+	})
+}
+
+// TestTopoList isn't parallel as it lowers maxTopoResponseSize.
+func TestTopoList(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell2", "cell1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	for _, keyspace := range []string{"ks5", "ks3", "ks1", "ks4", "ks2"} {
+		require.NoError(t, ts.CreateKeyspace(ctx, keyspace, &topodatapb.Keyspace{}))
+	}
+
+	// listAll lists path page by page, and returns the names of the entries
+	// of each page.
+	listAll := func(t *testing.T, path string, pageSize int32) [][]string {
+		var pages [][]string
+		req := &vtctldatapb.TopoListRequest{Path: path, PageSize: pageSize}
+		for {
+			resp, err := vtctld.TopoList(ctx, req)
+			require.NoError(t, err)
+
+			var names []string
+			for _, entry := range resp.Entries {
+				names = append(names, entry.Name)
+			}
+			pages = append(pages, names)
+			if resp.NextPageToken == "" {
+				return pages
+			}
+			req.PageToken = resp.NextPageToken
+		}
+	}
+
+	t.Run("root", func(t *testing.T) {
+		resp, err := vtctld.TopoList(ctx, &vtctldatapb.TopoListRequest{Path: "/"})
+		require.NoError(t, err)
+		utils.MustMatch(t, &vtctldatapb.TopoListResponse{
+			Entries: []*vtctldatapb.TopoEntry{
+				{Name: "cell1", Type: vtctldatapb.TopoEntry_DIRECTORY},
+				{Name: "cell2", Type: vtctldatapb.TopoEntry_DIRECTORY},
+				{Name: "global", Type: vtctldatapb.TopoEntry_DIRECTORY},
+			},
+		}, resp)
+	})
+
+	t.Run("pages", func(t *testing.T) {
+		assert.Equal(t, [][]string{{"ks1", "ks2"}, {"ks3", "ks4"}, {"ks5"}}, listAll(t, "/global/keyspaces", 2))
+		assert.Equal(t, [][]string{{"ks1", "ks2", "ks3", "ks4", "ks5"}}, listAll(t, "/global/keyspaces", 0))
+	})
+
+	t.Run("files", func(t *testing.T) {
+		conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+		require.NoError(t, err)
+		data, version, err := conn.Get(ctx, "/keyspaces/ks1/Keyspace")
+		require.NoError(t, err)
+
+		resp, err := vtctld.TopoList(ctx, &vtctldatapb.TopoListRequest{Path: "/global/keyspaces/ks1"})
+		require.NoError(t, err)
+		utils.MustMatch(t, &vtctldatapb.TopoListResponse{
+			Entries: []*vtctldatapb.TopoEntry{{
+				Name:    "Keyspace",
+				Type:    vtctldatapb.TopoEntry_FILE,
+				Version: version.String(),
+				Size:    int64(len(data)),
+			}},
+		}, resp)
+	})
+
+	t.Run("response size limit", func(t *testing.T) {
+		defer func(size int) { maxTopoResponseSize = size }(maxTopoResponseSize)
+		maxTopoResponseSize = 1
+
+		// A page has at least one entry, whatever its size.
+		assert.Equal(t, [][]string{{"ks1"}, {"ks2"}, {"ks3"}, {"ks4"}, {"ks5"}}, listAll(t, "/global/keyspaces", 0))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, req := range []*vtctldatapb.TopoListRequest{
+			{Path: ""},
+			{Path: "/global/keyspaces", PageToken: "not a token"},
+			{Path: "/global/keyspaces", PageSize: -1},
+		} {
+			_, err := vtctld.TopoList(ctx, req)
+			require.Error(t, err)
+			assert.Equal(t, vtrpcpb.Code_INVALID_ARGUMENT, vterrors.Code(err), "TopoList(%v)", req)
+		}
+
+		_, err := vtctld.TopoList(ctx, &vtctldatapb.TopoListRequest{Path: "/global/keyspaces/ks6"})
+		require.Error(t, err)
+		assert.True(t, topo.IsErrType(err, topo.NoNode))
+	})
+}
+
 func TestUpdateCellInfo(t *testing.T) {
 	t.Parallel()
 
@@ -13538,6 +14280,7 @@ func TestUpdateCellsAlias(t *testing.T) {
 				CellsAlias: &topodatapb.CellsAlias{
 					Cells: []string{"zone1", "zone2"},
 				},
+				RemovedCells: []string{"zone3"},
 			},
 		},
 		{
@@ -13571,6 +14314,7 @@ func TestUpdateCellsAlias(t *testing.T) {
 						"zone4",
 					},
 				},
+				AddedCells: []string{"zone4"},
 			},
 		},
 		{
@@ -13587,6 +14331,7 @@ func TestUpdateCellsAlias(t *testing.T) {
 				CellsAlias: &topodatapb.CellsAlias{
 					Cells: []string{"zone1", "zone2"},
 				},
+				AddedCells: []string{"zone1", "zone2"},
 			},
 		},
 		{
@@ -13656,15 +14401,81 @@ func TestUpdateCellsAlias(t *testing.T) {
 	}
 }
 
-func TestValidate(t *testing.T) {
+func TestCellsAliasRoutingChanges(t *testing.T) {
 	t.Parallel()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	ts := memorytopo.NewServer(ctx, "zone1", "zone2", "zone3")
-	tablets := []*topodatapb.Tablet{
-		{
-			Keyspace: "ks1",
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	servedTypes := func(tabletTypes ...topodatapb.TabletType) *topodatapb.SrvKeyspace {
+		srvKeyspace := &topodatapb.SrvKeyspace{}
+		for _, tabletType := range tabletTypes {
+			srvKeyspace.Partitions = append(srvKeyspace.Partitions, &topodatapb.SrvKeyspace_KeyspacePartition{ServedType: tabletType})
+		}
+		return srvKeyspace
+	}
+	require.NoError(t, ts.UpdateSrvKeyspace(ctx, "zone1", "ks1", servedTypes(topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA)))
+	require.NoError(t, ts.UpdateSrvKeyspace(ctx, "zone2", "ks1", servedTypes(topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY)))
+	require.NoError(t, ts.UpdateSrvKeyspace(ctx, "zone3", "ks2", servedTypes(topodatapb.TabletType_REPLICA)))
+
+	_, err := vtctld.AddCellsAlias(ctx, &vtctldatapb.AddCellsAliasRequest{Name: "zone", Cells: []string{"zone1", "zone4"}})
+	assert.ErrorContains(t, err, "cells [zone4] of alias zone have no CellInfo")
+
+	addResp, err := vtctld.AddCellsAlias(ctx, &vtctldatapb.AddCellsAliasRequest{Name: "zone", Cells: []string{"zone1", "zone2"}, DryRun: true})
+	require.NoError(t, err)
+	utils.MustMatch(t, []*vtctldatapb.CellsAliasRoutingChange{
+		{Keyspace: "ks1", TabletType: topodatapb.TabletType_REPLICA, Cells: []string{"zone1", "zone2"}},
+		{Keyspace: "ks1", TabletType: topodatapb.TabletType_RDONLY, Cells: []string{"zone2"}},
+	}, addResp.RoutingChanges)
+	_, err = ts.GetCellsAlias(ctx, "zone", true)
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "dry run should not create the alias: %v", err)
+
+	_, err = vtctld.AddCellsAlias(ctx, &vtctldatapb.AddCellsAliasRequest{Name: "zone", Cells: []string{"zone1", "zone2"}})
+	require.NoError(t, err)
+
+	updateResp, err := vtctld.UpdateCellsAlias(ctx, &vtctldatapb.UpdateCellsAliasRequest{
+		Name:       "zone",
+		CellsAlias: &topodatapb.CellsAlias{Cells: []string{"zone1", "zone3"}},
+		DryRun:     true,
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, &vtctldatapb.UpdateCellsAliasResponse{
+		Name:         "zone",
+		CellsAlias:   &topodatapb.CellsAlias{Cells: []string{"zone1", "zone3"}},
+		AddedCells:   []string{"zone3"},
+		RemovedCells: []string{"zone2"},
+		RoutingChanges: []*vtctldatapb.CellsAliasRoutingChange{
+			{Keyspace: "ks1", TabletType: topodatapb.TabletType_REPLICA, Cells: []string{"zone1", "zone2"}},
+			{Keyspace: "ks1", TabletType: topodatapb.TabletType_RDONLY, Cells: []string{"zone2"}},
+			{Keyspace: "ks2", TabletType: topodatapb.TabletType_REPLICA, Cells: []string{"zone3"}},
+		},
+	}, updateResp)
+	ca, err := ts.GetCellsAlias(ctx, "zone", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zone1", "zone2"}, ca.Cells, "dry run should not update the alias")
+
+	_, err = vtctld.AddCellsAlias(ctx, &vtctldatapb.AddCellsAliasRequest{Name: "other", Cells: []string{"zone2", "zone3"}})
+	assert.ErrorContains(t, err, "cells alias other overlaps with alias zone on cells [zone2]")
+	_, err = vtctld.AddCellsAlias(ctx, &vtctldatapb.AddCellsAliasRequest{Name: "other", Cells: []string{"zone2", "zone3"}, Force: true})
+	require.NoError(t, err)
+	ca, err = ts.GetCellsAlias(ctx, "other", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zone2", "zone3"}, ca.Cells)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2", "zone3")
+	tablets := []*topodatapb.Tablet{
+		{
+			Keyspace: "ks1",
 			Shard:    "-",
 			Type:     topodatapb.TabletType_PRIMARY,
 			Alias: &topodatapb.TabletAlias{
@@ -13774,6 +14585,147 @@ func TestValidate(t *testing.T) {
 	}, resp)
 }
 
+func TestValidateKeyspaceReplicationGraphs(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	tablets := []*topodatapb.Tablet{
+		{
+			Keyspace: "ks1",
+			Shard:    "-80",
+			Type:     topodatapb.TabletType_PRIMARY,
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+		},
+		{
+			Keyspace: "ks1",
+			Shard:    "-80",
+			Type:     topodatapb.TabletType_REPLICA,
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone2",
+				Uid:  200,
+			},
+		},
+		{
+			Keyspace: "ks1",
+			Shard:    "80-",
+			Type:     topodatapb.TabletType_PRIMARY,
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  101,
+			},
+		},
+		{
+			Keyspace: "ks2",
+			Shard:    "-",
+			Type:     topodatapb.TabletType_REPLICA,
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  300,
+			},
+		},
+	}
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary: true,
+	}, tablets...)
+
+	// zone2-200 is in no graph, the graph of ks1/-80 in zone1 has a tablet
+	// that doesn't exist, and that of ks1/80- has a tablet of ks2.
+	require.NoError(t, topo.RemoveShardReplicationRecord(ctx, ts, "zone2", "ks1", "-80", tablets[1].Alias))
+	require.NoError(t, topo.UpdateShardReplicationRecord(ctx, ts, "ks1", "-80", &topodatapb.TabletAlias{Cell: "zone1", Uid: 199}))
+	require.NoError(t, topo.UpdateShardReplicationRecord(ctx, ts, "ks1", "80-", tablets[3].Alias))
+
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+	resp, err := vtctld.ValidateKeyspace(ctx, &vtctldatapb.ValidateKeyspaceRequest{
+		Keyspace: "ks1",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Results)
+	assert.Contains(t, resp.ResultsByShard["-80"].Results, "tablet zone2-0000000200 of shard ks1/-80 is in no replication graph, run 'ShardReplicationFix zone2 ks1/-80' to fix it")
+	assert.Contains(t, resp.ResultsByShard["-80"].Results, "replication graph of shard ks1/-80 in cell zone1 has tablet zone1-0000000199, which does not exist, run 'ShardReplicationFix zone1 ks1/-80' to fix it")
+	assert.Contains(t, resp.ResultsByShard["80-"].Results, "replication graph of shard ks1/80- in cell zone1 has tablet zone1-0000000300, which belongs to shard ks2/- in cell zone1, run 'ShardReplicationFix zone1 ks1/80-' to fix it")
+
+	// The other keyspace is fine.
+	resp, err = vtctld.ValidateKeyspace(ctx, &vtctldatapb.ValidateKeyspaceRequest{
+		Keyspace: "ks2",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &vtctldatapb.ValidateKeyspaceResponse{
+		ResultsByShard: map[string]*vtctldatapb.ValidateShardResponse{
+			"-": {Results: []string{"no primary for shard ks2/-"}},
+		},
+	}, resp)
+}
+
+func TestValidateKeyspaceSrvKeyspacePartitions(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	testutil.AddShards(ctx, t, ts,
+		&vtctldatapb.Shard{Keyspace: "ks", Name: "-80"},
+		&vtctldatapb.Shard{Keyspace: "ks", Name: "80-"},
+		&vtctldatapb.Shard{Keyspace: "ks", Name: "80-c0"},
+	)
+
+	shardRef := func(name string) *topodatapb.ShardReference {
+		keyRange, err := key.ParseShardingSpec(name)
+		require.NoError(t, err)
+		return &topodatapb.ShardReference{Name: name, KeyRange: keyRange[0]}
+	}
+	// In zone1, 80-c0 has taken over the reads of half of 80-, but nothing
+	// serves the other half. zone2 is fine.
+	require.NoError(t, ts.UpdateSrvKeyspace(ctx, "zone1", "ks", &topodatapb.SrvKeyspace{
+		Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{
+			{
+				ServedType:      topodatapb.TabletType_PRIMARY,
+				ShardReferences: []*topodatapb.ShardReference{shardRef("-80"), shardRef("80-")},
+			},
+			{
+				ServedType:      topodatapb.TabletType_REPLICA,
+				ShardReferences: []*topodatapb.ShardReference{shardRef("-80"), shardRef("80-c0")},
+			},
+		},
+	}))
+	require.NoError(t, ts.UpdateSrvKeyspace(ctx, "zone2", "ks", &topodatapb.SrvKeyspace{
+		Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{
+			{
+				ServedType:      topodatapb.TabletType_PRIMARY,
+				ShardReferences: []*topodatapb.ShardReference{shardRef("-80"), shardRef("80-")},
+			},
+		},
+	}))
+
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+	resp, err := vtctld.ValidateKeyspace(ctx, &vtctldatapb.ValidateKeyspaceRequest{
+		Keyspace: "ks",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SrvKeyspace of keyspace ks in cell zone1 has a gap: no shard serves c0- for REPLICA"}, resp.Results)
+
+	// 80-c0 overlaps 80-, so it isn't serving, and a rebuild would serve
+	// every tablet type from -80 and 80-.
+	resp, err = vtctld.ValidateKeyspace(ctx, &vtctldatapb.ValidateKeyspaceRequest{
+		Keyspace:         "ks",
+		CheckSrvKeyspace: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"SrvKeyspace of keyspace ks in cell zone1 has a gap: no shard serves c0- for REPLICA",
+		"SrvKeyspace of keyspace ks in cell zone1 doesn't match the shard records: REPLICA partition has missing shards 80-, unexpected shards 80-c0; RDONLY partition has missing shards -80, 80-; run 'RebuildKeyspaceGraph --cells=zone1 ks' to rebuild it",
+		"SrvKeyspace of keyspace ks in cell zone2 doesn't match the shard records: REPLICA partition has missing shards -80, 80-; RDONLY partition has missing shards -80, 80-; run 'RebuildKeyspaceGraph --cells=zone2 ks' to rebuild it",
+	}, resp.Results)
+}
+
 func TestValidateSchemaKeyspace(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -13924,6 +14876,8 @@ func TestValidateSchemaKeyspace(t *testing.T) {
 				ResultsByShard: map[string]*vtctldatapb.ValidateShardResponse{
 					"-": {Results: []string{}},
 				},
+				ReferenceTablet: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+				ReferenceReason: "zone1-0000000100 is the primary of shard -, the only shard whose primary schema could be read",
 			},
 			setup: func() {
 				setupSchema(&topodatapb.TabletAlias{
@@ -13946,6 +14900,8 @@ func TestValidateSchemaKeyspace(t *testing.T) {
 				ResultsByShard: map[string]*vtctldatapb.ValidateShardResponse{
 					"-": {Results: []string{"zone1-0000000100 has an extra table named not_in_vschema"}},
 				},
+				ReferenceTablet: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+				ReferenceReason: "zone1-0000000100 is the primary of shard -, the only shard whose primary schema could be read",
 			},
 			setup: func() {
 				setupSchema(&topodatapb.TabletAlias{
@@ -13970,6 +14926,8 @@ func TestValidateSchemaKeyspace(t *testing.T) {
 					"-80": {Results: []string{"no primary in shard ks2/-80"}},
 					"80-": {Results: []string{}},
 				},
+				ReferenceTablet: &topodatapb.TabletAlias{Cell: "zone1", Uid: 103},
+				ReferenceReason: "zone1-0000000103 is the primary of shard 80-, the only shard whose primary schema could be read",
 			},
 			setup: func() {
 				setupSchema(&topodatapb.TabletAlias{
@@ -13995,10 +14953,309 @@ func TestValidateSchemaKeyspace(t *testing.T) {
 
 			assert.NoError(t, err)
 			utils.MustMatch(t, tt.expected, resp)
+
+			// The streaming variant finds the same results.
+			stream, err := localvtctldclient.New(vtctld).ValidateSchemaKeyspaceStream(ctx, tt.req)
+			require.NoError(t, err)
+			findings, err := vtctldclient.CollectValidationFindings(stream)
+			require.NoError(t, err)
+			var messages []string
+			for _, finding := range findings {
+				messages = append(messages, finding.Message)
+			}
+			assert.ElementsMatch(t, tt.expected.Results, messages)
 		})
 	}
 }
 
+func TestValidateSchemaKeyspaceReference(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	tmc := testutil.TabletManagerClient{
+		GetSchemaResults: map[string]struct {
+			Schema *tabletmanagerdatapb.SchemaDefinition
+			Error  error
+		}{},
+	}
+	testutil.AddKeyspace(ctx, t, ts, &vtctldatapb.Keyspace{
+		Name:     "ks",
+		Keyspace: &topodatapb.Keyspace{},
+	})
+
+	schema := &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+			Name:   "t1",
+			Schema: "CREATE TABLE `t1` (`id` bigint NOT NULL, PRIMARY KEY (`id`))",
+		}},
+	}
+	driftedSchema := &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+			Name:   "t1",
+			Schema: "CREATE TABLE `t1` (`id` bigint NOT NULL, `c1` int, PRIMARY KEY (`id`))",
+		}},
+	}
+
+	// The primary of the first shard is the one that drifted.
+	for i, shard := range []string{"-40", "40-80", "80-"} {
+		tablet := &topodatapb.Tablet{
+			Keyspace: "ks",
+			Shard:    shard,
+			Type:     topodatapb.TabletType_PRIMARY,
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: uint32(100 + i)},
+		}
+		testutil.AddTablet(ctx, t, ts, tablet, &testutil.AddTabletOptions{AlsoSetShardPrimary: true})
+
+		tabletSchema := schema
+		if shard == "-40" {
+			tabletSchema = driftedSchema
+		}
+		tmc.GetSchemaResults[topoproto.TabletAliasString(tablet.Alias)] = struct {
+			Schema *tabletmanagerdatapb.SchemaDefinition
+			Error  error
+		}{Schema: tabletSchema}
+	}
+
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	diff := func(reference, other *topodatapb.TabletAlias, referenceSchema, otherSchema *tabletmanagerdatapb.SchemaDefinition) string {
+		return fmt.Sprintf("schemas differ on table t1:\n%v: %v\n differs from:\n%v: %v", topoproto.TabletAliasString(reference), referenceSchema.TableDefinitions[0].Schema, topoproto.TabletAliasString(other), otherSchema.TableDefinitions[0].Schema)
+	}
+	primary := func(uid uint32) *topodatapb.TabletAlias {
+		return &topodatapb.TabletAlias{Cell: "zone1", Uid: uid}
+	}
+
+	tests := []struct {
+		name     string
+		req      *vtctldatapb.ValidateSchemaKeyspaceRequest
+		expected *vtctldatapb.ValidateSchemaKeyspaceResponse
+		err      string
+	}{
+		{
+			name: "majority schema",
+			req: &vtctldatapb.ValidateSchemaKeyspaceRequest{
+				Keyspace: "ks",
+			},
+			expected: &vtctldatapb.ValidateSchemaKeyspaceResponse{
+				Results: []string{diff(primary(101), primary(100), schema, driftedSchema)},
+				ResultsByShard: map[string]*vtctldatapb.ValidateShardResponse{
+					"-40":   {Results: []string{diff(primary(101), primary(100), schema, driftedSchema)}},
+					"40-80": {Results: []string{}},
+					"80-":   {Results: []string{}},
+				},
+				ReferenceTablet: primary(101),
+				ReferenceReason: "zone1-0000000101 is the primary of shard 40-80, which has the schema of the majority of the shard primaries (2 of 3)",
+				OutlierShards:   []string{"-40"},
+			},
+		},
+		{
+			name: "tie",
+			req: &vtctldatapb.ValidateSchemaKeyspaceRequest{
+				Keyspace: "ks",
+				Shards:   []string{"40-80", "-40"},
+			},
+			expected: &vtctldatapb.ValidateSchemaKeyspaceResponse{
+				Results: []string{diff(primary(100), primary(101), driftedSchema, schema)},
+				ResultsByShard: map[string]*vtctldatapb.ValidateShardResponse{
+					"-40":   {Results: []string{}},
+					"40-80": {Results: []string{diff(primary(100), primary(101), driftedSchema, schema)}},
+				},
+				ReferenceTablet: primary(100),
+				ReferenceReason: "no schema is shared by the majority of the shard primaries, zone1-0000000100 is the primary of shard -40, the first shard with the most common schema (1 of 2)",
+				OutlierShards:   []string{"40-80"},
+			},
+		},
+		{
+			name: "reference shard",
+			req: &vtctldatapb.ValidateSchemaKeyspaceRequest{
+				Keyspace:       "ks",
+				ReferenceShard: "-40",
+			},
+			expected: &vtctldatapb.ValidateSchemaKeyspaceResponse{
+				Results: []string{
+					diff(primary(100), primary(101), driftedSchema, schema),
+					diff(primary(100), primary(102), driftedSchema, schema),
+				},
+				ResultsByShard: map[string]*vtctldatapb.ValidateShardResponse{
+					"-40":   {Results: []string{}},
+					"40-80": {Results: []string{diff(primary(100), primary(101), driftedSchema, schema)}},
+					"80-":   {Results: []string{diff(primary(100), primary(102), driftedSchema, schema)}},
+				},
+				ReferenceTablet: primary(100),
+				ReferenceReason: "zone1-0000000100 is the primary of the requested reference shard -40",
+			},
+		},
+		{
+			name: "reference tablet",
+			req: &vtctldatapb.ValidateSchemaKeyspaceRequest{
+				Keyspace:        "ks",
+				ReferenceTablet: primary(102),
+			},
+			expected: &vtctldatapb.ValidateSchemaKeyspaceResponse{
+				Results: []string{diff(primary(102), primary(100), schema, driftedSchema)},
+				ResultsByShard: map[string]*vtctldatapb.ValidateShardResponse{
+					"-40":   {Results: []string{diff(primary(102), primary(100), schema, driftedSchema)}},
+					"40-80": {Results: []string{}},
+					"80-":   {Results: []string{}},
+				},
+				ReferenceTablet: primary(102),
+				ReferenceReason: "zone1-0000000102 is the requested reference tablet",
+			},
+		},
+		{
+			name: "reference shard not validated",
+			req: &vtctldatapb.ValidateSchemaKeyspaceRequest{
+				Keyspace:       "ks",
+				Shards:         []string{"40-80", "80-"},
+				ReferenceShard: "-40",
+			},
+			err: "reference shard ks/-40 is not one of the validated shards",
+		},
+		{
+			name: "reference shard and tablet",
+			req: &vtctldatapb.ValidateSchemaKeyspaceRequest{
+				Keyspace:        "ks",
+				ReferenceShard:  "-40",
+				ReferenceTablet: primary(102),
+			},
+			err: "only one of reference_shard and reference_tablet can be set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := vtctld.ValidateSchemaKeyspace(ctx, tt.req)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
+// schemaRetentionTMC is a TabletManagerClient that returns a distinct copy
+// of the schema to each caller, and records the most heap in use once a
+// schema is fetched.
+type schemaRetentionTMC struct {
+	testutil.TabletManagerClient
+
+	mu      sync.Mutex
+	maxHeap uint64
+}
+
+func (fake *schemaRetentionTMC) GetSchema(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetSchemaRequest) (*tabletmanagerdatapb.SchemaDefinition, error) {
+	time.Sleep(time.Millisecond)
+	sd, err := fake.TabletManagerClient.GetSchema(ctx, tablet, request)
+	sd = sd.CloneVT()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	fake.maxHeap = max(fake.maxHeap, stats.HeapAlloc)
+	return sd, err
+}
+
+func TestValidateSchemaKeyspaceLargeSchema(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	tmc := &schemaRetentionTMC{
+		TabletManagerClient: testutil.TabletManagerClient{
+			GetSchemaResults: map[string]struct {
+				Schema *tabletmanagerdatapb.SchemaDefinition
+				Error  error
+			}{},
+		},
+	}
+
+	// A large schema, of which one tablet has a different version of a
+	// table.
+	schema := &tabletmanagerdatapb.SchemaDefinition{}
+	for i := range 2000 {
+		schema.TableDefinitions = append(schema.TableDefinitions, &tabletmanagerdatapb.TableDefinition{
+			Name:              fmt.Sprintf("t%04d", i),
+			Schema:            fmt.Sprintf("CREATE TABLE `t%04d` (`c1` bigint, `c2` varchar(255), PRIMARY KEY (`c1`))", i),
+			Columns:           []string{"c1", "c2"},
+			PrimaryKeyColumns: []string{"c1"},
+			Type:              tmutils.TableBaseTable,
+			Fields:            sqltypes.MakeTestFields("c1|c2", "int64|varchar"),
+		})
+	}
+	otherSchema := schema.CloneVT()
+	otherSchema.TableDefinitions[1000].Schema = "CREATE TABLE `t1000` (`c1` bigint, PRIMARY KEY (`c1`))"
+
+	testutil.AddKeyspace(ctx, t, ts, &vtctldatapb.Keyspace{
+		Name:     "ks",
+		Keyspace: &topodatapb.Keyspace{},
+	})
+	uid := uint32(100)
+	for _, shard := range []string{"-80", "80-"} {
+		for i := range 20 {
+			tablet := &topodatapb.Tablet{
+				Keyspace: "ks",
+				Shard:    shard,
+				Type:     topodatapb.TabletType_REPLICA,
+				Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: uid},
+			}
+			if i == 0 {
+				tablet.Type = topodatapb.TabletType_PRIMARY
+			}
+			testutil.AddTablet(ctx, t, ts, tablet, &testutil.AddTabletOptions{AlsoSetShardPrimary: true})
+
+			tabletSchema := schema
+			if uid == 125 {
+				tabletSchema = otherSchema
+			}
+			tmc.GetSchemaResults[topoproto.TabletAliasString(tablet.Alias)] = struct {
+				Schema *tabletmanagerdatapb.SchemaDefinition
+				Error  error
+			}{Schema: tabletSchema}
+			uid++
+		}
+	}
+
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	// The heap one more copy of the schema takes.
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	schemaCopy := schema.CloneVT()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	require.Greater(t, after.HeapAlloc, before.HeapAlloc)
+	schemaSize := after.HeapAlloc - before.HeapAlloc
+	runtime.KeepAlive(schemaCopy)
+
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	resp, err := vtctld.ValidateSchemaKeyspace(ctx, &vtctldatapb.ValidateSchemaKeyspaceRequest{
+		Keyspace: "ks",
+	})
+	require.NoError(t, err)
+
+	want := fmt.Sprintf("schemas differ on table t1000:\nzone1-0000000100: %v\n differs from:\nzone1-0000000125: %v", schema.TableDefinitions[1000].Schema, otherSchema.TableDefinitions[1000].Schema)
+	require.Equal(t, []string{want}, resp.Results)
+	require.Empty(t, resp.ResultsByShard["-80"].Results)
+	require.Equal(t, []string{want}, resp.ResultsByShard["80-"].Results)
+
+	// Only the schemas being compared, and the reference, are retained,
+	// rather than one per tablet: fetching the schemas of the 39 other
+	// tablets never holds more than about validateSchemaKeyspaceConcurrency
+	// copies at once.
+	retained := tmc.maxHeap - min(tmc.maxHeap, before.HeapAlloc)
+	require.Less(t, retained, 2*validateSchemaKeyspaceConcurrency*schemaSize, "retained %d bytes, for schemas of %d bytes", retained, schemaSize)
+}
+
 func TestValidateVersionKeyspace(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -14086,10 +15343,16 @@ func TestValidateVersionKeyspace(t *testing.T) {
 				Keyspace: "ks1",
 			},
 			expected: &vtctldatapb.ValidateVersionKeyspaceResponse{
-				Results: []string{"primary zone1-0000000100 version version:\"version1\" is different than replica zone1-0000000101 version version:\"version2\""},
+				Results: []string{"primary zone1-0000000100 version version1 is different than replica zone1-0000000101 version version2"},
 				ResultsByShard: map[string]*vtctldatapb.ValidateShardResponse{
-					"-": {Results: []string{"primary zone1-0000000100 version version:\"version1\" is different than replica zone1-0000000101 version version:\"version2\""}},
+					"-": {Results: []string{"primary zone1-0000000100 version version1 is different than replica zone1-0000000101 version version2"}},
 				},
+				Findings: []*vtctldatapb.ValidationFinding{{
+					Severity:    vtctldatapb.ValidationFinding_ERROR,
+					Shard:       "-",
+					TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+					Message:     "primary zone1-0000000100 version version1 is different than replica zone1-0000000101 version version2",
+				}},
 			},
 			setup: func() {
 				addrVersionMap := map[string]string{
@@ -14100,6 +15363,20 @@ func TestValidateVersionKeyspace(t *testing.T) {
 			},
 			shouldErr: false,
 		},
+		{
+			name: "unknown reference shard",
+			req: &vtctldatapb.ValidateVersionKeyspaceRequest{
+				Keyspace:       "ks1",
+				Shards:         []string{"-"},
+				ReferenceShard: "-80",
+			},
+			expected: &vtctldatapb.ValidateVersionKeyspaceResponse{
+				Results:        []string{"reference shard ks1/-80 is not one of the validated shards"},
+				ResultsByShard: map[string]*vtctldatapb.ValidateShardResponse{},
+			},
+			setup:     func() {},
+			shouldErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -14113,6 +15390,19 @@ func TestValidateVersionKeyspace(t *testing.T) {
 
 			assert.NoError(t, err)
 			utils.MustMatch(t, tt.expected, resp)
+
+			// The streaming variant has the same findings, and fails if the
+			// validation can't start.
+			stream, err := localvtctldclient.New(vtctld).ValidateVersionKeyspaceStream(ctx, tt.req)
+			require.NoError(t, err)
+			findings, err := vtctldclient.CollectValidationFindings(stream)
+			if len(tt.expected.Findings) == 0 && len(tt.expected.Results) > 0 {
+				assert.ErrorContains(t, err, tt.expected.Results[0])
+				return
+			}
+
+			assert.NoError(t, err)
+			utils.MustMatch(t, tt.expected.Findings, findings)
 		})
 	}
 }
@@ -14281,6 +15571,53 @@ func TestValidateShard(t *testing.T) {
 			},
 			expected: &vtctldatapb.ValidateShardResponse{},
 		},
+		{
+			name: "tablet in the replication graph of another cell",
+			ts:   memorytopo.NewServer(ctx, "zone1", "zone2"),
+			tmc:  nil,
+			setup: func(t *testing.T, tt *testcase) {
+				tablets := []*topodatapb.Tablet{
+					{
+						Keyspace: "ks1",
+						Shard:    "-",
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  100,
+						},
+						Type:     topodatapb.TabletType_PRIMARY,
+						Hostname: "ks1-primary",
+					},
+					{
+						Keyspace: "ks1",
+						Shard:    "-",
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  101,
+						},
+						Type:     topodatapb.TabletType_REPLICA,
+						Hostname: "ks1-replica",
+					},
+				}
+				testutil.AddTablets(ctx, t, tt.ts, &testutil.AddTabletOptions{
+					AlsoSetShardPrimary: true,
+				}, tablets...)
+				err := tt.ts.UpdateShardReplicationFields(ctx, "zone2", "ks1", "-", func(sr *topodatapb.ShardReplication) error {
+					sr.Nodes = append(sr.Nodes, &topodatapb.ShardReplication_Node{TabletAlias: tablets[1].Alias})
+					return nil
+				})
+				require.NoError(t, err)
+			},
+			req: &vtctldatapb.ValidateShardRequest{
+				Keyspace: "ks1",
+				Shard:    "-",
+			},
+			expected: &vtctldatapb.ValidateShardResponse{
+				Results: []string{
+					"tablet zone1-0000000101 is in the replication graph of ks1/- in cell zone2 instead of cell zone1, run 'ShardReplicationFix zone2 ks1/-' to remove it",
+					"tablet zone1-0000000101 is in the replication graphs of ks1/- in cells zone1, zone2, run 'ShardReplicationFix zone2 ks1/-' to keep it only in cell zone1",
+				},
+			},
+		},
 		{
 			name: "no shard",
 			ts:   memorytopo.NewServer(ctx, "zone1"),
@@ -14770,6 +16107,68 @@ func TestValidateShard(t *testing.T) {
 		})
 	}
 }
+func TestValidateShardPingTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	tmc := &testutil.TabletManagerClient{
+		GetReplicasResults: map[string]struct {
+			Replicas []string
+			Error    error
+		}{
+			"zone1-0000000100": {},
+		},
+		PingDelays:  map[string]time.Duration{},
+		PingResults: map[string]error{"zone1-0000000100": nil},
+	}
+	tablets := []*topodatapb.Tablet{{
+		Keyspace:      "ks1",
+		Shard:         "-",
+		Alias:         &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		Type:          topodatapb.TabletType_PRIMARY,
+		MysqlHostname: "10.0.0.100",
+	}}
+	// Many more dead replicas than workers.
+	var expected []string
+	for uid := uint32(101); uid <= 140; uid++ {
+		tablet := &topodatapb.Tablet{
+			Keyspace:      "ks1",
+			Shard:         "-",
+			Alias:         &topodatapb.TabletAlias{Cell: "zone1", Uid: uid},
+			Type:          topodatapb.TabletType_REPLICA,
+			Hostname:      fmt.Sprintf("replica-%d", uid),
+			MysqlHostname: fmt.Sprintf("10.0.0.%d", uid),
+		}
+		tablets = append(tablets, tablet)
+		alias := topoproto.TabletAliasString(tablet.Alias)
+		tmc.PingDelays[alias] = time.Hour
+		r := tmc.GetReplicasResults["zone1-0000000100"]
+		r.Replicas = append(r.Replicas, tablet.MysqlHostname)
+		tmc.GetReplicasResults["zone1-0000000100"] = r
+		expected = append(expected, fmt.Sprintf("Ping(%v) failed: context deadline exceeded tablet hostname: %v", alias, tablet.Hostname))
+	}
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary: true,
+	}, tablets...)
+
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+	start := time.Now()
+	resp, err := vtctld.ValidateShard(ctx, &vtctldatapb.ValidateShardRequest{
+		Keyspace:    "ks1",
+		Shard:       "-",
+		PingTablets: true,
+		PingTimeout: protoutil.DurationToProto(100 * time.Millisecond),
+	})
+	require.NoError(t, err)
+	// Serial pings would take 4s.
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.ElementsMatch(t, expected, resp.Results)
+}
+
 func TestMain(m *testing.M) {
 	_flag.ParseFlagsForTest()
 	os.Exit(m.Run())
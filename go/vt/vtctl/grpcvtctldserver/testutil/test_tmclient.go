@@ -311,7 +311,12 @@ type TabletManagerClient struct {
 	// keyed by `<tablet_alias>/<wait_pos>`.
 	ReloadSchemaDelays map[string]time.Duration
 	// keyed by `<tablet_alias>/<wait_pos>`.
-	ReloadSchemaResults      map[string]error
+	ReloadSchemaResults map[string]error
+	// keyed by tablet alias.
+	ReloadTabletConfigResults map[string]struct {
+		Hash  string
+		Error error
+	}
 	ReplicationStatusDelays  map[string]time.Duration
 	ReplicationStatusResults map[string]struct {
 		Position *replicationdatapb.Status
@@ -388,6 +393,8 @@ type TabletManagerClient struct {
 	CheckThrottlerDelays map[string]time.Duration
 	// keyed by tablet alias
 	CheckThrottlerResults map[string]*tabletmanagerdatapb.CheckThrottlerResponse
+	// keyed by tablet alias
+	GetThrottlerStatusResults map[string]*tabletmanagerdatapb.GetThrottlerStatusResponse
 }
 
 type backupStreamAdapter struct {
@@ -1062,6 +1069,20 @@ func (fake *TabletManagerClient) ReloadSchema(ctx context.Context, tablet *topod
 	return fmt.Errorf("%w: no ReloadSchema result set for tablet %s", assert.AnError, key)
 }
 
+// ReloadTabletConfig is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) ReloadTabletConfig(ctx context.Context, tablet *topodatapb.Tablet, category tabletmanagerdatapb.TabletConfigCategory) (string, error) {
+	if fake.ReloadTabletConfigResults == nil {
+		return "", fmt.Errorf("%w: no ReloadTabletConfig results on fake TabletManagerClient", assert.AnError)
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if result, ok := fake.ReloadTabletConfigResults[key]; ok {
+		return result.Hash, result.Error
+	}
+
+	return "", fmt.Errorf("%w: no ReloadTabletConfig result set for tablet %s", assert.AnError, key)
+}
+
 // ReplicationStatus is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) ReplicationStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.Status, error) {
 	if fake.ReplicationStatusResults == nil {
@@ -1603,3 +1624,20 @@ func (fake *TabletManagerClient) CheckThrottler(ctx context.Context, tablet *top
 
 	return nil, assert.AnError
 }
+
+// GetThrottlerStatus is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) GetThrottlerStatus(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.GetThrottlerStatusRequest) (*tabletmanagerdatapb.GetThrottlerStatusResponse, error) {
+	if fake.GetThrottlerStatusResults == nil {
+		return nil, assert.AnError
+	}
+
+	if tablet.Alias == nil {
+		return nil, assert.AnError
+	}
+
+	if result, ok := fake.GetThrottlerStatusResults[topoproto.TabletAliasString(tablet.Alias)]; ok {
+		return result, nil
+	}
+
+	return nil, assert.AnError
+}
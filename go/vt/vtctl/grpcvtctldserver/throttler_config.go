@@ -0,0 +1,181 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtctldserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// throttlerConfigAckInterval is how often the throttlers of the tablets that
+// haven't acknowledged a throttler configuration yet are checked again.
+var throttlerConfigAckInterval = time.Second
+
+// updateKeyspaceThrottlerConfig applies update to the throttler configuration
+// of the keyspace, and to the one of its SrvKeyspace in every cell, which the
+// tablets watch. It returns the updated configuration of the keyspace.
+func (s *VtctldServer) updateKeyspaceThrottlerConfig(ctx context.Context, keyspace string, update func(*topodatapb.ThrottlerConfig) *topodatapb.ThrottlerConfig) (config *topodatapb.ThrottlerConfig, err error) {
+	ctx, unlock, lockErr := s.ts.LockKeyspace(ctx, keyspace, "UpdateThrottlerConfig")
+	if lockErr != nil {
+		return nil, lockErr
+	}
+	defer unlock(&err)
+
+	ki, err := s.ts.GetKeyspace(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	ki.ThrottlerConfig = update(ki.ThrottlerConfig)
+
+	if err = s.ts.UpdateKeyspace(ctx, ki); err != nil {
+		return nil, err
+	}
+
+	_, err = s.ts.UpdateSrvKeyspaceThrottlerConfig(ctx, keyspace, []string{}, update)
+	return ki.ThrottlerConfig, err
+}
+
+// throttlerConfigAcknowledgements reports, in tablet alias order, whether the
+// throttler of each tablet in the keyspace has applied config. If wait is
+// positive, the tablets that haven't are checked again until they do, or
+// until wait has elapsed.
+func (s *VtctldServer) throttlerConfigAcknowledgements(ctx context.Context, keyspace string, config *topodatapb.ThrottlerConfig, wait time.Duration) ([]*vtctldatapb.ThrottlerConfigAcknowledgement, error) {
+	shards, err := s.ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	var tablets []*topodatapb.Tablet
+	for _, shard := range shards {
+		tabletMap, err := s.ts.GetTabletMapForShard(ctx, keyspace, shard)
+		if err != nil && !topo.IsErrType(err, topo.PartialResult) {
+			return nil, fmt.Errorf("GetTabletMapForShard(%s, %s) failed: %w", keyspace, shard, err)
+		}
+		for _, ti := range tabletMap {
+			tablets = append(tablets, ti.Tablet)
+		}
+	}
+	sort.Slice(tablets, func(i, j int) bool {
+		return topoproto.TabletAliasString(tablets[i].Alias) < topoproto.TabletAliasString(tablets[j].Alias)
+	})
+
+	deadline := time.Now().Add(wait)
+	acks := make([]*vtctldatapb.ThrottlerConfigAcknowledgement, len(tablets))
+	wg := sync.WaitGroup{}
+	for i, tablet := range tablets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			acks[i] = s.waitForThrottlerConfig(ctx, tablet, config, deadline)
+		}()
+	}
+	wg.Wait()
+
+	return acks, nil
+}
+
+// waitForThrottlerConfig checks whether the throttler of the tablet has
+// applied config until it has, or until the deadline.
+func (s *VtctldServer) waitForThrottlerConfig(ctx context.Context, tablet *topodatapb.Tablet, config *topodatapb.ThrottlerConfig, deadline time.Time) *vtctldatapb.ThrottlerConfigAcknowledgement {
+	ack := &vtctldatapb.ThrottlerConfigAcknowledgement{
+		TabletAlias: tablet.Alias,
+	}
+	for {
+		status, err := s.tmc.GetThrottlerStatus(ctx, tablet, &tabletmanagerdatapb.GetThrottlerStatusRequest{})
+		if err != nil {
+			ack.Reason = fmt.Sprintf("failed to get the throttler status: %v", err)
+		} else if ack.Reason = throttlerConfigMismatch(config, status); ack.Reason == "" {
+			ack.Acknowledged = true
+			return ack
+		}
+
+		if time.Now().Add(throttlerConfigAckInterval).After(deadline) {
+			return ack
+		}
+		select {
+		case <-ctx.Done():
+			return ack
+		case <-time.After(throttlerConfigAckInterval):
+		}
+	}
+}
+
+// throttlerConfigMismatch describes how the status of a throttler differs
+// from config, or returns an empty string if the throttler has applied it.
+// Thresholds left to the tablet's defaults, and apps the tablet throttles
+// on its own, are not compared.
+func throttlerConfigMismatch(config *topodatapb.ThrottlerConfig, status *tabletmanagerdatapb.GetThrottlerStatusResponse) string {
+	if config == nil {
+		config = &topodatapb.ThrottlerConfig{}
+	}
+	if !status.IsOpen {
+		return "throttler is not open"
+	}
+
+	var mismatches []string
+	if status.IsEnabled != config.Enabled {
+		mismatches = append(mismatches, fmt.Sprintf("enabled is %t, want %t", status.IsEnabled, config.Enabled))
+	}
+	if status.CustomMetricQuery != config.CustomQuery {
+		mismatches = append(mismatches, fmt.Sprintf("custom query is %q, want %q", status.CustomMetricQuery, config.CustomQuery))
+	}
+	if (config.Threshold > 0 || config.CustomQuery != "") && status.DefaultThreshold != config.Threshold {
+		mismatches = append(mismatches, fmt.Sprintf("threshold is %v, want %v", status.DefaultThreshold, config.Threshold))
+	}
+	for name, threshold := range config.MetricThresholds {
+		if got, ok := status.MetricThresholds[name]; !ok || got != threshold {
+			mismatches = append(mismatches, fmt.Sprintf("%s threshold is %v, want %v", name, got, threshold))
+		}
+	}
+	for name, rule := range config.ThrottledApps {
+		if !protoutil.TimeFromProto(rule.ExpiresAt).After(time.Now()) {
+			continue
+		}
+		got, ok := status.ThrottledApps[name]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, fmt.Sprintf("app %s is not throttled", name))
+		case got.Ratio != rule.Ratio || got.Exempt != rule.Exempt:
+			mismatches = append(mismatches, fmt.Sprintf("app %s is throttled with ratio %v and exempt %t, want ratio %v and exempt %t",
+				name, got.Ratio, got.Exempt, rule.Ratio, rule.Exempt))
+		}
+	}
+	for app, metrics := range config.AppCheckedMetrics {
+		if len(metrics.Names) == 0 {
+			continue
+		}
+		if want := strings.Join(metrics.Names, ","); status.AppCheckedMetrics[app] != want {
+			mismatches = append(mismatches, fmt.Sprintf("app %s checks metrics %q, want %q", app, status.AppCheckedMetrics[app], want))
+		}
+	}
+
+	sort.Strings(mismatches)
+	return strings.Join(mismatches, "; ")
+}
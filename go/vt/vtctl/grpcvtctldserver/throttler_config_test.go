@@ -0,0 +1,219 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtctldserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver/testutil"
+	"vitess.io/vitess/go/vt/vtenv"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
+)
+
+func TestThrottlerConfigMismatch(t *testing.T) {
+	expiresAt := protoutil.TimeToProto(time.Now().Add(time.Hour))
+	config := &topodatapb.ThrottlerConfig{
+		Enabled:   true,
+		Threshold: 5,
+		MetricThresholds: map[string]float64{
+			"loadavg": 2,
+		},
+		ThrottledApps: map[string]*topodatapb.ThrottledAppRule{
+			"online-ddl": {Name: "online-ddl", Ratio: 0.5, ExpiresAt: expiresAt},
+			"expired":    {Name: "expired", Ratio: 1, ExpiresAt: protoutil.TimeToProto(time.Now().Add(-time.Hour))},
+		},
+		AppCheckedMetrics: map[string]*topodatapb.ThrottlerConfig_MetricNames{
+			"vreplication": {Names: []string{"lag", "loadavg"}},
+		},
+	}
+	applied := func() *tabletmanagerdatapb.GetThrottlerStatusResponse {
+		return &tabletmanagerdatapb.GetThrottlerStatusResponse{
+			IsOpen:           true,
+			IsEnabled:        true,
+			DefaultThreshold: 5,
+			MetricThresholds: map[string]float64{
+				"lag":     5,
+				"loadavg": 2,
+			},
+			ThrottledApps: map[string]*topodatapb.ThrottledAppRule{
+				"online-ddl":             {Name: "online-ddl", Ratio: 0.5, ExpiresAt: expiresAt},
+				"always-throttled-app":   {Name: "always-throttled-app", Ratio: 1, ExpiresAt: expiresAt},
+				"not-in-the-config-here": {Name: "not-in-the-config-here", Ratio: 1, ExpiresAt: expiresAt},
+			},
+			AppCheckedMetrics: map[string]string{
+				"vreplication": "lag,loadavg",
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		config *topodatapb.ThrottlerConfig
+		status func(status *tabletmanagerdatapb.GetThrottlerStatusResponse)
+		want   string
+	}{
+		{
+			name:   "applied",
+			config: config,
+		},
+		{
+			name:   "no config",
+			config: nil,
+			status: func(status *tabletmanagerdatapb.GetThrottlerStatusResponse) {
+				status.IsEnabled = false
+			},
+		},
+		{
+			name:   "not open",
+			config: config,
+			status: func(status *tabletmanagerdatapb.GetThrottlerStatusResponse) {
+				status.IsOpen = false
+			},
+			want: "throttler is not open",
+		},
+		{
+			name:   "stale",
+			config: config,
+			status: func(status *tabletmanagerdatapb.GetThrottlerStatusResponse) {
+				status.IsEnabled = false
+				status.DefaultThreshold = 1
+				status.MetricThresholds["loadavg"] = 1
+				status.ThrottledApps["online-ddl"].Exempt = true
+				status.AppCheckedMetrics["vreplication"] = "lag"
+			},
+			want: `app online-ddl is throttled with ratio 0.5 and exempt true, want ratio 0.5 and exempt false; ` +
+				`app vreplication checks metrics "lag", want "lag,loadavg"; ` +
+				`enabled is false, want true; ` +
+				`loadavg threshold is 1, want 2; ` +
+				`threshold is 1, want 5`,
+		},
+		{
+			name:   "app not throttled",
+			config: config,
+			status: func(status *tabletmanagerdatapb.GetThrottlerStatusResponse) {
+				delete(status.ThrottledApps, "online-ddl")
+			},
+			want: "app online-ddl is not throttled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := applied()
+			if tt.status != nil {
+				tt.status(status)
+			}
+			assert.Equal(t, tt.want, throttlerConfigMismatch(tt.config, status))
+		})
+	}
+}
+
+func TestThrottlerConfigAcknowledgements(t *testing.T) {
+	defer func(interval time.Duration) { throttlerConfigAckInterval = interval }(throttlerConfigAckInterval)
+	throttlerConfigAckInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	tmc := &testutil.TabletManagerClient{
+		GetThrottlerStatusResults: map[string]*tabletmanagerdatapb.GetThrottlerStatusResponse{
+			"zone1-0000000100": {IsOpen: true, IsEnabled: true},
+			"zone1-0000000101": {IsOpen: true},
+		},
+	}
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	testutil.AddTablets(ctx, t, ts, nil,
+		&topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			Keyspace: "ks",
+			Shard:    "-",
+			Type:     topodatapb.TabletType_REPLICA,
+		},
+		&topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+			Keyspace: "ks",
+			Shard:    "-",
+			Type:     topodatapb.TabletType_PRIMARY,
+		},
+		&topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 102},
+			Keyspace: "ks",
+			Shard:    "-",
+			Type:     topodatapb.TabletType_RDONLY,
+		},
+	)
+
+	wantAcks := []*vtctldatapb.ThrottlerConfigAcknowledgement{
+		{
+			TabletAlias:  &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+			Acknowledged: true,
+		},
+		{
+			TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			Reason:      "enabled is false, want true",
+		},
+		{
+			TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 102},
+			Reason:      "failed to get the throttler status: assert.AnError general error for testing",
+		},
+	}
+
+	// Without waiting, the update doesn't report acknowledgements.
+	updateResp, err := vtctld.UpdateThrottlerConfig(ctx, &vtctldatapb.UpdateThrottlerConfigRequest{
+		Keyspace: "ks",
+		Enable:   true,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, updateResp.Acknowledgements)
+
+	updateResp, err = vtctld.UpdateThrottlerConfig(ctx, &vtctldatapb.UpdateThrottlerConfigRequest{
+		Keyspace:               "ks",
+		Enable:                 true,
+		WaitForAcknowledgement: protoutil.DurationToProto(50 * time.Millisecond),
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, wantAcks, updateResp.Acknowledgements)
+
+	getResp, err := vtctld.GetThrottlerConfig(ctx, &vtctldatapb.GetThrottlerConfigRequest{
+		Keyspace: "ks",
+	})
+	require.NoError(t, err)
+	assert.True(t, getResp.ThrottlerConfig.Enabled)
+	utils.MustMatch(t, wantAcks, getResp.Acknowledgements)
+
+	_, err = vtctld.GetThrottlerConfig(ctx, &vtctldatapb.GetThrottlerConfigRequest{
+		Keyspace: "unknown",
+	})
+	assert.Error(t, err)
+}
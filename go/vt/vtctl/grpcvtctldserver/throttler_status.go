@@ -0,0 +1,102 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtctldserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// shardThrottlerStatus checks the throttler of the primary of the shard as
+// appName. Errors are reported in the returned status, so that a single
+// unreachable primary doesn't hide the status of the other shards.
+func (s *VtctldServer) shardThrottlerStatus(ctx context.Context, keyspace string, shard string, appName string) *vtctldatapb.ShardThrottlerStatus {
+	status := &vtctldatapb.ShardThrottlerStatus{
+		Shard: shard,
+	}
+
+	si, err := s.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	if si.PrimaryAlias == nil {
+		status.Error = fmt.Sprintf("shard %s/%s has no primary", keyspace, shard)
+		return status
+	}
+	status.PrimaryAlias = si.PrimaryAlias
+
+	ti, err := s.ts.GetTablet(ctx, si.PrimaryAlias)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	check, err := s.tmc.CheckThrottler(ctx, ti.Tablet, &tabletmanagerdatapb.CheckThrottlerRequest{
+		AppName:               appName,
+		SkipRequestHeartbeats: true,
+	})
+	if err != nil {
+		status.Error = fmt.Sprintf("failed to check the throttler: %v", err)
+		return status
+	}
+	throttlerStatus, err := s.tmc.GetThrottlerStatus(ctx, ti.Tablet, &tabletmanagerdatapb.GetThrottlerStatusRequest{})
+	if err != nil {
+		status.Error = fmt.Sprintf("failed to get the throttler status: %v", err)
+		return status
+	}
+
+	status.Throttled = check.ResponseCode != tabletmanagerdatapb.CheckThrottlerResponseCode_OK
+	status.Summary = check.Summary
+	for name, metric := range check.Metrics {
+		if metric.ResponseCode != tabletmanagerdatapb.CheckThrottlerResponseCode_THRESHOLD_EXCEEDED {
+			continue
+		}
+		if metric.Name == "" {
+			// Older tablets only return the default metric, without a name.
+			metric.Name = name
+		}
+		status.ExceededMetrics = append(status.ExceededMetrics, metric)
+	}
+	sort.Slice(status.ExceededMetrics, func(i, j int) bool {
+		return status.ExceededMetrics[i].Name < status.ExceededMetrics[j].Name
+	})
+
+	now := time.Now()
+	for name, rule := range throttlerStatus.ThrottledApps {
+		if name == throttlerapp.TestingAlwaysThrottledName.String() || rule.Exempt || rule.Ratio <= 0 {
+			continue
+		}
+		if !protoutil.TimeFromProto(rule.ExpiresAt).After(now) {
+			continue
+		}
+		status.ThrottledApps = append(status.ThrottledApps, rule)
+	}
+	sort.Slice(status.ThrottledApps, func(i, j int) bool {
+		return status.ThrottledApps[i].Name < status.ThrottledApps[j].Name
+	})
+
+	return status
+}
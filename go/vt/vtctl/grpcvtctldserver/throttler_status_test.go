@@ -0,0 +1,150 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtctldserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver/testutil"
+	"vitess.io/vitess/go/vt/vtenv"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
+)
+
+func TestGetKeyspaceThrottlerStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expiresAt := protoutil.TimeToProto(time.Now().Add(time.Hour))
+	lag := &tabletmanagerdatapb.CheckThrottlerResponse_Metric{
+		Name:         "lag",
+		Value:        12.5,
+		Threshold:    5,
+		ResponseCode: tabletmanagerdatapb.CheckThrottlerResponseCode_THRESHOLD_EXCEEDED,
+	}
+	ts := memorytopo.NewServer(ctx, "zone1")
+	tmc := &testutil.TabletManagerClient{
+		CheckThrottlerResults: map[string]*tabletmanagerdatapb.CheckThrottlerResponse{
+			"zone1-0000000100": {
+				ResponseCode: tabletmanagerdatapb.CheckThrottlerResponseCode_OK,
+				Metrics: map[string]*tabletmanagerdatapb.CheckThrottlerResponse_Metric{
+					"lag": {Name: "lag", Value: 1, Threshold: 5, ResponseCode: tabletmanagerdatapb.CheckThrottlerResponseCode_OK},
+				},
+			},
+			"zone1-0000000200": {
+				ResponseCode: tabletmanagerdatapb.CheckThrottlerResponseCode_THRESHOLD_EXCEEDED,
+				Summary:      "lag is over the threshold",
+				Metrics: map[string]*tabletmanagerdatapb.CheckThrottlerResponse_Metric{
+					"lag":     lag,
+					"loadavg": {Name: "loadavg", Value: 0.5, Threshold: 1, ResponseCode: tabletmanagerdatapb.CheckThrottlerResponseCode_OK},
+				},
+			},
+		},
+		GetThrottlerStatusResults: map[string]*tabletmanagerdatapb.GetThrottlerStatusResponse{
+			"zone1-0000000100": {},
+			"zone1-0000000200": {
+				ThrottledApps: map[string]*topodatapb.ThrottledAppRule{
+					"vreplication":         {Name: "vreplication", Ratio: 0.5, ExpiresAt: expiresAt},
+					"online-ddl":           {Name: "online-ddl", Ratio: 1, ExpiresAt: expiresAt},
+					"always-throttled-app": {Name: "always-throttled-app", Ratio: 1, ExpiresAt: expiresAt},
+					"exempt":               {Name: "exempt", Exempt: true, ExpiresAt: expiresAt},
+					"expired":              {Name: "expired", Ratio: 1, ExpiresAt: protoutil.TimeToProto(time.Now().Add(-time.Hour))},
+				},
+			},
+		},
+	}
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{AlsoSetShardPrimary: true},
+		&topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+			Keyspace: "ks",
+			Shard:    "-40",
+			Type:     topodatapb.TabletType_PRIMARY,
+		},
+		&topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+			Keyspace: "ks",
+			Shard:    "40-80",
+			Type:     topodatapb.TabletType_PRIMARY,
+		},
+		// The throttler of this primary can't be checked.
+		&topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 300},
+			Keyspace: "ks",
+			Shard:    "80-c0",
+			Type:     topodatapb.TabletType_PRIMARY,
+		},
+		// This shard has no primary.
+		&topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 401},
+			Keyspace: "ks",
+			Shard:    "c0-",
+			Type:     topodatapb.TabletType_REPLICA,
+		},
+	)
+
+	resp, err := vtctld.GetKeyspaceThrottlerStatus(ctx, &vtctldatapb.GetKeyspaceThrottlerStatusRequest{
+		Keyspace: "ks",
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, []*vtctldatapb.ShardThrottlerStatus{
+		{
+			Shard:        "-40",
+			PrimaryAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		},
+		{
+			Shard:           "40-80",
+			PrimaryAlias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+			Throttled:       true,
+			Summary:         "lag is over the threshold",
+			ExceededMetrics: []*tabletmanagerdatapb.CheckThrottlerResponse_Metric{lag},
+			ThrottledApps: []*topodatapb.ThrottledAppRule{
+				{Name: "online-ddl", Ratio: 1, ExpiresAt: expiresAt},
+				{Name: "vreplication", Ratio: 0.5, ExpiresAt: expiresAt},
+			},
+		},
+		{
+			Shard:        "80-c0",
+			PrimaryAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 300},
+			Error:        "failed to check the throttler: assert.AnError general error for testing",
+		},
+		{
+			Shard: "c0-",
+			Error: "shard ks/c0- has no primary",
+		},
+	}, resp.Shards)
+
+	_, err = vtctld.GetKeyspaceThrottlerStatus(ctx, &vtctldatapb.GetKeyspaceThrottlerStatusRequest{
+		Keyspace: "unknown",
+	})
+	assert.Error(t, err)
+}
@@ -18,9 +18,16 @@ package grpcvtctldserver
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
+	"vitess.io/vitess/go/sets"
 	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/topo"
@@ -29,9 +36,22 @@ import (
 	"vitess.io/vitess/go/vt/vterrors"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	"vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
+const (
+	// defaultTopoListPageSize is the number of entries TopoList returns when
+	// the request doesn't set a page size.
+	defaultTopoListPageSize = 100
+	// maxTopoListPageSize is the maximum number of entries TopoList returns.
+	maxTopoListPageSize = 1000
+)
+
+// maxTopoResponseSize is the maximum size, in bytes, of the data returned by
+// TopoGet and TopoList. It is a variable so tests can lower it.
+var maxTopoResponseSize = 4 << 20
+
 func deleteShard(ctx context.Context, ts *topo.Server, keyspace string, shard string, recursive bool, evenIfServing bool, force bool) (err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.deleteShard")
 	defer span.Finish()
@@ -107,6 +127,12 @@ func deleteShard(ctx context.Context, ts *topo.Server, keyspace string, shard st
 		}
 	}
 
+	// The tablets are deleted, so finish the deletion even if ctx is done by
+	// now, rather than leave the shard with the replication graphs of some
+	// cells only.
+	ctx, cancel := cleanupContext(ctx)
+	defer cancel()
+
 	// Try to remove the replication and serving graphs from each cell,
 	// regardless of whether they exist.
 	for _, cell := range cells {
@@ -119,6 +145,13 @@ func deleteShard(ctx context.Context, ts *topo.Server, keyspace string, shard st
 	return err
 }
 
+// cleanupContext returns the context to finish a deletion that already
+// started. It keeps the values of ctx, like the held locks, but is not
+// cancelled with it, and gets topo.RemoteOperationTimeout of its own.
+func cleanupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), topo.RemoteOperationTimeout)
+}
+
 // deleteShardCell is the per-cell helper function for deleteShard, and is
 // distinct from the RemoveShardCell rpc. Despite having similar names, they are
 // **not** the same!
@@ -256,8 +289,12 @@ func deleteTablet(ctx context.Context, ts *topo.Server, alias *topodatapb.Tablet
 		}
 	}
 
-	// Remove the tablet record and its replication graph entry.
-	if err := topotools.DeleteTablet(ctx, ts, tablet.Tablet); err != nil {
+	// Remove the tablet record and its replication graph entry together,
+	// even if ctx is done in between: a record left without its entry would
+	// be missed by deleteShard, and orphaned.
+	cleanupCtx, cancel := cleanupContext(ctx)
+	defer cancel()
+	if err := topotools.DeleteTablet(cleanupCtx, ts, tablet.Tablet); err != nil {
 		return err
 	}
 
@@ -356,3 +393,448 @@ func removeShardCell(ctx context.Context, ts *topo.Server, cell string, keyspace
 
 	return err
 }
+
+// cellReferences are the records that refer to a cell, found by
+// getCellReferences.
+type cellReferences struct {
+	counts *vtctldatapb.CellReferences
+	// tabletControlShards are the shards with tablet controls that list the
+	// cell.
+	tabletControlShards []*topo.ShardInfo
+	// aliases are the names of the cells aliases that contain the cell.
+	aliases []string
+}
+
+// blocking returns true if the cell has references that cannot be purged.
+func (refs *cellReferences) blocking() bool {
+	c := refs.counts
+	return c.Tablets > 0 || c.ShardReplications > 0 || c.ShardPrimaries > 0 || c.SrvKeyspaces > 0
+}
+
+// purgeable returns true if the cell has references that can be purged. The
+// SrvVSchema is one of them: every cell has one, and nothing else is left
+// that it routes to once the cell is drained.
+func (refs *cellReferences) purgeable() bool {
+	return len(refs.tabletControlShards) > 0 || len(refs.aliases) > 0 || refs.counts.SrvVschema
+}
+
+func (refs *cellReferences) String() string {
+	c := refs.counts
+	return fmt.Sprintf("tablets: %d, shard replication graphs: %d, shard tablet controls: %d, shard primaries: %d, SrvKeyspaces: %d, SrvVSchema: %t, cells aliases: %d",
+		c.Tablets, c.ShardReplications, c.ShardTabletControls, c.ShardPrimaries, c.SrvKeyspaces, c.SrvVschema, c.CellsAliases)
+}
+
+// getCellReferences finds the records that refer to the cell, in the global
+// topo and in the topo of the cell. If force is set, failing to read the topo
+// of the cell is only logged, assuming it was already shut down, and the
+// records in it are not counted.
+func getCellReferences(ctx context.Context, ts *topo.Server, cell string, force bool) (*cellReferences, error) {
+	refs := &cellReferences{counts: &vtctldatapb.CellReferences{}}
+
+	keyspaces, err := ts.GetKeyspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var shards []*topo.ShardInfo
+	for _, keyspace := range keyspaces {
+		ksShards, err := ts.FindAllShardsInKeyspace(ctx, keyspace, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range slices.Sorted(maps.Keys(ksShards)) {
+			si := ksShards[name]
+			shards = append(shards, si)
+			if si.PrimaryAlias != nil && si.PrimaryAlias.Cell == cell {
+				refs.counts.ShardPrimaries++
+			}
+			if slices.ContainsFunc(si.TabletControls, func(tc *topodatapb.Shard_TabletControl) bool {
+				return slices.Contains(tc.Cells, cell)
+			}) {
+				refs.tabletControlShards = append(refs.tabletControlShards, si)
+			}
+		}
+	}
+	refs.counts.ShardTabletControls = int32(len(refs.tabletControlShards))
+
+	aliases, err := ts.GetCellsAliases(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range slices.Sorted(maps.Keys(aliases)) {
+		if slices.Contains(aliases[name].Cells, cell) {
+			refs.aliases = append(refs.aliases, name)
+		}
+	}
+	refs.counts.CellsAliases = int32(len(refs.aliases))
+
+	if err := countCellRecords(ctx, ts, cell, shards, refs.counts); err != nil {
+		if !force {
+			return nil, vterrors.Wrapf(err, "can't read the records of cell %v; use --force to continue anyway (e.g. if the cell topo was already permanently shut down)", cell)
+		}
+		log.Warningf("Cannot read the records of cell %v, assuming its topo server is down: %v", cell, err)
+	}
+
+	return refs, nil
+}
+
+// countCellRecords counts the tablets, replication graphs and serving records
+// in the topo of the cell.
+func countCellRecords(ctx context.Context, ts *topo.Server, cell string, shards []*topo.ShardInfo, counts *vtctldatapb.CellReferences) error {
+	tablets, err := ts.GetTabletAliasesByCell(ctx, cell)
+	if err != nil && !topo.IsErrType(err, topo.NoNode) {
+		return err
+	}
+	counts.Tablets = int32(len(tablets))
+
+	for _, si := range shards {
+		_, err := ts.GetShardReplication(ctx, cell, si.Keyspace(), si.ShardName())
+		switch {
+		case err == nil:
+			counts.ShardReplications++
+		case topo.IsErrType(err, topo.NoNode):
+		default:
+			return err
+		}
+	}
+
+	srvKeyspaces, err := ts.GetSrvKeyspaceNames(ctx, cell)
+	if err != nil && !topo.IsErrType(err, topo.NoNode) {
+		return err
+	}
+	counts.SrvKeyspaces = int32(len(srvKeyspaces))
+
+	_, err = ts.GetSrvVSchema(ctx, cell)
+	switch {
+	case err == nil:
+		counts.SrvVschema = true
+	case !topo.IsErrType(err, topo.NoNode):
+		return err
+	}
+
+	return nil
+}
+
+// purgeCellReferences removes the cell from the tablet controls of the shards
+// and from the cells aliases found by getCellReferences, and deletes the
+// SrvVSchema of the cell. A tablet control or cells alias that only lists the
+// cell is removed, as an empty list of cells would apply to all of them. It
+// returns a description of each change.
+func purgeCellReferences(ctx context.Context, ts *topo.Server, cell string, refs *cellReferences) (purged []string, err error) {
+	for _, shard := range refs.tabletControlShards {
+		if err := func() (err error) {
+			ctx, unlock, lockErr := ts.LockKeyspace(ctx, shard.Keyspace(), fmt.Sprintf("DeleteCellInfo(%v)", cell))
+			if lockErr != nil {
+				return lockErr
+			}
+			defer unlock(&err)
+
+			var changes []string
+			_, err = ts.UpdateShardFields(ctx, shard.Keyspace(), shard.ShardName(), func(si *topo.ShardInfo) error {
+				changes = nil
+				tabletControls := si.TabletControls[:0]
+				for _, tc := range si.TabletControls {
+					if !slices.Contains(tc.Cells, cell) {
+						tabletControls = append(tabletControls, tc)
+						continue
+					}
+					tc.Cells = slices.DeleteFunc(tc.Cells, func(c string) bool { return c == cell })
+					if len(tc.Cells) == 0 {
+						changes = append(changes, fmt.Sprintf("Removed the %v tablet control of shard %v/%v, which only listed cell %v", tc.TabletType, si.Keyspace(), si.ShardName(), cell))
+						continue
+					}
+					changes = append(changes, fmt.Sprintf("Removed cell %v from the %v tablet control of shard %v/%v", cell, tc.TabletType, si.Keyspace(), si.ShardName()))
+					tabletControls = append(tabletControls, tc)
+				}
+				if len(changes) == 0 {
+					return topo.NewError(topo.NoUpdateNeeded, si.Keyspace()+"/"+si.ShardName())
+				}
+				si.TabletControls = tabletControls
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, change := range changes {
+				log.Info(change)
+				purged = append(purged, change)
+			}
+			return nil
+		}(); err != nil {
+			return purged, err
+		}
+	}
+
+	for _, alias := range refs.aliases {
+		var empty bool
+		err := ts.UpdateCellsAlias(ctx, alias, func(ca *topodatapb.CellsAlias) error {
+			ca.Cells = slices.DeleteFunc(ca.Cells, func(c string) bool { return c == cell })
+			empty = len(ca.Cells) == 0
+			return nil
+		})
+		if err != nil {
+			return purged, err
+		}
+		change := fmt.Sprintf("Removed cell %v from cells alias %v", cell, alias)
+		if empty {
+			if err := ts.DeleteCellsAlias(ctx, alias); err != nil {
+				return purged, err
+			}
+			change = fmt.Sprintf("Removed cells alias %v, which only listed cell %v", alias, cell)
+		}
+		log.Info(change)
+		purged = append(purged, change)
+	}
+
+	if refs.counts.SrvVschema {
+		if err := ts.DeleteSrvVSchema(ctx, cell); err != nil && !topo.IsErrType(err, topo.NoNode) {
+			return purged, err
+		}
+		change := fmt.Sprintf("Deleted the SrvVSchema of cell %v", cell)
+		log.Info(change)
+		purged = append(purged, change)
+	}
+
+	return purged, nil
+}
+
+// validateCellsAlias checks that every cell of a cells alias has a CellInfo
+// and, unless force is set, that none of them are in another alias.
+func validateCellsAlias(ctx context.Context, ts *topo.Server, name string, cells []string, force bool) error {
+	knownCells, err := ts.GetKnownCells(ctx)
+	if err != nil {
+		return err
+	}
+	var unknownCells []string
+	for _, cell := range cells {
+		if !slices.Contains(knownCells, cell) {
+			unknownCells = append(unknownCells, cell)
+		}
+	}
+	if len(unknownCells) > 0 {
+		return vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "cells %v of alias %v have no CellInfo", unknownCells, name)
+	}
+
+	if force {
+		return nil
+	}
+	aliases, err := ts.GetCellsAliases(ctx, true)
+	if err != nil {
+		return err
+	}
+	overlaps := topo.CellsAliasOverlaps(aliases, name, &topodatapb.CellsAlias{Cells: cells})
+	if len(overlaps) == 0 {
+		return nil
+	}
+	descs := make([]string, 0, len(overlaps))
+	for _, alias := range slices.Sorted(maps.Keys(overlaps)) {
+		descs = append(descs, fmt.Sprintf("alias %v on cells %v", alias, overlaps[alias]))
+	}
+	return vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "cells alias %v overlaps with %v, which makes the cells vtgate routes to ambiguous; use --force to allow it anyway", name, strings.Join(descs, ", "))
+}
+
+// cellsAliasRoutingChanges returns the keyspaces and tablet types whose routing
+// changes when the cells of an alias change from oldCells to newCells, from
+// the SrvKeyspaces of those cells. Once a cell is added to or removed from
+// the alias, vtgates in the other cells gain or lose its tablets, and the
+// vtgates in it gain or lose theirs. Primaries are routed to in any cell, so
+// they are never affected.
+func cellsAliasRoutingChanges(ctx context.Context, ts *topo.Server, oldCells []string, newCells []string) ([]*vtctldatapb.CellsAliasRoutingChange, error) {
+	oldSet, newSet := sets.New(oldCells...), sets.New(newCells...)
+	cells := sets.New(oldCells...).Insert(newCells...)
+	if oldSet.Equal(newSet) || cells.Len() < 2 {
+		return nil, nil
+	}
+
+	type servedType struct {
+		keyspace   string
+		tabletType topodatapb.TabletType
+	}
+	servingCells := map[servedType][]string{}
+	for _, cell := range sets.List(cells) {
+		keyspaces, err := ts.GetSrvKeyspaceNames(ctx, cell)
+		if err != nil && !topo.IsErrType(err, topo.NoNode) {
+			return nil, err
+		}
+		for _, keyspace := range keyspaces {
+			srvKeyspace, err := ts.GetSrvKeyspace(ctx, cell, keyspace)
+			if err != nil {
+				return nil, err
+			}
+			for _, partition := range srvKeyspace.Partitions {
+				if partition.ServedType == topodatapb.TabletType_PRIMARY {
+					continue
+				}
+				st := servedType{keyspace, partition.ServedType}
+				servingCells[st] = append(servingCells[st], cell)
+			}
+		}
+	}
+
+	changes := make([]*vtctldatapb.CellsAliasRoutingChange, 0, len(servingCells))
+	for st, cells := range servingCells {
+		changes = append(changes, &vtctldatapb.CellsAliasRoutingChange{
+			Keyspace:   st.keyspace,
+			TabletType: st.tabletType,
+			Cells:      cells,
+		})
+	}
+	slices.SortFunc(changes, func(a, b *vtctldatapb.CellsAliasRoutingChange) int {
+		if a.Keyspace != b.Keyspace {
+			return strings.Compare(a.Keyspace, b.Keyspace)
+		}
+		return int(a.TabletType) - int(b.TabletType)
+	})
+	return changes, nil
+}
+
+// splitTopoPath splits a path like /global/keyspaces/commerce into its cell
+// and the path relative to the root of the cell.
+func splitTopoPath(topoPath string) (cell string, relativePath string, err error) {
+	if !strings.HasPrefix(topoPath, "/") {
+		return "", "", vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "invalid path %q: it must start with /<cell>", topoPath)
+	}
+	cell, relativePath, _ = strings.Cut(topoPath[1:], "/")
+	if cell == "" {
+		return "", "", vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "invalid path %q: it must start with /<cell>", topoPath)
+	}
+	return cell, path.Clean("/" + relativePath), nil
+}
+
+// getTopoFile returns the contents of the file at topoPath, decoded if
+// decode is set and the file is of a known type.
+func getTopoFile(ctx context.Context, ts *topo.Server, topoPath string, decode bool, asJSON bool) (*vtctldatapb.TopoGetResponse, error) {
+	cell, relativePath, err := splitTopoPath(topoPath)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := ts.ConnForCell(ctx, cell)
+	if err != nil {
+		return nil, err
+	}
+
+	data, version, err := conn.Get(ctx, relativePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxTopoResponseSize {
+		return nil, vterrors.Errorf(vtrpc.Code_RESOURCE_EXHAUSTED, "%s is %d bytes, more than the limit of %d bytes", topoPath, len(data), maxTopoResponseSize)
+	}
+
+	resp := &vtctldatapb.TopoGetResponse{
+		Version: version.String(),
+		Size:    int64(len(data)),
+	}
+	if !decode || !topo.CanDecodeContent(relativePath) {
+		resp.Data = data
+		return resp, nil
+	}
+	resp.Decoded, err = topo.DecodeContent(relativePath, data, asJSON)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot decode %s", topoPath)
+	}
+	return resp, nil
+}
+
+// listTopoDir returns the page of the children of the directory at topoPath
+// that follows pageToken. The root, /, lists the global cell and the known
+// cells.
+func listTopoDir(ctx context.Context, ts *topo.Server, topoPath string, pageToken string, pageSize int) (*vtctldatapb.TopoListResponse, error) {
+	switch {
+	case pageSize < 0:
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "invalid page size %d", pageSize)
+	case pageSize == 0:
+		pageSize = defaultTopoListPageSize
+	case pageSize > maxTopoListPageSize:
+		pageSize = maxTopoListPageSize
+	}
+	after, err := decodeTopoPageToken(pageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		conn    topo.Conn
+		dirPath string
+		entries []topo.DirEntry
+	)
+	if topoPath == "/" {
+		cells, err := ts.GetKnownCells(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, cell := range append([]string{topo.GlobalCell}, cells...) {
+			entries = append(entries, topo.DirEntry{Name: cell, Type: topo.TypeDirectory})
+		}
+	} else {
+		var cell string
+		cell, dirPath, err = splitTopoPath(topoPath)
+		if err != nil {
+			return nil, err
+		}
+		conn, err = ts.ConnForCell(ctx, cell)
+		if err != nil {
+			return nil, err
+		}
+		entries, err = conn.ListDir(ctx, dirPath, true /*full*/)
+		if err != nil {
+			return nil, err
+		}
+	}
+	topo.DirEntriesSortByName(entries)
+	entries = entries[sort.Search(len(entries), func(i int) bool { return entries[i].Name > after }):]
+
+	resp := &vtctldatapb.TopoListResponse{}
+	size := 0
+	for _, entry := range entries {
+		if len(resp.Entries) == pageSize {
+			resp.NextPageToken = encodeTopoPageToken(resp.Entries[len(resp.Entries)-1].Name)
+			break
+		}
+
+		topoEntry := &vtctldatapb.TopoEntry{
+			Name:      entry.Name,
+			Type:      vtctldatapb.TopoEntry_DIRECTORY,
+			Ephemeral: entry.Ephemeral,
+		}
+		if entry.Type == topo.TypeFile {
+			topoEntry.Type = vtctldatapb.TopoEntry_FILE
+			// Ephemeral files, like locks, can't be read through the file API.
+			if !entry.Ephemeral {
+				data, version, err := conn.Get(ctx, path.Join(dirPath, entry.Name))
+				switch {
+				case topo.IsErrType(err, topo.NoNode):
+					// The file was deleted since the directory was listed.
+					continue
+				case err != nil:
+					return nil, err
+				}
+				topoEntry.Version = version.String()
+				topoEntry.Size = int64(len(data))
+			}
+		}
+
+		size += topoEntry.SizeVT()
+		if size > maxTopoResponseSize && len(resp.Entries) > 0 {
+			resp.NextPageToken = encodeTopoPageToken(resp.Entries[len(resp.Entries)-1].Name)
+			break
+		}
+		resp.Entries = append(resp.Entries, topoEntry)
+	}
+	return resp, nil
+}
+
+// encodeTopoPageToken returns the page token of the page that follows the
+// entry name.
+func encodeTopoPageToken(name string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(name))
+}
+
+// decodeTopoPageToken returns the name of the last entry of the page before
+// the one of pageToken.
+func decodeTopoPageToken(pageToken string) (string, error) {
+	name, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err != nil {
+		return "", vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "invalid page token %q", pageToken)
+	}
+	return string(name), nil
+}
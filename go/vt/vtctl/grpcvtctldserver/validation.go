@@ -0,0 +1,211 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtctldserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// The checks of the Validate, ValidateKeyspace and ValidateShard RPCs,
+// which their findings are recorded under.
+const (
+	ValidationCheckTopology         = "topology"
+	ValidationCheckTabletRecords    = "tablet record"
+	ValidationCheckPrimary          = "primary"
+	ValidationCheckReplicationGraph = "replication graph"
+	ValidationCheckReplication      = "replication"
+	ValidationCheckPing             = "ping"
+	ValidationCheckServingState     = "serving state"
+	ValidationCheckServingGraph     = "serving graph"
+)
+
+// ValidationRecorder records what the Validate, ValidateKeyspace and
+// ValidateShard RPCs check and find, as they go. Their responses only
+// describe the findings, so callers that need the check and the severity of
+// each finding, or to count what was checked, pass a recorder to the
+// WithRecorder variants of the RPCs. Its methods are called concurrently.
+type ValidationRecorder interface {
+	// RecordKeyspace records that the keyspace is validated.
+	RecordKeyspace(keyspace string)
+	// RecordShard records that the shard, and its tablets, are validated.
+	RecordShard(keyspace, shard string, tabletAliases []*topodatapb.TabletAlias)
+	// RecordChecked records that check ran on object, e.g. a shard or a
+	// tablet. A check may run more than once on the same object.
+	RecordChecked(check, object string)
+	// RecordFinding records a problem check found on object, in the shard
+	// of the keyspace, either of which may be empty.
+	RecordFinding(check string, severity vtctldatapb.ValidationFinding_Severity, keyspace, shard, object, message string)
+	// Logger returns the logger the validation logs its progress to, such
+	// as the latency of each tablet ping.
+	Logger() logutil.Logger
+}
+
+// consoleValidationRecorder is the recorder of the RPCs called without
+// one. It only logs to the console.
+type consoleValidationRecorder struct{}
+
+// RecordKeyspace is part of the ValidationRecorder interface.
+func (consoleValidationRecorder) RecordKeyspace(keyspace string) {}
+
+// RecordShard is part of the ValidationRecorder interface.
+func (consoleValidationRecorder) RecordShard(keyspace, shard string, tabletAliases []*topodatapb.TabletAlias) {
+}
+
+// RecordChecked is part of the ValidationRecorder interface.
+func (consoleValidationRecorder) RecordChecked(check, object string) {}
+
+// RecordFinding is part of the ValidationRecorder interface.
+func (consoleValidationRecorder) RecordFinding(check string, severity vtctldatapb.ValidationFinding_Severity, keyspace, shard, object, message string) {
+}
+
+// Logger is part of the ValidationRecorder interface.
+func (consoleValidationRecorder) Logger() logutil.Logger {
+	return logutil.NewConsoleLogger()
+}
+
+// validationResults collects the result strings of the response of a
+// validation, and records each of them as a finding, with its check and
+// severity.
+type validationResults struct {
+	rec      ValidationRecorder
+	keyspace string
+	shard    string
+
+	mu      sync.Mutex
+	results []string
+}
+
+func newValidationResults(rec ValidationRecorder, keyspace, shard string) *validationResults {
+	return &validationResults{rec: rec, keyspace: keyspace, shard: shard}
+}
+
+// errorf records an error check found on object.
+func (v *validationResults) errorf(check, object, format string, args ...any) {
+	v.add(check, vtctldatapb.ValidationFinding_ERROR, object, fmt.Sprintf(format, args...))
+}
+
+// warningf records a warning check found on object, i.e. a problem that is
+// often transient, or that kept the check from running.
+func (v *validationResults) warningf(check, object, format string, args ...any) {
+	v.add(check, vtctldatapb.ValidationFinding_WARNING, object, fmt.Sprintf(format, args...))
+}
+
+// topologyErrorf records an error reading the topology, which is its own
+// object.
+func (v *validationResults) topologyErrorf(format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	v.add(ValidationCheckTopology, vtctldatapb.ValidationFinding_ERROR, message, message)
+}
+
+func (v *validationResults) add(check string, severity vtctldatapb.ValidationFinding_Severity, object, message string) {
+	v.rec.RecordFinding(check, severity, v.keyspace, v.shard, object, message)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.results = append(v.results, message)
+}
+
+// merge appends the results collected by other, which were already
+// recorded.
+func (v *validationResults) merge(other *validationResults) {
+	results := other.get()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.results = append(v.results, results...)
+}
+
+// get returns the results collected so far.
+func (v *validationResults) get() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.results
+}
+
+// srvKeyspaceObject names the SrvKeyspace of the keyspace in the cell, as
+// an object of the serving graph check.
+func srvKeyspaceObject(keyspace, cell string) string {
+	return keyspace + "@" + cell
+}
+
+// ReadTabletHealth returns the first health record the tablet streams, or an
+// error if it doesn't send one within timeout.
+func ReadTabletHealth(ctx context.Context, tablet *topodatapb.Tablet, timeout time.Duration) (*querypb.StreamHealthResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := tabletconn.GetDialer()(ctx, tablet, grpcclient.FailFast(true))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close(ctx)
+
+	var health *querypb.StreamHealthResponse
+	err = conn.StreamHealth(ctx, func(shr *querypb.StreamHealthResponse) error {
+		health = shr
+		return io.EOF
+	})
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if health == nil {
+		return nil, fmt.Errorf("tablet %v ended its health stream without a record", topoproto.TabletAliasString(tablet.Alias))
+	}
+	return health, nil
+}
+
+// ServingStateResult describes the mismatch between the topo record of a
+// tablet and the health record it broadcast, or the error reading it
+// within wait, which is only a warning. It returns an empty string if they
+// match.
+func ServingStateResult(si *topo.ShardInfo, tablet *topodatapb.Tablet, shr *querypb.StreamHealthResponse, err error, wait time.Duration) (string, vtctldatapb.ValidationFinding_Severity) {
+	alias := topoproto.TabletAliasString(tablet.Alias)
+	if err != nil {
+		return fmt.Sprintf("tablet %v sent no health data within %v: %v", alias, wait, err), vtctldatapb.ValidationFinding_WARNING
+	}
+	if healthType := shr.Target.GetTabletType(); healthType != tablet.Type {
+		return fmt.Sprintf("tablet %v broadcasts tablet type %v, but its topo record has type %v", alias, healthType, tablet.Type), vtctldatapb.ValidationFinding_ERROR
+	}
+
+	// Only the primary the shard record points to serves as primary.
+	wantServing := topo.IsInServingGraph(tablet.Type)
+	if tablet.Type == topodatapb.TabletType_PRIMARY {
+		wantServing = si.IsPrimaryServing && topoproto.TabletAliasEqual(si.PrimaryAlias, tablet.Alias)
+	}
+	if shr.Serving != wantServing {
+		reason := ""
+		if healthError := shr.RealtimeStats.GetHealthError(); healthError != "" {
+			reason = ": " + healthError
+		}
+		return fmt.Sprintf("tablet %v of type %v broadcasts serving state %v, but its topo records imply %v%v", alias, tablet.Type, shr.Serving, wantServing, reason), vtctldatapb.ValidationFinding_ERROR
+	}
+	return "", vtctldatapb.ValidationFinding_ERROR
+}
@@ -22,8 +22,10 @@ package grpcvtctlserver
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"vitess.io/vitess/go/vt/vtenv"
 
@@ -31,6 +33,7 @@ import (
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vtctl"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 	"vitess.io/vitess/go/vt/wrangler"
 
@@ -39,6 +42,23 @@ import (
 	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
 )
 
+// CorrelationIDHeader is the gRPC response header holding the correlation
+// ID of a command, which prefixes its log events.
+const CorrelationIDHeader = "vtctl-correlation-id"
+
+func init() {
+	servenv.OnParseFor("vtctld", logutil.RegisterConsoleLoggerFlags)
+}
+
+// readOnly makes the server run the commands on read-only wranglers.
+var readOnly atomic.Bool
+
+// SetReadOnly sets whether the server runs the commands on read-only
+// wranglers, which refuse the commands that change the topo or the tablets.
+func SetReadOnly(enabled bool) {
+	readOnly.Store(enabled)
+}
+
 // VtctlServer is our RPC server
 type VtctlServer struct {
 	vtctlservicepb.UnimplementedVtctlServer
@@ -70,15 +90,41 @@ func (s *VtctlServer) ExecuteVtctlCommand(args *vtctldatapb.ExecuteVtctlCommandR
 		})
 		mu.Unlock()
 	})
-	logger := logutil.NewTeeLogger(logstream, logutil.NewConsoleLogger())
+	// Only the client's stream is filtered by the level it asked for, the
+	// console logs keep the level they are configured with.
+	var clientLogger logutil.Logger = logstream
+	if args.LogLevel != logutilpb.Level_INFO {
+		clientLogger = logutil.NewFilteredClientLogger(logstream, args.LogLevel)
+	}
+
+	// Only the server's log is prefixed with the correlation ID of the
+	// command, the client gets its output as is.
+	ctx, correlationID := logutil.EnsureCorrelationID(stream.Context())
+	consoleLogger := logutil.NewCorrelationIDLogger(ctx, logutil.NewConsoleLoggerFromFlags())
+	logger := logutil.NewTeeLogger(clientLogger, consoleLogger)
 
 	// create the wrangler
 	tmc := tmclient.NewTabletManagerClient()
 	defer tmc.Close()
-	wr := wrangler.New(s.env, logger, s.ts, tmc)
-
-	// execute the command
-	return vtctl.RunCommand(stream.Context(), wr, args.Args)
+	opts := []wrangler.Option{wrangler.WithTabletManagerClient(tmc)}
+	if readOnly.Load() {
+		opts = append(opts, wrangler.WithReadOnly())
+	}
+	wr := wrangler.NewWithOptions(s.env, logger, s.ts, opts...)
+
+	// Return the correlation ID of the command in the response headers and
+	// in its error, so a user can quote it to find its log events.
+	if err := stream.SendHeader(metadata.Pairs(CorrelationIDHeader, correlationID)); err != nil {
+		logger.Warningf("cannot send correlation id %v: %v", correlationID, err)
+	}
+
+	// execute the command, and return the code of its error as the gRPC
+	// status code, so that clients can tell e.g. a refused operation from an
+	// unreachable topo server.
+	if err := vtctl.RunCommand(ctx, wr, args.Args); err != nil {
+		return vterrors.ToGRPC(vterrors.Errorf(wrangler.ErrorCode(err), "%v (correlation id %v)", err, correlationID))
+	}
+	return nil
 }
 
 // StartServer registers the VtctlServer for RPCs
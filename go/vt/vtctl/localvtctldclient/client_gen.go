@@ -316,6 +316,11 @@ func (client *localVtctldClient) GetKeyspaceRoutingRules(ctx context.Context, in
 	return client.s.GetKeyspaceRoutingRules(ctx, in)
 }
 
+// GetKeyspaceThrottlerStatus is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetKeyspaceThrottlerStatus(ctx context.Context, in *vtctldatapb.GetKeyspaceThrottlerStatusRequest, opts ...grpc.CallOption) (*vtctldatapb.GetKeyspaceThrottlerStatusResponse, error) {
+	return client.s.GetKeyspaceThrottlerStatus(ctx, in)
+}
+
 // GetKeyspaces is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetKeyspaces(ctx context.Context, in *vtctldatapb.GetKeyspacesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetKeyspacesResponse, error) {
 	return client.s.GetKeyspaces(ctx, in)
@@ -341,6 +346,11 @@ func (client *localVtctldClient) GetSchema(ctx context.Context, in *vtctldatapb.
 	return client.s.GetSchema(ctx, in)
 }
 
+// GetSchemaChangeHistory is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetSchemaChangeHistory(ctx context.Context, in *vtctldatapb.GetSchemaChangeHistoryRequest, opts ...grpc.CallOption) (*vtctldatapb.GetSchemaChangeHistoryResponse, error) {
+	return client.s.GetSchemaChangeHistory(ctx, in)
+}
+
 // GetSchemaMigrations is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetSchemaMigrations(ctx context.Context, in *vtctldatapb.GetSchemaMigrationsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetSchemaMigrationsResponse, error) {
 	return client.s.GetSchemaMigrations(ctx, in)
@@ -391,6 +401,11 @@ func (client *localVtctldClient) GetTablets(ctx context.Context, in *vtctldatapb
 	return client.s.GetTablets(ctx, in)
 }
 
+// GetThrottlerConfig is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetThrottlerConfig(ctx context.Context, in *vtctldatapb.GetThrottlerConfigRequest, opts ...grpc.CallOption) (*vtctldatapb.GetThrottlerConfigResponse, error) {
+	return client.s.GetThrottlerConfig(ctx, in)
+}
+
 // GetThrottlerStatus is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetThrottlerStatus(ctx context.Context, in *vtctldatapb.GetThrottlerStatusRequest, opts ...grpc.CallOption) (*vtctldatapb.GetThrottlerStatusResponse, error) {
 	return client.s.GetThrottlerStatus(ctx, in)
@@ -421,6 +436,11 @@ func (client *localVtctldClient) GetVersion(ctx context.Context, in *vtctldatapb
 	return client.s.GetVersion(ctx, in)
 }
 
+// GetWorkflowProgress is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetWorkflowProgress(ctx context.Context, in *vtctldatapb.GetWorkflowProgressRequest, opts ...grpc.CallOption) (*vtctldatapb.GetWorkflowProgressResponse, error) {
+	return client.s.GetWorkflowProgress(ctx, in)
+}
+
 // GetWorkflows is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetWorkflows(ctx context.Context, in *vtctldatapb.GetWorkflowsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetWorkflowsResponse, error) {
 	return client.s.GetWorkflows(ctx, in)
@@ -541,6 +561,11 @@ func (client *localVtctldClient) ReloadSchemaShard(ctx context.Context, in *vtct
 	return client.s.ReloadSchemaShard(ctx, in)
 }
 
+// ReloadTabletConfig is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ReloadTabletConfig(ctx context.Context, in *vtctldatapb.ReloadTabletConfigRequest, opts ...grpc.CallOption) (*vtctldatapb.ReloadTabletConfigResponse, error) {
+	return client.s.ReloadTabletConfig(ctx, in)
+}
+
 // RemoveBackup is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) RemoveBackup(ctx context.Context, in *vtctldatapb.RemoveBackupRequest, opts ...grpc.CallOption) (*vtctldatapb.RemoveBackupResponse, error) {
 	return client.s.RemoveBackup(ctx, in)
@@ -697,6 +722,16 @@ func (client *localVtctldClient) TabletExternallyReparented(ctx context.Context,
 	return client.s.TabletExternallyReparented(ctx, in)
 }
 
+// TopoGet is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) TopoGet(ctx context.Context, in *vtctldatapb.TopoGetRequest, opts ...grpc.CallOption) (*vtctldatapb.TopoGetResponse, error) {
+	return client.s.TopoGet(ctx, in)
+}
+
+// TopoList is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) TopoList(ctx context.Context, in *vtctldatapb.TopoListRequest, opts ...grpc.CallOption) (*vtctldatapb.TopoListResponse, error) {
+	return client.s.TopoList(ctx, in)
+}
+
 // UpdateCellInfo is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) UpdateCellInfo(ctx context.Context, in *vtctldatapb.UpdateCellInfoRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateCellInfoResponse, error) {
 	return client.s.UpdateCellInfo(ctx, in)
@@ -752,11 +787,114 @@ func (client *localVtctldClient) ValidatePermissionsKeyspace(ctx context.Context
 	return client.s.ValidatePermissionsKeyspace(ctx, in)
 }
 
+type validatePermissionsKeyspaceStreamAdapter struct {
+	*grpcshim.BidiStream
+	ch chan *vtctldatapb.ValidationStreamResponse
+}
+
+func (stream *validatePermissionsKeyspaceStreamAdapter) Recv() (*vtctldatapb.ValidationStreamResponse, error) {
+	select {
+	case <-stream.Context().Done():
+		return nil, stream.Context().Err()
+	case <-stream.Closed():
+		// Stream has been closed for future sends. If there are messages that
+		// have already been sent, receive them until there are no more. After
+		// all sent messages have been received, Recv will return the CloseErr.
+		select {
+		case msg := <-stream.ch:
+			return msg, nil
+		default:
+			return nil, stream.CloseErr()
+		}
+	case msg := <-stream.ch:
+		return msg, nil
+	}
+}
+
+func (stream *validatePermissionsKeyspaceStreamAdapter) Send(msg *vtctldatapb.ValidationStreamResponse) error {
+	select {
+	case <-stream.Context().Done():
+		return stream.Context().Err()
+	case <-stream.Closed():
+		return grpcshim.ErrStreamClosed
+	case stream.ch <- msg:
+		return nil
+	}
+}
+
+// ValidatePermissionsKeyspaceStream is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ValidatePermissionsKeyspaceStream(ctx context.Context, in *vtctldatapb.ValidatePermissionsKeyspaceRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_ValidatePermissionsKeyspaceStreamClient, error) {
+	stream := &validatePermissionsKeyspaceStreamAdapter{
+		BidiStream: grpcshim.NewBidiStream(ctx),
+		ch:         make(chan *vtctldatapb.ValidationStreamResponse, 1),
+	}
+	go func() {
+		err := client.s.ValidatePermissionsKeyspaceStream(in, stream)
+		stream.CloseWithError(err)
+	}()
+
+	return stream, nil
+}
+
+// ValidatePermissionsShard is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ValidatePermissionsShard(ctx context.Context, in *vtctldatapb.ValidatePermissionsShardRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidatePermissionsShardResponse, error) {
+	return client.s.ValidatePermissionsShard(ctx, in)
+}
+
 // ValidateSchemaKeyspace is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ValidateSchemaKeyspace(ctx context.Context, in *vtctldatapb.ValidateSchemaKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateSchemaKeyspaceResponse, error) {
 	return client.s.ValidateSchemaKeyspace(ctx, in)
 }
 
+type validateSchemaKeyspaceStreamAdapter struct {
+	*grpcshim.BidiStream
+	ch chan *vtctldatapb.ValidationStreamResponse
+}
+
+func (stream *validateSchemaKeyspaceStreamAdapter) Recv() (*vtctldatapb.ValidationStreamResponse, error) {
+	select {
+	case <-stream.Context().Done():
+		return nil, stream.Context().Err()
+	case <-stream.Closed():
+		// Stream has been closed for future sends. If there are messages that
+		// have already been sent, receive them until there are no more. After
+		// all sent messages have been received, Recv will return the CloseErr.
+		select {
+		case msg := <-stream.ch:
+			return msg, nil
+		default:
+			return nil, stream.CloseErr()
+		}
+	case msg := <-stream.ch:
+		return msg, nil
+	}
+}
+
+func (stream *validateSchemaKeyspaceStreamAdapter) Send(msg *vtctldatapb.ValidationStreamResponse) error {
+	select {
+	case <-stream.Context().Done():
+		return stream.Context().Err()
+	case <-stream.Closed():
+		return grpcshim.ErrStreamClosed
+	case stream.ch <- msg:
+		return nil
+	}
+}
+
+// ValidateSchemaKeyspaceStream is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ValidateSchemaKeyspaceStream(ctx context.Context, in *vtctldatapb.ValidateSchemaKeyspaceRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_ValidateSchemaKeyspaceStreamClient, error) {
+	stream := &validateSchemaKeyspaceStreamAdapter{
+		BidiStream: grpcshim.NewBidiStream(ctx),
+		ch:         make(chan *vtctldatapb.ValidationStreamResponse, 1),
+	}
+	go func() {
+		err := client.s.ValidateSchemaKeyspaceStream(in, stream)
+		stream.CloseWithError(err)
+	}()
+
+	return stream, nil
+}
+
 // ValidateShard is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ValidateShard(ctx context.Context, in *vtctldatapb.ValidateShardRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateShardResponse, error) {
 	return client.s.ValidateShard(ctx, in)
@@ -772,6 +910,55 @@ func (client *localVtctldClient) ValidateVersionKeyspace(ctx context.Context, in
 	return client.s.ValidateVersionKeyspace(ctx, in)
 }
 
+type validateVersionKeyspaceStreamAdapter struct {
+	*grpcshim.BidiStream
+	ch chan *vtctldatapb.ValidationStreamResponse
+}
+
+func (stream *validateVersionKeyspaceStreamAdapter) Recv() (*vtctldatapb.ValidationStreamResponse, error) {
+	select {
+	case <-stream.Context().Done():
+		return nil, stream.Context().Err()
+	case <-stream.Closed():
+		// Stream has been closed for future sends. If there are messages that
+		// have already been sent, receive them until there are no more. After
+		// all sent messages have been received, Recv will return the CloseErr.
+		select {
+		case msg := <-stream.ch:
+			return msg, nil
+		default:
+			return nil, stream.CloseErr()
+		}
+	case msg := <-stream.ch:
+		return msg, nil
+	}
+}
+
+func (stream *validateVersionKeyspaceStreamAdapter) Send(msg *vtctldatapb.ValidationStreamResponse) error {
+	select {
+	case <-stream.Context().Done():
+		return stream.Context().Err()
+	case <-stream.Closed():
+		return grpcshim.ErrStreamClosed
+	case stream.ch <- msg:
+		return nil
+	}
+}
+
+// ValidateVersionKeyspaceStream is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ValidateVersionKeyspaceStream(ctx context.Context, in *vtctldatapb.ValidateVersionKeyspaceRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_ValidateVersionKeyspaceStreamClient, error) {
+	stream := &validateVersionKeyspaceStreamAdapter{
+		BidiStream: grpcshim.NewBidiStream(ctx),
+		ch:         make(chan *vtctldatapb.ValidationStreamResponse, 1),
+	}
+	go func() {
+		err := client.s.ValidateVersionKeyspaceStream(in, stream)
+		stream.CloseWithError(err)
+	}()
+
+	return stream, nil
+}
+
 // ValidateVersionShard is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ValidateVersionShard(ctx context.Context, in *vtctldatapb.ValidateVersionShardRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateVersionShardResponse, error) {
 	return client.s.ValidateVersionShard(ctx, in)
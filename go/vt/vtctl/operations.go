@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/wrangler"
+)
+
+// This file contains the Operations command group for vtctl.
+
+const operationsGroupName = "Operations"
+
+func init() {
+	addCommandGroup(operationsGroupName)
+
+	addCommand(operationsGroupName, command{
+		name:   "ListOperations",
+		method: commandListOperations,
+		params: "",
+		help:   "Lists the long-running operations (Validate, BackupKeyspace, DrainCell) of this vtctld and, if vtctld runs with --operations_topo_path, of every vtctld. Stale operations are recorded in the topo, but their vtctld stopped checking them.",
+	})
+
+	addCommand(operationsGroupName, command{
+		name:   "CancelOperation",
+		method: commandCancelOperation,
+		params: "<operation id>",
+		help:   "Cancels a long-running operation listed by ListOperations. It stops at its next safe point, after the cleanup it normally does. Operations of another vtctld can only be canceled if vtctld runs with --operations_topo_path, and within the interval at which the other vtctld checks them.",
+	})
+}
+
+func commandListOperations(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 0 {
+		return fmt.Errorf("ListOperations command takes no parameter")
+	}
+
+	ops, err := wr.ListOperations(ctx)
+	if err != nil {
+		return err
+	}
+	return printJSON(wr.Logger(), ops)
+}
+
+func commandCancelOperation(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <operation id> argument is required for the CancelOperation command")
+	}
+
+	id := subFlags.Arg(0)
+	if err := wr.CancelOperation(ctx, id); err != nil {
+		return err
+	}
+	wr.Logger().Printf("Requested the cancellation of operation %v\n", id)
+	return nil
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctl
+
+import (
+	"vitess.io/vitess/go/vt/wrangler"
+)
+
+// commandAccess tells whether a command changes the topo or the tablets.
+type commandAccess int
+
+const (
+	// commandUnclassified is the access of the commands missing from
+	// commandAccesses, which a read-only wrangler refuses.
+	commandUnclassified commandAccess = iota
+	// commandReadOnly commands only read the topo and the tablets.
+	commandReadOnly
+	// commandMutating commands write to the topo, or issue tablet RPCs that
+	// change the tablets.
+	commandMutating
+	// commandMutatingByArgs commands mutate for some of their arguments
+	// only, e.g. Workflow start but not Workflow show. They check
+	// Wrangler.CheckWritable themselves, once they parsed their arguments.
+	commandMutatingByArgs
+)
+
+// commandAccesses classifies every command, by name. A command that isn't
+// listed is refused by a read-only wrangler, and TestCommandAccesses fails
+// until it is.
+var commandAccesses = map[string]commandAccess{
+	"AddCellInfo":                   commandMutating,
+	"AddCellsAlias":                 commandMutating,
+	"AddTabletTag":                  commandMutating,
+	"ApplyRoutingRules":             commandMutating,
+	"ApplySchema":                   commandMutating,
+	"ApplySchemaStaged":             commandMutatingByArgs,
+	"ApplyVSchema":                  commandMutating,
+	"AuditTablets":                  commandMutatingByArgs,
+	"Backup":                        commandMutating,
+	"BackupKeyspace":                commandMutating,
+	"BackupShard":                   commandMutating,
+	"CancelOperation":               commandMutating,
+	"ChangeTabletType":              commandMutating,
+	"CheckErrantGTIDs":              commandReadOnly,
+	"CompareRowCounts":              commandReadOnly,
+	"CompareSchemasAcrossKeyspaces": commandReadOnly,
+	"CopySchemaShard":               commandMutating,
+	"CreateKeyspace":                commandMutating,
+	"CreateLookupVindex":            commandMutating,
+	"CreateShard":                   commandMutating,
+	"DecommissionTablet":            commandMutating,
+	"DeleteCellInfo":                commandMutating,
+	"DeleteCellsAlias":              commandMutating,
+	"DeleteKeyspace":                commandMutating,
+	"DeleteShard":                   commandMutating,
+	"DeleteSrvVSchema":              commandMutating,
+	"DeleteTablet":                  commandMutating,
+	"DiffShardVariables":            commandReadOnly,
+	"DrainCell":                     commandMutating,
+	"EmergencyReparentShard":        commandMutating,
+	"ExecuteFetchAsApp":             commandMutating,
+	"ExecuteFetchAsDba":             commandMutating,
+	"ExecuteHook":                   commandMutating,
+	"ExternalizeVindex":             commandMutating,
+	"FindAllShardsInKeyspace":       commandReadOnly,
+	"GenerateShardRanges":           commandMutatingByArgs,
+	"GetCellInfo":                   commandReadOnly,
+	"GetCellInfoNames":              commandReadOnly,
+	"GetCellsAliases":               commandReadOnly,
+	"GetCompletionData":             commandReadOnly,
+	"GetFullStatus":                 commandReadOnly,
+	"GetKeyspace":                   commandReadOnly,
+	"GetKeyspaces":                  commandReadOnly,
+	"GetPermissions":                commandReadOnly,
+	"GetPrimaryTermHistory":         commandReadOnly,
+	"GetRoutingRules":               commandReadOnly,
+	"GetSchema":                     commandReadOnly,
+	"GetShard":                      commandReadOnly,
+	"GetShardJournals":              commandReadOnly,
+	"GetShardReplication":           commandReadOnly,
+	"GetSrvKeyspace":                commandReadOnly,
+	"GetSrvKeyspaceNames":           commandReadOnly,
+	"GetSrvVSchema":                 commandReadOnly,
+	"GetTablet":                     commandReadOnly,
+	"GetThrottlerConfig":            commandReadOnly,
+	"GetVSchema":                    commandReadOnly,
+	"Help":                          commandReadOnly,
+	"InitShardPrimary":              commandMutating,
+	"InitTablet":                    commandMutating,
+	"LegacyVtctlCommand":            commandReadOnly,
+	"ListAllTablets":                commandReadOnly,
+	"ListBackups":                   commandReadOnly,
+	"ListOperations":                commandReadOnly,
+	"ListShardTablets":              commandReadOnly,
+	"ListTablets":                   commandReadOnly,
+	"Materialize":                   commandMutating,
+	"Migrate":                       commandMutating,
+	"Mount":                         commandMutatingByArgs,
+	"MoveTables":                    commandMutating,
+	"OnlineDDL":                     commandMutatingByArgs,
+	"Panic":                         commandMutating,
+	"Ping":                          commandReadOnly,
+	"PlannedReparentShard":          commandMutating,
+	"RebuildKeyspaceGraph":          commandMutating,
+	"RebuildReplicationGraph":       commandMutating,
+	"RebuildVSchemaGraph":           commandMutating,
+	"RefreshState":                  commandMutating,
+	"RefreshStateByShard":           commandMutating,
+	"ReloadSchema":                  commandMutating,
+	"ReloadSchemaKeyspace":          commandMutating,
+	"ReloadSchemaShard":             commandMutating,
+	"RemoveBackup":                  commandMutating,
+	"RemoveBackups":                 commandMutating,
+	"RemoveKeyspaceCell":            commandMutating,
+	"RemoveShardCell":               commandMutating,
+	"RemoveTabletTag":               commandMutating,
+	"ReparentTablet":                commandMutating,
+	"Reshard":                       commandMutating,
+	"RestoreDrill":                  commandMutating,
+	"RestoreFromBackup":             commandMutating,
+	"RestoreShardToPointInTime":     commandMutating,
+	"RunHealthCheck":                commandReadOnly,
+	"SetReadOnly":                   commandMutating,
+	"SetReadWrite":                  commandMutating,
+	"SetShardIsPrimaryServing":      commandMutating,
+	"SetShardReadOnly":              commandMutating,
+	"SetShardReadWrite":             commandMutating,
+	"SetShardTabletControl":         commandMutating,
+	"ShardReadiness":                commandReadOnly,
+	"ShardReplicaMembership":        commandReadOnly,
+	"ShardReplicationAdd":           commandMutating,
+	"ShardReplicationFix":           commandMutating,
+	"ShardReplicationPositions":     commandReadOnly,
+	"ShardReplicationRemove":        commandMutating,
+	"Sleep":                         commandMutating,
+	"SnapshotShardState":            commandReadOnly,
+	"SourceShardAdd":                commandMutating,
+	"SourceShardDelete":             commandMutating,
+	"StartReplication":              commandMutating,
+	"StartReplicationUntilAfter":    commandMutating,
+	"StopReplication":               commandMutating,
+	"TabletExternallyReparented":    commandMutating,
+	"TopoCat":                       commandReadOnly,
+	"TopoCp":                        commandMutating,
+	"UpdateCellInfo":                commandMutating,
+	"UpdateCellsAlias":              commandMutating,
+	"UpdateSrvKeyspacePartition":    commandMutating,
+	"UpdateTabletAddrs":             commandMutating,
+	"UpdateThrottlerConfig":         commandMutating,
+	"VDiff":                         commandMutating,
+	"VReplicationExec":              commandMutating,
+	"Validate":                      commandReadOnly,
+	"ValidateBackup":                commandReadOnly,
+	"ValidateKeyspace":              commandReadOnly,
+	"ValidatePermissionsKeyspace":   commandReadOnly,
+	"ValidatePermissionsShard":      commandReadOnly,
+	"ValidateSchemaKeyspace":        commandReadOnly,
+	"ValidateSchemaShard":           commandReadOnly,
+	"ValidateShard":                 commandReadOnly,
+	"ValidateShardReplication":      commandReadOnly,
+	"ValidateVersionKeyspace":       commandReadOnly,
+	"ValidateVersionShard":          commandReadOnly,
+	"VtctldCommand":                 commandReadOnly,
+	"WaitForFilteredReplication":    commandReadOnly,
+	"WaitForShardReplicasPosition":  commandReadOnly,
+	"Workflow":                      commandMutatingByArgs,
+}
+
+// checkCommandWritable returns the error of the wrangler if it is read-only
+// and the command may mutate whatever its arguments.
+func checkCommandWritable(wr *wrangler.Wrangler, cmd command) error {
+	switch commandAccesses[cmd.name] {
+	case commandReadOnly, commandMutatingByArgs:
+		return nil
+	}
+	return wr.CheckWritable(cmd.name)
+}
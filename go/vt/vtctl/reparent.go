@@ -72,13 +72,14 @@ func commandReparentTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags
 		return fmt.Errorf("active reparent commands disabled (unset the --disable_active_reparents flag to enable)")
 	}
 
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("action ReparentTablet requires <tablet alias>")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -92,6 +93,7 @@ func commandInitShardPrimary(ctx context.Context, wr *wrangler.Wrangler, subFlag
 
 	force := subFlags.Bool("force", false, "will force the reparent even if the provided tablet is not writable or the shard primary")
 	waitReplicasTimeout := subFlags.Duration("wait_replicas_timeout", topo.RemoteOperationTimeout, "time to wait for replicas to catch up in reparenting")
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -102,7 +104,7 @@ func commandInitShardPrimary(ctx context.Context, wr *wrangler.Wrangler, subFlag
 	if err != nil {
 		return err
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(1))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(1))
 	if err != nil {
 		return err
 	}
@@ -120,6 +122,7 @@ func commandPlannedReparentShard(ctx context.Context, wr *wrangler.Wrangler, sub
 	newPrimary := subFlags.String("new_primary", "", "alias of a tablet that should be the new primary")
 	avoidTablet := subFlags.String("avoid_tablet", "", "alias of a tablet that should not be the primary, i.e. reparent to any other tablet if this one is the primary")
 	allowCrossCellPromotion := subFlags.Bool("allow-cross-cell-promotion", false, "allow cross cell promotions")
+	tablets := newTabletResolver(wr, subFlags)
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -141,13 +144,13 @@ func commandPlannedReparentShard(ctx context.Context, wr *wrangler.Wrangler, sub
 	}
 	var newPrimaryAlias, avoidTabletAlias *topodatapb.TabletAlias
 	if *newPrimary != "" {
-		newPrimaryAlias, err = topoproto.ParseTabletAlias(*newPrimary)
+		newPrimaryAlias, err = tablets.resolve(ctx, *newPrimary)
 		if err != nil {
 			return err
 		}
 	}
 	if *avoidTablet != "" {
-		avoidTabletAlias, err = topoproto.ParseTabletAlias(*avoidTablet)
+		avoidTabletAlias, err = tablets.resolve(ctx, *avoidTablet)
 		if err != nil {
 			return err
 		}
@@ -173,6 +176,7 @@ func commandEmergencyReparentShard(ctx context.Context, wr *wrangler.Wrangler, s
 	preventCrossCellPromotion := subFlags.Bool("prevent_cross_cell_promotion", false, "only promotes a new primary from the same cell as the previous primary")
 	ignoreReplicasList := subFlags.String("ignore_replicas", "", "comma-separated list of replica tablet aliases to ignore during emergency reparent")
 	waitForAllTablets := subFlags.Bool("wait_for_all_tablets", false, "should ERS wait for all the tablets to respond. Useful when all the tablets are reachable")
+	tablets := newTabletResolver(wr, subFlags)
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -194,7 +198,7 @@ func commandEmergencyReparentShard(ctx context.Context, wr *wrangler.Wrangler, s
 	}
 	var tabletAlias *topodatapb.TabletAlias
 	if *newPrimary != "" {
-		tabletAlias, err = topoproto.ParseTabletAlias(*newPrimary)
+		tabletAlias, err = tablets.resolve(ctx, *newPrimary)
 		if err != nil {
 			return err
 		}
@@ -210,6 +214,7 @@ func commandEmergencyReparentShard(ctx context.Context, wr *wrangler.Wrangler, s
 }
 
 func commandTabletExternallyReparented(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -217,7 +222,7 @@ func commandTabletExternallyReparented(ctx context.Context, wr *wrangler.Wrangle
 		return fmt.Errorf("action TabletExternallyReparented requires <tablet alias>")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -66,6 +66,10 @@ type PlannedReparentOptions struct {
 	WaitReplicasTimeout     time.Duration
 	TolerableReplLag        time.Duration
 	AllowCrossCellPromotion bool
+	// AvoidCells are cells in which no tablet is elected as the new primary,
+	// such as a cell that is being drained. Combine it with
+	// AllowCrossCellPromotion to move the primary out of its cell.
+	AvoidCells []string
 
 	// Private options managed internally. We use value-passing semantics to
 	// set these options inside a PlannedReparent without leaking these details
@@ -300,9 +304,10 @@ func (pr *PlannedReparenter) performGracefulPromotion(
 	if finalWaitErr != nil {
 		// It's possible that we've used up the calling context's timeout, or
 		// that not enough time is left on the it to finish the rollback.
-		// We create a new background context to avoid a partial rollback, which
-		// could leave the cluster in a worse state than when we started.
-		undoCtx, undoCancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+		// We create a context that isn't cancelled with the calling one to
+		// avoid a partial rollback, which could leave the cluster in a worse
+		// state than when we started. It keeps the shard lock and trace span.
+		undoCtx, undoCancel := context.WithTimeout(context.WithoutCancel(ctx), topo.RemoteOperationTimeout)
 		defer undoCancel()
 
 		if undoErr := pr.tmc.UndoDemotePrimary(undoCtx, currentPrimary.Tablet, policy.SemiSyncAckers(opts.durability, currentPrimary.Tablet) > 0); undoErr != nil {
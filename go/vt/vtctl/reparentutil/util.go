@@ -17,8 +17,10 @@ limitations under the License.
 package reparentutil
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -56,11 +58,11 @@ const (
 
 // ElectNewPrimary finds a tablet that should become a primary after reparent.
 // The criteria for the new primary-elect are (preferably) to be in the same
-// cell as the current primary, and to be different from avoidPrimaryAlias. The
-// tablet with the most advanced replication position is chosen to minimize the
-// amount of time spent catching up with the current primary. Further ties are
-// broken by the durability rules. Tablets taking backups are excluded from
-// consideration.
+// cell as the current primary, to be different from avoidPrimaryAlias, and to
+// be outside of the cells to avoid. The tablet with the most advanced
+// replication position is chosen to minimize the amount of time spent catching
+// up with the current primary. Further ties are broken by the durability
+// rules. Tablets taking backups are excluded from consideration.
 // Note that the search for the most advanced replication position will race
 // with transactions being executed on the current primary, so when all tablets
 // are at roughly the same position, then the choice of new primary-elect will
@@ -105,6 +107,9 @@ func ElectNewPrimary(
 		case !opts.AllowCrossCellPromotion && primaryCell != "" && tablet.Alias.Cell != primaryCell:
 			reasonsToInvalidate.WriteString(fmt.Sprintf("\n%v is not in the same cell as the previous primary", topoproto.TabletAliasString(tablet.Alias)))
 			continue
+		case slices.Contains(opts.AvoidCells, tablet.Alias.Cell):
+			reasonsToInvalidate.WriteString(fmt.Sprintf("\n%v is in a cell to avoid", topoproto.TabletAliasString(tablet.Alias)))
+			continue
 		case opts.AvoidPrimaryAlias != nil && topoproto.TabletAliasEqual(tablet.Alias, opts.AvoidPrimaryAlias):
 			reasonsToInvalidate.WriteString(fmt.Sprintf("\n%v matches the primary alias to avoid", topoproto.TabletAliasString(tablet.Alias)))
 			continue
@@ -393,3 +398,47 @@ func GetBackupCandidates(tablets []*topo.TabletInfo, stats []*replicationdatapb.
 	}
 	return res
 }
+
+// backupTypeRank orders the tablet types, other than the primary, a backup
+// of a shard can be taken from, most preferred first.
+var backupTypeRank = map[topodatapb.TabletType]int{
+	topodatapb.TabletType_RDONLY:  0,
+	topodatapb.TabletType_REPLICA: 1,
+	topodatapb.TabletType_SPARE:   2,
+}
+
+// IsBackupCandidateType returns true if a backup of a shard can be taken
+// from a replica of tabletType.
+func IsBackupCandidateType(tabletType topodatapb.TabletType) bool {
+	_, ok := backupTypeRank[tabletType]
+	return ok
+}
+
+// BackupCandidateMaxLag is the replication lag, in seconds, above which a
+// replica is only picked for a backup of its shard if no other replica is
+// within it, whatever their tablet types, so that a badly lagged RDONLY isn't
+// picked over a REPLICA that is caught up.
+const BackupCandidateMaxLag uint32 = 300
+
+// CompareBackupCandidates orders two replicas a backup of a shard can be
+// taken from, most preferred first. The replicas within
+// BackupCandidateMaxLag come first, by tablet type, RDONLY first, then
+// REPLICA, then SPARE, and then by replication lag. The replicas lagging
+// more than it come last, by replication lag. It returns a negative number if
+// a is preferred over b, a positive number if b is preferred, and zero if
+// neither is.
+func CompareBackupCandidates(aType topodatapb.TabletType, aLag uint32, bType topodatapb.TabletType, bLag uint32) int {
+	aLagging, bLagging := aLag > BackupCandidateMaxLag, bLag > BackupCandidateMaxLag
+	switch {
+	case aLagging && bLagging:
+		return cmp.Compare(aLag, bLag)
+	case aLagging:
+		return 1
+	case bLagging:
+		return -1
+	}
+	if c := cmp.Compare(backupTypeRank[aType], backupTypeRank[bType]); c != 0 {
+		return c
+	}
+	return cmp.Compare(aLag, bLag)
+}
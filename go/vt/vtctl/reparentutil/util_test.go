@@ -77,6 +77,7 @@ func TestElectNewPrimary(t *testing.T) {
 		avoidPrimaryAlias       *topodatapb.TabletAlias
 		tolerableReplLag        time.Duration
 		allowCrossCellPromotion bool
+		avoidCells              []string
 		expected                *topodatapb.TabletAlias
 		errContains             []string
 	}{
@@ -943,6 +944,65 @@ func TestElectNewPrimary(t *testing.T) {
 				Uid:  102,
 			},
 		},
+		{
+			name: "cross cell allowed but the most advanced replica is in a cell to avoid",
+			tmc: &chooseNewPrimaryTestTMClient{
+				// zone1-101 is behind zone2-201
+				replicationStatuses: map[string]*replicationdatapb.Status{
+					"zone1-0000000101": {
+						Position: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1",
+					},
+					"zone2-0000000201": {
+						Position: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5",
+					},
+				},
+			},
+			allowCrossCellPromotion: true,
+			avoidCells:              []string{"zone2"},
+			shardInfo: topo.NewShardInfo("testkeyspace", "-", &topodatapb.Shard{
+				PrimaryAlias: &topodatapb.TabletAlias{
+					Cell: "zone2",
+					Uid:  200,
+				},
+			}, nil),
+			tabletMap: map[string]*topo.TabletInfo{
+				"primary": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone2",
+							Uid:  200,
+						},
+						Type: topodatapb.TabletType_PRIMARY,
+					},
+				},
+				"replica1": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  101,
+						},
+						Type: topodatapb.TabletType_REPLICA,
+					},
+				},
+				"replica2": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone2",
+							Uid:  201,
+						},
+						Type: topodatapb.TabletType_REPLICA,
+					},
+				},
+			},
+			avoidPrimaryAlias: &topodatapb.TabletAlias{
+				Cell: "zone2",
+				Uid:  200,
+			},
+			expected: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  101,
+			},
+		},
 		{
 			name: "only available tablet is AvoidPrimary",
 			tmc: &chooseNewPrimaryTestTMClient{
@@ -1027,6 +1087,7 @@ zone1-0000000100 is not a replica`,
 				TolerableReplLag:        tt.tolerableReplLag,
 				durability:              durability,
 				AllowCrossCellPromotion: tt.allowCrossCellPromotion,
+				AvoidCells:              tt.avoidCells,
 				WaitReplicasTimeout:     time.Millisecond * 50,
 			}
 			actual, err := ElectNewPrimary(ctx, tt.tmc, tt.shardInfo, tt.tabletMap, tt.innodbBufferPoolData, options, logger)
@@ -1940,3 +2001,67 @@ func TestGetBackupCandidates(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareBackupCandidates(t *testing.T) {
+	tests := []struct {
+		name     string
+		aType    topodatapb.TabletType
+		aLag     uint32
+		bType    topodatapb.TabletType
+		bLag     uint32
+		expected int
+	}{
+		{
+			name:     "rdonly before replica despite its lag",
+			aType:    topodatapb.TabletType_RDONLY,
+			aLag:     30,
+			bType:    topodatapb.TabletType_REPLICA,
+			bLag:     0,
+			expected: -1,
+		},
+		{
+			name:     "replica before spare",
+			aType:    topodatapb.TabletType_SPARE,
+			bType:    topodatapb.TabletType_REPLICA,
+			expected: 1,
+		},
+		{
+			name:     "same type, least lag first",
+			aType:    topodatapb.TabletType_REPLICA,
+			aLag:     5,
+			bType:    topodatapb.TabletType_REPLICA,
+			bLag:     10,
+			expected: -1,
+		},
+		{
+			name:     "same type and lag",
+			aType:    topodatapb.TabletType_RDONLY,
+			bType:    topodatapb.TabletType_RDONLY,
+			expected: 0,
+		},
+		{
+			name:     "replica before rdonly lagging more than the max lag",
+			aType:    topodatapb.TabletType_RDONLY,
+			aLag:     BackupCandidateMaxLag + 1,
+			bType:    topodatapb.TabletType_REPLICA,
+			bLag:     BackupCandidateMaxLag,
+			expected: 1,
+		},
+		{
+			name:     "both lagging more than the max lag, least lag first",
+			aType:    topodatapb.TabletType_SPARE,
+			aLag:     BackupCandidateMaxLag + 10,
+			bType:    topodatapb.TabletType_RDONLY,
+			bLag:     BackupCandidateMaxLag + 20,
+			expected: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, CompareBackupCandidates(tt.aType, tt.aLag, tt.bType, tt.bLag))
+		})
+	}
+	require.True(t, IsBackupCandidateType(topodatapb.TabletType_SPARE))
+	require.False(t, IsBackupCandidateType(topodatapb.TabletType_PRIMARY))
+	require.False(t, IsBackupCandidateType(topodatapb.TabletType_DRAINED))
+}
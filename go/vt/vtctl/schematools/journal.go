@@ -0,0 +1,96 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schematools
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// SchemaChangesPath is the directory of the global topo under which the
+// schema changes applied to each keyspace are journaled, one file per
+// change in a directory per keyspace. It is not under the keyspace
+// directory, so that the journal is kept if the keyspace is deleted.
+const SchemaChangesPath = "schema_changes"
+
+// RecordSchemaChange journals the schema change in the global topo. The
+// change must have an AppliedAt time and an ExecutionUuid.
+func RecordSchemaChange(ctx context.Context, ts *topo.Server, change *vtctldatapb.SchemaChange) error {
+	if change.AppliedAt == nil || change.ExecutionUuid == "" {
+		return fmt.Errorf("schema change of keyspace %v has no applied_at time or execution_uuid", change.Keyspace)
+	}
+
+	data, err := change.MarshalVT()
+	if err != nil {
+		return err
+	}
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return err
+	}
+
+	// The file names sort in the order the changes were applied.
+	name := fmt.Sprintf("%020d-%s", protoutil.TimeFromProto(change.AppliedAt).UnixNano(), change.ExecutionUuid)
+	_, err = conn.Create(ctx, path.Join(SchemaChangesPath, change.Keyspace, name), data)
+	return err
+}
+
+// GetSchemaChangeHistory returns the journaled schema changes of the
+// keyspace, from the most recent one, and at most limit of them if it is
+// positive.
+func GetSchemaChangeHistory(ctx context.Context, ts *topo.Server, keyspace string, limit int) ([]*vtctldatapb.SchemaChange, error) {
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := path.Join(SchemaChangesPath, keyspace)
+	entries, err := conn.ListDir(ctx, dir, false /*full*/)
+	switch {
+	case topo.IsErrType(err, topo.NoNode):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	names := topo.DirEntriesToStringArray(entries)
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	if limit > 0 && len(names) > limit {
+		names = names[:limit]
+	}
+
+	changes := make([]*vtctldatapb.SchemaChange, 0, len(names))
+	for _, name := range names {
+		data, _, err := conn.Get(ctx, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		change := &vtctldatapb.SchemaChange{}
+		if err := change.UnmarshalVT(data); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal schema change %v of keyspace %v: %w", name, keyspace, err)
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schematools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestSchemaChangeJournal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+
+	changes, err := GetSchemaChangeHistory(ctx, ts, "ks", 0)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// The changes are recorded out of order, and returned from the most
+	// recent one.
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	var want []*vtctldatapb.SchemaChange
+	for _, i := range []int{1, 0, 2} {
+		change := &vtctldatapb.SchemaChange{
+			Keyspace:      "ks",
+			ExecutionUuid: fmt.Sprintf("uuid%d", i),
+			AppliedAt:     protoutil.TimeToProto(start.Add(time.Duration(i) * time.Hour)),
+			CallerId:      &vtrpcpb.CallerID{Principal: "alice"},
+			Sql:           []string{fmt.Sprintf("alter table t%d add column c int", i)},
+		}
+		require.NoError(t, RecordSchemaChange(ctx, ts, change))
+		want = append(want, change)
+	}
+	require.NoError(t, RecordSchemaChange(ctx, ts, &vtctldatapb.SchemaChange{
+		Keyspace:      "other",
+		ExecutionUuid: "uuid3",
+		AppliedAt:     protoutil.TimeToProto(start),
+	}))
+	want = []*vtctldatapb.SchemaChange{want[2], want[0], want[1]}
+
+	changes, err = GetSchemaChangeHistory(ctx, ts, "ks", 0)
+	require.NoError(t, err)
+	utils.MustMatch(t, want, changes)
+
+	changes, err = GetSchemaChangeHistory(ctx, ts, "ks", 2)
+	require.NoError(t, err)
+	utils.MustMatch(t, want[:2], changes)
+
+	err = RecordSchemaChange(ctx, ts, &vtctldatapb.SchemaChange{Keyspace: "ks"})
+	require.ErrorContains(t, err, "has no applied_at time or execution_uuid")
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schematools
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// DefaultMaxThrottleWait is the default maximum total time a ThrottleWaiter
+// waits for the throttler of a tablet.
+const DefaultMaxThrottleWait = 10 * time.Minute
+
+// throttleCheckInterval is how often a ThrottleWaiter checks the throttler
+// of a tablet while it is throttled.
+var throttleCheckInterval = time.Second
+
+// ThrottleWaiter makes schema changes that are applied directly on the
+// primaries wait while their throttler throttles them, so that they don't
+// add to the replication lag of an already loaded shard. Its methods are
+// safe for concurrent use, and do nothing on a nil ThrottleWaiter.
+type ThrottleWaiter struct {
+	tmc     tmclient.TabletManagerClient
+	logger  logutil.Logger
+	appName string
+	maxWait time.Duration
+
+	mu sync.Mutex
+	// waited is the total time waited for each tablet, keyed by alias.
+	waited map[string]time.Duration
+}
+
+// NewThrottleWaiter returns a ThrottleWaiter that checks the throttlers as
+// appName, or throttlerapp.DirectDDLName if it is empty, and gives up once
+// it has waited maxWait in total for a tablet, or DefaultMaxThrottleWait if
+// it is zero.
+func NewThrottleWaiter(tmc tmclient.TabletManagerClient, logger logutil.Logger, appName string, maxWait time.Duration) *ThrottleWaiter {
+	if appName == "" {
+		appName = throttlerapp.DirectDDLName.String()
+	}
+	if maxWait == 0 {
+		maxWait = DefaultMaxThrottleWait
+	}
+	return &ThrottleWaiter{
+		tmc:     tmc,
+		logger:  logger,
+		appName: appName,
+		maxWait: maxWait,
+		waited:  make(map[string]time.Duration),
+	}
+}
+
+// Wait returns once the throttler of the tablet doesn't throttle the app. It
+// returns an error if the throttler can't be checked, if ctx is done, or if
+// the total time waited for the tablet would exceed the maximum.
+func (w *ThrottleWaiter) Wait(ctx context.Context, tablet *topodatapb.Tablet) error {
+	if w == nil {
+		return nil
+	}
+
+	alias := topoproto.TabletAliasString(tablet.Alias)
+	req := &tabletmanagerdatapb.CheckThrottlerRequest{
+		AppName:       w.appName,
+		OkIfNotExists: true,
+	}
+	for {
+		resp, err := w.tmc.CheckThrottler(ctx, tablet, req)
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to check the throttler of %v", alias)
+		}
+		if resp.ResponseCode == tabletmanagerdatapb.CheckThrottlerResponseCode_OK {
+			return nil
+		}
+
+		w.mu.Lock()
+		waited := w.waited[alias]
+		w.mu.Unlock()
+		if waited+throttleCheckInterval > w.maxWait {
+			return vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "%v was throttled by %v for %v, which exceeds the maximum throttle wait of %v: %v",
+				w.appName, alias, waited, w.maxWait, resp.Summary)
+		}
+		w.logger.Infof("%v is throttled by %v, waiting %v (waited %v so far): %v", w.appName, alias, throttleCheckInterval, waited, resp.Summary)
+
+		start := time.Now()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(throttleCheckInterval):
+		}
+		w.mu.Lock()
+		w.waited[alias] += time.Since(start)
+		w.mu.Unlock()
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schematools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+type checkThrottlerTMC struct {
+	tmclient.TabletManagerClient
+
+	// throttledChecks is the number of checks that are throttled before the
+	// throttler lets the app through.
+	throttledChecks int
+	appNames        []string
+}
+
+func (tmc *checkThrottlerTMC) CheckThrottler(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error) {
+	tmc.appNames = append(tmc.appNames, req.AppName)
+	if len(tmc.appNames) <= tmc.throttledChecks {
+		return &tabletmanagerdatapb.CheckThrottlerResponse{
+			ResponseCode: tabletmanagerdatapb.CheckThrottlerResponseCode_THRESHOLD_EXCEEDED,
+			Summary:      "lag exceeds threshold",
+		}, nil
+	}
+	return &tabletmanagerdatapb.CheckThrottlerResponse{
+		ResponseCode: tabletmanagerdatapb.CheckThrottlerResponseCode_OK,
+	}, nil
+}
+
+func TestThrottleWaiter(t *testing.T) {
+	defer func(interval time.Duration) { throttleCheckInterval = interval }(throttleCheckInterval)
+	throttleCheckInterval = 10 * time.Millisecond
+
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  100,
+		},
+	}
+
+	tests := []struct {
+		name            string
+		appName         string
+		maxWait         time.Duration
+		throttledChecks int
+		wantAppName     string
+		wantChecks      int
+		wantErr         bool
+	}{
+		{
+			name:        "not throttled",
+			wantAppName: throttlerapp.DirectDDLName.String(),
+			wantChecks:  1,
+		},
+		{
+			name:            "waits until not throttled",
+			appName:         "my-app",
+			maxWait:         time.Second,
+			throttledChecks: 3,
+			wantAppName:     "my-app",
+			wantChecks:      4,
+		},
+		{
+			name:            "exceeds the maximum wait",
+			maxWait:         5 * time.Millisecond,
+			throttledChecks: 100,
+			wantAppName:     throttlerapp.DirectDDLName.String(),
+			wantChecks:      1,
+			wantErr:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmc := &checkThrottlerTMC{throttledChecks: tt.throttledChecks}
+			w := NewThrottleWaiter(tmc, logutil.NewMemoryLogger(), tt.appName, tt.maxWait)
+
+			err := w.Wait(context.Background(), tablet)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Len(t, tmc.appNames, tt.wantChecks)
+			for _, appName := range tmc.appNames {
+				assert.Equal(t, tt.wantAppName, appName)
+			}
+		})
+	}
+
+	t.Run("nil waiter", func(t *testing.T) {
+		var w *ThrottleWaiter
+		assert.NoError(t, w.Wait(context.Background(), tablet))
+	})
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctl
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/wrangler"
+)
+
+var (
+	// commandTimings records the count and the duration of the commands
+	// that ran, by command name and outcome.
+	commandTimings = stats.NewMultiTimings("VtctlCommands", "Vtctl commands run, by command and result", []string{"Command", "Result"})
+	// commandsRunning is the number of commands currently running, by
+	// command name.
+	commandsRunning = stats.NewGaugesWithSingleLabel("VtctlCommandsRunning", "Vtctl commands currently running", "Command")
+	// deprecatedCommands counts the commands invoked by a deprecated name,
+	// by that name.
+	deprecatedCommands = stats.NewCountersWithSingleLabel("VtctlDeprecatedCommands", "Vtctl commands invoked by a deprecated name, by name", "Name")
+)
+
+// commandStarted records that the command named name started, and returns
+// the function to call with its error once it is done. Failures are also
+// counted in the wrangler error stats, by category.
+func commandStarted(name string) func(err error) {
+	start := time.Now()
+	commandsRunning.Add(name, 1)
+	return func(err error) {
+		commandsRunning.Add(name, -1)
+		result := "Success"
+		if err != nil && err != pflag.ErrHelp {
+			result = "Failure"
+			wrangler.RecordError(err)
+		}
+		commandTimings.Record([]string{name, result}, start)
+	}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctl
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandStarted(t *testing.T) {
+	done := commandStarted("TestCommand")
+	require.EqualValues(t, 1, commandsRunning.Counts()["TestCommand"])
+	done(nil)
+	require.EqualValues(t, 0, commandsRunning.Counts()["TestCommand"])
+
+	commandStarted("TestCommand")(pflag.ErrHelp)
+	commandStarted("TestCommand")(errors.New("failed"))
+
+	counts := commandTimings.Counts()
+	require.EqualValues(t, 2, counts["TestCommand.Success"])
+	require.EqualValues(t, 1, counts["TestCommand.Failure"])
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctl
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+)
+
+var (
+	// optionalParamsRegexp matches the optional flags and the flags with a
+	// value in the params of a command.
+	optionalParamsRegexp = regexp.MustCompile(`\[[^\]]*\]|--\S+`)
+	// placeholderRegexp matches the placeholder of a positional argument.
+	placeholderRegexp = regexp.MustCompile(`<([^>]*)>`)
+)
+
+// annotateCommandSpan records the keyspace and shard that a command works
+// on, and the names of the flags it was given, in its span. Flag values and
+// other arguments are left out, as they may hold queries or other
+// sensitive data.
+func annotateCommandSpan(span trace.Span, cmd *command, subFlags *pflag.FlagSet) {
+	keyspace, shard := commandTarget(cmd.params, subFlags)
+	if keyspace != "" {
+		span.Annotate("keyspace", keyspace)
+	}
+	if shard != "" {
+		span.Annotate("shard", shard)
+	}
+
+	var flags []string
+	subFlags.Visit(func(f *pflag.Flag) {
+		flags = append(flags, f.Name)
+	})
+	if len(flags) > 0 {
+		sort.Strings(flags)
+		span.Annotate("flags", strings.Join(flags, ","))
+	}
+}
+
+// commandTarget returns the keyspace and shard that a command works on,
+// from its --keyspace, --shard or --keyspace_shard flags, or from its first
+// positional argument if params names it a keyspace or a keyspace/shard.
+func commandTarget(params string, subFlags *pflag.FlagSet) (keyspace, shard string) {
+	flagValue := func(name string) string {
+		if f := subFlags.Lookup(name); f != nil && f.Changed {
+			return f.Value.String()
+		}
+		return ""
+	}
+	keyspace, shard = flagValue("keyspace"), flagValue("shard")
+	if ks := flagValue("keyspace_shard"); ks != "" {
+		if k, s, err := topoproto.ParseKeyspaceShard(ks); err == nil {
+			keyspace, shard = k, s
+		}
+	}
+	if keyspace != "" || subFlags.NArg() == 0 {
+		return keyspace, shard
+	}
+
+	placeholder := placeholderRegexp.FindStringSubmatch(optionalParamsRegexp.ReplaceAllString(params, ""))
+	if placeholder == nil || !strings.HasPrefix(placeholder[1], "keyspace") {
+		return "", ""
+	}
+	arg := subFlags.Arg(0)
+	if strings.Contains(arg, "/") {
+		if k, s, err := topoproto.ParseKeyspaceShard(arg); err == nil {
+			return k, s
+		}
+		return "", ""
+	}
+	if strings.Contains(placeholder[1], ".") {
+		// A <keyspace.workflow> argument.
+		arg, _, _ = strings.Cut(arg, ".")
+	}
+	return arg, ""
+}
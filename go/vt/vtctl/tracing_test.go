@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctl
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandTarget(t *testing.T) {
+	tcases := []struct {
+		params       string
+		args         []string
+		wantKeyspace string
+		wantShard    string
+	}{{
+		params:       "[--ping-tablets] <keyspace/shard>",
+		args:         []string{"--ping-tablets", "ks/-80"},
+		wantKeyspace: "ks",
+		wantShard:    "-80",
+	}, {
+		params:       "[--ping-tablets] <keyspace name>",
+		args:         []string{"ks"},
+		wantKeyspace: "ks",
+	}, {
+		params:       "[--concurrency=8] <keyspace/shard|keyspace>",
+		args:         []string{"ks"},
+		wantKeyspace: "ks",
+	}, {
+		params:       "[--cells=<cells>] <action> <keyspace.workflow>",
+		args:         []string{"Show", "ks.wf"},
+		wantKeyspace: "",
+	}, {
+		params:       "--keyspace=<keyspace> --shard=<shard> <tablet alias> <tablet type>",
+		args:         []string{"--keyspace=ks", "--shard=0", "zone1-100", "replica"},
+		wantKeyspace: "ks",
+		wantShard:    "0",
+	}, {
+		params:       "--keyspace_shard=<keyspace/shard> [--new_primary=<tablet alias>]",
+		args:         []string{"--keyspace_shard=ks/80-"},
+		wantKeyspace: "ks",
+		wantShard:    "80-",
+	}, {
+		params: "<tablet alias>",
+		args:   []string{"zone1-100"},
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.params, func(t *testing.T) {
+			subFlags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			subFlags.Bool("ping-tablets", false, "")
+			subFlags.Int("concurrency", 8, "")
+			subFlags.String("cells", "", "")
+			subFlags.String("keyspace", "", "")
+			subFlags.String("shard", "", "")
+			subFlags.String("keyspace_shard", "", "")
+			require.NoError(t, subFlags.Parse(tcase.args))
+
+			keyspace, shard := commandTarget(tcase.params, subFlags)
+			require.Equal(t, tcase.wantKeyspace, keyspace)
+			require.Equal(t, tcase.wantShard, shard)
+		})
+	}
+}
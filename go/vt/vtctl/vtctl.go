@@ -29,7 +29,11 @@ COMMAND ARGUMENT DEFINITIONS
 
 - tablet alias: A Tablet Alias uniquely identifies a vttablet. The argument
                 value is in the format
-                <code>&lt;cell name&gt;-&lt;uid&gt;</code>.
+                <code>&lt;cell name&gt;-&lt;uid&gt;</code>. Commands also
+                accept the <code>&lt;hostname&gt;</code> or
+                <code>&lt;hostname&gt;:&lt;port&gt;</code> of a tablet,
+                which is resolved by scanning the tablet records of the
+                cells given with --cell, or of all cells.
 
 - keyspace, keyspace name: The name of a sharded database that contains one
             or more tables. Vitess distributes keyspace shards into multiple
@@ -87,6 +91,7 @@ import (
 	"io"
 	"math"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -99,6 +104,7 @@ import (
 
 	"vitess.io/vitess/go/constants/sidecar"
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/ptr"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil/policy"
 
@@ -108,6 +114,7 @@ import (
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/textutil"
+	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/discovery"
 	hk "vitess.io/vitess/go/vt/hook"
 	"vitess.io/vitess/go/vt/key"
@@ -115,6 +122,7 @@ import (
 	"vitess.io/vitess/go/vt/logutil"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
@@ -122,14 +130,15 @@ import (
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/proto/vttime"
 	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools"
 	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+	"vitess.io/vitess/go/vt/vtctl/schematools"
 	"vitess.io/vitess/go/vt/vtctl/workflow"
 	"vitess.io/vitess/go/vt/vterrors"
-	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
 	"vitess.io/vitess/go/vt/wrangler"
 )
@@ -137,6 +146,26 @@ import (
 // ErrUnknownCommand is returned for an unknown command.
 var ErrUnknownCommand = errors.New("unknown command")
 
+var (
+	// failOnDeprecated makes invoking a deprecated command, or a command by
+	// a deprecated alias, fail instead of emitting a warning.
+	failOnDeprecated bool
+
+	// verbose makes the commands log the timeouts in effect when they start.
+	verbose bool
+)
+
+func init() {
+	servenv.OnParseFor("vtctl", RegisterFlags)
+	servenv.OnParseFor("vtctld", RegisterFlags)
+}
+
+// RegisterFlags registers the flags of the vtctl commands on fs.
+func RegisterFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&failOnDeprecated, "fail-on-deprecated", failOnDeprecated, "if set, vtctl commands invoked by a deprecated name fail instead of emitting a deprecation warning. Meant for CI, to catch scripts that still use deprecated commands.")
+	fs.BoolVar(&verbose, "verbose", verbose, "if set, vtctl commands log the timeouts in effect when they start: the command timeout, and the --shard-timeout and --tablet-timeout derived from it unless they are set.")
+}
+
 const errWorkflowUpdateWithoutChanges = "no updates were provided; use --cells, --tablet-types, or --on-ddl to specify new values"
 
 type command struct {
@@ -148,6 +177,10 @@ type command struct {
 	// if set, PrintAllCommands will not show this command
 	hidden bool
 
+	// if set, the command caches the topo records it reads, as it would
+	// otherwise read the same ones many times.
+	cacheTopoReads bool
+
 	// (ajm188) - before we transition to cobra, we need to know whether to
 	// strip off a -- after the action (i.e. "command") name in RunCommand so
 	// that parsing continues to work.
@@ -156,6 +189,13 @@ type command struct {
 	// deprecation support
 	deprecated   bool
 	deprecatedBy string
+
+	// aliases are other names the command can be invoked by.
+	aliases []string
+	// deprecatedAliases are former names of the command. Invoking the
+	// command by one of them still works, but emits a deprecation warning
+	// naming the command.
+	deprecatedAliases []string
 }
 
 type commandGroup struct {
@@ -184,6 +224,12 @@ var commands = []commandGroup{
 				params: "<tablet alias>",
 				help:   "Outputs a JSON structure that contains information about the Tablet.",
 			},
+			{
+				name:   "GetFullStatus",
+				method: commandGetFullStatus,
+				params: "[--format=text|json] <tablet alias|keyspace/shard>",
+				help:   "Outputs the full status of the MySQL of a tablet: its server identity and version, read-only flags, binary log and GTID settings, replication and primary status, and semi-sync state, grouped by section. Given a shard, reads the full status of all its tablets concurrently and highlights the configuration that differs from the primary. The tablets that can't be reached are reported with the others.",
+			},
 			{
 				name:       "UpdateTabletAddrs",
 				method:     commandUpdateTabletAddrs,
@@ -194,8 +240,20 @@ var commands = []commandGroup{
 			{
 				name:   "DeleteTablet",
 				method: commandDeleteTablet,
-				params: "[--allow_primary] <tablet alias> ...",
-				help:   "Deletes tablet(s) from the topology.",
+				params: "[--allow_primary] [--force-corrupt --keyspace=<keyspace> --shard=<shard>] <tablet alias> ...",
+				help:   "Deletes tablet(s) from the topology. With --force-corrupt, a tablet record that can no longer be unmarshaled is deleted anyway, and its alias is removed from the replication graph of the given keyspace/shard in every cell.",
+			},
+			{
+				name:   "DecommissionTablet",
+				method: commandDecommissionTablet,
+				params: "[--drain-timeout=1m] [--max-qps=1] [--force] <tablet alias>",
+				help:   "Removes a tablet from service. It refuses the shard primary, and the only serving tablet of its type in the shard unless --force is set, changes the type of the tablet to DRAINED, waits until its health stream reports at most --max-qps, and deletes the tablet record and its replication graph entry. The outcome of each phase is logged, and running the command again after an interruption resumes it.",
+			},
+			{
+				name:   "AuditTablets",
+				method: commandAuditTablets,
+				params: "[--cells=<cell1>,<cell2>,...] [--health-window=30s] [--older-than=<duration>] [--concurrency=8] [--delete-stale]",
+				help:   "Outputs as JSON the tablet records that look stale: the tablet didn't send a health record within --health-window, its hostname doesn't resolve, or it last started more than --older-than ago. With --delete-stale, the records matching all of these are then deleted, except PRIMARY tablets.",
 			},
 			{
 				name:   "SetReadOnly",
@@ -210,23 +268,47 @@ var commands = []commandGroup{
 				help:   "Sets the tablet as read-write.",
 			},
 			{
-				name:   "StartReplication",
-				method: commandStartReplication,
-				params: "<table alias>",
-				help:   "Starts replication on the specified tablet.",
+				name:              "StartReplication",
+				method:            commandStartReplication,
+				params:            "<table alias>",
+				help:              "Starts replication on the specified tablet.",
+				deprecatedAliases: []string{"StartSlave"},
 			},
 			{
-				name:   "StopReplication",
-				method: commandStopReplication,
-				params: "<tablet alias>",
-				help:   "Stops replication on the specified tablet.",
+				name:   "StartReplicationUntilAfter",
+				method: commandStartReplicationUntilAfter,
+				params: "[--timeout=1m] <tablet alias> <position>",
+				help: "Starts replication on the specified tablet until it is past the given position, e.g. MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615, then waits for the tablet to reach it.\n" +
+					"Prints the last position the tablet reported, and fails if it did not reach the position within --timeout.",
+			},
+			{
+				name:              "StopReplication",
+				method:            commandStopReplication,
+				params:            "<tablet alias>",
+				help:              "Stops replication on the specified tablet.",
+				deprecatedAliases: []string{"StopSlave"},
 			},
 			{
 				name:   "ChangeTabletType",
 				method: commandChangeTabletType,
-				params: "[--dry-run] <tablet alias> <tablet type>",
+				params: "[--dry-run] [--min-remaining=<n>] <tablet alias> <tablet type>",
 				help: "Changes the db type for the specified tablet, if possible. This command is used primarily to arrange replicas, and it will not convert a primary.\n" +
+					"With --dry-run, prints a JSON report of whether the change is allowed (including the --min-remaining check) and how many serving tablets of the current type would remain in the tablet's cell and shard, without making the change. The command fails if the change would not be allowed.\n" +
+					"With --min-remaining, refuses to move a serving tablet out of its type if fewer than <n> serving tablets of that type would remain in the cell and shard.\n" +
 					"NOTE: This command automatically updates the serving graph.\n",
+				deprecatedAliases: []string{"ChangeSlaveType"},
+			},
+			{
+				name:   "AddTabletTag",
+				method: commandAddTabletTag,
+				params: "<tablet alias> <key:value> ...",
+				help:   "Adds or updates tags on the tablet, which updates its record and broadcasts them in its health stream. Tag values can't be empty.",
+			},
+			{
+				name:   "RemoveTabletTag",
+				method: commandRemoveTabletTag,
+				params: "<tablet alias> <key> ...",
+				help:   "Removes tags from the tablet, which updates its record and stops broadcasting them in its health stream.",
 			},
 			{
 				name:   "Ping",
@@ -261,21 +343,22 @@ var commands = []commandGroup{
 			{
 				name:   "ExecuteHook",
 				method: commandExecuteHook,
-				params: "<tablet alias> <hook name> [<param1=value1> <param2=value2> ...]",
+				params: "[--env=<KEY=VALUE> ...] [--timeout=<duration>] [--format=text|json] <tablet alias> <hook name> [<param1=value1> <param2=value2> ...]",
 				help: "Runs the specified hook on the given tablet. A hook is a script that resides in the $VTROOT/vthook directory. You can put any script into that directory and use this command to run that script.\n" +
-					"For this command, the param=value arguments are parameters that the command passes to the specified hook.",
+					"For this command, the param=value arguments are parameters that the command passes to the specified hook, and each --env adds a variable to the hook's environment.\n" +
+					"The command fails if the hook exits with a nonzero status or runs longer than --timeout.",
 				disableFlagInterspersal: true,
 			},
 			{
 				name:   "ExecuteFetchAsApp",
 				method: commandExecuteFetchAsApp,
-				params: "[--max_rows=10000] [--json] [--use_pool] <tablet alias> <sql command>",
-				help:   "Runs the given SQL command as a App on the remote tablet.",
+				params: "[--max_rows=10000] [--json|--format=text|json|csv] [--csv_header] [--csv_null=<string>] [--csv_binary=base64|hex] [--use_pool] [--caller-id=<principal>] [--caller-groups=<group1,group2,...>] <tablet alias> <sql command>",
+				help:   "Runs the given SQL command as a App on the remote tablet. With --caller-id, the tablet first checks its table ACLs for the principal, in the given groups, as its query service would: the command is rejected if they deny it and the tablet runs with --queryserver-config-strict-table-acl, unless --queryserver-config-enable-table-acl-dry-run is set or the principal is in --queryserver-config-acl-exempt-acl.",
 			},
 			{
 				name:   "ExecuteFetchAsDba",
 				method: commandExecuteFetchAsDba,
-				params: "[--max_rows=10000] [--disable_binlogs] [--json] <tablet alias> <sql command>",
+				params: "[--max_rows=10000] [--disable_binlogs] [--json|--format=text|json|csv] [--csv_header] [--csv_null=<string>] [--csv_binary=base64|hex] <tablet alias> <sql command>",
 				help:   "Runs the given SQL command as a DBA on the remote tablet.",
 			},
 			{
@@ -303,10 +386,11 @@ var commands = []commandGroup{
 				help:   "Outputs a JSON structure that contains information about the Shard.",
 			},
 			{
-				name:   "ValidateShard",
-				method: commandValidateShard,
-				params: "[--ping-tablets] <keyspace/shard>",
-				help:   "Validates that all nodes that are reachable from this shard are consistent.",
+				name:           "ValidateShard",
+				method:         commandValidateShard,
+				params:         "[--ping-tablets] [--ping-timeout=<duration>] [--include-serving-state] [--serving-state-wait=<duration>] [--json] <keyspace/shard>",
+				help:           "Validates that all nodes that are reachable from this shard are consistent. With --ping-tablets, the tablets are pinged in parallel, each with its own timeout, and every failed ping is reported. With --include-serving-state, the tablet type and serving state each tablet broadcasts in its health stream are compared with its topo record, and tablets that send no health data within --serving-state-wait are reported as warnings.",
+				cacheTopoReads: true,
 			},
 			{
 				name:   "ShardReplicationPositions",
@@ -315,16 +399,66 @@ var commands = []commandGroup{
 				help:   "Shows the replication status of each replica in the shard graph. In this case, the status refers to the replication lag between the primary vttablet and the replica vttablet. In Vitess, data is always written to the primary vttablet first and then replicated to all replica vttablets. Output is sorted by tablet type, then replication position. Use ctrl-C to interrupt command and see partial result if needed.",
 			},
 			{
-				name:   "ListShardTablets",
-				method: commandListShardTablets,
-				params: "<keyspace/shard>",
-				help:   "Lists all tablets in the specified shard.",
+				name:   "DiffShardVariables",
+				method: commandDiffShardVariables,
+				params: "[--variables=<name1>,<name2>,...] [--include-all] <keyspace/shard>",
+				help:   "Compares the MySQL global variables of every tablet in the shard with those of the primary, and outputs the differing values as JSON. Variables that always differ between servers (server_uuid, gtid_executed, hostname, paths...) are skipped unless --include-all is set or they are listed in --variables. Returns an error if any tablet differs.",
+			},
+			{
+				name:   "ShardReplicaMembership",
+				method: commandShardReplicaMembership,
+				params: "[--format=text|json] <keyspace/shard>",
+				help:   "Compares the replicas attached to the MySQL of the shard primary with the replication graph of the shard. Reports replicas attached but not in the graph, graph entries not attached, and replica addresses that don't map to any known tablet. Returns an error if they differ.",
+			},
+			{
+				name:   "ShardReadiness",
+				method: commandShardReadiness,
+				params: "[--min-replicas=1] [--max-lag=30s] [--format=text|json] <keyspace/shard>",
+				help:   "Reports whether the shard is healthy enough for a reparent, a deploy or a backup, from the shard record, the replication graph and the health of its tablets: whether the primary is serving, the serving replicas per cell, the maximum replication lag, the tablets restoring or backing up and the ValidateShard findings. Returns an error if the primary isn't serving, a cell with replica tablets has fewer than --min-replicas serving, the lag exceeds --max-lag or there are validation errors.",
+			},
+			{
+				name:   "WaitForShardReplicasPosition",
+				method: commandWaitForShardReplicasPosition,
+				params: "[--position=<position>] [--timeout=30s] [--tablet-types=REPLICA,RDONLY] [--tags=tag1:value1,tag2:value2] [--format=text|json] <keyspace/shard>",
+				help:   "Waits until the tablets of the shard of the given types and tags have applied the position, or the current position of the shard primary if it is not given. The tablets are waited for concurrently, and the report lists how long each took and the tablets that were skipped. Returns an error if any tablet didn't reach the position within the timeout.",
+			},
+			{
+				name:   "GetShardJournals",
+				method: commandGetShardJournals,
+				params: "[--format=text|json] [--migration-id=<id>] <keyspace/shard> | --migration-id=<id> <keyspace>",
+				help:   "Reads the resharding journals that SwitchWrites created on the primary of the shard, and prints their id, migration type, tables, participant shards and source positions. Given a keyspace, finds the journal of the migration id on the primaries of all its shards, and reports the participants it is missing from.",
 			},
 			{
-				name:   "SetShardIsPrimaryServing",
-				method: commandSetShardIsPrimaryServing,
-				params: "<keyspace/shard> <is_serving>",
-				help:   "Add or remove a shard from serving. This is meant as an emergency function. It does not rebuild any serving graph i.e. does not run 'RebuildKeyspaceGraph'.",
+				name:   "GetPrimaryTermHistory",
+				method: commandGetPrimaryTermHistory,
+				params: "[--format=text|json] <keyspace/shard> | [--format=text|json] [--window=24h] [--max-changes=3] <keyspace>",
+				help:   "Prints the primary changes of the shard in chronological order, with the time between them, from the reparent journal of the primary. If the journal can't be read, reconstructs what it can from the primary term start times of the shard and tablet records. Given a keyspace, prints the history of all its shards, and flags the shards whose primary changed more than --max-changes times in the last --window. Returns an error if any shard is flagged.",
+			},
+			{
+				name:   "SnapshotShardState",
+				method: commandSnapshotShardState,
+				params: "[--output-dir=<dir>] <keyspace/shard> | --compare <before file> <after file>",
+				help:   "Collects concurrently the shard record, the replication graph and serving graph entries of every cell, the tablet records, and the replication status and full status of each tablet of the shard, for incident forensics. Prints them as JSON, or writes them to a timestamped JSON file in the output directory of the host running the command. With --compare, prints what changed between two snapshot files.",
+			},
+			{
+				name:   "CheckErrantGTIDs",
+				method: commandCheckErrantGTIDs,
+				params: "[--format=text|json] <keyspace/shard|keyspace>",
+				help:   "Compares the executed GTID set of each replica of the shard with the one of the primary, and reports the errant transactions of each replica, which are not on the primary and didn't originate from it, with their count by originating server UUID. Given a keyspace, checks all its shards and summarizes the shards with errant GTIDs. Returns an error if any replica has errant GTIDs, or if a shard or a replica couldn't be checked.",
+			},
+			{
+				name:           "ListShardTablets",
+				method:         commandListShardTablets,
+				params:         "<keyspace/shard>",
+				help:           "Lists all tablets in the specified shard.",
+				cacheTopoReads: true,
+			},
+			{
+				name:              "SetShardIsPrimaryServing",
+				method:            commandSetShardIsPrimaryServing,
+				params:            "<keyspace/shard> <is_serving>",
+				help:              "Add or remove a shard from serving. This is meant as an emergency function. It does not rebuild any serving graph i.e. does not run 'RebuildKeyspaceGraph'.",
+				deprecatedAliases: []string{"SetShardIsMasterServing"},
 			},
 			{
 				name:   "SetShardTabletControl",
@@ -335,6 +469,20 @@ var commands = []commandGroup{
 					"To change the list of denied tables, specify the 'denied_tables' parameter with the new list.\n" +
 					"To just remove the ShardTabletControl entirely, use the 'remove' flag.",
 			},
+			{
+				name:   "SetShardReadOnly",
+				method: commandSetShardReadOnly,
+				params: "[--concurrency=8] <keyspace/shard|keyspace>",
+				help: "Makes the primary of the shard read-only, then verifies @@global.read_only on it.\n" +
+					"Given a keyspace, does this for every shard in it, at most --concurrency shards at a time, and reports how many shards were changed, verified and failed.",
+			},
+			{
+				name:   "SetShardReadWrite",
+				method: commandSetShardReadWrite,
+				params: "[--concurrency=8] <keyspace/shard|keyspace>",
+				help: "Makes the primary of the shard read-write, then verifies @@global.read_only on it.\n" +
+					"Given a keyspace, does this for every shard in it, at most --concurrency shards at a time, and reports how many shards were changed, verified and failed.",
+			},
 			{
 				name:   "UpdateSrvKeyspacePartition",
 				method: commandUpdateSrvKeyspacePartition,
@@ -371,7 +519,14 @@ var commands = []commandGroup{
 				name:   "ShardReplicationFix",
 				method: commandShardReplicationFix,
 				params: "<cell> <keyspace/shard>",
-				help:   "Walks through a ShardReplication object and fixes the first error that it encounters.",
+				help:   "Fixes the ShardReplication object of the shard in the given cell: removes the entries of tablets that don't exist or belong elsewhere, and adds the tablets of the shard that are missing. Prints the added and removed tablets.",
+			},
+			{
+				name:           "ValidateShardReplication",
+				method:         commandValidateShardReplication,
+				params:         "[--cells=c1,c2,...] [--json] <keyspace/shard>",
+				help:           "Validates that the ShardReplication objects of the shard in the given cells, or in all cells, list each tablet once, in the cell of its alias. This check is also part of ValidateShard.",
+				cacheTopoReads: true,
 			},
 			{
 				name:   "WaitForFilteredReplication",
@@ -428,25 +583,32 @@ var commands = []commandGroup{
 			{
 				name:   "RebuildKeyspaceGraph",
 				method: commandRebuildKeyspaceGraph,
-				params: "[--cells=c1,c2,...] [--allow_partial] <keyspace> ...",
-				help:   "Rebuilds the serving data for the keyspace. This command may trigger an update to all connected clients.",
+				params: "[--cells=c1,c2,...] [--allow_partial] [--force-write] <keyspace> ...",
+				help:   "Rebuilds the serving data for the keyspace. This command may trigger an update to all connected clients. The serving data that is unchanged is not written again, unless --force-write is set.",
+			},
+			{
+				name:   "RebuildReplicationGraph",
+				method: commandRebuildReplicationGraph,
+				params: "[--cells=c1,c2,...] <keyspace>",
+				help:   "Rebuilds the ShardReplication objects of all shards of the keyspace in the given cells, or in all cells, from the tablet records: removes the entries of tablets that don't exist or belong elsewhere, and adds the tablets that are missing. Each shard is locked while its objects are rebuilt. Prints the tablets added and removed for each shard and cell.",
 			},
 			{
-				name:   "ValidateKeyspace",
-				method: commandValidateKeyspace,
-				params: "[--ping-tablets] <keyspace name>",
-				help:   "Validates that all nodes reachable from the specified keyspace are consistent.",
+				name:           "ValidateKeyspace",
+				method:         commandValidateKeyspace,
+				params:         "[--ping-tablets] [--check-srv-keyspace] [--json] <keyspace name>",
+				help:           "Validates that all nodes reachable from the specified keyspace are consistent. With --check-srv-keyspace, also compares the SrvKeyspace of each cell with the one RebuildKeyspaceGraph would compute from the shard records, and reports the cells where it is stale or missing.",
+				cacheTopoReads: true,
 			},
 			{
 				name:   "Reshard",
 				method: commandReshard,
-				params: "[--source_shards=<source_shards>] [--target_shards=<target_shards>] [--cells=<cells>] [--tablet_types=<source_tablet_types>] [--on-ddl=<ddl-action>] [--defer-secondary-keys] [--skip_schema_copy] <action> 'action must be one of the following: Create, Complete, Cancel, SwitchTraffic, ReverseTrafffic, Show, or Progress' <keyspace.workflow>",
+				params: "[--source_shards=<source_shards>] [--target_shards=<target_shards>] [--cells=<cells>] [--tablet_types=<source_tablet_types>] [--on-ddl=<ddl-action>] [--defer-secondary-keys] [--skip_schema_copy] [--verify-reverse] [--verify-reverse-sample-pct=<pct>] [--verify-reverse-tables=<tables>] <action> 'action must be one of the following: Create, Complete, Cancel, SwitchTraffic, ReverseTrafffic, Show, or Progress' <keyspace.workflow>",
 				help:   "Start a Resharding process.",
 			},
 			{
 				name:   "MoveTables",
 				method: commandMoveTables,
-				params: "[--source=<sourceKs>] [--tables=<tableSpecs>] [--cells=<cells>] [--tablet_types=<source_tablet_types>] [--all] [--exclude=<tables>] [--auto_start] [--stop_after_copy] [--defer-secondary-keys] [--on-ddl=<ddl-action>] [--source_shards=<source_shards>] [--source_time_zone=<mysql_time_zone>] [--initialize-target-sequences] [--no-routing-rules] <action> 'action must be one of the following: Create, Complete, Cancel, SwitchTraffic, ReverseTrafffic, Show, or Progress' <targetKs.workflow>",
+				params: "[--source=<sourceKs>] [--tables=<tableSpecs>] [--cells=<cells>] [--tablet_types=<source_tablet_types>] [--all] [--exclude=<tables>] [--auto_start] [--stop_after_copy] [--defer-secondary-keys] [--on-ddl=<ddl-action>] [--source_shards=<source_shards>] [--source_time_zone=<mysql_time_zone>] [--initialize-target-sequences] [--no-routing-rules] [--verify-reverse] [--verify-reverse-sample-pct=<pct>] [--verify-reverse-tables=<tables>] <action> 'action must be one of the following: Create, Complete, Cancel, SwitchTraffic, ReverseTrafffic, Show, or Progress' <targetKs.workflow>",
 				help:   `Move table(s) to another keyspace, table_specs is a list of tables or the tables section of the vschema for the target keyspace. Example: '{"t1":{"column_vindexes": [{"column": "id1", "name": "hash"}]}, "t2":{"column_vindexes": [{"column": "id2", "name": "hash"}]}}'.  In the case of an unsharded target keyspace the vschema for each table may be empty. Example: '{"t1":{}, "t2":{}}'.`,
 			},
 			{
@@ -476,14 +638,21 @@ var commands = []commandGroup{
 			{
 				name:   "VDiff",
 				method: commandVDiff,
-				params: "[--source_cell=<cell>] [--target_cell=<cell>] [--tablet_types=in_order:RDONLY,REPLICA,PRIMARY] [--limit=<max rows to diff>] [--tables=<table list>] [--format=json] [--auto-retry] [--verbose] [--max_extra_rows_to_compare=1000] [--filtered_replication_wait_time=30s] [--debug_query] [--only_pks] [--wait] [--wait-update-interval=1m] <keyspace.workflow> [<action>] [<UUID>]",
+				params: "[--source_cell=<cell>] [--target_cell=<cell>] [--tablet_types=in_order:RDONLY,REPLICA,PRIMARY] [--limit=<max rows to diff>] [--tables=<table list>] [--format=json] [--auto-retry] [--verbose] [--max_extra_rows_to_compare=1000] [--filtered_replication_wait_time=30s] [--debug_query] [--only_pks] [--collation-aware] [--wait] [--wait-update-interval=1m] <keyspace.workflow> [<action>] [<UUID>]",
 				help:   "Perform a diff of all tables in the workflow",
 			},
 			{
-				name:   "FindAllShardsInKeyspace",
-				method: commandFindAllShardsInKeyspace,
-				params: "<keyspace>",
-				help:   "Displays all of the shards in the specified keyspace.",
+				name:   "CompareRowCounts",
+				method: commandCompareRowCounts,
+				params: "[--tables=<table list>] [--approximate] [--tolerance-pct=0] [--concurrency=8] [--format=text|json] [--source-shards=<shard list>] [--target-shards=<shard list>] <keyspace.workflow> | <source keyspace> <target keyspace>",
+				help:   "Counts the rows of every table of a workflow on an RDONLY, or else a REPLICA, tablet of each of its source and target shards, so as not to load the primaries, and compares the total of the target shards with the one of the source shards. Given a source and a target keyspace instead, compares the tables of the source keyspace, or those of --tables, across all the serving shards of both, or those of --source-shards and --target-shards, which are required if the keyspaces are the same. --approximate uses the row estimates of information_schema.tables rather than COUNT(*). At most --concurrency queries run at a time. Returns an error if the counts of a table differ by more than --tolerance-pct percent of its source row count, or if a table couldn't be counted.",
+			},
+			{
+				name:           "FindAllShardsInKeyspace",
+				method:         commandFindAllShardsInKeyspace,
+				params:         "<keyspace>",
+				help:           "Displays all of the shards in the specified keyspace.",
+				cacheTopoReads: true,
 			},
 			{
 				name:   "Mount",
@@ -496,28 +665,39 @@ var commands = []commandGroup{
 	{
 		"Generic", []command{
 			{
-				name:   "Validate",
-				method: commandValidate,
-				params: "[--ping-tablets]",
-				help:   "Validates that all nodes reachable from the global replication graph and that all tablets in all discoverable cells are consistent.",
+				name:           "Validate",
+				method:         commandValidate,
+				params:         "[--ping-tablets] [--json]",
+				help:           "Validates that all nodes reachable from the global replication graph and that all tablets in all discoverable cells are consistent.",
+				cacheTopoReads: true,
 			},
 			{
-				name:   "ListAllTablets",
-				method: commandListAllTablets,
-				params: "[--keyspace=''] [--tablet_type=<PRIMARY,REPLICA,RDONLY,SPARE>] [<cell_name1>,<cell_name2>,...]",
-				help:   "Lists all tablets in an awk-friendly way.",
+				name:           "ListAllTablets",
+				method:         commandListAllTablets,
+				params:         "[--keyspace=''] [--tablet_type=<PRIMARY,REPLICA,RDONLY,SPARE>] [<cell_name1>,<cell_name2>,...]",
+				help:           "Lists all tablets in an awk-friendly way.",
+				cacheTopoReads: true,
+				aliases:        []string{"GetTablets"},
 			},
 			{
-				name:   "ListTablets",
-				method: commandListTablets,
-				params: "<tablet alias> ...",
-				help:   "Lists specified tablets in an awk-friendly way.",
+				name:           "ListTablets",
+				method:         commandListTablets,
+				params:         "<tablet alias> ...",
+				help:           "Lists specified tablets in an awk-friendly way.",
+				cacheTopoReads: true,
 			},
 			{
 				name:   "GenerateShardRanges",
 				method: commandGenerateShardRanges,
-				params: "[--num_shards 2]",
-				help:   "Generates shard ranges assuming a keyspace with N shards.",
+				params: "[--num_shards 2] [--key_width=<bytes>] [--allow_uneven] [--format=json] [--create=<keyspace>]",
+				help:   "Generates shard ranges assuming a keyspace with N shards. The shard count must be a power of two unless --allow_uneven is set. With --create, also creates the shards in the given keyspace.",
+			},
+			{
+				name:   "GetCompletionData",
+				method: commandGetCompletionData,
+				params: "[--cell=<cell>] [--max-entries=10000] [--workflows]",
+				help:   "Outputs as JSON the cells, keyspaces, shards per keyspace and, with --workflows, workflows per keyspace, for shell completion. Shards are read from the SrvKeyspaces of --cell, or of the first cell. At most --max-entries names are output, and the output is marked truncated if some are left out.",
+				hidden: true,
 			},
 			{
 				name:   "Panic",
@@ -563,14 +743,14 @@ var commands = []commandGroup{
 			{
 				name:   "ReloadSchema",
 				method: commandReloadSchema,
-				params: "<tablet alias>",
-				help:   "Reloads the schema on a remote tablet.",
+				params: "[--wait] [--wait-table=<table>] [--wait-timeout=30s] <tablet alias>",
+				help:   "Reloads the schema on a remote tablet. With --wait, reads the schema of the tablet until the given table shows up, or without one until the schema changes, and prints a confirmation.",
 			},
 			{
 				name:   "ReloadSchemaShard",
 				method: commandReloadSchemaShard,
-				params: "[--concurrency=10] [--include_primary=false] <keyspace/shard>",
-				help:   "Reloads the schema on all the tablets in a shard.",
+				params: "[--concurrency=10] [--include_primary=false] [--wait] [--wait-table=<table>] [--wait-timeout=30s] <keyspace/shard>",
+				help:   "Reloads the schema on all the tablets in a shard. With --wait, reads the schema of each tablet until the given table shows up, or without one until it matches the schema of the primary, and outputs the result of each tablet as JSON.",
 			},
 			{
 				name:   "ReloadSchemaKeyspace",
@@ -579,28 +759,42 @@ var commands = []commandGroup{
 				help:   "Reloads the schema on all the tablets in a keyspace.",
 			},
 			{
-				name:   "ValidateSchemaShard",
-				method: commandValidateSchemaShard,
-				params: "[--exclude_tables=''] [--include-views] [--include-vschema] <keyspace/shard>",
-				help:   "Validates that the schema on primary tablet matches all of the replica tablets.",
+				name:           "ValidateSchemaShard",
+				method:         commandValidateSchemaShard,
+				params:         "[--exclude_tables=''] [--include-views] [--include-vschema] [--include-non-serving] <keyspace/shard>",
+				help:           "Validates that the schema on primary tablet matches all of the replica tablets. The DRAINED, BACKUP and RESTORE tablets are skipped and listed unless --include-non-serving is set.",
+				cacheTopoReads: true,
+			},
+			{
+				name:           "ValidateSchemaKeyspace",
+				method:         commandValidateSchemaKeyspace,
+				params:         "[--exclude_tables=''] [--include-views] [--skip-no-primary] [--include-vschema] [--include-non-serving] [--include-non-serving-shards] [--reference-shard=<shard> | --reference-tablet=<tablet alias>] <keyspace name>",
+				help:           "Validates that the schema on all of the tablets in the keyspace matches the schema on the reference tablet. The reference is the requested tablet or shard primary, or else the primary of a shard with the schema most shard primaries agree on, in which case the shards whose primary has another schema are reported as outliers. The shards that serve no traffic, such as the overlapping shards of a reshard, are skipped and listed unless --include-non-serving-shards is set. The DRAINED, BACKUP and RESTORE tablets are skipped and listed unless --include-non-serving is set.",
+				cacheTopoReads: true,
 			},
 			{
-				name:   "ValidateSchemaKeyspace",
-				method: commandValidateSchemaKeyspace,
-				params: "[--exclude_tables=''] [--include-views] [--skip-no-primary] [--include-vschema] <keyspace name>",
-				help:   "Validates that the schema on the primary tablet for the first shard matches the schema on all of the other tablets in the keyspace.",
+				name:   "CompareSchemasAcrossKeyspaces",
+				method: commandCompareSchemasAcrossKeyspaces,
+				params: "[--tables <table1,table2,...>] [--workflow <workflow>] [--ignore-extra-target-columns] [--ignore-partitioning] [--format=text|json] <source keyspace> <target keyspace>",
+				help:   "Compares the definitions of tables on the primary of the first shard of the source keyspace with their definitions on the primaries of all the shards of the target keyspace, and reports the differences as unified diffs. If no tables are given, the tables of the workflow of the target keyspace are compared.",
 			},
 			{
 				name:   "ApplySchema",
 				method: commandApplySchema,
-				params: "[--wait_replicas_timeout=10s] [--ddl_strategy=<ddl_strategy>] [--uuid_list=<comma_separated_uuids>] [--migration_context=<unique-request-context>] {--sql=<sql> || --sql-file=<filename>} [--batch-size=<n>] <keyspace>",
-				help:   "Applies the schema change to the specified keyspace on every primary, running in parallel on all shards. The changes are then propagated to replicas via replication. -ddl_strategy is used to instruct migrations via vreplication, mysql or direct with optional parameters. -migration_context allows the user to specify a custom request context for online DDL migrations.",
+				params: "[--wait_replicas_timeout=10s] [--ddl_strategy=<ddl_strategy>] [--uuid_list=<comma_separated_uuids>] [--migration_context=<unique-request-context>] [--respect_throttler [--throttler_app_name=<app>] [--max_throttle_wait=10m]] {--sql=<sql> || --sql-file=<filename>} [--batch-size=<n>] <keyspace>",
+				help:   "Applies the schema change to the specified keyspace on every primary, running in parallel on all shards. The changes are then propagated to replicas via replication. -ddl_strategy is used to instruct migrations via vreplication, mysql or direct with optional parameters. -migration_context allows the user to specify a custom request context for online DDL migrations. With --respect_throttler, direct schema changes wait before each statement (or batch) while the throttler of the primary throttles --throttler_app_name, and fail once they have waited --max_throttle_wait in total.",
+			},
+			{
+				name:   "ApplySchemaStaged",
+				method: commandApplySchemaStaged,
+				params: "[--format=text|json] [--ddl_strategy=<ddl_strategy>] [--wait_replicas_timeout=10s] [--canary-shards=<shards> | --canary-percent=<percent>] [--batch-size=<n>] [--soak-time=5m] [--max-replication-lag=30s] [--auto-promote] {--sql=<sql> || --sql-file=<filename>} <keyspace> | --resume=<rollout id> [--force] | --abort=<rollout id> | --status=<rollout id>",
+				help:   "Applies the schema change to the specified keyspace in stages: to the canary shards first, then to the other shards in batches. After each stage, the health of its serving tablets is checked for the soak time: a tablet fails the check if it reports a health error or lags more than --max-replication-lag. Query error rates are not checked. The rollout pauses after the canary stage and each batch unless --auto-promote is set, and after a stage that failed. Its state is recorded in the global topo under the printed rollout ID, so it can be resumed, aborted or shown by a separate invocation. A rollout left running by an invocation that died can only be resumed with --force.",
 			},
 			{
 				name:   "CopySchemaShard",
 				method: commandCopySchemaShard,
-				params: "[--tables=<table1>,<table2>,...] [--exclude_tables=<table1>,<table2>,...] [--include-views] [--skip-verify] [--wait_replicas_timeout=10s] {<source keyspace/shard> || <source tablet alias>} <destination keyspace/shard>",
-				help:   "Copies the schema from a source shard's primary (or a specific tablet) to a destination shard. The schema is applied directly on the primary of the destination shard, and it is propagated to the replicas through binlogs.",
+				params: "[--tables=<table1>,<table2>,...] [--exclude_tables=<table1>,<table2>,...] [--include-views] [--skip-verify] [--wait_replicas_timeout=10s] [--respect_throttler [--throttler_app_name=<app>] [--max_throttle_wait=10m]] {<source keyspace/shard> || <source tablet alias>} <destination keyspace/shard>",
+				help:   "Copies the schema from a source shard's primary (or a specific tablet) to a destination shard. The schema is applied directly on the primary of the destination shard, and it is propagated to the replicas through binlogs. With --respect_throttler, each statement waits while the throttler of the destination primary throttles --throttler_app_name.",
 			},
 			{
 				name:   "OnlineDDL",
@@ -630,16 +824,18 @@ var commands = []commandGroup{
 					"",
 			},
 			{
-				name:   "ValidateVersionShard",
-				method: commandValidateVersionShard,
-				params: "<keyspace/shard>",
-				help:   "Validates that the version on primary matches all of the replicas.",
+				name:           "ValidateVersionShard",
+				method:         commandValidateVersionShard,
+				params:         "[--include-non-serving] <keyspace/shard>",
+				help:           "Validates that the version on primary matches all of the replicas. The DRAINED, BACKUP and RESTORE tablets are skipped and listed unless --include-non-serving is set.",
+				cacheTopoReads: true,
 			},
 			{
-				name:   "ValidateVersionKeyspace",
-				method: commandValidateVersionKeyspace,
-				params: "<keyspace name>",
-				help:   "Validates that the version on primary of shard 0 matches all of the other tablets in the keyspace.",
+				name:           "ValidateVersionKeyspace",
+				method:         commandValidateVersionKeyspace,
+				params:         "[--reference-shard=<shard>] [--include-non-serving] [--include-non-serving-shards] <keyspace name>",
+				help:           "Validates that the version on the primary of the reference shard matches all of the other tablets in the keyspace. The reference shard defaults to the first serving shard. The shards that serve no traffic, such as the overlapping shards of a reshard, are skipped and listed unless --include-non-serving-shards is set. The DRAINED, BACKUP and RESTORE tablets are skipped and listed unless --include-non-serving is set.",
+				cacheTopoReads: true,
 			},
 			{
 				name:   "GetPermissions",
@@ -648,16 +844,18 @@ var commands = []commandGroup{
 				help:   "Displays the permissions for a tablet.",
 			},
 			{
-				name:   "ValidatePermissionsShard",
-				method: commandValidatePermissionsShard,
-				params: "<keyspace/shard>",
-				help:   "Validates that the permissions on primary match all the replicas.",
+				name:           "ValidatePermissionsShard",
+				method:         commandValidatePermissionsShard,
+				params:         "[--include-non-serving] <keyspace/shard>",
+				help:           "Validates that the permissions on primary match all the replicas. The DRAINED, BACKUP and RESTORE tablets are skipped and listed unless --include-non-serving is set.",
+				cacheTopoReads: true,
 			},
 			{
-				name:   "ValidatePermissionsKeyspace",
-				method: commandValidatePermissionsKeyspace,
-				params: "<keyspace name>",
-				help:   "Validates that the permissions on primary of shard 0 match those of all of the other tablets in the keyspace.",
+				name:           "ValidatePermissionsKeyspace",
+				method:         commandValidatePermissionsKeyspace,
+				params:         "[--reference-shard=<shard>] [--require-all-shards] [--include-non-serving] [--include-non-serving-shards] <keyspace name>",
+				help:           "Validates that the permissions on the primary of the reference shard match those of all of the other tablets in the keyspace. The reference shard defaults to the first serving shard. The shards without a primary are skipped, and reported apart from the shards whose permissions differ; with --require-all-shards, they fail the validation. The shards that serve no traffic, such as the overlapping shards of a reshard, are skipped and listed unless --include-non-serving-shards is set. The DRAINED, BACKUP and RESTORE tablets are skipped and listed unless --include-non-serving is set.",
+				cacheTopoReads: true,
 			},
 			{
 				name:   "GetVSchema",
@@ -668,8 +866,8 @@ var commands = []commandGroup{
 			{
 				name:   "ApplyVSchema",
 				method: commandApplyVSchema,
-				params: "{--vschema=<vschema> || --vschema_file=<vschema file> || --sql=<sql> || --sql_file=<sql file>} [--cells=c1,c2,...] [--skip_rebuild] [--dry-run] <keyspace>",
-				help:   "Applies the VTGate routing schema to the provided keyspace. Shows the result after application.",
+				params: "{--vschema=<vschema> || --vschema_file=<vschema file> || --sql=<sql> || --sql_file=<sql file>} [--cells=c1,c2,...] [--skip_rebuild] [--dry-run] [--show_diff] [--retry_on_conflict] <keyspace>",
+				help:   "Applies the VTGate routing schema to the provided keyspace. Shows the result after application. With --dry-run or --show_diff, first shows what changes from the current vschema of the keyspace. The vschema is saved under the keyspace lock, and the command fails if another writer changes the vschema while it is being applied. With --retry_on_conflict, a --sql or --sql_file change is instead applied again to the vschema saved by the other writer.",
 			},
 			{
 				name:   "GetRoutingRules",
@@ -702,26 +900,32 @@ var commands = []commandGroup{
 			{
 				name:   "GetSrvKeyspace",
 				method: commandGetSrvKeyspace,
-				params: "<cell> <keyspace>",
-				help:   "Outputs a JSON structure that contains information about the SrvKeyspace.",
+				params: "[--visualize] <cell> <keyspace>",
+				help:   "Outputs a JSON structure that contains information about the SrvKeyspace. With --visualize, outputs instead the key range segments of each served tablet type and the shards serving them, flagging gaps and overlaps.",
 			},
 			{
 				name:   "UpdateThrottlerConfig",
 				method: commandUpdateThrottlerConfig,
-				params: "[--enable|--disable] [--threshold=<float64>] [--custom-query=<query>] [--throttle-app|unthrottle-app=<name>] [--throttle-app-ratio=<float, range [0..1]>] [--throttle-app-duration=<duration>] [--throttle-app-exempt] <keyspace>",
+				params: "[--enable|--disable] [--threshold=<float64>] [--custom-query=<query>] [--throttle-app|unthrottle-app=<name>] [--throttle-app-ratio=<float, range [0..1]>] [--throttle-app-duration=<duration>] [--throttle-app-exempt] [--wait-for-acknowledgement=<duration>] <keyspace>",
 				help:   "Update the table throttler configuration for all cells and tablets of a given keyspace",
 			},
+			{
+				name:   "GetThrottlerConfig",
+				method: commandGetThrottlerConfig,
+				params: "<keyspace>",
+				help:   "Outputs a JSON structure that contains the throttler configuration of a keyspace, and whether the throttlers of its tablets have applied it.",
+			},
 			{
 				name:   "GetSrvVSchema",
 				method: commandGetSrvVSchema,
-				params: "<cell>",
-				help:   "Outputs a JSON structure that contains information about the SrvVSchema.",
+				params: "[--diff-against-cell <cell>] <cell>",
+				help:   "Outputs a JSON structure that contains information about the SrvVSchema, or the differences with the SrvVSchema of another cell.",
 			},
 			{
 				name:   "DeleteSrvVSchema",
 				method: commandDeleteSrvVSchema,
-				params: "<cell>",
-				help:   "Deletes the SrvVSchema object in the given cell.",
+				params: "[--force] <cell>",
+				help:   "Deletes the SrvVSchema object in the given cell. Without --force, only reports the keyspaces it covers and the keyspaces served in the cell.",
 			},
 		},
 	},
@@ -740,8 +944,8 @@ var commands = []commandGroup{
 			{
 				name:   "Workflow",
 				method: commandWorkflow,
-				params: "[--dry-run] [--cells] [--tablet-types] <keyspace>[.<workflow>] start/stop/update/delete/show/listall/tags [<tags>]",
-				help:   "Start/Stop/Update/Delete/Show/ListAll/Tags Workflow on all target tablets in workflow. Example: Workflow merchant.morders Start",
+				params: "[--dry-run] [--cells] [--tablet-types] [--include-logs] <keyspace>[.<workflow>] start/stop/update/delete/show/clearlogs/listall/tags [<tags>]",
+				help:   "Start/Stop/Update/Delete/Show/ClearLogs/ListAll/Tags Workflow on all target tablets in workflow. Show --include-logs adds the retained log history of each stream, and ClearLogs deletes it. Example: Workflow merchant.morders Start",
 			},
 		},
 	},
@@ -779,34 +983,6 @@ func addCommandGroup(groupName string) {
 	})
 }
 
-func fmtMapAwkable(m map[string]string) string {
-	pairs := make([]string, len(m))
-	i := 0
-	for k, v := range m {
-		pairs[i] = fmt.Sprintf("%v: %q", k, v)
-		i++
-	}
-	sort.Strings(pairs)
-	return "[" + strings.Join(pairs, " ") + "]"
-}
-
-func fmtTabletAwkable(ti *topo.TabletInfo) string {
-	keyspace := ti.Keyspace
-	shard := ti.Shard
-	if keyspace == "" {
-		keyspace = "<null>"
-	}
-	if shard == "" {
-		shard = "<null>"
-	}
-	mtst := "<null>"
-	// special case for old primary that hasn't updated topo yet
-	if ti.PrimaryTermStartTime != nil && ti.PrimaryTermStartTime.Seconds > 0 {
-		mtst = protoutil.TimeFromProto(ti.PrimaryTermStartTime).UTC().Format(time.RFC3339)
-	}
-	return fmt.Sprintf("%v %v %v %v %v %v %v %v", topoproto.TabletAliasString(ti.Alias), keyspace, shard, topoproto.TabletTypeLString(ti.Type), ti.Addr(), ti.MysqlAddr(), fmtMapAwkable(ti.Tags), mtst)
-}
-
 // getFileParam returns a string containing either flag is not "",
 // or the content of the file named flagFile
 func getFileParam(flag, flagFile, name string) (string, error) {
@@ -884,13 +1060,33 @@ func shardParamsToKeyspaceShards(ctx context.Context, wr *wrangler.Wrangler, par
 	return result, nil
 }
 
-// tabletParamsToTabletAliases takes multiple params and converts them
-// to tablet aliases.
-func tabletParamsToTabletAliases(params []string) ([]*topodatapb.TabletAlias, error) {
+// tabletResolver converts the tablet params of a command to tablet aliases.
+// A tablet param is either a tablet alias, or the "hostname" or
+// "hostname:port" of a tablet.
+type tabletResolver struct {
+	wr    *wrangler.Wrangler
+	cells []string
+}
+
+// newTabletResolver returns a tabletResolver for the command, and adds the
+// --cell flag restricting the resolution of hostnames to its flags.
+func newTabletResolver(wr *wrangler.Wrangler, subFlags *pflag.FlagSet) *tabletResolver {
+	r := &tabletResolver{wr: wr}
+	subFlags.StringSliceVar(&r.cells, "cell", nil, "Only resolve tablet hostnames to the tablets of these cells. By default, tablets in all cells are considered.")
+	return r
+}
+
+// resolve converts a single tablet param to a tablet alias.
+func (r *tabletResolver) resolve(ctx context.Context, param string) (*topodatapb.TabletAlias, error) {
+	return r.wr.ResolveTabletAlias(ctx, param, r.cells)
+}
+
+// resolveAll converts multiple tablet params to tablet aliases.
+func (r *tabletResolver) resolveAll(ctx context.Context, params []string) ([]*topodatapb.TabletAlias, error) {
 	result := make([]*topodatapb.TabletAlias, len(params))
 	var err error
 	for i, param := range params {
-		result[i], err = topoproto.ParseTabletAlias(param)
+		result[i], err = r.resolve(ctx, param)
 		if err != nil {
 			return nil, err
 		}
@@ -968,6 +1164,7 @@ func commandInitTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags *pfl
 }
 
 func commandGetTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -975,7 +1172,7 @@ func commandGetTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags *pfla
 		return fmt.Errorf("the <tablet alias> argument is required for the GetTablet command")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -987,12 +1184,162 @@ func commandGetTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags *pfla
 	return printJSON(wr.Logger(), tabletInfo.Tablet)
 }
 
+func commandGetFullStatus(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	format := subFlags.String("format", "text", "Format of the report") // "json" or "text"
+	tablets := newTabletResolver(wr, subFlags)
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <tablet alias> or <keyspace/shard> argument is required for the GetFullStatus command")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid --format %q, must be one of text, json", *format)
+	}
+
+	if !strings.Contains(subFlags.Arg(0), "/") {
+		tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
+		if err != nil {
+			return err
+		}
+		status, err := wr.GetFullStatus(ctx, tabletAlias)
+		if err != nil {
+			return err
+		}
+		if *format == "json" {
+			return printJSON(wr.Logger(), status)
+		}
+		var b strings.Builder
+		writeFullStatus(&b, status, "")
+		wr.Logger().Printf("%s", b.String())
+		return nil
+	}
+
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	report, err := wr.GetShardFullStatus(ctx, keyspace, shard)
+	if err != nil {
+		return err
+	}
+	if *format == "json" {
+		return printJSON(wr.Logger(), report)
+	}
+	var b strings.Builder
+	for _, tablet := range report.Tablets {
+		role := ""
+		if tablet.TabletAlias == report.PrimaryAlias {
+			role = ", primary"
+		}
+		fmt.Fprintf(&b, "%v (%v%v)\n", tablet.TabletAlias, tablet.Type, role)
+		if tablet.Error != "" {
+			fmt.Fprintf(&b, "  unreachable: %v\n\n", tablet.Error)
+			continue
+		}
+		for _, diff := range tablet.Diffs {
+			fmt.Fprintf(&b, "  DIFFERS FROM PRIMARY: %v\n", diff)
+		}
+		writeFullStatus(&b, tablet.Status, "  ")
+		b.WriteString("\n")
+	}
+	wr.Logger().Printf("%s", b.String())
+	return nil
+}
+
+// writeFullStatus writes the full status of a tablet as text, grouped by
+// section, each line prefixed with indent.
+func writeFullStatus(b *strings.Builder, status *replicationdatapb.FullStatus, indent string) {
+	section := func(name string) {
+		fmt.Fprintf(b, "%v%v:\n", indent, name)
+	}
+	field := func(name string, value any) {
+		fmt.Fprintf(b, "%v  %v: %v\n", indent, name, value)
+	}
+	replicationState := func(state int32) string {
+		switch replication.ReplicationState(state) {
+		case replication.ReplicationStateStopped:
+			return "stopped"
+		case replication.ReplicationStateConnecting:
+			return "connecting"
+		case replication.ReplicationStateRunning:
+			return "running"
+		default:
+			return "unknown"
+		}
+	}
+
+	section("Server")
+	field("server_id", status.ServerId)
+	field("server_uuid", status.ServerUuid)
+	field("version", status.Version)
+	field("version_comment", status.VersionComment)
+	field("disk_stalled", status.DiskStalled)
+
+	section("Read-only")
+	field("read_only", status.ReadOnly)
+	field("super_read_only", status.SuperReadOnly)
+
+	section("Binary log and GTIDs")
+	field("log_bin_enabled", status.LogBinEnabled)
+	field("binlog_format", status.BinlogFormat)
+	field("binlog_row_image", status.BinlogRowImage)
+	field("log_replica_updates", status.LogReplicaUpdates)
+	field("gtid_mode", status.GtidMode)
+	field("gtid_purged", status.GtidPurged)
+
+	if ps := status.PrimaryStatus; ps != nil {
+		section("Primary status")
+		field("position", ps.Position)
+		field("file_position", ps.FilePosition)
+	}
+
+	if rs := status.ReplicationStatus; rs != nil {
+		section("Replication status")
+		field("source", fmt.Sprintf("%v:%v", rs.SourceHost, rs.SourcePort))
+		field("source_uuid", rs.SourceUuid)
+		field("io_thread", replicationState(rs.IoState))
+		field("sql_thread", replicationState(rs.SqlState))
+		field("position", rs.Position)
+		field("relay_log_position", rs.RelayLogPosition)
+		if rs.ReplicationLagUnknown {
+			field("replication_lag", "unknown")
+		} else {
+			field("replication_lag", fmt.Sprintf("%ds", rs.ReplicationLagSeconds))
+		}
+		field("sql_delay", fmt.Sprintf("%ds", rs.SqlDelay))
+		if rs.LastIoError != "" {
+			field("last_io_error", rs.LastIoError)
+		}
+		if rs.LastSqlError != "" {
+			field("last_sql_error", rs.LastSqlError)
+		}
+	}
+
+	if rc := status.ReplicationConfiguration; rc != nil {
+		section("Replication configuration")
+		field("heartbeat_interval", fmt.Sprintf("%vs", rc.HeartbeatInterval))
+		field("replica_net_timeout", fmt.Sprintf("%ds", rc.ReplicaNetTimeout))
+	}
+
+	section("Semi-sync")
+	field("primary_enabled", status.SemiSyncPrimaryEnabled)
+	field("primary_status", status.SemiSyncPrimaryStatus)
+	field("primary_clients", status.SemiSyncPrimaryClients)
+	field("primary_timeout", fmt.Sprintf("%dms", status.SemiSyncPrimaryTimeout))
+	field("wait_for_replica_count", status.SemiSyncWaitForReplicaCount)
+	field("blocked", status.SemiSyncBlocked)
+	field("replica_enabled", status.SemiSyncReplicaEnabled)
+	field("replica_status", status.SemiSyncReplicaStatus)
+}
+
 func commandUpdateTabletAddrs(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	hostname := subFlags.String("hostname", "", "The fully qualified host name of the server on which the tablet is running.")
 	mysqlHost := subFlags.String("mysql_host", "", "The mysql host for the mysql server")
 	mysqlPort := subFlags.Int("mysql-port", 0, "The mysql port for the mysql daemon")
 	vtPort := subFlags.Int("vt-port", 0, "The main port for the vttablet process")
 	grpcPort := subFlags.Int("grpc-port", 0, "The gRPC port for the vttablet process")
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1001,7 +1348,7 @@ func commandUpdateTabletAddrs(ctx context.Context, wr *wrangler.Wrangler, subFla
 		return fmt.Errorf("the <tablet alias> argument is required for the UpdateTabletAddrs command")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1034,6 +1381,10 @@ func commandUpdateTabletAddrs(ctx context.Context, wr *wrangler.Wrangler, subFla
 
 func commandDeleteTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	allowPrimary := subFlags.Bool("allow_primary", false, "Allows for the primary tablet of a shard to be deleted. Use with caution.")
+	forceCorrupt := subFlags.Bool("force-corrupt", false, "If a tablet record cannot be unmarshaled, delete it anyway and remove its alias from the replication graph of --keyspace/--shard in every cell.")
+	keyspace := subFlags.String("keyspace", "", "With --force-corrupt, the keyspace the corrupt tablet belonged to.")
+	shard := subFlags.String("shard", "", "With --force-corrupt, the shard the corrupt tablet belonged to.")
+	tablets := newTabletResolver(wr, subFlags)
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -1042,11 +1393,19 @@ func commandDeleteTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 		return fmt.Errorf("the <tablet alias> argument must be used to specify at least one tablet when calling the DeleteTablet command")
 	}
 
-	tabletAliases, err := tabletParamsToTabletAliases(subFlags.Args())
+	tabletAliases, err := tablets.resolveAll(ctx, subFlags.Args())
 	if err != nil {
 		return err
 	}
 	for _, tabletAlias := range tabletAliases {
+		if *forceCorrupt {
+			if _, err := wr.TopoServer().GetTablet(ctx, tabletAlias); err != nil && !topo.IsErrType(err, topo.NoNode) {
+				if err := wr.DeleteCorruptTablet(ctx, tabletAlias, *keyspace, *shard); err != nil {
+					return err
+				}
+				continue
+			}
+		}
 		if err := wr.DeleteTablet(ctx, tabletAlias, *allowPrimary); err != nil {
 			return err
 		}
@@ -1054,7 +1413,72 @@ func commandDeleteTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 	return nil
 }
 
+func commandDecommissionTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	drainTimeout := subFlags.Duration("drain-timeout", time.Minute, "How long to wait for the qps of the drained tablet to drop to --max-qps")
+	maxQPS := subFlags.Float64("max-qps", 1, "The qps at or below which the tablet counts as drained")
+	force := subFlags.Bool("force", false, "Decommission the tablet even if it is the only serving tablet of its type in its shard")
+	tablets := newTabletResolver(wr, subFlags)
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <tablet alias> argument is required for the DecommissionTablet command")
+	}
+
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	return wr.DecommissionTablet(ctx, tabletAlias, wrangler.DecommissionOptions{
+		DrainTimeout: *drainTimeout,
+		MaxQPS:       *maxQPS,
+		Force:        *force,
+	})
+}
+
+func commandAuditTablets(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	cells := subFlags.StringSlice("cells", nil, "Comma-separated list of cells to audit, all cells if empty")
+	healthWindow := subFlags.Duration("health-window", 30*time.Second, "How long to wait for a health record from each tablet")
+	olderThan := subFlags.Duration("older-than", 0, "Report tablets that last started more than this long ago")
+	concurrency := subFlags.Int("concurrency", 8, "How many tablets to audit in parallel")
+	deleteStale := subFlags.Bool("delete-stale", false, "Delete the tablets that match all the criteria, requires --older-than")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 0 {
+		return fmt.Errorf("the AuditTablets command takes no arguments")
+	}
+	if *deleteStale && *olderThan <= 0 {
+		return fmt.Errorf("--delete-stale requires --older-than")
+	}
+	if *deleteStale {
+		if err := wr.CheckWritable("AuditTablets --delete-stale"); err != nil {
+			return err
+		}
+	}
+
+	audits, err := wr.AuditTablets(ctx, *cells, *healthWindow, *olderThan, *concurrency)
+	if err != nil {
+		return err
+	}
+	if err := printJSON(wr.Logger(), audits); err != nil {
+		return err
+	}
+	if !*deleteStale {
+		return nil
+	}
+
+	err = wr.DeleteStaleTablets(ctx, audits)
+	for _, audit := range audits {
+		if audit.Deleted {
+			wr.Logger().Printf("Deleted stale tablet %v\n", audit.TabletAlias)
+		}
+	}
+	return err
+}
+
 func commandSetReadOnly(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1062,7 +1486,7 @@ func commandSetReadOnly(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 		return fmt.Errorf("the <tablet alias> argument is required for the SetReadOnly command")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1074,6 +1498,7 @@ func commandSetReadOnly(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 }
 
 func commandSetReadWrite(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1081,7 +1506,7 @@ func commandSetReadWrite(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 		return fmt.Errorf("the <tablet alias> argument is required for the SetReadWrite command")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1093,6 +1518,7 @@ func commandSetReadWrite(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 }
 
 func commandStartReplication(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1100,7 +1526,7 @@ func commandStartReplication(ctx context.Context, wr *wrangler.Wrangler, subFlag
 		return fmt.Errorf("action StartReplication requires <tablet alias>")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1111,7 +1537,29 @@ func commandStartReplication(ctx context.Context, wr *wrangler.Wrangler, subFlag
 	return err
 }
 
+func commandStartReplicationUntilAfter(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	timeout := subFlags.Duration("timeout", time.Minute, "How long to wait for the tablet to reach the position")
+	tablets := newTabletResolver(wr, subFlags)
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 2 {
+		return fmt.Errorf("the <tablet alias> and <position> arguments are required for the StartReplicationUntilAfter command")
+	}
+
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	position, err := wr.StartReplicationUntilAfter(ctx, tabletAlias, subFlags.Arg(1), *timeout)
+	if position != "" {
+		wr.Logger().Printf("%v\n", position)
+	}
+	return err
+}
+
 func commandStopReplication(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1119,7 +1567,7 @@ func commandStopReplication(ctx context.Context, wr *wrangler.Wrangler, subFlags
 		return fmt.Errorf("action StopReplication requires <tablet alias>")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1131,7 +1579,9 @@ func commandStopReplication(ctx context.Context, wr *wrangler.Wrangler, subFlags
 }
 
 func commandChangeTabletType(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
-	dryRun := subFlags.Bool("dry-run", false, "Lists the proposed change without actually executing it")
+	dryRun := subFlags.Bool("dry-run", false, "Reports the impact of the proposed change without actually executing it")
+	minRemaining := subFlags.Int("min-remaining", 0, "Refuses the change if fewer than this many serving tablets of the current type would remain in the cell and shard")
+	tablets := newTabletResolver(wr, subFlags)
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -1140,7 +1590,7 @@ func commandChangeTabletType(ctx context.Context, wr *wrangler.Wrangler, subFlag
 		return fmt.Errorf("the <tablet alias> and <db type> arguments are required for the ChangeTabletType command")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1153,46 +1603,98 @@ func commandChangeTabletType(ctx context.Context, wr *wrangler.Wrangler, subFlag
 	defer cancel()
 
 	if *dryRun {
-		ti, err := wr.TopoServer().GetTablet(ctx, tabletAlias)
+		impact, err := wr.ChangeTabletTypeImpact(ctx, tabletAlias, newType, *minRemaining)
 		if err != nil {
-			return fmt.Errorf("failed reading tablet %v: %v", tabletAlias, err)
+			return fmt.Errorf("failed computing impact of type change for tablet %v: %v", topoproto.TabletAliasString(tabletAlias), err)
+		}
+		if err := printJSON(wr.Logger(), impact); err != nil {
+			return err
 		}
-		if !topo.IsTrivialTypeChange(ti.Type, newType) {
-			return fmt.Errorf("invalid type transition %v: %v -> %v", tabletAlias, ti.Type, newType)
+		if !impact.Allowed {
+			return fmt.Errorf("cannot change type of tablet %v to %v: %v", topoproto.TabletAliasString(tabletAlias), newType, impact.Reason)
 		}
-		wr.Logger().Printf("- %v\n", fmtTabletAwkable(ti))
-		ti.Type = newType
-		wr.Logger().Printf("+ %v\n", fmtTabletAwkable(ti))
 		return nil
 	}
+	if *minRemaining > 0 {
+		return wr.ChangeTabletTypeWithMinRemaining(ctx, tabletAlias, newType, *minRemaining)
+	}
 	return wr.ChangeTabletType(ctx, tabletAlias, newType)
 }
 
+func commandAddTabletTag(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tablets := newTabletResolver(wr, subFlags)
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() < 2 {
+		return fmt.Errorf("the <tablet alias> and at least one <key:value> argument are required for the AddTabletTag command")
+	}
+
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	tags := make(map[string]string, subFlags.NArg()-1)
+	for _, arg := range subFlags.Args()[1:] {
+		key, val, ok := strings.Cut(arg, ":")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid tag %q, expected <key:value>", arg)
+		}
+		tags[key] = val
+	}
+
+	result, err := wr.UpdateTabletTags(ctx, tabletAlias, tags, nil)
+	if err != nil {
+		return err
+	}
+	return printJSON(wr.Logger(), result)
+}
+
+func commandRemoveTabletTag(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tablets := newTabletResolver(wr, subFlags)
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() < 2 {
+		return fmt.Errorf("the <tablet alias> and at least one <key> argument are required for the RemoveTabletTag command")
+	}
+
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	result, err := wr.UpdateTabletTags(ctx, tabletAlias, nil, subFlags.Args()[1:])
+	if err != nil {
+		return err
+	}
+	return printJSON(wr.Logger(), result)
+}
+
 func commandPing(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <tablet alias> argument is required for the Ping command")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
-	_, err = wr.VtctldServer().PingTablet(ctx, &vtctldatapb.PingTabletRequest{
-		TabletAlias: tabletAlias,
-	})
-	return err
+	return wr.PingTablet(ctx, tabletAlias)
 }
 
 func commandRefreshState(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <tablet alias> argument is required for the RefreshState command")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1231,13 +1733,14 @@ func commandRefreshStateByShard(ctx context.Context, wr *wrangler.Wrangler, subF
 }
 
 func commandRunHealthCheck(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <tablet alias> argument is required for the RunHealthCheck command")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1249,13 +1752,14 @@ func commandRunHealthCheck(ctx context.Context, wr *wrangler.Wrangler, subFlags
 }
 
 func commandSleep(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
 	if subFlags.NArg() != 2 {
 		return fmt.Errorf("the <tablet alias> and <duration> arguments are required for the Sleep command")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1274,46 +1778,64 @@ func commandSleep(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.Fl
 func commandExecuteFetchAsApp(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	maxRows := subFlags.Int("max_rows", 10000, "Specifies the maximum number of rows to allow in fetch")
 	usePool := subFlags.Bool("use_pool", false, "Use connection from pool")
-	json := subFlags.Bool("json", false, "Output JSON instead of human-readable table")
+	callerPrincipal := subFlags.String("caller-id", "", "Principal the table ACLs are checked for before running the command")
+	callerGroups := subFlags.StringSlice("caller-groups", nil, "Groups of the --caller-id principal")
+	format := addQueryResultFormatFlags(subFlags)
+	tablets := newTabletResolver(wr, subFlags)
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
+	if err := format.validate(); err != nil {
+		return err
+	}
 	if subFlags.NArg() != 2 {
 		return fmt.Errorf("the <tablet alias> and <sql command> arguments are required for the ExecuteFetchAsApp command")
 	}
+	if *callerPrincipal == "" && len(*callerGroups) > 0 {
+		return fmt.Errorf("--caller-groups requires --caller-id")
+	}
+	var callerID *vtrpcpb.CallerID
+	if *callerPrincipal != "" {
+		callerID = &vtrpcpb.CallerID{Principal: *callerPrincipal, Groups: *callerGroups}
+	}
 
-	alias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	alias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
 	query := subFlags.Arg(1)
-	qrproto, err := wr.ExecuteFetchAsApp(ctx, alias, *usePool, query, *maxRows)
-	if err != nil {
+	qrproto, err := wr.ExecuteFetchAsApp(ctx, alias, *usePool, query, *maxRows, callerID)
+	switch vterrors.Code(err) {
+	case vtrpcpb.Code_OK:
+	case vtrpcpb.Code_PERMISSION_DENIED:
+		return fmt.Errorf("tablet %v rejected the command of %v because of its table ACLs: %w", topoproto.TabletAliasString(alias), *callerPrincipal, err)
+	case vtrpcpb.Code_UNAVAILABLE, vtrpcpb.Code_DEADLINE_EXCEEDED:
+		return fmt.Errorf("cannot reach tablet %v: %w", topoproto.TabletAliasString(alias), err)
+	default:
 		return err
 	}
-	qr := sqltypes.Proto3ToResult(qrproto)
-	if *json {
-		return printJSON(wr.Logger(), qr)
-	}
-	printQueryResult(loggerWriter{wr.Logger()}, qr)
-	return nil
+	return format.print(wr.Logger(), sqltypes.Proto3ToResult(qrproto))
 }
 
 func commandExecuteFetchAsDba(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	maxRows := subFlags.Int("max_rows", 10000, "Specifies the maximum number of rows to allow in fetch")
 	disableBinlogs := subFlags.Bool("disable_binlogs", false, "Disables writing to binlogs during the query")
 	reloadSchema := subFlags.Bool("reload_schema", false, "Indicates whether the tablet schema will be reloaded after executing the SQL command. The default value is <code>false</code>, which indicates that the tablet schema will not be reloaded.")
-	json := subFlags.Bool("json", false, "Output JSON instead of human-readable table")
+	format := addQueryResultFormatFlags(subFlags)
+	tablets := newTabletResolver(wr, subFlags)
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
+	if err := format.validate(); err != nil {
+		return err
+	}
 	if subFlags.NArg() != 2 {
 		return fmt.Errorf("the <tablet alias> and <sql command> arguments are required for the ExecuteFetchAsDba command")
 	}
 
-	alias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	alias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1322,18 +1844,12 @@ func commandExecuteFetchAsDba(ctx context.Context, wr *wrangler.Wrangler, subFla
 	if err != nil {
 		return err
 	}
-	qr := sqltypes.Proto3ToResult(qrproto)
-	if *json {
-		return printJSON(wr.Logger(), qr)
-	}
-	printQueryResult(loggerWriter{wr.Logger()}, qr)
-	return nil
+	return format.print(wr.Logger(), sqltypes.Proto3ToResult(qrproto))
 }
 
 func commandVReplicationExec(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
-	wr.Logger().Printf("\nWARNING: VReplicationExec is deprecated and will be removed in a future release. Please use 'Workflow -- <keyspace.workflow> <action>' instead.\n\n")
-
 	json := subFlags.Bool("json", false, "Output JSON instead of human-readable table")
+	tablets := newTabletResolver(wr, subFlags)
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -1342,7 +1858,7 @@ func commandVReplicationExec(ctx context.Context, wr *wrangler.Wrangler, subFlag
 		return fmt.Errorf("the <tablet alias> and <sql command> arguments are required for the VReplicationExec command")
 	}
 
-	alias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	alias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1360,7 +1876,12 @@ func commandVReplicationExec(ctx context.Context, wr *wrangler.Wrangler, subFlag
 }
 
 func commandExecuteHook(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
-	subFlags.SetInterspersed(false) // all flags should be treated as posargs to pass them to the actual hook
+	env := subFlags.StringArray("env", nil, "Sets KEY=VALUE in the hook's environment; may be repeated")
+	timeout := subFlags.Duration("timeout", 0, "Kills the hook if it runs longer than this; 0 means no timeout")
+	format := subFlags.String("format", "text", "Format of the result") // "json" or "text"
+
+	subFlags.SetInterspersed(false) // all flags after the tablet alias should be treated as posargs to pass them to the actual hook
+	tablets := newTabletResolver(wr, subFlags)
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -1368,20 +1889,43 @@ func commandExecuteHook(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 	if subFlags.NArg() < 2 {
 		return fmt.Errorf("the <tablet alias> and <hook name> arguments are required for the ExecuteHook command")
 	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid --format %q, must be one of text, json", *format)
+	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
+	hookName := subFlags.Arg(1)
 
-	resp, err := wr.VtctldServer().ExecuteHook(ctx, &vtctldatapb.ExecuteHookRequest{
-		TabletAlias: tabletAlias,
+	extraEnv := make(map[string]string, len(*env))
+	for _, kv := range *env {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid --env %q, must be KEY=VALUE", kv)
+		}
+		extraEnv[key] = value
+	}
+
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	resp, err := wr.VtctldServer().ExecuteHook(ctx, &vtctldatapb.ExecuteHookRequest{
+		TabletAlias: tabletAlias,
 		TabletHookRequest: &tabletmanagerdatapb.ExecuteHookRequest{
-			Name:       subFlags.Arg(1),
+			Name:       hookName,
 			Parameters: subFlags.Args()[2:],
+			ExtraEnv:   extraEnv,
 		},
 	})
 	if err != nil {
+		if *timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("hook %v on tablet %v did not finish within %v: %v", hookName, topoproto.TabletAliasString(tabletAlias), *timeout, err)
+		}
 		return err
 	}
 
@@ -1390,7 +1934,17 @@ func commandExecuteHook(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 		Stdout:     resp.HookResult.Stdout,
 		Stderr:     resp.HookResult.Stderr,
 	}
-	return printJSON(wr.Logger(), hr)
+	if *format == "json" {
+		if err := printJSON(wr.Logger(), hr); err != nil {
+			return err
+		}
+	} else {
+		wr.Logger().Printf("exit status: %d\nstdout:\n%s\nstderr:\n%s\n", hr.ExitStatus, hr.Stdout, hr.Stderr)
+	}
+	if hr.ExitStatus != hk.HOOK_SUCCESS {
+		return fmt.Errorf("hook %v on tablet %v exited with status %d", hookName, topoproto.TabletAliasString(tabletAlias), hr.ExitStatus)
+	}
+	return nil
 }
 
 func commandCreateShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
@@ -1403,92 +1957,631 @@ func commandCreateShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 		return fmt.Errorf("the <keyspace/shard> argument is required for the CreateShard command")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
-	if err != nil {
-		return err
-	}
-	if *parent {
-		if err := wr.TopoServer().CreateKeyspace(ctx, keyspace, &topodatapb.Keyspace{}); err != nil && !topo.IsErrType(err, topo.NodeExists) {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *parent {
+		if err := wr.TopoServer().CreateKeyspace(ctx, keyspace, &topodatapb.Keyspace{}); err != nil && !topo.IsErrType(err, topo.NodeExists) {
+			return err
+		}
+	}
+
+	err = wr.TopoServer().CreateShard(ctx, keyspace, shard)
+	if *force && topo.IsErrType(err, topo.NodeExists) {
+		wr.Logger().Infof("shard %v/%v already exists (ignoring error with --force)", keyspace, shard)
+		err = nil
+	}
+	return err
+}
+
+func commandGetShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace/shard> argument is required for the GetShard command")
+	}
+
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	shardInfo, err := wr.TopoServer().GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return err
+	}
+	// Pass the embedded proto directly or jsonpb will panic.
+	return printJSON(wr.Logger(), shardInfo.Shard)
+}
+
+func commandValidateShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	pingTablets := subFlags.Bool("ping-tablets", true, "Indicates whether all tablets should be pinged during the validation process")
+	pingTimeout := subFlags.Duration("ping-timeout", topo.RemoteOperationTimeout, "The timeout of each tablet ping")
+	includeServingState := subFlags.Bool("include-serving-state", false, "Compares the tablet type and serving state each tablet broadcasts in its health stream with its topo record")
+	servingStateWait := subFlags.Duration("serving-state-wait", 5*time.Second, "How long to wait for the health data of each tablet with --include-serving-state")
+	asJSON := subFlags.Bool("json", false, validationJSONUsage)
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace/shard> argument is required for the ValidateShard command")
+	}
+	if *servingStateWait <= 0 {
+		return fmt.Errorf("--serving-state-wait must be positive")
+	}
+
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	return runValidation(wr, *asJSON, func(report *wrangler.ValidationReport) error {
+		wait := time.Duration(0)
+		if *includeServingState {
+			wait = *servingStateWait
+		}
+		return wr.ValidateShard(ctx, keyspace, shard, *pingTablets, *pingTimeout, wait, report)
+	})
+}
+
+func commandShardReplicationPositions(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace/shard> argument is required for the ShardReplicationPositions command")
+	}
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	resp, err := wr.VtctldServer().ShardReplicationPositions(ctx, &vtctldatapb.ShardReplicationPositionsRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+	})
+	if err != nil {
+		return err
+	}
+
+	lines := make([]string, 0, 24)
+	for _, rt := range cli.SortedReplicatingTablets(resp.TabletMap, resp.ReplicationStatuses) {
+		status := rt.Status
+		tablet := rt.Tablet
+		if status == nil {
+			lines = append(lines, cli.MarshalTabletAWK(tablet)+" <err> <err> <err>")
+		} else {
+			lines = append(lines, cli.MarshalTabletAWK(tablet)+fmt.Sprintf(" %v %v", status.Position, status.ReplicationLagSeconds))
+		}
+	}
+	for _, l := range lines {
+		wr.Logger().Printf("%v\n", l)
+	}
+	return nil
+}
+
+func commandDiffShardVariables(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	variables := subFlags.StringSlice("variables", nil, "Comma-separated list of the global variables to compare, instead of all of them")
+	includeAll := subFlags.Bool("include-all", false, "Also compare the variables that always differ between servers")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace/shard> argument is required for the DiffShardVariables command")
+	}
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	diff, err := wr.DiffShardVariables(ctx, keyspace, shard, *variables, *includeAll)
+	if err != nil {
+		return err
+	}
+	if err := printJSON(wr.Logger(), diff); err != nil {
+		return err
+	}
+	if diff.HasDiffs() {
+		return fmt.Errorf("global variables of some tablets in %v/%v differ from primary %v", keyspace, shard, diff.Reference)
+	}
+	return nil
+}
+
+func commandShardReplicaMembership(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	format := subFlags.String("format", "text", "Format of the report") // "json" or "text"
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace/shard> argument is required for the ShardReplicaMembership command")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid --format %q, must be one of text, json", *format)
+	}
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	report, err := wr.ShardReplicaMembership(ctx, keyspace, shard)
+	if err != nil {
+		return err
+	}
+	if *format == "json" {
+		if err := printJSON(wr.Logger(), report); err != nil {
+			return err
+		}
+	} else {
+		var b strings.Builder
+		fmt.Fprintf(&b, "primary: %v\n", report.Primary)
+		fmt.Fprintf(&b, "attached replicas: %v\n", strings.Join(report.Attached, " "))
+		for _, m := range report.AttachedNotInGraph {
+			fmt.Fprintf(&b, "attached but not in the replication graph: %v (%v) from %v\n", m.TabletAlias, m.Type, m.Address)
+		}
+		for _, m := range report.InGraphNotAttached {
+			fmt.Fprintf(&b, "in the replication graph but not attached: %v (%v)\n", m.TabletAlias, m.Type)
+		}
+		for _, addr := range report.Unmapped {
+			fmt.Fprintf(&b, "attached from an address of no known tablet: %v\n", addr)
+		}
+		for _, addr := range report.Ambiguous {
+			fmt.Fprintf(&b, "address shared by several tablets: %v\n", addr)
+		}
+		wr.Logger().Printf("%s", b.String())
+	}
+	if report.HasDiffs() {
+		return fmt.Errorf("replicas attached to primary %v don't match the replication graph of %v/%v", report.Primary, keyspace, shard)
+	}
+	return nil
+}
+
+func commandShardReadiness(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	minReplicas := subFlags.Int("min-replicas", 1, "Minimum number of serving replica tablets in each cell that has replica tablets of the shard. Zero disables the check.")
+	maxLag := subFlags.Duration("max-lag", 30*time.Second, "Maximum replication lag of the replica and rdonly tablets. Zero disables the check.")
+	format := subFlags.String("format", "text", "Format of the report") // "json" or "text"
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace/shard> argument is required for the ShardReadiness command")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid --format %q, must be one of text, json", *format)
+	}
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	report, err := wr.ShardReadiness(ctx, keyspace, shard, wrangler.ShardReadinessThresholds{
+		MinReplicas: *minReplicas,
+		MaxLag:      *maxLag,
+	})
+	if err != nil {
+		return err
+	}
+	if *format == "json" {
+		if err := printJSON(wr.Logger(), report); err != nil {
+			return err
+		}
+	} else {
+		var b strings.Builder
+		verdict := "READY"
+		if !report.Ready {
+			verdict = "NOT READY"
+		}
+		fmt.Fprintf(&b, "shard %v/%v: %v\n", keyspace, shard, verdict)
+		fmt.Fprintf(&b, "primary: %v (serving: %v)\n", report.Primary, report.PrimaryServing)
+		cells := make([]string, 0, len(report.ReplicasByCell))
+		for cell := range report.ReplicasByCell {
+			cells = append(cells, cell)
+		}
+		sort.Strings(cells)
+		for _, cell := range cells {
+			fmt.Fprintf(&b, "serving replicas in %v: %d\n", cell, report.ReplicasByCell[cell])
+		}
+		fmt.Fprintf(&b, "max replication lag: %ds\n", report.MaxReplicationLagSeconds)
+		if len(report.Restoring) > 0 {
+			fmt.Fprintf(&b, "restoring: %v\n", strings.Join(report.Restoring, " "))
+		}
+		if len(report.BackingUp) > 0 {
+			fmt.Fprintf(&b, "backing up: %v\n", strings.Join(report.BackingUp, " "))
+		}
+		for _, tablet := range report.Tablets {
+			if tablet.HealthError != "" {
+				fmt.Fprintf(&b, "unhealthy tablet %v (%v): %v\n", tablet.TabletAlias, tablet.Type, tablet.HealthError)
+			}
+		}
+		for _, finding := range report.Findings {
+			fmt.Fprintf(&b, "%v: %v\n", finding.Severity, finding.Message)
+		}
+		for _, reason := range report.Reasons {
+			fmt.Fprintf(&b, "not ready: %v\n", reason)
+		}
+		wr.Logger().Printf("%s", b.String())
+	}
+	if !report.Ready {
+		return fmt.Errorf("shard %v/%v is not ready: %v", keyspace, shard, strings.Join(report.Reasons, "; "))
+	}
+	return nil
+}
+
+func commandWaitForShardReplicasPosition(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	position := subFlags.String("position", "", "Position to wait for. Defaults to the current position of the shard primary.")
+	timeout := subFlags.Duration("timeout", 30*time.Second, "How long to wait for the tablets to reach the position")
+	tabletTypes := subFlags.StringSlice("tablet-types", []string{"REPLICA", "RDONLY"}, "Types of the tablets to wait for")
+	var tags flagutil.StringMapValue
+	subFlags.Var(&tags, "tags", "A comma-separated list of key:value pairs the tablets to wait for must all have")
+	format := subFlags.String("format", "text", "Format of the report") // "json" or "text"
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace/shard> argument is required for the WaitForShardReplicasPosition command")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid --format %q, must be one of text, json", *format)
+	}
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	filter := wrangler.ReplicaPositionFilter{Tags: tags}
+	for _, tabletType := range *tabletTypes {
+		tt, err := topoproto.ParseTabletType(tabletType)
+		if err != nil {
+			return err
+		}
+		filter.TabletTypes = append(filter.TabletTypes, tt)
+	}
+
+	report, err := wr.WaitForShardReplicasPosition(ctx, keyspace, shard, *position, *timeout, filter)
+	if err != nil {
+		return err
+	}
+	if *format == "json" {
+		if err := printJSON(wr.Logger(), report); err != nil {
+			return err
+		}
+	} else {
+		var b strings.Builder
+		fmt.Fprintf(&b, "waiting for %v/%v to reach %v\n", keyspace, shard, report.Position)
+		for _, tablet := range report.Tablets {
+			if tablet.Reached {
+				fmt.Fprintf(&b, "%v (%v): reached in %v\n", tablet.TabletAlias, tablet.Type, tablet.Elapsed)
+			} else {
+				fmt.Fprintf(&b, "%v (%v): not reached after %v: %v\n", tablet.TabletAlias, tablet.Type, tablet.Elapsed, tablet.Error)
+			}
+		}
+		for _, skipped := range report.Skipped {
+			fmt.Fprintf(&b, "%v (%v): skipped, %v\n", skipped.TabletAlias, skipped.Type, skipped.Reason)
+		}
+		wr.Logger().Printf("%s", b.String())
+	}
+	if !report.AllReached() {
+		return fmt.Errorf("not all the tablets of %v/%v reached position %v within %v", keyspace, shard, report.Position, *timeout)
+	}
+	return nil
+}
+
+func commandSnapshotShardState(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	outputDir := subFlags.String("output-dir", "", "Writes the snapshot to a timestamped JSON file in this directory instead of printing it")
+	compare := subFlags.Bool("compare", false, "Compares two snapshot files, and prints what changed from the first one to the second one")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+
+	if *compare {
+		if subFlags.NArg() != 2 {
+			return fmt.Errorf("the <before file> and <after file> arguments are required for the SnapshotShardState command with --compare")
+		}
+		before, err := os.ReadFile(subFlags.Arg(0))
+		if err != nil {
+			return err
+		}
+		after, err := os.ReadFile(subFlags.Arg(1))
+		if err != nil {
+			return err
+		}
+		changes, err := wrangler.DiffShardSnapshots(before, after)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			wr.Logger().Printf("No changes\n")
+		}
+		for _, change := range changes {
+			wr.Logger().Printf("%s\n", change)
+		}
+		return nil
+	}
+
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace/shard> argument is required for the SnapshotShardState command")
+	}
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	snapshot, err := wr.SnapshotShardState(ctx, keyspace, shard)
+	if err != nil {
+		return err
+	}
+	for _, snapshotErr := range snapshot.Errors {
+		wr.Logger().Warningf("%v", snapshotErr)
+	}
+	for alias, tablet := range snapshot.Tablets {
+		for _, tabletErr := range tablet.Errors {
+			wr.Logger().Warningf("%v: %v", alias, tabletErr)
+		}
+	}
+
+	if *outputDir == "" {
+		return printJSON(wr.Logger(), snapshot)
+	}
+	data, err := MarshalJSON(snapshot)
+	if err != nil {
+		return err
+	}
+	name := filepath.Join(*outputDir, fmt.Sprintf("%v_%v_%v.json", keyspace, shard, snapshot.CapturedAt.Format("20060102T150405.000Z")))
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		return err
+	}
+	wr.Logger().Printf("Wrote the snapshot of %v/%v to %v\n", keyspace, shard, name)
+	return nil
+}
+
+func commandGetShardJournals(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	format := subFlags.String("format", "text", "Format of the report") // "json" or "text"
+	migrationID := subFlags.Int64("migration-id", 0, "Only read the journal of this migration, required with a keyspace")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace/shard> or <keyspace> argument is required for the GetShardJournals command")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid --format %q, must be one of text, json", *format)
+	}
+
+	var (
+		shardJournals []*wrangler.ShardJournals
+		report        any
+		errs          []string
+	)
+	if strings.Contains(subFlags.Arg(0), "/") {
+		keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+		if err != nil {
+			return err
+		}
+		result, err := wr.GetShardJournals(ctx, keyspace, shard, *migrationID)
+		if err != nil {
+			return err
+		}
+		shardJournals, report = []*wrangler.ShardJournals{result}, result
+	} else {
+		keyspaceReport, err := wr.FindKeyspaceJournal(ctx, subFlags.Arg(0), *migrationID)
+		if err != nil {
+			return err
+		}
+		shardJournals = append(keyspaceReport.Shards, keyspaceReport.ShardsWithErrors...)
+		report = keyspaceReport
+		if len(keyspaceReport.Shards) == 0 && len(keyspaceReport.ShardsWithErrors) == 0 {
+			errs = append(errs, fmt.Sprintf("journal %d not found in keyspace %v", *migrationID, keyspaceReport.Keyspace))
+		}
+		if len(keyspaceReport.MissingParticipants) > 0 {
+			errs = append(errs, fmt.Sprintf("journal %d is missing from participants %v", *migrationID, strings.Join(keyspaceReport.MissingParticipants, ", ")))
+		}
+		for _, result := range keyspaceReport.ShardsWithErrors {
+			errs = append(errs, fmt.Sprintf("%v/%v: %v", result.Keyspace, result.Shard, result.Error))
+		}
+	}
+
+	if *format == "json" {
+		if err := printJSON(wr.Logger(), report); err != nil {
 			return err
 		}
+	} else {
+		var b strings.Builder
+		for _, result := range shardJournals {
+			if result.Error != "" {
+				fmt.Fprintf(&b, "%v/%v: cannot read the journals: %v\n", result.Keyspace, result.Shard, result.Error)
+				continue
+			}
+			fmt.Fprintf(&b, "%v/%v: primary %v, %d journals\n", result.Keyspace, result.Shard, result.PrimaryAlias, len(result.Journals))
+			for _, journal := range result.Journals {
+				fmt.Fprintf(&b, "  id %d (%v): %v migration\n", journal.ID, journal.DbName, strings.ToLower(journal.MigrationType))
+				if len(journal.Tables) > 0 {
+					fmt.Fprintf(&b, "    tables: %v\n", strings.Join(journal.Tables, ", "))
+				}
+				fmt.Fprintf(&b, "    local position: %v\n", journal.LocalPosition)
+				fmt.Fprintf(&b, "    participants: %v\n", strings.Join(journal.Participants, ", "))
+				for _, position := range journal.SourcePositions {
+					fmt.Fprintf(&b, "    source %v/%v: %v\n", position.Keyspace, position.Shard, position.Position)
+				}
+				if len(journal.SourceWorkflows) > 0 {
+					fmt.Fprintf(&b, "    source workflows: %v\n", strings.Join(journal.SourceWorkflows, ", "))
+				}
+			}
+		}
+		wr.Logger().Printf("%s", b.String())
 	}
-
-	err = wr.TopoServer().CreateShard(ctx, keyspace, shard)
-	if *force && topo.IsErrType(err, topo.NodeExists) {
-		wr.Logger().Infof("shard %v/%v already exists (ignoring error with --force)", keyspace, shard)
-		err = nil
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
 	}
-	return err
+	return nil
 }
 
-func commandGetShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+func commandGetPrimaryTermHistory(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	format := subFlags.String("format", "text", "Format of the report") // "json" or "text"
+	window := subFlags.Duration("window", 24*time.Hour, "With a keyspace, the window to count the primary changes of each shard in")
+	maxChanges := subFlags.Int("max-changes", 3, "With a keyspace, the number of primary changes in the window above which a shard is flagged")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
 	if subFlags.NArg() != 1 {
-		return fmt.Errorf("the <keyspace/shard> argument is required for the GetShard command")
-	}
-
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
-	if err != nil {
-		return err
+		return fmt.Errorf("the <keyspace/shard> or <keyspace> argument is required for the GetPrimaryTermHistory command")
 	}
-	shardInfo, err := wr.TopoServer().GetShard(ctx, keyspace, shard)
-	if err != nil {
-		return err
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid --format %q, must be one of text, json", *format)
 	}
-	// Pass the embedded proto directly or jsonpb will panic.
-	return printJSON(wr.Logger(), shardInfo.Shard)
-}
 
-func commandValidateShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
-	pingTablets := subFlags.Bool("ping-tablets", true, "Indicates whether all tablets should be pinged during the validation process")
-	if err := subFlags.Parse(args); err != nil {
-		return err
-	}
-	if subFlags.NArg() != 1 {
-		return fmt.Errorf("the <keyspace/shard> argument is required for the ValidateShard command")
+	var (
+		histories      []*wrangler.PrimaryTermHistory
+		report         any
+		unstableShards []string
+	)
+	if strings.Contains(subFlags.Arg(0), "/") {
+		keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+		if err != nil {
+			return err
+		}
+		history, err := wr.GetPrimaryTermHistory(ctx, keyspace, shard)
+		if err != nil {
+			return err
+		}
+		histories, report = []*wrangler.PrimaryTermHistory{history}, history
+	} else {
+		keyspaceReport, err := wr.GetKeyspacePrimaryTermHistory(ctx, subFlags.Arg(0), *window, *maxChanges)
+		if err != nil {
+			return err
+		}
+		histories, report = keyspaceReport.Shards, keyspaceReport
+		unstableShards = keyspaceReport.UnstableShards
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
-	if err != nil {
-		return err
+	if *format == "json" {
+		if err := printJSON(wr.Logger(), report); err != nil {
+			return err
+		}
+	} else {
+		unstable := make(map[string]bool, len(unstableShards))
+		for _, shard := range unstableShards {
+			unstable[shard] = true
+		}
+		var b strings.Builder
+		for _, history := range histories {
+			if history.Error != "" {
+				fmt.Fprintf(&b, "%v/%v: cannot read the history: %v\n", history.Keyspace, history.Shard, history.Error)
+				continue
+			}
+			fmt.Fprintf(&b, "%v/%v: %d primary changes from the %v", history.Keyspace, history.Shard, len(history.Changes), history.Source)
+			if unstable[history.Shard] {
+				fmt.Fprintf(&b, ", UNSTABLE: %d changes in the last %v", history.RecentChanges, *window)
+			}
+			b.WriteString("\n")
+			if history.JournalError != "" {
+				fmt.Fprintf(&b, "  %v\n", history.JournalError)
+			}
+			for _, change := range history.Changes {
+				fmt.Fprintf(&b, "  %v  %v  %v", change.Time.Format(time.RFC3339), change.PrimaryAlias, change.Action)
+				if change.SincePrevious > 0 {
+					fmt.Fprintf(&b, "  (%v after the previous change)", change.SincePrevious)
+				}
+				b.WriteString("\n")
+			}
+			if len(history.Changes) > 0 {
+				fmt.Fprintf(&b, "  current term: %v\n", history.CurrentTerm.Round(time.Second))
+			}
+		}
+		wr.Logger().Printf("%s", b.String())
 	}
-	return wr.ValidateShard(ctx, keyspace, shard, *pingTablets)
+	if len(unstableShards) > 0 {
+		return fmt.Errorf("the primary changed more than %d times in the last %v in shards %v", *maxChanges, *window, strings.Join(unstableShards, ", "))
+	}
+	return nil
 }
 
-func commandShardReplicationPositions(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+func commandCheckErrantGTIDs(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	format := subFlags.String("format", "text", "Format of the report") // "json" or "text"
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
 	if subFlags.NArg() != 1 {
-		return fmt.Errorf("the <keyspace/shard> argument is required for the ShardReplicationPositions command")
+		return fmt.Errorf("the <keyspace/shard> or <keyspace> argument is required for the CheckErrantGTIDs command")
 	}
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
-	if err != nil {
-		return err
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid --format %q, must be one of text, json", *format)
 	}
 
-	resp, err := wr.VtctldServer().ShardReplicationPositions(ctx, &vtctldatapb.ShardReplicationPositionsRequest{
-		Keyspace: keyspace,
-		Shard:    shard,
-	})
-	if err != nil {
-		return err
+	var (
+		shardReports []*wrangler.ShardErrantGTIDReport
+		report       any
+		errantShards []string
+		errorShards  []string
+	)
+	if strings.Contains(subFlags.Arg(0), "/") {
+		keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+		if err != nil {
+			return err
+		}
+		shardReport, err := wr.CheckErrantGTIDsShard(ctx, keyspace, shard)
+		if err != nil {
+			return err
+		}
+		shardReports, report = []*wrangler.ShardErrantGTIDReport{shardReport}, shardReport
+		if shardReport.HasErrantGTIDs() {
+			errantShards = []string{shard}
+		}
+	} else {
+		keyspaceReport, err := wr.CheckErrantGTIDsKeyspace(ctx, subFlags.Arg(0))
+		if err != nil {
+			return err
+		}
+		shardReports, report = keyspaceReport.Shards, keyspaceReport
+		errantShards = keyspaceReport.ShardsWithErrantGTIDs
+		errorShards = keyspaceReport.ShardsWithErrors
+	}
+	var uncheckedTablets []string
+	for _, shardReport := range shardReports {
+		uncheckedTablets = append(uncheckedTablets, shardReport.UncheckedTablets()...)
 	}
 
-	lines := make([]string, 0, 24)
-	for _, rt := range cli.SortedReplicatingTablets(resp.TabletMap, resp.ReplicationStatuses) {
-		status := rt.Status
-		tablet := rt.Tablet
-		if status == nil {
-			lines = append(lines, cli.MarshalTabletAWK(tablet)+" <err> <err> <err>")
-		} else {
-			lines = append(lines, cli.MarshalTabletAWK(tablet)+fmt.Sprintf(" %v %v", status.Position, status.ReplicationLagSeconds))
+	if *format == "json" {
+		if err := printJSON(wr.Logger(), report); err != nil {
+			return err
+		}
+	} else {
+		var b strings.Builder
+		for _, shardReport := range shardReports {
+			if shardReport.Error != "" {
+				fmt.Fprintf(&b, "%v/%v: cannot check: %v\n", shardReport.Keyspace, shardReport.Shard, shardReport.Error)
+				continue
+			}
+			fmt.Fprintf(&b, "%v/%v: primary %v at %v\n", shardReport.Keyspace, shardReport.Shard, shardReport.PrimaryAlias, shardReport.PrimaryPosition)
+			for _, tablet := range shardReport.Tablets {
+				switch {
+				case tablet.Error != "":
+					fmt.Fprintf(&b, "  %v (%v): cannot check: %v\n", tablet.TabletAlias, tablet.Type, tablet.Error)
+				case tablet.ErrantCount == 0:
+					fmt.Fprintf(&b, "  %v (%v): no errant GTIDs\n", tablet.TabletAlias, tablet.Type)
+				default:
+					fmt.Fprintf(&b, "  %v (%v): %d errant GTIDs: %v\n", tablet.TabletAlias, tablet.Type, tablet.ErrantCount, tablet.ErrantGTIDs)
+					sources := make([]string, 0, len(tablet.ErrantCountBySource))
+					for source := range tablet.ErrantCountBySource {
+						sources = append(sources, source)
+					}
+					sort.Strings(sources)
+					for _, source := range sources {
+						fmt.Fprintf(&b, "    %v: %d\n", source, tablet.ErrantCountBySource[source])
+					}
+				}
+			}
 		}
+		wr.Logger().Printf("%s", b.String())
 	}
-	for _, l := range lines {
-		wr.Logger().Printf("%v\n", l)
+	var errs []string
+	if len(errantShards) > 0 {
+		errs = append(errs, fmt.Sprintf("errant GTIDs found in shards %v", strings.Join(errantShards, ", ")))
+	}
+	if len(errorShards) > 0 {
+		errs = append(errs, fmt.Sprintf("cannot check shards %v", strings.Join(errorShards, ", ")))
+	}
+	if len(uncheckedTablets) > 0 {
+		errs = append(errs, fmt.Sprintf("cannot check tablets %v", strings.Join(uncheckedTablets, ", ")))
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
 	}
 	return nil
 }
@@ -1577,6 +2670,68 @@ func commandUpdateSrvKeyspacePartition(ctx context.Context, wr *wrangler.Wrangle
 	return nil
 }
 
+func commandSetShardReadOnly(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	return setShardWritable(ctx, wr, subFlags, args, false)
+}
+
+func commandSetShardReadWrite(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	return setShardWritable(ctx, wr, subFlags, args, true)
+}
+
+func setShardWritable(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string, writable bool) error {
+	concurrency := subFlags.Int("concurrency", 8, "Number of shards to change at once when given a keyspace")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace/shard> or <keyspace> argument is required")
+	}
+
+	var (
+		results []*wrangler.ShardWritableResult
+		err     error
+	)
+	if strings.Contains(subFlags.Arg(0), "/") {
+		keyspace, shard, perr := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+		if perr != nil {
+			return perr
+		}
+		var result *wrangler.ShardWritableResult
+		result, err = wr.SetShardWritable(ctx, keyspace, shard, writable)
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = []*wrangler.ShardWritableResult{result}
+	} else {
+		results, err = wr.SetKeyspaceWritable(ctx, subFlags.Arg(0), writable, *concurrency)
+		if results == nil {
+			return err
+		}
+	}
+
+	summary := struct {
+		Shards   []*wrangler.ShardWritableResult
+		Changed  int
+		Verified int
+		Failed   int
+	}{Shards: results}
+	for _, result := range results {
+		if result.Changed {
+			summary.Changed++
+		}
+		if result.Verified {
+			summary.Verified++
+		}
+		if result.Error != "" {
+			summary.Failed++
+		}
+	}
+	if perr := printJSON(wr.Logger(), summary); perr != nil {
+		return perr
+	}
+	return err
+}
+
 func commandSetShardTabletControl(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	cellsStr := subFlags.String("cells", "", "Specifies a comma-separated list of cells to update")
 	deniedTablesStr := subFlags.String("denied_tables", "", "Specifies a comma-separated list of tables to add to the denylist (used for VReplication). Each is either an exact match, or a regular expression of the form '/regexp/'.")
@@ -1697,6 +2852,7 @@ func commandShardReplicationAdd(ctx context.Context, wr *wrangler.Wrangler, subF
 }
 
 func commandShardReplicationRemove(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1708,7 +2864,7 @@ func commandShardReplicationRemove(ctx context.Context, wr *wrangler.Wrangler, s
 	if err != nil {
 		return err
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(1))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(1))
 	if err != nil {
 		return err
 	}
@@ -1734,8 +2890,40 @@ func commandShardReplicationFix(ctx context.Context, wr *wrangler.Wrangler, subF
 	if err != nil {
 		return err
 	}
-	_, err = topo.FixShardReplication(ctx, wr.TopoServer(), wr.Logger(), cell, keyspace, shard)
-	return err
+	result, err := topotools.FixShardReplication(ctx, wr.TopoServer(), wr.Logger(), cell, keyspace, shard)
+	if err != nil {
+		return err
+	}
+	for _, alias := range result.Added {
+		wr.Logger().Printf("Added %v\n", topoproto.TabletAliasString(alias))
+	}
+	for _, problem := range result.Removed {
+		wr.Logger().Printf("Removed %v (%v)\n", topoproto.TabletAliasString(problem.TabletAlias), problem.Type)
+	}
+	return nil
+}
+
+func commandValidateShardReplication(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	cells := subFlags.String("cells", "", "Specifies a comma-separated list of cells to validate")
+	asJSON := subFlags.Bool("json", false, validationJSONUsage)
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace/shard> argument is required for the ValidateShardReplication command")
+	}
+
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	var cellArray []string
+	if *cells != "" {
+		cellArray = strings.Split(*cells, ",")
+	}
+	return runValidation(wr, *asJSON, func(report *wrangler.ValidationReport) error {
+		return wr.ValidateShardReplication(ctx, keyspace, shard, cellArray, report)
+	})
 }
 
 func commandWaitForFilteredReplication(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
@@ -1993,6 +3181,7 @@ func commandGetKeyspaces(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 func commandRebuildKeyspaceGraph(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	cells := subFlags.String("cells", "", "Specifies a comma-separated list of cells to update")
 	allowPartial := subFlags.Bool("allow_partial", false, "Specifies whether a SNAPSHOT keyspace is allowed to serve with an incomplete set of shards. Ignored for all other types of keyspaces")
+	forceWrite := subFlags.Bool("force-write", false, "Writes the serving data even if it is unchanged, which triggers an update to all connected clients")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -2014,6 +3203,7 @@ func commandRebuildKeyspaceGraph(ctx context.Context, wr *wrangler.Wrangler, sub
 			Keyspace:     keyspace,
 			Cells:        cellArray,
 			AllowPartial: *allowPartial,
+			ForceWrite:   *forceWrite,
 		})
 		if err != nil {
 			return err
@@ -2022,8 +3212,40 @@ func commandRebuildKeyspaceGraph(ctx context.Context, wr *wrangler.Wrangler, sub
 	return nil
 }
 
+func commandRebuildReplicationGraph(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	cells := subFlags.String("cells", "", "Specifies a comma-separated list of cells to rebuild")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace> argument is required for the RebuildReplicationGraph command")
+	}
+
+	var cellArray []string
+	if *cells != "" {
+		cellArray = strings.Split(*cells, ",")
+	}
+
+	results, err := topotools.RebuildReplicationGraph(ctx, wr.TopoServer(), wr.Logger(), subFlags.Arg(0), cellArray)
+	for _, result := range results {
+		added := make([]string, len(result.Added))
+		for i, alias := range result.Added {
+			added[i] = topoproto.TabletAliasString(alias)
+		}
+		removed := make([]string, len(result.Removed))
+		for i, problem := range result.Removed {
+			removed[i] = fmt.Sprintf("%v (%v)", topoproto.TabletAliasString(problem.TabletAlias), problem.Type)
+		}
+		wr.Logger().Printf("%v/%v in %v: added %d [%v], removed %d [%v]\n", result.Keyspace, result.Shard, result.Cell,
+			len(added), strings.Join(added, ", "), len(removed), strings.Join(removed, ", "))
+	}
+	return err
+}
+
 func commandValidateKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	pingTablets := subFlags.Bool("ping-tablets", false, "Specifies whether all tablets will be pinged during the validation process")
+	checkSrvKeyspace := subFlags.Bool("check-srv-keyspace", false, "Compares the SrvKeyspace of each cell with the one RebuildKeyspaceGraph would compute from the shard records")
+	asJSON := subFlags.Bool("json", false, validationJSONUsage)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -2032,7 +3254,9 @@ func commandValidateKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlag
 	}
 
 	keyspace := subFlags.Arg(0)
-	return wr.ValidateKeyspace(ctx, keyspace, *pingTablets)
+	return runValidation(wr, *asJSON, func(report *wrangler.ValidationReport) error {
+		return wr.ValidateKeyspace(ctx, keyspace, *pingTablets, *checkSrvKeyspace, report)
+	})
 }
 
 func commandReshard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
@@ -2085,13 +3309,16 @@ func commandVReplicationWorkflow(ctx context.Context, wr *wrangler.Wrangler, sub
 	dryRun := subFlags.Bool("dry_run", false, "Does a dry run of SwitchTraffic and only reports the actions to be taken. --dry_run is only supported for SwitchTraffic, ReverseTraffic and Complete.")
 	timeout := subFlags.Duration("timeout", defaultWaitTime, "Specifies the maximum time to wait, in seconds, for vreplication to catch up on primary migrations. The migration will be cancelled on a timeout. --timeout is only supported for SwitchTraffic and ReverseTraffic.")
 	reverseReplication := subFlags.Bool("reverse_replication", true, "Also reverse the replication (default true). --reverse_replication is only supported for SwitchTraffic.")
+	verifyReverse := subFlags.Bool("verify-reverse", false, "After switching writes, run a sampled VDiff on the reverse workflow and fail if it finds differences. --verify-reverse is only supported for SwitchTraffic, with --reverse_replication.")
+	verifyReverseSamplePct := subFlags.Int("verify-reverse-sample-pct", 10, "The percentage of the rows of each table that --verify-reverse compares. The rows are contiguous ranges of the leading primary key column, so reruns compare the same rows. The tables whose leading primary key column is not an integer are compared in full.")
+	verifyReverseTables := subFlags.String("verify-reverse-tables", "", "The tables (comma-separated) that --verify-reverse compares. All the tables of the workflow are compared if empty.")
 	keepData := subFlags.Bool("keep_data", false, "Do not drop tables or shards (if true, only vreplication artifacts are cleaned up).  --keep_data is only supported for Complete and Cancel.")
 	keepRoutingRules := subFlags.Bool("keep_routing_rules", false, "Do not remove the routing rules for the source keyspace.  --keep_routing_rules is only supported for Complete and Cancel.")
 	autoStart := subFlags.Bool("auto_start", true, "If false, streams will start in the Stopped state and will need to be explicitly started")
 	stopAfterCopy := subFlags.Bool("stop_after_copy", false, "Streams will be stopped once the copy phase is completed")
 	dropForeignKeys := subFlags.Bool("drop_foreign_keys", false, "If true, tables in the target keyspace will be created without foreign keys.")
 	maxReplicationLagAllowed := subFlags.Duration("max_replication_lag_allowed", defaultMaxReplicationLagAllowed, "Allow traffic to be switched only if vreplication lag is below this (in seconds)")
-	atomicCopy := subFlags.Bool("atomic-copy", false, "(EXPERIMENTAL) Use this if your source keyspace has tables which use foreign key constraints. All tables from the source will be moved.")
+	atomicCopy := subFlags.Bool("atomic-copy", false, "(EXPERIMENTAL) Use this if your source keyspace has tables which use foreign key constraints. All tables from the source will be moved, in a single consistent snapshot. Requires --all, and fails with the unmet prerequisites if a foreign key references a table outside the source keyspace or a table doesn't use InnoDB.")
 	shards := subFlags.StringSlice("shards", nil, "(Optional) Specifies a comma-separated list of shards to operate on.")
 
 	onDDL := "IGNORE"
@@ -2163,7 +3390,11 @@ func commandVReplicationWorkflow(ctx context.Context, wr *wrangler.Wrangler, sub
 		if err != nil {
 			return err
 		}
-		s += fmt.Sprintf("The following vreplication streams exist for workflow %s.%s:\n\n", target, workflowName)
+		s += fmt.Sprintf("The following vreplication streams exist for workflow %s.%s", target, workflowName)
+		if res.AtomicCopy {
+			s += ", which copies all the tables in a single consistent snapshot (atomic copy)"
+		}
+		s += ":\n\n"
 
 		// Sort the results for consistent and intuitive output.
 		ksShardKeys := make([]string, 0, len(res.ShardStatuses))
@@ -2247,23 +3478,6 @@ func commandVReplicationWorkflow(ctx context.Context, wr *wrangler.Wrangler, sub
 				return err
 			}
 
-			if *atomicCopy {
-				var errs []string
-				if !*allTables {
-					errs = append(errs, "atomic copy requires --all.")
-				}
-				if *tables != "" {
-					errs = append(errs, "atomic copy does not support specifying tables.")
-				}
-				if *excludes != "" {
-					errs = append(errs, "atomic copy does not support specifying excludes.")
-				}
-				if len(errs) > 0 {
-					errs = append(errs, "Found options incompatible with atomic copy:")
-					return errors.New(strings.Join(errs, " "))
-				}
-			}
-
 			if !*allTables && *tables == "" {
 				return errors.New("no tables specified to move")
 			}
@@ -2381,6 +3595,17 @@ func commandVReplicationWorkflow(ctx context.Context, wr *wrangler.Wrangler, sub
 			return fmt.Errorf("--dry_run is only supported for SwitchTraffic, ReverseTraffic and Complete, not for %s", originalAction)
 		}
 	}
+	if *verifyReverse {
+		if action != vReplicationWorkflowActionSwitchTraffic {
+			return fmt.Errorf("--verify-reverse is only supported for SwitchTraffic, not for %s", originalAction)
+		}
+		if !*reverseReplication {
+			return fmt.Errorf("--verify-reverse needs --reverse_replication")
+		}
+		if *verifyReverseSamplePct < 1 || *verifyReverseSamplePct > 100 {
+			return fmt.Errorf("--verify-reverse-sample-pct must be between 1 and 100, got %d", *verifyReverseSamplePct)
+		}
+	}
 
 	wr.WorkflowParams = vrwp
 
@@ -2498,6 +3723,32 @@ func commandVReplicationWorkflow(ctx context.Context, wr *wrangler.Wrangler, sub
 	}
 	wr.Logger().Printf("%s was successful for workflow %s.%s\nStart State: %s\nCurrent State: %s\n\n",
 		originalAction, vrwp.TargetKeyspace, vrwp.Workflow, startState, wf.CurrentState())
+	if *verifyReverse && !*dryRun {
+		return verifyReverseWorkflow(ctx, wr, vrwp.TargetKeyspace, vrwp.Workflow, *verifyReverseSamplePct, *verifyReverseTables, *timeout)
+	}
+	return nil
+}
+
+// verifyReverseWorkflow runs a sampled VDiff on the reverse workflow of the
+// workflow, and fails if it finds differences, which must be resolved before
+// the workflow is completed.
+func verifyReverseWorkflow(ctx context.Context, wr *wrangler.Wrangler, keyspace, workflowName string, samplePercent int, tables string, waitTime time.Duration) error {
+	reports, err := wr.VerifyReverseWorkflow(ctx, keyspace, workflowName, samplePercent, tables, waitTime)
+	if err != nil {
+		return err
+	}
+	var differing []string
+	for table, dr := range reports {
+		if dr.HasDiffs() {
+			differing = append(differing, table)
+		}
+	}
+	if len(differing) > 0 {
+		sort.Strings(differing)
+		return fmt.Errorf("the reverse workflow of %s.%s differs on the sampled rows of %s: check it before completing the workflow",
+			keyspace, workflowName, strings.Join(differing, ", "))
+	}
+	wr.Logger().Printf("The reverse workflow of %s.%s matches on the sampled rows of all the tables\n", keyspace, workflowName)
 	return nil
 }
 
@@ -2581,6 +3832,7 @@ func commandVDiff(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.Fl
 	format := subFlags.String("format", "", "Format of report") // "json" or ""
 	tables := subFlags.String("tables", "", "Only run vdiff for these tables in the workflow")
 	maxExtraRowsToCompare := subFlags.Int("max_extra_rows_to_compare", 1000, "If there are collation differences between the source and target, you can have rows that are identical but simply returned in a different order from MySQL. We will do a second pass to compare the rows for any actual differences in this case and this flag allows you to control the resources used for this operation.")
+	collationAware := subFlags.Bool("collation-aware", false, "Compare the text columns using their collation, so that rows which only differ e.g. in case under a case-insensitive collation match. These rows are reported as CollationMatchingRows. Binary and blob columns are still compared exactly.")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -2604,7 +3856,7 @@ func commandVDiff(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.Fl
 	}()
 
 	_, err = wr.VDiff(ctx, keyspace, workflow, *sourceCell, *targetCell, *tabletTypesStr, *filteredReplicationWaitTime, *format,
-		*maxRows, *tables, *debugQuery, *onlyPks, *maxExtraRowsToCompare)
+		*maxRows, *tables, *debugQuery, *onlyPks, *maxExtraRowsToCompare, *collationAware)
 	if err != nil {
 		log.Errorf("vdiff returning with error: %v", err)
 		if strings.Contains(err.Error(), "context deadline exceeded") {
@@ -2614,6 +3866,95 @@ func commandVDiff(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.Fl
 	return err
 }
 
+func commandCompareRowCounts(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tables := subFlags.StringSlice("tables", nil, "Only compare these tables")
+	approximate := subFlags.Bool("approximate", false, "Compare the row estimates of information_schema.tables, which are much cheaper but not exact, rather than COUNT(*)")
+	tolerancePct := subFlags.Float64("tolerance-pct", 0, "Difference between the target and source row counts of a table, in percent of the source row count, above which the table is reported")
+	concurrency := subFlags.Int("concurrency", 8, "Number of count queries to run at a time")
+	format := subFlags.String("format", "text", "Format of the report") // "json" or "text"
+	sourceShards := subFlags.StringSlice("source-shards", nil, "With a source and a target keyspace, only count the rows of these source shards")
+	targetShards := subFlags.StringSlice("target-shards", nil, "With a source and a target keyspace, only count the rows of these target shards")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 && subFlags.NArg() != 2 {
+		return fmt.Errorf("the <keyspace.workflow> or <source keyspace> <target keyspace> arguments are required for the CompareRowCounts command")
+	}
+	if subFlags.NArg() == 1 && (len(*sourceShards) > 0 || len(*targetShards) > 0) {
+		return fmt.Errorf("--source-shards and --target-shards require a source and a target keyspace")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid --format %q, must be one of text, json", *format)
+	}
+	if *tolerancePct < 0 {
+		return fmt.Errorf("--tolerance-pct must not be negative, got %v", *tolerancePct)
+	}
+
+	opts := wrangler.RowCountOptions{
+		Tables:       *tables,
+		Approximate:  *approximate,
+		TolerancePct: *tolerancePct,
+		Concurrency:  *concurrency,
+		SourceShards: *sourceShards,
+		TargetShards: *targetShards,
+	}
+	var (
+		report *wrangler.RowCountReport
+		err    error
+	)
+	if subFlags.NArg() == 1 {
+		keyspace, workflow, err := splitKeyspaceWorkflow(subFlags.Arg(0))
+		if err != nil {
+			return err
+		}
+		report, err = wr.CompareWorkflowRowCounts(ctx, keyspace, workflow, opts)
+		if err != nil {
+			return err
+		}
+	} else {
+		report, err = wr.CompareRowCounts(ctx, subFlags.Arg(0), subFlags.Arg(1), opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *format == "json" {
+		if err := printJSON(wr.Logger(), report); err != nil {
+			return err
+		}
+	} else {
+		var b strings.Builder
+		kind := "exact"
+		if report.Approximate {
+			kind = "approximate"
+		}
+		fmt.Fprintf(&b, "%v row counts of %v vs %v, tolerance %v%%\n", kind, report.SourceKeyspace, report.TargetKeyspace, report.TolerancePct)
+		for _, table := range report.Tables {
+			switch {
+			case len(table.Errors) > 0:
+				fmt.Fprintf(&b, "  %v: cannot count: %v\n", table.Table, strings.Join(table.Errors, "; "))
+			case table.Mismatch:
+				fmt.Fprintf(&b, "  %v: MISMATCH source %d, target %d (%+d)\n", table.Table, table.SourceRows, table.TargetRows, table.Difference)
+			default:
+				fmt.Fprintf(&b, "  %v: source %d, target %d (%+d)\n", table.Table, table.SourceRows, table.TargetRows, table.Difference)
+			}
+		}
+		wr.Logger().Printf("%s", b.String())
+	}
+
+	var errs []string
+	if len(report.Mismatches) > 0 {
+		errs = append(errs, fmt.Sprintf("row counts differ in tables %v", strings.Join(report.Mismatches, ", ")))
+	}
+	if len(report.TablesWithErrors) > 0 {
+		errs = append(errs, fmt.Sprintf("cannot count the rows of tables %v", strings.Join(report.TablesWithErrors, ", ")))
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
 func splitKeyspaceWorkflow(in string) (keyspace, workflow string, err error) {
 	splits := strings.Split(in, ".")
 	if len(splits) != 2 {
@@ -2650,6 +3991,7 @@ func commandFindAllShardsInKeyspace(ctx context.Context, wr *wrangler.Wrangler,
 
 func commandValidate(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	pingTablets := subFlags.Bool("ping-tablets", false, "Indicates whether all tablets should be pinged during the validation process")
+	asJSON := subFlags.Bool("json", false, validationJSONUsage)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -2657,7 +3999,28 @@ func commandValidate(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag
 	if subFlags.NArg() != 0 {
 		wr.Logger().Warningf("action Validate doesn't take any parameter any more")
 	}
-	return wr.Validate(ctx, *pingTablets)
+	return runValidation(wr, *asJSON, func(report *wrangler.ValidationReport) error {
+		return wr.Validate(ctx, *pingTablets, report)
+	})
+}
+
+const validationJSONUsage = "Prints the findings as JSON, after a summary of what was checked and found"
+
+// runValidation runs a validation, then prints its report: as JSON, or as
+// logs followed by a summary.
+func runValidation(wr *wrangler.Wrangler, asJSON bool, validate func(report *wrangler.ValidationReport) error) error {
+	report := wrangler.NewValidationReport()
+	err := validate(report)
+	if !asJSON {
+		wr.LogValidationReport(report)
+		return err
+	}
+
+	report.Finish()
+	if jsonErr := printJSON(wr.Logger(), report); jsonErr != nil {
+		return jsonErr
+	}
+	return err
 }
 
 func commandListAllTablets(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
@@ -2698,6 +4061,7 @@ func commandListAllTablets(ctx context.Context, wr *wrangler.Wrangler, subFlags
 }
 
 func commandListTablets(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -2709,7 +4073,7 @@ func commandListTablets(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 	aliases := make([]*topodatapb.TabletAlias, len(paths))
 	var err error
 	for i, path := range paths {
-		aliases[i], err = topoproto.ParseTabletAlias(path)
+		aliases[i], err = tablets.resolve(ctx, path)
 		if err != nil {
 			return err
 		}
@@ -2738,6 +4102,7 @@ func commandGetSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *pfla
 	tableNamesOnly := subFlags.Bool("table_names_only", false, "Only displays table names that match")
 	tableSizesOnly := subFlags.Bool("table_sizes_only", false, "Only displays size information for tables. Ignored if --table_names_only is passed.")
 	tableSchemaOnly := subFlags.Bool("table_schema_only", false, "Only displays table schema. Skip columns and fields.")
+	tablets := newTabletResolver(wr, subFlags)
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -2745,7 +4110,7 @@ func commandGetSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *pfla
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <tablet alias> argument is required for the GetSchema command")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -2782,16 +4147,32 @@ func commandGetSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *pfla
 }
 
 func commandReloadSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	wait := subFlags.Bool("wait", false, "Wait until the reloaded schema can be read, and print whether it changed")
+	waitTable := subFlags.String("wait-table", "", "With --wait, a table to wait for in the reloaded schema")
+	waitTimeout := subFlags.Duration("wait-timeout", 30*time.Second, "With --wait, how long to wait for the change")
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <tablet alias> argument is required for the ReloadSchema command")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
+	if *wait {
+		result, err := wr.ReloadSchemaAndWait(ctx, tabletAlias, *waitTable, *waitTimeout)
+		if err != nil {
+			return err
+		}
+		if *waitTable == "" {
+			wr.Logger().Printf("Schema of tablet %v reloaded, schema hash %v, changed: %v\n", result.TabletAlias, result.SchemaHash, result.Changed)
+			return nil
+		}
+		wr.Logger().Printf("Schema of tablet %v reloaded and confirmed, schema hash %v\n", result.TabletAlias, result.SchemaHash)
+		return nil
+	}
 	_, err = wr.VtctldServer().ReloadSchema(ctx, &vtctldatapb.ReloadSchemaRequest{
 		TabletAlias: tabletAlias,
 	})
@@ -2801,6 +4182,9 @@ func commandReloadSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 func commandReloadSchemaShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	concurrency := subFlags.Int32("concurrency", 10, "How many tablets to reload in parallel")
 	includePrimary := subFlags.Bool("include_primary", true, "Include the primary tablet")
+	wait := subFlags.Bool("wait", false, "Wait until the reloaded schema of each tablet reflects the change")
+	waitTable := subFlags.String("wait-table", "", "With --wait, the table to wait for instead of the schema of the primary")
+	waitTimeout := subFlags.Duration("wait-timeout", 30*time.Second, "With --wait, how long to wait for each tablet")
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -2812,6 +4196,15 @@ func commandReloadSchemaShard(ctx context.Context, wr *wrangler.Wrangler, subFla
 	if err != nil {
 		return err
 	}
+	if *wait {
+		results, err := wr.ReloadSchemaShardAndWait(ctx, keyspace, shard, *waitTable, *includePrimary, int(*concurrency), *waitTimeout)
+		if results != nil {
+			if err := printJSON(wr.Logger(), results); err != nil {
+				return err
+			}
+		}
+		return err
+	}
 	resp, err := wr.VtctldServer().ReloadSchemaShard(ctx, &vtctldatapb.ReloadSchemaShardRequest{
 		Keyspace:       keyspace,
 		Shard:          shard,
@@ -2837,24 +4230,14 @@ func commandReloadSchemaKeyspace(ctx context.Context, wr *wrangler.Wrangler, sub
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <keyspace> argument is required for the ReloadSchemaKeyspace command")
 	}
-	resp, err := wr.VtctldServer().ReloadSchemaKeyspace(ctx, &vtctldatapb.ReloadSchemaKeyspaceRequest{
-		Keyspace:       subFlags.Arg(0),
-		WaitPosition:   "",
-		IncludePrimary: *includePrimary,
-		Concurrency:    *concurrency,
-	})
-	if resp != nil {
-		for _, e := range resp.Events {
-			logutil.LogEvent(wr.Logger(), e)
-		}
-	}
-	return err
+	return wr.ReloadSchemaKeyspace(ctx, subFlags.Arg(0), *includePrimary, int(*concurrency))
 }
 
 func commandValidateSchemaShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	excludeTables := subFlags.String("exclude_tables", "", "Specifies a comma-separated list of tables to exclude. Each is either an exact match, or a regular expression of the form /regexp/")
 	includeViews := subFlags.Bool("include-views", false, "Includes views in the validation")
 	includeVSchema := subFlags.Bool("include-vschema", false, "Validate schemas against the vschema")
+	includeNonServing := subFlags.Bool("include-non-serving", false, "Also validates the DRAINED, BACKUP and RESTORE tablets, which are skipped by default")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -2870,7 +4253,7 @@ func commandValidateSchemaShard(ctx context.Context, wr *wrangler.Wrangler, subF
 	if *excludeTables != "" {
 		excludeTableArray = strings.Split(*excludeTables, ",")
 	}
-	return wr.ValidateSchemaShard(ctx, keyspace, shard, excludeTableArray, *includeViews, *includeVSchema)
+	return wr.ValidateSchemaShard(ctx, keyspace, shard, excludeTableArray, *includeViews, *includeVSchema, *includeNonServing)
 }
 
 func commandValidateSchemaKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
@@ -2878,6 +4261,10 @@ func commandValidateSchemaKeyspace(ctx context.Context, wr *wrangler.Wrangler, s
 	includeViews := subFlags.Bool("include-views", false, "Includes views in the validation")
 	skipNoPrimary := subFlags.Bool("skip-no-primary", true, "Skip shards that don't have primary when performing validation")
 	includeVSchema := subFlags.Bool("include-vschema", false, "Validate schemas against the vschema")
+	referenceShard := subFlags.String("reference-shard", "", "The shard whose primary has the reference schema, defaults to a shard with the schema of the majority of the shard primaries")
+	referenceTablet := subFlags.String("reference-tablet", "", "The tablet that has the reference schema, instead of a shard primary")
+	includeNonServingShards := subFlags.Bool("include-non-serving-shards", false, "Also validates the shards that serve no traffic, such as the overlapping shards of a reshard, which are skipped by default")
+	includeNonServing := subFlags.Bool("include-non-serving", false, "Also validates the DRAINED, BACKUP and RESTORE tablets, which are skipped by default")
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -2891,12 +4278,24 @@ func commandValidateSchemaKeyspace(ctx context.Context, wr *wrangler.Wrangler, s
 	if *excludeTables != "" {
 		excludeTableArray = strings.Split(*excludeTables, ",")
 	}
+	var referenceTabletAlias *topodatapb.TabletAlias
+	if *referenceTablet != "" {
+		var err error
+		referenceTabletAlias, err = topoproto.ParseTabletAlias(*referenceTablet)
+		if err != nil {
+			return err
+		}
+	}
 	resp, err := wr.VtctldServer().ValidateSchemaKeyspace(ctx, &vtctldatapb.ValidateSchemaKeyspaceRequest{
-		Keyspace:       keyspace,
-		ExcludeTables:  excludeTableArray,
-		IncludeViews:   *includeViews,
-		SkipNoPrimary:  *skipNoPrimary,
-		IncludeVschema: *includeVSchema,
+		Keyspace:                keyspace,
+		ExcludeTables:           excludeTableArray,
+		IncludeViews:            *includeViews,
+		SkipNoPrimary:           *skipNoPrimary,
+		IncludeVschema:          *includeVSchema,
+		ReferenceShard:          *referenceShard,
+		ReferenceTablet:         referenceTabletAlias,
+		IncludeNonServing:       *includeNonServing,
+		IncludeNonServingShards: *includeNonServingShards,
 	})
 
 	if err != nil {
@@ -2904,6 +4303,18 @@ func commandValidateSchemaKeyspace(ctx context.Context, wr *wrangler.Wrangler, s
 		return err
 	}
 
+	if len(resp.NonServingShards) > 0 {
+		wr.Logger().Printf("Skipped non-serving shards: %s\n", strings.Join(resp.NonServingShards, ","))
+	}
+	for _, st := range resp.SkippedTablets {
+		wr.Logger().Printf("Skipped %v tablet %v of shard %v\n", st.TabletType, topoproto.TabletAliasString(st.TabletAlias), st.Shard)
+	}
+	if resp.ReferenceReason != "" {
+		wr.Logger().Printf("Reference: %s\n", resp.ReferenceReason)
+	}
+	if len(resp.OutlierShards) > 0 {
+		wr.Logger().Printf("Outlier shards: %s\n", strings.Join(resp.OutlierShards, ","))
+	}
 	for _, result := range resp.Results {
 		wr.Logger().Printf("%s\n", result)
 	}
@@ -2915,6 +4326,55 @@ func commandValidateSchemaKeyspace(ctx context.Context, wr *wrangler.Wrangler, s
 	return nil
 }
 
+func commandCompareSchemasAcrossKeyspaces(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tables := subFlags.StringSlice("tables", nil, "Tables to compare. Defaults to the tables of --workflow")
+	workflow := subFlags.String("workflow", "", "Workflow of the target keyspace whose tables are compared when --tables is not set")
+	ignoreExtraTargetColumns := subFlags.Bool("ignore-extra-target-columns", false, "Ignores the columns that only exist on the target, such as the ones added for VReplication")
+	ignorePartitioning := subFlags.Bool("ignore-partitioning", false, "Ignores the partitioning of the tables")
+	format := subFlags.String("format", "text", "Format of the report") // "json" or "text"
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 2 {
+		return fmt.Errorf("the <source keyspace> and <target keyspace> arguments are required for the CompareSchemasAcrossKeyspaces command")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid --format %q, must be one of text, json", *format)
+	}
+
+	report, err := wr.CompareSchemasAcrossKeyspaces(ctx, subFlags.Arg(0), subFlags.Arg(1), wrangler.CompareSchemasOptions{
+		Tables:                   *tables,
+		Workflow:                 *workflow,
+		IgnoreExtraTargetColumns: *ignoreExtraTargetColumns,
+		IgnorePartitioning:       *ignorePartitioning,
+	})
+	if err != nil {
+		return err
+	}
+	if *format == "json" {
+		if err := printJSON(wr.Logger(), report); err != nil {
+			return err
+		}
+	} else {
+		var b strings.Builder
+		for _, table := range report.Tables {
+			switch {
+			case table.Match:
+				fmt.Fprintf(&b, "%v on %v/%v: matches %v/%v\n", table.Table, report.TargetKeyspace, table.TargetShard, report.SourceKeyspace, table.SourceShard)
+			case table.Missing != "":
+				fmt.Fprintf(&b, "%v on %v/%v: missing on the %v\n", table.Table, report.TargetKeyspace, table.TargetShard, table.Missing)
+			default:
+				fmt.Fprintf(&b, "%v on %v/%v: differs from %v/%v\n%v", table.Table, report.TargetKeyspace, table.TargetShard, report.SourceKeyspace, table.SourceShard, table.Diff)
+			}
+		}
+		wr.Logger().Printf("%s", b.String())
+	}
+	if mismatches := report.Mismatches(); len(mismatches) > 0 {
+		return fmt.Errorf("%d of the %d table comparisons between %v and %v did not match", len(mismatches), len(report.Tables), report.SourceKeyspace, report.TargetKeyspace)
+	}
+	return nil
+}
+
 func commandApplySchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	sql := subFlags.String("sql", "", "A list of semicolon-delimited SQL commands")
 	sqlFile := subFlags.String("sql-file", "", "Identifies the file that contains the SQL commands")
@@ -2924,6 +4384,9 @@ func commandApplySchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 	requestContext := subFlags.String("request_context", "", "synonym for --migration_context")
 	waitReplicasTimeout := subFlags.Duration("wait_replicas_timeout", grpcvtctldserver.DefaultWaitReplicasTimeout, "The amount of time to wait for replicas to receive the schema change via replication.")
 	batchSize := subFlags.Int64("batch_size", 0, "How many queries to batch together")
+	respectThrottler := subFlags.Bool("respect_throttler", false, "Wait while the throttler of each primary throttles the schema change before applying each statement (or batch). Only applicable to the direct strategy.")
+	throttlerAppName := subFlags.String("throttler_app_name", "", "The app name to check the throttler as, with --respect_throttler. Defaults to \"direct-ddl\".")
+	maxThrottleWait := subFlags.Duration("max_throttle_wait", schematools.DefaultMaxThrottleWait, "The maximum total time to wait for the throttler of a primary, with --respect_throttler, before failing the schema change.")
 
 	callerID := subFlags.String("caller_id", "", "This is the effective caller ID used for the operation and should map to an ACL name which grants this identity the necessary permissions to perform the operation (this is only necessary when strict table ACLs are used)")
 	if err := subFlags.Parse(args); err != nil {
@@ -2967,6 +4430,9 @@ func commandApplySchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 		WaitReplicasTimeout: protoutil.DurationToProto(*waitReplicasTimeout),
 		CallerId:            cID,
 		BatchSize:           *batchSize,
+		RespectThrottler:    *respectThrottler,
+		ThrottlerAppName:    *throttlerAppName,
+		MaxThrottleWait:     protoutil.DurationToProto(*maxThrottleWait),
 	})
 
 	if err != nil {
@@ -2981,6 +4447,101 @@ func commandApplySchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 	return nil
 }
 
+func commandApplySchemaStaged(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	sql := subFlags.String("sql", "", "A list of semicolon-delimited SQL commands")
+	sqlFile := subFlags.String("sql-file", "", "Identifies the file that contains the SQL commands")
+	ddlStrategy := subFlags.String("ddl_strategy", string(schema.DDLStrategyDirect), "Online DDL strategy, compatible with @@ddl_strategy session variable (examples: 'direct', 'mysql', 'vitess --postpone-completion'")
+	waitReplicasTimeout := subFlags.Duration("wait_replicas_timeout", grpcvtctldserver.DefaultWaitReplicasTimeout, "The amount of time to wait for replicas to receive the schema change via replication.")
+	canaryShards := subFlags.StringSlice("canary-shards", nil, "Comma-separated shards to apply the change to first.")
+	canaryPercent := subFlags.Int("canary-percent", 0, "Percentage of the shards to apply the change to first, if --canary-shards is not set. Only the first shard is the canary by default.")
+	batchSize := subFlags.Int("batch-size", 0, "Number of shards of each stage after the canary one. Zero applies the change to all the other shards in one stage.")
+	soakTime := subFlags.Duration("soak-time", 5*time.Minute, "How long to check the health of the tablets of each stage before moving on.")
+	maxReplicationLag := subFlags.Duration("max-replication-lag", 30*time.Second, "Replication lag above which a tablet fails the check of its stage. Zero disables the check.")
+	autoPromote := subFlags.Bool("auto-promote", false, "Move on to the next stage once a stage passed its check, instead of pausing for --resume.")
+	resume := subFlags.String("resume", "", "ID of a paused rollout to resume.")
+	force := subFlags.Bool("force", false, "With --resume, also resume a rollout left running by an invocation that died.")
+	abort := subFlags.String("abort", "", "ID of a rollout to abort.")
+	status := subFlags.String("status", "", "ID of a rollout to show.")
+	format := subFlags.String("format", "text", "Format of the rollout") // "json" or "text"
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid --format %q, must be one of text, json", *format)
+	}
+
+	var (
+		rollout *wrangler.SchemaRollout
+		err     error
+	)
+	switch {
+	case *resume != "" || *abort != "" || *status != "":
+		if subFlags.NArg() != 0 || (*resume != "" && *abort != "") || (*resume != "" && *status != "") || (*abort != "" && *status != "") {
+			return fmt.Errorf("--resume, --abort and --status take a rollout ID and no other argument for the ApplySchemaStaged command")
+		}
+		switch {
+		case *resume != "":
+			rollout, err = wr.ResumeSchemaRollout(ctx, *resume, *force)
+		case *abort != "":
+			rollout, err = wr.AbortSchemaRollout(ctx, *abort)
+		default:
+			rollout, err = wr.GetSchemaRollout(ctx, *status)
+		}
+	default:
+		if subFlags.NArg() != 1 {
+			return fmt.Errorf("the <keyspace> argument is required for the ApplySchemaStaged command")
+		}
+		if len(*canaryShards) > 0 && *canaryPercent != 0 {
+			return fmt.Errorf("--canary-shards and --canary-percent cannot be used together")
+		}
+		change, err := getFileParam(*sql, *sqlFile, "sql")
+		if err != nil {
+			return err
+		}
+		parts, err := wr.SQLParser().SplitStatementToPieces(change)
+		if err != nil {
+			return err
+		}
+		rollout, err = wr.ApplySchemaStaged(ctx, subFlags.Arg(0), parts, wrangler.SchemaRolloutOptions{
+			DdlStrategy:         *ddlStrategy,
+			WaitReplicasTimeout: *waitReplicasTimeout,
+			CanaryShards:        *canaryShards,
+			CanaryPercent:       *canaryPercent,
+			BatchSize:           *batchSize,
+			SoakTime:            *soakTime,
+			MaxReplicationLag:   *maxReplicationLag,
+			AutoPromote:         *autoPromote,
+		})
+	}
+	if rollout == nil {
+		return err
+	}
+
+	if *format == "json" {
+		if printErr := printJSON(wr.Logger(), rollout); printErr != nil {
+			return printErr
+		}
+	} else {
+		var b strings.Builder
+		fmt.Fprintf(&b, "schema rollout %v of keyspace %v: %v\n", rollout.ID, rollout.Keyspace, rollout.State)
+		for i, stage := range rollout.Stages {
+			state := "pending"
+			switch {
+			case !stage.Soaked.IsZero():
+				state = "done"
+			case !stage.Applied.IsZero():
+				state = "applied"
+			}
+			fmt.Fprintf(&b, "stage %d (%v): %v\n", i+1, strings.Join(stage.Shards, ","), state)
+		}
+		if rollout.Error != "" {
+			fmt.Fprintf(&b, "error: %v\n", rollout.Error)
+		}
+		wr.Logger().Printf("%s", b.String())
+	}
+	return err
+}
+
 func generateOnlineDDLQuery(command string, arg string, allSupported bool) (string, error) {
 	// Accept inputs like so:
 	//  "launch", "all"
@@ -3098,6 +4659,9 @@ func commandOnlineDDL(ctx context.Context, wr *wrangler.Wrangler, subFlags *pfla
 	}
 
 	if applySchemaQuery != "" {
+		if err := wr.CheckWritable("OnlineDDL " + command); err != nil {
+			return err
+		}
 		log.Info("Calling ApplySchema on VtctldServer")
 
 		resp, err := wr.VtctldServer().ApplySchema(ctx, &vtctldatapb.ApplySchemaRequest{
@@ -3149,6 +4713,10 @@ func commandCopySchemaShard(ctx context.Context, wr *wrangler.Wrangler, subFlags
 	skipVerify := subFlags.Bool("skip-verify", false, "Skip verification of source and target schema after copy")
 	// for backwards compatibility
 	waitReplicasTimeout := subFlags.Duration("wait_replicas_timeout", grpcvtctldserver.DefaultWaitReplicasTimeout, "The amount of time to wait for replicas to receive the schema change via replication.")
+	respectThrottler := subFlags.Bool("respect_throttler", false, "Wait while the throttler of the destination primary throttles the copy before applying each statement.")
+	throttlerAppName := subFlags.String("throttler_app_name", "", "The app name to check the throttler as, with --respect_throttler. Defaults to \"direct-ddl\".")
+	maxThrottleWait := subFlags.Duration("max_throttle_wait", schematools.DefaultMaxThrottleWait, "The maximum total time to wait for the throttler of the destination primary, with --respect_throttler, before failing the copy.")
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -3168,19 +4736,24 @@ func commandCopySchemaShard(ctx context.Context, wr *wrangler.Wrangler, subFlags
 	if err != nil {
 		return err
 	}
+	var throttleWaiter *schematools.ThrottleWaiter
+	if *respectThrottler {
+		throttleWaiter = schematools.NewThrottleWaiter(wr.TabletManagerClient(), wr.Logger(), *throttlerAppName, *maxThrottleWait)
+	}
 
 	sourceKeyspace, sourceShard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
 	if err == nil {
-		return wr.CopySchemaShardFromShard(ctx, tableArray, excludeTableArray, *includeViews, sourceKeyspace, sourceShard, destKeyspace, destShard, *waitReplicasTimeout, *skipVerify)
+		return wr.CopySchemaShardFromShard(ctx, tableArray, excludeTableArray, *includeViews, sourceKeyspace, sourceShard, destKeyspace, destShard, *waitReplicasTimeout, *skipVerify, throttleWaiter)
 	}
-	sourceTabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	sourceTabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err == nil {
-		return wr.CopySchemaShard(ctx, sourceTabletAlias, tableArray, excludeTableArray, *includeViews, destKeyspace, destShard, *waitReplicasTimeout, *skipVerify)
+		return wr.CopySchemaShard(ctx, sourceTabletAlias, tableArray, excludeTableArray, *includeViews, destKeyspace, destShard, *waitReplicasTimeout, *skipVerify, throttleWaiter)
 	}
 	return err
 }
 
 func commandValidateVersionShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	includeNonServing := subFlags.Bool("include-non-serving", false, "Also validates the DRAINED, BACKUP and RESTORE tablets, which are skipped by default")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -3192,10 +4765,13 @@ func commandValidateVersionShard(ctx context.Context, wr *wrangler.Wrangler, sub
 	if err != nil {
 		return err
 	}
-	return wr.ValidateVersionShard(ctx, keyspace, shard)
+	return wr.ValidateVersionShard(ctx, keyspace, shard, *includeNonServing)
 }
 
 func commandValidateVersionKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	referenceShard := subFlags.String("reference-shard", "", "The shard whose primary has the reference version, defaults to the first serving shard")
+	includeNonServingShards := subFlags.Bool("include-non-serving-shards", false, "Also validates the shards that serve no traffic, such as the overlapping shards of a reshard, which are skipped by default")
+	includeNonServing := subFlags.Bool("include-non-serving", false, "Also validates the DRAINED, BACKUP and RESTORE tablets, which are skipped by default")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -3204,31 +4780,18 @@ func commandValidateVersionKeyspace(ctx context.Context, wr *wrangler.Wrangler,
 	}
 
 	keyspace := subFlags.Arg(0)
-	res, err := wr.VtctldServer().ValidateVersionKeyspace(ctx, &vtctldatapb.ValidateVersionKeyspaceRequest{Keyspace: keyspace})
-
-	if err != nil {
-		return err
-	}
-
-	for _, result := range res.Results {
-		wr.Logger().Printf("%s\n", result)
-	}
-
-	if len(res.Results) > 0 {
-		return fmt.Errorf("%s", res.Results[0])
-	}
-
-	return nil
+	return wr.ValidateVersionKeyspace(ctx, keyspace, *referenceShard, *includeNonServing, *includeNonServingShards)
 }
 
 func commandGetPermissions(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	tablets := newTabletResolver(wr, subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <tablet alias> argument is required for the GetPermissions command")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := tablets.resolve(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -3247,6 +4810,7 @@ func commandGetPermissions(ctx context.Context, wr *wrangler.Wrangler, subFlags
 }
 
 func commandValidatePermissionsShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	includeNonServing := subFlags.Bool("include-non-serving", false, "Also validates the DRAINED, BACKUP and RESTORE tablets, which are skipped by default")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -3258,10 +4822,14 @@ func commandValidatePermissionsShard(ctx context.Context, wr *wrangler.Wrangler,
 	if err != nil {
 		return err
 	}
-	return wr.ValidatePermissionsShard(ctx, keyspace, shard)
+	return wr.ValidatePermissionsShard(ctx, keyspace, shard, *includeNonServing)
 }
 
 func commandValidatePermissionsKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	referenceShard := subFlags.String("reference-shard", "", "The shard whose primary has the reference permissions, defaults to the first serving shard")
+	requireAllShards := subFlags.Bool("require-all-shards", false, "Fail the validation if a shard is skipped because it has no primary")
+	includeNonServingShards := subFlags.Bool("include-non-serving-shards", false, "Also validates the shards that serve no traffic, such as the overlapping shards of a reshard, which are skipped by default")
+	includeNonServing := subFlags.Bool("include-non-serving", false, "Also validates the DRAINED, BACKUP and RESTORE tablets, which are skipped by default")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -3270,7 +4838,7 @@ func commandValidatePermissionsKeyspace(ctx context.Context, wr *wrangler.Wrangl
 	}
 
 	keyspace := subFlags.Arg(0)
-	return wr.ValidatePermissionsKeyspace(ctx, keyspace)
+	return wr.ValidatePermissionsKeyspace(ctx, keyspace, *referenceShard, *requireAllShards, *includeNonServing, *includeNonServingShards)
 }
 
 func commandGetVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
@@ -3335,8 +4903,10 @@ func commandApplyVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 	vschemaFile := subFlags.String("vschema_file", "", "Identifies the VTGate routing schema file")
 	sql := subFlags.String("sql", "", "A vschema ddl SQL statement (e.g. `add vindex`, `alter table t add vindex hash(id)`, etc)")
 	sqlFile := subFlags.String("sql_file", "", "A vschema ddl SQL statement (e.g. `add vindex`, `alter table t add vindex hash(id)`, etc)")
-	dryRun := subFlags.Bool("dry-run", false, "If set, do not save the altered vschema, simply echo to console.")
+	dryRun := subFlags.Bool("dry-run", false, "If set, do not save the altered vschema, simply echo it and its changes to console.")
+	showDiff := subFlags.Bool("show_diff", false, "If set, also echo the changes to the vschema to console. Always set with --dry-run.")
 	skipRebuild := subFlags.Bool("skip_rebuild", false, "If set, do not rebuild the SrvSchema objects.")
+	retryOnConflict := subFlags.Bool("retry_on_conflict", false, "If another writer changes the vschema while it is being applied, apply the --sql or --sql_file change again to the updated vschema instead of failing.")
 	var cells []string
 	subFlags.StringSliceVar(&cells, "cells", cells, "If specified, limits the rebuild to the cells, after upload. Ignored if --skip_rebuild is set.")
 
@@ -3348,9 +4918,6 @@ func commandApplyVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 	}
 	keyspace := subFlags.Arg(0)
 
-	var ksvs *topo.KeyspaceVSchemaInfo
-	var err error
-
 	sqlMode := (*sql != "") != (*sqlFile != "")
 	jsonMode := (*vschema != "") != (*vschemaFile != "")
 
@@ -3362,6 +4929,17 @@ func commandApplyVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 		return fmt.Errorf("one of the --sql, --sql_file, --vschema, or --vschema_file flags must be specified when calling the ApplyVSchema command")
 	}
 
+	if *retryOnConflict && !sqlMode {
+		return fmt.Errorf("the --retry_on_conflict flag may only be specified with the --sql or --sql_file flags when calling the ApplyVSchema command")
+	}
+
+	req := &vtctldatapb.ApplyVSchemaRequest{
+		Keyspace:        keyspace,
+		SkipRebuild:     *skipRebuild,
+		Cells:           cells,
+		DryRun:          *dryRun,
+		RetryOnConflict: *retryOnConflict,
+	}
 	if sqlMode {
 		if *sqlFile != "" {
 			sqlBytes, err := os.ReadFile(*sqlFile)
@@ -3375,15 +4953,10 @@ func commandApplyVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 		if err != nil {
 			return fmt.Errorf("error parsing vschema statement `%s`: %v", *sql, err)
 		}
-		ddl, ok := stmt.(*sqlparser.AlterVschema)
-		if !ok {
+		if _, ok := stmt.(*sqlparser.AlterVschema); !ok {
 			return fmt.Errorf("error parsing vschema statement `%s`: not a ddl statement", *sql)
 		}
-
-		ksvs, err = topotools.ApplyVSchemaDDL(ctx, keyspace, wr.TopoServer(), ddl)
-		if err != nil {
-			return err
-		}
+		req.Sql = *sql
 	} else {
 		// json mode
 		var schema []byte
@@ -3397,41 +4970,39 @@ func commandApplyVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 			schema = []byte(*vschema)
 		}
 
-		ksvs = &topo.KeyspaceVSchemaInfo{
-			Name:     keyspace,
-			Keyspace: &vschemapb.Keyspace{},
-		}
-		err := json2.UnmarshalPB(schema, ksvs.Keyspace)
-		if err != nil {
+		req.VSchema = &vschemapb.Keyspace{}
+		if err := json2.UnmarshalPB(schema, req.VSchema); err != nil {
 			return err
 		}
 	}
 
-	b, err := json2.MarshalIndentPB(ksvs.Keyspace, "  ")
+	// The VSchema is saved under the keyspace lock, and only if no other
+	// writer changed it since it was read to apply the change.
+	resp, err := wr.VtctldServer().ApplyVSchema(ctx, req)
 	if err != nil {
-		wr.Logger().Errorf2(err, "Failed to marshal VSchema for display")
-	} else {
-		wr.Logger().Printf("New VSchema object:\n%s\nIf this is not what you expected, check the input data (as JSON parsing will skip unexpected fields).\n", b)
+		return err
+	}
+
+	if *dryRun || *showDiff {
+		logVSchemaDiff(wr.Logger(), keyspace, resp.Diff)
 	}
 
-	// Validate the VSchema.
-	ksVs, err := vindexes.BuildKeyspace(ksvs.Keyspace, wr.SQLParser())
+	b, err := json2.MarshalIndentPB(resp.VSchema, "  ")
 	if err != nil {
-		return err
+		wr.Logger().Errorf2(err, "Failed to marshal VSchema for display")
+	} else {
+		wr.Logger().Printf("New VSchema object:\n%s\nIf this is not what you expected, check the input data (as JSON parsing will skip unexpected fields).\n", b)
 	}
 
 	// Log unknown Vindex params as warnings.
 	var vdxNames []string
-	for name := range ksVs.Vindexes {
+	for name := range resp.UnknownVindexParams {
 		vdxNames = append(vdxNames, name)
 	}
 	sort.Strings(vdxNames)
 	for _, name := range vdxNames {
-		vdx := ksVs.Vindexes[name]
-		if val, ok := vdx.(vindexes.ParamValidating); ok {
-			for _, param := range val.UnknownParams() {
-				wr.Logger().Warningf("Unknown parameter in vindex %s: %s", name, param)
-			}
+		for _, param := range resp.UnknownVindexParams[name].Params {
+			wr.Logger().Warningf("Unknown parameter in vindex %s: %s", name, param)
 		}
 	}
 
@@ -3440,26 +5011,23 @@ func commandApplyVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 		return nil
 	}
 
-	if _, err := wr.TopoServer().GetKeyspace(ctx, keyspace); err != nil {
-		if strings.Contains(err.Error(), "node doesn't exist") {
-			return fmt.Errorf("keyspace(%s) doesn't exist, check if the keyspace is initialized", keyspace)
-		}
-		return err
-	}
-
-	if _, err := vindexes.BuildKeyspace(ksvs.Keyspace, wr.SQLParser()); err != nil {
-		return err
+	if *skipRebuild {
+		wr.Logger().Warningf("Skipping rebuild of SrvVSchema, will need to run RebuildVSchemaGraph for changes to take effect")
 	}
+	return nil
+}
 
-	if err := wr.TopoServer().SaveVSchema(ctx, ksvs); err != nil {
-		return err
+// logVSchemaDiff logs the changes to the VSchema of the keyspace.
+func logVSchemaDiff(logger logutil.Logger, keyspace string, diff []string) {
+	if len(diff) == 0 {
+		logger.Printf("No changes to the VSchema of keyspace %s.\n", keyspace)
+		return
 	}
 
-	if *skipRebuild {
-		wr.Logger().Warningf("Skipping rebuild of SrvVSchema, will need to run RebuildVSchemaGraph for changes to take effect")
-		return nil
+	logger.Printf("Changes to the VSchema of keyspace %s:\n", keyspace)
+	for _, change := range diff {
+		logger.Printf("  %s\n", change)
 	}
-	return wr.TopoServer().RebuildSrvVSchema(ctx, cells)
 }
 
 func commandApplyRoutingRules(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
@@ -3568,6 +5136,7 @@ func commandGetSrvKeyspaceNames(ctx context.Context, wr *wrangler.Wrangler, subF
 }
 
 func commandGetSrvKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	visualize := subFlags.Bool("visualize", false, "Outputs the key range segments of each served tablet type and the shards serving them, flagging gaps and overlaps, instead of the SrvKeyspace")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -3590,6 +5159,10 @@ func commandGetSrvKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags
 	if cellKs == nil {
 		return fmt.Errorf("missing keyspace %q in cell %q", keyspace, cell)
 	}
+	if *visualize {
+		wr.Logger().Printf("%s", topotools.FormatSrvKeyspacePartitions(cellKs))
+		return nil
+	}
 	return printJSON(wr.Logger(), cellKs)
 }
 
@@ -3603,6 +5176,7 @@ func commandUpdateThrottlerConfig(ctx context.Context, wr *wrangler.Wrangler, su
 	throttledAppRatio := subFlags.Float64("throttle-app-ratio", throttle.DefaultThrottleRatio, "ratio to throttle app (app specififed in --throttled-app)")
 	throttledAppDuration := subFlags.Duration("throttle-app-duration", throttle.DefaultAppThrottleDuration, "duration after which throttled app rule expires (app specified in --throttled-app)")
 	throttledAppExempt := subFlags.Bool("throttle-app-exempt", false, "exempt this app from being at all throttled. WARNING: use with extreme care, as this is likely to push metrics beyond the throttler's threshold, and starve other apps (app specified in --throttled-app)")
+	waitForAcknowledgement := subFlags.Duration("wait-for-acknowledgement", 0, "if set, how long to wait for the tablets in the keyspace to apply the updated configuration, reporting which tablets did")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -3636,6 +5210,8 @@ func commandUpdateThrottlerConfig(ctx context.Context, wr *wrangler.Wrangler, su
 		CustomQuery:    *customQuery,
 		CustomQuerySet: customQuerySet,
 		Threshold:      *threshold,
+
+		WaitForAcknowledgement: protoutil.DurationToProto(*waitForAcknowledgement),
 	}
 	if *throttledApp != "" {
 		req.ThrottledApp = &topodatapb.ThrottledAppRule{
@@ -3651,11 +5227,32 @@ func commandUpdateThrottlerConfig(ctx context.Context, wr *wrangler.Wrangler, su
 			ExpiresAt: &vttime.Time{}, // zero
 		}
 	}
-	_, err = wr.VtctldServer().UpdateThrottlerConfig(ctx, req)
-	return err
+	resp, err := wr.VtctldServer().UpdateThrottlerConfig(ctx, req)
+	if err != nil || *waitForAcknowledgement == 0 {
+		return err
+	}
+	return printJSON(wr.Logger(), resp)
+}
+
+func commandGetThrottlerConfig(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace> argument is required for the GetThrottlerConfig command")
+	}
+
+	resp, err := wr.VtctldServer().GetThrottlerConfig(ctx, &vtctldatapb.GetThrottlerConfigRequest{
+		Keyspace: subFlags.Arg(0),
+	})
+	if err != nil {
+		return err
+	}
+	return printJSON(wr.Logger(), resp)
 }
 
 func commandGetSrvVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	diffAgainstCell := subFlags.String("diff-against-cell", "", "Show the differences between the SrvVSchema of the cell and the one of this cell, instead of the SrvVSchema")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -3663,14 +5260,32 @@ func commandGetSrvVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *
 		return fmt.Errorf("the <cell> argument is required for the GetSrvVSchema command")
 	}
 
-	srvVSchema, err := wr.TopoServer().GetSrvVSchema(ctx, subFlags.Arg(0))
+	cell := subFlags.Arg(0)
+	srvVSchema, err := wr.TopoServer().GetSrvVSchema(ctx, cell)
+	if err != nil {
+		return err
+	}
+	if *diffAgainstCell == "" {
+		return printJSON(wr.Logger(), srvVSchema)
+	}
+
+	otherSrvVSchema, err := wr.TopoServer().GetSrvVSchema(ctx, *diffAgainstCell)
 	if err != nil {
 		return err
 	}
-	return printJSON(wr.Logger(), srvVSchema)
+	diffs := topotools.DiffSrvVSchemas(cell, srvVSchema, *diffAgainstCell, otherSrvVSchema)
+	if len(diffs) == 0 {
+		wr.Logger().Printf("The SrvVSchemas in cells %s and %s are identical.\n", cell, *diffAgainstCell)
+		return nil
+	}
+	for _, diff := range diffs {
+		wr.Logger().Printf("%s\n", diff)
+	}
+	return nil
 }
 
 func commandDeleteSrvVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	force := subFlags.Bool("force", false, "Delete the SrvVSchema. Without it, only reports what the SrvVSchema covers")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -3678,10 +5293,21 @@ func commandDeleteSrvVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlag
 		return fmt.Errorf("the <cell> argument is required for the DeleteSrvVSchema command")
 	}
 
-	_, err := wr.VtctldServer().DeleteSrvVSchema(ctx, &vtctldatapb.DeleteSrvVSchemaRequest{
-		Cell: subFlags.Arg(0),
+	cell := subFlags.Arg(0)
+	resp, err := wr.VtctldServer().DeleteSrvVSchema(ctx, &vtctldatapb.DeleteSrvVSchemaRequest{
+		Cell:   cell,
+		DryRun: !*force,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+
+	wr.Logger().Printf("The SrvVSchema in cell %s covers %d keyspace(s): %s\n", cell, len(resp.Keyspaces), strings.Join(resp.Keyspaces, ", "))
+	wr.Logger().Printf("%d keyspace(s) are served in cell %s: %s\n", len(resp.ServingKeyspaces), cell, strings.Join(resp.ServingKeyspaces, ", "))
+	if !resp.Deleted {
+		return fmt.Errorf("not deleting the SrvVSchema in cell %s without --force", cell)
+	}
+	return nil
 }
 
 func commandGetShardReplication(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
@@ -3722,12 +5348,13 @@ func commandHelp(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.Fla
 }
 
 func commandWorkflow(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
-	usage := "usage: Workflow [--shards <shards>] [--dry-run] [--cells] [--tablet-types] <keyspace>[.<workflow>] start/stop/update/delete/show/listall/tags [<tags>]"
+	usage := "usage: Workflow [--shards <shards>] [--dry-run] [--cells] [--tablet-types] [--include-logs] <keyspace>[.<workflow>] start/stop/update/delete/show/clearlogs/listall/tags [<tags>]"
 	dryRun := subFlags.Bool("dry-run", false, "Does a dry run of the Workflow action and reports the query and list of tablets on which the operation will be applied")
 	shards := subFlags.StringSlice("shards", nil, "(Optional) Specifies a comma-separated list of shards to operate on.")
 	cells := subFlags.StringSlice("cells", []string{}, "New Cell(s) or CellAlias(es) (comma-separated) to replicate from. (Update only)")
 	tabletTypesStrs := subFlags.StringSlice("tablet-types", []string{}, "New source tablet types to replicate from (e.g. PRIMARY, REPLICA, RDONLY). (Update only)")
 	onDDL := subFlags.String("on-ddl", "", "New instruction on what to do when DDL is encountered in the VReplication stream. Possible values are IGNORE, STOP, EXEC, and EXEC_IGNORE. (Update only)")
+	includeLogs := subFlags.Bool("include-logs", false, "Includes the retained history of state transitions and messages of each stream, with their timestamps. (Show only)")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -3739,6 +5366,11 @@ func commandWorkflow(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag
 	}
 	keyspace := subFlags.Arg(0)
 	action := strings.ToLower(subFlags.Arg(1))
+	if action != "show" && action != "listall" {
+		if err := wr.CheckWritable("Workflow " + action); err != nil {
+			return err
+		}
+	}
 	var workflow string
 	var err error
 	if action != "listall" {
@@ -3770,6 +5402,9 @@ func commandWorkflow(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag
 			return errors.New(usage)
 		}
 		var rpcReq any = nil
+		if action == "show" {
+			rpcReq = &wrangler.WorkflowShowOptions{IncludeLogs: *includeLogs}
+		}
 		if action == "update" {
 			changes := false
 			// We need to implicitly distinguish between an empty value (which is valid)
@@ -3903,16 +5538,42 @@ func commandMount(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.Fl
 
 func commandGenerateShardRanges(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	numShards := subFlags.Int("num_shards", 2, "Number of shards to generate shard ranges for.")
+	keyWidth := subFlags.Int("key_width", 0, "Width of the shard range boundaries, in bytes. Defaults to 1 for up to 256 shards and 2 for more.")
+	allowUneven := subFlags.Bool("allow_uneven", false, "Allow a shard count that is not a power of two, spreading the remainder of the key space over the shards.")
+	format := subFlags.String("format", "json", "Output format to use; valid choices are (json, text).")
+	create := subFlags.String("create", "", "Also create the shards in this keyspace, which must already exist.")
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
+	if *format != "json" && *format != "text" {
+		return fmt.Errorf("invalid --format %q, must be one of json, text", *format)
+	}
 
-	shardRanges, err := key.GenerateShardRanges(*numShards)
+	shardRanges, err := key.GenerateShardRangesWithWidth(*numShards, *keyWidth, *allowUneven)
 	if err != nil {
 		return err
 	}
 
+	if *create != "" {
+		if err := wr.CheckWritable("GenerateShardRanges --create"); err != nil {
+			return err
+		}
+		for _, shard := range shardRanges {
+			if _, err := wr.VtctldServer().CreateShard(ctx, &vtctldatapb.CreateShardRequest{
+				Keyspace:  *create,
+				ShardName: shard,
+			}); err != nil {
+				return fmt.Errorf("failed to create shard %s/%s: %w", *create, shard, err)
+			}
+		}
+		wr.Logger().Infof("Created %d shards in keyspace %s.", len(shardRanges), *create)
+	}
+
+	if *format == "text" {
+		wr.Logger().Printf("%s\n", strings.Join(shardRanges, ","))
+		return nil
+	}
 	return printJSON(wr.Logger(), shardRanges)
 }
 
@@ -3920,6 +5581,31 @@ func commandPanic(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.Fl
 	panic(fmt.Errorf("this command panics on purpose"))
 }
 
+func commandGetCompletionData(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	cell := subFlags.String("cell", "", "Cell whose SrvKeyspaces the shard names are read from. Defaults to the first cell.")
+	maxEntries := subFlags.Int("max-entries", 10000, "Maximum number of names to output. Zero means no limit.")
+	workflows := subFlags.Bool("workflows", false, "Also lists the workflows of each keyspace, which queries the primary tablets.")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 0 {
+		return fmt.Errorf("the GetCompletionData command takes no arguments")
+	}
+	if *maxEntries < 0 {
+		return fmt.Errorf("--max-entries must not be negative")
+	}
+
+	data, err := wr.CompletionData(ctx, wrangler.CompletionOptions{
+		Cell:       *cell,
+		MaxEntries: *maxEntries,
+		Workflows:  *workflows,
+	})
+	if err != nil {
+		return err
+	}
+	return printJSON(wr.Logger(), data)
+}
+
 // printJSON will print the JSON version of the structure to the logger.
 func printJSON(logger logutil.Logger, val any) error {
 	data, err := MarshalJSON(val)
@@ -3946,6 +5632,58 @@ func printQueryResult(writer io.Writer, qr *sqltypes.Result) {
 	cli.WriteQueryResultTable(writer, qr)
 }
 
+// queryResultFormatFlags are the flags of the commands that can print a
+// QueryResult as a table, JSON or CSV.
+type queryResultFormatFlags struct {
+	json      *bool
+	format    *string
+	csvHeader *bool
+	csvNull   *string
+	csvBinary *string
+
+	csvOptions sqltypes.CSVOptions
+}
+
+func addQueryResultFormatFlags(subFlags *pflag.FlagSet) *queryResultFormatFlags {
+	return &queryResultFormatFlags{
+		json:      subFlags.Bool("json", false, "Output JSON instead of human-readable table. Same as --format=json"),
+		format:    subFlags.String("format", "text", "Format of the result: text, json or csv"),
+		csvHeader: subFlags.Bool("csv_header", true, "With --format=csv, writes the column names as the first row"),
+		csvNull:   subFlags.String("csv_null", "", "With --format=csv, the value written for NULL"),
+		csvBinary: subFlags.String("csv_binary", "base64", "With --format=csv, the encoding of binary columns: base64 or hex"),
+	}
+}
+
+// validate checks the flags once they are parsed.
+func (f *queryResultFormatFlags) validate() error {
+	if *f.json {
+		*f.format = "json"
+	}
+	switch *f.format {
+	case "text", "json":
+	case "csv":
+		binary, err := sqltypes.ParseCSVBinaryEncoding(*f.csvBinary)
+		if err != nil {
+			return err
+		}
+		f.csvOptions = sqltypes.CSVOptions{Header: *f.csvHeader, Null: *f.csvNull, Binary: binary}
+	default:
+		return fmt.Errorf("invalid --format %q, expected text, json or csv", *f.format)
+	}
+	return nil
+}
+
+func (f *queryResultFormatFlags) print(logger logutil.Logger, qr *sqltypes.Result) error {
+	switch *f.format {
+	case "json":
+		return printJSON(logger, qr)
+	case "csv":
+		return sqltypes.NewCSVEncoder(loggerWriter{logger}, f.csvOptions).WriteResult(qr)
+	}
+	printQueryResult(loggerWriter{logger}, qr)
+	return nil
+}
+
 // MarshalJSON marshals "obj" to a JSON string. It uses the "jsonpb" marshaler
 // or Go's standard one.
 //
@@ -3986,6 +5724,13 @@ func MarshalJSON(obj any) (data []byte, err error) {
 		if err != nil {
 			return nil, fmt.Errorf("json error: %v", err)
 		}
+	case *sqltypes.Result:
+		// Query results use the canonical JSON of their values, which
+		// keeps the types and the binary data intact.
+		data, err = json.MarshalIndent((*sqltypes.JSONResult)(obj), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("json error: %v", err)
+		}
 	default:
 		data, err = json.MarshalIndent(obj, "", "  ")
 		if err != nil {
@@ -4007,51 +5752,112 @@ func RunCommand(ctx context.Context, wr *wrangler.Wrangler, args []string) error
 	}
 
 	action := args[0]
-	actionLowerCase := strings.ToLower(action)
-	for _, group := range commands {
-		for _, cmd := range group.commands {
-			if strings.ToLower(cmd.name) == actionLowerCase {
-				subFlags := pflag.NewFlagSet(action, pflag.ContinueOnError)
-				subFlags.SetOutput(logutil.NewLoggerWriter(wr.Logger()))
-				subFlags.Usage = func() {
-					if cmd.deprecated {
-						msg := &strings.Builder{}
-						msg.WriteString("WARNING: ")
-						msg.WriteString(action)
-						msg.WriteString(" is deprecated and will be removed in a future release.")
-						if cmd.deprecatedBy != "" {
-							msg.WriteString(" Use ")
-							msg.WriteString(cmd.deprecatedBy)
-							msg.WriteString(" instead.")
-						}
-
-						wr.Logger().Printf("%s\n", msg.String())
-					}
+	cmd, deprecated, replacement, ok := findCommand(action)
+	if !ok {
+		return ErrUnknownCommand
+	}
+	if deprecated {
+		if err := warnDeprecatedCommand(wr, action, replacement); err != nil {
+			return err
+		}
+	}
 
-					wr.Logger().Printf("Usage: %s %s\n\n", action, cmd.params)
-					wr.Logger().Printf("%s\n\n", cmd.help)
+	subFlags := pflag.NewFlagSet(action, pflag.ContinueOnError)
+	subFlags.SetOutput(logutil.NewLoggerWriter(wr.Logger()))
+	subFlags.Usage = func() {
+		wr.Logger().Printf("Usage: %s %s\n\n", action, cmd.params)
+		if len(cmd.aliases) > 0 {
+			wr.Logger().Printf("Aliases: %s\n\n", strings.Join(cmd.aliases, ", "))
+		}
+		wr.Logger().Printf("%s\n\n", cmd.help)
 
-					wr.Logger().Printf("%s\n", subFlags.FlagUsages())
-				}
+		wr.Logger().Printf("%s\n", subFlags.FlagUsages())
+	}
 
-				if len(args) > 1 && args[1] == "--" && !cmd.disableFlagInterspersal {
-					PrintDoubleDashDeprecationNotice(wr)
-					args = args[1:]
-				}
+	if len(args) > 1 && args[1] == "--" && !cmd.disableFlagInterspersal {
+		PrintDoubleDashDeprecationNotice(wr)
+		args = args[1:]
+	}
 
-				switch err := cmd.method(ctx, wr, subFlags, args[1:]); err {
-				case pflag.ErrHelp:
-					// Don't actually error if the user requested --help on a
-					// subcommand.
-					return nil
-				default:
-					return err
+	if err := checkCommandWritable(wr, cmd); err != nil {
+		return err
+	}
+
+	span, ctx := trace.NewSpan(ctx, "vtctl."+cmd.name)
+	defer span.Finish()
+	// The timeouts are derived once, from the time the whole command has.
+	timeouts := wr.Timeouts(ctx)
+	wr = wr.WithTimeouts(timeouts)
+	if verbose {
+		wr.Logger().Printf("Timeouts: %v\n", timeouts)
+	}
+	if cmd.cacheTopoReads {
+		wr = wr.WithTopoReadCache()
+	}
+	var err error
+	done := commandStarted(cmd.name)
+	defer func() { done(err) }()
+	err = cmd.method(ctx, wr, subFlags, args[1:])
+	annotateCommandSpan(span, &cmd, subFlags)
+	if err != nil && err != pflag.ErrHelp {
+		span.Annotate("error", err.Error())
+	}
+
+	switch err {
+	case pflag.ErrHelp:
+		// Don't actually error if the user requested --help on a
+		// subcommand.
+		return nil
+	default:
+		return err
+	}
+}
+
+// findCommand returns the command that action, case-insensitively, is the
+// name or an alias of. If action is deprecated, either because the command
+// is or because it is a deprecated alias, deprecated is set and replacement
+// is what to use instead, if anything.
+func findCommand(action string) (cmd command, deprecated bool, replacement string, ok bool) {
+	for _, group := range commands {
+		for _, cmd := range group.commands {
+			if strings.EqualFold(cmd.name, action) {
+				return cmd, cmd.deprecated, cmd.deprecatedBy, true
+			}
+			for _, alias := range cmd.aliases {
+				if strings.EqualFold(alias, action) {
+					return cmd, cmd.deprecated, cmd.deprecatedBy, true
+				}
+			}
+			for _, alias := range cmd.deprecatedAliases {
+				if strings.EqualFold(alias, action) {
+					return cmd, true, cmd.name, true
 				}
 			}
 		}
 	}
+	return command{}, false, "", false
+}
 
-	return ErrUnknownCommand
+// warnDeprecatedCommand emits the deprecation warning of the command invoked
+// as action, as a WARNING event, and counts the invocation. With
+// --fail-on-deprecated, the warning is returned as an error instead, and
+// the command doesn't run.
+func warnDeprecatedCommand(wr *wrangler.Wrangler, action, replacement string) error {
+	deprecatedCommands.Add(action, 1)
+	msg := &strings.Builder{}
+	msg.WriteString("DEPRECATED: ")
+	msg.WriteString(action)
+	msg.WriteString(" is deprecated and will be removed in a future release.")
+	if replacement != "" {
+		msg.WriteString(" Use ")
+		msg.WriteString(replacement)
+		msg.WriteString(" instead.")
+	}
+	if failOnDeprecated {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "%s (--fail-on-deprecated is set)", msg.String())
+	}
+	wr.Logger().Warningf("%s", msg.String())
+	return nil
 }
 
 func PrintDoubleDashDeprecationNotice(wr *wrangler.Wrangler) {
@@ -4085,6 +5891,16 @@ func PrintAllCommands(logger logutil.Logger) {
 			msg.WriteString(cmd.name)
 			msg.WriteString(" ")
 			msg.WriteString(cmd.params)
+			if len(cmd.aliases) > 0 {
+				msg.WriteString(" (aliases: ")
+				msg.WriteString(strings.Join(cmd.aliases, ", "))
+				msg.WriteString(")")
+			}
+			if len(cmd.deprecatedAliases) > 0 {
+				msg.WriteString(" (deprecated aliases: ")
+				msg.WriteString(strings.Join(cmd.deprecatedAliases, ", "))
+				msg.WriteString(")")
+			}
 			logger.Printf("%s\n", msg.String())
 
 			msg.Reset()
@@ -19,10 +19,13 @@ package vtctl
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/grpcclient"
+	hk "vitess.io/vitess/go/vt/hook"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
@@ -80,6 +83,7 @@ func newTestVTCtlEnv(ctx context.Context) *testVTCtlEnv {
 		cmdlog:     logutil.NewMemoryLogger(),
 	}
 	env.wr = wrangler.NewTestWrangler(env.cmdlog, env.topoServ, env.tmc)
+	vtctlEnv = env
 	return env
 }
 
@@ -170,12 +174,16 @@ type testVTCtlTMClient struct {
 	tmclient.TabletManagerClient
 	vrQueries  map[int]map[string]*querypb.QueryResult
 	dbaQueries map[int]map[string]*querypb.QueryResult
+
+	mu          sync.Mutex
+	changeTypes map[int]topodatapb.TabletType
 }
 
 func newTestVTCtlTMClient() *testVTCtlTMClient {
 	return &testVTCtlTMClient{
-		vrQueries:  make(map[int]map[string]*querypb.QueryResult),
-		dbaQueries: make(map[int]map[string]*querypb.QueryResult),
+		vrQueries:   make(map[int]map[string]*querypb.QueryResult),
+		dbaQueries:  make(map[int]map[string]*querypb.QueryResult),
+		changeTypes: make(map[int]topodatapb.TabletType),
 	}
 }
 
@@ -213,6 +221,36 @@ func (tmc *testVTCtlTMClient) ExecuteFetchAsDba(ctx context.Context, tablet *top
 	return result, nil
 }
 
+// ChangeType records the requested type instead of changing the tablet.
+func (tmc *testVTCtlTMClient) ChangeType(ctx context.Context, tablet *topodatapb.Tablet, tabletType topodatapb.TabletType, semiSync bool) error {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	tmc.changeTypes[int(tablet.Alias.Uid)] = tabletType
+	return nil
+}
+
+// ExecuteHook echoes the hook's parameters and its GREETING env var, and
+// exits with the status in its EXIT_STATUS env var. The "sleep" hook runs
+// until the context is done.
+func (tmc *testVTCtlTMClient) ExecuteHook(ctx context.Context, tablet *topodatapb.Tablet, hook *hk.Hook) (*hk.HookResult, error) {
+	if hook.Name == "sleep" {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	hr := &hk.HookResult{
+		Stdout: strings.Join(hook.Parameters, " ") + "\n",
+		Stderr: hook.ExtraEnv["GREETING"] + "\n",
+	}
+	if status, ok := hook.ExtraEnv["EXIT_STATUS"]; ok {
+		exitStatus, err := strconv.Atoi(status)
+		if err != nil {
+			return nil, err
+		}
+		hr.ExitStatus = exitStatus
+	}
+	return hr, nil
+}
+
 func (tmc *testVTCtlTMClient) clearResults() {
 	tmc.vrQueries = make(map[int]map[string]*querypb.QueryResult)
 	tmc.dbaQueries = make(map[int]map[string]*querypb.QueryResult)
@@ -20,6 +20,7 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"path"
 	"regexp"
 	"strings"
 	"testing"
@@ -30,8 +31,17 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/logutil"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/wrangler"
 )
 
@@ -65,6 +75,11 @@ func TestApplyVSchema(t *testing.T) {
 			args: []string{"--vschema", "{}", ks},
 			want: "New VSchema object:\n{}\nIf this is not what you expected, check the input data (as JSON parsing will skip unexpected fields).\n\n",
 		},
+		{
+			name: "EmptyVSchemaShowDiff",
+			args: []string{"--vschema", "{}", "--show_diff", ks},
+			want: "No changes to the VSchema of keyspace ks.\n\nNew VSchema object:\n{}\nIf this is not what you expected, check the input data (as JSON parsing will skip unexpected fields).\n\n",
+		},
 		{
 			name: "UnknownParamsLogged",
 			args: []string{"--vschema", unknownParamsLoggedVSchema, ks},
@@ -139,6 +154,71 @@ Dry run: Skipping update of VSchema`,
 	}
 }
 
+// TestApplyVSchemaConflict tests that ApplyVSchema doesn't overwrite a
+// VSchema that another writer saved while it was being applied.
+func TestApplyVSchemaConflict(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts, factory := memorytopo.NewServerAndFactory(ctx, "cell1")
+	defer ts.Close()
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{Name: "ks", Keyspace: &vschemapb.Keyspace{}}))
+	wr := wrangler.NewTestWrangler(logutil.NewMemoryLogger(), ts, nil)
+
+	// Hold the keyspace lock, so that the command reads the VSchema and
+	// then waits for the lock.
+	_, unlock, err := ts.LockKeyspace(ctx, "ks", "TestApplyVSchemaConflict")
+	require.NoError(t, err)
+	locks := func() int64 {
+		counts := factory.GetCallStats().Counts()
+		return counts["Lock"] + counts["LockWithTTL"]
+	}
+	lockCalls := locks()
+
+	errCh := make(chan error, 1)
+	go func() {
+		subFlags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		errCh <- commandApplyVSchema(ctx, wr, subFlags, []string{"--sql", "alter vschema create vindex v1 using hash", "--skip_rebuild", "ks"})
+	}()
+	require.Eventually(t, func() bool { return locks() > lockCalls }, 10*time.Second, time.Millisecond)
+
+	// Another writer changes the VSchema in the meantime.
+	other := &vschemapb.Keyspace{Vindexes: map[string]*vschemapb.Vindex{"v2": {Type: "xxhash"}}}
+	require.NoError(t, ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{Name: "ks", Keyspace: other}))
+	unlock(&err)
+	require.NoError(t, err)
+
+	err = <-errCh
+	require.Error(t, err)
+	assert.Equal(t, vtrpcpb.Code_ABORTED, vterrors.Code(err))
+
+	// The other writer's VSchema is kept.
+	vs, err := ts.GetVSchema(ctx, "ks")
+	require.NoError(t, err)
+	utils.MustMatch(t, other, vs.Keyspace)
+}
+
+// TestGenerateShardRanges tests that GenerateShardRanges prints the shard
+// ranges, and creates them with --create.
+func TestGenerateShardRanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	logger := logutil.NewMemoryLogger()
+	wr := wrangler.NewTestWrangler(logger, ts, nil)
+
+	subFlags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	err := commandGenerateShardRanges(ctx, wr, subFlags, []string{"--num_shards", "4", "--format", "text", "--create", "ks"})
+	require.NoError(t, err)
+	assert.Contains(t, logger.String(), "-40,40-80,80-c0,c0-\n")
+
+	shards, err := ts.GetShardNames(ctx, "ks")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"-40", "40-80", "80-c0", "c0-"}, shards)
+}
+
 // TestMoveTables tests the MoveTables client command
 // via the commandVReplicationWorkflow() cmd handler.
 // This currently only tests the Progress action (which is
@@ -484,3 +564,376 @@ func TestGenerateOnlineDDLQuery(t *testing.T) {
 		})
 	}
 }
+
+// TestDeleteTabletForceCorrupt tests the DeleteTablet client command
+// with --force-corrupt via the commandDeleteTablet() cmd handler.
+func TestDeleteTabletForceCorrupt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestVTCtlEnv(ctx)
+	defer env.close()
+	primary := env.addTablet(100, "ks", "0", &topodatapb.KeyRange{}, topodatapb.TabletType_PRIMARY)
+	replica := env.addTablet(101, "ks", "0", &topodatapb.KeyRange{}, topodatapb.TabletType_REPLICA)
+
+	// Overwrite the replica's record with a truncated field.
+	conn, err := env.topoServ.ConnForCell(ctx, env.cell)
+	require.NoError(t, err)
+	tabletPath := path.Join(topo.TabletsPath, topoproto.TabletAliasString(replica.tablet.Alias), topo.TabletFile)
+	_, err = conn.Update(ctx, tabletPath, []byte{0x0a, 0x10}, nil)
+	require.NoError(t, err)
+
+	deleteTablet := func(args ...string) error {
+		subFlags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		return commandDeleteTablet(ctx, env.wr, subFlags, args)
+	}
+
+	err = deleteTablet("cell1-101")
+	require.Error(t, err)
+	err = deleteTablet("--force-corrupt", "cell1-101")
+	require.ErrorContains(t, err, "keyspace and shard are required")
+	err = deleteTablet("--force-corrupt", "--keyspace", "ks", "--shard", "1", "cell1-101")
+	require.ErrorContains(t, err, "cannot read shard ks/1")
+
+	// A readable record still goes through the regular DeleteTablet checks.
+	err = deleteTablet("--force-corrupt", "--keyspace", "ks", "--shard", "0", "cell1-100")
+	require.ErrorContains(t, err, "as it is a primary")
+	_, err = env.topoServ.GetTablet(ctx, primary.tablet.Alias)
+	require.NoError(t, err)
+
+	err = deleteTablet("--force-corrupt", "--keyspace", "ks", "--shard", "0", "cell1-101")
+	require.NoError(t, err)
+	_, err = env.topoServ.GetTablet(ctx, replica.tablet.Alias)
+	require.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+	sri, err := env.topoServ.GetShardReplication(ctx, env.cell, "ks", "0")
+	require.NoError(t, err)
+	_, err = sri.GetShardReplicationNode(replica.tablet.Alias)
+	require.True(t, topo.IsErrType(err, topo.NoNode), "expected %v to be gone from the replication graph, got %v", replica.tablet.Alias, err)
+}
+
+// TestChangeTabletType tests the --dry-run and --min-remaining flags of the
+// ChangeTabletType client command via the commandChangeTabletType() cmd handler.
+func TestChangeTabletType(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestVTCtlEnv(ctx)
+	defer env.close()
+	_ = env.addTablet(100, "ks", "0", &topodatapb.KeyRange{}, topodatapb.TabletType_PRIMARY)
+	_ = env.addTablet(101, "ks", "0", &topodatapb.KeyRange{}, topodatapb.TabletType_REPLICA)
+	_ = env.addTablet(102, "ks", "0", &topodatapb.KeyRange{}, topodatapb.TabletType_REPLICA)
+
+	tests := []struct {
+		name           string
+		args           []string
+		want           string
+		wantErr        string
+		wantChangeType topodatapb.TabletType
+	}{
+		{
+			name: "DryRun",
+			args: []string{"--dry-run", "--min-remaining", "1", "cell1-101", "rdonly"},
+			want: `{
+  "TabletAlias": "cell1-0000000101",
+  "Keyspace": "ks",
+  "Shard": "0",
+  "CurrentType": "replica",
+  "RequestedType": "rdonly",
+  "Allowed": true,
+  "RemainingServing": 1,
+  "MinRemaining": 1
+}
+
+`,
+		},
+		{
+			name: "DryRunBelowMinRemaining",
+			args: []string{"--dry-run", "--min-remaining", "2", "cell1-101", "rdonly"},
+			want: `{
+  "TabletAlias": "cell1-0000000101",
+  "Keyspace": "ks",
+  "Shard": "0",
+  "CurrentType": "replica",
+  "RequestedType": "rdonly",
+  "Allowed": false,
+  "Reason": "only 1 serving replica tablets would remain in cell cell1 for ks/0, fewer than the minimum of 2",
+  "RemainingServing": 1,
+  "MinRemaining": 2
+}
+
+`,
+			wantErr: "fewer than the minimum of 2",
+		},
+		{
+			name:    "DryRunPrimary",
+			args:    []string{"--dry-run", "cell1-100", "replica"},
+			want:    "/\"Allowed\": false,\n  \"Reason\": \"ChangeTabletType does not convert to or from a primary",
+			wantErr: "use PlannedReparentShard instead",
+		},
+		{
+			name:    "BelowMinRemaining",
+			args:    []string{"--min-remaining", "2", "cell1-101", "rdonly"},
+			wantErr: "fewer than the minimum of 2",
+		},
+		{
+			name:           "MinRemaining",
+			args:           []string{"--min-remaining", "1", "cell1-101", "rdonly"},
+			wantChangeType: topodatapb.TabletType_RDONLY,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env.cmdlog.Clear()
+			env.tmc.changeTypes = make(map[int]topodatapb.TabletType)
+			subFlags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			err := commandChangeTabletType(ctx, env.wr, subFlags, tt.args)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			if strings.HasPrefix(tt.want, "/") {
+				require.Regexp(t, regexp.MustCompile(tt.want[1:]), env.cmdlog.String())
+			} else {
+				require.Equal(t, tt.want, env.cmdlog.String())
+			}
+			if tt.wantChangeType != topodatapb.TabletType_UNKNOWN {
+				require.Equal(t, map[int]topodatapb.TabletType{101: tt.wantChangeType}, env.tmc.changeTypes)
+			} else {
+				require.Empty(t, env.tmc.changeTypes)
+			}
+		})
+	}
+}
+
+// TestExecuteHook tests the ExecuteHook client command
+// via the commandExecuteHook() cmd handler.
+func TestExecuteHook(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestVTCtlEnv(ctx)
+	defer env.close()
+	_ = env.addTablet(100, "ks", "0", &topodatapb.KeyRange{}, topodatapb.TabletType_PRIMARY)
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "Text",
+			args: []string{"--env", "GREETING=hello, world", "cell1-100", "test_hook", "--param1=value1"},
+			want: "exit status: 0\nstdout:\n--param1=value1\n\nstderr:\nhello, world\n\n\n",
+		},
+		{
+			name: "JSON",
+			args: []string{"--format", "json", "--env", "GREETING=a=b", "cell1-100", "test_hook"},
+			want: "{\n  \"ExitStatus\": 0,\n  \"Stdout\": \"\\n\",\n  \"Stderr\": \"a=b\\n\"\n}\n\n",
+		},
+		{
+			name:    "NonzeroExitStatus",
+			args:    []string{"--format", "json", "--env", "EXIT_STATUS=3", "cell1-100", "test_hook"},
+			want:    "{\n  \"ExitStatus\": 3,\n  \"Stdout\": \"\\n\",\n  \"Stderr\": \"\\n\"\n}\n\n",
+			wantErr: "exited with status 3",
+		},
+		{
+			name:    "Timeout",
+			args:    []string{"--timeout", "10ms", "cell1-100", "sleep"},
+			wantErr: "did not finish within 10ms",
+		},
+		{
+			name:    "InvalidEnv",
+			args:    []string{"--env", "GREETING", "cell1-100", "test_hook"},
+			wantErr: "must be KEY=VALUE",
+		},
+		{
+			name:    "InvalidFormat",
+			args:    []string{"--format", "yaml", "cell1-100", "test_hook"},
+			wantErr: "invalid --format",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env.cmdlog.Clear()
+			subFlags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			err := commandExecuteHook(ctx, env.wr, subFlags, tt.args)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.want, env.cmdlog.String())
+		})
+	}
+}
+
+// TestExecuteFetchAsDbaFormat tests the output formats of the
+// ExecuteFetchAsDba client command.
+func TestExecuteFetchAsDbaFormat(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestVTCtlEnv(ctx)
+	defer env.close()
+	tablet := env.addTablet(100, "ks", "0", &topodatapb.KeyRange{}, topodatapb.TabletType_PRIMARY)
+	query := "select id, name, data from t"
+	env.tmc.setDBAResults(tablet.tablet, query, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("id|name|data", "int64|varchar|varbinary"),
+		"1|a, b|xy",
+		"2|null|null",
+	))
+	env.tmc.setDBAResults(tablet.tablet, "select id from t limit 1", sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("id", "int64"),
+		"1",
+	))
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "CSV",
+			args: []string{"--format", "csv", "cell1-100", query},
+			want: "id,name,data\n1,\"a, b\",eHk=\n2,,\n",
+		},
+		{
+			name: "JSON",
+			args: []string{"--json", "cell1-100", "select id from t limit 1"},
+			want: `{
+  "fields": [
+    {
+      "name": "id",
+      "type": 265
+    }
+  ],
+  "rows_affected": 0,
+  "insert_id": 0,
+  "insert_id_changed": false,
+  "rows": [
+    [
+      {
+        "type": "INT64",
+        "value": "1"
+      }
+    ]
+  ],
+  "session_state_changes": "",
+  "status_flags": 0,
+  "info": ""
+}
+`,
+		},
+		{
+			name: "CSVOptions",
+			args: []string{"--format", "csv", "--csv_header=false", "--csv_null", "NULL", "--csv_binary", "hex", "cell1-100", query},
+			want: "1,\"a, b\",7879\n2,NULL,NULL\n",
+		},
+		{
+			name:    "InvalidBinaryEncoding",
+			args:    []string{"--format", "csv", "--csv_binary", "raw", "cell1-100", query},
+			wantErr: "invalid binary encoding",
+		},
+		{
+			name:    "InvalidFormat",
+			args:    []string{"--format", "yaml", "cell1-100", query},
+			wantErr: "invalid --format",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env.cmdlog.Clear()
+			subFlags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			err := commandExecuteFetchAsDba(ctx, env.wr, subFlags, tt.args)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			// Each record is a console event of its own, printed as is
+			// by the client.
+			var output strings.Builder
+			for _, event := range env.cmdlog.LogEvents() {
+				output.WriteString(event.Value)
+			}
+			require.Equal(t, tt.want, output.String())
+		})
+	}
+}
+
+// TestCommandNames checks that every command name and alias invokes a
+// single command.
+func TestCommandNames(t *testing.T) {
+	names := make(map[string]string)
+	for _, group := range commands {
+		for _, cmd := range group.commands {
+			for _, name := range append(append([]string{cmd.name}, cmd.aliases...), cmd.deprecatedAliases...) {
+				other, ok := names[strings.ToLower(name)]
+				assert.False(t, ok, "%v of command %v is already a name of command %v", name, cmd.name, other)
+				names[strings.ToLower(name)] = cmd.name
+			}
+		}
+	}
+
+	cmd, deprecated, replacement, ok := findCommand("stopslave")
+	require.True(t, ok)
+	assert.Equal(t, "StopReplication", cmd.name)
+	assert.True(t, deprecated)
+	assert.Equal(t, "StopReplication", replacement)
+
+	cmd, deprecated, _, ok = findCommand("GetTablets")
+	require.True(t, ok)
+	assert.Equal(t, "ListAllTablets", cmd.name)
+	assert.False(t, deprecated)
+
+	cmd, deprecated, replacement, ok = findCommand("VReplicationExec")
+	require.True(t, ok)
+	assert.Equal(t, "VReplicationExec", cmd.name)
+	assert.True(t, deprecated)
+	assert.Equal(t, "Workflow -- <keyspace.workflow> <action>", replacement)
+
+	_, _, _, ok = findCommand("NoSuchCommand")
+	assert.False(t, ok)
+}
+
+// TestCommandAccesses checks that every command is classified as read-only
+// or mutating, and that a read-only wrangler refuses the mutating ones.
+func TestCommandAccesses(t *testing.T) {
+	registered := make(map[string]bool)
+	for _, group := range commands {
+		for _, cmd := range group.commands {
+			registered[cmd.name] = true
+			assert.NotEqual(t, commandUnclassified, commandAccesses[cmd.name], "command %v of group %v is not classified in commandAccesses", cmd.name, group.name)
+		}
+	}
+	for name, access := range commandAccesses {
+		assert.True(t, registered[name], "%v is classified in commandAccesses but is not a command", name)
+		assert.NotEqual(t, commandUnclassified, access, "%v is explicitly unclassified", name)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestVTCtlEnv(ctx)
+	defer env.close()
+	replica := env.addTablet(101, "ks", "0", &topodatapb.KeyRange{}, topodatapb.TabletType_REPLICA)
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), env.cmdlog, env.topoServ, wrangler.WithTabletManagerClient(env.tmc), wrangler.WithReadOnly())
+	require.True(t, wr.ReadOnly())
+
+	err := RunCommand(ctx, wr, []string{"GetTablet", "cell1-101"})
+	require.NoError(t, err)
+
+	for _, args := range [][]string{
+		{"ChangeTabletType", "cell1-101", "rdonly"},
+		{"DeleteTablet", "cell1-101"},
+		{"Workflow", "ks.wf", "stop"},
+		{"AuditTablets", "--older-than=1h", "--delete-stale"},
+		{"Mount", "--topo_type=etcd2", "--topo_server=localhost", "--topo_root=/vitess", "ext"},
+	} {
+		err := RunCommand(ctx, wr, args)
+		require.Error(t, err, "%v", args)
+		assert.Equal(t, vtrpcpb.Code_PERMISSION_DENIED, wrangler.ErrorCode(err), "%v: %v", args, err)
+		assert.ErrorContains(t, err, "is not allowed: the wrangler is read-only")
+	}
+	tablet, err := env.topoServ.GetTablet(ctx, replica.tablet.Alias)
+	require.NoError(t, err)
+	assert.Equal(t, topodatapb.TabletType_REPLICA, tablet.Type)
+}
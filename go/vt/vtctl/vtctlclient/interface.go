@@ -27,6 +27,8 @@ import (
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/servenv"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
 )
 
 // vtctlClientProtocol specifics which RPC client implementation should be used.
@@ -55,6 +57,22 @@ type VtctlClient interface {
 	Close()
 }
 
+// The datatype for the log level Context Key
+type logLevelKey struct{}
+
+// NewContextWithLogLevel returns a Context asking the server to stream back
+// only the log events at or above level of the commands executed with it.
+func NewContextWithLogLevel(ctx context.Context, level logutilpb.Level) context.Context {
+	return context.WithValue(ctx, logLevelKey{}, level)
+}
+
+// LogLevelFromContext returns the log level stored in the Context, or INFO
+// if there is none.
+func LogLevelFromContext(ctx context.Context) logutilpb.Level {
+	level, _ := ctx.Value(logLevelKey{}).(logutilpb.Level)
+	return level
+}
+
 // Factory functions are registered by client implementations
 type Factory func(ctx context.Context, addr string) (VtctlClient, error)
 
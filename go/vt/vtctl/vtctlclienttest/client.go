@@ -41,6 +41,7 @@ import (
 	// import the gRPC client implementation for tablet manager
 	_ "vitess.io/vitess/go/vt/vttablet/grpctmclient"
 
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
@@ -97,6 +98,20 @@ func TestSuite(t *testing.T, ts *topo.Server, client vtctlclient.VtctlClient) {
 		t.Errorf("Didn't get end of log stream: %v %v", got, err)
 	}
 
+	// the output of a command is streamed back whatever the log level
+	stream, err = client.ExecuteVtctlCommand(vtctlclient.NewContextWithLogLevel(ctx, logutilpb.Level_ERROR), []string{"ListAllTablets", "cell1"}, 30*time.Second)
+	if err != nil {
+		t.Fatalf("Remote error: %v", err)
+	}
+
+	got, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("failed to get first line: %v", err)
+	}
+	if logutil.EventString(got) != expected {
+		t.Errorf("Got unexpected log line '%v' expected '%v'", got.String(), expected)
+	}
+
 	// run a command that's gonna fail
 	stream, err = client.ExecuteVtctlCommand(ctx, []string{"ListAllTablets", "cell2"}, 30*time.Second)
 	if err != nil {
@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctldclient
+
+import (
+	"errors"
+	"io"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// ValidationStream is the client side of a streaming keyspace-wide
+// validation, e.g. a ValidateVersionKeyspaceStream.
+type ValidationStream interface {
+	Recv() (*vtctldatapb.ValidationStreamResponse, error)
+}
+
+// ConsumeValidationStream calls onFinding with each finding of the stream as
+// it is received, onSkipped, if set, with each skipped tablet, and
+// onProgress, if set, with each progress report, until the stream ends. It returns the first error of the stream or of a callback.
+//
+// Returning early doesn't stop the validation: callers should cancel the
+// context of the stream for that.
+func ConsumeValidationStream(stream ValidationStream, onFinding func(*vtctldatapb.ValidationFinding) error, onSkipped func(*vtctldatapb.SkippedTablet) error, onProgress func(*vtctldatapb.ValidationProgress) error) error {
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case resp.Finding != nil:
+			if err := onFinding(resp.Finding); err != nil {
+				return err
+			}
+		case resp.SkippedTablet != nil && onSkipped != nil:
+			if err := onSkipped(resp.SkippedTablet); err != nil {
+				return err
+			}
+		case resp.Progress != nil && onProgress != nil:
+			if err := onProgress(resp.Progress); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// CollectValidationFindings returns all the findings of the stream, in the
+// order they are received, once it ends.
+func CollectValidationFindings(stream ValidationStream) ([]*vtctldatapb.ValidationFinding, error) {
+	var findings []*vtctldatapb.ValidationFinding
+	err := ConsumeValidationStream(stream, func(finding *vtctldatapb.ValidationFinding) error {
+		findings = append(findings, finding)
+		return nil
+	}, nil, nil)
+	return findings, err
+}
@@ -21,11 +21,13 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"sync"
 
 	"golang.org/x/exp/maps"
 	"google.golang.org/protobuf/proto"
 
 	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/schema"
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -595,3 +597,226 @@ func (lv *lookupVindex) validateExternalized(ctx context.Context, vindex *vschem
 	}
 	return nil
 }
+
+// maxMissingLookupRows is the maximum number of the sampled rows that are
+// missing from the lookup table which verifyLookupTable reports.
+const maxMissingLookupRows = 10
+
+// verifyLookupTable samples the most recent rows, by primary key, of the
+// table using the lookup vindex on each source shard, and confirms that the
+// lookup table has a row for each of them. The owner table is used if the
+// vindex has one. Sampled rows with a NULL vindex column are skipped, as they
+// may not have been added to the lookup table.
+func (lv *lookupVindex) verifyLookupTable(ctx context.Context, vindex *vschemapb.Vindex, name string, vschema *vschemapb.Keyspace,
+	sourceShards []*topo.ShardInfo, targetShards []*topo.ShardInfo, sampleSize int) error {
+	sourceTableName, sourceCols := lookupVindexSourceColumns(vindex, name, vschema)
+	if sourceTableName == "" {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no table uses vindex %s, so its lookup table cannot be verified", name)
+	}
+	_, lookupTableName, err := lv.parser.ParseTable(vindex.Params["table"])
+	if err != nil {
+		return vterrors.Wrapf(err, "invalid lookup table name %s", vindex.Params["table"])
+	}
+	fromCols := strings.Split(vindex.Params["from"], ",")
+	for i, col := range fromCols {
+		fromCols[i] = strings.TrimSpace(col)
+	}
+	if len(fromCols) != len(sourceCols) {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vindex %s has %d 'from' columns but is on %d columns of table %s",
+			name, len(fromCols), len(sourceCols), sourceTableName)
+	}
+
+	var (
+		mu      sync.Mutex
+		sampled = make(map[string][]sqltypes.Value)
+	)
+	err = forAllShards(sourceShards, func(sourceShard *topo.ShardInfo) error {
+		sourcePrimary, err := lv.ts.GetTablet(ctx, sourceShard.PrimaryAlias)
+		if err != nil {
+			return err
+		}
+		schema, err := schematools.GetSchema(ctx, lv.ts, lv.tmc, sourceShard.PrimaryAlias, &tabletmanagerdatapb.GetSchemaRequest{
+			Tables: []string{sourceTableName},
+		})
+		if err != nil {
+			return err
+		}
+		if len(schema.TableDefinitions) != 1 {
+			return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found on %v", sourceTableName, topoproto.TabletAliasString(sourcePrimary.Alias))
+		}
+		query := generateLookupSampleQuery(sourceTableName, sourceCols, schema.TableDefinitions[0].PrimaryKeyColumns, sampleSize)
+		p3qr, err := lv.tmc.ExecuteFetchAsApp(ctx, sourcePrimary.Tablet, true, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
+			Query:   []byte(query),
+			MaxRows: uint64(sampleSize),
+		})
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to sample table %s on %v", sourceTableName, topoproto.TabletAliasString(sourcePrimary.Alias))
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, row := range sqltypes.Proto3ToResult(p3qr).Rows {
+			sampled[lookupRowKey(row)] = row
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(sampled) == 0 {
+		return nil
+	}
+
+	samples := make([][]sqltypes.Value, 0, len(sampled))
+	keys := maps.Keys(sampled)
+	slices.Sort(keys)
+	for _, key := range keys {
+		samples = append(samples, sampled[key])
+	}
+	query := generateLookupVerifyQuery(lookupTableName, fromCols, samples)
+	found := make(map[string]bool, len(samples))
+	err = forAllShards(targetShards, func(targetShard *topo.ShardInfo) error {
+		targetPrimary, err := lv.ts.GetTablet(ctx, targetShard.PrimaryAlias)
+		if err != nil {
+			return err
+		}
+		p3qr, err := lv.tmc.ExecuteFetchAsApp(ctx, targetPrimary.Tablet, true, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
+			Query:   []byte(query),
+			MaxRows: uint64(len(samples)),
+		})
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to read lookup table %s on %v", lookupTableName, topoproto.TabletAliasString(targetPrimary.Alias))
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, row := range sqltypes.Proto3ToResult(p3qr).Rows {
+			found[lookupRowKey(row)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, sample := range samples {
+		if !found[lookupRowKey(sample)] {
+			missing = append(missing, lookupRowString(sample))
+		}
+	}
+	if len(missing) > 0 {
+		missingCount := len(missing)
+		if missingCount > maxMissingLookupRows {
+			missing = append(missing[:maxMissingLookupRows], "...")
+		}
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "lookup table %s has no rows for %d of the %d sampled rows of table %s: %s",
+			lookupTableName, missingCount, len(samples), sourceTableName, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// lookupVindexSourceColumns returns the table using the lookup vindex, and
+// the columns of the table the vindex is on. The owner table is returned if
+// the vindex has one. The table is empty if no table uses the vindex.
+func lookupVindexSourceColumns(vindex *vschemapb.Vindex, name string, vschema *vschemapb.Keyspace) (string, []string) {
+	tableNames := maps.Keys(vschema.Tables)
+	slices.Sort(tableNames)
+	if vindex.Owner != "" {
+		tableNames = []string{vindex.Owner}
+	}
+	for _, tableName := range tableNames {
+		for _, columnVindex := range vschema.Tables[tableName].GetColumnVindexes() {
+			if columnVindex.Name != name {
+				continue
+			}
+			if columnVindex.Column != "" {
+				return tableName, []string{columnVindex.Column}
+			}
+			return tableName, columnVindex.Columns
+		}
+	}
+	return "", nil
+}
+
+// generateLookupSampleQuery returns the query that samples the most recent
+// non-NULL values of the vindex columns of the source table.
+func generateLookupSampleQuery(sourceTableName string, sourceCols []string, pkCols []string, sampleSize int) string {
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("select ")
+	for i, col := range sourceCols {
+		if i > 0 {
+			buf.Myprintf(", ")
+		}
+		buf.Myprintf("%s", sqlparser.String(sqlparser.NewIdentifierCI(col)))
+	}
+	buf.Myprintf(" from %s where ", sqlparser.String(sqlparser.NewIdentifierCS(sourceTableName)))
+	for i, col := range sourceCols {
+		if i > 0 {
+			buf.Myprintf(" and ")
+		}
+		buf.Myprintf("%s is not null", sqlparser.String(sqlparser.NewIdentifierCI(col)))
+	}
+	for i, col := range pkCols {
+		if i == 0 {
+			buf.Myprintf(" order by ")
+		} else {
+			buf.Myprintf(", ")
+		}
+		buf.Myprintf("%s desc", sqlparser.String(sqlparser.NewIdentifierCI(col)))
+	}
+	buf.Myprintf(" limit %d", sampleSize)
+	return buf.String()
+}
+
+// generateLookupVerifyQuery returns the query that reads the rows of the
+// lookup table matching the sampled values of the vindex columns.
+func generateLookupVerifyQuery(lookupTableName string, fromCols []string, samples [][]sqltypes.Value) string {
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("select distinct ")
+	for i, col := range fromCols {
+		if i > 0 {
+			buf.Myprintf(", ")
+		}
+		buf.Myprintf("%s", sqlparser.String(sqlparser.NewIdentifierCI(col)))
+	}
+	buf.Myprintf(" from %s where (", sqlparser.String(sqlparser.NewIdentifierCS(lookupTableName)))
+	for i, col := range fromCols {
+		if i > 0 {
+			buf.Myprintf(", ")
+		}
+		buf.Myprintf("%s", sqlparser.String(sqlparser.NewIdentifierCI(col)))
+	}
+	buf.Myprintf(") in (")
+	for i, sample := range samples {
+		if i > 0 {
+			buf.Myprintf(", ")
+		}
+		buf.Myprintf("%s", lookupRowString(sample))
+	}
+	buf.Myprintf(")")
+	return buf.String()
+}
+
+// lookupRowKey returns a key that identifies the values of the vindex
+// columns of a row, regardless of their types.
+func lookupRowKey(row []sqltypes.Value) string {
+	values := make([]string, len(row))
+	for i, value := range row {
+		values[i] = value.ToString()
+	}
+	return strings.Join(values, "\x00")
+}
+
+// lookupRowString returns the values of the vindex columns of a row as an
+// SQL tuple.
+func lookupRowString(row []sqltypes.Value) string {
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("(")
+	for i, value := range row {
+		if i > 0 {
+			buf.Myprintf(", ")
+		}
+		value.EncodeSQL(buf)
+	}
+	buf.Myprintf(")")
+	return buf.String()
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/topo"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestLookupVindexExternalizeVerification(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+
+	sampleQuery := "select c1 from t1 where c1 is not null order by id desc limit 2"
+	verifyQuery := "select distinct c1 from t1_lookup where (c1) in ((1), (2), (3))"
+	sampleFields := sqltypes.MakeTestFields("c1", "int64")
+
+	testcases := []struct {
+		name          string
+		force         bool
+		streamState   binlogdatapb.VReplicationWorkflowState
+		streamMessage string
+		lookupRows    []string
+		wantErr       string
+	}{
+		{
+			name:          "verified",
+			streamState:   binlogdatapb.VReplicationWorkflowState_Stopped,
+			streamMessage: "Stopped after copy",
+			lookupRows:    []string{"1", "2", "3"},
+		},
+		{
+			name:          "missing lookup rows",
+			streamState:   binlogdatapb.VReplicationWorkflowState_Stopped,
+			streamMessage: "Stopped after copy",
+			lookupRows:    []string{"1", "3"},
+			wantErr:       "lookup table t1_lookup has no rows for 1 of the 3 sampled rows of table t1: (2)",
+		},
+		{
+			name:        "still copying",
+			streamState: binlogdatapb.VReplicationWorkflowState_Copying,
+			wantErr:     "is not in Stopped after copy state",
+		},
+		{
+			name:        "forced",
+			force:       true,
+			streamState: binlogdatapb.VReplicationWorkflowState_Copying,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			te := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+			defer te.close()
+
+			err := te.ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{
+				Name: sourceKeyspace.KeyspaceName,
+				Keyspace: &vschemapb.Keyspace{
+					Sharded: true,
+					Vindexes: map[string]*vschemapb.Vindex{
+						"xxhash": {Type: "xxhash"},
+						"t1_lookup": {
+							Type: "consistent_lookup_unique",
+							Params: map[string]string{
+								"table":      "targetks.t1_lookup",
+								"from":       "c1",
+								"to":         "keyspace_id",
+								"write_only": "true",
+							},
+							Owner: "t1",
+						},
+					},
+					Tables: map[string]*vschemapb.Table{
+						"t1": {
+							ColumnVindexes: []*vschemapb.ColumnVindex{
+								{Name: "xxhash", Column: "id"},
+								{Name: "t1_lookup", Columns: []string{"c1"}},
+							},
+						},
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			te.tmc.schema["sourceks.t1"] = &tabletmanagerdatapb.SchemaDefinition{
+				TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+					Name:              "t1",
+					Columns:           []string{"id", "c1"},
+					PrimaryKeyColumns: []string{"id"},
+				}},
+			}
+			te.tmc.expectReadVReplicationWorkflowRequestOnTargetTablets(&readVReplicationWorkflowRequestResponse{
+				req: &tabletmanagerdatapb.ReadVReplicationWorkflowRequest{Workflow: "t1_lookup"},
+				res: &tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+					Workflow:     "t1_lookup",
+					WorkflowType: binlogdatapb.VReplicationWorkflowType_CreateLookupIndex,
+					Streams: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{{
+						Id: 1,
+						Bls: &binlogdatapb.BinlogSource{
+							Keyspace: "sourceks",
+							Shard:    "-80",
+							Filter: &binlogdatapb.Filter{
+								Rules: []*binlogdatapb.Rule{{
+									Match:  "t1_lookup",
+									Filter: "select c1 as c1, keyspace_id() as keyspace_id from t1 group by c1, keyspace_id",
+								}},
+							},
+							StopAfterCopy: true,
+						},
+						State:   tc.streamState,
+						Message: tc.streamMessage,
+					}},
+				},
+			})
+			if tc.lookupRows != nil {
+				te.tmc.expectVRQuery(startingSourceTabletUID, sampleQuery, sqltypes.MakeTestResult(sampleFields, "3", "1"))
+				te.tmc.expectVRQuery(startingSourceTabletUID+tabletUIDStep, sampleQuery, sqltypes.MakeTestResult(sampleFields, "2", "1"))
+				te.tmc.expectVRQuery(startingTargetTabletUID, verifyQuery, sqltypes.MakeTestResult(sampleFields, tc.lookupRows...))
+			}
+
+			resp, err := te.ws.LookupVindexExternalize(ctx, &vtctldatapb.LookupVindexExternalizeRequest{
+				Keyspace:               "sourceks",
+				Name:                   "t1_lookup",
+				TableKeyspace:          "targetks",
+				Force:                  tc.force,
+				VerificationSampleSize: 2,
+			})
+			require.Empty(t, te.tmc.vrQueries[startingSourceTabletUID])
+			require.Empty(t, te.tmc.vrQueries[startingSourceTabletUID+tabletUIDStep])
+			require.Empty(t, te.tmc.vrQueries[startingTargetTabletUID])
+
+			vschema, verr := te.ts.GetVSchema(ctx, "sourceks")
+			require.NoError(t, verr)
+			writeOnly, ok := vschema.Vindexes["t1_lookup"].Params["write_only"]
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				require.True(t, ok && writeOnly == "true", "vindex should not have been externalized")
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, resp.WorkflowStopped)
+			require.False(t, ok, "vindex should have been externalized")
+		})
+	}
+}
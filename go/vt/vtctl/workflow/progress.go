@@ -0,0 +1,331 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/sets"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vreplication"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// defaultRowEstimatesTTL is how long GetWorkflowProgress reuses the row
+	// count estimates of the tables of a source shard before fetching them
+	// again.
+	defaultRowEstimatesTTL = 5 * time.Minute
+	// maxRowEstimateTables is the maximum number of tables of a source shard
+	// that row count estimates are fetched for.
+	maxRowEstimateTables = 10000
+)
+
+// rowEstimates are the estimated row counts of the tables of a source shard.
+type rowEstimates struct {
+	fetchedAt time.Time
+	keyRange  *topodatapb.KeyRange
+	rows      map[string]int64
+}
+
+// rowEstimatesCache caches the rowEstimates of the source shards, so that
+// polling the progress of a workflow doesn't query information_schema on the
+// source primaries every time.
+type rowEstimatesCache struct {
+	ttl time.Duration
+
+	mu sync.Mutex
+	// entries are keyed by keyspace/shard.
+	entries map[string]*rowEstimates
+}
+
+func newRowEstimatesCache(ttl time.Duration) *rowEstimatesCache {
+	return &rowEstimatesCache{
+		ttl:     ttl,
+		entries: make(map[string]*rowEstimates),
+	}
+}
+
+// get returns the cached rowEstimates of the source shard, or the ones
+// returned by fetch if there are none or they are older than the TTL.
+func (c *rowEstimatesCache) get(ctx context.Context, keyspace string, shard string, fetch func(ctx context.Context, keyspace string, shard string) (*rowEstimates, error)) (*rowEstimates, error) {
+	keyspaceShard := topoproto.KeyspaceShardString(keyspace, shard)
+
+	c.mu.Lock()
+	estimates, ok := c.entries[keyspaceShard]
+	c.mu.Unlock()
+	if ok && time.Since(estimates.fetchedAt) < c.ttl {
+		return estimates, nil
+	}
+
+	estimates, err := fetch(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[keyspaceShard] = estimates
+	return estimates, nil
+}
+
+// GetWorkflowProgress is part of the vtctlservicepb.VtctldServer interface.
+// It returns the copy and replication progress of each stream of the
+// workflow, aggregated per target shard and overall.
+//
+// The row counts of the source tables are estimates from information_schema,
+// which are cached for the TTL set with WithRowEstimatesTTL so that the
+// progress can be polled cheaply.
+func (s *Server) GetWorkflowProgress(ctx context.Context, req *vtctldatapb.GetWorkflowProgressRequest) (*vtctldatapb.GetWorkflowProgressResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.GetWorkflowProgress")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("shards", req.Shards)
+
+	workflow, err := s.GetWorkflow(ctx, req.Keyspace, req.Workflow, false, req.Shards)
+	if err != nil {
+		return nil, err
+	}
+	// The source primaries of a Migrate workflow are in another cluster, so
+	// we can't estimate the row counts of their tables.
+	estimate := workflow.WorkflowType != binlogdatapb.VReplicationWorkflowType_Migrate.String()
+	lookup := workflow.WorkflowType == binlogdatapb.VReplicationWorkflowType_CreateLookupIndex.String()
+
+	targetKeyRanges := make(map[string]*topodatapb.KeyRange, len(workflow.GetTarget().GetShards()))
+	for _, shard := range workflow.GetTarget().GetShards() {
+		si, err := s.ts.GetShard(ctx, req.Keyspace, shard)
+		if err != nil {
+			return nil, err
+		}
+		targetKeyRanges[shard] = si.KeyRange
+	}
+
+	resp := &vtctldatapb.GetWorkflowProgressResponse{
+		Progress: &vtctldatapb.GetWorkflowProgressResponse_Progress{},
+		Shards:   make(map[string]*vtctldatapb.GetWorkflowProgressResponse_ShardProgress),
+	}
+	tablesRemaining := sets.New[string]()
+	shardTablesRemaining := make(map[string]sets.Set[string])
+	now := time.Now()
+	for _, shardStream := range workflow.ShardStreams {
+		for _, stream := range shardStream.GetStreams() {
+			streamTables := sets.New[string]()
+			for _, copyState := range stream.CopyStates {
+				streamTables.Insert(copyState.Table)
+			}
+
+			streamProgress := &vtctldatapb.GetWorkflowProgressResponse_StreamProgress{
+				Id:                    stream.Id,
+				Tablet:                stream.Tablet,
+				SourceShard:           topoproto.KeyspaceShardString(stream.BinlogSource.GetKeyspace(), stream.BinlogSource.GetShard()),
+				State:                 stream.State,
+				TablesRemaining:       uint32(streamTables.Len()),
+				RowsCopied:            stream.RowsCopied,
+				ReplicationLagSeconds: int64(now.Sub(time.Unix(stream.TimeUpdated.GetSeconds(), 0)).Seconds()),
+			}
+			if stream.State == binlogdatapb.VReplicationWorkflowState_Error.String() {
+				streamProgress.LastError = stream.Message
+			}
+			if estimate {
+				streamProgress.RowsEstimated, err = s.estimateStreamRows(ctx, stream, targetKeyRanges[stream.Shard], lookup)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			shardProgress, ok := resp.Shards[stream.Shard]
+			if !ok {
+				shardProgress = &vtctldatapb.GetWorkflowProgressResponse_ShardProgress{
+					Progress: &vtctldatapb.GetWorkflowProgressResponse_Progress{},
+				}
+				resp.Shards[stream.Shard] = shardProgress
+				shardTablesRemaining[stream.Shard] = sets.New[string]()
+			}
+			shardProgress.Streams = append(shardProgress.Streams, streamProgress)
+			addStreamProgress(shardProgress.Progress, streamProgress)
+			addStreamProgress(resp.Progress, streamProgress)
+			shardTablesRemaining[stream.Shard].Insert(sets.List(streamTables)...)
+			tablesRemaining.Insert(sets.List(streamTables)...)
+		}
+	}
+
+	for shard, shardProgress := range resp.Shards {
+		shardProgress.Progress.TablesRemaining = uint32(shardTablesRemaining[shard].Len())
+	}
+	resp.Progress.TablesRemaining = uint32(tablesRemaining.Len())
+	return resp, nil
+}
+
+// addStreamProgress adds the progress of a stream to the aggregated progress.
+func addStreamProgress(progress *vtctldatapb.GetWorkflowProgressResponse_Progress, streamProgress *vtctldatapb.GetWorkflowProgressResponse_StreamProgress) {
+	progress.Streams++
+	progress.RowsCopied += streamProgress.RowsCopied
+	progress.RowsEstimated += streamProgress.RowsEstimated
+	progress.MaxReplicationLagSeconds = max(progress.MaxReplicationLagSeconds, streamProgress.ReplicationLagSeconds)
+	if streamProgress.State == binlogdatapb.VReplicationWorkflowState_Error.String() {
+		progress.ErroredStreams++
+	}
+}
+
+// estimateStreamRows returns the estimated number of rows the stream copies
+// from the tables of its source shard that match its filter. The rows of
+// tables that are filtered by key range are assumed to be evenly distributed
+// over the key range of the source shard. The rows of a lookup table are
+// sharded by the values of the vindex columns instead of the keyspace ids of
+// the source rows, so each target shard of a lookup workflow is assumed to get
+// its share of the rows of every source shard.
+func (s *Server) estimateStreamRows(ctx context.Context, stream *vtctldatapb.Workflow_Stream, targetKeyRange *topodatapb.KeyRange, lookup bool) (int64, error) {
+	source := stream.BinlogSource
+	estimates, err := s.rowEstimates.get(ctx, source.GetKeyspace(), source.GetShard(), s.fetchRowEstimates)
+	if err != nil {
+		return 0, err
+	}
+
+	share := keyRangeShare(estimates.keyRange, targetKeyRange)
+	if lookup {
+		share = keyRangeShare(nil, targetKeyRange)
+	}
+	ruleRows := func(rule *binlogdatapb.Rule, tableRows int64) float64 {
+		if key.IsValidKeyRange(rule.Filter) || strings.Contains(rule.Filter, "in_keyrange") {
+			return float64(tableRows) * share
+		}
+		return float64(tableRows)
+	}
+
+	var rows float64
+	// The rules with a query as their filter copy from the table in the
+	// query, which isn't necessarily the one they match, e.g. the owner table
+	// of a lookup vindex.
+	for _, rule := range source.GetFilter().GetRules() {
+		if !isQueryFilter(rule.Filter) {
+			continue
+		}
+		table, err := s.env.Parser().TableFromStatement(rule.Filter)
+		if err != nil {
+			return 0, err
+		}
+		rows += ruleRows(rule, estimates.rows[table.Name.String()])
+	}
+	for table, tableRows := range estimates.rows {
+		rule, err := vreplication.MatchTable(table, source.GetFilter())
+		if err != nil {
+			return 0, err
+		}
+		if rule == nil || rule.Filter == vreplication.ExcludeStr || isQueryFilter(rule.Filter) {
+			continue
+		}
+		rows += ruleRows(rule, tableRows)
+	}
+	return int64(rows), nil
+}
+
+// isQueryFilter returns true if the filter of a rule is a query, rather than
+// empty, a key range or an exclusion, like vreplication does when it builds
+// its table plans.
+func isQueryFilter(filter string) bool {
+	return filter != "" && !key.IsValidKeyRange(filter) && filter != vreplication.ExcludeStr
+}
+
+// fetchRowEstimates returns the row count estimates of the tables of the
+// source shard, from information_schema on its primary.
+func (s *Server) fetchRowEstimates(ctx context.Context, keyspace string, shard string) (*rowEstimates, error) {
+	si, err := s.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	if si.PrimaryAlias == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "source shard %s/%s has no primary", keyspace, shard)
+	}
+	primary, err := s.ts.GetTablet(ctx, si.PrimaryAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("select table_name, table_rows from information_schema.tables where table_schema = %s and table_type = 'BASE TABLE'",
+		encodeString(primary.DbName()))
+	p3qr, err := s.tmc.ExecuteFetchAsDba(ctx, primary.Tablet, true, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+		Query:   []byte(query),
+		MaxRows: maxRowEstimateTables,
+	})
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to get the table row counts of source shard %s/%s", keyspace, shard)
+	}
+
+	qr := sqltypes.Proto3ToResult(p3qr)
+	estimates := &rowEstimates{
+		fetchedAt: time.Now(),
+		keyRange:  si.KeyRange,
+		rows:      make(map[string]int64, len(qr.Rows)),
+	}
+	for _, row := range qr.Rows {
+		table := row[0].ToString()
+		if schema.IsInternalOperationTableName(table) {
+			continue
+		}
+		tableRows, err := row[1].ToCastInt64()
+		if err != nil {
+			return nil, err
+		}
+		estimates.rows[table] = tableRows
+	}
+	return estimates, nil
+}
+
+// keyRangeShare returns the share of the keyspace ids of the source key range
+// that are also in the target key range, assuming they are evenly
+// distributed. A nil key range covers all the keyspace ids.
+func keyRangeShare(source *topodatapb.KeyRange, target *topodatapb.KeyRange) float64 {
+	sourceStart, sourceEnd := keyRangeBounds(source)
+	targetStart, targetEnd := keyRangeBounds(target)
+	start, end := max(sourceStart, targetStart), min(sourceEnd, targetEnd)
+	if end <= start {
+		return 0
+	}
+	return (end - start) / (sourceEnd - sourceStart)
+}
+
+// keyRangeBounds returns the start and end of the key range as fractions of
+// the whole keyspace id range, based on their first 8 bytes.
+func keyRangeBounds(keyRange *topodatapb.KeyRange) (float64, float64) {
+	bound := func(id []byte, empty float64) float64 {
+		if len(id) == 0 {
+			return empty
+		}
+		var prefix [8]byte
+		copy(prefix[:], id)
+		return float64(binary.BigEndian.Uint64(prefix[:])) / math.Exp2(64)
+	}
+	return bound(keyRange.GetStart(), 0), bound(keyRange.GetEnd(), 1)
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/key"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestGetWorkflowProgress(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "source",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "target",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	te := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer te.close()
+
+	now := time.Now()
+	newResponse := func(shard string, state binlogdatapb.VReplicationWorkflowState, message string, rowsCopied int64, timeUpdated time.Time) *tabletmanagerdatapb.ReadVReplicationWorkflowsResponse {
+		return &tabletmanagerdatapb.ReadVReplicationWorkflowsResponse{
+			Workflows: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+				{
+					Workflow:     "wf1",
+					WorkflowType: binlogdatapb.VReplicationWorkflowType_MoveTables,
+					Streams: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{
+						{
+							Id: 1,
+							Bls: &binlogdatapb.BinlogSource{
+								Keyspace: "source",
+								Shard:    "0",
+								Filter: &binlogdatapb.Filter{
+									Rules: []*binlogdatapb.Rule{
+										{Match: "t1", Filter: fmt.Sprintf("select * from t1 where in_keyrange(id, 'source.hash', '%s')", shard)},
+										{Match: "t2", Filter: fmt.Sprintf("select * from t2 where in_keyrange(id, 'source.hash', '%s')", shard)},
+									},
+								},
+							},
+							Pos:         position,
+							State:       state,
+							Message:     message,
+							RowsCopied:  rowsCopied,
+							TimeUpdated: protoutil.TimeToProto(timeUpdated),
+						},
+					},
+				},
+			},
+		}
+	}
+	for range 2 {
+		te.tmc.AddVReplicationWorkflowsResponse("target/-80",
+			newResponse("-80", binlogdatapb.VReplicationWorkflowState_Running, "", 100, now.Add(-5*time.Second)))
+		te.tmc.AddVReplicationWorkflowsResponse("target/80-",
+			newResponse("80-", binlogdatapb.VReplicationWorkflowState_Running, "Error: duplicate key", 150, now.Add(-30*time.Second)))
+	}
+
+	copyStateQuery := "select vrepl_id, table_name, lastpk from _vt.copy_state where vrepl_id in (1) and id in (select max(id) from _vt.copy_state where vrepl_id in (1) group by vrepl_id, table_name)"
+	copyStateFields := sqltypes.MakeTestFields("vrepl_id|table_name|lastpk", "int64|varchar|varbinary")
+	rowEstimatesQuery := "select table_name, table_rows from information_schema.tables where table_schema = 'vt_source' and table_type = 'BASE TABLE'"
+	for range 2 {
+		te.tmc.expectVRQuery(startingTargetTabletUID, copyStateQuery, sqltypes.MakeTestResult(copyStateFields, "1|t2|id:50"))
+		te.tmc.expectVRQuery(startingTargetTabletUID+tabletUIDStep, copyStateQuery, sqltypes.MakeTestResult(copyStateFields))
+	}
+	// The row estimates are only expected to be fetched once, as the second
+	// call reuses the cached ones.
+	te.tmc.expectVRQuery(startingSourceTabletUID, rowEstimatesQuery, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("table_name|table_rows", "varchar|int64"),
+		"t1|1000",
+		"t2|400",
+		"t3|50",
+	))
+
+	want := &vtctldatapb.GetWorkflowProgressResponse{
+		Progress: &vtctldatapb.GetWorkflowProgressResponse_Progress{
+			Streams:         2,
+			TablesRemaining: 1,
+			RowsCopied:      250,
+			RowsEstimated:   1400,
+			ErroredStreams:  1,
+		},
+		Shards: map[string]*vtctldatapb.GetWorkflowProgressResponse_ShardProgress{
+			"-80": {
+				Progress: &vtctldatapb.GetWorkflowProgressResponse_Progress{
+					Streams:         1,
+					TablesRemaining: 1,
+					RowsCopied:      100,
+					RowsEstimated:   700,
+				},
+				Streams: []*vtctldatapb.GetWorkflowProgressResponse_StreamProgress{
+					{
+						Id:              1,
+						Tablet:          &topodatapb.TabletAlias{Cell: defaultCellName, Uid: startingTargetTabletUID},
+						SourceShard:     "source/0",
+						State:           binlogdatapb.VReplicationWorkflowState_Copying.String(),
+						TablesRemaining: 1,
+						RowsCopied:      100,
+						RowsEstimated:   700,
+					},
+				},
+			},
+			"80-": {
+				Progress: &vtctldatapb.GetWorkflowProgressResponse_Progress{
+					Streams:        1,
+					RowsCopied:     150,
+					RowsEstimated:  700,
+					ErroredStreams: 1,
+				},
+				Streams: []*vtctldatapb.GetWorkflowProgressResponse_StreamProgress{
+					{
+						Id:            1,
+						Tablet:        &topodatapb.TabletAlias{Cell: defaultCellName, Uid: startingTargetTabletUID + tabletUIDStep},
+						SourceShard:   "source/0",
+						State:         binlogdatapb.VReplicationWorkflowState_Error.String(),
+						RowsCopied:    150,
+						RowsEstimated: 700,
+						LastError:     "Error: duplicate key",
+					},
+				},
+			},
+		},
+	}
+
+	for i := range 2 {
+		resp, err := te.ws.GetWorkflowProgress(ctx, &vtctldatapb.GetWorkflowProgressRequest{
+			Keyspace: "target",
+			Workflow: "wf1",
+		})
+		require.NoError(t, err, "call %d", i)
+
+		// The replication lag depends on how long the call took, so check it
+		// separately.
+		require.InDelta(t, 30, resp.Progress.MaxReplicationLagSeconds, 2, "call %d", i)
+		require.InDelta(t, 5, resp.Shards["-80"].Progress.MaxReplicationLagSeconds, 2, "call %d", i)
+		require.InDelta(t, 30, resp.Shards["80-"].Progress.MaxReplicationLagSeconds, 2, "call %d", i)
+		resp.Progress.MaxReplicationLagSeconds = 0
+		for _, shardProgress := range resp.Shards {
+			shardProgress.Progress.MaxReplicationLagSeconds = 0
+			for _, streamProgress := range shardProgress.Streams {
+				streamProgress.ReplicationLagSeconds = 0
+			}
+		}
+		utils.MustMatch(t, want, resp, fmt.Sprintf("call %d", i))
+	}
+}
+
+func TestRowEstimatesCache(t *testing.T) {
+	ctx := context.Background()
+	cache := newRowEstimatesCache(time.Hour)
+
+	fetches := 0
+	fetch := func(ctx context.Context, keyspace string, shard string) (*rowEstimates, error) {
+		fetches++
+		return &rowEstimates{
+			fetchedAt: time.Now(),
+			rows:      map[string]int64{"t1": int64(fetches)},
+		}, nil
+	}
+
+	estimates, err := cache.get(ctx, "ks", "-80", fetch)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, estimates.rows["t1"])
+
+	// Cached.
+	estimates, err = cache.get(ctx, "ks", "-80", fetch)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, estimates.rows["t1"])
+	require.Equal(t, 1, fetches)
+
+	// Another shard.
+	estimates, err = cache.get(ctx, "ks", "80-", fetch)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, estimates.rows["t1"])
+
+	// Expired.
+	cache.entries["ks/-80"].fetchedAt = time.Now().Add(-2 * time.Hour)
+	estimates, err = cache.get(ctx, "ks", "-80", fetch)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, estimates.rows["t1"])
+
+	// Failed fetches aren't cached.
+	cache.entries["ks/-80"].fetchedAt = time.Now().Add(-2 * time.Hour)
+	_, err = cache.get(ctx, "ks", "-80", func(ctx context.Context, keyspace string, shard string) (*rowEstimates, error) {
+		return nil, fmt.Errorf("failed")
+	})
+	require.Error(t, err)
+	require.Equal(t, 3, fetches)
+}
+
+func TestEstimateLookupStreamRows(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "source",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "target",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	te := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer te.close()
+
+	sourceKeyRange, err := key.ParseShardingSpec("-80")
+	require.NoError(t, err)
+	targetKeyRange, err := key.ParseShardingSpec("80-")
+	require.NoError(t, err)
+	te.ws.rowEstimates.entries["source/-80"] = &rowEstimates{
+		fetchedAt: time.Now(),
+		keyRange:  sourceKeyRange[0],
+		rows:      map[string]int64{"t1": 1000, "t1_lookup": 10},
+	}
+
+	// The lookup table is filled from the owner table, and is sharded by the
+	// lookup column, so every target shard gets its share of the rows of the
+	// source shard.
+	stream := &vtctldatapb.Workflow_Stream{
+		BinlogSource: &binlogdatapb.BinlogSource{
+			Keyspace: "source",
+			Shard:    "-80",
+			Filter: &binlogdatapb.Filter{
+				Rules: []*binlogdatapb.Rule{{
+					Match:  "t1_lookup",
+					Filter: "select c1 as c1, keyspace_id() as keyspace_id from t1 where in_keyrange(c1, 'target.xxhash', '80-') group by c1, keyspace_id",
+				}},
+			},
+		},
+	}
+	rows, err := te.ws.estimateStreamRows(ctx, stream, targetKeyRange[0], true)
+	require.NoError(t, err)
+	require.EqualValues(t, 500, rows)
+
+	// The rows of a MoveTables stream are sharded by keyspace id, so none of
+	// them are in the other half of the keyspace.
+	rows, err = te.ws.estimateStreamRows(ctx, stream, targetKeyRange[0], false)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, rows)
+}
+
+func TestKeyRangeShare(t *testing.T) {
+	tcs := []struct {
+		source string
+		target string
+		want   float64
+	}{
+		{source: "-", target: "-", want: 1},
+		{source: "-", target: "-80", want: 0.5},
+		{source: "-", target: "80-", want: 0.5},
+		{source: "-80", target: "-40", want: 0.5},
+		{source: "-80", target: "40-c0", want: 0.5},
+		{source: "-80", target: "80-", want: 0},
+		{source: "40-80", target: "-", want: 1},
+		{source: "-", target: "c0-", want: 0.25},
+	}
+	for _, tc := range tcs {
+		t.Run(fmt.Sprintf("%s in %s", tc.target, tc.source), func(t *testing.T) {
+			source, err := key.ParseShardingSpec(tc.source)
+			require.NoError(t, err)
+			target, err := key.ParseShardingSpec(tc.target)
+			require.NoError(t, err)
+			require.InDelta(t, tc.want, keyRangeShare(source[0], target[0]), 0.0001)
+		})
+	}
+}
@@ -19,6 +19,7 @@ package workflow
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 
@@ -40,6 +41,7 @@ import (
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
@@ -565,6 +567,275 @@ func (ts *trafficSwitcher) createSequenceTable(ctx context.Context, tableName st
 	return err
 }
 
+// createTargetSequences is used when creating a MoveTables workflow into a
+// sharded keyspace. For each of the tables being moved that has a MySQL
+// auto_increment column on the source along with a vschema AutoIncrement
+// definition for it on the target, it creates the backing sequence table in
+// the global keyspace if it does not yet exist and initializes it so that the
+// next value it provides is greater than the max value used on the source.
+// An existing backing table whose next value is already higher is never
+// lowered: an error is returned instead and no changes are made.
+func (ts *trafficSwitcher) createTargetSequences(ctx context.Context, globalKeyspace string) ([]*vtctldatapb.WorkflowStatusResponse_Sequence, error) {
+	vschema, err := ts.TopoServer().GetVSchema(ctx, ts.targetKeyspace)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to get vschema for target keyspace %s", ts.targetKeyspace)
+	}
+	sequencesByBackingTable, _, err := ts.findSequenceUsageInKeyspace(vschema.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if len(sequencesByBackingTable) == 0 {
+		return nil, nil
+	}
+	autoIncColumns, err := ts.getSourceAutoIncrementColumns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	globalVSchema, err := ts.TopoServer().GetVSchema(ctx, globalKeyspace)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to get vschema for the global-keyspace %s", globalKeyspace)
+	}
+	sequenceShard, err := ts.TopoServer().GetOnlyShard(ctx, globalKeyspace)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "the global-keyspace %s must have a single shard", globalKeyspace)
+	}
+	if sequenceShard.PrimaryAlias == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "global-keyspace %s does not currently have a primary tablet",
+			globalKeyspace)
+	}
+	primary, err := ts.TopoServer().GetTablet(ctx, sequenceShard.PrimaryAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate everything before making any changes.
+	type sequenceInit struct {
+		sm         *sequenceMetadata
+		startValue int64
+		exists     bool
+	}
+	var inits []*sequenceInit
+	backingTables := maps.Keys(sequencesByBackingTable)
+	slices.Sort(backingTables)
+	for _, backingTable := range backingTables {
+		sm := sequencesByBackingTable[backingTable]
+		if !strings.EqualFold(autoIncColumns[sm.usingTableName], sm.usingTableDefinition.AutoIncrement.Column) {
+			ts.Logger().Infof("Skipping the sequence %s as column %s in table %s is not an auto_increment column on the source",
+				backingTable, sm.usingTableDefinition.AutoIncrement.Column, sm.usingTableName)
+			continue
+		}
+		if sm.backingTableKeyspace != "" && sm.backingTableKeyspace != globalKeyspace {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the vschema for table %s references the sequence table %s in keyspace %s rather than in the global-keyspace %s",
+				sm.usingTableName, backingTable, sm.backingTableKeyspace, globalKeyspace)
+		}
+		if bt := globalVSchema.Tables[backingTable]; bt != nil && bt.Type != vindexes.TypeSequence {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s in the global-keyspace %s is not a sequence table",
+				backingTable, globalKeyspace)
+		}
+		sm.backingTableKeyspace = globalKeyspace
+		sm.backingTableDBName = primary.DbName()
+		if err := sm.escapeValues(); err != nil {
+			return nil, err
+		}
+		maxValue, err := ts.getSourceMaxSequenceValue(ctx, sm)
+		if err != nil {
+			return nil, err
+		}
+		currentValue, exists, err := ts.getSequenceNextValue(ctx, sm, primary)
+		if err != nil {
+			return nil, err
+		}
+		startValue := maxValue + 1
+		if exists && currentValue > startValue {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the backing sequence table %s.%s already exists with a next value of %d which is higher than the value of %d it would be initialized to",
+				globalKeyspace, backingTable, currentValue, startValue)
+		}
+		inits = append(inits, &sequenceInit{sm: sm, startValue: startValue, exists: exists})
+	}
+
+	sequences := make([]*vtctldatapb.WorkflowStatusResponse_Sequence, 0, len(inits))
+	updatedGlobalVSchema := false
+	for _, si := range inits {
+		sm := si.sm
+		if !si.exists {
+			if err := ts.createSequenceTable(ctx, sm.backingTableName, primary); err != nil {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to create the backing sequence table %s in the global-keyspace %s: %v",
+					sm.backingTableName, globalKeyspace, err)
+			}
+		}
+		initQuery := sqlparser.BuildParsedQuery(sqlInitSequenceTable,
+			sm.backingDB,
+			sm.backingTable,
+			si.startValue,
+			si.startValue,
+			si.startValue,
+		)
+		if _, err := ts.ws.tmc.ExecuteFetchAsApp(ctx, primary.Tablet, true, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
+			Query:   []byte(initQuery.Query),
+			MaxRows: 1,
+		}); err != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to initialize the backing sequence table %s.%s: %v",
+				sm.backingTableDBName, sm.backingTableName, err)
+		}
+		if si.exists {
+			// The sequence may already be in use, so be sure that the tablet
+			// does not keep handing out cached values.
+			if err := ts.ws.tmc.ResetSequences(ctx, primary.Tablet, []string{sm.backingTableName}); err != nil {
+				return nil, vterrors.Wrapf(err, "failed to reset the sequence cache for %s on tablet %s",
+					sm.backingTableName, topoproto.TabletAliasString(primary.Alias))
+			}
+		}
+		if globalVSchema.Tables[sm.backingTableName] == nil {
+			if globalVSchema.Tables == nil {
+				globalVSchema.Tables = make(map[string]*vschemapb.Table)
+			}
+			globalVSchema.Tables[sm.backingTableName] = &vschemapb.Table{
+				Type: vindexes.TypeSequence,
+			}
+			updatedGlobalVSchema = true
+		}
+		sequences = append(sequences, &vtctldatapb.WorkflowStatusResponse_Sequence{
+			Keyspace:   globalKeyspace,
+			Table:      sm.backingTableName,
+			UsingTable: sm.usingTableName,
+			StartValue: si.startValue,
+			Created:    !si.exists,
+		})
+	}
+	if updatedGlobalVSchema {
+		if err := ts.TopoServer().SaveVSchema(ctx, globalVSchema); err != nil {
+			return nil, vterrors.Wrapf(err, "failed to update vschema in the global-keyspace %s", globalKeyspace)
+		}
+	}
+	return sequences, nil
+}
+
+// getSourceAutoIncrementColumns returns the MySQL auto_increment column used
+// by each of the tables being moved, keyed by table name, as defined on the
+// source.
+func (ts *trafficSwitcher) getSourceAutoIncrementColumns(ctx context.Context) (map[string]string, error) {
+	var source *MigrationSource
+	for _, ms := range ts.sources {
+		source = ms
+		break
+	}
+	if source == nil || source.GetPrimary() == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no primary tablet found for source keyspace %s", ts.sourceKeyspace)
+	}
+	tables := make([]string, 0, len(ts.tables))
+	for _, table := range ts.tables {
+		unescapedTable, err := sqlescape.UnescapeID(table)
+		if err != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid table name %q: %v", table, err)
+		}
+		tables = append(tables, unescapedTable)
+	}
+	schema, err := ts.ws.tmc.GetSchema(ctx, source.GetPrimary().Tablet, &tabletmanagerdatapb.GetSchemaRequest{Tables: tables})
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to get the schema from source tablet %s", topoproto.TabletAliasString(source.GetPrimary().Alias))
+	}
+	columns := make(map[string]string, len(schema.GetTableDefinitions()))
+	for _, td := range schema.GetTableDefinitions() {
+		if _, err := stripAutoIncrement(td.Schema, ts.ws.env.Parser(), func(columnName string) error {
+			column, err := sqlescape.UnescapeID(columnName)
+			if err != nil {
+				return err
+			}
+			columns[td.Name] = column
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return columns, nil
+}
+
+// getSourceMaxSequenceValue returns the max value used for the sequence's
+// column in the using table across all of the source shards.
+func (ts *trafficSwitcher) getSourceMaxSequenceValue(ctx context.Context, seq *sequenceMetadata) (int64, error) {
+	var maxSequenceValue int64
+	var mu sync.Mutex
+	if err := seq.escapeValues(); err != nil {
+		return 0, err
+	}
+	errs := ts.ForAllSources(func(source *MigrationSource) error {
+		primary := source.GetPrimary()
+		if primary == nil || primary.GetAlias() == nil {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no primary tablet found for source shard %s/%s",
+				ts.sourceKeyspace, source.GetShard().ShardName())
+		}
+		sourceDB, err := sqlescape.EnsureEscaped(primary.DbName())
+		if err != nil {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid database name %s on source tablet %s: %v",
+				primary.DbName(), topoproto.TabletAliasString(primary.Alias), err)
+		}
+		query := sqlparser.BuildParsedQuery(sqlGetMaxSequenceVal,
+			seq.usingCol,
+			sourceDB,
+			seq.usingTable,
+		)
+		qr, err := ts.ws.tmc.ExecuteFetchAsApp(ctx, primary.Tablet, true, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
+			Query:   []byte(query.Query),
+			MaxRows: 1,
+		})
+		if err != nil || len(qr.Rows) != 1 {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to get the max used sequence value for source table %s.%s on tablet %s: %v",
+				ts.sourceKeyspace, seq.usingTableName, topoproto.TabletAliasString(primary.Alias), err)
+		}
+		rawVal := sqltypes.Proto3ToResult(qr).Rows[0][0]
+		if rawVal.IsNull() { // There are no rows
+			return nil
+		}
+		maxID, err := rawVal.ToInt64()
+		if err != nil {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to get the max used sequence value for source table %s.%s on tablet %s: %v",
+				ts.sourceKeyspace, seq.usingTableName, topoproto.TabletAliasString(primary.Alias), err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		maxSequenceValue = max(maxSequenceValue, maxID)
+		return nil
+	})
+	return maxSequenceValue, errs
+}
+
+// getSequenceNextValue returns the next value stored in the backing sequence
+// table along with whether or not the table exists.
+func (ts *trafficSwitcher) getSequenceNextValue(ctx context.Context, seq *sequenceMetadata, primary *topo.TabletInfo) (int64, bool, error) {
+	if err := seq.escapeValues(); err != nil {
+		return 0, false, err
+	}
+	query := sqlparser.BuildParsedQuery(sqlGetCurrentSequenceVal,
+		seq.backingDB,
+		seq.backingTable,
+	)
+	qr, err := ts.ws.tmc.ExecuteFetchAsApp(ctx, primary.Tablet, true, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
+		Query:   []byte(query.Query),
+		MaxRows: 1,
+	})
+	if err != nil {
+		sqlErr, ok := sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError)
+		if ok && (sqlErr.Num == sqlerror.ERNoSuchTable || sqlErr.Num == sqlerror.ERBadTable) {
+			return 0, false, nil
+		}
+		return 0, false, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to get the current value for the sequence table %s.%s: %v",
+			seq.backingTableDBName, seq.backingTableName, err)
+	}
+	if len(qr.Rows) == 0 {
+		return 0, true, nil
+	}
+	rawVal := sqltypes.Proto3ToResult(qr).Rows[0][0]
+	if rawVal.IsNull() {
+		return 0, true, nil
+	}
+	nextVal, err := rawVal.ToInt64()
+	if err != nil {
+		return 0, true, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to convert the current value for the sequence table %s.%s: %v",
+			seq.backingTableDBName, seq.backingTableName, err)
+	}
+	return nextVal, true, nil
+}
+
 // findSequenceUsageInKeyspace searches the keyspace's vschema for usage
 // of sequences. It returns a map of sequence metadata keyed by the backing
 // sequence table name -- if any usage is found -- along with a boolean to
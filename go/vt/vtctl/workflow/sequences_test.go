@@ -25,10 +25,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/sqlescape"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/proto/vschema"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -699,3 +701,162 @@ func TestDryRunInitializeTargetSequences(t *testing.T) {
 		require.Contains(t, drLog.logs[i+1], fmt.Sprintf("Backing table: %s, current value 0, new value 1", sm.usingTableName))
 	}
 }
+
+func TestCreateTargetSequences(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	globalKeyspace := "global"
+	globalTabletUID := 300
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	_ = env.addTablet(t, ctx, globalTabletUID, globalKeyspace, "0", topodatapb.TabletType_PRIMARY, true)
+
+	env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+		"sourceks.t1": {
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   "t1",
+					Schema: "create table t1 (id int not null auto_increment primary key, c1 varchar(10))",
+				},
+			},
+		},
+		"sourceks.t2": {
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   "t2",
+					Schema: "create table t2 (id int not null primary key, c1 varchar(10))",
+				},
+			},
+		},
+	}
+	targetVSchema := &vschema.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschema.Vindex{
+			"xxhash": {
+				Type: "xxhash",
+			},
+		},
+		Tables: map[string]*vschema.Table{
+			"t1": {
+				ColumnVindexes: []*vschema.ColumnVindex{{Name: "xxhash", Column: "id"}},
+				AutoIncrement:  &vschema.AutoIncrement{Column: "id", Sequence: "global.t1_seq"},
+			},
+			"t2": { // There's no auto_increment column on the source
+				ColumnVindexes: []*vschema.ColumnVindex{{Name: "xxhash", Column: "id"}},
+				AutoIncrement:  &vschema.AutoIncrement{Column: "id", Sequence: "t2_seq"},
+			},
+		},
+	}
+	noSuchTable := sqlerror.NewSQLError(sqlerror.ERNoSuchTable, sqlerror.SSUnknownSQLState, "Table 'vt_global.t1_seq' doesn't exist")
+
+	tests := []struct {
+		name         string
+		currentValue *queryResult
+		expectCreate bool
+		want         []*vtctldatapb.WorkflowStatusResponse_Sequence
+		wantErr      string
+	}{
+		{
+			name: "create missing sequence table",
+			currentValue: &queryResult{
+				query: "/select next_id from `vt_global`.`t1_seq` where id = 0",
+				err:   noSuchTable,
+			},
+			expectCreate: true,
+			want: []*vtctldatapb.WorkflowStatusResponse_Sequence{
+				{Keyspace: globalKeyspace, Table: "t1_seq", UsingTable: "t1", StartValue: 35, Created: true},
+			},
+		},
+		{
+			name: "existing sequence table with a lower value",
+			currentValue: &queryResult{
+				query:  "/select next_id from `vt_global`.`t1_seq` where id = 0",
+				result: sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("next_id", "int64"), "10")),
+			},
+			want: []*vtctldatapb.WorkflowStatusResponse_Sequence{
+				{Keyspace: globalKeyspace, Table: "t1_seq", UsingTable: "t1", StartValue: 35},
+			},
+		},
+		{
+			name: "existing sequence table with a higher value",
+			currentValue: &queryResult{
+				query:  "/select next_id from `vt_global`.`t1_seq` where id = 0",
+				result: sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("next_id", "int64"), "100")),
+			},
+			wantErr: "the backing sequence table global.t1_seq already exists with a next value of 100 which is higher than the value of 35 it would be initialized to",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := env.ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{
+				Name:     targetKeyspace.KeyspaceName,
+				Keyspace: targetVSchema.CloneVT(),
+			})
+			require.NoError(t, err)
+			err = env.ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{
+				Name:     globalKeyspace,
+				Keyspace: &vschema.Keyspace{},
+			})
+			require.NoError(t, err)
+
+			sources := map[string]*MigrationSource{
+				"0": {
+					primary: &topo.TabletInfo{
+						Tablet: env.tablets[sourceKeyspace.KeyspaceName][startingSourceTabletUID],
+					},
+				},
+			}
+			targets := make(map[string]*MigrationTarget, len(targetKeyspace.ShardNames))
+			for i, shard := range targetKeyspace.ShardNames {
+				targets[shard] = &MigrationTarget{
+					primary: &topo.TabletInfo{
+						Tablet: env.tablets[targetKeyspace.KeyspaceName][startingTargetTabletUID+(i*tabletUIDStep)],
+					},
+				}
+			}
+			ts := &trafficSwitcher{
+				id:             1,
+				ws:             env.ws,
+				workflow:       "wf1",
+				tables:         []string{"t1", "t2"},
+				sourceKeyspace: sourceKeyspace.KeyspaceName,
+				targetKeyspace: targetKeyspace.KeyspaceName,
+				sources:        sources,
+				targets:        targets,
+			}
+
+			env.tmc.expectVRQuery(startingSourceTabletUID, "select max(`id`) as maxval from `vt_sourceks`.`t1`",
+				sqltypes.MakeTestResult(sqltypes.MakeTestFields("maxval", "int64"), "34"))
+			env.tmc.expectVRQueryResultOnKeyspaceTablets(globalKeyspace, tc.currentValue)
+			if tc.expectCreate {
+				env.tmc.expectVRQuery(globalTabletUID, "/create table if not exists `t1_seq`", &sqltypes.Result{})
+			}
+			if tc.wantErr == "" {
+				env.tmc.expectVRQuery(globalTabletUID, "/insert into `vt_global`.`t1_seq` .* values \\(0, 35, 1000\\)", &sqltypes.Result{RowsAffected: 1})
+			}
+
+			got, err := ts.createTargetSequences(ctx, globalKeyspace)
+			require.Empty(t, env.tmc.vrQueries[startingSourceTabletUID])
+			require.Empty(t, env.tmc.vrQueries[globalTabletUID])
+			gvs, verr := env.ts.GetVSchema(ctx, globalKeyspace)
+			require.NoError(t, verr)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				require.Empty(t, gvs.Tables, "the global vschema should not be changed")
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.want, got)
+			require.Equal(t, "sequence", gvs.Tables["t1_seq"].GetType())
+			require.NotContains(t, gvs.Tables, "t2_seq")
+		})
+	}
+}
@@ -136,6 +136,9 @@ type Server struct {
 	sem     *semaphore.Weighted
 	env     *vtenv.Environment
 	options serverOptions
+	// rowEstimates caches the row count estimates of the source tables for
+	// GetWorkflowProgress.
+	rowEstimates *rowEstimatesCache
 }
 
 // NewServer returns a new server instance with the given topo.Server and
@@ -152,6 +155,10 @@ func NewServer(env *vtenv.Environment, ts *topo.Server, tmc tmclient.TabletManag
 	if s.options.logger == nil {
 		s.options.logger = logutil.NewConsoleLogger() // Use the default system logger
 	}
+	if s.options.rowEstimatesTTL == 0 {
+		s.options.rowEstimatesTTL = defaultRowEstimatesTTL
+	}
+	s.rowEstimates = newRowEstimatesCache(s.options.rowEstimatesTTL)
 	return s
 }
 
@@ -623,7 +630,9 @@ func (s *Server) LookupVindexCreate(ctx context.Context, req *vtctldatapb.Lookup
 
 // LookupVindexExternalize externalizes a lookup vindex that's
 // finished backfilling or has caught up. If the vindex has an
-// owner then the workflow will also be stopped.
+// owner then the workflow will also be stopped. Unless forced,
+// it first verifies that the lookup table has rows for a sample
+// of the most recent source rows, if a sample size is set.
 func (s *Server) LookupVindexExternalize(ctx context.Context, req *vtctldatapb.LookupVindexExternalizeRequest) (*vtctldatapb.LookupVindexExternalizeResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "workflow.Server.LookupVindexExternalize")
 	defer span.Finish()
@@ -632,6 +641,12 @@ func (s *Server) LookupVindexExternalize(ctx context.Context, req *vtctldatapb.L
 	span.Annotate("name", req.Name)
 	span.Annotate("table_keyspace", req.TableKeyspace)
 	span.Annotate("delete_workflow", req.DeleteWorkflow)
+	span.Annotate("force", req.Force)
+	span.Annotate("verification_sample_size", req.VerificationSampleSize)
+
+	if req.VerificationSampleSize < 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "verification sample size must not be negative: %d", req.VerificationSampleSize)
+	}
 
 	vindex, sourceKsVS, err := getVindexAndVSchema(ctx, s.ts, req.Keyspace, req.Name)
 	if err != nil {
@@ -643,41 +658,57 @@ func (s *Server) LookupVindexExternalize(ctx context.Context, req *vtctldatapb.L
 		return nil, err
 	}
 
-	err = forAllShards(targetShards, func(targetShard *topo.ShardInfo) error {
-		targetPrimary, err := s.ts.GetTablet(ctx, targetShard.PrimaryAlias)
-		if err != nil {
-			return err
-		}
-		res, err := s.tmc.ReadVReplicationWorkflow(ctx, targetPrimary.Tablet, &tabletmanagerdatapb.ReadVReplicationWorkflowRequest{
-			Workflow: req.Name,
+	// Unless forced, the backfill must be done and the lookup table must have
+	// rows for the sampled rows of the source table, as queries use the
+	// lookup table once the vindex is externalized.
+	if !req.Force {
+		err = forAllShards(targetShards, func(targetShard *topo.ShardInfo) error {
+			targetPrimary, err := s.ts.GetTablet(ctx, targetShard.PrimaryAlias)
+			if err != nil {
+				return err
+			}
+			res, err := s.tmc.ReadVReplicationWorkflow(ctx, targetPrimary.Tablet, &tabletmanagerdatapb.ReadVReplicationWorkflowRequest{
+				Workflow: req.Name,
+			})
+			if err != nil {
+				return err
+			}
+			if res == nil || res.Workflow == "" {
+				return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "workflow %s not found on %v", req.Name, topoproto.TabletAliasString(targetPrimary.Alias))
+			}
+			for _, stream := range res.Streams {
+				if stream.Bls.Filter == nil || len(stream.Bls.Filter.Rules) != 1 {
+					return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid binlog source")
+				}
+				if vindex.Owner == "" || !stream.Bls.StopAfterCopy {
+					// If there's no owner or we've requested that the workflow NOT be stopped
+					// after the copy phase completes, then all streams need to be running.
+					if stream.State != binlogdatapb.VReplicationWorkflowState_Running {
+						return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "stream %d for %v.%v is not in Running state: %v", stream.Id, targetShard.Keyspace(), targetShard.ShardName(), stream.State)
+					}
+				} else {
+					// If there is an owner, all streams need to be stopped after copy.
+					if stream.State != binlogdatapb.VReplicationWorkflowState_Stopped || !strings.Contains(stream.Message, "Stopped after copy") {
+						return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "stream %d for %v.%v is not in Stopped after copy state: %v, %v", stream.Id, targetShard.Keyspace(), targetShard.ShardName(), stream.State, stream.Message)
+					}
+				}
+			}
+			return nil
 		})
 		if err != nil {
-			return err
-		}
-		if res == nil || res.Workflow == "" {
-			return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "workflow %s not found on %v", req.Name, topoproto.TabletAliasString(targetPrimary.Alias))
+			return nil, err
 		}
-		for _, stream := range res.Streams {
-			if stream.Bls.Filter == nil || len(stream.Bls.Filter.Rules) != 1 {
-				return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid binlog source")
-			}
-			if vindex.Owner == "" || !stream.Bls.StopAfterCopy {
-				// If there's no owner or we've requested that the workflow NOT be stopped
-				// after the copy phase completes, then all streams need to be running.
-				if stream.State != binlogdatapb.VReplicationWorkflowState_Running {
-					return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "stream %d for %v.%v is not in Running state: %v", stream.Id, targetShard.Keyspace(), targetShard.ShardName(), stream.State)
-				}
-			} else {
-				// If there is an owner, all streams need to be stopped after copy.
-				if stream.State != binlogdatapb.VReplicationWorkflowState_Stopped || !strings.Contains(stream.Message, "Stopped after copy") {
-					return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "stream %d for %v.%v is not in Stopped after copy state: %v, %v", stream.Id, targetShard.Keyspace(), targetShard.ShardName(), stream.State, stream.Message)
-				}
+
+		if req.VerificationSampleSize > 0 {
+			sourceShards, err := s.ts.GetServingShards(ctx, req.Keyspace)
+			if err != nil {
+				return nil, err
+			}
+			lv := newLookupVindex(s)
+			if err := lv.verifyLookupTable(ctx, vindex, req.Name, sourceKsVS.Keyspace, sourceShards, targetShards, int(req.VerificationSampleSize)); err != nil {
+				return nil, err
 			}
 		}
-		return nil
-	})
-	if err != nil {
-		return nil, err
 	}
 
 	resp := &vtctldatapb.LookupVindexExternalizeResponse{}
@@ -862,6 +893,7 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 	span.Annotate("cells", req.Cells)
 	span.Annotate("tablet_types", req.TabletTypes)
 	span.Annotate("on_ddl", req.OnDdl)
+	span.Annotate("create_sequences", req.CreateSequences)
 
 	sourceKeyspace := req.SourceKeyspace
 	targetKeyspace := req.TargetKeyspace
@@ -886,6 +918,15 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 				req.WorkflowOptions.GlobalKeyspace)
 		}
 	}
+	if req.CreateSequences && req.GetWorkflowOptions().GetGlobalKeyspace() == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a global-keyspace is required in order to create sequence tables")
+	}
+	if req.CreateSequences {
+		// The sequences are seeded from the source when the workflow is created,
+		// but writes continue on the source until they are switched. So they are
+		// initialized again, from the target, when writes are switched.
+		req.WorkflowOptions.InitializeTargetSequences = true
+	}
 
 	// When the source is an external cluster mounted using the Mount command.
 	if req.ExternalClusterName != "" {
@@ -907,6 +948,10 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 	if vschema == nil {
 		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no vschema found for target keyspace %s", targetKeyspace)
 	}
+	if req.CreateSequences && !vschema.Sharded {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "sequence tables can only be created when moving tables into a sharded keyspace and %s is unsharded",
+			targetKeyspace)
+	}
 
 	if workflowType == binlogdatapb.VReplicationWorkflowType_MoveTables &&
 		req.GetWorkflowOptions().GetTenantId() != "" {
@@ -1082,6 +1127,16 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 			return nil, vterrors.Wrapf(err, "failed to put initial denied tables entries in place on the target shards")
 		}
 	}
+	var sequences []*vtctldatapb.WorkflowStatusResponse_Sequence
+	if req.CreateSequences {
+		if sequences, err = ts.createTargetSequences(ctx, req.WorkflowOptions.GlobalKeyspace); err != nil {
+			return nil, vterrors.Wrapf(err, "failed to create the sequence tables")
+		}
+		for _, seq := range sequences {
+			s.Logger().Infof("Initialized sequence %s.%s used by table %s with a starting value of %d (created: %t)",
+				seq.Keyspace, seq.Table, seq.UsingTable, seq.StartValue, seq.Created)
+		}
+	}
 	if err := s.ts.RebuildSrvVSchema(ctx, nil); err != nil {
 		return nil, err
 	}
@@ -1126,11 +1181,16 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 	for _, shard := range mz.targetShards {
 		targetShards = append(targetShards, shard.ShardName())
 	}
-	return s.WorkflowStatus(ctx, &vtctldatapb.WorkflowStatusRequest{
+	res, err = s.WorkflowStatus(ctx, &vtctldatapb.WorkflowStatusRequest{
 		Keyspace: targetKeyspace,
 		Workflow: req.Workflow,
 		Shards:   targetShards,
 	})
+	if err != nil {
+		return nil, err
+	}
+	res.Sequences = sequences
+	return res, nil
 }
 
 func validateRoutingRuleFlags(req *vtctldatapb.MoveTablesCreateRequest, mz *materializer) error {
@@ -2934,9 +2994,12 @@ func (s *Server) switchWrites(ctx context.Context, req *vtctldatapb.WorkflowSwit
 	// value generation. If so, then we'll need to ensure that they are
 	// initialized properly before allowing new writes on the target.
 	sequenceMetadata := make(map[string]*sequenceMetadata)
+	// Sequences created by the workflow are always initialized again as they
+	// were seeded when the workflow was created.
+	initializeTargetSequences := req.InitializeTargetSequences || ts.options.GetInitializeTargetSequences()
 	// For sharded to sharded migrations the sequence must already be setup.
 	// For reshards the sequence usage is not changed.
-	if req.InitializeTargetSequences && ts.workflowType == binlogdatapb.VReplicationWorkflowType_MoveTables &&
+	if initializeTargetSequences && ts.workflowType == binlogdatapb.VReplicationWorkflowType_MoveTables &&
 		ts.SourceKeyspaceSchema() != nil && ts.SourceKeyspaceSchema().Keyspace != nil &&
 		!ts.SourceKeyspaceSchema().Keyspace.Sharded {
 		sequenceMetadata, err = ts.getTargetSequenceMetadata(ctx)
@@ -3069,7 +3132,7 @@ func (s *Server) switchWrites(ctx context.Context, req *vtctldatapb.WorkflowSwit
 			return handleError("locks were lost", err)
 		}
 		// Initialize any target sequences, if there are any, before allowing new writes.
-		if req.InitializeTargetSequences && len(sequenceMetadata) > 0 {
+		if initializeTargetSequences && len(sequenceMetadata) > 0 {
 			ts.Logger().Infof("Initializing target sequences")
 			// Writes are blocked so we can safely initialize the sequence tables but
 			// we also want to use a shorter timeout than the the default.
@@ -3191,8 +3254,9 @@ func (s *Server) VReplicationExec(ctx context.Context, tabletAlias *topodatapb.T
 // CopySchemaShard copies the schema from a source tablet to the
 // specified shard. The schema is applied directly on the primary of
 // the destination shard, and is propagated to the replicas through
-// binlogs.
-func (s *Server) CopySchemaShard(ctx context.Context, sourceTabletAlias *topodatapb.TabletAlias, tables, excludeTables []string, includeViews bool, destKeyspace, destShard string, waitReplicasTimeout time.Duration, skipVerify bool) error {
+// binlogs. If throttleWaiter is set, each statement waits for the
+// throttler of the destination primary first.
+func (s *Server) CopySchemaShard(ctx context.Context, sourceTabletAlias *topodatapb.TabletAlias, tables, excludeTables []string, includeViews bool, destKeyspace, destShard string, waitReplicasTimeout time.Duration, skipVerify bool, throttleWaiter *schematools.ThrottleWaiter) error {
 	destShardInfo, err := s.ts.GetShard(ctx, destKeyspace, destShard)
 	if err != nil {
 		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "GetShard(%v, %v) failed: %v", destKeyspace, destShard, err)
@@ -3224,6 +3288,9 @@ func (s *Server) CopySchemaShard(ctx context.Context, sourceTabletAlias *topodat
 		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "GetTablet(%v) failed: %v", destShardInfo.PrimaryAlias, err)
 	}
 	for _, createSQL := range createSQLstmts {
+		if err := throttleWaiter.Wait(ctx, destTabletInfo.Tablet); err != nil {
+			return vterrors.Wrapf(err, "CopySchemaShard aborted while waiting for the throttler")
+		}
 		err = s.applySQLShard(ctx, destTabletInfo, createSQL)
 		if err != nil {
 			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "creating a table failed."+
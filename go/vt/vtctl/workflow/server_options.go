@@ -17,13 +17,16 @@ limitations under the License.
 package workflow
 
 import (
+	"time"
+
 	"vitess.io/vitess/go/vt/logutil"
 )
 
 // serverOptions configure a Workflow Server. serverOptions are set by
 // the ServerOption values passed to the server functions.
 type serverOptions struct {
-	logger logutil.Logger
+	logger          logutil.Logger
+	rowEstimatesTTL time.Duration
 }
 
 // ServerOption configures how we perform the certain operations.
@@ -55,6 +58,15 @@ func WithLogger(l logutil.Logger) ServerOption {
 	})
 }
 
+// WithRowEstimatesTTL sets how long GetWorkflowProgress reuses the row count
+// estimates of the source tables before fetching them again. If this option
+// is not provided then they are reused for 5 minutes.
+func WithRowEstimatesTTL(ttl time.Duration) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.rowEstimatesTTL = ttl
+	})
+}
+
 // workflowActionOptions configure a workflow's optional behavior when
 // performing actions in the worfklow server. Note: these should be used
 // for options that are rarely used so that most callers do not need to
@@ -2430,7 +2430,7 @@ func TestCopySchemaShard(t *testing.T) {
 	})
 
 	sourceTablet := te.tablets[sourceKeyspace.KeyspaceName][100]
-	err := te.ws.CopySchemaShard(ctx, sourceTablet.Alias, []string{"/.*/"}, nil, false, targetKeyspace.KeyspaceName, "-", 1*time.Second, true)
+	err := te.ws.CopySchemaShard(ctx, sourceTablet.Alias, []string{"/.*/"}, nil, false, targetKeyspace.KeyspaceName, "-", 1*time.Second, true, nil)
 	assert.NoError(t, err)
 	assert.Empty(t, te.tmc.applySchemaRequests[200])
 }
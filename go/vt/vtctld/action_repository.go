@@ -129,7 +129,8 @@ func (ar *ActionRepository) ApplyKeyspaceAction(ctx context.Context, actionName,
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, actionTimeout)
-	wr := wrangler.New(ar.env, logutil.NewConsoleLogger(), ar.ts, tmclient.NewTabletManagerClient())
+	ctx, _ = logutil.EnsureCorrelationID(ctx)
+	wr := newWrangler(ar.env, logutil.NewCorrelationIDLogger(ctx, logutil.NewConsoleLoggerFromFlags()), ar.ts, tmclient.NewTabletManagerClient())
 	output, err := action(ctx, wr, keyspace)
 	cancel()
 	if err != nil {
@@ -156,7 +157,8 @@ func (ar *ActionRepository) ApplyShardAction(ctx context.Context, actionName, ke
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, actionTimeout)
-	wr := wrangler.New(ar.env, logutil.NewConsoleLogger(), ar.ts, tmclient.NewTabletManagerClient())
+	ctx, _ = logutil.EnsureCorrelationID(ctx)
+	wr := newWrangler(ar.env, logutil.NewCorrelationIDLogger(ctx, logutil.NewConsoleLoggerFromFlags()), ar.ts, tmclient.NewTabletManagerClient())
 	output, err := action(ctx, wr, keyspace, shard)
 	cancel()
 	if err != nil {
@@ -190,7 +192,8 @@ func (ar *ActionRepository) ApplyTabletAction(ctx context.Context, actionName st
 
 	// run the action
 	ctx, cancel := context.WithTimeout(ctx, actionTimeout)
-	wr := wrangler.New(ar.env, logutil.NewConsoleLogger(), ar.ts, tmclient.NewTabletManagerClient())
+	ctx, _ = logutil.EnsureCorrelationID(ctx)
+	wr := newWrangler(ar.env, logutil.NewCorrelationIDLogger(ctx, logutil.NewConsoleLoggerFromFlags()), ar.ts, tmclient.NewTabletManagerClient())
 	output, err := action.method(ctx, wr, tabletAlias)
 	cancel()
 	if err != nil {
@@ -200,3 +203,13 @@ func (ar *ActionRepository) ApplyTabletAction(ctx context.Context, actionName st
 	result.Output = output
 	return result
 }
+
+// newWrangler returns a wrangler for an action or an API call, which is
+// read-only if vtctld runs with --vtctld_read_only.
+func newWrangler(env *vtenv.Environment, logger logutil.Logger, ts *topo.Server, tmc tmclient.TabletManagerClient) *wrangler.Wrangler {
+	opts := []wrangler.Option{wrangler.WithTabletManagerClient(tmc)}
+	if readOnly {
+		opts = append(opts, wrangler.WithReadOnly())
+	}
+	return wrangler.NewWithOptions(env, logger, ts, opts...)
+}
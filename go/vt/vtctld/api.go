@@ -41,7 +41,6 @@ import (
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtctl"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
-	"vitess.io/vitess/go/vt/wrangler"
 
 	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -50,6 +49,12 @@ import (
 var (
 	localCell    string
 	proxyTablets bool
+
+	// vtctlAPIMaxLogEvents and vtctlAPIMaxLogBytes bound the logs of a vtctl
+	// command run through the API, which are kept in memory until the
+	// command is done.
+	vtctlAPIMaxLogEvents = 100_000
+	vtctlAPIMaxLogBytes  = 64 * 1024 * 1024
 )
 
 // This file implements a REST-style API for the vtctld web interface.
@@ -85,6 +90,8 @@ func init() {
 func registerVtctldAPIFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&localCell, "cell", localCell, "cell to use")
 	fs.BoolVar(&proxyTablets, "proxy_tablets", proxyTablets, "Setting this true will make vtctld proxy the tablet status instead of redirecting to them")
+	fs.IntVar(&vtctlAPIMaxLogEvents, "vtctl-api-max-log-events", vtctlAPIMaxLogEvents, "maximum number of log events of a vtctl command run through the API to return, older events are truncated (0 for no limit)")
+	fs.IntVar(&vtctlAPIMaxLogBytes, "vtctl-api-max-log-bytes", vtctlAPIMaxLogBytes, "maximum size of the log events of a vtctl command run through the API to return, older events are truncated (0 for no limit)")
 }
 
 func newTabletWithURL(t *topodatapb.Tablet) *TabletWithURL {
@@ -477,18 +484,25 @@ func initAPI(ctx context.Context, ts *topo.Server, actions *ActionRepository) {
 			return nil
 		}
 		var args []string
+		ctx, correlationID := logutil.EnsureCorrelationID(r.Context())
 		resp := struct {
-			Error  string
-			Output string
-		}{}
+			Error         string
+			Output        string
+			CorrelationID string
+		}{
+			CorrelationID: correlationID,
+		}
 		if err := unmarshalRequest(r, &args); err != nil {
 			return fmt.Errorf("can't unmarshal request: %v", err)
 		}
 
-		logstream := logutil.NewMemoryLogger()
+		// As for the gRPC API, only the server's log is prefixed with the
+		// correlation ID of the command.
+		logstream := logutil.NewMemoryLoggerWithLimits(vtctlAPIMaxLogEvents, vtctlAPIMaxLogBytes)
+		logger := logutil.NewTeeLogger(logstream, logutil.NewCorrelationIDLogger(ctx, logutil.NewConsoleLoggerFromFlags()))
 
-		wr := wrangler.New(actions.env, logstream, ts, tmClient)
-		err := vtctl.RunCommand(r.Context(), wr, args)
+		wr := newWrangler(actions.env, logger, ts, tmClient)
+		err := vtctl.RunCommand(ctx, wr, args)
 		if err != nil {
 			resp.Error = err.Error()
 		}
@@ -520,10 +534,14 @@ func initAPI(ctx context.Context, ts *topo.Server, actions *ActionRepository) {
 			req.ReplicaTimeoutSeconds = 10
 		}
 
-		logger := logutil.NewCallbackLogger(func(ev *logutilpb.Event) {
+		ctx, _ := logutil.EnsureCorrelationID(ctx)
+		logger := logutil.NewTeeLogger(logutil.NewCallbackLogger(func(ev *logutilpb.Event) {
 			w.Write([]byte(logutil.EventString(ev)))
-		})
-		wr := wrangler.New(actions.env, logger, ts, tmClient)
+		}), logutil.NewCorrelationIDLogger(ctx, logutil.NewConsoleLoggerFromFlags()))
+		wr := newWrangler(actions.env, logger, ts, tmClient)
+		if err := wr.CheckWritable("schema/apply"); err != nil {
+			return err
+		}
 
 		apiCallUUID, err := schema.CreateUUID()
 		if err != nil {
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -38,6 +39,8 @@ import (
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 )
 
+var correlationIDRegexp = regexp.MustCompile(`,"CorrelationID":"[0-9a-f]{16}"`)
+
 func compactJSON(in []byte) string {
 	buf := &bytes.Buffer{}
 	json.Compact(buf, in)
@@ -368,6 +371,11 @@ func TestAPI(t *testing.T) {
 			require.Equal(t, in.statusCode, resp.StatusCode)
 
 			got := compactJSON(body)
+			if in.path == "vtctl/" && resp.StatusCode == http.StatusOK {
+				// The correlation ID of the command is random.
+				require.Regexp(t, correlationIDRegexp, got)
+				got = correlationIDRegexp.ReplaceAllString(got, "")
+			}
 			want := compactJSON([]byte(in.want))
 			if want == "" {
 				// want is not valid JSON. Fallback to a string comparison.
@@ -0,0 +1,51 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctld
+
+import (
+	"fmt"
+	"net/http"
+
+	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+// RegisterDebugConsoleLogHandler registers a debug http endpoint that shows
+// the minimum level of the command logs written by the server, and changes
+// it to the one passed in the level parameter, for the commands already
+// running too.
+func RegisterDebugConsoleLogHandler() {
+	servenv.HTTPHandleFunc("/debug/console-log-min-level", func(w http.ResponseWriter, r *http.Request) {
+		if err := acl.CheckAccessHTTP(r, acl.DEBUGGING); err != nil {
+			acl.SendError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		if s := r.FormValue("level"); s != "" {
+			level, err := logutil.ParseLevel(s)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			logutil.SetConsoleLogMinLevel(level)
+			log.Infof("Set console log min level to: %v", level)
+		}
+		fmt.Fprintf(w, "console log min level: %v", logutil.ConsoleLogMinLevel())
+	})
+}
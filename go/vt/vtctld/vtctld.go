@@ -29,6 +29,8 @@ import (
 
 	"vitess.io/vitess/go/acl"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctlserver"
 	"vitess.io/vitess/go/vt/wrangler"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -37,6 +39,9 @@ import (
 
 var (
 	sanitizeLogMessages = false
+	operationsTopoPath  = ""
+	schemaChangeJournal = false
+	readOnly            = false
 )
 
 func init() {
@@ -47,52 +52,67 @@ func init() {
 
 func registerVtctldFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&sanitizeLogMessages, "vtctld_sanitize_log_messages", sanitizeLogMessages, "When true, vtctld sanitizes logging.")
+	fs.StringVar(&operationsTopoPath, "operations_topo_path", operationsTopoPath, "Path in the global topo under which the long-running wrangler operations are recorded, so that they can be listed and canceled from any vtctld. If empty, they can only be canceled on the vtctld that runs them.")
+	fs.BoolVar(&schemaChangeJournal, "schema_change_journal", schemaChangeJournal, "When true, the schema changes applied with ApplySchema are journaled in the global topo, with the caller who requested them, so that they can be listed with GetSchemaChangeHistory. They are logged either way.")
+	fs.BoolVar(&readOnly, "vtctld_read_only", readOnly, "When true, the legacy vtctl commands, the vtctld HTTP API and the vtctld UI actions run on read-only wranglers: the commands that would change the topo or the tablets fail with PERMISSION_DENIED, the ones that read, list or validate work as usual. The VtctldServer gRPC API refuses the RPCs that would change them too.")
 }
 
 // InitVtctld initializes all the vtctld functionality.
 func InitVtctld(env *vtenv.Environment, ts *topo.Server) error {
+	wrangler.SetOperationsTopoPath(operationsTopoPath)
+	grpcvtctldserver.SetSchemaChangeJournal(schemaChangeJournal)
+	grpcvtctlserver.SetReadOnly(readOnly)
+	grpcvtctldserver.SetReadOnly(readOnly)
+	servenv.AddGRPCServerInterceptors(grpcvtctldserver.ReadOnlyInterceptors())
+
 	actionRepo := NewActionRepository(env, ts)
 
 	// keyspace actions
 	actionRepo.RegisterKeyspaceAction("ValidateKeyspace",
 		func(ctx context.Context, wr *wrangler.Wrangler, keyspace string) (string, error) {
-			return "", wr.ValidateKeyspace(ctx, keyspace, false)
+			report := wrangler.NewValidationReport()
+			err := wr.ValidateKeyspace(ctx, keyspace, false /*pingTablets*/, false /*checkSrvKeyspace*/, report)
+			wr.LogValidationReport(report)
+			return "", err
 		})
 
 	actionRepo.RegisterKeyspaceAction("ValidateSchemaKeyspace",
 		func(ctx context.Context, wr *wrangler.Wrangler, keyspace string) (string, error) {
-			return "", wr.ValidateSchemaKeyspace(ctx, keyspace, nil /*excludeTables*/, false /*includeViews*/, false /*skipNoPrimary*/, false /*includeVSchema*/)
+			return "", wr.ValidateSchemaKeyspace(ctx, keyspace, nil /*excludeTables*/, false /*includeViews*/, false /*skipNoPrimary*/, false /*includeVSchema*/, false /*includeNonServing*/, false /*includeNonServingShards*/)
 		})
 
 	actionRepo.RegisterKeyspaceAction("ValidateVersionKeyspace",
 		func(ctx context.Context, wr *wrangler.Wrangler, keyspace string) (string, error) {
-			return "", wr.ValidateVersionKeyspace(ctx, keyspace)
+			return "", wr.ValidateVersionKeyspace(ctx, keyspace, "", false /* includeNonServing */, false /* includeNonServingShards */)
 		})
 
 	actionRepo.RegisterKeyspaceAction("ValidatePermissionsKeyspace",
 		func(ctx context.Context, wr *wrangler.Wrangler, keyspace string) (string, error) {
-			return "", wr.ValidatePermissionsKeyspace(ctx, keyspace)
+			return "", wr.ValidatePermissionsKeyspace(ctx, keyspace, "", false /* requireAllShards */, false /* includeNonServing */, false /* includeNonServingShards */)
 		})
 
 	// shard actions
 	actionRepo.RegisterShardAction("ValidateShard",
 		func(ctx context.Context, wr *wrangler.Wrangler, keyspace, shard string) (string, error) {
-			return "", wr.ValidateShard(ctx, keyspace, shard, false)
+			report := wrangler.NewValidationReport()
+			err := wr.ValidateShard(ctx, keyspace, shard, false, 0, 0, report)
+			wr.LogValidationReport(report)
+			return "", err
 		})
 
 	actionRepo.RegisterShardAction("ValidateSchemaShard",
 		func(ctx context.Context, wr *wrangler.Wrangler, keyspace, shard string) (string, error) {
-			return "", wr.ValidateSchemaShard(ctx, keyspace, shard, nil, false, false /*includeVSchema*/)
+			return "", wr.ValidateSchemaShard(ctx, keyspace, shard, nil, false, false /*includeVSchema*/, false /*includeNonServing*/)
 		})
 
 	actionRepo.RegisterShardAction("ValidateVersionShard",
 		func(ctx context.Context, wr *wrangler.Wrangler, keyspace, shard string) (string, error) {
-			return "", wr.ValidateVersionShard(ctx, keyspace, shard)
+			return "", wr.ValidateVersionShard(ctx, keyspace, shard, false /* includeNonServing */)
 		})
 
 	actionRepo.RegisterShardAction("ValidatePermissionsShard",
 		func(ctx context.Context, wr *wrangler.Wrangler, keyspace, shard string) (string, error) {
-			return "", wr.ValidatePermissionsShard(ctx, keyspace, shard)
+			return "", wr.ValidatePermissionsShard(ctx, keyspace, shard, false /* includeNonServing */)
 		})
 
 	// tablet actions
@@ -107,6 +127,9 @@ func InitVtctld(env *vtenv.Environment, ts *topo.Server) error {
 
 	actionRepo.RegisterTabletAction("RefreshState", acl.ADMIN,
 		func(ctx context.Context, wr *wrangler.Wrangler, tabletAlias *topodatapb.TabletAlias) (string, error) {
+			if err := wr.CheckWritable("RefreshState"); err != nil {
+				return "", err
+			}
 			ti, err := wr.TopoServer().GetTablet(ctx, tabletAlias)
 			if err != nil {
 				return "", err
@@ -116,11 +139,17 @@ func InitVtctld(env *vtenv.Environment, ts *topo.Server) error {
 
 	actionRepo.RegisterTabletAction("DeleteTablet", acl.ADMIN,
 		func(ctx context.Context, wr *wrangler.Wrangler, tabletAlias *topodatapb.TabletAlias) (string, error) {
+			if err := wr.CheckWritable("DeleteTablet"); err != nil {
+				return "", err
+			}
 			return "", wr.DeleteTablet(ctx, tabletAlias, false)
 		})
 
 	actionRepo.RegisterTabletAction("ReloadSchema", acl.ADMIN,
 		func(ctx context.Context, wr *wrangler.Wrangler, tabletAlias *topodatapb.TabletAlias) (string, error) {
+			if err := wr.CheckWritable("ReloadSchema"); err != nil {
+				return "", err
+			}
 			_, err := wr.VtctldServer().ReloadSchema(ctx, &vtctldatapb.ReloadSchemaRequest{
 				TabletAlias: tabletAlias,
 			})
@@ -437,7 +437,7 @@ func rebuildTopoGraphs(ctx context.Context, topoServer *topo.Server, cell string
 		case err == nil:
 		case topo.IsErrType(err, topo.NoNode):
 			log.Infof("Rebuilding Serving Keyspace %v", ks)
-			if err := topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), topoServer, ks, []string{cell}, false); err != nil {
+			if err := topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), topoServer, ks, []string{cell}, false, false); err != nil {
 				return vterrors.Wrap(err, "vtgate Init: failed to RebuildKeyspace")
 			}
 		default:
@@ -52,6 +52,10 @@ var (
 	vreplicationStoreCompressedGTID   = false
 	vreplicationParallelInsertWorkers = 1
 
+	// vreplicationLogMaxEntries is how many of the most recent records of
+	// each stream are kept in the vreplication_log table.
+	vreplicationLogMaxEntries = 100
+
 	// VStreamerBinlogRotationThreshold is the threshold, above which we rotate binlogs, before taking a GTID snapshot
 	VStreamerBinlogRotationThreshold = int64(64 * 1024 * 1024) // 64MiB
 	VStreamerDefaultPacketSize       = 250000
@@ -68,6 +72,13 @@ func GetVReplicationNetWriteTimeout() int {
 	return vreplicationNetWriteTimeout
 }
 
+// GetVReplicationLogMaxEntries returns how many of the most recent records
+// of each stream are kept in the vreplication_log table, or 0 if they are
+// all kept.
+func GetVReplicationLogMaxEntries() int {
+	return vreplicationLogMaxEntries
+}
+
 func init() {
 	servenv.OnParseFor("vttablet", registerFlags)
 	servenv.OnParseFor("vtcombo", registerFlags)
@@ -100,5 +111,7 @@ func registerFlags(fs *pflag.FlagSet) {
 
 	fs.Uint64Var(&mysql.ZstdInMemoryDecompressorMaxSize, "binlog-in-memory-decompressor-max-size", mysql.ZstdInMemoryDecompressorMaxSize, "This value sets the uncompressed transaction payload size at which we switch from in-memory buffer based decompression to the slower streaming mode.")
 
+	fs.IntVar(&vreplicationLogMaxEntries, "vreplication-log-max-entries", vreplicationLogMaxEntries, "Number of the most recent state transitions and messages of each vreplication stream kept in the vreplication_log sidecar table. Older ones are deleted. Set to 0 to keep them all.")
+
 	fs.BoolVar(&vreplicationEnableHttpLog, "vreplication-enable-http-log", vreplicationEnableHttpLog, "Enable the /debug/vrlog HTTP endpoint, which will produce a log of the events replicated on primary tablets in the target keyspace by all VReplication workflows that are in the running/replicating phase.")
 }
@@ -190,6 +190,11 @@ func (client *FakeTabletManagerClient) ReloadSchema(ctx context.Context, tablet
 	return nil
 }
 
+// ReloadTabletConfig is part of the tmclient.TabletManagerClient interface.
+func (client *FakeTabletManagerClient) ReloadTabletConfig(ctx context.Context, tablet *topodatapb.Tablet, category tabletmanagerdatapb.TabletConfigCategory) (string, error) {
+	return "", nil
+}
+
 // PreflightSchema is part of the tmclient.TabletManagerClient interface.
 func (client *FakeTabletManagerClient) PreflightSchema(ctx context.Context, tablet *topodatapb.Tablet, changes []string) ([]*tabletmanagerdatapb.SchemaChangeResult, error) {
 	return make([]*tabletmanagerdatapb.SchemaChangeResult, len(changes)), nil
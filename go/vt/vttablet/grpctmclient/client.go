@@ -446,6 +446,22 @@ func (client *Client) ReloadSchema(ctx context.Context, tablet *topodatapb.Table
 	return err
 }
 
+// ReloadTabletConfig is part of the tmclient.TabletManagerClient interface.
+func (client *Client) ReloadTabletConfig(ctx context.Context, tablet *topodatapb.Tablet, category tabletmanagerdatapb.TabletConfigCategory) (string, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return "", err
+	}
+	defer closer.Close()
+	response, err := c.ReloadTabletConfig(ctx, &tabletmanagerdatapb.ReloadTabletConfigRequest{
+		Category: category,
+	})
+	if err != nil {
+		return "", err
+	}
+	return response.ConfigHash, nil
+}
+
 func (client *Client) ResetSequences(ctx context.Context, tablet *topodatapb.Tablet, tables []string) error {
 	c, closer, err := client.dialer.dial(ctx, tablet)
 	if err != nil {
@@ -608,6 +624,11 @@ func (client *Client) ExecuteMultiFetchAsDba(ctx context.Context, tablet *topoda
 		defer closer.Close()
 	}
 
+	cid := req.CallerId
+	if cid == nil {
+		cid = callerid.EffectiveCallerIDFromContext(ctx)
+	}
+
 	response, err := c.ExecuteMultiFetchAsDba(ctx, &tabletmanagerdatapb.ExecuteMultiFetchAsDbaRequest{
 		Sql:                     req.Sql,
 		DbName:                  topoproto.TabletDbName(tablet),
@@ -615,6 +636,7 @@ func (client *Client) ExecuteMultiFetchAsDba(ctx context.Context, tablet *topoda
 		DisableBinlogs:          req.DisableBinlogs,
 		ReloadSchema:            req.DisableBinlogs,
 		DisableForeignKeyChecks: req.DisableForeignKeyChecks,
+		CallerId:                cid,
 	})
 	if err != nil {
 		return nil, err
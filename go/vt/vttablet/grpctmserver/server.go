@@ -165,6 +165,17 @@ func (s *server) ReloadSchema(ctx context.Context, request *tabletmanagerdatapb.
 	return response, s.tm.ReloadSchema(ctx, request.WaitPosition)
 }
 
+func (s *server) ReloadTabletConfig(ctx context.Context, request *tabletmanagerdatapb.ReloadTabletConfigRequest) (response *tabletmanagerdatapb.ReloadTabletConfigResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "ReloadTabletConfig", request, response, true /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	response = &tabletmanagerdatapb.ReloadTabletConfigResponse{Category: request.Category}
+	hash, err := s.tm.ReloadTabletConfig(ctx, request.Category)
+	if err == nil {
+		response.ConfigHash = hash
+	}
+	return response, err
+}
+
 func (s *server) PreflightSchema(ctx context.Context, request *tabletmanagerdatapb.PreflightSchemaRequest) (response *tabletmanagerdatapb.PreflightSchemaResponse, err error) {
 	defer s.tm.HandleRPCPanic(ctx, "PreflightSchema", request, response, true /*verbose*/, &err)
 	ctx = callinfo.GRPCCallInfo(ctx)
@@ -251,6 +262,11 @@ func (s *server) ExecuteFetchAsDba(ctx context.Context, request *tabletmanagerda
 func (s *server) ExecuteMultiFetchAsDba(ctx context.Context, request *tabletmanagerdatapb.ExecuteMultiFetchAsDbaRequest) (response *tabletmanagerdatapb.ExecuteMultiFetchAsDbaResponse, err error) {
 	defer s.tm.HandleRPCPanic(ctx, "ExecuteFetchAsDba", request, response, false /*verbose*/, &err)
 	ctx = callinfo.GRPCCallInfo(ctx)
+
+	// Attach the callerID as the EffectiveCallerID.
+	if request.CallerId != nil {
+		ctx = callerid.NewContext(ctx, request.CallerId, &querypb.VTGateCallerID{Username: request.CallerId.Principal})
+	}
 	response = &tabletmanagerdatapb.ExecuteMultiFetchAsDbaResponse{}
 	qrs, err := s.tm.ExecuteMultiFetchAsDba(ctx, request)
 	if err != nil {
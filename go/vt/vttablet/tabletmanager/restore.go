@@ -204,6 +204,12 @@ func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.L
 	if request.RestoreToPos != "" && !restoreToTimestamp.IsZero() {
 		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "--restore-to-pos and --restore-to-timestamp are mutually exclusive")
 	}
+	if request.BackupName != "" {
+		if request.RestoreToPos != "" || !restoreToTimestamp.IsZero() || !protoutil.TimeFromProto(request.BackupTime).IsZero() {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "--backup-name is mutually exclusive with --backup-timestamp, --restore-to-pos and --restore-to-timestamp")
+		}
+		params.BackupName = request.BackupName
+	}
 	if request.RestoreToPos != "" {
 		pos, _, err := replication.DecodePositionMySQL56(request.RestoreToPos)
 		if err != nil {
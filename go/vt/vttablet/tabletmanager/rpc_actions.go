@@ -30,6 +30,7 @@ import (
 
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 // DBAction is used to tell ChangeTabletType whether to call SetReadOnly on change to
@@ -63,7 +64,7 @@ func (tm *TabletManager) Ping(ctx context.Context, args string) string {
 
 // GetPermissions returns the db permissions.
 func (tm *TabletManager) GetPermissions(ctx context.Context) (*tabletmanagerdatapb.Permissions, error) {
-	return mysqlctl.GetPermissions(tm.MysqlDaemon)
+	return mysqlctl.GetPermissions(ctx, tm.MysqlDaemon)
 }
 
 // GetGlobalStatusVars returns the server's global status variables asked for.
@@ -103,9 +104,13 @@ func (tm *TabletManager) ChangeTags(ctx context.Context, tabletTags map[string]s
 	defer tm.unlock()
 
 	tags := tm.tmState.Tablet().Tags
-	if replace || len(tags) == 0 {
+	if replace {
 		tags = tabletTags
 	} else {
+		// An empty value removes the tag, even from a tablet with no tags.
+		if tags == nil {
+			tags = make(map[string]string, len(tabletTags))
+		}
 		for key, val := range tabletTags {
 			if val == "" {
 				delete(tags, key)
@@ -171,9 +176,10 @@ func (tm *TabletManager) ExecuteHook(ctx context.Context, hk *hook.Hook) *hook.H
 	}
 	defer tm.unlock()
 
-	// Execute the hooks
+	// Execute the hooks, bounded by the caller's deadline so a client
+	// timeout kills the hook instead of leaving it running.
 	topotools.ConfigureTabletHook(hk, tm.tabletAlias)
-	return hk.Execute()
+	return hk.ExecuteContext(ctx)
 }
 
 // RefreshState reload the tablet record from the topo server.
@@ -191,6 +197,17 @@ func (tm *TabletManager) RunHealthCheck(ctx context.Context) {
 	tm.QueryServiceControl.BroadcastHealth()
 }
 
+// ReloadTabletConfig reloads a config of the tablet and returns a hash of the
+// config in use afterwards.
+func (tm *TabletManager) ReloadTabletConfig(ctx context.Context, category tabletmanagerdatapb.TabletConfigCategory) (string, error) {
+	switch category {
+	case tabletmanagerdatapb.TabletConfigCategory_TABLE_ACL:
+		return tm.QueryServiceControl.ReloadTableACL(ctx)
+	default:
+		return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown tablet config category %v", category)
+	}
+}
+
 func (tm *TabletManager) convertBoolToSemiSyncAction(ctx context.Context, semiSync bool) (SemiSyncAction, error) {
 	semiSyncExtensionLoaded, err := tm.MysqlDaemon.SemiSyncExtensionLoaded(ctx)
 	if err != nil {
@@ -65,6 +65,8 @@ type RPCTM interface {
 
 	ReloadSchema(ctx context.Context, waitPosition string) error
 
+	ReloadTabletConfig(ctx context.Context, category tabletmanagerdatapb.TabletConfigCategory) (string, error)
+
 	PreflightSchema(ctx context.Context, changes []string) ([]*tabletmanagerdatapb.SchemaChangeResult, error)
 
 	ApplySchema(ctx context.Context, change *tmutils.SchemaChange) (*tabletmanagerdatapb.SchemaChangeResult, error)
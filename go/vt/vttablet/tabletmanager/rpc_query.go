@@ -24,7 +24,11 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/tableacl"
+	tacl "vitess.io/vitess/go/vt/tableacl/acl"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
@@ -208,6 +212,11 @@ func (tm *TabletManager) ExecuteFetchAsDba(ctx context.Context, req *tabletmanag
 
 // ExecuteMultiFetchAsDba will execute the given queries, possibly disabling binlogs and reload schema.
 func (tm *TabletManager) ExecuteMultiFetchAsDba(ctx context.Context, req *tabletmanagerdatapb.ExecuteMultiFetchAsDbaRequest) ([]*querypb.QueryResult, error) {
+	if req.CallerId != nil {
+		// The statements run as the dba user, so this is the only record of
+		// who requested them, e.g. for a schema change.
+		log.Infof("ExecuteMultiFetchAsDba on behalf of %v: %v", req.CallerId.Principal, string(req.Sql))
+	}
 	results, err := tm.executeMultiFetchAsDba(
 		ctx,
 		req.DbName,
@@ -271,10 +280,88 @@ func (tm *TabletManager) ExecuteFetchAsApp(ctx context.Context, req *tabletmanag
 	if err != nil {
 		return nil, err
 	}
+	if req.CallerId != nil {
+		if err := checkTableACL(tm.Env.Parser(), uq, req.CallerId, tm.tableACL); err != nil {
+			return nil, err
+		}
+	}
 	result, err := conn.Conn.ExecuteFetch(uq, int(req.MaxRows), true /*wantFields*/)
 	return sqltypes.ResultToProto3(result), err
 }
 
+// tableACLConfig is how the query service of the tablet enforces the table
+// ACLs, which checkTableACL applies the same way.
+type tableACLConfig struct {
+	// strict is --queryserver-config-strict-table-acl.
+	strict bool
+	// dryRun is --queryserver-config-enable-table-acl-dry-run.
+	dryRun bool
+	// exemptACL is built from --queryserver-config-acl-exempt-acl.
+	exemptACL tacl.ACL
+}
+
+// newTableACLConfig builds the tableACLConfig of the query service config,
+// as the query engine does.
+func newTableACLConfig(config *tabletenv.TabletConfig) tableACLConfig {
+	aclConfig := tableACLConfig{
+		strict: config.StrictTableACL,
+		dryRun: config.EnableTableACLDryRun,
+	}
+	if config.TableACLExemptACL == "" {
+		return aclConfig
+	}
+	f, err := tableacl.GetCurrentACLFactory()
+	if err != nil {
+		log.Infof("Cannot get current ACL Factory: %v", err)
+		return aclConfig
+	}
+	exemptACL, err := f.New([]string{config.TableACLExemptACL})
+	if err != nil {
+		log.Infof("Cannot build exempt ACL for table ACL: %v", err)
+		return aclConfig
+	}
+	aclConfig.exemptACL = exemptACL
+	return aclConfig
+}
+
+// checkTableACL returns a PERMISSION_DENIED error if the table ACLs don't
+// allow callerID to run query on every table it uses, as the query
+// service would for a query of callerID: callers in the exempt ACL are
+// not checked, and denials are only logged in dry run mode and ignored
+// unless the table ACLs are strict.
+func checkTableACL(parser *sqlparser.Parser, query string, callerID *vtrpc.CallerID, aclConfig tableACLConfig) (err error) {
+	caller := &querypb.VTGateCallerID{Username: callerID.Principal, Groups: callerID.Groups}
+	if aclConfig.exemptACL != nil && aclConfig.exemptACL.IsMember(caller) {
+		return nil
+	}
+	if len(tableacl.GetCurrentConfig().GetTableGroups()) == 0 {
+		return vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "cannot check the table ACLs of caller %v: no table ACL is configured on this tablet", callerID.Principal)
+	}
+	stmt, err := parser.Parse(query)
+	if err != nil {
+		return err
+	}
+	// BuildPermissions panics on the statements the query service never
+	// plans, so those are rejected.
+	defer func() {
+		if x := recover(); x != nil {
+			err = vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "cannot check the table ACLs of a %v statement", sqlparser.ASTToStatementType(stmt))
+		}
+	}()
+	for _, perm := range planbuilder.BuildPermissions(stmt) {
+		if perm.TableName == "dual" || tableacl.Authorized(perm.TableName, perm.Role).IsMember(caller) {
+			continue
+		}
+		switch {
+		case aclConfig.dryRun:
+			log.Infof("%s access denied to user '%s' for table '%s' (ACL dry run)", perm.Role.Name(), caller.Username, perm.TableName)
+		case aclConfig.strict:
+			return vterrors.Errorf(vtrpc.Code_PERMISSION_DENIED, "%s access denied to user '%s' for table '%s' (ACL check error)", perm.Role.Name(), caller.Username, perm.TableName)
+		}
+	}
+	return nil
+}
+
 // MysqlHostMetrics gets system metrics from mysqlctl[d]
 func (tm *TabletManager) MysqlHostMetrics(ctx context.Context, req *tabletmanagerdatapb.MysqlHostMetricsRequest) (*tabletmanagerdatapb.MysqlHostMetricsResponse, error) {
 	mysqlResp, err := tm.MysqlDaemon.HostMetrics(ctx, tm.Cnf)
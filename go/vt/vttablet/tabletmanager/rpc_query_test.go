@@ -18,6 +18,8 @@ package tabletmanager
 
 import (
 	"context"
+	"fmt"
+	"math/rand/v2"
 	"strings"
 	"testing"
 
@@ -30,10 +32,16 @@ import (
 	"vitess.io/vitess/go/vt/dbconfigs"
 	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/tableacl"
+	"vitess.io/vitess/go/vt/tableacl/simpleacl"
 	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletservermock"
 
+	tableaclpb "vitess.io/vitess/go/vt/proto/tableacl"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 func TestAnalyzeExecuteFetchAsDbaMultiQuery(t *testing.T) {
@@ -146,3 +154,46 @@ func TestTabletManager_ExecuteFetchAsDba(t *testing.T) {
 		require.Contains(t, got, w)
 	}
 }
+
+func TestCheckTableACL(t *testing.T) {
+	parser := sqlparser.NewTestParser()
+	caller := &vtrpcpb.CallerID{Principal: "app"}
+	aclName := fmt.Sprintf("simpleacl-test-%d", rand.Int64())
+	tableacl.Register(aclName, &simpleacl.Factory{})
+	tableacl.SetDefaultACL(aclName)
+	strict := newTableACLConfig(&tabletenv.TabletConfig{StrictTableACL: true, TableACLExemptACL: "superuser"})
+	require.NotNil(t, strict.exemptACL)
+
+	require.NoError(t, tableacl.InitFromProto(&tableaclpb.Config{}))
+	err := checkTableACL(parser, "select * from t1", caller, strict)
+	assert.Equal(t, vtrpcpb.Code_FAILED_PRECONDITION, vterrors.Code(err), err)
+
+	err = tableacl.InitFromProto(&tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			Name:                 "group01",
+			TableNamesOrPrefixes: []string{"t1"},
+			Readers:              []string{"app", "readers"},
+			Writers:              []string{"admin"},
+		}},
+	})
+	require.NoError(t, err)
+	defer tableacl.InitFromProto(&tableaclpb.Config{})
+
+	assert.NoError(t, checkTableACL(parser, "select * from t1", caller, strict))
+	assert.NoError(t, checkTableACL(parser, "select 1 from dual", caller, strict))
+	assert.NoError(t, checkTableACL(parser, "select * from t1", &vtrpcpb.CallerID{Principal: "other", Groups: []string{"readers"}}, strict))
+
+	err = checkTableACL(parser, "update t1 set a = 1", caller, strict)
+	assert.Equal(t, vtrpcpb.Code_PERMISSION_DENIED, vterrors.Code(err), err)
+	assert.ErrorContains(t, err, "WRITER access denied to user 'app' for table 't1'")
+
+	err = checkTableACL(parser, "select * from t1 join t2", caller, strict)
+	assert.Equal(t, vtrpcpb.Code_PERMISSION_DENIED, vterrors.Code(err), err)
+	assert.ErrorContains(t, err, "table 't2'")
+
+	// The exempt ACL skips the check, and denials are only enforced when
+	// the table ACLs are strict and not in dry run mode.
+	assert.NoError(t, checkTableACL(parser, "update t1 set a = 1", &vtrpcpb.CallerID{Principal: "superuser"}, strict))
+	assert.NoError(t, checkTableACL(parser, "update t1 set a = 1", caller, tableACLConfig{}))
+	assert.NoError(t, checkTableACL(parser, "update t1 set a = 1", caller, tableACLConfig{strict: true, dryRun: true}))
+}
@@ -170,6 +170,10 @@ type TabletManager struct {
 	// tmc is used to run an RPC against other vttablets.
 	tmc tmclient.TabletManagerClient
 
+	// tableACL is how ExecuteFetchAsApp enforces the table ACLs for a
+	// caller ID, as the query service does.
+	tableACL tableACLConfig
+
 	// tmState manages the TabletManager state.
 	tmState *tmState
 
@@ -372,6 +376,10 @@ func (tm *TabletManager) Start(tablet *topodatapb.Tablet, config *tabletenv.Tabl
 	tm.DBConfigs.DBName = topoproto.TabletDbName(tablet)
 	tm.tabletAlias = tablet.Alias
 	tm.tmc = tmclient.NewTabletManagerClient()
+	if config != nil {
+		tm.tableACL = newTableACLConfig(config)
+	}
+	tablet.TabletStartTime = protoutil.TimeToProto(time.Now())
 	tm.tmState = newTMState(tm, tablet)
 	tm.actionSema = semaphore.NewWeighted(1)
 	tm._waitForGrantsComplete = make(chan struct{})
@@ -649,7 +657,7 @@ func (tm *TabletManager) rebuildKeyspace(ctx context.Context, done chan<- struct
 				return
 			}
 		}
-		err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), tm.TopoServer, keyspace, []string{tm.tabletAlias.Cell}, false)
+		err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), tm.TopoServer, keyspace, []string{tm.tabletAlias.Cell}, false, false)
 		if err == nil {
 			srvKeyspace, err = tm.TopoServer.GetSrvKeyspace(ctx, tm.tabletAlias.Cell, keyspace)
 			if err == nil || ctx.Err() != nil {
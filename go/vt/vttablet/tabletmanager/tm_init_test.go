@@ -206,7 +206,7 @@ func TestStartCreateKeyspaceShard(t *testing.T) {
 	// srvKeyspace already created
 	_, err = ts.GetOrCreateShard(ctx, "ks2", "0")
 	require.NoError(t, err)
-	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, "ks2", []string{cell}, false)
+	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, "ks2", []string{cell}, false, false)
 	require.NoError(t, err)
 	tm = newTestTM(t, ts, 3, "ks2", "0", nil)
 	defer tm.Stop()
@@ -221,7 +221,7 @@ func TestStartCreateKeyspaceShard(t *testing.T) {
 	// srvVSchema already created
 	_, err = ts.GetOrCreateShard(ctx, "ks3", "0")
 	require.NoError(t, err)
-	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, "ks3", []string{cell}, false)
+	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, "ks3", []string{cell}, false, false)
 	require.NoError(t, err)
 	err = ts.RebuildSrvVSchema(ctx, []string{cell})
 	require.NoError(t, err)
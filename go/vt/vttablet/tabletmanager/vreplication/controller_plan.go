@@ -53,6 +53,7 @@ const (
 	deleteQuery
 	selectQuery
 	reshardingJournalQuery
+	vreplicationLogQuery
 )
 
 // A comment directive that you can include in your VReplication write
@@ -122,6 +123,9 @@ var tableSelectiveColumns = map[string][]*sqlparser.ColName{
 		{Name: sqlparser.NewIdentifierCI("id")},
 		{Name: sqlparser.NewIdentifierCI("workflow")},
 	},
+	vreplicationLogTableName: {
+		{Name: sqlparser.NewIdentifierCI("vrepl_id")},
+	},
 }
 
 // columnsAsCSV returns a comma-separated list of column names.
@@ -317,6 +321,15 @@ func buildDeletePlan(del *sqlparser.Delete) (*controllerPlan, error) {
 		return &controllerPlan{
 			opcode: reshardingJournalQuery,
 		}, nil
+	case vreplicationLogTableName:
+		// Deleting the log records of streams clears their history.
+		if safe := isSelective(del.Where, tableSelectiveColumns[vreplicationLogTableName]...); !safe {
+			return nil, fmt.Errorf("unsafe WHERE clause in delete: %s; should be using = or in with the following column: %s",
+				sqlparser.String(del.Where), columnsAsCSV(tableSelectiveColumns[vreplicationLogTableName]))
+		}
+		return &controllerPlan{
+			opcode: vreplicationLogQuery,
+		}, nil
 	case vreplicationTableName:
 		if del.Comments == nil || del.Comments.Directives() == nil || !del.Comments.Directives().IsSet(AllowUnsafeWriteCommentDirective) {
 			if safe := isSelective(del.Where, tableSelectiveColumns[vreplicationTableName]...); !safe {
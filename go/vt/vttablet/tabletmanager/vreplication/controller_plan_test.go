@@ -204,6 +204,15 @@ func TestControllerPlan(t *testing.T) {
 			query:  "delete from _vt.resharding_journal where id = 1",
 			opcode: reshardingJournalQuery,
 		},
+	}, {
+		in: "delete from _vt.vreplication_log where vrepl_id in (1, 2)",
+		plan: &testControllerPlan{
+			query:  "delete from _vt.vreplication_log where vrepl_id in (1, 2)",
+			opcode: vreplicationLogQuery,
+		},
+	}, {
+		in:  "delete from _vt.vreplication_log where state = 'Error'",
+		err: "unsafe WHERE clause in delete:  where state = 'Error'; should be using = or in with the following column: vrepl_id",
 	}, {
 		in:  "delete from _vt.a where id = 1",
 		err: "invalid table name: a",
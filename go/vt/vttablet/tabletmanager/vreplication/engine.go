@@ -529,8 +529,9 @@ func (vre *Engine) exec(query string, runAsAdmin bool) (*sqltypes.Result, error)
 			return nil, err
 		}
 		return qr, nil
-	case selectQuery, reshardingJournalQuery:
-		// Selects and resharding journal queries are passed through.
+	case selectQuery, reshardingJournalQuery, vreplicationLogQuery:
+		// Selects, resharding journal queries and deletes of stream logs
+		// are passed through.
 		return dbClient.ExecuteFetch(plan.query, maxRows)
 	}
 	panic("unreachable")
@@ -29,6 +29,7 @@ import (
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
+	vttablet "vitess.io/vitess/go/vt/vttablet/common"
 
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
@@ -99,9 +100,11 @@ func insertLog(dbClient *vdbClient, typ string, vreplID int32, state, message st
 		return
 	}
 	var query string
+	inserted := false
 	if id > 0 && message == lastLogMessage {
 		query = fmt.Sprintf("update %s.vreplication_log set count = count + 1 where id = %d", sidecar.GetIdentifier(), id)
 	} else {
+		inserted = true
 		buf := sqlparser.NewTrackedBuffer(nil)
 		if len(message) > maxVReplicationLogMessageLen {
 			message, err = textutil.TruncateText(message, maxVReplicationLogMessageLen, binlogplayer.TruncationLocation, binlogplayer.TruncationIndicator)
@@ -116,6 +119,38 @@ func insertLog(dbClient *vdbClient, typ string, vreplID int32, state, message st
 	}
 	if _, err = dbClient.ExecuteFetch(query, 10000); err != nil {
 		log.Errorf("Could not insert into vreplication_log table: %v: %v", query, err)
+		return
+	}
+	if inserted {
+		pruneLog(dbClient, vreplID, vttablet.GetVReplicationLogMaxEntries())
+	}
+}
+
+// pruneLog deletes the log records of a stream older than its maxEntries
+// most recent ones, so that the history of a stream flapping between
+// states stays bounded. A maxEntries of 0 keeps all the records.
+func pruneLog(dbClient *vdbClient, vreplID int32, maxEntries int) {
+	if maxEntries <= 0 {
+		return
+	}
+	query := fmt.Sprintf("select id from %s.vreplication_log where vrepl_id = %d order by id desc limit %d, 1",
+		sidecar.GetIdentifier(), vreplID, maxEntries)
+	qr, err := dbClient.Execute(query)
+	if err != nil {
+		log.Errorf("Could not prune the vreplication_log records of stream %d: %v", vreplID, err)
+		return
+	}
+	if len(qr.Rows) != 1 {
+		return
+	}
+	newestPruned, err := qr.Rows[0][0].ToCastInt64()
+	if err != nil {
+		log.Errorf("Could not prune the vreplication_log records of stream %d: %v", vreplID, err)
+		return
+	}
+	query = fmt.Sprintf("delete from %s.vreplication_log where vrepl_id = %d and id <= %d", sidecar.GetIdentifier(), vreplID, newestPruned)
+	if _, err = dbClient.ExecuteFetch(query, 10000); err != nil {
+		log.Errorf("Could not prune the vreplication_log records of stream %d: %v: %v", vreplID, query, err)
 	}
 }
 
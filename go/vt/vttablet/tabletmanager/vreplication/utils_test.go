@@ -103,6 +103,31 @@ func TestInsertLogTruncation(t *testing.T) {
 	}
 }
 
+func TestPruneLog(t *testing.T) {
+	dbClient := binlogplayer.NewMockDBClient(t)
+	defer dbClient.Close()
+	dbClient.RemoveInvariant("select id from _vt.vreplication_log where vrepl_id") // Otherwise the select will be ignored
+	stats := binlogplayer.NewStats()
+	defer stats.Stop()
+	vdbClient := newVDBClient(dbClient, stats, vttablet.DefaultVReplicationConfig.RelayLogMaxItems)
+	defer vdbClient.Close()
+
+	// The stream has no more records than the maximum.
+	dbClient.ExpectRequest("select id from _vt.vreplication_log where vrepl_id = 1 order by id desc limit 3, 1", &sqltypes.Result{}, nil)
+	pruneLog(vdbClient, 1, 3)
+	dbClient.Wait()
+
+	// The records older than the 3 most recent ones are deleted.
+	dbClient.ExpectRequest("select id from _vt.vreplication_log where vrepl_id = 1 order by id desc limit 3, 1",
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "7"), nil)
+	dbClient.ExpectRequest("delete from _vt.vreplication_log where vrepl_id = 1 and id <= 7", &sqltypes.Result{}, nil)
+	pruneLog(vdbClient, 1, 3)
+	dbClient.Wait()
+
+	// Nothing is pruned without a maximum.
+	pruneLog(vdbClient, 1, 0)
+}
+
 // TestIsUnrecoverableError tests the different error cases for isUnrecoverableError().
 func TestIsUnrecoverableError(t *testing.T) {
 	if runNoBlobTest {
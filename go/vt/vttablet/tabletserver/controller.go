@@ -70,6 +70,10 @@ type Controller interface {
 	// ReloadSchema makes the query service reload its schema cache
 	ReloadSchema(ctx context.Context) error
 
+	// ReloadTableACL reloads the table ACL config file, and returns a hash of
+	// the config in use afterwards
+	ReloadTableACL(ctx context.Context) (string, error)
+
 	// RegisterQueryRuleSource adds a query rule source
 	RegisterQueryRuleSource(ruleSource string)
 
@@ -127,6 +127,9 @@ type TabletServer struct {
 	// alias is used for identifying this tabletserver in healthcheck responses.
 	alias *topodatapb.TabletAlias
 
+	// tableACLConfigFile is the file the table ACL was loaded from by InitACL.
+	tableACLConfigFile string
+
 	// This field is only stored for testing
 	checkMysqlGaugeFunc *stats.GaugeFunc
 
@@ -372,6 +375,7 @@ func (tsv *TabletServer) initACL(tableACLConfigFile string) error {
 
 // InitACL loads the table ACL and sets up a SIGHUP handler for reloading it.
 func (tsv *TabletServer) InitACL(tableACLConfigFile string, reloadACLConfigFileInterval time.Duration) error {
+	tsv.tableACLConfigFile = tableACLConfigFile
 	if err := tsv.initACL(tableACLConfigFile); err != nil {
 		return err
 	}
@@ -400,6 +404,19 @@ func (tsv *TabletServer) InitACL(tableACLConfigFile string, reloadACLConfigFileI
 	return nil
 }
 
+// ReloadTableACL reloads the table ACL from the file given to InitACL, and
+// returns a hash of the config in use afterwards.
+func (tsv *TabletServer) ReloadTableACL(ctx context.Context) (string, error) {
+	if tsv.tableACLConfigFile == "" {
+		return "", vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no table ACL config file is set")
+	}
+	if err := tsv.initACL(tsv.tableACLConfigFile); err != nil {
+		return "", vterrors.Wrapf(err, "failed to reload table ACL config file %s", tsv.tableACLConfigFile)
+	}
+	log.Infof("Reloaded table ACL config file %s", tsv.tableACLConfigFile)
+	return tableacl.ConfigHash()
+}
+
 // SetServingType changes the serving type of the tabletserver. It starts or
 // stops internal services as deemed necessary.
 // Returns true if the state of QueryService or the tablet type changed.
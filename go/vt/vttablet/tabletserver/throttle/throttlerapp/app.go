@@ -76,6 +76,9 @@ const (
 	BinlogWatcherName Name = "binlog-watcher"
 	MessagerName      Name = "messager"
 	SchemaTrackerName Name = "schema-tracker"
+	// DirectDDLName is used by vtctld when it applies schema changes directly
+	// on the primaries, e.g. in CopySchemaShard, and respects the throttler.
+	DirectDDLName Name = "direct-ddl"
 
 	TestingName                Name = "test"
 	TestingAlwaysThrottledName Name = "always-throttled-app"
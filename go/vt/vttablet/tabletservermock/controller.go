@@ -175,6 +175,11 @@ func (tqsc *Controller) ReloadSchema(ctx context.Context) error {
 	return nil
 }
 
+// ReloadTableACL is part of the tabletserver.Controller interface
+func (tqsc *Controller) ReloadTableACL(ctx context.Context) (string, error) {
+	return "", nil
+}
+
 // ClearQueryPlanCache is part of the tabletserver.Controller interface
 func (tqsc *Controller) ClearQueryPlanCache() {
 }
@@ -112,6 +112,10 @@ type TabletManagerClient interface {
 	// ReloadSchema asks the remote tablet to reload its schema
 	ReloadSchema(ctx context.Context, tablet *topodatapb.Tablet, waitPosition string) error
 
+	// ReloadTabletConfig asks the remote tablet to reload a config, and
+	// returns a hash of the config it uses afterwards
+	ReloadTabletConfig(ctx context.Context, tablet *topodatapb.Tablet, category tabletmanagerdatapb.TabletConfigCategory) (string, error)
+
 	// PreflightSchema will test a list of schema changes.
 	PreflightSchema(ctx context.Context, tablet *topodatapb.Tablet, changes []string) ([]*tabletmanagerdatapb.SchemaChangeResult, error)
 
@@ -659,6 +659,26 @@ func tmRPCTestReloadSchemaPanic(ctx context.Context, t *testing.T, client tmclie
 	expectHandleRPCPanic(t, "ReloadSchema", false /*verbose*/, err)
 }
 
+var testReloadTabletConfigHash = "0123456789abcdef"
+
+func (fra *fakeRPCTM) ReloadTabletConfig(ctx context.Context, category tabletmanagerdatapb.TabletConfigCategory) (string, error) {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "ReloadTabletConfig category", category, tabletmanagerdatapb.TabletConfigCategory_TABLE_ACL)
+	return testReloadTabletConfigHash, nil
+}
+
+func tmRPCTestReloadTabletConfig(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	hash, err := client.ReloadTabletConfig(ctx, tablet, tabletmanagerdatapb.TabletConfigCategory_TABLE_ACL)
+	compareError(t, "ReloadTabletConfig", err, hash, testReloadTabletConfigHash)
+}
+
+func tmRPCTestReloadTabletConfigPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	_, err := client.ReloadTabletConfig(ctx, tablet, tabletmanagerdatapb.TabletConfigCategory_TABLE_ACL)
+	expectHandleRPCPanic(t, "ReloadTabletConfig", true /*verbose*/, err)
+}
+
 var testPreflightSchema = []string{"change table add table cloth"}
 var testSchemaChangeResult = []*tabletmanagerdatapb.SchemaChangeResult{
 	{
@@ -1557,6 +1577,7 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	tmRPCTestRefreshState(ctx, t, client, tablet)
 	tmRPCTestRunHealthCheck(ctx, t, client, tablet)
 	tmRPCTestReloadSchema(ctx, t, client, tablet)
+	tmRPCTestReloadTabletConfig(ctx, t, client, tablet)
 	tmRPCTestPreflightSchema(ctx, t, client, tablet)
 	tmRPCTestApplySchema(ctx, t, client, tablet)
 	tmRPCTestExecuteFetch(ctx, t, client, tablet)
@@ -1622,6 +1643,7 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	tmRPCTestRefreshStatePanic(ctx, t, client, tablet)
 	tmRPCTestRunHealthCheckPanic(ctx, t, client, tablet)
 	tmRPCTestReloadSchemaPanic(ctx, t, client, tablet)
+	tmRPCTestReloadTabletConfigPanic(ctx, t, client, tablet)
 	tmRPCTestPreflightSchemaPanic(ctx, t, client, tablet)
 	tmRPCTestApplySchemaPanic(ctx, t, client, tablet)
 	tmRPCTestExecuteFetchPanic(ctx, t, client, tablet)
@@ -0,0 +1,115 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// validateAtomicCopy checks the prerequisites of a MoveTables with atomic
+// copy, which copies all the tables of the source keyspace in a single
+// consistent snapshot so that the foreign keys between them hold on the
+// target:
+//   - the move includes all the tables of all the source shards,
+//   - every foreign key references a table of the move,
+//   - every table uses a storage engine that supports consistent snapshots.
+//
+// The error lists all the prerequisites that are not met.
+func (wr *Wrangler) validateAtomicCopy(ctx context.Context, sourceKeyspace, tableSpecs string, allTables bool, excludeTables string, sourceShards []string) error {
+	var problems []string
+	if !allTables {
+		problems = append(problems, "--all is required, as atomic copy moves all the tables of the source keyspace")
+	}
+	if strings.TrimSpace(tableSpecs) != "" {
+		problems = append(problems, fmt.Sprintf("the table list %q is not supported, as atomic copy moves all the tables of the source keyspace", tableSpecs))
+	}
+	if strings.TrimSpace(excludeTables) != "" {
+		problems = append(problems, fmt.Sprintf("the excluded tables %q are not supported, as atomic copy moves all the tables of the source keyspace", excludeTables))
+	}
+	if len(sourceShards) > 0 {
+		problems = append(problems, fmt.Sprintf("the source shards %v are not supported, as atomic copy moves all the shards of the source keyspace", strings.Join(sourceShards, ",")))
+	}
+
+	sd, err := wr.getKeyspaceSchema(ctx, sourceKeyspace, wr.sourceTs)
+	if err != nil {
+		return fmt.Errorf("cannot get the schema of source keyspace %v to validate the atomic copy: %w", sourceKeyspace, err)
+	}
+	problems = append(problems, wr.atomicCopySchemaProblems(sd)...)
+
+	if len(problems) > 0 {
+		return operationErrorf(vtrpcpb.Code_FAILED_PRECONDITION, keyspaceObject(sourceKeyspace),
+			"atomic copy of keyspace %v is not possible: %v", sourceKeyspace, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// atomicCopySchemaProblems returns the foreign keys to tables that are not
+// moved, and the tables whose engine can't take part in a consistent
+// snapshot, of a source keyspace all the tables of which are moved.
+func (wr *Wrangler) atomicCopySchemaProblems(sd *tabletmanagerdatapb.SchemaDefinition) []string {
+	moved := make(map[string]bool, len(sd.TableDefinitions))
+	for _, td := range sd.TableDefinitions {
+		if td.Type != tmutils.TableView {
+			moved[td.Name] = true
+		}
+	}
+
+	var problems []string
+	for _, td := range sd.TableDefinitions {
+		if !moved[td.Name] {
+			continue
+		}
+		stmt, err := wr.env.Parser().ParseStrictDDL(td.Schema)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("cannot parse the schema of table %v: %v", td.Name, err))
+			continue
+		}
+		createTable, ok := stmt.(*sqlparser.CreateTable)
+		if !ok || createTable.TableSpec == nil {
+			problems = append(problems, fmt.Sprintf("the schema of table %v is not a CREATE TABLE statement", td.Name))
+			continue
+		}
+
+		for _, option := range createTable.TableSpec.Options {
+			if strings.EqualFold(option.Name, "ENGINE") && !strings.EqualFold(option.String, "InnoDB") {
+				problems = append(problems, fmt.Sprintf("table %v uses the %v engine, which doesn't support consistent snapshots", td.Name, option.String))
+			}
+		}
+		for _, constraint := range createTable.TableSpec.Constraints {
+			fk, ok := constraint.Details.(*sqlparser.ForeignKeyDefinition)
+			if !ok {
+				continue
+			}
+			referenced := fk.ReferenceDefinition.ReferencedTable
+			switch {
+			case !referenced.Qualifier.IsEmpty():
+				problems = append(problems, fmt.Sprintf("foreign key %v of table %v references table %v.%v, in another database", constraint.Name.String(), td.Name, referenced.Qualifier.String(), referenced.Name.String()))
+			case !moved[referenced.Name.String()]:
+				problems = append(problems, fmt.Sprintf("foreign key %v of table %v references table %v, which is not moved", constraint.Name.String(), td.Name, referenced.Name.String()))
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestMoveTablesAtomicCopyValidation(t *testing.T) {
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:       "workflow",
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+	}
+	env, ctx := newTestMaterializerEnv(t, ms, []string{"0"}, []string{"0"})
+	setSchema := func(tables map[string]string) {
+		env.tmc.schema = make(map[string]*tabletmanagerdatapb.SchemaDefinition)
+		for name, schema := range tables {
+			td := &tabletmanagerdatapb.TableDefinition{Name: name, Schema: schema, Type: tmutils.TableBaseTable}
+			if name == "v1" {
+				td.Type = tmutils.TableView
+			}
+			env.tmc.schema["sourceks."+name] = &tabletmanagerdatapb.SchemaDefinition{
+				TableDefinitions: []*tabletmanagerdatapb.TableDefinition{td},
+			}
+		}
+	}
+
+	// The view isn't moved, and isn't parsed as a table.
+	setSchema(map[string]string{
+		"parent": "create table parent (id int primary key) engine=InnoDB",
+		"child":  "create table child (id int primary key, parent_id int, constraint fk_parent foreign key (parent_id) references parent (id))",
+		"v1":     "create view v1 as select id from parent",
+	})
+	err := env.wr.validateAtomicCopy(ctx, "sourceks", "", true, "", nil)
+	require.NoError(t, err)
+
+	setSchema(map[string]string{
+		"parent": "create table parent (id int primary key) engine=MyISAM",
+		"child":  "create table child (id int primary key, parent_id int, constraint fk_missing foreign key (parent_id) references missing (id))",
+		"other":  "create table other (id int primary key, ref_id int, constraint fk_other foreign key (ref_id) references otherdb.ref (id))",
+	})
+	err = env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", "parent", "", "", false, "", true, false, "", false, false, "", defaultOnDDL, []string{"0"}, false, true)
+	require.Error(t, err)
+	require.Equal(t, vtrpcpb.Code_FAILED_PRECONDITION, vterrors.Code(err))
+	for _, problem := range []string{
+		"atomic copy of keyspace sourceks is not possible",
+		"--all is required",
+		`the table list "parent" is not supported`,
+		"the source shards 0 are not supported",
+		"foreign key fk_missing of table child references table missing, which is not moved",
+		"foreign key fk_other of table other references table otherdb.ref, in another database",
+		"table parent uses the MyISAM engine, which doesn't support consistent snapshots",
+	} {
+		require.ErrorContains(t, err, problem)
+	}
+
+	setSchema(map[string]string{
+		"t1": "not a create table",
+	})
+	err = env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", "", "", "", true, "t2", true, false, "", false, false, "", defaultOnDDL, nil, false, true)
+	require.ErrorContains(t, err, `the excluded tables "t2" are not supported`)
+	require.ErrorContains(t, err, "cannot parse the schema of table t1")
+}
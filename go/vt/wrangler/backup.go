@@ -0,0 +1,991 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// backupCandidate is a tablet considered by SelectBackupTablet.
+type backupCandidate struct {
+	tablet *topodatapb.Tablet
+	lag    uint32
+	// reason is why the tablet isn't eligible, if it isn't.
+	reason string
+}
+
+// SelectBackupTablet picks the tablet of the shard to take a backup from.
+// Replicas are ranked like in the BackupShard RPC, with
+// reparentutil.CompareBackupCandidates: by type, RDONLY first, then REPLICA,
+// then SPARE, and then by their current replication lag, read with
+// ReplicationStatus, except that the replicas lagging more than
+// reparentutil.BackupCandidateMaxLag come last. Tablets not replicating or
+// already running a backup are skipped.
+// The primary is only picked if allowPrimary is set and no replica is
+// eligible. If preferredTablet is set, it is picked as long as it is
+// eligible. The rationale is logged for every tablet considered.
+func (wr *Wrangler) SelectBackupTablet(ctx context.Context, keyspace, shard string, allowPrimary bool, preferredTablet *topodatapb.TabletAlias) (*topodatapb.Tablet, error) {
+	// The tablets that can't be read are not considered for the backup.
+	tabletMap, err := wr.getTabletMapForShard(ctx, keyspace, shard, nil, warnOnUnreadableTablets)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		candidates []*backupCandidate
+		primary    *topodatapb.Tablet
+	)
+	for _, ti := range tabletMap {
+		if ti.Type == topodatapb.TabletType_PRIMARY {
+			primary = ti.Tablet
+			continue
+		}
+		if preferredTablet != nil && !topoproto.TabletAliasEqual(ti.Alias, preferredTablet) {
+			continue
+		}
+		wg.Add(1)
+		go func(tablet *topodatapb.Tablet) {
+			defer wg.Done()
+			ctx, cancel := wr.tabletContext(ctx)
+			defer cancel()
+			candidate := wr.checkBackupCandidate(ctx, tablet)
+			mu.Lock()
+			defer mu.Unlock()
+			candidates = append(candidates, candidate)
+		}(ti.Tablet)
+	}
+	wg.Wait()
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if (a.reason == "") != (b.reason == "") {
+			return a.reason == ""
+		}
+		if c := reparentutil.CompareBackupCandidates(a.tablet.Type, a.lag, b.tablet.Type, b.lag); c != 0 {
+			return c < 0
+		}
+		return topoproto.TabletAliasString(a.tablet.Alias) < topoproto.TabletAliasString(b.tablet.Alias)
+	})
+	for _, candidate := range candidates {
+		alias := topoproto.TabletAliasString(candidate.tablet.Alias)
+		tabletType := topoproto.TabletTypeLString(candidate.tablet.Type)
+		if candidate.reason != "" {
+			wr.Logger().Infof("backup candidate %v (%v) is not eligible: %v", alias, tabletType, candidate.reason)
+		} else {
+			wr.Logger().Infof("backup candidate %v (%v) has a replication lag of %ds", alias, tabletType, candidate.lag)
+		}
+	}
+
+	if len(candidates) > 0 && candidates[0].reason == "" {
+		chosen := candidates[0]
+		wr.Logger().Infof("selected tablet %v (%v) with a replication lag of %ds for the backup of %v/%v", topoproto.TabletAliasString(chosen.tablet.Alias), topoproto.TabletTypeLString(chosen.tablet.Type), chosen.lag, keyspace, shard)
+		return chosen.tablet, nil
+	}
+
+	isPreferredPrimary := preferredTablet != nil && primary != nil && topoproto.TabletAliasEqual(primary.Alias, preferredTablet)
+	if preferredTablet != nil && !isPreferredPrimary {
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("preferred tablet %v is not in shard %v/%v", topoproto.TabletAliasString(preferredTablet), keyspace, shard)
+		}
+		return nil, fmt.Errorf("preferred tablet %v is not eligible for a backup: %v", topoproto.TabletAliasString(preferredTablet), candidates[0].reason)
+	}
+	if primary != nil && allowPrimary {
+		wr.Logger().Infof("selected primary %v for the backup of %v/%v, as no replica is eligible", topoproto.TabletAliasString(primary.Alias), keyspace, shard)
+		return primary, nil
+	}
+	if isPreferredPrimary {
+		return nil, fmt.Errorf("preferred tablet %v is the primary, which requires allowing backups from the primary", topoproto.TabletAliasString(preferredTablet))
+	}
+	return nil, fmt.Errorf("no tablet available for a backup of %v/%v", keyspace, shard)
+}
+
+// checkBackupCandidate reads the replication status of the tablet to decide
+// if a backup can be taken from it.
+func (wr *Wrangler) checkBackupCandidate(ctx context.Context, tablet *topodatapb.Tablet) *backupCandidate {
+	candidate := &backupCandidate{tablet: tablet}
+	if !reparentutil.IsBackupCandidateType(tablet.Type) {
+		candidate.reason = fmt.Sprintf("tablet type %v cannot be backed up", topoproto.TabletTypeLString(tablet.Type))
+		return candidate
+	}
+	status, err := callTablet(ctx, "ReplicationStatus", idempotent, func(ctx context.Context) (*replicationdatapb.Status, error) {
+		return wr.tmc.ReplicationStatus(ctx, tablet)
+	})
+	switch {
+	case err != nil:
+		candidate.reason = fmt.Sprintf("cannot get replication status: %v", err)
+	case status.BackupRunning:
+		candidate.reason = "a backup is already running"
+	case status.IoState != int32(replication.ReplicationStateRunning) || status.SqlState != int32(replication.ReplicationStateRunning):
+		candidate.reason = "replication is not running"
+	case status.ReplicationLagUnknown:
+		candidate.reason = "replication lag is unknown"
+	default:
+		candidate.lag = status.ReplicationLagSeconds
+	}
+	return candidate
+}
+
+// BackupEventForwarder forwards the events streamed by a backup to the
+// wrangler logger. Progress events are forwarded at most once per interval,
+// as a line with the completion, the throughput and an ETA, and the final
+// one as a summary of the backup. Other events are forwarded as they come.
+type BackupEventForwarder struct {
+	logger   logutil.Logger
+	interval time.Duration
+
+	mu       sync.Mutex
+	lastSent time.Time
+	phase    string
+}
+
+// NewBackupEventForwarder returns a BackupEventForwarder for the wrangler
+// logger. An interval of 0 forwards every progress event.
+func (wr *Wrangler) NewBackupEventForwarder(interval time.Duration) *BackupEventForwarder {
+	return &BackupEventForwarder{
+		logger:   wr.Logger(),
+		interval: interval,
+	}
+}
+
+// Forward forwards one event of the backup stream.
+func (f *BackupEventForwarder) Forward(event *logutilpb.Event) {
+	progress, ok := mysqlctl.ParseBackupProgress(event)
+	if !ok {
+		logutil.LogEvent(f.logger, event)
+		return
+	}
+	if progress.Phase == mysqlctl.BackupPhaseDone {
+		f.logger.Infof("backup %v done: %v files, %v in %v", progress.BackupName, progress.FilesDone, humanize.IBytes(uint64(progress.BytesWritten)), progress.Elapsed.Round(time.Second))
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	if progress.Phase == f.phase && now.Sub(f.lastSent) < f.interval {
+		return
+	}
+	f.lastSent = now
+	f.phase = progress.Phase
+	f.logger.Infof("%v", formatBackupProgress(progress))
+}
+
+// formatBackupProgress describes the progress of a backup. The throughput
+// and the ETA are based on the bytes written so far.
+func formatBackupProgress(progress *mysqlctl.BackupProgress) string {
+	line := fmt.Sprintf("backup %v: %v, %v/%v files, %v/%v", progress.BackupName, progress.Phase, progress.FilesDone, progress.FilesTotal, humanize.IBytes(uint64(progress.BytesWritten)), humanize.IBytes(uint64(progress.BytesTotal)))
+	if progress.BytesTotal > 0 {
+		line += fmt.Sprintf(" (%.1f%%)", 100*float64(progress.BytesWritten)/float64(progress.BytesTotal))
+	}
+	if progress.BytesWritten <= 0 || progress.Elapsed <= 0 {
+		return line
+	}
+	throughput := float64(progress.BytesWritten) / progress.Elapsed.Seconds()
+	line += fmt.Sprintf(", %v/s", humanize.IBytes(uint64(throughput)))
+	if remaining := progress.BytesTotal - progress.BytesWritten; remaining > 0 {
+		eta := time.Duration(float64(remaining) / throughput * float64(time.Second))
+		line += fmt.Sprintf(", ETA %v", eta.Round(time.Second))
+	}
+	return line
+}
+
+// FindBackupToRestore looks up, in backup storage, the full backup that a
+// restore of the tablet would use: the one named backupName if set, or
+// else the most recent one taken at or before backupTime. It returns the
+// name of the backup and its manifest, or an error listing the available
+// backups when there is no such backup.
+func (wr *Wrangler) FindBackupToRestore(ctx context.Context, tabletAlias *topodatapb.TabletAlias, backupName string, backupTime time.Time) (string, *mysqlctl.BackupManifest, error) {
+	tablet, err := wr.ts.GetTablet(ctx, tabletAlias)
+	if err != nil {
+		return "", nil, err
+	}
+	// Tablets of a SNAPSHOT keyspace restore the backups of their base keyspace.
+	keyspace := tablet.Keyspace
+	ki, err := wr.ts.GetKeyspace(ctx, keyspace)
+	if err != nil {
+		return "", nil, err
+	}
+	if ki.KeyspaceType == topodatapb.KeyspaceType_SNAPSHOT && ki.BaseKeyspace != "" {
+		keyspace = ki.BaseKeyspace
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return "", nil, err
+	}
+	defer bs.Close()
+	backupDir := mysqlctl.GetBackupDir(keyspace, tablet.Shard)
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return "", nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+
+	// Backups are listed oldest first.
+	var available []string
+	for i := len(bhs) - 1; i >= 0; i-- {
+		bh := bhs[i]
+		manifest, err := mysqlctl.GetBackupManifest(ctx, bh)
+		if err != nil {
+			if bh.Name() == backupName {
+				return "", nil, vterrors.Wrapf(err, "backup %v in %v is incomplete", backupName, backupDir)
+			}
+			continue
+		}
+		if manifest.Incremental {
+			if bh.Name() == backupName {
+				return "", nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "backup %v in %v is incremental, only full backups can be restored by name", backupName, backupDir)
+			}
+			continue
+		}
+		available = append(available, bh.Name())
+		switch {
+		case backupName != "":
+			if bh.Name() == backupName {
+				return bh.Name(), manifest, nil
+			}
+		case !backupTime.IsZero():
+			taken, err := mysqlctl.ParseRFC3339(manifest.BackupTime)
+			if err != nil {
+				wr.Logger().Warningf("skipping backup %v/%v with invalid time %v: %v", backupDir, bh.Name(), manifest.BackupTime, err)
+				continue
+			}
+			if !taken.After(backupTime) {
+				return bh.Name(), manifest, nil
+			}
+		default:
+			return bh.Name(), manifest, nil
+		}
+	}
+
+	var wanted string
+	switch {
+	case backupName != "":
+		wanted = fmt.Sprintf("backup %v", backupName)
+	case !backupTime.IsZero():
+		wanted = fmt.Sprintf("full backup taken at or before %v", backupTime.UTC().Format(mysqlctl.BackupTimestampFormat))
+	default:
+		wanted = "full backup"
+	}
+	if len(available) == 0 {
+		return "", nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "no %v in %v, there are no complete full backups", wanted, backupDir)
+	}
+	return "", nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "no %v in %v, available backups: %v", wanted, backupDir, strings.Join(available, ", "))
+}
+
+// BackupRetentionPolicy tells RemoveBackups which backups of a shard to
+// keep. A backup is removed only if it is outside of every limit that is set.
+type BackupRetentionPolicy struct {
+	// KeepLast is the number of most recent complete full backups to keep,
+	// along with the incremental backups taken after the oldest of them.
+	KeepLast int
+	// OlderThan is the age past which backups can be removed.
+	OlderThan time.Duration
+}
+
+// RemovedBackup is a backup removed, or to be removed, by RemoveBackups.
+type RemovedBackup struct {
+	Name       string
+	BackupTime time.Time
+	Incomplete bool `json:",omitempty"`
+}
+
+// RemoveBackups removes the backups of the shard that are outside of the
+// retention policy, and returns them, oldest first. With dryRun, nothing is
+// removed. The most recent complete full backup is never removed, nor is
+// the full backup an incremental backup that is kept is based on, i.e. the
+// most recent full backup taken before it, nor the incremental backups in
+// between. Backups without a readable MANIFEST, which may still be in
+// progress, are skipped with a warning unless includeIncomplete is set, in
+// which case they are removed if they are older than the policy's
+// OlderThan, or without it, if a more recent complete full backup exists.
+//
+// If removing a backup fails, RemoveBackups returns the backups it removed
+// before along with the error.
+func (wr *Wrangler) RemoveBackups(ctx context.Context, keyspace, shard string, policy BackupRetentionPolicy, includeIncomplete, dryRun bool) ([]*RemovedBackup, error) {
+	if policy.KeepLast <= 0 && policy.OlderThan <= 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a retention policy needs a number of backups to keep or a maximum age")
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+	backupDir := mysqlctl.GetBackupDir(keyspace, shard)
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+
+	now := wr.now()
+	var (
+		toRemove []*RemovedBackup
+		// full is the number of complete full backups seen so far, newest
+		// first.
+		full int
+		// needBase is set when an incremental backup is kept, until the
+		// full backup it is based on is seen.
+		needBase bool
+	)
+	// Backups are listed oldest first.
+	for i := len(bhs) - 1; i >= 0; i-- {
+		bh := bhs[i]
+		backup := &RemovedBackup{Name: bh.Name()}
+		manifest, err := mysqlctl.GetBackupManifest(ctx, bh)
+		if err == nil {
+			backup.BackupTime, err = mysqlctl.ParseRFC3339(manifest.BackupTime)
+		} else {
+			backup.Incomplete = true
+			if !includeIncomplete {
+				wr.Logger().Warningf("skipping backup %v/%v, which is incomplete or still in progress: %v", backupDir, bh.Name(), err)
+				continue
+			}
+			var backupTime *time.Time
+			backupTime, _, err = mysqlctl.ParseBackupName(backupDir, bh.Name())
+			if err == nil && backupTime == nil {
+				err = fmt.Errorf("invalid backup time")
+			}
+			if err == nil {
+				backup.BackupTime = *backupTime
+			}
+		}
+		if err != nil {
+			wr.Logger().Warningf("skipping backup %v/%v, its time is unknown: %v", backupDir, bh.Name(), err)
+			continue
+		}
+
+		tooOld := policy.OlderThan <= 0 || now.Sub(backup.BackupTime) > policy.OlderThan
+		if backup.Incomplete {
+			if tooOld && (policy.OlderThan > 0 || full > 0) {
+				toRemove = append(toRemove, backup)
+			}
+			continue
+		}
+		if manifest.Incremental {
+			// Incremental backups don't count towards KeepLast, they are
+			// kept with the full backups taken before them.
+			beyondKeepLast := policy.KeepLast <= 0 || full >= policy.KeepLast
+			switch {
+			case needBase:
+				// A more recent incremental backup that is kept may build
+				// on this one.
+			case tooOld && beyondKeepLast:
+				toRemove = append(toRemove, backup)
+			default:
+				needBase = true
+			}
+			continue
+		}
+		full++
+		if full == 1 {
+			// Always keep the most recent complete full backup.
+			needBase = false
+			continue
+		}
+		if needBase {
+			wr.Logger().Infof("keeping backup %v/%v, which the incremental backups that are kept are based on", backupDir, bh.Name())
+			needBase = false
+			continue
+		}
+		beyondKeepLast := policy.KeepLast <= 0 || full > policy.KeepLast
+		if tooOld && beyondKeepLast {
+			toRemove = append(toRemove, backup)
+		}
+	}
+
+	// Remove the oldest backups first, so an interrupted run keeps the most
+	// recent ones.
+	sort.Slice(toRemove, func(i, j int) bool {
+		return toRemove[i].BackupTime.Before(toRemove[j].BackupTime)
+	})
+	for i, backup := range toRemove {
+		if dryRun {
+			wr.Logger().Printf("would remove backup %v/%v\n", backupDir, backup.Name)
+			continue
+		}
+		if err := bs.RemoveBackup(ctx, backupDir, backup.Name); err != nil {
+			return toRemove[:i], vterrors.Wrapf(err, "failed to remove backup %v/%v", backupDir, backup.Name)
+		}
+		wr.Logger().Infof("removed backup %v/%v", backupDir, backup.Name)
+	}
+	return toRemove, nil
+}
+
+// ValidateBackup validates the backup of the shard named backupName, or if
+// backupName is empty, its most recent backup with a MANIFEST. Backups
+// without a MANIFEST are skipped with a warning, as they may still be in
+// progress. See mysqlctl.ValidateBackup for what is checked.
+func (wr *Wrangler) ValidateBackup(ctx context.Context, keyspace, shard, backupName string) (*mysqlctl.BackupValidation, error) {
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+	backupDir := mysqlctl.GetBackupDir(keyspace, shard)
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+
+	// Backups are listed oldest first.
+	for i := len(bhs) - 1; i >= 0; i-- {
+		bh := bhs[i]
+		if backupName != "" {
+			if bh.Name() != backupName {
+				continue
+			}
+			validation, err := mysqlctl.ValidateBackup(ctx, bh)
+			if err != nil {
+				return nil, vterrors.Wrapf(err, "backup %v/%v is incomplete", backupDir, backupName)
+			}
+			return validation, nil
+		}
+		validation, err := mysqlctl.ValidateBackup(ctx, bh)
+		if err != nil {
+			wr.Logger().Warningf("skipping backup %v/%v, which is incomplete or still in progress: %v", backupDir, bh.Name(), err)
+			continue
+		}
+		return validation, nil
+	}
+	if backupName != "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "no backup %v in %v", backupName, backupDir)
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "no complete backup in %v", backupDir)
+}
+
+// ShardBackupValidation is the validation of the most recent backup of a
+// shard, or the reason why it could not be validated.
+type ShardBackupValidation struct {
+	Shard      string
+	Validation *mysqlctl.BackupValidation `json:",omitempty"`
+	Error      string                     `json:",omitempty"`
+}
+
+// Valid returns true if the shard has a valid backup.
+func (v *ShardBackupValidation) Valid() bool {
+	return v.Error == "" && v.Validation != nil && v.Validation.Valid()
+}
+
+// ValidateKeyspaceBackups validates the most recent backup of every shard
+// of the keyspace concurrently, and returns the results sorted by shard.
+func (wr *Wrangler) ValidateKeyspaceBackups(ctx context.Context, keyspace string) ([]*ShardBackupValidation, error) {
+	shards, err := wr.ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*ShardBackupValidation, len(shards))
+	wg := sync.WaitGroup{}
+	for i, shard := range shards {
+		wg.Add(1)
+		go func(i int, shard string) {
+			defer wg.Done()
+			ctx, cancel := wr.shardContext(ctx)
+			defer cancel()
+			validation, err := wr.ValidateBackup(ctx, keyspace, shard, "")
+			result := &ShardBackupValidation{Shard: shard, Validation: validation}
+			if err != nil {
+				result.Error = err.Error()
+			}
+			results[i] = result
+		}(i, shard)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Shard < results[j].Shard
+	})
+	return results, nil
+}
+
+// BackupInfo describes a backup of a shard. Without details, only the name,
+// the time and the tablet, which are parsed from the name, are set.
+type BackupInfo struct {
+	Name         string
+	BackupTime   time.Time `json:",omitzero"`
+	TabletAlias  string    `json:",omitempty"`
+	FinishedTime time.Time `json:",omitzero"`
+	Engine       string    `json:",omitempty"`
+	Incremental  bool      `json:",omitempty"`
+	// Size is the total size of the backup in backup storage, 0 if unknown.
+	Size int64 `json:",omitempty"`
+	// Incomplete is set, with details, for backups without a readable
+	// MANIFEST, which may still be in progress.
+	Incomplete bool `json:",omitempty"`
+}
+
+// ListBackups lists the backups of the shard, newest first. With detail,
+// the MANIFEST of every backup is read for its engine, its start and end
+// times, the tablet that took it and its size.
+func (wr *Wrangler) ListBackups(ctx context.Context, keyspace, shard string, detail bool) ([]*BackupInfo, error) {
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+	backupDir := mysqlctl.GetBackupDir(keyspace, shard)
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+
+	backups := make([]*BackupInfo, 0, len(bhs))
+	for _, bh := range bhs {
+		backup := &BackupInfo{Name: bh.Name()}
+		backupTime, alias, err := mysqlctl.ParseBackupName(backupDir, bh.Name())
+		if err == nil {
+			if backupTime != nil {
+				backup.BackupTime = *backupTime
+			}
+			if alias != nil {
+				backup.TabletAlias = topoproto.TabletAliasString(alias)
+			}
+		}
+		backups = append(backups, backup)
+		if !detail {
+			continue
+		}
+
+		manifest, err := mysqlctl.GetBackupManifest(ctx, bh)
+		if err != nil {
+			backup.Incomplete = true
+			continue
+		}
+		backup.Engine = manifest.BackupMethod
+		backup.Incremental = manifest.Incremental
+		if manifest.TabletAlias != "" {
+			backup.TabletAlias = manifest.TabletAlias
+		}
+		if t, err := mysqlctl.ParseRFC3339(manifest.BackupTime); err == nil {
+			backup.BackupTime = t
+		}
+		if t, err := mysqlctl.ParseRFC3339(manifest.FinishedTime); err == nil {
+			backup.FinishedTime = t
+		}
+		backup.Size, err = mysqlctl.GetBackupSize(ctx, bh, manifest)
+		if err != nil {
+			wr.Logger().Warningf("cannot get the size of backup %v/%v: %v", backupDir, bh.Name(), err)
+		}
+	}
+
+	// Backups are listed oldest first.
+	slices.Reverse(backups)
+	return backups, nil
+}
+
+// ShardBackups are the backups of a shard, newest first, as listed by
+// ListKeyspaceBackups.
+type ShardBackups struct {
+	Shard   string
+	Backups []*BackupInfo
+	// Warning is set when the shard has no recent enough backup.
+	Warning string `json:",omitempty"`
+}
+
+// ListKeyspaceBackups lists the backups of every shard of the keyspace,
+// sorted by shard. If warnOlderThan is set, a warning is set for the shards
+// whose most recent backup is older than that. Incomplete backups are only
+// told apart, and skipped, with detail.
+func (wr *Wrangler) ListKeyspaceBackups(ctx context.Context, keyspace string, detail bool, warnOlderThan time.Duration) ([]*ShardBackups, error) {
+	shards, err := wr.ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(shards)
+
+	now := wr.now()
+	results := make([]*ShardBackups, 0, len(shards))
+	for _, shard := range shards {
+		backups, err := wr.ListBackups(ctx, keyspace, shard, detail)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot list the backups of %v/%v", keyspace, shard)
+		}
+		result := &ShardBackups{Shard: shard, Backups: backups}
+		results = append(results, result)
+		if warnOlderThan <= 0 {
+			continue
+		}
+		i := slices.IndexFunc(backups, func(backup *BackupInfo) bool {
+			return !backup.Incomplete && !backup.BackupTime.IsZero()
+		})
+		switch {
+		case i < 0:
+			result.Warning = "no backup"
+		case now.Sub(backups[i].BackupTime) > warnOlderThan:
+			result.Warning = fmt.Sprintf("newest backup %v is %v old", backups[i].Name, now.Sub(backups[i].BackupTime).Round(time.Minute))
+		}
+	}
+	return results, nil
+}
+
+// BackupKeyspaceOptions are the options of BackupKeyspace.
+type BackupKeyspaceOptions struct {
+	// MaxConcurrentShards is how many shards are backed up at the same time.
+	MaxConcurrentShards int
+	// Stagger is the minimum delay between the starts of two shard backups.
+	Stagger time.Duration
+	// SkipRecent skips the shards that have a complete backup more recent
+	// than that, to resume an interrupted run.
+	SkipRecent time.Duration
+	// ProgressInterval is how often the progress of every backup is logged.
+	ProgressInterval time.Duration
+
+	AllowPrimary         bool
+	Concurrency          int32
+	UpgradeSafe          bool
+	MysqlShutdownTimeout time.Duration
+}
+
+// Statuses of a shard in a ShardBackupReport.
+const (
+	ShardBackupSucceeded = "succeeded"
+	ShardBackupFailed    = "failed"
+	ShardBackupSkipped   = "skipped"
+	ShardBackupCanceled  = "canceled"
+)
+
+// ShardBackupReport is the outcome of the backup of a shard by
+// BackupKeyspace.
+type ShardBackupReport struct {
+	Shard      string
+	Status     string
+	Tablet     string        `json:",omitempty"`
+	BackupName string        `json:",omitempty"`
+	Duration   time.Duration `json:",omitempty"`
+	// Reason is why the backup failed or was skipped.
+	Reason string `json:",omitempty"`
+}
+
+// BackupKeyspace backs up every shard of the keyspace, at most
+// opts.MaxConcurrentShards at a time, starting the backups at least
+// opts.Stagger apart. The tablet of every shard is picked as in
+// SelectBackupTablet. It returns a report for every shard, sorted by shard,
+// even if some backups failed.
+//
+// It is a long-running operation: once canceled with CancelOperation, it
+// starts no more shard backups, and waits for the running ones to finish.
+func (wr *Wrangler) BackupKeyspace(ctx context.Context, keyspace string, opts BackupKeyspaceOptions) ([]*ShardBackupReport, error) {
+	if opts.MaxConcurrentShards <= 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the maximum number of concurrent shard backups must be positive")
+	}
+	ctx, op := wr.startOperation(ctx, "BackupKeyspace", keyspace, false /*interruptible*/)
+	defer op.finish()
+	shards, err := wr.ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(shards)
+
+	var (
+		reports  = make([]*ShardBackupReport, len(shards))
+		toBackup []int
+	)
+	for i, shard := range shards {
+		reports[i] = &ShardBackupReport{Shard: shard}
+		if opts.SkipRecent <= 0 {
+			toBackup = append(toBackup, i)
+			continue
+		}
+		recent, err := wr.recentBackup(ctx, keyspace, shard, wr.now().Add(-opts.SkipRecent))
+		switch {
+		case err != nil:
+			return nil, vterrors.Wrapf(err, "cannot list the backups of %v/%v", keyspace, shard)
+		case recent != nil:
+			reports[i].Status = ShardBackupSkipped
+			reports[i].BackupName = recent.Name
+			reports[i].Reason = fmt.Sprintf("backup %v was taken less than %v ago", recent.Name, opts.SkipRecent)
+			wr.Logger().Infof("skipping %v/%v: %v", keyspace, shard, reports[i].Reason)
+		default:
+			toBackup = append(toBackup, i)
+		}
+	}
+
+	sem := make(chan struct{}, opts.MaxConcurrentShards)
+	wg := sync.WaitGroup{}
+	var lastStart time.Time
+	for _, i := range toBackup {
+		report := reports[i]
+		err := func() error {
+			if op.canceled() {
+				return op.errCanceled()
+			}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			if wait := time.Until(lastStart.Add(opts.Stagger)); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-ctx.Done():
+					<-sem
+					return ctx.Err()
+				}
+			}
+			if op.canceled() {
+				<-sem
+				return op.errCanceled()
+			}
+			return nil
+		}()
+		if err != nil {
+			report.Status = ShardBackupFailed
+			if op.canceled() {
+				report.Status = ShardBackupCanceled
+			}
+			report.Reason = fmt.Sprintf("not started: %v", err)
+			continue
+		}
+		lastStart = time.Now()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			wr.backupKeyspaceShard(ctx, keyspace, report, opts)
+		}()
+	}
+	wg.Wait()
+	return reports, nil
+}
+
+// backupKeyspaceShard backs up one shard for BackupKeyspace, and fills its
+// report.
+func (wr *Wrangler) backupKeyspaceShard(ctx context.Context, keyspace string, report *ShardBackupReport, opts BackupKeyspaceOptions) {
+	span, ctx := trace.NewSpan(ctx, "Wrangler.backupKeyspaceShard")
+	defer span.Finish()
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("shard", report.Shard)
+
+	start := time.Now()
+	err := func() error {
+		tablet, err := wr.SelectBackupTablet(ctx, keyspace, report.Shard, opts.AllowPrimary, nil)
+		if err != nil {
+			return err
+		}
+		report.Tablet = topoproto.TabletAliasString(tablet.Alias)
+		wr.Logger().Infof("starting the backup of %v/%v on %v", keyspace, report.Shard, report.Tablet)
+
+		stream, err := wr.tmc.Backup(ctx, tablet, &tabletmanagerdatapb.BackupRequest{
+			Concurrency:          opts.Concurrency,
+			AllowPrimary:         opts.AllowPrimary,
+			UpgradeSafe:          opts.UpgradeSafe,
+			MysqlShutdownTimeout: protoutil.DurationToProto(opts.MysqlShutdownTimeout),
+		})
+		if err != nil {
+			return err
+		}
+		forwarder := wr.NewBackupEventForwarder(opts.ProgressInterval)
+		for {
+			event, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			forwarder.Forward(event)
+		}
+
+		// The backup is named after its start time and the tablet.
+		backups, err := wr.ListBackups(ctx, keyspace, report.Shard, false)
+		if err != nil {
+			return vterrors.Wrap(err, "the backup is done, but cannot be listed")
+		}
+		for _, backup := range backups {
+			if backup.TabletAlias == report.Tablet && !backup.BackupTime.Before(start.Truncate(time.Second)) {
+				report.BackupName = backup.Name
+				break
+			}
+		}
+		return nil
+	}()
+	report.Duration = time.Since(start)
+	if err != nil {
+		report.Status = ShardBackupFailed
+		report.Reason = err.Error()
+		wr.Logger().Errorf("backup of %v/%v failed after %v: %v", keyspace, report.Shard, report.Duration.Round(time.Second), err)
+		return
+	}
+	report.Status = ShardBackupSucceeded
+	wr.Logger().Infof("backup of %v/%v done in %v: %v", keyspace, report.Shard, report.Duration.Round(time.Second), report.BackupName)
+}
+
+// recentBackup returns the most recent complete backup of the shard if it
+// was taken after since, or nil.
+func (wr *Wrangler) recentBackup(ctx context.Context, keyspace, shard string, since time.Time) (*BackupInfo, error) {
+	backups, err := wr.ListBackups(ctx, keyspace, shard, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, backup := range backups {
+		if backup.Incomplete {
+			continue
+		}
+		if backup.BackupTime.After(since) {
+			return backup, nil
+		}
+		return nil, nil
+	}
+	return nil, nil
+}
+
+// PointInTimeRestore is the outcome of RestoreShardToPointInTime.
+type PointInTimeRestore struct {
+	Tablet string
+	// BackupName is the full backup the restore started from, and
+	// IncrementalBackups the incremental backups applied on top of it.
+	BackupName         string
+	IncrementalBackups []string `json:",omitempty"`
+	// Position is the position the tablet reached.
+	Position string
+}
+
+// RestoreShardToPointInTime restores a tablet of the shard to a point in
+// time, given as either restoreToTimestamp or restoreToPos. The best full
+// backup taken before that point is picked, and the tablet restores it and
+// applies the binary logs of the incremental backups up to that point. The
+// tablet is left DRAINED, with replication disabled, at the returned
+// position, so the other tablets of the shard can be reparented to it or
+// cloned from it. The shard must not be serving, unless force is set.
+func (wr *Wrangler) RestoreShardToPointInTime(ctx context.Context, keyspace, shard string, tabletAlias *topodatapb.TabletAlias, restoreToTimestamp time.Time, restoreToPos string, force bool) (*PointInTimeRestore, error) {
+	if restoreToTimestamp.IsZero() == (restoreToPos == "") {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "exactly one of a timestamp or a position to restore to is required")
+	}
+	var restoreToGTIDSet replication.GTIDSet
+	if restoreToPos != "" {
+		pos, _, err := replication.DecodePositionMySQL56(restoreToPos)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot decode the position to restore to %v", restoreToPos)
+		}
+		restoreToGTIDSet = pos.GTIDSet
+	}
+
+	si, err := wr.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	if si.IsPrimaryServing {
+		if !force {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %v/%v is serving, a point in time restore would leave it inconsistent, use force to restore anyway", keyspace, shard)
+		}
+		wr.Logger().Warningf("shard %v/%v is serving, restoring it to a point in time anyway", keyspace, shard)
+	}
+	ti, err := wr.ts.GetTablet(ctx, tabletAlias)
+	if err != nil {
+		return nil, err
+	}
+	if ti.Keyspace != keyspace || ti.Shard != shard {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "tablet %v is in shard %v/%v, not %v/%v", topoproto.TabletAliasString(tabletAlias), ti.Keyspace, ti.Shard, keyspace, shard)
+	}
+	if ti.Type == topodatapb.TabletType_PRIMARY {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "tablet %v is the primary of shard %v/%v, it cannot be restored to a point in time", topoproto.TabletAliasString(tabletAlias), keyspace, shard)
+	}
+
+	// Pick the backups the tablet will restore, as it would, to report them
+	// and to fail early if there are none.
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+	backupDir := mysqlctl.GetBackupDir(keyspace, shard)
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	var manifests []*mysqlctl.BackupManifest
+	for _, bh := range bhs {
+		manifest, err := mysqlctl.GetBackupManifest(ctx, bh)
+		if err != nil {
+			wr.Logger().Warningf("skipping backup %v/%v, which is incomplete or still in progress: %v", backupDir, bh.Name(), err)
+			continue
+		}
+		manifests = append(manifests, manifest)
+	}
+	var path []*mysqlctl.BackupManifest
+	if restoreToGTIDSet != nil {
+		path, err = mysqlctl.FindPITRPath(restoreToGTIDSet, manifests)
+	} else {
+		path, err = mysqlctl.FindPITRToTimePath(restoreToTimestamp, manifests)
+	}
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot find backups to restore %v/%v from", keyspace, shard)
+	}
+	result := &PointInTimeRestore{
+		Tablet:     topoproto.TabletAliasString(tabletAlias),
+		BackupName: path[0].BackupName,
+	}
+	for _, manifest := range path[1:] {
+		result.IncrementalBackups = append(result.IncrementalBackups, manifest.BackupName)
+	}
+	wr.Logger().Infof("restoring %v from backup %v and %v incremental backups", result.Tablet, result.BackupName, len(result.IncrementalBackups))
+
+	stream, err := wr.tmc.RestoreFromBackup(ctx, ti.Tablet, &tabletmanagerdatapb.RestoreFromBackupRequest{
+		RestoreToPos:       restoreToPos,
+		RestoreToTimestamp: protoutil.TimeToProto(restoreToTimestamp),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "restore of %v failed", result.Tablet)
+		}
+		logutil.LogEvent(wr.Logger(), event)
+	}
+
+	result.Position, err = callTablet(ctx, "PrimaryPosition", idempotent, func(ctx context.Context) (string, error) {
+		return wr.tmc.PrimaryPosition(ctx, ti.Tablet)
+	})
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "%v was restored, but its position is unknown", result.Tablet)
+	}
+	wr.Logger().Infof("%v was restored to position %v", result.Tablet, result.Position)
+	return result, nil
+}
@@ -0,0 +1,749 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// backupStatusTMClient serves ReplicationStatus from a per-tablet map.
+type backupStatusTMClient struct {
+	tmclient.TabletManagerClient
+
+	mu       sync.Mutex
+	statuses map[uint32]*replicationdatapb.Status
+}
+
+func (tmc *backupStatusTMClient) ReplicationStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.Status, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	status, ok := tmc.statuses[tablet.Alias.Uid]
+	if !ok {
+		return nil, fmt.Errorf("not a replica")
+	}
+	return status, nil
+}
+
+func replicatingStatus(lag uint32) *replicationdatapb.Status {
+	return &replicationdatapb.Status{
+		IoState:               int32(replication.ReplicationStateRunning),
+		SqlState:              int32(replication.ReplicationStateRunning),
+		ReplicationLagSeconds: lag,
+	}
+}
+
+func TestSelectBackupTablet(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	stopped := replicatingStatus(0)
+	stopped.SqlState = int32(replication.ReplicationStateStopped)
+	backingUp := replicatingStatus(0)
+	backingUp.BackupRunning = true
+	tmc := &backupStatusTMClient{
+		statuses: map[uint32]*replicationdatapb.Status{
+			101: replicatingStatus(0),
+			102: replicatingStatus(0),
+			103: replicatingStatus(300),
+			104: stopped,
+			105: backingUp,
+			106: replicatingStatus(0),
+		},
+	}
+	logger := logutil.NewMemoryLogger()
+	wr := New(vtenv.NewTestEnv(), logger, ts, tmc)
+
+	for uid, tabletType := range map[uint32]topodatapb.TabletType{
+		100: topodatapb.TabletType_PRIMARY,
+		101: topodatapb.TabletType_REPLICA,
+		102: topodatapb.TabletType_RDONLY,
+		103: topodatapb.TabletType_RDONLY,
+		104: topodatapb.TabletType_REPLICA,
+		105: topodatapb.TabletType_RDONLY,
+		106: topodatapb.TabletType_DRAINED,
+	} {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    "0",
+			Type:     tabletType,
+		}
+		err := ts.InitTablet(ctx, tablet, true /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+	}
+	alias := func(uid uint32) *topodatapb.TabletAlias {
+		return &topodatapb.TabletAlias{Cell: "cell1", Uid: uid}
+	}
+
+	// 101 and 102 are equally caught up, the RDONLY one wins.
+	tablet, err := wr.SelectBackupTablet(ctx, "ks", "0", false, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 102, tablet.Alias.Uid)
+	logs := logger.String()
+	require.Contains(t, logs, "backup candidate cell1-0000000103 (rdonly) has a replication lag of 300s")
+	require.Contains(t, logs, "backup candidate cell1-0000000104 (replica) is not eligible: replication is not running")
+	require.Contains(t, logs, "backup candidate cell1-0000000105 (rdonly) is not eligible: a backup is already running")
+	require.Contains(t, logs, "backup candidate cell1-0000000106 (drained) is not eligible: tablet type drained cannot be backed up")
+	require.Contains(t, logs, "selected tablet cell1-0000000102 (rdonly) with a replication lag of 0s for the backup of ks/0")
+
+	t.Run("type beats lag", func(t *testing.T) {
+		tmc.statuses[102] = replicatingStatus(10)
+		defer func() { tmc.statuses[102] = replicatingStatus(0) }()
+		tablet, err := wr.SelectBackupTablet(ctx, "ks", "0", false, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, 102, tablet.Alias.Uid)
+
+		// Among the RDONLY tablets, the least lagged one wins.
+		tmc.statuses[102] = replicatingStatus(600)
+		tablet, err = wr.SelectBackupTablet(ctx, "ks", "0", false, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, 103, tablet.Alias.Uid)
+	})
+
+	t.Run("max lag beats type", func(t *testing.T) {
+		// The RDONLY tablets lag more than the max lag, the caught up
+		// REPLICA wins.
+		tmc.statuses[102] = replicatingStatus(600)
+		tmc.statuses[103] = replicatingStatus(400)
+		defer func() {
+			tmc.statuses[102] = replicatingStatus(0)
+			tmc.statuses[103] = replicatingStatus(300)
+		}()
+		tablet, err := wr.SelectBackupTablet(ctx, "ks", "0", false, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, 101, tablet.Alias.Uid)
+	})
+
+	t.Run("preferred tablet", func(t *testing.T) {
+		tablet, err := wr.SelectBackupTablet(ctx, "ks", "0", false, alias(103))
+		require.NoError(t, err)
+		require.EqualValues(t, 103, tablet.Alias.Uid)
+
+		_, err = wr.SelectBackupTablet(ctx, "ks", "0", false, alias(104))
+		require.ErrorContains(t, err, "preferred tablet cell1-0000000104 is not eligible for a backup: replication is not running")
+
+		_, err = wr.SelectBackupTablet(ctx, "ks", "0", true, alias(106))
+		require.ErrorContains(t, err, "tablet type drained cannot be backed up")
+
+		_, err = wr.SelectBackupTablet(ctx, "ks", "0", true, alias(999))
+		require.ErrorContains(t, err, "preferred tablet cell1-0000000999 is not in shard ks/0")
+
+		_, err = wr.SelectBackupTablet(ctx, "ks", "0", false, alias(100))
+		require.ErrorContains(t, err, "preferred tablet cell1-0000000100 is the primary")
+
+		tablet, err = wr.SelectBackupTablet(ctx, "ks", "0", true, alias(100))
+		require.NoError(t, err)
+		require.EqualValues(t, 100, tablet.Alias.Uid)
+	})
+
+	t.Run("no eligible replica", func(t *testing.T) {
+		for _, uid := range []uint32{101, 102, 103} {
+			tmc.statuses[uid] = stopped
+		}
+		_, err := wr.SelectBackupTablet(ctx, "ks", "0", false, nil)
+		require.ErrorContains(t, err, "no tablet available for a backup of ks/0")
+
+		tablet, err := wr.SelectBackupTablet(ctx, "ks", "0", true, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, 100, tablet.Alias.Uid)
+	})
+}
+
+func TestBackupEventForwarder(t *testing.T) {
+	progressEvent := func(progress mysqlctl.BackupProgress) *logutilpb.Event {
+		data, err := json.Marshal(progress)
+		require.NoError(t, err)
+		return &logutilpb.Event{Level: logutilpb.Level_INFO, Value: "backup progress: " + string(data)}
+	}
+	copying := mysqlctl.BackupProgress{
+		BackupName:   "2025-01-01.000000.cell1-0000000101",
+		Phase:        mysqlctl.BackupPhaseCopyingFiles,
+		FilesTotal:   4,
+		FilesDone:    1,
+		BytesTotal:   4 << 30,
+		BytesWritten: 1 << 30,
+		Elapsed:      10 * time.Second,
+	}
+
+	logger := logutil.NewMemoryLogger()
+	wr := New(vtenv.NewTestEnv(), logger, nil, nil)
+	forwarder := wr.NewBackupEventForwarder(time.Hour)
+
+	forwarder.Forward(&logutilpb.Event{Level: logutilpb.Level_INFO, Value: "Backing up file: ibdata1"})
+	forwarder.Forward(progressEvent(copying))
+	// Within the interval, and in the same phase.
+	forwarder.Forward(progressEvent(copying))
+	manifest := copying
+	manifest.Phase = mysqlctl.BackupPhaseWritingManifest
+	forwarder.Forward(progressEvent(manifest))
+	done := copying
+	done.Phase = mysqlctl.BackupPhaseDone
+	done.FilesDone = 4
+	done.BytesWritten = 4 << 30
+	done.Elapsed = 40 * time.Second
+	forwarder.Forward(progressEvent(done))
+
+	require.Len(t, logger.Events, 4)
+	require.Contains(t, logger.Events[0].Value, "Backing up file: ibdata1")
+	require.Equal(t, "backup 2025-01-01.000000.cell1-0000000101: copying files, 1/4 files, 1.0 GiB/4.0 GiB (25.0%), 102 MiB/s, ETA 30s", logger.Events[1].Value)
+	require.Contains(t, logger.Events[2].Value, "writing manifest")
+	require.Equal(t, "backup 2025-01-01.000000.cell1-0000000101 done: 4 files, 4.0 GiB in 40s", logger.Events[3].Value)
+}
+
+// useFileBackupStorage points the backup storage to a temporary directory
+// for the duration of the test.
+func useFileBackupStorage(t *testing.T) {
+	oldRoot, oldImplementation := filebackupstorage.FileBackupStorageRoot, backupstorage.BackupStorageImplementation
+	t.Cleanup(func() {
+		filebackupstorage.FileBackupStorageRoot, backupstorage.BackupStorageImplementation = oldRoot, oldImplementation
+	})
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	backupstorage.BackupStorageImplementation = "file"
+}
+
+// failingRemoveBackupStorage is a backup storage that fails to remove the
+// backup named failName.
+type failingRemoveBackupStorage struct {
+	backupstorage.BackupStorage
+	failName string
+}
+
+func (bs *failingRemoveBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
+	if name == bs.failName {
+		return errors.New("permission denied")
+	}
+	return bs.BackupStorage.RemoveBackup(ctx, dir, name)
+}
+
+// writeTestBackup creates a backup of ks/0, with a MANIFEST unless manifest
+// is nil.
+func writeTestBackup(t *testing.T, name string, manifest *mysqlctl.BackupManifest) {
+	dir := path.Join(filebackupstorage.FileBackupStorageRoot, "ks", "0", name)
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	if manifest == nil {
+		return
+	}
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path.Join(dir, "MANIFEST"), data, 0o644))
+}
+
+func TestFindBackupToRestore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	useFileBackupStorage(t)
+	writeBackup := func(name string, manifest *mysqlctl.BackupManifest) {
+		writeTestBackup(t, name, manifest)
+	}
+	writeBackup("2025-01-01.000000.cell1-0000000101", &mysqlctl.BackupManifest{BackupMethod: "builtin", BackupTime: "2025-01-01T00:00:00Z"})
+	writeBackup("2025-01-02.000000.cell1-0000000101", &mysqlctl.BackupManifest{BackupMethod: "builtin", BackupTime: "2025-01-02T00:00:00Z"})
+	writeBackup("2025-01-02.120000.cell1-0000000101", &mysqlctl.BackupManifest{BackupMethod: "builtin", BackupTime: "2025-01-02T12:00:00Z", Incremental: true})
+	writeBackup("2025-01-03.000000.cell1-0000000101", nil)
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	tabletAlias := &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}
+	err := ts.InitTablet(ctx, &topodatapb.Tablet{
+		Alias:    tabletAlias,
+		Keyspace: "ks",
+		Shard:    "0",
+		Type:     topodatapb.TabletType_REPLICA,
+	}, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+	require.NoError(t, err)
+	wr := New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, nil)
+
+	backupTime := func(value string) time.Time {
+		tm, err := time.Parse(mysqlctl.BackupTimestampFormat, value)
+		require.NoError(t, err)
+		return tm
+	}
+
+	name, manifest, err := wr.FindBackupToRestore(ctx, tabletAlias, "2025-01-01.000000.cell1-0000000101", time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, "2025-01-01.000000.cell1-0000000101", name)
+	require.Equal(t, "2025-01-01T00:00:00Z", manifest.BackupTime)
+
+	name, _, err = wr.FindBackupToRestore(ctx, tabletAlias, "", backupTime("2025-01-02.235959"))
+	require.NoError(t, err)
+	require.Equal(t, "2025-01-02.000000.cell1-0000000101", name)
+
+	_, _, err = wr.FindBackupToRestore(ctx, tabletAlias, "2024-12-31.000000.cell1-0000000101", time.Time{})
+	require.ErrorContains(t, err, "no backup 2024-12-31.000000.cell1-0000000101 in ks/0, available backups: 2025-01-02.000000.cell1-0000000101, 2025-01-01.000000.cell1-0000000101")
+
+	_, _, err = wr.FindBackupToRestore(ctx, tabletAlias, "", backupTime("2024-12-31.000000"))
+	require.ErrorContains(t, err, "no full backup taken at or before 2024-12-31.000000 in ks/0, available backups:")
+
+	_, _, err = wr.FindBackupToRestore(ctx, tabletAlias, "2025-01-02.120000.cell1-0000000101", time.Time{})
+	require.ErrorContains(t, err, "is incremental")
+
+	_, _, err = wr.FindBackupToRestore(ctx, tabletAlias, "2025-01-03.000000.cell1-0000000101", time.Time{})
+	require.ErrorContains(t, err, "is incomplete")
+}
+
+func TestRemoveBackups(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Backups taken every day at midnight for the last five days, and one
+	// incomplete backup from two days ago, and one in progress.
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	name := func(daysAgo int) string {
+		return today.AddDate(0, 0, -daysAgo).Format(mysqlctl.BackupTimestampFormat) + ".cell1-0000000101"
+	}
+	setup := func(t *testing.T) {
+		useFileBackupStorage(t)
+		for daysAgo := 1; daysAgo <= 5; daysAgo++ {
+			writeTestBackup(t, name(daysAgo), &mysqlctl.BackupManifest{
+				BackupMethod: "builtin",
+				BackupTime:   mysqlctl.FormatRFC3339(today.AddDate(0, 0, -daysAgo)),
+			})
+		}
+		writeTestBackup(t, today.AddDate(0, 0, -2).Add(time.Hour).Format(mysqlctl.BackupTimestampFormat)+".cell1-0000000102", nil)
+		writeTestBackup(t, name(0), nil)
+	}
+	removedNames := func(removed []*RemovedBackup) []string {
+		var names []string
+		for _, backup := range removed {
+			names = append(names, backup.Name)
+		}
+		return names
+	}
+	listBackups := func(t *testing.T) []string {
+		entries, err := os.ReadDir(path.Join(filebackupstorage.FileBackupStorageRoot, "ks", "0"))
+		require.NoError(t, err)
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		return names
+	}
+	// The backup ages are a whole number of days plus an hour.
+	wr := NewWithOptions(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), nil, WithClock(func() time.Time {
+		return today.Add(time.Hour)
+	}))
+
+	t.Run("keep last", func(t *testing.T) {
+		setup(t)
+		removed, err := wr.RemoveBackups(ctx, "ks", "0", BackupRetentionPolicy{KeepLast: 2}, false, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{name(5), name(4), name(3)}, removedNames(removed))
+		require.Len(t, listBackups(t), 4)
+	})
+
+	t.Run("keep last and older than", func(t *testing.T) {
+		setup(t)
+		removed, err := wr.RemoveBackups(ctx, "ks", "0", BackupRetentionPolicy{KeepLast: 2, OlderThan: 84 * time.Hour}, false, true)
+		require.NoError(t, err)
+		require.Equal(t, []string{name(5), name(4)}, removedNames(removed))
+		// Nothing removed in a dry run.
+		require.Len(t, listBackups(t), 7)
+	})
+
+	t.Run("never removes the last complete backup", func(t *testing.T) {
+		setup(t)
+		removed, err := wr.RemoveBackups(ctx, "ks", "0", BackupRetentionPolicy{OlderThan: time.Minute}, false, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{name(5), name(4), name(3), name(2)}, removedNames(removed))
+		require.ElementsMatch(t, []string{name(1), name(0), today.AddDate(0, 0, -2).Add(time.Hour).Format(mysqlctl.BackupTimestampFormat) + ".cell1-0000000102"}, listBackups(t))
+	})
+
+	t.Run("include incomplete", func(t *testing.T) {
+		setup(t)
+		removed, err := wr.RemoveBackups(ctx, "ks", "0", BackupRetentionPolicy{KeepLast: 4}, true, false)
+		require.NoError(t, err)
+		// The backup in progress is more recent than any complete backup, so it is kept.
+		require.Equal(t, []string{name(5), today.AddDate(0, 0, -2).Add(time.Hour).Format(mysqlctl.BackupTimestampFormat) + ".cell1-0000000102"}, removedNames(removed))
+		require.Contains(t, listBackups(t), name(0))
+	})
+
+	// setupIncremental writes full backups taken six, five and three days
+	// ago, and incremental backups taken four, two and one days ago.
+	setupIncremental := func(t *testing.T) {
+		useFileBackupStorage(t)
+		for daysAgo := 1; daysAgo <= 6; daysAgo++ {
+			if daysAgo == 3 || daysAgo == 5 || daysAgo == 6 {
+				writeTestBackup(t, name(daysAgo), &mysqlctl.BackupManifest{
+					BackupMethod: "builtin",
+					BackupTime:   mysqlctl.FormatRFC3339(today.AddDate(0, 0, -daysAgo)),
+				})
+				continue
+			}
+			writeTestBackup(t, name(daysAgo), &mysqlctl.BackupManifest{
+				BackupMethod: "builtin",
+				BackupTime:   mysqlctl.FormatRFC3339(today.AddDate(0, 0, -daysAgo)),
+				Incremental:  true,
+			})
+		}
+	}
+
+	t.Run("incremental backups don't count towards keep last", func(t *testing.T) {
+		setupIncremental(t)
+		removed, err := wr.RemoveBackups(ctx, "ks", "0", BackupRetentionPolicy{KeepLast: 1}, false, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{name(6), name(5), name(4)}, removedNames(removed))
+		require.ElementsMatch(t, []string{name(3), name(2), name(1)}, listBackups(t))
+	})
+
+	t.Run("keeps the full backup of the incremental backups that are kept", func(t *testing.T) {
+		setupIncremental(t)
+		removed, err := wr.RemoveBackups(ctx, "ks", "0", BackupRetentionPolicy{OlderThan: 108 * time.Hour}, false, false)
+		require.NoError(t, err)
+		// The incremental backup of four days ago is recent enough, so the
+		// full backup of five days ago it is based on is kept.
+		require.Equal(t, []string{name(6)}, removedNames(removed))
+	})
+
+	t.Run("returns the removed backups on error", func(t *testing.T) {
+		setup(t)
+		// The oldest backup is removed, the next one cannot be.
+		backupstorage.BackupStorageMap["failing"] = &failingRemoveBackupStorage{
+			BackupStorage: backupstorage.BackupStorageMap["file"],
+			failName:      name(4),
+		}
+		backupstorage.BackupStorageImplementation = "failing"
+		t.Cleanup(func() { delete(backupstorage.BackupStorageMap, "failing") })
+		removed, err := wr.RemoveBackups(ctx, "ks", "0", BackupRetentionPolicy{KeepLast: 2}, false, false)
+		require.ErrorContains(t, err, "failed to remove backup ks/0/"+name(4))
+		require.Equal(t, []string{name(5)}, removedNames(removed))
+	})
+
+	t.Run("requires a policy", func(t *testing.T) {
+		_, err := wr.RemoveBackups(ctx, "ks", "0", BackupRetentionPolicy{}, false, false)
+		require.ErrorContains(t, err, "a retention policy needs")
+	})
+}
+
+func TestValidateKeyspaceBackups(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	useFileBackupStorage(t)
+	// writeBackup writes a builtin backup of ks/<shard> made of one file.
+	writeBackup := func(shard, name, hash, data string) {
+		dir := path.Join(filebackupstorage.FileBackupStorageRoot, "ks", shard, name)
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		manifest, err := json.Marshal(map[string]any{
+			"BackupMethod": "builtin",
+			"BackupTime":   "2025-01-01T00:00:00Z",
+			"FileEntries":  []map[string]any{{"Name": "ibdata1", "Hash": hash, "Size": 4}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path.Join(dir, "MANIFEST"), manifest, 0o644))
+		require.NoError(t, os.WriteFile(path.Join(dir, "0"), []byte(data), 0o644))
+	}
+	// The crc32 of "data".
+	const hash = "adf3f363"
+	writeBackup("-80", "2025-01-01.000000.cell1-0000000101", hash, "data")
+	// The most recent backup is in progress, the previous one is validated.
+	writeBackup("80-c0", "2025-01-01.000000.cell1-0000000201", hash, "dat")
+	require.NoError(t, os.MkdirAll(path.Join(filebackupstorage.FileBackupStorageRoot, "ks", "80-c0", "2025-01-02.000000.cell1-0000000201"), 0o755))
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	for _, shard := range []string{"80-c0", "-80", "c0-"} {
+		require.NoError(t, ts.CreateShard(ctx, "ks", shard))
+	}
+	wr := New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, nil)
+
+	validation, err := wr.ValidateBackup(ctx, "ks", "-80", "")
+	require.NoError(t, err)
+	require.True(t, validation.Valid(), validation.Errors)
+	require.Equal(t, "2025-01-01.000000.cell1-0000000101", validation.BackupName)
+
+	_, err = wr.ValidateBackup(ctx, "ks", "80-c0", "2025-01-02.000000.cell1-0000000201")
+	require.ErrorContains(t, err, "is incomplete")
+
+	results, err := wr.ValidateKeyspaceBackups(ctx, "ks")
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.Equal(t, "-80", results[0].Shard)
+	require.True(t, results[0].Valid())
+	require.Equal(t, "80-c0", results[1].Shard)
+	require.False(t, results[1].Valid())
+	require.Equal(t, "2025-01-01.000000.cell1-0000000201", results[1].Validation.BackupName)
+	require.Equal(t, []string{"file 0: size mismatch, got 3 bytes expected 4"}, results[1].Validation.Errors)
+	require.Equal(t, "c0-", results[2].Shard)
+	require.Contains(t, results[2].Error, "no complete backup in ks/c0-")
+}
+
+func TestListBackups(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	useFileBackupStorage(t)
+	writeTestBackup(t, "2025-01-01.000000.cell1-0000000101", &mysqlctl.BackupManifest{
+		BackupMethod: "xtrabackup",
+		BackupTime:   "2025-01-01T00:00:00Z",
+		FinishedTime: "2025-01-01T00:10:00Z",
+		TabletAlias:  "cell1-0000000101",
+	})
+	// A builtin backup that records the size of its files.
+	dir := path.Join(filebackupstorage.FileBackupStorageRoot, "ks", "0", "2025-01-02.000000.cell1-0000000102")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	manifest, err := json.Marshal(map[string]any{
+		"BackupMethod": "builtin",
+		"BackupTime":   "2025-01-02T00:00:00Z",
+		"TabletAlias":  "cell1-0000000102",
+		"FileEntries":  []map[string]any{{"Name": "ibdata1", "Size": 1024}, {"Name": "t1.ibd", "Size": 2048}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path.Join(dir, "MANIFEST"), manifest, 0o644))
+	writeTestBackup(t, "2025-01-03.000000.cell1-0000000101", nil)
+
+	wr := New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), nil, nil)
+
+	backups, err := wr.ListBackups(ctx, "ks", "0", false)
+	require.NoError(t, err)
+	require.Len(t, backups, 3)
+	require.Equal(t, &BackupInfo{
+		Name:        "2025-01-03.000000.cell1-0000000101",
+		BackupTime:  time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+		TabletAlias: "cell1-0000000101",
+	}, backups[0])
+
+	backups, err = wr.ListBackups(ctx, "ks", "0", true)
+	require.NoError(t, err)
+	require.Len(t, backups, 3)
+	require.True(t, backups[0].Incomplete)
+	require.Equal(t, "builtin", backups[1].Engine)
+	require.EqualValues(t, 3072, backups[1].Size)
+	require.Equal(t, "cell1-0000000102", backups[1].TabletAlias)
+	require.Equal(t, "xtrabackup", backups[2].Engine)
+	// xtrabackup doesn't record the size of its files.
+	require.Zero(t, backups[2].Size)
+	require.Equal(t, 10*time.Minute, backups[2].FinishedTime.Sub(backups[2].BackupTime))
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "0"))
+	wr = New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, nil)
+
+	results, err := wr.ListKeyspaceBackups(ctx, "ks", true, 24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Len(t, results[0].Backups, 3)
+	// The incomplete backup doesn't count.
+	require.Contains(t, results[0].Warning, "newest backup 2025-01-02.000000.cell1-0000000102 is")
+
+	results, err = wr.ListKeyspaceBackups(ctx, "ks", false, 0)
+	require.NoError(t, err)
+	require.Empty(t, results[0].Warning)
+}
+
+// backupTMClient takes backups of ks/<shard> in the file backup storage.
+type backupTMClient struct {
+	backupStatusTMClient
+
+	// fail lists the tablets whose backups fail.
+	fail map[uint32]bool
+
+	running, maxRunning atomic.Int32
+}
+
+type eventStream []*logutilpb.Event
+
+func (s *eventStream) Recv() (*logutilpb.Event, error) {
+	if len(*s) == 0 {
+		return nil, io.EOF
+	}
+	event := (*s)[0]
+	*s = (*s)[1:]
+	return event, nil
+}
+
+func (tmc *backupTMClient) Backup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.BackupRequest) (logutil.EventStream, error) {
+	running := tmc.running.Add(1)
+	defer tmc.running.Add(-1)
+	for {
+		maxRunning := tmc.maxRunning.Load()
+		if running <= maxRunning || tmc.maxRunning.CompareAndSwap(maxRunning, running) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	if tmc.fail[tablet.Alias.Uid] {
+		return nil, fmt.Errorf("mysqld is down")
+	}
+	name := time.Now().UTC().Format(mysqlctl.BackupTimestampFormat) + "." + topoproto.TabletAliasString(tablet.Alias)
+	dir := path.Join(filebackupstorage.FileBackupStorageRoot, "ks", tablet.Shard, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	manifest, err := json.Marshal(&mysqlctl.BackupManifest{BackupMethod: "builtin", BackupTime: time.Now().UTC().Format(time.RFC3339)})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path.Join(dir, "MANIFEST"), manifest, 0o644); err != nil {
+		return nil, err
+	}
+	return &eventStream{{Value: "backup done"}}, nil
+}
+
+func TestBackupKeyspace(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	useFileBackupStorage(t)
+	ts := memorytopo.NewServer(ctx, "cell1")
+	tmc := &backupTMClient{
+		backupStatusTMClient: backupStatusTMClient{statuses: map[uint32]*replicationdatapb.Status{}},
+		fail:                 map[uint32]bool{102: true},
+	}
+	for i, shard := range []string{"-40", "40-80", "80-c0", "c0-"} {
+		uid := uint32(100 + i)
+		require.NoError(t, ts.InitTablet(ctx, &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    shard,
+			Type:     topodatapb.TabletType_RDONLY,
+		}, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/))
+		tmc.statuses[uid] = replicatingStatus(0)
+	}
+	// c0- was backed up by an earlier run.
+	recent := time.Now().UTC().Add(-time.Minute).Format(mysqlctl.BackupTimestampFormat) + ".cell1-0000000103"
+	require.NoError(t, os.MkdirAll(path.Join(filebackupstorage.FileBackupStorageRoot, "ks", "c0-", recent), 0o755))
+	require.NoError(t, os.WriteFile(path.Join(filebackupstorage.FileBackupStorageRoot, "ks", "c0-", recent, "MANIFEST"), []byte(`{"BackupMethod":"builtin"}`), 0o644))
+
+	wr := New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, tmc)
+	reports, err := wr.BackupKeyspace(ctx, "ks", BackupKeyspaceOptions{
+		MaxConcurrentShards: 2,
+		SkipRecent:          time.Hour,
+	})
+	require.NoError(t, err)
+	require.Len(t, reports, 4)
+	require.LessOrEqual(t, tmc.maxRunning.Load(), int32(2))
+
+	require.Equal(t, ShardBackupSucceeded, reports[0].Status)
+	require.Equal(t, "cell1-0000000100", reports[0].Tablet)
+	require.Contains(t, reports[0].BackupName, ".cell1-0000000100")
+	require.Equal(t, ShardBackupSucceeded, reports[1].Status)
+	require.Equal(t, ShardBackupFailed, reports[2].Status)
+	require.Contains(t, reports[2].Reason, "mysqld is down")
+	require.Equal(t, ShardBackupSkipped, reports[3].Status)
+	require.Equal(t, recent, reports[3].BackupName)
+
+	_, err = wr.BackupKeyspace(ctx, "ks", BackupKeyspaceOptions{})
+	require.ErrorContains(t, err, "must be positive")
+}
+
+// restoreTMClient restores tablets to the position it is given.
+type restoreTMClient struct {
+	tmclient.TabletManagerClient
+
+	requests []*tabletmanagerdatapb.RestoreFromBackupRequest
+	position string
+}
+
+func (tmc *restoreTMClient) RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RestoreFromBackupRequest) (logutil.EventStream, error) {
+	tmc.requests = append(tmc.requests, req)
+	tmc.position = "MySQL56/" + req.RestoreToPos
+	return &eventStream{{Value: "restore done"}}, nil
+}
+
+func (tmc *restoreTMClient) PrimaryPosition(ctx context.Context, tablet *topodatapb.Tablet) (string, error) {
+	return tmc.position, nil
+}
+
+func TestRestoreShardToPointInTime(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	useFileBackupStorage(t)
+	const gtids = "16b1039f-22b6-11ed-b765-0a43f95f28a3:1-100"
+	position, err := replication.DecodePosition("MySQL56/" + gtids)
+	require.NoError(t, err)
+	writeTestBackup(t, "2025-01-01.000000.cell1-0000000101", &mysqlctl.BackupManifest{
+		BackupName:   "2025-01-01.000000.cell1-0000000101",
+		BackupMethod: "builtin",
+		BackupTime:   "2025-01-01T00:00:00Z",
+		FinishedTime: "2025-01-01T00:10:00Z",
+		Position:     position,
+	})
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	for uid, tabletType := range map[uint32]topodatapb.TabletType{100: topodatapb.TabletType_PRIMARY, 101: topodatapb.TabletType_REPLICA} {
+		require.NoError(t, ts.InitTablet(ctx, &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    "0",
+			Type:     tabletType,
+		}, true /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/))
+	}
+	tmc := &restoreTMClient{}
+	wr := New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, tmc)
+	primary := &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}
+	replica := &topodatapb.TabletAlias{Cell: "cell1", Uid: 101}
+
+	_, err = wr.RestoreShardToPointInTime(ctx, "ks", "0", replica, time.Time{}, gtids, false)
+	require.ErrorContains(t, err, "shard ks/0 is serving")
+
+	_, err = ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.IsPrimaryServing = false
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = wr.RestoreShardToPointInTime(ctx, "ks", "0", primary, time.Time{}, gtids, false)
+	require.ErrorContains(t, err, "is the primary of shard ks/0")
+
+	_, err = wr.RestoreShardToPointInTime(ctx, "ks", "0", replica, time.Time{}, "", false)
+	require.ErrorContains(t, err, "exactly one of")
+
+	_, err = wr.RestoreShardToPointInTime(ctx, "ks", "0", replica, time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), "", false)
+	require.ErrorContains(t, err, "no full backup found before")
+	require.Empty(t, tmc.requests)
+
+	result, err := wr.RestoreShardToPointInTime(ctx, "ks", "0", replica, time.Time{}, gtids, false)
+	require.NoError(t, err)
+	require.Equal(t, &PointInTimeRestore{
+		Tablet:     "cell1-0000000101",
+		BackupName: "2025-01-01.000000.cell1-0000000101",
+		Position:   "MySQL56/" + gtids,
+	}, result)
+	require.Len(t, tmc.requests, 1)
+	require.Equal(t, gtids, tmc.requests[0].RestoreToPos)
+}
@@ -0,0 +1,418 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// DrainCellPhase is a phase of DrainCell.
+type DrainCellPhase string
+
+const (
+	// DrainCellReparent moves the primaries in the cell to other cells.
+	DrainCellReparent DrainCellPhase = "reparent"
+	// DrainCellDrain changes the type of the tablets in the cell to DRAINED.
+	DrainCellDrain DrainCellPhase = "drain"
+	// DrainCellVerify checks that no tablet in the cell is serving. It
+	// changes nothing, and always runs right before DrainCellCleanup. A
+	// tablet whose health can't be read doesn't stop the cleanup, as the
+	// tablets of a decommissioned cell are often already shut down.
+	DrainCellVerify DrainCellPhase = "verify"
+	// DrainCellCleanup deletes the tablet records, replication graphs and
+	// SrvKeyspaces of the cell.
+	DrainCellCleanup DrainCellPhase = "cleanup"
+)
+
+// DrainCellOptions are the options of DrainCell.
+type DrainCellOptions struct {
+	// Execute runs the phases. Otherwise DrainCell only reports what it
+	// would do.
+	Execute bool
+	// AllPhases runs all the remaining phases. Otherwise DrainCell stops
+	// after the first phase that changes anything, so that the next one
+	// can be reviewed and confirmed by running DrainCell again.
+	AllPhases bool
+	// WaitReplicasTimeout is passed to PlannedReparentShard.
+	WaitReplicasTimeout time.Duration
+	// HealthTimeout bounds how long the verification waits for the health
+	// record of each tablet.
+	HealthTimeout time.Duration
+}
+
+// DrainCellPrimary is a shard whose primary is in the drained cell.
+type DrainCellPrimary struct {
+	Keyspace    string
+	Shard       string
+	TabletAlias string
+	// NewPrimary is the primary the shard was reparented to.
+	NewPrimary string `json:",omitempty"`
+	Done       bool
+	Error      string `json:",omitempty"`
+
+	alias *topodatapb.TabletAlias
+}
+
+// DrainCellTabletHealth is the serving state of a tablet in the drained
+// cell, as found by the verification.
+type DrainCellTabletHealth string
+
+const (
+	// DrainCellTabletServing is a tablet that is still serving.
+	DrainCellTabletServing DrainCellTabletHealth = "serving"
+	// DrainCellTabletNotServing is a tablet that reported it isn't serving.
+	DrainCellTabletNotServing DrainCellTabletHealth = "not_serving"
+	// DrainCellTabletUnknown is a tablet whose health couldn't be read.
+	DrainCellTabletUnknown DrainCellTabletHealth = "unknown"
+)
+
+// DrainCellTablet is a tablet in the drained cell.
+type DrainCellTablet struct {
+	TabletAlias string
+	Keyspace    string
+	Shard       string
+	Type        string
+	Drained     bool
+	// Health and HealthError are set by the verification. HealthError is
+	// the health error the tablet reported, or why its health couldn't be
+	// read.
+	Health      DrainCellTabletHealth `json:",omitempty"`
+	HealthError string                `json:",omitempty"`
+	Error       string                `json:",omitempty"`
+
+	tablet *topodatapb.Tablet
+}
+
+// DrainCellReport describes what DrainCell did, and what is left to do.
+type DrainCellReport struct {
+	Cell      string
+	Primaries []*DrainCellPrimary
+	Tablets   []*DrainCellTablet
+	// Shards are the keyspace/shards that have a replication graph in the
+	// cell, and SrvKeyspaces the keyspaces that have a SrvKeyspace in it.
+	Shards       []string
+	SrvKeyspaces []string
+	// Phases are the phases that ran, in order.
+	Phases []DrainCellPhase
+	// NextPhase is the phase to run next. It is empty once the cell is
+	// drained and cleaned up.
+	NextPhase DrainCellPhase `json:",omitempty"`
+
+	cleaned bool
+}
+
+// pendingPhase returns the first phase that has work left, skipping the
+// items that are already done so that DrainCell can resume.
+func (report *DrainCellReport) pendingPhase() DrainCellPhase {
+	for _, primary := range report.Primaries {
+		if !primary.Done {
+			return DrainCellReparent
+		}
+	}
+	for _, tablet := range report.Tablets {
+		if !tablet.Drained {
+			return DrainCellDrain
+		}
+	}
+	if !report.cleaned && (len(report.Tablets) > 0 || len(report.Shards) > 0 || len(report.SrvKeyspaces) > 0) {
+		return DrainCellCleanup
+	}
+	return ""
+}
+
+// DrainCell decommissions a cell in phases: it reparents the shards whose
+// primary is in the cell to a tablet in another cell, changes the type of
+// the tablets in the cell to DRAINED, verifies that none of them is
+// serving, and then deletes the tablet records, replication graphs and
+// SrvKeyspaces of the cell from the topo. The CellInfo itself is left for
+// DeleteCellInfo.
+//
+// The work left is worked out from the topo on every call, so DrainCell can
+// be run again after a failure or a pause, and skips what is already done.
+// Without opts.Execute, it only reports what it would do.
+//
+// With opts.Execute, it is a long-running operation: once canceled with
+// CancelOperation, it stops before the next shard to reparent, tablet to
+// drain or phase to run, and can be resumed by running it again.
+func (wr *Wrangler) DrainCell(ctx context.Context, cell string, opts DrainCellOptions) (*DrainCellReport, error) {
+	report, err := wr.planDrainCell(ctx, cell)
+	if err != nil {
+		return nil, err
+	}
+
+	var op *operation
+	if opts.Execute {
+		ctx, op = wr.startOperation(ctx, "DrainCell", cell, false /*interruptible*/)
+		defer op.finish()
+	}
+
+	ranPhase := false
+	for {
+		phase := report.pendingPhase()
+		if phase == DrainCellCleanup {
+			report.Phases = append(report.Phases, DrainCellVerify)
+			if err := wr.verifyDrainedCell(ctx, report, opts.HealthTimeout); err != nil {
+				report.NextPhase = DrainCellCleanup
+				return report, err
+			}
+		}
+		report.NextPhase = phase
+		if phase == "" || !opts.Execute || (ranPhase && !opts.AllPhases) {
+			return report, nil
+		}
+
+		if op.canceled() {
+			return report, op.errCanceled()
+		}
+
+		wr.Logger().Infof("DrainCell %v: running phase %v", cell, phase)
+		report.Phases = append(report.Phases, phase)
+		ranPhase = true
+		switch phase {
+		case DrainCellReparent:
+			err = wr.reparentOutOfCell(ctx, op, report, opts.WaitReplicasTimeout)
+		case DrainCellDrain:
+			err = wr.drainCellTablets(ctx, op, report)
+		case DrainCellCleanup:
+			err = wr.cleanupDrainedCell(ctx, report)
+		}
+		if err != nil {
+			report.NextPhase = phase
+			return report, fmt.Errorf("phase %v of DrainCell %v failed: %w", phase, cell, err)
+		}
+		if op.canceled() {
+			report.NextPhase = report.pendingPhase()
+			return report, op.errCanceled()
+		}
+	}
+}
+
+// planDrainCell reads from the topo what DrainCell has left to do in the
+// cell.
+func (wr *Wrangler) planDrainCell(ctx context.Context, cell string) (*DrainCellReport, error) {
+	if _, err := wr.ts.GetCellInfo(ctx, cell, true /*strongRead*/); err != nil {
+		return nil, fmt.Errorf("cannot read cell %v: %w", cell, err)
+	}
+	report := &DrainCellReport{Cell: cell}
+
+	tablets, err := wr.ts.GetTabletsByCell(ctx, cell, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list tablets in cell %v: %w", cell, err)
+	}
+	sort.Slice(tablets, func(i, j int) bool {
+		return topoproto.TabletAliasString(tablets[i].Alias) < topoproto.TabletAliasString(tablets[j].Alias)
+	})
+	for _, ti := range tablets {
+		report.Tablets = append(report.Tablets, &DrainCellTablet{
+			TabletAlias: topoproto.TabletAliasString(ti.Alias),
+			Keyspace:    ti.Keyspace,
+			Shard:       ti.Shard,
+			Type:        topoproto.TabletTypeLString(ti.Type),
+			Drained:     ti.Type == topodatapb.TabletType_DRAINED,
+			tablet:      ti.Tablet,
+		})
+	}
+
+	keyspaces, err := wr.ts.GetKeyspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, keyspace := range keyspaces {
+		shards, err := wr.ts.GetShardNames(ctx, keyspace)
+		if err != nil {
+			return nil, err
+		}
+		for _, shard := range shards {
+			si, err := wr.ts.GetShard(ctx, keyspace, shard)
+			if err != nil {
+				return nil, err
+			}
+			if si.PrimaryAlias != nil && si.PrimaryAlias.Cell == cell {
+				report.Primaries = append(report.Primaries, &DrainCellPrimary{
+					Keyspace:    keyspace,
+					Shard:       shard,
+					TabletAlias: topoproto.TabletAliasString(si.PrimaryAlias),
+					alias:       si.PrimaryAlias,
+				})
+			}
+
+			_, err = wr.ts.GetShardReplication(ctx, cell, keyspace, shard)
+			switch {
+			case err == nil:
+				report.Shards = append(report.Shards, topoproto.KeyspaceShardString(keyspace, shard))
+			case !topo.IsErrType(err, topo.NoNode):
+				return nil, fmt.Errorf("cannot read the replication graph of %v/%v in cell %v: %w", keyspace, shard, cell, err)
+			}
+		}
+	}
+
+	srvKeyspaces, err := wr.ts.GetSrvKeyspaceNames(ctx, cell)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list SrvKeyspaces in cell %v: %w", cell, err)
+	}
+	for _, keyspace := range srvKeyspaces {
+		_, err := wr.ts.GetSrvKeyspace(ctx, cell, keyspace)
+		switch {
+		case err == nil:
+			report.SrvKeyspaces = append(report.SrvKeyspaces, keyspace)
+		case !topo.IsErrType(err, topo.NoNode):
+			return nil, fmt.Errorf("cannot read SrvKeyspace %v in cell %v: %w", keyspace, cell, err)
+		}
+	}
+	return report, nil
+}
+
+// reparentOutOfCell reparents each shard whose primary is in the drained
+// cell to the best replica in another cell. It stops early once op is
+// canceled.
+func (wr *Wrangler) reparentOutOfCell(ctx context.Context, op *operation, report *DrainCellReport, waitReplicasTimeout time.Duration) error {
+	rec := concurrency.AllErrorRecorder{}
+	for _, primary := range report.Primaries {
+		if primary.Done {
+			continue
+		}
+		if op.canceled() {
+			break
+		}
+		err := wr.PlannedReparentShard(ctx, primary.Keyspace, primary.Shard, reparentutil.PlannedReparentOptions{
+			AvoidPrimaryAlias:       primary.alias,
+			AllowCrossCellPromotion: true,
+			AvoidCells:              []string{report.Cell},
+			WaitReplicasTimeout:     waitReplicasTimeout,
+		})
+		if err == nil {
+			var si *topo.ShardInfo
+			si, err = wr.ts.GetShard(ctx, primary.Keyspace, primary.Shard)
+			if err == nil {
+				primary.NewPrimary = topoproto.TabletAliasString(si.PrimaryAlias)
+				if si.PrimaryAlias.GetCell() == report.Cell {
+					err = fmt.Errorf("the primary %v is still in cell %v", primary.NewPrimary, report.Cell)
+				}
+			}
+		}
+		if err != nil {
+			primary.Error = err.Error()
+			rec.RecordError(fmt.Errorf("cannot reparent %v/%v: %w", primary.Keyspace, primary.Shard, err))
+			continue
+		}
+		primary.Done = true
+		primary.Error = ""
+		wr.Logger().Infof("Reparented %v/%v from %v to %v", primary.Keyspace, primary.Shard, primary.TabletAlias, primary.NewPrimary)
+	}
+	return rec.Error()
+}
+
+// drainCellTablets changes the type of the tablets in the drained cell to
+// DRAINED. It stops early once op is canceled.
+func (wr *Wrangler) drainCellTablets(ctx context.Context, op *operation, report *DrainCellReport) error {
+	rec := concurrency.AllErrorRecorder{}
+	for _, tablet := range report.Tablets {
+		if tablet.Drained {
+			continue
+		}
+		if op.canceled() {
+			break
+		}
+		ti, err := wr.ts.GetTablet(ctx, tablet.tablet.Alias)
+		switch {
+		case topo.IsErrType(err, topo.NoNode):
+			tablet.Drained = true
+			continue
+		case err != nil:
+		case ti.Type == topodatapb.TabletType_DRAINED:
+			tablet.Type = topoproto.TabletTypeLString(ti.Type)
+			tablet.Drained = true
+			continue
+		case ti.Type == topodatapb.TabletType_PRIMARY:
+			err = fmt.Errorf("tablet is still a primary")
+		default:
+			err = wr.ChangeTabletType(ctx, ti.Alias, topodatapb.TabletType_DRAINED)
+		}
+		if err != nil {
+			tablet.Error = err.Error()
+			rec.RecordError(fmt.Errorf("cannot drain tablet %v: %w", tablet.TabletAlias, err))
+			continue
+		}
+		tablet.Type = topoproto.TabletTypeLString(topodatapb.TabletType_DRAINED)
+		tablet.Drained = true
+		tablet.Error = ""
+	}
+	return rec.Error()
+}
+
+// verifyDrainedCell reads the health of each tablet in the drained cell,
+// and returns an error if any of them is still serving.
+func (wr *Wrangler) verifyDrainedCell(ctx context.Context, report *DrainCellReport, healthTimeout time.Duration) error {
+	var serving []string
+	for _, tablet := range report.Tablets {
+		health, err := grpcvtctldserver.ReadTabletHealth(ctx, tablet.tablet, healthTimeout)
+		if err != nil {
+			wr.Logger().Warningf("cannot read the health of tablet %v, its serving state is unknown: %v", tablet.TabletAlias, err)
+			tablet.Health = DrainCellTabletUnknown
+			tablet.HealthError = err.Error()
+			continue
+		}
+		tablet.HealthError = health.RealtimeStats.GetHealthError()
+		if !health.Serving {
+			tablet.Health = DrainCellTabletNotServing
+			continue
+		}
+		tablet.Health = DrainCellTabletServing
+		serving = append(serving, tablet.TabletAlias)
+	}
+	if len(serving) > 0 {
+		return fmt.Errorf("%d tablets in cell %v are still serving: %v", len(serving), report.Cell, serving)
+	}
+	return nil
+}
+
+// cleanupDrainedCell deletes the tablet records, replication graphs and
+// SrvKeyspaces of the drained cell.
+func (wr *Wrangler) cleanupDrainedCell(ctx context.Context, report *DrainCellReport) error {
+	for _, tablet := range report.Tablets {
+		if err := wr.ts.DeleteTablet(ctx, tablet.tablet.Alias); err != nil && !topo.IsErrType(err, topo.NoNode) {
+			return fmt.Errorf("cannot delete tablet %v: %w", tablet.TabletAlias, err)
+		}
+	}
+	for _, keyspaceShard := range report.Shards {
+		keyspace, shard, err := topoproto.ParseKeyspaceShard(keyspaceShard)
+		if err != nil {
+			return err
+		}
+		if err := wr.ts.DeleteShardReplication(ctx, report.Cell, keyspace, shard); err != nil && !topo.IsErrType(err, topo.NoNode) {
+			return fmt.Errorf("cannot delete the replication graph of %v in cell %v: %w", keyspaceShard, report.Cell, err)
+		}
+	}
+	for _, keyspace := range report.SrvKeyspaces {
+		if err := wr.ts.DeleteSrvKeyspace(ctx, report.Cell, keyspace); err != nil && !topo.IsErrType(err, topo.NoNode) {
+			return fmt.Errorf("cannot delete SrvKeyspace %v in cell %v: %w", keyspace, report.Cell, err)
+		}
+	}
+	report.cleaned = true
+	return nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+	"vitess.io/vitess/go/vt/vttablet/queryservice/fakes"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+	"vitess.io/vitess/go/vt/vttablet/tabletconntest"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// changeTypeTMClient changes the type of the tablet record like a tablet
+// would, and stops the tablet from serving once it is DRAINED.
+type changeTypeTMClient struct {
+	tmclient.TabletManagerClient
+
+	ts          *topo.Server
+	mu          sync.Mutex
+	serving     map[uint32]bool
+	unreachable map[uint32]bool
+}
+
+func (tmc *changeTypeTMClient) ChangeType(ctx context.Context, tablet *topodatapb.Tablet, dbType topodatapb.TabletType, semiSync bool) error {
+	_, err := tmc.ts.UpdateTabletFields(ctx, tablet.Alias, func(t *topodatapb.Tablet) error {
+		t.Type = dbType
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	tmc.serving[tablet.Alias.Uid] = dbType != topodatapb.TabletType_DRAINED
+	return nil
+}
+
+func (tmc *changeTypeTMClient) isServing(uid uint32) bool {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	return tmc.serving[uid]
+}
+
+func (tmc *changeTypeTMClient) isUnreachable(uid uint32) bool {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	return tmc.unreachable[uid]
+}
+
+func TestDrainCell(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
+	tmc := &changeTypeTMClient{ts: ts, serving: map[uint32]bool{}, unreachable: map[uint32]bool{}}
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmc)
+
+	dialerName := fmt.Sprintf("DrainCellTest-%d", rand.IntN(1000000000))
+	tabletconn.RegisterDialer(dialerName, func(ctx context.Context, tablet *topodatapb.Tablet, failFast grpcclient.FailFast) (queryservice.QueryService, error) {
+		if tmc.isUnreachable(tablet.Alias.Uid) {
+			return nil, fmt.Errorf("tablet %d is unreachable", tablet.Alias.Uid)
+		}
+		return &servingQueryService{
+			QueryService: fakes.ErrorQueryService,
+			serving:      tmc.isServing(tablet.Alias.Uid),
+		}, nil
+	})
+	tabletconntest.SetProtocol("go.vt.wrangler.cell_test", dialerName)
+
+	addTablet := func(cell string, uid uint32, tabletType topodatapb.TabletType) {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: cell, Uid: uid},
+			Keyspace: "ks",
+			Shard:    "0",
+			Type:     tabletType,
+		}
+		err := ts.InitTablet(ctx, tablet, true /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+		tmc.serving[uid] = true
+	}
+	addTablet("cell1", 100, topodatapb.TabletType_PRIMARY)
+	addTablet("cell1", 101, topodatapb.TabletType_REPLICA)
+	addTablet("cell2", 200, topodatapb.TabletType_REPLICA)
+	addTablet("cell2", 201, topodatapb.TabletType_RDONLY)
+	_, err := ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}
+		return nil
+	})
+	require.NoError(t, err)
+	for _, cell := range []string{"cell1", "cell2"} {
+		err = ts.UpdateSrvKeyspace(ctx, cell, "ks", &topodatapb.SrvKeyspace{})
+		require.NoError(t, err)
+	}
+
+	opts := DrainCellOptions{HealthTimeout: time.Second}
+
+	// The primary would have to move out of cell1 first.
+	report, err := wr.DrainCell(ctx, "cell1", opts)
+	require.NoError(t, err)
+	require.Equal(t, DrainCellReparent, report.NextPhase)
+	require.Len(t, report.Primaries, 1)
+	require.Equal(t, "cell1-0000000100", report.Primaries[0].TabletAlias)
+	require.Len(t, report.Tablets, 2)
+	require.Empty(t, report.Phases)
+
+	// Draining cell2 pauses before the cleanup, once the verification
+	// passed.
+	opts.Execute = true
+	report, err = wr.DrainCell(ctx, "cell2", opts)
+	require.NoError(t, err)
+	require.Empty(t, report.Primaries)
+	require.Equal(t, []DrainCellPhase{DrainCellDrain, DrainCellVerify}, report.Phases)
+	require.Equal(t, DrainCellCleanup, report.NextPhase)
+	require.Equal(t, []string{"ks/0"}, report.Shards)
+	require.Equal(t, []string{"ks"}, report.SrvKeyspaces)
+	for _, tablet := range report.Tablets {
+		require.True(t, tablet.Drained, tablet.TabletAlias)
+		require.Equal(t, "drained", tablet.Type, tablet.TabletAlias)
+		require.Equal(t, DrainCellTabletNotServing, tablet.Health, tablet.TabletAlias)
+	}
+	ti, err := ts.GetTablet(ctx, &topodatapb.TabletAlias{Cell: "cell2", Uid: 201})
+	require.NoError(t, err)
+	require.Equal(t, topodatapb.TabletType_DRAINED, ti.Type)
+
+	// A tablet that is still serving stops the cleanup.
+	tmc.serving[200] = true
+	report, err = wr.DrainCell(ctx, "cell2", opts)
+	require.ErrorContains(t, err, "1 tablets in cell cell2 are still serving: [cell2-0000000200]")
+	require.Equal(t, []DrainCellPhase{DrainCellVerify}, report.Phases)
+	require.Equal(t, DrainCellCleanup, report.NextPhase)
+	tmc.serving[200] = false
+
+	// A tablet whose health can't be read is reported as unknown, but
+	// doesn't stop the cleanup.
+	tmc.unreachable[201] = true
+	report, err = wr.DrainCell(ctx, "cell2", opts)
+	require.NoError(t, err)
+	require.Equal(t, []DrainCellPhase{DrainCellVerify, DrainCellCleanup}, report.Phases)
+	require.Len(t, report.Tablets, 2)
+	for _, tablet := range report.Tablets {
+		if tablet.TabletAlias == "cell2-0000000201" {
+			require.Equal(t, DrainCellTabletUnknown, tablet.Health)
+			require.Contains(t, tablet.HealthError, "tablet 201 is unreachable")
+		} else {
+			require.Equal(t, DrainCellTabletNotServing, tablet.Health, tablet.TabletAlias)
+		}
+	}
+	require.Empty(t, report.NextPhase)
+	tablets, err := ts.GetTabletsByCell(ctx, "cell2", nil)
+	require.NoError(t, err)
+	require.Empty(t, tablets)
+	_, err = ts.GetShardReplication(ctx, "cell2", "ks", "0")
+	require.True(t, topo.IsErrType(err, topo.NoNode), err)
+	_, err = ts.GetSrvKeyspace(ctx, "cell2", "ks")
+	require.True(t, topo.IsErrType(err, topo.NoNode), err)
+	_, err = ts.GetSrvKeyspace(ctx, "cell1", "ks")
+	require.NoError(t, err)
+
+	// Running it again finds nothing left to do.
+	report, err = wr.DrainCell(ctx, "cell2", opts)
+	require.NoError(t, err)
+	require.Empty(t, report.Phases)
+	require.Empty(t, report.NextPhase)
+	require.Empty(t, report.Tablets)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"vitess.io/vitess/go/vt/topo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// CompletionData holds the names that shell completion offers as the
+// arguments of commands.
+type CompletionData struct {
+	Cells     []string `json:"cells"`
+	Keyspaces []string `json:"keyspaces"`
+	// Shards are the shard names of each keyspace.
+	Shards map[string][]string `json:"shards"`
+	// Workflows are the workflow names of each keyspace. It is only set if
+	// the workflows were asked for.
+	Workflows map[string][]string `json:"workflows,omitempty"`
+	// Truncated is set if names were left out to keep the data under the
+	// maximum number of entries.
+	Truncated bool `json:"truncated,omitempty"`
+	// GeneratedAt is when the data was read, so that a client caching it
+	// knows how fresh it is.
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// CompletionOptions are the options of CompletionData.
+type CompletionOptions struct {
+	// Cell is the cell whose SrvKeyspaces the shard names are read from.
+	// It defaults to the first cell.
+	Cell string
+	// MaxEntries is the maximum number of names returned, all lists
+	// included. Zero means no limit.
+	MaxEntries int
+	// Workflows also lists the workflows of each keyspace, which queries
+	// the primary tablets rather than the topo.
+	Workflows bool
+}
+
+// CompletionData returns, in one call, the cells, keyspaces, shards and
+// optionally workflows that shell completion needs. To keep the topo load
+// low, the shards of a keyspace are read from its SrvKeyspace in one cell,
+// which is a single record, and only listed from the global topo if the
+// keyspace isn't served in that cell.
+func (wr *Wrangler) CompletionData(ctx context.Context, opts CompletionOptions) (*CompletionData, error) {
+	data := &CompletionData{
+		Shards:      make(map[string][]string),
+		GeneratedAt: time.Now().UTC(),
+	}
+	remaining := opts.MaxEntries
+	// take returns the first names that fit in the maximum number of
+	// entries, and marks the data truncated if some don't.
+	take := func(names []string) []string {
+		if opts.MaxEntries == 0 {
+			return names
+		}
+		if len(names) > remaining {
+			names = names[:remaining]
+			data.Truncated = true
+		}
+		remaining -= len(names)
+		return names
+	}
+
+	cells, err := wr.ts.GetCellInfoNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetCellInfoNames() failed: %w", err)
+	}
+	data.Cells = take(cells)
+	cell := opts.Cell
+	if cell == "" && len(cells) > 0 {
+		cell = cells[0]
+	}
+
+	keyspaces, err := wr.ts.GetKeyspaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetKeyspaces() failed: %w", err)
+	}
+	data.Keyspaces = take(keyspaces)
+
+	for _, keyspace := range data.Keyspaces {
+		if data.Truncated {
+			break
+		}
+		shards, err := wr.completionShardNames(ctx, cell, keyspace)
+		if err != nil {
+			return nil, err
+		}
+		if shards = take(shards); len(shards) == 0 && data.Truncated {
+			break
+		}
+		data.Shards[keyspace] = shards
+	}
+
+	if !opts.Workflows {
+		return data, nil
+	}
+	data.Workflows = make(map[string][]string)
+	for _, keyspace := range data.Keyspaces {
+		if data.Truncated {
+			break
+		}
+		workflows, err := wr.ListAllWorkflows(ctx, keyspace, false /* active */)
+		if err != nil {
+			// Completion is best effort: a keyspace without reachable
+			// primaries just offers no workflow.
+			wr.Logger().Warningf("cannot list the workflows of keyspace %v: %v", keyspace, err)
+			continue
+		}
+		if workflows = take(workflows); len(workflows) == 0 && data.Truncated {
+			break
+		}
+		data.Workflows[keyspace] = workflows
+	}
+	return data, nil
+}
+
+// completionShardNames returns the shards of keyspace, from its primary
+// partition in the SrvKeyspace of cell if there is one, or from the global
+// topo otherwise.
+func (wr *Wrangler) completionShardNames(ctx context.Context, cell, keyspace string) ([]string, error) {
+	if cell != "" {
+		srvKeyspace, err := wr.ts.GetSrvKeyspace(ctx, cell, keyspace)
+		switch {
+		case err == nil:
+			for _, partition := range srvKeyspace.GetPartitions() {
+				if partition.ServedType != topodatapb.TabletType_PRIMARY {
+					continue
+				}
+				shards := make([]string, 0, len(partition.ShardReferences))
+				for _, ref := range partition.ShardReferences {
+					shards = append(shards, ref.Name)
+				}
+				return shards, nil
+			}
+		case !topo.IsErrType(err, topo.NoNode):
+			return nil, fmt.Errorf("GetSrvKeyspace(%v, %v) failed: %w", cell, keyspace, err)
+		}
+	}
+
+	shards, err := wr.ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return nil, fmt.Errorf("GetShardNames(%v) failed: %w", keyspace, err)
+	}
+	return shards, nil
+}
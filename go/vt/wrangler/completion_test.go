@@ -0,0 +1,72 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestCompletionData(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, nil)
+
+	for _, ks := range []string{"customer", "commerce"} {
+		require.NoError(t, ts.CreateKeyspace(ctx, ks, &topodatapb.Keyspace{}))
+	}
+	for _, shard := range []string{"-80", "80-"} {
+		require.NoError(t, ts.CreateShard(ctx, "customer", shard))
+	}
+	require.NoError(t, ts.CreateShard(ctx, "commerce", "0"))
+
+	// The shards of a keyspace served in the cell come from its
+	// SrvKeyspace, the others from the global topo.
+	err := ts.UpdateSrvKeyspace(ctx, "cell1", "customer", &topodatapb.SrvKeyspace{
+		Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{{
+			ServedType:      topodatapb.TabletType_PRIMARY,
+			ShardReferences: []*topodatapb.ShardReference{{Name: "-80"}, {Name: "80-"}},
+		}},
+	})
+	require.NoError(t, err)
+
+	data, err := wr.CompletionData(ctx, CompletionOptions{Cell: "cell1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"cell1", "cell2"}, data.Cells)
+	require.Equal(t, []string{"commerce", "customer"}, data.Keyspaces)
+	require.Equal(t, map[string][]string{"commerce": {"0"}, "customer": {"-80", "80-"}}, data.Shards)
+	require.Nil(t, data.Workflows)
+	require.False(t, data.Truncated)
+	require.False(t, data.GeneratedAt.IsZero())
+
+	data, err = wr.CompletionData(ctx, CompletionOptions{MaxEntries: 5})
+	require.NoError(t, err)
+	require.Equal(t, []string{"cell1", "cell2"}, data.Cells)
+	require.Equal(t, []string{"commerce", "customer"}, data.Keyspaces)
+	require.Equal(t, map[string][]string{"commerce": {"0"}}, data.Shards)
+	require.True(t, data.Truncated)
+}
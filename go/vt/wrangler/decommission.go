@@ -0,0 +1,181 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// decommissionPollInterval is how often DecommissionTablet reads the health
+// of the tablet while waiting for its qps to drop.
+var decommissionPollInterval = time.Second
+
+// DecommissionOptions are the options of DecommissionTablet.
+type DecommissionOptions struct {
+	// DrainTimeout is how long to wait for the qps of the drained tablet to
+	// drop to MaxQPS.
+	DrainTimeout time.Duration
+	// MaxQPS is the qps at or below which the tablet counts as drained.
+	MaxQPS float64
+	// Force decommissions the tablet even if it is the only serving tablet
+	// of its type in its shard.
+	Force bool
+}
+
+// DecommissionTablet removes a tablet from service: it checks that the
+// tablet is not the shard primary, nor the only serving tablet of its type
+// in the shard unless forced, changes its type to DRAINED, waits for the
+// qps of its health stream to drop, and deletes its record and replication
+// graph entry. The outcome of each phase is logged. Running it again after
+// an interruption resumes where it stopped, as the phases already done are
+// skipped.
+func (wr *Wrangler) DecommissionTablet(ctx context.Context, tabletAlias *topodatapb.TabletAlias, options DecommissionOptions) error {
+	alias := topoproto.TabletAliasString(tabletAlias)
+	logPhase := func(phase, format string, args ...any) {
+		wr.Logger().Printf("DecommissionTablet(%v) %v: %v\n", alias, phase, fmt.Sprintf(format, args...))
+	}
+
+	ti, err := wr.ts.GetTablet(ctx, tabletAlias)
+	if topo.IsErrType(err, topo.NoNode) {
+		logPhase("delete", "the tablet record is already deleted")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	isPrimary, err := wr.isPrimaryTablet(ctx, ti)
+	if err != nil && !topo.IsErrType(err, topo.NoNode) {
+		return err
+	}
+	if isPrimary {
+		return fmt.Errorf("cannot decommission tablet %v as it is the primary of shard %v/%v, use PlannedReparentShard first", alias, ti.Keyspace, ti.Shard)
+	}
+
+	if ti.Type == topodatapb.TabletType_DRAINED {
+		logPhase("drain", "the tablet is already drained")
+	} else {
+		if !topo.IsTrivialTypeChange(ti.Type, topodatapb.TabletType_DRAINED) {
+			return fmt.Errorf("cannot decommission tablet %v of type %v", alias, topoproto.TabletTypeLString(ti.Type))
+		}
+		if topo.IsInServingGraph(ti.Type) {
+			remaining, err := wr.countOtherServingTablets(ctx, ti)
+			if err != nil {
+				return err
+			}
+			switch {
+			case remaining > 0:
+				logPhase("check", "%d other serving %v tablets remain in shard %v/%v", remaining, topoproto.TabletTypeLString(ti.Type), ti.Keyspace, ti.Shard)
+			case options.Force:
+				logPhase("check", "the tablet is the only serving %v tablet of shard %v/%v, proceeding as forced", topoproto.TabletTypeLString(ti.Type), ti.Keyspace, ti.Shard)
+			default:
+				return fmt.Errorf("cannot decommission tablet %v as it is the only serving %v tablet of shard %v/%v, use --force to decommission it anyway",
+					alias, topoproto.TabletTypeLString(ti.Type), ti.Keyspace, ti.Shard)
+			}
+		}
+		if err := wr.ChangeTabletType(ctx, tabletAlias, topodatapb.TabletType_DRAINED); err != nil {
+			return fmt.Errorf("cannot drain tablet %v: %v", alias, err)
+		}
+		logPhase("drain", "changed the type from %v to drained", topoproto.TabletTypeLString(ti.Type))
+	}
+
+	if err := wr.waitForTabletQPS(ctx, ti.Tablet, options, logPhase); err != nil {
+		return err
+	}
+
+	if err := wr.DeleteTablet(ctx, tabletAlias, false /* allowPrimary */); err != nil {
+		return fmt.Errorf("cannot delete tablet %v: %v", alias, err)
+	}
+	logPhase("delete", "deleted the tablet record and its replication graph entry")
+	return nil
+}
+
+// countOtherServingTablets returns how many other tablets of the shard of
+// the tablet, in all cells, have its type and are serving.
+func (wr *Wrangler) countOtherServingTablets(ctx context.Context, ti *topo.TabletInfo) (int, error) {
+	// A tablet we cannot read could be the last one serving, so it fails
+	// the check.
+	tabletMap, err := wr.getTabletMapForShard(ctx, ti.Keyspace, ti.Shard, nil, failOnUnreadableTablets)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		remaining int
+	)
+	for _, other := range tabletMap {
+		if other.Type != ti.Type || topoproto.TabletAliasEqual(other.Alias, ti.Alias) {
+			continue
+		}
+		wg.Add(1)
+		go func(other *topo.TabletInfo) {
+			defer wg.Done()
+			ctx, cancel := wr.tabletContext(ctx)
+			defer cancel()
+			serving, err := wr.isTabletServing(ctx, other.Tablet)
+			if err != nil {
+				wr.Logger().Warningf("cannot get health of tablet %v: %v", other.AliasString(), err)
+				return
+			}
+			if serving {
+				mu.Lock()
+				remaining++
+				mu.Unlock()
+			}
+		}(other)
+	}
+	wg.Wait()
+	return remaining, nil
+}
+
+// waitForTabletQPS waits, for at most options.DrainTimeout, until the qps
+// the tablet reports in its health stream drops to options.MaxQPS. A tablet
+// that doesn't answer serves no queries either.
+func (wr *Wrangler) waitForTabletQPS(ctx context.Context, tablet *topodatapb.Tablet, options DecommissionOptions, logPhase func(phase, format string, args ...any)) error {
+	ctx, cancel := context.WithTimeout(ctx, options.DrainTimeout)
+	defer cancel()
+	var qps float64
+	for {
+		shr, err := grpcvtctldserver.ReadTabletHealth(ctx, tablet, healthProbeTimeout)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			logPhase("wait", "cannot read the health of the tablet, assuming it serves no queries: %v", err)
+			return nil
+		case err == nil:
+			qps = shr.RealtimeStats.GetQps()
+			if qps <= options.MaxQPS {
+				logPhase("wait", "the tablet serves %.2f qps", qps)
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("tablet %v still serves %.2f qps after %v, run DecommissionTablet again to resume", topoproto.TabletAliasString(tablet.Alias), qps, options.DrainTimeout)
+		case <-time.After(decommissionPollInterval):
+		}
+	}
+}
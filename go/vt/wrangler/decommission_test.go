@@ -0,0 +1,170 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+	"vitess.io/vitess/go/vt/vttablet/queryservice/fakes"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+	"vitess.io/vitess/go/vt/vttablet/tabletconntest"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// decommissionTMClient changes the type of the tablet record, as the tablet
+// would.
+type decommissionTMClient struct {
+	tmclient.TabletManagerClient
+
+	ts *topo.Server
+}
+
+func (tmc *decommissionTMClient) ChangeType(ctx context.Context, tablet *topodatapb.Tablet, dbType topodatapb.TabletType, semiSync bool) error {
+	_, err := tmc.ts.UpdateTabletFields(ctx, tablet.Alias, func(tablet *topodatapb.Tablet) error {
+		tablet.Type = dbType
+		return nil
+	})
+	return err
+}
+
+// qpsQueryService answers StreamHealth with a single serving health record
+// with the given qps.
+type qpsQueryService struct {
+	queryservice.QueryService
+	qps float64
+}
+
+func (qs *qpsQueryService) StreamHealth(ctx context.Context, callback func(*querypb.StreamHealthResponse) error) error {
+	return callback(&querypb.StreamHealthResponse{
+		Serving:       true,
+		RealtimeStats: &querypb.RealtimeStats{Qps: qs.qps},
+	})
+}
+
+func (qs *qpsQueryService) Close(ctx context.Context) error {
+	return nil
+}
+
+func TestDecommissionTablet(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	oldPollInterval := decommissionPollInterval
+	decommissionPollInterval = 10 * time.Millisecond
+	defer func() { decommissionPollInterval = oldPollInterval }()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	logger := logutil.NewMemoryLogger()
+	wr := New(vtenv.NewTestEnv(), logger, ts, &decommissionTMClient{ts: ts})
+
+	var mu sync.Mutex
+	qps := map[uint32]float64{}
+	setQPS := func(uid uint32, value float64) {
+		mu.Lock()
+		defer mu.Unlock()
+		qps[uid] = value
+	}
+	dialerName := fmt.Sprintf("DecommissionTabletTest-%d", rand.IntN(1000000000))
+	tabletconn.RegisterDialer(dialerName, func(ctx context.Context, tablet *topodatapb.Tablet, failFast grpcclient.FailFast) (queryservice.QueryService, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return &qpsQueryService{
+			QueryService: fakes.ErrorQueryService,
+			qps:          qps[tablet.Alias.Uid],
+		}, nil
+	})
+	tabletconntest.SetProtocol("go.vt.wrangler.tablet_test", dialerName)
+
+	addTablet := func(uid uint32, tabletType topodatapb.TabletType) *topodatapb.TabletAlias {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    "0",
+			Type:     tabletType,
+		}
+		err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+		return tablet.Alias
+	}
+	primary := addTablet(100, topodatapb.TabletType_PRIMARY)
+	replica1 := addTablet(101, topodatapb.TabletType_REPLICA)
+	replica2 := addTablet(102, topodatapb.TabletType_REPLICA)
+	_, err := ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = primary
+		return nil
+	})
+	require.NoError(t, err)
+
+	options := DecommissionOptions{
+		DrainTimeout: 100 * time.Millisecond,
+		MaxQPS:       1,
+	}
+
+	err = wr.DecommissionTablet(ctx, primary, options)
+	require.ErrorContains(t, err, "cannot decommission tablet cell1-0000000100 as it is the primary of shard ks/0")
+
+	// The tablet still serves queries after the drain timeout: it is left
+	// drained, and a second run resumes from the wait.
+	setQPS(101, 50)
+	err = wr.DecommissionTablet(ctx, replica1, options)
+	require.ErrorContains(t, err, "tablet cell1-0000000101 still serves 50.00 qps after 100ms, run DecommissionTablet again to resume")
+	ti, err := ts.GetTablet(ctx, replica1)
+	require.NoError(t, err)
+	require.Equal(t, topodatapb.TabletType_DRAINED, ti.Type)
+
+	setQPS(101, 0)
+	require.NoError(t, wr.DecommissionTablet(ctx, replica1, options))
+	require.Contains(t, logger.String(), "DecommissionTablet(cell1-0000000101) drain: the tablet is already drained")
+	require.Contains(t, logger.String(), "DecommissionTablet(cell1-0000000101) delete: deleted the tablet record and its replication graph entry")
+	_, err = ts.GetTablet(ctx, replica1)
+	require.True(t, topo.IsErrType(err, topo.NoNode))
+	aliases, err := ts.FindAllTabletAliasesInShard(ctx, "ks", "0")
+	require.NoError(t, err)
+	require.Len(t, aliases, 2)
+
+	// A deleted tablet is already decommissioned.
+	require.NoError(t, wr.DecommissionTablet(ctx, replica1, options))
+	require.Contains(t, logger.String(), "DecommissionTablet(cell1-0000000101) delete: the tablet record is already deleted")
+
+	// The last serving replica needs --force.
+	err = wr.DecommissionTablet(ctx, replica2, options)
+	require.ErrorContains(t, err, "cannot decommission tablet cell1-0000000102 as it is the only serving replica tablet of shard ks/0, use --force")
+	ti, err = ts.GetTablet(ctx, replica2)
+	require.NoError(t, err)
+	require.Equal(t, topodatapb.TabletType_REPLICA, ti.Type)
+
+	options.Force = true
+	require.NoError(t, wr.DecommissionTablet(ctx, replica2, options))
+	_, err = ts.GetTablet(ctx, replica2)
+	require.True(t, topo.IsErrType(err, topo.NoNode))
+}
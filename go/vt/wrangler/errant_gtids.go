@@ -0,0 +1,263 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// ErrantGTIDTablet is the errant GTID check of one replica.
+type ErrantGTIDTablet struct {
+	TabletAlias string
+	Type        string
+	// Position is the executed GTID set of the replica.
+	Position string `json:",omitempty"`
+	// ErrantGTIDs are the transactions of the replica that are not on the
+	// primary, and didn't originate from it.
+	ErrantGTIDs string `json:",omitempty"`
+	ErrantCount int64
+	// ErrantCountBySource counts the errant transactions by the UUID of the
+	// server they originated from.
+	ErrantCountBySource map[string]int64 `json:",omitempty"`
+	// Error is why the replica couldn't be checked.
+	Error string `json:",omitempty"`
+}
+
+// ShardErrantGTIDReport is the result of CheckErrantGTIDsShard.
+type ShardErrantGTIDReport struct {
+	Keyspace          string
+	Shard             string
+	PrimaryAlias      string `json:",omitempty"`
+	PrimaryPosition   string `json:",omitempty"`
+	PrimaryServerUUID string `json:",omitempty"`
+	Tablets           []*ErrantGTIDTablet
+	// Error is why the shard couldn't be checked, in a keyspace report.
+	Error string `json:",omitempty"`
+}
+
+// HasErrantGTIDs returns true if any replica of the shard has errant GTIDs.
+func (r *ShardErrantGTIDReport) HasErrantGTIDs() bool {
+	for _, tablet := range r.Tablets {
+		if tablet.ErrantCount > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// UncheckedTablets returns the aliases of the replicas of the shard that
+// couldn't be checked.
+func (r *ShardErrantGTIDReport) UncheckedTablets() []string {
+	var aliases []string
+	for _, tablet := range r.Tablets {
+		if tablet.Error != "" {
+			aliases = append(aliases, tablet.TabletAlias)
+		}
+	}
+	return aliases
+}
+
+// KeyspaceErrantGTIDReport is the result of CheckErrantGTIDsKeyspace.
+type KeyspaceErrantGTIDReport struct {
+	Keyspace string
+	Shards   []*ShardErrantGTIDReport
+	// ShardsWithErrantGTIDs are the shards that have a replica with errant
+	// GTIDs.
+	ShardsWithErrantGTIDs []string
+	// ShardsWithErrors are the shards that couldn't be checked.
+	ShardsWithErrors []string
+}
+
+// CheckErrantGTIDsShard compares the executed GTID set of each replica of
+// the shard with the one of the primary, and reports the transactions of
+// the replica that are not on the primary and didn't originate from it.
+// Replicas with errant GTIDs fail the next reparent that promotes them, or
+// whose new primary doesn't have them. Replicas that can't be checked are
+// reported, not returned as an error. Only MySQL 5.6 GTID sets are supported.
+func (wr *Wrangler) CheckErrantGTIDsShard(ctx context.Context, keyspace, shard string) (*ShardErrantGTIDReport, error) {
+	si, err := wr.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	if !si.HasPrimary() {
+		return nil, fmt.Errorf("no primary in shard %v/%v to compare the replicas with", keyspace, shard)
+	}
+	tabletMap, err := wr.getTabletMapForShard(ctx, keyspace, shard, nil, warnOnUnreadableTablets)
+	if err != nil {
+		return nil, err
+	}
+	primary, ok := tabletMap[topoproto.TabletAliasString(si.PrimaryAlias)]
+	if !ok {
+		return nil, fmt.Errorf("primary %v of shard %v/%v is not in the shard's replication graph", topoproto.TabletAliasString(si.PrimaryAlias), keyspace, shard)
+	}
+
+	status, err := callTablet(ctx, "PrimaryStatus", idempotent, func(ctx context.Context) (*replicationdatapb.PrimaryStatus, error) {
+		return wr.tmc.PrimaryStatus(ctx, primary.Tablet)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the status of primary %v: %v", primary.AliasString(), err)
+	}
+	primaryPosition, err := replication.DecodePosition(status.Position)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode the position of primary %v: %v", primary.AliasString(), err)
+	}
+	if _, ok := primaryPosition.GTIDSet.(replication.Mysql56GTIDSet); !ok {
+		return nil, fmt.Errorf("position %v of primary %v is not a MySQL 5.6 GTID set", status.Position, primary.AliasString())
+	}
+	primarySID, err := replication.ParseSID(status.ServerUuid)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the server UUID of primary %v: %v", primary.AliasString(), err)
+	}
+
+	report := &ShardErrantGTIDReport{
+		Keyspace:          keyspace,
+		Shard:             shard,
+		PrimaryAlias:      primary.AliasString(),
+		PrimaryPosition:   status.Position,
+		PrimaryServerUUID: status.ServerUuid,
+	}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, ti := range tabletMap {
+		if topoproto.TabletAliasEqual(ti.Alias, si.PrimaryAlias) {
+			continue
+		}
+		wg.Add(1)
+		go func(tablet *topodatapb.Tablet) {
+			defer wg.Done()
+			result := wr.checkErrantGTIDs(ctx, tablet, primaryPosition, primarySID)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Tablets = append(report.Tablets, result)
+		}(ti.Tablet)
+	}
+	wg.Wait()
+
+	sort.Slice(report.Tablets, func(i, j int) bool { return report.Tablets[i].TabletAlias < report.Tablets[j].TabletAlias })
+	return report, nil
+}
+
+// checkErrantGTIDs reads the executed GTID set of the replica and returns
+// its errant GTIDs relative to the primary.
+func (wr *Wrangler) checkErrantGTIDs(ctx context.Context, tablet *topodatapb.Tablet, primaryPosition replication.Position, primarySID replication.SID) *ErrantGTIDTablet {
+	result := &ErrantGTIDTablet{
+		TabletAlias: topoproto.TabletAliasString(tablet.Alias),
+		Type:        topoproto.TabletTypeLString(tablet.Type),
+	}
+	ctx, cancel := wr.tabletContext(ctx)
+	defer cancel()
+	status, err := callTablet(ctx, "ReplicationStatus", idempotent, func(ctx context.Context) (*replicationdatapb.Status, error) {
+		return wr.tmc.ReplicationStatus(ctx, tablet)
+	})
+	if err != nil {
+		result.Error = fmt.Sprintf("cannot read the replication status: %v", err)
+		return result
+	}
+	result.Position = status.Position
+	position, err := replication.DecodePosition(status.Position)
+	if err != nil {
+		result.Error = fmt.Sprintf("cannot decode the position: %v", err)
+		return result
+	}
+	if _, ok := position.GTIDSet.(replication.Mysql56GTIDSet); !ok {
+		result.Error = fmt.Sprintf("position %v is not a MySQL 5.6 GTID set", status.Position)
+		return result
+	}
+
+	errantGTIDs, err := replication.ErrantGTIDsOnReplica(position, primaryPosition, primarySID)
+	if err != nil {
+		result.Error = fmt.Sprintf("cannot compute the errant GTIDs: %v", err)
+		return result
+	}
+	if errantGTIDs == "" {
+		return result
+	}
+	errant, err := replication.ParseMysql56GTIDSet(errantGTIDs)
+	if err != nil {
+		result.Error = fmt.Sprintf("cannot parse the errant GTIDs %v: %v", errantGTIDs, err)
+		return result
+	}
+	result.ErrantGTIDs = errantGTIDs
+	result.ErrantCountBySource = make(map[string]int64, len(errant))
+	for _, sid := range errant.SIDs() {
+		// The difference with the set without the SID is the set of the SID.
+		count, err := replication.GTIDCount(errant.Difference(errant.RemoveUUID(sid)).String())
+		if err != nil {
+			result.Error = fmt.Sprintf("cannot count the errant GTIDs of %v: %v", sid, err)
+			return result
+		}
+		result.ErrantCountBySource[sid.String()] = count
+		result.ErrantCount += count
+	}
+	return result
+}
+
+// CheckErrantGTIDsKeyspace runs CheckErrantGTIDsShard on all the shards of
+// the keyspace, and summarizes which shards have errant GTIDs. A shard that
+// can't be checked is reported, not returned as an error.
+func (wr *Wrangler) CheckErrantGTIDsKeyspace(ctx context.Context, keyspace string) (*KeyspaceErrantGTIDReport, error) {
+	shards, err := wr.ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(shards)
+
+	report := &KeyspaceErrantGTIDReport{
+		Keyspace: keyspace,
+		Shards:   make([]*ShardErrantGTIDReport, len(shards)),
+	}
+	var wg sync.WaitGroup
+	for i, shard := range shards {
+		wg.Add(1)
+		go func(i int, shard string) {
+			defer wg.Done()
+			ctx, cancel := wr.shardContext(ctx)
+			defer cancel()
+			shardReport, err := wr.CheckErrantGTIDsShard(ctx, keyspace, shard)
+			if err != nil {
+				shardReport = &ShardErrantGTIDReport{
+					Keyspace: keyspace,
+					Shard:    shard,
+					Error:    err.Error(),
+				}
+			}
+			report.Shards[i] = shardReport
+		}(i, shard)
+	}
+	wg.Wait()
+
+	for _, shardReport := range report.Shards {
+		switch {
+		case shardReport.Error != "":
+			report.ShardsWithErrors = append(report.ShardsWithErrors, shardReport.Shard)
+		case shardReport.HasErrantGTIDs():
+			report.ShardsWithErrantGTIDs = append(report.ShardsWithErrantGTIDs, shardReport.Shard)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const (
+	primaryUUID = "00000000-0000-0000-0000-000000000001"
+	oldUUID     = "00000000-0000-0000-0000-000000000002"
+	errantUUID  = "00000000-0000-0000-0000-000000000003"
+)
+
+// errantGTIDTMClient returns the position of each tablet, and fails for the
+// tablets without one.
+type errantGTIDTMClient struct {
+	tmclient.TabletManagerClient
+
+	positions map[uint32]string
+}
+
+func (tmc *errantGTIDTMClient) PrimaryStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.PrimaryStatus, error) {
+	return &replicationdatapb.PrimaryStatus{
+		Position:   tmc.positions[tablet.Alias.Uid],
+		ServerUuid: primaryUUID,
+	}, nil
+}
+
+func (tmc *errantGTIDTMClient) ReplicationStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.Status, error) {
+	position, ok := tmc.positions[tablet.Alias.Uid]
+	if !ok {
+		return nil, fmt.Errorf("not replicating")
+	}
+	return &replicationdatapb.Status{Position: position}, nil
+}
+
+func TestCheckErrantGTIDs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	tmc := &errantGTIDTMClient{
+		positions: map[uint32]string{
+			100: fmt.Sprintf("MySQL56/%s:1-100,%s:1-50", primaryUUID, oldUUID),
+			// Ahead of the primary on its own UUID, which is not errant.
+			101: fmt.Sprintf("MySQL56/%s:1-105,%s:1-50", primaryUUID, oldUUID),
+			// Transactions of the old primary the new one doesn't have, and
+			// of a server that never was a primary.
+			102: fmt.Sprintf("MySQL56/%s:1-90,%s:1-52,%s:1-3:7", primaryUUID, oldUUID, errantUUID),
+			200: fmt.Sprintf("MySQL56/%s:1-10", primaryUUID),
+			201: fmt.Sprintf("MySQL56/%s:1-10", primaryUUID),
+		},
+	}
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmc)
+
+	addTablet := func(uid uint32, shard string, tabletType topodatapb.TabletType) {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    shard,
+			Type:     tabletType,
+		}
+		err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+		if tabletType == topodatapb.TabletType_PRIMARY {
+			_, err = ts.UpdateShardFields(ctx, "ks", shard, func(si *topo.ShardInfo) error {
+				si.PrimaryAlias = tablet.Alias
+				return nil
+			})
+			require.NoError(t, err)
+		}
+	}
+	addTablet(100, "-80", topodatapb.TabletType_PRIMARY)
+	addTablet(101, "-80", topodatapb.TabletType_REPLICA)
+	addTablet(102, "-80", topodatapb.TabletType_RDONLY)
+	addTablet(103, "-80", topodatapb.TabletType_REPLICA)
+	addTablet(200, "80-", topodatapb.TabletType_PRIMARY)
+	addTablet(201, "80-", topodatapb.TabletType_REPLICA)
+	require.NoError(t, ts.CreateShard(ctx, "ks", "c0-"))
+
+	report, err := wr.CheckErrantGTIDsShard(ctx, "ks", "-80")
+	require.NoError(t, err)
+	require.True(t, report.HasErrantGTIDs())
+	require.Equal(t, "cell1-0000000100", report.PrimaryAlias)
+	require.Equal(t, primaryUUID, report.PrimaryServerUUID)
+	require.Len(t, report.Tablets, 3)
+	require.Equal(t, &ErrantGTIDTablet{
+		TabletAlias: "cell1-0000000101",
+		Type:        "replica",
+		Position:    tmc.positions[101],
+	}, report.Tablets[0])
+	require.Equal(t, &ErrantGTIDTablet{
+		TabletAlias: "cell1-0000000102",
+		Type:        "rdonly",
+		Position:    tmc.positions[102],
+		ErrantGTIDs: fmt.Sprintf("%s:51-52,%s:1-3:7", oldUUID, errantUUID),
+		ErrantCount: 6,
+		ErrantCountBySource: map[string]int64{
+			oldUUID:    2,
+			errantUUID: 4,
+		},
+	}, report.Tablets[1])
+	require.Equal(t, "cell1-0000000103", report.Tablets[2].TabletAlias)
+	require.Contains(t, report.Tablets[2].Error, "not replicating")
+	require.Equal(t, []string{"cell1-0000000103"}, report.UncheckedTablets())
+
+	keyspaceReport, err := wr.CheckErrantGTIDsKeyspace(ctx, "ks")
+	require.NoError(t, err)
+	require.Len(t, keyspaceReport.Shards, 3)
+	require.Equal(t, []string{"-80"}, keyspaceReport.ShardsWithErrantGTIDs)
+	require.Equal(t, []string{"c0-"}, keyspaceReport.ShardsWithErrors)
+	require.False(t, keyspaceReport.Shards[1].HasErrantGTIDs())
+	require.Contains(t, keyspaceReport.Shards[2].Error, "no primary in shard ks/c0-")
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// OperationError is an error of a wrangler operation, classified by a vtrpc
+// code so that automation can tell why the operation failed:
+//   - FAILED_PRECONDITION: the operation was refused, e.g. because the shard
+//     to delete is still serving, or a validation found differences.
+//   - NOT_FOUND: the object the operation is about doesn't exist.
+//   - UNAVAILABLE, DEADLINE_EXCEEDED: the topo server or a tablet couldn't
+//     be reached.
+//   - ABORTED: the operation stopped partway, and can be run again to
+//     finish it.
+//
+// vterrors.Code returns its code, and it unwraps to its cause, so the topo
+// error types are still recognized.
+type OperationError struct {
+	Code vtrpcpb.Code
+	// Object is the keyspace, shard, tablet or workflow the error is about,
+	// e.g. "shard ks/-80".
+	Object string
+	Err    error
+}
+
+// Error is part of the error interface. The message is the one of the
+// cause, which already names the object.
+func (e *OperationError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the cause of the error.
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode is part of the vterrors.ErrorWithCode interface.
+func (e *OperationError) ErrorCode() vtrpcpb.Code {
+	return e.Code
+}
+
+// ErrorCode returns the vtrpc code of an error of a wrangler operation, even
+// if it was wrapped with fmt.Errorf since.
+func ErrorCode(err error) vtrpcpb.Code {
+	if code := vterrors.Code(err); code != vtrpcpb.Code_UNKNOWN {
+		return code
+	}
+	var coded vterrors.ErrorWithCode
+	if errors.As(err, &coded) {
+		return coded.ErrorCode()
+	}
+	return causeErrorCode(err)
+}
+
+// causeErrorCode returns the code of an error that doesn't have one, from
+// the topo error or context error it wraps.
+func causeErrorCode(err error) vtrpcpb.Code {
+	switch {
+	case topo.IsErrType(err, topo.PartialResult):
+		return vtrpcpb.Code_UNAVAILABLE
+	case errors.Is(err, context.DeadlineExceeded):
+		return vtrpcpb.Code_DEADLINE_EXCEEDED
+	case errors.Is(err, context.Canceled):
+		return vtrpcpb.Code_CANCELED
+	}
+	return topoErrorCode(err)
+}
+
+// wrapError classifies err, about object, with its own code, or with the
+// code of the topo or context error it wraps. Errors that can't be
+// classified are returned as they are.
+func wrapError(err error, object string) error {
+	if err == nil {
+		return nil
+	}
+	var opErr *OperationError
+	if errors.As(err, &opErr) {
+		return err
+	}
+	code := ErrorCode(err)
+	if code == vtrpcpb.Code_UNKNOWN {
+		return err
+	}
+	return &OperationError{Code: code, Object: object, Err: err}
+}
+
+// topoError classifies an error of the topo server about object, which is
+// UNAVAILABLE unless the topo error says otherwise.
+func topoError(err error, object string, format string, args ...any) error {
+	code := ErrorCode(err)
+	if code == vtrpcpb.Code_UNKNOWN {
+		code = vtrpcpb.Code_UNAVAILABLE
+	}
+	return &OperationError{Code: code, Object: object, Err: fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), err)}
+}
+
+// operationErrorf returns an error about object with the given code.
+func operationErrorf(code vtrpcpb.Code, object string, format string, args ...any) error {
+	return &OperationError{Code: code, Object: object, Err: fmt.Errorf(format, args...)}
+}
+
+// shardObject names a shard in an OperationError.
+func shardObject(keyspace, shard string) string {
+	return "shard " + topoproto.KeyspaceShardString(keyspace, shard)
+}
+
+// tabletObject names a tablet in an OperationError.
+func tabletObject(alias *topodatapb.TabletAlias) string {
+	return "tablet " + topoproto.TabletAliasString(alias)
+}
+
+// keyspaceObject names a keyspace in an OperationError.
+func keyspaceObject(keyspace string) string {
+	return "keyspace " + keyspace
+}
+
+// workflowObject names a workflow in an OperationError.
+func workflowObject(keyspace, workflow string) string {
+	return "workflow " + keyspace + "." + workflow
+}
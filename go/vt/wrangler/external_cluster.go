@@ -25,6 +25,9 @@ import (
 
 // MountExternalVitessCluster adds a topo record for cluster with specified parameters so that it is available to a Migrate command
 func (wr *Wrangler) MountExternalVitessCluster(ctx context.Context, clusterName, topoType, topoServer, topoRoot string) error {
+	if err := wr.CheckWritable("MountExternalVitessCluster"); err != nil {
+		return err
+	}
 	vci, err := wr.TopoServer().GetExternalVitessCluster(ctx, clusterName)
 	if err != nil {
 		return err
@@ -44,6 +47,9 @@ func (wr *Wrangler) MountExternalVitessCluster(ctx context.Context, clusterName,
 
 // UnmountExternalVitessCluster deletes a mounted cluster from the topo
 func (wr *Wrangler) UnmountExternalVitessCluster(ctx context.Context, clusterName string) error {
+	if err := wr.CheckWritable("UnmountExternalVitessCluster"); err != nil {
+		return err
+	}
 	vci, err := wr.TopoServer().GetExternalVitessCluster(ctx, clusterName)
 	if err != nil {
 		return err
@@ -83,6 +83,7 @@ func newFakeDBClient(name string) *fakeDBClient {
 			"select * from _vt.vreplication where db_name='db'":         {},
 			"select id, type, state, message from _vt.vreplication_log": {},
 			"insert into _vt.vreplication_log":                          {},
+			"select id from _vt.vreplication_log where vrepl_id":        {},
 			"SELECT db_name FROM _vt.vreplication LIMIT 0":              {},
 			"select @@session.auto_increment_increment":                 {},
 		},
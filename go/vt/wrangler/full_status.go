@@ -0,0 +1,188 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// TabletFullStatus is the full status of one tablet of a shard.
+type TabletFullStatus struct {
+	TabletAlias string
+	Type        string
+	Status      *replicationdatapb.FullStatus
+	// Diffs are the fields of Status that differ from the primary, as
+	// "name: value (primary: value)".
+	Diffs []string
+	// Error is why the full status of the tablet couldn't be read.
+	Error string
+}
+
+// MarshalJSON is part of the json.Marshaler interface. The status is
+// marshaled as a proto.
+func (ts *TabletFullStatus) MarshalJSON() ([]byte, error) {
+	out := struct {
+		TabletAlias string
+		Type        string
+		Status      json.RawMessage `json:",omitempty"`
+		Diffs       []string        `json:",omitempty"`
+		Error       string          `json:",omitempty"`
+	}{
+		TabletAlias: ts.TabletAlias,
+		Type:        ts.Type,
+		Diffs:       ts.Diffs,
+		Error:       ts.Error,
+	}
+	var err error
+	if out.Status, err = marshalSnapshotProto(ts.Status); err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+// ShardFullStatusReport is the full status of every tablet of a shard.
+type ShardFullStatusReport struct {
+	Keyspace     string
+	Shard        string
+	PrimaryAlias string `json:",omitempty"`
+	// Tablets are sorted by alias.
+	Tablets []*TabletFullStatus
+}
+
+// fullStatusComparedFields are the fields of the full status that are
+// compared with the primary: the configuration that is expected to be the
+// same on all the tablets of a shard, unlike the server identity, the
+// read-only flags and the semi-sync state, which depend on the tablet role.
+var fullStatusComparedFields = []struct {
+	name  string
+	value func(*replicationdatapb.FullStatus) any
+}{
+	{"version", func(s *replicationdatapb.FullStatus) any { return s.Version }},
+	{"version_comment", func(s *replicationdatapb.FullStatus) any { return s.VersionComment }},
+	{"gtid_mode", func(s *replicationdatapb.FullStatus) any { return s.GtidMode }},
+	{"log_bin_enabled", func(s *replicationdatapb.FullStatus) any { return s.LogBinEnabled }},
+	{"binlog_format", func(s *replicationdatapb.FullStatus) any { return s.BinlogFormat }},
+	{"binlog_row_image", func(s *replicationdatapb.FullStatus) any { return s.BinlogRowImage }},
+	{"log_replica_updates", func(s *replicationdatapb.FullStatus) any { return s.LogReplicaUpdates }},
+	{"semi_sync_primary_timeout", func(s *replicationdatapb.FullStatus) any { return s.SemiSyncPrimaryTimeout }},
+	{"semi_sync_wait_for_replica_count", func(s *replicationdatapb.FullStatus) any { return s.SemiSyncWaitForReplicaCount }},
+	{"disk_stalled", func(s *replicationdatapb.FullStatus) any { return s.DiskStalled }},
+}
+
+// GetFullStatus returns the full status of the MySQL of a tablet.
+func (wr *Wrangler) GetFullStatus(ctx context.Context, tabletAlias *topodatapb.TabletAlias) (*replicationdatapb.FullStatus, error) {
+	resp, err := wr.VtctldServer().GetFullStatus(ctx, &vtctldatapb.GetFullStatusRequest{
+		TabletAlias: tabletAlias,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Status, nil
+}
+
+// GetShardFullStatus reads the full status of all the tablets of the shard
+// concurrently, and compares the configuration of each of them with the
+// primary. A tablet whose status can't be read is reported, not returned as
+// an error. The tablets are not compared if the shard has no primary or its
+// status can't be read.
+func (wr *Wrangler) GetShardFullStatus(ctx context.Context, keyspace, shard string) (*ShardFullStatusReport, error) {
+	si, err := wr.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	tabletMap, err := wr.getTabletMapForShard(ctx, keyspace, shard, nil, warnOnUnreadableTablets)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ShardFullStatusReport{
+		Keyspace: keyspace,
+		Shard:    shard,
+	}
+	if si.HasPrimary() {
+		report.PrimaryAlias = topoproto.TabletAliasString(si.PrimaryAlias)
+	}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, ti := range tabletMap {
+		wg.Add(1)
+		go func(tablet *topodatapb.Tablet) {
+			defer wg.Done()
+			result := &TabletFullStatus{
+				TabletAlias: topoproto.TabletAliasString(tablet.Alias),
+				Type:        topoproto.TabletTypeLString(tablet.Type),
+			}
+			ctx, cancel := wr.tabletContext(ctx)
+			defer cancel()
+			status, err := callTablet(ctx, "FullStatus", idempotent, func(ctx context.Context) (*replicationdatapb.FullStatus, error) {
+				return wr.tmc.FullStatus(ctx, tablet)
+			})
+			if err != nil {
+				result.Error = fmt.Sprintf("cannot get the full status: %v", err)
+			} else {
+				result.Status = status
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			report.Tablets = append(report.Tablets, result)
+		}(ti.Tablet)
+	}
+	wg.Wait()
+	sort.Slice(report.Tablets, func(i, j int) bool { return report.Tablets[i].TabletAlias < report.Tablets[j].TabletAlias })
+
+	var primary *replicationdatapb.FullStatus
+	for _, tablet := range report.Tablets {
+		if tablet.TabletAlias == report.PrimaryAlias {
+			primary = tablet.Status
+		}
+	}
+	if primary == nil {
+		return report, nil
+	}
+	for _, tablet := range report.Tablets {
+		if tablet.Status == nil || tablet.TabletAlias == report.PrimaryAlias {
+			continue
+		}
+		tablet.Diffs = fullStatusDiffs(primary, tablet.Status)
+	}
+	return report, nil
+}
+
+// fullStatusDiffs returns the compared fields of status that differ from the
+// ones of primary.
+func fullStatusDiffs(primary, status *replicationdatapb.FullStatus) []string {
+	var diffs []string
+	for _, field := range fullStatusComparedFields {
+		value, primaryValue := field.value(status), field.value(primary)
+		if value != primaryValue {
+			diffs = append(diffs, fmt.Sprintf("%v: %v (primary: %v)", field.name, value, primaryValue))
+		}
+	}
+	return diffs
+}
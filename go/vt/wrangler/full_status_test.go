@@ -0,0 +1,144 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// fullStatusTMClient returns the full status of each tablet by uid, and
+// fails for the tablets that have none.
+type fullStatusTMClient struct {
+	tmclient.TabletManagerClient
+
+	statuses map[uint32]*replicationdatapb.FullStatus
+}
+
+func (tmc *fullStatusTMClient) FullStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.FullStatus, error) {
+	status, ok := tmc.statuses[tablet.Alias.Uid]
+	if !ok {
+		return nil, fmt.Errorf("tablet %v is down", tablet.Alias.Uid)
+	}
+	return status, nil
+}
+
+func TestGetShardFullStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+	primaryStatus := &replicationdatapb.FullStatus{
+		ServerId:       100,
+		Version:        "8.0.40",
+		GtidMode:       "ON",
+		BinlogFormat:   "ROW",
+		BinlogRowImage: "FULL",
+		LogBinEnabled:  true,
+	}
+	tmc := &fullStatusTMClient{statuses: map[uint32]*replicationdatapb.FullStatus{
+		100: primaryStatus,
+		101: {
+			ServerId:       101,
+			Version:        "8.0.40",
+			GtidMode:       "ON",
+			BinlogFormat:   "ROW",
+			BinlogRowImage: "FULL",
+			LogBinEnabled:  true,
+			ReadOnly:       true,
+		},
+		102: {
+			ServerId:       102,
+			Version:        "8.0.36",
+			GtidMode:       "ON",
+			BinlogFormat:   "ROW",
+			BinlogRowImage: "MINIMAL",
+			LogBinEnabled:  true,
+			ReadOnly:       true,
+		},
+	}}
+	wr := New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, tmc)
+
+	for uid, tabletType := range map[uint32]topodatapb.TabletType{
+		100: topodatapb.TabletType_PRIMARY,
+		101: topodatapb.TabletType_REPLICA,
+		102: topodatapb.TabletType_REPLICA,
+		103: topodatapb.TabletType_RDONLY,
+	} {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    "0",
+			Type:     tabletType,
+		}
+		err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+	}
+	_, err := ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}
+		return nil
+	})
+	require.NoError(t, err)
+
+	report, err := wr.GetShardFullStatus(ctx, "ks", "0")
+	require.NoError(t, err)
+	require.Equal(t, "cell1-0000000100", report.PrimaryAlias)
+	require.Len(t, report.Tablets, 4)
+
+	// The read-only flag depends on the role of the tablet, and is not
+	// compared.
+	require.Equal(t, "cell1-0000000100", report.Tablets[0].TabletAlias)
+	require.Equal(t, primaryStatus, report.Tablets[0].Status)
+	require.Empty(t, report.Tablets[0].Diffs)
+	require.Equal(t, "cell1-0000000101", report.Tablets[1].TabletAlias)
+	require.Empty(t, report.Tablets[1].Diffs)
+	require.Equal(t, []string{
+		"version: 8.0.36 (primary: 8.0.40)",
+		"binlog_row_image: MINIMAL (primary: FULL)",
+	}, report.Tablets[2].Diffs)
+
+	// The unreachable tablet is reported with the others.
+	require.Equal(t, "rdonly", report.Tablets[3].Type)
+	require.Nil(t, report.Tablets[3].Status)
+	require.Contains(t, report.Tablets[3].Error, "tablet 103 is down")
+
+	data, err := json.Marshal(report.Tablets[2])
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"binlog_row_image":"MINIMAL"`)
+	require.Contains(t, string(data), `"Diffs":["version: 8.0.36 (primary: 8.0.40)"`)
+
+	// Without the status of the primary, the tablets are not compared.
+	delete(tmc.statuses, 100)
+	report, err = wr.GetShardFullStatus(ctx, "ks", "0")
+	require.NoError(t, err)
+	require.NotEmpty(t, report.Tablets[0].Error)
+	require.Empty(t, report.Tablets[2].Diffs)
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/workflow"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// maxJournalRows bounds the number of journal rows read from a primary.
+const maxJournalRows = 10000
+
+// JournalPosition is the position of a shard in a resharding journal.
+type JournalPosition struct {
+	Keyspace string
+	Shard    string
+	Position string
+}
+
+// ShardJournal is a row of the _vt.resharding_journal table of a shard
+// primary, which SwitchWrites creates on each source primary of a migration.
+type ShardJournal struct {
+	ID            int64
+	DbName        string
+	MigrationType string
+	// Tables are the migrated tables, for a table migration.
+	Tables []string `json:",omitempty"`
+	// LocalPosition is the position of the source shard when its writes
+	// were switched.
+	LocalPosition string
+	// SourcePositions are the positions of the target shards, from which
+	// the reverse workflows stream.
+	SourcePositions []*JournalPosition
+	// Participants are the source shards of the migration, which all have
+	// the same journal.
+	Participants    []string
+	SourceWorkflows []string `json:",omitempty"`
+}
+
+// ShardJournals are the journals of a shard.
+type ShardJournals struct {
+	Keyspace     string
+	Shard        string
+	PrimaryAlias string `json:",omitempty"`
+	Journals     []*ShardJournal
+	// Error is why the journals of the shard couldn't be read, in a
+	// keyspace report.
+	Error string `json:",omitempty"`
+}
+
+// KeyspaceJournalReport is the result of FindKeyspaceJournal.
+type KeyspaceJournalReport struct {
+	Keyspace    string
+	MigrationID int64
+	// Shards are the shards the journal was found in.
+	Shards []*ShardJournals
+	// MissingParticipants are the participants of the journal it was not
+	// found in, which keep its reverse workflows from starting.
+	MissingParticipants []string `json:",omitempty"`
+	// ShardsWithErrors are the shards whose journals couldn't be read.
+	ShardsWithErrors []*ShardJournals `json:",omitempty"`
+}
+
+// GetShardJournals reads the resharding journals of the primary of the
+// shard, or only the one of migrationID if it is not zero, in id order. A
+// primary without the journal table has no journals.
+func (wr *Wrangler) GetShardJournals(ctx context.Context, keyspace, shard string, migrationID int64) (*ShardJournals, error) {
+	si, err := wr.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	if !si.HasPrimary() {
+		return nil, fmt.Errorf("no primary in shard %v/%v to read the journals from", keyspace, shard)
+	}
+	primary, err := wr.ts.GetTablet(ctx, si.PrimaryAlias)
+	if err != nil {
+		return nil, err
+	}
+	return wr.primaryJournals(ctx, keyspace, shard, primary, migrationID)
+}
+
+// primaryJournals is the implementation of GetShardJournals once the
+// primary of the shard is found.
+func (wr *Wrangler) primaryJournals(ctx context.Context, keyspace, shard string, primary *topo.TabletInfo, migrationID int64) (*ShardJournals, error) {
+	result := &ShardJournals{
+		Keyspace:     keyspace,
+		Shard:        shard,
+		PrimaryAlias: primary.AliasString(),
+	}
+	var err error
+	result.Journals, err = wr.readJournals(ctx, primary.Tablet, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the journals of primary %v: %v", primary.AliasString(), err)
+	}
+	return result, nil
+}
+
+// readJournals reads and decodes the resharding journals of the tablet.
+func (wr *Wrangler) readJournals(ctx context.Context, tablet *topodatapb.Tablet, migrationID int64) ([]*ShardJournal, error) {
+	query := "select id, db_name, val from _vt.resharding_journal"
+	if migrationID != 0 {
+		query += fmt.Sprintf(" where id = %d", migrationID)
+	}
+	query += " order by id"
+	qr, err := callTablet(ctx, "ExecuteFetchAsDba", idempotent, func(ctx context.Context) (*querypb.QueryResult, error) {
+		return wr.tmc.ExecuteFetchAsDba(ctx, tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:   []byte(query),
+			MaxRows: maxJournalRows,
+		})
+	})
+	if err != nil {
+		if workflow.IsTableDidNotExistError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var journals []*ShardJournal
+	for _, row := range sqltypes.Proto3ToResult(qr).Named().Rows {
+		id, err := row.ToInt64("id")
+		if err != nil {
+			return nil, err
+		}
+		val, err := row.ToBytes("val")
+		if err != nil {
+			return nil, err
+		}
+		journal := &binlogdatapb.Journal{}
+		if err := prototext.Unmarshal(val, journal); err != nil {
+			return nil, fmt.Errorf("cannot decode journal %d: %v", id, err)
+		}
+		journals = append(journals, newShardJournal(id, row.AsString("db_name", ""), journal))
+	}
+	return journals, nil
+}
+
+// newShardJournal converts a decoded journal.
+func newShardJournal(id int64, dbName string, journal *binlogdatapb.Journal) *ShardJournal {
+	result := &ShardJournal{
+		ID:              id,
+		DbName:          dbName,
+		MigrationType:   journal.MigrationType.String(),
+		Tables:          journal.Tables,
+		LocalPosition:   journal.LocalPosition,
+		SourceWorkflows: journal.SourceWorkflows,
+	}
+	for _, sg := range journal.ShardGtids {
+		result.SourcePositions = append(result.SourcePositions, &JournalPosition{
+			Keyspace: sg.Keyspace,
+			Shard:    sg.Shard,
+			Position: sg.Gtid,
+		})
+	}
+	for _, participant := range journal.Participants {
+		result.Participants = append(result.Participants, topoproto.KeyspaceShardString(participant.Keyspace, participant.Shard))
+	}
+	return result
+}
+
+// FindKeyspaceJournal looks for the journal of migrationID on the primaries
+// of all the shards of the keyspace, and reports the participants of the
+// journal it is missing from. A shard whose journals can't be read is
+// reported, not returned as an error.
+func (wr *Wrangler) FindKeyspaceJournal(ctx context.Context, keyspace string, migrationID int64) (*KeyspaceJournalReport, error) {
+	if migrationID == 0 {
+		return nil, fmt.Errorf("a migration id is required to find a journal in keyspace %v", keyspace)
+	}
+	var (
+		mu      sync.Mutex
+		byShard = make(map[string]*ShardJournals)
+	)
+	shardResults, err := wr.ForEachShardPrimary(ctx, keyspace, ShardPrimaryOptions{}, func(ctx context.Context, shard string, primary *topo.TabletInfo) error {
+		result, err := wr.primaryJournals(ctx, keyspace, shard, primary, migrationID)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		byShard[shard] = result
+		return nil
+	})
+	if shardResults == nil {
+		return nil, err
+	}
+	results := make([]*ShardJournals, len(shardResults))
+	for i, shardResult := range shardResults {
+		result, ok := byShard[shardResult.Shard]
+		if !ok {
+			result = &ShardJournals{
+				Keyspace:     keyspace,
+				Shard:        shardResult.Shard,
+				PrimaryAlias: shardResult.Primary,
+				Error:        shardResult.Error,
+			}
+		}
+		results[i] = result
+	}
+
+	report := &KeyspaceJournalReport{
+		Keyspace:    keyspace,
+		MigrationID: migrationID,
+	}
+	participants := make(map[string]bool)
+	found := make(map[string]bool)
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			report.ShardsWithErrors = append(report.ShardsWithErrors, result)
+		case len(result.Journals) > 0:
+			report.Shards = append(report.Shards, result)
+			found[topoproto.KeyspaceShardString(keyspace, result.Shard)] = true
+			for _, participant := range result.Journals[0].Participants {
+				participants[participant] = true
+			}
+		}
+	}
+	for participant := range participants {
+		if !found[participant] {
+			report.MissingParticipants = append(report.MissingParticipants, participant)
+		}
+	}
+	sort.Strings(report.MissingParticipants)
+	return report, nil
+}
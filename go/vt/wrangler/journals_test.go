@@ -0,0 +1,164 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// journalTMClient answers the journal queries with the journals of each
+// tablet, and fails as if the journal table didn't exist for the tablets
+// without any.
+type journalTMClient struct {
+	tmclient.TabletManagerClient
+
+	journals map[uint32][]*binlogdatapb.Journal
+
+	mu      sync.Mutex
+	queries []string
+}
+
+func (tmc *journalTMClient) ExecuteFetchAsDba(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsDbaRequest) (*querypb.QueryResult, error) {
+	query := string(req.Query)
+	tmc.mu.Lock()
+	tmc.queries = append(tmc.queries, query)
+	tmc.mu.Unlock()
+
+	journals, ok := tmc.journals[tablet.Alias.Uid]
+	if !ok {
+		return nil, sqlerror.NewSQLError(sqlerror.ERNoSuchTable, sqlerror.SSUnknownSQLState, "Table '_vt.resharding_journal' doesn't exist")
+	}
+	var rows []string
+	for _, journal := range journals {
+		if strings.Contains(query, " where id = ") && !strings.Contains(query, fmt.Sprintf(" where id = %d ", journal.Id)) {
+			continue
+		}
+		rows = append(rows, fmt.Sprintf("%d|vt_ks|%s", journal.Id, prototext.Format(journal)))
+	}
+	return sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|db_name|val", "int64|varchar|varbinary"), rows...)), nil
+}
+
+func TestShardJournals(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	journal := &binlogdatapb.Journal{
+		Id:            7,
+		MigrationType: binlogdatapb.MigrationType_SHARDS,
+		LocalPosition: "MySQL56/00000000-0000-0000-0000-000000000001:1-10",
+		ShardGtids: []*binlogdatapb.ShardGtid{{
+			Keyspace: "ks",
+			Shard:    "-40",
+			Gtid:     "MySQL56/00000000-0000-0000-0000-000000000002:1-5",
+		}},
+		Participants: []*binlogdatapb.KeyspaceShard{
+			{Keyspace: "ks", Shard: "-80"},
+			{Keyspace: "ks", Shard: "80-"},
+		},
+		SourceWorkflows: []string{"wf"},
+	}
+	ts := memorytopo.NewServer(ctx, "cell1")
+	tmc := &journalTMClient{
+		journals: map[uint32][]*binlogdatapb.Journal{
+			100: {journal},
+		},
+	}
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmc)
+
+	for shard, uid := range map[string]uint32{"-80": 100, "80-": 200} {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    shard,
+			Type:     topodatapb.TabletType_PRIMARY,
+		}
+		err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+		_, err = ts.UpdateShardFields(ctx, "ks", shard, func(si *topo.ShardInfo) error {
+			si.PrimaryAlias = tablet.Alias
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, ts.CreateShard(ctx, "ks", "c0-"))
+
+	result, err := wr.GetShardJournals(ctx, "ks", "-80", 0)
+	require.NoError(t, err)
+	require.Equal(t, "select id, db_name, val from _vt.resharding_journal order by id", tmc.queries[0])
+	require.Equal(t, &ShardJournals{
+		Keyspace:     "ks",
+		Shard:        "-80",
+		PrimaryAlias: "cell1-0000000100",
+		Journals: []*ShardJournal{{
+			ID:            7,
+			DbName:        "vt_ks",
+			MigrationType: "SHARDS",
+			LocalPosition: journal.LocalPosition,
+			SourcePositions: []*JournalPosition{{
+				Keyspace: "ks",
+				Shard:    "-40",
+				Position: "MySQL56/00000000-0000-0000-0000-000000000002:1-5",
+			}},
+			Participants:    []string{"ks/-80", "ks/80-"},
+			SourceWorkflows: []string{"wf"},
+		}},
+	}, result)
+
+	// A primary without the journal table has no journals.
+	result, err = wr.GetShardJournals(ctx, "ks", "80-", 0)
+	require.NoError(t, err)
+	require.Empty(t, result.Journals)
+
+	_, err = wr.GetShardJournals(ctx, "ks", "c0-", 0)
+	require.ErrorContains(t, err, "no primary in shard ks/c0-")
+
+	report, err := wr.FindKeyspaceJournal(ctx, "ks", 7)
+	require.NoError(t, err)
+	require.Len(t, report.Shards, 1)
+	require.Equal(t, "-80", report.Shards[0].Shard)
+	require.Equal(t, []string{"ks/80-"}, report.MissingParticipants)
+	require.Len(t, report.ShardsWithErrors, 1)
+	require.Equal(t, "c0-", report.ShardsWithErrors[0].Shard)
+
+	report, err = wr.FindKeyspaceJournal(ctx, "ks", 8)
+	require.NoError(t, err)
+	require.Empty(t, report.Shards)
+	require.Empty(t, report.MissingParticipants)
+
+	_, err = wr.FindKeyspaceJournal(ctx, "ks", 0)
+	require.ErrorContains(t, err, "a migration id is required")
+}
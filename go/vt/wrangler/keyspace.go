@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
@@ -58,6 +59,8 @@ func (wr *Wrangler) validateNewWorkflow(ctx context.Context, keyspace, workflow
 		wg.Add(1)
 		go func(si *topo.ShardInfo) {
 			defer wg.Done()
+			ctx, cancel := wr.tabletContext(ctx)
+			defer cancel()
 
 			primary, err := wr.ts.GetTablet(ctx, si.PrimaryAlias)
 			if err != nil {
@@ -94,23 +97,34 @@ func (wr *Wrangler) validateNewWorkflow(ctx context.Context, keyspace, workflow
 
 // refreshPrimaryTablets will just RPC-ping all the primary tablets with RefreshState
 func (wr *Wrangler) refreshPrimaryTablets(ctx context.Context, shards []*topo.ShardInfo) error {
+	span, ctx := trace.NewSpan(ctx, "Wrangler.refreshPrimaryTablets")
+	defer span.Finish()
+	span.Annotate("shards", len(shards))
+
+	logger, flush := wr.fanOutLogger()
+	defer flush()
 	wg := sync.WaitGroup{}
 	rec := concurrency.AllErrorRecorder{}
 	for _, si := range shards {
 		wg.Add(1)
 		go func(si *topo.ShardInfo) {
 			defer wg.Done()
-			wr.Logger().Infof("RefreshState primary %v", topoproto.TabletAliasString(si.PrimaryAlias))
+			ctx, cancel := wr.tabletContext(ctx)
+			defer cancel()
+			logger.Infof("RefreshState primary %v", topoproto.TabletAliasString(si.PrimaryAlias))
 			ti, err := wr.ts.GetTablet(ctx, si.PrimaryAlias)
 			if err != nil {
 				rec.RecordError(err)
 				return
 			}
 
+			span, ctx := trace.NewSpan(ctx, "Wrangler.RefreshState")
+			defer span.Finish()
+			span.Annotate("tablet", ti.AliasString())
 			if err := wr.tmc.RefreshState(ctx, ti.Tablet); err != nil {
 				rec.RecordError(err)
 			} else {
-				wr.Logger().Infof("%v responded", topoproto.TabletAliasString(si.PrimaryAlias))
+				logger.Infof("%v responded", topoproto.TabletAliasString(si.PrimaryAlias))
 			}
 		}(si)
 	}
@@ -0,0 +1,55 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+var (
+	// fanOutLogMaxEvents is how many similar messages the loops over all
+	// the tablets of a shard or keyspace log per fanOutLogInterval. Zero
+	// means no limit.
+	fanOutLogMaxEvents int
+	fanOutLogInterval  = 10 * time.Second
+)
+
+func init() {
+	servenv.OnParseFor("vtctl", registerFanOutLogFlags)
+	servenv.OnParseFor("vtctld", registerFanOutLogFlags)
+}
+
+func registerFanOutLogFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&fanOutLogMaxEvents, "fanout-log-max-events", fanOutLogMaxEvents, "if positive, validation and refresh commands log at most this many similar per-tablet messages per --fanout-log-interval, and summarize the others.")
+	fs.DurationVar(&fanOutLogInterval, "fanout-log-interval", fanOutLogInterval, "interval over which --fanout-log-max-events applies.")
+}
+
+// fanOutLogger returns the logger to use in a loop over many tablets, and
+// the function to call once the loop is done. If --fanout-log-max-events
+// is set, the logger throttles similar messages.
+func (wr *Wrangler) fanOutLogger() (logutil.Logger, func()) {
+	if fanOutLogMaxEvents <= 0 {
+		return wr.Logger(), func() {}
+	}
+	logger := logutil.NewTemplateThrottledLogger(wr.Logger(), fanOutLogMaxEvents, fanOutLogInterval)
+	return logger, logger.Flush
+}
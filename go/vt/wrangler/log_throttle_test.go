@@ -0,0 +1,51 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+)
+
+func TestLogValidationFindingsThrottled(t *testing.T) {
+	var findings []ValidationFinding
+	for i := range 10 {
+		findings = append(findings, ValidationFinding{
+			Severity: ValidationError,
+			Message:  fmt.Sprintf("tablet zone1-%010d is not replicating", i),
+		})
+	}
+
+	logger := logutil.NewMemoryLogger()
+	wr := &Wrangler{logger: logger}
+	require.ErrorContains(t, wr.logValidationFindings(findings), "some validation errors")
+	require.Len(t, logger.Events, 10)
+
+	oldMaxEvents := fanOutLogMaxEvents
+	fanOutLogMaxEvents = 3
+	defer func() { fanOutLogMaxEvents = oldMaxEvents }()
+
+	logger.Clear()
+	require.ErrorContains(t, wr.logValidationFindings(findings), "some validation errors")
+	require.Len(t, logger.Events, 4)
+	require.Equal(t, "tablet zone1-0000000002 is not replicating", logger.Events[2].Value)
+	require.Equal(t, `suppressed 7 similar messages: "tablet zone#-# is not replicating"`, logger.Events[3].Value)
+}
@@ -147,6 +147,11 @@ func (wr *Wrangler) MoveTables(ctx context.Context, workflow, sourceKeyspace, ta
 		wr.sourceTs = externalTopo
 		log.Infof("Successfully opened external topo: %+v", externalTopo)
 	}
+	if atomicCopy {
+		if err := wr.validateAtomicCopy(ctx, sourceKeyspace, tableSpecs, allTables, excludeTables, sourceShards); err != nil {
+			return err
+		}
+	}
 
 	var (
 		vschema     *topo.KeyspaceVSchemaInfo
@@ -272,6 +277,8 @@ func (wr *Wrangler) MoveTables(ctx context.Context, workflow, sourceKeyspace, ta
 	// have been created, then we clean up the workflow's artifacts.
 	defer func() {
 		if err != nil {
+			ctx, cancel := wr.cleanupContext(ctx)
+			defer cancel()
 			ts, cerr := wr.buildTrafficSwitcher(ctx, ms.TargetKeyspace, ms.Workflow)
 			if cerr != nil {
 				err = vterrors.Wrapf(err, "failed to cleanup workflow artifacts: %v", cerr)
@@ -395,6 +402,21 @@ func (wr *Wrangler) validateSourceTablesExist(sourceKeyspace string, ksTables, t
 }
 
 func (wr *Wrangler) getKeyspaceTables(ctx context.Context, ks string, ts *topo.Server) ([]string, error) {
+	schema, err := wr.getKeyspaceSchema(ctx, ks, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	var sourceTables []string
+	for _, td := range schema.TableDefinitions {
+		sourceTables = append(sourceTables, td.Name)
+	}
+	return sourceTables, nil
+}
+
+// getKeyspaceSchema returns the schema of all the tables of the keyspace,
+// from the primary of its first serving shard.
+func (wr *Wrangler) getKeyspaceSchema(ctx context.Context, ks string, ts *topo.Server) (*tabletmanagerdatapb.SchemaDefinition, error) {
 	shards, err := ts.GetServingShards(ctx, ks)
 	if err != nil {
 		return nil, err
@@ -413,17 +435,14 @@ func (wr *Wrangler) getKeyspaceTables(ctx context.Context, ks string, ts *topo.S
 		return nil, err
 	}
 	req := &tabletmanagerdatapb.GetSchemaRequest{Tables: allTables}
-	schema, err := wr.tmc.GetSchema(ctx, ti.Tablet, req)
+	schema, err := callTablet(ctx, "GetSchema", idempotent, func(ctx context.Context) (*tabletmanagerdatapb.SchemaDefinition, error) {
+		return wr.tmc.GetSchema(ctx, ti.Tablet, req)
+	})
 	if err != nil {
 		return nil, err
 	}
 	log.Infof("got table schemas from source primary %v.", primary)
-
-	var sourceTables []string
-	for _, td := range schema.TableDefinitions {
-		sourceTables = append(sourceTables, td.Name)
-	}
-	return sourceTables, nil
+	return schema, nil
 }
 
 func (wr *Wrangler) checkIfPreviousJournalExists(ctx context.Context, mz *materializer, migrationID int64) (bool, []string, error) {
@@ -1158,7 +1177,9 @@ func (mz *materializer) getSourceTableDDLs(ctx context.Context) (map[string]stri
 		return nil, err
 	}
 	req := &tabletmanagerdatapb.GetSchemaRequest{Tables: allTables}
-	sourceSchema, err := mz.wr.tmc.GetSchema(ctx, ti.Tablet, req)
+	sourceSchema, err := callTablet(ctx, "GetSchema", idempotent, func(ctx context.Context) (*tabletmanagerdatapb.SchemaDefinition, error) {
+		return mz.wr.tmc.GetSchema(ctx, ti.Tablet, req)
+	})
 	if err != nil {
 		return nil, err
 	}
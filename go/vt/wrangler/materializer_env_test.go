@@ -280,7 +280,7 @@ func (tmc *testMaterializerTMClient) verifyQueries(t *testing.T) {
 			for _, qr := range qrs {
 				list = append(list, qr.query)
 			}
-			assert("tablet %v: found queries that were expected but never got executed by the test: %v", tabletID, list)
+			t.Errorf("tablet %v: found queries that were expected but never got executed by the test: %v", tabletID, list)
 		}
 	}
 }
@@ -0,0 +1,401 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// Operation describes a long-running wrangler operation, such as Validate,
+// BackupKeyspace or DrainCell, that can be canceled with CancelOperation
+// while it runs.
+type Operation struct {
+	ID   string
+	Name string
+	// Target is what the operation runs on, e.g. a keyspace or a cell.
+	Target  string
+	Server  string
+	Started time.Time
+	// Heartbeat is when the vtctld running the operation last checked its
+	// record in the topo.
+	Heartbeat       time.Time `json:",omitempty"`
+	CancelRequested bool
+	// Stale is set by ListOperations for an operation recorded in the topo
+	// whose vtctld stopped checking it, e.g. because it died.
+	Stale bool `json:",omitempty"`
+}
+
+// operationsStaleHeartbeats is how many check intervals an operation
+// recorded in the topo can go without a heartbeat before it is stale.
+const operationsStaleHeartbeats = 3
+
+// operationRegistry keeps the operations running in this process and, if
+// topoPath is set, records them in the global topo so that any vtctld can
+// list and cancel them.
+type operationRegistry struct {
+	mu  sync.Mutex
+	ops map[string]*operation
+
+	topoPath      string
+	checkInterval time.Duration
+	server        string
+}
+
+func newOperationRegistry() *operationRegistry {
+	server, err := os.Hostname()
+	if err != nil {
+		server = "unknown"
+	}
+	return &operationRegistry{
+		ops:           map[string]*operation{},
+		checkInterval: 10 * time.Second,
+		server:        server,
+	}
+}
+
+// operations is the registry of the operations of the wranglers of this
+// process.
+var operations = newOperationRegistry()
+
+// SetOperationsTopoPath makes the wranglers of this process record their
+// long-running operations under the given path of the global topo, so that
+// they can be listed and canceled from any vtctld. An empty path, the
+// default, keeps them in memory only.
+func SetOperationsTopoPath(topoPath string) {
+	operations.mu.Lock()
+	defer operations.mu.Unlock()
+	operations.topoPath = topoPath
+}
+
+// operation is a running Operation.
+type operation struct {
+	registry *operationRegistry
+	ts       *topo.Server
+
+	mu sync.Mutex
+	Operation
+	// cancel cancels the context of an interruptible operation.
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// start registers a new operation, and returns the context to run it with.
+// If interruptible is set, that context is canceled when the operation is,
+// which is only safe for operations that change nothing. Otherwise the
+// operation has to check canceled at its safe points. The operation has to
+// be finished once it returns.
+func (r *operationRegistry) start(ctx context.Context, ts *topo.Server, name, target string, interruptible bool) (context.Context, *operation) {
+	op := &operation{
+		registry: r,
+		ts:       ts,
+		Operation: Operation{
+			ID:      uuid.NewString(),
+			Name:    name,
+			Target:  target,
+			Server:  r.server,
+			Started: time.Now(),
+		},
+		done: make(chan struct{}),
+	}
+	if interruptible {
+		ctx, op.cancel = context.WithCancel(ctx)
+	}
+
+	r.mu.Lock()
+	r.ops[op.ID] = op
+	topoPath := r.topoPath
+	r.mu.Unlock()
+
+	if topoPath != "" {
+		if err := op.create(ctx, topoPath); err != nil {
+			log.Warningf("Cannot record operation %v in the topo, it can only be canceled from this vtctld: %v", op.ID, err)
+		} else {
+			go op.watch(topoPath)
+		}
+	}
+	return ctx, op
+}
+
+// finish unregisters the operation, and removes its record from the topo.
+func (op *operation) finish() {
+	r := op.registry
+	r.mu.Lock()
+	delete(r.ops, op.ID)
+	topoPath := r.topoPath
+	r.mu.Unlock()
+
+	close(op.done)
+	if op.cancel != nil {
+		op.cancel()
+	}
+	if topoPath != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+		defer cancel()
+		if err := op.deleteRecord(ctx, topoPath); err != nil && !topo.IsErrType(err, topo.NoNode) {
+			log.Warningf("Cannot remove the record of operation %v from the topo: %v", op.ID, err)
+		}
+	}
+}
+
+// canceled returns true once the operation was canceled. It can be called
+// on a nil operation.
+func (op *operation) canceled() bool {
+	if op == nil {
+		return false
+	}
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.CancelRequested
+}
+
+// errCanceled is the error an operation returns when it stops because it
+// was canceled.
+func (op *operation) errCanceled() error {
+	return vterrors.Errorf(vtrpcpb.Code_CANCELED, "operation %v (%v %v) was canceled", op.ID, op.Name, op.Target)
+}
+
+// requestCancel marks the operation canceled, and cancels its context if
+// it is interruptible.
+func (op *operation) requestCancel() {
+	op.mu.Lock()
+	op.CancelRequested = true
+	op.mu.Unlock()
+	if op.cancel != nil {
+		op.cancel()
+	}
+}
+
+func (op *operation) snapshot() *Operation {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	o := op.Operation
+	return &o
+}
+
+func operationTopoFile(topoPath, id string) string {
+	return path.Join(topoPath, id)
+}
+
+func (op *operation) create(ctx context.Context, topoPath string) error {
+	conn, err := op.ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return err
+	}
+	o := op.snapshot()
+	o.Heartbeat = time.Now()
+	data, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Create(ctx, operationTopoFile(topoPath, op.ID), data)
+	return err
+}
+
+func (op *operation) deleteRecord(ctx context.Context, topoPath string) error {
+	conn, err := op.ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return err
+	}
+	return conn.Delete(ctx, operationTopoFile(topoPath, op.ID), nil)
+}
+
+// watch checks the record of the operation in the topo every check
+// interval until the operation finishes, cancels the operation if another
+// vtctld requested it, and updates its heartbeat otherwise.
+func (op *operation) watch(topoPath string) {
+	ticker := time.NewTicker(op.registry.checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-op.done:
+			return
+		case <-ticker.C:
+		}
+		if err := op.check(topoPath); err != nil {
+			log.Warningf("Cannot check the record of operation %v in the topo: %v", op.ID, err)
+		}
+	}
+}
+
+func (op *operation) check(topoPath string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+	defer cancel()
+	conn, err := op.ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return err
+	}
+	file := operationTopoFile(topoPath, op.ID)
+	data, version, err := conn.Get(ctx, file)
+	if err != nil {
+		return err
+	}
+	var o Operation
+	if err := json.Unmarshal(data, &o); err != nil {
+		return err
+	}
+	if o.CancelRequested {
+		if !op.canceled() {
+			log.Infof("Operation %v (%v %v) was canceled from the topo", op.ID, op.Name, op.Target)
+			op.requestCancel()
+		}
+		return nil
+	}
+	o.Heartbeat = time.Now()
+	if data, err = json.Marshal(&o); err != nil {
+		return err
+	}
+	// A BadVersion error means the record was just changed, probably to
+	// cancel the operation, which the next check picks up.
+	if _, err := conn.Update(ctx, file, data, version); err != nil && !topo.IsErrType(err, topo.BadVersion) {
+		return err
+	}
+	return nil
+}
+
+// list returns the operations running in this process and, if the registry
+// records them in the topo, those of the other vtctlds, sorted by start
+// time.
+func (r *operationRegistry) list(ctx context.Context, ts *topo.Server) ([]*Operation, error) {
+	r.mu.Lock()
+	topoPath := r.topoPath
+	ops := make(map[string]*Operation, len(r.ops))
+	for id, op := range r.ops {
+		ops[id] = op.snapshot()
+	}
+	r.mu.Unlock()
+
+	if topoPath != "" {
+		conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := conn.ListDir(ctx, topoPath, false /*full*/)
+		if err != nil && !topo.IsErrType(err, topo.NoNode) {
+			return nil, err
+		}
+		staleBefore := time.Now().Add(-operationsStaleHeartbeats * r.checkInterval)
+		for _, entry := range entries {
+			if _, ok := ops[entry.Name]; ok {
+				continue
+			}
+			data, _, err := conn.Get(ctx, operationTopoFile(topoPath, entry.Name))
+			if topo.IsErrType(err, topo.NoNode) {
+				// It just finished.
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			o := &Operation{}
+			if err := json.Unmarshal(data, o); err != nil {
+				return nil, vterrors.Wrapf(err, "cannot parse the record of operation %v", entry.Name)
+			}
+			o.Stale = o.Heartbeat.Before(staleBefore)
+			ops[o.ID] = o
+		}
+	}
+
+	result := make([]*Operation, 0, len(ops))
+	for _, o := range ops {
+		result = append(result, o)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Started.Equal(result[j].Started) {
+			return result[i].Started.Before(result[j].Started)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// cancel cancels the operation with the given ID, if it runs in this
+// process, or requests its vtctld to cancel it through the topo.
+func (r *operationRegistry) cancel(ctx context.Context, ts *topo.Server, id string) error {
+	r.mu.Lock()
+	op := r.ops[id]
+	topoPath := r.topoPath
+	r.mu.Unlock()
+
+	if op != nil {
+		op.requestCancel()
+		log.Infof("Operation %v (%v %v) was canceled", op.ID, op.Name, op.Target)
+		return nil
+	}
+	if topoPath == "" {
+		return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "operation %v is not running on this vtctld", id)
+	}
+
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return err
+	}
+	file := operationTopoFile(topoPath, id)
+	for {
+		data, version, err := conn.Get(ctx, file)
+		if topo.IsErrType(err, topo.NoNode) {
+			return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "operation %v is not running", id)
+		}
+		if err != nil {
+			return err
+		}
+		var o Operation
+		if err := json.Unmarshal(data, &o); err != nil {
+			return vterrors.Wrapf(err, "cannot parse the record of operation %v", id)
+		}
+		o.CancelRequested = true
+		if data, err = json.Marshal(&o); err != nil {
+			return err
+		}
+		if _, err = conn.Update(ctx, file, data, version); !topo.IsErrType(err, topo.BadVersion) {
+			return err
+		}
+	}
+}
+
+// startOperation registers a long-running operation of the wrangler, see
+// operationRegistry.start.
+func (wr *Wrangler) startOperation(ctx context.Context, name, target string, interruptible bool) (context.Context, *operation) {
+	ctx, op := operations.start(ctx, wr.ts, name, target, interruptible)
+	wr.Logger().Infof("Started operation %v (%v %v), it can be canceled with CancelOperation", op.ID, name, target)
+	return ctx, op
+}
+
+// ListOperations returns the long-running operations of the wranglers of
+// this vtctld and, if they are recorded in the topo, of the other vtctlds.
+func (wr *Wrangler) ListOperations(ctx context.Context) ([]*Operation, error) {
+	return operations.list(ctx, wr.ts)
+}
+
+// CancelOperation cancels a long-running operation. It stops at its next
+// safe point, after the cleanup it normally does.
+func (wr *Wrangler) CancelOperation(ctx context.Context, id string) error {
+	return operations.cancel(ctx, wr.ts, id)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestOperationRegistry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+
+	r := newOperationRegistry()
+	opCtx, op := r.start(ctx, ts, "Validate", "all keyspaces", true /*interruptible*/)
+	_, op2 := r.start(ctx, ts, "BackupKeyspace", "ks", false /*interruptible*/)
+
+	ops, err := r.list(ctx, ts)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	require.Equal(t, op.ID, ops[0].ID)
+	require.Equal(t, "Validate", ops[0].Name)
+	require.Equal(t, op2.ID, ops[1].ID)
+
+	require.NoError(t, r.cancel(ctx, ts, op.ID))
+	require.True(t, op.canceled())
+	require.Error(t, opCtx.Err(), "the context of an interruptible operation is canceled")
+	require.False(t, op2.canceled())
+	require.Equal(t, vtrpcpb.Code_CANCELED, vterrors.Code(op.errCanceled()))
+
+	op.finish()
+	op2.finish()
+	ops, err = r.list(ctx, ts)
+	require.NoError(t, err)
+	require.Empty(t, ops)
+
+	err = r.cancel(ctx, ts, op.ID)
+	require.Equal(t, vtrpcpb.Code_NOT_FOUND, vterrors.Code(err), err)
+}
+
+func TestOperationRegistryTopo(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+
+	// Two vtctlds sharing the topo.
+	newRegistry := func(server string) *operationRegistry {
+		r := newOperationRegistry()
+		r.topoPath = "operations"
+		r.checkInterval = 10 * time.Millisecond
+		r.server = server
+		return r
+	}
+	r1, r2 := newRegistry("vtctld1"), newRegistry("vtctld2")
+
+	opCtx, op := r1.start(ctx, ts, "DrainCell", "cell2", false /*interruptible*/)
+	ops, err := r2.list(ctx, ts)
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	require.Equal(t, op.ID, ops[0].ID)
+	require.Equal(t, "vtctld1", ops[0].Server)
+	require.False(t, ops[0].Stale)
+
+	require.NoError(t, r2.cancel(ctx, ts, op.ID))
+	require.Eventually(t, op.canceled, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, opCtx.Err(), "the context of an operation that is not interruptible is left alone")
+
+	op.finish()
+	ops, err = r2.list(ctx, ts)
+	require.NoError(t, err)
+	require.Empty(t, ops)
+	err = r2.cancel(ctx, ts, op.ID)
+	require.Equal(t, vtrpcpb.Code_NOT_FOUND, vterrors.Code(err), err)
+
+	// The record of an operation whose vtctld died is stale.
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	require.NoError(t, err)
+	data, err := json.Marshal(&Operation{ID: "dead", Name: "Validate", Heartbeat: time.Now().Add(-time.Minute)})
+	require.NoError(t, err)
+	_, err = conn.Create(ctx, "operations/dead", data)
+	require.NoError(t, err)
+	ops, err = r2.list(ctx, ts)
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	require.True(t, ops[0].Stale)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"time"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+)
+
+// options configure a Wrangler created with NewWithOptions. options are set
+// by the Option values passed to it.
+type options struct {
+	tmc           tmclient.TabletManagerClient
+	pickerOptions discovery.TabletPickerOptions
+	now           func() time.Time
+	loggerPrefix  string
+	readOnly      bool
+}
+
+// Option configures a Wrangler created with NewWithOptions.
+type Option interface {
+	apply(*options)
+}
+
+// funcOption wraps a function that modifies options into an implementation
+// of the Option interface.
+type funcOption struct {
+	f func(*options)
+}
+
+func (fo *funcOption) apply(o *options) {
+	fo.f(o)
+}
+
+func newFuncOption(f func(*options)) *funcOption {
+	return &funcOption{
+		f: f,
+	}
+}
+
+// WithTabletManagerClient sets the client the wrangler, and the vtctld
+// server it delegates to, use to talk to the tablets. If this option is not
+// provided then tmclient.NewTabletManagerClient is used.
+func WithTabletManagerClient(tmc tmclient.TabletManagerClient) Option {
+	return newFuncOption(func(o *options) {
+		o.tmc = tmc
+	})
+}
+
+// WithTabletPickerOptions sets the options of the tablet pickers the
+// wrangler creates, e.g. to pick tablets to stream from. The fields set by
+// the action itself, like IncludeNonServingTablets for a VDiff of a
+// Reshard, are overridden.
+func WithTabletPickerOptions(pickerOptions discovery.TabletPickerOptions) Option {
+	return newFuncOption(func(o *options) {
+		o.pickerOptions = pickerOptions
+	})
+}
+
+// WithClock sets the function the wrangler gets the current time from, e.g.
+// to decide which backups are old enough to be removed. If this option is
+// not provided then time.Now is used.
+func WithClock(now func() time.Time) Option {
+	return newFuncOption(func(o *options) {
+		o.now = now
+	})
+}
+
+// WithLoggerPrefix prefixes the messages the wrangler logs with prefix.
+func WithLoggerPrefix(prefix string) Option {
+	return newFuncOption(func(o *options) {
+		o.loggerPrefix = prefix
+	})
+}
+
+// WithReadOnly makes the wrangler read-only: the operations that would
+// change the topo or the tablets are refused, see Wrangler.CheckWritable.
+func WithReadOnly() Option {
+	return newFuncOption(func(o *options) {
+		o.readOnly = true
+	})
+}
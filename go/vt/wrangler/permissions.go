@@ -17,19 +17,16 @@ limitations under the License.
 package wrangler
 
 import (
+	"errors"
 	"fmt"
-	"sort"
-	"sync"
+	"strings"
 
 	"context"
 
-	"vitess.io/vitess/go/vt/concurrency"
-	"vitess.io/vitess/go/vt/log"
-	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
-	"vitess.io/vitess/go/vt/topo/topoproto"
-
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 // GetPermissions returns the permissions set on a remote tablet
@@ -39,120 +36,81 @@ func (wr *Wrangler) GetPermissions(ctx context.Context, tabletAlias *topodatapb.
 		return nil, err
 	}
 
-	return wr.tmc.GetPermissions(ctx, ti.Tablet)
-}
-
-// diffPermissions is a helper method to asynchronously diff a permissions
-func (wr *Wrangler) diffPermissions(ctx context.Context, primaryPermissions *tabletmanagerdatapb.Permissions, primaryAlias *topodatapb.TabletAlias, alias *topodatapb.TabletAlias, wg *sync.WaitGroup, er concurrency.ErrorRecorder) {
-	defer wg.Done()
-	log.Infof("Gathering permissions for %v", topoproto.TabletAliasString(alias))
-	replicaPermissions, err := wr.GetPermissions(ctx, alias)
-	if err != nil {
-		er.RecordError(err)
-		return
-	}
-
-	log.Infof("Diffing permissions for %v", topoproto.TabletAliasString(alias))
-	tmutils.DiffPermissions(topoproto.TabletAliasString(primaryAlias), primaryPermissions, topoproto.TabletAliasString(alias), replicaPermissions, er)
+	return callTablet(ctx, "GetPermissions", idempotent, func(ctx context.Context) (*tabletmanagerdatapb.Permissions, error) {
+		return wr.tmc.GetPermissions(ctx, ti.Tablet)
+	})
 }
 
 // ValidatePermissionsShard validates all the permissions are the same
-// in a shard
-func (wr *Wrangler) ValidatePermissionsShard(ctx context.Context, keyspace, shard string) error {
-	si, err := wr.ts.GetShard(ctx, keyspace, shard)
-	if err != nil {
-		return err
-	}
-
-	// get permissions from the primary, or error
-	if !si.HasPrimary() {
-		return fmt.Errorf("no primary in shard %v/%v", keyspace, shard)
-	}
-	log.Infof("Gathering permissions for primary %v", topoproto.TabletAliasString(si.PrimaryAlias))
-	primaryPermissions, err := wr.GetPermissions(ctx, si.PrimaryAlias)
-	if err != nil {
-		return err
-	}
-
-	// read all the aliases in the shard, that is all tablets that are
-	// replicating from the primary
-	aliases, err := wr.ts.FindAllTabletAliasesInShard(ctx, keyspace, shard)
+// in a shard. The DRAINED, BACKUP and RESTORE tablets are skipped, and
+// logged, unless includeNonServing is set.
+func (wr *Wrangler) ValidatePermissionsShard(ctx context.Context, keyspace, shard string, includeNonServing bool) error {
+	resp, err := wr.VtctldServer().ValidatePermissionsShard(wr.vtctldContext(ctx), &vtctldatapb.ValidatePermissionsShardRequest{
+		Keyspace:          keyspace,
+		Shard:             shard,
+		IncludeNonServing: includeNonServing,
+	})
 	if err != nil {
-		return err
-	}
-
-	// then diff all of them, except primary
-	er := concurrency.AllErrorRecorder{}
-	wg := sync.WaitGroup{}
-	for _, alias := range aliases {
-		if topoproto.TabletAliasEqual(alias, si.PrimaryAlias) {
-			continue
-		}
-		wg.Add(1)
-		go wr.diffPermissions(ctx, primaryPermissions, si.PrimaryAlias, alias, &wg, &er)
+		return wrapError(err, shardObject(keyspace, shard))
 	}
-	wg.Wait()
-	if er.HasErrors() {
-		return fmt.Errorf("permissions diffs: %v", er.Error().Error())
+	wr.logSkippedTablets(resp.SkippedTablets)
+	if len(resp.Findings) > 0 {
+		return operationErrorf(vtrpcpb.Code_FAILED_PRECONDITION, shardObject(keyspace, shard), "permissions diffs: %v", strings.Join(findingMessages(resp.Findings), ";"))
 	}
 	return nil
 }
 
 // ValidatePermissionsKeyspace validates all the permissions are the same
-// in a keyspace
-func (wr *Wrangler) ValidatePermissionsKeyspace(ctx context.Context, keyspace string) error {
-	// find all the shards
-	shards, err := wr.ts.GetShardNames(ctx, keyspace)
+// in a keyspace, as those of the primary of referenceShard, or of the first
+// serving shard if it is empty. The shards without a primary are skipped
+// with a warning, and only fail the validation if requireAllShards is set.
+// The shards that serve no traffic are skipped, unless
+// includeNonServingShards is set, and so are the DRAINED, BACKUP and RESTORE
+// tablets, unless includeNonServing is set.
+func (wr *Wrangler) ValidatePermissionsKeyspace(ctx context.Context, keyspace, referenceShard string, requireAllShards, includeNonServing, includeNonServingShards bool) error {
+	resp, err := wr.VtctldServer().ValidatePermissionsKeyspace(wr.vtctldContext(ctx), &vtctldatapb.ValidatePermissionsKeyspaceRequest{
+		Keyspace:                keyspace,
+		ReferenceShard:          referenceShard,
+		IncludeNonServing:       includeNonServing,
+		IncludeNonServingShards: includeNonServingShards,
+		ReportFindings:          true,
+	})
 	if err != nil {
-		return err
+		return wrapError(err, keyspaceObject(keyspace))
 	}
+	wr.logNonServingShards(resp.NonServingShards)
+	wr.logSkippedTablets(resp.SkippedTablets)
 
-	// corner cases
-	if len(shards) == 0 {
-		return fmt.Errorf("no shards in keyspace %v", keyspace)
+	var diffs []*vtctldatapb.ValidationFinding
+	var skippedShards []string
+	for _, finding := range resp.Findings {
+		if finding.Severity == vtctldatapb.ValidationFinding_SKIPPED {
+			if finding.NotServing {
+				continue
+			}
+			wr.Logger().Warningf("%v", finding.Message)
+			skippedShards = append(skippedShards, finding.Shard)
+			continue
+		}
+		diffs = append(diffs, finding)
 	}
-	sort.Strings(shards)
-	if len(shards) == 1 {
-		return wr.ValidatePermissionsShard(ctx, keyspace, shards[0])
+	if !requireAllShards {
+		skippedShards = nil
 	}
 
-	// find the reference permissions using the first shard's primary
-	si, err := wr.ts.GetShard(ctx, keyspace, shards[0])
-	if err != nil {
-		return err
+	var errs []string
+	if len(diffs) > 0 {
+		errs = append(errs, fmt.Sprintf("permissions diffs: %v", strings.Join(findingMessages(diffs), ";")))
 	}
-	if !si.HasPrimary() {
-		return fmt.Errorf("no primary in shard %v/%v", keyspace, shards[0])
-	}
-	referenceAlias := si.PrimaryAlias
-	log.Infof("Gathering permissions for reference primary %v", topoproto.TabletAliasString(referenceAlias))
-	referencePermissions, err := wr.GetPermissions(ctx, si.PrimaryAlias)
-	if err != nil {
-		return err
+	if len(skippedShards) > 0 {
+		errs = append(errs, fmt.Sprintf("skipped shards without a primary: %v", strings.Join(skippedShards, ",")))
 	}
-
-	// then diff with all tablets but primary 0
-	er := concurrency.AllErrorRecorder{}
-	wg := sync.WaitGroup{}
-	for _, shard := range shards {
-		aliases, err := wr.ts.FindAllTabletAliasesInShard(ctx, keyspace, shard)
-		if err != nil {
-			er.RecordError(err)
-			continue
-		}
-
-		for _, alias := range aliases {
-			if topoproto.TabletAliasEqual(alias, si.PrimaryAlias) {
-				continue
-			}
-
-			wg.Add(1)
-			go wr.diffPermissions(ctx, referencePermissions, referenceAlias, alias, &wg, &er)
+	if len(errs) > 0 {
+		return &OperationError{
+			Code:   vtrpcpb.Code_FAILED_PRECONDITION,
+			Object: keyspaceObject(keyspace),
+			Err:    errors.New(strings.Join(errs, "; ")),
 		}
 	}
-	wg.Wait()
-	if er.HasErrors() {
-		return fmt.Errorf("permissions diffs: %v", er.Error().Error())
-	}
 	return nil
 }
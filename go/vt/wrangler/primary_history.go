@@ -0,0 +1,286 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/constants/sidecar"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/workflow"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const (
+	// PrimaryHistoryFromJournal is the source of a primary term history
+	// read from the reparent journal of the primary.
+	PrimaryHistoryFromJournal = "reparent_journal"
+	// PrimaryHistoryFromTopo is the source of a primary term history
+	// reconstructed from the shard and tablet records.
+	PrimaryHistoryFromTopo = "topo"
+)
+
+// PrimaryChange is a change of the primary of a shard.
+type PrimaryChange struct {
+	Time         time.Time
+	PrimaryAlias string
+	// Action is the reparent that made the change, as recorded in the
+	// reparent journal, or the record the change was reconstructed from.
+	Action string
+	// SincePrevious is how long after the previous change this one
+	// happened, zero for the first change.
+	SincePrevious time.Duration `json:",omitempty"`
+}
+
+// PrimaryTermHistory is the chronological list of the primary changes of a
+// shard.
+type PrimaryTermHistory struct {
+	Keyspace     string
+	Shard        string
+	PrimaryAlias string `json:",omitempty"`
+	// Source is where the changes come from. The topo only knows the start
+	// of the current term of each tablet that is still recorded as a
+	// primary, so the history it gives is partial.
+	Source string `json:",omitempty"`
+	// JournalError is why the reparent journal couldn't be read, when the
+	// changes come from the topo.
+	JournalError string `json:",omitempty"`
+	Changes      []*PrimaryChange
+	// CurrentTerm is how long ago the last change happened.
+	CurrentTerm time.Duration `json:",omitempty"`
+	// RecentChanges is the number of changes in the window, in a keyspace
+	// report.
+	RecentChanges int `json:",omitempty"`
+	// Error is why the history of the shard couldn't be read, in a keyspace
+	// report.
+	Error string `json:",omitempty"`
+}
+
+// KeyspacePrimaryTermReport is the result of GetKeyspacePrimaryTermHistory.
+type KeyspacePrimaryTermReport struct {
+	Keyspace   string
+	Window     time.Duration
+	MaxChanges int
+	Shards     []*PrimaryTermHistory
+	// UnstableShards are the shards whose primary changed more than
+	// MaxChanges times in the window.
+	UnstableShards   []string `json:",omitempty"`
+	ShardsWithErrors []string `json:",omitempty"`
+}
+
+// GetPrimaryTermHistory lists the primary changes of the shard in
+// chronological order, from the reparent journal of its primary. If the
+// journal can't be read or is empty, the changes are reconstructed from the
+// term start times of the shard record and of the tablet records of the
+// primaries.
+func (wr *Wrangler) GetPrimaryTermHistory(ctx context.Context, keyspace, shard string) (*PrimaryTermHistory, error) {
+	return wr.primaryTermHistory(ctx, keyspace, shard, time.Now())
+}
+
+// primaryTermHistory is the implementation of GetPrimaryTermHistory, with
+// the current terms computed at now.
+func (wr *Wrangler) primaryTermHistory(ctx context.Context, keyspace, shard string, now time.Time) (*PrimaryTermHistory, error) {
+	si, err := wr.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	history := &PrimaryTermHistory{
+		Keyspace: keyspace,
+		Shard:    shard,
+	}
+	if si.HasPrimary() {
+		history.PrimaryAlias = topoproto.TabletAliasString(si.PrimaryAlias)
+		primary, err := wr.ts.GetTablet(ctx, si.PrimaryAlias)
+		if err != nil {
+			history.JournalError = fmt.Sprintf("cannot read the primary tablet record: %v", err)
+		} else if history.Changes, err = wr.readReparentJournal(ctx, primary.Tablet); err != nil {
+			history.JournalError = fmt.Sprintf("cannot read the reparent journal of primary %v: %v", history.PrimaryAlias, err)
+		}
+	} else {
+		history.JournalError = "no primary in the shard to read the reparent journal from"
+	}
+
+	if len(history.Changes) > 0 {
+		history.Source = PrimaryHistoryFromJournal
+	} else {
+		history.Source = PrimaryHistoryFromTopo
+		history.Changes, err = wr.topoPrimaryChanges(ctx, si)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i, change := range history.Changes {
+		if i > 0 {
+			change.SincePrevious = change.Time.Sub(history.Changes[i-1].Time)
+		}
+	}
+	if len(history.Changes) > 0 {
+		history.CurrentTerm = now.Sub(history.Changes[len(history.Changes)-1].Time)
+	}
+	return history, nil
+}
+
+// readReparentJournal reads the primary changes from the reparent journal
+// of the tablet. A tablet without the journal table has no changes.
+func (wr *Wrangler) readReparentJournal(ctx context.Context, tablet *topodatapb.Tablet) ([]*PrimaryChange, error) {
+	query := sqlparser.BuildParsedQuery("select time_created_ns, action_name, primary_alias from %s.reparent_journal order by time_created_ns",
+		sidecar.GetIdentifier()).Query
+	qr, err := callTablet(ctx, "ExecuteFetchAsDba", idempotent, func(ctx context.Context) (*querypb.QueryResult, error) {
+		return wr.tmc.ExecuteFetchAsDba(ctx, tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:   []byte(query),
+			MaxRows: maxJournalRows,
+		})
+	})
+	if err != nil {
+		if workflow.IsTableDidNotExistError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var changes []*PrimaryChange
+	for _, row := range sqltypes.Proto3ToResult(qr).Named().Rows {
+		timeCreatedNS, err := row.ToInt64("time_created_ns")
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, &PrimaryChange{
+			Time:         time.Unix(0, timeCreatedNS).UTC(),
+			PrimaryAlias: row.AsString("primary_alias", ""),
+			Action:       row.AsString("action_name", ""),
+		})
+	}
+	return changes, nil
+}
+
+// topoPrimaryChanges reconstructs the primary changes of the shard from the
+// term start time of its primary, and of the tablets of the shard that are
+// still recorded as primaries, such as the primaries that were demoted while
+// they were down.
+func (wr *Wrangler) topoPrimaryChanges(ctx context.Context, si *topo.ShardInfo) ([]*PrimaryChange, error) {
+	var changes []*PrimaryChange
+	if si.HasPrimary() && si.PrimaryTermStartTime != nil {
+		changes = append(changes, &PrimaryChange{
+			Time:         si.GetPrimaryTermStartTime(),
+			PrimaryAlias: topoproto.TabletAliasString(si.PrimaryAlias),
+			Action:       "shard record",
+		})
+	}
+	tabletMap, err := wr.getTabletMapForShard(ctx, si.Keyspace(), si.ShardName(), nil, warnOnUnreadableTablets)
+	if err != nil {
+		return nil, err
+	}
+	for _, ti := range tabletMap {
+		if ti.Type != topodatapb.TabletType_PRIMARY || ti.PrimaryTermStartTime == nil {
+			continue
+		}
+		// The term of the shard primary is the one of the shard record.
+		if topoproto.TabletAliasEqual(ti.Alias, si.PrimaryAlias) && si.PrimaryTermStartTime != nil {
+			continue
+		}
+		changes = append(changes, &PrimaryChange{
+			Time:         ti.GetPrimaryTermStartTime(),
+			PrimaryAlias: ti.AliasString(),
+			Action:       "tablet record",
+		})
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Time.Before(changes[j].Time) })
+	return changes, nil
+}
+
+// GetKeyspacePrimaryTermHistory runs GetPrimaryTermHistory on all the
+// shards of the keyspace, and flags the shards whose primary changed more
+// than maxChanges times in the last window. A shard whose history can't be
+// read is reported, not returned as an error.
+func (wr *Wrangler) GetKeyspacePrimaryTermHistory(ctx context.Context, keyspace string, window time.Duration, maxChanges int) (*KeyspacePrimaryTermReport, error) {
+	return wr.keyspacePrimaryTermHistory(ctx, keyspace, window, maxChanges, time.Now())
+}
+
+// keyspacePrimaryTermHistory is the implementation of
+// GetKeyspacePrimaryTermHistory, with the window ending at now.
+func (wr *Wrangler) keyspacePrimaryTermHistory(ctx context.Context, keyspace string, window time.Duration, maxChanges int, now time.Time) (*KeyspacePrimaryTermReport, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("the window must be positive, got %v", window)
+	}
+	if maxChanges < 0 {
+		return nil, fmt.Errorf("the maximum number of changes must not be negative, got %d", maxChanges)
+	}
+	var (
+		mu        sync.Mutex
+		histories = make(map[string]*PrimaryTermHistory)
+	)
+	// The history of a shard doesn't depend on its current primary, so the
+	// shards without one are read too.
+	results, err := wr.ForEachShardPrimary(ctx, keyspace, ShardPrimaryOptions{RunWithoutPrimary: true}, func(ctx context.Context, shard string, primary *topo.TabletInfo) error {
+		history, err := wr.primaryTermHistory(ctx, keyspace, shard, now)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		histories[shard] = history
+		return nil
+	})
+	if results == nil {
+		return nil, err
+	}
+
+	report := &KeyspacePrimaryTermReport{
+		Keyspace:   keyspace,
+		Window:     window,
+		MaxChanges: maxChanges,
+		Shards:     make([]*PrimaryTermHistory, len(results)),
+	}
+	for i, result := range results {
+		history := histories[result.Shard]
+		if result.Error != "" {
+			history = &PrimaryTermHistory{
+				Keyspace: keyspace,
+				Shard:    result.Shard,
+				Error:    result.Error,
+			}
+		}
+		report.Shards[i] = history
+	}
+
+	since := now.Add(-window)
+	for _, history := range report.Shards {
+		if history.Error != "" {
+			report.ShardsWithErrors = append(report.ShardsWithErrors, history.Shard)
+			continue
+		}
+		for _, change := range history.Changes {
+			if change.Time.After(since) {
+				history.RecentChanges++
+			}
+		}
+		if history.RecentChanges > maxChanges {
+			report.UnstableShards = append(report.UnstableShards, history.Shard)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// reparentJournalTMClient answers the reparent journal queries with the
+// rows of each tablet, and fails as if the journal table didn't exist for
+// the tablets without any.
+type reparentJournalTMClient struct {
+	tmclient.TabletManagerClient
+
+	rows map[uint32][]string
+}
+
+func (tmc *reparentJournalTMClient) ExecuteFetchAsDba(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsDbaRequest) (*querypb.QueryResult, error) {
+	rows, ok := tmc.rows[tablet.Alias.Uid]
+	if !ok {
+		return nil, sqlerror.NewSQLError(sqlerror.ERNoSuchTable, sqlerror.SSUnknownSQLState, "Table '_vt.reparent_journal' doesn't exist")
+	}
+	return sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("time_created_ns|action_name|primary_alias", "int64|varbinary|varbinary"), rows...)), nil
+}
+
+func TestPrimaryTermHistory(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	journalRow := func(ago time.Duration, action, alias string) string {
+		return fmt.Sprintf("%d|%s|%s", now.Add(-ago).UnixNano(), action, alias)
+	}
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+	tmc := &reparentJournalTMClient{rows: map[uint32][]string{
+		100: {
+			journalRow(72*time.Hour, "InitShardPrimary", "cell1-0000000100"),
+			journalRow(10*time.Hour, "EmergencyReparentShard", "cell1-0000000101"),
+			journalRow(4*time.Hour, "PlannedReparentShard", "cell1-0000000100"),
+			journalRow(time.Hour, "EmergencyReparentShard", "cell1-0000000101"),
+			journalRow(30*time.Minute, "PlannedReparentShard", "cell1-0000000100"),
+		},
+	}}
+	wr := New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, tmc)
+
+	// The primary of -80 has a reparent journal, the one of 80- doesn't, and
+	// 80- has a primary that was demoted while it was down.
+	for uid, shard := range map[uint32]string{100: "-80", 101: "-80", 200: "80-", 201: "80-"} {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    shard,
+			Type:     topodatapb.TabletType_REPLICA,
+		}
+		if uid%100 == 0 || uid == 201 {
+			tablet.Type = topodatapb.TabletType_PRIMARY
+		}
+		err := ts.InitTablet(ctx, tablet, true /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+		if tablet.Type == topodatapb.TabletType_PRIMARY {
+			_, err = ts.UpdateTabletFields(ctx, tablet.Alias, func(tablet *topodatapb.Tablet) error {
+				tablet.PrimaryTermStartTime = protoutil.TimeToProto(now.Add(-time.Duration(300-uid) * time.Hour))
+				return nil
+			})
+			require.NoError(t, err)
+		}
+	}
+	for shard, uid := range map[string]uint32{"-80": 100, "80-": 200} {
+		_, err := ts.UpdateShardFields(ctx, "ks", shard, func(si *topo.ShardInfo) error {
+			si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "cell1", Uid: uid}
+			si.SetPrimaryTermStartTime(now.Add(-time.Duration(300-uid) * time.Hour))
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	history, err := wr.primaryTermHistory(ctx, "ks", "-80", now)
+	require.NoError(t, err)
+	require.Equal(t, PrimaryHistoryFromJournal, history.Source)
+	require.Empty(t, history.JournalError)
+	require.Len(t, history.Changes, 5)
+	require.Equal(t, "InitShardPrimary", history.Changes[0].Action)
+	require.Equal(t, now.Add(-72*time.Hour), history.Changes[0].Time)
+	require.Zero(t, history.Changes[0].SincePrevious)
+	require.Equal(t, "cell1-0000000101", history.Changes[1].PrimaryAlias)
+	require.Equal(t, 62*time.Hour, history.Changes[1].SincePrevious)
+	require.Equal(t, 30*time.Minute, history.Changes[4].SincePrevious)
+	require.Equal(t, 30*time.Minute, history.CurrentTerm)
+
+	// Without a journal, the changes come from the shard record and the
+	// tablet records that are still primaries.
+	history, err = wr.primaryTermHistory(ctx, "ks", "80-", now)
+	require.NoError(t, err)
+	require.Equal(t, PrimaryHistoryFromTopo, history.Source)
+	require.Equal(t, "cell1-0000000200", history.PrimaryAlias)
+	require.Len(t, history.Changes, 2)
+	require.Equal(t, "cell1-0000000200", history.Changes[0].PrimaryAlias)
+	require.Equal(t, "shard record", history.Changes[0].Action)
+	require.Equal(t, "cell1-0000000201", history.Changes[1].PrimaryAlias)
+	require.Equal(t, "tablet record", history.Changes[1].Action)
+	require.Equal(t, time.Hour, history.Changes[1].SincePrevious)
+	require.Equal(t, 99*time.Hour, history.CurrentTerm)
+
+	report, err := wr.keyspacePrimaryTermHistory(ctx, "ks", 12*time.Hour, 3, now)
+	require.NoError(t, err)
+	require.Len(t, report.Shards, 2)
+	require.Equal(t, 4, report.Shards[0].RecentChanges)
+	require.Zero(t, report.Shards[1].RecentChanges)
+	require.Equal(t, []string{"-80"}, report.UnstableShards)
+	require.Empty(t, report.ShardsWithErrors)
+
+	report, err = wr.keyspacePrimaryTermHistory(ctx, "ks", 12*time.Hour, 4, now)
+	require.NoError(t, err)
+	require.Empty(t, report.UnstableShards)
+
+	_, err = wr.keyspacePrimaryTermHistory(ctx, "ks", 0, 3, now)
+	require.ErrorContains(t, err, "the window must be positive")
+}
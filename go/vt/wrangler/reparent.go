@@ -76,6 +76,8 @@ func (wr *Wrangler) InitShardPrimary(ctx context.Context, keyspace, shard string
 
 // PlannedReparentShard will make the provided tablet the primary for the shard,
 // when both the current and new primary are reachable and in good shape.
+// Its errors are OperationErrors about the shard, e.g. FAILED_PRECONDITION
+// if the primary-elect cannot be promoted.
 func (wr *Wrangler) PlannedReparentShard(
 	ctx context.Context,
 	keyspace, shard string,
@@ -88,11 +90,12 @@ func (wr *Wrangler) PlannedReparentShard(
 		opts,
 	)
 
-	return err
+	return wrapError(err, shardObject(keyspace, shard))
 }
 
 // EmergencyReparentShard will make the provided tablet the primary for
-// the shard, when the old primary is completely unreachable.
+// the shard, when the old primary is completely unreachable. Its errors are
+// OperationErrors about the shard.
 func (wr *Wrangler) EmergencyReparentShard(ctx context.Context, keyspace, shard string, opts reparentutil.EmergencyReparentOptions) (err error) {
 	_, err = reparentutil.NewEmergencyReparenter(wr.ts, wr.tmc, wr.logger).ReparentShard(
 		ctx,
@@ -101,7 +104,7 @@ func (wr *Wrangler) EmergencyReparentShard(ctx context.Context, keyspace, shard
 		opts,
 	)
 
-	return err
+	return wrapError(err, shardObject(keyspace, shard))
 }
 
 // TabletExternallyReparented changes the type of new primary for this shard to PRIMARY
@@ -153,7 +156,9 @@ func (wr *Wrangler) TabletExternallyReparented(ctx context.Context, newPrimaryAl
 		}()
 		event.DispatchUpdate(ev, "starting external reparent")
 
-		if err := wr.tmc.ChangeType(ctx, tablet, topodatapb.TabletType_PRIMARY, policy.SemiSyncAckers(durability, tablet) > 0); err != nil {
+		if _, err := callTablet(ctx, "ChangeType", notIdempotent, func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, wr.tmc.ChangeType(ctx, tablet, topodatapb.TabletType_PRIMARY, policy.SemiSyncAckers(durability, tablet) > 0)
+		}); err != nil {
 			log.Warningf("Error calling ChangeType on new primary %v: %v", topoproto.TabletAliasString(newPrimaryAlias), err)
 			return err
 		}
@@ -0,0 +1,196 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// defaultReplicaPositionTabletTypes are the tablets that
+// WaitForShardReplicasPosition waits for if no tablet types are given.
+var defaultReplicaPositionTabletTypes = []topodatapb.TabletType{
+	topodatapb.TabletType_REPLICA,
+	topodatapb.TabletType_RDONLY,
+}
+
+// ReplicaPositionFilter selects the tablets of a shard that
+// WaitForShardReplicasPosition waits for.
+type ReplicaPositionFilter struct {
+	// TabletTypes are the types of the tablets to wait for. If empty, the
+	// replica and rdonly tablets are waited for.
+	TabletTypes []topodatapb.TabletType
+	// Tags are the tags the tablets must all have to be waited for.
+	Tags map[string]string
+}
+
+// TabletPositionWait is the result of waiting for one tablet.
+type TabletPositionWait struct {
+	TabletAlias string
+	Type        string
+	Reached     bool
+	// Elapsed is how long the tablet took to reach the position, or how
+	// long it was waited for before the error.
+	Elapsed string
+	Error   string `json:",omitempty"`
+}
+
+// SkippedTabletPosition is a tablet WaitForShardReplicasPosition didn't wait
+// for, and why.
+type SkippedTabletPosition struct {
+	TabletAlias string
+	Type        string
+	Reason      string
+}
+
+// ShardReplicasPositionReport is the result of WaitForShardReplicasPosition.
+type ShardReplicasPositionReport struct {
+	Keyspace string
+	Shard    string
+	Position string
+	Timeout  string
+	Tablets  []*TabletPositionWait
+	Skipped  []*SkippedTabletPosition `json:",omitempty"`
+}
+
+// AllReached returns true if every tablet that was waited for reached the
+// position.
+func (r *ShardReplicasPositionReport) AllReached() bool {
+	for _, tablet := range r.Tablets {
+		if !tablet.Reached {
+			return false
+		}
+	}
+	return true
+}
+
+// WaitForShardReplicasPosition waits, for at most timeout, until the tablets
+// of the shard selected by filter have applied position, or the current
+// position of the shard primary if it is empty. The tablets are waited for
+// concurrently, and the others, including the primary, are reported as
+// skipped. A tablet failing to reach the position is reported, not
+// returned as an error.
+func (wr *Wrangler) WaitForShardReplicasPosition(ctx context.Context, keyspace, shard, position string, timeout time.Duration, filter ReplicaPositionFilter) (*ShardReplicasPositionReport, error) {
+	si, err := wr.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	tabletMap, err := wr.getTabletMapForShard(ctx, keyspace, shard, nil, warnOnUnreadableTablets)
+	if err != nil {
+		return nil, err
+	}
+
+	if position == "" {
+		if !si.HasPrimary() {
+			return nil, fmt.Errorf("no primary in shard %v/%v to read the position from", keyspace, shard)
+		}
+		primary, ok := tabletMap[topoproto.TabletAliasString(si.PrimaryAlias)]
+		if !ok {
+			return nil, fmt.Errorf("primary %v of shard %v/%v is not in the shard's replication graph", topoproto.TabletAliasString(si.PrimaryAlias), keyspace, shard)
+		}
+		position, err = callTablet(ctx, "PrimaryPosition", idempotent, func(ctx context.Context) (string, error) {
+			return wr.tmc.PrimaryPosition(ctx, primary.Tablet)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the position of primary %v: %v", primary.AliasString(), err)
+		}
+	}
+
+	tabletTypes := filter.TabletTypes
+	if len(tabletTypes) == 0 {
+		tabletTypes = defaultReplicaPositionTabletTypes
+	}
+	report := &ShardReplicasPositionReport{
+		Keyspace: keyspace,
+		Shard:    shard,
+		Position: position,
+		Timeout:  timeout.String(),
+	}
+	var waited []*topo.TabletInfo
+	for _, ti := range tabletMap {
+		var reason string
+		switch {
+		case topoproto.TabletAliasEqual(ti.Alias, si.PrimaryAlias):
+			reason = "shard primary"
+		case !slices.Contains(tabletTypes, ti.Type):
+			reason = "tablet type"
+		case !tabletHasTags(ti.Tablet, filter.Tags):
+			reason = "tablet tags"
+		default:
+			waited = append(waited, ti)
+			continue
+		}
+		report.Skipped = append(report.Skipped, &SkippedTabletPosition{
+			TabletAlias: ti.AliasString(),
+			Type:        topoproto.TabletTypeLString(ti.Type),
+			Reason:      reason,
+		})
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, ti := range waited {
+		wg.Add(1)
+		go func(tablet *topodatapb.Tablet) {
+			defer wg.Done()
+			start := time.Now()
+			_, err := callTablet(waitCtx, "WaitForPosition", idempotent, func(ctx context.Context) (struct{}, error) {
+				return struct{}{}, wr.tmc.WaitForPosition(ctx, tablet, position)
+			})
+			wait := &TabletPositionWait{
+				TabletAlias: topoproto.TabletAliasString(tablet.Alias),
+				Type:        topoproto.TabletTypeLString(tablet.Type),
+				Reached:     err == nil,
+				Elapsed:     time.Since(start).Round(time.Millisecond).String(),
+			}
+			if err != nil {
+				wait.Error = err.Error()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			report.Tablets = append(report.Tablets, wait)
+		}(ti.Tablet)
+	}
+	wg.Wait()
+
+	sort.Slice(report.Tablets, func(i, j int) bool { return report.Tablets[i].TabletAlias < report.Tablets[j].TabletAlias })
+	sort.Slice(report.Skipped, func(i, j int) bool { return report.Skipped[i].TabletAlias < report.Skipped[j].TabletAlias })
+	return report, nil
+}
+
+// tabletHasTags returns true if the tablet has all of the tags.
+func tabletHasTags(tablet *topodatapb.Tablet, tags map[string]string) bool {
+	for key, value := range tags {
+		if tabletValue, ok := tablet.Tags[key]; !ok || tabletValue != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// positionTMClient returns the primary position, and makes the tablets
+// that are not caught up wait until the context is done.
+type positionTMClient struct {
+	tmclient.TabletManagerClient
+
+	mu        sync.Mutex
+	position  string
+	caughtUp  map[uint32]bool
+	positions map[uint32]string
+}
+
+func (tmc *positionTMClient) PrimaryPosition(ctx context.Context, tablet *topodatapb.Tablet) (string, error) {
+	return tmc.position, nil
+}
+
+func (tmc *positionTMClient) WaitForPosition(ctx context.Context, tablet *topodatapb.Tablet, pos string) error {
+	tmc.mu.Lock()
+	tmc.positions[tablet.Alias.Uid] = pos
+	caughtUp := tmc.caughtUp[tablet.Alias.Uid]
+	tmc.mu.Unlock()
+	if caughtUp {
+		return nil
+	}
+	<-ctx.Done()
+	return fmt.Errorf("tablet %v did not reach %v: %v", tablet.Alias.Uid, pos, ctx.Err())
+}
+
+func TestWaitForShardReplicasPosition(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	tmc := &positionTMClient{
+		position:  "MySQL56/00000000-0000-0000-0000-000000000001:1-10",
+		caughtUp:  map[uint32]bool{101: true, 102: true},
+		positions: make(map[uint32]string),
+	}
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmc)
+
+	addTablet := func(uid uint32, tabletType topodatapb.TabletType, tags map[string]string) {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    "0",
+			Type:     tabletType,
+			Tags:     tags,
+		}
+		err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+	}
+	addTablet(100, topodatapb.TabletType_PRIMARY, nil)
+	addTablet(101, topodatapb.TabletType_REPLICA, map[string]string{"pool": "a"})
+	addTablet(102, topodatapb.TabletType_RDONLY, map[string]string{"pool": "a"})
+	addTablet(103, topodatapb.TabletType_REPLICA, map[string]string{"pool": "b"})
+	addTablet(104, topodatapb.TabletType_BACKUP, nil)
+	_, err := ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}
+		return nil
+	})
+	require.NoError(t, err)
+
+	// By default, the replica and rdonly tablets wait for the position of
+	// the primary, and 103 never reaches it.
+	report, err := wr.WaitForShardReplicasPosition(ctx, "ks", "0", "", 100*time.Millisecond, ReplicaPositionFilter{})
+	require.NoError(t, err)
+	require.Equal(t, tmc.position, report.Position)
+	require.False(t, report.AllReached())
+	require.Len(t, report.Tablets, 3)
+	require.True(t, report.Tablets[0].Reached)
+	require.True(t, report.Tablets[1].Reached)
+	require.False(t, report.Tablets[2].Reached)
+	require.Equal(t, "cell1-0000000103", report.Tablets[2].TabletAlias)
+	require.Contains(t, report.Tablets[2].Error, "context deadline exceeded")
+	require.Equal(t, []*SkippedTabletPosition{
+		{TabletAlias: "cell1-0000000100", Type: "primary", Reason: "shard primary"},
+		{TabletAlias: "cell1-0000000104", Type: "backup", Reason: "tablet type"},
+	}, report.Skipped)
+	require.Equal(t, map[uint32]string{101: tmc.position, 102: tmc.position, 103: tmc.position}, tmc.positions)
+
+	// Filtering by type and tags skips 103 and 102, and the given position
+	// is waited for.
+	report, err = wr.WaitForShardReplicasPosition(ctx, "ks", "0", "MySQL56/00000000-0000-0000-0000-000000000001:1-5", time.Second, ReplicaPositionFilter{
+		TabletTypes: []topodatapb.TabletType{topodatapb.TabletType_REPLICA},
+		Tags:        map[string]string{"pool": "a"},
+	})
+	require.NoError(t, err)
+	require.True(t, report.AllReached())
+	require.Len(t, report.Tablets, 1)
+	require.Equal(t, "cell1-0000000101", report.Tablets[0].TabletAlias)
+	require.Equal(t, "MySQL56/00000000-0000-0000-0000-000000000001:1-5", tmc.positions[101])
+	require.Equal(t, []*SkippedTabletPosition{
+		{TabletAlias: "cell1-0000000100", Type: "primary", Reason: "shard primary"},
+		{TabletAlias: "cell1-0000000102", Type: "rdonly", Reason: "tablet type"},
+		{TabletAlias: "cell1-0000000103", Type: "replica", Reason: "tablet tags"},
+		{TabletAlias: "cell1-0000000104", Type: "backup", Reason: "tablet type"},
+	}, report.Skipped)
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// restoreDrillMaxRows bounds the number of rows a validation query of a
+	// restore drill may return.
+	restoreDrillMaxRows = 10000
+)
+
+// restoreDrillPollInterval is how often a restore drill checks if the
+// tablet caught up.
+var restoreDrillPollInterval = time.Second
+
+// RestoreDrillOptions are the options of RestoreDrill.
+type RestoreDrillOptions struct {
+	// BackupName is the full backup to restore, the most recent one if it
+	// is empty.
+	BackupName string
+	// MaxReplicationLag is the replication lag the tablet must get down to
+	// after the restore, within CatchUpTimeout.
+	MaxReplicationLag time.Duration
+	CatchUpTimeout    time.Duration
+	// ValidationQueries are run on the database of the tablet as the dba
+	// user once it caught up. A query that fails or returns no rows fails
+	// the drill.
+	ValidationQueries []string
+}
+
+// RestoreDrillQuery is the outcome of a validation query of a restore
+// drill.
+type RestoreDrillQuery struct {
+	Query    string
+	Rows     int
+	Duration time.Duration
+	Error    string `json:",omitempty"`
+}
+
+// RestoreDrillReport is the outcome of RestoreDrill.
+type RestoreDrillReport struct {
+	Tablet string
+	// OriginalType is the type of the tablet before the drill, which it is
+	// returned to.
+	OriginalType string
+	BackupName   string
+	Passed       bool
+	// Failure is why the drill didn't pass.
+	Failure string `json:",omitempty"`
+	// RestoreDuration is how long the restore took, and CatchUpDuration how
+	// long the tablet then took to get within the maximum replication lag.
+	RestoreDuration time.Duration
+	CatchUpDuration time.Duration `json:",omitempty"`
+	// ReplicationLag is the last replication lag seen while catching up.
+	ReplicationLag time.Duration `json:",omitempty"`
+	Queries        []*RestoreDrillQuery
+	// RevertError is why the tablet couldn't be returned to its state from
+	// before the drill.
+	RevertError   string `json:",omitempty"`
+	TotalDuration time.Duration
+}
+
+// RestoreDrill tests the backups of the shard of a spare tablet: it restores
+// the most recent full backup, or the one of opts, on the tablet, waits for
+// its replication to catch up, and runs the validation queries on it. The
+// tablet is then returned to its type from before the drill, with its
+// replication stopped if it was. The drill refuses to run on a tablet that
+// is not SPARE or DRAINED, or that is the primary of its shard. A drill that
+// ran but failed is reported, not returned as an error.
+func (wr *Wrangler) RestoreDrill(ctx context.Context, tabletAlias *topodatapb.TabletAlias, opts RestoreDrillOptions) (*RestoreDrillReport, error) {
+	if opts.CatchUpTimeout <= 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the catch up timeout must be positive, got %v", opts.CatchUpTimeout)
+	}
+	ti, err := wr.ts.GetTablet(ctx, tabletAlias)
+	if err != nil {
+		return nil, err
+	}
+	alias := topoproto.TabletAliasString(tabletAlias)
+	si, err := wr.ts.GetShard(ctx, ti.Keyspace, ti.Shard)
+	if err != nil {
+		return nil, err
+	}
+	if ti.Type == topodatapb.TabletType_PRIMARY || topoproto.TabletAliasEqual(si.PrimaryAlias, tabletAlias) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "tablet %v is the primary of shard %v/%v, a restore drill cannot run on it", alias, ti.Keyspace, ti.Shard)
+	}
+	if ti.Type != topodatapb.TabletType_SPARE && ti.Type != topodatapb.TabletType_DRAINED {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "tablet %v is %v, a restore drill only runs on a SPARE or DRAINED tablet", alias, topoproto.TabletTypeLString(ti.Type))
+	}
+	backupName, _, err := wr.FindBackupToRestore(ctx, tabletAlias, opts.BackupName, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	// The tablet may not replicate at all, in which case it is left so.
+	status, err := callTablet(ctx, "ReplicationStatus", idempotent, func(ctx context.Context) (*replicationdatapb.Status, error) {
+		return wr.tmc.ReplicationStatus(ctx, ti.Tablet)
+	})
+	wasReplicating := err == nil && replicationRunning(status)
+
+	report := &RestoreDrillReport{
+		Tablet:       alias,
+		OriginalType: topoproto.TabletTypeLString(ti.Type),
+		BackupName:   backupName,
+	}
+	start := wr.now()
+	defer func() {
+		ctx, cancel := wr.cleanupContext(ctx)
+		defer cancel()
+		if err := wr.revertRestoreDrill(ctx, tabletAlias, ti.Type, wasReplicating); err != nil {
+			report.RevertError = err.Error()
+			wr.Logger().Errorf("cannot return %v to its state from before the restore drill: %v", alias, err)
+		}
+		report.TotalDuration = wr.now().Sub(start)
+	}()
+
+	wr.Logger().Infof("restore drill: restoring backup %v on %v", backupName, alias)
+	if err := wr.restoreDrillBackup(ctx, ti.Tablet, backupName); err != nil {
+		report.Failure = fmt.Sprintf("restore failed: %v", err)
+		report.RestoreDuration = wr.now().Sub(start)
+		return report, nil
+	}
+	report.RestoreDuration = wr.now().Sub(start)
+
+	caughtUp := wr.now()
+	report.ReplicationLag, err = wr.waitForRestoreDrillCatchUp(ctx, ti.Tablet, opts)
+	report.CatchUpDuration = wr.now().Sub(caughtUp)
+	if err != nil {
+		report.Failure = err.Error()
+		return report, nil
+	}
+
+	for _, query := range opts.ValidationQueries {
+		result := wr.runRestoreDrillQuery(ctx, ti.Tablet, query)
+		report.Queries = append(report.Queries, result)
+		if result.Error != "" && report.Failure == "" {
+			report.Failure = fmt.Sprintf("validation query %q failed: %v", query, result.Error)
+		}
+	}
+	report.Passed = report.Failure == ""
+	return report, nil
+}
+
+// restoreDrillBackup restores the backup on the tablet, logging the events
+// of the restore.
+func (wr *Wrangler) restoreDrillBackup(ctx context.Context, tablet *topodatapb.Tablet, backupName string) error {
+	stream, err := wr.tmc.RestoreFromBackup(ctx, tablet, &tabletmanagerdatapb.RestoreFromBackupRequest{
+		BackupName: backupName,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		logutil.LogEvent(wr.Logger(), event)
+	}
+}
+
+// waitForRestoreDrillCatchUp waits until the replication of the tablet is
+// running with a known lag of at most the maximum of opts, and returns the
+// last lag seen.
+func (wr *Wrangler) waitForRestoreDrillCatchUp(ctx context.Context, tablet *topodatapb.Tablet, opts RestoreDrillOptions) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.CatchUpTimeout)
+	defer cancel()
+	var (
+		lag     time.Duration
+		lastErr error
+	)
+	for {
+		status, err := callTablet(ctx, "ReplicationStatus", idempotent, func(ctx context.Context) (*replicationdatapb.Status, error) {
+			return wr.tmc.ReplicationStatus(ctx, tablet)
+		})
+		switch {
+		case err != nil:
+			lastErr = fmt.Errorf("cannot get the replication status: %v", err)
+		case !replicationRunning(status):
+			lastErr = fmt.Errorf("replication is not running")
+		case status.ReplicationLagUnknown:
+			lastErr = fmt.Errorf("replication lag is unknown")
+		default:
+			lag = time.Duration(status.ReplicationLagSeconds) * time.Second
+			if lag <= opts.MaxReplicationLag {
+				return lag, nil
+			}
+			lastErr = fmt.Errorf("replication lag is %v", lag)
+		}
+
+		select {
+		case <-ctx.Done():
+			return lag, fmt.Errorf("the tablet did not catch up to a replication lag of %v within %v: %v", opts.MaxReplicationLag, opts.CatchUpTimeout, lastErr)
+		case <-time.After(restoreDrillPollInterval):
+		}
+	}
+}
+
+// runRestoreDrillQuery runs a validation query on the database of the
+// tablet.
+func (wr *Wrangler) runRestoreDrillQuery(ctx context.Context, tablet *topodatapb.Tablet, query string) *RestoreDrillQuery {
+	result := &RestoreDrillQuery{Query: query}
+	start := wr.now()
+	qr, err := callTablet(ctx, "ExecuteFetchAsDba", notIdempotent, func(ctx context.Context) (*querypb.QueryResult, error) {
+		return wr.tmc.ExecuteFetchAsDba(ctx, tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:   []byte(query),
+			DbName:  topoproto.TabletDbName(tablet),
+			MaxRows: restoreDrillMaxRows,
+		})
+	})
+	result.Duration = wr.now().Sub(start)
+	switch {
+	case err != nil:
+		result.Error = err.Error()
+	case len(qr.GetRows()) == 0:
+		result.Error = "no rows returned"
+	default:
+		result.Rows = len(qr.Rows)
+	}
+	return result
+}
+
+// revertRestoreDrill returns the tablet to its type from before the drill,
+// and stops its replication if it wasn't replicating before.
+func (wr *Wrangler) revertRestoreDrill(ctx context.Context, tabletAlias *topodatapb.TabletAlias, originalType topodatapb.TabletType, wasReplicating bool) error {
+	ti, err := wr.ts.GetTablet(ctx, tabletAlias)
+	if err != nil {
+		return err
+	}
+	if ti.Type != originalType {
+		wr.Logger().Infof("restore drill: changing %v back from %v to %v", ti.AliasString(), topoproto.TabletTypeLString(ti.Type), topoproto.TabletTypeLString(originalType))
+		// A tablet left RESTORE by a failed restore can't go back through
+		// ChangeTabletType, and a SPARE or DRAINED tablet never acks
+		// semi-sync.
+		if _, err := callTablet(ctx, "ChangeType", notIdempotent, func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, wr.tmc.ChangeType(ctx, ti.Tablet, originalType, false /*semiSync*/)
+		}); err != nil {
+			return fmt.Errorf("cannot change the type back to %v: %v", topoproto.TabletTypeLString(originalType), err)
+		}
+	}
+	if !wasReplicating {
+		if _, err := callTablet(ctx, "StopReplication", idempotent, func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, wr.tmc.StopReplication(ctx, ti.Tablet)
+		}); err != nil {
+			return fmt.Errorf("cannot stop replication: %v", err)
+		}
+	}
+	return nil
+}
+
+// replicationRunning returns whether both replication threads of a status
+// are running.
+func replicationRunning(status *replicationdatapb.Status) bool {
+	return status.IoState == int32(replication.ReplicationStateRunning) && status.SqlState == int32(replication.ReplicationStateRunning)
+}
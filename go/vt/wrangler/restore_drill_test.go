@@ -0,0 +1,219 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// restoreDrillTMClient restores tablets and answers the validation queries
+// with the number of rows of each. The replication statuses are returned
+// in order, the last one repeatedly.
+type restoreDrillTMClient struct {
+	tmclient.TabletManagerClient
+
+	ts *topo.Server
+	// restoreErr fails the restores, which then leave the tablet RESTORE.
+	restoreErr error
+	rows       map[string]int
+
+	mu          sync.Mutex
+	statuses    []*replicationdatapb.Status
+	restored    []string
+	typeChanges []topodatapb.TabletType
+	stops       int
+}
+
+func (tmc *restoreDrillTMClient) ReplicationStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.Status, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	status := tmc.statuses[0]
+	if len(tmc.statuses) > 1 {
+		tmc.statuses = tmc.statuses[1:]
+	}
+	return status, nil
+}
+
+func (tmc *restoreDrillTMClient) RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RestoreFromBackupRequest) (logutil.EventStream, error) {
+	tmc.mu.Lock()
+	tmc.restored = append(tmc.restored, req.BackupName)
+	tmc.mu.Unlock()
+	if tmc.restoreErr != nil {
+		if _, err := tmc.ts.UpdateTabletFields(ctx, tablet.Alias, func(tablet *topodatapb.Tablet) error {
+			tablet.Type = topodatapb.TabletType_RESTORE
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		return nil, tmc.restoreErr
+	}
+	return &eventStream{{Value: "restore done"}}, nil
+}
+
+func (tmc *restoreDrillTMClient) ChangeType(ctx context.Context, tablet *topodatapb.Tablet, tabletType topodatapb.TabletType, semiSync bool) error {
+	tmc.mu.Lock()
+	tmc.typeChanges = append(tmc.typeChanges, tabletType)
+	tmc.mu.Unlock()
+	_, err := tmc.ts.UpdateTabletFields(ctx, tablet.Alias, func(tablet *topodatapb.Tablet) error {
+		tablet.Type = tabletType
+		return nil
+	})
+	return err
+}
+
+func (tmc *restoreDrillTMClient) StopReplication(ctx context.Context, tablet *topodatapb.Tablet) error {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	tmc.stops++
+	return nil
+}
+
+func (tmc *restoreDrillTMClient) ExecuteFetchAsDba(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsDbaRequest) (*querypb.QueryResult, error) {
+	rows, ok := tmc.rows[string(req.Query)]
+	if !ok {
+		return nil, fmt.Errorf("unknown query %q", req.Query)
+	}
+	result := &sqltypes.Result{}
+	for i := range rows {
+		result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewInt64(int64(i))})
+	}
+	return sqltypes.ResultToProto3(result), nil
+}
+
+func TestRestoreDrill(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	oldPollInterval := restoreDrillPollInterval
+	restoreDrillPollInterval = 10 * time.Millisecond
+	defer func() { restoreDrillPollInterval = oldPollInterval }()
+
+	useFileBackupStorage(t)
+	for _, name := range []string{"2025-01-01.000000.cell1-0000000101", "2025-01-02.000000.cell1-0000000101"} {
+		writeTestBackup(t, name, &mysqlctl.BackupManifest{BackupName: name, BackupMethod: "builtin"})
+	}
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+	for uid, tabletType := range map[uint32]topodatapb.TabletType{
+		100: topodatapb.TabletType_PRIMARY,
+		101: topodatapb.TabletType_REPLICA,
+		102: topodatapb.TabletType_DRAINED,
+	} {
+		require.NoError(t, ts.InitTablet(ctx, &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    "0",
+			Type:     tabletType,
+		}, true /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/))
+	}
+	drained := &topodatapb.TabletAlias{Cell: "cell1", Uid: 102}
+	opts := RestoreDrillOptions{
+		MaxReplicationLag: 10 * time.Second,
+		CatchUpTimeout:    time.Minute,
+		ValidationQueries: []string{"select count(*) from t"},
+	}
+	newTMClient := func(statuses ...*replicationdatapb.Status) *restoreDrillTMClient {
+		return &restoreDrillTMClient{
+			ts:       ts,
+			rows:     map[string]int{"select count(*) from t": 1, "select 1 from empty_table": 0},
+			statuses: statuses,
+		}
+	}
+	stopped := &replicationdatapb.Status{}
+
+	wr := New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, newTMClient(stopped))
+	_, err := wr.RestoreDrill(ctx, &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}, opts)
+	require.ErrorContains(t, err, "is the primary of shard ks/0")
+	_, err = wr.RestoreDrill(ctx, &topodatapb.TabletAlias{Cell: "cell1", Uid: 101}, opts)
+	require.ErrorContains(t, err, "tablet cell1-0000000101 is replica, a restore drill only runs on a SPARE or DRAINED tablet")
+	_, err = wr.RestoreDrill(ctx, drained, RestoreDrillOptions{BackupName: "2024-12-31.000000.cell1-0000000101", CatchUpTimeout: time.Minute})
+	require.ErrorContains(t, err, "no backup 2024-12-31.000000.cell1-0000000101")
+
+	// The tablet didn't replicate before the drill, and is left so once it
+	// caught up.
+	tmc := newTMClient(stopped, replicatingStatus(100), replicatingStatus(5))
+	wr = New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, tmc)
+	report, err := wr.RestoreDrill(ctx, drained, opts)
+	require.NoError(t, err)
+	require.True(t, report.Passed, report.Failure)
+	require.Equal(t, "cell1-0000000102", report.Tablet)
+	require.Equal(t, "drained", report.OriginalType)
+	require.Equal(t, "2025-01-02.000000.cell1-0000000101", report.BackupName)
+	require.Equal(t, 5*time.Second, report.ReplicationLag)
+	require.Len(t, report.Queries, 1)
+	require.Equal(t, 1, report.Queries[0].Rows)
+	require.Empty(t, report.RevertError)
+	require.Equal(t, []string{"2025-01-02.000000.cell1-0000000101"}, tmc.restored)
+	require.Empty(t, tmc.typeChanges)
+	require.Equal(t, 1, tmc.stops)
+
+	// A validation query without rows fails the drill, and the others still
+	// run.
+	tmc = newTMClient(replicatingStatus(0))
+	wr = New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, tmc)
+	opts.BackupName = "2025-01-01.000000.cell1-0000000101"
+	opts.ValidationQueries = []string{"select 1 from empty_table", "select count(*) from t"}
+	report, err = wr.RestoreDrill(ctx, drained, opts)
+	require.NoError(t, err)
+	require.False(t, report.Passed)
+	require.Contains(t, report.Failure, `validation query "select 1 from empty_table" failed: no rows returned`)
+	require.Len(t, report.Queries, 2)
+	require.Equal(t, 1, report.Queries[1].Rows)
+	require.Equal(t, []string{"2025-01-01.000000.cell1-0000000101"}, tmc.restored)
+	require.Zero(t, tmc.stops)
+
+	tmc = newTMClient(replicatingStatus(0), replicatingStatus(100))
+	wr = New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, tmc)
+	opts.CatchUpTimeout = 50 * time.Millisecond
+	report, err = wr.RestoreDrill(ctx, drained, opts)
+	require.NoError(t, err)
+	require.False(t, report.Passed)
+	require.Contains(t, report.Failure, "did not catch up to a replication lag of 10s within 50ms: replication lag is 1m40s")
+	require.Empty(t, report.Queries)
+
+	// A failed restore leaves the tablet RESTORE, and it is changed back.
+	tmc = newTMClient(replicatingStatus(0))
+	tmc.restoreErr = fmt.Errorf("mysqld is down")
+	wr = New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, tmc)
+	report, err = wr.RestoreDrill(ctx, drained, opts)
+	require.NoError(t, err)
+	require.False(t, report.Passed)
+	require.Contains(t, report.Failure, "restore failed: mysqld is down")
+	require.Equal(t, []topodatapb.TabletType{topodatapb.TabletType_DRAINED}, tmc.typeChanges)
+	ti, err := ts.GetTablet(ctx, drained)
+	require.NoError(t, err)
+	require.Equal(t, topodatapb.TabletType_DRAINED, ti.Type)
+}
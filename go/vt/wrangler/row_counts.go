@@ -0,0 +1,383 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+	"vitess.io/vitess/go/vt/vtctl/schematools"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// RowCountOptions are the options of the row count comparisons.
+type RowCountOptions struct {
+	// Tables are the tables to compare. If empty, the tables of the
+	// workflow, or all the base tables of the source keyspace, are.
+	Tables []string
+	// Approximate compares the row estimates of information_schema.tables
+	// rather than the results of COUNT(*).
+	Approximate bool
+	// TolerancePct is the difference between the target and source row
+	// counts of a table, in percent of the source row count, above which
+	// the table is reported as different.
+	TolerancePct float64
+	// Concurrency is how many count queries run at a time.
+	Concurrency int
+	// SourceShards and TargetShards are the shards of the source and target
+	// keyspaces to compare, all the serving ones if empty. They are required to
+	// compare the shards of a single keyspace, as in a reshard, and must
+	// not overlap then.
+	SourceShards []string
+	TargetShards []string
+}
+
+// TableRowCount is the comparison of the row counts of a table.
+type TableRowCount struct {
+	Table      string
+	SourceRows int64
+	TargetRows int64
+	// Difference is TargetRows - SourceRows.
+	Difference int64
+	// Mismatch is set when the difference is beyond the tolerance.
+	Mismatch bool `json:",omitempty"`
+	// SourceShardRows and TargetShardRows are the row counts of each shard.
+	SourceShardRows map[string]int64
+	TargetShardRows map[string]int64
+	// Errors are why the table couldn't be counted on some shards, in which
+	// case it is not compared.
+	Errors []string `json:",omitempty"`
+}
+
+// RowCountReport is the result of CompareRowCounts and
+// CompareWorkflowRowCounts.
+type RowCountReport struct {
+	Time           time.Time
+	Workflow       string `json:",omitempty"`
+	SourceKeyspace string
+	TargetKeyspace string
+	Approximate    bool
+	TolerancePct   float64
+	Tables         []*TableRowCount
+	// Mismatches are the tables whose row counts differ beyond the
+	// tolerance.
+	Mismatches       []string `json:",omitempty"`
+	TablesWithErrors []string `json:",omitempty"`
+}
+
+// rowCountShard is a shard whose rows are counted, on one of its replicas.
+type rowCountShard struct {
+	shard  string
+	tablet *topo.TabletInfo
+	source bool
+}
+
+// CompareWorkflowRowCounts compares, for every table of the workflow, the
+// total row count of its source shards with the one of its target shards.
+func (wr *Wrangler) CompareWorkflowRowCounts(ctx context.Context, targetKeyspace, workflowName string, opts RowCountOptions) (*RowCountReport, error) {
+	if opts.Concurrency <= 0 {
+		return nil, fmt.Errorf("the concurrency must be positive, got %d", opts.Concurrency)
+	}
+	ts, err := wr.buildTrafficSwitcher(ctx, targetKeyspace, workflowName)
+	if err != nil {
+		return nil, err
+	}
+	var shards []*rowCountShard
+	for shard := range ts.Sources() {
+		tablet, err := wr.rowCountTablet(ctx, ts.SourceKeyspaceName(), shard)
+		if err != nil {
+			return nil, err
+		}
+		shards = append(shards, &rowCountShard{shard: shard, tablet: tablet, source: true})
+	}
+	for shard := range ts.Targets() {
+		tablet, err := wr.rowCountTablet(ctx, ts.TargetKeyspaceName(), shard)
+		if err != nil {
+			return nil, err
+		}
+		shards = append(shards, &rowCountShard{shard: shard, tablet: tablet})
+	}
+	tables := opts.Tables
+	if len(tables) == 0 {
+		tables = ts.Tables()
+	}
+	report := &RowCountReport{
+		Workflow:       workflowName,
+		SourceKeyspace: ts.SourceKeyspaceName(),
+		TargetKeyspace: ts.TargetKeyspaceName(),
+	}
+	return wr.compareRowCounts(ctx, report, shards, tables, opts)
+}
+
+// CompareRowCounts compares, for every table, the total row count of the
+// source shards with the one of the target shards, as after moving the
+// tables from one keyspace to the other, or resharding a keyspace.
+func (wr *Wrangler) CompareRowCounts(ctx context.Context, sourceKeyspace, targetKeyspace string, opts RowCountOptions) (*RowCountReport, error) {
+	if opts.Concurrency <= 0 {
+		return nil, fmt.Errorf("the concurrency must be positive, got %d", opts.Concurrency)
+	}
+	if sourceKeyspace == targetKeyspace {
+		if len(opts.SourceShards) == 0 || len(opts.TargetShards) == 0 {
+			return nil, fmt.Errorf("the source and target keyspaces must be different, unless the source and target shards are given")
+		}
+		for _, shard := range opts.SourceShards {
+			if slices.Contains(opts.TargetShards, shard) {
+				return nil, fmt.Errorf("shard %v/%v cannot be both a source and a target shard", sourceKeyspace, shard)
+			}
+		}
+	}
+	sourceShards, err := wr.rowCountShards(ctx, sourceKeyspace, opts.SourceShards, true)
+	if err != nil {
+		return nil, err
+	}
+	targetShards, err := wr.rowCountShards(ctx, targetKeyspace, opts.TargetShards, false)
+	if err != nil {
+		return nil, err
+	}
+	shards := append(sourceShards, targetShards...)
+	report := &RowCountReport{
+		SourceKeyspace: sourceKeyspace,
+		TargetKeyspace: targetKeyspace,
+	}
+	return wr.compareRowCounts(ctx, report, shards, opts.Tables, opts)
+}
+
+// rowCountShards returns the given shards of the keyspace, or all the
+// serving ones if none are given, with the tablet to count their rows on.
+// The shards that serve no traffic, such as the source shards of a
+// completed reshard, don't hold the rows of the keyspace anymore.
+func (wr *Wrangler) rowCountShards(ctx context.Context, keyspace string, names []string, source bool) ([]*rowCountShard, error) {
+	if len(names) == 0 {
+		var err error
+		if names, err = wr.servingShardNames(ctx, keyspace); err != nil {
+			return nil, err
+		}
+	}
+	var (
+		mu     sync.Mutex
+		shards []*rowCountShard
+	)
+	// The rows are counted on a replica, so the shards without a primary
+	// are counted too.
+	_, err := wr.ForEachShardPrimary(ctx, keyspace, ShardPrimaryOptions{RunWithoutPrimary: true, Shards: names}, func(ctx context.Context, shard string, primary *topo.TabletInfo) error {
+		tablet, err := wr.rowCountTablet(ctx, keyspace, shard)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		shards = append(shards, &rowCountShard{shard: shard, tablet: tablet, source: source})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shards, nil
+}
+
+// servingShardNames returns the names of the shards of the keyspace that
+// serve traffic, in order, and logs the others.
+func (wr *Wrangler) servingShardNames(ctx context.Context, keyspace string) ([]string, error) {
+	shards, err := wr.ts.FindAllShardsInKeyspace(ctx, keyspace, nil)
+	if err != nil {
+		return nil, err
+	}
+	var serving, nonServing []string
+	for name, si := range shards {
+		if !grpcvtctldserver.IsShardServing(ctx, wr.ts, si) {
+			nonServing = append(nonServing, name)
+			continue
+		}
+		serving = append(serving, name)
+	}
+	sort.Strings(serving)
+	sort.Strings(nonServing)
+	wr.logNonServingShards(nonServing)
+	if len(serving) == 0 {
+		return nil, fmt.Errorf("no serving shard in keyspace %v", keyspace)
+	}
+	return serving, nil
+}
+
+// rowCountTablet returns the tablet to count the rows of the shard on, so
+// that the counts don't load its primary: an RDONLY tablet if it has one,
+// or else a REPLICA.
+func (wr *Wrangler) rowCountTablet(ctx context.Context, keyspace, shard string) (*topo.TabletInfo, error) {
+	tabletMap, err := wr.getTabletMapForShard(ctx, keyspace, shard, nil, warnOnUnreadableTablets)
+	if err != nil {
+		return nil, err
+	}
+	rank := map[topodatapb.TabletType]int{
+		topodatapb.TabletType_RDONLY:  0,
+		topodatapb.TabletType_REPLICA: 1,
+	}
+	var tablet *topo.TabletInfo
+	for _, ti := range tabletMap {
+		r, ok := rank[ti.Type]
+		if !ok {
+			continue
+		}
+		if tablet == nil || r < rank[tablet.Type] || (r == rank[tablet.Type] && ti.AliasString() < tablet.AliasString()) {
+			tablet = ti
+		}
+	}
+	if tablet == nil {
+		return nil, fmt.Errorf("no replica or rdonly tablet in shard %v/%v to count the rows on", keyspace, shard)
+	}
+	return tablet, nil
+}
+
+// compareRowCounts counts the rows of the tables on the shards, at most
+// opts.Concurrency queries at a time, and fills in the report. If no tables
+// are given, all the base tables of the first source shard are counted.
+func (wr *Wrangler) compareRowCounts(ctx context.Context, report *RowCountReport, shards []*rowCountShard, tables []string, opts RowCountOptions) (*RowCountReport, error) {
+	sort.Slice(shards, func(i, j int) bool { return shards[i].shard < shards[j].shard })
+	if len(tables) == 0 {
+		var err error
+		if tables, err = wr.rowCountTables(ctx, shards); err != nil {
+			return nil, err
+		}
+	}
+	tables = slices.Clone(tables)
+	sort.Strings(tables)
+	report.Time = wr.now().UTC()
+	report.Approximate = opts.Approximate
+	report.TolerancePct = opts.TolerancePct
+	report.Tables = make([]*TableRowCount, len(tables))
+	for i, table := range tables {
+		report.Tables[i] = &TableRowCount{
+			Table:           table,
+			SourceShardRows: make(map[string]int64),
+			TargetShardRows: make(map[string]int64),
+		}
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, opts.Concurrency)
+	)
+	for _, result := range report.Tables {
+		for _, shard := range shards {
+			wg.Add(1)
+			go func(result *TableRowCount, shard *rowCountShard) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				ctx, cancel := wr.shardContext(ctx)
+				defer cancel()
+				rows, err := wr.countTableRows(ctx, shard.tablet, result.Table, opts.Approximate)
+				side := "target"
+				if shard.source {
+					side = "source"
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				switch {
+				case err != nil:
+					result.Errors = append(result.Errors, fmt.Sprintf("%v shard %v: %v", side, shard.shard, err))
+				case shard.source:
+					result.SourceShardRows[shard.shard] = rows
+					result.SourceRows += rows
+				default:
+					result.TargetShardRows[shard.shard] = rows
+					result.TargetRows += rows
+				}
+			}(result, shard)
+		}
+	}
+	wg.Wait()
+
+	for _, result := range report.Tables {
+		if len(result.Errors) > 0 {
+			sort.Strings(result.Errors)
+			report.TablesWithErrors = append(report.TablesWithErrors, result.Table)
+			continue
+		}
+		result.Difference = result.TargetRows - result.SourceRows
+		difference := result.Difference
+		if difference < 0 {
+			difference = -difference
+		}
+		if float64(difference) > float64(result.SourceRows)*opts.TolerancePct/100 {
+			result.Mismatch = true
+			report.Mismatches = append(report.Mismatches, result.Table)
+		}
+	}
+	return report, nil
+}
+
+// rowCountTables returns the base tables of the first source shard, without
+// the internal tables of Vitess.
+func (wr *Wrangler) rowCountTables(ctx context.Context, shards []*rowCountShard) ([]string, error) {
+	for _, shard := range shards {
+		if !shard.source {
+			continue
+		}
+		sd, err := schematools.GetSchema(ctx, wr.ts, wr.tmc, shard.tablet.Alias, &tabletmanagerdatapb.GetSchemaRequest{TableSchemaOnly: true})
+		if err != nil {
+			return nil, fmt.Errorf("cannot get the tables of source shard %v: %v", shard.shard, err)
+		}
+		var tables []string
+		for _, td := range sd.TableDefinitions {
+			if td.Type == tmutils.TableBaseTable && !schema.IsInternalOperationTableName(td.Name) {
+				tables = append(tables, td.Name)
+			}
+		}
+		return tables, nil
+	}
+	return nil, fmt.Errorf("no source shard to get the tables from")
+}
+
+// countTableRows returns the row count of the table on the tablet, or its
+// estimate if approximate is set.
+func (wr *Wrangler) countTableRows(ctx context.Context, ti *topo.TabletInfo, table string, approximate bool) (int64, error) {
+	query := fmt.Sprintf("select count(*) from %s", sqlescape.EscapeID(table))
+	if approximate {
+		query = fmt.Sprintf("select table_rows from information_schema.tables where table_schema = database() and table_name = %s", encodeString(table))
+	}
+	qr, err := callTablet(ctx, "ExecuteFetchAsDba", idempotent, func(ctx context.Context) (*querypb.QueryResult, error) {
+		return wr.tmc.ExecuteFetchAsDba(ctx, ti.Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:   []byte(query),
+			DbName:  topoproto.TabletDbName(ti.Tablet),
+			MaxRows: 1,
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	result := sqltypes.Proto3ToResult(qr)
+	if len(result.Rows) != 1 {
+		return 0, fmt.Errorf("table %v not found on %v", table, ti.AliasString())
+	}
+	return result.Rows[0][0].ToInt64()
+}
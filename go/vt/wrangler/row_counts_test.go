@@ -0,0 +1,195 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// rowCountTMClient answers the count queries with the row counts of each
+// tablet by table, and fails them for the tables a tablet has no count for.
+type rowCountTMClient struct {
+	tmclient.TabletManagerClient
+
+	schema *tabletmanagerdatapb.SchemaDefinition
+	counts map[uint32]map[string]int64
+
+	mu      sync.Mutex
+	queries []string
+}
+
+func (tmc *rowCountTMClient) GetSchema(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.GetSchemaRequest) (*tabletmanagerdatapb.SchemaDefinition, error) {
+	return tmc.schema, nil
+}
+
+func (tmc *rowCountTMClient) ExecuteFetchAsDba(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsDbaRequest) (*querypb.QueryResult, error) {
+	query := string(req.Query)
+	tmc.mu.Lock()
+	tmc.queries = append(tmc.queries, query)
+	tmc.mu.Unlock()
+
+	for table, count := range tmc.counts[tablet.Alias.Uid] {
+		if strings.HasSuffix(query, fmt.Sprintf("from `%s`", table)) || strings.HasSuffix(query, fmt.Sprintf("table_name = '%s'", table)) {
+			return sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("count", "int64"), fmt.Sprint(count))), nil
+		}
+	}
+	return nil, fmt.Errorf("table does not exist")
+}
+
+func TestCompareRowCounts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+	tmc := &rowCountTMClient{
+		schema: &tabletmanagerdatapb.SchemaDefinition{
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{Name: "t2", Type: tmutils.TableBaseTable},
+				{Name: "t1", Type: tmutils.TableBaseTable},
+				{Name: "t3", Type: tmutils.TableBaseTable},
+				{Name: "v1", Type: tmutils.TableView},
+				{Name: "_vt_hld_6ace8bcef73211ea87e9f875a4d24e90_20200915120410_", Type: tmutils.TableBaseTable},
+			},
+		},
+		// The rows are counted on the RDONLY tablet of a shard, or else on
+		// its REPLICA, never on its primary.
+		counts: map[uint32]map[string]int64{
+			100: {"t1": 1, "t2": 1, "t3": 1},
+			101: {"t1": 100, "t2": 50, "t3": 10},
+			102: {"t1": 1, "t2": 1, "t3": 1},
+			200: {"t1": 1, "t2": 1, "t3": 1},
+			201: {"t1": 60, "t2": 30, "t3": 4},
+			301: {"t1": 40, "t2": 18},
+		},
+	}
+	wr := New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, tmc)
+
+	// An unsharded keyspace moved to a sharded one, and a keyspace without
+	// replicas.
+	tablets := []struct {
+		uid        uint32
+		keyspace   string
+		shard      string
+		tabletType topodatapb.TabletType
+	}{
+		{100, "src", "0", topodatapb.TabletType_PRIMARY},
+		{101, "src", "0", topodatapb.TabletType_RDONLY},
+		{102, "src", "0", topodatapb.TabletType_REPLICA},
+		{200, "dst", "-80", topodatapb.TabletType_PRIMARY},
+		{201, "dst", "-80", topodatapb.TabletType_REPLICA},
+		{300, "dst", "80-", topodatapb.TabletType_PRIMARY},
+		{301, "dst", "80-", topodatapb.TabletType_RDONLY},
+		{400, "solo", "0", topodatapb.TabletType_PRIMARY},
+	}
+	for _, tablet := range tablets {
+		alias := &topodatapb.TabletAlias{Cell: "cell1", Uid: tablet.uid}
+		err := ts.InitTablet(ctx, &topodatapb.Tablet{
+			Alias:    alias,
+			Keyspace: tablet.keyspace,
+			Shard:    tablet.shard,
+			Type:     tablet.tabletType,
+		}, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+		if tablet.tabletType != topodatapb.TabletType_PRIMARY {
+			continue
+		}
+		_, err = ts.UpdateShardFields(ctx, tablet.keyspace, tablet.shard, func(si *topo.ShardInfo) error {
+			si.PrimaryAlias = alias
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	report, err := wr.CompareRowCounts(ctx, "src", "dst", RowCountOptions{Concurrency: 2})
+	require.NoError(t, err)
+	require.Equal(t, "src", report.SourceKeyspace)
+	require.Equal(t, "dst", report.TargetKeyspace)
+	require.Len(t, report.Tables, 3)
+	require.Equal(t, &TableRowCount{
+		Table:           "t1",
+		SourceRows:      100,
+		TargetRows:      100,
+		SourceShardRows: map[string]int64{"0": 100},
+		TargetShardRows: map[string]int64{"-80": 60, "80-": 40},
+	}, report.Tables[0])
+	require.Equal(t, int64(-2), report.Tables[1].Difference)
+	require.True(t, report.Tables[1].Mismatch)
+	require.Equal(t, []string{"t2"}, report.Mismatches)
+	require.Equal(t, []string{"target shard 80-: table does not exist"}, report.Tables[2].Errors)
+	require.Equal(t, []string{"t3"}, report.TablesWithErrors)
+
+	// A difference within the tolerance is not reported.
+	tmc.queries = nil
+	report, err = wr.CompareRowCounts(ctx, "src", "dst", RowCountOptions{
+		Tables:       []string{"t2"},
+		Approximate:  true,
+		TolerancePct: 5,
+		Concurrency:  1,
+	})
+	require.NoError(t, err)
+	require.Len(t, report.Tables, 1)
+	require.False(t, report.Tables[0].Mismatch)
+	require.Empty(t, report.Mismatches)
+	require.Len(t, tmc.queries, 3)
+	require.Contains(t, tmc.queries[0], "from information_schema.tables where table_schema = database() and table_name = 't2'")
+
+	// The shards of a single keyspace can be compared with each other.
+	report, err = wr.CompareRowCounts(ctx, "dst", "dst", RowCountOptions{
+		Tables:       []string{"t1"},
+		Concurrency:  1,
+		SourceShards: []string{"-80"},
+		TargetShards: []string{"80-"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{"-80": 60}, report.Tables[0].SourceShardRows)
+	require.Equal(t, map[string]int64{"80-": 40}, report.Tables[0].TargetShardRows)
+
+	_, err = wr.CompareRowCounts(ctx, "src", "src", RowCountOptions{Concurrency: 1})
+	require.ErrorContains(t, err, "must be different, unless the source and target shards are given")
+	_, err = wr.CompareRowCounts(ctx, "dst", "dst", RowCountOptions{Concurrency: 1, SourceShards: []string{"-80"}, TargetShards: []string{"-80", "80-"}})
+	require.ErrorContains(t, err, "shard dst/-80 cannot be both a source and a target shard")
+	_, err = wr.CompareRowCounts(ctx, "solo", "dst", RowCountOptions{Concurrency: 1})
+	require.ErrorContains(t, err, "no replica or rdonly tablet in shard solo/0")
+	_, err = wr.CompareRowCounts(ctx, "src", "dst", RowCountOptions{})
+	require.ErrorContains(t, err, "the concurrency must be positive")
+
+	// The shards that serve no traffic, here one overlapping the serving
+	// ones and without any tablet, are not counted.
+	require.NoError(t, ts.CreateShard(ctx, "dst", "-"))
+	report, err = wr.CompareRowCounts(ctx, "src", "dst", RowCountOptions{Tables: []string{"t1"}, Concurrency: 1})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{"-80": 60, "80-": 40}, report.Tables[0].TargetShardRows)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+var (
+	disableTabletRPCRetries bool
+
+	// tabletRPCRetries is how many times an idempotent RPC is sent again
+	// after a transport error, waiting tabletRPCRetryBackoff before the
+	// first retry and twice as long before each next one.
+	tabletRPCRetries      = 3
+	tabletRPCRetryBackoff = 100 * time.Millisecond
+
+	tabletRPCRetryCount = stats.NewCountersWithSingleLabel("WranglerTabletRPCRetries", "Tablet manager RPCs sent again by the wrangler after a transport error", "RPC")
+)
+
+func init() {
+	servenv.OnParseFor("vtctl", registerTabletRPCFlags)
+	servenv.OnParseFor("vtctld", registerTabletRPCFlags)
+}
+
+func registerTabletRPCFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&disableTabletRPCRetries, "disable-tablet-rpc-retries", disableTabletRPCRetries, "if set, do not retry idempotent tablet manager RPCs that failed with a transport error, such as a connection reset by a restarting tablet.")
+}
+
+// rpcIdempotency tells whether a tablet manager RPC can be sent again
+// without changing its outcome.
+type rpcIdempotency bool
+
+const (
+	idempotent    rpcIdempotency = true
+	notIdempotent rpcIdempotency = false
+)
+
+// callTablet runs call, which sends the tablet manager RPC named rpc. If the
+// RPC is idempotent, it is retried with backoff when it fails with a
+// transport error, unless retries are disabled. The RPC and its retries
+// are traced in one span.
+func callTablet[T any](ctx context.Context, rpc string, idempotency rpcIdempotency, call func(ctx context.Context) (T, error)) (T, error) {
+	span, ctx := trace.NewSpan(ctx, "Wrangler."+rpc)
+	defer span.Finish()
+
+	backoff := tabletRPCRetryBackoff
+	for attempt := 0; ; attempt++ {
+		result, err := call(ctx)
+		if err == nil || idempotency == notIdempotent || disableTabletRPCRetries || attempt == tabletRPCRetries || !isTransportError(err) {
+			span.Annotate("attempts", attempt+1)
+			return result, err
+		}
+
+		select {
+		case <-ctx.Done():
+			span.Annotate("attempts", attempt+1)
+			return result, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		tabletRPCRetryCount.Add(rpc, 1)
+	}
+}
+
+// isTransportError returns true if err means the RPC didn't reach the
+// tablet, rather than the tablet failing it. The tablet manager clients
+// return either vterrors or raw gRPC status errors, so both are checked.
+func isTransportError(err error) bool {
+	return vterrors.Code(err) == vtrpcpb.Code_UNAVAILABLE || status.Code(err) == codes.Unavailable
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestCallTablet(t *testing.T) {
+	oldBackoff := tabletRPCRetryBackoff
+	tabletRPCRetryBackoff = time.Millisecond
+	defer func() { tabletRPCRetryBackoff = oldBackoff }()
+
+	// failing returns a call that fails with code the first failures times.
+	failing := func(failures int, code vtrpcpb.Code) (func(ctx context.Context) (string, error), *int) {
+		calls := 0
+		return func(ctx context.Context) (string, error) {
+			calls++
+			if calls <= failures {
+				return "", vterrors.New(code, "connection reset by peer")
+			}
+			return "ok", nil
+		}, &calls
+	}
+
+	tcases := []struct {
+		name        string
+		idempotency rpcIdempotency
+		failures    int
+		code        vtrpcpb.Code
+		disabled    bool
+		wantCalls   int
+		wantErr     bool
+	}{{
+		name:        "idempotent call recovers",
+		idempotency: idempotent,
+		failures:    2,
+		code:        vtrpcpb.Code_UNAVAILABLE,
+		wantCalls:   3,
+	}, {
+		name:        "idempotent call runs out of retries",
+		idempotency: idempotent,
+		failures:    10,
+		code:        vtrpcpb.Code_UNAVAILABLE,
+		wantCalls:   tabletRPCRetries + 1,
+		wantErr:     true,
+	}, {
+		name:        "tablet errors are not retried",
+		idempotency: idempotent,
+		failures:    1,
+		code:        vtrpcpb.Code_FAILED_PRECONDITION,
+		wantCalls:   1,
+		wantErr:     true,
+	}, {
+		name:        "non idempotent call is not retried",
+		idempotency: notIdempotent,
+		failures:    1,
+		code:        vtrpcpb.Code_UNAVAILABLE,
+		wantCalls:   1,
+		wantErr:     true,
+	}, {
+		name:        "retries disabled",
+		idempotency: idempotent,
+		failures:    1,
+		code:        vtrpcpb.Code_UNAVAILABLE,
+		disabled:    true,
+		wantCalls:   1,
+		wantErr:     true,
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			disableTabletRPCRetries = tcase.disabled
+			defer func() { disableTabletRPCRetries = false }()
+
+			retries := tabletRPCRetryCount.Counts()["Ping"]
+			call, calls := failing(tcase.failures, tcase.code)
+			result, err := callTablet(context.Background(), "Ping", tcase.idempotency, call)
+			require.Equal(t, tcase.wantCalls, *calls)
+			require.Equal(t, int64(tcase.wantCalls-1), tabletRPCRetryCount.Counts()["Ping"]-retries)
+			if tcase.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "ok", result)
+		})
+	}
+
+	t.Run("raw grpc transport error", func(t *testing.T) {
+		calls := 0
+		result, err := callTablet(context.Background(), "Ping", idempotent, func(ctx context.Context) (string, error) {
+			calls++
+			if calls == 1 {
+				return "", status.Error(codes.Unavailable, "connection reset by peer")
+			}
+			return "ok", nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, "ok", result)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		call, calls := failing(10, vtrpcpb.Code_UNAVAILABLE)
+		_, err := callTablet(ctx, "Ping", idempotent, call)
+		require.Error(t, err)
+		require.Equal(t, 1, *calls)
+	})
+}
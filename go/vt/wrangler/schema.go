@@ -18,14 +18,20 @@ package wrangler
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"text/template"
 	"time"
 
+	"golang.org/x/sync/semaphore"
+
+	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/concurrency"
-	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
 	"vitess.io/vitess/go/vt/schema"
@@ -39,84 +45,59 @@ import (
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
-// helper method to asynchronously diff a schema
-func (wr *Wrangler) diffSchema(ctx context.Context, primarySchema *tabletmanagerdatapb.SchemaDefinition, primaryTabletAlias, alias *topodatapb.TabletAlias, excludeTables []string, includeViews bool, wg *sync.WaitGroup, er concurrency.ErrorRecorder) {
-	defer wg.Done()
-	log.Infof("Gathering schema for %v", topoproto.TabletAliasString(alias))
-	req := &tabletmanagerdatapb.GetSchemaRequest{ExcludeTables: excludeTables, IncludeViews: includeViews}
-	replicaSchema, err := schematools.GetSchema(ctx, wr.ts, wr.tmc, alias, req)
-	if err != nil {
-		er.RecordError(fmt.Errorf("GetSchema(%v, nil, %v, %v) failed: %v", alias, excludeTables, includeViews, err))
-		return
-	}
-
-	log.Infof("Diffing schema for %v", topoproto.TabletAliasString(alias))
-	tmutils.DiffSchema(topoproto.TabletAliasString(primaryTabletAlias), primarySchema, topoproto.TabletAliasString(alias), replicaSchema, er)
-}
-
-// ValidateSchemaShard will diff the schema from all the tablets in the shard.
-func (wr *Wrangler) ValidateSchemaShard(ctx context.Context, keyspace, shard string, excludeTables []string, includeViews bool, includeVSchema bool) error {
-	si, err := wr.ts.GetShard(ctx, keyspace, shard)
-	if err != nil {
-		return fmt.Errorf("GetShard(%v, %v) failed: %v", keyspace, shard, err)
-	}
-
-	// get schema from the primary, or error
-	if !si.HasPrimary() {
-		return fmt.Errorf("no primary in shard %v/%v", keyspace, shard)
-	}
-	log.Infof("Gathering schema for primary %v", topoproto.TabletAliasString(si.PrimaryAlias))
-	req := &tabletmanagerdatapb.GetSchemaRequest{ExcludeTables: excludeTables, IncludeViews: includeViews}
-	primarySchema, err := schematools.GetSchema(ctx, wr.ts, wr.tmc, si.PrimaryAlias, req)
-	if err != nil {
-		return fmt.Errorf("GetSchema(%v, nil, %v, %v) failed: %v", si.PrimaryAlias, excludeTables, includeViews, err)
-	}
-
-	if includeVSchema {
-		err := wr.ValidateVSchema(ctx, keyspace, []string{shard}, excludeTables, includeViews)
-		if err != nil {
-			return err
-		}
-	}
-
-	// read all the aliases in the shard, that is all tablets that are
-	// replicating from the primary
-	aliases, err := wr.ts.FindAllTabletAliasesInShard(ctx, keyspace, shard)
+// ValidateSchemaShard will diff the schema from all the tablets in the shard,
+// except the DRAINED, BACKUP and RESTORE ones, which are logged, unless
+// includeNonServing is set.
+func (wr *Wrangler) ValidateSchemaShard(ctx context.Context, keyspace, shard string, excludeTables []string, includeViews bool, includeVSchema bool, includeNonServing bool) error {
+	res, err := wr.VtctldServer().ValidateSchemaKeyspace(wr.vtctldContext(ctx), &vtctldatapb.ValidateSchemaKeyspaceRequest{
+		Keyspace:          keyspace,
+		Shards:            []string{shard},
+		ExcludeTables:     excludeTables,
+		IncludeViews:      includeViews,
+		IncludeVschema:    includeVSchema,
+		IncludeNonServing: includeNonServing,
+		// The requested shard is validated even if it serves no traffic.
+		IncludeNonServingShards: true,
+	})
 	if err != nil {
-		return fmt.Errorf("FindAllTabletAliasesInShard(%v, %v) failed: %v", keyspace, shard, err)
-	}
-
-	// then diff with all replicas
-	er := concurrency.AllErrorRecorder{}
-	wg := sync.WaitGroup{}
-	for _, alias := range aliases {
-		if topoproto.TabletAliasEqual(alias, si.PrimaryAlias) {
-			continue
-		}
-
-		wg.Add(1)
-		go wr.diffSchema(ctx, primarySchema, si.PrimaryAlias, alias, excludeTables, includeViews, &wg, &er)
+		return err
 	}
-	wg.Wait()
-	if er.HasErrors() {
-		return fmt.Errorf("schema diffs: %v", er.Error().Error())
+	wr.logSkippedTablets(res.SkippedTablets)
+	if len(res.Results) > 0 {
+		return fmt.Errorf("schema diffs: %v", res.Results)
 	}
 	return nil
 }
 
-// ValidateSchemaKeyspace will diff the schema from all the tablets in the keyspace.
-func (wr *Wrangler) ValidateSchemaKeyspace(ctx context.Context, keyspace string, excludeTables []string, includeViews, skipNoPrimary bool, includeVSchema bool) error {
-	res, err := wr.VtctldServer().ValidateSchemaKeyspace(ctx, &vtctldatapb.ValidateSchemaKeyspaceRequest{
-		Keyspace:       keyspace,
-		ExcludeTables:  excludeTables,
-		IncludeViews:   includeViews,
-		IncludeVschema: includeVSchema,
-		SkipNoPrimary:  skipNoPrimary,
+// ValidateSchemaKeyspace will diff the schema from all the tablets in the
+// keyspace. The shards that serve no traffic are skipped, unless
+// includeNonServingShards is set, and so are the DRAINED, BACKUP and RESTORE
+// tablets, unless includeNonServing is set.
+func (wr *Wrangler) ValidateSchemaKeyspace(ctx context.Context, keyspace string, excludeTables []string, includeViews, skipNoPrimary bool, includeVSchema bool, includeNonServing, includeNonServingShards bool) error {
+	res, err := wr.VtctldServer().ValidateSchemaKeyspace(wr.vtctldContext(ctx), &vtctldatapb.ValidateSchemaKeyspaceRequest{
+		Keyspace:                keyspace,
+		ExcludeTables:           excludeTables,
+		IncludeViews:            includeViews,
+		IncludeVschema:          includeVSchema,
+		SkipNoPrimary:           skipNoPrimary,
+		IncludeNonServing:       includeNonServing,
+		IncludeNonServingShards: includeNonServingShards,
 	})
 
+	logger, flush := wr.fanOutLogger()
+	if len(res.GetNonServingShards()) > 0 {
+		logger.Printf("Skipped non-serving shards: %s\n", strings.Join(res.NonServingShards, ","))
+	}
+	for _, st := range res.GetSkippedTablets() {
+		logger.Printf("Skipped %v tablet %v of shard %v\n", st.TabletType, topoproto.TabletAliasString(st.TabletAlias), st.Shard)
+	}
+	if res.ReferenceReason != "" {
+		logger.Printf("Reference: %s\n", res.ReferenceReason)
+	}
 	for _, result := range res.Results {
-		wr.Logger().Printf("%s\n", result)
+		logger.Printf("%s\n", result)
 	}
+	flush()
 
 	if len(res.Results) > 0 {
 		return fmt.Errorf("schema diffs: %v", res.Results)
@@ -139,6 +120,8 @@ func (wr *Wrangler) ValidateVSchema(ctx context.Context, keyspace string, shards
 	for _, shard := range shards {
 		go func(shard string) {
 			defer wg.Done()
+			ctx, cancel := wr.shardContext(ctx)
+			defer cancel()
 			notFoundTables := []string{}
 			si, err := wr.ts.GetShard(ctx, keyspace, shard)
 			if err != nil {
@@ -179,12 +162,14 @@ func (wr *Wrangler) PreflightSchema(ctx context.Context, tabletAlias *topodatapb
 	if err != nil {
 		return nil, fmt.Errorf("GetTablet(%v) failed: %v", tabletAlias, err)
 	}
-	return wr.tmc.PreflightSchema(ctx, ti.Tablet, changes)
+	return callTablet(ctx, "PreflightSchema", idempotent, func(ctx context.Context) ([]*tabletmanagerdatapb.SchemaChangeResult, error) {
+		return wr.tmc.PreflightSchema(ctx, ti.Tablet, changes)
+	})
 }
 
 // CopySchemaShardFromShard copies the schema from a source shard to the specified destination shard.
 // For both source and destination it picks the primary tablet. See also CopySchemaShard.
-func (wr *Wrangler) CopySchemaShardFromShard(ctx context.Context, tables, excludeTables []string, includeViews bool, sourceKeyspace, sourceShard, destKeyspace, destShard string, waitReplicasTimeout time.Duration, skipVerify bool) error {
+func (wr *Wrangler) CopySchemaShardFromShard(ctx context.Context, tables, excludeTables []string, includeViews bool, sourceKeyspace, sourceShard, destKeyspace, destShard string, waitReplicasTimeout time.Duration, skipVerify bool, throttleWaiter *schematools.ThrottleWaiter) error {
 	sourceShardInfo, err := wr.ts.GetShard(ctx, sourceKeyspace, sourceShard)
 	if err != nil {
 		return fmt.Errorf("GetShard(%v, %v) failed: %v", sourceKeyspace, sourceShard, err)
@@ -193,14 +178,15 @@ func (wr *Wrangler) CopySchemaShardFromShard(ctx context.Context, tables, exclud
 		return fmt.Errorf("no primary in shard record %v/%v. Consider running 'vtctl InitShardPrimary' in case of a new shard or reparenting the shard to fix the topology data, or providing a non-primary tablet alias", sourceKeyspace, sourceShard)
 	}
 
-	return wr.CopySchemaShard(ctx, sourceShardInfo.PrimaryAlias, tables, excludeTables, includeViews, destKeyspace, destShard, waitReplicasTimeout, skipVerify)
+	return wr.CopySchemaShard(ctx, sourceShardInfo.PrimaryAlias, tables, excludeTables, includeViews, destKeyspace, destShard, waitReplicasTimeout, skipVerify, throttleWaiter)
 }
 
 // CopySchemaShard copies the schema from a source tablet to the
 // specified shard.  The schema is applied directly on the primary of
 // the destination shard, and is propagated to the replicas through
-// binlogs.
-func (wr *Wrangler) CopySchemaShard(ctx context.Context, sourceTabletAlias *topodatapb.TabletAlias, tables, excludeTables []string, includeViews bool, destKeyspace, destShard string, waitReplicasTimeout time.Duration, skipVerify bool) error {
+// binlogs. If throttleWaiter is set, each statement waits for the
+// throttler of the destination primary first.
+func (wr *Wrangler) CopySchemaShard(ctx context.Context, sourceTabletAlias *topodatapb.TabletAlias, tables, excludeTables []string, includeViews bool, destKeyspace, destShard string, waitReplicasTimeout time.Duration, skipVerify bool, throttleWaiter *schematools.ThrottleWaiter) error {
 	destShardInfo, err := wr.ts.GetShard(ctx, destKeyspace, destShard)
 	if err != nil {
 		return fmt.Errorf("GetShard(%v, %v) failed: %v", destKeyspace, destShard, err)
@@ -232,6 +218,9 @@ func (wr *Wrangler) CopySchemaShard(ctx context.Context, sourceTabletAlias *topo
 		return fmt.Errorf("GetTablet(%v) failed: %v", destShardInfo.PrimaryAlias, err)
 	}
 	for _, createSQL := range createSQLstmts {
+		if err := throttleWaiter.Wait(ctx, destTabletInfo.Tablet); err != nil {
+			return fmt.Errorf("CopySchemaShard aborted while waiting for the throttler: %v", err)
+		}
 		err = wr.applySQLShard(ctx, destTabletInfo, createSQL)
 		if err != nil {
 			return fmt.Errorf("creating a table failed."+
@@ -242,7 +231,9 @@ func (wr *Wrangler) CopySchemaShard(ctx context.Context, sourceTabletAlias *topo
 	}
 
 	// Remember the replication position after all the above were applied.
-	destPrimaryPos, err := wr.tmc.PrimaryPosition(ctx, destTabletInfo.Tablet)
+	destPrimaryPos, err := callTablet(ctx, "PrimaryPosition", idempotent, func(ctx context.Context) (string, error) {
+		return wr.tmc.PrimaryPosition(ctx, destTabletInfo.Tablet)
+	})
 	if err != nil {
 		return fmt.Errorf("CopySchemaShard: can't get replication position after schema applied: %v", err)
 	}
@@ -280,6 +271,220 @@ func (wr *Wrangler) CopySchemaShard(ctx context.Context, sourceTabletAlias *topo
 	return err
 }
 
+// ReloadSchemaKeyspace reloads the schema on the tablets of every shard of
+// the keyspace, at most concurrency of them at a time if it is positive. The
+// primary of each shard, if includePrimary is set, is reloaded before its
+// replicas. Like ReloadSchemaShard, it is best effort: the tablets that fail
+// to reload are logged, and only the shards that can't be read fail it.
+func (wr *Wrangler) ReloadSchemaKeyspace(ctx context.Context, keyspace string, includePrimary bool, concurrency int) error {
+	var sema *semaphore.Weighted
+	if concurrency > 0 {
+		sema = semaphore.NewWeighted(int64(concurrency))
+	}
+	// The replicas of a shard without a primary are reloaded too.
+	_, err := wr.ForEachShardPrimary(ctx, keyspace, ShardPrimaryOptions{RunWithoutPrimary: true}, func(ctx context.Context, shard string, primary *topo.TabletInfo) error {
+		if includePrimary && primary != nil {
+			wr.reloadPrimarySchema(ctx, primary, sema)
+		}
+		schematools.ReloadShard(ctx, wr.ts, wr.tmc, wr.Logger(), keyspace, shard, "", sema, false /* includePrimary */)
+		return nil
+	})
+	return err
+}
+
+// reloadPrimarySchema reloads the schema of the primary of a shard, holding
+// sema if it is set, and logs a failure.
+func (wr *Wrangler) reloadPrimarySchema(ctx context.Context, primary *topo.TabletInfo, sema *semaphore.Weighted) {
+	if sema != nil {
+		if err := sema.Acquire(ctx, 1); err != nil {
+			wr.Logger().Warningf("Failed to reload schema on primary tablet %v in %v/%v (use vtctl ReloadSchema to try again): timed out waiting for concurrency: %v", primary.AliasString(), primary.Keyspace, primary.Shard, err)
+			return
+		}
+		defer sema.Release(1)
+	}
+	if err := wr.tmc.ReloadSchema(ctx, primary.Tablet, ""); err != nil {
+		wr.Logger().Warningf("Failed to reload schema on primary tablet %v in %v/%v (use vtctl ReloadSchema to try again): %v", primary.AliasString(), primary.Keyspace, primary.Shard, err)
+	}
+}
+
+// schemaReloadPollInterval is how often ReloadSchemaAndWait reads the schema
+// of a tablet while waiting for a reload to show.
+var schemaReloadPollInterval = time.Second
+
+// SchemaReloadResult is the outcome of reloading the schema of one tablet
+// and waiting for it.
+type SchemaReloadResult struct {
+	TabletAlias string
+	// SchemaHash is the hash of the last schema read from the tablet.
+	SchemaHash string `json:",omitempty"`
+	// Changed tells, when no change was waited for, whether the reload
+	// changed the schema hash.
+	Changed   bool `json:",omitempty"`
+	Confirmed bool
+	Error     string `json:",omitempty"`
+}
+
+// ReloadSchemaAndWait reloads the schema of a tablet, then reads it with
+// GetSchema until it reflects the expected change or timeout passes. If
+// table is set, the change is that table showing up. Otherwise no change is
+// expected, as a schema may be reloaded to check that it didn't change: the
+// reload is confirmed once the schema can be read after it, and the result
+// tells whether its hash changed.
+func (wr *Wrangler) ReloadSchemaAndWait(ctx context.Context, tabletAlias *topodatapb.TabletAlias, table string, timeout time.Duration) (*SchemaReloadResult, error) {
+	ti, err := wr.ts.GetTablet(ctx, tabletAlias)
+	if err != nil {
+		return &SchemaReloadResult{TabletAlias: topoproto.TabletAliasString(tabletAlias)}, err
+	}
+	return wr.reloadSchemaAndWait(ctx, ti.Tablet, table, "", timeout)
+}
+
+// ReloadSchemaShardAndWait reloads the schema on the tablets of a shard, and
+// waits for each of them like ReloadSchemaAndWait. Without a table, each
+// tablet waits until its schema hash matches the one of the primary.
+func (wr *Wrangler) ReloadSchemaShardAndWait(ctx context.Context, keyspace, shard, table string, includePrimary bool, maxConcurrency int, timeout time.Duration) ([]*SchemaReloadResult, error) {
+	if maxConcurrency < 1 {
+		return nil, fmt.Errorf("concurrency must be at least 1")
+	}
+	span, ctx := trace.NewSpan(ctx, "Wrangler.ReloadSchemaShardAndWait")
+	defer span.Finish()
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("shard", shard)
+
+	// The tablets that can't be read are not reloaded.
+	tablets, err := wr.getTabletMapForShard(ctx, keyspace, shard, nil, warnOnUnreadableTablets)
+	if err != nil {
+		return nil, err
+	}
+
+	var expectedHash string
+	if table == "" {
+		si, err := wr.ts.GetShard(ctx, keyspace, shard)
+		if err != nil {
+			return nil, err
+		}
+		primary, ok := tablets[topoproto.TabletAliasString(si.PrimaryAlias)]
+		if !si.HasPrimary() || !ok {
+			return nil, fmt.Errorf("shard %v/%v has no primary to compare schemas with, wait for a table instead", keyspace, shard)
+		}
+		sd, err := callTablet(ctx, "GetSchema", idempotent, func(ctx context.Context) (*tabletmanagerdatapb.SchemaDefinition, error) {
+			return wr.tmc.GetSchema(ctx, primary.Tablet, &tabletmanagerdatapb.GetSchemaRequest{IncludeViews: true, TableSchemaOnly: true})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the schema of primary %v: %v", primary.AliasString(), err)
+		}
+		expectedHash = schemaHash(sd)
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		rec     concurrency.AllErrorRecorder
+		sem     = make(chan struct{}, maxConcurrency)
+		results []*SchemaReloadResult
+	)
+	for _, ti := range tablets {
+		if !includePrimary && ti.Type == topodatapb.TabletType_PRIMARY {
+			continue
+		}
+		wg.Add(1)
+		go func(tablet *topodatapb.Tablet) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result, err := wr.reloadSchemaAndWait(ctx, tablet, table, expectedHash, timeout)
+			if err != nil {
+				result.Error = err.Error()
+				rec.RecordError(err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, result)
+		}(ti.Tablet)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].TabletAlias < results[j].TabletAlias
+	})
+	return results, rec.Error()
+}
+
+// reloadSchemaAndWait is the implementation of ReloadSchemaAndWait. A
+// non-empty expectedHash is waited for like a table.
+func (wr *Wrangler) reloadSchemaAndWait(ctx context.Context, tablet *topodatapb.Tablet, table, expectedHash string, timeout time.Duration) (*SchemaReloadResult, error) {
+	alias := topoproto.TabletAliasString(tablet.Alias)
+	result := &SchemaReloadResult{TabletAlias: alias}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req := &tabletmanagerdatapb.GetSchemaRequest{IncludeViews: true, TableSchemaOnly: true}
+	var before string
+	if table == "" && expectedHash == "" {
+		sd, err := callTablet(ctx, "GetSchema", idempotent, func(ctx context.Context) (*tabletmanagerdatapb.SchemaDefinition, error) {
+			return wr.tmc.GetSchema(ctx, tablet, req)
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to read the schema of %v before reloading it: %v", alias, err)
+		}
+		before = schemaHash(sd)
+	}
+	if _, err := callTablet(ctx, "ReloadSchema", idempotent, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, wr.tmc.ReloadSchema(ctx, tablet, "")
+	}); err != nil {
+		return result, fmt.Errorf("failed to reload the schema of %v: %v", alias, err)
+	}
+
+	ticker := time.NewTicker(schemaReloadPollInterval)
+	defer ticker.Stop()
+	for {
+		sd, err := callTablet(ctx, "GetSchema", idempotent, func(ctx context.Context) (*tabletmanagerdatapb.SchemaDefinition, error) {
+			return wr.tmc.GetSchema(ctx, tablet, req)
+		})
+		if err == nil {
+			result.SchemaHash = schemaHash(sd)
+			switch {
+			case table != "":
+				_, result.Confirmed = tmutils.SchemaDefinitionGetTable(sd, table)
+			case expectedHash != "":
+				result.Confirmed = result.SchemaHash == expectedHash
+			default:
+				result.Changed = result.SchemaHash != before
+				result.Confirmed = true
+			}
+			if result.Confirmed {
+				return result, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return result, fmt.Errorf("failed to read the schema of %v: %v", alias, err)
+			}
+			if table != "" {
+				return result, fmt.Errorf("table %v did not show in the schema of %v within %v", table, alias, timeout)
+			}
+			return result, fmt.Errorf("schema of %v did not match the one of the primary within %v", alias, timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// schemaHash returns a digest of the table definitions in sd, which the
+// tablet already normalized.
+func schemaHash(sd *tabletmanagerdatapb.SchemaDefinition) string {
+	tds := slices.Clone(sd.TableDefinitions)
+	sort.Slice(tds, func(i, j int) bool {
+		return tds[i].Name < tds[j].Name
+	})
+	h := sha256.New()
+	for _, td := range tds {
+		fmt.Fprintf(h, "%s\n%s\n", td.Name, td.Schema)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 // applySQLShard applies a given SQL change on a given tablet alias. It allows executing arbitrary
 // SQL statements, but doesn't return any results, so it's only useful for SQL statements
 // that would be run for their effects (e.g., CREATE).
@@ -295,11 +500,13 @@ func (wr *Wrangler) applySQLShard(ctx context.Context, tabletInfo *topo.TabletIn
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 	// Need to make sure that replication is enabled since we're only applying the statement on primaries
-	_, err = wr.tmc.ApplySchema(ctx, tabletInfo.Tablet, &tmutils.SchemaChange{
-		SQL:              filledChange,
-		Force:            false,
-		AllowReplication: true,
-		SQLMode:          vreplication.SQLMode,
+	_, err = callTablet(ctx, "ApplySchema", notIdempotent, func(ctx context.Context) (*tabletmanagerdatapb.SchemaChangeResult, error) {
+		return wr.tmc.ApplySchema(ctx, tabletInfo.Tablet, &tmutils.SchemaChange{
+			SQL:              filledChange,
+			Force:            false,
+			AllowReplication: true,
+			SQLMode:          vreplication.SQLMode,
+		})
 	})
 	return err
 }
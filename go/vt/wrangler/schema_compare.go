@@ -0,0 +1,377 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"vitess.io/vitess/go/sets"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+// partitioningRegexp matches the partitioning clause that ends the output
+// of SHOW CREATE TABLE for a partitioned table.
+var partitioningRegexp = regexp.MustCompile(`(?s)\n(/\*!\d+ )?PARTITION BY .*$`)
+
+// CompareSchemasOptions are the options of CompareSchemasAcrossKeyspaces.
+type CompareSchemasOptions struct {
+	// Tables are the tables to compare. If empty, the tables replicated by
+	// Workflow are compared.
+	Tables []string
+	// Workflow is the workflow of the target keyspace whose tables are
+	// compared when Tables is empty.
+	Workflow string
+	// IgnoreExtraTargetColumns ignores the columns that only exist on the
+	// target, such as the ones added for VReplication.
+	IgnoreExtraTargetColumns bool
+	// IgnorePartitioning ignores the partitioning of the tables.
+	IgnorePartitioning bool
+}
+
+// TableSchemaComparison is the comparison of the definition of a table on
+// the reference source shard with its definition on a target shard.
+type TableSchemaComparison struct {
+	Table       string
+	SourceShard string
+	TargetShard string
+	Match       bool
+	// Missing is "source" or "target" when the table doesn't exist on that
+	// side.
+	Missing string `json:",omitempty"`
+	// Diff is the unified diff from the source definition to the target
+	// definition, after normalization.
+	Diff string `json:",omitempty"`
+}
+
+// SchemaComparisonReport is the result of CompareSchemasAcrossKeyspaces.
+type SchemaComparisonReport struct {
+	SourceKeyspace string
+	TargetKeyspace string
+	Tables         []*TableSchemaComparison
+}
+
+// Mismatches returns the comparisons that didn't match.
+func (r *SchemaComparisonReport) Mismatches() []*TableSchemaComparison {
+	var mismatches []*TableSchemaComparison
+	for _, table := range r.Tables {
+		if !table.Match {
+			mismatches = append(mismatches, table)
+		}
+	}
+	return mismatches
+}
+
+// CompareSchemasAcrossKeyspaces compares the definitions of tables on the
+// primary of the first shard of the source keyspace with their definitions
+// on the primaries of all the shards of the target keyspace, as done before
+// cutting over a MoveTables workflow. The database names are normalized
+// away, and the differences are reported as unified diffs.
+func (wr *Wrangler) CompareSchemasAcrossKeyspaces(ctx context.Context, sourceKeyspace, targetKeyspace string, options CompareSchemasOptions) (*SchemaComparisonReport, error) {
+	tables := options.Tables
+	if len(tables) == 0 {
+		if options.Workflow == "" {
+			return nil, fmt.Errorf("either the tables or the workflow to compare the schemas of must be specified")
+		}
+		var err error
+		if tables, err = wr.workflowTables(ctx, targetKeyspace, options.Workflow); err != nil {
+			return nil, err
+		}
+	}
+
+	sourcePrimaries, err := wr.keyspacePrimaries(ctx, sourceKeyspace)
+	if err != nil {
+		return nil, err
+	}
+	targetPrimaries, err := wr.keyspacePrimaries(ctx, targetKeyspace)
+	if err != nil {
+		return nil, err
+	}
+	source := sourcePrimaries[0]
+	sourceDefinitions, err := wr.tableDefinitions(ctx, source, tables, options.IgnorePartitioning)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SchemaComparisonReport{
+		SourceKeyspace: sourceKeyspace,
+		TargetKeyspace: targetKeyspace,
+	}
+	for _, target := range targetPrimaries {
+		targetDefinitions, err := wr.tableDefinitions(ctx, target, tables, options.IgnorePartitioning)
+		if err != nil {
+			return nil, err
+		}
+		for _, table := range tables {
+			comparison := &TableSchemaComparison{
+				Table:       table,
+				SourceShard: source.Shard,
+				TargetShard: target.Shard,
+			}
+			report.Tables = append(report.Tables, comparison)
+			sourceLines, inSource := sourceDefinitions[table]
+			targetLines, inTarget := targetDefinitions[table]
+			switch {
+			case !inSource:
+				comparison.Missing = "source"
+				continue
+			case !inTarget:
+				comparison.Missing = "target"
+				continue
+			}
+			if options.IgnoreExtraTargetColumns {
+				targetLines = withoutExtraColumns(targetLines, sourceLines)
+			}
+			comparison.Diff = unifiedDiff(
+				fmt.Sprintf("%s/%s %s", sourceKeyspace, source.Shard, table),
+				fmt.Sprintf("%s/%s %s", targetKeyspace, target.Shard, table),
+				sourceLines, targetLines)
+			comparison.Match = comparison.Diff == ""
+		}
+	}
+	return report, nil
+}
+
+// workflowTables returns the tables replicated by the streams of the workflow.
+func (wr *Wrangler) workflowTables(ctx context.Context, keyspace, workflow string) ([]string, error) {
+	results, err := wr.runVexec(ctx, workflow, keyspace, "select source from _vt.vreplication", nil, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	tables := sets.New[string]()
+	for _, result := range results {
+		for _, row := range sqltypes.Proto3ToResult(result).Rows {
+			var bls binlogdatapb.BinlogSource
+			if err := prototext.Unmarshal(row[0].Raw(), &bls); err != nil {
+				return nil, err
+			}
+			for _, rule := range bls.GetFilter().GetRules() {
+				if strings.HasPrefix(rule.Match, "/") {
+					return nil, fmt.Errorf("workflow %s replicates the tables matching %s, the tables to compare must be specified", workflow, rule.Match)
+				}
+				tables.Insert(rule.Match)
+			}
+		}
+	}
+	if tables.Len() == 0 {
+		return nil, fmt.Errorf("no tables found for workflow %s in keyspace %s", workflow, keyspace)
+	}
+	return sets.List(tables), nil
+}
+
+// keyspacePrimaries returns the primaries of the shards of the keyspace,
+// sorted by shard name. Every shard must have a primary.
+func (wr *Wrangler) keyspacePrimaries(ctx context.Context, keyspace string) ([]*topo.TabletInfo, error) {
+	shards, err := wr.ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("no shards in keyspace %s", keyspace)
+	}
+	sort.Strings(shards)
+	primaries := make([]*topo.TabletInfo, 0, len(shards))
+	for _, shard := range shards {
+		si, err := wr.ts.GetShard(ctx, keyspace, shard)
+		if err != nil {
+			return nil, err
+		}
+		if !si.HasPrimary() {
+			return nil, fmt.Errorf("no primary in shard %v/%v", keyspace, shard)
+		}
+		primary, err := wr.ts.GetTablet(ctx, si.PrimaryAlias)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read primary %v of shard %v/%v: %v", topoproto.TabletAliasString(si.PrimaryAlias), keyspace, shard, err)
+		}
+		primaries = append(primaries, primary)
+	}
+	return primaries, nil
+}
+
+// tableDefinitions returns the normalized definitions of the tables on the
+// tablet, split in lines, by table name. Missing tables are not returned.
+func (wr *Wrangler) tableDefinitions(ctx context.Context, ti *topo.TabletInfo, tables []string, ignorePartitioning bool) (map[string][]string, error) {
+	req := &tabletmanagerdatapb.GetSchemaRequest{Tables: tables}
+	sd, err := callTablet(ctx, "GetSchema", idempotent, func(ctx context.Context) (*tabletmanagerdatapb.SchemaDefinition, error) {
+		return wr.tmc.GetSchema(ctx, ti.Tablet, req)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the schema of %v: %v", ti.AliasString(), err)
+	}
+	dbName := topoproto.TabletDbName(ti.Tablet)
+	definitions := make(map[string][]string, len(sd.TableDefinitions))
+	for _, td := range sd.TableDefinitions {
+		definitions[td.Name] = normalizeTableSchema(td.Schema, dbName, ignorePartitioning)
+	}
+	return definitions, nil
+}
+
+// normalizeTableSchema returns the lines of the CREATE TABLE statement,
+// without the qualifications by the database name, the commas ending the
+// lines, and optionally the partitioning.
+func normalizeTableSchema(schema, dbName string, ignorePartitioning bool) []string {
+	schema = strings.ReplaceAll(schema, "`"+dbName+"`.", "")
+	if ignorePartitioning {
+		schema = partitioningRegexp.ReplaceAllString(schema, "")
+	}
+	lines := strings.Split(strings.TrimSpace(schema), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(strings.TrimRight(line, " \t"), ",")
+	}
+	return lines
+}
+
+// columnName returns the name of the column defined by the line of a CREATE
+// TABLE statement, or false if the line doesn't define a column.
+func columnName(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "`") {
+		return "", false
+	}
+	end := strings.Index(line[1:], "`")
+	if end < 0 {
+		return "", false
+	}
+	return line[1 : end+1], true
+}
+
+// withoutExtraColumns returns the target lines without the definitions of
+// the columns the source doesn't have.
+func withoutExtraColumns(targetLines, sourceLines []string) []string {
+	sourceColumns := sets.New[string]()
+	for _, line := range sourceLines {
+		if name, ok := columnName(line); ok {
+			sourceColumns.Insert(name)
+		}
+	}
+	lines := make([]string, 0, len(targetLines))
+	for _, line := range targetLines {
+		if name, ok := columnName(line); ok && !sourceColumns.Has(name) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// unifiedDiff returns the differences between the lines of a and b in the
+// unified diff format, with three lines of context, or an empty string if
+// they are the same.
+func unifiedDiff(aName, bName string, a, b []string) string {
+	const contextLines = 3
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type edit struct {
+		op   byte
+		line string
+		// ai and bi are the indexes in a and b before the edit.
+		ai, bi int
+	}
+	var edits []edit
+	changed := false
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{' ', a[i], i, j})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', a[i], i, j})
+			changed = true
+			i++
+		default:
+			edits = append(edits, edit{'+', b[j], i, j})
+			changed = true
+			j++
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", aName, bName)
+	next := 0
+	for next < len(edits) {
+		first := next
+		for first < len(edits) && edits[first].op == ' ' {
+			first++
+		}
+		if first == len(edits) {
+			break
+		}
+		// Extend the hunk until the unchanged lines are too many to be the
+		// context of both the last change and the next one.
+		last := first
+		for k := first; k < len(edits) && k-last <= 2*contextLines; k++ {
+			if edits[k].op != ' ' {
+				last = k
+			}
+		}
+		start := max(first-contextLines, next)
+		end := min(last+contextLines+1, len(edits))
+
+		aCount, bCount := 0, 0
+		for _, e := range edits[start:end] {
+			if e.op != '+' {
+				aCount++
+			}
+			if e.op != '-' {
+				bCount++
+			}
+		}
+		aStart, bStart := edits[start].ai+1, edits[start].bi+1
+		if aCount == 0 {
+			aStart--
+		}
+		if bCount == 0 {
+			bStart--
+		}
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+		for _, e := range edits[start:end] {
+			fmt.Fprintf(&sb, "%c%s\n", e.op, e.line)
+		}
+		next = end
+	}
+	return sb.String()
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// schemaCompareTMClient returns the table definitions of each tablet, and
+// the streams of the workflow.
+type schemaCompareTMClient struct {
+	tmclient.TabletManagerClient
+
+	schemas map[uint32]map[string]string
+	streams *sqltypes.Result
+}
+
+func (tmc *schemaCompareTMClient) GetSchema(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetSchemaRequest) (*tabletmanagerdatapb.SchemaDefinition, error) {
+	sd := &tabletmanagerdatapb.SchemaDefinition{}
+	for _, table := range request.Tables {
+		if schema, ok := tmc.schemas[tablet.Alias.Uid][table]; ok {
+			sd.TableDefinitions = append(sd.TableDefinitions, &tabletmanagerdatapb.TableDefinition{Name: table, Schema: schema})
+		}
+	}
+	return sd, nil
+}
+
+func (tmc *schemaCompareTMClient) VReplicationExec(ctx context.Context, tablet *topodatapb.Tablet, query string) (*querypb.QueryResult, error) {
+	if query != "select source from _vt.vreplication where db_name = 'vt_target' and workflow = 'wf'" {
+		return nil, fmt.Errorf("unexpected query %q", query)
+	}
+	return sqltypes.ResultToProto3(tmc.streams), nil
+}
+
+func TestCompareSchemasAcrossKeyspaces(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	const t1 = "CREATE TABLE `t1` (\n  `id` bigint NOT NULL,\n  `val` varchar(64) DEFAULT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB"
+	const t2 = "CREATE TABLE `t2` (\n  `id` bigint NOT NULL,\n  `t1_id` bigint NOT NULL,\n  PRIMARY KEY (`id`),\n  CONSTRAINT `fk` FOREIGN KEY (`t1_id`) REFERENCES `vt_source`.`t1` (`id`)\n) ENGINE=InnoDB"
+	tmc := &schemaCompareTMClient{
+		schemas: map[uint32]map[string]string{
+			100: {"t1": t1, "t2": t2},
+			200: {
+				"t1": "CREATE TABLE `t1` (\n  `id` bigint NOT NULL,\n  `val` varchar(64) DEFAULT NULL,\n  `_vt_extra` int DEFAULT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB\n/*!50100 PARTITION BY HASH (`id`)\nPARTITIONS 4 */",
+				"t2": "CREATE TABLE `t2` (\n  `id` bigint NOT NULL,\n  `t1_id` bigint NOT NULL,\n  PRIMARY KEY (`id`),\n  CONSTRAINT `fk` FOREIGN KEY (`t1_id`) REFERENCES `vt_target`.`t1` (`id`)\n) ENGINE=InnoDB",
+			},
+			210: {"t1": t1},
+		},
+		streams: sqltypes.MakeTestResult(sqltypes.MakeTestFields("source", "varchar"),
+			`keyspace:"source" shard:"0" filter:{rules:{match:"t1"} rules:{match:"t2"}}`),
+	}
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmc)
+
+	addPrimary := func(uid uint32, keyspace, shard string) {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: keyspace,
+			Shard:    shard,
+			Type:     topodatapb.TabletType_PRIMARY,
+		}
+		err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+		_, err = ts.UpdateShardFields(ctx, keyspace, shard, func(si *topo.ShardInfo) error {
+			si.PrimaryAlias = tablet.Alias
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	addPrimary(100, "source", "0")
+	addPrimary(200, "target", "-80")
+	addPrimary(210, "target", "80-")
+
+	// The tables of the workflow are compared, and the database names in
+	// the foreign key are normalized away.
+	report, err := wr.CompareSchemasAcrossKeyspaces(ctx, "source", "target", CompareSchemasOptions{Workflow: "wf"})
+	require.NoError(t, err)
+	require.Len(t, report.Tables, 4)
+	require.Len(t, report.Mismatches(), 2)
+	t1Diff := report.Tables[0]
+	require.Equal(t, "t1", t1Diff.Table)
+	require.Equal(t, "-80", t1Diff.TargetShard)
+	require.False(t, t1Diff.Match)
+	require.Equal(t, "--- source/0 t1\n+++ target/-80 t1\n@@ -1,5 +1,8 @@\n CREATE TABLE `t1` (\n   `id` bigint NOT NULL\n   `val` varchar(64) DEFAULT NULL\n+  `_vt_extra` int DEFAULT NULL\n   PRIMARY KEY (`id`)\n ) ENGINE=InnoDB\n+/*!50100 PARTITION BY HASH (`id`)\n+PARTITIONS 4 */\n", t1Diff.Diff)
+	require.True(t, report.Tables[1].Match)
+	require.Equal(t, "t2", report.Tables[1].Table)
+	require.True(t, report.Tables[2].Match)
+	require.Equal(t, &TableSchemaComparison{Table: "t2", SourceShard: "0", TargetShard: "80-", Missing: "target"}, report.Tables[3])
+
+	// The extra column and the partitioning can be ignored.
+	report, err = wr.CompareSchemasAcrossKeyspaces(ctx, "source", "target", CompareSchemasOptions{
+		Tables:                   []string{"t1"},
+		IgnoreExtraTargetColumns: true,
+		IgnorePartitioning:       true,
+	})
+	require.NoError(t, err)
+	require.Len(t, report.Tables, 2)
+	require.Empty(t, report.Mismatches())
+
+	_, err = wr.CompareSchemasAcrossKeyspaces(ctx, "source", "target", CompareSchemasOptions{})
+	require.Error(t, err)
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}
+	b := []string{"1", "two", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13"}
+	require.Equal(t, "", unifiedDiff("a", "b", a, a))
+	require.Equal(t, "--- a\n+++ b\n@@ -1,5 +1,5 @@\n 1\n-2\n+two\n 3\n 4\n 5\n@@ -10,3 +10,4 @@\n 10\n 11\n 12\n+13\n", unifiedDiff("a", "b", a, b))
+	require.Equal(t, "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+1\n", unifiedDiff("a", "b", nil, []string{"1"}))
+}
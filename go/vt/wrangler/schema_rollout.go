@@ -0,0 +1,500 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/schemamanager"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// schemaRolloutsPath is where the staged schema rollouts are recorded in
+// the global topo, one file per rollout ID.
+const schemaRolloutsPath = "schema_rollouts"
+
+// schemaRolloutPollInterval is how often the health of the tablets of a
+// stage is checked while it soaks.
+var schemaRolloutPollInterval = 10 * time.Second
+
+// The states of a SchemaRollout.
+const (
+	// SchemaRolloutRunning is the state of a rollout while an invocation
+	// applies its stages.
+	SchemaRolloutRunning = "running"
+	// SchemaRolloutPaused is the state of a rollout waiting to be resumed,
+	// either for confirmation of its next stage, or after a failed stage.
+	SchemaRolloutPaused = "paused"
+	// SchemaRolloutDone is the state of a rollout applied to all its shards.
+	SchemaRolloutDone = "done"
+	// SchemaRolloutAborted is the state of an aborted rollout. The shards
+	// it already changed keep the change.
+	SchemaRolloutAborted = "aborted"
+)
+
+// SchemaRolloutOptions are the options of ApplySchemaStaged.
+type SchemaRolloutOptions struct {
+	DdlStrategy         string
+	WaitReplicasTimeout time.Duration
+	// CanaryShards are the shards to change first. If empty, the first
+	// CanaryPercent percent of the shards are, or only the first shard if
+	// CanaryPercent is zero too.
+	CanaryShards  []string
+	CanaryPercent int
+	// BatchSize is the number of shards of each stage after the canary
+	// stage. All the other shards are changed in one stage if it is zero.
+	BatchSize int
+	// SoakTime is how long the tablets of each stage are checked after the
+	// change, before the rollout moves on.
+	SoakTime time.Duration
+	// MaxReplicationLag is the replication lag above which a tablet fails
+	// the check of its stage. It is not checked if zero.
+	MaxReplicationLag time.Duration
+	// AutoPromote moves on to the next stage once a stage soaked. Otherwise
+	// the rollout pauses after each stage, until it is resumed.
+	AutoPromote bool
+}
+
+// SchemaRolloutStage is a set of shards a rollout changes together.
+type SchemaRolloutStage struct {
+	Shards  []string
+	Applied time.Time `json:",omitempty"`
+	Soaked  time.Time `json:",omitempty"`
+	// UUIDs are the migrations of the stage, for an online DDL strategy.
+	UUIDs []string `json:",omitempty"`
+}
+
+// SchemaRollout is a schema change applied to the shards of a keyspace in
+// stages: the canary shards first, then the others in batches. Each stage
+// soaks while the health of its tablets is checked. It is recorded in the
+// global topo so that a separate invocation can resume or abort it.
+type SchemaRollout struct {
+	ID       string
+	Keyspace string
+	Sql      []string
+	SchemaRolloutOptions
+	Stages []*SchemaRolloutStage
+	// NextStage is the index of the next stage to apply or soak.
+	NextStage int
+	State     string
+	// Error is why the rollout paused, if a stage failed.
+	Error   string `json:",omitempty"`
+	Created time.Time
+	Updated time.Time
+
+	version topo.Version
+}
+
+// migrationContext is the migration context of all the stages, which groups
+// their online DDL migrations.
+func (rollout *SchemaRollout) migrationContext() string {
+	return "vtctl:rollout:" + rollout.ID
+}
+
+// ApplySchemaStaged records a new staged rollout of the schema change to the
+// keyspace, and runs it as far as it goes: up to the end of the canary
+// stage, or to the end if options.AutoPromote is set. The rollout is
+// returned with the error of the stage that failed, if any, and can be
+// resumed with ResumeSchemaRollout.
+func (wr *Wrangler) ApplySchemaStaged(ctx context.Context, keyspace string, sql []string, options SchemaRolloutOptions) (*SchemaRollout, error) {
+	if err := wr.CheckWritable("ApplySchemaStaged"); err != nil {
+		return nil, err
+	}
+	if len(sql) == 0 {
+		return nil, operationErrorf(vtrpcpb.Code_INVALID_ARGUMENT, keyspaceObject(keyspace), "no schema change to apply to keyspace %v", keyspace)
+	}
+	ddlStrategy, err := schema.ParseDDLStrategy(options.DdlStrategy)
+	if err != nil {
+		return nil, operationErrorf(vtrpcpb.Code_INVALID_ARGUMENT, keyspaceObject(keyspace), "invalid ddl strategy %q: %v", options.DdlStrategy, err)
+	}
+	shards, err := wr.ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return nil, topoError(err, keyspaceObject(keyspace), "cannot get the shards of keyspace %v", keyspace)
+	}
+	sort.Strings(shards)
+	stages, err := schemaRolloutStages(keyspace, shards, options)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	rollout := &SchemaRollout{
+		ID:                   uuid.NewString(),
+		Keyspace:             keyspace,
+		Sql:                  sql,
+		SchemaRolloutOptions: options,
+		Stages:               stages,
+		State:                SchemaRolloutRunning,
+		Created:              now,
+		Updated:              now,
+	}
+	if !ddlStrategy.Strategy.IsDirect() {
+		// All the stages submit the same migrations, so that they can be
+		// followed with the usual online DDL commands.
+		for _, stage := range rollout.Stages {
+			stage.UUIDs = make([]string, len(sql))
+		}
+		for i := range sql {
+			id, err := schema.CreateOnlineDDLUUID()
+			if err != nil {
+				return nil, err
+			}
+			for _, stage := range rollout.Stages {
+				stage.UUIDs[i] = id
+			}
+		}
+	}
+	if err := wr.createSchemaRollout(ctx, rollout); err != nil {
+		return nil, err
+	}
+	wr.Logger().Infof("Started schema rollout %v of keyspace %v in %d stages", rollout.ID, keyspace, len(rollout.Stages))
+	return rollout, wr.runSchemaRollout(ctx, rollout)
+}
+
+// schemaRolloutStages splits the shards into the canary stage and the
+// batches after it.
+func schemaRolloutStages(keyspace string, shards []string, options SchemaRolloutOptions) ([]*SchemaRolloutStage, error) {
+	if len(shards) == 0 {
+		return nil, operationErrorf(vtrpcpb.Code_FAILED_PRECONDITION, keyspaceObject(keyspace), "keyspace %v has no shards", keyspace)
+	}
+	var canary []string
+	switch {
+	case len(options.CanaryShards) > 0:
+		for _, shard := range options.CanaryShards {
+			if !slices.Contains(shards, shard) {
+				return nil, operationErrorf(vtrpcpb.Code_INVALID_ARGUMENT, keyspaceObject(keyspace), "canary shard %v is not a shard of keyspace %v", shard, keyspace)
+			}
+		}
+		canary = options.CanaryShards
+	case options.CanaryPercent < 0 || options.CanaryPercent > 100:
+		return nil, operationErrorf(vtrpcpb.Code_INVALID_ARGUMENT, keyspaceObject(keyspace), "the canary percent must be between 0 and 100, not %d", options.CanaryPercent)
+	case options.CanaryPercent > 0:
+		n := (len(shards)*options.CanaryPercent + 99) / 100
+		canary = shards[:n]
+	default:
+		canary = shards[:1]
+	}
+	if options.BatchSize < 0 {
+		return nil, operationErrorf(vtrpcpb.Code_INVALID_ARGUMENT, keyspaceObject(keyspace), "the batch size cannot be negative")
+	}
+
+	stages := []*SchemaRolloutStage{{Shards: canary}}
+	var rest []string
+	for _, shard := range shards {
+		if !slices.Contains(canary, shard) {
+			rest = append(rest, shard)
+		}
+	}
+	for len(rest) > 0 {
+		n := len(rest)
+		if options.BatchSize > 0 && options.BatchSize < n {
+			n = options.BatchSize
+		}
+		stages = append(stages, &SchemaRolloutStage{Shards: rest[:n]})
+		rest = rest[n:]
+	}
+	return stages, nil
+}
+
+// ResumeSchemaRollout runs a paused rollout from its next stage, as far as
+// ApplySchemaStaged would. With force, a rollout left running by an
+// invocation that died is resumed too. If that invocation is in fact still
+// running, it stops at its next save, since the record changed under it.
+func (wr *Wrangler) ResumeSchemaRollout(ctx context.Context, id string, force bool) (*SchemaRollout, error) {
+	if err := wr.CheckWritable("ResumeSchemaRollout"); err != nil {
+		return nil, err
+	}
+	rollout, err := wr.GetSchemaRollout(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rollout.State == SchemaRolloutRunning && !force {
+		return rollout, operationErrorf(vtrpcpb.Code_FAILED_PRECONDITION, schemaRolloutObject(id), "schema rollout %v is running since %v, resume it with --force only if the invocation running it is gone", id, rollout.Updated.Format(time.RFC3339))
+	}
+	if rollout.State != SchemaRolloutPaused && rollout.State != SchemaRolloutRunning {
+		return rollout, operationErrorf(vtrpcpb.Code_FAILED_PRECONDITION, schemaRolloutObject(id), "schema rollout %v is %v, only a paused rollout can be resumed", id, rollout.State)
+	}
+	rollout.State = SchemaRolloutRunning
+	rollout.Error = ""
+	if err := wr.saveSchemaRollout(ctx, rollout); err != nil {
+		return rollout, err
+	}
+	wr.Logger().Infof("Resuming schema rollout %v of keyspace %v at stage %d of %d", id, rollout.Keyspace, rollout.NextStage+1, len(rollout.Stages))
+	return rollout, wr.runSchemaRollout(ctx, rollout)
+}
+
+// AbortSchemaRollout aborts a rollout that is not done, so that it can't be
+// resumed. A running rollout stops after its current step. The shards the
+// rollout already changed keep the change.
+func (wr *Wrangler) AbortSchemaRollout(ctx context.Context, id string) (*SchemaRollout, error) {
+	if err := wr.CheckWritable("AbortSchemaRollout"); err != nil {
+		return nil, err
+	}
+	for {
+		rollout, err := wr.GetSchemaRollout(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		switch rollout.State {
+		case SchemaRolloutDone, SchemaRolloutAborted:
+			return rollout, operationErrorf(vtrpcpb.Code_FAILED_PRECONDITION, schemaRolloutObject(id), "schema rollout %v is already %v", id, rollout.State)
+		}
+		rollout.State = SchemaRolloutAborted
+		err = wr.saveSchemaRollout(ctx, rollout)
+		if topo.IsErrType(err, topo.BadVersion) {
+			// The running invocation just saved its progress.
+			continue
+		}
+		return rollout, err
+	}
+}
+
+// GetSchemaRollout reads the record of a rollout.
+func (wr *Wrangler) GetSchemaRollout(ctx context.Context, id string) (*SchemaRollout, error) {
+	conn, err := wr.ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return nil, topoError(err, schemaRolloutObject(id), "cannot connect to the global topo")
+	}
+	data, version, err := conn.Get(ctx, path.Join(schemaRolloutsPath, id))
+	if err != nil {
+		return nil, topoError(err, schemaRolloutObject(id), "cannot read schema rollout %v", id)
+	}
+	rollout := &SchemaRollout{}
+	if err := json.Unmarshal(data, rollout); err != nil {
+		return nil, fmt.Errorf("cannot parse schema rollout %v: %v", id, err)
+	}
+	rollout.version = version
+	return rollout, nil
+}
+
+func (wr *Wrangler) createSchemaRollout(ctx context.Context, rollout *SchemaRollout) error {
+	conn, err := wr.ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return topoError(err, schemaRolloutObject(rollout.ID), "cannot connect to the global topo")
+	}
+	data, err := json.Marshal(rollout)
+	if err != nil {
+		return err
+	}
+	rollout.version, err = conn.Create(ctx, path.Join(schemaRolloutsPath, rollout.ID), data)
+	if err != nil {
+		return topoError(err, schemaRolloutObject(rollout.ID), "cannot record schema rollout %v", rollout.ID)
+	}
+	return nil
+}
+
+// saveSchemaRollout updates the record of the rollout, unless it changed
+// since it was read, in which case a BadVersion error is returned.
+func (wr *Wrangler) saveSchemaRollout(ctx context.Context, rollout *SchemaRollout) error {
+	conn, err := wr.ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return topoError(err, schemaRolloutObject(rollout.ID), "cannot connect to the global topo")
+	}
+	rollout.Updated = time.Now()
+	data, err := json.Marshal(rollout)
+	if err != nil {
+		return err
+	}
+	version, err := conn.Update(ctx, path.Join(schemaRolloutsPath, rollout.ID), data, rollout.version)
+	if err != nil {
+		return topoError(err, schemaRolloutObject(rollout.ID), "cannot save schema rollout %v", rollout.ID)
+	}
+	rollout.version = version
+	return nil
+}
+
+// runSchemaRollout applies and soaks the stages of a running rollout from
+// its next stage. It pauses the rollout before each stage after the canary
+// one unless it auto-promotes, and on the first stage that fails. It stops
+// if the rollout was aborted meanwhile.
+func (wr *Wrangler) runSchemaRollout(ctx context.Context, rollout *SchemaRollout) error {
+	// save records the progress of the rollout, even if ctx is done.
+	save := func() error {
+		saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), topo.RemoteOperationTimeout)
+		defer cancel()
+		err := wr.saveSchemaRollout(saveCtx, rollout)
+		if !topo.IsErrType(err, topo.BadVersion) {
+			return err
+		}
+		current, getErr := wr.GetSchemaRollout(saveCtx, rollout.ID)
+		if getErr == nil && current.State == SchemaRolloutAborted {
+			*rollout = *current
+			return operationErrorf(vtrpcpb.Code_ABORTED, schemaRolloutObject(rollout.ID), "schema rollout %v was aborted", rollout.ID)
+		}
+		return err
+	}
+	pause := func(err error) error {
+		rollout.State = SchemaRolloutPaused
+		rollout.Error = err.Error()
+		if saveErr := save(); saveErr != nil {
+			return saveErr
+		}
+		wr.Logger().Errorf("Paused schema rollout %v at stage %d of %d, run ApplySchemaStaged --resume=%v to retry it, or --abort=%v: %v",
+			rollout.ID, rollout.NextStage+1, len(rollout.Stages), rollout.ID, rollout.ID, err)
+		return err
+	}
+
+	for rollout.NextStage < len(rollout.Stages) {
+		stage := rollout.Stages[rollout.NextStage]
+		stageName := fmt.Sprintf("stage %d of %d (shards %v)", rollout.NextStage+1, len(rollout.Stages), strings.Join(stage.Shards, ","))
+		if stage.Applied.IsZero() {
+			wr.Logger().Infof("Schema rollout %v: applying %v", rollout.ID, stageName)
+			if err := wr.applySchemaRolloutStage(ctx, rollout, stage); err != nil {
+				return pause(operationErrorf(vtrpcpb.Code_ABORTED, keyspaceObject(rollout.Keyspace), "cannot apply %v: %v", stageName, err))
+			}
+			stage.Applied = time.Now()
+			if err := save(); err != nil {
+				return err
+			}
+		}
+
+		wr.Logger().Infof("Schema rollout %v: soaking %v for %v", rollout.ID, stageName, rollout.SoakTime)
+		if err := wr.soakSchemaRolloutStage(ctx, rollout, stage); err != nil {
+			return pause(operationErrorf(vtrpcpb.Code_FAILED_PRECONDITION, keyspaceObject(rollout.Keyspace), "%v failed its health check: %v", stageName, err))
+		}
+		stage.Soaked = time.Now()
+		rollout.NextStage++
+
+		if rollout.NextStage < len(rollout.Stages) && !rollout.AutoPromote {
+			rollout.State = SchemaRolloutPaused
+			if err := save(); err != nil {
+				return err
+			}
+			wr.Logger().Printf("Schema rollout %v: %v is done, run ApplySchemaStaged --resume=%v to apply the next stage, or --abort=%v\n", rollout.ID, stageName, rollout.ID, rollout.ID)
+			return nil
+		}
+		if err := save(); err != nil {
+			return err
+		}
+	}
+
+	rollout.State = SchemaRolloutDone
+	if err := save(); err != nil {
+		return err
+	}
+	wr.Logger().Printf("Schema rollout %v of keyspace %v is done\n", rollout.ID, rollout.Keyspace)
+	return nil
+}
+
+// applySchemaRolloutStage applies the schema change to the shards of the
+// stage.
+func (wr *Wrangler) applySchemaRolloutStage(ctx context.Context, rollout *SchemaRollout, stage *SchemaRolloutStage) error {
+	executor := schemamanager.NewTabletExecutor(rollout.migrationContext(), wr.ts, wr.tmc, wr.Logger(), rollout.WaitReplicasTimeout, 0, wr.SQLParser())
+	if err := executor.SetDDLStrategy(rollout.DdlStrategy); err != nil {
+		return err
+	}
+	if len(stage.UUIDs) > 0 {
+		if err := executor.SetUUIDList(stage.UUIDs); err != nil {
+			return err
+		}
+	}
+	executor.SetShards(stage.Shards)
+	_, err := schemamanager.Run(ctx, schemamanager.NewPlainController(rollout.Sql, rollout.Keyspace), executor)
+	return err
+}
+
+// soakSchemaRolloutStage checks the health of the serving tablets of the
+// shards of the stage every poll interval until the soak time elapsed, at
+// least once. A tablet fails the check if its health can't be read, if it
+// reports a health error, or if its replication lag is above the maximum.
+// The health stream carries no query error rate, so errors of the queries
+// served by the tablets are not checked.
+func (wr *Wrangler) soakSchemaRolloutStage(ctx context.Context, rollout *SchemaRollout, stage *SchemaRolloutStage) error {
+	deadline := time.Now().Add(rollout.SoakTime)
+	for {
+		if err := wr.checkSchemaRolloutStage(ctx, rollout, stage); err != nil {
+			return err
+		}
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return nil
+		}
+		if wait > schemaRolloutPollInterval {
+			wait = schemaRolloutPollInterval
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (wr *Wrangler) checkSchemaRolloutStage(ctx context.Context, rollout *SchemaRollout, stage *SchemaRolloutStage) error {
+	var problems []string
+	for _, shard := range stage.Shards {
+		tabletMap, err := wr.getTabletMapForShard(ctx, rollout.Keyspace, shard, nil, failOnUnreadableTablets)
+		if err != nil {
+			return err
+		}
+		aliases := make([]string, 0, len(tabletMap))
+		for alias := range tabletMap {
+			aliases = append(aliases, alias)
+		}
+		sort.Strings(aliases)
+		for _, alias := range aliases {
+			tablet := tabletMap[alias].Tablet
+			if !topo.IsInServingGraph(tablet.Type) {
+				continue
+			}
+			if problem := wr.checkSchemaRolloutTablet(ctx, rollout, tablet); problem != "" {
+				problems = append(problems, fmt.Sprintf("tablet %v %v", alias, problem))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%v", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// checkSchemaRolloutTablet returns why the tablet fails the check of its
+// stage, or an empty string.
+func (wr *Wrangler) checkSchemaRolloutTablet(ctx context.Context, rollout *SchemaRollout, tablet *topodatapb.Tablet) string {
+	shr, err := grpcvtctldserver.ReadTabletHealth(ctx, tablet, healthProbeTimeout)
+	if err != nil {
+		return fmt.Sprintf("cannot report its health: %v", err)
+	}
+	if healthError := shr.RealtimeStats.GetHealthError(); healthError != "" {
+		return fmt.Sprintf("reports a health error: %v", healthError)
+	}
+	lag := time.Duration(shr.RealtimeStats.GetReplicationLagSeconds()) * time.Second
+	if tablet.Type != topodatapb.TabletType_PRIMARY && rollout.MaxReplicationLag > 0 && lag > rollout.MaxReplicationLag {
+		return fmt.Sprintf("(%v) lags %v behind its primary, more than %v", topoproto.TabletTypeLString(tablet.Type), lag, rollout.MaxReplicationLag)
+	}
+	return ""
+}
+
+// schemaRolloutObject names a schema rollout in an OperationError.
+func schemaRolloutObject(id string) string {
+	return "schema rollout " + id
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+	"vitess.io/vitess/go/vt/vttablet/queryservice/fakes"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+	"vitess.io/vitess/go/vt/vttablet/tabletconntest"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// schemaChangeTMClient counts the schema changes applied to each shard.
+type schemaChangeTMClient struct {
+	tmclient.TabletManagerClient
+
+	mu      sync.Mutex
+	applied map[string]int
+}
+
+func (tmc *schemaChangeTMClient) ExecuteMultiFetchAsDba(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteMultiFetchAsDbaRequest) ([]*querypb.QueryResult, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	tmc.applied[tablet.Shard]++
+	return []*querypb.QueryResult{{}}, nil
+}
+
+func (tmc *schemaChangeTMClient) PrimaryPosition(ctx context.Context, tablet *topodatapb.Tablet) (string, error) {
+	return "", nil
+}
+
+func (tmc *schemaChangeTMClient) ReloadSchema(ctx context.Context, tablet *topodatapb.Tablet, waitPosition string) error {
+	return nil
+}
+
+func (tmc *schemaChangeTMClient) appliedShards() map[string]int {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	applied := make(map[string]int, len(tmc.applied))
+	for shard, n := range tmc.applied {
+		applied[shard] = n
+	}
+	return applied
+}
+
+// lagQueryService answers StreamHealth with a single health record with
+// the given replication lag.
+type lagQueryService struct {
+	queryservice.QueryService
+	lag uint32
+}
+
+func (qs *lagQueryService) StreamHealth(ctx context.Context, callback func(*querypb.StreamHealthResponse) error) error {
+	return callback(&querypb.StreamHealthResponse{
+		Serving:       true,
+		RealtimeStats: &querypb.RealtimeStats{ReplicationLagSeconds: qs.lag},
+	})
+}
+
+func (qs *lagQueryService) Close(ctx context.Context) error {
+	return nil
+}
+
+func TestApplySchemaStaged(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	oldPollInterval := schemaRolloutPollInterval
+	schemaRolloutPollInterval = 10 * time.Millisecond
+	defer func() { schemaRolloutPollInterval = oldPollInterval }()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	tmc := &schemaChangeTMClient{applied: map[string]int{}}
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmc)
+
+	var mu sync.Mutex
+	lags := map[uint32]uint32{}
+	setLag := func(uid, lag uint32) {
+		mu.Lock()
+		defer mu.Unlock()
+		lags[uid] = lag
+	}
+	dialerName := fmt.Sprintf("ApplySchemaStagedTest-%d", rand.IntN(1000000000))
+	tabletconn.RegisterDialer(dialerName, func(ctx context.Context, tablet *topodatapb.Tablet, failFast grpcclient.FailFast) (queryservice.QueryService, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return &lagQueryService{
+			QueryService: fakes.ErrorQueryService,
+			lag:          lags[tablet.Alias.Uid],
+		}, nil
+	})
+	tabletconntest.SetProtocol("go.vt.wrangler.tablet_test", dialerName)
+
+	for i, shard := range []string{"-40", "40-80", "80-c0", "c0-"} {
+		uid := uint32(100 * (i + 1))
+		for _, tablet := range []*topodatapb.Tablet{
+			{Alias: &topodatapb.TabletAlias{Cell: "cell1", Uid: uid}, Keyspace: "ks", Shard: shard, Type: topodatapb.TabletType_PRIMARY},
+			{Alias: &topodatapb.TabletAlias{Cell: "cell1", Uid: uid + 1}, Keyspace: "ks", Shard: shard, Type: topodatapb.TabletType_REPLICA},
+		} {
+			err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+			require.NoError(t, err)
+		}
+		_, err := ts.UpdateShardFields(ctx, "ks", shard, func(si *topo.ShardInfo) error {
+			si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "cell1", Uid: uid}
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	sql := []string{"create table t (id int primary key)"}
+	options := SchemaRolloutOptions{
+		DdlStrategy:       "direct",
+		CanaryShards:      []string{"40-80"},
+		BatchSize:         2,
+		SoakTime:          30 * time.Millisecond,
+		MaxReplicationLag: 10 * time.Second,
+	}
+
+	// The rollout pauses after the canary stage.
+	rollout, err := wr.ApplySchemaStaged(ctx, "ks", sql, options)
+	require.NoError(t, err)
+	require.Equal(t, SchemaRolloutPaused, rollout.State)
+	require.Equal(t, 1, rollout.NextStage)
+	require.Len(t, rollout.Stages, 3)
+	require.Equal(t, []string{"40-80"}, rollout.Stages[0].Shards)
+	require.Equal(t, []string{"-40", "80-c0"}, rollout.Stages[1].Shards)
+	require.Equal(t, []string{"c0-"}, rollout.Stages[2].Shards)
+	require.Equal(t, map[string]int{"40-80": 1}, tmc.appliedShards())
+
+	// A lagging replica fails the check of the next stage, which pauses the
+	// rollout.
+	setLag(101, 60)
+	rollout, err = wr.ResumeSchemaRollout(ctx, rollout.ID, false)
+	require.ErrorContains(t, err, "stage 2 of 3 (shards -40,80-c0) failed its health check: tablet cell1-0000000101 (replica) lags 1m0s behind its primary, more than 10s")
+	require.Equal(t, vtrpcpb.Code_FAILED_PRECONDITION, vterrors.Code(err))
+	require.Equal(t, SchemaRolloutPaused, rollout.State)
+	require.Equal(t, map[string]int{"40-80": 1, "-40": 1, "80-c0": 1}, tmc.appliedShards())
+
+	// The record in the topo is what a separate invocation resumes from.
+	recorded, err := wr.GetSchemaRollout(ctx, rollout.ID)
+	require.NoError(t, err)
+	require.Equal(t, SchemaRolloutPaused, recorded.State)
+	require.Equal(t, 1, recorded.NextStage)
+	require.False(t, recorded.Stages[1].Applied.IsZero())
+	require.Contains(t, recorded.Error, "lags 1m0s behind its primary")
+
+	// Once the replica caught up, the stage passes its check without being
+	// applied again.
+	setLag(101, 0)
+	rollout, err = wr.ResumeSchemaRollout(ctx, rollout.ID, false)
+	require.NoError(t, err)
+	require.Equal(t, SchemaRolloutPaused, rollout.State)
+	require.Equal(t, 2, rollout.NextStage)
+	require.Empty(t, rollout.Error)
+	require.Equal(t, map[string]int{"40-80": 1, "-40": 1, "80-c0": 1}, tmc.appliedShards())
+
+	rollout, err = wr.AbortSchemaRollout(ctx, rollout.ID)
+	require.NoError(t, err)
+	require.Equal(t, SchemaRolloutAborted, rollout.State)
+	_, err = wr.ResumeSchemaRollout(ctx, rollout.ID, false)
+	require.ErrorContains(t, err, fmt.Sprintf("schema rollout %v is aborted, only a paused rollout can be resumed", rollout.ID))
+	require.Equal(t, vtrpcpb.Code_FAILED_PRECONDITION, vterrors.Code(err))
+	require.NotContains(t, tmc.appliedShards(), "c0-")
+
+	// With auto-promote, the rollout goes through all the stages.
+	options.CanaryShards = nil
+	options.CanaryPercent = 50
+	options.BatchSize = 0
+	options.AutoPromote = true
+	rollout, err = wr.ApplySchemaStaged(ctx, "ks", sql, options)
+	require.NoError(t, err)
+	require.Equal(t, SchemaRolloutDone, rollout.State)
+	require.Len(t, rollout.Stages, 2)
+	require.Equal(t, []string{"-40", "40-80"}, rollout.Stages[0].Shards)
+	require.Equal(t, map[string]int{"40-80": 2, "-40": 2, "80-c0": 2, "c0-": 1}, tmc.appliedShards())
+
+	// A rollout left running by an invocation that died is only resumed
+	// with force.
+	options.AutoPromote = false
+	rollout, err = wr.ApplySchemaStaged(ctx, "ks", sql, options)
+	require.NoError(t, err)
+	require.Equal(t, SchemaRolloutPaused, rollout.State)
+	rollout.State = SchemaRolloutRunning
+	err = wr.saveSchemaRollout(ctx, rollout)
+	require.NoError(t, err)
+	_, err = wr.ResumeSchemaRollout(ctx, rollout.ID, false)
+	require.ErrorContains(t, err, fmt.Sprintf("schema rollout %v is running since", rollout.ID))
+	require.Equal(t, vtrpcpb.Code_FAILED_PRECONDITION, vterrors.Code(err))
+	rollout, err = wr.ResumeSchemaRollout(ctx, rollout.ID, true)
+	require.NoError(t, err)
+	require.Equal(t, SchemaRolloutDone, rollout.State)
+	require.Equal(t, map[string]int{"40-80": 3, "-40": 3, "80-c0": 3, "c0-": 2}, tmc.appliedShards())
+
+	_, err = wr.ApplySchemaStaged(ctx, "ks", sql, SchemaRolloutOptions{CanaryShards: []string{"c0-d0"}})
+	require.ErrorContains(t, err, "canary shard c0-d0 is not a shard of keyspace ks")
+}
@@ -18,12 +18,22 @@ package wrangler
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 func TestValidateSchemaShard(t *testing.T) {
@@ -69,9 +79,9 @@ func TestValidateSchemaShard(t *testing.T) {
 	}
 
 	// Schema Checks
-	err := tme.wr.ValidateSchemaShard(ctx, "ks", "-80", nil /*excludeTables*/, true /*includeViews*/, true /*includeVSchema*/)
+	err := tme.wr.ValidateSchemaShard(ctx, "ks", "-80", nil /*excludeTables*/, true /*includeViews*/, true /*includeVSchema*/, false /*includeNonServing*/)
 	require.NoError(t, err)
-	shouldErr := tme.wr.ValidateSchemaShard(ctx, "ks", "80-", nil /*excludeTables*/, true /*includeViews*/, true /*includeVSchema*/)
+	shouldErr := tme.wr.ValidateSchemaShard(ctx, "ks", "80-", nil /*excludeTables*/, true /*includeViews*/, true /*includeVSchema*/, false /*includeNonServing*/)
 	require.Contains(t, shouldErr.Error(), "ks/80- has tables that are not in the vschema:")
 
 	// VSchema Specific Checks
@@ -125,10 +135,138 @@ func TestValidateSchemaKeyspace(t *testing.T) {
 	}
 
 	// Schema Checks
-	err := tmePass.wr.ValidateSchemaKeyspace(ctx, "ks", nil /*excludeTables*/, true /*includeViews*/, true /*skipNoPrimary*/, true /*includeVSchema*/)
+	err := tmePass.wr.ValidateSchemaKeyspace(ctx, "ks", nil /*excludeTables*/, true /*includeViews*/, true /*skipNoPrimary*/, true /*includeVSchema*/, false /*includeNonServing*/, true /*includeNonServingShards*/)
 	require.NoError(t, err)
-	err = tmePass.wr.ValidateSchemaKeyspace(ctx, "ks", nil /*excludeTables*/, true /*includeViews*/, true /*skipNoPrimary*/, false /*includeVSchema*/)
+	err = tmePass.wr.ValidateSchemaKeyspace(ctx, "ks", nil /*excludeTables*/, true /*includeViews*/, true /*skipNoPrimary*/, false /*includeVSchema*/, false /*includeNonServing*/, true /*includeNonServingShards*/)
 	require.NoError(t, err)
-	shouldErr := tmeDiffs.wr.ValidateSchemaKeyspace(ctx, "ks", nil /*excludeTables*/, true /*includeViews*/, true /*skipNoPrimary*/, true /*includeVSchema*/)
+	shouldErr := tmeDiffs.wr.ValidateSchemaKeyspace(ctx, "ks", nil /*excludeTables*/, true /*includeViews*/, true /*skipNoPrimary*/, true /*includeVSchema*/, false /*includeNonServing*/, true /*includeNonServingShards*/)
 	require.Error(t, shouldErr)
 }
+
+// schemaReloadTMClient serves the tables of each tablet. Pending tables show
+// up after a reload, once the schema was read lag more times.
+type schemaReloadTMClient struct {
+	tmclient.TabletManagerClient
+
+	mu      sync.Mutex
+	tables  map[uint32][]string
+	pending map[uint32][]string
+	lag     map[uint32]int
+}
+
+func (tmc *schemaReloadTMClient) ReloadSchema(ctx context.Context, tablet *topodatapb.Tablet, waitPosition string) error {
+	return nil
+}
+
+func (tmc *schemaReloadTMClient) GetSchema(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetSchemaRequest) (*tabletmanagerdatapb.SchemaDefinition, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	uid := tablet.Alias.Uid
+	if len(tmc.pending[uid]) > 0 {
+		if tmc.lag[uid] == 0 {
+			tmc.tables[uid] = append(tmc.tables[uid], tmc.pending[uid]...)
+			tmc.pending[uid] = nil
+		} else {
+			tmc.lag[uid]--
+		}
+	}
+	sd := &tabletmanagerdatapb.SchemaDefinition{}
+	for _, table := range tmc.tables[uid] {
+		sd.TableDefinitions = append(sd.TableDefinitions, &tabletmanagerdatapb.TableDefinition{
+			Name:   table,
+			Schema: fmt.Sprintf("CREATE TABLE `%s` (`id` bigint)", table),
+		})
+	}
+	return sd, nil
+}
+
+func TestReloadSchemaAndWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	oldInterval := schemaReloadPollInterval
+	schemaReloadPollInterval = 10 * time.Millisecond
+	defer func() { schemaReloadPollInterval = oldInterval }()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	tmc := &schemaReloadTMClient{
+		tables: map[uint32][]string{
+			100: {"t1", "t2"},
+			101: {"t1"},
+			102: {"t1"},
+		},
+		pending: map[uint32][]string{
+			101: {"t2"},
+		},
+		lag: map[uint32]int{101: 3},
+	}
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmc)
+
+	for _, uid := range []uint32{100, 101, 102} {
+		tabletType := topodatapb.TabletType_REPLICA
+		if uid == 100 {
+			tabletType = topodatapb.TabletType_PRIMARY
+		}
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    "0",
+			Type:     tabletType,
+		}
+		err := ts.InitTablet(ctx, tablet, true /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+	}
+	_, err := ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}
+		return nil
+	})
+	require.NoError(t, err)
+
+	// 101 catches up with the primary after a few reads, 102 never does.
+	results, err := wr.ReloadSchemaShardAndWait(ctx, "ks", "0", "", false, 2, time.Second)
+	require.ErrorContains(t, err, "schema of cell1-0000000102 did not match the one of the primary within 1s")
+	require.Len(t, results, 2)
+	require.Equal(t, "cell1-0000000101", results[0].TabletAlias)
+	require.True(t, results[0].Confirmed)
+	require.Empty(t, results[0].Error)
+	require.Equal(t, "cell1-0000000102", results[1].TabletAlias)
+	require.False(t, results[1].Confirmed)
+	require.NotEmpty(t, results[1].Error)
+
+	primaryHash := schemaHash(&tabletmanagerdatapb.SchemaDefinition{TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+		{Name: "t2", Schema: "CREATE TABLE `t2` (`id` bigint)"},
+		{Name: "t1", Schema: "CREATE TABLE `t1` (`id` bigint)"},
+	}})
+	require.Equal(t, primaryHash, results[0].SchemaHash)
+
+	t.Run("table", func(t *testing.T) {
+		tmc.pending[102] = []string{"t3"}
+		tmc.lag[102] = 2
+		result, err := wr.ReloadSchemaAndWait(ctx, &topodatapb.TabletAlias{Cell: "cell1", Uid: 102}, "t3", time.Second)
+		require.NoError(t, err)
+		require.True(t, result.Confirmed)
+
+		result, err = wr.ReloadSchemaAndWait(ctx, &topodatapb.TabletAlias{Cell: "cell1", Uid: 102}, "t4", 50*time.Millisecond)
+		require.ErrorContains(t, err, "table t4 did not show in the schema of cell1-0000000102")
+		require.False(t, result.Confirmed)
+	})
+
+	t.Run("schema change", func(t *testing.T) {
+		tmc.pending[100] = []string{"t5"}
+		tmc.lag[100] = 1
+		result, err := wr.ReloadSchemaAndWait(ctx, &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}, "", time.Second)
+		require.NoError(t, err)
+		require.True(t, result.Confirmed)
+		require.True(t, result.Changed)
+		require.NotEqual(t, primaryHash, result.SchemaHash)
+	})
+
+	t.Run("unchanged schema", func(t *testing.T) {
+		// Reloading a schema that didn't change is not an error.
+		result, err := wr.ReloadSchemaAndWait(ctx, &topodatapb.TabletAlias{Cell: "cell1", Uid: 101}, "", 50*time.Millisecond)
+		require.NoError(t, err)
+		require.True(t, result.Confirmed)
+		require.False(t, result.Changed)
+		require.Equal(t, primaryHash, result.SchemaHash)
+	})
+}
@@ -19,11 +19,18 @@ package wrangler
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
 
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 // shard related methods for Wrangler
@@ -52,32 +59,35 @@ func (wr *Wrangler) UpdateSrvKeyspacePartitions(ctx context.Context, keyspace, s
 }
 
 // DeleteShard will do all the necessary changes in the topology server
-// to entirely remove a shard.
+// to entirely remove a shard. Its errors are OperationErrors: it fails with
+// FAILED_PRECONDITION if the shard is still serving or still has tablets,
+// and with ABORTED if it stopped after deleting some of the tablets.
 func (wr *Wrangler) DeleteShard(ctx context.Context, keyspace, shard string, recursive, evenIfServing bool) error {
+	object := shardObject(keyspace, shard)
 	// Read the Shard object. If it's not there, try to clean up
 	// the topology anyway.
 	shardInfo, err := wr.ts.GetShard(ctx, keyspace, shard)
 	if err != nil {
 		if topo.IsErrType(err, topo.NoNode) {
 			wr.Logger().Infof("Shard %v/%v doesn't seem to exist, cleaning up any potential leftover", keyspace, shard)
-			return wr.ts.DeleteShard(ctx, keyspace, shard)
+			return wrapError(wr.ts.DeleteShard(ctx, keyspace, shard), object)
 		}
-		return err
+		return topoError(err, object, "cannot read shard %v/%v", keyspace, shard)
 	}
 
 	servingCells, err := wr.ts.GetShardServingCells(ctx, shardInfo)
 	if err != nil {
-		return err
+		return topoError(err, object, "cannot get the serving cells of shard %v/%v", keyspace, shard)
 	}
 	// Check the Serving map for the shard, we don't want to
 	// remove a serving shard if not absolutely sure.
 	if !evenIfServing && len(servingCells) > 0 {
-		return fmt.Errorf("shard %v/%v is still serving, cannot delete it, use even_if_serving flag if needed", keyspace, shard)
+		return operationErrorf(vtrpcpb.Code_FAILED_PRECONDITION, object, "shard %v/%v is still serving, cannot delete it, use even_if_serving flag if needed", keyspace, shard)
 	}
 
 	cells, err := wr.ts.GetCellInfoNames(ctx)
 	if err != nil {
-		return err
+		return topoError(err, object, "cannot get the cells")
 	}
 
 	// Go through all the cells.
@@ -96,7 +106,7 @@ func (wr *Wrangler) DeleteShard(ctx context.Context, keyspace, shard string, rec
 			// try to delete them.
 			aliases, err = wr.ts.GetTabletAliasesByCell(ctx, cell)
 			if err != nil {
-				return fmt.Errorf("GetTabletsByCell(%v) failed: %v", cell, err)
+				return topoError(err, object, "GetTabletsByCell(%v) failed", cell)
 			}
 		case err == nil:
 			// We found a ShardReplication object. We
@@ -106,16 +116,17 @@ func (wr *Wrangler) DeleteShard(ctx context.Context, keyspace, shard string, rec
 				aliases[i] = n.TabletAlias
 			}
 		default:
-			return fmt.Errorf("GetShardReplication(%v, %v, %v) failed: %v", cell, keyspace, shard, err)
+			return topoError(err, object, "GetShardReplication(%v, %v, %v) failed", cell, keyspace, shard)
 		}
 
 		// Get the corresponding Tablet records. Note
-		// GetTabletMap ignores ErrNoNode, and it's good for
+		// getTabletMap ignores ErrNoNode, and it's good for
 		// our purpose, it means a tablet was deleted but is
-		// still referenced.
-		tabletMap, err := wr.ts.GetTabletMap(ctx, aliases, nil)
+		// still referenced. Any other error fails, as we
+		// could miss tablets to delete.
+		tabletMap, err := wr.getTabletMap(ctx, aliases, failOnUnreadableTablets)
 		if err != nil {
-			return fmt.Errorf("GetTabletMap() failed: %v", err)
+			return topoError(err, object, "GetTabletMap() failed")
 		}
 
 		// Remove the tablets that don't belong to our
@@ -129,7 +140,7 @@ func (wr *Wrangler) DeleteShard(ctx context.Context, keyspace, shard string, rec
 		// Now see if we need to DeleteTablet, and if we can, do it.
 		if len(tabletMap) > 0 {
 			if !recursive {
-				return fmt.Errorf("shard %v/%v still has %v tablets in cell %v; use -recursive or remove them manually", keyspace, shard, len(tabletMap), cell)
+				return operationErrorf(vtrpcpb.Code_FAILED_PRECONDITION, object, "shard %v/%v still has %v tablets in cell %v; use -recursive or remove them manually", keyspace, shard, len(tabletMap), cell)
 			}
 
 			wr.Logger().Infof("Deleting all tablets in shard %v/%v cell %v", keyspace, shard, cell)
@@ -147,12 +158,22 @@ func (wr *Wrangler) DeleteShard(ctx context.Context, keyspace, shard string, rec
 					//
 					// If the problem is temporary, or resolved externally, re-running
 					// DeleteShard will skip over tablets that were already deleted.
-					return fmt.Errorf("can't delete tablet %v: %v", tabletAlias, err)
+					return &OperationError{
+						Code:   vtrpcpb.Code_ABORTED,
+						Object: tabletObject(tabletInfo.Alias),
+						Err:    fmt.Errorf("can't delete tablet %v, re-run DeleteShard to resume: %w", tabletAlias, err),
+					}
 				}
 			}
 		}
 	}
 
+	// The tablets are deleted, so finish the deletion even if ctx is done by
+	// now, rather than leave the shard with the replication graphs of some
+	// cells only.
+	ctx, cancel := wr.cleanupContext(ctx)
+	defer cancel()
+
 	// Try to remove the replication graph and serving graph in each cell,
 	// regardless of its existence.
 	for _, cell := range cells {
@@ -161,7 +182,10 @@ func (wr *Wrangler) DeleteShard(ctx context.Context, keyspace, shard string, rec
 		}
 	}
 
-	return wr.ts.DeleteShard(ctx, keyspace, shard)
+	if err := wr.ts.DeleteShard(ctx, keyspace, shard); err != nil {
+		return topoError(err, object, "cannot delete shard %v/%v", keyspace, shard)
+	}
+	return nil
 }
 
 // SourceShardDelete will delete a SourceShard inside a shard, by index.
@@ -205,3 +229,304 @@ func (wr *Wrangler) SourceShardAdd(ctx context.Context, keyspace, shard string,
 
 	return nil
 }
+
+// ShardWritableResult is the outcome of SetShardWritable for one shard.
+type ShardWritableResult struct {
+	Keyspace string
+	Shard    string
+	Primary  string `json:",omitempty"`
+	// Changed is set if read_only was different before the change.
+	Changed bool
+	// Verified is set once @@global.read_only was read back from the
+	// primary and matched the requested state.
+	Verified bool
+	ReadOnly bool
+	Error    string `json:",omitempty"`
+}
+
+// SetShardWritable makes the primary of keyspace/shard read-only or
+// read-write, then reads @@global.read_only back from it to verify the
+// change took effect.
+func (wr *Wrangler) SetShardWritable(ctx context.Context, keyspace, shard string, writable bool) (*ShardWritableResult, error) {
+	result := &ShardWritableResult{
+		Keyspace: keyspace,
+		Shard:    shard,
+	}
+
+	si, err := wr.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return result, err
+	}
+	if !si.HasPrimary() {
+		return result, fmt.Errorf("shard %v/%v has no primary", keyspace, shard)
+	}
+	result.Primary = topoproto.TabletAliasString(si.PrimaryAlias)
+	ti, err := wr.ts.GetTablet(ctx, si.PrimaryAlias)
+	if err != nil {
+		return result, err
+	}
+	return result, wr.setPrimaryWritable(ctx, ti, writable, result)
+}
+
+// setPrimaryWritable is the implementation of SetShardWritable once the
+// primary of the shard of result is found.
+func (wr *Wrangler) setPrimaryWritable(ctx context.Context, ti *topo.TabletInfo, writable bool, result *ShardWritableResult) error {
+	readOnly, err := wr.getGlobalReadOnly(ctx, ti.Tablet)
+	if err != nil {
+		return err
+	}
+	result.Changed = readOnly == writable
+
+	if writable {
+		_, err = callTablet(ctx, "SetReadWrite", idempotent, func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, wr.tmc.SetReadWrite(ctx, ti.Tablet)
+		})
+	} else {
+		_, err = callTablet(ctx, "SetReadOnly", idempotent, func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, wr.tmc.SetReadOnly(ctx, ti.Tablet)
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set read_only=%v on primary %v of %v/%v: %v", !writable, result.Primary, result.Keyspace, result.Shard, err)
+	}
+
+	readOnly, err = wr.getGlobalReadOnly(ctx, ti.Tablet)
+	if err != nil {
+		return err
+	}
+	result.ReadOnly = readOnly
+	if readOnly == writable {
+		return fmt.Errorf("primary %v of %v/%v still has read_only=%v after the change", result.Primary, result.Keyspace, result.Shard, readOnly)
+	}
+	result.Verified = true
+	return nil
+}
+
+// SetKeyspaceWritable runs SetShardWritable on every shard of the keyspace,
+// at most maxConcurrency shards at a time. It returns one result per shard,
+// sorted by shard name, and an error if any shard failed.
+func (wr *Wrangler) SetKeyspaceWritable(ctx context.Context, keyspace string, writable bool, maxConcurrency int) ([]*ShardWritableResult, error) {
+	if maxConcurrency < 1 {
+		return nil, fmt.Errorf("concurrency must be at least 1, got %d", maxConcurrency)
+	}
+
+	var (
+		mu      sync.Mutex
+		byShard = make(map[string]*ShardWritableResult)
+	)
+	shardResults, err := wr.ForEachShardPrimary(ctx, keyspace, ShardPrimaryOptions{Concurrency: maxConcurrency}, func(ctx context.Context, shard string, primary *topo.TabletInfo) error {
+		result := &ShardWritableResult{
+			Keyspace: keyspace,
+			Shard:    shard,
+			Primary:  primary.AliasString(),
+		}
+		mu.Lock()
+		byShard[shard] = result
+		mu.Unlock()
+		return wr.setPrimaryWritable(ctx, primary, writable, result)
+	})
+	if shardResults == nil {
+		return nil, err
+	}
+
+	results := make([]*ShardWritableResult, len(shardResults))
+	for i, shardResult := range shardResults {
+		result, ok := byShard[shardResult.Shard]
+		if !ok {
+			// The primary of the shard couldn't be found.
+			result = &ShardWritableResult{
+				Keyspace: keyspace,
+				Shard:    shardResult.Shard,
+				Primary:  shardResult.Primary,
+			}
+		}
+		result.Error = shardResult.Error
+		results[i] = result
+	}
+	return results, err
+}
+
+// getGlobalReadOnly reads @@global.read_only from the tablet's mysqld.
+func (wr *Wrangler) getGlobalReadOnly(ctx context.Context, tablet *topodatapb.Tablet) (bool, error) {
+	qr, err := callTablet(ctx, "ExecuteFetchAsDba", notIdempotent, func(ctx context.Context) (*querypb.QueryResult, error) {
+		return wr.tmc.ExecuteFetchAsDba(ctx, tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:   []byte("select @@global.read_only"),
+			MaxRows: 1,
+		})
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to read @@global.read_only from %v: %v", topoproto.TabletAliasString(tablet.Alias), err)
+	}
+	result := sqltypes.Proto3ToResult(qr)
+	if len(result.Rows) != 1 || len(result.Rows[0]) != 1 {
+		return false, fmt.Errorf("unexpected result reading @@global.read_only from %v: %v", topoproto.TabletAliasString(tablet.Alias), result.Rows)
+	}
+	return result.Rows[0][0].ToBool()
+}
+
+// ReplicaMembership is a tablet found on only one side of a
+// ShardReplicaMembership report.
+type ReplicaMembership struct {
+	TabletAlias string
+	Type        string
+	// Address is the address the replica is connected from, for tablets
+	// attached to the primary's mysqld.
+	Address string `json:",omitempty"`
+}
+
+// ShardReplicaMembershipReport compares the replicas attached to the mysqld
+// of a shard primary with the replication graph of the shard.
+type ShardReplicaMembershipReport struct {
+	Keyspace string
+	Shard    string
+	Primary  string
+	// Attached are the tablets the replica addresses were mapped to.
+	Attached []string
+	// AttachedNotInGraph are replicating from the primary but missing from
+	// the replication graph of the shard.
+	AttachedNotInGraph []*ReplicaMembership
+	// InGraphNotAttached are in the replication graph of the shard but not
+	// replicating from the primary.
+	InGraphNotAttached []*ReplicaMembership
+	// Unmapped are replica addresses that don't belong to any known tablet.
+	Unmapped []string
+	// Ambiguous are replica addresses shared by several tablets, which are
+	// all counted as attached.
+	Ambiguous []string
+}
+
+// HasDiffs returns true if the replicas attached to the primary don't match
+// the replication graph.
+func (r *ShardReplicaMembershipReport) HasDiffs() bool {
+	return len(r.AttachedNotInGraph) > 0 || len(r.InGraphNotAttached) > 0 || len(r.Unmapped) > 0
+}
+
+// ShardReplicaMembership asks the primary of the shard which replicas are
+// attached to its mysqld, maps their addresses back to tablets using the
+// MySQL hostnames of the tablets of the shard, and diffs them with the
+// ShardReplication records of the shard in all cells. The tablets of other
+// shards are left out, as their mysqld may run on the same host as one of
+// the shard.
+func (wr *Wrangler) ShardReplicaMembership(ctx context.Context, keyspace, shard string) (*ShardReplicaMembershipReport, error) {
+	si, err := wr.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	if !si.HasPrimary() {
+		return nil, fmt.Errorf("shard %v/%v has no primary", keyspace, shard)
+	}
+	primary, err := wr.ts.GetTablet(ctx, si.PrimaryAlias)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := callTablet(ctx, "GetReplicas", idempotent, func(ctx context.Context) ([]string, error) {
+		return wr.tmc.GetReplicas(ctx, primary.Tablet)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the replicas of primary %v: %v", primary.AliasString(), err)
+	}
+
+	cells, err := wr.ts.GetKnownCells(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tablets := make(map[string]*topodatapb.Tablet)
+	graph := make(map[string]bool)
+	for _, cell := range cells {
+		cellTablets, err := wr.ts.GetTabletsByCell(ctx, cell, &topo.GetTabletsByCellOptions{
+			KeyspaceShard: &topo.KeyspaceShard{Keyspace: keyspace, Shard: shard},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot list tablets in cell %v: %v", cell, err)
+		}
+		for _, ti := range cellTablets {
+			if ti.Keyspace == keyspace && ti.Shard == shard {
+				tablets[ti.AliasString()] = ti.Tablet
+			}
+		}
+
+		sri, err := wr.ts.GetShardReplication(ctx, cell, keyspace, shard)
+		switch {
+		case topo.IsErrType(err, topo.NoNode):
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("cannot read the replication graph of %v/%v in cell %v: %v", keyspace, shard, cell, err)
+		}
+		for _, node := range sri.Nodes {
+			graph[topoproto.TabletAliasString(node.TabletAlias)] = true
+		}
+	}
+	delete(graph, primary.AliasString())
+
+	// Map the resolved addresses of the mysqld of every tablet of the shard
+	// back to it.
+	byAddr := make(map[string][]string)
+	for alias, tablet := range tablets {
+		if alias == primary.AliasString() {
+			continue
+		}
+		host := tablet.MysqlHostname
+		if host == "" {
+			host = tablet.Hostname
+		}
+		ips, err := lookupHost(ctx, host)
+		if err != nil {
+			continue
+		}
+		for _, ip := range ips {
+			byAddr[ip] = append(byAddr[ip], alias)
+		}
+	}
+
+	report := &ShardReplicaMembershipReport{
+		Keyspace: keyspace,
+		Shard:    shard,
+		Primary:  primary.AliasString(),
+	}
+	attached := make(map[string]string)
+	for _, addr := range addrs {
+		aliases := byAddr[addr]
+		switch len(aliases) {
+		case 0:
+			report.Unmapped = append(report.Unmapped, addr)
+			continue
+		case 1:
+		default:
+			report.Ambiguous = append(report.Ambiguous, addr)
+		}
+		for _, alias := range aliases {
+			attached[alias] = addr
+		}
+	}
+
+	for alias, addr := range attached {
+		report.Attached = append(report.Attached, alias)
+		if !graph[alias] {
+			report.AttachedNotInGraph = append(report.AttachedNotInGraph, &ReplicaMembership{
+				TabletAlias: alias,
+				Type:        topoproto.TabletTypeLString(tablets[alias].Type),
+				Address:     addr,
+			})
+		}
+	}
+	for alias := range graph {
+		if _, ok := attached[alias]; ok {
+			continue
+		}
+		membership := &ReplicaMembership{TabletAlias: alias, Type: "unknown"}
+		if tablet, ok := tablets[alias]; ok {
+			membership.Type = topoproto.TabletTypeLString(tablet.Type)
+		}
+		report.InGraphNotAttached = append(report.InGraphNotAttached, membership)
+	}
+
+	sort.Strings(report.Attached)
+	sort.Strings(report.Unmapped)
+	sort.Strings(report.Ambiguous)
+	for _, memberships := range [][]*ReplicaMembership{report.AttachedNotInGraph, report.InGraphNotAttached} {
+		sort.Slice(memberships, func(i, j int) bool {
+			return memberships[i].TabletAlias < memberships[j].TabletAlias
+		})
+	}
+	return report, nil
+}
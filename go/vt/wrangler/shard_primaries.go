@@ -0,0 +1,149 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+)
+
+// ShardPrimaryOptions are the options of ForEachShardPrimary.
+type ShardPrimaryOptions struct {
+	// Concurrency is how many shards are processed at a time. Zero
+	// processes all of them at once.
+	Concurrency int
+	// ShardTimeout is how long the callback may take on each shard. Zero
+	// uses the shard timeout of the command.
+	ShardTimeout time.Duration
+	// SkipNoPrimary skips the shards without a primary, which are
+	// otherwise failed.
+	SkipNoPrimary bool
+	// RunWithoutPrimary runs the callback with a nil primary on the shards
+	// without a primary, for the callbacks that don't always need it,
+	// instead of failing them. It can't be set with SkipNoPrimary.
+	RunWithoutPrimary bool
+	// Shards are the shards to process, all the shards of the keyspace if
+	// empty.
+	Shards []string
+}
+
+// ShardPrimaryResult is the outcome of the callback of ForEachShardPrimary
+// on one shard.
+type ShardPrimaryResult struct {
+	Shard   string
+	Primary string `json:",omitempty"`
+	// Skipped is set for the shards without a primary, with SkipNoPrimary.
+	Skipped bool   `json:",omitempty"`
+	Error   string `json:",omitempty"`
+}
+
+// ForEachShardPrimary runs fn on the primary of every shard of the keyspace,
+// or of opts.Shards, with the options of opts, and returns one result per
+// shard, sorted by shard name whatever the order the shards were processed
+// in. A shard whose primary can't be found fails without running fn. The
+// error lists the errors of all the shards that failed, in shard order.
+func (wr *Wrangler) ForEachShardPrimary(ctx context.Context, keyspace string, opts ShardPrimaryOptions, fn func(ctx context.Context, shard string, primary *topo.TabletInfo) error) ([]*ShardPrimaryResult, error) {
+	if opts.Concurrency < 0 {
+		return nil, fmt.Errorf("concurrency must not be negative, got %d", opts.Concurrency)
+	}
+	if opts.SkipNoPrimary && opts.RunWithoutPrimary {
+		return nil, fmt.Errorf("the shards without a primary cannot be both skipped and run")
+	}
+	shards := slices.Clone(opts.Shards)
+	if len(shards) == 0 {
+		var err error
+		if shards, err = wr.ts.GetShardNames(ctx, keyspace); err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(shards)
+	shards = slices.Compact(shards)
+
+	limit := opts.Concurrency
+	if limit == 0 {
+		limit = max(len(shards), 1)
+	}
+	timeout := opts.ShardTimeout
+	if timeout <= 0 {
+		timeout = wr.Timeouts(ctx).Shard
+	}
+	var (
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, limit)
+		errs    = make([]error, len(shards))
+		results = make([]*ShardPrimaryResult, len(shards))
+	)
+	for i, shard := range shards {
+		results[i] = &ShardPrimaryResult{Shard: shard}
+		wg.Add(1)
+		go func(i int, result *ShardPrimaryResult) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			shardCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			errs[i] = wr.runOnShardPrimary(shardCtx, keyspace, result, opts, fn)
+			if errs[i] != nil {
+				result.Error = errs[i].Error()
+			}
+		}(i, results[i])
+	}
+	wg.Wait()
+
+	var rec concurrency.AllErrorRecorder
+	for _, err := range errs {
+		if err != nil {
+			rec.RecordError(err)
+		}
+	}
+	return results, rec.Error()
+}
+
+// runOnShardPrimary finds the primary of the shard of result and runs fn on
+// it, for ForEachShardPrimary.
+func (wr *Wrangler) runOnShardPrimary(ctx context.Context, keyspace string, result *ShardPrimaryResult, opts ShardPrimaryOptions, fn func(ctx context.Context, shard string, primary *topo.TabletInfo) error) error {
+	si, err := wr.ts.GetShard(ctx, keyspace, result.Shard)
+	if err != nil {
+		return err
+	}
+	if !si.HasPrimary() {
+		switch {
+		case opts.SkipNoPrimary:
+			result.Skipped = true
+			return nil
+		case opts.RunWithoutPrimary:
+			return fn(ctx, result.Shard, nil)
+		}
+		return fmt.Errorf("no primary in shard %v/%v", keyspace, result.Shard)
+	}
+	result.Primary = topoproto.TabletAliasString(si.PrimaryAlias)
+	primary, err := wr.ts.GetTablet(ctx, si.PrimaryAlias)
+	if err != nil {
+		return fmt.Errorf("cannot read primary %v of shard %v/%v: %v", result.Primary, keyspace, result.Shard, err)
+	}
+	return fn(ctx, result.Shard, primary)
+}
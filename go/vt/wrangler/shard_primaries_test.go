@@ -0,0 +1,153 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestForEachShardPrimary(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+	wr := New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, nil)
+
+	shards := []string{"-40", "40-80", "80-c0", "c0-"}
+	for i, shard := range shards {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uint32(100 * (i + 1))},
+			Keyspace: "ks",
+			Shard:    shard,
+			Type:     topodatapb.TabletType_PRIMARY,
+		}
+		require.NoError(t, ts.InitTablet(ctx, tablet, true /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/))
+		_, err := ts.UpdateShardFields(ctx, "ks", shard, func(si *topo.ShardInfo) error {
+			si.PrimaryAlias = tablet.Alias
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, ts.CreateShard(ctx, "ks", "00-10"))
+
+	t.Run("ordering and aggregation", func(t *testing.T) {
+		// The shards finish in reverse order, and two of them fail.
+		delays := map[string]time.Duration{"-40": 30 * time.Millisecond, "40-80": 20 * time.Millisecond, "80-c0": 10 * time.Millisecond}
+		results, err := wr.ForEachShardPrimary(ctx, "ks", ShardPrimaryOptions{}, func(ctx context.Context, shard string, primary *topo.TabletInfo) error {
+			time.Sleep(delays[shard])
+			if shard == "40-80" || shard == "c0-" {
+				return errors.New("failed on " + primary.AliasString())
+			}
+			return nil
+		})
+		require.EqualError(t, err, "no primary in shard ks/00-10;failed on cell1-0000000200;failed on cell1-0000000400")
+		require.Equal(t, []*ShardPrimaryResult{
+			{Shard: "-40", Primary: "cell1-0000000100"},
+			{Shard: "00-10", Error: "no primary in shard ks/00-10"},
+			{Shard: "40-80", Primary: "cell1-0000000200", Error: "failed on cell1-0000000200"},
+			{Shard: "80-c0", Primary: "cell1-0000000300"},
+			{Shard: "c0-", Primary: "cell1-0000000400", Error: "failed on cell1-0000000400"},
+		}, results)
+	})
+
+	t.Run("skip no primary", func(t *testing.T) {
+		results, err := wr.ForEachShardPrimary(ctx, "ks", ShardPrimaryOptions{SkipNoPrimary: true}, func(ctx context.Context, shard string, primary *topo.TabletInfo) error {
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 5)
+		require.Equal(t, &ShardPrimaryResult{Shard: "00-10", Skipped: true}, results[1])
+	})
+
+	t.Run("run without primary", func(t *testing.T) {
+		var (
+			mu   sync.Mutex
+			runs = map[string]string{}
+		)
+		results, err := wr.ForEachShardPrimary(ctx, "ks", ShardPrimaryOptions{RunWithoutPrimary: true, Shards: []string{"40-80", "00-10"}}, func(ctx context.Context, shard string, primary *topo.TabletInfo) error {
+			mu.Lock()
+			defer mu.Unlock()
+			runs[shard] = ""
+			if primary != nil {
+				runs[shard] = primary.AliasString()
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*ShardPrimaryResult{
+			{Shard: "00-10"},
+			{Shard: "40-80", Primary: "cell1-0000000200"},
+		}, results)
+		require.Equal(t, map[string]string{"00-10": "", "40-80": "cell1-0000000200"}, runs)
+
+		_, err = wr.ForEachShardPrimary(ctx, "ks", ShardPrimaryOptions{RunWithoutPrimary: true, SkipNoPrimary: true}, nil)
+		require.ErrorContains(t, err, "cannot be both skipped and run")
+	})
+
+	t.Run("concurrency", func(t *testing.T) {
+		var (
+			mu                sync.Mutex
+			running, maxCount int
+		)
+		_, err := wr.ForEachShardPrimary(ctx, "ks", ShardPrimaryOptions{Concurrency: 2, SkipNoPrimary: true}, func(ctx context.Context, shard string, primary *topo.TabletInfo) error {
+			mu.Lock()
+			running++
+			maxCount = max(maxCount, running)
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, maxCount)
+
+		_, err = wr.ForEachShardPrimary(ctx, "ks", ShardPrimaryOptions{Concurrency: -1}, nil)
+		require.ErrorContains(t, err, "concurrency must not be negative")
+	})
+
+	t.Run("shard timeout", func(t *testing.T) {
+		results, err := wr.ForEachShardPrimary(ctx, "ks", ShardPrimaryOptions{ShardTimeout: 10 * time.Millisecond, SkipNoPrimary: true}, func(ctx context.Context, shard string, primary *topo.TabletInfo) error {
+			if shard != "-40" {
+				return nil
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		require.EqualError(t, err, context.DeadlineExceeded.Error())
+		require.Equal(t, context.DeadlineExceeded.Error(), results[0].Error)
+		require.Empty(t, results[2].Error)
+	})
+
+	_, err := wr.ForEachShardPrimary(ctx, "missing", ShardPrimaryOptions{}, nil)
+	require.True(t, topo.IsErrType(err, topo.NoNode))
+}
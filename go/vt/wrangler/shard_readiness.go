@@ -0,0 +1,223 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// ShardReadinessThresholds are what ShardReadiness checks a shard against.
+type ShardReadinessThresholds struct {
+	// MinReplicas is the minimum number of serving replica tablets in each
+	// cell that has replica tablets of the shard. Zero disables the check.
+	MinReplicas int
+	// MaxLag is the maximum replication lag of the replica and rdonly
+	// tablets. Zero disables the check.
+	MaxLag time.Duration
+}
+
+// TabletReadiness is the state of a tablet in a ShardReadinessReport, from
+// its topo record and the health record it broadcast.
+type TabletReadiness struct {
+	TabletAlias           string
+	Type                  string
+	Serving               bool
+	ReplicationLagSeconds uint32 `json:",omitempty"`
+	// HealthError is the health error the tablet reported, or the error
+	// reading its health record.
+	HealthError string `json:",omitempty"`
+}
+
+// ShardReadinessReport says whether a shard is healthy enough for an
+// operation such as a reparent, a deploy or a backup.
+type ShardReadinessReport struct {
+	Keyspace       string
+	Shard          string
+	Primary        string
+	PrimaryServing bool
+	// ReplicasByCell counts the serving replica tablets of each cell that
+	// has replica tablets of the shard.
+	ReplicasByCell           map[string]int
+	MaxReplicationLagSeconds uint32
+	Restoring                []string
+	BackingUp                []string
+	Tablets                  []*TabletReadiness
+	// Findings are those of ValidateShard without pings, and of the
+	// comparison of the tablets' health records with their topo records.
+	Findings    []ValidationFinding
+	MinReplicas int
+	MaxLag      string `json:",omitempty"`
+	// Ready is the verdict, and Reasons say why the shard isn't ready.
+	Ready   bool
+	Reasons []string
+}
+
+// ShardReadiness combines the shard record, the replication graph of the
+// shard and the health records of its tablets into a single report, and
+// checks it against the thresholds. The shard is ready if its primary is
+// serving, there are no validation errors and the thresholds are met.
+// Validation warnings, and tablets restoring or backing up, are reported
+// without failing the verdict.
+func (wr *Wrangler) ShardReadiness(ctx context.Context, keyspace, shard string, thresholds ShardReadinessThresholds) (*ShardReadinessReport, error) {
+	si, err := wr.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	// The tablets that can't be read are left out of the report.
+	tabletMap, err := wr.getTabletMapForShard(ctx, keyspace, shard, nil, warnOnUnreadableTablets)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ShardReadinessReport{
+		Keyspace:       keyspace,
+		Shard:          shard,
+		ReplicasByCell: make(map[string]int),
+		MinReplicas:    thresholds.MinReplicas,
+	}
+	if thresholds.MaxLag > 0 {
+		report.MaxLag = thresholds.MaxLag.String()
+	}
+	if si.HasPrimary() {
+		report.Primary = topoproto.TabletAliasString(si.PrimaryAlias)
+	}
+
+	validation := NewValidationReport()
+	if err := wr.ValidateShard(ctx, keyspace, shard, false /*pingTablets*/, 0, 0, validation); err != nil && !errors.Is(err, errValidation) {
+		return nil, err
+	}
+
+	infos := make([]*topo.TabletInfo, 0, len(tabletMap))
+	for _, ti := range tabletMap {
+		tablet := &TabletReadiness{
+			TabletAlias: ti.AliasString(),
+			Type:        topoproto.TabletTypeLString(ti.Type),
+		}
+		infos = append(infos, ti)
+		report.Tablets = append(report.Tablets, tablet)
+		switch ti.Type {
+		case topodatapb.TabletType_REPLICA:
+			report.ReplicasByCell[ti.Alias.Cell] = 0
+		case topodatapb.TabletType_RESTORE:
+			report.Restoring = append(report.Restoring, tablet.TabletAlias)
+		case topodatapb.TabletType_BACKUP:
+			report.BackingUp = append(report.BackingUp, tablet.TabletAlias)
+		}
+	}
+
+	var (
+		mu             sync.Mutex
+		wg             sync.WaitGroup
+		healthResults  = make(map[string]string)
+		healthSeverity = make(map[string]vtctldatapb.ValidationFinding_Severity)
+	)
+	for i, ti := range infos {
+		wg.Add(1)
+		go func(ti *topo.TabletInfo, tablet *TabletReadiness) {
+			defer wg.Done()
+			shr, err := grpcvtctldserver.ReadTabletHealth(ctx, ti.Tablet, healthProbeTimeout)
+			result, severity := grpcvtctldserver.ServingStateResult(si, ti.Tablet, shr, err, healthProbeTimeout)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if result != "" {
+				healthResults[ti.AliasString()] = result
+				healthSeverity[ti.AliasString()] = severity
+			}
+			if err != nil {
+				tablet.HealthError = err.Error()
+				return
+			}
+			tablet.HealthError = shr.RealtimeStats.GetHealthError()
+			tablet.Serving = shr.Serving && tablet.HealthError == ""
+			addTabletReadiness(report, ti.Tablet, tablet, shr)
+		}(ti, report.Tablets[i])
+	}
+	wg.Wait()
+	sort.Slice(report.Tablets, func(i, j int) bool { return report.Tablets[i].TabletAlias < report.Tablets[j].TabletAlias })
+	sort.Strings(report.Restoring)
+	sort.Strings(report.BackingUp)
+
+	for _, tablet := range report.Tablets {
+		validation.RecordChecked(ValidationCheckServingState, tablet.TabletAlias)
+		if result, ok := healthResults[tablet.TabletAlias]; ok {
+			validation.RecordFinding(ValidationCheckServingState, healthSeverity[tablet.TabletAlias], keyspace, shard, tablet.TabletAlias, result)
+		}
+	}
+	validation.Finish()
+	report.Findings = validation.Findings
+
+	// The verdict.
+	switch {
+	case report.Primary == "":
+		report.Reasons = append(report.Reasons, fmt.Sprintf("shard %v/%v has no primary", keyspace, shard))
+	case !si.IsPrimaryServing:
+		report.Reasons = append(report.Reasons, fmt.Sprintf("the shard record of %v/%v says its primary is not serving", keyspace, shard))
+	case !report.PrimaryServing:
+		report.Reasons = append(report.Reasons, fmt.Sprintf("primary %v is not serving", report.Primary))
+	}
+	if thresholds.MinReplicas > 0 {
+		cells := make([]string, 0, len(report.ReplicasByCell))
+		for cell := range report.ReplicasByCell {
+			cells = append(cells, cell)
+		}
+		sort.Strings(cells)
+		for _, cell := range cells {
+			if n := report.ReplicasByCell[cell]; n < thresholds.MinReplicas {
+				report.Reasons = append(report.Reasons, fmt.Sprintf("only %d serving replica tablets in cell %v, fewer than the minimum of %d", n, cell, thresholds.MinReplicas))
+			}
+		}
+	}
+	if thresholds.MaxLag > 0 && time.Duration(report.MaxReplicationLagSeconds)*time.Second > thresholds.MaxLag {
+		report.Reasons = append(report.Reasons, fmt.Sprintf("replication lag of %ds exceeds the maximum of %v", report.MaxReplicationLagSeconds, thresholds.MaxLag))
+	}
+	if validation.Summary.Errors > 0 {
+		report.Reasons = append(report.Reasons, fmt.Sprintf("%d validation errors", validation.Summary.Errors))
+	}
+	report.Ready = len(report.Reasons) == 0
+	return report, nil
+}
+
+// addTabletReadiness adds what the health record of the tablet tells about
+// the shard to the report.
+func addTabletReadiness(report *ShardReadinessReport, tablet *topodatapb.Tablet, readiness *TabletReadiness, shr *querypb.StreamHealthResponse) {
+	switch tablet.Type {
+	case topodatapb.TabletType_PRIMARY:
+		if readiness.TabletAlias == report.Primary {
+			report.PrimaryServing = readiness.Serving
+		}
+	case topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY:
+		readiness.ReplicationLagSeconds = shr.RealtimeStats.GetReplicationLagSeconds()
+		report.MaxReplicationLagSeconds = max(report.MaxReplicationLagSeconds, readiness.ReplicationLagSeconds)
+		if tablet.Type == topodatapb.TabletType_REPLICA && readiness.Serving {
+			report.ReplicasByCell[tablet.Alias.Cell]++
+		}
+	}
+}
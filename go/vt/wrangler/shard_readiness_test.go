@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+	"vitess.io/vitess/go/vt/vttablet/queryservice/fakes"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+	"vitess.io/vitess/go/vt/vttablet/tabletconntest"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestShardReadiness(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
+	wr := New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, nil)
+
+	health := map[uint32]*querypb.StreamHealthResponse{}
+	dialerName := fmt.Sprintf("ShardReadinessTest-%d", rand.IntN(1000000000))
+	tabletconn.RegisterDialer(dialerName, func(ctx context.Context, tablet *topodatapb.Tablet, failFast grpcclient.FailFast) (queryservice.QueryService, error) {
+		shr, ok := health[tablet.Alias.Uid]
+		if !ok {
+			return nil, errors.New("connection refused")
+		}
+		return &healthQueryService{QueryService: fakes.ErrorQueryService, health: shr}, nil
+	})
+	tabletconntest.SetProtocol("go.vt.wrangler.shard_readiness_test", dialerName)
+
+	addTablet := func(cell string, uid uint32, tabletType topodatapb.TabletType, reachable bool, serving bool, lag uint32) {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: cell, Uid: uid},
+			Keyspace: "ks",
+			Shard:    "0",
+			Type:     tabletType,
+		}
+		err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+		if reachable {
+			health[uid] = &querypb.StreamHealthResponse{
+				Target:        &querypb.Target{Keyspace: "ks", Shard: "0", TabletType: tabletType},
+				Serving:       serving,
+				RealtimeStats: &querypb.RealtimeStats{ReplicationLagSeconds: lag},
+			}
+		}
+	}
+	addTablet("cell1", 100, topodatapb.TabletType_PRIMARY, true, true, 0)
+	addTablet("cell1", 101, topodatapb.TabletType_REPLICA, true, true, 2)
+	addTablet("cell1", 102, topodatapb.TabletType_REPLICA, true, true, 40)
+	addTablet("cell1", 103, topodatapb.TabletType_BACKUP, true, false, 0)
+	addTablet("cell2", 200, topodatapb.TabletType_REPLICA, false, false, 0)
+	addTablet("cell2", 201, topodatapb.TabletType_RESTORE, true, false, 0)
+	_, err := ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}
+		si.IsPrimaryServing = true
+		return nil
+	})
+	require.NoError(t, err)
+
+	report, err := wr.ShardReadiness(ctx, "ks", "0", ShardReadinessThresholds{MinReplicas: 1, MaxLag: 30 * time.Second})
+	require.NoError(t, err)
+	require.False(t, report.Ready)
+	require.Equal(t, "cell1-0000000100", report.Primary)
+	require.True(t, report.PrimaryServing)
+	require.Equal(t, map[string]int{"cell1": 2, "cell2": 0}, report.ReplicasByCell)
+	require.EqualValues(t, 40, report.MaxReplicationLagSeconds)
+	require.Equal(t, []string{"cell2-0000000201"}, report.Restoring)
+	require.Equal(t, []string{"cell1-0000000103"}, report.BackingUp)
+	require.Len(t, report.Tablets, 6)
+	require.Contains(t, report.Tablets[4].HealthError, "connection refused")
+	require.Len(t, report.Findings, 1)
+	require.Equal(t, ValidationWarning, report.Findings[0].Severity)
+	require.Contains(t, report.Findings[0].Message, "tablet cell2-0000000200 sent no health data")
+	require.Equal(t, []string{
+		"only 0 serving replica tablets in cell cell2, fewer than the minimum of 1",
+		"replication lag of 40s exceeds the maximum of 30s",
+	}, report.Reasons)
+
+	// Warnings, and tablets restoring or backing up, don't fail the verdict.
+	report, err = wr.ShardReadiness(ctx, "ks", "0", ShardReadinessThresholds{})
+	require.NoError(t, err)
+	require.True(t, report.Ready)
+	require.Empty(t, report.Reasons)
+
+	// A primary that isn't serving fails it, and doesn't match its topo
+	// records either.
+	health[100].Serving = false
+	report, err = wr.ShardReadiness(ctx, "ks", "0", ShardReadinessThresholds{})
+	require.NoError(t, err)
+	require.False(t, report.Ready)
+	require.Equal(t, []string{"primary cell1-0000000100 is not serving", "1 validation errors"}, report.Reasons)
+}
@@ -0,0 +1,362 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// ShardSnapshot is the state of a shard at a point in time, as collected by
+// SnapshotShardState for incident forensics.
+type ShardSnapshot struct {
+	Keyspace   string
+	Shard      string
+	CapturedAt time.Time
+	// CaptureDuration is how long the collection took: the records were
+	// read concurrently, within that window.
+	CaptureDuration time.Duration
+	ShardRecord     *topodatapb.Shard
+	// ShardReplications are the replication graphs of the shard, by cell.
+	ShardReplications map[string]*topodatapb.ShardReplication
+	// SrvKeyspaces are the serving graph entries of the keyspace, by cell.
+	SrvKeyspaces map[string]*topodatapb.SrvKeyspace
+	// Tablets are the tablets of the replication graphs, by alias.
+	Tablets map[string]*TabletSnapshot
+	// Errors are the records of the shard that couldn't be read.
+	Errors []string
+}
+
+// TabletSnapshot is the state of a tablet in a ShardSnapshot.
+type TabletSnapshot struct {
+	Tablet *topodatapb.Tablet
+	// ReplicationStatus is not collected for a primary.
+	ReplicationStatus *replicationdatapb.Status
+	FullStatus        *replicationdatapb.FullStatus
+	// Errors are the records and statuses of the tablet that couldn't be
+	// read, e.g. because it is down.
+	Errors []string
+}
+
+// snapshotProtoJSON marshals the records of a snapshot with the names of the
+// fields and enum values they have in the protos.
+var snapshotProtoJSON = protojson.MarshalOptions{UseProtoNames: true}
+
+// MarshalJSON is part of the json.Marshaler interface. The records are
+// marshaled as protos.
+func (s *ShardSnapshot) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Keyspace          string
+		Shard             string
+		CapturedAt        time.Time
+		CaptureDuration   string
+		ShardRecord       json.RawMessage `json:",omitempty"`
+		ShardReplications map[string]json.RawMessage
+		SrvKeyspaces      map[string]json.RawMessage
+		Tablets           map[string]*TabletSnapshot
+		Errors            []string `json:",omitempty"`
+	}{
+		Keyspace:          s.Keyspace,
+		Shard:             s.Shard,
+		CapturedAt:        s.CapturedAt,
+		CaptureDuration:   s.CaptureDuration.String(),
+		ShardReplications: make(map[string]json.RawMessage, len(s.ShardReplications)),
+		SrvKeyspaces:      make(map[string]json.RawMessage, len(s.SrvKeyspaces)),
+		Tablets:           s.Tablets,
+		Errors:            s.Errors,
+	}
+
+	var err error
+	if out.ShardRecord, err = marshalSnapshotProto(s.ShardRecord); err != nil {
+		return nil, err
+	}
+	for cell, sr := range s.ShardReplications {
+		if out.ShardReplications[cell], err = marshalSnapshotProto(sr); err != nil {
+			return nil, err
+		}
+	}
+	for cell, srvKeyspace := range s.SrvKeyspaces {
+		if out.SrvKeyspaces[cell], err = marshalSnapshotProto(srvKeyspace); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(out)
+}
+
+// MarshalJSON is part of the json.Marshaler interface. The records are
+// marshaled as protos.
+func (ts *TabletSnapshot) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Tablet            json.RawMessage `json:",omitempty"`
+		ReplicationStatus json.RawMessage `json:",omitempty"`
+		FullStatus        json.RawMessage `json:",omitempty"`
+		Errors            []string        `json:",omitempty"`
+	}{
+		Errors: ts.Errors,
+	}
+
+	var err error
+	if out.Tablet, err = marshalSnapshotProto(ts.Tablet); err != nil {
+		return nil, err
+	}
+	if out.ReplicationStatus, err = marshalSnapshotProto(ts.ReplicationStatus); err != nil {
+		return nil, err
+	}
+	if out.FullStatus, err = marshalSnapshotProto(ts.FullStatus); err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+// marshalSnapshotProto returns nil for a nil message, which omits it.
+func marshalSnapshotProto(m proto.Message) (json.RawMessage, error) {
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return nil, nil
+	}
+	return snapshotProtoJSON.Marshal(m)
+}
+
+// SnapshotShardState collects, concurrently, the shard record, the
+// replication graph and serving graph entries of every cell, the tablet
+// records, and the replication status and full status of each tablet. Only
+// a missing shard fails the snapshot: what else can't be read is recorded in
+// the Errors of the snapshot or of the tablet.
+func (wr *Wrangler) SnapshotShardState(ctx context.Context, keyspace, shard string) (*ShardSnapshot, error) {
+	start := time.Now()
+	si, err := wr.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	cells, err := wr.ts.GetCellInfoNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &ShardSnapshot{
+		Keyspace:          keyspace,
+		Shard:             shard,
+		CapturedAt:        start.UTC(),
+		ShardRecord:       si.Shard,
+		ShardReplications: make(map[string]*topodatapb.ShardReplication),
+		SrvKeyspaces:      make(map[string]*topodatapb.SrvKeyspace),
+		Tablets:           make(map[string]*TabletSnapshot),
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		aliases []*topodatapb.TabletAlias
+	)
+	recordError := func(format string, args ...any) {
+		mu.Lock()
+		defer mu.Unlock()
+		snapshot.Errors = append(snapshot.Errors, fmt.Sprintf(format, args...))
+	}
+	for _, cell := range cells {
+		wg.Add(2)
+		go func(cell string) {
+			defer wg.Done()
+			sri, err := wr.ts.GetShardReplication(ctx, cell, keyspace, shard)
+			switch {
+			case topo.IsErrType(err, topo.NoNode):
+			case err != nil:
+				recordError("cannot read the replication graph of cell %v: %v", cell, err)
+			default:
+				mu.Lock()
+				defer mu.Unlock()
+				snapshot.ShardReplications[cell] = sri.ShardReplication
+				for _, node := range sri.Nodes {
+					aliases = append(aliases, node.TabletAlias)
+				}
+			}
+		}(cell)
+		go func(cell string) {
+			defer wg.Done()
+			srvKeyspace, err := wr.ts.GetSrvKeyspace(ctx, cell, keyspace)
+			switch {
+			case topo.IsErrType(err, topo.NoNode):
+			case err != nil:
+				recordError("cannot read the serving graph of cell %v: %v", cell, err)
+			default:
+				mu.Lock()
+				defer mu.Unlock()
+				snapshot.SrvKeyspaces[cell] = srvKeyspace
+			}
+		}(cell)
+	}
+	wg.Wait()
+
+	tabletMap, readErrs, err := wr.ts.GetTabletMapWithErrors(ctx, aliases, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, readErr := range readErrs {
+		snapshot.Tablets[topoproto.TabletAliasString(readErr.Alias)] = &TabletSnapshot{
+			Errors: []string{fmt.Sprintf("cannot read the tablet record: %v", readErr.Err)},
+		}
+	}
+	for alias, ti := range tabletMap {
+		tablet := &TabletSnapshot{Tablet: ti.Tablet}
+		snapshot.Tablets[alias] = tablet
+
+		recordTabletError := func(format string, args ...any) {
+			mu.Lock()
+			defer mu.Unlock()
+			tablet.Errors = append(tablet.Errors, fmt.Sprintf(format, args...))
+		}
+		if ti.Type != topodatapb.TabletType_PRIMARY {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctx, cancel := wr.tabletContext(ctx)
+				defer cancel()
+				status, err := callTablet(ctx, "ReplicationStatus", idempotent, func(ctx context.Context) (*replicationdatapb.Status, error) {
+					return wr.tmc.ReplicationStatus(ctx, ti.Tablet)
+				})
+				if err != nil {
+					recordTabletError("cannot get the replication status: %v", err)
+					return
+				}
+				tablet.ReplicationStatus = status
+			}()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := wr.tabletContext(ctx)
+			defer cancel()
+			status, err := callTablet(ctx, "FullStatus", idempotent, func(ctx context.Context) (*replicationdatapb.FullStatus, error) {
+				return wr.tmc.FullStatus(ctx, ti.Tablet)
+			})
+			if err != nil {
+				recordTabletError("cannot get the full status: %v", err)
+				return
+			}
+			tablet.FullStatus = status
+		}()
+	}
+	wg.Wait()
+
+	sort.Strings(snapshot.Errors)
+	for _, tablet := range snapshot.Tablets {
+		sort.Strings(tablet.Errors)
+	}
+	snapshot.CaptureDuration = time.Since(start)
+	return snapshot, nil
+}
+
+// DiffShardSnapshots compares two snapshots written by SnapshotShardState,
+// in their JSON form, and returns a line for each value that was removed
+// ("-"), added ("+") or changed ("~") in after, in path order. The capture
+// times are not compared.
+func DiffShardSnapshots(before, after []byte) ([]string, error) {
+	beforeValues, err := flattenSnapshot(before)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the first snapshot: %v", err)
+	}
+	afterValues, err := flattenSnapshot(after)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the second snapshot: %v", err)
+	}
+
+	paths := make([]string, 0, len(beforeValues)+len(afterValues))
+	for path := range beforeValues {
+		paths = append(paths, path)
+	}
+	for path := range afterValues {
+		if _, ok := beforeValues[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var changes []string
+	for _, path := range paths {
+		beforeValue, inBefore := beforeValues[path]
+		afterValue, inAfter := afterValues[path]
+		switch {
+		case !inAfter:
+			changes = append(changes, fmt.Sprintf("- %v: %v", path, beforeValue))
+		case !inBefore:
+			changes = append(changes, fmt.Sprintf("+ %v: %v", path, afterValue))
+		case beforeValue != afterValue:
+			changes = append(changes, fmt.Sprintf("~ %v: %v -> %v", path, beforeValue, afterValue))
+		}
+	}
+	return changes, nil
+}
+
+// flattenSnapshot returns the values of a snapshot by path, e.g.
+// Tablets.zone1-0000000100.Tablet.type, without the capture times.
+func flattenSnapshot(data []byte) (map[string]string, error) {
+	var snapshot map[string]any
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	if _, ok := snapshot["Shard"]; !ok {
+		return nil, fmt.Errorf("not a shard snapshot")
+	}
+	delete(snapshot, "CapturedAt")
+	delete(snapshot, "CaptureDuration")
+
+	values := make(map[string]string)
+	flattenJSON("", snapshot, values)
+	return values, nil
+}
+
+func flattenJSON(path string, value any, values map[string]string) {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		if len(value) == 0 && path != "" {
+			values[path] = "{}"
+		}
+		for key, v := range value {
+			flattenJSON(join(key), v, values)
+		}
+	case []any:
+		if len(value) == 0 {
+			values[path] = "[]"
+		}
+		for i, v := range value {
+			flattenJSON(path+"["+strconv.Itoa(i)+"]", v, values)
+		}
+	default:
+		data, _ := json.Marshal(value)
+		values[path] = string(data)
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// snapshotTMClient returns statuses named after the tablet, except for the
+// tablets that are down.
+type snapshotTMClient struct {
+	tmclient.TabletManagerClient
+
+	mu   sync.Mutex
+	down map[uint32]bool
+}
+
+func (tmc *snapshotTMClient) isDown(tablet *topodatapb.Tablet) bool {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	return tmc.down[tablet.Alias.Uid]
+}
+
+func (tmc *snapshotTMClient) ReplicationStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.Status, error) {
+	if tmc.isDown(tablet) {
+		return nil, fmt.Errorf("tablet %v is down", tablet.Alias.Uid)
+	}
+	return &replicationdatapb.Status{Position: fmt.Sprintf("MySQL56/source:1-%d", tablet.Alias.Uid)}, nil
+}
+
+func (tmc *snapshotTMClient) FullStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.FullStatus, error) {
+	if tmc.isDown(tablet) {
+		return nil, fmt.Errorf("tablet %v is down", tablet.Alias.Uid)
+	}
+	return &replicationdatapb.FullStatus{ServerId: tablet.Alias.Uid}, nil
+}
+
+func TestSnapshotShardState(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
+	tmc := &snapshotTMClient{down: map[uint32]bool{200: true}}
+	wr := New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, tmc)
+
+	for _, tablet := range []*topodatapb.Tablet{
+		{Alias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}, Keyspace: "ks", Shard: "-80", Type: topodatapb.TabletType_PRIMARY},
+		{Alias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 101}, Keyspace: "ks", Shard: "-80", Type: topodatapb.TabletType_REPLICA},
+		{Alias: &topodatapb.TabletAlias{Cell: "cell2", Uid: 200}, Keyspace: "ks", Shard: "-80", Type: topodatapb.TabletType_REPLICA},
+	} {
+		err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+	}
+	_, err := ts.UpdateShardFields(ctx, "ks", "-80", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}
+		return nil
+	})
+	require.NoError(t, err)
+	err = ts.UpdateSrvKeyspace(ctx, "cell1", "ks", &topodatapb.SrvKeyspace{
+		Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{{
+			ServedType:      topodatapb.TabletType_PRIMARY,
+			ShardReferences: []*topodatapb.ShardReference{{Name: "-80"}},
+		}},
+	})
+	require.NoError(t, err)
+
+	before, err := wr.SnapshotShardState(ctx, "ks", "-80")
+	require.NoError(t, err)
+	require.Equal(t, uint32(100), before.ShardRecord.PrimaryAlias.Uid)
+	require.Len(t, before.ShardReplications["cell1"].Nodes, 2)
+	require.Len(t, before.ShardReplications["cell2"].Nodes, 1)
+	require.Contains(t, before.SrvKeyspaces, "cell1")
+	require.NotContains(t, before.SrvKeyspaces, "cell2")
+	require.Empty(t, before.Errors)
+	require.Len(t, before.Tablets, 3)
+
+	// The replication status of the primary isn't collected.
+	primary := before.Tablets["cell1-0000000100"]
+	require.Nil(t, primary.ReplicationStatus)
+	require.Equal(t, uint32(100), primary.FullStatus.ServerId)
+	require.Empty(t, primary.Errors)
+	replica := before.Tablets["cell1-0000000101"]
+	require.Equal(t, "MySQL56/source:1-101", replica.ReplicationStatus.Position)
+	require.Equal(t, uint32(101), replica.FullStatus.ServerId)
+	down := before.Tablets["cell2-0000000200"]
+	require.Equal(t, topodatapb.TabletType_REPLICA, down.Tablet.Type)
+	require.Equal(t, []string{
+		"cannot get the full status: tablet 200 is down",
+		"cannot get the replication status: tablet 200 is down",
+	}, down.Errors)
+
+	beforeJSON, err := json.Marshal(before)
+	require.NoError(t, err)
+	require.Contains(t, string(beforeJSON), `"type":"REPLICA"`)
+
+	// The tablet that was down is back, and the replica was drained.
+	tmc.mu.Lock()
+	delete(tmc.down, 200)
+	tmc.mu.Unlock()
+	_, err = ts.UpdateTabletFields(ctx, &topodatapb.TabletAlias{Cell: "cell1", Uid: 101}, func(tablet *topodatapb.Tablet) error {
+		tablet.Type = topodatapb.TabletType_DRAINED
+		return nil
+	})
+	require.NoError(t, err)
+
+	after, err := wr.SnapshotShardState(ctx, "ks", "-80")
+	require.NoError(t, err)
+	afterJSON, err := json.Marshal(after)
+	require.NoError(t, err)
+
+	changes, err := DiffShardSnapshots(beforeJSON, afterJSON)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`~ Tablets.cell1-0000000101.Tablet.type: "REPLICA" -> "DRAINED"`,
+		`- Tablets.cell2-0000000200.Errors[0]: "cannot get the full status: tablet 200 is down"`,
+		`- Tablets.cell2-0000000200.Errors[1]: "cannot get the replication status: tablet 200 is down"`,
+		`+ Tablets.cell2-0000000200.FullStatus.server_id: 200`,
+		`+ Tablets.cell2-0000000200.ReplicationStatus.position: "MySQL56/source:1-200"`,
+	}, changes)
+
+	// The capture times aren't compared.
+	changes, err = DiffShardSnapshots(afterJSON, afterJSON)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	_, err = DiffShardSnapshots(beforeJSON, []byte(`{"Keyspace": "ks"}`))
+	require.ErrorContains(t, err, "cannot read the second snapshot: not a shard snapshot")
+}
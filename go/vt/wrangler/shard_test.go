@@ -0,0 +1,332 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/event"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/events"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// readOnlyTMClient keeps the read_only state of each tablet, and serves it
+// to "select @@global.read_only". Tablets in stuck ignore SetReadWrite.
+type readOnlyTMClient struct {
+	tmclient.TabletManagerClient
+
+	mu       sync.Mutex
+	readOnly map[uint32]bool
+	stuck    map[uint32]bool
+}
+
+func (tmc *readOnlyTMClient) SetReadOnly(ctx context.Context, tablet *topodatapb.Tablet) error {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	tmc.readOnly[tablet.Alias.Uid] = true
+	return nil
+}
+
+func (tmc *readOnlyTMClient) SetReadWrite(ctx context.Context, tablet *topodatapb.Tablet) error {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	if !tmc.stuck[tablet.Alias.Uid] {
+		tmc.readOnly[tablet.Alias.Uid] = false
+	}
+	return nil
+}
+
+func (tmc *readOnlyTMClient) ExecuteFetchAsDba(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsDbaRequest) (*querypb.QueryResult, error) {
+	if string(req.Query) != "select @@global.read_only" {
+		return nil, fmt.Errorf("unexpected query %q", req.Query)
+	}
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	value := "0"
+	if tmc.readOnly[tablet.Alias.Uid] {
+		value = "1"
+	}
+	return sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("@@global.read_only", "int64"), value)), nil
+}
+
+func TestSetKeyspaceWritable(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	tmc := &readOnlyTMClient{
+		readOnly: map[uint32]bool{},
+		stuck:    map[uint32]bool{300: true},
+	}
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmc)
+
+	for i, shard := range []string{"-40", "40-80", "80-"} {
+		uid := uint32(100 * (i + 1))
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    shard,
+			Type:     topodatapb.TabletType_PRIMARY,
+		}
+		err := ts.InitTablet(ctx, tablet, true /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+		_, err = ts.UpdateShardFields(ctx, "ks", shard, func(si *topo.ShardInfo) error {
+			si.PrimaryAlias = tablet.Alias
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	err := ts.CreateShard(ctx, "ks", "no_primary")
+	require.NoError(t, err)
+	tmc.readOnly[100] = true
+
+	// All primaries but the one on -40 start out read-write.
+	results, err := wr.SetKeyspaceWritable(ctx, "ks", false, 2)
+	require.ErrorContains(t, err, "no primary in shard ks/no_primary")
+	require.Len(t, results, 4)
+	for _, result := range results[:3] {
+		require.Empty(t, result.Error, result.Shard)
+		require.True(t, result.Verified, result.Shard)
+		require.True(t, result.ReadOnly, result.Shard)
+		require.Equal(t, result.Shard != "-40", result.Changed, result.Shard)
+	}
+	require.Equal(t, "no_primary", results[3].Shard)
+	require.False(t, results[3].Verified)
+
+	// The primary of 80- doesn't actually become writable.
+	result, err := wr.SetShardWritable(ctx, "ks", "80-", true)
+	require.ErrorContains(t, err, "primary cell1-0000000300 of ks/80- still has read_only=true")
+	require.True(t, result.ReadOnly)
+	require.False(t, result.Verified)
+
+	result, err = wr.SetShardWritable(ctx, "ks", "40-80", true)
+	require.NoError(t, err)
+	require.Equal(t, &ShardWritableResult{
+		Keyspace: "ks",
+		Shard:    "40-80",
+		Primary:  "cell1-0000000200",
+		Changed:  true,
+		Verified: true,
+		ReadOnly: false,
+	}, result)
+
+	_, err = wr.SetKeyspaceWritable(ctx, "ks", true, 0)
+	require.ErrorContains(t, err, "concurrency must be at least 1")
+}
+
+// replicasTMClient returns fixed replica addresses from GetReplicas.
+type replicasTMClient struct {
+	tmclient.TabletManagerClient
+
+	replicas []string
+}
+
+func (tmc *replicasTMClient) GetReplicas(ctx context.Context, tablet *topodatapb.Tablet) ([]string, error) {
+	return tmc.replicas, nil
+}
+
+func TestShardReplicaMembership(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
+	tmc := &replicasTMClient{
+		replicas: []string{"10.0.0.1", "10.0.0.4", "10.0.0.5", "10.9.9.9"},
+	}
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmc)
+
+	oldLookupHost := lookupHost
+	lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		switch host {
+		case "r1":
+			return []string{"10.0.0.1"}, nil
+		case "r2":
+			return []string{"10.0.0.2"}, nil
+		case "r4":
+			return []string{"10.0.0.4"}, nil
+		case "shared":
+			return []string{"10.0.0.5"}, nil
+		}
+		return nil, fmt.Errorf("no such host %v", host)
+	}
+	defer func() { lookupHost = oldLookupHost }()
+
+	addShardTablet := func(shard, cell string, uid uint32, tabletType topodatapb.TabletType, mysqlHostname string) *topodatapb.TabletAlias {
+		tablet := &topodatapb.Tablet{
+			Alias:         &topodatapb.TabletAlias{Cell: cell, Uid: uid},
+			Hostname:      "vttablet",
+			MysqlHostname: mysqlHostname,
+			Keyspace:      "ks",
+			Shard:         shard,
+			Type:          tabletType,
+		}
+		err := ts.InitTablet(ctx, tablet, true /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+		return tablet.Alias
+	}
+	addTablet := func(cell string, uid uint32, tabletType topodatapb.TabletType, mysqlHostname string) *topodatapb.TabletAlias {
+		return addShardTablet("0", cell, uid, tabletType, mysqlHostname)
+	}
+	primary := addTablet("cell1", 100, topodatapb.TabletType_PRIMARY, "p")
+	addTablet("cell1", 101, topodatapb.TabletType_REPLICA, "r1")
+	addTablet("cell2", 102, topodatapb.TabletType_RDONLY, "r2")
+	orphan := addTablet("cell2", 104, topodatapb.TabletType_REPLICA, "r4")
+	addTablet("cell1", 105, topodatapb.TabletType_REPLICA, "shared")
+	addTablet("cell1", 106, topodatapb.TabletType_RDONLY, "shared")
+	// The mysqld of a tablet of another shard on the host of a replica
+	// doesn't make its address ambiguous.
+	addShardTablet("1", "cell1", 201, topodatapb.TabletType_REPLICA, "r1")
+	err := topo.RemoveShardReplicationRecord(ctx, ts, "cell2", "ks", "0", orphan)
+	require.NoError(t, err)
+
+	_, err = wr.ShardReplicaMembership(ctx, "ks", "0")
+	require.ErrorContains(t, err, "shard ks/0 has no primary")
+
+	_, err = ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = primary
+		return nil
+	})
+	require.NoError(t, err)
+
+	report, err := wr.ShardReplicaMembership(ctx, "ks", "0")
+	require.NoError(t, err)
+	require.True(t, report.HasDiffs())
+	require.Equal(t, &ShardReplicaMembershipReport{
+		Keyspace: "ks",
+		Shard:    "0",
+		Primary:  "cell1-0000000100",
+		Attached: []string{"cell1-0000000101", "cell1-0000000105", "cell1-0000000106", "cell2-0000000104"},
+		AttachedNotInGraph: []*ReplicaMembership{
+			{TabletAlias: "cell2-0000000104", Type: "replica", Address: "10.0.0.4"},
+		},
+		InGraphNotAttached: []*ReplicaMembership{
+			{TabletAlias: "cell2-0000000102", Type: "rdonly"},
+		},
+		Unmapped:  []string{"10.9.9.9"},
+		Ambiguous: []string{"10.0.0.5"},
+	}, report)
+}
+
+func TestDeleteShardErrorCodes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts, factory := memorytopo.NewServerAndFactory(ctx, "cell1")
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, nil)
+
+	tablet := &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: 100},
+		Keyspace: "ks",
+		Shard:    "0",
+		Type:     topodatapb.TabletType_REPLICA,
+	}
+	err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+	require.NoError(t, err)
+	err = ts.UpdateSrvKeyspace(ctx, "cell1", "ks", &topodatapb.SrvKeyspace{
+		Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{{
+			ServedType:      topodatapb.TabletType_PRIMARY,
+			ShardReferences: []*topodatapb.ShardReference{{Name: "0"}},
+		}},
+	})
+	require.NoError(t, err)
+
+	requireOperationError := func(err error, code vtrpcpb.Code, object string) {
+		t.Helper()
+		require.Error(t, err)
+		require.Equal(t, code, vterrors.Code(err), err.Error())
+		var opErr *OperationError
+		require.ErrorAs(t, err, &opErr)
+		require.Equal(t, object, opErr.Object)
+		// The code is kept when the error is wrapped again.
+		require.Equal(t, code, ErrorCode(fmt.Errorf("DeleteShard failed: %w", err)))
+	}
+
+	err = wr.DeleteShard(ctx, "ks", "0", false /* recursive */, false /* evenIfServing */)
+	requireOperationError(err, vtrpcpb.Code_FAILED_PRECONDITION, "shard ks/0")
+	require.ErrorContains(t, err, "shard ks/0 is still serving")
+
+	err = wr.DeleteShard(ctx, "ks", "0", false /* recursive */, true /* evenIfServing */)
+	requireOperationError(err, vtrpcpb.Code_FAILED_PRECONDITION, "shard ks/0")
+	require.ErrorContains(t, err, "shard ks/0 still has 1 tablets in cell cell1")
+
+	// A tablet that can't be deleted stops the deletion partway.
+	factory.AddOperationError(memorytopo.Delete, "tablets/cell1-0000000100/Tablet", errors.New("fake error"))
+	err = wr.DeleteShard(ctx, "ks", "0", true /* recursive */, true /* evenIfServing */)
+	requireOperationError(err, vtrpcpb.Code_ABORTED, "tablet cell1-0000000100")
+	require.ErrorContains(t, err, "can't delete tablet cell1-0000000100, re-run DeleteShard to resume: fake error")
+
+	// The topo errors are classified, and can still be told apart.
+	factory.AddOperationError(memorytopo.Get, "keyspaces/ks/shards/1/Shard", topo.NewError(topo.Timeout, "keyspaces/ks/shards/1/Shard"))
+	err = wr.DeleteShard(ctx, "ks", "1", false /* recursive */, false /* evenIfServing */)
+	requireOperationError(err, vtrpcpb.Code_DEADLINE_EXCEEDED, "shard ks/1")
+	require.True(t, topo.IsErrType(err, topo.Timeout))
+}
+
+// TestDeleteShardCancelled tests that once its tablets are deleted, the
+// shard and its replication graphs are still deleted if the context is
+// cancelled.
+func TestDeleteShardCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, nil)
+
+	tablet := &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: 100},
+		Keyspace: "delete_shard_cancelled",
+		Shard:    "0",
+		Type:     topodatapb.TabletType_REPLICA,
+	}
+	err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+	require.NoError(t, err)
+
+	// Cancel the action right after its last tablet is deleted.
+	actionCtx, actionCancel := context.WithCancel(ctx)
+	defer actionCancel()
+	event.AddListener(func(ev *events.TabletChange) {
+		if ev.Tablet.Keyspace == tablet.Keyspace && ev.Status == "deleted" {
+			actionCancel()
+		}
+	})
+
+	err = wr.DeleteShard(actionCtx, tablet.Keyspace, tablet.Shard, true /* recursive */, true /* evenIfServing */)
+	require.NoError(t, err)
+	require.Error(t, actionCtx.Err())
+
+	_, err = ts.GetShard(ctx, tablet.Keyspace, tablet.Shard)
+	require.True(t, topo.IsErrType(err, topo.NoNode), err)
+	_, err = ts.GetShardReplication(ctx, "cell1", tablet.Keyspace, tablet.Shard)
+	require.True(t, topo.IsErrType(err, topo.NoNode), err)
+}
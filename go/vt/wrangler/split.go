@@ -40,7 +40,7 @@ const (
 // on a Shard.
 func (wr *Wrangler) SetSourceShards(ctx context.Context, keyspace, shard string, sources []*topodatapb.TabletAlias, tables []string) error {
 	// Read the source tablets.
-	sourceTablets, err := wr.ts.GetTabletMap(ctx, sources, nil)
+	sourceTablets, err := wr.getTabletMap(ctx, sources, failOnUnreadableTablets)
 	if err != nil {
 		return err
 	}
@@ -97,7 +97,9 @@ func (wr *Wrangler) WaitForFilteredReplication(ctx context.Context, keyspace, sh
 	// Always run an explicit healthcheck first to make sure we don't see any outdated values.
 	// This is especially true for tests and automation where there is no pause of multiple seconds
 	// between commands and the periodic healthcheck did not run again yet.
-	if err := wr.TabletManagerClient().RunHealthCheck(ctx, tabletInfo.Tablet); err != nil {
+	if _, err := callTablet(ctx, "RunHealthCheck", idempotent, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, wr.tmc.RunHealthCheck(ctx, tabletInfo.Tablet)
+	}); err != nil {
 		return fmt.Errorf("failed to run explicit healthcheck on tablet: %v err: %v", tabletInfo, err)
 	}
 
@@ -0,0 +1,96 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"errors"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// The categories of the errors counted in errorCount.
+const (
+	errorCategoryTopo       = "topo"
+	errorCategoryTabletRPC  = "tablet_rpc"
+	errorCategoryValidation = "validation"
+	errorCategoryOther      = "other"
+)
+
+var errorCount = stats.NewCountersWithMultiLabels("WranglerErrors", "Failed wrangler operations, by error category and canonical error code", []string{"Category", "Code"})
+
+// RecordError counts err, the error a wrangler operation failed with, in
+// the WranglerErrors stats. It does nothing if err is nil.
+func RecordError(err error) {
+	if err == nil {
+		return
+	}
+	category, code := classifyError(err)
+	errorCount.Add([]string{category, code.String()}, 1)
+}
+
+// classifyError returns the category and the canonical error code of err.
+// Topo errors carry their own codes, which are mapped to canonical ones.
+// Other errors are categorized by their canonical code: the codes of
+// requests that were rejected before doing anything are validation errors,
+// and the codes of RPCs that didn't complete are tablet RPC errors.
+func classifyError(err error) (string, vtrpcpb.Code) {
+	var topoErr topo.Error
+	if errors.As(err, &topoErr) {
+		return errorCategoryTopo, topoErrorCode(err)
+	}
+
+	code := vterrors.Code(err)
+	switch code {
+	case vtrpcpb.Code_INVALID_ARGUMENT, vtrpcpb.Code_FAILED_PRECONDITION, vtrpcpb.Code_OUT_OF_RANGE,
+		vtrpcpb.Code_ALREADY_EXISTS, vtrpcpb.Code_NOT_FOUND:
+		return errorCategoryValidation, code
+	case vtrpcpb.Code_UNAVAILABLE, vtrpcpb.Code_DEADLINE_EXCEEDED, vtrpcpb.Code_ABORTED,
+		vtrpcpb.Code_RESOURCE_EXHAUSTED:
+		return errorCategoryTabletRPC, code
+	default:
+		return errorCategoryOther, code
+	}
+}
+
+// topoErrorCode returns the canonical error code matching the code of the
+// topo error err.
+func topoErrorCode(err error) vtrpcpb.Code {
+	switch {
+	case topo.IsErrType(err, topo.NoNode):
+		return vtrpcpb.Code_NOT_FOUND
+	case topo.IsErrType(err, topo.NodeExists):
+		return vtrpcpb.Code_ALREADY_EXISTS
+	case topo.IsErrType(err, topo.NodeNotEmpty), topo.IsErrType(err, topo.NoUpdateNeeded):
+		return vtrpcpb.Code_FAILED_PRECONDITION
+	case topo.IsErrType(err, topo.BadVersion):
+		return vtrpcpb.Code_ABORTED
+	case topo.IsErrType(err, topo.Timeout):
+		return vtrpcpb.Code_DEADLINE_EXCEEDED
+	case topo.IsErrType(err, topo.Interrupted):
+		return vtrpcpb.Code_CANCELED
+	case topo.IsErrType(err, topo.ResourceExhausted):
+		return vtrpcpb.Code_RESOURCE_EXHAUSTED
+	case topo.IsErrType(err, topo.NoImplementation), topo.IsErrType(err, topo.NoReadOnlyImplementation):
+		return vtrpcpb.Code_UNIMPLEMENTED
+	default:
+		return vtrpcpb.Code_UNKNOWN
+	}
+}
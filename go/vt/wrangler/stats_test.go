@@ -0,0 +1,92 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		category string
+		code     vtrpcpb.Code
+	}{
+		{
+			name:     "topo",
+			err:      fmt.Errorf("GetShard(ks, 0) failed: %w", topo.NewError(topo.NoNode, "keyspaces/ks/shards/0")),
+			category: errorCategoryTopo,
+			code:     vtrpcpb.Code_NOT_FOUND,
+		},
+		{
+			name:     "topo bad version",
+			err:      topo.NewError(topo.BadVersion, "keyspaces/ks"),
+			category: errorCategoryTopo,
+			code:     vtrpcpb.Code_ABORTED,
+		},
+		{
+			name:     "validation",
+			err:      vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard ks/0 still has tablets"),
+			category: errorCategoryValidation,
+			code:     vtrpcpb.Code_FAILED_PRECONDITION,
+		},
+		{
+			name:     "tablet rpc",
+			err:      vterrors.Wrap(vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "connection refused"), "ReloadSchema failed"),
+			category: errorCategoryTabletRPC,
+			code:     vtrpcpb.Code_UNAVAILABLE,
+		},
+		{
+			name:     "deadline",
+			err:      context.DeadlineExceeded,
+			category: errorCategoryTabletRPC,
+			code:     vtrpcpb.Code_DEADLINE_EXCEEDED,
+		},
+		{
+			name:     "other",
+			err:      errors.New("something broke"),
+			category: errorCategoryOther,
+			code:     vtrpcpb.Code_UNKNOWN,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category, code := classifyError(tt.err)
+			assert.Equal(t, tt.category, category)
+			assert.Equal(t, tt.code, code)
+		})
+	}
+}
+
+func TestRecordError(t *testing.T) {
+	key := errorCategoryValidation + ".INVALID_ARGUMENT"
+	before := errorCount.Counts()[key]
+	RecordError(nil)
+	RecordError(vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "bad keyspace name"))
+	assert.Equal(t, before+1, errorCount.Counts()[key])
+}
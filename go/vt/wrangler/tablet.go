@@ -18,22 +18,118 @@ package wrangler
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/netutil"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil/policy"
+	"vitess.io/vitess/go/vt/vterrors"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 // Tablet related methods for wrangler
 
+// ResolveTabletAlias returns the alias of the tablet designated by s, which is
+// either a tablet alias, or the "hostname" or "hostname:port" of a tablet. A
+// hostname is resolved by scanning the tablet records of the given cells, or
+// of all cells if none are given, and the port can be any port of the tablet.
+// It fails with the list of candidates if the hostname matches more than one
+// tablet.
+func (wr *Wrangler) ResolveTabletAlias(ctx context.Context, s string, cells []string) (*topodatapb.TabletAlias, error) {
+	alias, parseErr := topoproto.ParseTabletAlias(s)
+	knownCells, err := wr.ts.GetKnownCells(ctx)
+	if parseErr == nil && (err != nil || slices.Contains(knownCells, alias.Cell)) {
+		// A hostname such as "db-101" also parses as a tablet alias, so it is
+		// only taken as one if its cell exists.
+		return alias, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, port := s, int32(0)
+	if h, p, err := net.SplitHostPort(s); err == nil {
+		n, err := strconv.ParseInt(p, 10, 32)
+		if err != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid port in %v", s)
+		}
+		hostname, port = h, int32(n)
+	}
+	if hostname == "" {
+		return nil, parseErr
+	}
+
+	if len(cells) == 0 {
+		cells = knownCells
+	}
+	var matches []*topodatapb.Tablet
+	for _, cell := range cells {
+		tablets, err := wr.ts.GetTabletsByCell(ctx, cell, nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list tablets in cell %v: %v", cell, err)
+		}
+		for _, ti := range tablets {
+			if tabletHasAddress(ti.Tablet, hostname, port) {
+				matches = append(matches, ti.Tablet)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "%v is neither a tablet alias nor the hostname of a tablet in cells %v", s, cells)
+	case 1:
+		return matches[0].Alias, nil
+	}
+	candidates := make([]string, 0, len(matches))
+	for _, tablet := range matches {
+		candidates = append(candidates, fmt.Sprintf("%v (%v)", topoproto.TabletAliasString(tablet.Alias), netutil.JoinHostPort(tablet.Hostname, tablet.PortMap["vt"])))
+	}
+	sort.Strings(candidates)
+	return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "%v matches %d tablets: %v; use a tablet alias or hostname:port instead", s, len(matches), strings.Join(candidates, ", "))
+}
+
+// tabletHasAddress returns true if the tablet has the given hostname and, if
+// port is set, has it as one of its ports.
+func tabletHasAddress(tablet *topodatapb.Tablet, hostname string, port int32) bool {
+	if !strings.EqualFold(tablet.Hostname, hostname) {
+		return false
+	}
+	if port == 0 || tablet.MysqlPort == port {
+		return true
+	}
+	for _, p := range tablet.PortMap {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
 // DeleteTablet removes a tablet from a shard.
 // - if allowPrimary is set, we can Delete a primary tablet (and clear
 // its record from the Shard record if it was the primary).
@@ -50,7 +146,7 @@ func (wr *Wrangler) DeleteTablet(ctx context.Context, tabletAlias *topodatapb.Ta
 	}
 
 	if wasPrimary && !allowPrimary {
-		return fmt.Errorf("cannot delete tablet %v as it is a primary, use allow_primary flag", topoproto.TabletAliasString(tabletAlias))
+		return operationErrorf(vtrpcpb.Code_FAILED_PRECONDITION, tabletObject(tabletAlias), "cannot delete tablet %v as it is a primary, use allow_primary flag", topoproto.TabletAliasString(tabletAlias))
 	}
 
 	// update the Shard object if the primary was scrapped.
@@ -78,14 +174,120 @@ func (wr *Wrangler) DeleteTablet(ctx context.Context, tabletAlias *topodatapb.Ta
 		}
 	}
 
-	// remove the record and its replication graph entry
-	if err := topotools.DeleteTablet(ctx, wr.ts, ti.Tablet); err != nil {
+	// Remove the record and its replication graph entry together, even if
+	// ctx is done in between: a record left without its entry would be
+	// missed by DeleteShard, and orphaned.
+	cleanupCtx, cancel := wr.cleanupContext(ctx)
+	defer cancel()
+	if err := topotools.DeleteTablet(cleanupCtx, wr.ts, ti.Tablet); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// DeleteCorruptTablet removes a tablet record that exists but can no longer
+// be unmarshaled, then scrubs the alias from the ShardReplication record of
+// keyspace/shard in every cell. The record can't tell us which shard the
+// tablet belonged to, so the caller has to provide it.
+//
+// It refuses to do anything if the record is readable or missing, so it
+// can't be used to bypass the checks in DeleteTablet. It also refuses if
+// keyspace/shard doesn't exist or doesn't list the alias in any cell, since
+// deleting the record would then leave a stale replication graph entry
+// behind that can no longer be traced back to the tablet.
+func (wr *Wrangler) DeleteCorruptTablet(ctx context.Context, tabletAlias *topodatapb.TabletAlias, keyspace, shard string) error {
+	aliasStr := topoproto.TabletAliasString(tabletAlias)
+	if keyspace == "" || shard == "" {
+		return fmt.Errorf("keyspace and shard are required to clean up the replication graph for corrupt tablet %v", aliasStr)
+	}
+	if _, err := wr.ts.GetShard(ctx, keyspace, shard); err != nil {
+		return fmt.Errorf("cannot read shard %v/%v given for corrupt tablet %v: %v", keyspace, shard, aliasStr, err)
+	}
+
+	_, tabletErr := wr.ts.GetTablet(ctx, tabletAlias)
+	switch {
+	case tabletErr == nil:
+		return fmt.Errorf("tablet record for %v is not corrupt, use DeleteTablet instead", aliasStr)
+	case topo.IsErrType(tabletErr, topo.NoNode):
+		return fmt.Errorf("tablet record for %v does not exist, use ShardReplicationFix to clean up the replication graph: %v", aliasStr, tabletErr)
+	}
+	corrupt, err := wr.isTabletRecordCorrupt(ctx, tabletAlias)
+	if err != nil {
+		return err
+	}
+	if !corrupt {
+		// GetTablet failed for some other reason, e.g. the topo is unreachable.
+		return tabletErr
+	}
+
+	cells, err := wr.ts.GetKnownCells(ctx)
+	if err != nil {
+		return err
+	}
+	var replicationCells []string
+	for _, cell := range cells {
+		sri, err := wr.ts.GetShardReplication(ctx, cell, keyspace, shard)
+		switch {
+		case topo.IsErrType(err, topo.NoNode):
+			continue
+		case err != nil:
+			return fmt.Errorf("cannot read replication graph of %v/%v in cell %v: %v", keyspace, shard, cell, err)
+		}
+		if _, err := sri.GetShardReplicationNode(tabletAlias); err == nil {
+			replicationCells = append(replicationCells, cell)
+		}
+	}
+	if len(replicationCells) == 0 {
+		return fmt.Errorf("tablet %v is not in the replication graph of %v/%v in any cell, check that it is the shard the tablet belonged to", aliasStr, keyspace, shard)
+	}
+
+	wr.Logger().Warningf("Tablet record for %v cannot be unmarshaled (%v), deleting it", aliasStr, tabletErr)
+	if err := wr.ts.DeleteTablet(ctx, tabletAlias); err != nil {
+		return fmt.Errorf("failed to delete tablet record for %v: %v", aliasStr, err)
+	}
+	wr.Logger().Infof("Deleted tablet record for %v", aliasStr)
+
+	for _, cell := range replicationCells {
+		removed := 0
+		err := wr.ts.UpdateShardReplicationFields(ctx, cell, keyspace, shard, func(sr *topodatapb.ShardReplication) error {
+			nodes := make([]*topodatapb.ShardReplication_Node, 0, len(sr.Nodes))
+			for _, node := range sr.Nodes {
+				if !proto.Equal(node.TabletAlias, tabletAlias) {
+					nodes = append(nodes, node)
+				}
+			}
+			removed = len(sr.Nodes) - len(nodes)
+			if removed == 0 {
+				return topo.NewError(topo.NoUpdateNeeded, aliasStr)
+			}
+			sr.Nodes = nodes
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove %v from the replication graph of %v/%v in cell %v: %v", aliasStr, keyspace, shard, cell, err)
+		}
+		if removed > 0 {
+			wr.Logger().Infof("Removed %v from the replication graph of %v/%v in cell %v", aliasStr, keyspace, shard, cell)
+		}
+	}
+	return nil
+}
+
+// isTabletRecordCorrupt reads the raw tablet record and reports whether it
+// fails to unmarshal.
+func (wr *Wrangler) isTabletRecordCorrupt(ctx context.Context, tabletAlias *topodatapb.TabletAlias) (bool, error) {
+	conn, err := wr.ts.ConnForCell(ctx, tabletAlias.Cell)
+	if err != nil {
+		return false, err
+	}
+	data, _, err := conn.Get(ctx, path.Join(topo.TabletsPath, topoproto.TabletAliasString(tabletAlias), topo.TabletFile))
+	if err != nil {
+		return false, err
+	}
+	return (&topodatapb.Tablet{}).UnmarshalVT(data) != nil, nil
+}
+
 // ChangeTabletType changes the type of tablet and recomputes all
 // necessary derived paths in the serving graph, if necessary.
 //
@@ -111,7 +313,221 @@ func (wr *Wrangler) ChangeTabletType(ctx context.Context, tabletAlias *topodatap
 		return err
 	}
 	// and ask the tablet to make the change
-	return wr.tmc.ChangeType(ctx, ti.Tablet, tabletType, semiSync)
+	_, err = callTablet(ctx, "ChangeType", notIdempotent, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, wr.tmc.ChangeType(ctx, ti.Tablet, tabletType, semiSync)
+	})
+	return err
+}
+
+// healthProbeTimeout bounds how long ChangeTabletTypeImpact waits for a
+// single tablet's health record.
+const healthProbeTimeout = 5 * time.Second
+
+// TabletTypeChangeImpact describes what a ChangeTabletType would do to the
+// serving graph of the tablet's cell and shard.
+type TabletTypeChangeImpact struct {
+	TabletAlias   *topodatapb.TabletAlias
+	Keyspace      string
+	Shard         string
+	CurrentType   topodatapb.TabletType
+	RequestedType topodatapb.TabletType
+	// Allowed is false if ChangeTabletType would refuse the change, or if
+	// it would drop below MinRemaining. Reason then says why.
+	Allowed bool
+	Reason  string
+	// RemainingServing is the number of healthy, serving tablets of
+	// CurrentType left in the tablet's cell and shard after the change.
+	RemainingServing int
+	MinRemaining     int
+}
+
+// MarshalJSON renders the alias and tablet types by name.
+func (impact *TabletTypeChangeImpact) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		TabletAlias      string
+		Keyspace         string
+		Shard            string
+		CurrentType      string
+		RequestedType    string
+		Allowed          bool
+		Reason           string `json:",omitempty"`
+		RemainingServing int
+		MinRemaining     int `json:",omitempty"`
+	}{
+		TabletAlias:      topoproto.TabletAliasString(impact.TabletAlias),
+		Keyspace:         impact.Keyspace,
+		Shard:            impact.Shard,
+		CurrentType:      topoproto.TabletTypeLString(impact.CurrentType),
+		RequestedType:    topoproto.TabletTypeLString(impact.RequestedType),
+		Allowed:          impact.Allowed,
+		Reason:           impact.Reason,
+		RemainingServing: impact.RemainingServing,
+		MinRemaining:     impact.MinRemaining,
+	})
+}
+
+// ChangeTabletTypeImpact computes what changing the type of the tablet
+// would do without making the change. The remaining serving tablets are
+// found by walking the cell's replication graph for the shard and asking
+// each tablet of the current type for a health record.
+//
+// If minRemaining is positive, moving a tablet out of a serving type is
+// not allowed when fewer than minRemaining serving tablets of that type
+// would be left.
+func (wr *Wrangler) ChangeTabletTypeImpact(ctx context.Context, tabletAlias *topodatapb.TabletAlias, tabletType topodatapb.TabletType, minRemaining int) (*TabletTypeChangeImpact, error) {
+	ti, err := wr.ts.GetTablet(ctx, tabletAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	impact := &TabletTypeChangeImpact{
+		TabletAlias:   tabletAlias,
+		Keyspace:      ti.Keyspace,
+		Shard:         ti.Shard,
+		CurrentType:   ti.Type,
+		RequestedType: tabletType,
+		Allowed:       true,
+		MinRemaining:  minRemaining,
+	}
+	switch {
+	case ti.Type == topodatapb.TabletType_PRIMARY || tabletType == topodatapb.TabletType_PRIMARY:
+		impact.Allowed = false
+		impact.Reason = "ChangeTabletType does not convert to or from a primary, use PlannedReparentShard instead"
+	case !topo.IsTrivialTypeChange(ti.Type, tabletType):
+		impact.Allowed = false
+		impact.Reason = fmt.Sprintf("%v -> %v is not an allowed transition for ChangeTabletType", ti.Type, tabletType)
+	}
+
+	sri, err := wr.ts.GetShardReplication(ctx, tabletAlias.Cell, ti.Keyspace, ti.Shard)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read replication graph for %v/%v in cell %v: %v", ti.Keyspace, ti.Shard, tabletAlias.Cell, err)
+	}
+	aliases := make([]*topodatapb.TabletAlias, 0, len(sri.Nodes))
+	for _, node := range sri.Nodes {
+		// The tablet itself only remains of its current type if the
+		// type doesn't actually change.
+		if topoproto.TabletAliasEqual(node.TabletAlias, tabletAlias) && ti.Type != tabletType {
+			continue
+		}
+		aliases = append(aliases, node.TabletAlias)
+	}
+	// Tablets we cannot read are not counted as serving, which can only
+	// make the check stricter.
+	tabletMap, err := wr.getTabletMap(ctx, aliases, warnOnUnreadableTablets)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, other := range tabletMap {
+		if other.Type != ti.Type {
+			continue
+		}
+		wg.Add(1)
+		go func(other *topo.TabletInfo) {
+			defer wg.Done()
+			ctx, cancel := wr.tabletContext(ctx)
+			defer cancel()
+			serving, err := wr.isTabletServing(ctx, other.Tablet)
+			if err != nil {
+				wr.Logger().Warningf("cannot get health of tablet %v: %v", other.AliasString(), err)
+				return
+			}
+			if serving {
+				mu.Lock()
+				impact.RemainingServing++
+				mu.Unlock()
+			}
+		}(other)
+	}
+	wg.Wait()
+
+	if impact.Allowed && minRemaining > 0 && ti.Type != tabletType && topo.IsInServingGraph(ti.Type) && impact.RemainingServing < minRemaining {
+		impact.Allowed = false
+		impact.Reason = fmt.Sprintf("only %d serving %v tablets would remain in cell %v for %v/%v, fewer than the minimum of %d",
+			impact.RemainingServing, topoproto.TabletTypeLString(ti.Type), tabletAlias.Cell, ti.Keyspace, ti.Shard, minRemaining)
+	}
+	return impact, nil
+}
+
+// ChangeTabletTypeWithMinRemaining is ChangeTabletType, except that it
+// refuses to move a tablet out of a serving type if fewer than minRemaining
+// serving tablets of that type would be left in its cell and shard.
+func (wr *Wrangler) ChangeTabletTypeWithMinRemaining(ctx context.Context, tabletAlias *topodatapb.TabletAlias, tabletType topodatapb.TabletType, minRemaining int) error {
+	impact, err := wr.ChangeTabletTypeImpact(ctx, tabletAlias, tabletType, minRemaining)
+	if err != nil {
+		return err
+	}
+	if !impact.Allowed {
+		return fmt.Errorf("cannot change type of tablet %v to %v: %v", topoproto.TabletAliasString(tabletAlias), tabletType, impact.Reason)
+	}
+	return wr.ChangeTabletType(ctx, tabletAlias, tabletType)
+}
+
+// isTabletServing reads a single health record from the tablet and
+// reports whether it is serving without a health error. It gives up after
+// healthProbeTimeout so a hung tablet can't use up the caller's deadline.
+func (wr *Wrangler) isTabletServing(ctx context.Context, tablet *topodatapb.Tablet) (bool, error) {
+	shr, err := grpcvtctldserver.ReadTabletHealth(ctx, tablet, healthProbeTimeout)
+	if err != nil {
+		return false, err
+	}
+	return shr.Serving && shr.RealtimeStats.GetHealthError() == "", nil
+}
+
+// UpdateTabletTags adds and removes tags on the tablet through its
+// ChangeTags RPC, so that the tablet manager updates the record it publishes
+// on every state change, instead of writing its old tags back.
+//
+// The tablet applies the changes to its current tags under its action lock,
+// so concurrent tag updates are not lost. The resulting tags are returned;
+// removing the last tag yields an empty map.
+func (wr *Wrangler) UpdateTabletTags(ctx context.Context, tabletAlias *topodatapb.TabletAlias, add map[string]string, remove []string) (map[string]string, error) {
+	// ChangeTags removes the tags whose value is empty.
+	changes := make(map[string]string, len(add)+len(remove))
+	for key, val := range add {
+		if val == "" {
+			return nil, fmt.Errorf("tag %v has an empty value", key)
+		}
+		changes[key] = val
+	}
+	for _, key := range remove {
+		changes[key] = ""
+	}
+
+	ti, err := wr.ts.GetTablet(ctx, tabletAlias)
+	if err != nil {
+		return nil, err
+	}
+	changed := false
+	for key, val := range changes {
+		if cur, ok := ti.Tags[key]; (val == "" && ok) || (val != "" && cur != val) {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		// Nothing changes, so there is nothing for the tablet to do.
+		return tagsOrEmpty(ti.Tags), nil
+	}
+
+	resp, err := callTablet(ctx, "ChangeTags", idempotent, func(ctx context.Context) (*tabletmanagerdatapb.ChangeTagsResponse, error) {
+		return wr.tmc.ChangeTags(ctx, ti.Tablet, changes, false /* replace */)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot change the tags of tablet %v: %v", topoproto.TabletAliasString(tabletAlias), err)
+	}
+	return tagsOrEmpty(resp.Tags), nil
+}
+
+func tagsOrEmpty(tags map[string]string) map[string]string {
+	if tags == nil {
+		return map[string]string{}
+	}
+	return tags
 }
 
 // StartReplication is used to start replication on the specified tablet
@@ -121,7 +537,68 @@ func (wr *Wrangler) StartReplication(ctx context.Context, tablet *topodatapb.Tab
 	if err != nil {
 		return err
 	}
-	return wr.TabletManagerClient().StartReplication(ctx, tablet, semiSync)
+	_, err = callTablet(ctx, "StartReplication", idempotent, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, wr.tmc.StartReplication(ctx, tablet, semiSync)
+	})
+	return err
+}
+
+// replicationPositionPollInterval is how often StartReplicationUntilAfter
+// checks whether the tablet has reached the target position.
+var replicationPositionPollInterval = time.Second
+
+// StartReplicationUntilAfter starts replication on the tablet until it is
+// past the given position, then waits until the tablet reports it has
+// reached the position, or until the timeout expires. The last position
+// the tablet reported is returned in either case.
+func (wr *Wrangler) StartReplicationUntilAfter(ctx context.Context, tabletAlias *topodatapb.TabletAlias, position string, timeout time.Duration) (string, error) {
+	if position == "" {
+		return "", fmt.Errorf("a target position is required")
+	}
+	target, err := replication.DecodePosition(position)
+	if err != nil {
+		return "", fmt.Errorf("invalid position %q, expected <flavor>/<gtid set> such as MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615: %v", position, err)
+	}
+
+	ti, err := wr.ts.GetTablet(ctx, tabletAlias)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if _, err := callTablet(ctx, "StartReplicationUntilAfter", notIdempotent, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, wr.tmc.StartReplicationUntilAfter(ctx, ti.Tablet, position, timeout)
+	}); err != nil {
+		return "", fmt.Errorf("StartReplicationUntilAfter(%v) failed on tablet %v: %v", position, ti.AliasString(), err)
+	}
+
+	ticker := time.NewTicker(replicationPositionPollInterval)
+	defer ticker.Stop()
+	var lastPosition string
+	for {
+		status, err := callTablet(ctx, "ReplicationStatus", idempotent, func(ctx context.Context) (*replicationdatapb.Status, error) {
+			return wr.tmc.ReplicationStatus(ctx, ti.Tablet)
+		})
+		if err == nil {
+			lastPosition = status.Position
+			current, err := replication.DecodePosition(status.Position)
+			if err != nil {
+				return lastPosition, fmt.Errorf("tablet %v reported an invalid position %q: %v", ti.AliasString(), status.Position, err)
+			}
+			if current.AtLeast(target) {
+				return lastPosition, nil
+			}
+		} else if ctx.Err() == nil {
+			wr.Logger().Warningf("cannot get replication status of tablet %v, retrying: %v", ti.AliasString(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return lastPosition, fmt.Errorf("tablet %v did not reach position %v within %v", ti.AliasString(), position, timeout)
+		case <-ticker.C:
+		}
+	}
 }
 
 // SetReplicationSource is used to set the replication source on the specified tablet to the current shard primary (if available).
@@ -154,6 +631,20 @@ func (wr *Wrangler) getShardPrimaryForTablet(ctx context.Context, tablet *topoda
 	return topotools.GetShardPrimaryForTablet(ctx, wr.ts, tablet)
 }
 
+// PingTablet checks that the tablet answers tablet manager RPCs. The ping is
+// retried if it fails with a transport error.
+func (wr *Wrangler) PingTablet(ctx context.Context, tabletAlias *topodatapb.TabletAlias) error {
+	ti, err := wr.ts.GetTablet(ctx, tabletAlias)
+	if err != nil {
+		return err
+	}
+
+	_, err = callTablet(ctx, "Ping", idempotent, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, wr.tmc.Ping(ctx, ti.Tablet)
+	})
+	return err
+}
+
 // RefreshTabletState refreshes tablet state
 func (wr *Wrangler) RefreshTabletState(ctx context.Context, tabletAlias *topodatapb.TabletAlias) error {
 	// Load tablet to find endpoint, and keyspace and shard assignment.
@@ -163,16 +654,21 @@ func (wr *Wrangler) RefreshTabletState(ctx context.Context, tabletAlias *topodat
 	}
 
 	// and ask the tablet to refresh itself
-	return wr.tmc.RefreshState(ctx, ti.Tablet)
+	_, err = callTablet(ctx, "RefreshState", idempotent, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, wr.tmc.RefreshState(ctx, ti.Tablet)
+	})
+	return err
 }
 
-// ExecuteFetchAsApp executes a query remotely using the App pool
-func (wr *Wrangler) ExecuteFetchAsApp(ctx context.Context, tabletAlias *topodatapb.TabletAlias, usePool bool, query string, maxRows int) (*querypb.QueryResult, error) {
+// ExecuteFetchAsApp executes a query remotely using the App pool. If
+// callerID is set, the tablet checks the table ACLs for it first.
+func (wr *Wrangler) ExecuteFetchAsApp(ctx context.Context, tabletAlias *topodatapb.TabletAlias, usePool bool, query string, maxRows int, callerID *vtrpcpb.CallerID) (*querypb.QueryResult, error) {
 	resp, err := wr.VtctldServer().ExecuteFetchAsApp(ctx, &vtctldatapb.ExecuteFetchAsAppRequest{
 		TabletAlias: tabletAlias,
 		Query:       query,
 		MaxRows:     int64(maxRows),
 		UsePool:     usePool,
+		CallerId:    callerID,
 	})
 	if err != nil {
 		return nil, err
@@ -218,7 +714,9 @@ func (wr *Wrangler) VReplicationExec(ctx context.Context, tabletAlias *topodatap
 	if err != nil {
 		return nil, err
 	}
-	return wr.tmc.VReplicationExec(ctx, ti.Tablet, query)
+	return callTablet(ctx, "VReplicationExec", notIdempotent, func(ctx context.Context) (*querypb.QueryResult, error) {
+		return wr.tmc.VReplicationExec(ctx, ti.Tablet, query)
+	})
 }
 
 // isPrimaryTablet is a shortcut way to determine whether the current tablet
@@ -234,3 +732,141 @@ func (wr *Wrangler) VReplicationExec(ctx context.Context, tabletAlias *topodatap
 func (wr *Wrangler) isPrimaryTablet(ctx context.Context, ti *topo.TabletInfo) (bool, error) {
 	return topotools.IsPrimaryTablet(ctx, wr.TopoServer(), ti)
 }
+
+// lookupHost resolves tablet hostnames for AuditTablets.
+var lookupHost = net.DefaultResolver.LookupHost
+
+// TabletAudit describes a tablet record that AuditTablets found suspect.
+type TabletAudit struct {
+	TabletAlias string
+	Keyspace    string
+	Shard       string
+	Type        string
+	Hostname    string
+	StartTime   string `json:",omitempty"`
+
+	// NoHealth is set if the tablet didn't send a health record within the
+	// health window, with the reason in HealthError.
+	NoHealth    bool
+	HealthError string `json:",omitempty"`
+	// UnresolvableHostname is set if the hostname of the tablet doesn't
+	// resolve.
+	UnresolvableHostname bool
+	// OlderThanCutoff is set if the tablet last started before the cutoff,
+	// or its record doesn't say when it started.
+	OlderThanCutoff bool
+
+	Deleted     bool
+	DeleteError string `json:",omitempty"`
+
+	tablet *topodatapb.Tablet
+}
+
+// Stale returns true if the tablet matches all the criteria of a
+// decommissioned tablet.
+func (audit *TabletAudit) Stale() bool {
+	return audit.NoHealth && audit.UnresolvableHostname && audit.OlderThanCutoff
+}
+
+// AuditTablets checks every tablet record in the given cells, or in all
+// cells if none are given, against live data: whether the tablet sends a
+// health record within healthWindow, whether its hostname resolves, and,
+// if olderThan is set, whether it last started more than olderThan ago.
+// It returns the tablets matching any of these, sorted by alias.
+func (wr *Wrangler) AuditTablets(ctx context.Context, cells []string, healthWindow, olderThan time.Duration, maxConcurrency int) ([]*TabletAudit, error) {
+	if maxConcurrency < 1 {
+		return nil, fmt.Errorf("concurrency must be at least 1")
+	}
+	if len(cells) == 0 {
+		var err error
+		cells, err = wr.ts.GetKnownCells(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var tablets []*topo.TabletInfo
+	for _, cell := range cells {
+		cellTablets, err := wr.ts.GetTabletsByCell(ctx, cell, nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list tablets in cell %v: %v", cell, err)
+		}
+		tablets = append(tablets, cellTablets...)
+	}
+
+	cutoff := wr.now().Add(-olderThan)
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, maxConcurrency)
+		results = []*TabletAudit{}
+	)
+	for _, ti := range tablets {
+		wg.Add(1)
+		go func(tablet *topodatapb.Tablet) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			audit := &TabletAudit{
+				TabletAlias: topoproto.TabletAliasString(tablet.Alias),
+				Keyspace:    tablet.Keyspace,
+				Shard:       tablet.Shard,
+				Type:        topoproto.TabletTypeLString(tablet.Type),
+				Hostname:    tablet.Hostname,
+				tablet:      tablet,
+			}
+			startTime := protoutil.TimeFromProto(tablet.TabletStartTime)
+			if !startTime.IsZero() {
+				audit.StartTime = startTime.UTC().Format(time.RFC3339)
+			}
+			if _, err := grpcvtctldserver.ReadTabletHealth(ctx, tablet, healthWindow); err != nil {
+				audit.NoHealth = true
+				audit.HealthError = err.Error()
+			}
+			if _, err := lookupHost(ctx, tablet.Hostname); err != nil {
+				audit.UnresolvableHostname = true
+			}
+			audit.OlderThanCutoff = olderThan > 0 && (startTime.IsZero() || startTime.Before(cutoff))
+			if !audit.NoHealth && !audit.UnresolvableHostname && !audit.OlderThanCutoff {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, audit)
+		}(ti.Tablet)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].TabletAlias < results[j].TabletAlias
+	})
+	return results, nil
+}
+
+// DeleteStaleTablets deletes the records of the audited tablets that are
+// stale, and records the outcome in each audit. Primary tablets are never
+// deleted.
+func (wr *Wrangler) DeleteStaleTablets(ctx context.Context, audits []*TabletAudit) error {
+	if err := wr.CheckWritable("DeleteStaleTablets"); err != nil {
+		return err
+	}
+	rec := concurrency.AllErrorRecorder{}
+	for _, audit := range audits {
+		if !audit.Stale() {
+			continue
+		}
+		if audit.tablet.Type == topodatapb.TabletType_PRIMARY {
+			audit.DeleteError = "refusing to delete a PRIMARY tablet"
+			rec.RecordError(fmt.Errorf("tablet %v: %v", audit.TabletAlias, audit.DeleteError))
+			continue
+		}
+		if err := wr.DeleteTablet(ctx, audit.tablet.Alias, false /* allowPrimary */); err != nil {
+			audit.DeleteError = err.Error()
+			rec.RecordError(fmt.Errorf("tablet %v: %v", audit.TabletAlias, err))
+			continue
+		}
+		audit.Deleted = true
+	}
+	return rec.Error()
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"vitess.io/vitess/go/vt/topo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// unreadableTablets says what an operation does when some of the tablets
+// it builds a tablet map of can't be read from the topo.
+type unreadableTablets int
+
+const (
+	// failOnUnreadableTablets fails the operation. Operations that change
+	// tablets or the topology use it, so they never act on a partial view.
+	failOnUnreadableTablets unreadableTablets = iota
+	// warnOnUnreadableTablets logs a warning for each tablet that can't be
+	// read, and proceeds with the others. Read-only operations, such as
+	// validations, refreshes and listings, use it.
+	warnOnUnreadableTablets
+)
+
+// getTabletMap reads the tablets, like topo.Server.GetTabletMap, and handles
+// the ones that can't be read as onUnreadable says. Tablets that don't
+// exist are ignored either way.
+func (wr *Wrangler) getTabletMap(ctx context.Context, aliases []*topodatapb.TabletAlias, onUnreadable unreadableTablets) (map[string]*topo.TabletInfo, error) {
+	tabletMap, readErrs, err := wr.ts.GetTabletMapWithErrors(ctx, aliases, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(readErrs) == 0 {
+		return tabletMap, nil
+	}
+	if onUnreadable == failOnUnreadableTablets {
+		errs := make([]error, len(readErrs))
+		for i, readErr := range readErrs {
+			errs[i] = readErr
+		}
+		return nil, fmt.Errorf("cannot read %d of the %d tablets: %w", len(readErrs), len(aliases), errors.Join(errs...))
+	}
+	for _, readErr := range readErrs {
+		wr.Logger().Warningf("cannot read tablet %v, proceeding without it", readErr)
+	}
+	return tabletMap, nil
+}
+
+// getTabletMapForShard reads the tablets of the shard in the cells, or in
+// all the cells if there are none, and handles the ones that can't be read
+// as onUnreadable says. A cell whose replication graph can't be read counts
+// as unreadable tablets.
+func (wr *Wrangler) getTabletMapForShard(ctx context.Context, keyspace, shard string, cells []string, onUnreadable unreadableTablets) (map[string]*topo.TabletInfo, error) {
+	aliases, err := wr.ts.FindAllTabletAliasesInShardByCell(ctx, keyspace, shard, cells)
+	if err != nil {
+		if !topo.IsErrType(err, topo.PartialResult) || onUnreadable == failOnUnreadableTablets {
+			return nil, err
+		}
+		wr.Logger().Warningf("cannot read the replication graph of %v/%v in all the cells, proceeding without the tablets of the missing cells: %v", keyspace, shard, err)
+	}
+	return wr.getTabletMap(ctx, aliases, onUnreadable)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestGetTabletMapUnreadableTablets(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts, factory := memorytopo.NewServerAndFactory(ctx, "cell1")
+	tmc := &positionTMClient{
+		position:  "MySQL56/00000000-0000-0000-0000-000000000001:1-10",
+		caughtUp:  map[uint32]bool{101: true, 102: true},
+		positions: make(map[uint32]string),
+	}
+	logger := logutil.NewMemoryLogger()
+	wr := New(vtenv.NewTestEnv(), logger, ts, tmc)
+
+	var aliases []*topodatapb.TabletAlias
+	for uid := uint32(100); uid <= 102; uid++ {
+		tabletType := topodatapb.TabletType_REPLICA
+		if uid == 100 {
+			tabletType = topodatapb.TabletType_PRIMARY
+		}
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    "0",
+			Type:     tabletType,
+		}
+		err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+		aliases = append(aliases, tablet.Alias)
+	}
+	_, err := ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}
+		return nil
+	})
+	require.NoError(t, err)
+	factory.AddOperationError(memorytopo.Get, "tablets/cell1-0000000102/Tablet", errors.New("fake error"))
+
+	// Read-only operations proceed with the tablets that can be read, with
+	// a warning for the others.
+	tabletMap, err := wr.getTabletMapForShard(ctx, "ks", "0", nil, warnOnUnreadableTablets)
+	require.NoError(t, err)
+	require.Len(t, tabletMap, 2)
+	require.NotContains(t, tabletMap, "cell1-0000000102")
+	require.Contains(t, logger.String(), "cannot read tablet cell1-0000000102: fake error, proceeding without it")
+
+	report, err := wr.WaitForShardReplicasPosition(ctx, "ks", "0", "", time.Second, ReplicaPositionFilter{})
+	require.NoError(t, err)
+	require.True(t, report.AllReached())
+	require.Len(t, report.Tablets, 1)
+	require.Equal(t, "cell1-0000000101", report.Tablets[0].TabletAlias)
+
+	// Mutating operations fail.
+	_, err = wr.getTabletMap(ctx, aliases, failOnUnreadableTablets)
+	require.ErrorContains(t, err, "cannot read 1 of the 3 tablets: cell1-0000000102: fake error")
+
+	err = wr.SetSourceShards(ctx, "ks", "0", aliases[1:], nil)
+	require.ErrorContains(t, err, "cell1-0000000102: fake error")
+}
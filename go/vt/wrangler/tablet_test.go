@@ -18,15 +18,38 @@ package wrangler
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"path"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
+	"vitess.io/vitess/go/event"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/grpcclient"
 	"vitess.io/vitess/go/vt/logutil"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	vttimepb "vitess.io/vitess/go/vt/proto/vttime"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/events"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+	"vitess.io/vitess/go/vt/vttablet/queryservice/fakes"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+	"vitess.io/vitess/go/vt/vttablet/tabletconntest"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
 )
 
 // TestInitTabletShardConversion makes sure InitTablet converts the
@@ -219,3 +242,646 @@ func TestDeleteTabletShardNonExisting(t *testing.T) {
 	err = wr.DeleteTablet(context.Background(), tablet.Alias, true)
 	require.NoError(t, err)
 }
+
+// TestDeleteTabletCancelled tests that once the shard record of a primary
+// is updated, the tablet record and its replication graph entry are still
+// deleted if the context is cancelled.
+func TestDeleteTabletCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cell := "cell1"
+	ts := memorytopo.NewServer(ctx, cell)
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, nil)
+
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: cell,
+			Uid:  1,
+		},
+		Keyspace: "delete_tablet_cancelled",
+		Shard:    "0",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}
+
+	err := wr.TopoServer().InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+	require.NoError(t, err)
+	_, err = ts.UpdateShardFields(ctx, tablet.Keyspace, tablet.Shard, func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = tablet.Alias
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Cancel the action right after the shard record no longer points to
+	// the tablet.
+	actionCtx, actionCancel := context.WithCancel(ctx)
+	defer actionCancel()
+	event.AddListener(func(ev *events.ShardChange) {
+		if ev.KeyspaceName == tablet.Keyspace && ev.Status == "updated" {
+			actionCancel()
+		}
+	})
+
+	err = wr.DeleteTablet(actionCtx, tablet.Alias, true)
+	require.NoError(t, err)
+	require.Error(t, actionCtx.Err())
+
+	_, err = ts.GetTablet(ctx, tablet.Alias)
+	require.True(t, topo.IsErrType(err, topo.NoNode), err)
+	sri, err := ts.GetShardReplication(ctx, cell, tablet.Keyspace, tablet.Shard)
+	require.NoError(t, err)
+	require.Empty(t, sri.Nodes)
+	si, err := ts.GetShard(ctx, tablet.Keyspace, tablet.Shard)
+	require.NoError(t, err)
+	require.Nil(t, si.PrimaryAlias)
+}
+
+// changeTagsTMClient plays the tablet manager of the tablets ChangeTags is
+// called on: it applies the changes to the tags it holds under a lock, and
+// publishes its whole tablet record, as a state change does.
+type changeTagsTMClient struct {
+	tmclient.TabletManagerClient
+	ts *topo.Server
+
+	mu      sync.Mutex
+	tablets map[string]*topodatapb.Tablet
+	calls   int
+}
+
+func (tmc *changeTagsTMClient) ChangeTags(ctx context.Context, tablet *topodatapb.Tablet, tabletTags map[string]string, replace bool) (*tabletmanagerdatapb.ChangeTagsResponse, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	tmc.calls++
+	alias := topoproto.TabletAliasString(tablet.Alias)
+	state, ok := tmc.tablets[alias]
+	if !ok {
+		state = tablet.CloneVT()
+		tmc.tablets[alias] = state
+	}
+	if state.Tags == nil {
+		state.Tags = make(map[string]string)
+	}
+	for key, val := range tabletTags {
+		if val == "" {
+			delete(state.Tags, key)
+			continue
+		}
+		state.Tags[key] = val
+	}
+	if err := tmc.publish(ctx, state); err != nil {
+		return nil, err
+	}
+	return &tabletmanagerdatapb.ChangeTagsResponse{Tags: maps.Clone(state.Tags)}, nil
+}
+
+// publish overwrites the tablet record with the one of the tablet manager.
+func (tmc *changeTagsTMClient) publish(ctx context.Context, state *topodatapb.Tablet) error {
+	_, err := tmc.ts.UpdateTabletFields(ctx, state.Alias, func(tablet *topodatapb.Tablet) error {
+		proto.Reset(tablet)
+		proto.Merge(tablet, state)
+		return nil
+	})
+	return err
+}
+
+// TestUpdateTabletTags tests adding and removing tags on a tablet, and that
+// they survive the tablet publishing its state.
+func TestUpdateTabletTags(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cell := "cell1"
+	ts := memorytopo.NewServer(ctx, cell)
+	tmc := &changeTagsTMClient{ts: ts, tablets: map[string]*topodatapb.Tablet{}}
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmc)
+
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: cell,
+			Uid:  1,
+		},
+		Keyspace: "test",
+		Shard:    "0",
+		Type:     topodatapb.TabletType_REPLICA,
+	}
+	err := wr.TopoServer().InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+	require.NoError(t, err)
+
+	tags, err := wr.UpdateTabletTags(ctx, tablet.Alias, map[string]string{"maintenance": "true", "rack": "r1"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"maintenance": "true", "rack": "r1"}, tags)
+	require.Equal(t, 1, tmc.calls)
+
+	// A no-op update does not reach the tablet.
+	tags, err = wr.UpdateTabletTags(ctx, tablet.Alias, map[string]string{"rack": "r1"}, []string{"unknown"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"maintenance": "true", "rack": "r1"}, tags)
+	require.Equal(t, 1, tmc.calls)
+
+	// A state change of the tablet keeps the tags.
+	state := tmc.tablets["cell1-0000000001"]
+	state.Type = topodatapb.TabletType_RDONLY
+	require.NoError(t, tmc.publish(ctx, state))
+	ti, err := ts.GetTablet(ctx, tablet.Alias)
+	require.NoError(t, err)
+	require.Equal(t, topodatapb.TabletType_RDONLY, ti.Type)
+	require.Equal(t, map[string]string{"maintenance": "true", "rack": "r1"}, ti.Tags)
+
+	tags, err = wr.UpdateTabletTags(ctx, tablet.Alias, nil, []string{"maintenance", "rack"})
+	require.NoError(t, err)
+	require.NotNil(t, tags)
+	require.Empty(t, tags)
+	require.Equal(t, 2, tmc.calls)
+
+	ti, err = ts.GetTablet(ctx, tablet.Alias)
+	require.NoError(t, err)
+	require.Empty(t, ti.Tags)
+
+	_, err = wr.UpdateTabletTags(ctx, tablet.Alias, map[string]string{"rack": ""}, nil)
+	require.ErrorContains(t, err, "tag rack has an empty value")
+}
+
+// TestUpdateTabletTagsConcurrent makes sure concurrent tag updates on the
+// same tablet are not lost.
+func TestUpdateTabletTagsConcurrent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cell := "cell1"
+	ts := memorytopo.NewServer(ctx, cell)
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, &changeTagsTMClient{ts: ts, tablets: map[string]*topodatapb.Tablet{}})
+
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: cell,
+			Uid:  1,
+		},
+		Keyspace: "test",
+		Shard:    "0",
+		Type:     topodatapb.TabletType_REPLICA,
+	}
+	err := wr.TopoServer().InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+	require.NoError(t, err)
+
+	const numTags = 10
+	var wg sync.WaitGroup
+	errs := make([]error, numTags)
+	for i := 0; i < numTags; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = wr.UpdateTabletTags(ctx, tablet.Alias, map[string]string{fmt.Sprintf("tag%d", i): "v"}, nil)
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		require.NoError(t, err, "tag%d", i)
+	}
+
+	ti, err := ts.GetTablet(ctx, tablet.Alias)
+	require.NoError(t, err)
+	require.Len(t, ti.Tags, numTags)
+}
+
+// TestDeleteCorruptTablet tests that a tablet record that cannot be
+// unmarshaled is deleted, and its alias removed from every cell's
+// replication graph.
+func TestDeleteCorruptTablet(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, nil)
+
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "cell1",
+			Uid:  1,
+		},
+		Keyspace: "test",
+		Shard:    "0",
+		Type:     topodatapb.TabletType_REPLICA,
+	}
+	err := wr.TopoServer().InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+	require.NoError(t, err)
+
+	// A readable record must go through DeleteTablet.
+	err = wr.DeleteCorruptTablet(ctx, tablet.Alias, "test", "0")
+	require.ErrorContains(t, err, "is not corrupt")
+
+	// Simulate topo surgery gone wrong: the alias also shows up in cell2.
+	err = ts.UpdateShardReplicationFields(ctx, "cell2", "test", "0", func(sr *topodatapb.ShardReplication) error {
+		sr.Nodes = append(sr.Nodes, &topodatapb.ShardReplication_Node{TabletAlias: tablet.Alias})
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Overwrite the tablet record with a truncated field: a length-delimited
+	// alias claiming 16 bytes, with none following.
+	conn, err := ts.ConnForCell(ctx, "cell1")
+	require.NoError(t, err)
+	tabletPath := path.Join(topo.TabletsPath, topoproto.TabletAliasString(tablet.Alias), topo.TabletFile)
+	_, err = conn.Update(ctx, tabletPath, []byte{0x0a, 0x10}, nil)
+	require.NoError(t, err)
+
+	_, err = ts.GetTablet(ctx, tablet.Alias)
+	require.Error(t, err)
+	err = wr.DeleteTablet(ctx, tablet.Alias, false)
+	require.Error(t, err)
+
+	err = wr.DeleteCorruptTablet(ctx, tablet.Alias, "", "")
+	require.ErrorContains(t, err, "keyspace and shard are required")
+
+	// A wrong keyspace/shard hint must not delete the record, or the alias
+	// would be left behind in the real shard's replication graph.
+	err = wr.DeleteCorruptTablet(ctx, tablet.Alias, "test", "1")
+	require.ErrorContains(t, err, "cannot read shard test/1")
+	err = ts.CreateShard(ctx, "test", "80-")
+	require.NoError(t, err)
+	err = wr.DeleteCorruptTablet(ctx, tablet.Alias, "test", "80-")
+	require.ErrorContains(t, err, "is not in the replication graph of test/80- in any cell")
+	_, err = ts.GetTablet(ctx, tablet.Alias)
+	require.False(t, topo.IsErrType(err, topo.NoNode), "the corrupt record should still exist, got %v", err)
+
+	err = wr.DeleteCorruptTablet(ctx, tablet.Alias, "test", "0")
+	require.NoError(t, err)
+
+	_, err = ts.GetTablet(ctx, tablet.Alias)
+	require.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+	for _, cell := range []string{"cell1", "cell2"} {
+		sri, err := ts.GetShardReplication(ctx, cell, "test", "0")
+		require.NoError(t, err)
+		require.Empty(t, sri.Nodes, "cell %v", cell)
+	}
+
+	// Once the record is gone, there is nothing left to force.
+	err = wr.DeleteCorruptTablet(ctx, tablet.Alias, "test", "0")
+	require.ErrorContains(t, err, "does not exist")
+}
+
+// servingQueryService answers StreamHealth with a single health record.
+type servingQueryService struct {
+	queryservice.QueryService
+	serving bool
+}
+
+func (qs *servingQueryService) StreamHealth(ctx context.Context, callback func(*querypb.StreamHealthResponse) error) error {
+	return callback(&querypb.StreamHealthResponse{
+		Serving:       qs.serving,
+		RealtimeStats: &querypb.RealtimeStats{},
+	})
+}
+
+func (qs *servingQueryService) Close(ctx context.Context) error {
+	return nil
+}
+
+func TestChangeTabletTypeImpact(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, nil)
+
+	serving := map[uint32]bool{}
+	dialerName := fmt.Sprintf("TabletTypeImpactTest-%d", rand.IntN(1000000000))
+	tabletconn.RegisterDialer(dialerName, func(ctx context.Context, tablet *topodatapb.Tablet, failFast grpcclient.FailFast) (queryservice.QueryService, error) {
+		return &servingQueryService{
+			QueryService: fakes.ErrorQueryService,
+			serving:      serving[tablet.Alias.Uid],
+		}, nil
+	})
+	tabletconntest.SetProtocol("go.vt.wrangler.tablet_test", dialerName)
+
+	addTablet := func(cell string, uid uint32, tabletType topodatapb.TabletType, isServing bool) *topodatapb.TabletAlias {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: cell, Uid: uid},
+			Keyspace: "test",
+			Shard:    "0",
+			Type:     tabletType,
+		}
+		err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+		serving[uid] = isServing
+		return tablet.Alias
+	}
+	primary := addTablet("cell1", 100, topodatapb.TabletType_PRIMARY, true)
+	replica := addTablet("cell1", 101, topodatapb.TabletType_REPLICA, true)
+	addTablet("cell1", 102, topodatapb.TabletType_REPLICA, true)
+	addTablet("cell1", 103, topodatapb.TabletType_REPLICA, false)
+	addTablet("cell1", 104, topodatapb.TabletType_RDONLY, true)
+	spare := addTablet("cell1", 105, topodatapb.TabletType_SPARE, false)
+	addTablet("cell2", 200, topodatapb.TabletType_REPLICA, true)
+
+	// Only 102 is left: 103 isn't serving, 104 is of another type and 200
+	// is in another cell.
+	impact, err := wr.ChangeTabletTypeImpact(ctx, replica, topodatapb.TabletType_DRAINED, 1)
+	require.NoError(t, err)
+	require.Equal(t, &TabletTypeChangeImpact{
+		TabletAlias:      replica,
+		Keyspace:         "test",
+		Shard:            "0",
+		CurrentType:      topodatapb.TabletType_REPLICA,
+		RequestedType:    topodatapb.TabletType_DRAINED,
+		Allowed:          true,
+		RemainingServing: 1,
+		MinRemaining:     1,
+	}, impact)
+
+	impact, err = wr.ChangeTabletTypeImpact(ctx, replica, topodatapb.TabletType_DRAINED, 2)
+	require.NoError(t, err)
+	require.False(t, impact.Allowed)
+	require.Contains(t, impact.Reason, "only 1 serving replica tablets would remain")
+	err = wr.ChangeTabletTypeWithMinRemaining(ctx, replica, topodatapb.TabletType_DRAINED, 2)
+	require.ErrorContains(t, err, "fewer than the minimum of 2")
+
+	// A no-op change leaves the tablet itself in place.
+	impact, err = wr.ChangeTabletTypeImpact(ctx, replica, topodatapb.TabletType_REPLICA, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, impact.RemainingServing)
+
+	// Leaving a non-serving type is never held back by the threshold.
+	impact, err = wr.ChangeTabletTypeImpact(ctx, spare, topodatapb.TabletType_REPLICA, 5)
+	require.NoError(t, err)
+	require.True(t, impact.Allowed)
+
+	impact, err = wr.ChangeTabletTypeImpact(ctx, primary, topodatapb.TabletType_REPLICA, 0)
+	require.NoError(t, err)
+	require.False(t, impact.Allowed)
+	require.Contains(t, impact.Reason, "PlannedReparentShard")
+
+	impact, err = wr.ChangeTabletTypeImpact(ctx, replica, topodatapb.TabletType_PRIMARY, 0)
+	require.NoError(t, err)
+	require.False(t, impact.Allowed)
+}
+
+// replicationUntilTMClient reports the next of positions on every
+// ReplicationStatus call, sticking to the last one.
+type replicationUntilTMClient struct {
+	tmclient.TabletManagerClient
+
+	mu        sync.Mutex
+	untilPos  string
+	positions []string
+}
+
+func (tmc *replicationUntilTMClient) StartReplicationUntilAfter(ctx context.Context, tablet *topodatapb.Tablet, position string, duration time.Duration) error {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	tmc.untilPos = position
+	return nil
+}
+
+func (tmc *replicationUntilTMClient) ReplicationStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.Status, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	pos := tmc.positions[0]
+	if len(tmc.positions) > 1 {
+		tmc.positions = tmc.positions[1:]
+	}
+	return &replicationdatapb.Status{Position: pos}, nil
+}
+
+func TestStartReplicationUntilAfter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defer func(interval time.Duration) { replicationPositionPollInterval = interval }(replicationPositionPollInterval)
+	replicationPositionPollInterval = 10 * time.Millisecond
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	tmc := &replicationUntilTMClient{}
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmc)
+	tablet := &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: 1},
+		Keyspace: "ks",
+		Shard:    "0",
+		Type:     topodatapb.TabletType_REPLICA,
+	}
+	err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+	require.NoError(t, err)
+
+	const (
+		pos5 = "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-5"
+		pos7 = "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-7"
+		pos9 = "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-9"
+	)
+
+	_, err = wr.StartReplicationUntilAfter(ctx, tablet.Alias, "MySQL56/not-a-gtid", time.Second)
+	require.ErrorContains(t, err, `invalid position "MySQL56/not-a-gtid"`)
+	_, err = wr.StartReplicationUntilAfter(ctx, tablet.Alias, "", time.Second)
+	require.ErrorContains(t, err, "a target position is required")
+	require.Empty(t, tmc.untilPos)
+
+	tmc.positions = []string{pos5, pos7, pos9}
+	pos, err := wr.StartReplicationUntilAfter(ctx, tablet.Alias, pos7, 10*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, pos7, pos)
+	require.Equal(t, pos7, tmc.untilPos)
+
+	// The tablet never gets past pos7.
+	tmc.positions = []string{pos5, pos7}
+	pos, err = wr.StartReplicationUntilAfter(ctx, tablet.Alias, pos9, 100*time.Millisecond)
+	require.ErrorContains(t, err, "did not reach position "+pos9+" within 100ms")
+	require.Equal(t, pos7, pos)
+}
+
+func TestResolveTabletAlias(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, nil)
+
+	addTablet := func(cell string, uid uint32, hostname string, vtPort int32) {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: cell, Uid: uid},
+			Hostname: hostname,
+			PortMap:  map[string]int32{"vt": vtPort, "grpc": vtPort + 1000},
+			Keyspace: "test",
+			Shard:    "0",
+			Type:     topodatapb.TabletType_REPLICA,
+		}
+		err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+	}
+	addTablet("cell1", 100, "db1.example.com", 15100)
+	addTablet("cell1", 101, "db2.example.com", 15101)
+	addTablet("cell1", 102, "db2.example.com", 15102)
+	addTablet("cell2", 200, "db3.example.com", 15200)
+	addTablet("cell2", 201, "cell3-1", 15201)
+
+	tests := []struct {
+		name     string
+		in       string
+		cells    []string
+		expected string
+		code     vtrpcpb.Code
+		errMsg   string
+	}{
+		{
+			name:     "tablet alias",
+			in:       "cell1-0000000101",
+			expected: "cell1-0000000101",
+		},
+		{
+			name:     "alias of a tablet without a record",
+			in:       "cell2-300",
+			expected: "cell2-0000000300",
+		},
+		{
+			name:     "hostname",
+			in:       "db1.example.com",
+			expected: "cell1-0000000100",
+		},
+		{
+			name:     "hostname is not case sensitive",
+			in:       "DB3.example.com",
+			expected: "cell2-0000000200",
+		},
+		{
+			name:     "hostname that parses as an alias of an unknown cell",
+			in:       "cell3-1",
+			expected: "cell2-0000000201",
+		},
+		{
+			name:     "hostname and port",
+			in:       "db2.example.com:15102",
+			expected: "cell1-0000000102",
+		},
+		{
+			name:     "hostname and grpc port",
+			in:       "db2.example.com:16101",
+			expected: "cell1-0000000101",
+		},
+		{
+			name:   "ambiguous hostname",
+			in:     "db2.example.com",
+			code:   vtrpcpb.Code_FAILED_PRECONDITION,
+			errMsg: "db2.example.com matches 2 tablets: cell1-0000000101 (db2.example.com:15101), cell1-0000000102 (db2.example.com:15102)",
+		},
+		{
+			name:   "unknown hostname",
+			in:     "db4.example.com",
+			code:   vtrpcpb.Code_NOT_FOUND,
+			errMsg: "db4.example.com is neither a tablet alias nor the hostname of a tablet",
+		},
+		{
+			name:   "unknown port",
+			in:     "db1.example.com:3306",
+			code:   vtrpcpb.Code_NOT_FOUND,
+			errMsg: "db1.example.com:3306 is neither a tablet alias nor the hostname of a tablet",
+		},
+		{
+			name:   "hostname in another cell",
+			in:     "db1.example.com",
+			cells:  []string{"cell2"},
+			code:   vtrpcpb.Code_NOT_FOUND,
+			errMsg: "in cells [cell2]",
+		},
+		{
+			name:     "hostname in the given cell",
+			in:       "db3.example.com",
+			cells:    []string{"cell2"},
+			expected: "cell2-0000000200",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alias, err := wr.ResolveTabletAlias(ctx, tt.in, tt.cells)
+			if tt.errMsg != "" {
+				require.ErrorContains(t, err, tt.errMsg)
+				require.Equal(t, tt.code, vterrors.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, topoproto.TabletAliasString(alias))
+		})
+	}
+}
+
+func TestAuditTablets(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, nil)
+
+	dead := map[uint32]bool{}
+	dialerName := fmt.Sprintf("AuditTabletsTest-%d", rand.IntN(1000000000))
+	tabletconn.RegisterDialer(dialerName, func(ctx context.Context, tablet *topodatapb.Tablet, failFast grpcclient.FailFast) (queryservice.QueryService, error) {
+		if dead[tablet.Alias.Uid] {
+			return nil, fmt.Errorf("connection refused")
+		}
+		return &servingQueryService{
+			QueryService: fakes.ErrorQueryService,
+			serving:      true,
+		}, nil
+	})
+	tabletconntest.SetProtocol("go.vt.wrangler.tablet_test", dialerName)
+
+	oldLookupHost := lookupHost
+	lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		if host == "gone" {
+			return nil, fmt.Errorf("no such host")
+		}
+		return []string{"10.0.0.1"}, nil
+	}
+	defer func() { lookupHost = oldLookupHost }()
+
+	recent := protoutil.TimeToProto(time.Now().Add(-time.Hour))
+	old := protoutil.TimeToProto(time.Now().Add(-30 * 24 * time.Hour))
+	addTablet := func(cell string, uid uint32, tabletType topodatapb.TabletType, isDead bool, hostname string, startTime *vttimepb.Time) {
+		tablet := &topodatapb.Tablet{
+			Alias:           &topodatapb.TabletAlias{Cell: cell, Uid: uid},
+			Hostname:        hostname,
+			Keyspace:        "test",
+			Shard:           "0",
+			Type:            tabletType,
+			TabletStartTime: startTime,
+		}
+		err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+		dead[uid] = isDead
+	}
+	addTablet("cell1", 100, topodatapb.TabletType_PRIMARY, true, "gone", nil)
+	addTablet("cell1", 101, topodatapb.TabletType_REPLICA, true, "gone", old)
+	addTablet("cell1", 102, topodatapb.TabletType_REPLICA, false, "alive", recent)
+	addTablet("cell1", 103, topodatapb.TabletType_REPLICA, true, "alive", old)
+	addTablet("cell2", 200, topodatapb.TabletType_RDONLY, false, "alive", nil)
+
+	audits, err := wr.AuditTablets(ctx, nil, time.Second, 7*24*time.Hour, 2)
+	require.NoError(t, err)
+	var aliases []string
+	for _, audit := range audits {
+		aliases = append(aliases, audit.TabletAlias)
+	}
+	require.Equal(t, []string{"cell1-0000000100", "cell1-0000000101", "cell1-0000000103", "cell2-0000000200"}, aliases)
+	require.True(t, audits[0].Stale())
+	require.True(t, audits[1].Stale())
+	require.Equal(t, time.Unix(old.Seconds, 0).UTC().Format(time.RFC3339), audits[1].StartTime)
+	require.Contains(t, audits[1].HealthError, "connection refused")
+	require.False(t, audits[2].Stale())
+	require.True(t, audits[2].NoHealth)
+	require.False(t, audits[2].UnresolvableHostname)
+	require.False(t, audits[3].Stale())
+	require.True(t, audits[3].OlderThanCutoff)
+	require.False(t, audits[3].NoHealth)
+
+	// Only the stale replica is deleted, the primary is left alone.
+	err = wr.DeleteStaleTablets(ctx, audits)
+	require.ErrorContains(t, err, "refusing to delete a PRIMARY tablet")
+	require.False(t, audits[0].Deleted)
+	require.True(t, audits[1].Deleted)
+	require.False(t, audits[2].Deleted)
+	_, err = ts.GetTablet(ctx, &topodatapb.TabletAlias{Cell: "cell1", Uid: 101})
+	require.True(t, topo.IsErrType(err, topo.NoNode))
+	_, err = ts.GetTablet(ctx, &topodatapb.TabletAlias{Cell: "cell1", Uid: 100})
+	require.NoError(t, err)
+
+	t.Run("single cell without age cutoff", func(t *testing.T) {
+		audits, err := wr.AuditTablets(ctx, []string{"cell2"}, time.Second, 0, 1)
+		require.NoError(t, err)
+		require.Empty(t, audits)
+	})
+}
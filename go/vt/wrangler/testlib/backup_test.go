@@ -19,6 +19,7 @@ package testlib
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"testing"
@@ -32,6 +33,7 @@ import (
 	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/grpcclient"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
@@ -40,9 +42,10 @@ import (
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtenv"
-	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
 	"vitess.io/vitess/go/vt/wrangler"
 
+	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
@@ -91,7 +94,7 @@ func testBackupRestore(t *testing.T, cDetails *compressionDetails) error {
 	db := fakesqldb.New(t)
 	defer db.Close()
 	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
 	vp := NewVtctlPipe(ctx, t, ts)
 	defer vp.Close()
 
@@ -343,7 +346,7 @@ func TestBackupRestoreLagged(t *testing.T) {
 	db := fakesqldb.New(t)
 	defer db.Close()
 	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
 	vp := NewVtctlPipe(ctx, t, ts)
 	defer vp.Close()
 
@@ -563,7 +566,7 @@ func TestRestoreUnreachablePrimary(t *testing.T) {
 	db := fakesqldb.New(t)
 	defer db.Close()
 	ts := memorytopo.NewServer(ctx, "cell1")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
 	vp := NewVtctlPipe(ctx, t, ts)
 	defer vp.Close()
 
@@ -739,7 +742,7 @@ func TestDisableActiveReparents(t *testing.T) {
 	db := fakesqldb.New(t)
 	defer db.Close()
 	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
 	vp := NewVtctlPipe(ctx, t, ts)
 	defer vp.Close()
 
@@ -877,3 +880,97 @@ func TestDisableActiveReparents(t *testing.T) {
 	assert.False(t, destTablet.FakeMysqlDaemon.Replicating)
 	assert.True(t, destTablet.FakeMysqlDaemon.Running)
 }
+
+// TestBackupShardFakeBackup checks the tablet BackupShard picks, and that
+// the tablet goes through BACKUP and returns to its type and to serving,
+// with fake tablets that simulate the backups.
+func TestBackupShardFakeBackup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
+	vp := NewVtctlPipe(ctx, t, ts)
+	defer vp.Close()
+
+	primary := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_PRIMARY, nil)
+	primary.FakeMysqlDaemon.ReadOnly = false
+	primary.FakeMysqlDaemon.Replicating = false
+	primary.StartActionLoop(t, wr)
+	defer primary.StopActionLoop(t)
+
+	// The RDONLY tablet lags less than the REPLICA one, so it is picked
+	// first.
+	newReplica := func(uid uint32, tabletType topodatapb.TabletType, lag uint32) *FakeTablet {
+		ft := NewFakeTablet(t, wr, "cell1", uid, tabletType, nil)
+		ft.FakeBackup = true
+		ft.BackupDuration = 500 * time.Millisecond
+		ft.FakeMysqlDaemon.ReadOnly = true
+		ft.FakeMysqlDaemon.ReplicationLagSeconds = lag
+		ft.FakeMysqlDaemon.SetReplicationSourceInputs = append(ft.FakeMysqlDaemon.SetReplicationSourceInputs, topoproto.MysqlAddr(primary.Tablet))
+		ft.FakeMysqlDaemon.ExpectedExecuteSuperQueryList = []string{
+			// These 3 statements come from tablet startup
+			"STOP REPLICA",
+			"FAKE SET SOURCE",
+			"START REPLICA",
+		}
+		ft.StartActionLoop(t, wr)
+		return ft
+	}
+	replica := newReplica(1, topodatapb.TabletType_REPLICA, 5)
+	defer replica.StopActionLoop(t)
+	rdonly := newReplica(2, topodatapb.TabletType_RDONLY, 1)
+	defer rdonly.StopActionLoop(t)
+
+	backupErr := make(chan error, 1)
+	go func() {
+		backupErr <- vp.Run([]string{"BackupShard", "test_keyspace/0"})
+	}()
+
+	// While the backup runs, the RDONLY tablet is not eligible for another
+	// one, so the REPLICA one would be picked.
+	require.Eventually(t, func() bool {
+		ti, err := ts.GetTablet(ctx, rdonly.Tablet.Alias)
+		return err == nil && ti.Type == topodatapb.TabletType_BACKUP
+	}, 5*time.Second, 10*time.Millisecond)
+	tablet, err := wr.SelectBackupTablet(ctx, "test_keyspace", "0", false, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "cell1-0000000001", topoproto.TabletAliasString(tablet.Alias))
+	require.NoError(t, <-backupErr)
+
+	// The RDONLY tablet is back to its type, and serving.
+	ti, err := ts.GetTablet(ctx, rdonly.Tablet.Alias)
+	require.NoError(t, err)
+	assert.Equal(t, topodatapb.TabletType_RDONLY, ti.Type)
+	var transitions []string
+	for _, shr := range rdonly.HealthBroadcasts() {
+		transitions = append(transitions, fmt.Sprintf("%v serving=%v", topoproto.TabletTypeLString(shr.Target.TabletType), shr.Serving))
+	}
+	assert.Equal(t, []string{"rdonly serving=true", "backup serving=false", "rdonly serving=true"}, transitions)
+	assert.Len(t, replica.HealthBroadcasts(), 1)
+
+	conn, err := tabletconn.GetDialer()(ctx, ti.Tablet, grpcclient.FailFast(false))
+	require.NoError(t, err)
+	defer conn.Close(ctx)
+	var health *querypb.StreamHealthResponse
+	err = conn.StreamHealth(ctx, func(shr *querypb.StreamHealthResponse) error {
+		health = shr
+		return io.EOF
+	})
+	require.NoError(t, err)
+	require.NotNil(t, health)
+	assert.Equal(t, topodatapb.TabletType_RDONLY, health.Target.TabletType)
+	assert.True(t, health.Serving)
+
+	// The helpers drive the RESTORE transition the same way.
+	require.NoError(t, replica.EnterRestoreMode(ctx))
+	require.ErrorContains(t, replica.EnterBackupMode(ctx), "tablet cell1-0000000001 is already in restore mode")
+	require.NoError(t, replica.RestoreOriginalType(ctx))
+	ti, err = ts.GetTablet(ctx, replica.Tablet.Alias)
+	require.NoError(t, err)
+	assert.Equal(t, topodatapb.TabletType_REPLICA, ti.Type)
+	broadcasts := replica.HealthBroadcasts()
+	require.Len(t, broadcasts, 3)
+	assert.Equal(t, topodatapb.TabletType_RESTORE, broadcasts[1].Target.TabletType)
+	assert.False(t, broadcasts[1].Serving)
+	assert.True(t, broadcasts[2].Serving)
+}
@@ -31,7 +31,6 @@ import (
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vreplication"
-	"vitess.io/vitess/go/vt/vttablet/tmclient"
 	"vitess.io/vitess/go/vt/wrangler"
 
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
@@ -56,12 +55,12 @@ func copySchema(t *testing.T, useShardAsSource bool) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
 	vp := NewVtctlPipe(ctx, t, ts)
 	defer vp.Close()
 
 	if err := ts.CreateKeyspace(context.Background(), "ks", &topodatapb.Keyspace{}); err != nil {
-		require("CreateKeyspace failed: %v", err)
+		t.Fatalf("CreateKeyspace failed: %v", err)
 	}
 
 	sourcePrimaryDb := fakesqldb.New(t).SetName("sourcePrimaryDb")
@@ -162,17 +161,17 @@ func copySchema(t *testing.T, useShardAsSource bool) {
 	waitForShardPrimary(t, wr, destinationPrimary.Tablet)
 
 	if err := vp.Run([]string{"CopySchemaShard", "--include-views", source, "ks/-40"}); err != nil {
-		require("CopySchemaShard failed: %v", err)
+		t.Fatalf("CopySchemaShard failed: %v", err)
 	}
 
 	// Check call count on destinationPrimaryDb
 	if count := destinationPrimaryDb.GetQueryCalledNum(createDb); count != 1 {
-		assert("CopySchemaShard did not create the db exactly once. Query count: %v", count)
+		t.Errorf("CopySchemaShard did not create the db exactly once. Query count: %v", count)
 	}
 	if count := destinationPrimaryDb.GetQueryCalledNum(createTable); count != 1 {
-		assert("CopySchemaShard did not create the table exactly once. Query count: %v", count)
+		t.Errorf("CopySchemaShard did not create the table exactly once. Query count: %v", count)
 	}
 	if count := destinationPrimaryDb.GetQueryCalledNum(createTableView); count != 1 {
-		assert("CopySchemaShard did not create the table view exactly once. Query count: %v", count)
+		t.Errorf("CopySchemaShard did not create the table view exactly once. Query count: %v", count)
 	}
 }
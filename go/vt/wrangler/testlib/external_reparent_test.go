@@ -30,7 +30,6 @@ import (
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools"
 	"vitess.io/vitess/go/vt/vtenv"
-	"vitess.io/vitess/go/vt/vttablet/tmclient"
 	"vitess.io/vitess/go/vt/wrangler"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -50,7 +49,7 @@ func TestTabletExternallyReparentedBasic(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
 	ts := memorytopo.NewServer(ctx, "cell1")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
 	vp := NewVtctlPipe(ctx, t, ts)
 	defer vp.Close()
 
@@ -59,9 +58,9 @@ func TestTabletExternallyReparentedBasic(t *testing.T) {
 	newPrimary := NewFakeTablet(t, wr, "cell1", 1, topodatapb.TabletType_REPLICA, nil)
 
 	// Build keyspace graph
-	err := topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, oldPrimary.Tablet.Keyspace, []string{"cell1"}, false)
+	err := topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, oldPrimary.Tablet.Keyspace, []string{"cell1"}, false, false)
 	if err != nil {
-		require("RebuildKeyspaceLocked failed: %v", err)
+		t.Fatalf("RebuildKeyspaceLocked failed: %v", err)
 	}
 
 	// On the elected primary, we will respond to
@@ -77,16 +76,16 @@ func TestTabletExternallyReparentedBasic(t *testing.T) {
 	// First test: reparent to the same primary, make sure it works
 	// as expected.
 	if err := vp.Run([]string{"TabletExternallyReparented", topoproto.TabletAliasString(oldPrimary.Tablet.Alias)}); err != nil {
-		require("TabletExternallyReparented(same primary) should have worked: %v", err)
+		t.Fatalf("TabletExternallyReparented(same primary) should have worked: %v", err)
 	}
 
 	// check the old primary is still primary
 	tablet, err := ts.GetTablet(ctx, oldPrimary.Tablet.Alias)
 	if err != nil {
-		require("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
+		t.Fatalf("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
 	}
 	if tablet.Type != topodatapb.TabletType_PRIMARY {
-		require("old primary should be PRIMARY but is: %v", tablet.Type)
+		t.Fatalf("old primary should be PRIMARY but is: %v", tablet.Type)
 	}
 
 	oldPrimary.FakeMysqlDaemon.SetReplicationSourceInputs = append(oldPrimary.FakeMysqlDaemon.SetReplicationSourceInputs, topoproto.MysqlAddr(newPrimary.Tablet))
@@ -98,16 +97,16 @@ func TestTabletExternallyReparentedBasic(t *testing.T) {
 	// This tests the good case, where everything works as planned
 	t.Logf("TabletExternallyReparented(new primary) expecting success")
 	if err := wr.TabletExternallyReparented(ctx, newPrimary.Tablet.Alias); err != nil {
-		require("TabletExternallyReparented(replica) failed: %v", err)
+		t.Fatalf("TabletExternallyReparented(replica) failed: %v", err)
 	}
 
 	// check the new primary is primary
 	tablet, err = ts.GetTablet(ctx, newPrimary.Tablet.Alias)
 	if err != nil {
-		require("GetTablet(%v) failed: %v", newPrimary.Tablet.Alias, err)
+		t.Fatalf("GetTablet(%v) failed: %v", newPrimary.Tablet.Alias, err)
 	}
 	if tablet.Type != topodatapb.TabletType_PRIMARY {
-		require("new primary should be PRIMARY but is: %v", tablet.Type)
+		t.Fatalf("new primary should be PRIMARY but is: %v", tablet.Type)
 	}
 
 	// We have to wait for shard sync to do its magic in the background
@@ -116,14 +115,14 @@ func TestTabletExternallyReparentedBasic(t *testing.T) {
 		if time.Since(startTime) > 10*time.Second /* timeout */ {
 			tablet, err = ts.GetTablet(ctx, oldPrimary.Tablet.Alias)
 			if err != nil {
-				require("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
+				t.Fatalf("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
 			}
-			require("old primary (%v) should be replica but is: %v", topoproto.TabletAliasString(oldPrimary.Tablet.Alias), tablet.Type)
+			t.Fatalf("old primary (%v) should be replica but is: %v", topoproto.TabletAliasString(oldPrimary.Tablet.Alias), tablet.Type)
 		}
 		// check the old primary was converted to replica
 		tablet, err = ts.GetTablet(ctx, oldPrimary.Tablet.Alias)
 		if err != nil {
-			require("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
+			t.Fatalf("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
 		}
 		if tablet.Type == topodatapb.TabletType_REPLICA {
 			break
@@ -143,7 +142,7 @@ func TestTabletExternallyReparentedToReplica(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
 	ts := memorytopo.NewServer(ctx, "cell1")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
 
 	// Create an old primary, a new primary, two good replicas, one bad replica
 	oldPrimary := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_PRIMARY, nil)
@@ -152,9 +151,9 @@ func TestTabletExternallyReparentedToReplica(t *testing.T) {
 	newPrimary.FakeMysqlDaemon.Replicating = true
 
 	// Build keyspace graph
-	err := topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, oldPrimary.Tablet.Keyspace, []string{"cell1"}, false)
+	err := topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, oldPrimary.Tablet.Keyspace, []string{"cell1"}, false, false)
 	if err != nil {
-		require("RebuildKeyspaceLocked failed: %v", err)
+		t.Fatalf("RebuildKeyspaceLocked failed: %v", err)
 	}
 
 	// On the elected primary, we will respond to
@@ -178,16 +177,16 @@ func TestTabletExternallyReparentedToReplica(t *testing.T) {
 	// This tests a bad case: the new designated primary is a replica at mysql level,
 	// but we should do what we're told anyway.
 	if err := wr.TabletExternallyReparented(ctx, newPrimary.Tablet.Alias); err != nil {
-		require("TabletExternallyReparented(replica) error: %v", err)
+		t.Fatalf("TabletExternallyReparented(replica) error: %v", err)
 	}
 
 	// check that newPrimary is primary
 	tablet, err := ts.GetTablet(ctx, newPrimary.Tablet.Alias)
 	if err != nil {
-		require("GetTablet(%v) failed: %v", newPrimary.Tablet.Alias, err)
+		t.Fatalf("GetTablet(%v) failed: %v", newPrimary.Tablet.Alias, err)
 	}
 	if tablet.Type != topodatapb.TabletType_PRIMARY {
-		require("new primary should be PRIMARY but is: %v", tablet.Type)
+		t.Fatalf("new primary should be PRIMARY but is: %v", tablet.Type)
 	}
 
 	// We have to wait for shard sync to do its magic in the background
@@ -196,14 +195,14 @@ func TestTabletExternallyReparentedToReplica(t *testing.T) {
 		if time.Since(startTime) > 10*time.Second /* timeout */ {
 			tablet, err = ts.GetTablet(ctx, oldPrimary.Tablet.Alias)
 			if err != nil {
-				require("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
+				t.Fatalf("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
 			}
-			require("old primary (%v) should be replica but is: %v", topoproto.TabletAliasString(oldPrimary.Tablet.Alias), tablet.Type)
+			t.Fatalf("old primary (%v) should be replica but is: %v", topoproto.TabletAliasString(oldPrimary.Tablet.Alias), tablet.Type)
 		}
 		// check the old primary was converted to replica
 		tablet, err = ts.GetTablet(ctx, oldPrimary.Tablet.Alias)
 		if err != nil {
-			require("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
+			t.Fatalf("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
 		}
 		if tablet.Type == topodatapb.TabletType_REPLICA {
 			break
@@ -226,7 +225,7 @@ func TestTabletExternallyReparentedWithDifferentMysqlPort(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
 	ts := memorytopo.NewServer(ctx, "cell1")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
 
 	// Create an old primary, a new primary, two good replicas, one bad replica
 	oldPrimary := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_PRIMARY, nil)
@@ -234,9 +233,9 @@ func TestTabletExternallyReparentedWithDifferentMysqlPort(t *testing.T) {
 	goodReplica := NewFakeTablet(t, wr, "cell1", 2, topodatapb.TabletType_REPLICA, nil)
 
 	// Build keyspace graph
-	err := topotools.RebuildKeyspace(context.Background(), logutil.NewConsoleLogger(), ts, oldPrimary.Tablet.Keyspace, []string{"cell1"}, false)
+	err := topotools.RebuildKeyspace(context.Background(), logutil.NewConsoleLogger(), ts, oldPrimary.Tablet.Keyspace, []string{"cell1"}, false, false)
 	if err != nil {
-		require("RebuildKeyspaceLocked failed: %v", err)
+		t.Fatalf("RebuildKeyspaceLocked failed: %v", err)
 	}
 	// Now we're restarting mysql on a different port, 3301->3303
 	// but without updating the Tablet record in topology.
@@ -273,15 +272,15 @@ func TestTabletExternallyReparentedWithDifferentMysqlPort(t *testing.T) {
 	// This tests the good case, where everything works as planned
 	t.Logf("TabletExternallyReparented(new primary) expecting success")
 	if err := wr.TabletExternallyReparented(ctx, newPrimary.Tablet.Alias); err != nil {
-		require("TabletExternallyReparented(replica) failed: %v", err)
+		t.Fatalf("TabletExternallyReparented(replica) failed: %v", err)
 	}
 	// check the new primary is primary
 	tablet, err := ts.GetTablet(ctx, newPrimary.Tablet.Alias)
 	if err != nil {
-		require("GetTablet(%v) failed: %v", newPrimary.Tablet.Alias, err)
+		t.Fatalf("GetTablet(%v) failed: %v", newPrimary.Tablet.Alias, err)
 	}
 	if tablet.Type != topodatapb.TabletType_PRIMARY {
-		require("new primary should be PRIMARY but is: %v", tablet.Type)
+		t.Fatalf("new primary should be PRIMARY but is: %v", tablet.Type)
 	}
 
 	// We have to wait for shard sync to do its magic in the background
@@ -290,14 +289,14 @@ func TestTabletExternallyReparentedWithDifferentMysqlPort(t *testing.T) {
 		if time.Since(startTime) > 10*time.Second /* timeout */ {
 			tablet, err = ts.GetTablet(ctx, oldPrimary.Tablet.Alias)
 			if err != nil {
-				require("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
+				t.Fatalf("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
 			}
-			require("old primary (%v) should be replica but is: %v", topoproto.TabletAliasString(oldPrimary.Tablet.Alias), tablet.Type)
+			t.Fatalf("old primary (%v) should be replica but is: %v", topoproto.TabletAliasString(oldPrimary.Tablet.Alias), tablet.Type)
 		}
 		// check the old primary was converted to replica
 		tablet, err = ts.GetTablet(ctx, oldPrimary.Tablet.Alias)
 		if err != nil {
-			require("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
+			t.Fatalf("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
 		}
 		if tablet.Type == topodatapb.TabletType_REPLICA {
 			break
@@ -319,7 +318,7 @@ func TestTabletExternallyReparentedContinueOnUnexpectedPrimary(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
 	ts := memorytopo.NewServer(ctx, "cell1")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
 
 	// Create an old primary, a new primary, two good replicas, one bad replica
 	oldPrimary := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_PRIMARY, nil)
@@ -327,9 +326,9 @@ func TestTabletExternallyReparentedContinueOnUnexpectedPrimary(t *testing.T) {
 	goodReplica := NewFakeTablet(t, wr, "cell1", 2, topodatapb.TabletType_REPLICA, nil)
 
 	// Build keyspace graph
-	err := topotools.RebuildKeyspace(context.Background(), logutil.NewConsoleLogger(), ts, oldPrimary.Tablet.Keyspace, []string{"cell1"}, false)
+	err := topotools.RebuildKeyspace(context.Background(), logutil.NewConsoleLogger(), ts, oldPrimary.Tablet.Keyspace, []string{"cell1"}, false, false)
 	if err != nil {
-		require("RebuildKeyspaceLocked failed: %v", err)
+		t.Fatalf("RebuildKeyspaceLocked failed: %v", err)
 	}
 	// On the elected primary, we will respond to
 	// TabletActionReplicaWasPromoted, so we need a MysqlDaemon
@@ -362,15 +361,15 @@ func TestTabletExternallyReparentedContinueOnUnexpectedPrimary(t *testing.T) {
 	// This tests the good case, where everything works as planned
 	t.Logf("TabletExternallyReparented(new primary) expecting success")
 	if err := wr.TabletExternallyReparented(ctx, newPrimary.Tablet.Alias); err != nil {
-		require("TabletExternallyReparented(replica) failed: %v", err)
+		t.Fatalf("TabletExternallyReparented(replica) failed: %v", err)
 	}
 	// check the new primary is primary
 	tablet, err := ts.GetTablet(ctx, newPrimary.Tablet.Alias)
 	if err != nil {
-		require("GetTablet(%v) failed: %v", newPrimary.Tablet.Alias, err)
+		t.Fatalf("GetTablet(%v) failed: %v", newPrimary.Tablet.Alias, err)
 	}
 	if tablet.Type != topodatapb.TabletType_PRIMARY {
-		require("new primary should be PRIMARY but is: %v", tablet.Type)
+		t.Fatalf("new primary should be PRIMARY but is: %v", tablet.Type)
 	}
 	// We have to wait for shard sync to do its magic in the background
 	startTime := time.Now()
@@ -378,14 +377,14 @@ func TestTabletExternallyReparentedContinueOnUnexpectedPrimary(t *testing.T) {
 		if time.Since(startTime) > 10*time.Second /* timeout */ {
 			tablet, err = ts.GetTablet(ctx, oldPrimary.Tablet.Alias)
 			if err != nil {
-				require("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
+				t.Fatalf("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
 			}
-			require("old primary (%v) should be replica but is: %v", topoproto.TabletAliasString(oldPrimary.Tablet.Alias), tablet.Type)
+			t.Fatalf("old primary (%v) should be replica but is: %v", topoproto.TabletAliasString(oldPrimary.Tablet.Alias), tablet.Type)
 		}
 		// check the old primary was converted to replica
 		tablet, err = ts.GetTablet(ctx, oldPrimary.Tablet.Alias)
 		if err != nil {
-			require("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
+			t.Fatalf("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
 		}
 		if tablet.Type == topodatapb.TabletType_REPLICA {
 			break
@@ -405,7 +404,7 @@ func TestTabletExternallyReparentedRerun(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
 	ts := memorytopo.NewServer(ctx, "cell1")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
 
 	// Create an old primary, a new primary, and a good replica.
 	oldPrimary := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_PRIMARY, nil)
@@ -413,9 +412,9 @@ func TestTabletExternallyReparentedRerun(t *testing.T) {
 	goodReplica := NewFakeTablet(t, wr, "cell1", 2, topodatapb.TabletType_REPLICA, nil)
 
 	// Build keyspace graph
-	err := topotools.RebuildKeyspace(context.Background(), logutil.NewConsoleLogger(), ts, oldPrimary.Tablet.Keyspace, []string{"cell1"}, false)
+	err := topotools.RebuildKeyspace(context.Background(), logutil.NewConsoleLogger(), ts, oldPrimary.Tablet.Keyspace, []string{"cell1"}, false, false)
 	if err != nil {
-		require("RebuildKeyspaceLocked failed: %v", err)
+		t.Fatalf("RebuildKeyspaceLocked failed: %v", err)
 	}
 	// On the elected primary, we will respond to
 	// TabletActionReplicaWasPromoted.
@@ -446,16 +445,16 @@ func TestTabletExternallyReparentedRerun(t *testing.T) {
 
 	// The reparent should work as expected here
 	if err := wr.TabletExternallyReparented(ctx, newPrimary.Tablet.Alias); err != nil {
-		require("TabletExternallyReparented(replica) failed: %v", err)
+		t.Fatalf("TabletExternallyReparented(replica) failed: %v", err)
 	}
 
 	// check the new primary is primary
 	tablet, err := ts.GetTablet(ctx, newPrimary.Tablet.Alias)
 	if err != nil {
-		require("GetTablet(%v) failed: %v", newPrimary.Tablet.Alias, err)
+		t.Fatalf("GetTablet(%v) failed: %v", newPrimary.Tablet.Alias, err)
 	}
 	if tablet.Type != topodatapb.TabletType_PRIMARY {
-		require("new primary should be PRIMARY but is: %v", tablet.Type)
+		t.Fatalf("new primary should be PRIMARY but is: %v", tablet.Type)
 	}
 
 	// We have to wait for shard sync to do its magic in the background
@@ -464,14 +463,14 @@ func TestTabletExternallyReparentedRerun(t *testing.T) {
 		if time.Since(startTime) > 10*time.Second /* timeout */ {
 			tablet, err = ts.GetTablet(ctx, oldPrimary.Tablet.Alias)
 			if err != nil {
-				require("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
+				t.Fatalf("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
 			}
-			require("old primary (%v) should be replica but is: %v", topoproto.TabletAliasString(oldPrimary.Tablet.Alias), tablet.Type)
+			t.Fatalf("old primary (%v) should be replica but is: %v", topoproto.TabletAliasString(oldPrimary.Tablet.Alias), tablet.Type)
 		}
 		// check the old primary was converted to replica
 		tablet, err = ts.GetTablet(ctx, oldPrimary.Tablet.Alias)
 		if err != nil {
-			require("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
+			t.Fatalf("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
 		}
 		if tablet.Type == topodatapb.TabletType_REPLICA {
 			break
@@ -482,16 +481,16 @@ func TestTabletExternallyReparentedRerun(t *testing.T) {
 
 	// run TER again and make sure the primary is still correct
 	if err := wr.TabletExternallyReparented(ctx, newPrimary.Tablet.Alias); err != nil {
-		require("TabletExternallyReparented(replica) failed: %v", err)
+		t.Fatalf("TabletExternallyReparented(replica) failed: %v", err)
 	}
 
 	// check the new primary is still primary
 	tablet, err = ts.GetTablet(ctx, newPrimary.Tablet.Alias)
 	if err != nil {
-		require("GetTablet(%v) failed: %v", newPrimary.Tablet.Alias, err)
+		t.Fatalf("GetTablet(%v) failed: %v", newPrimary.Tablet.Alias, err)
 	}
 	if tablet.Type != topodatapb.TabletType_PRIMARY {
-		require("new primary should be PRIMARY but is: %v", tablet.Type)
+		t.Fatalf("new primary should be PRIMARY but is: %v", tablet.Type)
 	}
 
 }
@@ -509,7 +508,7 @@ func TestRPCTabletExternallyReparentedDemotesPrimaryToConfiguredTabletType(t *te
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
 	ts := memorytopo.NewServer(ctx, "cell1")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
 
 	// Create an old primary and a new primary
 	oldPrimary := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_SPARE, nil)
@@ -521,17 +520,17 @@ func TestRPCTabletExternallyReparentedDemotesPrimaryToConfiguredTabletType(t *te
 	defer newPrimary.StopActionLoop(t)
 
 	// Build keyspace graph
-	err := topotools.RebuildKeyspace(context.Background(), logutil.NewConsoleLogger(), ts, oldPrimary.Tablet.Keyspace, []string{"cell1"}, false)
+	err := topotools.RebuildKeyspace(context.Background(), logutil.NewConsoleLogger(), ts, oldPrimary.Tablet.Keyspace, []string{"cell1"}, false, false)
 	assert.NoError(t, err, "RebuildKeyspaceLocked failed: %v", err)
 
 	// Reparent to new primary
 	ti, err := ts.GetTablet(ctx, newPrimary.Tablet.Alias)
 	if err != nil {
-		require("GetTablet failed: %v", err)
+		t.Fatalf("GetTablet failed: %v", err)
 	}
 
 	if err := wr.TabletExternallyReparented(context.Background(), ti.Tablet.Alias); err != nil {
-		require("TabletExternallyReparented failed: %v", err)
+		t.Fatalf("TabletExternallyReparented failed: %v", err)
 	}
 
 	// We have to wait for shard sync to do its magic in the background
@@ -540,14 +539,14 @@ func TestRPCTabletExternallyReparentedDemotesPrimaryToConfiguredTabletType(t *te
 		if time.Since(startTime) > 10*time.Second /* timeout */ {
 			tablet, err := ts.GetTablet(ctx, oldPrimary.Tablet.Alias)
 			if err != nil {
-				require("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
+				t.Fatalf("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
 			}
-			require("old primary (%v) should be spare but is: %v", topoproto.TabletAliasString(oldPrimary.Tablet.Alias), tablet.Type)
+			t.Fatalf("old primary (%v) should be spare but is: %v", topoproto.TabletAliasString(oldPrimary.Tablet.Alias), tablet.Type)
 		}
 		// check the old primary was converted to replica
 		tablet, err := ts.GetTablet(ctx, oldPrimary.Tablet.Alias)
 		if err != nil {
-			require("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
+			t.Fatalf("GetTablet(%v) failed: %v", oldPrimary.Tablet.Alias, err)
 		}
 		if tablet.Type == topodatapb.TabletType_SPARE {
 			break
@@ -24,6 +24,7 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 
@@ -37,6 +38,7 @@ import (
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/grpcqueryservice"
 	"vitess.io/vitess/go/vt/vttablet/grpctmserver"
 	"vitess.io/vitess/go/vt/vttablet/tabletconntest"
 	"vitess.io/vitess/go/vt/vttablet/tabletmanager"
@@ -84,6 +86,21 @@ type FakeTablet struct {
 	StartHTTPServer bool
 	HTTPListener    net.Listener
 	HTTPServer      *http.Server
+
+	// FakeBackup makes the Backup and RestoreFromBackup RPCs of the tablet
+	// only simulate the type transitions: the tablet goes to BACKUP or
+	// RESTORE for BackupDuration, then back to its type. The tablet then
+	// also streams its health, with the transitions. It has to be set
+	// before StartActionLoop.
+	FakeBackup     bool
+	BackupDuration time.Duration
+
+	// health, originalType and modeType hold the state of the simulated
+	// backups and restores, see fake_tablet_backup.go.
+	health       *fakeTabletHealth
+	mu           sync.Mutex
+	originalType topodatapb.TabletType
+	modeType     topodatapb.TabletType
 }
 
 // TabletOption is an interface for changing tablet parameters.
@@ -97,7 +114,7 @@ func TabletKeyspaceShard(t *testing.T, keyspace, shard string) TabletOption {
 		tablet.Keyspace = keyspace
 		shard, kr, err := topo.ValidateShardName(shard)
 		if err != nil {
-			require("cannot ValidateShardName value %v", shard)
+			t.Fatalf("cannot ValidateShardName value %v", shard)
 		}
 		tablet.Shard = shard
 		tablet.KeyRange = kr
@@ -122,7 +139,7 @@ func NewFakeTablet(t *testing.T, wr *wrangler.Wrangler, cell string, uid uint32,
 	t.Helper()
 
 	if uid > 99 {
-		require("uid has to be between 0 and 99: %v", uid)
+		t.Fatalf("uid has to be between 0 and 99: %v", uid)
 	}
 	mysqlPort := int32(3300 + uid)
 	tablet := &topodatapb.Tablet{
@@ -146,7 +163,7 @@ func NewFakeTablet(t *testing.T, wr *wrangler.Wrangler, cell string, uid uint32,
 	_, force := tablet.PortMap["force_init"]
 	delete(tablet.PortMap, "force_init")
 	if err := wr.TopoServer().InitTablet(context.Background(), tablet, force, true /* createShardAndKeyspace */, false /* allowUpdate */); err != nil {
-		require("cannot create tablet %v: %v", uid, err)
+		t.Fatalf("cannot create tablet %v: %v", uid, err)
 	}
 
 	// create a FakeMysqlDaemon with the right information by default
@@ -170,14 +187,14 @@ var (
 func (ft *FakeTablet) StartActionLoop(t *testing.T, wr *wrangler.Wrangler) {
 	t.Helper()
 	if ft.TM != nil {
-		require("TM for %v is already running", ft.Tablet.Alias)
+		t.Fatalf("TM for %v is already running", ft.Tablet.Alias)
 	}
 
 	// Listen on a random port for gRPC.
 	var err error
 	ft.Listener, err = net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
-		require("Cannot listen: %v", err)
+		t.Fatalf("Cannot listen: %v", err)
 	}
 	gRPCPort := int32(ft.Listener.Addr().(*net.TCPAddr).Port)
 
@@ -186,7 +203,7 @@ func (ft *FakeTablet) StartActionLoop(t *testing.T, wr *wrangler.Wrangler) {
 	if ft.StartHTTPServer {
 		ft.HTTPListener, err = net.Listen("tcp", "127.0.0.1:0")
 		if err != nil {
-			require("Cannot listen on http port: %v", err)
+			t.Fatalf("Cannot listen on http port: %v", err)
 		}
 		handler := http.NewServeMux()
 		ft.HTTPServer = &http.Server{
@@ -212,12 +229,18 @@ func (ft *FakeTablet) StartActionLoop(t *testing.T, wr *wrangler.Wrangler) {
 		Env:                 vtenv.NewTestEnv(),
 	}
 	if err := ft.TM.Start(ft.Tablet, nil); err != nil {
-		require("Error in tablet - %v, err - %v", topoproto.TabletAliasString(ft.Tablet.Alias), err.Error())
+		t.Fatalf("Error in tablet - %v, err - %v", topoproto.TabletAliasString(ft.Tablet.Alias), err.Error())
 	}
 	ft.Tablet = ft.TM.Tablet()
 
 	// Register the gRPC server, and starts listening.
-	grpctmserver.RegisterForTest(ft.RPCServer, ft.TM)
+	if ft.FakeBackup {
+		ft.health = newFakeTabletHealth(ft.Tablet)
+		grpcqueryservice.Register(ft.RPCServer, ft.health)
+		grpctmserver.RegisterForTest(ft.RPCServer, &fakeBackupTM{RPCTM: ft.TM, ft: ft})
+	} else {
+		grpctmserver.RegisterForTest(ft.RPCServer, ft.TM)
+	}
 	go ft.RPCServer.Serve(ft.Listener)
 
 	// And wait for it to serve, so we don't start using it before it's
@@ -0,0 +1,256 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testlib
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+	"vitess.io/vitess/go/vt/vttablet/queryservice/fakes"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// This file contains the simulation of backups and restores by a
+// FakeTablet with FakeBackup set.
+
+// EnterBackupMode changes the type of the tablet to BACKUP, as the tablet
+// manager does while it takes a backup, and broadcasts that the tablet is
+// not serving. RestoreOriginalType ends it. The action loop of the tablet
+// has to be running.
+func (ft *FakeTablet) EnterBackupMode(ctx context.Context) error {
+	return ft.enterMode(ctx, topodatapb.TabletType_BACKUP)
+}
+
+// EnterRestoreMode changes the type of the tablet to RESTORE, as the tablet
+// manager does while it restores a backup, and broadcasts that the tablet
+// is not serving. RestoreOriginalType ends it.
+func (ft *FakeTablet) EnterRestoreMode(ctx context.Context) error {
+	return ft.enterMode(ctx, topodatapb.TabletType_RESTORE)
+}
+
+func (ft *FakeTablet) enterMode(ctx context.Context, tabletType topodatapb.TabletType) error {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	if ft.modeType != topodatapb.TabletType_UNKNOWN {
+		return fmt.Errorf("tablet %v is already in %v mode", topoproto.TabletAliasString(ft.Tablet.Alias), topoproto.TabletTypeLString(ft.modeType))
+	}
+
+	var originalType topodatapb.TabletType
+	tablet, err := ft.TM.TopoServer.UpdateTabletFields(ctx, ft.Tablet.Alias, func(tablet *topodatapb.Tablet) error {
+		originalType = tablet.Type
+		tablet.Type = tabletType
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	ft.originalType = originalType
+	ft.modeType = tabletType
+	ft.broadcastHealth(tablet, false)
+	return nil
+}
+
+// RestoreOriginalType ends the backup or restore mode of the tablet: it
+// changes its type back to the one it had before, and broadcasts that the
+// tablet is serving again.
+func (ft *FakeTablet) RestoreOriginalType(ctx context.Context) error {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	if ft.modeType == topodatapb.TabletType_UNKNOWN {
+		return fmt.Errorf("tablet %v is not in backup or restore mode", topoproto.TabletAliasString(ft.Tablet.Alias))
+	}
+
+	tablet, err := ft.TM.TopoServer.UpdateTabletFields(ctx, ft.Tablet.Alias, func(tablet *topodatapb.Tablet) error {
+		tablet.Type = ft.originalType
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	ft.modeType = topodatapb.TabletType_UNKNOWN
+	ft.broadcastHealth(tablet, true)
+	return nil
+}
+
+// inBackupMode returns true if the tablet is simulating a backup.
+func (ft *FakeTablet) inBackupMode() bool {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	return ft.modeType == topodatapb.TabletType_BACKUP
+}
+
+// HealthBroadcasts returns the health records the tablet broadcast since
+// StartActionLoop, the first one being the one of its start. It is only set
+// with FakeBackup.
+func (ft *FakeTablet) HealthBroadcasts() []*querypb.StreamHealthResponse {
+	if ft.health == nil {
+		return nil
+	}
+	return ft.health.broadcasts()
+}
+
+func (ft *FakeTablet) broadcastHealth(tablet *topodatapb.Tablet, serving bool) {
+	if ft.health != nil {
+		ft.health.broadcast(tablet, serving)
+	}
+}
+
+// simulate runs a fake backup or restore: the tablet stays in the mode for
+// BackupDuration, or until ctx is done.
+func (ft *FakeTablet) simulate(ctx context.Context, logger logutil.Logger, tabletType topodatapb.TabletType) error {
+	if err := ft.enterMode(ctx, tabletType); err != nil {
+		return err
+	}
+	logger.Infof("simulating a %v of %v for %v", topoproto.TabletTypeLString(tabletType), topoproto.TabletAliasString(ft.Tablet.Alias), ft.BackupDuration)
+
+	var err error
+	select {
+	case <-time.After(ft.BackupDuration):
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	// The original type is restored even if the backup was interrupted, as
+	// the tablet manager does.
+	if restoreErr := ft.RestoreOriginalType(context.Background()); restoreErr != nil {
+		return restoreErr
+	}
+	return err
+}
+
+// fakeBackupTM is the tablet manager of a FakeTablet with FakeBackup: its
+// Backup and RestoreFromBackup RPCs only simulate the type transitions.
+type fakeBackupTM struct {
+	tabletmanager.RPCTM
+	ft *FakeTablet
+}
+
+// Backup is part of the tabletmanager.RPCTM interface.
+func (tm *fakeBackupTM) Backup(ctx context.Context, logger logutil.Logger, req *tabletmanagerdatapb.BackupRequest) error {
+	tablet, err := tm.ft.TM.TopoServer.GetTablet(ctx, tm.ft.Tablet.Alias)
+	if err != nil {
+		return err
+	}
+	if !req.AllowPrimary && tablet.Type == topodatapb.TabletType_PRIMARY {
+		return fmt.Errorf("type PRIMARY cannot take backup. if you really need to do this, rerun the backup command with --allow_primary")
+	}
+	return tm.ft.simulate(ctx, logger, topodatapb.TabletType_BACKUP)
+}
+
+// RestoreFromBackup is part of the tabletmanager.RPCTM interface.
+func (tm *fakeBackupTM) RestoreFromBackup(ctx context.Context, logger logutil.Logger, req *tabletmanagerdatapb.RestoreFromBackupRequest) error {
+	tablet, err := tm.ft.TM.TopoServer.GetTablet(ctx, tm.ft.Tablet.Alias)
+	if err != nil {
+		return err
+	}
+	if tablet.Type == topodatapb.TabletType_PRIMARY {
+		return fmt.Errorf("type PRIMARY cannot restore from backup, if you really need to do this, restart vttablet in replica mode")
+	}
+	return tm.ft.simulate(ctx, logger, topodatapb.TabletType_RESTORE)
+}
+
+// ReplicationStatus is part of the tabletmanager.RPCTM interface. It reports
+// the simulated backup as running.
+func (tm *fakeBackupTM) ReplicationStatus(ctx context.Context) (*replicationdatapb.Status, error) {
+	status, err := tm.RPCTM.ReplicationStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status.BackupRunning = status.BackupRunning || tm.ft.inBackupMode()
+	return status, nil
+}
+
+// fakeTabletHealth is the query service of a FakeTablet with FakeBackup. It
+// streams the last health record the tablet broadcast to every client, then
+// the following ones.
+type fakeTabletHealth struct {
+	queryservice.QueryService
+
+	mu      sync.Mutex
+	history []*querypb.StreamHealthResponse
+	// changed is closed, and replaced, at each broadcast.
+	changed chan struct{}
+}
+
+func newFakeTabletHealth(tablet *topodatapb.Tablet) *fakeTabletHealth {
+	h := &fakeTabletHealth{
+		QueryService: fakes.ErrorQueryService,
+		changed:      make(chan struct{}),
+	}
+	h.broadcast(tablet, true)
+	return h
+}
+
+func (h *fakeTabletHealth) broadcast(tablet *topodatapb.Tablet, serving bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.history = append(h.history, &querypb.StreamHealthResponse{
+		Target: &querypb.Target{
+			Keyspace:   tablet.Keyspace,
+			Shard:      tablet.Shard,
+			TabletType: tablet.Type,
+		},
+		TabletAlias:   tablet.Alias,
+		Serving:       serving,
+		RealtimeStats: &querypb.RealtimeStats{},
+	})
+	close(h.changed)
+	h.changed = make(chan struct{})
+}
+
+func (h *fakeTabletHealth) broadcasts() []*querypb.StreamHealthResponse {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	history := make([]*querypb.StreamHealthResponse, len(h.history))
+	for i, shr := range h.history {
+		history[i] = shr.CloneVT()
+	}
+	return history
+}
+
+// StreamHealth is part of the queryservice.QueryService interface.
+func (h *fakeTabletHealth) StreamHealth(ctx context.Context, callback func(*querypb.StreamHealthResponse) error) error {
+	for {
+		h.mu.Lock()
+		shr := h.history[len(h.history)-1].CloneVT()
+		changed := h.changed
+		h.mu.Unlock()
+
+		if err := callback(shr); err != nil {
+			return err
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Close is part of the queryservice.QueryService interface.
+func (h *fakeTabletHealth) Close(ctx context.Context) error {
+	return nil
+}
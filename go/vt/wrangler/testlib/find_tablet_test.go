@@ -27,7 +27,6 @@ import (
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools"
 	"vitess.io/vitess/go/vt/vtenv"
-	"vitess.io/vitess/go/vt/vttablet/tmclient"
 	"vitess.io/vitess/go/vt/wrangler"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -37,7 +36,7 @@ func TestFindTablet(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
 	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
 
 	// Create an old primary, two good replicas
 	oldPrimary := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_PRIMARY, nil)
@@ -45,46 +44,46 @@ func TestFindTablet(t *testing.T) {
 	goodReplica2 := NewFakeTablet(t, wr, "cell2", 3, topodatapb.TabletType_REPLICA, nil)
 
 	// Build keyspace graph
-	err := topotools.RebuildKeyspace(context.Background(), logutil.NewConsoleLogger(), ts, oldPrimary.Tablet.Keyspace, []string{"cell1", "cell2"}, false)
+	err := topotools.RebuildKeyspace(context.Background(), logutil.NewConsoleLogger(), ts, oldPrimary.Tablet.Keyspace, []string{"cell1", "cell2"}, false, false)
 	if err != nil {
-		require("RebuildKeyspaceLocked failed: %v", err)
+		t.Fatalf("RebuildKeyspaceLocked failed: %v", err)
 	}
 
 	// make sure we can find the tablets
 	// even with a datacenter being down.
 	tabletMap, err := ts.GetTabletMapForShardByCell(ctx, "test_keyspace", "0", []string{"cell1"})
 	if err != nil {
-		require("GetTabletMapForShardByCell should have worked but got: %v", err)
+		t.Fatalf("GetTabletMapForShardByCell should have worked but got: %v", err)
 	}
 	primary, err := topotools.FindTabletByHostAndPort(tabletMap, oldPrimary.Tablet.Hostname, "vt", oldPrimary.Tablet.PortMap["vt"])
 	if err != nil || !topoproto.TabletAliasEqual(primary, oldPrimary.Tablet.Alias) {
-		require("FindTabletByHostAndPort(primary) failed: %v %v", err, primary)
+		t.Fatalf("FindTabletByHostAndPort(primary) failed: %v %v", err, primary)
 	}
 	replica1, err := topotools.FindTabletByHostAndPort(tabletMap, goodReplica1.Tablet.Hostname, "vt", goodReplica1.Tablet.PortMap["vt"])
 	if err != nil || !topoproto.TabletAliasEqual(replica1, goodReplica1.Tablet.Alias) {
-		require("FindTabletByHostAndPort(replica1) failed: %v %v", err, primary)
+		t.Fatalf("FindTabletByHostAndPort(replica1) failed: %v %v", err, primary)
 	}
 	replica2, err := topotools.FindTabletByHostAndPort(tabletMap, goodReplica2.Tablet.Hostname, "vt", goodReplica2.Tablet.PortMap["vt"])
 	if !topo.IsErrType(err, topo.NoNode) {
-		require("FindTabletByHostAndPort(replica2) worked: %v %v", err, replica2)
+		t.Fatalf("FindTabletByHostAndPort(replica2) worked: %v %v", err, replica2)
 	}
 
 	// Make sure the primary is not exported in other cells
 	tabletMap, _ = ts.GetTabletMapForShardByCell(ctx, "test_keyspace", "0", []string{"cell2"})
 	primary, err = topotools.FindTabletByHostAndPort(tabletMap, oldPrimary.Tablet.Hostname, "vt", oldPrimary.Tablet.PortMap["vt"])
 	if !topo.IsErrType(err, topo.NoNode) {
-		require("FindTabletByHostAndPort(primary) worked in cell2: %v %v", err, primary)
+		t.Fatalf("FindTabletByHostAndPort(primary) worked in cell2: %v %v", err, primary)
 	}
 
 	// Get tablet map for all cells.  If there were to be failures talking to local cells, this will return the tablet map
 	// and forward a partial result error
 	tabletMap, err = ts.GetTabletMapForShard(ctx, "test_keyspace", "0")
 	if err != nil {
-		require("GetTabletMapForShard should nil but got: %v", err)
+		t.Fatalf("GetTabletMapForShard should nil but got: %v", err)
 	}
 	primary, err = topotools.FindTabletByHostAndPort(tabletMap, oldPrimary.Tablet.Hostname, "vt", oldPrimary.Tablet.PortMap["vt"])
 	if err != nil || !topoproto.TabletAliasEqual(primary, oldPrimary.Tablet.Alias) {
-		require("FindTabletByHostAndPort(primary) failed: %v %v", err, primary)
+		t.Fatalf("FindTabletByHostAndPort(primary) failed: %v %v", err, primary)
 	}
 
 }
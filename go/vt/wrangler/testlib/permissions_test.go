@@ -22,10 +22,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtenv"
-	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/logutil"
@@ -35,20 +37,16 @@ import (
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 func TestPermissions(t *testing.T) {
-	delay := discovery.GetTabletPickerRetryDelay()
-	defer func() {
-		discovery.SetTabletPickerRetryDelay(delay)
-	}()
-	discovery.SetTabletPickerRetryDelay(5 * time.Millisecond)
-
 	// Initialize our environment
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
 	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts,
+		wrangler.WithTabletPickerOptions(discovery.TabletPickerOptions{RetryDelay: 5 * time.Millisecond}))
 	vp := NewVtctlPipe(ctx, t, ts)
 	defer vp.Close()
 
@@ -61,495 +59,84 @@ func TestPermissions(t *testing.T) {
 		return nil
 	})
 	if err != nil {
-		require("UpdateShardFields failed: %v", err)
+		t.Fatalf("UpdateShardFields failed: %v", err)
 	}
 
 	// primary will be asked for permissions
+	userPrivileges := []string{
+		"Select_priv", "Insert_priv", "Update_priv", "Delete_priv", "Create_priv", "Drop_priv",
+		"Reload_priv", "Shutdown_priv", "Process_priv", "File_priv", "Grant_priv", "References_priv",
+		"Index_priv", "Alter_priv", "Show_db_priv", "Super_priv", "Create_tmp_table_priv",
+		"Lock_tables_priv", "Execute_priv", "Repl_slave_priv", "Repl_client_priv", "Create_view_priv",
+		"Show_view_priv", "Create_routine_priv", "Alter_routine_priv", "Create_user_priv",
+		"Event_priv", "Trigger_priv", "Create_tablespace_priv",
+	}
+	userFields := sqltypes.MakeTestFields(
+		"Host|User|Password|"+strings.Join(userPrivileges, "|")+
+			"|ssl_type|ssl_cipher|x509_issuer|x509_subject|max_questions|max_updates|max_connections|max_user_connections|plugin|authentication_string|password_expired|is_role",
+		"char|char|char|"+strings.Repeat("char|", len(userPrivileges))+
+			"char|blob|blob|blob|int32|int32|int32|int32|char|blob|char|char",
+	)
+	userDefaults := withPrivileges(map[string]string{
+		"ssl_type":              "",
+		"ssl_cipher":            "",
+		"x509_issuer":           "",
+		"x509_subject":          "",
+		"max_questions":         "0",
+		"max_updates":           "0",
+		"max_connections":       "0",
+		"max_user_connections":  "0",
+		"plugin":                "",
+		"authentication_string": "",
+		"password_expired":      "N",
+		"is_role":               "N",
+	}, userPrivileges, "Y")
+
+	dbPrivileges := []string{
+		"Select_priv", "Insert_priv", "Update_priv", "Delete_priv", "Create_priv", "Drop_priv",
+		"Grant_priv", "References_priv", "Index_priv", "Alter_priv", "Create_tmp_table_priv",
+		"Lock_tables_priv", "Create_view_priv", "Show_view_priv", "Create_routine_priv",
+		"Alter_routine_priv", "Execute_priv", "Event_priv", "Trigger_priv",
+	}
+	dbFields := sqltypes.MakeTestFields(
+		"Host|Db|User|"+strings.Join(dbPrivileges, "|"),
+		"char|char|char"+strings.Repeat("|char", len(dbPrivileges)),
+	)
+	tablesPrivFields := sqltypes.MakeTestFields(
+		"Host|Db|User|Table_name|Grantor|Timestamp|Table_priv|Column_priv",
+		"char|char|char|char|varchar|timestamp|varchar|varchar",
+	)
+	columnsPrivFields := sqltypes.MakeTestFields(
+		"Host|Db|User|Table_name|Column_name|Timestamp|Column_priv",
+		"char|char|char|char|char|timestamp|varchar",
+	)
+
 	primary.FakeMysqlDaemon.FetchSuperQueryMap = map[string]*sqltypes.Result{
-		"SELECT * FROM mysql.user ORDER BY host, user": {
-			Fields: []*querypb.Field{
-				{
-					Name: "Host",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "User",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Password",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Select_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Insert_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Update_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Delete_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Create_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Drop_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Reload_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Shutdown_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Process_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "File_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Grant_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "References_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Index_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Alter_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Show_db_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Super_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Create_tmp_table_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Lock_tables_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Execute_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Repl_slave_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Repl_client_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Create_view_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Show_view_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Create_routine_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Alter_routine_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Create_user_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Event_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Trigger_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Create_tablespace_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "ssl_type",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "ssl_cipher",
-					Type: 252,
-				},
-				{
-					Name: "x509_issuer",
-					Type: 252,
-				},
-				{
-					Name: "x509_subject",
-					Type: 252,
-				},
-				{
-					Name: "max_questions",
-					Type: 3,
-				},
-				{
-					Name: "max_updates",
-					Type: 3,
-				},
-				{
-					Name: "max_connections",
-					Type: 3,
-				},
-				{
-					Name: "max_user_connections",
-					Type: 3,
-				},
-				{
-					Name: "plugin",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "authentication_string",
-					Type: 252,
-				},
-				{
-					Name: "password_expired",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "is_role",
-					Type: sqltypes.Char,
-				}},
-			RowsAffected: 0x6,
-			InsertID:     0x0,
-			Rows: [][]sqltypes.Value{
-				{
-					sqltypes.NewVarBinary("test_host1"),
-					sqltypes.NewVarBinary("test_user1"),
-					sqltypes.NewVarBinary("test_password1"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary("0"),
-					sqltypes.NewVarBinary("0"),
-					sqltypes.NewVarBinary("0"),
-					sqltypes.NewVarBinary("0"),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N")},
-				{
-					sqltypes.NewVarBinary("test_host2"),
-					sqltypes.NewVarBinary("test_user2"),
-					sqltypes.NewVarBinary("test_password2"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary("0"),
-					sqltypes.NewVarBinary("0"),
-					sqltypes.NewVarBinary("0"),
-					sqltypes.NewVarBinary("0"),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N")},
-				{
-					sqltypes.NewVarBinary("test_host3"),
-					sqltypes.NewVarBinary("test_user3"),
-					sqltypes.NewVarBinary("test_password3"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary("0"),
-					sqltypes.NewVarBinary("0"),
-					sqltypes.NewVarBinary("0"),
-					sqltypes.NewVarBinary("0"),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N")},
-				{
-					sqltypes.NewVarBinary("test_host4"),
-					sqltypes.NewVarBinary("test_user4"),
-					sqltypes.NewVarBinary("test_password4"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary("0"),
-					sqltypes.NewVarBinary("0"),
-					sqltypes.NewVarBinary("0"),
-					sqltypes.NewVarBinary("0"),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary(""),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("N"),
-				},
-			},
-		},
-		"SELECT * FROM mysql.db ORDER BY host, db, user": {
-			Fields: []*querypb.Field{
-				{
-					Name: "Host",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Db",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "User",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Select_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Insert_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Update_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Delete_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Create_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Drop_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Grant_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "References_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Index_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Alter_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Create_tmp_table_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Lock_tables_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Create_view_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Show_view_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Create_routine_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Alter_routine_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Execute_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Event_priv",
-					Type: sqltypes.Char,
-				},
-				{
-					Name: "Trigger_priv",
-					Type: sqltypes.Char,
-				},
+		"SELECT * FROM mysql.user ORDER BY host, user": sqltypes.MakeTestNamedResult(userFields, userDefaults,
+			map[string]string{"Host": "test_host1", "User": "test_user1", "Password": "test_password1"},
+			map[string]string{"Host": "test_host2", "User": "test_user2", "Password": "test_password2"},
+			map[string]string{
+				"Host": "test_host3", "User": "test_user3", "Password": "test_password3",
+				"Shutdown_priv": "N", "Grant_priv": "N", "Super_priv": "N", "Create_tablespace_priv": "N",
 			},
-			RowsAffected: 0,
-			InsertID:     0,
-			Rows: [][]sqltypes.Value{
-				{
-					sqltypes.NewVarBinary("test_host"),
-					sqltypes.NewVarBinary("test_db"),
-					sqltypes.NewVarBinary("test_user"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("N"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-					sqltypes.NewVarBinary("Y"),
-				},
+			withPrivileges(map[string]string{
+				"Host": "test_host4", "User": "test_user4", "Password": "test_password4",
+				"Repl_slave_priv": "Y",
+			}, userPrivileges, "N"),
+		),
+		"SELECT * FROM mysql.db ORDER BY host, db, user": sqltypes.MakeTestNamedResult(dbFields, withPrivileges(nil, dbPrivileges, "Y"),
+			map[string]string{
+				"Host": "test_host", "Db": "test_db", "User": "test_user",
+				"Grant_priv": "N", "Create_view_priv": "N",
 			},
-		},
+		),
+		"SELECT * FROM mysql.tables_priv ORDER BY host, db, user, table_name": sqltypes.MakeTestResult(tablesPrivFields,
+			"test_host|test_db|test_user|t1|root@localhost|2025-01-01 00:00:00|Select,Insert|",
+			"test_host|test_db|test_user|t2|root@localhost|2025-01-01 00:00:00||Select",
+		),
+		"SELECT * FROM mysql.columns_priv ORDER BY host, db, user, table_name, column_name": sqltypes.MakeTestResult(columnsPrivFields,
+			"test_host|test_db|test_user|t2|c1|2025-01-01 00:00:00|Select",
+		),
 	}
 	primary.StartActionLoop(t, wr)
 	defer primary.StopActionLoop(t)
@@ -557,11 +144,15 @@ func TestPermissions(t *testing.T) {
 	// Make a two-level-deep copy, so we can make them diverge later.
 	user := *primary.FakeMysqlDaemon.FetchSuperQueryMap["SELECT * FROM mysql.user ORDER BY host, user"]
 	user.Fields = append([]*querypb.Field{}, user.Fields...)
+	tablesPriv := *primary.FakeMysqlDaemon.FetchSuperQueryMap["SELECT * FROM mysql.tables_priv ORDER BY host, db, user, table_name"]
+	tablesPriv.Rows = append([]sqltypes.Row{}, tablesPriv.Rows...)
 
 	// replica will be asked for permissions
 	replica.FakeMysqlDaemon.FetchSuperQueryMap = map[string]*sqltypes.Result{
-		"SELECT * FROM mysql.user ORDER BY host, user":   &user,
-		"SELECT * FROM mysql.db ORDER BY host, db, user": primary.FakeMysqlDaemon.FetchSuperQueryMap["SELECT * FROM mysql.db ORDER BY host, db, user"],
+		"SELECT * FROM mysql.user ORDER BY host, user":                                      &user,
+		"SELECT * FROM mysql.db ORDER BY host, db, user":                                    primary.FakeMysqlDaemon.FetchSuperQueryMap["SELECT * FROM mysql.db ORDER BY host, db, user"],
+		"SELECT * FROM mysql.tables_priv ORDER BY host, db, user, table_name":               &tablesPriv,
+		"SELECT * FROM mysql.columns_priv ORDER BY host, db, user, table_name, column_name": primary.FakeMysqlDaemon.FetchSuperQueryMap["SELECT * FROM mysql.columns_priv ORDER BY host, db, user, table_name, column_name"],
 	}
 	replica.FakeMysqlDaemon.SetReplicationSourceInputs = append(replica.FakeMysqlDaemon.SetReplicationSourceInputs, topoproto.MysqlAddr(primary.Tablet))
 	replica.FakeMysqlDaemon.ExpectedExecuteSuperQueryList = []string{
@@ -581,7 +172,7 @@ func TestPermissions(t *testing.T) {
 
 	// run ValidatePermissionsKeyspace, this should work
 	if err := vp.Run([]string{"ValidatePermissionsKeyspace", primary.Tablet.Keyspace}); err != nil {
-		require("ValidatePermissionsKeyspace failed: %v", err)
+		t.Fatalf("ValidatePermissionsKeyspace failed: %v", err)
 	}
 
 	// modify one field, this should fail
@@ -592,7 +183,112 @@ func TestPermissions(t *testing.T) {
 
 	// run ValidatePermissionsKeyspace again, this should now fail
 	if err := vp.Run([]string{"ValidatePermissionsKeyspace", primary.Tablet.Keyspace}); err == nil || !strings.Contains(err.Error(), "has an extra user") {
-		require("ValidatePermissionsKeyspace has unexpected err: %v", err)
+		t.Fatalf("ValidatePermissionsKeyspace has unexpected err: %v", err)
+	}
+
+	// Fix the users, and revoke a table-level privilege on the replica
+	// only: this should fail too, naming the grant that differs.
+	replica.FakeMysqlDaemon.FetchSuperQueryMap["SELECT * FROM mysql.user ORDER BY host, user"].Fields[0] = &querypb.Field{
+		Name: "Host",
+		Type: sqltypes.Char,
+	}
+	tablesPriv.Rows[0] = sqltypes.MakeTestResult(tablesPrivFields, "test_host|test_db|test_user|t1|root@localhost|2025-01-01 00:00:00|Select|").Rows[0]
+	err = vp.Run([]string{"ValidatePermissionsKeyspace", primary.Tablet.Keyspace})
+	require.ErrorContains(t, err, "permissions differ on table grant test_host:test_db:test_user:t1")
+	require.NotContains(t, err.Error(), "has an extra user")
+
+	// Ignoring another user still compares the table grants, ignoring the
+	// user of the grant doesn't.
+	resp, err := wr.VtctldServer().ValidatePermissionsShard(ctx, &vtctldatapb.ValidatePermissionsShardRequest{
+		Keyspace:    primary.Tablet.Keyspace,
+		Shard:       primary.Tablet.Shard,
+		IgnoreUsers: []string{"other_user"},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Findings)
+	assert.Contains(t, resp.Findings[0].Message, "permissions differ on table grant test_host:test_db:test_user:t1")
+
+	resp, err = wr.VtctldServer().ValidatePermissionsShard(ctx, &vtctldatapb.ValidatePermissionsShardRequest{
+		Keyspace:    primary.Tablet.Keyspace,
+		Shard:       primary.Tablet.Shard,
+		IgnoreUsers: []string{"test_user"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Findings)
+}
+
+// withPrivileges returns row with the privileges it doesn't list set to
+// value.
+func withPrivileges(row map[string]string, privileges []string, value string) map[string]string {
+	result := make(map[string]string, len(row)+len(privileges))
+	for _, privilege := range privileges {
+		result[privilege] = value
+	}
+	for k, v := range row {
+		result[k] = v
 	}
+	return result
+}
+
+func TestPermissionsTabletTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, nil)
+
+	// Each tablet is only given a short time to return its permissions.
+	defer func(timeout time.Duration) { topo.RemoteOperationTimeout = timeout }(topo.RemoteOperationTimeout)
+	topo.RemoteOperationTimeout = 200 * time.Millisecond
+
+	primary := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_PRIMARY, nil)
+	replica1 := NewFakeTablet(t, wr, "cell1", 1, topodatapb.TabletType_REPLICA, nil)
+	replica2 := NewFakeTablet(t, wr, "cell1", 2, topodatapb.TabletType_REPLICA, nil)
+
+	_, err := ts.UpdateShardFields(ctx, primary.Tablet.Keyspace, primary.Tablet.Shard, func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = primary.Tablet.Alias
+		return nil
+	})
+	require.NoError(t, err)
+
+	permissions := map[string]*sqltypes.Result{
+		"SELECT * FROM mysql.user ORDER BY host, user": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Host|User|Password", "char|char|char"),
+			"test_host|test_user|test_password"),
+		"SELECT * FROM mysql.db ORDER BY host, db, user": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Host|Db|User", "char|char|char"),
+			"test_host|test_db|test_user"),
+		"SELECT * FROM mysql.tables_priv ORDER BY host, db, user, table_name": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Host|Db|User|Table_name|Table_priv", "char|char|char|char|varchar"),
+			"test_host|test_db|test_user|t1|Select"),
+		"SELECT * FROM mysql.columns_priv ORDER BY host, db, user, table_name, column_name": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Host|Db|User|Table_name|Column_name", "char|char|char|char|char")),
+	}
+	primary.FakeMysqlDaemon.FetchSuperQueryMap = permissions
+	primary.StartActionLoop(t, wr)
+	defer primary.StopActionLoop(t)
+	for _, replica := range []*FakeTablet{replica1, replica2} {
+		replica.FakeMysqlDaemon.FetchSuperQueryMap = permissions
+		replica.FakeMysqlDaemon.SetReplicationSourceInputs = append(replica.FakeMysqlDaemon.SetReplicationSourceInputs, topoproto.MysqlAddr(primary.Tablet))
+		replica.FakeMysqlDaemon.ExpectedExecuteSuperQueryList = []string{
+			// These 3 statements come from tablet startup
+			"STOP REPLICA",
+			"FAKE SET SOURCE",
+			"START REPLICA",
+		}
+		replica.StartActionLoop(t, wr)
+		defer replica.StopActionLoop(t)
+	}
+
+	// The mysqld of replica2 hangs reading the users: the validation gives
+	// up on it, and still compares the permissions of replica1.
+	replica2.FakeMysqlDaemon.SetQueryLatency("FROM mysql\\.user", time.Hour)
+	resp, err := wr.VtctldServer().ValidatePermissionsShard(ctx, &vtctldatapb.ValidatePermissionsShardRequest{
+		Keyspace: primary.Tablet.Keyspace,
+		Shard:    primary.Tablet.Shard,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Findings, 1)
+	assert.Equal(t, vtctldatapb.ValidationFinding_WARNING, resp.Findings[0].Severity)
+	assert.Equal(t, "cell1-0000000002", topoproto.TabletAliasString(resp.Findings[0].TabletAlias))
+	assert.Contains(t, resp.Findings[0].Message, "cannot get the permissions of cell1-0000000002")
 
+	// Once replica2 answers again, the shard validates.
+	replica2.FakeMysqlDaemon.SetQueryLatency("FROM mysql\\.user", 0)
+	require.NoError(t, wr.ValidatePermissionsShard(ctx, primary.Tablet.Keyspace, primary.Tablet.Shard, false /* includeNonServing */))
 }
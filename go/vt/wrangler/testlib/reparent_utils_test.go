@@ -34,7 +34,6 @@ import (
 	"vitess.io/vitess/go/vt/vtctl/reparentutil/policy"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil/reparenttestutil"
 	"vitess.io/vitess/go/vt/vtenv"
-	"vitess.io/vitess/go/vt/vttablet/tmclient"
 	"vitess.io/vitess/go/vt/wrangler"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -50,11 +49,11 @@ func TestShardReplicationStatuses(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
 
 	// create shard and tablets
 	if _, err := ts.GetOrCreateShard(ctx, "test_keyspace", "0"); err != nil {
-		require("GetOrCreateShard failed: %v", err)
+		t.Fatalf("GetOrCreateShard failed: %v", err)
 	}
 	primary := NewFakeTablet(t, wr, "cell1", 1, topodatapb.TabletType_PRIMARY, nil)
 	replica := NewFakeTablet(t, wr, "cell1", 2, topodatapb.TabletType_REPLICA, nil)
@@ -64,7 +63,7 @@ func TestShardReplicationStatuses(t *testing.T) {
 		si.PrimaryAlias = primary.Tablet.Alias
 		return nil
 	}); err != nil {
-		require("UpdateShardFields failed: %v", err)
+		t.Fatalf("UpdateShardFields failed: %v", err)
 	}
 
 	// primary action loop (to initialize host and port)
@@ -105,12 +104,12 @@ func TestShardReplicationStatuses(t *testing.T) {
 	// run ShardReplicationStatuses
 	ti, rs, err := reparentutil.ShardReplicationStatuses(ctx, wr.TopoServer(), wr.TabletManagerClient(), "test_keyspace", "0")
 	if err != nil {
-		require("ShardReplicationStatuses failed: %v", err)
+		t.Fatalf("ShardReplicationStatuses failed: %v", err)
 	}
 
 	// check result (make primary first in the array)
 	if len(ti) != 2 || len(rs) != 2 {
-		require("ShardReplicationStatuses returned wrong results: %v %v", ti, rs)
+		t.Fatalf("ShardReplicationStatuses returned wrong results: %v %v", ti, rs)
 	}
 	if topoproto.TabletAliasEqual(ti[0].Alias, replica.Tablet.Alias) {
 		ti[0], ti[1] = ti[1], ti[0]
@@ -120,7 +119,7 @@ func TestShardReplicationStatuses(t *testing.T) {
 		!topoproto.TabletAliasEqual(ti[1].Alias, replica.Tablet.Alias) ||
 		rs[0].SourceHost != "" ||
 		rs[1].SourceHost != primary.Tablet.Hostname {
-		require("ShardReplicationStatuses returend wrong results: %v %v", ti, rs)
+		t.Fatalf("ShardReplicationStatuses returend wrong results: %v %v", ti, rs)
 	}
 }
 
@@ -134,11 +133,11 @@ func TestReparentTablet(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
 
 	// create shard and tablets
 	if _, err := ts.GetOrCreateShard(ctx, "test_keyspace", "0"); err != nil {
-		require("CreateShard failed: %v", err)
+		t.Fatalf("CreateShard failed: %v", err)
 	}
 	primary := NewFakeTablet(t, wr, "cell1", 1, topodatapb.TabletType_PRIMARY, nil)
 	replica := NewFakeTablet(t, wr, "cell1", 2, topodatapb.TabletType_REPLICA, nil)
@@ -149,7 +148,7 @@ func TestReparentTablet(t *testing.T) {
 		si.PrimaryAlias = primary.Tablet.Alias
 		return nil
 	}); err != nil {
-		require("UpdateShardFields failed: %v", err)
+		t.Fatalf("UpdateShardFields failed: %v", err)
 	}
 
 	// primary action loop (to initialize host and port)
@@ -176,12 +175,12 @@ func TestReparentTablet(t *testing.T) {
 
 	// run ReparentTablet
 	if err := wr.ReparentTablet(ctx, replica.Tablet.Alias); err != nil {
-		require("ReparentTablet failed: %v", err)
+		t.Fatalf("ReparentTablet failed: %v", err)
 	}
 
 	// check what was run
 	if err := replica.FakeMysqlDaemon.CheckSuperQueryList(); err != nil {
-		require("replica.FakeMysqlDaemon.CheckSuperQueryList failed: %v", err)
+		t.Fatalf("replica.FakeMysqlDaemon.CheckSuperQueryList failed: %v", err)
 	}
 	checkSemiSyncEnabled(t, false, true, replica)
 }
@@ -191,7 +190,7 @@ func TestSetReplicationSource(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
 
 	// create shard and tablets
 	_, err := ts.GetOrCreateShard(ctx, "test_keyspace", "0")
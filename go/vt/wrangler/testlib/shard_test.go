@@ -21,12 +21,14 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools"
 	"vitess.io/vitess/go/vt/vtenv"
-	"vitess.io/vitess/go/vt/vttablet/tmclient"
 	"vitess.io/vitess/go/vt/wrangler"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -36,7 +38,7 @@ func TestDeleteShardCleanup(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
 	vp := NewVtctlPipe(ctx, t, ts)
 	defer vp.Close()
 
@@ -46,14 +48,14 @@ func TestDeleteShardCleanup(t *testing.T) {
 	remoteReplica := NewFakeTablet(t, wr, "cell2", 2, topodatapb.TabletType_REPLICA, nil)
 
 	// Build keyspace graph
-	err := topotools.RebuildKeyspace(context.Background(), logutil.NewConsoleLogger(), ts, primary.Tablet.Keyspace, []string{"cell1", "cell2"}, false)
+	err := topotools.RebuildKeyspace(context.Background(), logutil.NewConsoleLogger(), ts, primary.Tablet.Keyspace, []string{"cell1", "cell2"}, false, false)
 	if err != nil {
-		require("RebuildKeyspaceLocked failed: %v", err)
+		t.Fatalf("RebuildKeyspaceLocked failed: %v", err)
 	}
 
 	// Delete the ShardReplication record in cell2
 	if err := ts.DeleteShardReplication(ctx, "cell2", remoteReplica.Tablet.Keyspace, remoteReplica.Tablet.Shard); err != nil {
-		require("DeleteShardReplication failed: %v", err)
+		t.Fatalf("DeleteShardReplication failed: %v", err)
 	}
 
 	// Now try to delete the shard without even_if_serving or
@@ -62,7 +64,7 @@ func TestDeleteShardCleanup(t *testing.T) {
 		"DeleteShard",
 		primary.Tablet.Keyspace + "/" + primary.Tablet.Shard,
 	}); err == nil || !strings.Contains(err.Error(), "is still serving, cannot delete it") {
-		require("DeleteShard() returned wrong error: %v", err)
+		t.Fatalf("DeleteShard() returned wrong error: %v", err)
 	}
 
 	// Now try to delete the shard with even_if_serving, but
@@ -72,7 +74,7 @@ func TestDeleteShardCleanup(t *testing.T) {
 		"--even_if_serving",
 		primary.Tablet.Keyspace + "/" + primary.Tablet.Shard,
 	}); err == nil || !strings.Contains(err.Error(), "use -recursive or remove them manually") {
-		require("DeleteShard(evenIfServing=true) returned wrong error: %v", err)
+		t.Fatalf("DeleteShard(evenIfServing=true) returned wrong error: %v", err)
 	}
 
 	// Now try to delete the shard with even_if_serving and recursive,
@@ -83,18 +85,118 @@ func TestDeleteShardCleanup(t *testing.T) {
 		"--even_if_serving",
 		primary.Tablet.Keyspace + "/" + primary.Tablet.Shard,
 	}); err != nil {
-		require("DeleteShard(recursive=true, evenIfServing=true) should have worked but returned: %v", err)
+		t.Fatalf("DeleteShard(recursive=true, evenIfServing=true) should have worked but returned: %v", err)
 	}
 
 	// Make sure all tablets are gone.
 	for _, ft := range []*FakeTablet{primary, replica, remoteReplica} {
 		if _, err := ts.GetTablet(ctx, ft.Tablet.Alias); !topo.IsErrType(err, topo.NoNode) {
-			assert("tablet %v is still in topo: %v", ft.Tablet.Alias, err)
+			t.Errorf("tablet %v is still in topo: %v", ft.Tablet.Alias, err)
 		}
 	}
 
 	// Make sure the shard is gone.
 	if _, err := ts.GetShard(ctx, primary.Tablet.Keyspace, primary.Tablet.Shard); !topo.IsErrType(err, topo.NoNode) {
-		assert("shard %v/%v is still in topo: %v", primary.Tablet.Keyspace, primary.Tablet.Shard, err)
+		t.Errorf("shard %v/%v is still in topo: %v", primary.Tablet.Keyspace, primary.Tablet.Shard, err)
+	}
+}
+
+func TestShardReplicationFix(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
+	vp := NewVtctlPipe(ctx, t, ts)
+	defer vp.Close()
+
+	primary := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_PRIMARY, nil)
+	replica := NewFakeTablet(t, wr, "cell1", 1, topodatapb.TabletType_REPLICA, nil)
+	remoteReplica := NewFakeTablet(t, wr, "cell2", 2, topodatapb.TabletType_REPLICA, nil)
+	keyspace, shard := primary.Tablet.Keyspace, primary.Tablet.Shard
+
+	// Lose the ShardReplication record in cell1, as after a partial
+	// restore of the topo, then add a stale entry to the new one.
+	require.NoError(t, ts.DeleteShardReplication(ctx, "cell1", keyspace, shard))
+	stale := &topodatapb.TabletAlias{Cell: "cell1", Uid: 99}
+	require.NoError(t, topo.UpdateShardReplicationRecord(ctx, ts, keyspace, shard, stale))
+
+	output, err := vp.RunAndOutput([]string{"ShardReplicationFix", "cell1", keyspace + "/" + shard})
+	require.NoError(t, err)
+	require.Contains(t, output, "Added cell1-0000000000")
+	require.Contains(t, output, "Added cell1-0000000001")
+	require.Contains(t, output, "Removed cell1-0000000099 (NOT_FOUND)")
+
+	sri, err := ts.GetShardReplication(ctx, "cell1", keyspace, shard)
+	require.NoError(t, err)
+	var aliases []string
+	for _, node := range sri.Nodes {
+		aliases = append(aliases, topoproto.TabletAliasString(node.TabletAlias))
+	}
+	require.ElementsMatch(t, []string{
+		topoproto.TabletAliasString(primary.Tablet.Alias),
+		topoproto.TabletAliasString(replica.Tablet.Alias),
+	}, aliases)
+
+	// The graph is now complete, and the other cell was left alone.
+	output, err = vp.RunAndOutput([]string{"ShardReplicationFix", "cell1", keyspace + "/" + shard})
+	require.NoError(t, err)
+	require.NotContains(t, output, "Added")
+	require.NotContains(t, output, "Removed")
+	sri, err = ts.GetShardReplication(ctx, "cell2", keyspace, shard)
+	require.NoError(t, err)
+	require.Len(t, sri.Nodes, 1)
+	require.True(t, topoproto.TabletAliasEqual(remoteReplica.Tablet.Alias, sri.Nodes[0].TabletAlias))
+}
+
+func TestRebuildReplicationGraph(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts)
+	vp := NewVtctlPipe(ctx, t, ts)
+	defer vp.Close()
+
+	NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_PRIMARY, nil, TabletKeyspaceShard(t, "ks", "-80"))
+	NewFakeTablet(t, wr, "cell1", 1, topodatapb.TabletType_REPLICA, nil, TabletKeyspaceShard(t, "ks", "-80"))
+	NewFakeTablet(t, wr, "cell1", 2, topodatapb.TabletType_PRIMARY, nil, TabletKeyspaceShard(t, "ks", "80-"))
+	NewFakeTablet(t, wr, "cell2", 3, topodatapb.TabletType_REPLICA, nil, TabletKeyspaceShard(t, "ks", "80-"))
+	// A tablet of another keyspace, which must be left alone.
+	NewFakeTablet(t, wr, "cell1", 4, topodatapb.TabletType_PRIMARY, nil)
+
+	// Lose the graph of -80 in cell1, and add a stale entry to that of
+	// 80- in cell2.
+	require.NoError(t, ts.DeleteShardReplication(ctx, "cell1", "ks", "-80"))
+	stale := &topodatapb.TabletAlias{Cell: "cell2", Uid: 99}
+	require.NoError(t, topo.UpdateShardReplicationRecord(ctx, ts, "ks", "80-", stale))
+
+	output, err := vp.RunAndOutput([]string{"RebuildReplicationGraph", "--cells=cell1,cell2", "ks"})
+	require.NoError(t, err)
+	require.Contains(t, output, "ks/-80 in cell1: added 2 [cell1-0000000000, cell1-0000000001], removed 0 []")
+	require.Contains(t, output, "ks/-80 in cell2: added 0 [], removed 0 []")
+	require.Contains(t, output, "ks/80- in cell1: added 0 [], removed 0 []")
+	require.Contains(t, output, "ks/80- in cell2: added 0 [], removed 1 [cell2-0000000099 (NOT_FOUND)]")
+
+	for cell, want := range map[string]int{"cell1": 2, "cell2": 0} {
+		sri, err := ts.GetShardReplication(ctx, cell, "ks", "-80")
+		if want == 0 {
+			require.True(t, topo.IsErrType(err, topo.NoNode), "%v: %v", cell, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Len(t, sri.Nodes, want, cell)
 	}
+	sri, err := ts.GetShardReplication(ctx, "cell2", "ks", "80-")
+	require.NoError(t, err)
+	require.Len(t, sri.Nodes, 1)
+	require.Equal(t, uint32(3), sri.Nodes[0].TabletAlias.Uid)
+
+	// All cells are rebuilt by default, and there is nothing left to do.
+	output, err = vp.RunAndOutput([]string{"RebuildReplicationGraph", "ks"})
+	require.NoError(t, err)
+	require.NotContains(t, output, "added 1")
+	require.NotContains(t, output, "removed 1")
+	require.Contains(t, output, "ks/80- in cell2: added 0 [], removed 0 []")
+
+	_, err = vp.RunAndOutput([]string{"RebuildReplicationGraph"})
+	require.ErrorContains(t, err, "the <keyspace> argument is required")
 }
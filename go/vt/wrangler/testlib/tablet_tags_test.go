@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testlib
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// TestTabletTags tests that the tags added and removed with AddTabletTag
+// and RemoveTabletTag are kept when the tablet publishes its state.
+func TestTabletTags(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, nil)
+	vp := NewVtctlPipe(ctx, t, ts)
+	defer vp.Close()
+
+	primary := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_PRIMARY, nil)
+	replica := NewFakeTablet(t, wr, "cell1", 1, topodatapb.TabletType_REPLICA, nil)
+	primary.StartActionLoop(t, wr)
+	defer primary.StopActionLoop(t)
+	replica.FakeMysqlDaemon.SetReplicationSourceInputs = append(replica.FakeMysqlDaemon.SetReplicationSourceInputs, topoproto.MysqlAddr(primary.Tablet))
+	replica.FakeMysqlDaemon.ExpectedExecuteSuperQueryList = []string{
+		// These 3 statements come from tablet startup
+		"STOP REPLICA",
+		"FAKE SET SOURCE",
+		"START REPLICA",
+	}
+	replica.StartActionLoop(t, wr)
+	defer replica.StopActionLoop(t)
+
+	alias := topoproto.TabletAliasString(replica.Tablet.Alias)
+	require.NoError(t, vp.Run([]string{"AddTabletTag", alias, "maintenance:true", "rack:r1"}))
+	require.NoError(t, vp.Run([]string{"RemoveTabletTag", alias, "maintenance"}))
+
+	// The tablet publishes its whole record when its type changes.
+	require.NoError(t, vp.Run([]string{"ChangeTabletType", alias, "rdonly"}))
+	ti, err := ts.GetTablet(ctx, replica.Tablet.Alias)
+	require.NoError(t, err)
+	require.Equal(t, topodatapb.TabletType_RDONLY, ti.Type)
+	require.Equal(t, map[string]string{"rack": "r1"}, ti.Tags)
+	require.Equal(t, map[string]string{"rack": "r1"}, replica.TM.Tablet().Tags)
+}
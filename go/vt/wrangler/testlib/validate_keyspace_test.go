@@ -0,0 +1,119 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testlib
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// TestValidateKeyspaceNonServingShards tests that the keyspace-wide
+// validators skip the overlapping shard of a reshard, which is not serving
+// yet, unless asked to include it.
+func TestValidateKeyspaceNonServingShards(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, nil)
+	vp := NewVtctlPipe(ctx, t, ts)
+	defer vp.Close()
+
+	// The -80 shard is created over the serving 0 shard, so it is not
+	// serving, and its primary diverges from the one of 0 in every way.
+	servingPrimary := NewFakeTablet(t, wr, "cell1", 100, topodatapb.TabletType_PRIMARY, nil,
+		TabletKeyspaceShard(t, "ks", "0"),
+		StartHTTPServer())
+	nonServingPrimary := NewFakeTablet(t, wr, "cell1", 200, topodatapb.TabletType_PRIMARY, nil,
+		TabletKeyspaceShard(t, "ks", "-80"),
+		StartHTTPServer())
+	for _, primary := range []*FakeTablet{servingPrimary, nonServingPrimary} {
+		_, err := ts.UpdateShardFields(ctx, "ks", primary.Tablet.Shard, func(si *topo.ShardInfo) error {
+			si.PrimaryAlias = primary.Tablet.Alias
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	si, err := ts.GetShard(ctx, "ks", "-80")
+	require.NoError(t, err)
+	require.False(t, si.IsPrimaryServing)
+
+	servingGitRev, nonServingGitRev := "fake git rev", "other fake git rev"
+	servingPrimary.FakeMysqlDaemon.Schema = &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+			Name:   "t1",
+			Schema: "CREATE TABLE `t1` (`id` bigint NOT NULL, PRIMARY KEY (`id`))",
+			Type:   tmutils.TableBaseTable,
+		}},
+	}
+	nonServingPrimary.FakeMysqlDaemon.Schema = &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+			Name:   "t2",
+			Schema: "CREATE TABLE `t2` (`id` bigint NOT NULL, PRIMARY KEY (`id`))",
+			Type:   tmutils.TableBaseTable,
+		}},
+	}
+	// The permissions of the non-serving primary can't be read.
+	servingPrimary.FakeMysqlDaemon.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		"SELECT * FROM mysql.user ORDER BY host, user": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Host|User|Password", "char|char|char"),
+			"test_host|test_user|test_password"),
+		"SELECT * FROM mysql.db ORDER BY host, db, user": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Host|Db|User", "char|char|char"),
+			"test_host|test_db|test_user"),
+		"SELECT * FROM mysql.tables_priv ORDER BY host, db, user, table_name":               sqltypes.MakeTestResult(sqltypes.MakeTestFields("Host|Db|User|Table_name", "char|char|char|char")),
+		"SELECT * FROM mysql.columns_priv ORDER BY host, db, user, table_name, column_name": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Host|Db|User|Table_name|Column_name", "char|char|char|char|char")),
+	}
+
+	servingPrimary.StartActionLoop(t, wr)
+	defer servingPrimary.StopActionLoop(t)
+	servingPrimary.HTTPServer.Handler.(*http.ServeMux).HandleFunc("/debug/vars", expvarHandler(&servingGitRev))
+	nonServingPrimary.StartActionLoop(t, wr)
+	defer nonServingPrimary.StopActionLoop(t)
+	nonServingPrimary.HTTPServer.Handler.(*http.ServeMux).HandleFunc("/debug/vars", expvarHandler(&nonServingGitRev))
+
+	for _, tc := range []struct {
+		command string
+		err     string
+	}{{
+		command: "ValidateVersionKeyspace",
+		err:     "primary cell1-0000000100 version fake git rev is different than primary cell1-0000000200 version other fake git rev",
+	}, {
+		command: "ValidateSchemaKeyspace",
+		err:     "has an extra table named",
+	}, {
+		command: "ValidatePermissionsKeyspace",
+		err:     "cannot get the permissions of cell1-0000000200",
+	}} {
+		t.Run(tc.command, func(t *testing.T) {
+			require.NoError(t, vp.Run([]string{tc.command, "ks"}))
+			require.ErrorContains(t, vp.Run([]string{tc.command, "--include-non-serving-shards", "ks"}), tc.err)
+		})
+	}
+}
@@ -28,7 +28,6 @@ import (
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtenv"
-	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
@@ -61,17 +60,12 @@ func expvarHandler(gitRev *string) func(http.ResponseWriter, *http.Request) {
 }
 
 func TestVersion(t *testing.T) {
-	delay := discovery.GetTabletPickerRetryDelay()
-	defer func() {
-		discovery.SetTabletPickerRetryDelay(delay)
-	}()
-	discovery.SetTabletPickerRetryDelay(5 * time.Millisecond)
-
 	// Initialize our environment
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
-	wr := wrangler.New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.NewWithOptions(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts,
+		wrangler.WithTabletPickerOptions(discovery.TabletPickerOptions{RetryDelay: 5 * time.Millisecond}))
 	vp := NewVtctlPipe(ctx, t, ts)
 	defer vp.Close()
 
@@ -105,7 +99,7 @@ func TestVersion(t *testing.T) {
 	// test when versions are the same
 	sourceReplicaGitRev = "fake git rev"
 	if err := vp.Run([]string{"ValidateVersionKeyspace", sourcePrimary.Tablet.Keyspace}); err != nil {
-		require("ValidateVersionKeyspace(same) failed: %v", err)
+		t.Fatalf("ValidateVersionKeyspace(same) failed: %v", err)
 	}
 
 	// test when versions are different
@@ -113,6 +107,32 @@ func TestVersion(t *testing.T) {
 	err := vp.Run([]string{"ValidateVersionKeyspace", sourcePrimary.Tablet.Keyspace})
 	fmt.Printf("ERROR %v", err)
 	if err == nil || !strings.Contains(err.Error(), "is different than replica") {
-		require("ValidateVersionKeyspace(different) returned an unexpected error: %v", err)
+		t.Fatalf("ValidateVersionKeyspace(different) returned an unexpected error: %v", err)
+	}
+
+	// A DRAINED replica is skipped and listed, unless --include-non-serving
+	// is set.
+	if _, err := ts.UpdateTabletFields(ctx, sourceReplica.Tablet.Alias, func(tablet *topodatapb.Tablet) error {
+		tablet.Type = topodatapb.TabletType_DRAINED
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateTabletFields failed: %v", err)
+	}
+	for _, command := range []string{"ValidateVersionKeyspace", "ValidateVersionShard"} {
+		target := sourcePrimary.Tablet.Keyspace
+		if command == "ValidateVersionShard" {
+			target += "/" + sourcePrimary.Tablet.Shard
+		}
+		out, err := vp.RunAndOutput([]string{command, target})
+		if err != nil {
+			t.Fatalf("%v(drained) failed: %v", command, err)
+		}
+		if !strings.Contains(out, "Skipped DRAINED tablet cell1-0000000011 of shard 0") {
+			t.Fatalf("%v(drained) didn't list the skipped tablet: %v", command, out)
+		}
+		err = vp.Run([]string{command, "--include-non-serving", target})
+		if err == nil || !strings.Contains(err.Error(), "is different than replica") {
+			t.Fatalf("%v(drained, --include-non-serving) returned an unexpected error: %v", command, err)
+		}
 	}
 }
@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testlib
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtctl"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// runAndCollectEvents runs the vtctl command and returns the events it
+// logged, and its error.
+func runAndCollectEvents(t *testing.T, vp *VtctlPipe, args []string) ([]*logutilpb.Event, error) {
+	stream, err := vp.RunAndStreamOutput(args)
+	require.NoError(t, err)
+	var events []*logutilpb.Event
+	for {
+		e, err := stream.Recv()
+		switch err {
+		case nil:
+			events = append(events, e)
+		case io.EOF:
+			return events, nil
+		default:
+			return events, err
+		}
+	}
+}
+
+// warnings returns the values of the WARNING events.
+func warnings(events []*logutilpb.Event) []string {
+	var values []string
+	for _, e := range events {
+		if e.Level == logutilpb.Level_WARNING {
+			values = append(values, e.Value)
+		}
+	}
+	return values
+}
+
+func TestVtctlCommandAliases(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "0"))
+	vp := NewVtctlPipe(ctx, t, ts)
+	defer vp.Close()
+
+	// A command invoked by its name or a plain alias doesn't warn.
+	events, err := runAndCollectEvents(t, vp, []string{"SetShardIsPrimaryServing", "ks/0", "true"})
+	require.NoError(t, err)
+	assert.Empty(t, warnings(events))
+	events, err = runAndCollectEvents(t, vp, []string{"GetTablets", "cell1"})
+	require.NoError(t, err)
+	assert.Empty(t, warnings(events))
+
+	// A deprecated alias runs the command, with a warning naming it.
+	events, err = runAndCollectEvents(t, vp, []string{"SetShardIsMasterServing", "ks/0", "false"})
+	require.NoError(t, err)
+	require.Len(t, warnings(events), 1)
+	assert.Contains(t, warnings(events)[0], "DEPRECATED: SetShardIsMasterServing is deprecated and will be removed in a future release. Use SetShardIsPrimaryServing instead.")
+	si, err := ts.GetShard(ctx, "ks", "0")
+	require.NoError(t, err)
+	assert.False(t, si.IsPrimaryServing)
+
+	// The help lists the aliases.
+	output, err := vp.RunAndOutput([]string{"Help"})
+	require.NoError(t, err)
+	assert.Contains(t, output, "(aliases: GetTablets)")
+	assert.Contains(t, output, "(deprecated aliases: SetShardIsMasterServing)")
+
+	// With --fail-on-deprecated, the deprecated alias fails without
+	// running the command.
+	fs := pflag.NewFlagSet("vtctld", pflag.ContinueOnError)
+	vtctl.RegisterFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--fail-on-deprecated"}))
+	defer func() {
+		require.NoError(t, fs.Parse([]string{"--fail-on-deprecated=false"}))
+	}()
+	_, err = runAndCollectEvents(t, vp, []string{"SetShardIsMasterServing", "ks/0", "true"})
+	require.ErrorContains(t, err, "SetShardIsMasterServing is deprecated")
+	require.ErrorContains(t, err, "--fail-on-deprecated is set")
+	si, err = ts.GetShard(ctx, "ks", "0")
+	require.NoError(t, err)
+	assert.False(t, si.IsPrimaryServing)
+
+	// The command itself still runs.
+	_, err = runAndCollectEvents(t, vp, []string{"SetShardIsPrimaryServing", "ks/0", "true"})
+	require.NoError(t, err)
+}
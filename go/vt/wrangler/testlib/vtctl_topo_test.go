@@ -33,7 +33,7 @@ import (
 func testVtctlTopoCommand(t *testing.T, vp *VtctlPipe, args []string, want string) {
 	got, err := vp.RunAndOutput(args)
 	if err != nil {
-		require("testVtctlTopoCommand(%v) failed: %v", args, err)
+		t.Fatalf("testVtctlTopoCommand(%v) failed: %v", args, err)
 	}
 
 	// Remove the variable version numbers.
@@ -45,7 +45,7 @@ func testVtctlTopoCommand(t *testing.T, vp *VtctlPipe, args []string, want strin
 	}
 	got = strings.Join(lines, "\n")
 	if got != want {
-		assert("testVtctlTopoCommand(%v) failed: got:\n%vwant:\n%v", args, got, want)
+		t.Errorf("testVtctlTopoCommand(%v) failed: got:\n%vwant:\n%v", args, got, want)
 	}
 }
 
@@ -57,10 +57,10 @@ func TestVtctlTopoCommands(t *testing.T) {
 
 	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
 	if err := ts.CreateKeyspace(context.Background(), "ks1", &topodatapb.Keyspace{KeyspaceType: topodatapb.KeyspaceType_NORMAL}); err != nil {
-		require("CreateKeyspace() failed: %v", err)
+		t.Fatalf("CreateKeyspace() failed: %v", err)
 	}
 	if err := ts.CreateKeyspace(context.Background(), "ks2", &topodatapb.Keyspace{KeyspaceType: topodatapb.KeyspaceType_SNAPSHOT}); err != nil {
-		require("CreateKeyspace() failed: %v", err)
+		t.Fatalf("CreateKeyspace() failed: %v", err)
 	}
 	vp := NewVtctlPipe(ctx, t, ts)
 	defer vp.Close()
@@ -77,31 +77,31 @@ keyspace_type:SNAPSHOT
 	ksFile := path.Join(tmp, "Keyspace")
 	_, err := vp.RunAndOutput([]string{"TopoCp", "/keyspaces/ks1/Keyspace", ksFile})
 	if err != nil {
-		require("TopoCp(/keyspaces/ks1/Keyspace) failed: %v", err)
+		t.Fatalf("TopoCp(/keyspaces/ks1/Keyspace) failed: %v", err)
 	}
 	contents, err := os.ReadFile(ksFile)
 	if err != nil {
-		require("copy failed: %v", err)
+		t.Fatalf("copy failed: %v", err)
 	}
 	expected := &topodatapb.Keyspace{KeyspaceType: topodatapb.KeyspaceType_NORMAL}
 	got := &topodatapb.Keyspace{}
 	if err = got.UnmarshalVT(contents); err != nil {
-		require("bad keyspace data %v", err)
+		t.Fatalf("bad keyspace data %v", err)
 	}
 	if !proto.Equal(got, expected) {
-		require("bad proto data: Got %v expected %v", got, expected)
+		t.Fatalf("bad proto data: Got %v expected %v", got, expected)
 	}
 
 	// Test TopoCp from disk to topo.
 	_, err = vp.RunAndOutput([]string{"TopoCp", "--to_topo", ksFile, "/keyspaces/ks3/Keyspace"})
 	if err != nil {
-		require("TopoCp(/keyspaces/ks3/Keyspace) failed: %v", err)
+		t.Fatalf("TopoCp(/keyspaces/ks3/Keyspace) failed: %v", err)
 	}
 	ks3, err := ts.GetKeyspace(context.Background(), "ks3")
 	if err != nil {
-		require("copy from disk to topo failed: %v", err)
+		t.Fatalf("copy from disk to topo failed: %v", err)
 	}
 	if !proto.Equal(ks3.Keyspace, expected) {
-		require("copy data to topo failed, got %v expected %v", ks3.Keyspace, expected)
+		t.Fatalf("copy data to topo failed, got %v expected %v", ks3.Keyspace, expected)
 	}
 }
@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+)
+
+var (
+	// shardTimeout and tabletTimeout are the flag values of the shard and
+	// tablet tiers of Timeouts. Zero derives them from the command timeout.
+	shardTimeout  time.Duration
+	tabletTimeout time.Duration
+)
+
+func init() {
+	servenv.OnParseFor("vtctl", registerTimeoutFlags)
+	servenv.OnParseFor("vtctld", registerTimeoutFlags)
+}
+
+func registerTimeoutFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&shardTimeout, "shard-timeout", shardTimeout, "how long the work on each shard of a keyspace-wide command may take, e.g. validating its schema. Defaults to half of the command timeout.")
+	fs.DurationVar(&tabletTimeout, "tablet-timeout", tabletTimeout, "how long each tablet RPC of a command that fans out to many tablets may take, so that a dead tablet doesn't hold up the whole command. Defaults to the smallest of the shard timeout and --remote_operation_timeout.")
+}
+
+// Timeouts are the time budgets of the tiers of a command: the whole
+// command, the work on each shard of a keyspace-wide command, and each
+// tablet RPC of a fan-out. A tier is never longer than the one above it.
+type Timeouts struct {
+	Command time.Duration
+	Shard   time.Duration
+	Tablet  time.Duration
+}
+
+// String is part of the fmt.Stringer interface.
+func (t Timeouts) String() string {
+	return fmt.Sprintf("command %v, per shard %v, per tablet RPC %v", t.Command, t.Shard, t.Tablet)
+}
+
+// withDefaults returns the timeouts with the tiers that are not set derived
+// from the command timeout, or from DefaultActionTimeout if it isn't set
+// either.
+func (t Timeouts) withDefaults() Timeouts {
+	if t.Command <= 0 {
+		t.Command = DefaultActionTimeout
+	}
+	if t.Shard <= 0 {
+		t.Shard = t.Command / 2
+	}
+	t.Shard = min(t.Shard, t.Command)
+	if t.Tablet <= 0 {
+		t.Tablet = topo.RemoteOperationTimeout
+	}
+	t.Tablet = min(t.Tablet, t.Shard)
+	return t
+}
+
+// Timeouts returns the timeouts in effect for a command running with ctx:
+// the ones set with WithTimeouts, then those of the --shard-timeout and
+// --tablet-timeout flags, the command timeout being what is left until the
+// deadline of ctx.
+func (wr *Wrangler) Timeouts(ctx context.Context) Timeouts {
+	t := wr.timeouts
+	if t.Command <= 0 {
+		if deadline, ok := ctx.Deadline(); ok {
+			t.Command = time.Until(deadline)
+		}
+	}
+	if t.Shard <= 0 {
+		t.Shard = shardTimeout
+	}
+	if t.Tablet <= 0 {
+		t.Tablet = tabletTimeout
+	}
+	return t.withDefaults()
+}
+
+// shardContext returns the context of the work on one shard of a
+// keyspace-wide fan-out, which times out after the shard timeout.
+func (wr *Wrangler) shardContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, wr.Timeouts(ctx).Shard)
+}
+
+// vtctldContext returns ctx with the tablet timeout, for the calls to the
+// vtctld server that fan out to many tablets, e.g. the validations.
+func (wr *Wrangler) vtctldContext(ctx context.Context) context.Context {
+	return grpcvtctldserver.WithTabletTimeout(ctx, wr.Timeouts(ctx).Tablet)
+}
+
+// tabletContext returns the context of one tablet RPC of a fan-out, which
+// times out after the tablet timeout.
+func (wr *Wrangler) tabletContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, wr.Timeouts(ctx).Tablet)
+}
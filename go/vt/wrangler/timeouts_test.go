@@ -0,0 +1,68 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+)
+
+func TestTimeouts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+	wr := New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, nil)
+
+	// Without a deadline, the tiers are derived from DefaultActionTimeout.
+	require.Equal(t, Timeouts{
+		Command: DefaultActionTimeout,
+		Shard:   DefaultActionTimeout / 2,
+		Tablet:  min(topo.RemoteOperationTimeout, DefaultActionTimeout/2),
+	}, wr.Timeouts(ctx))
+
+	// A short command shortens the tiers below it.
+	timeouts := wr.WithTimeouts(Timeouts{Command: 10 * time.Second}).Timeouts(ctx)
+	require.Equal(t, Timeouts{Command: 10 * time.Second, Shard: 5 * time.Second, Tablet: 5 * time.Second}, timeouts)
+
+	// The tiers that are set are kept, but never exceed the one above.
+	timeouts = wr.WithTimeouts(Timeouts{Command: time.Hour, Shard: 2 * time.Hour, Tablet: 3 * time.Second}).Timeouts(ctx)
+	require.Equal(t, Timeouts{Command: time.Hour, Shard: time.Hour, Tablet: 3 * time.Second}, timeouts)
+	require.Equal(t, "command 1h0m0s, per shard 1h0m0s, per tablet RPC 3s", timeouts.String())
+
+	// The command timeout is what is left until the deadline.
+	deadlineCtx, deadlineCancel := context.WithTimeout(ctx, time.Minute)
+	defer deadlineCancel()
+	timeouts = wr.Timeouts(deadlineCtx)
+	require.LessOrEqual(t, timeouts.Command, time.Minute)
+	require.Greater(t, timeouts.Command, 50*time.Second)
+	require.Equal(t, timeouts.Command/2, timeouts.Shard)
+
+	// The tablet context times out after the tablet tier.
+	tabletCtx, tabletCancel := wr.WithTimeouts(Timeouts{Tablet: time.Millisecond}).tabletContext(ctx)
+	defer tabletCancel()
+	<-tabletCtx.Done()
+	require.ErrorIs(t, tabletCtx.Err(), context.DeadlineExceeded)
+}
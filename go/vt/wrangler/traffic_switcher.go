@@ -452,7 +452,7 @@ func (wr *Wrangler) areTabletsAvailableToStreamFrom(ctx context.Context, ts *tra
 			if cells == nil {
 				cells = append(cells, shard.PrimaryAlias.Cell)
 			}
-			tp, err := discovery.NewTabletPicker(ctx, wr.ts, cells, shard.PrimaryAlias.Cell, keyspace, shard.ShardName(), tabletTypes, discovery.TabletPickerOptions{})
+			tp, err := discovery.NewTabletPicker(ctx, wr.ts, cells, shard.PrimaryAlias.Cell, keyspace, shard.ShardName(), tabletTypes, wr.pickerOptions)
 			if err != nil {
 				allErrors.RecordError(err)
 				return
@@ -1225,7 +1225,9 @@ func (ts *trafficSwitcher) stopSourceWrites(ctx context.Context) error {
 	}
 	return ts.ForAllSources(func(source *workflow.MigrationSource) error {
 		var err error
-		source.Position, err = ts.TabletManagerClient().PrimaryPosition(ctx, source.GetPrimary().Tablet)
+		source.Position, err = callTablet(ctx, "PrimaryPosition", idempotent, func(ctx context.Context) (string, error) {
+			return ts.TabletManagerClient().PrimaryPosition(ctx, source.GetPrimary().Tablet)
+		})
 		ts.wr.Logger().Infof("Stopped Source Writes. Position for source %v:%v: %v",
 			ts.SourceKeyspaceName(), source.GetShard().ShardName(), source.Position)
 		if err != nil {
@@ -1304,7 +1306,9 @@ func (ts *trafficSwitcher) waitForCatchup(ctx context.Context, filteredReplicati
 		source := ts.Sources()[bls.Shard]
 		ts.Logger().Infof("Before Catchup: waiting for keyspace:shard: %v:%v to reach source position %v, uid %d",
 			ts.TargetKeyspaceName(), target.GetShard().ShardName(), source.Position, uid)
-		if err := ts.TabletManagerClient().VReplicationWaitForPos(ctx, target.GetPrimary().Tablet, uid, source.Position); err != nil {
+		if _, err := callTablet(ctx, "VReplicationWaitForPos", idempotent, func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, ts.TabletManagerClient().VReplicationWaitForPos(ctx, target.GetPrimary().Tablet, uid, source.Position)
+		}); err != nil {
 			return err
 		}
 		log.Infof("After catchup: target keyspace:shard: %v:%v, source position %v, uid %d",
@@ -1322,13 +1326,20 @@ func (ts *trafficSwitcher) waitForCatchup(ctx context.Context, filteredReplicati
 	// all targets have caught up, record their positions for setting up reverse workflows
 	return ts.ForAllTargets(func(target *workflow.MigrationTarget) error {
 		var err error
-		target.Position, err = ts.TabletManagerClient().PrimaryPosition(ctx, target.GetPrimary().Tablet)
+		target.Position, err = callTablet(ctx, "PrimaryPosition", idempotent, func(ctx context.Context) (string, error) {
+			return ts.TabletManagerClient().PrimaryPosition(ctx, target.GetPrimary().Tablet)
+		})
 		ts.Logger().Infof("After catchup, position for target primary %s, %v", target.GetPrimary().AliasString(), target.Position)
 		return err
 	})
 }
 
 func (ts *trafficSwitcher) cancelMigration(ctx context.Context, sm *workflow.StreamMigrator) {
+	// The migration is often cancelled because ctx is done, but the source
+	// writes still need to be restored.
+	ctx, cancel := ts.wr.cleanupContext(ctx)
+	defer cancel()
+
 	var err error
 	if ts.MigrationType() == binlogdatapb.MigrationType_TABLES {
 		err = ts.changeTableSourceWrites(ctx, allowWrites)
@@ -1359,7 +1370,9 @@ func (ts *trafficSwitcher) cancelMigration(ctx context.Context, sm *workflow.Str
 func (ts *trafficSwitcher) gatherPositions(ctx context.Context) error {
 	err := ts.ForAllSources(func(source *workflow.MigrationSource) error {
 		var err error
-		source.Position, err = ts.TabletManagerClient().PrimaryPosition(ctx, source.GetPrimary().Tablet)
+		source.Position, err = callTablet(ctx, "PrimaryPosition", idempotent, func(ctx context.Context) (string, error) {
+			return ts.TabletManagerClient().PrimaryPosition(ctx, source.GetPrimary().Tablet)
+		})
 		ts.Logger().Infof("Position for source %v:%v: %v", ts.SourceKeyspaceName(), source.GetShard().ShardName(), source.Position)
 		return err
 	})
@@ -1368,7 +1381,9 @@ func (ts *trafficSwitcher) gatherPositions(ctx context.Context) error {
 	}
 	return ts.ForAllTargets(func(target *workflow.MigrationTarget) error {
 		var err error
-		target.Position, err = ts.TabletManagerClient().PrimaryPosition(ctx, target.GetPrimary().Tablet)
+		target.Position, err = callTablet(ctx, "PrimaryPosition", idempotent, func(ctx context.Context) (string, error) {
+			return ts.TabletManagerClient().PrimaryPosition(ctx, target.GetPrimary().Tablet)
+		})
 		ts.Logger().Infof("Position for target %v:%v: %v", ts.TargetKeyspaceName(), target.GetShard().ShardName(), target.Position)
 		return err
 	})
@@ -2199,7 +2214,7 @@ func (ts *trafficSwitcher) initializeTargetSequences(ctx context.Context, sequen
 				sqlescape.EscapeID(sequenceMetadata.usingTableDBName),
 				sqlescape.EscapeID(sequenceMetadata.usingTableName),
 			)
-			qr, terr := ts.wr.ExecuteFetchAsApp(ictx, primary.GetAlias(), true, query.Query, 1)
+			qr, terr := ts.wr.ExecuteFetchAsApp(ictx, primary.GetAlias(), true, query.Query, 1, nil)
 			if terr != nil || len(qr.Rows) != 1 {
 				return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to get the max used sequence value for target table %s.%s on tablet %s in order to initialize the backing sequence table: %v",
 					ts.targetKeyspace, sequenceMetadata.usingTableName, topoproto.TabletAliasString(primary.Alias), terr)
@@ -2264,7 +2279,7 @@ func (ts *trafficSwitcher) initializeTargetSequences(ctx context.Context, sequen
 		)
 		// Now execute this on the primary tablet of the unsharded keyspace
 		// housing the backing table.
-		qr, ierr := ts.wr.ExecuteFetchAsApp(ictx, sequenceShard.PrimaryAlias, true, query.Query, 1)
+		qr, ierr := ts.wr.ExecuteFetchAsApp(ictx, sequenceShard.PrimaryAlias, true, query.Query, 1, nil)
 		if ierr != nil {
 			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to initialize the backing sequence table %s.%s: %v",
 				sequenceMetadata.backingTableDBName, sequenceMetadata.backingTableName, ierr)
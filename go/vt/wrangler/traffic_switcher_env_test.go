@@ -297,11 +297,11 @@ func newTestTableMigraterCustom(ctx context.Context, t *testing.T, sourceShards,
 	if err := tme.ts.RebuildSrvVSchema(ctx, nil); err != nil {
 		t.Fatal(err)
 	}
-	err := topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), tme.ts, "ks1", []string{"cell1"}, false)
+	err := topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), tme.ts, "ks1", []string{"cell1"}, false, false)
 	if err != nil {
 		t.Fatal(err)
 	}
-	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), tme.ts, "ks2", []string{"cell1"}, false)
+	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), tme.ts, "ks2", []string{"cell1"}, false, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -483,9 +483,9 @@ func newTestTablePartialMigrater(ctx context.Context, t *testing.T, shards, shar
 	require.NoError(t, err)
 	err = tme.ts.RebuildSrvVSchema(ctx, nil)
 	require.NoError(t, err)
-	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), tme.ts, "ks1", []string{"cell1"}, false)
+	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), tme.ts, "ks1", []string{"cell1"}, false, false)
 	require.NoError(t, err)
-	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), tme.ts, "ks2", []string{"cell1"}, false)
+	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), tme.ts, "ks2", []string{"cell1"}, false, false)
 	require.NoError(t, err)
 
 	tme.startTablets(t)
@@ -657,7 +657,7 @@ func newTestShardMigrater(ctx context.Context, t *testing.T, sourceShards, targe
 	if err := tme.ts.RebuildSrvVSchema(ctx, nil); err != nil {
 		t.Fatal(err)
 	}
-	err := topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), tme.ts, "ks", nil, false)
+	err := topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), tme.ts, "ks", nil, false, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -749,7 +749,7 @@ func (tme *testMigraterEnv) startTablets(t *testing.T) {
 			time.Sleep(10 * time.Millisecond)
 		}
 		if !primaryFound {
-			require("shard primary did not get updated for tablet: %v", primary)
+			t.Fatalf("shard primary did not get updated for tablet: %v", primary)
 		}
 	}
 }
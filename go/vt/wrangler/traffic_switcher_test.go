@@ -281,7 +281,7 @@ func TestTableMigrateMainflow(t *testing.T) {
 	_, err = tme.wr.SwitchReads(ctx, tme.targetKeyspace, "test", []topodatapb.TabletType{topodatapb.TabletType_PRIMARY}, nil, workflow.DirectionForward, false)
 	want := "invalid tablet type: tablet type must be REPLICA or RDONLY: PRIMARY"
 	if err == nil || err.Error() != want {
-		assert("SwitchReads(primary) err: %v, want %v", err, want)
+		t.Errorf("SwitchReads(primary) err: %v, want %v", err, want)
 	}
 	verifyQueries(t, tme.allDBClients)
 
@@ -352,7 +352,7 @@ func TestTableMigrateMainflow(t *testing.T) {
 	_, _, err = tme.wr.SwitchWrites(ctx, tme.targetKeyspace, "test", 0*time.Second, false, false, true, false, true)
 	want = "DeadlineExceeded"
 	if err == nil || !strings.Contains(err.Error(), want) {
-		assert("SwitchWrites(0 timeout) err: %v, must contain %v", err, want)
+		t.Errorf("SwitchWrites(0 timeout) err: %v, must contain %v", err, want)
 	}
 	verifyQueries(t, tme.allDBClients)
 	checkRouting(t, tme.wr, map[string][]string{
@@ -462,7 +462,7 @@ func TestTableMigrateMainflow(t *testing.T) {
 		t.Fatal(err)
 	}
 	if journalID != 7672494164556733923 {
-		assert("journal id: %d, want 7672494164556733923", journalID)
+		t.Errorf("journal id: %d, want 7672494164556733923", journalID)
 	}
 
 	checkRouting(t, tme.wr, map[string][]string{
@@ -602,7 +602,7 @@ func TestShardMigrateMainflow(t *testing.T) {
 	_, err = tme.wr.SwitchReads(ctx, tme.targetKeyspace, "test", []topodatapb.TabletType{topodatapb.TabletType_PRIMARY}, nil, workflow.DirectionForward, false)
 	want := "invalid tablet type: tablet type must be REPLICA or RDONLY: PRIMARY"
 	if err == nil || err.Error() != want {
-		assert("SwitchReads(primary) err: %v, want %v", err, want)
+		t.Errorf("SwitchReads(primary) err: %v, want %v", err, want)
 	}
 	verifyQueries(t, tme.allDBClients)
 
@@ -667,7 +667,7 @@ func TestShardMigrateMainflow(t *testing.T) {
 	_, _, err = tme.wr.SwitchWrites(ctx, tme.targetKeyspace, "test", 0*time.Second, false, false, true, false, true)
 	want = "DeadlineExceeded"
 	if err == nil || !strings.Contains(err.Error(), want) {
-		assert("SwitchWrites(0 timeout) err: %v, must contain %v", err, want)
+		t.Errorf("SwitchWrites(0 timeout) err: %v, must contain %v", err, want)
 	}
 
 	verifyQueries(t, tme.allDBClients)
@@ -758,7 +758,7 @@ func TestShardMigrateMainflow(t *testing.T) {
 		t.Fatal(err)
 	}
 	if journalID != 6432976123657117097 {
-		assert("journal id: %d, want 6432976123657117097", journalID)
+		t.Errorf("journal id: %d, want 6432976123657117097", journalID)
 	}
 
 	verifyQueries(t, tme.allDBClients)
@@ -1196,18 +1196,18 @@ func TestMigrateFailJournal(t *testing.T) {
 	_, _, err = tme.wr.SwitchWrites(ctx, tme.targetKeyspace, "test", 1*time.Second, false, false, true, false, true)
 	want := "journaling intentionally failed"
 	if err == nil || !strings.Contains(err.Error(), want) {
-		assert("SwitchWrites(0 timeout) err: %v, must contain %v", err, want)
+		t.Errorf("SwitchWrites(0 timeout) err: %v, must contain %v", err, want)
 	}
 
 	// Verify that cancel didn't happen.
 	if tme.dbTargetClients[0].queries[cancel1].exhausted() {
-		assert("tme.dbTargetClients[0].queries[cancel1].exhausted: %v, want false", tme.dbTargetClients[0].queries[cancel1])
+		t.Errorf("tme.dbTargetClients[0].queries[cancel1].exhausted: %v, want false", tme.dbTargetClients[0].queries[cancel1])
 	}
 	if tme.dbTargetClients[1].queries[cancel1].exhausted() {
-		assert("tme.dbTargetClients[0].queries[cancel1].exhausted: %v, want false", tme.dbTargetClients[0].queries[cancel1])
+		t.Errorf("tme.dbTargetClients[0].queries[cancel1].exhausted: %v, want false", tme.dbTargetClients[0].queries[cancel1])
 	}
 	if tme.dbTargetClients[0].queries[cancel2].exhausted() {
-		assert("tme.dbTargetClients[0].queries[cancel1].exhausted: %v, want false", tme.dbTargetClients[0].queries[cancel1])
+		t.Errorf("tme.dbTargetClients[0].queries[cancel1].exhausted: %v, want false", tme.dbTargetClients[0].queries[cancel1])
 	}
 }
 
@@ -1465,6 +1465,82 @@ func TestTableMigrateCancelDryRun(t *testing.T) {
 	require.Empty(t, cmp.Diff(want, *dryRunResults))
 }
 
+// TestTableMigrateCancelledContext tests that SwitchWrites restores the
+// source writes when its context is cancelled while waiting for the streams
+// to catch up.
+func TestTableMigrateCancelledContext(t *testing.T) {
+	ctx := context.Background()
+	tme := newTestTableMigrater(ctx, t)
+	defer tme.close(t)
+
+	tme.expectNoPreviousJournals()
+	_, err := tme.wr.SwitchReads(ctx, tme.targetKeyspace, "test", []topodatapb.TabletType{topodatapb.TabletType_RDONLY}, nil, workflow.DirectionForward, false)
+	require.NoError(t, err)
+	tme.expectNoPreviousJournals()
+	_, err = tme.wr.SwitchReads(ctx, tme.targetKeyspace, "test", []topodatapb.TabletType{topodatapb.TabletType_REPLICA}, nil, workflow.DirectionForward, false)
+	require.NoError(t, err)
+
+	tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=7672494164556733923", &sqltypes.Result{}, nil)
+	tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=7672494164556733923", &sqltypes.Result{}, nil)
+
+	// The streams never catch up.
+	behind := sqltypes.MakeTestResult(sqltypes.MakeTestFields(
+		"pos|state|message",
+		"varchar|varchar|varchar"),
+		"MariaDB/5-456-800|Running",
+	)
+	for _, dbclient := range tme.dbTargetClients {
+		dbclient.addInvariant("select pos, state, message from _vt.vreplication where id=1", behind)
+		dbclient.addInvariant("select pos, state, message from _vt.vreplication where id=2", behind)
+	}
+
+	// cancelMigration
+	tme.dbTargetClients[0].addQuery("select id from _vt.vreplication where db_name = 'vt_ks2' and workflow = 'test'", resultid12, nil)
+	tme.dbTargetClients[1].addQuery("select id from _vt.vreplication where db_name = 'vt_ks2' and workflow = 'test'", resultid12, nil)
+	tme.dbTargetClients[0].addQuery("update _vt.vreplication set state = 'Running', message = '' where id in (1, 2)", &sqltypes.Result{}, nil)
+	tme.dbTargetClients[1].addQuery("update _vt.vreplication set state = 'Running', message = '' where id in (1, 2)", &sqltypes.Result{}, nil)
+	tme.dbTargetClients[0].addQuery("select * from _vt.vreplication where id = 1", runningResult(1), nil)
+	tme.dbTargetClients[0].addQuery("select * from _vt.vreplication where id = 2", runningResult(2), nil)
+	tme.dbTargetClients[1].addQuery("select * from _vt.vreplication where id = 1", runningResult(1), nil)
+	tme.dbTargetClients[1].addQuery("select * from _vt.vreplication where id = 2", runningResult(2), nil)
+	tme.dbSourceClients[0].addQuery("select id from _vt.vreplication where db_name = 'vt_ks1' and workflow = 'test_reverse'", resultid34, nil)
+	tme.dbSourceClients[1].addQuery("select id from _vt.vreplication where db_name = 'vt_ks1' and workflow = 'test_reverse'", resultid34, nil)
+	tme.dbSourceClients[0].addQuery("delete from _vt.vreplication where id in (3, 4)", &sqltypes.Result{}, nil)
+	tme.dbSourceClients[1].addQuery("delete from _vt.vreplication where id in (3, 4)", &sqltypes.Result{}, nil)
+	tme.dbSourceClients[0].addQuery("delete from _vt.copy_state where vrepl_id in (3, 4)", &sqltypes.Result{}, nil)
+	tme.dbSourceClients[0].addQuery("delete from _vt.post_copy_action where vrepl_id in (3, 4)", &sqltypes.Result{}, nil)
+	tme.dbSourceClients[1].addQuery("delete from _vt.copy_state where vrepl_id in (3, 4)", &sqltypes.Result{}, nil)
+	tme.dbSourceClients[1].addQuery("delete from _vt.post_copy_action where vrepl_id in (3, 4)", &sqltypes.Result{}, nil)
+
+	switchWrites(tme)
+
+	// Cancel the command once the source writes are stopped.
+	cmdCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		defer cancel()
+		for cmdCtx.Err() == nil {
+			stopped := true
+			for _, shard := range tme.sourceShards {
+				si, err := tme.ts.GetShard(ctx, "ks1", shard)
+				if err != nil || si.GetTabletControl(topodatapb.TabletType_PRIMARY) == nil {
+					stopped = false
+				}
+			}
+			if stopped {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	_, _, err = tme.wr.SwitchWrites(cmdCtx, tme.targetKeyspace, "test", 30*time.Second, false, false, true, false, true)
+	require.ErrorContains(t, err, "context canceled")
+	verifyQueries(t, tme.allDBClients)
+	checkDenyList(t, tme.ts, "ks1:-40", nil)
+	checkDenyList(t, tme.ts, "ks1:40-", nil)
+}
+
 func TestTableMigrateNoReverse(t *testing.T) {
 	ctx := context.Background()
 	tme := newTestTableMigrater(ctx, t)
@@ -1622,7 +1698,7 @@ func TestMigrateNoStreamsFound(t *testing.T) {
 	_, err := tme.wr.SwitchReads(ctx, tme.targetKeyspace, "test", []topodatapb.TabletType{topodatapb.TabletType_RDONLY}, nil, workflow.DirectionForward, false)
 	want := "workflow test not found in keyspace ks2"
 	if err == nil || !strings.Contains(err.Error(), want) {
-		assert("SwitchReads: %v, must contain %v", err, want)
+		t.Errorf("SwitchReads: %v, must contain %v", err, want)
 	}
 }
 
@@ -1654,7 +1730,7 @@ func TestMigrateDistinctSources(t *testing.T) {
 	_, err := tme.wr.SwitchReads(ctx, tme.targetKeyspace, "test", []topodatapb.TabletType{topodatapb.TabletType_RDONLY}, nil, workflow.DirectionForward, false)
 	want := "source keyspaces are mismatched across streams"
 	if err == nil || !strings.Contains(err.Error(), want) {
-		assert("SwitchReads: %v, must contain %v", err, want)
+		t.Errorf("SwitchReads: %v, must contain %v", err, want)
 	}
 }
 
@@ -1684,7 +1760,7 @@ func TestMigrateMismatchedTables(t *testing.T) {
 	_, err := tme.wr.SwitchReads(ctx, tme.targetKeyspace, "test", []topodatapb.TabletType{topodatapb.TabletType_RDONLY}, nil, workflow.DirectionForward, false)
 	want := "table lists are mismatched across streams"
 	if err == nil || !strings.Contains(err.Error(), want) {
-		assert("SwitchReads: %v, must contain %v", err, want)
+		t.Errorf("SwitchReads: %v, must contain %v", err, want)
 	}
 }
 
@@ -1699,7 +1775,7 @@ func TestTableMigrateAllShardsNotPresent(t *testing.T) {
 	_, err := tme.wr.SwitchReads(ctx, tme.targetKeyspace, "test", []topodatapb.TabletType{topodatapb.TabletType_RDONLY}, nil, workflow.DirectionForward, false)
 	want := "mismatched shards for keyspace"
 	if err == nil || !strings.Contains(err.Error(), want) {
-		assert("SwitchReads: %v, must contain %v", err, want)
+		t.Errorf("SwitchReads: %v, must contain %v", err, want)
 	}
 }
 
@@ -1757,7 +1833,7 @@ func TestMigrateNoTableWildcards(t *testing.T) {
 	_, err := tme.wr.SwitchReads(ctx, tme.targetKeyspace, "test", []topodatapb.TabletType{topodatapb.TabletType_RDONLY}, nil, workflow.DirectionForward, false)
 	want := "cannot migrate streams with wild card table names: /.*"
 	if err == nil || !strings.Contains(err.Error(), want) {
-		assert("SwitchReads: %v, must contain %v", err, want)
+		t.Errorf("SwitchReads: %v, must contain %v", err, want)
 	}
 }
 
@@ -1915,7 +1991,7 @@ func TestShardMigrateNoAvailableTabletsForReverseReplication(t *testing.T) {
 	_, err = tme.wr.SwitchReads(ctx, tme.targetKeyspace, "test", []topodatapb.TabletType{topodatapb.TabletType_PRIMARY}, nil, workflow.DirectionForward, false)
 	want := "invalid tablet type: tablet type must be REPLICA or RDONLY: PRIMARY"
 	if err == nil || err.Error() != want {
-		assert("SwitchReads(primary) err: %v, want %v", err, want)
+		t.Errorf("SwitchReads(primary) err: %v, want %v", err, want)
 	}
 	verifyQueries(t, tme.allDBClients)
 
@@ -1981,7 +2057,7 @@ func TestShardMigrateNoAvailableTabletsForReverseReplication(t *testing.T) {
 	_, _, err = tme.wr.SwitchWrites(ctx, tme.targetKeyspace, "test", 0*time.Second, false, false, true, false, true)
 	want = "DeadlineExceeded"
 	if err == nil || !strings.Contains(err.Error(), want) {
-		assert("SwitchWrites(0 timeout) err: %v, must contain %v", err, want)
+		t.Errorf("SwitchWrites(0 timeout) err: %v, must contain %v", err, want)
 	}
 
 	verifyQueries(t, tme.allDBClients)
@@ -2088,7 +2164,7 @@ func TestShardMigrateNoAvailableTabletsForReverseReplication(t *testing.T) {
 		t.Fatal(err)
 	}
 	if journalID != 6432976123657117097 {
-		assert("journal id: %d, want 6432976123657117097", journalID)
+		t.Errorf("journal id: %d, want 6432976123657117097", journalID)
 	}
 
 	verifyQueries(t, tme.allDBClients)
@@ -2179,7 +2255,7 @@ func TestNoOrphanedRoutingRulesOnFailedCreate(t *testing.T) {
 	require.NoError(t, err, "failed to save vschema")
 	err = tme.ts.RebuildSrvVSchema(ctx, nil)
 	require.NoError(t, err, "failed to rebuild serving vschema")
-	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), tme.ts, "ks1", []string{"cell1"}, false)
+	err = topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), tme.ts, "ks1", []string{"cell1"}, false, false)
 	require.NoError(t, err, "failed to rebuild keyspace")
 
 	err = tme.wr.MoveTables(ctx, "testwf", "ks1", "ks2", "t1,t2", "cell1", "primary,replica", false, "", true, false, "", false, false, "", "", nil, false, false)
@@ -2198,7 +2274,7 @@ func checkRouting(t *testing.T, wr *Wrangler, want map[string][]string) {
 		t.Fatal(err)
 	}
 	if !reflect.DeepEqual(got, want) {
-		assert("rules:\n%v, want\n%v", got, want)
+		t.Errorf("rules:\n%v, want\n%v", got, want)
 	}
 	cells, err := wr.ts.GetCellInfoNames(ctx)
 	if err != nil {
@@ -2221,7 +2297,7 @@ func checkCellRouting(t *testing.T, wr *Wrangler, cell string, want map[string][
 		got[rr.FromTable] = append(got[rr.FromTable], rr.ToTables...)
 	}
 	if !reflect.DeepEqual(got, want) {
-		require("ERROR: routing rules don't match for cell %s:got\n%v, want\n%v", cell, got, want)
+		t.Fatalf("ERROR: routing rules don't match for cell %s:got\n%v, want\n%v", cell, got, want)
 	}
 }
 
@@ -2239,7 +2315,7 @@ func checkDenyList(t *testing.T, ts *topo.Server, keyspaceShard string, want []s
 		got = tc.DeniedTables
 	}
 	if !reflect.DeepEqual(got, want) {
-		assert("Denied tables for %v: %v, want %v", keyspaceShard, got, want)
+		t.Errorf("Denied tables for %v: %v, want %v", keyspaceShard, got, want)
 	}
 }
 
@@ -2291,7 +2367,7 @@ func checkIfPrimaryServing(t *testing.T, ts *topo.Server, keyspaceShard string,
 		t.Fatal(err)
 	}
 	if want != si.IsPrimaryServing {
-		assert("IsPrimaryServing(%v): %v, want %v", keyspaceShard, si.IsPrimaryServing, want)
+		t.Errorf("IsPrimaryServing(%v): %v, want %v", keyspaceShard, si.IsPrimaryServing, want)
 	}
 }
 
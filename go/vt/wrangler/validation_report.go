@@ -0,0 +1,226 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/sets"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// The severities of validation findings.
+const (
+	ValidationError   = "ERROR"
+	ValidationWarning = "WARNING"
+)
+
+// The checks validation findings are counted under.
+const (
+	ValidationCheckTopology         = grpcvtctldserver.ValidationCheckTopology
+	ValidationCheckTabletRecords    = grpcvtctldserver.ValidationCheckTabletRecords
+	ValidationCheckPrimary          = grpcvtctldserver.ValidationCheckPrimary
+	ValidationCheckReplicationGraph = grpcvtctldserver.ValidationCheckReplicationGraph
+	ValidationCheckReplication      = grpcvtctldserver.ValidationCheckReplication
+	ValidationCheckPing             = grpcvtctldserver.ValidationCheckPing
+	ValidationCheckServingState     = grpcvtctldserver.ValidationCheckServingState
+	ValidationCheckServingGraph     = grpcvtctldserver.ValidationCheckServingGraph
+)
+
+// ValidationFinding is a problem found by a validation.
+type ValidationFinding struct {
+	Severity string `json:"severity"`
+	Check    string `json:"check"`
+	Keyspace string `json:"keyspace,omitempty"`
+	Shard    string `json:"shard,omitempty"`
+	Message  string `json:"message"`
+}
+
+// ValidationCheckCounts counts the objects a check passed and failed on.
+type ValidationCheckCounts struct {
+	OK     int `json:"ok"`
+	Failed int `json:"failed"`
+}
+
+// ValidationSummary sums up a ValidationReport.
+type ValidationSummary struct {
+	Keyspaces int                               `json:"keyspaces"`
+	Shards    int                               `json:"shards"`
+	Tablets   int                               `json:"tablets"`
+	Cells     int                               `json:"cells"`
+	Errors    int                               `json:"errors"`
+	Warnings  int                               `json:"warnings"`
+	Checks    map[string]*ValidationCheckCounts `json:"checks"`
+	Elapsed   string                            `json:"elapsed"`
+}
+
+// ValidationReport accumulates the findings of the Validate family of
+// methods, and the objects, such as shards and tablets, each check ran on.
+// It is safe for concurrent use, and Finish must be called before reading
+// its fields.
+type ValidationReport struct {
+	Summary  ValidationSummary   `json:"summary"`
+	Findings []ValidationFinding `json:"findings"`
+
+	mu    sync.Mutex
+	start time.Time
+	cells sets.Set[string]
+	// checked has the objects each check ran on, and failed the objects
+	// each check found problems on.
+	checked map[string]sets.Set[string]
+	failed  map[string]sets.Set[string]
+}
+
+// NewValidationReport returns an empty report, which measures the time
+// elapsed from now to Finish.
+func NewValidationReport() *ValidationReport {
+	return &ValidationReport{
+		Findings: []ValidationFinding{},
+		start:    time.Now(),
+		cells:    sets.New[string](),
+		checked:  make(map[string]sets.Set[string]),
+		failed:   make(map[string]sets.Set[string]),
+	}
+}
+
+// errValidation is returned by the Validate family of methods when they
+// found problems.
+var errValidation = errors.New("some validation errors - see log")
+
+// err returns errValidation if the report has findings. The warnings of the
+// serving state check, such as a tablet that sent no health data, are the
+// only ones that don't fail a validation: a tablet that can't be pinged or
+// doesn't replicate does.
+func (r *ValidationReport) err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, finding := range r.Findings {
+		if finding.Severity == ValidationWarning && finding.Check == ValidationCheckServingState {
+			continue
+		}
+		return errValidation
+	}
+	return nil
+}
+
+// RecordKeyspace counts a validated keyspace.
+func (r *ValidationReport) RecordKeyspace(keyspace string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Summary.Keyspaces++
+}
+
+// RecordShard counts a validated shard, its tablets and their cells.
+func (r *ValidationReport) RecordShard(keyspace, shard string, tabletAliases []*topodatapb.TabletAlias) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Summary.Shards++
+	r.Summary.Tablets += len(tabletAliases)
+	for _, alias := range tabletAliases {
+		r.cells.Insert(alias.Cell)
+	}
+}
+
+// RecordChecked records that check ran on object.
+func (r *ValidationReport) RecordChecked(check, object string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.checked[check] == nil {
+		r.checked[check] = sets.New[string]()
+	}
+	r.checked[check].Insert(object)
+}
+
+// RecordFinding records a problem check found on object, in the shard of
+// the keyspace, either of which may be empty.
+func (r *ValidationReport) RecordFinding(check string, severity vtctldatapb.ValidationFinding_Severity, keyspace, shard, object, message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	finding := ValidationFinding{
+		Severity: ValidationError,
+		Check:    check,
+		Keyspace: keyspace,
+		Shard:    shard,
+		Message:  message,
+	}
+	if severity == vtctldatapb.ValidationFinding_WARNING {
+		finding.Severity = ValidationWarning
+	}
+	r.Findings = append(r.Findings, finding)
+
+	if r.failed[check] == nil {
+		r.failed[check] = sets.New[string]()
+	}
+	r.failed[check].Insert(object)
+}
+
+// Finish computes the summary of the report.
+func (r *ValidationReport) Finish() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Summary.Cells = r.cells.Len()
+	r.Summary.Errors, r.Summary.Warnings = 0, 0
+	for _, finding := range r.Findings {
+		if finding.Severity == ValidationWarning {
+			r.Summary.Warnings++
+		} else {
+			r.Summary.Errors++
+		}
+	}
+	r.Summary.Checks = make(map[string]*ValidationCheckCounts)
+	for check, objects := range r.checked {
+		r.Summary.Checks[check] = &ValidationCheckCounts{OK: objects.Difference(r.failed[check]).Len()}
+	}
+	for check, objects := range r.failed {
+		counts, ok := r.Summary.Checks[check]
+		if !ok {
+			counts = &ValidationCheckCounts{}
+			r.Summary.Checks[check] = counts
+		}
+		counts.Failed = objects.Len()
+	}
+	r.Summary.Elapsed = time.Since(r.start).Round(time.Millisecond).String()
+}
+
+// checkNames returns the names of the checks in the summary, sorted.
+func (s *ValidationSummary) checkNames() []string {
+	names := make([]string, 0, len(s.Checks))
+	for name := range s.Checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validationRecorder records the validations of the vtctld server in a
+// report, and logs their progress to the logger of the wrangler.
+type validationRecorder struct {
+	*ValidationReport
+	logger logutil.Logger
+}
+
+// Logger is part of the grpcvtctldserver.ValidationRecorder interface.
+func (r validationRecorder) Logger() logutil.Logger {
+	return r.logger
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+	"vitess.io/vitess/go/vt/vttablet/queryservice/fakes"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+	"vitess.io/vitess/go/vt/vttablet/tabletconntest"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestValidationReport(t *testing.T) {
+	report := NewValidationReport()
+	require.NoError(t, report.err())
+
+	alias := func(cell string, uid uint32) *topodatapb.TabletAlias {
+		return &topodatapb.TabletAlias{Cell: cell, Uid: uid}
+	}
+	report.RecordKeyspace("ks")
+	report.RecordShard("ks", "-80", []*topodatapb.TabletAlias{alias("zone1", 100), alias("zone1", 101), alias("zone2", 102)})
+	report.RecordShard("ks", "80-", []*topodatapb.TabletAlias{alias("zone1", 200), alias("zone1", 201)})
+	for _, shard := range []string{"ks/-80", "ks/80-"} {
+		report.RecordChecked(ValidationCheckPrimary, shard)
+		report.RecordChecked(ValidationCheckReplicationGraph, shard)
+		report.RecordChecked(ValidationCheckReplication, shard)
+	}
+	for _, tablet := range []string{"zone1-0000000100", "zone1-0000000101", "zone2-0000000102", "zone1-0000000200", "zone1-0000000201"} {
+		report.RecordChecked(ValidationCheckPing, tablet)
+		// A check may run more than once on the same object.
+		report.RecordChecked(ValidationCheckPing, tablet)
+	}
+	// A tablet that can't be pinged fails the validation, even though it is
+	// only a warning.
+	report.RecordFinding(ValidationCheckPing, vtctldatapb.ValidationFinding_WARNING, "ks", "-80", "zone1-0000000101",
+		"Ping(zone1-0000000101) failed: timeout tablet hostname: host1")
+	require.ErrorContains(t, report.err(), "some validation errors")
+	report.RecordFinding(ValidationCheckPrimary, vtctldatapb.ValidationFinding_ERROR, "ks", "-80", "ks/-80",
+		"primary mismatch for shard ks/-80: found zone1-0000000100, expected zone1-0000000101")
+	report.RecordFinding(ValidationCheckPrimary, vtctldatapb.ValidationFinding_ERROR, "ks", "-80", "ks/-80",
+		"shard ks/-80 already has primary zone1-0000000100 but found other primary zone1-0000000101")
+	report.RecordFinding(ValidationCheckReplicationGraph, vtctldatapb.ValidationFinding_ERROR, "ks", "80-", "ks/80-",
+		"tablet zone1-0000000201 of shard ks/80- is in no replication graph, run 'ShardReplicationFix zone1 ks/80-' to fix it")
+	report.RecordFinding(ValidationCheckTopology, vtctldatapb.ValidationFinding_ERROR, "ks", "", "GetCellInfoNames failed",
+		"GetCellInfoNames failed")
+	require.ErrorContains(t, report.err(), "some validation errors")
+
+	report.Finish()
+	summary := report.Summary
+	require.Equal(t, 1, summary.Keyspaces)
+	require.Equal(t, 2, summary.Shards)
+	require.Equal(t, 5, summary.Tablets)
+	require.Equal(t, 2, summary.Cells)
+	require.Equal(t, 4, summary.Errors)
+	require.Equal(t, 1, summary.Warnings)
+	require.Equal(t, []string{"ping", "primary", "replication", "replication graph", "topology"}, summary.checkNames())
+	require.Equal(t, ValidationCheckCounts{OK: 4, Failed: 1}, *summary.Checks[ValidationCheckPing])
+	// Both primary findings are on the same shard, so it fails once.
+	require.Equal(t, ValidationCheckCounts{OK: 1, Failed: 1}, *summary.Checks[ValidationCheckPrimary])
+	require.Equal(t, ValidationCheckCounts{OK: 1, Failed: 1}, *summary.Checks[ValidationCheckReplicationGraph])
+	require.Equal(t, ValidationCheckCounts{OK: 2}, *summary.Checks[ValidationCheckReplication])
+	require.Equal(t, ValidationCheckCounts{Failed: 1}, *summary.Checks[ValidationCheckTopology])
+	require.Equal(t, ValidationFinding{
+		Severity: ValidationWarning,
+		Check:    ValidationCheckPing,
+		Keyspace: "ks",
+		Shard:    "-80",
+		Message:  "Ping(zone1-0000000101) failed: timeout tablet hostname: host1",
+	}, report.Findings[0])
+
+	b, err := json.Marshal(report)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(b), `{"summary":{"keyspaces":1,`), "%s", b)
+
+	// A tablet that sent no health data is the only warning that doesn't
+	// fail a validation.
+	report = NewValidationReport()
+	report.RecordFinding(ValidationCheckServingState, vtctldatapb.ValidationFinding_WARNING, "ks", "-80", "zone1-0000000101",
+		"tablet zone1-0000000101 sent no health data within 5s: timeout")
+	require.NoError(t, report.err())
+	report.RecordFinding(ValidationCheckReplication, vtctldatapb.ValidationFinding_WARNING, "ks", "-80", "ks/-80",
+		"replica zone1-0000000101 not replicating: 10.0.0.1 replica list: []")
+	require.ErrorContains(t, report.err(), "some validation errors")
+}
+
+// healthQueryService answers StreamHealth with a single health record.
+type healthQueryService struct {
+	queryservice.QueryService
+	health *querypb.StreamHealthResponse
+}
+
+func (qs *healthQueryService) StreamHealth(ctx context.Context, callback func(*querypb.StreamHealthResponse) error) error {
+	return callback(qs.health)
+}
+
+func (qs *healthQueryService) Close(ctx context.Context) error {
+	return nil
+}
+
+func TestValidateShardServingState(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	wr := New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, nil)
+
+	health := map[uint32]*querypb.StreamHealthResponse{}
+	dialerName := fmt.Sprintf("ValidateShardServingStateTest-%d", rand.IntN(1000000000))
+	tabletconn.RegisterDialer(dialerName, func(ctx context.Context, tablet *topodatapb.Tablet, failFast grpcclient.FailFast) (queryservice.QueryService, error) {
+		shr, ok := health[tablet.Alias.Uid]
+		if !ok {
+			return nil, errors.New("connection refused")
+		}
+		return &healthQueryService{QueryService: fakes.ErrorQueryService, health: shr}, nil
+	})
+	tabletconntest.SetProtocol("go.vt.wrangler.validation_report_test", dialerName)
+
+	addTablet := func(uid uint32, tabletType, healthType topodatapb.TabletType, serving bool, healthError string) {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    "0",
+			Type:     tabletType,
+		}
+		err := ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+		if healthType != topodatapb.TabletType_UNKNOWN {
+			health[uid] = &querypb.StreamHealthResponse{
+				Target:        &querypb.Target{Keyspace: "ks", Shard: "0", TabletType: healthType},
+				Serving:       serving,
+				RealtimeStats: &querypb.RealtimeStats{HealthError: healthError},
+			}
+		}
+	}
+	addTablet(100, topodatapb.TabletType_PRIMARY, topodatapb.TabletType_PRIMARY, true, "")
+	addTablet(101, topodatapb.TabletType_REPLICA, topodatapb.TabletType_REPLICA, true, "")
+	addTablet(102, topodatapb.TabletType_SPARE, topodatapb.TabletType_SPARE, false, "")
+	_, err := ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}
+		return nil
+	})
+	require.NoError(t, err)
+
+	report := NewValidationReport()
+	require.NoError(t, wr.ValidateShard(ctx, "ks", "0", false /*pingTablets*/, 0, time.Second, report))
+
+	// A tablet that doesn't answer is only a warning.
+	addTablet(105, topodatapb.TabletType_RDONLY, topodatapb.TabletType_UNKNOWN, false, "")
+
+	report = NewValidationReport()
+	require.NoError(t, wr.ValidateShard(ctx, "ks", "0", false /*pingTablets*/, 0, time.Second, report))
+	report.Finish()
+	require.Len(t, report.Findings, 1)
+	require.Equal(t, ValidationWarning, report.Findings[0].Severity)
+
+	// A replica that took over as primary behind the topo's back, and a
+	// lagging replica.
+	addTablet(103, topodatapb.TabletType_REPLICA, topodatapb.TabletType_PRIMARY, true, "")
+	addTablet(104, topodatapb.TabletType_REPLICA, topodatapb.TabletType_REPLICA, false, "replication lag")
+
+	report = NewValidationReport()
+	require.ErrorContains(t, wr.ValidateShard(ctx, "ks", "0", false /*pingTablets*/, 0, time.Second, report), "some validation errors")
+	report.Finish()
+	require.Len(t, report.Findings, 3)
+	require.Equal(t, ValidationFinding{
+		Severity: ValidationError,
+		Check:    ValidationCheckServingState,
+		Keyspace: "ks",
+		Shard:    "0",
+		Message:  "tablet cell1-0000000103 broadcasts tablet type PRIMARY, but its topo record has type REPLICA",
+	}, report.Findings[0])
+	require.Equal(t, "tablet cell1-0000000104 of type REPLICA broadcasts serving state false, but its topo records imply true: replication lag", report.Findings[1].Message)
+	require.Equal(t, ValidationError, report.Findings[1].Severity)
+	require.Contains(t, report.Findings[2].Message, "tablet cell1-0000000105 sent no health data within 1s")
+	require.Equal(t, ValidationWarning, report.Findings[2].Severity)
+	require.Equal(t, 1, report.Summary.Warnings)
+	require.Equal(t, ValidationCheckCounts{OK: 3, Failed: 3}, *report.Summary.Checks[ValidationCheckServingState])
+}
@@ -19,77 +19,151 @@ package wrangler
 import (
 	"context"
 	"errors"
+	"time"
 
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/topotools"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
 
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
-// consumeValidationResults consumes results from Validate(Keyspace|Shard)? methods.
-// If there are any results (synonymous with "validation failure") then the
-// overall method returns a generic error instructing the user to look in the
-// vtctld logs, and each validation failure is logged at the error level.
-func consumeValidationResults(logger logutil.Logger, results []string) (err error) {
-	for _, result := range results {
-		if err == nil {
-			err = errors.New("some validation errors - see log")
+// logValidationFindings logs the findings of a validation, errors at the
+// error level and warnings at the warning level. If there are any findings
+// then it returns a generic error instructing the user to look in the
+// vtctld logs.
+func logValidationFindings(logger logutil.Logger, findings []ValidationFinding) error {
+	for _, finding := range findings {
+		if finding.Severity == ValidationWarning {
+			logger.Warningf("%v", finding.Message)
+			continue
 		}
 
-		logger.Error(errors.New(result))
+		logger.Error(errors.New(finding.Message))
 	}
 
-	return err
+	if len(findings) > 0 {
+		return errValidation
+	}
+	return nil
 }
 
-// Validate a whole TopologyServer tree
-func (wr *Wrangler) Validate(ctx context.Context, pingTablets bool) error {
-	resp, err := wr.VtctldServer().Validate(ctx, &vtctldatapb.ValidateRequest{
-		PingTablets: pingTablets,
-	})
-	if err != nil {
-		return err
-	}
+// logValidationFindings logs the findings of a validation through the
+// fan-out logger, as there is often one per tablet.
+func (wr *Wrangler) logValidationFindings(findings []ValidationFinding) error {
+	logger, flush := wr.fanOutLogger()
+	defer flush()
+	return logValidationFindings(logger, findings)
+}
 
-	aggrResults := resp.Results
-	for _, keyspaceResults := range resp.ResultsByKeyspace {
-		aggrResults = append(aggrResults, keyspaceResults.Results...)
-		for _, shardResults := range keyspaceResults.ResultsByShard {
-			aggrResults = append(aggrResults, shardResults.Results...)
-		}
+// LogValidationReport finishes the report, then logs its findings followed
+// by its summary.
+func (wr *Wrangler) LogValidationReport(report *ValidationReport) {
+	report.Finish()
+	_ = wr.logValidationFindings(report.Findings)
+
+	summary := &report.Summary
+	wr.Logger().Printf("Validation summary: checked %d keyspaces, %d shards, %d tablets and %d cells in %v\n",
+		summary.Keyspaces, summary.Shards, summary.Tablets, summary.Cells, summary.Elapsed)
+	wr.Logger().Printf("  findings: %d errors, %d warnings\n", summary.Errors, summary.Warnings)
+	for _, name := range summary.checkNames() {
+		counts := summary.Checks[name]
+		wr.Logger().Printf("  %v checks: %d ok, %d failed\n", name, counts.OK, counts.Failed)
 	}
+}
 
-	return consumeValidationResults(wr.Logger(), aggrResults)
+// validationRecorder returns the recorder the validations of the vtctld
+// server record their findings in the report through.
+func (wr *Wrangler) validationRecorder(report *ValidationReport) validationRecorder {
+	return validationRecorder{ValidationReport: report, logger: wr.Logger()}
 }
 
-// ValidateKeyspace will validate a bunch of information in a keyspace
-// is correct.
-func (wr *Wrangler) ValidateKeyspace(ctx context.Context, keyspace string, pingTablets bool) error {
-	resp, err := wr.VtctldServer().ValidateKeyspace(ctx, &vtctldatapb.ValidateKeyspaceRequest{
-		Keyspace:    keyspace,
+// validationServer returns the vtctld server, whose validations record what
+// they check and find.
+func (wr *Wrangler) validationServer() *grpcvtctldserver.VtctldServer {
+	return wr.vtctld.(*grpcvtctldserver.VtctldServer)
+}
+
+// Validate a whole TopologyServer tree, recording the findings in the
+// report.
+//
+// It is a long-running operation that changes nothing, so canceling it with
+// CancelOperation interrupts it right away.
+func (wr *Wrangler) Validate(ctx context.Context, pingTablets bool, report *ValidationReport) error {
+	ctx, op := wr.startOperation(ctx, "Validate", "all keyspaces", true /*interruptible*/)
+	defer op.finish()
+
+	_, err := wr.validationServer().ValidateWithRecorder(wr.vtctldContext(ctx), &vtctldatapb.ValidateRequest{
 		PingTablets: pingTablets,
-	})
+	}, wr.validationRecorder(report))
+	if op.canceled() {
+		return op.errCanceled()
+	}
 	if err != nil {
 		return err
 	}
+	return report.err()
+}
 
-	aggrResults := resp.Results
-	for _, shardResults := range resp.ResultsByShard {
-		aggrResults = append(aggrResults, shardResults.Results...)
+// ValidateKeyspace will validate a bunch of information in a keyspace
+// is correct, recording the findings in the report. If checkSrvKeyspace is
+// set, the SrvKeyspace of each cell is also compared with the one
+// RebuildKeyspaceGraph would compute from the shard records.
+func (wr *Wrangler) ValidateKeyspace(ctx context.Context, keyspace string, pingTablets, checkSrvKeyspace bool, report *ValidationReport) error {
+	_, err := wr.validationServer().ValidateKeyspaceWithRecorder(wr.vtctldContext(ctx), &vtctldatapb.ValidateKeyspaceRequest{
+		Keyspace:         keyspace,
+		PingTablets:      pingTablets,
+		CheckSrvKeyspace: checkSrvKeyspace,
+	}, wr.validationRecorder(report))
+	if err != nil {
+		return err
 	}
+	return report.err()
+}
 
-	return consumeValidationResults(wr.Logger(), aggrResults)
+// ValidateShard will validate a bunch of information in a shard is correct,
+// recording the findings in the report. Each tablet ping times out after
+// pingTimeout, or the tablet timeout if it is zero.
+//
+// If servingStateWait is not zero, the tablet type and serving flag each
+// tablet broadcasts in its health stream are also compared with its topo
+// record, which catches tablets whose type was changed behind the topo's
+// back, for instance by an external failover. Tablets that send no health
+// record within servingStateWait are reported as warnings.
+func (wr *Wrangler) ValidateShard(ctx context.Context, keyspace, shard string, pingTablets bool, pingTimeout, servingStateWait time.Duration, report *ValidationReport) error {
+	req := &vtctldatapb.ValidateShardRequest{
+		Keyspace:            keyspace,
+		Shard:               shard,
+		PingTablets:         pingTablets,
+		IncludeServingState: servingStateWait > 0,
+	}
+	if pingTimeout > 0 {
+		req.PingTimeout = protoutil.DurationToProto(pingTimeout)
+	}
+	if servingStateWait > 0 {
+		req.ServingStateWait = protoutil.DurationToProto(servingStateWait)
+	}
+	if _, err := wr.validationServer().ValidateShardWithRecorder(wr.vtctldContext(ctx), req, wr.validationRecorder(report)); err != nil {
+		return err
+	}
+	return report.err()
 }
 
-// ValidateShard will validate a bunch of information in a shard is correct.
-func (wr *Wrangler) ValidateShard(ctx context.Context, keyspace, shard string, pingTablets bool) error {
-	resp, err := wr.VtctldServer().ValidateShard(ctx, &vtctldatapb.ValidateShardRequest{
-		Keyspace:    keyspace,
-		Shard:       shard,
-		PingTablets: pingTablets,
-	})
+// ValidateShardReplication checks that the replication graphs of a shard in
+// the given cells, or in all cells, list each of its tablets once, in the
+// cell of the tablet. The findings are recorded in the report.
+func (wr *Wrangler) ValidateShardReplication(ctx context.Context, keyspace, shard string, cells []string, report *ValidationReport) error {
+	results, err := topotools.ValidateShardReplicationCells(ctx, wr.ts, keyspace, shard, cells)
 	if err != nil {
 		return err
 	}
 
-	return consumeValidationResults(wr.Logger(), resp.Results)
+	object := topoproto.KeyspaceShardString(keyspace, shard)
+	report.RecordChecked(ValidationCheckReplicationGraph, object)
+	for _, result := range results {
+		report.RecordFinding(ValidationCheckReplicationGraph, vtctldatapb.ValidationFinding_ERROR, keyspace, shard, object, result)
+	}
+	return report.err()
 }
@@ -0,0 +1,231 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// volatileVariables are global variables that legitimately differ between
+// any two mysqld instances, so DiffShardVariables skips them by default.
+var volatileVariables = map[string]bool{
+	"datadir":             true,
+	"general_log_file":    true,
+	"gtid_executed":       true,
+	"gtid_owned":          true,
+	"gtid_purged":         true,
+	"hostname":            true,
+	"log_bin_basename":    true,
+	"log_bin_index":       true,
+	"log_error":           true,
+	"pid_file":            true,
+	"port":                true,
+	"relay_log":           true,
+	"relay_log_basename":  true,
+	"relay_log_index":     true,
+	"relay_log_info_file": true,
+	"report_host":         true,
+	"report_port":         true,
+	"server_id":           true,
+	"server_uuid":         true,
+	"slow_query_log_file": true,
+	"socket":              true,
+	"timestamp":           true,
+	"tmpdir":              true,
+}
+
+var variableNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// missingVariable is reported as the value of a variable that a tablet
+// doesn't have at all.
+const missingVariable = "<missing>"
+
+// VariableDiff is a global variable whose value differs from the reference.
+type VariableDiff struct {
+	Name           string
+	ReferenceValue string
+	Value          string
+}
+
+// TabletVariablesDiff lists the variables of one tablet that differ from
+// the reference tablet.
+type TabletVariablesDiff struct {
+	TabletAlias string
+	Diffs       []VariableDiff `json:",omitempty"`
+	Error       string         `json:",omitempty"`
+}
+
+// ShardVariablesDiff is the result of DiffShardVariables.
+type ShardVariablesDiff struct {
+	Keyspace  string
+	Shard     string
+	Reference string
+	Tablets   []*TabletVariablesDiff
+}
+
+// HasDiffs returns true if any tablet differs from the reference or
+// couldn't be compared.
+func (d *ShardVariablesDiff) HasDiffs() bool {
+	for _, tablet := range d.Tablets {
+		if len(tablet.Diffs) > 0 || tablet.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffShardVariables compares the global variables of every tablet in the
+// shard with those of the shard primary. If variables is empty, all of
+// them are compared, except the volatile ones unless includeAll is set.
+func (wr *Wrangler) DiffShardVariables(ctx context.Context, keyspace, shard string, variables []string, includeAll bool) (*ShardVariablesDiff, error) {
+	query := "SHOW GLOBAL VARIABLES"
+	if len(variables) > 0 {
+		quoted := make([]string, 0, len(variables))
+		for _, name := range variables {
+			if !variableNameRegexp.MatchString(name) {
+				return nil, fmt.Errorf("invalid variable name %q", name)
+			}
+			quoted = append(quoted, "'"+strings.ToLower(name)+"'")
+		}
+		query += " WHERE Variable_name IN (" + strings.Join(quoted, ", ") + ")"
+	}
+
+	si, err := wr.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	if !si.HasPrimary() {
+		return nil, fmt.Errorf("no primary in shard %v/%v", keyspace, shard)
+	}
+	aliases, err := wr.ts.FindAllTabletAliasesInShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	// The tablets that can't be read are not compared.
+	tabletMap, err := wr.getTabletMap(ctx, aliases, warnOnUnreadableTablets)
+	if err != nil {
+		return nil, err
+	}
+	primary, ok := tabletMap[topoproto.TabletAliasString(si.PrimaryAlias)]
+	if !ok {
+		return nil, fmt.Errorf("primary %v of shard %v/%v is not in the shard's replication graph", topoproto.TabletAliasString(si.PrimaryAlias), keyspace, shard)
+	}
+
+	reference, err := wr.getGlobalVariables(ctx, primary.Tablet, query, includeAll || len(variables) > 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ShardVariablesDiff{
+		Keyspace:  keyspace,
+		Shard:     shard,
+		Reference: primary.AliasString(),
+	}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for alias, ti := range tabletMap {
+		if alias == primary.AliasString() {
+			continue
+		}
+		wg.Add(1)
+		go func(tablet *topodatapb.Tablet) {
+			defer wg.Done()
+			ctx, cancel := wr.tabletContext(ctx)
+			defer cancel()
+			tabletDiff := &TabletVariablesDiff{TabletAlias: topoproto.TabletAliasString(tablet.Alias)}
+			values, err := wr.getGlobalVariables(ctx, tablet, query, includeAll || len(variables) > 0)
+			if err != nil {
+				tabletDiff.Error = err.Error()
+			} else {
+				tabletDiff.Diffs = diffVariables(reference, values)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			result.Tablets = append(result.Tablets, tabletDiff)
+		}(ti.Tablet)
+	}
+	wg.Wait()
+
+	sort.Slice(result.Tablets, func(i, j int) bool {
+		return result.Tablets[i].TabletAlias < result.Tablets[j].TabletAlias
+	})
+	return result, nil
+}
+
+// getGlobalVariables runs query, a SHOW GLOBAL VARIABLES statement, on the
+// tablet and returns the variables by lower-cased name.
+func (wr *Wrangler) getGlobalVariables(ctx context.Context, tablet *topodatapb.Tablet, query string, includeVolatile bool) (map[string]string, error) {
+	qr, err := callTablet(ctx, "ExecuteFetchAsDba", notIdempotent, func(ctx context.Context) (*querypb.QueryResult, error) {
+		return wr.tmc.ExecuteFetchAsDba(ctx, tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:   []byte(query),
+			MaxRows: 10000,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read global variables from %v: %v", topoproto.TabletAliasString(tablet.Alias), err)
+	}
+	values := make(map[string]string)
+	for _, row := range sqltypes.Proto3ToResult(qr).Rows {
+		if len(row) != 2 {
+			return nil, fmt.Errorf("unexpected row reading global variables from %v: %v", topoproto.TabletAliasString(tablet.Alias), row)
+		}
+		name := strings.ToLower(row[0].ToString())
+		if !includeVolatile && volatileVariables[name] {
+			continue
+		}
+		values[name] = row[1].ToString()
+	}
+	return values, nil
+}
+
+// diffVariables returns the variables that differ between reference and
+// values, sorted by name.
+func diffVariables(reference, values map[string]string) []VariableDiff {
+	var diffs []VariableDiff
+	for name, referenceValue := range reference {
+		value, ok := values[name]
+		if !ok {
+			value = missingVariable
+		}
+		if value != referenceValue {
+			diffs = append(diffs, VariableDiff{Name: name, ReferenceValue: referenceValue, Value: value})
+		}
+	}
+	for name, value := range values {
+		if _, ok := reference[name]; !ok {
+			diffs = append(diffs, VariableDiff{Name: name, ReferenceValue: missingVariable, Value: value})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs
+}
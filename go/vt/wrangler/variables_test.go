@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// variablesTMClient serves SHOW GLOBAL VARIABLES from a per-tablet map.
+type variablesTMClient struct {
+	tmclient.TabletManagerClient
+
+	mu        sync.Mutex
+	variables map[uint32]map[string]string
+	queries   []string
+}
+
+func (tmc *variablesTMClient) ExecuteFetchAsDba(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsDbaRequest) (*querypb.QueryResult, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	tmc.queries = append(tmc.queries, string(req.Query))
+	variables, ok := tmc.variables[tablet.Alias.Uid]
+	if !ok {
+		return nil, fmt.Errorf("tablet %v is unreachable", tablet.Alias.Uid)
+	}
+	var rows []string
+	for name, value := range variables {
+		rows = append(rows, name+"|"+value)
+	}
+	return sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("Variable_name|Value", "varchar|varchar"), rows...)), nil
+}
+
+func TestDiffShardVariables(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	tmc := &variablesTMClient{
+		variables: map[uint32]map[string]string{
+			100: {"server_uuid": "a", "sql_mode": "STRICT_TRANS_TABLES", "max_connections": "100"},
+			101: {"server_uuid": "b", "sql_mode": "STRICT_TRANS_TABLES", "max_connections": "100"},
+			102: {"server_uuid": "c", "sql_mode": "", "binlog_format": "ROW"},
+		},
+	}
+	wr := New(vtenv.NewTestEnv(), logutil.NewConsoleLogger(), ts, tmc)
+
+	for _, uid := range []uint32{100, 101, 102, 103} {
+		tabletType := topodatapb.TabletType_REPLICA
+		if uid == 100 {
+			tabletType = topodatapb.TabletType_PRIMARY
+		}
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    "0",
+			Type:     tabletType,
+		}
+		err := ts.InitTablet(ctx, tablet, true /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/)
+		require.NoError(t, err)
+	}
+	_, err := ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "cell1", Uid: 100}
+		return nil
+	})
+	require.NoError(t, err)
+
+	diff, err := wr.DiffShardVariables(ctx, "ks", "0", nil, false)
+	require.NoError(t, err)
+	require.True(t, diff.HasDiffs())
+	require.Equal(t, "cell1-0000000100", diff.Reference)
+	require.Len(t, diff.Tablets, 3)
+	require.Equal(t, &TabletVariablesDiff{TabletAlias: "cell1-0000000101"}, diff.Tablets[0])
+	require.Equal(t, &TabletVariablesDiff{
+		TabletAlias: "cell1-0000000102",
+		Diffs: []VariableDiff{
+			{Name: "binlog_format", ReferenceValue: missingVariable, Value: "ROW"},
+			{Name: "max_connections", ReferenceValue: "100", Value: missingVariable},
+			{Name: "sql_mode", ReferenceValue: "STRICT_TRANS_TABLES", Value: ""},
+		},
+	}, diff.Tablets[1])
+	require.Equal(t, "cell1-0000000103", diff.Tablets[2].TabletAlias)
+	require.Contains(t, diff.Tablets[2].Error, "tablet 103 is unreachable")
+
+	t.Run("include all", func(t *testing.T) {
+		diff, err := wr.DiffShardVariables(ctx, "ks", "0", nil, true)
+		require.NoError(t, err)
+		require.Equal(t, []VariableDiff{
+			{Name: "server_uuid", ReferenceValue: "a", Value: "b"},
+		}, diff.Tablets[0].Diffs)
+	})
+
+	t.Run("targeted variables", func(t *testing.T) {
+		tmc.queries = nil
+		_, err := wr.DiffShardVariables(ctx, "ks", "0", []string{"sql_mode", "Server_UUID"}, false)
+		require.NoError(t, err)
+		for _, query := range tmc.queries {
+			require.Equal(t, "SHOW GLOBAL VARIABLES WHERE Variable_name IN ('sql_mode', 'server_uuid')", query)
+		}
+
+		_, err = wr.DiffShardVariables(ctx, "ks", "0", []string{"sql_mode'; drop table t; --"}, false)
+		require.ErrorContains(t, err, "invalid variable name")
+	})
+
+	t.Run("no primary", func(t *testing.T) {
+		require.NoError(t, ts.CreateShard(ctx, "ks", "80-"))
+		_, err := wr.DiffShardVariables(ctx, "ks", "80-", nil, false)
+		require.ErrorContains(t, err, "no primary in shard ks/80-")
+	})
+}
@@ -78,6 +78,18 @@ type DiffReport struct {
 	ExtraRowsTargetDiffs []*RowDiff
 	MismatchedRowsSample []*DiffMismatch
 	TableName            string
+	// SamplePercent is the percentage of the rows that were compared, if
+	// the diff was sampled.
+	SamplePercent int `json:",omitempty"`
+	// CollationMatchingRows is the number of MatchingRows whose text columns
+	// are only equal under their collation, e.g. differing in case under a
+	// case-insensitive collation. It's only set by collation-aware diffs.
+	CollationMatchingRows int
+}
+
+// HasDiffs returns true if the source and target of the table differ.
+func (dr *DiffReport) HasDiffs() bool {
+	return dr.MismatchedRows > 0 || dr.ExtraRowsSource > 0 || dr.ExtraRowsTarget > 0
 }
 
 // DiffMismatch is a sample of row diffs between source and target.
@@ -113,6 +125,10 @@ type vdiff struct {
 	tables         []string
 	sourceTimeZone string
 	targetTimeZone string
+	collationAware bool
+	// samplePercent is the percentage of the rows of each table to compare,
+	// or 0 to compare all of them.
+	samplePercent int
 }
 
 // compareColInfo contains the metadata for a column of the table being diffed
@@ -139,9 +155,17 @@ type tableDiffer struct {
 	comparePKs []compareColInfo
 	// pkCols has the indices of PK cols in the select list
 	pkCols []int
+	// collationCompareCols is compareCols with the text columns compared
+	// using their collation, for collation-aware diffs. Binary and blob
+	// columns are still compared as bytes. It's nil if the diff is not
+	// collation-aware.
+	collationCompareCols []compareColInfo
 
 	// selectPks is the list of pk columns as they appear in the select clause for the diff.
 	selectPks []int
+	// samplePercent is the percentage of the rows compared, or 0 if all of
+	// them are, see sampleFilter.
+	samplePercent int
 
 	// source Primitive and targetPrimitive are used for streaming
 	sourcePrimitive engine.Primitive
@@ -170,9 +194,23 @@ type shardStreamer struct {
 var _ engine.StreamExecutor = (*shardStreamer)(nil)
 
 // VDiff reports differences between the sources and targets of a vreplication workflow.
+// If collationAware is set, rows whose text columns differ are compared again
+// using the collations of the columns, and the rows that then match are
+// counted as matching, and as CollationMatchingRows.
 func (wr *Wrangler) VDiff(ctx context.Context, targetKeyspace, workflowName, sourceCell, targetCell, tabletTypesStr string,
 	filteredReplicationWaitTime time.Duration, format string, maxRows int64, tables string, debug, onlyPks bool,
-	maxExtraRowsToCompare int) (map[string]*DiffReport, error) {
+	maxExtraRowsToCompare int, collationAware bool) (map[string]*DiffReport, error) {
+	return wr.runVDiff(ctx, targetKeyspace, workflowName, sourceCell, targetCell, tabletTypesStr, filteredReplicationWaitTime,
+		format, maxRows, tables, debug, onlyPks, maxExtraRowsToCompare, collationAware, 0)
+}
+
+// runVDiff is the implementation of VDiff, which compares only samplePercent
+// of the rows of each table if it is not 0. The rows are contiguous ranges
+// of the primary key, so that the same rows are compared by every run, see
+// sampleFilter.
+func (wr *Wrangler) runVDiff(ctx context.Context, targetKeyspace, workflowName, sourceCell, targetCell, tabletTypesStr string,
+	filteredReplicationWaitTime time.Duration, format string, maxRows int64, tables string, debug, onlyPks bool,
+	maxExtraRowsToCompare int, collationAware bool, samplePercent int) (map[string]*DiffReport, error) {
 	log.Infof("Starting VDiff for %s.%s, sourceCell %s, targetCell %s, tabletTypes %s, timeout %s",
 		targetKeyspace, workflowName, sourceCell, targetCell, tabletTypesStr, filteredReplicationWaitTime.String())
 	// Assign defaults to sourceCell and targetCell if not specified.
@@ -228,6 +266,8 @@ func (wr *Wrangler) VDiff(ctx context.Context, targetKeyspace, workflowName, sou
 		tables:         includeTables,
 		sourceTimeZone: ts.sourceTimeZone,
 		targetTimeZone: ts.targetTimeZone,
+		collationAware: collationAware,
+		samplePercent:  samplePercent,
 	}
 	for shard, source := range ts.Sources() {
 		df.sources[shard] = &shardStreamer{
@@ -259,10 +299,12 @@ func (wr *Wrangler) VDiff(ctx context.Context, targetKeyspace, workflowName, sou
 		return nil, vterrors.Wrap(err, "selectTablets")
 	}
 	defer func() {
-		// We use a new context as we want to reset the state even
-		// when the parent context has timed out or been canceled.
+		// We use a context that is not canceled with the parent one, but
+		// keeps its values such as the correlation ID, as we want to reset
+		// the state even when the parent context has timed out or been
+		// canceled.
 		log.Infof("Restarting the %q VReplication workflow on target tablets in keyspace %q", df.workflow, df.targetKeyspace)
-		restartCtx, restartCancel := context.WithTimeout(context.Background(), DefaultActionTimeout)
+		restartCtx, restartCancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultActionTimeout)
 		defer restartCancel()
 		if err := df.restartTargets(restartCtx); err != nil {
 			wr.Logger().Errorf("Could not restart workflow %q on target tablets in keyspace %q: %v, please restart it manually",
@@ -315,6 +357,7 @@ func (wr *Wrangler) VDiff(ctx context.Context, targetKeyspace, workflowName, sou
 			return nil, vterrors.Wrap(err, "diff")
 		}
 		dr.TableName = table
+		dr.SamplePercent = td.samplePercent
 		// This could be a reference table with a different number of shards on
 		// the source and target, so let's check and adjust if needed. In that
 		// case we should have no mismatched rows and the number of extra rows
@@ -388,8 +431,14 @@ func (wr *Wrangler) VDiff(ctx context.Context, targetKeyspace, workflowName, sou
 	} else {
 		for table, dr := range diffReports {
 			wr.Logger().Printf("Summary for table %v:\n", table)
+			if dr.SamplePercent > 0 {
+				wr.Logger().Printf("\tSamplePercent: %v\n", dr.SamplePercent)
+			}
 			wr.Logger().Printf("\tProcessedRows: %v\n", dr.ProcessedRows)
 			wr.Logger().Printf("\tMatchingRows: %v\n", dr.MatchingRows)
+			if collationAware {
+				wr.Logger().Printf("\tCollationMatchingRows: %v\n", dr.CollationMatchingRows)
+			}
 			wr.Logger().Printf("\tMismatchedRows: %v\n", dr.MismatchedRows)
 			wr.Logger().Printf("\tExtraRowsSource: %v\n", dr.ExtraRowsSource)
 			wr.Logger().Printf("\tExtraRowsTarget: %v\n", dr.ExtraRowsTarget)
@@ -600,6 +649,30 @@ func getColumnCollations(venv *vtenv.Environment, table *tabletmanagerdatapb.Tab
 	return columnCollations, columnValues, nil
 }
 
+// setCollationCompareCols sets the columns of a collation-aware diff, which
+// compare the text columns using their collation. Binary strings and blobs
+// have no collation, or the binary one, and are compared as bytes.
+func setCollationCompareCols(env *vtenv.Environment, table *tabletmanagerdatapb.TableDefinition, targetSelect *sqlparser.Select, fields map[string]querypb.Type, td *tableDiffer) error {
+	columnCollations, _, err := getColumnCollations(env, table)
+	if err != nil {
+		return err
+	}
+	td.collationCompareCols = make([]compareColInfo, len(td.compareCols))
+	copy(td.collationCompareCols, td.compareCols)
+	for i := range td.collationCompareCols {
+		colname, err := getColumnNameForSelectExpr(targetSelect.GetColumns()[i])
+		if err != nil {
+			return err
+		}
+		collation := columnCollations[colname]
+		if collation == collations.Unknown || collation == collations.CollationBinaryID || !sqltypes.IsText(fields[colname]) {
+			continue
+		}
+		td.collationCompareCols[i].collation = collation
+	}
+	return nil
+}
+
 // If SourceTimeZone is defined in the BinlogSource, the VReplication workflow would have converted the datetime
 // columns expecting the source to have been in the SourceTimeZone and target in TargetTimeZone. We need to do the reverse
 // conversion in VDiff before comparing to the source
@@ -753,8 +826,30 @@ func (df *vdiff) buildTablePlan(table *tabletmanagerdatapb.TableDefinition, quer
 	if err != nil {
 		return nil, err
 	}
+	if df.collationAware {
+		if err := setCollationCompareCols(df.env, table, targetSelect, fields, td); err != nil {
+			return nil, err
+		}
+	}
 	// Remove in_keyrange. It's not understood by mysql.
 	sourceSelect.Where = removeKeyrange(sel.Where)
+	if df.samplePercent > 0 {
+		if !sqltypes.IsIntegral(fields[strings.ToLower(table.PrimaryKeyColumns[0])]) {
+			log.Warningf("Comparing all the rows of table %v, as only tables whose leading primary key column is an integer can be sampled", table.Name)
+		} else {
+			sourceSample, err := df.sampleFilter(sourceSelect, td.pkCols[0])
+			if err != nil {
+				return nil, err
+			}
+			sourceSelect.AddWhere(sourceSample)
+			targetSample, err := df.sampleFilter(targetSelect, td.pkCols[0])
+			if err != nil {
+				return nil, err
+			}
+			targetSelect.AddWhere(targetSample)
+			td.samplePercent = df.samplePercent
+		}
+	}
 	// The source should also perform the group by.
 	sourceSelect.GroupBy = sel.GroupBy
 	sourceSelect.OrderBy = orderby
@@ -780,6 +875,24 @@ func (df *vdiff) buildTablePlan(table *tabletmanagerdatapb.TableDefinition, quer
 	return td, nil
 }
 
+// sampleRangeSize is the number of values of the leading primary key
+// column in each of the ranges a sampled diff is made of.
+const sampleRangeSize = 1000
+
+// sampleFilter returns the condition that selects the sampled rows of the
+// query, given the index of its leading primary key column, which must be
+// an integer: the values of the column are split into contiguous ranges of
+// sampleRangeSize values, and the first samplePercent ranges of every 100
+// are selected. So the sample is made of contiguous ranges of the primary
+// key, spread over all of it, and every run compares the same rows. The
+// source and target queries select their primary key with different
+// expressions, but the same values, so they select the same rows. Negative
+// values, which are rare in a primary key, are all selected.
+func (df *vdiff) sampleFilter(sel *sqlparser.Select, pkCol int) (sqlparser.Expr, error) {
+	pk := sqlparser.String(sel.GetColumns()[pkCol].(*sqlparser.AliasedExpr).Expr)
+	return df.env.Parser().ParseExpr(fmt.Sprintf("%s div %d %% 100 < %d", pk, sampleRangeSize, df.samplePercent))
+}
+
 func pkColsToGroupByParams(pkCols []int, collationEnv *collations.Environment) []*engine.GroupByParams {
 	var res []*engine.GroupByParams
 	for _, col := range pkCols {
@@ -825,7 +938,7 @@ func (df *vdiff) selectTablets(ctx context.Context, ts *trafficSwitcher) error {
 				sourceTopo = ts.ExternalTopo()
 			}
 			tp, err := discovery.NewTabletPicker(ctx, sourceTopo, []string{df.sourceCell}, df.sourceCell,
-				df.ts.SourceKeyspaceName(), shard, df.tabletTypesStr, discovery.TabletPickerOptions{})
+				df.ts.SourceKeyspaceName(), shard, df.tabletTypesStr, df.ts.wr.pickerOptions)
 			if err != nil {
 				return err
 			}
@@ -850,10 +963,11 @@ func (df *vdiff) selectTablets(ctx context.Context, ts *trafficSwitcher) error {
 			// switched or not, so we just include non-serving tablets for all reshards.
 			includeNonServingTablets = true
 		}
+		pickerOptions := df.ts.wr.pickerOptions
+		pickerOptions.IncludeNonServingTablets = includeNonServingTablets
 		err2 = df.forAll(df.targets, func(shard string, target *shardStreamer) error {
 			tp, err := discovery.NewTabletPicker(ctx, df.ts.TopoServer(), []string{df.targetCell}, df.targetCell,
-				df.ts.TargetKeyspaceName(), shard, df.tabletTypesStr,
-				discovery.TabletPickerOptions{IncludeNonServingTablets: includeNonServingTablets})
+				df.ts.TargetKeyspaceName(), shard, df.tabletTypesStr, pickerOptions)
 			if err != nil {
 				return err
 			}
@@ -1286,6 +1400,15 @@ func (td *tableDiffer) diff(ctx context.Context, rowsToCompare *int64, debug, on
 		// c == 0
 		// Compare the non-pk values.
 		c, err = td.compare(sourceRow, targetRow, td.compareCols, true)
+		if err == nil && c != 0 && td.collationCompareCols != nil {
+			// The row may differ only in text that is equal under the
+			// collation of its columns, which is counted separately so
+			// that it remains visible.
+			c, err = td.compare(sourceRow, targetRow, td.collationCompareCols, true)
+			if err == nil && c == 0 {
+				dr.CollationMatchingRows++
+			}
+		}
 		switch {
 		case err != nil:
 			return nil, err
@@ -804,7 +804,7 @@ func TestVDiffUnsharded(t *testing.T) {
 			env.tablets[101].setResults("select c1, c2 from t1 order by c1 asc", vdiffSourceGtid, tcase.source)
 			env.tablets[201].setResults("select c1, c2 from t1 order by c1 asc", vdiffTargetPrimaryPosition, tcase.target)
 
-			dr, err := env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, env.cell, "replica", 30*time.Second, "", 100, "", tcase.debug, tcase.onlyPks, 100)
+			dr, err := env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, env.cell, "replica", 30*time.Second, "", 100, "", tcase.debug, tcase.onlyPks, 100, false)
 			require.NoError(t, err)
 			assert.Equal(t, tcase.dr, dr["t1"], tcase.id)
 		})
@@ -878,7 +878,7 @@ func TestVDiffSharded(t *testing.T) {
 		),
 	)
 
-	dr, err := env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, env.cell, "replica", 30*time.Second, "", 100, "", false /*debug*/, false /*onlyPks*/, 100)
+	dr, err := env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, env.cell, "replica", 30*time.Second, "", 100, "", false /*debug*/, false /*onlyPks*/, 100, false)
 	require.NoError(t, err)
 	wantdr := &DiffReport{
 		ProcessedRows: 3,
@@ -948,7 +948,7 @@ func TestVDiffAggregates(t *testing.T) {
 		),
 	)
 
-	dr, err := env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, env.cell, "replica", 30*time.Second, "", 100, "", false /*debug*/, false /*onlyPks*/, 100)
+	dr, err := env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, env.cell, "replica", 30*time.Second, "", 100, "", false /*debug*/, false /*onlyPks*/, 100, false)
 	require.NoError(t, err)
 	wantdr := &DiffReport{
 		ProcessedRows: 5,
@@ -990,23 +990,23 @@ func TestVDiffDefaults(t *testing.T) {
 	env.tablets[101].setResults("select c1, c2 from t1 order by c1 asc", vdiffSourceGtid, source)
 	env.tablets[201].setResults("select c1, c2 from t1 order by c1 asc", vdiffTargetPrimaryPosition, target)
 
-	_, err := env.wr.VDiff(context.Background(), "target", env.workflow, "", "", "replica", 30*time.Second, "", 100, "", false /*debug*/, false /*onlyPks*/, 100)
+	_, err := env.wr.VDiff(context.Background(), "target", env.workflow, "", "", "replica", 30*time.Second, "", 100, "", false /*debug*/, false /*onlyPks*/, 100, false)
 	require.NoError(t, err)
-	_, err = env.wr.VDiff(context.Background(), "target", env.workflow, "", env.cell, "replica", 30*time.Second, "", 100, "", false /*debug*/, false /*onlyPks*/, 100)
+	_, err = env.wr.VDiff(context.Background(), "target", env.workflow, "", env.cell, "replica", 30*time.Second, "", 100, "", false /*debug*/, false /*onlyPks*/, 100, false)
 	require.NoError(t, err)
 
 	var df map[string]*DiffReport
-	df, err = env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, "", "replica", 30*time.Second, "", 100, "", false /*debug*/, false /*onlyPks*/, 100)
+	df, err = env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, "", "replica", 30*time.Second, "", 100, "", false /*debug*/, false /*onlyPks*/, 100, false)
 	require.NoError(t, err)
 	require.Equal(t, df["t1"].ProcessedRows, 3)
-	df, err = env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, "", "replica", 30*time.Second, "", 1, "", false /*debug*/, false /*onlyPks*/, 100)
+	df, err = env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, "", "replica", 30*time.Second, "", 1, "", false /*debug*/, false /*onlyPks*/, 100, false)
 	require.NoError(t, err)
 	require.Equal(t, df["t1"].ProcessedRows, 1)
-	df, err = env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, "", "replica", 30*time.Second, "", 0, "", false /*debug*/, false /*onlyPks*/, 100)
+	df, err = env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, "", "replica", 30*time.Second, "", 0, "", false /*debug*/, false /*onlyPks*/, 100, false)
 	require.NoError(t, err)
 	require.Equal(t, df["t1"].ProcessedRows, 0)
 
-	_, err = env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, "", "replica", 1*time.Nanosecond, "", 100, "", false /*debug*/, false /*onlyPks*/, 100)
+	_, err = env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, "", "replica", 1*time.Nanosecond, "", 100, "", false /*debug*/, false /*onlyPks*/, 100, false)
 	require.Error(t, err)
 	err = topo.CheckKeyspaceLocked(context.Background(), "target")
 	require.EqualErrorf(t, err, "keyspace target is not locked (no locksInfo)", "")
@@ -1014,6 +1014,144 @@ func TestVDiffDefaults(t *testing.T) {
 	require.EqualErrorf(t, err, "keyspace source is not locked (no locksInfo)", "")
 }
 
+func TestVDiffCollationAware(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestVDiffEnv(t, ctx, []string{"0"}, []string{"0"}, "", nil)
+	defer env.close()
+
+	schm := &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+			Name:              "t1",
+			Columns:           []string{"c1", "c2", "c3"},
+			PrimaryKeyColumns: []string{"c1"},
+			Fields:            sqltypes.MakeTestFields("c1|c2|c3", "int64|varchar|varbinary"),
+			Schema:            "create table t1(c1 bigint, c2 varchar(20) collate utf8mb4_general_ci, c3 varbinary(20), primary key(c1))",
+		}},
+	}
+	env.tmc.schema = schm
+
+	fields := sqltypes.MakeTestFields(
+		"c1|c2|c3",
+		"int64|varchar|varbinary",
+	)
+	// Row 2 only differs in the case of its text column, row 3 in the case
+	// of its binary column, and row 4 in its text.
+	source := sqltypes.MakeTestStreamingResults(fields,
+		"1|abc|x",
+		"2|ABC|y",
+		"3|abc|Z",
+		"4|abc|w",
+	)
+	target := sqltypes.MakeTestStreamingResults(fields,
+		"1|abc|x",
+		"2|abc|y",
+		"3|abc|z",
+		"4|abd|w",
+	)
+	query := "select c1, c2, c3 from t1 order by c1 asc"
+
+	testcases := []struct {
+		name           string
+		collationAware bool
+		dr             *DiffReport
+	}{{
+		name:           "exact",
+		collationAware: false,
+		dr: &DiffReport{
+			ProcessedRows:  4,
+			MatchingRows:   1,
+			MismatchedRows: 3,
+		},
+	}, {
+		name:           "collation-aware",
+		collationAware: true,
+		dr: &DiffReport{
+			ProcessedRows:         4,
+			MatchingRows:          2,
+			CollationMatchingRows: 1,
+			MismatchedRows:        2,
+		},
+	}}
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			env.tablets[101].setResults(query, vdiffSourceGtid, source)
+			env.tablets[201].setResults(query, vdiffTargetPrimaryPosition, target)
+			dr, err := env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, env.cell, "replica", 30*time.Second, "", 100, "", false /*debug*/, false /*onlyPks*/, 100, tcase.collationAware)
+			require.NoError(t, err)
+			got := dr["t1"]
+			require.Equal(t, tcase.dr.ProcessedRows, got.ProcessedRows)
+			require.Equal(t, tcase.dr.MatchingRows, got.MatchingRows)
+			require.Equal(t, tcase.dr.CollationMatchingRows, got.CollationMatchingRows)
+			require.Equal(t, tcase.dr.MismatchedRows, got.MismatchedRows)
+		})
+	}
+}
+
+func TestVDiffPlanSample(t *testing.T) {
+	schm := &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+			Name:              "t1",
+			Columns:           []string{"c1", "c2"},
+			PrimaryKeyColumns: []string{"c1"},
+			Fields:            sqltypes.MakeTestFields("c1|c2", "int64|int64"),
+		}, {
+			Name:              "multipk",
+			Columns:           []string{"c1", "c2", "c3"},
+			PrimaryKeyColumns: []string{"c1", "c2"},
+			Fields:            sqltypes.MakeTestFields("c1|c2|c3", "int64|int64|int64"),
+		}, {
+			Name:              "varcharpk",
+			Columns:           []string{"c1", "c2"},
+			PrimaryKeyColumns: []string{"c1"},
+			Fields:            sqltypes.MakeTestFields("c1|c2", "varchar|int64"),
+		}},
+	}
+
+	testcases := []struct {
+		input         *binlogdatapb.Rule
+		table         string
+		source        string
+		target        string
+		samplePercent int
+	}{{
+		input:         &binlogdatapb.Rule{Match: "t1"},
+		table:         "t1",
+		source:        "select c1, c2 from t1 where c1 div 1000 % 100 < 10 order by c1 asc",
+		target:        "select c1, c2 from t1 where c1 div 1000 % 100 < 10 order by c1 asc",
+		samplePercent: 10,
+	}, {
+		// The source and target select the primary key differently, but
+		// sample the same ranges of its leading column.
+		input:         &binlogdatapb.Rule{Match: "multipk", Filter: "select c3, c2 as c1, c1 as c2 from t1 where c3 > 0"},
+		table:         "multipk",
+		source:        "select c3, c2 as c1, c1 as c2 from t1 where c3 > 0 and c2 div 1000 % 100 < 10 order by c1 asc, c2 asc",
+		target:        "select c3, c1, c2 from multipk where c1 div 1000 % 100 < 10 order by c1 asc, c2 asc",
+		samplePercent: 10,
+	}, {
+		// Only integer primary keys are split into ranges.
+		input:  &binlogdatapb.Rule{Match: "varcharpk"},
+		table:  "varcharpk",
+		source: "select c1, c2 from varcharpk order by c1 asc",
+		target: "select c1, c2 from varcharpk order by c1 asc",
+	}}
+	for _, tcase := range testcases {
+		t.Run(tcase.table, func(t *testing.T) {
+			filter := &binlogdatapb.Filter{Rules: []*binlogdatapb.Rule{tcase.input}}
+			df := &vdiff{env: vtenv.NewTestEnv(), samplePercent: 10}
+			err := df.buildVDiffPlan(filter, schm, nil)
+			require.NoError(t, err)
+			td := df.differs[tcase.table]
+			require.Equal(t, tcase.source, td.sourceExpression)
+			require.Equal(t, tcase.target, td.targetExpression)
+			require.Equal(t, tcase.samplePercent, td.samplePercent)
+		})
+	}
+
+	require.False(t, (&DiffReport{MatchingRows: 3}).HasDiffs())
+	require.True(t, (&DiffReport{ExtraRowsTarget: 1}).HasDiffs())
+}
+
 func TestVDiffReplicationWait(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -1046,7 +1184,7 @@ func TestVDiffReplicationWait(t *testing.T) {
 	env.tablets[101].setResults("select c1, c2 from t1 order by c1 asc", vdiffSourceGtid, source)
 	env.tablets[201].setResults("select c1, c2 from t1 order by c1 asc", vdiffTargetPrimaryPosition, target)
 
-	_, err := env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, env.cell, "replica", 0*time.Second, "", 100, "", false /*debug*/, false /*onlyPks*/, 100)
+	_, err := env.wr.VDiff(context.Background(), "target", env.workflow, env.cell, env.cell, "replica", 0*time.Second, "", 100, "", false /*debug*/, false /*onlyPks*/, 100, false)
 	require.Error(t, err)
 	require.True(t, strings.Contains(err.Error(), "context deadline exceeded"))
 }
@@ -0,0 +1,60 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// verifyReverseTabletTypes are the tablet types the reverse VDiff streams
+// from, as for VDiff.
+const verifyReverseTabletTypes = "in_order:RDONLY,REPLICA,PRIMARY"
+
+// VerifyReverseWorkflow runs a VDiff of samplePercent of the rows of the
+// tables, or of all the tables if it is empty, on the reverse workflow of a
+// workflow whose writes have been switched, so that the reverse replication
+// can be trusted before the workflow is completed. The rows are contiguous
+// ranges of the primary key, so that every run compares the same rows.
+func (wr *Wrangler) VerifyReverseWorkflow(ctx context.Context, targetKeyspace, workflowName string, samplePercent int, tables string, filteredReplicationWaitTime time.Duration) (map[string]*DiffReport, error) {
+	if samplePercent < 1 || samplePercent > 100 {
+		return nil, fmt.Errorf("the sample percentage must be between 1 and 100, got %d", samplePercent)
+	}
+	ts, err := wr.buildTrafficSwitcher(ctx, targetKeyspace, workflowName)
+	if err != nil {
+		return nil, err
+	}
+	if !ts.frozen {
+		return nil, operationErrorf(vtrpcpb.Code_FAILED_PRECONDITION, keyspaceObject(targetKeyspace),
+			"writes have not been switched for workflow %s.%s, so it has no reverse workflow to verify", targetKeyspace, workflowName)
+	}
+	reverseKeyspace, reverseWorkflow := ts.sourceKeyspace, ts.ReverseWorkflowName()
+	if samplePercent == 100 {
+		samplePercent = 0
+	}
+	wr.Logger().Printf("Verifying the reverse workflow %s.%s of %s.%s\n", reverseKeyspace, reverseWorkflow, targetKeyspace, workflowName)
+	reports, err := wr.runVDiff(ctx, reverseKeyspace, reverseWorkflow, "", "", verifyReverseTabletTypes, filteredReplicationWaitTime,
+		"", math.MaxInt64, tables, false /*debug*/, false /*onlyPks*/, 1000, false /*collationAware*/, samplePercent)
+	if err != nil {
+		return nil, fmt.Errorf("cannot verify the reverse workflow %s.%s: %w", reverseKeyspace, reverseWorkflow, err)
+	}
+	return reports, nil
+}
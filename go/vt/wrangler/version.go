@@ -17,15 +17,21 @@ limitations under the License.
 package wrangler
 
 import (
-	"fmt"
-
 	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 
 	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 // GetVersion returns the version string from a tablet
@@ -33,33 +39,166 @@ func (wr *Wrangler) GetVersion(ctx context.Context, tabletAlias *topodatapb.Tabl
 	resp, err := wr.VtctldServer().GetVersion(ctx, &vtctldatapb.GetVersionRequest{
 		TabletAlias: tabletAlias,
 	})
+	if err != nil {
+		return "", err
+	}
 	log.Infof("Tablet %v is running version '%v'", topoproto.TabletAliasString(tabletAlias), resp.Version)
-	return resp.Version, err
+	return resp.Version, nil
 }
 
 // ValidateVersionShard validates all versions are the same in all
-// tablets in a shard
-func (wr *Wrangler) ValidateVersionShard(ctx context.Context, keyspace, shard string) error {
-	res, err := wr.VtctldServer().ValidateVersionShard(ctx, &vtctldatapb.ValidateVersionShardRequest{
-		Keyspace: keyspace,
-		Shard:    shard,
+// tablets in a shard. The DRAINED, BACKUP and RESTORE tablets are skipped,
+// and logged, unless includeNonServing is set.
+func (wr *Wrangler) ValidateVersionShard(ctx context.Context, keyspace, shard string, includeNonServing bool) error {
+	res, err := wr.VtctldServer().ValidateVersionShard(wr.vtctldContext(ctx), &vtctldatapb.ValidateVersionShardRequest{
+		Keyspace:          keyspace,
+		Shard:             shard,
+		IncludeNonServing: includeNonServing,
 	})
-
+	if err != nil {
+		return wrapError(err, shardObject(keyspace, shard))
+	}
+	wr.logSkippedTablets(res.SkippedTablets)
 	if len(res.Results) > 0 {
-		return fmt.Errorf("version diffs: %v", res.Results)
+		return operationErrorf(vtrpcpb.Code_FAILED_PRECONDITION, shardObject(keyspace, shard), "version diffs: %v", res.Results)
 	}
-	return err
+	return nil
 }
 
 // ValidateVersionKeyspace validates all versions are the same in all
-// tablets in a keyspace
-func (wr *Wrangler) ValidateVersionKeyspace(ctx context.Context, keyspace string) error {
-	res, err := wr.VtctldServer().ValidateVersionKeyspace(ctx, &vtctldatapb.ValidateVersionKeyspaceRequest{
-		Keyspace: keyspace,
+// tablets in a keyspace, as the one of the primary of referenceShard, or of
+// the first serving shard if it is empty. The primary of each shard is
+// compared with the reference, and the other tablets of the shard with
+// their primary. Each difference is logged. The shards that serve no
+// traffic are skipped, unless includeNonServingShards is set, and so are the
+// DRAINED, BACKUP and RESTORE tablets, unless includeNonServing is set.
+func (wr *Wrangler) ValidateVersionKeyspace(ctx context.Context, keyspace, referenceShard string, includeNonServing, includeNonServingShards bool) error {
+	shards, err := wr.ts.FindAllShardsInKeyspace(ctx, keyspace, nil)
+	if err != nil {
+		return wrapError(err, keyspaceObject(keyspace))
+	}
+	var serving, nonServing []string
+	for name, si := range shards {
+		if !includeNonServingShards && !grpcvtctldserver.IsShardServing(ctx, wr.ts, si) {
+			nonServing = append(nonServing, name)
+			continue
+		}
+		serving = append(serving, name)
+	}
+	sort.Strings(serving)
+	sort.Strings(nonServing)
+	wr.logNonServingShards(nonServing)
+
+	referenceAlias, err := versionReferenceAlias(keyspace, referenceShard, serving, shards)
+	if err != nil {
+		return operationErrorf(vtrpcpb.Code_FAILED_PRECONDITION, keyspaceObject(keyspace), "%v", err)
+	}
+	tabletCtx, cancel := wr.tabletContext(ctx)
+	referenceVersion, err := wr.GetVersion(tabletCtx, referenceAlias)
+	cancel()
+	if err != nil {
+		return wrapError(fmt.Errorf("cannot get the version of reference tablet %v: %w", topoproto.TabletAliasString(referenceAlias), err), keyspaceObject(keyspace))
+	}
+
+	var (
+		mu      sync.Mutex
+		diffs   = make(map[string][]string)
+		skipped = make(map[string][]*vtctldatapb.SkippedTablet)
+	)
+	results, err := wr.ForEachShardPrimary(ctx, keyspace, ShardPrimaryOptions{Shards: serving}, func(ctx context.Context, shard string, primary *topo.TabletInfo) error {
+		var shardDiffs []string
+		if !topoproto.TabletAliasEqual(primary.Alias, referenceAlias) {
+			tabletCtx, cancel := wr.tabletContext(ctx)
+			version, err := wr.GetVersion(tabletCtx, primary.Alias)
+			cancel()
+			if err != nil {
+				return fmt.Errorf("unable to get version for tablet %v: %v", primary.AliasString(), err)
+			}
+			if version != referenceVersion {
+				shardDiffs = append(shardDiffs, fmt.Sprintf("primary %v version %v is different than primary %v version %v", topoproto.TabletAliasString(referenceAlias), referenceVersion, primary.AliasString(), version))
+			}
+		}
+		resp, err := wr.VtctldServer().ValidateVersionShard(wr.vtctldContext(ctx), &vtctldatapb.ValidateVersionShardRequest{
+			Keyspace:          keyspace,
+			Shard:             shard,
+			IncludeNonServing: includeNonServing,
+		})
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		diffs[shard] = append(shardDiffs, resp.Results...)
+		skipped[shard] = resp.SkippedTablets
+		return nil
 	})
+	if results == nil {
+		return wrapError(err, keyspaceObject(keyspace))
+	}
 
-	if len(res.Results) > 0 {
-		return fmt.Errorf("version diffs: %v", res.Results)
+	var all []string
+	for _, result := range results {
+		wr.logSkippedTablets(skipped[result.Shard])
+		if result.Error != "" {
+			all = append(all, result.Error)
+		}
+		all = append(all, diffs[result.Shard]...)
+	}
+	if len(all) == 0 {
+		return nil
+	}
+	for _, diff := range all {
+		wr.Logger().Printf("%s\n", diff)
+	}
+	return operationErrorf(vtrpcpb.Code_FAILED_PRECONDITION, keyspaceObject(keyspace), "version diffs: %v", all)
+}
+
+// versionReferenceAlias returns the primary of referenceShard, which must
+// be one of the serving shards, or of the first serving shard whose primary
+// is serving if it is empty.
+func versionReferenceAlias(keyspace, referenceShard string, serving []string, shards map[string]*topo.ShardInfo) (*topodatapb.TabletAlias, error) {
+	if referenceShard != "" {
+		si, ok := shards[referenceShard]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("reference shard %v/%v is not one of the validated shards", keyspace, referenceShard)
+		case !slices.Contains(serving, referenceShard):
+			return nil, fmt.Errorf("reference shard %v/%v is not serving", keyspace, referenceShard)
+		case !si.HasPrimary():
+			return nil, fmt.Errorf("no primary in reference shard %v/%v", keyspace, referenceShard)
+		}
+		return si.PrimaryAlias, nil
+	}
+	for _, name := range serving {
+		if si := shards[name]; si.IsPrimaryServing && si.HasPrimary() {
+			return si.PrimaryAlias, nil
+		}
+	}
+	return nil, fmt.Errorf("no serving shard with a primary in keyspace %v to use as the reference", keyspace)
+}
+
+// logNonServingShards lists the shards a keyspace-wide validation skipped
+// because they serve no traffic.
+func (wr *Wrangler) logNonServingShards(shards []string) {
+	if len(shards) > 0 {
+		wr.Logger().Printf("Skipped non-serving shards: %s\n", strings.Join(shards, ","))
+	}
+}
+
+// logSkippedTablets lists the tablets a validation skipped because of their
+// type.
+func (wr *Wrangler) logSkippedTablets(skipped []*vtctldatapb.SkippedTablet) {
+	for _, st := range skipped {
+		wr.Logger().Printf("Skipped %v tablet %v of shard %v\n", st.TabletType, topoproto.TabletAliasString(st.TabletAlias), st.Shard)
+	}
+}
+
+// findingMessages returns the messages of the findings of a keyspace-wide
+// validation, in order.
+func findingMessages(findings []*vtctldatapb.ValidationFinding) []string {
+	messages := make([]string, len(findings))
+	for i, finding := range findings {
+		messages[i] = finding.Message
 	}
-	return err
+	return messages
 }
@@ -337,21 +337,36 @@ func (wr *Wrangler) convertQueryResultToSQLTypesResult(results map[*topo.TabletI
 	return retResults
 }
 
+// WorkflowShowOptions are the options of the show action of WorkflowAction.
+type WorkflowShowOptions struct {
+	// IncludeLogs adds the retained log history of each stream to the output.
+	IncludeLogs bool
+}
+
 // WorkflowAction can start/stop/update/delete or list streams in _vt.vreplication
 // on all primaries in the target keyspace of the workflow.
 // rpcReq is an optional argument for any actions that use the new RPC path. Today
 // that is only the update action. When using the SQL interface this is ignored and
-// you can pass nil.
+// you can pass nil. The show action also accepts a *WorkflowShowOptions as rpcReq.
 func (wr *Wrangler) WorkflowAction(ctx context.Context, workflow, keyspace, action string, dryRun bool, rpcReq any,
 	shards []string) (map[*topo.TabletInfo]*sqltypes.Result, error) {
 	switch action {
 	case "show":
-		replStatus, err := wr.ShowWorkflow(ctx, workflow, keyspace, shards)
+		var includeLogs bool
+		if options, ok := rpcReq.(*WorkflowShowOptions); ok && options != nil {
+			includeLogs = options.IncludeLogs
+		}
+		replStatus, err := wr.showWorkflow(ctx, workflow, keyspace, shards, includeLogs)
 		if err != nil {
 			return nil, err
 		}
 		err = dumpStreamListAsJSON(replStatus, wr)
 		return nil, err
+	case "clearlogs":
+		if dryRun {
+			return nil, fmt.Errorf("the clearlogs action does not support --dry-run")
+		}
+		return wr.ClearWorkflowLogs(ctx, workflow, keyspace, shards)
 	case "listall":
 		workflows, err := wr.ListAllWorkflows(ctx, keyspace, false)
 		if err != nil {
@@ -366,7 +381,7 @@ func (wr *Wrangler) WorkflowAction(ctx context.Context, workflow, keyspace, acti
 		return nil, err
 	}
 	if len(results) == 0 && !dryRun { // Dry runs produce no actual tablet results
-		return nil, fmt.Errorf("the %s workflow does not exist in the %s keyspace", workflow, keyspace)
+		return nil, operationErrorf(vtrpcpb.Code_NOT_FOUND, workflowObject(keyspace, workflow), "the %s workflow does not exist in the %s keyspace", workflow, keyspace)
 	}
 	return wr.convertQueryResultToSQLTypesResult(results), err
 }
@@ -385,7 +400,7 @@ func (wr *Wrangler) getWorkflowActionQuery(action string) (string, error) {
 	case "delete":
 		query = sqlVReplicationDelete
 	default:
-		return "", fmt.Errorf("invalid action found: %s", action)
+		return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid action found: %s", action)
 	}
 	return query, nil
 }
@@ -520,6 +535,8 @@ type ReplicationStatusResult struct {
 	OnDDL string `json:"OnDDL,omitempty"`
 	// DeferSecondaryKeys specifies whether to defer the creation of secondary keys.
 	DeferSecondaryKeys bool `json:"DeferSecondaryKeys,omitempty"`
+	// AtomicCopy is true if the workflow copies all the tables in a single consistent snapshot.
+	AtomicCopy bool `json:"AtomicCopy,omitempty"`
 }
 
 // ReplicationLocation represents a location that data is either replicating from, or replicating into.
@@ -579,6 +596,9 @@ type ReplicationStatus struct {
 	WorkflowSubType string
 	// CopyState represents the rows from the _vt.copy_state table.
 	CopyState []copyState
+	// Logs represents the rows from the _vt.vreplication_log table, oldest
+	// first. It is only set when the logs are requested.
+	Logs []*ReplicationStreamLog `json:",omitempty"`
 	// RowsCopied shows the number of rows copied per stream, only valid when workflow state is "Copying"
 	RowsCopied int64
 	// sourceTimeZone represents the time zone of each stream, only set if not UTC
@@ -588,6 +608,19 @@ type ReplicationStatus struct {
 	deferSecondaryKeys bool
 }
 
+// ReplicationStreamLog is a state transition or message recorded for a stream
+// in the _vt.vreplication_log table.
+type ReplicationStreamLog struct {
+	ID        int64
+	Type      string
+	State     string
+	Message   string
+	CreatedAt string
+	UpdatedAt string
+	// Count is how many times the same message was repeated in a row.
+	Count int64
+}
+
 func (wr *Wrangler) getReplicationStatusFromRow(ctx context.Context, row sqltypes.RowNamedValues, copyStates []copyState, primary *topo.TabletInfo) (*ReplicationStatus, string, error) {
 	var err error
 	var id int32
@@ -697,7 +730,7 @@ func (wr *Wrangler) getReplicationStatusFromRow(ctx context.Context, row sqltype
 	return status, bls.Keyspace, nil
 }
 
-func (wr *Wrangler) getStreams(ctx context.Context, workflow, keyspace string, shards []string) (*ReplicationStatusResult, error) {
+func (wr *Wrangler) getStreams(ctx context.Context, workflow, keyspace string, shards []string, includeLogs bool) (*ReplicationStatusResult, error) {
 	var rsr ReplicationStatusResult
 	rsr.ShardStatuses = make(map[string]*ShardReplicationStatus)
 	rsr.Workflow = workflow
@@ -752,6 +785,13 @@ func (wr *Wrangler) getStreams(ctx context.Context, workflow, keyspace string, s
 		if err != nil {
 			return nil, err
 		}
+		var logsByVReplID map[int64][]*ReplicationStreamLog
+		if includeLogs {
+			logsByVReplID, err = wr.getStreamLogs(ctx, primary, vreplIDs)
+			if err != nil {
+				return nil, err
+			}
+		}
 		for _, row := range nqr.Rows {
 			vreplID, err := row.ToInt64("id")
 			if err != nil {
@@ -763,6 +803,7 @@ func (wr *Wrangler) getStreams(ctx context.Context, workflow, keyspace string, s
 			if err != nil {
 				return nil, err
 			}
+			status.Logs = logsByVReplID[vreplID]
 			rsr.SourceTimeZone = status.sourceTimeZone
 			rsr.TargetTimeZone = status.targetTimeZone
 			sourceKeyspace = sk
@@ -786,6 +827,9 @@ func (wr *Wrangler) getStreams(ctx context.Context, workflow, keyspace string, s
 			}
 
 			rsr.DeferSecondaryKeys = status.deferSecondaryKeys
+			if status.WorkflowSubType == binlogdatapb.VReplicationWorkflowSubType_AtomicCopy.String() {
+				rsr.AtomicCopy = true
+			}
 
 			if status.Message == workflow2.Frozen {
 				rsr.Frozen = true
@@ -882,12 +926,16 @@ func (wr *Wrangler) ListAllWorkflows(ctx context.Context, keyspace string, activ
 // ShowWorkflow will return all of the relevant replication related information for the given workflow.
 // If shardSubset is nil, then all shards will be queried.
 func (wr *Wrangler) ShowWorkflow(ctx context.Context, workflow, keyspace string, shardSubset []string) (*ReplicationStatusResult, error) {
-	replStatus, err := wr.getStreams(ctx, workflow, keyspace, shardSubset)
+	return wr.showWorkflow(ctx, workflow, keyspace, shardSubset, false)
+}
+
+func (wr *Wrangler) showWorkflow(ctx context.Context, workflow, keyspace string, shardSubset []string, includeLogs bool) (*ReplicationStatusResult, error) {
+	replStatus, err := wr.getStreams(ctx, workflow, keyspace, shardSubset, includeLogs)
 	if err != nil {
 		return nil, err
 	}
 	if len(replStatus.ShardStatuses) == 0 {
-		return nil, fmt.Errorf("no streams found for workflow %s in keyspace %s", workflow, keyspace)
+		return nil, operationErrorf(vtrpcpb.Code_NOT_FOUND, workflowObject(keyspace, workflow), "no streams found for workflow %s in keyspace %s", workflow, keyspace)
 	}
 
 	return replStatus, nil
@@ -961,3 +1009,89 @@ func (wr *Wrangler) getCopyStates(ctx context.Context, tablet *topo.TabletInfo,
 
 	return cs, nil
 }
+
+func (wr *Wrangler) getStreamLogs(ctx context.Context, tablet *topo.TabletInfo, ids []int64) (map[int64][]*ReplicationStreamLog, error) {
+	idsBV, err := sqltypes.BuildBindVariable(ids)
+	if err != nil {
+		return nil, err
+	}
+	query, err := sqlparser.ParseAndBind("select id, vrepl_id, type, state, message, created_at, updated_at, count from _vt.vreplication_log where vrepl_id in %a order by vrepl_id, id",
+		idsBV)
+	if err != nil {
+		return nil, err
+	}
+	qr, err := wr.tmc.VReplicationExec(ctx, tablet.Tablet, query)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := make(map[int64][]*ReplicationStreamLog)
+	result := sqltypes.Proto3ToResult(qr)
+	if result == nil {
+		return logs, nil
+	}
+	for _, row := range result.Named().Rows {
+		id, err := row.ToInt64("id")
+		if err != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to cast id to int64: %v", err)
+		}
+		vreplID, err := row.ToInt64("vrepl_id")
+		if err != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to cast vrepl_id to int64: %v", err)
+		}
+		count, err := row.ToInt64("count")
+		if err != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to cast count to int64: %v", err)
+		}
+		logs[vreplID] = append(logs[vreplID], &ReplicationStreamLog{
+			ID:        id,
+			Type:      row.AsString("type", ""),
+			State:     row.AsString("state", ""),
+			Message:   row.AsString("message", ""),
+			CreatedAt: row.AsString("created_at", ""),
+			UpdatedAt: row.AsString("updated_at", ""),
+			Count:     count,
+		})
+	}
+	return logs, nil
+}
+
+// ClearWorkflowLogs deletes the log history kept in _vt.vreplication_log for
+// the streams of the workflow, on all primaries in its target keyspace. The
+// results hold the number of log rows deleted on each primary.
+func (wr *Wrangler) ClearWorkflowLogs(ctx context.Context, workflow, keyspace string, shards []string) (map[*topo.TabletInfo]*sqltypes.Result, error) {
+	results, err := wr.runVexec(ctx, workflow, keyspace, "select id from _vt.vreplication", nil, false, shards)
+	if err != nil {
+		return nil, err
+	}
+	cleared := make(map[*topo.TabletInfo]*sqltypes.Result, len(results))
+	for primary, result := range results {
+		qr := sqltypes.Proto3ToResult(result)
+		if len(qr.Rows) == 0 {
+			continue
+		}
+		ids := make([]int64, len(qr.Rows))
+		for i, row := range qr.Rows {
+			if ids[i], err = row[0].ToInt64(); err != nil {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to cast id to int64: %v", err)
+			}
+		}
+		idsBV, err := sqltypes.BuildBindVariable(ids)
+		if err != nil {
+			return nil, err
+		}
+		query, err := sqlparser.ParseAndBind("delete from _vt.vreplication_log where vrepl_id in %a", idsBV)
+		if err != nil {
+			return nil, err
+		}
+		res, err := wr.tmc.VReplicationExec(ctx, primary.Tablet, query)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "failed to clear the logs of workflow %s on %s", workflow, primary.AliasString())
+		}
+		cleared[primary] = sqltypes.Proto3ToResult(res)
+	}
+	if len(cleared) == 0 {
+		return nil, operationErrorf(vtrpcpb.Code_NOT_FOUND, workflowObject(keyspace, workflow), "the %s workflow does not exist in the %s keyspace", workflow, keyspace)
+	}
+	return cleared, nil
+}
@@ -84,7 +84,7 @@ func (p vreplicationPlanner) exec(
 	return qr, nil
 }
 func (p vreplicationPlanner) dryRun(ctx context.Context) error {
-	rsr, err := p.vx.wr.getStreams(p.vx.ctx, p.vx.workflow, p.vx.keyspace, nil)
+	rsr, err := p.vx.wr.getStreams(p.vx.ctx, p.vx.workflow, p.vx.keyspace, nil, false)
 	if err != nil {
 		return err
 	}
@@ -92,7 +92,7 @@ func TestVExec(t *testing.T) {
 	vx.plannedQuery = plan.parsedQuery.Query
 	vx.exec()
 
-	res, err := wr.getStreams(ctx, workflow, keyspace, nil)
+	res, err := wr.getStreams(ctx, workflow, keyspace, nil, false)
 	require.NoError(t, err)
 	require.Less(t, res.MaxVReplicationLag, int64(3 /*seconds*/)) // lag should be very small
 
@@ -283,6 +283,61 @@ will be run on the following streams in keyspace target for workflow wrWorkflow:
 	require.Equal(t, dryRunResult, logger.String())
 }
 
+func TestWorkflowStreamLogs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	workflow := "wrWorkflow"
+	keyspace := "target"
+	env := newWranglerTestEnv(t, ctx, []string{"0"}, []string{"-80", "80-"}, nil, 1234)
+	defer env.close()
+	logger := logutil.NewMemoryLogger()
+	wr := New(vtenv.NewTestEnv(), logger, env.topoServ, env.tmc)
+
+	logsResult := sqltypes.MakeTestResult(sqltypes.MakeTestFields(
+		"id|vrepl_id|type|state|message|created_at|updated_at|count",
+		"int64|int64|varchar|varchar|text|timestamp|timestamp|int64"),
+		"7|1|State Changed|Running||2025-01-02 03:04:05|2025-01-02 03:04:05|1",
+		"8|1|Error|Error|duplicate key|2025-01-02 03:05:00|2025-01-02 03:07:00|3",
+	)
+	for _, uid := range []int{200, 210} {
+		primary := env.tmc.tablets[uid].tablet
+		env.tmc.setVRResults(primary, "select id, vrepl_id, type, state, message, created_at, updated_at, count from _vt.vreplication_log where vrepl_id in (1) order by vrepl_id, id", logsResult)
+		env.tmc.setVRResults(primary, "select id from _vt.vreplication where db_name = 'vt_target' and workflow = 'wrWorkflow'",
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1"))
+		env.tmc.setVRResults(primary, "delete from _vt.vreplication_log where vrepl_id in (1)", &sqltypes.Result{RowsAffected: 2})
+		env.tmc.setVRResults(primary, "select id from _vt.vreplication where db_name = 'vt_target' and workflow = 'badwf'", &sqltypes.Result{})
+	}
+
+	// The logs are only shown when requested.
+	replStatus, err := wr.ShowWorkflow(ctx, workflow, keyspace, []string{"-80"})
+	require.NoError(t, err)
+	status := replStatus.ShardStatuses["-80/zone1-0000000200"].PrimaryReplicationStatuses[0]
+	require.Empty(t, status.Logs)
+
+	replStatus, err = wr.showWorkflow(ctx, workflow, keyspace, []string{"-80"}, true)
+	require.NoError(t, err)
+	status = replStatus.ShardStatuses["-80/zone1-0000000200"].PrimaryReplicationStatuses[0]
+	require.Equal(t, []*ReplicationStreamLog{
+		{ID: 7, Type: "State Changed", State: "Running", CreatedAt: "2025-01-02 03:04:05", UpdatedAt: "2025-01-02 03:04:05", Count: 1},
+		{ID: 8, Type: "Error", State: "Error", Message: "duplicate key", CreatedAt: "2025-01-02 03:05:00", UpdatedAt: "2025-01-02 03:07:00", Count: 3},
+	}, status.Logs)
+
+	_, err = wr.WorkflowAction(ctx, workflow, keyspace, "show", false, &WorkflowShowOptions{IncludeLogs: true}, []string{"80-"})
+	require.NoError(t, err)
+	require.Contains(t, logger.String(), `"Logs": [`)
+	require.Contains(t, logger.String(), `"Message": "duplicate key"`)
+
+	results, err := wr.WorkflowAction(ctx, workflow, keyspace, "clearlogs", false, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		require.EqualValues(t, 2, result.RowsAffected)
+	}
+
+	_, err = wr.WorkflowAction(ctx, "badwf", keyspace, "clearlogs", false, nil, nil)
+	require.ErrorContains(t, err, "the badwf workflow does not exist in the target keyspace")
+}
+
 func TestWorkflowListAll(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
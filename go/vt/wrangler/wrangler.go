@@ -20,10 +20,12 @@ package wrangler
 
 import (
 	"context"
+	"time"
 
 	"golang.org/x/sync/semaphore"
 
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
@@ -32,6 +34,7 @@ import (
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
 	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 var (
@@ -41,6 +44,13 @@ var (
 	// so basing this to be greater than RemoteOperationTimeout is good.
 	// Use this as the default value for Context that need a deadline.
 	DefaultActionTimeout = topo.RemoteOperationTimeout * 4
+
+	// CleanupTimeout is how long the cleanup after a failed action,
+	// like restoring writes on the source of a traffic switch, or the end
+	// of a deletion that already started, may take.
+	// It is counted from the failure, not from the start of the action,
+	// see cleanupContext.
+	CleanupTimeout = DefaultActionTimeout
 )
 
 // Wrangler manages complex actions on the topology, like reparents,
@@ -55,6 +65,16 @@ type Wrangler struct {
 	tmc      tmclient.TabletManagerClient
 	vtctld   vtctlservicepb.VtctldServer
 	sourceTs *topo.Server
+	// pickerOptions are the options of the tablet pickers the wrangler
+	// creates.
+	pickerOptions discovery.TabletPickerOptions
+	// clock returns the current time. If it is nil, time.Now is used.
+	clock func() time.Time
+	// readOnly refuses the operations that change the topo or the tablets,
+	// see CheckWritable.
+	readOnly bool
+	// timeouts are the timeouts set with WithTimeouts, see Timeouts.
+	timeouts Timeouts
 	// VExecFunc is a test-only fixture that allows us to short circuit vexec commands.
 	// DO NOT USE in production code.
 	VExecFunc func(ctx context.Context, workflow, keyspace, query string, dryRun bool) (map[*topo.TabletInfo]*sqltypes.Result, error)
@@ -75,6 +95,38 @@ func New(env *vtenv.Environment, logger logutil.Logger, ts *topo.Server, tmc tmc
 	}
 }
 
+// NewWithOptions creates a new Wrangler object, configured with the given
+// options. Without options, it is the same as one created with New, with a
+// new tmclient.TabletManagerClient.
+func NewWithOptions(env *vtenv.Environment, logger logutil.Logger, ts *topo.Server, opts ...Option) *Wrangler {
+	var o options
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	vtctld := grpcvtctldserver.NewVtctldServer(env, ts)
+	if o.tmc == nil {
+		o.tmc = tmclient.NewTabletManagerClient()
+	} else {
+		vtctld = grpcvtctldserver.NewVtctldServerWithTabletManagerClient(env, ts, o.tmc)
+	}
+	if o.loggerPrefix != "" {
+		logger = logutil.NewPrefixLogger(logger, o.loggerPrefix)
+	}
+
+	return &Wrangler{
+		env:           env,
+		logger:        logger,
+		ts:            ts,
+		tmc:           o.tmc,
+		vtctld:        vtctld,
+		sourceTs:      ts,
+		pickerOptions: o.pickerOptions,
+		clock:         o.now,
+		readOnly:      o.readOnly,
+	}
+}
+
 // NewTestWrangler creates a new Wrangler object for use in tests. This should NOT be used
 // in production.
 func NewTestWrangler(logger logutil.Logger, ts *topo.Server, tmc tmclient.TabletManagerClient) *Wrangler {
@@ -88,6 +140,69 @@ func NewTestWrangler(logger logutil.Logger, ts *topo.Server, tmc tmclient.Tablet
 	}
 }
 
+// WithTopoReadCache returns a copy of the wrangler that caches the
+// keyspace, shard and tablet records it reads, see topo.Server.WithReadCache.
+// It is meant to be used for a single read-heavy command, such as a
+// validation or a listing.
+func (wr *Wrangler) WithTopoReadCache() *Wrangler {
+	ts := wr.ts.WithReadCache()
+	cached := wr.clone()
+	cached.ts = ts
+	if wr.sourceTs == wr.ts {
+		cached.sourceTs = ts
+	}
+	if vtctld, ok := wr.vtctld.(*grpcvtctldserver.VtctldServer); ok {
+		cached.vtctld = vtctld.WithTopoServer(wr.env, ts)
+	}
+	return cached
+}
+
+// WithTimeouts returns a copy of the wrangler that uses the tiers of t that
+// are set, instead of the ones of the flags and of the context, see
+// Timeouts.
+func (wr *Wrangler) WithTimeouts(t Timeouts) *Wrangler {
+	timed := wr.clone()
+	timed.timeouts = t
+	return timed
+}
+
+// clone returns a shallow copy of the wrangler.
+func (wr *Wrangler) clone() *Wrangler {
+	return &Wrangler{
+		env:            wr.env,
+		logger:         wr.logger,
+		ts:             wr.ts,
+		tmc:            wr.tmc,
+		vtctld:         wr.vtctld,
+		sourceTs:       wr.sourceTs,
+		pickerOptions:  wr.pickerOptions,
+		clock:          wr.clock,
+		readOnly:       wr.readOnly,
+		timeouts:       wr.timeouts,
+		VExecFunc:      wr.VExecFunc,
+		sem:            wr.sem,
+		WorkflowParams: wr.WorkflowParams,
+	}
+}
+
+// cleanupContext returns the context to undo the changes of an action that
+// failed with ctx, or to finish a deletion that already started. Its values,
+// like the held topo locks and the trace span, are those of ctx, but it is
+// neither cancelled nor timed out with ctx: an action that failed because
+// its own deadline passed still needs to be able to clean up after itself.
+// Instead, it gets CleanupTimeout to do so.
+func (wr *Wrangler) cleanupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), CleanupTimeout)
+}
+
+// now returns the current time, from the clock set with WithClock if any.
+func (wr *Wrangler) now() time.Time {
+	if wr.clock == nil {
+		return time.Now()
+	}
+	return wr.clock()
+}
+
 // TopoServer returns the topo.Server this wrangler is using.
 func (wr *Wrangler) TopoServer() *topo.Server {
 	return wr.ts
@@ -116,6 +231,23 @@ func (wr *Wrangler) Logger() logutil.Logger {
 	return wr.logger
 }
 
+// ReadOnly returns true if the wrangler refuses the operations that change
+// the topo or the tablets.
+func (wr *Wrangler) ReadOnly() bool {
+	return wr.readOnly
+}
+
+// CheckWritable returns a PERMISSION_DENIED error naming action if the
+// wrangler is read-only. The callers that let untrusted users run commands,
+// such as vtctl.RunCommand, check it before each action that would change
+// the topo or issue a mutating tablet RPC.
+func (wr *Wrangler) CheckWritable(action string) error {
+	if !wr.readOnly {
+		return nil
+	}
+	return operationErrorf(vtrpcpb.Code_PERMISSION_DENIED, action, "%v is not allowed: the wrangler is read-only", action)
+}
+
 // SQLParser returns the parser this wrangler is using.
 func (wr *Wrangler) SQLParser() *sqlparser.Parser {
 	return wr.env.Parser()
@@ -0,0 +1,144 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/faketmclient"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// TestWithTopoReadCache tests that ValidateKeyspace reads each topo record
+// at most once with a read cache, however many checks look at it.
+func TestWithTopoReadCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cells := []string{"cell1", "cell2"}
+	shards := []string{"-80", "80-"}
+	ts, factory := memorytopo.NewServerAndFactory(ctx, cells...)
+	wr := New(vtenv.NewTestEnv(), logutil.NewMemoryLogger(), ts, nil)
+
+	// A primary and three replicas in each shard, across both cells.
+	uid := uint32(100)
+	tablets := 0
+	for _, shard := range shards {
+		for i := range 4 {
+			tablet := &topodatapb.Tablet{
+				Alias:    &topodatapb.TabletAlias{Cell: cells[i%len(cells)], Uid: uid},
+				Keyspace: "ks",
+				Shard:    shard,
+				Type:     topodatapb.TabletType_REPLICA,
+			}
+			if i == 0 {
+				tablet.Type = topodatapb.TabletType_PRIMARY
+			}
+			require.NoError(t, ts.InitTablet(ctx, tablet, false /*allowPrimaryOverride*/, true /*createShardAndKeyspace*/, false /*allowUpdate*/))
+			if i == 0 {
+				_, err := ts.UpdateShardFields(ctx, "ks", shard, func(si *topo.ShardInfo) error {
+					si.PrimaryAlias = tablet.Alias
+					return nil
+				})
+				require.NoError(t, err)
+			}
+			uid++
+			tablets++
+		}
+	}
+
+	validate := func(wr *Wrangler) int64 {
+		before := factory.GetCallStats().Counts()["Get"]
+		report := NewValidationReport()
+		require.NoError(t, wr.ValidateKeyspace(ctx, "ks", false /*pingTablets*/, false /*checkSrvKeyspace*/, report))
+		return factory.GetCallStats().Counts()["Get"] - before
+	}
+
+	// The cell infos, the keyspace and shard records, the replication
+	// graphs and the tablet records.
+	records := int64(len(cells) + 1 + len(shards) + len(shards)*len(cells) + tablets)
+	uncached := validate(wr)
+	cached := validate(wr.WithTopoReadCache())
+	require.LessOrEqual(t, cached, records)
+	require.Less(t, cached, uncached)
+}
+
+// TestCleanupContext tests that the cleanup context outlives the context of
+// the failed action, but keeps its values.
+func TestCleanupContext(t *testing.T) {
+	type key struct{}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+	cancel()
+
+	wr := &Wrangler{}
+	cleanupCtx, cleanupCancel := wr.cleanupContext(ctx)
+	require.NoError(t, cleanupCtx.Err())
+	require.Equal(t, "value", cleanupCtx.Value(key{}))
+	_, ok := cleanupCtx.Deadline()
+	require.True(t, ok)
+
+	cleanupCancel()
+	require.Error(t, cleanupCtx.Err())
+}
+
+// TestNewWithOptions tests that the options of a wrangler are applied, and
+// that it has the defaults of New without them.
+func TestNewWithOptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+
+	logger := logutil.NewMemoryLogger()
+	wr := NewWithOptions(vtenv.NewTestEnv(), logger, ts)
+	require.NotNil(t, wr.TabletManagerClient())
+	require.NotNil(t, wr.VtctldServer())
+	require.Equal(t, discovery.TabletPickerOptions{}, wr.pickerOptions)
+	require.WithinDuration(t, time.Now(), wr.now(), time.Minute)
+	wr.Logger().Infof("message")
+	require.Equal(t, "message", logger.Events[0].Value)
+
+	tmc := faketmclient.NewFakeTabletManagerClient()
+	pickerOptions := discovery.TabletPickerOptions{RetryDelay: time.Millisecond}
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	logger = logutil.NewMemoryLogger()
+	wr = NewWithOptions(vtenv.NewTestEnv(), logger, ts,
+		WithTabletManagerClient(tmc),
+		WithTabletPickerOptions(pickerOptions),
+		WithClock(func() time.Time { return now }),
+		WithLoggerPrefix("prefix: "),
+	)
+	require.Equal(t, tmc, wr.TabletManagerClient())
+	require.Equal(t, pickerOptions, wr.pickerOptions)
+	require.Equal(t, now, wr.now())
+	wr.Logger().Infof("message")
+	require.Equal(t, "prefix: message", logger.Events[0].Value)
+
+	// The options are kept by the copies of the wrangler.
+	cached := wr.WithTopoReadCache()
+	require.Equal(t, pickerOptions, cached.pickerOptions)
+	require.Equal(t, now, cached.now())
+}
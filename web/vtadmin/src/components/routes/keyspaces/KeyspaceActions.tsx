@@ -79,8 +79,9 @@ const KeyspaceActions: React.FC<KeyspaceActionsProps> = ({ keyspace, clusterID }
                 isOpen={currentDialog === 'Validate Schema'}
                 body={
                     <div className="text-sm mt-3">
-                        Validates that the schema on the primary tablet for the first shard matches the schema on all of
-                        the other tablets in the keyspace <span className="font-mono bg-gray-300">{keyspace}</span>.
+                        Validates that the schema on all of the tablets in the keyspace{' '}
+                        <span className="font-mono bg-gray-300">{keyspace}</span> matches the schema most shard
+                        primaries agree on.
                     </div>
                 }
                 successBody={